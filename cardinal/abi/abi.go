@@ -2,6 +2,7 @@ package abi
 
 import (
 	"encoding/json"
+	"errors"
 	"reflect"
 	"regexp"
 	"strings"
@@ -16,8 +17,12 @@ const (
 
 var (
 	hasNumbers = regexp.MustCompile(`\d+`)
+
+	ErrUnsupportedType = errors.New("unsupported type")
 )
 
+// GenerateABIType returns the ABI tuple type of a Go struct. It fails with ErrUnsupportedType if a field has no ABI
+// equivalent, and with ErrStaleABIType if generated code registered a different type for the struct with RegisterType.
 func GenerateABIType(goStruct any) (*abi.Type, error) {
 	rt := reflect.TypeOf(goStruct)
 	if rt.Kind() != reflect.Struct {
@@ -31,9 +36,21 @@ func GenerateABIType(goStruct any) (*abi.Type, error) {
 	if err != nil {
 		return nil, eris.Wrap(err, "")
 	}
+	if err = checkRegisteredType(rt, at); err != nil {
+		return nil, err
+	}
 	return &at, nil
 }
 
+// GenerateABIArguments returns the ABI arguments of the fields of a Go struct, i.e. the components of the tuple type that
+// GenerateABIType returns for it.
+func GenerateABIArguments(rt reflect.Type) ([]abi.ArgumentMarshaling, error) {
+	if rt.Kind() != reflect.Struct {
+		return nil, eris.Errorf("expected input to be of type struct, got %s", rt)
+	}
+	return getArgumentsForType(rt)
+}
+
 func getArgumentsForType(rt reflect.Type) ([]abi.ArgumentMarshaling, error) {
 	args := make([]abi.ArgumentMarshaling, 0, rt.NumField())
 	for i := 0; i < rt.NumField(); i++ {
@@ -126,7 +143,7 @@ func goTypeToSolidityType(t string, tag string) (string, error) {
 
 	// the final type we can support is int/uint, so if we don't have that by here, we error.
 	if !strings.Contains(t, "int") {
-		return "", eris.Errorf("unsupported type %s", t)
+		return "", eris.Wrap(ErrUnsupportedType, t)
 	}
 
 	// finally, check if the uint/int passed contains a size. uint/int without size does not work in ABI->Go.
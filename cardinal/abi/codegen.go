@@ -0,0 +1,253 @@
+package abi
+
+import (
+	"errors"
+	"fmt"
+	"go/format"
+	"io"
+	"path"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/rotisserie/eris"
+)
+
+var ErrStaleABIType = errors.New("generated ABI type does not match the Go struct")
+
+// registeredTypes holds the ABI types registered by generated code, keyed by the reflect.Type of the Go struct.
+var registeredTypes sync.Map
+
+// Binding describes a message or query that can be called from the EVM.
+type Binding struct {
+	// Name is the full name of the message or query, e.g. "game.move".
+	Name string
+	// In is the Go struct of the message input or query request.
+	In reflect.Type
+	// Out is the Go struct of the message output or query reply.
+	Out reflect.Type
+}
+
+// BindingSource is implemented by the messages and queries that can be called from the EVM.
+type BindingSource interface {
+	EVMBinding() (Binding, error)
+}
+
+// RegisterType registers the ABI type of a Go struct. It is called by the code that WriteGoBindings generates, and
+// panics if the arguments don't form a valid ABI type. GenerateABIType fails with ErrStaleABIType for structs whose
+// registered type no longer matches the struct, so outdated generated code is caught when the message or query is
+// registered instead of when a contract sends a transaction.
+func RegisterType(goStruct any, args []abi.ArgumentMarshaling) {
+	at, err := abi.NewType("tuple", "", args)
+	if err != nil {
+		panic(eris.Wrapf(err, "invalid ABI type for %T", goStruct))
+	}
+	registeredTypes.Store(reflect.TypeOf(goStruct), at)
+}
+
+// checkRegisteredType returns an ErrStaleABIType error if a type was registered for rt that differs from at.
+func checkRegisteredType(rt reflect.Type, at abi.Type) error {
+	value, ok := registeredTypes.Load(rt)
+	if !ok {
+		return nil
+	}
+	registered := value.(abi.Type) //nolint:errcheck // only abi.Type values are stored
+	if !sameABIType(registered, at) {
+		return eris.Wrapf(ErrStaleABIType, "%s is %s in the generated code but %s in Go, regenerate the bindings",
+			rt, registered.String(), at.String())
+	}
+	return nil
+}
+
+// sameABIType reports if two ABI types have the same encoding and the same field names.
+func sameABIType(a, b abi.Type) bool {
+	if a.String() != b.String() || len(a.TupleRawNames) != len(b.TupleRawNames) {
+		return false
+	}
+	for i := range a.TupleRawNames {
+		if a.TupleRawNames[i] != b.TupleRawNames[i] || !sameABIType(*a.TupleElems[i], *b.TupleElems[i]) {
+			return false
+		}
+	}
+	if a.Elem != nil && b.Elem != nil {
+		return sameABIType(*a.Elem, *b.Elem)
+	}
+	return true
+}
+
+// WriteGoBindings writes a Go file of the given package that registers the ABI types of the bindings with
+// RegisterType. The generated file has to be in a different package than the structs, and be imported by the game
+// before its messages and queries are registered, usually with a blank import in main.go.
+func WriteGoBindings(w io.Writer, pkgName string, bindings []Binding) error {
+	types, err := bindingTypes(bindings)
+	if err != nil {
+		return err
+	}
+	// Import the packages of the structs under unique names, since different paths can end in the same name.
+	aliases := map[string]string{}
+	used := map[string]bool{"abi": true, "ethabi": true}
+	var paths []string
+	for _, rt := range types {
+		pkgPath := rt.PkgPath()
+		if pkgPath == "" || pkgPath == "main" || !isExported(rt.Name()) {
+			return eris.Errorf("%s can't be referenced by generated code, it has to be an exported struct of an "+
+				"importable package", rt)
+		}
+		if _, ok := aliases[pkgPath]; ok {
+			continue
+		}
+		alias := goIdentifier(path.Base(pkgPath))
+		for i := 2; used[alias]; i++ {
+			alias = goIdentifier(path.Base(pkgPath)) + strconv.Itoa(i)
+		}
+		used[alias] = true
+		aliases[pkgPath] = alias
+		paths = append(paths, pkgPath)
+	}
+	sort.Strings(paths)
+
+	var out strings.Builder
+	out.WriteString("// Code generated by cardinal. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&out, "package %s\n\n", pkgName)
+	out.WriteString("import (\n")
+	out.WriteString("\tethabi \"github.com/ethereum/go-ethereum/accounts/abi\"\n\n")
+	out.WriteString("\t\"pkg.world.dev/world-engine/cardinal/abi\"\n")
+	for _, p := range paths {
+		fmt.Fprintf(&out, "\t%s %q\n", aliases[p], p)
+	}
+	out.WriteString(")\n\nfunc init() {\n")
+	for _, rt := range types {
+		args, err := getArgumentsForType(rt)
+		if err != nil {
+			return eris.Wrapf(err, "struct %s", rt)
+		}
+		fmt.Fprintf(&out, "abi.RegisterType(%s.%s{}, ", aliases[rt.PkgPath()], rt.Name())
+		writeArguments(&out, args)
+		out.WriteString(")\n")
+	}
+	out.WriteString("}\n")
+
+	src, err := format.Source([]byte(out.String()))
+	if err != nil {
+		return eris.Wrap(err, "failed to format generated code")
+	}
+	_, err = w.Write(src)
+	return eris.Wrap(err, "")
+}
+
+// writeArguments writes the Go literal of a slice of arguments.
+func writeArguments(out *strings.Builder, args []abi.ArgumentMarshaling) {
+	out.WriteString("[]ethabi.ArgumentMarshaling{\n")
+	for _, arg := range args {
+		fmt.Fprintf(out, "{Name: %q, Type: %q", arg.Name, arg.Type)
+		if len(arg.Components) > 0 {
+			out.WriteString(", Components: ")
+			writeArguments(out, arg.Components)
+		}
+		out.WriteString("},\n")
+	}
+	out.WriteString("}")
+}
+
+// WriteSolidityBindings writes a Solidity file that declares structs that mirror the Go structs of the bindings, and
+// an interface with a function for every binding that takes the input struct and returns the output struct. Function
+// names are the binding names with dots replaced by underscores, e.g. game_move.
+func WriteSolidityBindings(w io.Writer, interfaceName string, bindings []Binding) error {
+	types, err := bindingTypes(bindings)
+	if err != nil {
+		return err
+	}
+	g := &solidityGenerator{structs: map[string]reflect.Type{}}
+	for _, rt := range types {
+		if err = g.declareStructs(rt); err != nil {
+			return err
+		}
+	}
+
+	var out strings.Builder
+	out.WriteString("// SPDX-License-Identifier: UNLICENSED\n")
+	out.WriteString("// Code generated by cardinal. DO NOT EDIT.\n")
+	out.WriteString("pragma solidity ^0.8.0;\n")
+	for _, name := range g.order {
+		fmt.Fprintf(&out, "\nstruct %s {\n", name)
+		rt := g.structs[name]
+		for i := 0; i < rt.NumField(); i++ {
+			field := rt.Field(i)
+			solType, err := solidityType(field.Type, field.Tag.Get(bigIntStructTag))
+			if err != nil {
+				return eris.Wrapf(err, "field %s.%s", name, field.Name)
+			}
+			fmt.Fprintf(&out, "    %s %s;\n", solType, field.Name)
+		}
+		out.WriteString("}\n")
+	}
+	fmt.Fprintf(&out, "\ninterface %s {\n", interfaceName)
+	for _, b := range bindings {
+		fmt.Fprintf(&out, "    function %s(%s calldata input) external returns (%s memory);\n",
+			solidityIdentifier(b.Name), b.In.Name(), b.Out.Name())
+	}
+	out.WriteString("}\n")
+	_, err = io.WriteString(w, out.String())
+	return eris.Wrap(err, "")
+}
+
+// declareStructs declares a struct type and the struct types of its fields, recursively.
+func (g *solidityGenerator) declareStructs(rt reflect.Type) error {
+	for rt.Kind() == reflect.Slice {
+		rt = rt.Elem()
+	}
+	if rt.Kind() != reflect.Struct || rt == bigIntType || rt == addressType {
+		return nil
+	}
+	if existing, ok := g.structs[rt.Name()]; ok && existing == rt {
+		return nil
+	}
+	if err := g.declareStruct(rt); err != nil {
+		return err
+	}
+	for i := 0; i < rt.NumField(); i++ {
+		if err := g.declareStructs(rt.Field(i).Type); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// bindingTypes returns the distinct input and output structs of the bindings, in order.
+func bindingTypes(bindings []Binding) ([]reflect.Type, error) {
+	seen := map[reflect.Type]bool{}
+	var types []reflect.Type
+	for _, b := range bindings {
+		for _, rt := range []reflect.Type{b.In, b.Out} {
+			if rt == nil || rt.Kind() != reflect.Struct {
+				return nil, eris.Errorf("binding %q: expected a struct, got %v", b.Name, rt)
+			}
+			if !seen[rt] {
+				seen[rt] = true
+				types = append(types, rt)
+			}
+		}
+	}
+	return types, nil
+}
+
+// goIdentifier replaces the characters of a package name that are not valid in a Go identifier.
+func goIdentifier(name string) string {
+	id := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' {
+			return r
+		}
+		return '_'
+	}, name)
+	if id == "" || id[0] >= '0' && id[0] <= '9' {
+		id = "_" + id
+	}
+	return id
+}
+
+func isExported(name string) bool {
+	return name != "" && name[0] >= 'A' && name[0] <= 'Z'
+}
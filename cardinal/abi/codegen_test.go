@@ -0,0 +1,87 @@
+package abi_test
+
+import (
+	"go/parser"
+	"go/token"
+	"reflect"
+	"strings"
+	"testing"
+
+	ethabi "github.com/ethereum/go-ethereum/accounts/abi"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal/abi"
+)
+
+type SpawnResult struct {
+	Success bool
+}
+
+var spawnBinding = abi.Binding{
+	Name: "game.spawn",
+	In:   reflect.TypeOf(Spawn{}),
+	Out:  reflect.TypeOf(SpawnResult{}),
+}
+
+func TestWriteGoBindings(t *testing.T) {
+	var sb strings.Builder
+	assert.NilError(t, abi.WriteGoBindings(&sb, "evmbindings", []abi.Binding{spawnBinding}))
+
+	src := sb.String()
+	_, err := parser.ParseFile(token.NewFileSet(), "evm_bindings.go", src, 0)
+	assert.NilError(t, err)
+	assert.Contains(t, src, "package evmbindings")
+	assert.Contains(t, src, `abi_test "pkg.world.dev/world-engine/cardinal/abi_test"`)
+	assert.Contains(t, src, "abi.RegisterType(abi_test.Spawn{}, []ethabi.ArgumentMarshaling{")
+	assert.Contains(t, src, `{Name: "Gold", Type: "uint256"},`)
+	assert.Contains(t, src, `{Name: "Positions", Type: "tuple[]", Components: []ethabi.ArgumentMarshaling{`)
+	assert.Contains(t, src, "abi.RegisterType(abi_test.SpawnResult{}, ")
+
+	type local struct{ X uint64 }
+	err = abi.WriteGoBindings(&sb, "evmbindings", []abi.Binding{{Name: "game.local", In: reflect.TypeOf(local{}),
+		Out: reflect.TypeOf(SpawnResult{})}})
+	assert.IsError(t, err)
+}
+
+func TestWriteSolidityBindings(t *testing.T) {
+	var sb strings.Builder
+	assert.NilError(t, abi.WriteSolidityBindings(&sb, "IWorld", []abi.Binding{spawnBinding}))
+
+	sol := sb.String()
+	assert.Contains(t, sol, "struct Spawn {\n    address Owner;\n    string Name;\n    uint256 Gold;\n"+
+		"    Position[] Positions;\n    string[] Tags;\n    bool Ready;\n}")
+	assert.Contains(t, sol, "struct Position {\n    int64 X;\n    int64 Y;\n}")
+	assert.Contains(t, sol, "struct SpawnResult {\n    bool Success;\n}")
+	assert.Contains(t, sol, "interface IWorld {\n"+
+		"    function game_spawn(Spawn calldata input) external returns (SpawnResult memory);\n}")
+}
+
+type Transfer struct {
+	To     string
+	Amount uint64
+}
+
+func TestRegisteredTypeMustMatchTheStruct(t *testing.T) {
+	abi.RegisterType(Transfer{}, []ethabi.ArgumentMarshaling{
+		{Name: "To", Type: "string"},
+		{Name: "Amount", Type: "uint64"},
+	})
+	at, err := abi.GenerateABIType(Transfer{})
+	assert.NilError(t, err)
+	assert.Equal(t, at.String(), "(string,uint64)")
+
+	// Renamed fields encode the same, but no longer decode into the struct.
+	abi.RegisterType(Transfer{}, []ethabi.ArgumentMarshaling{
+		{Name: "Recipient", Type: "string"},
+		{Name: "Amount", Type: "uint64"},
+	})
+	_, err = abi.GenerateABIType(Transfer{})
+	assert.ErrorIs(t, err, abi.ErrStaleABIType)
+
+	abi.RegisterType(Transfer{}, []ethabi.ArgumentMarshaling{
+		{Name: "Amount", Type: "uint64"},
+		{Name: "To", Type: "string"},
+	})
+	_, err = abi.GenerateABIType(Transfer{})
+	assert.ErrorIs(t, err, abi.ErrStaleABIType)
+}
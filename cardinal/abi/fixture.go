@@ -0,0 +1,230 @@
+package abi
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"hash/fnv"
+	"math/big"
+	"os"
+	"reflect"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/rotisserie/eris"
+)
+
+// sampleSliceLen is the number of elements of the slices in sample values.
+const sampleSliceLen = 2
+
+var (
+	ErrFixtureRoundTrip = errors.New("value does not survive an ABI round-trip")
+	ErrFixtureDrift     = errors.New("ABI fixtures do not match")
+
+	bigIntType  = reflect.TypeOf((*big.Int)(nil))
+	addressType = reflect.TypeOf(common.Address{})
+)
+
+// Fixture is the canonical ABI encoding of a sample value of a Go type. Fixtures are committed next to the game and
+// compared with freshly generated ones in tests, so that changes to the encoding of a message or query, such as
+// reordered fields, are caught before they break the contracts that call the world.
+type Fixture struct {
+	// Name identifies the fixture, e.g. "game.move input".
+	Name string `json:"name"`
+	// Type is the ABI type of the value, e.g. (uint64,string).
+	Type string `json:"type"`
+	// Value is the JSON encoding of the Go value.
+	Value json.RawMessage `json:"value"`
+	// Encoded is the hex encoded ABI encoding of the value.
+	Encoded string `json:"encoded"`
+
+	// value is the Go value, it is only set on generated fixtures.
+	value any
+}
+
+// FixtureSource is implemented by the messages and queries that can generate the fixtures of their EVM types.
+type FixtureSource interface {
+	EVMFixtures() ([]Fixture, error)
+}
+
+// NewSampleFixture generates the fixture of the sample value of T. See SampleValue.
+func NewSampleFixture[T any](name string) (Fixture, error) {
+	v, err := SampleValue[T]()
+	if err != nil {
+		return Fixture{}, err
+	}
+	return NewFixture(name, v)
+}
+
+// NewFixture ABI encodes the given struct value and decodes it back the way messages and queries do, and returns an
+// ErrFixtureRoundTrip error if the decoded value is not the same as the original one.
+func NewFixture(name string, value any) (Fixture, error) {
+	at, err := GenerateABIType(value)
+	if err != nil {
+		return Fixture{}, eris.Wrapf(err, "fixture %q", name)
+	}
+	args := abi.Arguments{{Type: *at}}
+	bz, err := args.Pack(value)
+	if err != nil {
+		return Fixture{}, eris.Wrapf(err, "fixture %q", name)
+	}
+	unpacked, err := args.Unpack(bz)
+	if err != nil {
+		return Fixture{}, eris.Wrapf(err, "fixture %q", name)
+	}
+	want, err := json.Marshal(value)
+	if err != nil {
+		return Fixture{}, eris.Wrapf(err, "fixture %q", name)
+	}
+	decoded := reflect.New(reflect.TypeOf(value))
+	unpackedJSON, err := json.Marshal(unpacked[0])
+	if err != nil {
+		return Fixture{}, eris.Wrapf(err, "fixture %q", name)
+	}
+	if err = json.Unmarshal(unpackedJSON, decoded.Interface()); err != nil {
+		return Fixture{}, eris.Wrapf(err, "fixture %q", name)
+	}
+	got, err := json.Marshal(decoded.Interface())
+	if err != nil {
+		return Fixture{}, eris.Wrapf(err, "fixture %q", name)
+	}
+	if !bytes.Equal(want, got) {
+		return Fixture{}, eris.Wrapf(ErrFixtureRoundTrip, "fixture %q: encoded %s, decoded %s", name, want, got)
+	}
+	return Fixture{
+		Name:    name,
+		Type:    at.String(),
+		Value:   want,
+		Encoded: hex.EncodeToString(bz),
+		value:   value,
+	}, nil
+}
+
+// CompareFixtures compares committed fixtures with freshly generated ones, and returns an ErrFixtureDrift error that
+// lists every fixture that was added, removed, or whose type or encoding changed.
+func CompareFixtures(want, got []Fixture) error {
+	gotByName := make(map[string]Fixture, len(got))
+	for _, f := range got {
+		gotByName[f.Name] = f
+	}
+	var errs []error
+	for _, w := range want {
+		g, ok := gotByName[w.Name]
+		delete(gotByName, w.Name)
+		switch {
+		case !ok:
+			errs = append(errs, eris.Errorf("%s was removed", w.Name))
+		case w.Type != g.Type:
+			errs = append(errs, eris.Errorf("%s changed type from %s to %s", w.Name, w.Type, g.Type))
+		case w.Encoded != g.Encoded:
+			errs = append(errs, eris.Errorf("%s changed encoding, fields were renamed or reordered", w.Name))
+		}
+	}
+	for _, g := range got {
+		if _, ok := gotByName[g.Name]; ok {
+			errs = append(errs, eris.Errorf("%s was added", g.Name))
+		}
+	}
+	if len(errs) > 0 {
+		return eris.Wrap(ErrFixtureDrift, errors.Join(errs...).Error())
+	}
+	return nil
+}
+
+// SampleValue returns a value of T in which every field is set. The values are derived from the path of the fields,
+// so a value changes its encoding when its fields are renamed or reordered, even if they have the same type.
+func SampleValue[T any]() (T, error) {
+	var t T
+	v, err := sampleValue(reflect.TypeOf(t), "", reflect.TypeOf(t).Name())
+	if err != nil {
+		return t, err
+	}
+	return v.Interface().(T), nil //nolint:errcheck // the sample is always of type T
+}
+
+func sampleValue(rt reflect.Type, tag string, path string) (reflect.Value, error) {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(path))
+	seed := h.Sum64()
+
+	v := reflect.New(rt).Elem()
+	switch {
+	case rt == bigIntType:
+		bits, err := integerBits(tag)
+		if err != nil {
+			return v, eris.Wrapf(err, "field %s", path)
+		}
+		v.Set(reflect.ValueOf(new(big.Int).SetUint64(seed % (1 << min(bits-1, 63)))))
+	case rt == addressType:
+		sum := sha256.Sum256([]byte(path))
+		v.Set(reflect.ValueOf(common.BytesToAddress(sum[:common.AddressLength])))
+	case rt.Kind() == reflect.Struct:
+		for i := 0; i < rt.NumField(); i++ {
+			field := rt.Field(i)
+			fv, err := sampleValue(field.Type, field.Tag.Get(bigIntStructTag), path+"."+field.Name)
+			if err != nil {
+				return v, err
+			}
+			v.Field(i).Set(fv)
+		}
+	case rt.Kind() == reflect.Slice:
+		v.Set(reflect.MakeSlice(rt, sampleSliceLen, sampleSliceLen))
+		for i := 0; i < sampleSliceLen; i++ {
+			ev, err := sampleValue(rt.Elem(), tag, path+"["+strconv.Itoa(i)+"]")
+			if err != nil {
+				return v, err
+			}
+			v.Index(i).Set(ev)
+		}
+	case rt.Kind() == reflect.String:
+		v.SetString(path)
+	case rt.Kind() == reflect.Bool:
+		v.SetBool(seed%2 == 1)
+	case v.CanUint():
+		v.SetUint(seed % (1 << min(rt.Bits(), 63)))
+	case v.CanInt():
+		n := int64(seed % (1 << min(rt.Bits()-1, 62)))
+		if seed%2 == 1 {
+			n = -n
+		}
+		v.SetInt(n)
+	default:
+		return v, eris.Errorf("field %s has unsupported type %s", path, rt)
+	}
+	return v, nil
+}
+
+// integerBits returns the size of an EVM integer type, e.g. 128 for int128.
+func integerBits(evmType string) (int, error) {
+	digits := hasNumbers.FindString(evmType)
+	if digits == "" {
+		return 0, eris.Errorf("when using *big.Int, you MUST use the `%s` struct tag to indicate which "+
+			"underlying evm integer type you wish to resolve to (i.e. uint256, int128, etc)", bigIntStructTag)
+	}
+	return strconv.Atoi(digits)
+}
+
+// ReadFixtures reads fixtures that were written with WriteFixtures.
+func ReadFixtures(path string) ([]Fixture, error) {
+	bz, err := os.ReadFile(path)
+	if err != nil {
+		return nil, eris.Wrap(err, "")
+	}
+	var fixtures []Fixture
+	if err = json.Unmarshal(bz, &fixtures); err != nil {
+		return nil, eris.Wrapf(err, "invalid fixtures file %s", path)
+	}
+	return fixtures, nil
+}
+
+// WriteFixtures writes fixtures to a JSON file, to be committed and compared with CompareFixtures.
+func WriteFixtures(path string, fixtures []Fixture) error {
+	bz, err := json.MarshalIndent(fixtures, "", "  ")
+	if err != nil {
+		return eris.Wrap(err, "")
+	}
+	return eris.Wrap(os.WriteFile(path, append(bz, '\n'), 0o600), "")
+}
@@ -0,0 +1,89 @@
+package abi_test
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal/abi"
+)
+
+type Position struct {
+	X int64
+	Y int64
+}
+
+type Spawn struct {
+	Owner     common.Address
+	Name      string
+	Gold      *big.Int `evm:"uint256"`
+	Positions []Position
+	Tags      []string
+	Ready     bool
+}
+
+func TestSampleFixtureRoundTrips(t *testing.T) {
+	f, err := abi.NewSampleFixture[Spawn]("game.spawn input")
+	assert.NilError(t, err)
+	assert.Equal(t, f.Type, "(address,string,uint256,(int64,int64)[],string[],bool)")
+
+	again, err := abi.NewSampleFixture[Spawn]("game.spawn input")
+	assert.NilError(t, err)
+	assert.NilError(t, abi.CompareFixtures([]abi.Fixture{f}, []abi.Fixture{again}))
+}
+
+func TestCompareFixturesCatchesReorderedFields(t *testing.T) {
+	before := func() abi.Fixture {
+		type Move struct {
+			X uint64
+			Y uint64
+		}
+		f, err := abi.NewSampleFixture[Move]("game.move input")
+		assert.NilError(t, err)
+		return f
+	}()
+	after := func() abi.Fixture {
+		type Move struct {
+			Y uint64
+			X uint64
+		}
+		f, err := abi.NewSampleFixture[Move]("game.move input")
+		assert.NilError(t, err)
+		return f
+	}()
+	// Both fields have the same type, so only the encoding changes.
+	assert.Equal(t, before.Type, after.Type)
+	assert.ErrorIs(t, abi.CompareFixtures([]abi.Fixture{before}, []abi.Fixture{after}), abi.ErrFixtureDrift)
+	assert.ErrorIs(t, abi.CompareFixtures([]abi.Fixture{before}, nil), abi.ErrFixtureDrift)
+}
+
+func TestNewFixtureCatchesValuesThatDontRoundTrip(t *testing.T) {
+	type Renamed struct {
+		// ABI values are decoded through JSON, so a JSON name that differs from the field name loses the value.
+		Amount uint64 `json:"amt"`
+	}
+	_, err := abi.NewFixture("game.renamed input", Renamed{Amount: 10})
+	assert.ErrorIs(t, err, abi.ErrFixtureRoundTrip)
+}
+
+func TestWriteSolidityConformance(t *testing.T) {
+	f, err := abi.NewSampleFixture[Spawn]("game.spawn input")
+	assert.NilError(t, err)
+	var sb strings.Builder
+	assert.NilError(t, abi.WriteSolidityConformance(&sb, "SpawnTest", []abi.Fixture{f}))
+
+	sol := sb.String()
+	assert.Contains(t, sol, "struct Spawn {\n    address Owner;\n    string Name;\n    uint256 Gold;\n"+
+		"    Position[] Positions;\n    string[] Tags;\n    bool Ready;\n}")
+	assert.Contains(t, sol, "struct Position {\n    int64 X;\n    int64 Y;\n}")
+	assert.Contains(t, sol, "function test_game_spawn_input() public pure {")
+	assert.Contains(t, sol, "Position[] memory v0 = new Position[](2);")
+	assert.Contains(t, sol, `keccak256(hex"`+f.Encoded+`")`)
+
+	// Fixtures read from disk don't have the Go value.
+	stripped := abi.Fixture{Name: f.Name, Type: f.Type, Encoded: f.Encoded}
+	assert.IsError(t, abi.WriteSolidityConformance(&sb, "SpawnTest", []abi.Fixture{stripped}))
+}
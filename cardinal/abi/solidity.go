@@ -0,0 +1,168 @@
+package abi
+
+import (
+	"fmt"
+	"io"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/rotisserie/eris"
+)
+
+// WriteSolidityConformance writes a Foundry test contract that checks that the structs of a contract encode the values
+// of the given fixtures to the same bytes as the world does. The generated file declares structs that mirror the Go
+// types, with the same field names. Replacing them with imports of the contract's own structs makes the test fail to
+// compile when a field is missing or renamed, and fail when fields are reordered.
+//
+// Only fixtures returned by NewFixture can be written, fixtures read from disk don't have the Go value.
+func WriteSolidityConformance(w io.Writer, contractName string, fixtures []Fixture) error {
+	g := &solidityGenerator{structs: map[string]reflect.Type{}}
+	var tests strings.Builder
+	for _, f := range fixtures {
+		if f.value == nil {
+			return eris.Errorf("fixture %q has no Go value", f.Name)
+		}
+		g.body.Reset()
+		g.vars = 0
+		expr, err := g.literal(reflect.ValueOf(f.value), "")
+		if err != nil {
+			return eris.Wrapf(err, "fixture %q", f.Name)
+		}
+		fmt.Fprintf(&tests, "\n    function test_%s() public pure {\n%s", solidityIdentifier(f.Name), g.body.String())
+		fmt.Fprintf(&tests, "        %s memory value = %s;\n", reflect.TypeOf(f.value).Name(), expr)
+		fmt.Fprintf(&tests, "        assert(keccak256(abi.encode(value)) == keccak256(hex\"%s\"));\n    }\n", f.Encoded)
+	}
+
+	var out strings.Builder
+	out.WriteString("// SPDX-License-Identifier: UNLICENSED\n")
+	out.WriteString("// Code generated by cardinal. DO NOT EDIT.\n")
+	out.WriteString("pragma solidity ^0.8.0;\n")
+	for _, name := range g.order {
+		fmt.Fprintf(&out, "\nstruct %s {\n", name)
+		rt := g.structs[name]
+		for i := 0; i < rt.NumField(); i++ {
+			field := rt.Field(i)
+			solType, err := solidityType(field.Type, field.Tag.Get(bigIntStructTag))
+			if err != nil {
+				return eris.Wrapf(err, "field %s.%s", name, field.Name)
+			}
+			fmt.Fprintf(&out, "    %s %s;\n", solType, field.Name)
+		}
+		out.WriteString("}\n")
+	}
+	fmt.Fprintf(&out, "\ncontract %s {%s}\n", contractName, tests.String())
+	_, err := io.WriteString(w, out.String())
+	return eris.Wrap(err, "")
+}
+
+// solidityGenerator builds Solidity expressions for Go values. Slices can't be written as literals, so they are
+// built in local variables, whose declarations are accumulated in body.
+type solidityGenerator struct {
+	structs map[string]reflect.Type
+	order   []string
+	body    strings.Builder
+	vars    int
+}
+
+func (g *solidityGenerator) literal(v reflect.Value, tag string) (string, error) {
+	rt := v.Type()
+	switch {
+	case rt == bigIntType:
+		return v.Interface().(*big.Int).String(), nil //nolint:errcheck // checked by the case
+	case rt == addressType:
+		return v.Interface().(common.Address).Hex(), nil //nolint:errcheck // checked by the case
+	case rt.Kind() == reflect.Struct:
+		if err := g.declareStruct(rt); err != nil {
+			return "", err
+		}
+		fields := make([]string, 0, rt.NumField())
+		for i := 0; i < rt.NumField(); i++ {
+			field := rt.Field(i)
+			expr, err := g.literal(v.Field(i), field.Tag.Get(bigIntStructTag))
+			if err != nil {
+				return "", err
+			}
+			fields = append(fields, field.Name+": "+expr)
+		}
+		return rt.Name() + "({" + strings.Join(fields, ", ") + "})", nil
+	case rt.Kind() == reflect.Slice:
+		solType, err := solidityType(rt, tag)
+		if err != nil {
+			return "", err
+		}
+		elems := make([]string, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			expr, err := g.literal(v.Index(i), tag)
+			if err != nil {
+				return "", err
+			}
+			elems = append(elems, expr)
+		}
+		name := "v" + strconv.Itoa(g.vars)
+		g.vars++
+		fmt.Fprintf(&g.body, "        %s memory %s = new %s(%d);\n", solType, name, solType, v.Len())
+		for i, expr := range elems {
+			fmt.Fprintf(&g.body, "        %s[%d] = %s;\n", name, i, expr)
+		}
+		return name, nil
+	case rt.Kind() == reflect.String:
+		return strconv.Quote(v.String()), nil
+	case rt.Kind() == reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case v.CanUint():
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case v.CanInt():
+		return strconv.FormatInt(v.Int(), 10), nil
+	default:
+		return "", eris.Errorf("unsupported type %s", rt)
+	}
+}
+
+// declareStruct adds the declaration of a struct type, and of the struct types of its fields, to the generated file.
+func (g *solidityGenerator) declareStruct(rt reflect.Type) error {
+	name := rt.Name()
+	if name == "" {
+		return eris.Errorf("anonymous struct %s can't be declared in Solidity", rt)
+	}
+	if existing, ok := g.structs[name]; ok {
+		if existing != rt {
+			return eris.Errorf("struct %s is declared by both %s and %s", name, existing.PkgPath(), rt.PkgPath())
+		}
+		return nil
+	}
+	g.structs[name] = rt
+	g.order = append(g.order, name)
+	return nil
+}
+
+// solidityType returns the Solidity type of a Go type, as getArgumentsForType does, with struct types named after the
+// Go struct.
+func solidityType(rt reflect.Type, tag string) (string, error) {
+	switch {
+	case rt == bigIntType || rt == addressType:
+		return goTypeToSolidityType(rt.String(), tag)
+	case rt.Kind() == reflect.Struct:
+		return rt.Name(), nil
+	case rt.Kind() == reflect.Slice:
+		inner, err := solidityType(rt.Elem(), tag)
+		if err != nil {
+			return "", err
+		}
+		return inner + "[]", nil
+	default:
+		return goTypeToSolidityType(rt.String(), tag)
+	}
+}
+
+// solidityIdentifier replaces the characters of a fixture name that are not valid in a Solidity identifier.
+func solidityIdentifier(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return '_'
+	}, name)
+}
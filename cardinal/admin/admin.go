@@ -0,0 +1,501 @@
+// Package admin serves the admin gRPC service, the control plane that the world CLI and ops tooling use to operate a
+// running shard: pausing and resuming the game loop, saving and rolling back to checkpoints, enabling and disabling
+// systems, updating config values, banning persona tags, listing and purging the namespaces of the worlds that share
+// the redis database, inspecting and draining the queue of pending transactions, and stepping through ticks system by
+// system with the step debugger, and profiling ticks. Every call must be authenticated with the admin token.
+package admin
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/rotisserie/eris"
+	zerolog "github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"pkg.world.dev/world-engine/cardinal/gamestate"
+	"pkg.world.dev/world-engine/cardinal/storage/redis"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/txpool"
+	adminv1 "pkg.world.dev/world-engine/rift/admin/v1"
+)
+
+const (
+	DefaultPort = "9040"
+
+	authorizationHeader = "authorization"
+	bearerPrefix        = "Bearer "
+)
+
+var (
+	ErrNotRunning       = errors.New("world is not running")
+	ErrSystemNotFound   = errors.New("system not found")
+	ErrUnknownConfigKey = errors.New("config key cannot be updated at runtime")
+	ErrInvalidValue     = errors.New("invalid value")
+	ErrNoToken          = errors.New("admin token must not be empty")
+	ErrMessageNotFound  = errors.New("message not found")
+	ErrDebuggerDisabled = errors.New("step debugger is not enabled")
+	ErrNotPaused        = errors.New("world is not paused")
+	ErrTickStopped      = errors.New("a tick is stopped at a breakpoint")
+	ErrNoTickStopped    = errors.New("no tick is stopped at a breakpoint")
+	ErrProfiling        = errors.New("ticks are already being profiled")
+)
+
+var _ adminv1.AdminServer = (*Server)(nil)
+
+// Provider is the set of World methods the admin server depends on.
+type Provider interface {
+	Namespace() string
+	CurrentTick() uint64
+
+	Pause() error
+	Resume() error
+	IsPaused() bool
+
+	SaveCheckpoint(name string) (gamestate.CheckpointInfo, error)
+	ListCheckpoints() ([]gamestate.CheckpointInfo, error)
+	DeleteCheckpoint(name string) error
+	RollbackToCheckpoint(name string) (gamestate.CheckpointInfo, error)
+
+	GetDisabledSystems() []string
+	EnableSystem(name string) error
+	DisableSystem(name string) error
+
+	UpdateConfig(key, value string) error
+
+	BanPersona(personaTag, reason string) error
+	UnbanPersona(personaTag string) error
+	ListBans() (map[string]string, error)
+
+	ListNamespaces() ([]redis.NamespaceInfo, error)
+	PurgeNamespace(namespace string, force bool) (uint64, error)
+
+	GetTxQueueStats() []TxQueueStat
+	DrainTxQueue(msgFullName string) ([]txpool.TxData, error)
+
+	SetBreakpoints(breakpoints []Breakpoint) error
+	Step() (DebugStatus, error)
+	Continue() (DebugStatus, error)
+	GetTickState() (DebugStatus, []EntityState, error)
+
+	ProfileTicks(ticks int) error
+	GetTickProfiles() (remaining int, profiles []TickProfile, err error)
+}
+
+// TxQueueStat is the number of pending transactions of a message.
+type TxQueueStat struct {
+	Message string
+	Pending int
+	// OldestAddedAt is the time at which the oldest pending transaction of the message was submitted.
+	OldestAddedAt time.Time
+}
+
+// Breakpoint stops a tick before or after a system.
+type Breakpoint struct {
+	System string
+	After  bool
+}
+
+// DebugStatus is the state of the step debugger.
+type DebugStatus struct {
+	// Tick is the tick that is stopped at a breakpoint, or the last tick that completed.
+	Tick uint64
+	// StoppedAt is the breakpoint at which the tick is stopped. It is nil if no tick is stopped.
+	StoppedAt *Breakpoint
+}
+
+// EntityState is an entity and its JSON encoded components, by component name.
+type EntityState struct {
+	ID         types.EntityID
+	Components map[string]json.RawMessage
+}
+
+// TickProfile holds the profiles of a tick.
+type TickProfile struct {
+	Tick     uint64
+	Duration time.Duration
+	// CPU is the pprof CPU profile of the tick.
+	CPU []byte
+	// AllocsBefore and AllocsAfter are the pprof allocation profiles at the start and at the end of the tick.
+	AllocsBefore []byte
+	AllocsAfter  []byte
+	// AllocBytes and Allocs are the number of bytes and objects that were allocated during the tick.
+	AllocBytes uint64
+	Allocs     uint64
+}
+
+type Server struct {
+	adminv1.UnimplementedAdminServer
+
+	provider   Provider
+	grpcServer *grpc.Server
+	port       string
+	token      string
+}
+
+// NewServer returns an admin server that only accepts calls that carry the given token.
+func NewServer(provider Provider, port, token string) *Server {
+	s := &Server{
+		provider: provider,
+		port:     port,
+		token:    token,
+	}
+	s.grpcServer = grpc.NewServer(grpc.UnaryInterceptor(s.authenticate))
+	adminv1.RegisterAdminServer(s.grpcServer, s)
+	return s
+}
+
+// Start serves the admin gRPC server. The server refuses to start without a token, since it would be impossible to
+// authenticate any call.
+func (s *Server) Start() error {
+	if s.token == "" {
+		return eris.Wrap(ErrNoToken, "")
+	}
+	listener, err := net.Listen("tcp", ":"+s.port)
+	if err != nil {
+		return eris.Wrapf(err, "error listening to port %s", s.port)
+	}
+	go func() {
+		err = eris.Wrap(s.grpcServer.Serve(listener), "error serving admin gRPC server")
+		if err != nil {
+			zerolog.Fatal().Err(err).Msg(eris.ToString(err, true))
+		}
+	}()
+	return nil
+}
+
+// Shutdown stops the gRPC server.
+func (s *Server) Shutdown() {
+	s.grpcServer.GracefulStop()
+}
+
+// authenticate rejects calls that do not carry the admin token in their "authorization" metadata.
+func (s *Server) authenticate(
+	ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler,
+) (any, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get(authorizationHeader)
+	if len(values) != 1 || !strings.HasPrefix(values[0], bearerPrefix) {
+		return nil, status.Error(codes.Unauthenticated, "missing admin token")
+	}
+	token := strings.TrimPrefix(values[0], bearerPrefix)
+	if subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) != 1 {
+		return nil, status.Error(codes.Unauthenticated, "invalid admin token")
+	}
+	return handler(ctx, req)
+}
+
+func (s *Server) GetStatus(context.Context, *adminv1.GetStatusRequest) (*adminv1.GetStatusResponse, error) {
+	return &adminv1.GetStatusResponse{
+		Namespace:       s.provider.Namespace(),
+		Tick:            s.provider.CurrentTick(),
+		Paused:          s.provider.IsPaused(),
+		DisabledSystems: s.provider.GetDisabledSystems(),
+	}, nil
+}
+
+func (s *Server) Pause(context.Context, *adminv1.PauseRequest) (*adminv1.PauseResponse, error) {
+	if err := s.provider.Pause(); err != nil {
+		return nil, toStatus(err)
+	}
+	return &adminv1.PauseResponse{Tick: s.provider.CurrentTick()}, nil
+}
+
+func (s *Server) Resume(context.Context, *adminv1.ResumeRequest) (*adminv1.ResumeResponse, error) {
+	if err := s.provider.Resume(); err != nil {
+		return nil, toStatus(err)
+	}
+	return &adminv1.ResumeResponse{Tick: s.provider.CurrentTick()}, nil
+}
+
+func (s *Server) Snapshot(_ context.Context, req *adminv1.SnapshotRequest) (*adminv1.SnapshotResponse, error) {
+	info, err := s.provider.SaveCheckpoint(req.GetCheckpoint())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &adminv1.SnapshotResponse{Checkpoint: toCheckpoint(info)}, nil
+}
+
+func (s *Server) ListCheckpoints(
+	context.Context, *adminv1.ListCheckpointsRequest,
+) (*adminv1.ListCheckpointsResponse, error) {
+	infos, err := s.provider.ListCheckpoints()
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	res := &adminv1.ListCheckpointsResponse{Checkpoints: make([]*adminv1.Checkpoint, 0, len(infos))}
+	for _, info := range infos {
+		res.Checkpoints = append(res.Checkpoints, toCheckpoint(info))
+	}
+	return res, nil
+}
+
+func (s *Server) DeleteCheckpoint(
+	_ context.Context, req *adminv1.DeleteCheckpointRequest,
+) (*adminv1.DeleteCheckpointResponse, error) {
+	if err := s.provider.DeleteCheckpoint(req.GetCheckpoint()); err != nil {
+		return nil, toStatus(err)
+	}
+	return &adminv1.DeleteCheckpointResponse{}, nil
+}
+
+func (s *Server) Rollback(_ context.Context, req *adminv1.RollbackRequest) (*adminv1.RollbackResponse, error) {
+	info, err := s.provider.RollbackToCheckpoint(req.GetCheckpoint())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &adminv1.RollbackResponse{Tick: info.Tick}, nil
+}
+
+func (s *Server) SetSystemEnabled(
+	_ context.Context, req *adminv1.SetSystemEnabledRequest,
+) (*adminv1.SetSystemEnabledResponse, error) {
+	var err error
+	if req.GetEnabled() {
+		err = s.provider.EnableSystem(req.GetSystemName())
+	} else {
+		err = s.provider.DisableSystem(req.GetSystemName())
+	}
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &adminv1.SetSystemEnabledResponse{}, nil
+}
+
+func (s *Server) UpdateConfig(
+	_ context.Context, req *adminv1.UpdateConfigRequest,
+) (*adminv1.UpdateConfigResponse, error) {
+	if err := s.provider.UpdateConfig(req.GetKey(), req.GetValue()); err != nil {
+		return nil, toStatus(err)
+	}
+	zerolog.Info().Str("key", req.GetKey()).Str("value", req.GetValue()).Msg("config updated by admin")
+	return &adminv1.UpdateConfigResponse{}, nil
+}
+
+func (s *Server) BanPersona(_ context.Context, req *adminv1.BanPersonaRequest) (*adminv1.BanPersonaResponse, error) {
+	if req.GetBan().GetPersonaTag() == "" {
+		return nil, status.Error(codes.InvalidArgument, "persona tag is required")
+	}
+	if err := s.provider.BanPersona(req.GetBan().GetPersonaTag(), req.GetBan().GetReason()); err != nil {
+		return nil, toStatus(err)
+	}
+	return &adminv1.BanPersonaResponse{}, nil
+}
+
+func (s *Server) UnbanPersona(
+	_ context.Context, req *adminv1.UnbanPersonaRequest,
+) (*adminv1.UnbanPersonaResponse, error) {
+	if err := s.provider.UnbanPersona(req.GetPersonaTag()); err != nil {
+		return nil, toStatus(err)
+	}
+	return &adminv1.UnbanPersonaResponse{}, nil
+}
+
+func (s *Server) ListBans(context.Context, *adminv1.ListBansRequest) (*adminv1.ListBansResponse, error) {
+	bans, err := s.provider.ListBans()
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	res := &adminv1.ListBansResponse{Bans: make([]*adminv1.Ban, 0, len(bans))}
+	for personaTag, reason := range bans {
+		res.Bans = append(res.Bans, &adminv1.Ban{PersonaTag: personaTag, Reason: reason})
+	}
+	slices.SortFunc(res.Bans, func(a, b *adminv1.Ban) int { return strings.Compare(a.GetPersonaTag(), b.GetPersonaTag()) })
+	return res, nil
+}
+
+func (s *Server) ListNamespaces(
+	context.Context, *adminv1.ListNamespacesRequest,
+) (*adminv1.ListNamespacesResponse, error) {
+	namespaces, err := s.provider.ListNamespaces()
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	res := &adminv1.ListNamespacesResponse{Namespaces: make([]*adminv1.Namespace, 0, len(namespaces))}
+	for _, ns := range namespaces {
+		res.Namespaces = append(res.Namespaces, &adminv1.Namespace{Name: ns.Name, Keys: ns.Keys, Owner: ns.Owner})
+	}
+	return res, nil
+}
+
+func (s *Server) PurgeNamespace(
+	_ context.Context, req *adminv1.PurgeNamespaceRequest,
+) (*adminv1.PurgeNamespaceResponse, error) {
+	if req.GetNamespace() == "" {
+		return nil, status.Error(codes.InvalidArgument, "namespace is required")
+	}
+	keys, err := s.provider.PurgeNamespace(req.GetNamespace(), req.GetForce())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &adminv1.PurgeNamespaceResponse{Keys: keys}, nil
+}
+
+func (s *Server) GetTxQueueStats(
+	context.Context, *adminv1.GetTxQueueStatsRequest,
+) (*adminv1.GetTxQueueStatsResponse, error) {
+	stats := s.provider.GetTxQueueStats()
+	res := &adminv1.GetTxQueueStatsResponse{Stats: make([]*adminv1.TxQueueStat, 0, len(stats))}
+	now := time.Now()
+	for _, stat := range stats {
+		res.Pending += uint64(stat.Pending)
+		res.Stats = append(res.Stats, &adminv1.TxQueueStat{
+			Message:            stat.Message,
+			Pending:            uint64(stat.Pending),
+			OldestPendingAgeMs: now.Sub(stat.OldestAddedAt).Milliseconds(),
+		})
+	}
+	slices.SortFunc(res.Stats, func(a, b *adminv1.TxQueueStat) int {
+		return strings.Compare(a.GetMessage(), b.GetMessage())
+	})
+	return res, nil
+}
+
+func (s *Server) DrainTxQueue(
+	_ context.Context, req *adminv1.DrainTxQueueRequest,
+) (*adminv1.DrainTxQueueResponse, error) {
+	if req.GetMessage() == "" {
+		return nil, status.Error(codes.InvalidArgument, "message is required")
+	}
+	txs, err := s.provider.DrainTxQueue(req.GetMessage())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	res := &adminv1.DrainTxQueueResponse{Dropped: uint64(len(txs))}
+	if !req.GetExport() {
+		return res, nil
+	}
+	res.Transactions = make([]*adminv1.PendingTransaction, 0, len(txs))
+	for _, tx := range txs {
+		bz, err := tx.Tx.Marshal()
+		if err != nil {
+			return nil, toStatus(err)
+		}
+		res.Transactions = append(res.Transactions, &adminv1.PendingTransaction{
+			TxHash:      string(tx.TxHash),
+			Transaction: bz,
+		})
+	}
+	return res, nil
+}
+
+func (s *Server) SetBreakpoints(
+	_ context.Context, req *adminv1.SetBreakpointsRequest,
+) (*adminv1.SetBreakpointsResponse, error) {
+	breakpoints := make([]Breakpoint, 0, len(req.GetBreakpoints()))
+	for _, bp := range req.GetBreakpoints() {
+		breakpoints = append(breakpoints, Breakpoint{System: bp.GetSystemName(), After: bp.GetAfter()})
+	}
+	if err := s.provider.SetBreakpoints(breakpoints); err != nil {
+		return nil, toStatus(err)
+	}
+	return &adminv1.SetBreakpointsResponse{}, nil
+}
+
+func (s *Server) Step(context.Context, *adminv1.StepRequest) (*adminv1.StepResponse, error) {
+	st, err := s.provider.Step()
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &adminv1.StepResponse{Tick: st.Tick, StoppedAt: toBreakpoint(st.StoppedAt)}, nil
+}
+
+func (s *Server) Continue(context.Context, *adminv1.ContinueRequest) (*adminv1.ContinueResponse, error) {
+	st, err := s.provider.Continue()
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &adminv1.ContinueResponse{Tick: st.Tick, StoppedAt: toBreakpoint(st.StoppedAt)}, nil
+}
+
+func (s *Server) GetTickState(context.Context, *adminv1.GetTickStateRequest) (*adminv1.GetTickStateResponse, error) {
+	st, entities, err := s.provider.GetTickState()
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	res := &adminv1.GetTickStateResponse{
+		Tick:      st.Tick,
+		StoppedAt: toBreakpoint(st.StoppedAt),
+		Entities:  make([]*adminv1.EntityState, 0, len(entities)),
+	}
+	for _, entity := range entities {
+		components := make(map[string][]byte, len(entity.Components))
+		for name, data := range entity.Components {
+			components[name] = data
+		}
+		res.Entities = append(res.Entities, &adminv1.EntityState{Id: uint64(entity.ID), Components: components})
+	}
+	return res, nil
+}
+
+func (s *Server) ProfileTicks(
+	_ context.Context, req *adminv1.ProfileTicksRequest,
+) (*adminv1.ProfileTicksResponse, error) {
+	if err := s.provider.ProfileTicks(int(req.GetTicks())); err != nil {
+		return nil, toStatus(err)
+	}
+	return &adminv1.ProfileTicksResponse{}, nil
+}
+
+func (s *Server) GetTickProfiles(
+	context.Context, *adminv1.GetTickProfilesRequest,
+) (*adminv1.GetTickProfilesResponse, error) {
+	remaining, profiles, err := s.provider.GetTickProfiles()
+	res := &adminv1.GetTickProfilesResponse{
+		RemainingTicks: uint32(remaining),
+		Profiles:       make([]*adminv1.TickProfile, 0, len(profiles)),
+	}
+	if err != nil {
+		res.Error = err.Error()
+	}
+	for _, p := range profiles {
+		res.Profiles = append(res.Profiles, &adminv1.TickProfile{
+			Tick:         p.Tick,
+			DurationUs:   p.Duration.Microseconds(),
+			Cpu:          p.CPU,
+			AllocsBefore: p.AllocsBefore,
+			AllocsAfter:  p.AllocsAfter,
+			AllocBytes:   p.AllocBytes,
+			Allocs:       p.Allocs,
+		})
+	}
+	return res, nil
+}
+
+func toBreakpoint(bp *Breakpoint) *adminv1.Breakpoint {
+	if bp == nil {
+		return nil
+	}
+	return &adminv1.Breakpoint{SystemName: bp.System, After: bp.After}
+}
+
+func toCheckpoint(info gamestate.CheckpointInfo) *adminv1.Checkpoint {
+	return &adminv1.Checkpoint{Name: info.Name, Tick: info.Tick}
+}
+
+// toStatus converts the errors returned by the provider into gRPC status errors.
+func toStatus(err error) error {
+	code := codes.Internal
+	switch {
+	case errors.Is(err, gamestate.ErrCheckpointNotFound), errors.Is(err, ErrSystemNotFound),
+		errors.Is(err, ErrMessageNotFound):
+		code = codes.NotFound
+	case errors.Is(err, gamestate.ErrInvalidCheckpoint), errors.Is(err, ErrUnknownConfigKey),
+		errors.Is(err, ErrInvalidValue):
+		code = codes.InvalidArgument
+	case errors.Is(err, ErrNotRunning), errors.Is(err, gamestate.ErrPendingChanges),
+		errors.Is(err, redis.ErrNamespaceInUse), errors.Is(err, ErrDebuggerDisabled), errors.Is(err, ErrNotPaused),
+		errors.Is(err, ErrTickStopped), errors.Is(err, ErrNoTickStopped), errors.Is(err, ErrProfiling):
+		code = codes.FailedPrecondition
+	}
+	return status.Error(code, err.Error())
+}
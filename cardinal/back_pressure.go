@@ -0,0 +1,70 @@
+package cardinal
+
+import (
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"pkg.world.dev/world-engine/cardinal/statsd"
+	"pkg.world.dev/world-engine/cardinal/types"
+)
+
+// BackPressure limits how much work the world accepts from transaction submitters. When a limit is exceeded, the HTTP
+// server, Nakama and the EVM are told to retry their transactions later, instead of the transactions being queued
+// without bound. A zero value for any field disables that particular limit.
+type BackPressure struct {
+	// MaxQueuedTxs is the number of transactions waiting for the next tick at which new transactions are rejected.
+	// Transactions that are submitted at the same time are checked against the same count, so the queue can briefly
+	// hold a few more transactions than the limit.
+	MaxQueuedTxs int
+	// MaxTickLag is how far the ticks can fall behind their schedule before new transactions are rejected, e.g.
+	// because systems take longer than the tick interval.
+	MaxTickLag time.Duration
+}
+
+// CheckBackPressure returns a RetryAfter if the world is not accepting new transactions because a limit of its
+// BackPressure is exceeded, or nil if it is accepting them. Transactions that are rejected because the queue is full
+// can be retried after the next tick, which empties the queue. Transactions that are rejected because the ticks are
+// behind schedule can be retried once the world had the time to catch up, which is the lag itself.
+//
+// While the storage circuit breaker is open, new transactions are rejected until the next retry of the failed tick.
+func (w *World) CheckBackPressure() *types.RetryAfter {
+	if health := w.StorageHealth(); health.Outage {
+		return w.rejectForBackPressure(&types.RetryAfter{
+			Reason:    types.RetryReasonStorageOutage,
+			After:     max(time.Until(health.NextRetry), w.tickClock.interval),
+			QueuedTxs: w.txPool.GetAmountOfTxs(),
+		})
+	}
+	bp := w.backPressure
+	if bp.MaxQueuedTxs <= 0 && bp.MaxTickLag <= 0 {
+		return nil
+	}
+	res := &types.RetryAfter{
+		After:        w.tickClock.interval,
+		QueuedTxs:    w.txPool.GetAmountOfTxs(),
+		MaxQueuedTxs: bp.MaxQueuedTxs,
+		TickLag:      w.tickClock.lag(time.Now()),
+		MaxTickLag:   bp.MaxTickLag,
+	}
+	if bp.MaxQueuedTxs > 0 {
+		res.QueueFill = float64(res.QueuedTxs) / float64(bp.MaxQueuedTxs)
+	}
+	switch {
+	case bp.MaxTickLag > 0 && res.TickLag > bp.MaxTickLag:
+		res.Reason = types.RetryReasonTickLag
+		res.After = max(res.After, res.TickLag)
+	case bp.MaxQueuedTxs > 0 && res.QueuedTxs >= bp.MaxQueuedTxs:
+		res.Reason = types.RetryReasonQueueFull
+	default:
+		return nil
+	}
+	return w.rejectForBackPressure(res)
+}
+
+func (w *World) rejectForBackPressure(res *types.RetryAfter) *types.RetryAfter {
+	if err := statsd.Client().Count("tx_back_pressure", 1, []string{"reason:" + res.Reason}, 1); err != nil {
+		log.Warn().Err(err).Msg("failed to emit back pressure")
+	}
+	return res
+}
@@ -0,0 +1,291 @@
+package benchmark_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/rs/zerolog"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/search/filter"
+	"pkg.world.dev/world-engine/cardinal/testutils"
+	"pkg.world.dev/world-engine/cardinal/types"
+)
+
+// The W components are added to the health of entities to make them as wide as the widest entities of games.
+type W01 struct{ Value int }
+
+func (W01) Name() string { return "w01" }
+
+type W02 struct{ Value int }
+
+func (W02) Name() string { return "w02" }
+
+type W03 struct{ Value int }
+
+func (W03) Name() string { return "w03" }
+
+type W04 struct{ Value int }
+
+func (W04) Name() string { return "w04" }
+
+type W05 struct{ Value int }
+
+func (W05) Name() string { return "w05" }
+
+type W06 struct{ Value int }
+
+func (W06) Name() string { return "w06" }
+
+type W07 struct{ Value int }
+
+func (W07) Name() string { return "w07" }
+
+type W08 struct{ Value int }
+
+func (W08) Name() string { return "w08" }
+
+type W09 struct{ Value int }
+
+func (W09) Name() string { return "w09" }
+
+type W10 struct{ Value int }
+
+func (W10) Name() string { return "w10" }
+
+type W11 struct{ Value int }
+
+func (W11) Name() string { return "w11" }
+
+type W12 struct{ Value int }
+
+func (W12) Name() string { return "w12" }
+
+type W13 struct{ Value int }
+
+func (W13) Name() string { return "w13" }
+
+type W14 struct{ Value int }
+
+func (W14) Name() string { return "w14" }
+
+type W15 struct{ Value int }
+
+func (W15) Name() string { return "w15" }
+
+type W16 struct{ Value int }
+
+func (W16) Name() string { return "w16" }
+
+type W17 struct{ Value int }
+
+func (W17) Name() string { return "w17" }
+
+type W18 struct{ Value int }
+
+func (W18) Name() string { return "w18" }
+
+type W19 struct{ Value int }
+
+func (W19) Name() string { return "w19" }
+
+type W20 struct{ Value int }
+
+func (W20) Name() string { return "w20" }
+
+type W21 struct{ Value int }
+
+func (W21) Name() string { return "w21" }
+
+type W22 struct{ Value int }
+
+func (W22) Name() string { return "w22" }
+
+type W23 struct{ Value int }
+
+func (W23) Name() string { return "w23" }
+
+type W24 struct{ Value int }
+
+func (W24) Name() string { return "w24" }
+
+type W25 struct{ Value int }
+
+func (W25) Name() string { return "w25" }
+
+type W26 struct{ Value int }
+
+func (W26) Name() string { return "w26" }
+
+type W27 struct{ Value int }
+
+func (W27) Name() string { return "w27" }
+
+type W28 struct{ Value int }
+
+func (W28) Name() string { return "w28" }
+
+type W29 struct{ Value int }
+
+func (W29) Name() string { return "w29" }
+
+// wideComponents are the components that layouts add to Health.
+var wideComponents = []types.Component{W01{}, W02{}, W03{}, W04{}, W05{}, W06{}, W07{}, W08{}, W09{}, W10{}, W11{}, W12{}, W13{}, W14{}, W15{}, W16{}, W17{}, W18{}, W19{}, W20{}, W21{}, W22{}, W23{}, W24{}, W25{}, W26{}, W27{}, W28{}, W29{}}
+
+// layout is a way of spreading entities with a Health component over archetypes.
+type layout struct {
+	name string
+	// components returns the components of the i-th entity.
+	components func(i int) []types.Component
+}
+
+var layouts = []layout{
+	{
+		name:       "narrow",
+		components: func(int) []types.Component { return []types.Component{Health{}} },
+	},
+	{
+		// Every entity has 30 components, like an entity that holds all the state of a player
+		name: "wide",
+		components: func(int) []types.Component {
+			return append([]types.Component{Health{}}, wideComponents...)
+		},
+	},
+	{
+		// Entities have 2 components, but are spread over 29 archetypes
+		name: "fragmented",
+		components: func(i int) []types.Component {
+			return []types.Component{Health{}, wideComponents[i%len(wideComponents)]}
+		},
+	},
+}
+
+// setupLayoutWorld creates a world with numOfEntities entities of the layout, and returns it with their IDs.
+func setupLayoutWorld(t testing.TB, l layout, numOfEntities int) (*testutils.TestFixture, []types.EntityID) {
+	tf := testutils.NewTestFixture(t, nil)
+	world := tf.World
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+	assert.NilError(t, errors.Join(
+		cardinal.RegisterComponent[Health](world),
+		cardinal.RegisterComponent[W01](world),
+		cardinal.RegisterComponent[W02](world),
+		cardinal.RegisterComponent[W03](world),
+		cardinal.RegisterComponent[W04](world),
+		cardinal.RegisterComponent[W05](world),
+		cardinal.RegisterComponent[W06](world),
+		cardinal.RegisterComponent[W07](world),
+		cardinal.RegisterComponent[W08](world),
+		cardinal.RegisterComponent[W09](world),
+		cardinal.RegisterComponent[W10](world),
+		cardinal.RegisterComponent[W11](world),
+		cardinal.RegisterComponent[W12](world),
+		cardinal.RegisterComponent[W13](world),
+		cardinal.RegisterComponent[W14](world),
+		cardinal.RegisterComponent[W15](world),
+		cardinal.RegisterComponent[W16](world),
+		cardinal.RegisterComponent[W17](world),
+		cardinal.RegisterComponent[W18](world),
+		cardinal.RegisterComponent[W19](world),
+		cardinal.RegisterComponent[W20](world),
+		cardinal.RegisterComponent[W21](world),
+		cardinal.RegisterComponent[W22](world),
+		cardinal.RegisterComponent[W23](world),
+		cardinal.RegisterComponent[W24](world),
+		cardinal.RegisterComponent[W25](world),
+		cardinal.RegisterComponent[W26](world),
+		cardinal.RegisterComponent[W27](world),
+		cardinal.RegisterComponent[W28](world),
+		cardinal.RegisterComponent[W29](world),
+	))
+	tf.StartWorld()
+
+	wCtx := cardinal.NewWorldContext(world)
+	ids := make([]types.EntityID, 0, numOfEntities)
+	for i := 0; i < numOfEntities; i++ {
+		id, err := cardinal.Create(wCtx, l.components(i)...)
+		assert.NilError(t, err)
+		ids = append(ids, id)
+	}
+	// Perform a game tick to ensure the newly created entities have been committed to the DB
+	tf.DoTick()
+	return tf, ids
+}
+
+func BenchmarkLayout_Create(b *testing.B) {
+	for _, l := range layouts {
+		b.Run(l.name, func(b *testing.B) {
+			tf, _ := setupLayoutWorld(b, l, 0)
+			wCtx := cardinal.NewWorldContext(tf.World)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_, err := cardinal.Create(wCtx, l.components(i)...)
+				assert.NilError(b, err)
+			}
+		})
+	}
+}
+
+func BenchmarkLayout_GetComponent(b *testing.B) {
+	for _, l := range layouts {
+		b.Run(l.name, func(b *testing.B) {
+			tf, ids := setupLayoutWorld(b, l, 1000)
+			wCtx := cardinal.NewWorldContext(tf.World)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_, err := cardinal.GetComponent[Health](wCtx, ids[i%len(ids)])
+				assert.NilError(b, err)
+			}
+		})
+	}
+}
+
+func BenchmarkLayout_SetComponent(b *testing.B) {
+	for _, l := range layouts {
+		b.Run(l.name, func(b *testing.B) {
+			tf, ids := setupLayoutWorld(b, l, 1000)
+			wCtx := cardinal.NewWorldContext(tf.World)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				assert.NilError(b, cardinal.SetComponent[Health](wCtx, ids[i%len(ids)], &Health{Value: i}))
+			}
+		})
+	}
+}
+
+// BenchmarkLayout_Search ticks a system that reads the health of every entity.
+func BenchmarkLayout_Search(b *testing.B) {
+	for _, l := range layouts {
+		for numOfEntities := 10; numOfEntities <= 1000; numOfEntities *= 10 {
+			b.Run(fmt.Sprintf("%s/%d entities", l.name, numOfEntities), func(b *testing.B) {
+				tf, _ := setupLayoutWorld(b, l, numOfEntities)
+				q := cardinal.NewSearch().Entity(filter.Contains(filter.Component[Health]()))
+				wCtx := cardinal.NewWorldContext(tf.World)
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					err := q.Each(wCtx, func(id types.EntityID) bool {
+						_, err := cardinal.GetComponent[Health](wCtx, id)
+						assert.NilError(b, err)
+						return true
+					})
+					assert.NilError(b, err)
+				}
+			})
+		}
+	}
+}
+
+// TestLayoutReport checks that the layout report tells the layouts apart.
+func TestLayoutReport(t *testing.T) {
+	wantArchetypes := map[string]int{"narrow": 1, "wide": 1, "fragmented": len(wideComponents)}
+	wantWide := map[string]int{"narrow": 0, "wide": 1, "fragmented": 0}
+	for _, l := range layouts {
+		tf, _ := setupLayoutWorld(t, l, 100)
+		report, err := tf.World.LayoutReport()
+		assert.NilError(t, err)
+		assert.Equal(t, 100, report.Entities)
+		assert.Equal(t, wantArchetypes[l.name], report.Archetypes, l.name)
+		assert.Equal(t, wantWide[l.name], len(report.WideArchetypes), l.name)
+	}
+}
@@ -3,12 +3,15 @@ package cardinal
 import (
 	"errors"
 	"reflect"
+	"slices"
 	"strconv"
 
 	"github.com/rotisserie/eris"
+	"github.com/rs/zerolog"
 
 	"pkg.world.dev/world-engine/cardinal/component"
 	"pkg.world.dev/world-engine/cardinal/iterators"
+	ecslog "pkg.world.dev/world-engine/cardinal/log"
 	"pkg.world.dev/world-engine/cardinal/message"
 	"pkg.world.dev/world-engine/cardinal/query"
 	"pkg.world.dev/world-engine/cardinal/search"
@@ -61,6 +64,14 @@ func FilterFunction[T types.Component](f func(comp T) bool) func(ctx engine.Cont
 	return search.ComponentFilter[T](f)
 }
 
+// FilterFieldsFunction is like FilterFunction, but only decodes the given fields of the component, e.g. the X and Y
+// fields of a Position component, so that searches over many entities don't decode fields that the filter never reads.
+func FilterFieldsFunction[T types.Component](
+	fields []string, f func(comp T) bool,
+) func(ctx engine.Context, id types.EntityID) (bool, error) {
+	return search.ComponentFieldsFilter[T](fields, f)
+}
+
 func RegisterSystems(w *World, sys ...System) error {
 	if w.worldStage.Current() != worldstage.Init {
 		return eris.Errorf(
@@ -69,7 +80,7 @@ func RegisterSystems(w *World, sys ...System) error {
 			worldstage.Init,
 		)
 	}
-	return w.SystemManager.registerSystems(false, sys...)
+	return w.SystemManager.registerSystems(false, everyTick, sys...)
 }
 
 func RegisterInitSystems(w *World, sys ...System) error {
@@ -80,10 +91,10 @@ func RegisterInitSystems(w *World, sys ...System) error {
 			worldstage.Init,
 		)
 	}
-	return w.SystemManager.registerSystems(true, sys...)
+	return w.SystemManager.registerSystems(true, everyTick, sys...)
 }
 
-func RegisterComponent[T types.Component](w *World) error {
+func RegisterComponent[T types.Component](w *World, opts ...component.Option[T]) error {
 	if w.worldStage.Current() != worldstage.Init {
 		return eris.Errorf(
 			"world state is %s, expected %s to register component",
@@ -92,7 +103,13 @@ func RegisterComponent[T types.Component](w *World) error {
 		)
 	}
 
-	compMetadata, err := component.NewComponentMetadata[T]()
+	// The codec of the world comes first so that it can be overridden by the options of the component.
+	opts = append([]component.Option[T]{component.WithCodec[T](w.componentCodec)}, opts...)
+	// Encryption comes last so that it applies to whichever codec the component uses.
+	if w.componentEncryption != nil {
+		opts = append(opts, component.WithEncryption[T](w.componentEncryption))
+	}
+	compMetadata, err := component.NewComponentMetadata[T](opts...)
 	if err != nil {
 		return err
 	}
@@ -105,8 +122,8 @@ func RegisterComponent[T types.Component](w *World) error {
 	return nil
 }
 
-func MustRegisterComponent[T types.Component](w *World) {
-	err := RegisterComponent[T](w)
+func MustRegisterComponent[T types.Component](w *World, opts ...component.Option[T]) {
+	err := RegisterComponent[T](w, opts...)
 	if err != nil {
 		panic(err)
 	}
@@ -128,6 +145,13 @@ func EachMessage[In any, Out any](wCtx engine.Context, fn func(message.TxData[In
 	return nil
 }
 
+// EntityLogger returns the logger of the context tagged with the given entity. In a system, the logger is also tagged
+// with the tick and the system, and, in the function passed to EachMessage, with the hash and the persona of the
+// transaction, so that the logs about an entity can be correlated with what changed it.
+func EntityLogger(wCtx engine.Context, id types.EntityID) *zerolog.Logger {
+	return ecslog.CreateEntityLogger(wCtx.Logger(), id)
+}
+
 // RegisterMessage registers a message to the world. Cardinal will automatically set up HTTP routes that map to each
 // registered message. Message URLs are take the form of "group.name". A default group, "game", is used
 // unless the WithCustomMessageGroup option is used. Example: game.throw-rock
@@ -175,7 +199,8 @@ func RegisterQuery[Request any, Reply any](
 }
 
 // Create creates a single entity in the world, and returns the id of the newly created entity.
-// At least 1 component must be provided.
+// At least 1 component must be provided. Components that are provided with their zero value are initialized with their
+// default value, see types.ComponentDefaulter.
 func Create(wCtx engine.Context, components ...types.Component) (_ types.EntityID, err error) {
 	// We don't handle panics here because we let CreateMany handle it for us
 	entityIDs, err := CreateMany(wCtx, 1, components...)
@@ -186,10 +211,18 @@ func Create(wCtx engine.Context, components ...types.Component) (_ types.EntityI
 }
 
 // CreateMany creates multiple entities in the world, and returns the slice of ids for the newly created
-// entities. At least 1 component must be provided.
+// entities. At least 1 component must be provided. Components that are provided with their zero value are initialized
+// with their default value, see types.ComponentDefaulter.
 func CreateMany(wCtx engine.Context, num int, components ...types.Component) (entityIDs []types.EntityID, err error) {
 	defer func() { panicOnFatalError(wCtx, err) }()
+	return createMany(wCtx, "", num, components...)
+}
 
+// createMany creates the entities after reserving them against the world's entity quota. The reservation is
+// accounted to the given persona if it is not empty.
+func createMany(
+	wCtx engine.Context, personaTag string, num int, components ...types.Component,
+) (entityIDs []types.EntityID, err error) {
 	// Error if the context is read only
 	if wCtx.IsReadOnly() {
 		return nil, ErrEntityMutationOnReadOnly
@@ -208,24 +241,37 @@ func CreateMany(wCtx engine.Context, num int, components ...types.Component) (en
 		}
 		acc = append(acc, c)
 	}
+	return createEntities(wCtx, personaTag, num, acc, withDefaultValues(acc, components))
+}
+
+// createEntities creates the entities with the given components, whose metadata is at the same index in metadata.
+func createEntities(
+	wCtx engine.Context, personaTag string, num int, metadata []types.ComponentMetadata, components []types.Component,
+) (entityIDs []types.EntityID, err error) {
+	if err = wCtx.ReserveEntityQuota(personaTag, num, num*len(metadata)); err != nil {
+		return nil, err
+	}
 
-	// Create the entities
-	entityIDs, err = wCtx.StoreManager().CreateManyEntities(num, acc...)
+	// Create the entities. The store sorts the components it is given, so it gets a copy to keep metadata in the same
+	// order as components.
+	entityIDs, err = wCtx.StoreManager().CreateManyEntities(num, slices.Clone(metadata)...)
 	if err != nil {
+		return nil, withCleanup(err, wCtx.CancelEntityQuota(num, num*len(metadata)))
+	}
+
+	// Index the components, and undo the creation if another entity already has one of their keys
+	if err = indexNewEntities(wCtx, entityIDs, metadata, components); err != nil {
 		return nil, err
 	}
 
 	// Store the components for the entities
 	for _, id := range entityIDs {
-		for _, comp := range components {
-			var c types.ComponentMetadata
-			c, err = wCtx.GetComponentByName(comp.Name())
+		for i, comp := range components {
+			err = wCtx.StoreManager().SetComponentForEntity(metadata[i], id, comp)
 			if err != nil {
-				return nil, eris.Wrap(err, "failed to create entity because component is not registered")
+				return nil, err
 			}
-
-			err = wCtx.StoreManager().SetComponentForEntity(c, id, comp)
-			if err != nil {
+			if err = wCtx.TrackComponentChange(metadata[i], id, true); err != nil {
 				return nil, err
 			}
 		}
@@ -234,6 +280,126 @@ func CreateMany(wCtx engine.Context, num int, components ...types.Component) (en
 	return entityIDs, nil
 }
 
+// indexNewEntities adds the components of newly created entities to their unique indexes. If one of them violates an
+// index, the entities are removed again.
+func indexNewEntities(
+	wCtx engine.Context, entityIDs []types.EntityID, metadata []types.ComponentMetadata, components []types.Component,
+) error {
+	var err error
+	for _, id := range entityIDs {
+		for i, comp := range components {
+			if err = wCtx.IndexComponent(metadata[i], id, comp); err != nil {
+				break
+			}
+		}
+		if err != nil {
+			break
+		}
+	}
+	if err == nil {
+		return nil
+	}
+	return withCleanup(err, undoCreate(wCtx, entityIDs))
+}
+
+// undoCreate removes entities that were just created with the same components, along with their index entries, and
+// gives back the entity quota that was reserved for them.
+func undoCreate(wCtx engine.Context, entityIDs []types.EntityID) error {
+	if len(entityIDs) == 0 {
+		return nil
+	}
+	metadata, err := wCtx.StoreReader().GetComponentTypesForEntity(entityIDs[0])
+	if err != nil {
+		return err
+	}
+	errs := []error{wCtx.CancelEntityQuota(len(entityIDs), len(entityIDs)*len(metadata))}
+	for _, id := range entityIDs {
+		for _, c := range metadata {
+			errs = append(errs, wCtx.IndexComponent(c, id, nil))
+		}
+		errs = append(errs, wCtx.StoreManager().RemoveEntity(id))
+	}
+	return errors.Join(errs...)
+}
+
+// CloneEntity creates a new entity with a deep copy of every component of the given entity, e.g. to spawn variations
+// of a template entity. The clone doesn't share slices, maps or pointers with the original entity, so changing one
+// doesn't change the other. Overrides replace the components of the same type on the clone, and components that the
+// original entity doesn't have are added to the clone.
+func CloneEntity(wCtx engine.Context, id types.EntityID, overrides ...types.Component) (_ types.EntityID, err error) {
+	defer func() { panicOnFatalError(wCtx, err) }()
+
+	if wCtx.IsReadOnly() {
+		return 0, ErrEntityMutationOnReadOnly
+	}
+
+	if !wCtx.IsWorldReady() {
+		return 0, ErrEntitiesCreatedBeforeReady
+	}
+
+	metadata, err := wCtx.StoreReader().GetComponentTypesForEntity(id)
+	if err != nil {
+		return 0, err
+	}
+	// The components are copied through their encoding, which is also how they are persisted.
+	metadata = slices.Clone(metadata)
+	encoded := make([][]byte, len(metadata))
+	for i, c := range metadata {
+		if encoded[i], err = wCtx.StoreReader().GetComponentForEntityInRawJSON(c, id); err != nil {
+			return 0, err
+		}
+	}
+	for _, override := range overrides {
+		c, err := wCtx.GetComponentByName(override.Name())
+		if err != nil {
+			return 0, eris.Wrap(err, "failed to clone entity because component is not registered")
+		}
+		bz, err := c.Encode(override)
+		if err != nil {
+			return 0, err
+		}
+		i := slices.IndexFunc(metadata, func(m types.ComponentMetadata) bool { return m.ID() == c.ID() })
+		if i == -1 {
+			metadata = append(metadata, c)
+			encoded = append(encoded, bz)
+		} else {
+			encoded[i] = bz
+		}
+	}
+	components := make([]types.Component, len(metadata))
+	for i, c := range metadata {
+		if components[i], err = c.Decode(encoded[i]); err != nil {
+			return 0, err
+		}
+	}
+
+	ids, err := createEntities(wCtx, "", 1, metadata, components)
+	if err != nil {
+		return 0, err
+	}
+	return ids[0], nil
+}
+
+// withDefaultValues replaces the components that have their zero value with the default value of their type, if it has
+// one. The given slice is not modified.
+func withDefaultValues(metadata []types.ComponentMetadata, components []types.Component) []types.Component {
+	var replaced []types.Component
+	for i, comp := range components {
+		def, ok := metadata[i].DefaultValue()
+		if !ok || !reflect.Indirect(reflect.ValueOf(comp)).IsZero() {
+			continue
+		}
+		if replaced == nil {
+			replaced = slices.Clone(components)
+		}
+		replaced[i] = def
+	}
+	if replaced == nil {
+		return components
+	}
+	return replaced
+}
+
 // SetComponent sets component data to the entity.
 func SetComponent[T types.Component](wCtx engine.Context, id types.EntityID, component *T) (err error) {
 	defer func() { panicOnFatalError(wCtx, err) }()
@@ -250,10 +416,18 @@ func SetComponent[T types.Component](wCtx engine.Context, id types.EntityID, com
 		return err
 	}
 
+	if err = wCtx.TrackComponentChange(c, id, false); err != nil {
+		return err
+	}
+	if err = wCtx.IndexComponent(c, id, component); err != nil {
+		return err
+	}
+
 	// Store the component
 	err = wCtx.StoreManager().SetComponentForEntity(c, id, component)
 	if err != nil {
-		return err
+		// The entity doesn't have the component, so it must not stay in the component's indexes
+		return withCleanup(err, wCtx.IndexComponent(c, id, nil))
 	}
 
 	// Log
@@ -297,6 +471,32 @@ func GetComponent[T types.Component](wCtx engine.Context, id types.EntityID) (co
 	return comp, nil
 }
 
+// GetComponentFields returns the component of type T of the given entity with only the given fields decoded, e.g.
+// GetComponentFields[Position](wCtx, id, "X", "Y"). All other fields are left at their zero value, so the returned
+// component must not be written back with SetComponent. Fields are named by their Go name. If the component was
+// already read or changed in the current tick, it is returned whole.
+func GetComponentFields[T types.Component](wCtx engine.Context, id types.EntityID, fields ...string) (comp *T, err error) {
+	defer func() { panicOnFatalError(wCtx, err) }()
+
+	var t T
+	c, err := wCtx.GetComponentByName(t.Name())
+	if err != nil {
+		return nil, err
+	}
+	compValue, err := wCtx.StoreReader().GetComponentFieldsForEntity(c, id, fields)
+	if err != nil {
+		return nil, err
+	}
+	switch v := compValue.(type) {
+	case T:
+		return &v, nil
+	case *T:
+		return v, nil
+	default:
+		return nil, eris.Errorf("component %q has an unexpected type %T", t.Name(), compValue)
+	}
+}
+
 func UpdateComponent[T types.Component](wCtx engine.Context, id types.EntityID, fn func(*T) *T) (err error) {
 	defer func() { panicOnFatalError(wCtx, err) }()
 
@@ -305,6 +505,16 @@ func UpdateComponent[T types.Component](wCtx engine.Context, id types.EntityID,
 		return err
 	}
 
+	// The change is tracked before fn runs, since fn can modify the stored value in place
+	var t T
+	c, err := wCtx.GetComponentByName(t.Name())
+	if err != nil {
+		return err
+	}
+	if err = wCtx.TrackComponentChange(c, id, false); err != nil {
+		return err
+	}
+
 	// Get current component value
 	val, err := GetComponent[T](wCtx, id)
 	if err != nil {
@@ -338,13 +548,26 @@ func AddComponentTo[T types.Component](wCtx engine.Context, id types.EntityID) (
 		return err
 	}
 
+	if err = wCtx.ReserveEntityQuota("", 0, 1); err != nil {
+		return err
+	}
+
 	// Add the component to entity
 	err = wCtx.StoreManager().AddComponentToEntity(c, id)
+	if err != nil {
+		return withCleanup(err, wCtx.ReleaseEntityQuota(0, 1))
+	}
+
+	// Index the default value of the component, and undo the addition if another entity already has its key
+	value, err := wCtx.StoreReader().GetComponentForEntity(c, id)
 	if err != nil {
 		return err
 	}
+	if err = wCtx.IndexComponent(c, id, value); err != nil {
+		return withCleanup(err, wCtx.StoreManager().RemoveComponentFromEntity(c, id), wCtx.ReleaseEntityQuota(0, 1))
+	}
 
-	return nil
+	return wCtx.TrackComponentChange(c, id, true)
 }
 
 // RemoveComponentFrom removes a component from an entity.
@@ -368,8 +591,11 @@ func RemoveComponentFrom[T types.Component](wCtx engine.Context, id types.Entity
 	if err != nil {
 		return err
 	}
+	if err = wCtx.IndexComponent(c, id, nil); err != nil {
+		return err
+	}
 
-	return nil
+	return wCtx.ReleaseEntityQuota(0, 1)
 }
 
 // Remove removes the given Entity from the world.
@@ -381,10 +607,48 @@ func Remove(wCtx engine.Context, id types.EntityID) (err error) {
 		return ErrEntityMutationOnReadOnly
 	}
 
+	comps, err := wCtx.StoreManager().GetComponentTypesForEntity(id)
+	if err != nil {
+		return err
+	}
 	err = wCtx.StoreManager().RemoveEntity(id)
 	if err != nil {
 		return err
 	}
+	if err = wCtx.ReleaseEntityQuota(1, len(comps)); err != nil {
+		return err
+	}
+	for _, c := range comps {
+		if err = wCtx.IndexComponent(c, id, nil); err != nil {
+			return err
+		}
+	}
+
+	return releaseEntityOwnership(wCtx, id)
+}
 
+// Destroy marks the given entity for removal at the end of the tick, after all systems and triggers ran. Until then,
+// the entity and its components can still be read and changed, so every system in the tick sees the same entities no
+// matter in which order they run, and searches of later systems aren't disturbed by the removal. Use IsDestroyed to
+// skip entities that are about to be removed. Destroying an entity twice is a no-op.
+func Destroy(wCtx engine.Context, id types.EntityID) (err error) {
+	defer func() { panicOnFatalError(wCtx, err) }()
+
+	// Error if the context is read only
+	if wCtx.IsReadOnly() {
+		return ErrEntityMutationOnReadOnly
+	}
+
+	// Fail early if the entity doesn't exist, instead of at the end of the tick
+	if _, err = wCtx.StoreManager().GetComponentTypesForEntity(id); err != nil {
+		return err
+	}
+	wCtx.DestroyEntity(id)
 	return nil
 }
+
+// IsDestroyed returns true if the given entity was destroyed with Destroy in the current tick, and will be removed at
+// its end.
+func IsDestroyed(wCtx engine.Context, id types.EntityID) bool {
+	return wCtx.IsEntityDestroyed(id)
+}
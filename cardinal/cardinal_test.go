@@ -20,6 +20,7 @@ import (
 	"pkg.world.dev/world-engine/assert"
 	"pkg.world.dev/world-engine/cardinal"
 	"pkg.world.dev/world-engine/cardinal/message"
+	"pkg.world.dev/world-engine/cardinal/receipt"
 	"pkg.world.dev/world-engine/cardinal/router/mocks"
 	"pkg.world.dev/world-engine/cardinal/search/filter"
 	"pkg.world.dev/world-engine/cardinal/testutils"
@@ -137,6 +138,49 @@ type ModifyScoreMsg struct {
 
 type EmptyMsgResult struct{}
 
+func TestExpiredTransactionsAreDropped(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil)
+	world := tf.World
+	type PingMsg struct{}
+	type PongMsg struct{}
+	assert.NilError(t, cardinal.RegisterMessage[PingMsg, PongMsg](world, "ping"))
+	var executed []types.TxHash
+	err := cardinal.RegisterSystems(world, func(wCtx engine.Context) error {
+		return cardinal.EachMessage[PingMsg, PongMsg](wCtx, func(tx message.TxData[PingMsg]) (PongMsg, error) {
+			executed = append(executed, tx.Hash)
+			return PongMsg{}, nil
+		})
+	})
+	assert.NilError(t, err)
+	tf.StartWorld()
+	tf.DoTick()
+	pingMsg, ok := world.GetMessageByFullName("game.ping")
+	assert.True(t, ok)
+
+	// The next tick is tick 1, so a transaction that expires at tick 1 is dropped, while one that expires at tick 2
+	// is still executed.
+	expiring := func(expiresAtTick uint64) *sign.Transaction {
+		sig := testutils.UniqueSignature()
+		sig.ExpiresAtTick = expiresAtTick
+		return sig
+	}
+	expiredHash := tf.AddTransaction(pingMsg.ID(), PingMsg{}, expiring(1))
+	validHash := tf.AddTransaction(pingMsg.ID(), PingMsg{}, expiring(2))
+	tf.DoTick()
+
+	assert.DeepEqual(t, []types.TxHash{validHash}, executed)
+	receipts, err := world.GetTransactionReceiptsForTick(1)
+	assert.NilError(t, err)
+	statuses := map[types.TxHash]string{}
+	for _, r := range receipts {
+		statuses[r.TxHash] = r.Status()
+	}
+	assert.DeepEqual(t, map[types.TxHash]string{
+		expiredHash: receipt.StatusExpired,
+		validHash:   receipt.StatusSuccess,
+	}, statuses)
+}
+
 func TestSystemsAreExecutedDuringGameTick(t *testing.T) {
 	tf := testutils.NewTestFixture(t, nil)
 	world := tf.World
@@ -846,6 +890,7 @@ func TestCallsRegisterGameShardOnStartup(t *testing.T) {
 	rtr.EXPECT().Start().Times(1)
 	rtr.EXPECT().RegisterGameShard(gomock.Any()).Times(1)
 	rtr.EXPECT().SubmitTxBlob(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1)
+	rtr.EXPECT().Shutdown().Times(1)
 	tf.DoTick()
 }
 
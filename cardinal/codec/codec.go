@@ -1,19 +1,119 @@
+// Package codec encodes and decodes the values that cardinal persists, such as components.
+//
+// Values are encoded as JSON unless another Codec is selected. The output of every codec other than JSON starts with
+// a two byte header, a zero byte followed by the ID of the codec, which JSON can never start with. Decode reads the
+// header to pick the codec, so values that were written with different codecs can be read side by side. This is the
+// migration path when the codec of a component changes: old values are still read with the codec that wrote them,
+// and are written with the new codec the next time they are saved.
 package codec
 
 import (
+	"sync"
+
 	"github.com/goccy/go-json"
 	"github.com/rotisserie/eris"
 )
 
+// headerMarker is the first byte of values that are not encoded as JSON.
+const headerMarker byte = 0
+
+// Codec is a binary encoding for values.
+type Codec interface {
+	// ID identifies the codec in the header of the values that it encoded. It must be unique among the registered
+	// codecs. ID 0 is reserved for JSON.
+	ID() byte
+	// Name is the human-readable name of the codec, e.g. "msgpack".
+	Name() string
+	Marshal(v any) ([]byte, error)
+	Unmarshal(bz []byte, v any) error
+}
+
+var (
+	// JSON is the default codec.
+	JSON Codec = jsonCodec{}
+	// MsgPack encodes structs as MessagePack maps keyed by their JSON field names. It is several times more compact,
+	// and faster, than JSON. Types that implement msgp.Marshaler and msgp.Unmarshaler, e.g. because their code was
+	// generated with github.com/tinylib/msgp, are encoded with their generated code.
+	MsgPack Codec = msgpackCodec{}
+	// Protobuf encodes values whose pointer type is a proto.Message.
+	Protobuf Codec = protobufCodec{}
+)
+
+var (
+	codecsMu sync.RWMutex
+	codecs   = map[byte]Codec{}
+)
+
+func init() {
+	for _, c := range []Codec{MsgPack, Protobuf} {
+		if err := Register(c); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// Register makes a codec available to Decode. The built-in codecs are registered automatically.
+func Register(c Codec) error {
+	codecsMu.Lock()
+	defer codecsMu.Unlock()
+	if c.ID() == JSON.ID() {
+		return eris.Errorf("codec ID %d is reserved for JSON", c.ID())
+	}
+	if _, ok := c.(encryptedCodec); ok {
+		return eris.New("the encrypted codec can't be registered, its values are decoded with DecodeWith")
+	}
+	if registered, ok := codecs[c.ID()]; ok && registered.Name() != c.Name() {
+		return eris.Errorf("codec ID %d is already used by %s", c.ID(), registered.Name())
+	}
+	codecs[c.ID()] = c
+	return nil
+}
+
+// codecOfWith is like codecOf, but returns the given codec if bz has its header.
+func codecOfWith(c Codec, bz []byte) (Codec, []byte, error) {
+	if c != nil && !IsJSON(bz) && len(bz) >= 2 && bz[1] == c.ID() { //nolint:gomnd // marker and ID
+		return c, bz[2:], nil
+	}
+	return codecOf(bz)
+}
+
+// codecOf returns the codec that encoded bz, and bz without the header.
+func codecOf(bz []byte) (Codec, []byte, error) {
+	if IsJSON(bz) {
+		return JSON, bz, nil
+	}
+	if len(bz) < 2 { //nolint:gomnd // marker and ID
+		return nil, nil, eris.New("encoded value has a truncated codec header")
+	}
+	codecsMu.RLock()
+	c, ok := codecs[bz[1]]
+	codecsMu.RUnlock()
+	if !ok {
+		return nil, nil, eris.Errorf("encoded value has an unknown codec ID %d", bz[1])
+	}
+	return c, bz[2:], nil
+}
+
+// Decode decodes a value that was encoded with Encode or EncodeWith.
 func Decode[T any](bz []byte) (T, error) {
+	return DecodeWith[T](nil, bz)
+}
+
+// DecodeWith is like Decode, but a value with the header of the given codec is decoded with it rather than with the
+// registered codec of its ID. Values of the Encrypted codec, which is not registered, must be decoded this way.
+func DecodeWith[T any](c Codec, bz []byte) (T, error) {
 	comp := new(T)
-	err := json.Unmarshal(bz, comp)
+	c, body, err := codecOfWith(c, bz)
 	if err != nil {
+		return *comp, err
+	}
+	if err = c.Unmarshal(body, comp); err != nil {
 		return *comp, eris.Wrap(err, "")
 	}
 	return *comp, nil
 }
 
+// Encode encodes a value as JSON.
 func Encode(comp any) ([]byte, error) {
 	bz, err := json.Marshal(comp)
 	if err != nil {
@@ -21,3 +121,38 @@ func Encode(comp any) ([]byte, error) {
 	}
 	return bz, nil
 }
+
+// EncodeWith encodes a value with the given codec, prefixed with the header of the codec unless it is JSON.
+func EncodeWith(c Codec, v any) ([]byte, error) {
+	if c == nil || c.ID() == JSON.ID() {
+		return Encode(v)
+	}
+	bz, err := c.Marshal(v)
+	if err != nil {
+		return nil, eris.Wrapf(err, "failed to encode %T with %s", v, c.Name())
+	}
+	return append([]byte{headerMarker, c.ID()}, bz...), nil
+}
+
+// IsJSON reports whether a value was encoded as JSON, rather than with another codec.
+func IsJSON(bz []byte) bool {
+	return len(bz) == 0 || bz[0] != headerMarker
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) ID() byte {
+	return 0
+}
+
+func (jsonCodec) Name() string {
+	return "json"
+}
+
+func (jsonCodec) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Unmarshal(bz []byte, v any) error {
+	return json.Unmarshal(bz, v)
+}
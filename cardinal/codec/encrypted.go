@@ -0,0 +1,264 @@
+package codec
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"sync"
+
+	"github.com/rotisserie/eris"
+)
+
+// maxKeyIDLength is the maximum length of a key ID, which is stored in front of every encrypted value.
+const maxKeyIDLength = 255
+
+var (
+	// ErrDecryptionFailed is returned for encrypted values that were tampered with or encrypted with another key.
+	ErrDecryptionFailed = errors.New("failed to decrypt value")
+	ErrKeyNotAvailable  = errors.New("encryption key is not available")
+	ErrInvalidKey       = errors.New("invalid encryption key")
+)
+
+// KeyProvider supplies the AES keys that values are encrypted with, e.g. from the config or a KMS. Keys are looked up
+// by their ID, which is stored with every encrypted value, so that values that were encrypted with a previous key can
+// still be decrypted after the current key was rotated. The key of an ID must never change.
+type KeyProvider interface {
+	// CurrentKeyID returns the ID of the key that values are encrypted with.
+	CurrentKeyID() string
+	// Key returns the key with the given ID. It must be 16, 24 or 32 bytes long, to select AES-128, AES-192 or AES-256.
+	Key(id string) ([]byte, error)
+}
+
+// StaticKeys is a KeyProvider of keys that are known in advance. Values are encrypted with the key Current, and can be
+// decrypted with any of Keys.
+type StaticKeys struct {
+	Current string
+	Keys    map[string][]byte
+}
+
+var _ KeyProvider = StaticKeys{}
+
+// NewStaticKey returns a KeyProvider of a single key, whose ID is derived from the key. See KeyID.
+func NewStaticKey(key []byte) StaticKeys {
+	id := KeyID(key)
+	return StaticKeys{Current: id, Keys: map[string][]byte{id: key}}
+}
+
+// KeyID returns a short fingerprint of a key, which identifies the key without revealing it.
+func KeyID(key []byte) string {
+	sum := sha256.Sum256(key)
+	return hex.EncodeToString(sum[:4])
+}
+
+func (s StaticKeys) CurrentKeyID() string {
+	return s.Current
+}
+
+func (s StaticKeys) Key(id string) ([]byte, error) {
+	key, ok := s.Keys[id]
+	if !ok {
+		return nil, eris.Wrapf(ErrKeyNotAvailable, "key %q", id)
+	}
+	return key, nil
+}
+
+// encryptedCodec encrypts the values of another codec with AES-GCM. See Cipher for the format of encrypted values.
+type encryptedCodec struct {
+	inner  Codec
+	cipher *Cipher
+	// location is where the values are stored. See At.
+	location string
+}
+
+// Encrypted returns a codec that encodes values with the inner codec, and encrypts them with AES-GCM with the current
+// key of keys. Unlike the other codecs, it is not registered, since every world has its own keys: values that it
+// encrypted are decoded with DecodeWith and the codec itself. They are decoded with the codec that encoded them,
+// regardless of the inner codec.
+func Encrypted(inner Codec, keys KeyProvider) Codec {
+	if inner == nil {
+		inner = JSON
+	}
+	return encryptedCodec{inner: inner, cipher: NewCipher(keys)}
+}
+
+// At returns a codec that binds the values that c encrypts to the given location, e.g. the component and entity that
+// they are stored for, so that they can only be decoded with a codec bound to the same location. A value that is
+// copied to another location fails to decode with ErrDecryptionFailed. Codecs that don't encrypt are returned as is.
+func At(c Codec, location string) Codec {
+	if enc, ok := c.(encryptedCodec); ok {
+		enc.location = location
+		return enc
+	}
+	return c
+}
+
+// Plain returns the codec whose values c encrypts, or c itself if it doesn't encrypt them.
+func Plain(c Codec) Codec {
+	if enc, ok := c.(encryptedCodec); ok {
+		return enc.inner
+	}
+	return c
+}
+
+func (encryptedCodec) ID() byte {
+	return encryptedCodecID
+}
+
+func (encryptedCodec) Name() string {
+	return "encrypted"
+}
+
+func (c encryptedCodec) Marshal(v any) ([]byte, error) {
+	plain, err := EncodeWith(c.inner, v)
+	if err != nil {
+		return nil, err
+	}
+	return c.cipher.seal(plain, c.location)
+}
+
+func (c encryptedCodec) Unmarshal(bz []byte, v any) error {
+	plain, err := c.cipher.open(bz, c.location)
+	if err != nil {
+		return err
+	}
+	inner, body, err := codecOf(plain)
+	if err != nil {
+		return err
+	}
+	return inner.Unmarshal(body, v)
+}
+
+// encryptedCodecID is the ID of the Encrypted codec, which also starts the values that a Cipher encrypted.
+const encryptedCodecID byte = 'e'
+
+// Cipher encrypts values with AES-GCM with the current key of a KeyProvider. It encrypts the values of the Encrypted
+// codec, and the values that cardinal stores as bytes, such as raw storage values and archived entities. A value that
+// a Cipher encrypted starts with the header of the Encrypted codec, followed by the length of the key ID, the key ID,
+// the nonce, and the sealed value. The key ID and the location of the value, e.g. its storage key, are authenticated
+// along with the value, so that a value that is moved to another location can't be decrypted.
+//
+// The nonce is derived from the key, the location and the value, rather than chosen at random, so that the same value
+// is always encrypted the same way: the determinism audit and the digests of the game state compare encoded values. As
+// a consequence, whether two values stored at the same location are equal is not hidden.
+//
+// A nil Cipher leaves values unchanged.
+type Cipher struct {
+	keys KeyProvider
+	// ciphers caches the keyCipher of every key ID.
+	ciphers *sync.Map
+}
+
+type keyCipher struct {
+	aead     cipher.AEAD
+	nonceKey []byte
+}
+
+// NewCipher returns a Cipher that encrypts values with the current key of keys.
+func NewCipher(keys KeyProvider) *Cipher {
+	return &Cipher{keys: keys, ciphers: &sync.Map{}}
+}
+
+// Seal encrypts a value that is stored at the given location, e.g. its storage key. It can only be decrypted with Open
+// and the same location.
+func (c *Cipher) Seal(bz []byte, location string) ([]byte, error) {
+	if c == nil {
+		return bz, nil
+	}
+	sealed, err := c.seal(bz, location)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{headerMarker, encryptedCodecID}, sealed...), nil
+}
+
+// Open decrypts a value that was encrypted with Seal for the given location. Values that were not encrypted, because
+// they were stored before encryption was enabled, are returned unchanged. Values are told apart by their header, so a
+// value that was not encrypted but starts with the header of the Encrypted codec can't be read.
+func (c *Cipher) Open(bz []byte, location string) ([]byte, error) {
+	if c == nil || IsJSON(bz) || len(bz) < 2 || bz[1] != encryptedCodecID { //nolint:gomnd // marker and ID
+		return bz, nil
+	}
+	return c.open(bz[2:], location)
+}
+
+func (c *Cipher) seal(plain []byte, location string) ([]byte, error) {
+	id := c.keys.CurrentKeyID()
+	if len(id) > maxKeyIDLength {
+		return nil, eris.Errorf("encryption key ID %q is longer than %d bytes", id, maxKeyIDLength)
+	}
+	kc, err := c.cipher(id)
+	if err != nil {
+		return nil, err
+	}
+	ad := additionalData(id, location)
+	// The same value is encrypted with another nonce at every location, since reusing a nonce with other additional
+	// data would break GCM. The additional data is prefixed with its length, so that it can't run into the value.
+	mac := hmac.New(sha256.New, kc.nonceKey)
+	mac.Write(binary.BigEndian.AppendUint64(nil, uint64(len(ad))))
+	mac.Write(ad)
+	mac.Write(plain)
+	nonce := mac.Sum(nil)[:kc.aead.NonceSize()]
+
+	bz := make([]byte, 0, 1+len(id)+len(nonce)+len(plain)+kc.aead.Overhead())
+	bz = append(bz, byte(len(id)))
+	bz = append(bz, id...)
+	bz = append(bz, nonce...)
+	return kc.aead.Seal(bz, nonce, plain, ad), nil
+}
+
+func (c *Cipher) open(bz []byte, location string) ([]byte, error) {
+	if len(bz) == 0 || len(bz) < 1+int(bz[0]) {
+		return nil, eris.New("encrypted value is truncated")
+	}
+	id, rest := string(bz[1:1+bz[0]]), bz[1+bz[0]:]
+	kc, err := c.cipher(id)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) < kc.aead.NonceSize() {
+		return nil, eris.New("encrypted value is truncated")
+	}
+	nonce, sealed := rest[:kc.aead.NonceSize()], rest[kc.aead.NonceSize():]
+	plain, err := kc.aead.Open(nil, nonce, sealed, additionalData(id, location))
+	if err != nil {
+		return nil, eris.Wrapf(ErrDecryptionFailed, "key %q at %q: %v", id, location, err)
+	}
+	return plain, nil
+}
+
+// additionalData is the data that is authenticated along with a value: the key ID, prefixed with its length so that
+// it can't run into the location, and the location of the value.
+func additionalData(id, location string) []byte {
+	ad := make([]byte, 0, 1+len(id)+len(location))
+	ad = append(ad, byte(len(id)))
+	ad = append(ad, id...)
+	return append(ad, location...)
+}
+
+// cipher returns the cipher of the key with the given ID.
+func (c *Cipher) cipher(id string) (*keyCipher, error) {
+	if kc, ok := c.ciphers.Load(id); ok {
+		return kc.(*keyCipher), nil //nolint:errcheck // only keyCiphers are stored
+	}
+	key, err := c.keys.Key(id)
+	if err != nil {
+		return nil, eris.Wrapf(err, "failed to get encryption key %q", id)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, eris.Wrapf(ErrInvalidKey, "key %q: %v", id, err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, eris.Wrap(err, "")
+	}
+	// The nonces are derived with another key than the one that encrypts the values.
+	nonceKey := sha256.Sum256(append([]byte("cardinal-encryption-nonce:"), key...))
+	kc := &keyCipher{aead: aead, nonceKey: nonceKey[:]}
+	c.ciphers.Store(id, kc)
+	return kc, nil
+}
@@ -0,0 +1,118 @@
+package codec_test
+
+import (
+	"bytes"
+	"testing"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal/codec"
+)
+
+type Profile struct {
+	Email string `json:"email"`
+	Age   int    `json:"age"`
+}
+
+func TestEncryptedCodec(t *testing.T) {
+	oldKey := bytes.Repeat([]byte{1}, 32)
+	newKey := bytes.Repeat([]byte{2}, 16)
+	keys := codec.StaticKeys{Current: "old", Keys: map[string][]byte{"old": oldKey}}
+	encryptedMsgPack := codec.Encrypted(codec.MsgPack, keys)
+
+	profile := Profile{Email: "aria@example.com", Age: 30}
+	encrypted, err := codec.EncodeWith(encryptedMsgPack, profile)
+	assert.NilError(t, err)
+	assert.Assert(t, !codec.IsJSON(encrypted))
+	assert.Assert(t, !bytes.Contains(encrypted, []byte("aria")))
+	decoded, err := codec.DecodeWith[Profile](encryptedMsgPack, encrypted)
+	assert.NilError(t, err)
+	assert.Equal(t, profile, decoded)
+	fields, err := codec.DecodeFieldsWith[Profile](encryptedMsgPack, encrypted, []string{"Age"})
+	assert.NilError(t, err)
+	assert.Equal(t, Profile{Age: profile.Age}, fields)
+
+	// The same value is always encrypted the same way, so that the game state stays deterministic.
+	again, err := codec.EncodeWith(codec.Encrypted(codec.MsgPack, keys), profile)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, encrypted, again)
+
+	// Values that were encrypted with the previous key are still decrypted after the key was rotated.
+	keys = codec.StaticKeys{Current: "new", Keys: map[string][]byte{"old": oldKey, "new": newKey}}
+	rotatedJSON := codec.Encrypted(codec.JSON, keys)
+	rotated, err := codec.EncodeWith(rotatedJSON, profile)
+	assert.NilError(t, err)
+	for _, bz := range [][]byte{encrypted, rotated} {
+		decoded, err = codec.DecodeWith[Profile](rotatedJSON, bz)
+		assert.NilError(t, err)
+		assert.Equal(t, profile, decoded)
+	}
+
+	// Tampered values and values of unknown keys are not decrypted.
+	tampered := bytes.Clone(rotated)
+	tampered[len(tampered)-1] ^= 1
+	_, err = codec.DecodeWith[Profile](rotatedJSON, tampered)
+	assert.ErrorIs(t, err, codec.ErrDecryptionFailed)
+	_, err = codec.DecodeWith[Profile](codec.Encrypted(codec.JSON, codec.NewStaticKey(newKey)), encrypted)
+	assert.ErrorIs(t, err, codec.ErrKeyNotAvailable)
+
+	// Values bound to a location are only decoded at that location.
+	atAria, err := codec.EncodeWith(codec.At(rotatedJSON, "aria"), profile)
+	assert.NilError(t, err)
+	decoded, err = codec.DecodeWith[Profile](codec.At(rotatedJSON, "aria"), atAria)
+	assert.NilError(t, err)
+	assert.Equal(t, profile, decoded)
+	_, err = codec.DecodeWith[Profile](codec.At(rotatedJSON, "bob"), atAria)
+	assert.ErrorIs(t, err, codec.ErrDecryptionFailed)
+	_, err = codec.DecodeFieldsWith[Profile](codec.At(rotatedJSON, "bob"), atAria, []string{"Age"})
+	assert.ErrorIs(t, err, codec.ErrDecryptionFailed)
+
+	_, err = codec.EncodeWith(codec.Encrypted(codec.JSON, codec.NewStaticKey([]byte("short"))), profile)
+	assert.ErrorIs(t, err, codec.ErrInvalidKey)
+}
+
+func TestEncryptedCodecIsNotShared(t *testing.T) {
+	// Two worlds in the same process encrypt their values with their own keys.
+	first := codec.Encrypted(codec.JSON, codec.NewStaticKey(bytes.Repeat([]byte{1}, 16)))
+	second := codec.Encrypted(codec.JSON, codec.NewStaticKey(bytes.Repeat([]byte{2}, 16)))
+	assert.ErrorContains(t, codec.Register(first), "can't be registered")
+
+	profile := Profile{Email: "aria@example.com", Age: 30}
+	bz, err := codec.EncodeWith(first, profile)
+	assert.NilError(t, err)
+	decoded, err := codec.DecodeWith[Profile](first, bz)
+	assert.NilError(t, err)
+	assert.Equal(t, profile, decoded)
+	_, err = codec.DecodeWith[Profile](second, bz)
+	assert.ErrorIs(t, err, codec.ErrKeyNotAvailable)
+	decodedFields, err := codec.DecodeFieldsWith[Profile](first, bz, []string{"Email"})
+	assert.NilError(t, err)
+	assert.Equal(t, Profile{Email: profile.Email}, decodedFields)
+}
+
+func TestCipher(t *testing.T) {
+	cipher := codec.NewCipher(codec.NewStaticKey(bytes.Repeat([]byte{1}, 16)))
+	sealed, err := cipher.Seal([]byte("aria@example.com"), "profiles:aria")
+	assert.NilError(t, err)
+	assert.Assert(t, !bytes.Contains(sealed, []byte("aria")))
+	opened, err := cipher.Open(sealed, "profiles:aria")
+	assert.NilError(t, err)
+	assert.Equal(t, "aria@example.com", string(opened))
+
+	// A value that is moved to another location can't be decrypted, and the same value is encrypted differently there.
+	_, err = cipher.Open(sealed, "profiles:bob")
+	assert.ErrorIs(t, err, codec.ErrDecryptionFailed)
+	elsewhere, err := cipher.Seal([]byte("aria@example.com"), "profiles:bob")
+	assert.NilError(t, err)
+	assert.Assert(t, !bytes.Equal(sealed, elsewhere))
+
+	// Values that were stored before encryption was enabled are read as they are.
+	opened, err = cipher.Open([]byte(`{"email":"aria@example.com"}`), "profiles:aria")
+	assert.NilError(t, err)
+	assert.Equal(t, `{"email":"aria@example.com"}`, string(opened))
+
+	// A nil cipher leaves values unchanged.
+	var disabled *codec.Cipher
+	plain, err := disabled.Seal([]byte("aria"), "profiles:aria")
+	assert.NilError(t, err)
+	assert.Equal(t, "aria", string(plain))
+}
@@ -0,0 +1,469 @@
+package codec
+
+import (
+	"encoding"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/rotisserie/eris"
+	"github.com/tinylib/msgp/msgp"
+)
+
+var (
+	msgpMarshalerType   = reflect.TypeOf((*msgp.Marshaler)(nil)).Elem()
+	msgpUnmarshalerType = reflect.TypeOf((*msgp.Unmarshaler)(nil)).Elem()
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// msgpackCodec encodes values as MessagePack by reflection. Structs are encoded as maps keyed by the JSON names of
+// their fields, so that the encoded values have the same shape as the JSON schema of a component, and fields can be
+// added or removed without breaking stored values, like with JSON.
+type msgpackCodec struct{}
+
+func (msgpackCodec) ID() byte {
+	return 'm'
+}
+
+func (msgpackCodec) Name() string {
+	return "msgpack"
+}
+
+func (msgpackCodec) Marshal(v any) ([]byte, error) {
+	rv := reflect.ValueOf(v)
+	if !rv.IsValid() {
+		return msgp.AppendNil(nil), nil
+	}
+	// Copy the value so that it is addressable, which lets pointer receiver marshalers of its fields be used.
+	addressable := reflect.New(rv.Type()).Elem()
+	addressable.Set(rv)
+	return appendValue(make([]byte, 0, 64), addressable) //nolint:gomnd // initial capacity
+}
+
+func (msgpackCodec) Unmarshal(bz []byte, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return eris.Errorf("msgpack can only decode into a non-nil pointer, not %T", v)
+	}
+	rest, err := readValue(bz, rv.Elem())
+	if err != nil {
+		return err
+	}
+	if len(rest) != 0 {
+		return eris.Errorf("msgpack value has %d trailing bytes", len(rest))
+	}
+	return nil
+}
+
+//nolint:gocyclo,cyclop // one case per kind
+func appendValue(b []byte, v reflect.Value) ([]byte, error) {
+	if v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return msgp.AppendNil(b), nil
+		}
+	}
+	if m, ok := marshalerOf(v, msgpMarshalerType); ok {
+		return m.(msgp.Marshaler).MarshalMsg(b) //nolint:errcheck // checked by marshalerOf
+	}
+	if m, ok := marshalerOf(v, textMarshalerType); ok {
+		text, err := m.(encoding.TextMarshaler).MarshalText() //nolint:errcheck // checked by marshalerOf
+		if err != nil {
+			return nil, eris.Wrap(err, "")
+		}
+		return msgp.AppendStringFromBytes(b, text), nil
+	}
+
+	switch v.Kind() { //nolint:exhaustive // unsupported kinds are handled by default
+	case reflect.Pointer:
+		return appendValue(b, v.Elem())
+	case reflect.Interface:
+		elem := reflect.New(v.Elem().Type()).Elem()
+		elem.Set(v.Elem())
+		return appendValue(b, elem)
+	case reflect.Bool:
+		return msgp.AppendBool(b, v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return msgp.AppendInt64(b, v.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return msgp.AppendUint64(b, v.Uint()), nil
+	case reflect.Float32:
+		return msgp.AppendFloat32(b, float32(v.Float())), nil
+	case reflect.Float64:
+		return msgp.AppendFloat64(b, v.Float()), nil
+	case reflect.String:
+		return msgp.AppendString(b, v.String()), nil
+	case reflect.Slice:
+		if v.IsNil() {
+			return msgp.AppendNil(b), nil
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return msgp.AppendBytes(b, v.Bytes()), nil
+		}
+		return appendArray(b, v)
+	case reflect.Array:
+		return appendArray(b, v)
+	case reflect.Map:
+		return appendMap(b, v)
+	case reflect.Struct:
+		return appendStruct(b, v)
+	default:
+		return nil, eris.Errorf("msgpack can't encode values of type %s", v.Type())
+	}
+}
+
+func appendArray(b []byte, v reflect.Value) ([]byte, error) {
+	var err error
+	b = msgp.AppendArrayHeader(b, uint32(v.Len()))
+	for i := 0; i < v.Len(); i++ {
+		if b, err = appendValue(b, v.Index(i)); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+// appendMap encodes the entries of a map sorted by their encoded key, so that equal maps are always encoded to the
+// same bytes.
+func appendMap(b []byte, v reflect.Value) ([]byte, error) {
+	if v.IsNil() {
+		return msgp.AppendNil(b), nil
+	}
+	type entry struct {
+		key   []byte
+		value reflect.Value
+	}
+	entries := make([]entry, 0, v.Len())
+	iter := v.MapRange()
+	for iter.Next() {
+		key := reflect.New(iter.Key().Type()).Elem()
+		key.Set(iter.Key())
+		bz, err := appendValue(nil, key)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry{key: bz, value: iter.Value()})
+	}
+	slices.SortFunc(entries, func(a, b entry) int {
+		return strings.Compare(string(a.key), string(b.key))
+	})
+
+	var err error
+	b = msgp.AppendMapHeader(b, uint32(len(entries)))
+	for _, e := range entries {
+		b = append(b, e.key...)
+		value := reflect.New(e.value.Type()).Elem()
+		value.Set(e.value)
+		if b, err = appendValue(b, value); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+func appendStruct(b []byte, v reflect.Value) ([]byte, error) {
+	fields := fieldsOf(v.Type())
+	values := make([]reflect.Value, 0, len(fields.list))
+	names := make([]string, 0, len(fields.list))
+	for _, f := range fields.list {
+		// Fields promoted through a nil embedded pointer are left out.
+		fv, err := v.FieldByIndexErr(f.index)
+		if err != nil {
+			continue
+		}
+		values = append(values, fv)
+		names = append(names, f.name)
+	}
+
+	var err error
+	b = msgp.AppendMapHeader(b, uint32(len(values)))
+	for i, fv := range values {
+		b = msgp.AppendString(b, names[i])
+		if b, err = appendValue(b, fv); err != nil {
+			return nil, eris.Wrapf(err, "field %s", names[i])
+		}
+	}
+	return b, nil
+}
+
+//nolint:gocyclo,cyclop // one case per kind
+func readValue(b []byte, v reflect.Value) ([]byte, error) {
+	if msgp.IsNil(b) {
+		rest, err := msgp.ReadNilBytes(b)
+		if err != nil {
+			return nil, eris.Wrap(err, "")
+		}
+		v.Set(reflect.Zero(v.Type()))
+		return rest, nil
+	}
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return readValue(b, v.Elem())
+	}
+	if u, ok := unmarshalerOf(v, msgpUnmarshalerType); ok {
+		rest, err := u.(msgp.Unmarshaler).UnmarshalMsg(b) //nolint:errcheck // checked by unmarshalerOf
+		return rest, eris.Wrap(err, "")
+	}
+	if u, ok := unmarshalerOf(v, textUnmarshalerType); ok {
+		text, rest, err := msgp.ReadStringZC(b)
+		if err != nil {
+			return nil, eris.Wrap(err, "")
+		}
+		return rest, eris.Wrap(u.(encoding.TextUnmarshaler).UnmarshalText(text), "") //nolint:errcheck // checked above
+	}
+
+	var err error
+	switch v.Kind() { //nolint:exhaustive // unsupported kinds are handled by default
+	case reflect.Interface:
+		var i any
+		if i, b, err = msgp.ReadIntfBytes(b); err != nil {
+			return nil, eris.Wrap(err, "")
+		}
+		if i == nil {
+			v.Set(reflect.Zero(v.Type()))
+			return b, nil
+		}
+		if !reflect.TypeOf(i).AssignableTo(v.Type()) {
+			return nil, eris.Errorf("msgpack can't decode %T into %s", i, v.Type())
+		}
+		v.Set(reflect.ValueOf(i))
+		return b, nil
+	case reflect.Bool:
+		var x bool
+		x, b, err = msgp.ReadBoolBytes(b)
+		v.SetBool(x)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var x int64
+		x, b, err = msgp.ReadInt64Bytes(b)
+		if err == nil && v.OverflowInt(x) {
+			return nil, eris.Errorf("%d overflows %s", x, v.Type())
+		}
+		v.SetInt(x)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		var x uint64
+		x, b, err = msgp.ReadUint64Bytes(b)
+		if err == nil && v.OverflowUint(x) {
+			return nil, eris.Errorf("%d overflows %s", x, v.Type())
+		}
+		v.SetUint(x)
+	case reflect.Float32:
+		var x float32
+		x, b, err = msgp.ReadFloat32Bytes(b)
+		v.SetFloat(float64(x))
+	case reflect.Float64:
+		var x float64
+		x, b, err = msgp.ReadFloat64Bytes(b)
+		v.SetFloat(x)
+	case reflect.String:
+		var x string
+		x, b, err = msgp.ReadStringBytes(b)
+		v.SetString(x)
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			var x []byte
+			x, b, err = msgp.ReadBytesBytes(b, nil)
+			v.SetBytes(x)
+			break
+		}
+		return readSlice(b, v)
+	case reflect.Array:
+		return readArray(b, v)
+	case reflect.Map:
+		return readMap(b, v)
+	case reflect.Struct:
+		return readStruct(b, v)
+	default:
+		return nil, eris.Errorf("msgpack can't decode values of type %s", v.Type())
+	}
+	return b, eris.Wrap(err, "")
+}
+
+func readSlice(b []byte, v reflect.Value) ([]byte, error) {
+	n, b, err := msgp.ReadArrayHeaderBytes(b)
+	if err != nil {
+		return nil, eris.Wrap(err, "")
+	}
+	slice := reflect.MakeSlice(v.Type(), int(n), int(n))
+	for i := 0; i < int(n); i++ {
+		if b, err = readValue(b, slice.Index(i)); err != nil {
+			return nil, err
+		}
+	}
+	v.Set(slice)
+	return b, nil
+}
+
+func readArray(b []byte, v reflect.Value) ([]byte, error) {
+	if v.Type().Elem().Kind() == reflect.Uint8 && msgp.NextType(b) == msgp.BinType {
+		x, rest, err := msgp.ReadBytesZC(b)
+		if err != nil {
+			return nil, eris.Wrap(err, "")
+		}
+		reflect.Copy(v, reflect.ValueOf(x))
+		return rest, nil
+	}
+	n, b, err := msgp.ReadArrayHeaderBytes(b)
+	if err != nil {
+		return nil, eris.Wrap(err, "")
+	}
+	v.Set(reflect.Zero(v.Type()))
+	for i := 0; i < int(n); i++ {
+		if i >= v.Len() {
+			if b, err = msgp.Skip(b); err != nil {
+				return nil, eris.Wrap(err, "")
+			}
+			continue
+		}
+		if b, err = readValue(b, v.Index(i)); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+func readMap(b []byte, v reflect.Value) ([]byte, error) {
+	n, b, err := msgp.ReadMapHeaderBytes(b)
+	if err != nil {
+		return nil, eris.Wrap(err, "")
+	}
+	m := reflect.MakeMapWithSize(v.Type(), int(n))
+	for i := 0; i < int(n); i++ {
+		key := reflect.New(v.Type().Key()).Elem()
+		if b, err = readValue(b, key); err != nil {
+			return nil, err
+		}
+		value := reflect.New(v.Type().Elem()).Elem()
+		if b, err = readValue(b, value); err != nil {
+			return nil, err
+		}
+		m.SetMapIndex(key, value)
+	}
+	v.Set(m)
+	return b, nil
+}
+
+// readStruct decodes the fields of a struct by name. Entries that don't match a field are skipped, and fields without
+// an entry keep their value.
+func readStruct(b []byte, v reflect.Value) ([]byte, error) {
+	fields := fieldsOf(v.Type())
+	n, b, err := msgp.ReadMapHeaderBytes(b)
+	if err != nil {
+		return nil, eris.Wrap(err, "")
+	}
+	for i := 0; i < int(n); i++ {
+		var name []byte
+		if name, b, err = msgp.ReadMapKeyZC(b); err != nil {
+			return nil, eris.Wrap(err, "")
+		}
+		f, ok := fields.byName[string(name)]
+		if !ok {
+			if b, err = msgp.Skip(b); err != nil {
+				return nil, eris.Wrap(err, "")
+			}
+			continue
+		}
+		if b, err = readValue(b, fieldByIndexAlloc(v, f.index)); err != nil {
+			return nil, eris.Wrapf(err, "field %s", f.name)
+		}
+	}
+	return b, nil
+}
+
+// fieldByIndexAlloc returns the nested field of v, allocating the embedded pointers on its path.
+func fieldByIndexAlloc(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
+
+// marshalerOf returns v, or its address, as the given interface if it implements it.
+func marshalerOf(v reflect.Value, iface reflect.Type) (any, bool) {
+	if v.Type().Implements(iface) {
+		return v.Interface(), true
+	}
+	if v.CanAddr() && reflect.PointerTo(v.Type()).Implements(iface) {
+		return v.Addr().Interface(), true
+	}
+	return nil, false
+}
+
+// unmarshalerOf returns the address of v as the given interface if it implements it.
+func unmarshalerOf(v reflect.Value, iface reflect.Type) (any, bool) {
+	if v.CanAddr() && reflect.PointerTo(v.Type()).Implements(iface) {
+		return v.Addr().Interface(), true
+	}
+	return nil, false
+}
+
+type structField struct {
+	name  string
+	index []int
+}
+
+type structFields struct {
+	list   []structField
+	byName map[string]structField
+}
+
+// fieldCache caches the encoded fields of each struct type.
+var fieldCache sync.Map // map[reflect.Type]*structFields
+
+// fieldsOf returns the fields of a struct that are encoded, named like encoding/json names them: by their JSON tag,
+// or by their Go name if they have none. Fields of embedded structs without a tag are promoted, and fields tagged "-"
+// and unexported fields are left out. Of several fields with the same name, the least nested one is encoded.
+func fieldsOf(typ reflect.Type) *structFields {
+	if fields, ok := fieldCache.Load(typ); ok {
+		return fields.(*structFields) //nolint:errcheck // only structFields are stored
+	}
+	fields := &structFields{byName: map[string]structField{}}
+	// notPromoted are the embedded structs whose fields are not promoted because they are tagged or left out.
+	var notPromoted [][]int
+	for _, f := range reflect.VisibleFields(typ) {
+		if slices.ContainsFunc(notPromoted, func(prefix []int) bool {
+			return len(prefix) < len(f.Index) && slices.Equal(prefix, f.Index[:len(prefix)])
+		}) {
+			continue
+		}
+		tag := f.Tag.Get("json")
+		name, _, _ := strings.Cut(tag, ",")
+		isStruct := f.Type.Kind() == reflect.Struct ||
+			(f.Type.Kind() == reflect.Pointer && f.Type.Elem().Kind() == reflect.Struct)
+		if f.Anonymous && isStruct && (name != "" || tag == "-") {
+			notPromoted = append(notPromoted, f.Index)
+		}
+		switch {
+		case tag == "-":
+			continue
+		case f.Anonymous && isStruct && name == "":
+			// Its fields are promoted, and listed by VisibleFields separately.
+			continue
+		case !f.IsExported():
+			continue
+		case name == "":
+			name = f.Name
+		}
+		if other, ok := fields.byName[name]; ok && len(other.index) <= len(f.Index) {
+			continue
+		}
+		fields.byName[name] = structField{name: name, index: f.Index}
+	}
+	for _, f := range reflect.VisibleFields(typ) {
+		for _, field := range fields.byName {
+			if slices.Equal(field.index, f.Index) {
+				fields.list = append(fields.list, field)
+			}
+		}
+	}
+	fieldCache.Store(typ, fields)
+	return fields
+}
@@ -0,0 +1,145 @@
+package codec_test
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal/codec"
+)
+
+type Stats struct {
+	Level int `json:"level"`
+}
+
+type Hero struct {
+	Stats
+	Name      string            `json:"name"`
+	HP        uint16            `json:"hp"`
+	Speed     float64           `json:"speed"`
+	Alive     bool              `json:"alive"`
+	Inventory []string          `json:"inventory"`
+	Slots     map[string]int    `json:"slots"`
+	Position  [2]int32          `json:"position"`
+	Gold      *big.Int          `json:"gold"`
+	SpawnedAt time.Time         `json:"spawnedAt"`
+	Pet       *Hero             `json:"pet,omitempty"`
+	Metadata  map[string]any    `json:"metadata"`
+	Ignored   string            `json:"-"`
+	Secret    string            `json:"secret"`
+	Tags      map[int32]string  `json:"tags"`
+	Raw       []byte            `json:"raw"`
+	Empty     map[string]string `json:"empty"`
+}
+
+func newHero() Hero {
+	return Hero{
+		Stats:     Stats{Level: 7},
+		Name:      "aria",
+		HP:        300,
+		Speed:     1.5,
+		Alive:     true,
+		Inventory: []string{"sword", "shield"},
+		Slots:     map[string]int{"head": 1, "feet": 2},
+		Position:  [2]int32{-4, 9},
+		Gold:      big.NewInt(1_000_000),
+		SpawnedAt: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Pet:       &Hero{Name: "wolf", Gold: big.NewInt(0)},
+		Metadata:  map[string]any{"guild": "dawn"},
+		Secret:    "s",
+		Tags:      map[int32]string{2: "b", 1: "a"},
+		Raw:       []byte{1, 2, 3},
+	}
+}
+
+func TestMsgPackRoundTrip(t *testing.T) {
+	hero := newHero()
+	hero.Ignored = "not encoded"
+	bz, err := codec.EncodeWith(codec.MsgPack, hero)
+	assert.NilError(t, err)
+	assert.Assert(t, !codec.IsJSON(bz))
+
+	got, err := codec.Decode[Hero](bz)
+	assert.NilError(t, err)
+	hero.Ignored = ""
+	assert.Equal(t, 0, hero.Gold.Cmp(got.Gold))
+	assert.Equal(t, 0, hero.Pet.Gold.Cmp(got.Pet.Gold))
+	hero.Gold, got.Gold, hero.Pet.Gold, got.Pet.Gold = nil, nil, nil, nil
+	assert.DeepEqual(t, hero, got)
+}
+
+func TestMsgPackIsDeterministic(t *testing.T) {
+	first, err := codec.EncodeWith(codec.MsgPack, newHero())
+	assert.NilError(t, err)
+	for i := 0; i < 20; i++ {
+		bz, err := codec.EncodeWith(codec.MsgPack, newHero())
+		assert.NilError(t, err)
+		assert.DeepEqual(t, first, bz)
+	}
+}
+
+func TestMsgPackIsSmallerThanJSON(t *testing.T) {
+	jsonBz, err := codec.Encode(newHero())
+	assert.NilError(t, err)
+	msgpackBz, err := codec.EncodeWith(codec.MsgPack, newHero())
+	assert.NilError(t, err)
+	assert.Check(t, len(msgpackBz) < len(jsonBz), "msgpack %d bytes, json %d bytes", len(msgpackBz), len(jsonBz))
+}
+
+func TestDecodeReadsEveryCodec(t *testing.T) {
+	unit := Unit{X: 1, Y: 2, Path: [][2]int{{3, 4}}, Name: "scout"}
+	for _, c := range []codec.Codec{codec.JSON, codec.MsgPack} {
+		bz, err := codec.EncodeWith(c, unit)
+		assert.NilError(t, err)
+		got, err := codec.Decode[Unit](bz)
+		assert.NilError(t, err, c.Name())
+		assert.DeepEqual(t, unit, got)
+
+		// Only the selected fields are decoded, whatever the codec.
+		got, err = codec.DecodeFields[Unit](bz, []string{"Y", "Name"})
+		assert.NilError(t, err, c.Name())
+		assert.DeepEqual(t, Unit{Y: 2, Name: "scout"}, got)
+	}
+
+	_, err := codec.Decode[Unit]([]byte{0, 'z', 1})
+	assert.ErrorContains(t, err, "unknown codec ID")
+	_, err = codec.EncodeWith(codec.Protobuf, unit)
+	assert.ErrorContains(t, err, "is not a protobuf message")
+}
+
+func TestRegisterCodec(t *testing.T) {
+	assert.ErrorContains(t, codec.Register(jsonAlias{}), "reserved for JSON")
+	assert.NilError(t, codec.Register(codec.MsgPack))
+}
+
+// jsonAlias is a codec that claims the ID of JSON.
+type jsonAlias struct {
+	codec.Codec
+}
+
+func (jsonAlias) ID() byte {
+	return 0
+}
+
+func BenchmarkMsgPackEncode(b *testing.B) {
+	hero := newHero()
+	for i := 0; i < b.N; i++ {
+		if _, err := codec.EncodeWith(codec.MsgPack, hero); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkMsgPackDecode(b *testing.B) {
+	bz, err := codec.EncodeWith(codec.MsgPack, newHero())
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := codec.Decode[Hero](bz); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
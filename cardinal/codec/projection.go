@@ -0,0 +1,100 @@
+package codec
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/rotisserie/eris"
+)
+
+// projections caches the projections built by DecodeFields.
+var projections sync.Map // map[projectionKey]*projection
+
+type projectionKey struct {
+	typ    reflect.Type
+	fields string
+}
+
+// projection is a struct type that only has some of the fields of another struct type. Decoding into it skips the
+// values of all other fields, which is much cheaper than decoding the whole struct when the skipped fields hold large
+// slices or maps.
+type projection struct {
+	typ reflect.Type
+	// index is the index of each field of typ in the original struct type.
+	index []int
+}
+
+// DecodeFields decodes only the given fields of a struct from bz. All other fields of the returned value are left at
+// their zero value. Fields are named by their Go name, and must be exported fields of T itself; fields of embedded
+// structs can't be selected. Values encoded with a codec that can't skip fields, such as Protobuf, are decoded
+// entirely.
+func DecodeFields[T any](bz []byte, fields []string) (T, error) {
+	return DecodeFieldsWith[T](nil, bz, fields)
+}
+
+// DecodeFieldsWith is like DecodeFields, but a value with the header of the given codec is decoded with it. See
+// DecodeWith.
+func DecodeFieldsWith[T any](c Codec, bz []byte, fields []string) (T, error) {
+	var t T
+	p, err := projectionOf(reflect.TypeOf(t), fields)
+	if err != nil {
+		return t, err
+	}
+	used, body, err := codecOfWith(c, bz)
+	if err != nil {
+		return t, err
+	}
+	if enc, ok := used.(encryptedCodec); ok {
+		// The fields are selected from the decrypted value, with the codec that encoded it.
+		var plain []byte
+		if plain, err = enc.cipher.open(body, enc.location); err != nil {
+			return t, err
+		}
+		if used, body, err = codecOf(plain); err != nil {
+			return t, err
+		}
+	}
+	if used.ID() != JSON.ID() && used.ID() != MsgPack.ID() {
+		if err = used.Unmarshal(body, &t); err != nil {
+			return t, eris.Wrap(err, "")
+		}
+		return t, nil
+	}
+	partial := reflect.New(p.typ)
+	if err = used.Unmarshal(body, partial.Interface()); err != nil {
+		return t, eris.Wrap(err, "")
+	}
+	value := reflect.ValueOf(&t).Elem()
+	for i, index := range p.index {
+		value.Field(index).Set(partial.Elem().Field(i))
+	}
+	return t, nil
+}
+
+func projectionOf(typ reflect.Type, fields []string) (*projection, error) {
+	key := projectionKey{typ: typ, fields: strings.Join(fields, ",")}
+	if p, ok := projections.Load(key); ok {
+		return p.(*projection), nil //nolint:errcheck // only projections are stored
+	}
+	if typ == nil || typ.Kind() != reflect.Struct {
+		return nil, eris.Errorf("fields can only be selected from structs, not %v", typ)
+	}
+	p := &projection{index: make([]int, 0, len(fields))}
+	structFields := make([]reflect.StructField, 0, len(fields))
+	for _, name := range fields {
+		field, ok := typ.FieldByName(name)
+		if !ok || len(field.Index) != 1 || !field.IsExported() || field.Anonymous {
+			return nil, eris.Errorf("%s has no exported field %q", typ, name)
+		}
+		if slices.Contains(p.index, field.Index[0]) {
+			continue
+		}
+		p.index = append(p.index, field.Index[0])
+		structFields = append(structFields, reflect.StructField{Name: field.Name, Type: field.Type, Tag: field.Tag})
+	}
+	p.typ = reflect.StructOf(structFields)
+	projections.Store(key, p)
+	return p, nil
+}
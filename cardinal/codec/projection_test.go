@@ -0,0 +1,56 @@
+package codec_test
+
+import (
+	"testing"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal/codec"
+)
+
+type Unit struct {
+	X    int      `json:"x"`
+	Y    int      `json:"y"`
+	Path [][2]int `json:"path"`
+	Name string
+}
+
+func TestDecodeFields(t *testing.T) {
+	bz, err := codec.Encode(Unit{X: 1, Y: 2, Path: [][2]int{{1, 2}, {3, 4}}, Name: "scout"})
+	assert.NilError(t, err)
+
+	unit, err := codec.DecodeFields[Unit](bz, []string{"X", "Y"})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, Unit{X: 1, Y: 2}, unit)
+
+	unit, err = codec.DecodeFields[Unit](bz, []string{"Name", "Name"})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, Unit{Name: "scout"}, unit)
+
+	_, err = codec.DecodeFields[Unit](bz, []string{"x"})
+	assert.ErrorContains(t, err, `has no exported field "x"`)
+	_, err = codec.DecodeFields[int](bz, []string{"X"})
+	assert.IsError(t, err)
+}
+
+// BenchmarkDecodeFields compares decoding two fields of a component with a large field to decoding the whole component.
+func BenchmarkDecodeFields(b *testing.B) {
+	unit := Unit{X: 1, Y: 2, Path: make([][2]int, 1000)}
+	bz, err := codec.Encode(unit)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Run("all fields", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := codec.Decode[Unit](bz); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("projected fields", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := codec.DecodeFields[Unit](bz, []string{"X", "Y"}); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
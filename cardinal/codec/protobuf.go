@@ -0,0 +1,44 @@
+package codec
+
+import (
+	"reflect"
+
+	"github.com/rotisserie/eris"
+	"google.golang.org/protobuf/proto"
+)
+
+type protobufCodec struct{}
+
+func (protobufCodec) ID() byte {
+	return 'p'
+}
+
+func (protobufCodec) Name() string {
+	return "protobuf"
+}
+
+// Marshal encodes a proto.Message. Components are stored by value, so a value whose pointer is a proto.Message is
+// encoded as well.
+func (protobufCodec) Marshal(v any) ([]byte, error) {
+	msg, ok := v.(proto.Message)
+	if !ok {
+		rv := reflect.ValueOf(v)
+		if !rv.IsValid() {
+			return nil, eris.New("cannot encode nil with protobuf")
+		}
+		ptr := reflect.New(rv.Type())
+		ptr.Elem().Set(rv)
+		if msg, ok = ptr.Interface().(proto.Message); !ok {
+			return nil, eris.Errorf("%T is not a protobuf message", v)
+		}
+	}
+	return proto.MarshalOptions{Deterministic: true}.Marshal(msg)
+}
+
+func (protobufCodec) Unmarshal(bz []byte, v any) error {
+	msg, ok := v.(proto.Message)
+	if !ok {
+		return eris.Errorf("%T is not a protobuf message", v)
+	}
+	return proto.Unmarshal(bz, msg)
+}
@@ -3,6 +3,7 @@ package component
 import (
 	"fmt"
 	"reflect"
+	"strconv"
 
 	"github.com/invopop/jsonschema"
 	"github.com/rotisserie/eris"
@@ -28,6 +29,7 @@ type componentMetadata[T types.Component] struct {
 	name       string
 	schema     []byte
 	defaultVal types.Component
+	codec      codec.Codec
 }
 
 // NewComponentMetadata creates a new component type.
@@ -47,10 +49,17 @@ func NewComponentMetadata[T types.Component](opts ...Option[T]) (
 		compType: compType,
 		name:     t.Name(),
 		schema:   schema,
+		codec:    codec.JSON,
+	}
+	if d, ok := any(t).(types.ComponentDefaulter[T]); ok {
+		compMetadata.defaultVal = d.Default()
 	}
 	for _, opt := range opts {
 		opt(compMetadata)
 	}
+	if _, err = compMetadata.Encode(compMetadata.newValue()); err != nil {
+		return nil, eris.Wrapf(err, "component %q can't be encoded with %s", compMetadata.name, compMetadata.codec.Name())
+	}
 
 	return compMetadata, nil
 }
@@ -90,19 +99,53 @@ func (c *componentMetadata[T]) ID() types.ComponentID {
 	return c.id
 }
 
+// New encodes the default value of the component. It is never stored, so it is not encrypted.
 func (c *componentMetadata[T]) New() ([]byte, error) {
+	return codec.EncodeWith(codec.Plain(c.codec), c.newValue())
+}
+
+func (c *componentMetadata[T]) newValue() any {
 	if c.defaultVal != nil {
-		return codec.Encode(c.defaultVal)
+		return c.defaultVal
 	}
-	return codec.Encode(c.compType)
+	var t T
+	return t
+}
+
+func (c *componentMetadata[T]) DefaultValue() (types.Component, bool) {
+	return c.defaultVal, c.defaultVal != nil
 }
 
+// Encode encodes a value of the component with the codec of the component.
 func (c *componentMetadata[T]) Encode(v any) ([]byte, error) {
-	return codec.Encode(v)
+	return codec.EncodeWith(c.codec, v)
 }
 
+// Decode decodes a value of the component. Values that were encoded with another codec than the current one of the
+// component, e.g. before the codec of the component changed, are decoded as well.
 func (c *componentMetadata[T]) Decode(bz []byte) (types.Component, error) {
-	return codec.Decode[T](bz)
+	return codec.DecodeWith[T](c.codec, bz)
+}
+
+func (c *componentMetadata[T]) DecodeFields(bz []byte, fields []string) (types.Component, error) {
+	return codec.DecodeFieldsWith[T](c.codec, bz, fields)
+}
+
+func (c *componentMetadata[T]) EncodeFor(id types.EntityID, v any) ([]byte, error) {
+	return codec.EncodeWith(c.codecFor(id), v)
+}
+
+func (c *componentMetadata[T]) DecodeFor(id types.EntityID, bz []byte) (types.Component, error) {
+	return codec.DecodeWith[T](c.codecFor(id), bz)
+}
+
+func (c *componentMetadata[T]) DecodeFieldsFor(id types.EntityID, bz []byte, fields []string) (types.Component, error) {
+	return codec.DecodeFieldsWith[T](c.codecFor(id), bz, fields)
+}
+
+// codecFor returns the codec of the component, with its encrypted values bound to the component of the given entity.
+func (c *componentMetadata[T]) codecFor(id types.EntityID) codec.Codec {
+	return codec.At(c.codec, "component:"+c.name+":"+strconv.FormatUint(uint64(id), 10))
 }
 
 func (c *componentMetadata[T]) ValidateAgainstSchema(targetSchema []byte) error {
@@ -124,10 +167,31 @@ func (c *componentMetadata[T]) validateDefaultVal() {
 	}
 }
 
-// WithDefault updated the created componentMetadata with a default value.
+// WithDefault updated the created componentMetadata with a default value. It takes precedence over the Default method
+// of the component.
 func WithDefault[T types.Component](defaultVal T) Option[T] {
 	return func(c *componentMetadata[T]) {
 		c.defaultVal = defaultVal
 		c.validateDefaultVal()
 	}
 }
+
+// WithCodec sets the codec that values of the component are stored with. Values that were stored with another codec
+// can still be read, and are stored with the new codec the next time they are set.
+func WithCodec[T types.Component](c codec.Codec) Option[T] {
+	return func(m *componentMetadata[T]) {
+		if c != nil {
+			m.codec = c
+		}
+	}
+}
+
+// WithEncryption encrypts the values of the component with the given keys, after they are encoded with the codec of
+// the component. See codec.Encrypted. It must come after WithCodec.
+func WithEncryption[T types.Component](keys codec.KeyProvider) Option[T] {
+	return func(m *componentMetadata[T]) {
+		if keys != nil {
+			m.codec = codec.Encrypted(m.codec, keys)
+		}
+	}
+}
@@ -0,0 +1,223 @@
+package cardinal_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"testing"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/codec"
+	"pkg.world.dev/world-engine/cardinal/component"
+	"pkg.world.dev/world-engine/cardinal/testutils"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+// storedComponent returns the stored bytes of the only component of the given entity.
+func storedComponent(t *testing.T, tf *testutils.TestFixture, id types.EntityID) []byte {
+	suffix := fmt.Sprintf(":ENTITY-ID-%d", id)
+	for _, key := range tf.Redis.Keys() {
+		if strings.Contains(key, "COMPONENT-VALUE") && strings.HasSuffix(key, suffix) {
+			value, err := tf.Redis.Get(key)
+			assert.NilError(t, err)
+			return []byte(value)
+		}
+	}
+	t.Fatalf("no component is stored for entity %d", id)
+	return nil
+}
+
+func TestComponentCodecMigration(t *testing.T) {
+	tf1 := testutils.NewTestFixture(t, nil)
+	assert.NilError(t, cardinal.RegisterComponent[EnergyComponent](tf1.World))
+	tf1.StartWorld()
+	wCtx := cardinal.NewWorldContext(tf1.World)
+	ids, err := cardinal.CreateMany(wCtx, 2, EnergyComponent{Amt: 1, Cap: 10})
+	assert.NilError(t, err)
+	tf1.DoTick()
+	assert.Assert(t, codec.IsJSON(storedComponent(t, tf1, ids[0])))
+
+	// The world restarts with msgpack, and can still read the components that were stored as JSON.
+	tf1.Shutdown()
+	tf2 := testutils.NewTestFixture(t, tf1.Redis, cardinal.WithComponentCodec(codec.MsgPack))
+	assert.NilError(t, cardinal.RegisterComponent[EnergyComponent](tf2.World))
+	assert.NilError(t, cardinal.RegisterComponent[ScalarComponentAlpha](
+		tf2.World, component.WithCodec[ScalarComponentAlpha](codec.JSON),
+	))
+	tf2.StartWorld()
+	wCtx = cardinal.NewWorldContext(tf2.World)
+	energy, err := cardinal.GetComponent[EnergyComponent](wCtx, ids[0])
+	assert.NilError(t, err)
+	assert.Equal(t, EnergyComponent{Amt: 1, Cap: 10}, *energy)
+
+	// Components are stored with msgpack once they are set, unless their component uses another codec.
+	assert.NilError(t, cardinal.SetComponent(wCtx, ids[0], &EnergyComponent{Amt: 2, Cap: 10}))
+	alphaID, err := cardinal.Create(wCtx, ScalarComponentAlpha{Val: 3})
+	assert.NilError(t, err)
+	tf2.DoTick()
+	stored := storedComponent(t, tf2, ids[0])
+	assert.Assert(t, !codec.IsJSON(stored))
+	assert.Assert(t, codec.IsJSON(storedComponent(t, tf2, ids[1])))
+	assert.Assert(t, codec.IsJSON(storedComponent(t, tf2, alphaID)))
+	energyValue, err := codec.Decode[EnergyComponent](stored)
+	assert.NilError(t, err)
+	assert.Equal(t, EnergyComponent{Amt: 2, Cap: 10}, energyValue)
+
+	// Reads of the stored components always see JSON.
+	readOnly := cardinal.NewReadOnlyWorldContext(tf2.World)
+	cType, err := readOnly.GetComponentByName(EnergyComponent{}.Name())
+	assert.NilError(t, err)
+	for i, want := range []string{`{"Amt":2,"Cap":10}`, `{"Amt":1,"Cap":10}`} {
+		bz, err := readOnly.StoreReader().GetComponentForEntityInRawJSON(cType, ids[i])
+		assert.NilError(t, err)
+		assert.Equal(t, want, string(bz))
+	}
+}
+
+func TestComponentEncryption(t *testing.T) {
+	key := bytes.Repeat([]byte{7}, 32)
+	tf1 := testutils.NewTestFixture(t, nil, cardinal.WithComponentEncryption(codec.NewStaticKey(key)))
+	assert.NilError(t, cardinal.RegisterComponent[EnergyComponent](tf1.World))
+	// Components with their own codec are encrypted as well.
+	assert.NilError(t, cardinal.RegisterComponent[ScalarComponentAlpha](
+		tf1.World, component.WithCodec[ScalarComponentAlpha](codec.MsgPack),
+	))
+	tf1.StartWorld()
+	wCtx := cardinal.NewWorldContext(tf1.World)
+	energyID, err := cardinal.Create(wCtx, EnergyComponent{Amt: 1, Cap: 10})
+	assert.NilError(t, err)
+	alphaID, err := cardinal.Create(wCtx, ScalarComponentAlpha{Val: 3})
+	assert.NilError(t, err)
+	tf1.DoTick()
+	for _, id := range []types.EntityID{energyID, alphaID} {
+		stored := storedComponent(t, tf1, id)
+		assert.Assert(t, !codec.IsJSON(stored))
+		assert.Assert(t, !bytes.Contains(stored, []byte("Amt")) && !bytes.Contains(stored, []byte("Val")))
+	}
+
+	// The world restarts with the same key from the config, and reads the encrypted components.
+	t.Setenv("CARDINAL_ENCRYPTION_KEY", hex.EncodeToString(key))
+	tf1.Shutdown()
+	tf2 := testutils.NewTestFixture(t, tf1.Redis)
+	assert.NilError(t, cardinal.RegisterComponent[EnergyComponent](tf2.World))
+	assert.NilError(t, cardinal.RegisterComponent[ScalarComponentAlpha](tf2.World))
+	tf2.StartWorld()
+	readOnly := cardinal.NewReadOnlyWorldContext(tf2.World)
+	energy, err := cardinal.GetComponent[EnergyComponent](readOnly, energyID)
+	assert.NilError(t, err)
+	assert.Equal(t, EnergyComponent{Amt: 1, Cap: 10}, *energy)
+	cType, err := readOnly.GetComponentByName(EnergyComponent{}.Name())
+	assert.NilError(t, err)
+	bz, err := readOnly.StoreReader().GetComponentForEntityInRawJSON(cType, energyID)
+	assert.NilError(t, err)
+	assert.Equal(t, `{"Amt":1,"Cap":10}`, string(bz))
+}
+
+func TestEncryptedValuesAreBoundToWhereTheyAreStored(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil,
+		cardinal.WithComponentEncryption(codec.NewStaticKey(bytes.Repeat([]byte{7}, 32))))
+	assert.NilError(t, cardinal.RegisterComponent[EnergyComponent](tf.World))
+	tf.StartWorld()
+	wCtx := cardinal.NewWorldContext(tf.World)
+	ids, err := cardinal.CreateMany(wCtx, 2, EnergyComponent{Amt: 1, Cap: 10})
+	assert.NilError(t, err)
+	assert.NilError(t, cardinal.SetComponent[EnergyComponent](wCtx, ids[0], &EnergyComponent{Amt: 100, Cap: 100}))
+	store, err := cardinal.NewRawStorage(wCtx, "profiles")
+	assert.NilError(t, err)
+	assert.NilError(t, store.Set("aria", []byte("admin")))
+	assert.NilError(t, store.Set("bob", []byte("player")))
+	tf.DoTick()
+
+	// Copy the stored component of the first entity over the component of the second one, and the raw value of aria
+	// over the value of bob.
+	storedKey := func(prefix, suffix string) string {
+		for _, key := range tf.Redis.Keys() {
+			if strings.Contains(key, prefix) && strings.HasSuffix(key, suffix) {
+				return key
+			}
+		}
+		t.Fatalf("no %s value is stored at %s", prefix, suffix)
+		return ""
+	}
+	moveValue := func(from, to string) {
+		value, err := tf.Redis.Get(from)
+		assert.NilError(t, err)
+		assert.NilError(t, tf.Redis.Set(to, value))
+	}
+	moveValue(storedKey("COMPONENT-VALUE", fmt.Sprintf(":ENTITY-ID-%d", ids[0])),
+		storedKey("COMPONENT-VALUE", fmt.Sprintf(":ENTITY-ID-%d", ids[1])))
+	moveValue(storedKey("RAW", ":aria"), storedKey("RAW", ":bob"))
+
+	readOnly := cardinal.NewReadOnlyWorldContext(tf.World)
+	_, err = cardinal.GetComponent[EnergyComponent](readOnly, ids[1])
+	assert.ErrorIs(t, err, codec.ErrDecryptionFailed)
+	readStore, err := cardinal.NewRawStorage(readOnly, "profiles")
+	assert.NilError(t, err)
+	_, _, err = readStore.Get("bob")
+	assert.ErrorIs(t, err, codec.ErrDecryptionFailed)
+
+	// The values are still read where they were stored.
+	energy, err := cardinal.GetComponent[EnergyComponent](readOnly, ids[0])
+	assert.NilError(t, err)
+	assert.Equal(t, EnergyComponent{Amt: 100, Cap: 100}, *energy)
+	value, ok, err := readStore.Get("aria")
+	assert.NilError(t, err)
+	assert.Assert(t, ok)
+	assert.Equal(t, "admin", string(value))
+}
+
+func TestWorldsEncryptWithTheirOwnKeys(t *testing.T) {
+	newWorld := func(key byte) (*testutils.TestFixture, types.EntityID) {
+		tf := testutils.NewTestFixture(t, nil,
+			cardinal.WithComponentEncryption(codec.NewStaticKey(bytes.Repeat([]byte{key}, 32))),
+			cardinal.WithEventHistory(cardinal.EventRetention{}))
+		assert.NilError(t, cardinal.RegisterComponent[EnergyComponent](tf.World))
+		assert.NilError(t, cardinal.RegisterSystems(tf.World, func(wCtx engine.Context) error {
+			return wCtx.EmitEvent(map[string]any{"email": "aria@example.com"})
+		}))
+		tf.StartWorld()
+		wCtx := cardinal.NewWorldContext(tf.World)
+		id, err := cardinal.Create(wCtx, EnergyComponent{Amt: int64(key), Cap: 10})
+		assert.NilError(t, err)
+		store, err := cardinal.NewRawStorage(wCtx, "profiles")
+		assert.NilError(t, err)
+		assert.NilError(t, store.Set("aria", []byte("aria@example.com")))
+		tf.DoTick()
+		return tf, id
+	}
+	// Both worlds run in the same process, so neither may decrypt with the keys of the other.
+	tf1, id1 := newWorld(1)
+	tf2, id2 := newWorld(2)
+
+	for i, world := range []struct {
+		tf *testutils.TestFixture
+		id types.EntityID
+	}{{tf1, id1}, {tf2, id2}} {
+		readOnly := cardinal.NewReadOnlyWorldContext(world.tf.World)
+		energy, err := cardinal.GetComponent[EnergyComponent](readOnly, world.id)
+		assert.NilError(t, err)
+		assert.Equal(t, EnergyComponent{Amt: int64(i + 1), Cap: 10}, *energy)
+
+		// Raw storage values and the events in the tick log are encrypted as well.
+		store, err := cardinal.NewRawStorage(readOnly, "profiles")
+		assert.NilError(t, err)
+		value, ok, err := store.Get("aria")
+		assert.NilError(t, err)
+		assert.Assert(t, ok)
+		assert.Equal(t, "aria@example.com", string(value))
+		ticks, _, err := world.tf.World.GetEventHistory(0, 0)
+		assert.NilError(t, err)
+		assert.Assert(t, len(ticks) > 0)
+		for _, key := range world.tf.Redis.Keys() {
+			stored, err := world.tf.Redis.Get(key)
+			if err != nil {
+				// Not a string
+				continue
+			}
+			assert.Assert(t, !strings.Contains(stored, "aria"), "%q is stored unencrypted", key)
+		}
+	}
+}
@@ -1,16 +1,25 @@
 package cardinal
 
 import (
+	"encoding/hex"
+	"flag"
+	"fmt"
 	"net"
 	"os"
+	"reflect"
 	"slices"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/JeremyLoy/config"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/rotisserie/eris"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
+	"gopkg.in/yaml.v3"
 
+	"pkg.world.dev/world-engine/cardinal/server"
 	"pkg.world.dev/world-engine/rift/credentials"
 )
 
@@ -19,6 +28,17 @@ const (
 	DefaultCardinalLogLevel          = "info"
 	DefaultRedisAddress              = "localhost:6379"
 	DefaultBaseShardSequencerAddress = "localhost:9601"
+	DefaultCardinalTickRate          = 1
+
+	// ConfigFileEnv is the environment variable with the path of an optional YAML config file, which NewWorld reads
+	// before the other environment variables. See LoadConfig.
+	ConfigFileEnv = "CARDINAL_CONFIG_FILE"
+
+	// MaxCardinalTickRate is the maximum number of ticks per second.
+	MaxCardinalTickRate = 1000
+
+	configTag     = "config"
+	redactedValue = "<redacted>"
 )
 
 var (
@@ -32,32 +52,129 @@ var (
 
 	defaultConfig = WorldConfig{
 		CardinalNamespace:         DefaultCardinalNamespace,
+		CardinalProfile:           "",
+		CardinalTickRate:          DefaultCardinalTickRate,
+		CardinalTickDriftMode:     string(DriftJump),
 		CardinalRollupEnabled:     false,
+		CardinalEVMServerEnabled:  true,
+		CardinalEVMServerCertPath: "",
+		CardinalEVMServerKeyPath:  "",
+		CardinalEVMServerCAPath:   "",
 		CardinalLogPretty:         false,
 		CardinalLogLevel:          DefaultCardinalLogLevel,
+		CardinalStrictMode:        false,
+		CardinalDeterminismAudit:  false,
+		CardinalAdminToken:        "",
+		CardinalAdminSigners:      "",
+		CardinalGenesisFile:       "",
+		CardinalCQLFilters:        false,
+		CardinalEncryptionKey:     "",
+		CardinalAPIKeys:           "",
+		CardinalJWTSecret:         "",
+		CardinalJWTIssuer:         "",
+		CardinalJWTAudience:       "",
+		CardinalCORSOrigins:       "",
 		RedisAddress:              DefaultRedisAddress,
 		RedisPassword:             "",
 		BaseShardSequencerAddress: DefaultBaseShardSequencerAddress,
 		BaseShardRouterKey:        "",
+		BaseShardAttestationKey:   "",
 		TelemetryEnabled:          false,
 		TelemetryStatsdAddress:    "",
 		TelemetryTraceAddress:     "",
+		TelemetryOTLPAddress:      "",
+	}
+
+	// secretConfigKeys are the config values that are redacted from the debug endpoint.
+	secretConfigKeys = []string{
+		"CARDINAL_ADMIN_TOKEN", "CARDINAL_ENCRYPTION_KEY", "CARDINAL_API_KEYS", "CARDINAL_JWT_SECRET", "REDIS_PASSWORD",
+		"BASE_SHARD_ROUTER_KEY",
 	}
 )
 
+// Config is the configuration of a world. It can be loaded with LoadConfig, or built in code and passed to NewWorld
+// with WithConfig.
+type Config = WorldConfig
+
 type WorldConfig struct {
 	// CardinalNamespace The shard namespace for Cardinal. This needs to be unique to prevent signature replay attacks.
 	CardinalNamespace string `config:"CARDINAL_NAMESPACE"`
 
+	// CardinalProfile The profile whose defaults are used for the values that are not set, one of "dev", "staging"
+	// or "prod". See Profile.
+	CardinalProfile string `config:"CARDINAL_PROFILE"`
+
+	// CardinalTickRate The number of ticks per second.
+	CardinalTickRate uint64 `config:"CARDINAL_TICK_RATE"`
+
+	// CardinalTickDriftMode How ticks that start late catch up, either "jump" or "slew". See DriftMode.
+	CardinalTickDriftMode string `config:"CARDINAL_TICK_DRIFT_MODE"`
+
 	// CardinalRollupEnabled When true, Cardinal will sequence and recover to/from base shard.
 	CardinalRollupEnabled bool `config:"CARDINAL_ROLLUP_ENABLED"`
 
+	// CardinalEVMServerEnabled When true and rollup mode is enabled, Cardinal serves the gRPC server that the base
+	// shard forwards EVM transactions and queries to.
+	CardinalEVMServerEnabled bool `config:"CARDINAL_EVM_SERVER_ENABLED"`
+
+	// CardinalEVMServerCertPath and CardinalEVMServerKeyPath The PEM encoded certificate and key of the EVM gRPC server.
+	// When set, the server only accepts TLS connections. The files are read again when they change, so that the
+	// certificate can be rotated without restarting Cardinal.
+	CardinalEVMServerCertPath string `config:"CARDINAL_EVM_SERVER_CERT_PATH"`
+	CardinalEVMServerKeyPath  string `config:"CARDINAL_EVM_SERVER_KEY_PATH"`
+
+	// CardinalEVMServerCAPath The PEM encoded CAs of the client certificates that the EVM gRPC server requires,
+	// e.g. the CA of the base shard router's certificate. Like the server certificate, it can be rotated.
+	CardinalEVMServerCAPath string `config:"CARDINAL_EVM_SERVER_CA_PATH"`
+
 	// CardinalLogLevel Determines the log level for Cardinal.
 	CardinalLogLevel string `config:"CARDINAL_LOG_LEVEL"`
 
 	// CardinalLogPretty Pretty logging, disable by default due to performance impact.
 	CardinalLogPretty bool `config:"CARDINAL_LOG_PRETTY"`
 
+	// CardinalStrictMode When true, ticks fail if systems leave goroutines running or call Now or GlobalRand.
+	// Recommended during development.
+	CardinalStrictMode bool `config:"CARDINAL_STRICT_MODE"`
+
+	// CardinalDeterminismAudit When true, every tick is run twice and fails if its systems diverge. Doubles tick time.
+	CardinalDeterminismAudit bool `config:"CARDINAL_DETERMINISM_AUDIT"`
+
+	// CardinalAdminToken When set, the admin gRPC service is enabled on its default port and requires this token.
+	CardinalAdminToken string `config:"CARDINAL_ADMIN_TOKEN"`
+
+	// CardinalAdminSigners A comma separated list of the addresses that can sign transactions of admin-only messages.
+	CardinalAdminSigners string `config:"CARDINAL_ADMIN_SIGNERS"`
+
+	// CardinalGenesisFile The path of a JSON or YAML file with the entities that are created at tick 0.
+	CardinalGenesisFile string `config:"CARDINAL_GENESIS_FILE"`
+
+	// CardinalCQLFilters When true, CQL queries can compare the fields of components. Recommended during development.
+	CardinalCQLFilters bool `config:"CARDINAL_CQL_FILTERS"`
+
+	// CardinalEncryptionKey When set, components are encrypted with this hex encoded 16, 24 or 32 byte AES key before
+	// they are stored. See WithComponentEncryption.
+	CardinalEncryptionKey string `config:"CARDINAL_ENCRYPTION_KEY"`
+
+	// CardinalAPIKeys A comma separated list of API keys. When set, the routes of the HTTP server that are not public
+	// require one of them, or a JWT if CARDINAL_JWT_SECRET is set. See WithHTTPAuth.
+	CardinalAPIKeys string `config:"CARDINAL_API_KEYS"`
+
+	// CardinalJWTSecret When set, the routes of the HTTP server that are not public require a JWT signed with this
+	// secret (HS256), or an API key of CARDINAL_API_KEYS. See WithHTTPAuth.
+	CardinalJWTSecret string `config:"CARDINAL_JWT_SECRET"`
+
+	// CardinalJWTIssuer When set, the JWTs must have this "iss" claim. It requires CARDINAL_JWT_SECRET.
+	CardinalJWTIssuer string `config:"CARDINAL_JWT_ISSUER"`
+
+	// CardinalJWTAudience When set, the "aud" claim of the JWTs must contain this audience. It requires
+	// CARDINAL_JWT_SECRET.
+	CardinalJWTAudience string `config:"CARDINAL_JWT_AUDIENCE"`
+
+	// CardinalCORSOrigins A comma separated list of the origins of the browser-based games that can call the HTTP
+	// server, e.g. "https://game.example.com". Every origin is allowed if it is empty. See WithHTTPLimits.
+	CardinalCORSOrigins string `config:"CARDINAL_CORS_ORIGINS"`
+
 	// RedisAddress The address of the redis server, supports unix sockets.
 	RedisAddress string `config:"REDIS_ADDRESS"`
 
@@ -70,6 +187,11 @@ type WorldConfig struct {
 	// BaseShardRouterKey is a token used to secure communications between the game shard and the base shard.
 	BaseShardRouterKey string `config:"BASE_SHARD_ROUTER_KEY"`
 
+	// BaseShardAttestationKey is the hex encoded ed25519 public key of the base shard router's attestation key. When
+	// set, Cardinal only accepts the messages from the base shard that the router signed with the attestation key, so
+	// that nobody else can send transactions on behalf of EVM accounts, even if they know the router key.
+	BaseShardAttestationKey string `config:"BASE_SHARD_ATTESTATION_KEY"`
+
 	// TelemetryEnabled When true, Cardinal will send telemetry to a telemetry agent.
 	TelemetryEnabled bool `config:"TELEMETRY_ENABLED"`
 
@@ -78,26 +200,118 @@ type WorldConfig struct {
 
 	// TelemetryTraceAddress The address of an agent that supports the collection of traces (e.g. a DataDog agent).
 	TelemetryTraceAddress string `config:"TELEMETRY_TRACE_ADDRESS"`
+
+	// TelemetryOTLPAddress The address of a collector that accepts OpenTelemetry traces over OTLP/gRPC (e.g. an
+	// OpenTelemetry collector or Jaeger). Cardinal emits a span for every tick, system and submitted transaction.
+	TelemetryOTLPAddress string `config:"TELEMETRY_OTLP_ADDRESS"`
 }
 
-func loadWorldConfig() (*WorldConfig, error) {
-	cfg := defaultConfig
+func loadWorldConfig(profile Profile) (*WorldConfig, error) {
+	cfg, err := loadConfig(profile, os.Getenv(ConfigFileEnv), nil)
+	if err != nil {
+		return nil, err
+	}
 
-	if err := config.FromEnv().To(&cfg); err != nil {
-		return nil, eris.Wrap(err, "Failed to load config")
+	if err = cfg.setLogger(); err != nil {
+		return nil, eris.Wrap(err, "Failed to set log level")
 	}
 
+	return cfg, nil
+}
+
+// DefaultConfig returns the config that is used for the values that are not set. Start from it when building a
+// config in code for WithConfig.
+func DefaultConfig() Config {
+	return defaultConfig
+}
+
+// worldConfig returns the config set with WithConfig, or loads it from the environment if none is set. The profile set
+// with WithProfile is used unless the config sets another one.
+func worldConfig(opts []WorldOption) (*WorldConfig, error) {
+	var cfg *WorldConfig
+	var profile Profile
+	for _, opt := range opts {
+		if opt.config != nil {
+			cfg = opt.config
+		}
+		if opt.profile != "" {
+			profile = opt.profile
+		}
+	}
+	if cfg == nil {
+		return loadWorldConfig(profile)
+	}
+	if cfg.CardinalProfile == "" {
+		cfg.CardinalProfile = string(profile)
+	}
 	if err := cfg.Validate(); err != nil {
 		return nil, eris.Wrap(err, "Invalid config")
 	}
-
 	if err := cfg.setLogger(); err != nil {
 		return nil, eris.Wrap(err, "Failed to set log level")
 	}
+	return cfg, nil
+}
+
+// LoadConfig loads the world config from, in increasing order of precedence: the defaults, the defaults of the profile
+// set by CARDINAL_PROFILE, the YAML file at path, the environment variables, and the command line flags in args. The
+// file is skipped if path is empty. The file and the flags use the names of the environment variables, e.g.
+// `CARDINAL_TICK_RATE: 10` in the file and `-cardinal-tick-rate=10` on the command line. The loaded config is
+// validated.
+func LoadConfig(path string, args []string) (*Config, error) {
+	return loadConfig("", path, args)
+}
+
+// loadConfig loads the world config like LoadConfig, with the given profile unless the sources set another one.
+func loadConfig(profile Profile, path string, args []string) (*Config, error) {
+	// The profile can be set by any of the sources, so they are read once to find it, and again on top of the
+	// defaults of the profile.
+	cfg := defaultConfig
+	cfg.CardinalProfile = string(profile)
+	if err := cfg.loadSources(path, args); err != nil {
+		return nil, err
+	}
+	if profile = Profile(cfg.CardinalProfile); profile != "" {
+		if err := profile.Validate(); err != nil {
+			return nil, eris.Wrap(err, "Invalid config")
+		}
+		cfg = defaultConfig
+		cfg.CardinalProfile = string(profile)
+		profile.apply(&cfg)
+		if err := cfg.loadSources(path, args); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, eris.Wrap(err, "Invalid config")
+	}
 
 	return &cfg, nil
 }
 
+// loadSources sets the config values that are set by the YAML file at path, the environment variables, and the
+// command line flags in args.
+func (w *WorldConfig) loadSources(path string, args []string) error {
+	if path != "" {
+		if err := w.loadFile(path); err != nil {
+			return eris.Wrap(err, "Failed to load config file")
+		}
+	}
+
+	if err := config.FromEnv().To(w); err != nil {
+		return eris.Wrap(err, "Failed to load config")
+	}
+
+	if len(args) > 0 {
+		if err := w.loadFlags(args); err != nil {
+			return eris.Wrap(err, "Failed to load config flags")
+		}
+	}
+
+	return nil
+}
+
 // Validate validates the config values.
 // If CARDINAL_ROLLUP=true, the BASE_SHARD_SEQUENCER_ADDRESS and BASE_SHARD_ROUTER_KEY are required.
 func (w *WorldConfig) Validate() error { //nolint:gocognit
@@ -105,10 +319,35 @@ func (w *WorldConfig) Validate() error { //nolint:gocognit
 	if err := Namespace(w.CardinalNamespace).Validate(); err != nil {
 		return eris.Wrap(err, "CARDINAL_NAMESPACE is not a valid namespace")
 	}
+	if err := Profile(w.CardinalProfile).Validate(); err != nil {
+		return eris.Wrap(err, "CARDINAL_PROFILE is not a valid profile")
+	}
+	if w.CardinalTickRate == 0 || w.CardinalTickRate > MaxCardinalTickRate {
+		return eris.Errorf("CARDINAL_TICK_RATE must be between 1 and %d", MaxCardinalTickRate)
+	}
+	if mode := DriftMode(w.CardinalTickDriftMode); mode != DriftJump && mode != DriftSlew {
+		return eris.Errorf("CARDINAL_TICK_DRIFT_MODE must be either %s or %s", DriftJump, DriftSlew)
+	}
 	if w.CardinalLogLevel == "" || !slices.Contains(validLogLevels, w.CardinalLogLevel) {
 		return eris.New("CARDINAL_LOG_LEVEL must be one of the following: " + strings.Join(validLogLevels, ", "))
 	}
 
+	if w.CardinalEncryptionKey != "" {
+		if _, err := w.encryptionKey(); err != nil {
+			return err
+		}
+	}
+
+	if w.CardinalJWTSecret == "" && (w.CardinalJWTIssuer != "" || w.CardinalJWTAudience != "") {
+		return eris.New("CARDINAL_JWT_ISSUER and CARDINAL_JWT_AUDIENCE require CARDINAL_JWT_SECRET")
+	}
+
+	for _, address := range w.adminSigners() {
+		if !common.IsHexAddress(address) {
+			return eris.Errorf("CARDINAL_ADMIN_SIGNERS contains an invalid address %q", address)
+		}
+	}
+
 	// Validate Redis address
 	if _, _, err := net.SplitHostPort(w.RedisAddress); err != nil {
 		return eris.New("REDIS_ADDRESS must follow the format <host>:<port>")
@@ -125,6 +364,17 @@ func (w *WorldConfig) Validate() error { //nolint:gocognit
 		if err := credentials.ValidateKey(w.BaseShardRouterKey); err != nil {
 			return err
 		}
+		if (w.CardinalEVMServerCertPath == "") != (w.CardinalEVMServerKeyPath == "") {
+			return eris.New("CARDINAL_EVM_SERVER_CERT_PATH and CARDINAL_EVM_SERVER_KEY_PATH must be set together")
+		}
+		if w.CardinalEVMServerCAPath != "" && w.CardinalEVMServerCertPath == "" {
+			return eris.New("CARDINAL_EVM_SERVER_CA_PATH requires CARDINAL_EVM_SERVER_CERT_PATH")
+		}
+		if w.BaseShardAttestationKey != "" {
+			if _, err := credentials.ParseAttestationPublicKey(w.BaseShardAttestationKey); err != nil {
+				return eris.Wrap(err, "BASE_SHARD_ATTESTATION_KEY is not a valid public key")
+			}
+		}
 	}
 
 	// Validate telemetry configs
@@ -140,6 +390,12 @@ func (w *WorldConfig) Validate() error { //nolint:gocognit
 				return eris.New("TELEMETRY_TRACE_ADDRESS must follow the format <host>:<port>")
 			}
 		}
+
+		if w.TelemetryOTLPAddress != "" {
+			if _, _, err := net.SplitHostPort(w.TelemetryOTLPAddress); err != nil {
+				return eris.New("TELEMETRY_OTLP_ADDRESS must follow the format <host>:<port>")
+			}
+		}
 	}
 
 	return nil
@@ -160,3 +416,145 @@ func (w *WorldConfig) setLogger() error {
 
 	return nil
 }
+
+// adminSigners returns the addresses in CARDINAL_ADMIN_SIGNERS.
+func (w *WorldConfig) adminSigners() []string {
+	var signers []string
+	for _, address := range strings.Split(w.CardinalAdminSigners, ",") {
+		if address = strings.TrimSpace(address); address != "" {
+			signers = append(signers, address)
+		}
+	}
+	return signers
+}
+
+// corsOrigins returns the origins in CARDINAL_CORS_ORIGINS.
+func (w *WorldConfig) corsOrigins() []string {
+	var origins []string
+	for _, origin := range strings.Split(w.CardinalCORSOrigins, ",") {
+		if origin = strings.TrimSpace(origin); origin != "" {
+			origins = append(origins, origin)
+		}
+	}
+	return origins
+}
+
+// httpAuth returns the authentication of the HTTP server that is set by CARDINAL_API_KEYS and CARDINAL_JWT_SECRET, or
+// nil if neither is set. The JWTs are checked against CARDINAL_JWT_ISSUER and CARDINAL_JWT_AUDIENCE if they are set.
+func (w *WorldConfig) httpAuth() *server.Auth {
+	var keys []string
+	for _, key := range strings.Split(w.CardinalAPIKeys, ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 && w.CardinalJWTSecret == "" {
+		return nil
+	}
+	auth := &server.Auth{APIKeys: keys}
+	if w.CardinalJWTSecret != "" {
+		auth.JWT = &server.JWTAuth{
+			Secret:   []byte(w.CardinalJWTSecret),
+			Issuer:   w.CardinalJWTIssuer,
+			Audience: w.CardinalJWTAudience,
+		}
+	}
+	return auth
+}
+
+// encryptionKey returns the decoded CARDINAL_ENCRYPTION_KEY.
+func (w *WorldConfig) encryptionKey() ([]byte, error) {
+	key, err := hex.DecodeString(strings.TrimPrefix(w.CardinalEncryptionKey, "0x"))
+	if err != nil {
+		return nil, eris.New("CARDINAL_ENCRYPTION_KEY must be hex encoded")
+	}
+	if len(key) != 16 && len(key) != 24 && len(key) != 32 { //nolint:gomnd // AES-128, AES-192 and AES-256
+		return nil, eris.Errorf("CARDINAL_ENCRYPTION_KEY must be 16, 24 or 32 bytes long, not %d", len(key))
+	}
+	return key, nil
+}
+
+// tickInterval returns the time between two ticks.
+func (w *WorldConfig) tickInterval() time.Duration {
+	return time.Second / time.Duration(w.CardinalTickRate)
+}
+
+// Redacted returns the config values keyed by their environment variable, with the secrets redacted. It is served by
+// the /debug/config endpoint.
+func (w *WorldConfig) Redacted() map[string]any {
+	values := map[string]any{}
+	rv := reflect.ValueOf(w).Elem()
+	for i := 0; i < rv.NumField(); i++ {
+		key := rv.Type().Field(i).Tag.Get(configTag)
+		value := rv.Field(i).Interface()
+		if slices.Contains(secretConfigKeys, key) && !rv.Field(i).IsZero() {
+			value = redactedValue
+		}
+		values[key] = value
+	}
+	return values
+}
+
+// loadFile sets the config values that are set in a YAML file, keyed by their environment variable.
+func (w *WorldConfig) loadFile(path string) error {
+	bz, err := os.ReadFile(path)
+	if err != nil {
+		return eris.Wrap(err, "")
+	}
+	var values map[string]any
+	if err = yaml.Unmarshal(bz, &values); err != nil {
+		return eris.Wrapf(err, "%s is not valid YAML", path)
+	}
+	for key, value := range values {
+		if err = w.set(key, fmt.Sprint(value)); err != nil {
+			return eris.Wrap(err, path)
+		}
+	}
+	return nil
+}
+
+// loadFlags sets the config values that are set by command line flags. The name of the flag of a value is its
+// environment variable in lowercase, with dashes instead of underscores.
+func (w *WorldConfig) loadFlags(args []string) error {
+	fs := flag.NewFlagSet("cardinal", flag.ContinueOnError)
+	rt := reflect.TypeOf(w).Elem()
+	for i := 0; i < rt.NumField(); i++ {
+		key := rt.Field(i).Tag.Get(configTag)
+		name := strings.ReplaceAll(strings.ToLower(key), "_", "-")
+		fs.Func(name, rt.Field(i).Name, func(value string) error {
+			return w.set(key, value)
+		})
+	}
+	return eris.Wrap(fs.Parse(args), "")
+}
+
+// set sets the config value of the given environment variable.
+func (w *WorldConfig) set(key, value string) error {
+	rv := reflect.ValueOf(w).Elem()
+	for i := 0; i < rv.NumField(); i++ {
+		if rv.Type().Field(i).Tag.Get(configTag) != key {
+			continue
+		}
+		field := rv.Field(i)
+		switch field.Kind() { //nolint:exhaustive // the config only has these kinds
+		case reflect.String:
+			field.SetString(value)
+		case reflect.Bool:
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				return eris.Errorf("%s must be a boolean, got %q", key, value)
+			}
+			field.SetBool(b)
+		case reflect.Uint64:
+			n, err := strconv.ParseUint(value, 10, 64)
+			if err != nil {
+				return eris.Errorf("%s must be a positive integer, got %q", key, value)
+			}
+			field.SetUint(n)
+		default:
+			return eris.Errorf("%s has unsupported type %s", key, field.Type())
+		}
+		return nil
+	}
+	return eris.Errorf("unknown config %s", key)
+}
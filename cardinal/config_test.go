@@ -1,8 +1,11 @@
 package cardinal
 
 import (
+	"os"
+	"path/filepath"
 	"reflect"
 	"strconv"
+	"strings"
 	"testing"
 
 	"pkg.world.dev/world-engine/assert"
@@ -10,7 +13,7 @@ import (
 
 func TestWorldConfig_loadWorldConfig(t *testing.T) {
 	// Test that loading config prorammatically works
-	cfg, err := loadWorldConfig()
+	cfg, err := loadWorldConfig("")
 	assert.NilError(t, err)
 	assert.Equal(t, defaultConfig, *cfg)
 }
@@ -20,37 +23,144 @@ func TestWorldConfig_LoadFromEnv(t *testing.T) {
 	// to make sure that all custom config is properly loaded from env vars.
 	wantCfg := WorldConfig{
 		CardinalNamespace:         "baz",
+		CardinalProfile:           "staging",
+		CardinalTickRate:          20,
+		CardinalTickDriftMode:     "slew",
 		CardinalRollupEnabled:     false,
+		CardinalEVMServerEnabled:  false,
+		CardinalEVMServerCertPath: "server.pem",
+		CardinalEVMServerKeyPath:  "server.key",
+		CardinalEVMServerCAPath:   "ca.pem",
 		CardinalLogLevel:          "error",
 		CardinalLogPretty:         true,
+		CardinalStrictMode:        true,
+		CardinalAdminToken:        "qux",
+		CardinalGenesisFile:       "genesis.yaml",
+		CardinalCQLFilters:        true,
+		CardinalEncryptionKey:     "000102030405060708090a0b0c0d0e0f",
+		CardinalAPIKeys:           "key1,key2",
+		CardinalJWTSecret:         "secret",
+		CardinalJWTIssuer:         "https://auth.example.com",
+		CardinalJWTAudience:       "game",
+		CardinalCORSOrigins:       "https://game.example.com",
 		RedisAddress:              "localhost:7070",
 		RedisPassword:             "bar",
 		BaseShardSequencerAddress: "localhost:8080",
 		BaseShardRouterKey:        "abcdefghijklmnopqrstuvwxyz0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ01",
+		BaseShardAttestationKey:   "3b6a27bcceb6a42d62a3a8d02a6f0d73653215771de243a63ac048a18b59da29",
 		TelemetryEnabled:          true,
 		TelemetryStatsdAddress:    "localhost:8125",
 		TelemetryTraceAddress:     "localhost:8126",
+		TelemetryOTLPAddress:      "localhost:4317",
 	}
 
 	// Set env vars to target config values
 	t.Setenv("CARDINAL_NAMESPACE", wantCfg.CardinalNamespace)
+	t.Setenv("CARDINAL_PROFILE", wantCfg.CardinalProfile)
+	t.Setenv("CARDINAL_TICK_RATE", strconv.FormatUint(wantCfg.CardinalTickRate, 10))
+	t.Setenv("CARDINAL_TICK_DRIFT_MODE", wantCfg.CardinalTickDriftMode)
 	t.Setenv("CARDINAL_ROLLUP_ENABLED", strconv.FormatBool(wantCfg.CardinalRollupEnabled))
+	t.Setenv("CARDINAL_EVM_SERVER_ENABLED", strconv.FormatBool(wantCfg.CardinalEVMServerEnabled))
+	t.Setenv("CARDINAL_EVM_SERVER_CERT_PATH", wantCfg.CardinalEVMServerCertPath)
+	t.Setenv("CARDINAL_EVM_SERVER_KEY_PATH", wantCfg.CardinalEVMServerKeyPath)
+	t.Setenv("CARDINAL_EVM_SERVER_CA_PATH", wantCfg.CardinalEVMServerCAPath)
 	t.Setenv("CARDINAL_LOG_LEVEL", wantCfg.CardinalLogLevel)
 	t.Setenv("CARDINAL_LOG_PRETTY", strconv.FormatBool(wantCfg.CardinalLogPretty))
+	t.Setenv("CARDINAL_STRICT_MODE", strconv.FormatBool(wantCfg.CardinalStrictMode))
+	t.Setenv("CARDINAL_ADMIN_TOKEN", wantCfg.CardinalAdminToken)
+	t.Setenv("CARDINAL_GENESIS_FILE", wantCfg.CardinalGenesisFile)
+	t.Setenv("CARDINAL_CQL_FILTERS", strconv.FormatBool(wantCfg.CardinalCQLFilters))
+	t.Setenv("CARDINAL_ENCRYPTION_KEY", wantCfg.CardinalEncryptionKey)
+	t.Setenv("CARDINAL_API_KEYS", wantCfg.CardinalAPIKeys)
+	t.Setenv("CARDINAL_JWT_SECRET", wantCfg.CardinalJWTSecret)
+	t.Setenv("CARDINAL_JWT_ISSUER", wantCfg.CardinalJWTIssuer)
+	t.Setenv("CARDINAL_JWT_AUDIENCE", wantCfg.CardinalJWTAudience)
+	t.Setenv("CARDINAL_CORS_ORIGINS", wantCfg.CardinalCORSOrigins)
 	t.Setenv("REDIS_ADDRESS", wantCfg.RedisAddress)
 	t.Setenv("REDIS_PASSWORD", wantCfg.RedisPassword)
 	t.Setenv("BASE_SHARD_SEQUENCER_ADDRESS", wantCfg.BaseShardSequencerAddress)
 	t.Setenv("BASE_SHARD_ROUTER_KEY", wantCfg.BaseShardRouterKey)
+	t.Setenv("BASE_SHARD_ATTESTATION_KEY", wantCfg.BaseShardAttestationKey)
 	t.Setenv("TELEMETRY_ENABLED", strconv.FormatBool(wantCfg.TelemetryEnabled))
 	t.Setenv("TELEMETRY_STATSD_ADDRESS", wantCfg.TelemetryStatsdAddress)
 	t.Setenv("TELEMETRY_TRACE_ADDRESS", wantCfg.TelemetryTraceAddress)
+	t.Setenv("TELEMETRY_OTLP_ADDRESS", wantCfg.TelemetryOTLPAddress)
 
-	gotCfg, err := loadWorldConfig()
+	gotCfg, err := loadWorldConfig("")
 	assert.NilError(t, err)
 
 	assert.Equal(t, wantCfg, *gotCfg)
 }
 
+func TestWorldConfig_LoadConfig_Precedence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cardinal.yaml")
+	err := os.WriteFile(path, []byte(
+		"CARDINAL_NAMESPACE: from-file\nCARDINAL_TICK_RATE: 5\nCARDINAL_LOG_LEVEL: warn\nCARDINAL_STRICT_MODE: true\n",
+	), 0o600)
+	assert.NilError(t, err)
+	t.Setenv("CARDINAL_TICK_RATE", "10")
+	t.Setenv("CARDINAL_LOG_LEVEL", "error")
+
+	cfg, err := LoadConfig(path, []string{"-cardinal-log-level=debug"})
+	assert.NilError(t, err)
+	assert.Equal(t, "from-file", cfg.CardinalNamespace)
+	assert.True(t, cfg.CardinalStrictMode)
+	// The environment overrides the file, and the flags override the environment.
+	assert.Equal(t, uint64(10), cfg.CardinalTickRate)
+	assert.Equal(t, "debug", cfg.CardinalLogLevel)
+	// Values that are not set anywhere keep their default.
+	assert.Equal(t, DefaultRedisAddress, cfg.RedisAddress)
+	assert.True(t, cfg.CardinalEVMServerEnabled)
+
+	_, err = LoadConfig("", []string{"-cardinal-tick-rate=fast"})
+	assert.IsError(t, err)
+	_, err = LoadConfig("", []string{"-cardinal-tick-rate=0"})
+	assert.IsError(t, err)
+}
+
+func TestWorldConfig_LoadConfig_Profile(t *testing.T) {
+	t.Setenv("CARDINAL_PROFILE", "dev")
+	t.Setenv("CARDINAL_LOG_PRETTY", "false")
+
+	cfg, err := LoadConfig("", nil)
+	assert.NilError(t, err)
+	assert.Equal(t, string(ProfileDev), cfg.CardinalProfile)
+	assert.True(t, cfg.CardinalStrictMode)
+	assert.Equal(t, "debug", cfg.CardinalLogLevel)
+	// The environment overrides the defaults of the profile.
+	assert.False(t, cfg.CardinalLogPretty)
+
+	// The profile of the sources overrides the profile set in code, and its defaults replace the ones of the latter.
+	cfg, err = loadConfig(ProfileProd, "", nil)
+	assert.NilError(t, err)
+	assert.Equal(t, string(ProfileDev), cfg.CardinalProfile)
+	assert.False(t, cfg.TelemetryEnabled)
+
+	cfg, err = loadConfig(ProfileProd, "", []string{"-cardinal-profile="})
+	assert.NilError(t, err)
+	assert.Equal(t, "", cfg.CardinalProfile)
+	assert.False(t, cfg.CardinalStrictMode)
+
+	_, err = LoadConfig("", []string{"-cardinal-profile=qa"})
+	assert.IsError(t, err)
+}
+
+func TestWorldConfig_Redacted(t *testing.T) {
+	cfg := defaultConfigWithOverrides(WorldConfig{
+		RedisPassword: "hunter2", CardinalAdminToken: "token", CardinalEncryptionKey: "000102030405060708090a0b0c0d0e0f",
+		CardinalAPIKeys: "key1,key2",
+	})
+	values := cfg.Redacted()
+	assert.Equal(t, redactedValue, values["REDIS_PASSWORD"])
+	assert.Equal(t, redactedValue, values["CARDINAL_ADMIN_TOKEN"])
+	assert.Equal(t, redactedValue, values["CARDINAL_ENCRYPTION_KEY"])
+	assert.Equal(t, redactedValue, values["CARDINAL_API_KEYS"])
+	// Unset secrets are not redacted, so that it is visible that they are missing.
+	assert.Equal(t, "", values["BASE_SHARD_ROUTER_KEY"])
+	assert.Equal(t, DefaultRedisAddress, values["REDIS_ADDRESS"])
+	assert.Equal(t, uint64(DefaultCardinalTickRate), values["CARDINAL_TICK_RATE"])
+}
+
 func TestWorldConfig_Validate_DefaultConfigIsValid(t *testing.T) {
 	// Validates the default config
 	assert.NilError(t, defaultConfig.Validate())
@@ -102,6 +212,43 @@ func TestWorldConfig_Validate_LogLevel(t *testing.T) {
 	})
 }
 
+func TestWorldConfig_Validate_TickDriftMode(t *testing.T) {
+	for _, mode := range []DriftMode{DriftJump, DriftSlew} {
+		cfg := defaultConfigWithOverrides(WorldConfig{CardinalTickDriftMode: string(mode)})
+		assert.NilError(t, cfg.Validate())
+	}
+	cfg := defaultConfigWithOverrides(WorldConfig{CardinalTickDriftMode: "skip"})
+	assert.IsError(t, cfg.Validate())
+}
+
+func TestWorldConfig_Validate_EncryptionKey(t *testing.T) {
+	for _, key := range []string{"", "000102030405060708090a0b0c0d0e0f", "0x" + strings.Repeat("ab", 32)} {
+		cfg := defaultConfigWithOverrides(WorldConfig{CardinalEncryptionKey: key})
+		assert.NilError(t, cfg.Validate())
+	}
+	for _, key := range []string{"not-hex", "0001020304"} {
+		cfg := defaultConfigWithOverrides(WorldConfig{CardinalEncryptionKey: key})
+		assert.IsError(t, cfg.Validate())
+	}
+}
+
+func TestWorldConfig_Validate_JWTClaims(t *testing.T) {
+	cfg := defaultConfigWithOverrides(WorldConfig{
+		CardinalJWTSecret:   "secret",
+		CardinalJWTIssuer:   "auth",
+		CardinalJWTAudience: "game",
+	})
+	assert.NilError(t, cfg.Validate())
+	auth := cfg.httpAuth()
+	assert.Equal(t, "auth", auth.JWT.Issuer)
+	assert.Equal(t, "game", auth.JWT.Audience)
+
+	for _, override := range []WorldConfig{{CardinalJWTIssuer: "auth"}, {CardinalJWTAudience: "game"}} {
+		cfg = defaultConfigWithOverrides(override)
+		assert.IsError(t, cfg.Validate())
+	}
+}
+
 func TestWorldConfig_Validate_Redis(t *testing.T) {
 	testCases := []struct {
 		name    string
@@ -163,6 +310,46 @@ func TestWorldConfig_Validate_RollupMode(t *testing.T) {
 			}),
 			wantErr: false,
 		},
+		{
+			name: "With base shard config, but bad attestation key",
+			cfg: defaultConfigWithOverrides(WorldConfig{
+				CardinalRollupEnabled:     true,
+				BaseShardSequencerAddress: "localhost:8080",
+				BaseShardRouterKey:        "abcdefghijklmnopqrstuvwxyz0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ01",
+				BaseShardAttestationKey:   "abcd",
+			}),
+			wantErr: true,
+		},
+		{
+			name: "With a server certificate without its key",
+			cfg: defaultConfigWithOverrides(WorldConfig{
+				CardinalRollupEnabled:     true,
+				BaseShardSequencerAddress: "localhost:8080",
+				BaseShardRouterKey:        "abcdefghijklmnopqrstuvwxyz0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ01",
+				CardinalEVMServerCertPath: "server.pem",
+			}),
+			wantErr: true,
+		},
+		{
+			name: "With a client CA without a server certificate",
+			cfg: defaultConfigWithOverrides(WorldConfig{
+				CardinalRollupEnabled:     true,
+				BaseShardSequencerAddress: "localhost:8080",
+				BaseShardRouterKey:        "abcdefghijklmnopqrstuvwxyz0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ01",
+				CardinalEVMServerCAPath:   "ca.pem",
+			}),
+			wantErr: true,
+		},
+		{
+			name: "With valid base shard config and attestation key",
+			cfg: defaultConfigWithOverrides(WorldConfig{
+				CardinalRollupEnabled:     true,
+				BaseShardSequencerAddress: "localhost:8080",
+				BaseShardRouterKey:        "abcdefghijklmnopqrstuvwxyz0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ01",
+				BaseShardAttestationKey:   "3b6a27bcceb6a42d62a3a8d02a6f0d73653215771de243a63ac048a18b59da29",
+			}),
+			wantErr: false,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -228,6 +415,14 @@ func TestWorldConfig_Validate_Telemetry(t *testing.T) {
 			}),
 			wantErr: true,
 		},
+		{
+			name: "If telemetry is enabled, bad OTLP address causes validation error",
+			cfg: defaultConfigWithOverrides(WorldConfig{
+				TelemetryEnabled:     true,
+				TelemetryOTLPAddress: "baz",
+			}),
+			wantErr: true,
+		},
 	}
 
 	for _, tc := range testCases {
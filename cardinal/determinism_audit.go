@@ -0,0 +1,150 @@
+package cardinal
+
+import (
+	"context"
+	"crypto/sha256"
+	"slices"
+	"strings"
+
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal/codec"
+	"pkg.world.dev/world-engine/cardinal/gamestate"
+	"pkg.world.dev/world-engine/cardinal/receipt"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+	"pkg.world.dev/world-engine/cardinal/types/txpool"
+)
+
+// Sections of the digest of a system, in addition to the sections of gamestate.PendingDigest.
+const (
+	digestReceipts = "receipts"
+	digestEvents   = "events"
+)
+
+// digestSections is the order in which the sections of a diverged system are reported.
+var digestSections = []string{
+	gamestate.DigestComponents,
+	gamestate.DigestEntities,
+	gamestate.DigestRawStorage,
+	gamestate.DigestOutbox,
+	digestReceipts,
+	digestEvents,
+}
+
+// determinismAudit records the state of a tick after each of its systems. See WithDeterminismAudit.
+type determinismAudit struct {
+	store *gamestate.EntityCommandBuffer
+	// eventOffset is the number of events that had been emitted in the tick before its systems ran.
+	eventOffset int
+	digests     []systemDigest
+}
+
+// systemDigest is the digest of the changes of a tick, up to and including the changes of a system.
+type systemDigest struct {
+	system   string
+	sections gamestate.PendingDigest
+}
+
+func (w *World) enableDeterminismAudit() {
+	w.determinismAudit = &determinismAudit{}
+	w.SystemManager.setAfterSystem(w.recordSystemDigest)
+}
+
+// runAuditedSystems runs the systems of the tick twice against the same game state, and fails with
+// ErrNondeterministicSystem if the two runs diverge. The changes of the first run are discarded, so the changes that
+// are committed are those of the second run, which is given wCtx.
+func (w *World) runAuditedSystems(ctx context.Context, wCtx engine.Context, txPool *txpool.TxPool) error {
+	ecb, ok := w.entityStore.(*gamestate.EntityCommandBuffer)
+	if !ok {
+		return eris.New("the determinism audit can only be used with the default store manager")
+	}
+	audit := w.determinismAudit
+	*audit = determinismAudit{store: ecb, eventOffset: len(w.tickResults.Events)}
+	if err := w.SystemManager.runSystems(ctx, newWorldContextForTick(w, txPool)); err != nil {
+		return err
+	}
+	first := audit.digests
+	if err := w.discardSystemChanges(ecb, audit.eventOffset); err != nil {
+		return err
+	}
+
+	audit.digests = nil
+	if err := w.SystemManager.runSystems(ctx, wCtx); err != nil {
+		return err
+	}
+	return compareSystemDigests(w.CurrentTick(), first, audit.digests)
+}
+
+// recordSystemDigest records the state of the tick after the given system ran.
+func (w *World) recordSystemDigest(system string) error {
+	audit := w.determinismAudit
+	if audit.store == nil {
+		return nil
+	}
+	sections, err := audit.store.PendingDigest()
+	if err != nil {
+		return err
+	}
+	if sections[digestReceipts], err = receiptsDigest(w.receiptHistory.GetReceiptsForCurrentTick()); err != nil {
+		return err
+	}
+	h := sha256.New()
+	for _, event := range w.tickResults.Events[audit.eventOffset:] {
+		_, _ = h.Write(event)
+		_, _ = h.Write([]byte{0})
+	}
+	sections[digestEvents] = [sha256.Size]byte(h.Sum(nil))
+	audit.digests = append(audit.digests, systemDigest{system: system, sections: sections})
+	return nil
+}
+
+// discardSystemChanges undoes everything that the systems of the current tick changed, so that they can be run again.
+func (w *World) discardSystemChanges(ecb *gamestate.EntityCommandBuffer, eventOffset int) error {
+	if err := ecb.DiscardPending(); err != nil {
+		return err
+	}
+	w.receiptHistory.DiscardCurrentTick()
+	w.tickResults.Events = w.tickResults.Events[:eventOffset]
+	w.entityQuota.discardTick()
+	clear(w.triggers.changes)
+	clear(w.destroyed)
+	forgetPersonaIndex(w.Namespace())
+	return nil
+}
+
+// compareSystemDigests returns an error naming the first system whose changes differ between the two runs of a tick.
+func compareSystemDigests(tick uint64, first, second []systemDigest) error {
+	for i := 0; i < min(len(first), len(second)); i++ {
+		if first[i].system != second[i].system {
+			return eris.Wrapf(ErrNondeterministicSystem, "tick %d ran system %s first and system %s second", tick,
+				first[i].system, second[i].system)
+		}
+		var diverged []string
+		for _, section := range digestSections {
+			if first[i].sections[section] != second[i].sections[section] {
+				diverged = append(diverged, section)
+			}
+		}
+		if len(diverged) > 0 {
+			return eris.Wrapf(ErrNondeterministicSystem, "system %s diverged when tick %d was run twice: %s differ",
+				first[i].system, tick, strings.Join(diverged, ", "))
+		}
+	}
+	if len(first) != len(second) {
+		return eris.Wrapf(ErrNondeterministicSystem, "tick %d ran %d systems first and %d systems second", tick,
+			len(first), len(second))
+	}
+	return nil
+}
+
+// receiptsDigest returns the digest of the receipts of a tick, in transaction hash order.
+func receiptsDigest(receipts []receipt.Receipt) ([sha256.Size]byte, error) {
+	slices.SortFunc(receipts, func(a, b receipt.Receipt) int {
+		return strings.Compare(string(a.TxHash), string(b.TxHash))
+	})
+	bz, err := codec.Encode(receipts)
+	if err != nil {
+		return [sha256.Size]byte{}, err
+	}
+	return sha256.Sum256(bz), nil
+}
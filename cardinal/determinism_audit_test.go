@@ -0,0 +1,126 @@
+package cardinal
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal/search/filter"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+	"pkg.world.dev/world-engine/cardinal/worldstage"
+)
+
+type auditCounter struct {
+	N int
+}
+
+func (auditCounter) Name() string {
+	return "auditCounter"
+}
+
+// hiddenStateCalls is state that is kept outside of components, so it is not reset when a tick is run again.
+var hiddenStateCalls int
+
+func hiddenStateSystem(wCtx engine.Context) error {
+	hiddenStateCalls++
+	_, err := Create(wCtx, auditCounter{N: hiddenStateCalls})
+	return err
+}
+
+func createCounterSystem(wCtx engine.Context) error {
+	_, err := Create(wCtx, auditCounter{})
+	return err
+}
+
+func incrementCountersSystem(wCtx engine.Context) error {
+	var err error
+	searchErr := NewSearch().Entity(filter.Exact(filter.Component[auditCounter]())).Each(wCtx,
+		func(id types.EntityID) bool {
+			err = UpdateComponent[auditCounter](wCtx, id, func(c *auditCounter) *auditCounter {
+				c.N++
+				return c
+			})
+			return err == nil
+		})
+	if err != nil {
+		return err
+	}
+	if searchErr != nil {
+		return searchErr
+	}
+	return wCtx.EmitEvent(map[string]any{"incremented": true})
+}
+
+func newAuditedWorld(t *testing.T) *World {
+	miniRedis := miniredis.RunT(t)
+	t.Setenv("REDIS_ADDRESS", miniRedis.Addr())
+	world, err := NewWorld(WithDeterminismAudit(), WithTickChannel(make(chan time.Time)), WithPort(getOpenPort(t)))
+	assert.NilError(t, err)
+	assert.NilError(t, RegisterComponent[auditCounter](world))
+	return world
+}
+
+func startAuditedWorld(t *testing.T, world *World) {
+	go func() {
+		assert.NilError(t, world.StartGame())
+	}()
+	<-world.worldStage.NotifyOnStage(worldstage.Running)
+	t.Cleanup(func() {
+		assert.NilError(t, world.Shutdown(context.Background()))
+	})
+}
+
+func TestDeterminismAuditCommitsTheChangesOfOneRun(t *testing.T) {
+	world := newAuditedWorld(t)
+	assert.NilError(t, RegisterInitSystems(world, createCounterSystem))
+	assert.NilError(t, RegisterSystems(world, incrementCountersSystem))
+	startAuditedWorld(t, world)
+
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		assert.NilError(t, doTickCapturePanic(ctx, world))
+	}
+
+	wCtx := NewReadOnlyWorldContext(world)
+	counters := NewSearch().Entity(filter.Exact(filter.Component[auditCounter]()))
+	count, err := counters.Count(wCtx)
+	assert.NilError(t, err)
+	assert.Equal(t, 1, count)
+	id, err := counters.First(wCtx)
+	assert.NilError(t, err)
+	counter, err := GetComponent[auditCounter](wCtx, id)
+	assert.NilError(t, err)
+	assert.Equal(t, 3, counter.N)
+}
+
+func TestDeterminismAuditReportsTheFirstDivergentSystem(t *testing.T) {
+	hiddenStateCalls = 0
+	world := newAuditedWorld(t)
+	// incrementCountersSystem diverges too, since it increments the counters that hiddenStateSystem created.
+	assert.NilError(t, RegisterSystems(world, hiddenStateSystem, incrementCountersSystem))
+	startAuditedWorld(t, world)
+
+	err := doTickCapturePanic(context.Background(), world)
+	assert.ErrorContains(t, err, "system cardinal.hiddenStateSystem diverged when tick 0 was run twice: components differ")
+}
+
+func TestCompareSystemDigests(t *testing.T) {
+	digest := func(system string, components byte) systemDigest {
+		return systemDigest{system: system, sections: map[string][32]byte{"components": {components}}}
+	}
+	same := []systemDigest{digest("a", 1), digest("b", 2)}
+	assert.NilError(t, compareSystemDigests(1, same, same))
+
+	err := compareSystemDigests(1, same, []systemDigest{digest("a", 1), digest("b", 3)})
+	assert.ErrorIs(t, err, ErrNondeterministicSystem)
+	assert.Check(t, strings.Contains(err.Error(), "system b diverged"), err.Error())
+
+	err = compareSystemDigests(1, same, same[:1])
+	assert.ErrorIs(t, err, ErrNondeterministicSystem)
+	assert.Check(t, strings.Contains(err.Error(), "tick 1 ran 2 systems first and 1 systems second"), err.Error())
+}
@@ -361,6 +361,96 @@ func TestCanRemoveEntriesDuringCallToEach(t *testing.T) {
 	}
 }
 
+type DefaultHealth struct {
+	HP  int
+	Max int
+}
+
+func (DefaultHealth) Name() string {
+	return "default_health"
+}
+
+func (DefaultHealth) Default() DefaultHealth {
+	return DefaultHealth{HP: 100, Max: 100}
+}
+
+func TestComponentsStartWithTheirDefaultValue(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil)
+	world := tf.World
+	assert.NilError(t, cardinal.RegisterComponent[DefaultHealth](world))
+	assert.NilError(t, cardinal.RegisterComponent[EnergyComponent](world))
+	tf.StartWorld()
+
+	wCtx := cardinal.NewWorldContext(world)
+	ids, err := cardinal.CreateMany(wCtx, 2, DefaultHealth{}, EnergyComponent{})
+	assert.NilError(t, err)
+	pointerID, err := cardinal.Create(wCtx, &DefaultHealth{})
+	assert.NilError(t, err)
+	setID, err := cardinal.Create(wCtx, DefaultHealth{HP: 5, Max: 100})
+	assert.NilError(t, err)
+	addedID, err := cardinal.Create(wCtx, EnergyComponent{})
+	assert.NilError(t, err)
+	assert.NilError(t, cardinal.AddComponentTo[DefaultHealth](wCtx, addedID))
+
+	for _, id := range append(ids, pointerID, addedID) {
+		health, err := cardinal.GetComponent[DefaultHealth](wCtx, id)
+		assert.NilError(t, err)
+		assert.Equal(t, *health, DefaultHealth{HP: 100, Max: 100})
+	}
+	health, err := cardinal.GetComponent[DefaultHealth](wCtx, setID)
+	assert.NilError(t, err)
+	assert.Equal(t, health.HP, 5)
+}
+
+type Loadout struct {
+	Items  []string
+	Charge *int
+}
+
+func (Loadout) Name() string {
+	return "loadout"
+}
+
+func TestCloneEntityCopiesComponentsDeeply(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil)
+	world := tf.World
+	assert.NilError(t, cardinal.RegisterComponent[Loadout](world))
+	assert.NilError(t, cardinal.RegisterComponent[EnergyComponent](world))
+	assert.NilError(t, cardinal.RegisterComponent[DefaultHealth](world))
+	tf.StartWorld()
+
+	wCtx := cardinal.NewWorldContext(world)
+	charge := 3
+	templateID, err := cardinal.Create(wCtx,
+		Loadout{Items: []string{"sword", "shield"}, Charge: &charge}, EnergyComponent{Amt: 10, Cap: 20})
+	assert.NilError(t, err)
+
+	cloneID, err := cardinal.CloneEntity(wCtx, templateID, EnergyComponent{Amt: 5, Cap: 20}, DefaultHealth{})
+	assert.NilError(t, err)
+	assert.NilError(t, cardinal.UpdateComponent[Loadout](wCtx, cloneID, func(l *Loadout) *Loadout {
+		l.Items[0] = "axe"
+		*l.Charge = 0
+		return l
+	}))
+
+	template, err := cardinal.GetComponent[Loadout](wCtx, templateID)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, template.Items, []string{"sword", "shield"})
+	assert.Equal(t, *template.Charge, 3)
+	clone, err := cardinal.GetComponent[Loadout](wCtx, cloneID)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, clone.Items, []string{"axe", "shield"})
+	assert.Equal(t, *clone.Charge, 0)
+
+	energy, err := cardinal.GetComponent[EnergyComponent](wCtx, cloneID)
+	assert.NilError(t, err)
+	assert.Equal(t, energy.Amt, int64(5))
+	// Components that are added by overrides are stored as given, zero values included.
+	health, err := cardinal.GetComponent[DefaultHealth](wCtx, cloneID)
+	assert.NilError(t, err)
+	assert.Equal(t, health.HP, 0)
+}
+
 func TestAddingAComponentThatAlreadyExistsIsError(t *testing.T) {
 	tf := testutils.NewTestFixture(t, nil)
 	world := tf.World
@@ -373,6 +463,69 @@ func TestAddingAComponentThatAlreadyExistsIsError(t *testing.T) {
 	assert.ErrorIs(t, cardinal.AddComponentTo[EnergyComponent](wCtx, ent), iterators.ErrComponentAlreadyOnEntity)
 }
 
+func TestDestroyedEntitiesAreRemovedAtEndOfTick(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil)
+	world := tf.World
+	assert.NilError(t, cardinal.RegisterComponent[CountComponent](world))
+
+	q := cardinal.NewSearch().Entity(filter.Exact(filter.Component[CountComponent]()))
+	var seen, seenDestroyed int
+	assert.NilError(t, cardinal.RegisterSystems(world,
+		// Destroys the entities with an even count, twice.
+		func(wCtx engine.Context) error {
+			var errs []error
+			errs = append(errs, q.Each(wCtx, func(id types.EntityID) bool {
+				c, err := cardinal.GetComponent[CountComponent](wCtx, id)
+				if err == nil && c.Val%2 == 0 {
+					err = errors.Join(cardinal.Destroy(wCtx, id), cardinal.Destroy(wCtx, id))
+				}
+				errs = append(errs, err)
+				return true
+			}))
+			return errors.Join(errs...)
+		},
+		// Still sees the destroyed entities.
+		func(wCtx engine.Context) error {
+			seen, seenDestroyed = 0, 0
+			return q.Each(wCtx, func(id types.EntityID) bool {
+				seen++
+				if cardinal.IsDestroyed(wCtx, id) {
+					seenDestroyed++
+				}
+				return true
+			})
+		},
+	))
+	tf.StartWorld()
+
+	wCtx := cardinal.NewWorldContext(world)
+	ids, err := cardinal.CreateMany(wCtx, 4, CountComponent{})
+	assert.NilError(t, err)
+	for i, id := range ids {
+		assert.NilError(t, cardinal.SetComponent[CountComponent](wCtx, id, &CountComponent{Val: i}))
+	}
+	tf.DoTick()
+	assert.Equal(t, 4, seen)
+	assert.Equal(t, 2, seenDestroyed)
+
+	for i, id := range ids {
+		_, err = cardinal.GetComponent[CountComponent](wCtx, id)
+		if i%2 == 0 {
+			assert.Check(t, err != nil)
+			assert.Check(t, !cardinal.IsDestroyed(wCtx, id))
+		} else {
+			assert.NilError(t, err)
+		}
+	}
+	tf.DoTick()
+	assert.Equal(t, 2, seen)
+	assert.Equal(t, 0, seenDestroyed)
+
+	assert.ErrorIs(t, cardinal.Destroy(cardinal.NewReadOnlyWorldContext(world), ids[1]),
+		cardinal.ErrEntityMutationOnReadOnly)
+	assert.Check(t, cardinal.Destroy(wCtx, ids[0]) != nil)
+}
+
 func TestRemovingAMissingComponentIsError(t *testing.T) {
 	tf := testutils.NewTestFixture(t, nil)
 	world := tf.World
@@ -1,7 +1,10 @@
 package cardinal_test
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"reflect"
 	"strconv"
 	"testing"
 	"time"
@@ -103,7 +106,7 @@ func TestWaitForNextTickReturnsFalseWhenEngineIsShutDown(t *testing.T) {
 	// Shutdown the engine at some point in the near future
 	time.AfterFunc(
 		100*time.Millisecond, func() {
-			assert.NilError(t, world.Shutdown())
+			assert.NilError(t, world.Shutdown(context.Background()))
 		},
 	)
 	// testTimeout will cause the test to fail if we have to wait too long for a WaitForNextTick failure
@@ -154,7 +157,7 @@ func TestCannotWaitForNextTickAfterEngineIsShutDown(t *testing.T) {
 
 	// add tx to queue
 	evmTxHash := "0xFooBar"
-	world.AddEVMTransaction(fooTx.ID(), FooIn{X: 32}, &sign.Transaction{PersonaTag: "foo"}, evmTxHash)
+	world.AddEVMTransaction(context.Background(), fooTx.ID(), FooIn{X: 32}, &sign.Transaction{PersonaTag: "foo"}, evmTxHash)
 
 	tf.StartWorld()
 
@@ -174,7 +177,7 @@ func TestCannotWaitForNextTickAfterEngineIsShutDown(t *testing.T) {
 	// lets check against a system that returns an error
 	returnVal = FooOut{}
 	returnErr = errors.New("omg error")
-	world.AddEVMTransaction(fooTx.ID(), FooIn{X: 32}, &sign.Transaction{PersonaTag: "foo"}, evmTxHash)
+	world.AddEVMTransaction(context.Background(), fooTx.ID(), FooIn{X: 32}, &sign.Transaction{PersonaTag: "foo"}, evmTxHash)
 	tf.DoTick()
 	evmTxReceipt, ok = world.GetEVMMsgReceipt(evmTxHash)
 
@@ -219,7 +222,7 @@ func TestEVMTxConsume(t *testing.T) {
 	assert.True(t, ok)
 	// add tx to queue
 	evmTxHash := "0xFooBar"
-	world.AddEVMTransaction(fooTx.ID(), FooIn{X: 32}, &sign.Transaction{PersonaTag: "foo"}, evmTxHash)
+	world.AddEVMTransaction(context.Background(), fooTx.ID(), FooIn{X: 32}, &sign.Transaction{PersonaTag: "foo"}, evmTxHash)
 
 	// let's check against a system that returns a result and no error
 	returnVal = FooOut{Y: "hi"}
@@ -237,7 +240,7 @@ func TestEVMTxConsume(t *testing.T) {
 	// lets check against a system that returns an error
 	returnVal = FooOut{}
 	returnErr = errors.New("omg error")
-	world.AddEVMTransaction(fooTx.ID(), FooIn{X: 32}, &sign.Transaction{PersonaTag: "foo"}, evmTxHash)
+	world.AddEVMTransaction(context.Background(), fooTx.ID(), FooIn{X: 32}, &sign.Transaction{PersonaTag: "foo"}, evmTxHash)
 	tf.DoTick()
 	evmTxReceipt, ok = world.GetEVMMsgReceipt(evmTxHash)
 
@@ -358,6 +361,28 @@ func TestWithoutRegistration(t *testing.T) {
 	assert.NilError(t, err)
 }
 
+// txMapIgnoringAddedAt matches a txpool.TxMap with the same transactions, regardless of when they were added to the pool.
+type txMapIgnoringAddedAt txpool.TxMap
+
+func (m txMapIgnoringAddedAt) Matches(x any) bool {
+	txs, ok := x.(txpool.TxMap)
+	if !ok {
+		return false
+	}
+	withoutAddedAt := make(txpool.TxMap, len(txs))
+	for id, data := range txs {
+		for _, tx := range data {
+			tx.AddedAt = time.Time{}
+			withoutAddedAt[id] = append(withoutAddedAt[id], tx)
+		}
+	}
+	return reflect.DeepEqual(txpool.TxMap(m), withoutAddedAt)
+}
+
+func (m txMapIgnoringAddedAt) String() string {
+	return fmt.Sprintf("is equal to %v (ignoring AddedAt)", txpool.TxMap(m))
+}
+
 func TestTransactionsSentToRouterAfterTick(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	rtr := mocks.NewMockRouter(ctrl)
@@ -379,14 +404,15 @@ func TestTransactionsSentToRouterAfterTick(t *testing.T) {
 	tx := &sign.Transaction{PersonaTag: "ty"}
 	fooMessage, ok := world.GetMessageByFullName("game." + msgName)
 	assert.True(t, ok)
-	_, txHash := world.AddEVMTransaction(fooMessage.ID(), msg, tx, evmTxHash)
+	_, txHash, err := world.AddEVMTransaction(context.Background(), fooMessage.ID(), msg, tx, evmTxHash)
+	assert.NilError(t, err)
 	ts := uint64(time.Now().Unix())
 
 	rtr.
 		EXPECT().
 		SubmitTxBlob(
 			gomock.Any(),
-			txpool.TxMap{
+			txMapIgnoringAddedAt{
 				fooMessage.ID(): {
 					{
 						MsgID:           fooMessage.ID(),
@@ -404,6 +430,7 @@ func TestTransactionsSentToRouterAfterTick(t *testing.T) {
 		Times(1)
 	rtr.EXPECT().Start().Times(1)
 	rtr.EXPECT().RegisterGameShard(gomock.Any()).Times(1)
+	rtr.EXPECT().Shutdown().Times(1)
 	tf.StartWorld()
 	tf.DoTick()
 
@@ -412,7 +439,7 @@ func TestTransactionsSentToRouterAfterTick(t *testing.T) {
 		EXPECT().
 		SubmitTxBlob(
 			gomock.Any(),
-			txpool.TxMap{},
+			txMapIgnoringAddedAt{},
 			world.CurrentTick(),
 			ts,
 		).
@@ -438,6 +465,7 @@ func TestRecoverFromChain(t *testing.T) {
 
 	rtr.EXPECT().Start().Times(1)
 	rtr.EXPECT().RegisterGameShard(gomock.Any()).Times(1)
+	rtr.EXPECT().Shutdown().Times(1)
 
 	tf := testutils.NewTestFixture(t, nil, cardinal.WithCustomRouter(rtr))
 	world := tf.World
@@ -0,0 +1,305 @@
+package cardinal
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+// entityOwnerNamespace is the raw storage namespace used to track which persona owns an entity. Ownership records are
+// committed with the rest of the tick, so they survive restarts and recoveries.
+const entityOwnerNamespace = "cardinal-entity-owner"
+
+var (
+	ErrEntityAlreadyOwned = errors.New("entity is owned by another persona")
+	ErrEntityNotOwned     = errors.New("entity is not owned by the persona")
+)
+
+// ClaimEntity makes the given persona the owner of an existing entity, e.g. when a player picks up an item or captures
+// a unit. Claiming an entity that the persona already owns does nothing. ErrEntityAlreadyOwned is returned if another
+// persona owns the entity, and ErrPersonaEntityQuotaExceeded if the persona already owns the maximum number of
+// entities allowed by the world's EntityQuota. Ownership is released with ReleaseEntity, or when the entity is removed.
+func ClaimEntity(wCtx engine.Context, personaTag string, id types.EntityID) (err error) {
+	defer func() { panicOnFatalError(wCtx, err) }()
+
+	if wCtx.IsReadOnly() {
+		return ErrEntityMutationOnReadOnly
+	}
+	if personaTag == "" {
+		return eris.New("persona tag must not be empty")
+	}
+	if _, err = wCtx.StoreReader().GetComponentTypesForEntity(id); err != nil {
+		return err
+	}
+	owner, ok, err := GetEntityOwner(wCtx, id)
+	if err != nil {
+		return err
+	}
+	if ok {
+		if owner == personaTag {
+			return nil
+		}
+		return eris.Wrapf(ErrEntityAlreadyOwned, "entity %d is owned by %q", id, owner)
+	}
+	if err = wCtx.ReservePersonaEntityQuota(personaTag, 1); err != nil {
+		return err
+	}
+	return claimEntities(wCtx, personaTag, []types.EntityID{id})
+}
+
+// ReleaseEntity gives up the ownership of an entity by the given persona. The entity itself is not changed.
+// ErrEntityNotOwned is returned if the persona does not own the entity.
+func ReleaseEntity(wCtx engine.Context, personaTag string, id types.EntityID) (err error) {
+	defer func() { panicOnFatalError(wCtx, err) }()
+
+	if wCtx.IsReadOnly() {
+		return ErrEntityMutationOnReadOnly
+	}
+	store, err := NewRawStorage(wCtx, entityOwnerNamespace)
+	if err != nil {
+		return err
+	}
+	owner, index, ok, err := getEntityOwner(store, id)
+	if err != nil {
+		return err
+	}
+	if !ok || owner != personaTag {
+		return eris.Wrapf(ErrEntityNotOwned, "entity %d is not owned by %q", id, personaTag)
+	}
+	return removeOwnedEntity(store, owner, index, id)
+}
+
+// OwnsEntity returns true if the given persona owns the entity. Systems use it to check that the signer of a message
+// may act on the entities the message refers to:
+//
+//	ok, err := cardinal.OwnsEntity(wCtx, tx.PersonaTag, msg.UnitID)
+func OwnsEntity(wCtx engine.Context, personaTag string, id types.EntityID) (bool, error) {
+	owner, ok, err := GetEntityOwner(wCtx, id)
+	if err != nil {
+		return false, err
+	}
+	return ok && owner == personaTag, nil
+}
+
+// GetEntityOwner returns the persona that owns the given entity. If the entity was not created with CreateForPersona
+// or CreateManyForPersona, or claimed with ClaimEntity, ok will be false.
+func GetEntityOwner(wCtx engine.Context, id types.EntityID) (personaTag string, ok bool, err error) {
+	store, err := NewRawStorage(wCtx, entityOwnerNamespace)
+	if err != nil {
+		return "", false, err
+	}
+	personaTag, _, ok, err = getEntityOwner(store, id)
+	return personaTag, ok, err
+}
+
+// GetOwnedEntities returns the live entities owned by the given persona, in ascending order. It is the same as
+// wCtx.GetOwnedEntities.
+func GetOwnedEntities(wCtx engine.Context, personaTag string) ([]types.EntityID, error) {
+	store, err := NewRawStorage(wCtx, entityOwnerNamespace)
+	if err != nil {
+		return nil, err
+	}
+	count, err := countOwnedEntities(store, personaTag)
+	if err != nil {
+		return nil, err
+	}
+	owned := make([]types.EntityID, count)
+	for i := range owned {
+		if owned[i], err = getOwnedEntity(store, personaTag, i); err != nil {
+			return nil, err
+		}
+	}
+	slices.Sort(owned)
+	return owned, nil
+}
+
+// CountPersonaEntities returns the number of live entities owned by the given persona.
+func CountPersonaEntities(wCtx engine.Context, personaTag string) (int, error) {
+	store, err := NewRawStorage(wCtx, entityOwnerNamespace)
+	if err != nil {
+		return 0, err
+	}
+	return countOwnedEntities(store, personaTag)
+}
+
+// The entities of a persona are stored as a list with one key per entity, so that claiming or releasing an entity
+// reads and writes the same number of keys no matter how many entities the persona owns. The owner record of each
+// entity holds its position in the list, and an entity is released by moving the last entity of the list into its
+// position.
+
+// claimEntities records that the given persona owns the entities, which must not be owned yet.
+func claimEntities(wCtx engine.Context, personaTag string, ids []types.EntityID) error {
+	store, err := NewRawStorage(wCtx, entityOwnerNamespace)
+	if err != nil {
+		return err
+	}
+	count, err := countOwnedEntities(store, personaTag)
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if err = setOwnedEntity(store, personaTag, count, id); err != nil {
+			return err
+		}
+		count++
+	}
+	return setOwnedEntityCount(store, personaTag, count)
+}
+
+// discardEntityClaims drops the ownership records that a failed claimEntities call made for the given entities, which
+// were just created. The persona's list is left as it was before the claim, since its count is written last.
+func discardEntityClaims(wCtx engine.Context, personaTag string, ids []types.EntityID) error {
+	store, err := NewRawStorage(wCtx, entityOwnerNamespace)
+	if err != nil {
+		return err
+	}
+	count, err := countOwnedEntities(store, personaTag)
+	if err != nil {
+		return err
+	}
+	for i, id := range ids {
+		if err = store.discard(entityOwnerKey(id)); err != nil {
+			return err
+		}
+		if err = store.discard(personaEntityKey(personaTag, count+i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// transferEntity moves the ownership of an entity from one owner to another. The entity quota of the new owner is not
+// checked, so the caller must check it first if the new owner is a persona.
+func transferEntity(wCtx engine.Context, from, to string, id types.EntityID) error {
+	store, err := NewRawStorage(wCtx, entityOwnerNamespace)
+	if err != nil {
+		return err
+	}
+	owner, index, ok, err := getEntityOwner(store, id)
+	if err != nil {
+		return err
+	}
+	if !ok || owner != from {
+		return eris.Wrapf(ErrEntityNotOwned, "entity %d is not owned by %q", id, from)
+	}
+	if err = removeOwnedEntity(store, owner, index, id); err != nil {
+		return err
+	}
+	return claimEntities(wCtx, to, []types.EntityID{id})
+}
+
+// releaseEntityOwnership removes the ownership record of an entity, if it has one. It is called for every entity that
+// is removed.
+func releaseEntityOwnership(wCtx engine.Context, id types.EntityID) error {
+	store, err := NewRawStorage(wCtx, entityOwnerNamespace)
+	if err != nil {
+		return err
+	}
+	personaTag, index, ok, err := getEntityOwner(store, id)
+	if err != nil || !ok {
+		return err
+	}
+	return removeOwnedEntity(store, personaTag, index, id)
+}
+
+// removeOwnedEntity removes the entity at the given position from the persona's list and deletes its owner record.
+func removeOwnedEntity(store *RawStorage, personaTag string, index int, id types.EntityID) error {
+	count, err := countOwnedEntities(store, personaTag)
+	if err != nil {
+		return err
+	}
+	last := count - 1
+	if index != last {
+		lastID, err := getOwnedEntity(store, personaTag, last)
+		if err != nil {
+			return err
+		}
+		if err = setOwnedEntity(store, personaTag, index, lastID); err != nil {
+			return err
+		}
+	}
+	if err = store.Delete(personaEntityKey(personaTag, last)); err != nil {
+		return err
+	}
+	if err = store.Delete(entityOwnerKey(id)); err != nil {
+		return err
+	}
+	return setOwnedEntityCount(store, personaTag, last)
+}
+
+// getEntityOwner returns the persona that owns the entity, and the position of the entity in the persona's list.
+func getEntityOwner(store *RawStorage, id types.EntityID) (personaTag string, index int, ok bool, err error) {
+	value, ok, err := store.Get(entityOwnerKey(id))
+	if err != nil || !ok {
+		return "", 0, false, err
+	}
+	// Persona tags can't contain slashes, but ClaimEntity doesn't check that the persona exists, so the last slash is
+	// used.
+	sep := strings.LastIndexByte(string(value), '/')
+	if sep < 0 {
+		return "", 0, false, eris.Errorf("invalid owner of entity %d", id)
+	}
+	index, err = strconv.Atoi(string(value[sep+1:]))
+	if err != nil {
+		return "", 0, false, eris.Wrapf(err, "invalid owner of entity %d", id)
+	}
+	return string(value[:sep]), index, true, nil
+}
+
+// setOwnedEntity puts the entity at the given position of the persona's list.
+func setOwnedEntity(store *RawStorage, personaTag string, index int, id types.EntityID) error {
+	owner := personaTag + "/" + strconv.Itoa(index)
+	if err := store.Set(entityOwnerKey(id), []byte(owner)); err != nil {
+		return err
+	}
+	return store.Set(personaEntityKey(personaTag, index), []byte(strconv.FormatUint(uint64(id), 10)))
+}
+
+func getOwnedEntity(store *RawStorage, personaTag string, index int) (types.EntityID, error) {
+	value, ok, err := store.Get(personaEntityKey(personaTag, index))
+	if err != nil {
+		return 0, err
+	}
+	if !ok {
+		return 0, eris.Errorf("entity %d of persona %q is missing", index, personaTag)
+	}
+	id, err := strconv.ParseUint(string(value), 10, 64)
+	if err != nil {
+		return 0, eris.Wrapf(err, "invalid entity %d of persona %q", index, personaTag)
+	}
+	return types.EntityID(id), nil
+}
+
+func countOwnedEntities(store *RawStorage, personaTag string) (int, error) {
+	value, ok, err := store.Get(personaEntityCountKey(personaTag))
+	if err != nil || !ok {
+		return 0, err
+	}
+	count, err := strconv.Atoi(string(value))
+	return count, eris.Wrapf(err, "invalid number of entities of persona %q", personaTag)
+}
+
+func setOwnedEntityCount(store *RawStorage, personaTag string, count int) error {
+	if count == 0 {
+		return store.Delete(personaEntityCountKey(personaTag))
+	}
+	return store.Set(personaEntityCountKey(personaTag), []byte(strconv.Itoa(count)))
+}
+
+func entityOwnerKey(id types.EntityID) string {
+	return "entity-" + strconv.FormatUint(uint64(id), 10)
+}
+
+func personaEntityCountKey(personaTag string) string {
+	return "persona-count-" + personaTag
+}
+
+func personaEntityKey(personaTag string, index int) string {
+	return "persona-entity-" + strconv.Itoa(index) + "-" + personaTag
+}
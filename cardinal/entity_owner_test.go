@@ -0,0 +1,122 @@
+package cardinal_test
+
+import (
+	"errors"
+	"testing"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/gamestate"
+	"pkg.world.dev/world-engine/cardinal/testutils"
+	"pkg.world.dev/world-engine/cardinal/types"
+)
+
+func TestPersonaOwnsClaimedEntities(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil)
+	world := tf.World
+	assert.NilError(t, cardinal.RegisterComponent[Health](world))
+	tf.StartWorld()
+	wCtx := cardinal.NewWorldContext(world)
+
+	created, err := cardinal.CreateForPersona(wCtx, "alice", Health{})
+	assert.NilError(t, err)
+	ids, err := cardinal.CreateMany(wCtx, 3, Health{})
+	assert.NilError(t, err)
+	assert.NilError(t, cardinal.ClaimEntity(wCtx, "alice", ids[2]))
+	assert.NilError(t, cardinal.ClaimEntity(wCtx, "alice", ids[0]))
+	// Claiming an entity twice does nothing.
+	assert.NilError(t, cardinal.ClaimEntity(wCtx, "alice", ids[0]))
+
+	owned, err := wCtx.GetOwnedEntities("alice")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []types.EntityID{created, ids[0], ids[2]}, owned)
+
+	ok, err := cardinal.OwnsEntity(wCtx, "alice", ids[0])
+	assert.NilError(t, err)
+	assert.Check(t, ok)
+	ok, err = cardinal.OwnsEntity(wCtx, "bob", ids[0])
+	assert.NilError(t, err)
+	assert.Check(t, !ok)
+	ok, err = cardinal.OwnsEntity(wCtx, "alice", ids[1])
+	assert.NilError(t, err)
+	assert.Check(t, !ok)
+
+	err = cardinal.ClaimEntity(wCtx, "bob", ids[0])
+	assert.ErrorIs(t, err, cardinal.ErrEntityAlreadyOwned)
+	err = cardinal.ReleaseEntity(wCtx, "bob", ids[0])
+	assert.ErrorIs(t, err, cardinal.ErrEntityNotOwned)
+	err = cardinal.ClaimEntity(wCtx, "bob", 1000)
+	assert.Check(t, errors.Is(err, cardinal.ErrEntityDoesNotExist))
+
+	// Released and removed entities are no longer owned, and can be claimed by another persona.
+	assert.NilError(t, cardinal.ReleaseEntity(wCtx, "alice", ids[0]))
+	assert.NilError(t, cardinal.Remove(wCtx, created))
+	owned, err = wCtx.GetOwnedEntities("alice")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []types.EntityID{ids[2]}, owned)
+	assert.NilError(t, cardinal.ClaimEntity(wCtx, "bob", ids[0]))
+	owner, ok, err := cardinal.GetEntityOwner(wCtx, ids[0])
+	assert.NilError(t, err)
+	assert.Check(t, ok)
+	assert.Equal(t, "bob", owner)
+
+	assert.NilError(t, cardinal.ReleaseEntity(wCtx, "alice", ids[2]))
+	owned, err = wCtx.GetOwnedEntities("alice")
+	assert.NilError(t, err)
+	assert.Equal(t, 0, len(owned))
+}
+
+func TestClaimEntityCountsTowardsThePersonaQuota(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil, cardinal.WithEntityQuota(cardinal.EntityQuota{MaxEntitiesPerPersona: 1}))
+	world := tf.World
+	assert.NilError(t, cardinal.RegisterComponent[Health](world))
+	tf.StartWorld()
+	wCtx := cardinal.NewWorldContext(world)
+
+	ids, err := cardinal.CreateMany(wCtx, 2, Health{})
+	assert.NilError(t, err)
+	assert.NilError(t, cardinal.ClaimEntity(wCtx, "alice", ids[0]))
+	err = cardinal.ClaimEntity(wCtx, "alice", ids[1])
+	assert.ErrorIs(t, err, cardinal.ErrPersonaEntityQuotaExceeded)
+	_, err = cardinal.CreateForPersona(wCtx, "alice", Health{})
+	assert.ErrorIs(t, err, cardinal.ErrPersonaEntityQuotaExceeded)
+
+	count, err := cardinal.CountPersonaEntities(wCtx, "alice")
+	assert.NilError(t, err)
+	assert.Equal(t, 1, count)
+}
+
+func TestReleasingEntitiesKeepsTheOtherOwnedEntities(t *testing.T) {
+	// The persona owns more entities than would fit in a single raw storage value as a list.
+	quota := gamestate.DefaultRawStorageQuota
+	quota.MaxValueSize = 64
+	tf := testutils.NewTestFixture(t, nil, cardinal.WithRawStorageQuota(quota))
+	world := tf.World
+	assert.NilError(t, cardinal.RegisterComponent[Health](world))
+	tf.StartWorld()
+	wCtx := cardinal.NewWorldContext(world)
+
+	ids, err := cardinal.CreateManyForPersona(wCtx, "alice", 100, Health{})
+	assert.NilError(t, err)
+	for _, i := range []int{0, 50, len(ids) - 1, 42} {
+		assert.NilError(t, cardinal.ReleaseEntity(wCtx, "alice", ids[i]))
+	}
+	assert.NilError(t, cardinal.Remove(wCtx, ids[7]))
+
+	owned, err := wCtx.GetOwnedEntities("alice")
+	assert.NilError(t, err)
+	assert.Equal(t, len(ids)-5, len(owned))
+	for _, id := range []types.EntityID{ids[0], ids[7], ids[42], ids[50], ids[len(ids)-1]} {
+		ok, err := cardinal.OwnsEntity(wCtx, "alice", id)
+		assert.NilError(t, err)
+		assert.Check(t, !ok)
+	}
+	for _, id := range owned {
+		ok, err := cardinal.OwnsEntity(wCtx, "alice", id)
+		assert.NilError(t, err)
+		assert.Check(t, ok)
+	}
+	count, err := cardinal.CountPersonaEntities(wCtx, "alice")
+	assert.NilError(t, err)
+	assert.Equal(t, len(ids)-5, count)
+}
@@ -0,0 +1,272 @@
+package cardinal
+
+import (
+	"errors"
+
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+// DefaultHighWaterMark is the fraction of the world's entity or component cap at which pressure events are emitted,
+// if EntityQuota.HighWaterMark is not set.
+const DefaultHighWaterMark = 0.9
+
+// Events emitted when the number of live entities or components crosses the high-water mark of the world's cap.
+const (
+	EntityPressureEvent         = "entity-pressure"
+	EntityPressureRelievedEvent = "entity-pressure-relieved"
+)
+
+var (
+	ErrPersonaEntityQuotaExceeded = errors.New("persona entity quota exceeded")
+	ErrSystemEntityQuotaExceeded  = errors.New("system entity quota exceeded")
+	ErrWorldEntityCapExceeded     = errors.New("world entity cap exceeded")
+	ErrWorldComponentCapExceeded  = errors.New("world component cap exceeded")
+)
+
+// EntityQuota limits how many entities can be created. A zero value for any field disables that particular limit.
+type EntityQuota struct {
+	// MaxEntitiesPerPersona is the maximum number of live entities that a single persona can own. Ownership is assigned
+	// with CreateForPersona, CreateManyForPersona and ClaimEntity.
+	MaxEntitiesPerPersona int
+	// MaxEntitiesPerSystemPerTick is the maximum number of entities that a single system can create in one tick.
+	MaxEntitiesPerSystemPerTick int
+	// MaxEntities is the maximum number of live entities in the world. Together with MaxComponents it bounds the memory
+	// used by the game state, so that a shard sized for a given number of entities rejects new ones instead of running
+	// out of memory in the middle of a tick.
+	MaxEntities int
+	// MaxComponents is the maximum number of live components, summed over all entities in the world.
+	MaxComponents int
+	// HighWaterMark is the fraction of MaxEntities or MaxComponents at which an EntityPressureEvent is emitted, so that
+	// games can start shedding load before creations are rejected. Defaults to DefaultHighWaterMark.
+	HighWaterMark float64
+}
+
+// entityUsage is the number of live entities and components in the world.
+type entityUsage struct {
+	entities   int
+	components int
+}
+
+// entityQuotaTracker enforces the EntityQuota of a world. It counts the entities created by each system in the current
+// tick, while the number of entities owned by each persona is read from raw storage.
+//
+// When the world has an entity or component cap, the live entities and components are counted once from the entity
+// store and then kept up to date as entities are created and removed.
+type entityQuotaTracker struct {
+	quota   EntityQuota
+	tick    uint64
+	created map[string]int
+
+	usage         entityUsage
+	usageLoaded   bool
+	underPressure bool
+}
+
+func newEntityQuotaTracker() *entityQuotaTracker {
+	return &entityQuotaTracker{created: map[string]int{}}
+}
+
+func (t *entityQuotaTracker) reservePersona(wCtx engine.Context, personaTag string, num int) error {
+	if t.quota.MaxEntitiesPerPersona <= 0 || personaTag == "" {
+		return nil
+	}
+	store, err := NewRawStorage(wCtx, entityOwnerNamespace)
+	if err != nil {
+		return err
+	}
+	owned, err := countOwnedEntities(store, personaTag)
+	if err != nil {
+		return err
+	}
+	if owned+num > t.quota.MaxEntitiesPerPersona {
+		return eris.Wrapf(ErrPersonaEntityQuotaExceeded, "persona %q owns %d of %d entities",
+			personaTag, owned, t.quota.MaxEntitiesPerPersona)
+	}
+	return nil
+}
+
+// checkSystem fails if the system can't create num more entities in the given tick. The entities only count towards
+// the quota of the system once they are added with commitSystem, so that a creation that is rejected for another
+// reason doesn't use up the quota.
+func (t *entityQuotaTracker) checkSystem(tick uint64, system string, num int) error {
+	// Entities created outside of systems (e.g. in tests) are not subject to the per-system quota.
+	if !t.limitsSystem(system) {
+		return nil
+	}
+	if t.tick != tick {
+		clear(t.created)
+		t.tick = tick
+	}
+	if t.created[system]+num > t.quota.MaxEntitiesPerSystemPerTick {
+		return eris.Wrapf(ErrSystemEntityQuotaExceeded, "system %s cannot create more than %d entities per tick",
+			system, t.quota.MaxEntitiesPerSystemPerTick)
+	}
+	return nil
+}
+
+// commitSystem counts num entities towards the quota of the system in the tick that was last passed to checkSystem.
+func (t *entityQuotaTracker) commitSystem(system string, num int) {
+	if t.limitsSystem(system) {
+		t.created[system] += num
+	}
+}
+
+// cancelSystem removes num entities that were added with commitSystem but could not be created from the quota of the
+// system.
+func (t *entityQuotaTracker) cancelSystem(system string, num int) {
+	if t.limitsSystem(system) {
+		t.created[system] = max(t.created[system]-num, 0)
+	}
+}
+
+func (t *entityQuotaTracker) limitsSystem(system string) bool {
+	return t.quota.MaxEntitiesPerSystemPerTick > 0 && system != noActiveSystemName
+}
+
+func (t *entityQuotaTracker) hasCap() bool {
+	return t.quota.MaxEntities > 0 || t.quota.MaxComponents > 0
+}
+
+// reserveCapacity fails if creating the given number of entities and components would exceed the world's cap.
+// Otherwise, they are added to the live counts.
+func (t *entityQuotaTracker) reserveCapacity(wCtx engine.Context, entities, components int) error {
+	if !t.hasCap() {
+		// The live counts are not kept up to date without a cap, so they are recounted once a cap is set.
+		t.usageLoaded = false
+		return nil
+	}
+	if err := t.loadUsage(wCtx); err != nil {
+		return err
+	}
+	if limit := t.quota.MaxEntities; limit > 0 && t.usage.entities+entities > limit {
+		return eris.Wrapf(ErrWorldEntityCapExceeded, "world has %d of %d entities", t.usage.entities, limit)
+	}
+	if limit := t.quota.MaxComponents; limit > 0 && t.usage.components+components > limit {
+		return eris.Wrapf(ErrWorldComponentCapExceeded, "world has %d of %d components", t.usage.components, limit)
+	}
+	t.usage.entities += entities
+	t.usage.components += components
+	return t.checkPressure(wCtx)
+}
+
+// release removes the given number of entities and components from the live counts.
+func (t *entityQuotaTracker) release(wCtx engine.Context, entities, components int) error {
+	if !t.hasCap() {
+		t.usageLoaded = false
+		return nil
+	}
+	if !t.usageLoaded {
+		return nil
+	}
+	t.usage.entities -= entities
+	t.usage.components -= components
+	return t.checkPressure(wCtx)
+}
+
+// reset makes the tracker recount the live entities and components, e.g. after the game state was rolled back.
+func (t *entityQuotaTracker) reset() {
+	t.usageLoaded = false
+	t.underPressure = false
+}
+
+// discardTick forgets the entities created in the current tick, e.g. when the changes of the tick are discarded.
+func (t *entityQuotaTracker) discardTick() {
+	clear(t.created)
+	t.reset()
+}
+
+func (t *entityQuotaTracker) loadUsage(wCtx engine.Context) error {
+	if t.usageLoaded {
+		return nil
+	}
+	store := wCtx.StoreManager()
+	var usage entityUsage
+	for i := 0; i < store.ArchetypeCount(); i++ {
+		archID := types.ArchetypeID(i)
+		count, err := store.CountEntitiesForArchID(archID)
+		if err != nil {
+			return err
+		}
+		comps, err := store.GetComponentTypesForArchID(archID)
+		if err != nil {
+			return err
+		}
+		usage.entities += count
+		usage.components += count * len(comps)
+	}
+	t.usage = usage
+	t.usageLoaded = true
+	return nil
+}
+
+// checkPressure emits an EntityPressureEvent when the live counts rise above the high-water mark of the cap, and an
+// EntityPressureRelievedEvent when they fall below it again.
+func (t *entityQuotaTracker) checkPressure(wCtx engine.Context) error {
+	mark := t.quota.HighWaterMark
+	if mark <= 0 {
+		mark = DefaultHighWaterMark
+	}
+	above := aboveMark(t.usage.entities, t.quota.MaxEntities, mark) ||
+		aboveMark(t.usage.components, t.quota.MaxComponents, mark)
+	if above == t.underPressure {
+		return nil
+	}
+	t.underPressure = above
+	event := EntityPressureRelievedEvent
+	if above {
+		event = EntityPressureEvent
+		wCtx.Logger().Warn().
+			Int("entities", t.usage.entities).Int("max_entities", t.quota.MaxEntities).
+			Int("components", t.usage.components).Int("max_components", t.quota.MaxComponents).
+			Msg("world is close to its entity cap")
+	}
+	return wCtx.EmitEvent(map[string]any{
+		"event":         event,
+		"entities":      t.usage.entities,
+		"maxEntities":   t.quota.MaxEntities,
+		"components":    t.usage.components,
+		"maxComponents": t.quota.MaxComponents,
+	})
+}
+
+func aboveMark(count, limit int, mark float64) bool {
+	return limit > 0 && float64(count) >= mark*float64(limit)
+}
+
+// CreateForPersona creates an entity that is owned by the given persona. ErrPersonaEntityQuotaExceeded is returned if
+// the persona already owns the maximum number of entities allowed by the world's EntityQuota.
+func CreateForPersona(wCtx engine.Context, personaTag string, components ...types.Component) (types.EntityID, error) {
+	entityIDs, err := CreateManyForPersona(wCtx, personaTag, 1, components...)
+	if err != nil {
+		return 0, err
+	}
+	return entityIDs[0], nil
+}
+
+// CreateManyForPersona creates multiple entities that are owned by the given persona. Either all entities are created,
+// or none are, e.g. if the persona would exceed its entity quota. Ownership is released when the entity is removed.
+// Ownership records are kept in raw storage, so they count towards the raw storage write quota of the tick, and no
+// entities are created if the records exceed it.
+func CreateManyForPersona(
+	wCtx engine.Context, personaTag string, num int, components ...types.Component,
+) (entityIDs []types.EntityID, err error) {
+	defer func() { panicOnFatalError(wCtx, err) }()
+
+	if personaTag == "" {
+		return nil, eris.New("persona tag must not be empty")
+	}
+
+	entityIDs, err = createMany(wCtx, personaTag, num, components...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = claimEntities(wCtx, personaTag, entityIDs); err != nil {
+		// Undo the creation, so that the persona doesn't end up with entities that it doesn't own
+		return nil, withCleanup(err, discardEntityClaims(wCtx, personaTag, entityIDs), undoCreate(wCtx, entityIDs))
+	}
+	return entityIDs, nil
+}
@@ -0,0 +1,234 @@
+package cardinal_test
+
+import (
+	"testing"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/faults"
+	"pkg.world.dev/world-engine/cardinal/gamestate"
+	"pkg.world.dev/world-engine/cardinal/search/filter"
+	"pkg.world.dev/world-engine/cardinal/testutils"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+func TestPersonaEntityQuota(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil, cardinal.WithEntityQuota(cardinal.EntityQuota{MaxEntitiesPerPersona: 2}))
+	world := tf.World
+	assert.NilError(t, cardinal.RegisterComponent[Health](world))
+	tf.StartWorld()
+	wCtx := cardinal.NewWorldContext(world)
+
+	ids, err := cardinal.CreateManyForPersona(wCtx, "alice", 2, Health{})
+	assert.NilError(t, err)
+	_, err = cardinal.CreateForPersona(wCtx, "alice", Health{})
+	assert.ErrorIs(t, err, cardinal.ErrPersonaEntityQuotaExceeded)
+
+	// Other personas and unowned entities are not affected by alice's quota.
+	_, err = cardinal.CreateForPersona(wCtx, "bob", Health{})
+	assert.NilError(t, err)
+	_, err = cardinal.CreateMany(wCtx, 5, Health{})
+	assert.NilError(t, err)
+
+	owner, ok, err := cardinal.GetEntityOwner(wCtx, ids[0])
+	assert.NilError(t, err)
+	assert.Assert(t, ok)
+	assert.Equal(t, "alice", owner)
+
+	// Removing an entity releases its slot in the quota.
+	assert.NilError(t, cardinal.Remove(wCtx, ids[0]))
+	count, err := cardinal.CountPersonaEntities(wCtx, "alice")
+	assert.NilError(t, err)
+	assert.Equal(t, 1, count)
+	_, ok, err = cardinal.GetEntityOwner(wCtx, ids[0])
+	assert.NilError(t, err)
+	assert.Assert(t, !ok)
+	_, err = cardinal.CreateForPersona(wCtx, "alice", Health{})
+	assert.NilError(t, err)
+}
+
+func TestSystemEntityQuotaIsPerTick(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil,
+		cardinal.WithEntityQuota(cardinal.EntityQuota{MaxEntitiesPerSystemPerTick: 3}))
+	world := tf.World
+	assert.NilError(t, cardinal.RegisterComponent[Health](world))
+
+	var errs []error
+	assert.NilError(t, cardinal.RegisterSystems(world, func(wCtx engine.Context) error {
+		_, err := cardinal.CreateMany(wCtx, 2, Health{})
+		errs = append(errs, err)
+		_, err = cardinal.CreateMany(wCtx, 2, Health{})
+		errs = append(errs, err)
+		return nil
+	}))
+
+	tf.DoTick()
+	tf.DoTick()
+
+	assert.Equal(t, 4, len(errs))
+	for tick := 0; tick < 2; tick++ {
+		assert.NilError(t, errs[tick*2])
+		assert.ErrorIs(t, errs[tick*2+1], cardinal.ErrSystemEntityQuotaExceeded)
+	}
+}
+
+func TestSystemEntityQuotaIsNotUsedByRejectedCreations(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil,
+		cardinal.WithEntityQuota(cardinal.EntityQuota{MaxEntitiesPerSystemPerTick: 3, MaxEntities: 2}))
+	world := tf.World
+	assert.NilError(t, cardinal.RegisterComponent[Health](world))
+
+	var errs []error
+	assert.NilError(t, cardinal.RegisterSystems(world, func(wCtx engine.Context) error {
+		if wCtx.CurrentTick() > 0 {
+			return nil
+		}
+		_, err := cardinal.CreateMany(wCtx, 3, Health{})
+		errs = append(errs, err)
+		_, err = cardinal.CreateMany(wCtx, 2, Health{})
+		errs = append(errs, err)
+		return nil
+	}))
+
+	tf.DoTick()
+
+	// The creation that exceeds the world cap doesn't count towards the quota of the system
+	assert.Equal(t, 2, len(errs))
+	assert.ErrorIs(t, errs[0], cardinal.ErrWorldEntityCapExceeded)
+	assert.NilError(t, errs[1])
+}
+
+func TestWorldEntityCap(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil, cardinal.WithEntityQuota(cardinal.EntityQuota{MaxEntities: 3}))
+	world := tf.World
+	assert.NilError(t, cardinal.RegisterComponent[Health](world))
+	tf.StartWorld()
+	wCtx := cardinal.NewWorldContext(world)
+
+	ids, err := cardinal.CreateMany(wCtx, 3, Health{})
+	assert.NilError(t, err)
+	// Creations beyond the cap are rejected as a whole, without creating any entities.
+	_, err = cardinal.Create(wCtx, Health{})
+	assert.ErrorIs(t, err, cardinal.ErrWorldEntityCapExceeded)
+	_, err = cardinal.CreateForPersona(wCtx, "alice", Health{})
+	assert.ErrorIs(t, err, cardinal.ErrWorldEntityCapExceeded)
+
+	// Removing an entity makes room for a new one.
+	assert.NilError(t, cardinal.Remove(wCtx, ids[0]))
+	_, err = cardinal.Create(wCtx, Health{})
+	assert.NilError(t, err)
+}
+
+func TestWorldComponentCap(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil)
+	world := tf.World
+	assert.NilError(t, cardinal.RegisterComponent[Health](world))
+	assert.NilError(t, cardinal.RegisterComponent[Foo](world))
+	tf.StartWorld()
+	wCtx := cardinal.NewWorldContext(world)
+
+	// Entities created before the cap is set count towards it.
+	id, err := cardinal.Create(wCtx, Health{}, Foo{})
+	assert.NilError(t, err)
+	assert.NilError(t, world.UpdateConfig(cardinal.ConfigKeyMaxComponents, "3"))
+
+	_, err = cardinal.Create(wCtx, Health{}, Foo{})
+	assert.ErrorIs(t, err, cardinal.ErrWorldComponentCapExceeded)
+	other, err := cardinal.Create(wCtx, Health{})
+	assert.NilError(t, err)
+	err = cardinal.AddComponentTo[Foo](wCtx, other)
+	assert.ErrorIs(t, err, cardinal.ErrWorldComponentCapExceeded)
+
+	assert.NilError(t, cardinal.RemoveComponentFrom[Foo](wCtx, id))
+	assert.NilError(t, cardinal.AddComponentTo[Foo](wCtx, other))
+}
+
+func TestFailedCreationGivesBackTheEntityQuota(t *testing.T) {
+	inj := faults.NewInjector()
+	tf := testutils.NewTestFixture(t, nil,
+		cardinal.WithFaultInjection(inj),
+		cardinal.WithEntityQuota(cardinal.EntityQuota{MaxEntities: 2}))
+	world := tf.World
+	assert.NilError(t, cardinal.RegisterComponent[Health](world))
+	assert.NilError(t, cardinal.RegisterComponent[Foo](world))
+	tf.StartWorld()
+	wCtx := cardinal.NewWorldContext(world)
+	_, err := cardinal.Create(wCtx, Foo{})
+	assert.NilError(t, err)
+
+	// The entities of the new archetype can't be loaded, so the store fails to create the entity.
+	assert.NilError(t, inj.Add(faults.Fault{Op: "storage.GetBytes", Err: cardinal.ErrEntityDoesNotExist}))
+	_, err = cardinal.Create(wCtx, Health{})
+	assert.ErrorIs(t, err, cardinal.ErrEntityDoesNotExist)
+	inj.Clear()
+
+	_, err = cardinal.Create(wCtx, Health{})
+	assert.NilError(t, err)
+}
+
+func TestRejectedCreationGivesBackTheSystemEntityQuota(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil,
+		cardinal.WithEntityQuota(cardinal.EntityQuota{MaxEntitiesPerSystemPerTick: 2}))
+	world := tf.World
+	assert.NilError(t, cardinal.RegisterComponent[Username](world))
+	assert.NilError(t, cardinal.RegisterUniqueIndex[Username](world, "username", func(u Username) string {
+		return u.Handle
+	}))
+
+	var errs []error
+	assert.NilError(t, cardinal.RegisterSystems(world, func(wCtx engine.Context) error {
+		if wCtx.CurrentTick() > 0 {
+			return nil
+		}
+		_, err := cardinal.CreateMany(wCtx, 2, Username{Handle: "twin"})
+		errs = append(errs, err)
+		_, err = cardinal.CreateMany(wCtx, 2, Username{})
+		errs = append(errs, err)
+		return nil
+	}))
+
+	tf.DoTick()
+
+	assert.Equal(t, 2, len(errs))
+	assert.ErrorIs(t, errs[0], cardinal.ErrUniqueIndexViolation)
+	assert.NilError(t, errs[1])
+}
+
+func TestPersonaCreationThatExceedsTheRawStorageQuotaCreatesNoEntities(t *testing.T) {
+	// Claiming two entities takes five raw writes.
+	quota := gamestate.DefaultRawStorageQuota
+	quota.MaxWritesPerTick = 3
+	tf := testutils.NewTestFixture(t, nil,
+		cardinal.WithRawStorageQuota(quota),
+		cardinal.WithEntityQuota(cardinal.EntityQuota{MaxEntities: 2}))
+	world := tf.World
+	assert.NilError(t, cardinal.RegisterComponent[Health](world))
+	tf.StartWorld()
+	wCtx := cardinal.NewWorldContext(world)
+
+	_, err := cardinal.CreateManyForPersona(wCtx, "alice", 2, Health{})
+	assert.ErrorIs(t, err, cardinal.ErrRawStorageQuotaExceeded)
+	healthSearch := cardinal.NewSearch().Entity(filter.Contains(filter.Component[Health]()))
+	count, err := healthSearch.Count(wCtx)
+	assert.NilError(t, err)
+	assert.Equal(t, 0, count)
+
+	// The reservation of the entities is given back
+	ids, err := cardinal.CreateMany(wCtx, 2, Health{})
+	assert.NilError(t, err)
+	tf.DoTick()
+
+	// No ownership records of the removed entities are committed
+	count, err = cardinal.CountPersonaEntities(wCtx, "alice")
+	assert.NilError(t, err)
+	assert.Equal(t, 0, count)
+	for _, id := range []types.EntityID{ids[0] - 2, ids[0] - 1} {
+		_, ok, err := cardinal.GetEntityOwner(wCtx, id)
+		assert.NilError(t, err)
+		assert.Check(t, !ok)
+	}
+	count, err = healthSearch.Count(wCtx)
+	assert.NilError(t, err)
+	assert.Equal(t, 2, count)
+}
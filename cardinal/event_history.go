@@ -0,0 +1,115 @@
+package cardinal
+
+import (
+	"errors"
+	"time"
+
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal/eventlog"
+	"pkg.world.dev/world-engine/cardinal/gamestate"
+	"pkg.world.dev/world-engine/cardinal/types"
+)
+
+// maxEventHistoryScan is the maximum number of ticks that one call of GetEventHistory reads, so that reading the
+// history of a world with many ticks without events can't stall the storage.
+const maxEventHistoryScan = 10_000
+
+var (
+	ErrEventHistoryDisabled = types.ErrEventHistoryDisabled
+	ErrEventsNotAvailable   = types.ErrEventsNotAvailable
+)
+
+// EventRetention limits how long the events of a tick are kept by WithEventHistory. The events of a tick are deleted
+// once either limit is exceeded. A zero value for any field disables that particular limit, so the zero EventRetention
+// keeps the events of all ticks.
+type EventRetention struct {
+	// Ticks is the number of most recent ticks whose events are kept.
+	Ticks uint64
+	// Age is how long the events of a tick are kept, measured from the timestamp of the tick.
+	Age time.Duration
+}
+
+// GetEventHistory returns the events of the completed ticks from fromTick onward, oldest first. Ticks without events
+// are skipped. At most limit ticks are returned, or all of them if limit is 0. The returned tick is the tick to read
+// the rest of the history from, which is the current tick once the whole history was read.
+//
+// ErrEventsNotAvailable is returned if the events of a tick were already deleted because of the EventRetention, so
+// that a client that fell too far behind knows that it missed events.
+func (w *World) GetEventHistory(fromTick uint64, limit int) ([]types.TickEvents, uint64, error) {
+	if !w.recordEvents {
+		return nil, 0, eris.Wrap(ErrEventHistoryDisabled, "")
+	}
+	end := min(w.CurrentTick(), fromTick+maxEventHistoryScan)
+	var ticks []types.TickEvents
+	next := fromTick
+	for ; next < end && (limit <= 0 || len(ticks) < limit); next++ {
+		bz, err := w.GetTickLog(next)
+		if errors.Is(err, gamestate.ErrTickLogNotFound) {
+			return nil, 0, eris.Wrapf(ErrEventsNotAvailable, "tick %d", next)
+		} else if err != nil {
+			return nil, 0, err
+		}
+		entry, err := eventlog.DecodeTickEntry(bz)
+		if err != nil {
+			return nil, 0, err
+		}
+		if len(entry.GetEvents()) == 0 {
+			continue
+		}
+		ticks = append(ticks, types.TickEvents{
+			Tick:      entry.GetTick(),
+			Timestamp: entry.GetTimestamp(),
+			Events:    entry.GetEvents(),
+		})
+	}
+	return ticks, next, nil
+}
+
+// pruneEventHistory buffers the deletion of the tick log entries that are older than the EventRetention allows. The
+// deletion is committed with the current tick, whose timestamp is given.
+func (w *World) pruneEventHistory(timestamp uint64) error {
+	retention := w.eventRetention
+	if retention.Ticks == 0 && retention.Age <= 0 {
+		return nil
+	}
+	tick := w.CurrentTick()
+	var before uint64
+	if retention.Ticks > 0 && tick >= retention.Ticks {
+		before = tick + 1 - retention.Ticks
+	}
+	if retention.Age > 0 {
+		start, err := w.entityStore.GetTickLogStart()
+		if err != nil {
+			return err
+		}
+		cutoff := timestamp - min(timestamp, uint64(retention.Age/time.Second))
+		// Only the oldest entries need to be read, since ticks are committed in timestamp order
+		for t := max(start, before); t < tick && t < start+gamestate.MaxTickLogPrunesPerTick; t++ {
+			expired, err := w.tickLogExpired(t, cutoff)
+			if err != nil {
+				return err
+			} else if !expired {
+				break
+			}
+			before = t + 1
+		}
+	}
+	return w.entityStore.PruneTickLog(before)
+}
+
+// tickLogExpired reports whether the tick log entry of the given tick has a timestamp before the cutoff. Ticks without
+// an entry, because they completed before events were recorded, have expired.
+func (w *World) tickLogExpired(tick, cutoff uint64) (bool, error) {
+	bz, err := w.GetTickLog(tick)
+	if errors.Is(err, gamestate.ErrTickLogNotFound) {
+		return true, nil
+	} else if err != nil {
+		return false, err
+	}
+	entry, err := eventlog.DecodeTickEntry(bz)
+	if err != nil {
+		return false, err
+	}
+	return entry.GetTimestamp() < cutoff, nil
+}
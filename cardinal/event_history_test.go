@@ -0,0 +1,80 @@
+package cardinal_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/testutils"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+// emitOnEvenTicksSystem emits an event with the current tick during every even tick.
+func emitOnEvenTicksSystem(wCtx engine.Context) error {
+	if wCtx.CurrentTick()%2 != 0 {
+		return nil
+	}
+	return wCtx.EmitEvent(map[string]any{"tick": wCtx.CurrentTick()})
+}
+
+func TestEventHistorySkipsTicksWithoutEvents(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil, cardinal.WithEventHistory(cardinal.EventRetention{}))
+	world := tf.World
+	assert.NilError(t, cardinal.RegisterSystems(world, emitOnEvenTicksSystem))
+	for i := 0; i < 5; i++ {
+		tf.DoTick()
+	}
+
+	ticks, next, err := world.GetEventHistory(0, 0)
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(5), next)
+	assert.Equal(t, 3, len(ticks))
+	for i, tick := range ticks {
+		assert.Equal(t, uint64(i*2), tick.Tick)
+		assert.Equal(t, 1, len(tick.Events))
+		var event map[string]any
+		assert.NilError(t, json.Unmarshal(tick.Events[0], &event))
+		assert.Equal(t, float64(i*2), event["tick"])
+	}
+
+	// The returned tick is the cursor to read the rest of the history from.
+	ticks, next, err = world.GetEventHistory(1, 1)
+	assert.NilError(t, err)
+	assert.Equal(t, 1, len(ticks))
+	assert.Equal(t, uint64(2), ticks[0].Tick)
+	assert.Equal(t, uint64(3), next)
+
+	ticks, next, err = world.GetEventHistory(next, 0)
+	assert.NilError(t, err)
+	assert.Equal(t, 1, len(ticks))
+	assert.Equal(t, uint64(4), ticks[0].Tick)
+	assert.Equal(t, uint64(5), next)
+}
+
+func TestEventHistoryIsPrunedByRetention(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil, cardinal.WithEventHistory(cardinal.EventRetention{Ticks: 3}))
+	world := tf.World
+	assert.NilError(t, cardinal.RegisterSystems(world, emitOnEvenTicksSystem))
+	for i := 0; i < 6; i++ {
+		tf.DoTick()
+	}
+
+	// Only the events of ticks 3, 4 and 5 are kept.
+	_, _, err := world.GetEventHistory(2, 0)
+	assert.ErrorIs(t, err, cardinal.ErrEventsNotAvailable)
+
+	ticks, next, err := world.GetEventHistory(3, 0)
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(6), next)
+	assert.Equal(t, 1, len(ticks))
+	assert.Equal(t, uint64(4), ticks[0].Tick)
+}
+
+func TestEventHistoryIsDisabledByDefault(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil)
+	tf.DoTick()
+
+	_, _, err := tf.World.GetEventHistory(0, 0)
+	assert.ErrorIs(t, err, cardinal.ErrEventHistoryDisabled)
+}
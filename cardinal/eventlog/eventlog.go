@@ -0,0 +1,227 @@
+// Package eventlog serves the tick-ordered log of events and receipts over gRPC. Every completed tick has exactly one
+// log entry, which is committed in the same transaction as the tick's state changes. Indexers can stream the log from
+// any tick onward and resume after a disconnect using the resume token attached to every entry.
+package eventlog
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/rotisserie/eris"
+	zerolog "github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"pkg.world.dev/world-engine/cardinal/gamestate"
+	"pkg.world.dev/world-engine/cardinal/receipt"
+	eventlogv1 "pkg.world.dev/world-engine/rift/eventlog/v1"
+)
+
+const (
+	DefaultPort = "9030"
+)
+
+var _ eventlogv1.EventLogServer = (*Server)(nil)
+
+// Provider is the set of World methods the event log server depends on.
+type Provider interface {
+	Namespace() string
+	CurrentTick() uint64
+	GetTickLog(tick uint64) ([]byte, error)
+}
+
+type Server struct {
+	eventlogv1.UnimplementedEventLogServer
+
+	provider   Provider
+	grpcServer *grpc.Server
+	port       string
+
+	mu sync.Mutex
+	// tickDone is closed (and replaced) every time a tick completes.
+	tickDone chan struct{}
+	// shutdown is closed when the server is shutting down so that open streams can return.
+	shutdown chan struct{}
+}
+
+func NewServer(provider Provider, port string) *Server {
+	s := &Server{
+		provider: provider,
+		port:     port,
+		tickDone: make(chan struct{}),
+		shutdown: make(chan struct{}),
+	}
+	s.grpcServer = grpc.NewServer()
+	eventlogv1.RegisterEventLogServer(s.grpcServer, s)
+	return s
+}
+
+// Start serves the event log gRPC server.
+func (s *Server) Start() error {
+	listener, err := net.Listen("tcp", ":"+s.port)
+	if err != nil {
+		return eris.Wrapf(err, "error listening to port %s", s.port)
+	}
+	go func() {
+		err = eris.Wrap(s.grpcServer.Serve(listener), "error serving event log gRPC server")
+		if err != nil {
+			zerolog.Fatal().Err(err).Msg(eris.ToString(err, true))
+		}
+	}()
+	return nil
+}
+
+// Shutdown closes all open streams and stops the gRPC server.
+func (s *Server) Shutdown() {
+	s.mu.Lock()
+	select {
+	case <-s.shutdown:
+	default:
+		close(s.shutdown)
+	}
+	s.mu.Unlock()
+	s.grpcServer.GracefulStop()
+}
+
+// NotifyTickDone wakes up all streams that are waiting for the next tick. It must be called after the tick counter
+// has been incremented.
+func (s *Server) NotifyTickDone() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	close(s.tickDone)
+	s.tickDone = make(chan struct{})
+}
+
+func (s *Server) waitForTick() <-chan struct{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.tickDone
+}
+
+// Subscribe streams the log entries of every completed tick starting at the requested tick. Entries are always sent in
+// tick order, and the stream stays open until the client disconnects or the server shuts down.
+func (s *Server) Subscribe(req *eventlogv1.SubscribeRequest, stream eventlogv1.EventLog_SubscribeServer) error {
+	next := req.GetFromTick()
+	if token := req.GetResumeToken(); token != "" {
+		tick, err := DecodeResumeToken(s.provider.Namespace(), token)
+		if err != nil {
+			return status.Errorf(codes.InvalidArgument, "invalid resume token: %v", err)
+		}
+		next = tick + 1
+	}
+
+	for {
+		// Fetch the notification channel before checking the current tick so a tick that completes while we are
+		// sending entries is not missed.
+		tickDone := s.waitForTick()
+		for ; next < s.provider.CurrentTick(); next++ {
+			entry, err := s.loadEntry(next)
+			if err != nil {
+				return err
+			}
+			if err = stream.Send(entry); err != nil {
+				return eris.Wrapf(err, "failed to send tick %d", next)
+			}
+		}
+
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-s.shutdown:
+			return status.Error(codes.Unavailable, "event log server is shutting down")
+		case <-tickDone:
+		}
+	}
+}
+
+func (s *Server) loadEntry(tick uint64) (*eventlogv1.TickEntry, error) {
+	bz, err := s.provider.GetTickLog(tick)
+	if errors.Is(err, gamestate.ErrTickLogNotFound) {
+		return nil, status.Errorf(codes.OutOfRange, "tick %d is not available in the event log", tick)
+	} else if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to load tick %d: %v", tick, err)
+	}
+	entry, err := DecodeTickEntry(bz)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to decode tick %d: %v", tick, err)
+	}
+	entry.ResumeToken = EncodeResumeToken(s.provider.Namespace(), tick)
+	return entry, nil
+}
+
+// NewTickEntry serializes the events and receipts of a tick into a log entry that can be saved with
+// gamestate.TickStorage.SetTickLog. Receipts are sorted by transaction hash so that the entry is deterministic.
+func NewTickEntry(tick, timestamp uint64, events [][]byte, receipts []receipt.Receipt) ([]byte, error) {
+	entry := &eventlogv1.TickEntry{
+		Tick:      tick,
+		Timestamp: timestamp,
+		Events:    events,
+		Receipts:  make([]*eventlogv1.Receipt, 0, len(receipts)),
+	}
+	slices.SortFunc(receipts, func(a, b receipt.Receipt) int {
+		return strings.Compare(string(a.TxHash), string(b.TxHash))
+	})
+	for _, rec := range receipts {
+		result, err := json.Marshal(rec.Result)
+		if err != nil {
+			return nil, eris.Wrapf(err, "failed to marshal result of tx %q", rec.TxHash)
+		}
+		errs := make([]string, 0, len(rec.Errs))
+		for _, e := range rec.Errs {
+			errs = append(errs, e.Error())
+		}
+		entry.Receipts = append(entry.Receipts, &eventlogv1.Receipt{
+			TxHash: string(rec.TxHash),
+			Result: result,
+			Errors: errs,
+		})
+	}
+	bz, err := proto.Marshal(entry)
+	if err != nil {
+		return nil, eris.Wrap(err, "failed to marshal tick log entry")
+	}
+	return bz, nil
+}
+
+// DecodeTickEntry deserializes a log entry that was created with NewTickEntry.
+func DecodeTickEntry(bz []byte) (*eventlogv1.TickEntry, error) {
+	entry := &eventlogv1.TickEntry{}
+	if err := proto.Unmarshal(bz, entry); err != nil {
+		return nil, eris.Wrap(err, "failed to unmarshal tick log entry")
+	}
+	return entry, nil
+}
+
+// EncodeResumeToken returns the opaque resume token that points at the given tick.
+func EncodeResumeToken(namespace string, tick uint64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(namespace + ":" + strconv.FormatUint(tick, 10)))
+}
+
+// DecodeResumeToken returns the tick that the resume token points at. Tokens issued by a shard with a different
+// namespace are rejected.
+func DecodeResumeToken(namespace string, token string) (uint64, error) {
+	bz, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, eris.Wrap(err, "resume token is not valid base64")
+	}
+	i := strings.LastIndex(string(bz), ":")
+	if i < 0 {
+		return 0, eris.New("malformed resume token")
+	}
+	if gotNamespace := string(bz[:i]); gotNamespace != namespace {
+		return 0, eris.Errorf("resume token was issued by namespace %q", gotNamespace)
+	}
+	tick, err := strconv.ParseUint(string(bz[i+1:]), 10, 64)
+	if err != nil {
+		return 0, eris.Wrap(err, "malformed resume token")
+	}
+	return tick, nil
+}
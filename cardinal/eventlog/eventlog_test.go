@@ -0,0 +1,150 @@
+package eventlog
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rotisserie/eris"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal/gamestate"
+	"pkg.world.dev/world-engine/cardinal/receipt"
+	eventlogv1 "pkg.world.dev/world-engine/rift/eventlog/v1"
+)
+
+type fakeProvider struct {
+	mu   sync.Mutex
+	tick atomic.Uint64
+	logs map[uint64][]byte
+}
+
+func newFakeProvider() *fakeProvider {
+	return &fakeProvider{logs: map[uint64][]byte{}}
+}
+
+func (f *fakeProvider) Namespace() string   { return "ns" }
+func (f *fakeProvider) CurrentTick() uint64 { return f.tick.Load() }
+
+func (f *fakeProvider) GetTickLog(tick uint64) ([]byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	bz, ok := f.logs[tick]
+	if !ok {
+		return nil, eris.Wrap(gamestate.ErrTickLogNotFound, "")
+	}
+	return bz, nil
+}
+
+// completeTick records a log entry with a single event for the current tick and advances the tick counter.
+func (f *fakeProvider) completeTick(t *testing.T, event string) {
+	tick := f.tick.Load()
+	bz, err := NewTickEntry(tick, tick*10, [][]byte{[]byte(event)}, nil)
+	assert.NilError(t, err)
+	f.mu.Lock()
+	f.logs[tick] = bz
+	f.mu.Unlock()
+	f.tick.Add(1)
+}
+
+type fakeStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	sent chan *eventlogv1.TickEntry
+}
+
+func (f *fakeStream) Context() context.Context { return f.ctx }
+
+func (f *fakeStream) Send(entry *eventlogv1.TickEntry) error {
+	f.sent <- entry
+	return nil
+}
+
+func subscribe(
+	t *testing.T,
+	s *Server,
+	req *eventlogv1.SubscribeRequest,
+) (*fakeStream, <-chan error, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	stream := &fakeStream{ctx: ctx, sent: make(chan *eventlogv1.TickEntry, 100)}
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- s.Subscribe(req, stream)
+	}()
+	return stream, errCh, cancel
+}
+
+func receive(t *testing.T, stream *fakeStream) *eventlogv1.TickEntry {
+	select {
+	case entry := <-stream.sent:
+		return entry
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for a tick entry")
+	}
+	return nil
+}
+
+func TestSubscribeStreamsFromRequestedTickAndWaitsForNewTicks(t *testing.T) {
+	provider := newFakeProvider()
+	s := NewServer(provider, "")
+	for i := 0; i < 3; i++ {
+		provider.completeTick(t, "old")
+	}
+
+	stream, errCh, cancel := subscribe(t, s, &eventlogv1.SubscribeRequest{FromTick: 1})
+	assert.Equal(t, uint64(1), receive(t, stream).GetTick())
+	last := receive(t, stream)
+	assert.Equal(t, uint64(2), last.GetTick())
+	assert.Equal(t, uint64(20), last.GetTimestamp())
+
+	provider.completeTick(t, "new")
+	s.NotifyTickDone()
+	entry := receive(t, stream)
+	assert.Equal(t, uint64(3), entry.GetTick())
+	assert.Equal(t, "new", string(entry.GetEvents()[0]))
+
+	cancel()
+	assert.NilError(t, <-errCh)
+
+	// Resuming from the token of tick 2 must continue with tick 3.
+	stream, _, _ = subscribe(t, s, &eventlogv1.SubscribeRequest{ResumeToken: last.GetResumeToken()})
+	assert.Equal(t, uint64(3), receive(t, stream).GetTick())
+}
+
+func TestSubscribeFailsForMissingTicksAndBadTokens(t *testing.T) {
+	provider := newFakeProvider()
+	s := NewServer(provider, "")
+	// Simulate a tick that completed before the event log was enabled.
+	provider.tick.Add(1)
+	provider.completeTick(t, "event")
+
+	_, errCh, _ := subscribe(t, s, &eventlogv1.SubscribeRequest{FromTick: 0})
+	assert.Equal(t, codes.OutOfRange, status.Code(<-errCh))
+
+	_, errCh, _ = subscribe(t, s, &eventlogv1.SubscribeRequest{ResumeToken: EncodeResumeToken("other", 0)})
+	assert.Equal(t, codes.InvalidArgument, status.Code(<-errCh))
+}
+
+func TestNewTickEntrySortsReceipts(t *testing.T) {
+	bz, err := NewTickEntry(1, 2, nil, []receipt.Receipt{
+		{TxHash: "b", Result: map[string]int{"x": 1}},
+		{TxHash: "a", Errs: []error{errors.New("boom")}},
+	})
+	assert.NilError(t, err)
+
+	provider := newFakeProvider()
+	provider.logs[1] = bz
+	entry, err := NewServer(provider, "").loadEntry(1)
+	assert.NilError(t, err)
+	assert.Equal(t, 2, len(entry.GetReceipts()))
+	assert.Equal(t, "a", entry.GetReceipts()[0].GetTxHash())
+	assert.Equal(t, "boom", entry.GetReceipts()[0].GetErrors()[0])
+	assert.Equal(t, `{"x":1}`, string(entry.GetReceipts()[1].GetResult()))
+}
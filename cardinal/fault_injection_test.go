@@ -0,0 +1,107 @@
+package cardinal_test
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/faults"
+	"pkg.world.dev/world-engine/cardinal/message"
+	"pkg.world.dev/world-engine/cardinal/testutils"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+func TestFailedTicksAreRecoveredWithoutLosingOrRepeatingTransactions(t *testing.T) {
+	testCases := []struct {
+		name  string
+		fault faults.Fault
+		// wantAttempts is the number of ticks that are started until one is done
+		wantAttempts int
+		wantTick     uint64
+	}{
+		{
+			name:         "the transactions of the tick are not saved",
+			fault:        faults.Fault{Op: faults.OpCommit, Nth: 1, Err: io.EOF},
+			wantAttempts: 2,
+			wantTick:     2,
+		},
+		{
+			name:         "the changes of the tick are not committed",
+			fault:        faults.Fault{Op: faults.OpCommit, Nth: 2, Err: io.EOF},
+			wantAttempts: 2,
+			wantTick:     2,
+		},
+		{
+			name:         "the tick fails several times after its systems ran",
+			fault:        faults.Fault{Op: faults.TickSystems, Times: 3, Err: io.EOF},
+			wantAttempts: 4,
+			wantTick:     2,
+		},
+		{
+			// The world moves on to the next tick instead of running the committed tick again
+			name:         "the tick fails after its changes are committed",
+			fault:        faults.Fault{Op: faults.TickCommitted, Err: io.EOF},
+			wantAttempts: 2,
+			wantTick:     3,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			inj := faults.NewInjector()
+			tf := testutils.NewTestFixture(t, nil,
+				cardinal.WithFaultInjection(inj),
+				cardinal.WithStorageBreaker(cardinal.StorageBreaker{
+					Threshold:  10,
+					MinBackoff: 10 * time.Millisecond,
+					MaxBackoff: 20 * time.Millisecond,
+				}),
+			)
+			world := tf.World
+			assert.NilError(t, cardinal.RegisterComponent[CounterComponent](world))
+			assert.NilError(t, cardinal.RegisterMessage[HeartbeatMsg, HeartbeatMsg](world, "heartbeat"))
+			var id types.EntityID
+			assert.NilError(t, cardinal.RegisterSystems(world, func(wCtx engine.Context) error {
+				return cardinal.EachMessage[HeartbeatMsg, HeartbeatMsg](wCtx,
+					func(message.TxData[HeartbeatMsg]) (HeartbeatMsg, error) {
+						return HeartbeatMsg{}, cardinal.UpdateComponent[CounterComponent](wCtx, id,
+							func(c *CounterComponent) *CounterComponent {
+								c.Count++
+								return c
+							})
+					})
+			}))
+			tf.StartWorld()
+			wCtx := cardinal.NewWorldContext(world)
+			var err error
+			id, err = cardinal.Create(wCtx, CounterComponent{})
+			assert.NilError(t, err)
+			tf.DoTick()
+
+			heartbeat, ok := world.GetMessageByFullName("game.heartbeat")
+			assert.True(t, ok)
+			tf.AddTransaction(heartbeat.ID(), HeartbeatMsg{}, testutils.UniqueSignature())
+			assert.NilError(t, inj.Add(tc.fault))
+			attempts := inj.Count(faults.TickStart)
+
+			done := false
+			for i := 0; !done; i++ {
+				assert.Assert(t, i < 100, "the world did not recover from the fault")
+				select {
+				case tf.StartTickCh <- time.Now():
+				case <-tf.DoneTickCh:
+					done = true
+				}
+			}
+			assert.Equal(t, tc.wantAttempts, inj.Count(faults.TickStart)-attempts)
+			assert.Equal(t, tc.wantTick, world.CurrentTick())
+			assert.Equal(t, types.StorageHealth{}, world.StorageHealth())
+
+			c, err := cardinal.GetComponent[CounterComponent](wCtx, id)
+			assert.NilError(t, err)
+			assert.Equal(t, 1, c.Count)
+		})
+	}
+}
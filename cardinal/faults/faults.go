@@ -0,0 +1,175 @@
+// Package faults injects faults into the storage and the tick loop of a world, so that tests can check how the world
+// recovers from slow or failing storage, failed ticks and torn writes, without killing processes. It is only meant for
+// tests.
+//
+//	inj := faults.NewInjector()
+//	world, err := cardinal.NewWorld(cardinal.WithFaultInjection(inj))
+//	// The 3rd tick commit only writes 5 of its changes, and fails as if the connection was lost.
+//	inj.Add(faults.Fault{Op: faults.OpCommit, Nth: 3, PartialWrite: true, KeepWrites: 5, Err: io.EOF})
+//	// Every read of the storage takes 50ms.
+//	inj.Add(faults.Fault{Op: "storage.Get*", Times: -1, Latency: 50 * time.Millisecond})
+package faults
+
+import (
+	"context"
+	"errors"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/rotisserie/eris"
+)
+
+// The operations of the tick loop that faults can be injected at. The operations of the storage are named after the
+// methods of gamestate.PrimitiveStorage, e.g. "storage.GetBytes" and "storage.Set".
+const (
+	// TickStart is before a tick saves its transactions to the storage.
+	TickStart = "tick.start"
+	// TickSystems is after the systems of a tick ran, before its changes are committed.
+	TickSystems = "tick.systems"
+	// TickCommitted is after the changes of a tick were committed, before the tick is submitted to the base shard.
+	TickCommitted = "tick.committed"
+
+	// OpCommit is the commit of a storage transaction, e.g. the commit of the changes of a tick.
+	OpCommit = "storage.EndTransaction"
+)
+
+// ErrInjected is the error of the faults that have no Err. The storage breaker doesn't consider it a storage error;
+// use e.g. io.EOF to simulate a lost connection instead.
+var ErrInjected = errors.New("injected fault")
+
+// Fault is a fault that happens at some of the operations whose name matches Op.
+type Fault struct {
+	// Op is the pattern of the names of the operations that the fault happens at, as in path.Match, e.g. "tick.*" or
+	// "storage.Get*".
+	Op string
+	// Nth is the number of the matching operation, counted from 1 since the fault was added, that the fault happens
+	// at first. Zero is the same as 1.
+	Nth int
+	// Times is the number of consecutive matching operations that the fault happens at. Zero is the same as 1, and a
+	// negative number makes the fault happen at every matching operation from the Nth on.
+	Times int
+
+	// Latency delays the operation. An operation whose context is done before the delay is over fails with the error
+	// of the context.
+	Latency time.Duration
+	// Err is the error that the operation fails with. Faults with a Latency and no Err only delay the operation, the
+	// other ones fail with ErrInjected if Err is nil.
+	Err error
+	// PartialWrite makes a commit write only the first KeepWrites changes of the transaction, and then fail with Err,
+	// like a commit that was torn by a crash. Other operations fail as if it wasn't set.
+	PartialWrite bool
+	KeepWrites   int
+}
+
+func (f Fault) err() error {
+	if f.Err != nil {
+		return f.Err
+	}
+	if f.Latency > 0 && !f.PartialWrite {
+		return nil
+	}
+	return ErrInjected
+}
+
+type activeFault struct {
+	Fault
+	// seen is the number of matching operations since the fault was added.
+	seen int
+}
+
+// hits reports whether the fault happens at the next matching operation, and counts it.
+func (f *activeFault) hits() bool {
+	f.seen++
+	first := max(f.Nth, 1)
+	if f.seen < first {
+		return false
+	}
+	return f.Times < 0 || f.seen < first+max(f.Times, 1)
+}
+
+// Injector decides which operations fail. It is safe to use from several goroutines, e.g. from the test and from the
+// game loop.
+type Injector struct {
+	mu     sync.Mutex
+	faults []*activeFault
+	counts map[string]int
+}
+
+// NewInjector returns an injector without faults, whose operations all succeed.
+func NewInjector() *Injector {
+	return &Injector{counts: map[string]int{}}
+}
+
+// Add adds a fault. When several faults happen at the same operation, the first one that was added is used.
+func (i *Injector) Add(f Fault) error {
+	if _, err := path.Match(f.Op, ""); err != nil {
+		return eris.Wrapf(err, "invalid operation pattern %q", f.Op)
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.faults = append(i.faults, &activeFault{Fault: f})
+	return nil
+}
+
+// Clear removes all faults.
+func (i *Injector) Clear() {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.faults = nil
+}
+
+// Count returns the number of times the operation ran, including the times it failed.
+func (i *Injector) Count(op string) int {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.counts[op]
+}
+
+// Inject runs the fault that happens at the operation, if any: it waits for the latency of the fault and returns its
+// error. A nil injector never injects faults.
+func (i *Injector) Inject(ctx context.Context, op string) error {
+	f, ok := i.next(op)
+	if !ok {
+		return nil
+	}
+	if err := f.wait(ctx); err != nil {
+		return err
+	}
+	return f.err()
+}
+
+// next counts the operation and returns the fault that happens at it.
+func (i *Injector) next(op string) (Fault, bool) {
+	if i == nil {
+		return Fault{}, false
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.counts[op]++
+	var hit *activeFault
+	for _, f := range i.faults {
+		// Every matching fault counts the operation, even if an earlier fault happens at it
+		if matched, _ := path.Match(f.Op, op); matched && f.hits() && hit == nil {
+			hit = f
+		}
+	}
+	if hit == nil {
+		return Fault{}, false
+	}
+	return hit.Fault, true
+}
+
+func (f Fault) wait(ctx context.Context) error {
+	if f.Latency <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(f.Latency)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return eris.Wrap(ctx.Err(), "injected latency")
+	}
+}
@@ -0,0 +1,99 @@
+package faults_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal/faults"
+	"pkg.world.dev/world-engine/cardinal/gamestate"
+)
+
+func TestInjectorFailsTheNthOperations(t *testing.T) {
+	ctx := context.Background()
+	inj := faults.NewInjector()
+	assert.NilError(t, inj.Add(faults.Fault{Op: "tick.*", Nth: 2, Times: 2, Err: io.EOF}))
+	assert.NilError(t, inj.Add(faults.Fault{Op: faults.TickStart, Nth: 3}))
+
+	// The second fault also counts the operations that the first one fails
+	for i, want := range []error{nil, io.EOF, io.EOF, nil, nil} {
+		err := inj.Inject(ctx, faults.TickStart)
+		assert.Check(t, errors.Is(err, want), "operation %d: got %v, want %v", i+1, err, want)
+	}
+	assert.Equal(t, 5, inj.Count(faults.TickStart))
+	assert.Equal(t, 0, inj.Count(faults.TickCommitted))
+
+	assert.NilError(t, inj.Add(faults.Fault{Op: faults.TickCommitted, Times: -1}))
+	for i := 0; i < 3; i++ {
+		assert.ErrorIs(t, inj.Inject(ctx, faults.TickCommitted), faults.ErrInjected)
+	}
+	inj.Clear()
+	assert.NilError(t, inj.Inject(ctx, faults.TickCommitted))
+
+	assert.Assert(t, inj.Add(faults.Fault{Op: "tick.["}) != nil)
+
+	var noFaults *faults.Injector
+	assert.NilError(t, noFaults.Inject(ctx, faults.TickStart))
+}
+
+func TestInjectorDelaysOperations(t *testing.T) {
+	inj := faults.NewInjector()
+	assert.NilError(t, inj.Add(faults.Fault{Op: "storage.Get*", Times: -1, Latency: 20 * time.Millisecond}))
+
+	start := time.Now()
+	assert.NilError(t, inj.Inject(context.Background(), "storage.GetBytes"))
+	assert.Assert(t, time.Since(start) >= 20*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, inj.Inject(ctx, "storage.GetInt"), context.Canceled)
+	assert.NilError(t, inj.Inject(ctx, "storage.Set"))
+}
+
+func TestStorageTearsCommits(t *testing.T) {
+	ctx := context.Background()
+	s := miniredis.RunT(t)
+	inner := gamestate.NewRedisPrimitiveStorage(redis.NewClient(&redis.Options{Addr: s.Addr()}))
+	inj := faults.NewInjector()
+	storage := faults.WrapStorage(&inner, inj)
+
+	commit := func(values map[string]int, keys ...string) error {
+		tx, err := storage.StartTransaction(ctx)
+		assert.NilError(t, err)
+		for _, key := range keys {
+			assert.NilError(t, tx.Set(ctx, key, values[key]))
+		}
+		return tx.EndTransaction(ctx)
+	}
+	values := map[string]int{"a": 1, "b": 2, "c": 3}
+
+	assert.NilError(t, inj.Add(faults.Fault{Op: faults.OpCommit, Err: io.EOF}))
+	assert.NilError(t, inj.Add(faults.Fault{Op: faults.OpCommit, Nth: 2, PartialWrite: true, KeepWrites: 2}))
+
+	// A failed commit writes nothing
+	assert.ErrorIs(t, commit(values, "a", "b", "c"), io.EOF)
+	keys, err := storage.Keys(ctx)
+	assert.NilError(t, err)
+	assert.Equal(t, 0, len(keys))
+
+	// A torn commit only writes the first changes
+	assert.ErrorIs(t, commit(values, "a", "b", "c"), faults.ErrInjected)
+	keys, err = storage.Keys(ctx)
+	assert.NilError(t, err)
+	assert.ElementsMatch(t, []string{"a", "b"}, keys)
+
+	assert.NilError(t, commit(values, "c"))
+	for key, want := range values {
+		got, err := storage.GetInt(ctx, key)
+		assert.NilError(t, err)
+		assert.Equal(t, want, got)
+	}
+	assert.Equal(t, 3, inj.Count(faults.OpCommit))
+	assert.Equal(t, 7, inj.Count("storage.Set"))
+}
@@ -0,0 +1,257 @@
+package faults
+
+import (
+	"context"
+
+	"pkg.world.dev/world-engine/cardinal/gamestate"
+)
+
+var _ gamestate.PrimitiveStorage[string] = (*Storage)(nil)
+
+// Storage is a storage whose operations run the faults of an injector before they reach the underlying storage. The
+// operations are named "storage." followed by the name of their method, both outside and inside transactions.
+type Storage struct {
+	inner gamestate.PrimitiveStorage[string]
+	inj   *Injector
+}
+
+// WrapStorage returns a storage that injects the faults of inj into the operations of inner.
+func WrapStorage(inner gamestate.PrimitiveStorage[string], inj *Injector) *Storage {
+	return &Storage{inner: inner, inj: inj}
+}
+
+func (s *Storage) GetFloat64(ctx context.Context, key string) (float64, error) {
+	if err := s.inj.Inject(ctx, "storage.GetFloat64"); err != nil {
+		return 0, err
+	}
+	return s.inner.GetFloat64(ctx, key)
+}
+
+func (s *Storage) GetFloat32(ctx context.Context, key string) (float32, error) {
+	if err := s.inj.Inject(ctx, "storage.GetFloat32"); err != nil {
+		return 0, err
+	}
+	return s.inner.GetFloat32(ctx, key)
+}
+
+func (s *Storage) GetUInt64(ctx context.Context, key string) (uint64, error) {
+	if err := s.inj.Inject(ctx, "storage.GetUInt64"); err != nil {
+		return 0, err
+	}
+	return s.inner.GetUInt64(ctx, key)
+}
+
+func (s *Storage) GetInt64(ctx context.Context, key string) (int64, error) {
+	if err := s.inj.Inject(ctx, "storage.GetInt64"); err != nil {
+		return 0, err
+	}
+	return s.inner.GetInt64(ctx, key)
+}
+
+func (s *Storage) GetInt(ctx context.Context, key string) (int, error) {
+	if err := s.inj.Inject(ctx, "storage.GetInt"); err != nil {
+		return 0, err
+	}
+	return s.inner.GetInt(ctx, key)
+}
+
+func (s *Storage) GetBool(ctx context.Context, key string) (bool, error) {
+	if err := s.inj.Inject(ctx, "storage.GetBool"); err != nil {
+		return false, err
+	}
+	return s.inner.GetBool(ctx, key)
+}
+
+func (s *Storage) GetBytes(ctx context.Context, key string) ([]byte, error) {
+	if err := s.inj.Inject(ctx, "storage.GetBytes"); err != nil {
+		return nil, err
+	}
+	return s.inner.GetBytes(ctx, key)
+}
+
+func (s *Storage) GetManyBytes(ctx context.Context, keys []string) ([][]byte, error) {
+	if err := s.inj.Inject(ctx, "storage.GetManyBytes"); err != nil {
+		return nil, err
+	}
+	return s.inner.GetManyBytes(ctx, keys)
+}
+
+func (s *Storage) Get(ctx context.Context, key string) (any, error) {
+	if err := s.inj.Inject(ctx, "storage.Get"); err != nil {
+		return nil, err
+	}
+	return s.inner.Get(ctx, key)
+}
+
+func (s *Storage) Set(ctx context.Context, key string, value any) error {
+	if err := s.inj.Inject(ctx, "storage.Set"); err != nil {
+		return err
+	}
+	return s.inner.Set(ctx, key, value)
+}
+
+func (s *Storage) Incr(ctx context.Context, key string) error {
+	if err := s.inj.Inject(ctx, "storage.Incr"); err != nil {
+		return err
+	}
+	return s.inner.Incr(ctx, key)
+}
+
+func (s *Storage) Decr(ctx context.Context, key string) error {
+	if err := s.inj.Inject(ctx, "storage.Decr"); err != nil {
+		return err
+	}
+	return s.inner.Decr(ctx, key)
+}
+
+func (s *Storage) Delete(ctx context.Context, key string) error {
+	if err := s.inj.Inject(ctx, "storage.Delete"); err != nil {
+		return err
+	}
+	return s.inner.Delete(ctx, key)
+}
+
+func (s *Storage) DeleteFields(ctx context.Context, key string, fields ...string) error {
+	if err := s.inj.Inject(ctx, "storage.DeleteFields"); err != nil {
+		return err
+	}
+	return s.inner.DeleteFields(ctx, key, fields...)
+}
+
+func (s *Storage) GetMembers(ctx context.Context, key string) ([]string, error) {
+	if err := s.inj.Inject(ctx, "storage.GetMembers"); err != nil {
+		return nil, err
+	}
+	return s.inner.GetMembers(ctx, key)
+}
+
+func (s *Storage) AddMembers(ctx context.Context, key string, members ...string) error {
+	if err := s.inj.Inject(ctx, "storage.AddMembers"); err != nil {
+		return err
+	}
+	return s.inner.AddMembers(ctx, key, members...)
+}
+
+func (s *Storage) RemoveMembers(ctx context.Context, key string, members ...string) error {
+	if err := s.inj.Inject(ctx, "storage.RemoveMembers"); err != nil {
+		return err
+	}
+	return s.inner.RemoveMembers(ctx, key, members...)
+}
+
+func (s *Storage) StartTransaction(ctx context.Context) (gamestate.Transaction[string], error) {
+	if err := s.inj.Inject(ctx, "storage.StartTransaction"); err != nil {
+		return nil, err
+	}
+	tx, err := s.inner.StartTransaction(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &transaction{Storage: Storage{inner: tx, inj: s.inj}}, nil
+}
+
+func (s *Storage) EndTransaction(ctx context.Context) error {
+	if err := s.inj.Inject(ctx, OpCommit); err != nil {
+		return err
+	}
+	return s.inner.EndTransaction(ctx)
+}
+
+func (s *Storage) Close(ctx context.Context) error {
+	if err := s.inj.Inject(ctx, "storage.Close"); err != nil {
+		return err
+	}
+	return s.inner.Close(ctx)
+}
+
+func (s *Storage) Clear(ctx context.Context) error {
+	if err := s.inj.Inject(ctx, "storage.Clear"); err != nil {
+		return err
+	}
+	return s.inner.Clear(ctx)
+}
+
+func (s *Storage) Keys(ctx context.Context) ([]string, error) {
+	if err := s.inj.Inject(ctx, "storage.Keys"); err != nil {
+		return nil, err
+	}
+	return s.inner.Keys(ctx)
+}
+
+// transaction holds the writes of a transaction until it is committed, so that a commit can write only some of them.
+type transaction struct {
+	Storage
+	writes []func(ctx context.Context) error
+}
+
+func (t *transaction) Set(ctx context.Context, key string, value any) error {
+	return t.write(ctx, "storage.Set", func(ctx context.Context) error { return t.inner.Set(ctx, key, value) })
+}
+
+func (t *transaction) Incr(ctx context.Context, key string) error {
+	return t.write(ctx, "storage.Incr", func(ctx context.Context) error { return t.inner.Incr(ctx, key) })
+}
+
+func (t *transaction) Decr(ctx context.Context, key string) error {
+	return t.write(ctx, "storage.Decr", func(ctx context.Context) error { return t.inner.Decr(ctx, key) })
+}
+
+func (t *transaction) Delete(ctx context.Context, key string) error {
+	return t.write(ctx, "storage.Delete", func(ctx context.Context) error { return t.inner.Delete(ctx, key) })
+}
+
+func (t *transaction) DeleteFields(ctx context.Context, key string, fields ...string) error {
+	return t.write(ctx, "storage.DeleteFields", func(ctx context.Context) error {
+		return t.inner.DeleteFields(ctx, key, fields...)
+	})
+}
+
+func (t *transaction) AddMembers(ctx context.Context, key string, members ...string) error {
+	return t.write(ctx, "storage.AddMembers", func(ctx context.Context) error {
+		return t.inner.AddMembers(ctx, key, members...)
+	})
+}
+
+func (t *transaction) RemoveMembers(ctx context.Context, key string, members ...string) error {
+	return t.write(ctx, "storage.RemoveMembers", func(ctx context.Context) error {
+		return t.inner.RemoveMembers(ctx, key, members...)
+	})
+}
+
+func (t *transaction) write(ctx context.Context, op string, w func(ctx context.Context) error) error {
+	if err := t.inj.Inject(ctx, op); err != nil {
+		return err
+	}
+	t.writes = append(t.writes, w)
+	return nil
+}
+
+// EndTransaction commits the writes of the transaction. A commit that fails without a partial write writes nothing.
+func (t *transaction) EndTransaction(ctx context.Context) error {
+	f, ok := t.inj.next(OpCommit)
+	if !ok {
+		return t.commit(ctx, len(t.writes))
+	}
+	if err := f.wait(ctx); err != nil {
+		return err
+	}
+	if f.PartialWrite {
+		if err := t.commit(ctx, min(f.KeepWrites, len(t.writes))); err != nil {
+			return err
+		}
+		return f.err()
+	}
+	if err := f.err(); err != nil {
+		return err
+	}
+	return t.commit(ctx, len(t.writes))
+}
+
+func (t *transaction) commit(ctx context.Context, n int) error {
+	for _, w := range t.writes[:n] {
+		if err := w(ctx); err != nil {
+			return err
+		}
+	}
+	return t.inner.EndTransaction(ctx)
+}
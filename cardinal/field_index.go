@@ -0,0 +1,247 @@
+package cardinal
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"slices"
+	"strconv"
+
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal/search"
+	"pkg.world.dev/world-engine/cardinal/search/filter"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+	"pkg.world.dev/world-engine/cardinal/worldstage"
+)
+
+// fieldIndexPrefix is the prefix of the keys of field indexes in the index storage. Like the unique indexes, they are
+// committed with the rest of the tick. The entities that have a value are kept in a set, so adding an entity to a value
+// that many entities have doesn't rewrite all of them.
+const fieldIndexPrefix = "field-"
+
+// fieldIndexBuiltKey is set in the entries of an index once the entities that existed before the index was registered
+// have been added to it.
+const fieldIndexBuiltKey = "built"
+
+var ErrFieldNotIndexed = errors.New("component field is not indexed")
+
+// fieldIndex is a type-erased index registered with RegisterFieldIndex.
+type fieldIndex struct {
+	// id identifies the index in the keys of its entries.
+	id    string
+	field string
+	// value returns the JSON encoded value of the field of the given component value.
+	value func(comp any) ([]byte, error)
+	// each calls fn with every entity that has the component of the index.
+	each func(wCtx engine.Context, fn func(id types.EntityID, value any) error) error
+}
+
+// RegisterFieldIndex registers an index of the entities that have a component of type T by the value of one of its
+// fields, e.g. the ID field of a Guild component, so that the entities whose field has a given value can be found
+// without scanning all entities, with search.WhereEq. The field is the name of an exported field of T of a boolean,
+// numeric or string type. Like the unique indexes, the index is maintained by the engine on every write of the
+// component, and entities that already exist when the index is registered are added to it when the world starts.
+//
+// Index entries are committed with the rest of the tick, but they don't count towards the raw storage quota of the
+// game, and any number of entities can have the same value.
+//
+// Usage:
+//
+//	err := cardinal.RegisterFieldIndex[Guild](w, "ID")
+//	members, err := search.WhereEq(filter.Component[Guild](), "ID", guildID).Collect(wCtx)
+func RegisterFieldIndex[T types.Component](w *World, field string) error {
+	if w.worldStage.Current() != worldstage.Init {
+		return eris.Errorf(
+			"world state is %s, expected %s to register field indexes",
+			w.worldStage.Current(),
+			worldstage.Init,
+		)
+	}
+	var t T
+	c, err := w.GetComponentByName(t.Name())
+	if err != nil {
+		return eris.Wrapf(err, "field index of %q covers a component that is not registered", field)
+	}
+	typ := reflect.TypeOf(t)
+	if typ.Kind() != reflect.Struct {
+		return eris.Errorf("component %q must be a struct to index its fields", t.Name())
+	}
+	sf, ok := typ.FieldByName(field)
+	if !ok || !sf.IsExported() || len(sf.Index) != 1 {
+		return eris.Errorf("component %q has no exported field %q", t.Name(), field)
+	}
+	switch sf.Type.Kind() { //nolint:exhaustive // other kinds can't be indexed
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+	default:
+		return eris.Errorf("field %q of component %q has type %s, which can't be indexed", field, t.Name(), sf.Type)
+	}
+	for _, existing := range w.fieldIndexes[c.ID()] {
+		if existing.field == field {
+			return eris.Errorf("field %q of component %q is already indexed", field, t.Name())
+		}
+	}
+
+	w.fieldIndexes[c.ID()] = append(w.fieldIndexes[c.ID()], fieldIndex{
+		id:    fieldIndexID(c.Name(), field),
+		field: field,
+		value: func(value any) ([]byte, error) {
+			comp, err := componentValue[T](value)
+			if err != nil {
+				return nil, err
+			}
+			return encodeFieldValue(reflect.ValueOf(comp).Elem().Field(sf.Index[0]).Interface())
+		},
+		each: func(wCtx engine.Context, fn func(id types.EntityID, value any) error) error {
+			var errs []error
+			err := search.NewSearch().Entity(filter.Contains(filter.Component[T]())).
+				Each(wCtx, func(id types.EntityID) bool {
+					comp, err := GetComponent[T](wCtx, id)
+					if err == nil {
+						err = fn(id, *comp)
+					}
+					if err != nil {
+						errs = append(errs, err)
+						return false
+					}
+					return true
+				})
+			return errors.Join(append(errs, err)...)
+		},
+	})
+	return nil
+}
+
+// FindByField returns the entities, in ascending order, whose field of the given component is equal to value, using
+// the index registered with RegisterFieldIndex. ErrFieldNotIndexed is returned if the field is not indexed. It is the
+// same as wCtx.FindByField, and is used by search.WhereEq.
+func FindByField(
+	wCtx engine.Context, cType types.ComponentMetadata, field string, value any,
+) ([]types.EntityID, error) {
+	return wCtx.FindByField(cType, field, value)
+}
+
+func (w *World) findByField(
+	wCtx engine.Context, cType types.ComponentMetadata, field string, value any,
+) ([]types.EntityID, error) {
+	idx := slices.IndexFunc(w.fieldIndexes[cType.ID()], func(index fieldIndex) bool { return index.field == field })
+	if idx < 0 {
+		return nil, eris.Wrapf(ErrFieldNotIndexed, "field %q of component %q", field, cType.Name())
+	}
+	bz, err := encodeFieldValue(value)
+	if err != nil {
+		return nil, err
+	}
+	return newIndexStorage(wCtx, fieldIndexPrefix+w.fieldIndexes[cType.ID()][idx].id).entities(fieldValueKey(bz))
+}
+
+// indexFields updates the field indexes of the given component for the value that the component of the entity is about
+// to be set to, or removes the entity from them if value is nil.
+func (w *World) indexFields(wCtx engine.Context, cType types.ComponentMetadata, id types.EntityID, value any) error {
+	for _, index := range w.fieldIndexes[cType.ID()] {
+		var bz []byte
+		if value != nil {
+			var err error
+			if bz, err = index.value(value); err != nil {
+				return err
+			}
+		}
+		if err := setFieldIndexEntry(wCtx, index.id, id, bz); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setFieldIndexEntry moves the entity to the given value in the index. A nil value removes the entity from the index.
+func setFieldIndexEntry(wCtx engine.Context, index string, id types.EntityID, value []byte) error {
+	store := newIndexStorage(wCtx, fieldIndexPrefix+index)
+	prev, ok, err := store.get(fieldEntityKey(id))
+	if err != nil {
+		return err
+	}
+	if ok && string(prev) == string(value) {
+		return nil
+	}
+	if ok {
+		if err = store.removeEntity(fieldValueKey(prev), id); err != nil {
+			return err
+		}
+	}
+	if value == nil {
+		if !ok {
+			return nil
+		}
+		return store.delete(fieldEntityKey(id))
+	}
+	if err = store.addEntity(fieldValueKey(value), id); err != nil {
+		return err
+	}
+	return store.set(fieldEntityKey(id), value)
+}
+
+// buildFieldIndexes adds the entities that existed before an index was registered to it, in batches that are committed
+// on their own. An index whose build was interrupted is built again from the start.
+func (w *World) buildFieldIndexes(wCtx engine.Context) error {
+	for _, indexes := range w.fieldIndexes {
+		for _, index := range indexes {
+			store := newIndexStorage(wCtx, fieldIndexPrefix+index.id)
+			_, built, err := store.get(fieldIndexBuiltKey)
+			if err != nil {
+				return err
+			}
+			if built {
+				continue
+			}
+			err = buildIndex(wCtx, index.each, func(id types.EntityID, value any) error {
+				bz, err := index.value(value)
+				if err != nil {
+					return err
+				}
+				return setFieldIndexEntry(wCtx, index.id, id, bz)
+			})
+			if err != nil {
+				return eris.Wrapf(err, "failed to build index of field %q", index.field)
+			}
+			if err = store.set(fieldIndexBuiltKey, []byte{1}); err != nil {
+				return err
+			}
+			if err = wCtx.StoreManager().FlushIndexes(context.Background()); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// encodeFieldValue encodes a field value as JSON, so that equal values of different numeric types, e.g. a field of
+// type uint32 and the untyped constant of a search, have the same key.
+func encodeFieldValue(value any) ([]byte, error) {
+	bz, err := json.Marshal(value)
+	if err != nil {
+		return nil, eris.Wrapf(err, "failed to encode field value %v", value)
+	}
+	return bz, nil
+}
+
+// fieldIndexID is a short identifier of the index of the given field, which keeps its keys short.
+func fieldIndexID(component, field string) string {
+	sum := sha256.Sum256([]byte(component + "\x00" + field))
+	return hex.EncodeToString(sum[:8])
+}
+
+func fieldValueKey(value []byte) string {
+	sum := sha256.Sum256(value)
+	return "value-" + hex.EncodeToString(sum[:])
+}
+
+func fieldEntityKey(id types.EntityID) string {
+	return "entity-" + strconv.FormatUint(uint64(id), 10)
+}
@@ -0,0 +1,122 @@
+package cardinal_test
+
+import (
+	"testing"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/search"
+	"pkg.world.dev/world-engine/cardinal/search/filter"
+	"pkg.world.dev/world-engine/cardinal/testutils"
+	"pkg.world.dev/world-engine/cardinal/types"
+)
+
+type Guild struct {
+	ID   uint32
+	Rank string
+	Tags []string
+}
+
+func (Guild) Name() string {
+	return "guild"
+}
+
+func TestFieldIndexFindsEntitiesByFieldValue(t *testing.T) {
+	tf1 := testutils.NewTestFixture(t, nil)
+	assert.NilError(t, cardinal.RegisterComponent[Guild](tf1.World))
+	assert.NilError(t, cardinal.RegisterComponent[Health](tf1.World))
+	tf1.StartWorld()
+	wCtx := cardinal.NewWorldContext(tf1.World)
+	// Entities that exist before the index is registered are added to it when the world starts.
+	existing, err := cardinal.Create(wCtx, Guild{ID: 7})
+	assert.NilError(t, err)
+	tf1.DoTick()
+
+	tf1.Shutdown()
+	tf2 := testutils.NewTestFixture(t, tf1.Redis)
+	world := tf2.World
+	assert.NilError(t, cardinal.RegisterComponent[Guild](world))
+	assert.NilError(t, cardinal.RegisterComponent[Health](world))
+	assert.NilError(t, cardinal.RegisterFieldIndex[Guild](world, "ID"))
+	assert.ErrorContains(t, cardinal.RegisterFieldIndex[Guild](world, "ID"), "already indexed")
+	assert.ErrorContains(t, cardinal.RegisterFieldIndex[Guild](world, "Tags"), "can't be indexed")
+	assert.ErrorContains(t, cardinal.RegisterFieldIndex[Guild](world, "Missing"), "no exported field")
+	tf2.StartWorld()
+	wCtx = cardinal.NewWorldContext(world)
+
+	members, err := cardinal.CreateMany(wCtx, 3, Guild{ID: 7, Rank: "member"}, Health{})
+	assert.NilError(t, err)
+	other, err := cardinal.Create(wCtx, Guild{ID: 8})
+	assert.NilError(t, err)
+	guild7 := search.WhereEq(filter.Component[Guild](), "ID", 7)
+	ids, err := guild7.Collect(wCtx)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, append([]types.EntityID{existing}, members...), ids)
+
+	// The index follows every write of the component.
+	assert.NilError(t, cardinal.UpdateComponent[Guild](wCtx, members[0], func(g *Guild) *Guild {
+		g.ID = 8
+		return g
+	}))
+	assert.NilError(t, cardinal.RemoveComponentFrom[Guild](wCtx, members[1]))
+	assert.NilError(t, cardinal.Remove(wCtx, existing))
+	// Setting another field doesn't move the entity.
+	assert.NilError(t, cardinal.SetComponent[Guild](wCtx, members[2], &Guild{ID: 7, Rank: "officer"}))
+	tf2.DoTick()
+
+	readOnly := cardinal.NewReadOnlyWorldContext(world)
+	ids, err = guild7.Collect(readOnly)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []types.EntityID{members[2]}, ids)
+	ids, err = search.WhereEq(filter.Component[Guild](), "ID", uint64(8)).Collect(readOnly)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []types.EntityID{members[0], other}, ids)
+	ids, err = search.WhereEq(filter.Component[Guild](), "ID", 9).Collect(readOnly)
+	assert.NilError(t, err)
+	assert.Equal(t, 0, len(ids))
+
+	// Field searches can be combined with the other searches.
+	ids, err = search.And(
+		search.WhereEq(filter.Component[Guild](), "ID", 8),
+		cardinal.NewSearch().Entity(filter.Contains(filter.Component[Health]())),
+	).Collect(readOnly)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []types.EntityID{members[0]}, ids)
+	ids, err = search.Not(search.WhereEq(filter.Component[Guild](), "ID", 8)).Collect(readOnly)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []types.EntityID{members[1], members[2]}, ids)
+
+	_, err = search.WhereEq(filter.Component[Guild](), "Rank", "officer").Collect(readOnly)
+	assert.ErrorIs(t, err, cardinal.ErrFieldNotIndexed)
+}
+
+func TestFieldIndexCoversMoreEntitiesThanTheRawStorageQuota(t *testing.T) {
+	// More entities than raw storage writes are allowed in a tick, whose IDs don't fit in a single raw storage value.
+	const numEntities = 10_001
+	tf1 := testutils.NewTestFixture(t, nil)
+	assert.NilError(t, cardinal.RegisterComponent[Guild](tf1.World))
+	tf1.StartWorld()
+	_, err := cardinal.CreateMany(cardinal.NewWorldContext(tf1.World), numEntities, Guild{ID: 7})
+	assert.NilError(t, err)
+	tf1.DoTick()
+
+	// The entities that already exist are added to the index in batches when the world starts.
+	tf1.Shutdown()
+	tf2 := testutils.NewTestFixture(t, tf1.Redis)
+	assert.NilError(t, cardinal.RegisterComponent[Guild](tf2.World))
+	assert.NilError(t, cardinal.RegisterFieldIndex[Guild](tf2.World, "ID"))
+	tf2.StartWorld()
+	wCtx := cardinal.NewWorldContext(tf2.World)
+	guild7 := search.WhereEq(filter.Component[Guild](), "ID", 7)
+	ids, err := guild7.Collect(wCtx)
+	assert.NilError(t, err)
+	assert.Equal(t, numEntities, len(ids))
+
+	// Indexing as many new entities in a single tick doesn't exhaust the raw storage quota either.
+	_, err = cardinal.CreateMany(wCtx, numEntities, Guild{ID: 7})
+	assert.NilError(t, err)
+	tf2.DoTick()
+	ids, err = guild7.Collect(cardinal.NewReadOnlyWorldContext(tf2.World))
+	assert.NilError(t, err)
+	assert.Equal(t, 2*numEntities, len(ids))
+}
@@ -0,0 +1,42 @@
+// Package gamelib is a library of optional building blocks that most games need, built on the public Cardinal API.
+//
+// The inventory plugin (see NewInventoryPlugin) keeps stacks of items in the inventories of entities, e.g. players or
+// chests, and moves items between inventories atomically: a move either completes or changes nothing, so items can't
+// be duplicated or lost when an inventory is full or a transfer fails halfway. Players move items out of the
+// inventories of the entities their persona owns (see cardinal.ClaimEntity) with the transfer-items message; other
+// inventories can only be changed by the game's systems.
+package gamelib
+
+// ItemStack is a quantity of a single item. Stacks of the same item in an inventory are merged up to the maximum stack
+// size of the item. It is also a component, for stacks that are entities of their own, e.g. loot on the ground.
+type ItemStack struct {
+	Item     string `json:"item"`
+	Quantity uint64 `json:"quantity"`
+}
+
+func (ItemStack) Name() string {
+	return "ItemStack"
+}
+
+// Inventory holds stacks of items.
+type Inventory struct {
+	// Capacity is the maximum number of stacks in the inventory. An inventory with a capacity of 0 holds any number of
+	// stacks.
+	Capacity int         `json:"capacity"`
+	Stacks   []ItemStack `json:"stacks"`
+}
+
+func (Inventory) Name() string {
+	return "Inventory"
+}
+
+// Count returns the total quantity of the item in the inventory.
+func (inv Inventory) Count(item string) uint64 {
+	var count uint64
+	for _, stack := range inv.Stacks {
+		if stack.Item == item {
+			count += stack.Quantity
+		}
+	}
+	return count
+}
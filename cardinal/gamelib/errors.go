@@ -0,0 +1,13 @@
+package gamelib
+
+import (
+	"errors"
+)
+
+var (
+	ErrNoInventory       = errors.New("entity has no inventory")
+	ErrInventoryFull     = errors.New("inventory is full")
+	ErrInsufficientItems = errors.New("insufficient items")
+	ErrNotOwner          = errors.New("entity is not owned by the persona")
+	ErrInvalidTransfer   = errors.New("invalid transfer")
+)
@@ -0,0 +1,217 @@
+package gamelib
+
+import (
+	"errors"
+	"math"
+
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/message"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+const inventoryGroup = "inventory"
+
+var _ cardinal.Plugin = (*InventoryPlugin)(nil)
+
+// InventoryPlugin is the inventory plugin. See NewInventoryPlugin.
+type InventoryPlugin struct {
+	maxStackSizes map[string]uint64
+}
+
+// InventoryOption configures the inventory plugin.
+type InventoryOption func(*InventoryPlugin)
+
+// WithMaxStackSize sets the maximum quantity of the item in a single stack. Items without a maximum stack size are
+// kept in a single stack of any size.
+func WithMaxStackSize(item string, size uint64) InventoryOption {
+	return func(p *InventoryPlugin) {
+		p.maxStackSizes[item] = size
+	}
+}
+
+// NewInventoryPlugin returns the inventory plugin, which registers the Inventory and ItemStack components and the
+// inventory transfer-items message. Systems change inventories with the AddItems, RemoveItems and
+// TransferItems methods of the plugin. Every change is validated completely before an inventory is changed, so a
+// change that fails, e.g. because the target inventory is full, leaves both inventories as they were. Register it with
+// World.RegisterPlugin before starting the game.
+func NewInventoryPlugin(opts ...InventoryOption) *InventoryPlugin {
+	p := &InventoryPlugin{maxStackSizes: map[string]uint64{}}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+func (p *InventoryPlugin) Register(world *cardinal.World) error {
+	return errors.Join(
+		cardinal.RegisterComponent[Inventory](world),
+		cardinal.RegisterComponent[ItemStack](world),
+		cardinal.RegisterMessage[TransferItems, TransferItemsResult](
+			world,
+			TransferItemsMessageName,
+			message.WithCustomMessageGroup[TransferItems, TransferItemsResult](inventoryGroup)),
+		cardinal.RegisterSystems(world, p.transferItemsSystem),
+	)
+}
+
+// AddItems adds a quantity of the item to the inventory of the entity. Existing stacks of the item are filled up
+// before new stacks are added. It fails with ErrInventoryFull if the items don't fit.
+func (p *InventoryPlugin) AddItems(wCtx engine.Context, id types.EntityID, item string, quantity uint64) error {
+	inv, err := getInventory(wCtx, id)
+	if err != nil {
+		return err
+	}
+	if err = p.add(inv, item, quantity); err != nil {
+		return eris.Wrapf(err, "entity %d", id)
+	}
+	return cardinal.SetComponent[Inventory](wCtx, id, inv)
+}
+
+// RemoveItems removes a quantity of the item from the inventory of the entity. It fails with ErrInsufficientItems if
+// the inventory holds less than quantity of the item.
+func (p *InventoryPlugin) RemoveItems(wCtx engine.Context, id types.EntityID, item string, quantity uint64) error {
+	inv, err := getInventory(wCtx, id)
+	if err != nil {
+		return err
+	}
+	if err = remove(inv, item, quantity); err != nil {
+		return eris.Wrapf(err, "entity %d", id)
+	}
+	return cardinal.SetComponent[Inventory](wCtx, id, inv)
+}
+
+// TransferItems moves a quantity of the item from the inventory of one entity to the inventory of another. Either all
+// items are moved or none are.
+func (p *InventoryPlugin) TransferItems(
+	wCtx engine.Context, from, to types.EntityID, item string, quantity uint64,
+) error {
+	if from == to {
+		return eris.Wrap(ErrInvalidTransfer, "cannot transfer items to the same entity")
+	}
+	if item == "" || quantity == 0 {
+		return eris.Wrap(ErrInvalidTransfer, "item and quantity must not be empty")
+	}
+	fromInv, err := getInventory(wCtx, from)
+	if err != nil {
+		return err
+	}
+	toInv, err := getInventory(wCtx, to)
+	if err != nil {
+		return err
+	}
+	if err = remove(fromInv, item, quantity); err != nil {
+		return eris.Wrapf(err, "entity %d", from)
+	}
+	if err = p.add(toInv, item, quantity); err != nil {
+		return eris.Wrapf(err, "entity %d", to)
+	}
+	if err = cardinal.SetComponent[Inventory](wCtx, from, fromInv); err != nil {
+		return err
+	}
+	return cardinal.SetComponent[Inventory](wCtx, to, toInv)
+}
+
+// CountItems returns the total quantity of the item in the inventory of the entity.
+func (p *InventoryPlugin) CountItems(wCtx engine.Context, id types.EntityID, item string) (uint64, error) {
+	inv, err := getInventory(wCtx, id)
+	if err != nil {
+		return 0, err
+	}
+	return inv.Count(item), nil
+}
+
+// transferItemsSystem moves items out of inventories on behalf of the personas that own them (see
+// cardinal.ClaimEntity).
+func (p *InventoryPlugin) transferItemsSystem(wCtx engine.Context) error {
+	return cardinal.EachMessage[TransferItems, TransferItemsResult](
+		wCtx,
+		func(txData message.TxData[TransferItems]) (result TransferItemsResult, err error) {
+			txMsg := txData.Msg
+			owns, err := cardinal.OwnsEntity(wCtx, txData.Tx.PersonaTag, txMsg.From)
+			if err != nil {
+				return result, err
+			}
+			if !owns {
+				return result, eris.Wrapf(ErrNotOwner, "%s does not own entity %d", txData.Tx.PersonaTag, txMsg.From)
+			}
+			if err = p.TransferItems(wCtx, txMsg.From, txMsg.To, txMsg.Item, txMsg.Quantity); err != nil {
+				return result, err
+			}
+			if result.FromCount, err = p.CountItems(wCtx, txMsg.From, txMsg.Item); err != nil {
+				return result, err
+			}
+			result.ToCount, err = p.CountItems(wCtx, txMsg.To, txMsg.Item)
+			return result, err
+		},
+	)
+}
+
+// add adds a quantity of the item to the inventory. The inventory is only changed if all items fit.
+func (p *InventoryPlugin) add(inv *Inventory, item string, quantity uint64) error {
+	if quantity == 0 {
+		return nil
+	}
+	maxStack, ok := p.maxStackSizes[item]
+	if !ok || maxStack == 0 {
+		maxStack = math.MaxUint64
+	}
+	stacks := make([]ItemStack, len(inv.Stacks), len(inv.Stacks)+1)
+	copy(stacks, inv.Stacks)
+	for i := range stacks {
+		if quantity == 0 {
+			break
+		}
+		if stacks[i].Item != item || stacks[i].Quantity >= maxStack {
+			continue
+		}
+		n := min(quantity, maxStack-stacks[i].Quantity)
+		stacks[i].Quantity += n
+		quantity -= n
+	}
+	for quantity > 0 {
+		if inv.Capacity > 0 && len(stacks) >= inv.Capacity {
+			return eris.Wrapf(ErrInventoryFull, "no room for %d %s", quantity, item)
+		}
+		n := min(quantity, maxStack)
+		stacks = append(stacks, ItemStack{Item: item, Quantity: n})
+		quantity -= n
+	}
+	inv.Stacks = stacks
+	return nil
+}
+
+// remove removes a quantity of the item from the inventory, emptying the last stacks of the item first. The inventory
+// is only changed if it holds enough of the item.
+func remove(inv *Inventory, item string, quantity uint64) error {
+	if count := inv.Count(item); count < quantity {
+		return eris.Wrapf(ErrInsufficientItems, "has %d %s, need %d", count, item, quantity)
+	}
+	stacks := make([]ItemStack, len(inv.Stacks))
+	copy(stacks, inv.Stacks)
+	for i := len(stacks) - 1; i >= 0 && quantity > 0; i-- {
+		if stacks[i].Item != item {
+			continue
+		}
+		n := min(quantity, stacks[i].Quantity)
+		stacks[i].Quantity -= n
+		quantity -= n
+	}
+	inv.Stacks = stacks[:0]
+	for _, stack := range stacks {
+		if stack.Quantity > 0 {
+			inv.Stacks = append(inv.Stacks, stack)
+		}
+	}
+	return nil
+}
+
+func getInventory(wCtx engine.Context, id types.EntityID) (*Inventory, error) {
+	inv, err := cardinal.GetComponent[Inventory](wCtx, id)
+	if errors.Is(err, cardinal.ErrComponentNotOnEntity) {
+		return nil, eris.Wrapf(ErrNoInventory, "entity %d", id)
+	}
+	return inv, err
+}
@@ -0,0 +1,107 @@
+package gamelib_test
+
+import (
+	"testing"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/gamelib"
+	"pkg.world.dev/world-engine/cardinal/testutils"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+func assertStacks(t *testing.T, wCtx engine.Context, id types.EntityID, want ...gamelib.ItemStack) {
+	inv, err := cardinal.GetComponent[gamelib.Inventory](wCtx, id)
+	assert.NilError(t, err)
+	if len(want) == 0 {
+		assert.Len(t, inv.Stacks, 0)
+		return
+	}
+	assert.DeepEqual(t, want, inv.Stacks)
+}
+
+func TestInventoryStacksItems(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil)
+	inv := gamelib.NewInventoryPlugin(gamelib.WithMaxStackSize("arrow", 10))
+	tf.World.RegisterPlugin(inv)
+	tf.StartWorld()
+	wCtx := cardinal.NewWorldContext(tf.World)
+	bag, err := cardinal.Create(wCtx, gamelib.Inventory{Capacity: 3})
+	assert.NilError(t, err)
+	assert.NilError(t, inv.AddItems(wCtx, bag, "arrow", 15))
+	// Arrows stack up to 10.
+	assertStacks(t, wCtx, bag,
+		gamelib.ItemStack{Item: "arrow", Quantity: 10},
+		gamelib.ItemStack{Item: "arrow", Quantity: 5})
+
+	// Items without a maximum stack size go into a single stack.
+	assert.NilError(t, inv.AddItems(wCtx, bag, "gold", 1000))
+	// The partial stack of arrows is filled up first, and the bag is full, so 16 arrows don't fit.
+	assert.ErrorIs(t, inv.AddItems(wCtx, bag, "arrow", 16), gamelib.ErrInventoryFull)
+	assert.NilError(t, inv.AddItems(wCtx, bag, "arrow", 5))
+	assertStacks(t, wCtx, bag,
+		gamelib.ItemStack{Item: "arrow", Quantity: 10},
+		gamelib.ItemStack{Item: "arrow", Quantity: 10},
+		gamelib.ItemStack{Item: "gold", Quantity: 1000})
+
+	assert.ErrorIs(t, inv.RemoveItems(wCtx, bag, "arrow", 21), gamelib.ErrInsufficientItems)
+	assert.NilError(t, inv.RemoveItems(wCtx, bag, "arrow", 12))
+	assertStacks(t, wCtx, bag,
+		gamelib.ItemStack{Item: "arrow", Quantity: 8},
+		gamelib.ItemStack{Item: "gold", Quantity: 1000})
+	count, err := inv.CountItems(wCtx, bag, "arrow")
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(8), count)
+}
+
+func TestTransferItemsIsAtomic(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil)
+	inv := gamelib.NewInventoryPlugin(gamelib.WithMaxStackSize("arrow", 10))
+	tf.World.RegisterPlugin(inv)
+	tf.StartWorld()
+	tf.CreatePersona("alice", "alice-address")
+	tf.CreatePersona("bob", "bob-address")
+	transfer := "inventory." + gamelib.TransferItemsMessageName
+
+	// alice owns a bag holding 15 arrows, and nobody owns the chest.
+	wCtx := cardinal.NewWorldContext(tf.World)
+	bag, err := cardinal.CreateForPersona(wCtx, "alice", gamelib.Inventory{Capacity: 3})
+	assert.NilError(t, err)
+	chest, err := cardinal.Create(wCtx, gamelib.Inventory{Capacity: 2})
+	assert.NilError(t, err)
+	assert.NilError(t, inv.AddItems(wCtx, bag, "arrow", 15))
+	tf.DoTick()
+
+	rec := tf.SendTransaction(transfer, gamelib.TransferItems{From: bag, To: chest, Item: "arrow", Quantity: 12}, "alice")
+	assert.Len(t, rec.Errs, 0)
+	result, ok := rec.Result.(gamelib.TransferItemsResult)
+	assert.Assert(t, ok)
+	assert.Equal(t, gamelib.TransferItemsResult{FromCount: 3, ToCount: 12}, result)
+	assertStacks(t, wCtx, bag, gamelib.ItemStack{Item: "arrow", Quantity: 3})
+	assertStacks(t, wCtx, chest,
+		gamelib.ItemStack{Item: "arrow", Quantity: 10},
+		gamelib.ItemStack{Item: "arrow", Quantity: 2})
+
+	// Only the owner of the source inventory can move items out of it.
+	rec = tf.SendTransaction(transfer, gamelib.TransferItems{From: bag, To: chest, Item: "arrow", Quantity: 1}, "bob")
+	assert.ErrorIs(t, rec.Errs[0], gamelib.ErrNotOwner)
+	rec = tf.SendTransaction(transfer, gamelib.TransferItems{From: chest, To: bag, Item: "arrow", Quantity: 1}, "alice")
+	assert.ErrorIs(t, rec.Errs[0], gamelib.ErrNotOwner)
+
+	// The chest is full, so nothing is taken out of the bag.
+	assert.NilError(t, inv.RemoveItems(wCtx, chest, "arrow", 2))
+	assert.NilError(t, inv.AddItems(wCtx, chest, "gold", 5))
+	tf.DoTick()
+	rec = tf.SendTransaction(transfer, gamelib.TransferItems{From: bag, To: chest, Item: "arrow", Quantity: 3}, "alice")
+	assert.ErrorIs(t, rec.Errs[0], gamelib.ErrInventoryFull)
+	assertStacks(t, wCtx, bag, gamelib.ItemStack{Item: "arrow", Quantity: 3})
+	assertStacks(t, wCtx, chest,
+		gamelib.ItemStack{Item: "arrow", Quantity: 10},
+		gamelib.ItemStack{Item: "gold", Quantity: 5})
+
+	// Moving all items empties the stacks.
+	assert.NilError(t, inv.TransferItems(wCtx, chest, bag, "gold", 5))
+	assertStacks(t, wCtx, chest, gamelib.ItemStack{Item: "arrow", Quantity: 10})
+	assert.ErrorIs(t, inv.TransferItems(wCtx, bag, bag, "gold", 1), gamelib.ErrInvalidTransfer)
+}
@@ -0,0 +1,22 @@
+package gamelib
+
+import (
+	"pkg.world.dev/world-engine/cardinal/types"
+)
+
+const TransferItemsMessageName = "transfer-items"
+
+// TransferItems moves a quantity of an item from one inventory to another. It must be signed by the owner of the
+// From entity.
+type TransferItems struct {
+	From     types.EntityID `json:"from"`
+	To       types.EntityID `json:"to"`
+	Item     string         `json:"item"`
+	Quantity uint64         `json:"quantity"`
+}
+
+type TransferItemsResult struct {
+	// FromCount and ToCount are the quantities of the item in both inventories after the transfer.
+	FromCount uint64 `json:"fromCount"`
+	ToCount   uint64 `json:"toCount"`
+}
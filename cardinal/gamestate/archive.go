@@ -0,0 +1,460 @@
+package gamestate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strconv"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal/codec"
+	"pkg.world.dev/world-engine/cardinal/iterators"
+	"pkg.world.dev/world-engine/cardinal/types"
+)
+
+// archivedArchetypeID is the origin archetype of an entity that was rehydrated from the archive during the current
+// tick. It makes sure the archetype of the entity is written back to the DB when the tick is committed.
+const archivedArchetypeID = types.ArchetypeID(-2)
+
+// lastAccessIntervalDivisor sets how often the last access of an entity that keeps being accessed is written: once
+// every inactiveTicks/lastAccessIntervalDivisor ticks, instead of on every tick.
+const lastAccessIntervalDivisor = 16
+
+// archivedEntity is an entity that was evicted from its archetype. The whole entity is stored under a single key, so
+// that it does not take up space in the active entity lists.
+type archivedEntity struct {
+	ArchID     types.ArchetypeID
+	Components map[types.ComponentID]json.RawMessage
+}
+
+// TrackEntityAccess makes the manager record the entities that are accessed during each tick, so that
+// ArchiveInactiveEntities can tell which entities are inactive. It must be called before the first tick runs, so that
+// the accesses of every tick are recorded the same way when the tick is replayed.
+func (m *EntityCommandBuffer) TrackEntityAccess() {
+	m.trackAccess = true
+}
+
+// ArchiveInactiveEntities moves every entity that has not been accessed for the given number of ticks into the
+// archive. Archived entities are rehydrated the first time they are accessed by ID, or when their archetype is
+// searched. The archival is committed with the rest of the tick's state changes and returns the number of archived
+// entities.
+//
+// The tick at which each entity was last accessed is committed with the ticks, so archival gives the same result when
+// ticks are replayed or run by another instance. To save writes, the last access of an entity is only updated when
+// the stored one is inactiveTicks/16 ticks old, and entities are archived once that much later. Entities that existed
+// before the first call are considered accessed at that time.
+func (m *EntityCommandBuffer) ArchiveInactiveEntities(inactiveTicks uint64) (int, error) {
+	m.TrackEntityAccess()
+	if err := m.loadLastAccess(); err != nil {
+		return 0, err
+	}
+	interval := max(1, inactiveTicks/lastAccessIntervalDivisor)
+	for id := range m.touchedEntities {
+		if last, ok := m.lastAccess[id]; ok && m.accessClock-last < interval {
+			continue
+		}
+		if _, err := m.entityIDToArchID.Get(id); err != nil {
+			if _, err = m.entityIDToOriginArchID.Get(id); err == nil {
+				// The entity was removed.
+				continue
+			}
+		}
+		m.pendingLastAccess[id] = m.accessClock
+	}
+
+	var inactive []types.EntityID
+	isInactive := func(id types.EntityID, last uint64) bool {
+		if pending, ok := m.pendingLastAccess[id]; ok {
+			last = pending
+		}
+		return m.accessClock-last >= inactiveTicks+interval-1
+	}
+	for id, last := range m.lastAccess {
+		if isInactive(id, last) {
+			inactive = append(inactive, id)
+		}
+	}
+	for id := range m.pendingLastAccess {
+		if _, ok := m.lastAccess[id]; !ok && isInactive(id, m.accessClock) {
+			inactive = append(inactive, id)
+		}
+	}
+	// Archiving reorders the active entity lists, so it must happen in the same order every time the tick is replayed.
+	slices.Sort(inactive)
+	count := 0
+	for _, id := range inactive {
+		err := m.archiveEntity(id)
+		delete(m.pendingLastAccess, id)
+		m.lastAccessToDelete[id] = true
+		if errors.Is(err, redis.Nil) {
+			// The entity was removed.
+			continue
+		} else if err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+// IsEntityArchived reports whether the given entity is currently in the archive.
+func (m *EntityCommandBuffer) IsEntityArchived(id types.EntityID) (bool, error) {
+	if _, err := m.entityIDToArchID.Get(id); err == nil {
+		return false, nil
+	}
+	if _, ok := m.pendingArchive[id]; ok {
+		return true, nil
+	}
+	_, err := m.dbStorage.GetBytes(m.ctx(), storageArchivedEntityKey(id))
+	if errors.Is(err, redis.Nil) {
+		return false, nil
+	} else if err != nil {
+		return false, eris.Wrap(err, "")
+	}
+	_, rehydrated := m.pendingRehydrate[id]
+	return !rehydrated, nil
+}
+
+// loadLastAccess loads the committed last access of every entity in the active entity lists, unless it is already
+// loaded. Entities without one were never accessed since archival started.
+func (m *EntityCommandBuffer) loadLastAccess() error {
+	if m.lastAccess != nil {
+		return nil
+	}
+	ctx := m.ctx()
+	clock, err := m.dbStorage.GetUInt64(ctx, storageEndTickKey())
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return eris.Wrap(err, "")
+	}
+	start, err := m.dbStorage.GetUInt64(ctx, storageLastAccessStartKey())
+	if errors.Is(err, redis.Nil) {
+		start = clock
+		m.isAccessStartPending = true
+	} else if err != nil {
+		return eris.Wrap(err, "")
+	}
+
+	lastAccess := map[types.EntityID]uint64{}
+	for i := 0; i < m.archIDToComps.Len(); i++ {
+		active, err := m.getActiveEntities(types.ArchetypeID(i))
+		if err != nil {
+			return err
+		}
+		for len(active.ids) > 0 {
+			batch := active.ids[:min(len(active.ids), maxKeysPerRead)]
+			active.ids = active.ids[len(batch):]
+			keys := make([]string, len(batch))
+			for j, id := range batch {
+				keys[j] = storageLastAccessKey(id)
+			}
+			bzs, err := m.dbStorage.GetManyBytes(ctx, keys)
+			if err != nil {
+				return err
+			}
+			for j, bz := range bzs {
+				last := start
+				if bz != nil {
+					if last, err = strconv.ParseUint(string(bz), 10, 64); err != nil {
+						return eris.Wrapf(err, "invalid last access of entity %d", batch[j])
+					}
+				}
+				lastAccess[batch[j]] = last
+			}
+		}
+	}
+	m.lastAccess = lastAccess
+	m.accessClock = clock
+	return nil
+}
+
+// touchEntity records that the given entity was accessed during the current tick.
+func (m *EntityCommandBuffer) touchEntity(id types.EntityID) {
+	if m.trackAccess {
+		m.touchedEntities[id] = true
+	}
+}
+
+// getArchivedEntities returns the archived entities of the archetype, including the changes of the current tick.
+func (m *EntityCommandBuffer) getArchivedEntities(archID types.ArchetypeID) (activeEntities, error) {
+	archived, err := m.archivedEntities.Get(archID)
+	if err == nil {
+		return archived, nil
+	}
+	bz, err := m.dbStorage.GetBytes(m.ctx(), storageArchivedEntityIDsKey(archID))
+	var ids []types.EntityID
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			return activeEntities{}, eris.Wrap(err, "")
+		}
+	} else if ids, err = codec.Decode[[]types.EntityID](bz); err != nil {
+		return activeEntities{}, err
+	}
+	archived = activeEntities{ids: ids}
+	if err = m.archivedEntities.Set(archID, archived); err != nil {
+		return activeEntities{}, err
+	}
+	return archived, nil
+}
+
+// setArchivedEntities sets the archived entities of the archetype and marks them as modified, so that they are
+// committed with the tick.
+func (m *EntityCommandBuffer) setArchivedEntities(archID types.ArchetypeID, archived activeEntities) error {
+	archived.modified = true
+	return m.archivedEntities.Set(archID, archived)
+}
+
+// rehydrateArchetype moves the archived entities of the archetype back into it, so that searches over the archetype
+// return them.
+func (m *EntityCommandBuffer) rehydrateArchetype(archID types.ArchetypeID) error {
+	archived, err := m.getArchivedEntities(archID)
+	if err != nil {
+		return err
+	}
+	for _, id := range slices.Clone(archived.ids) {
+		if _, err := m.rehydrateEntity(id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// archiveEntity evicts the given entity from its archetype and buffers its archived form.
+func (m *EntityCommandBuffer) archiveEntity(id types.EntityID) error {
+	archID, err := m.getArchetypeForEntity(id)
+	if err != nil {
+		return err
+	}
+	comps, err := m.GetComponentTypesForArchID(archID)
+	if err != nil {
+		return err
+	}
+	archived := archivedEntity{ArchID: archID, Components: make(map[types.ComponentID]json.RawMessage, len(comps))}
+	for _, comp := range comps {
+		bz, err := m.GetComponentForEntityInRawJSON(comp, id)
+		if err != nil {
+			return err
+		}
+		archived.Components[comp.ID()] = bz
+	}
+	bz, err := codec.Encode(archived)
+	if err != nil {
+		return err
+	}
+	if bz, err = m.cipher.Seal(bz, storageArchivedEntityKey(id)); err != nil {
+		return err
+	}
+
+	active, err := m.getActiveEntities(archID)
+	if err != nil {
+		return err
+	}
+	if err = active.swapRemove(id); err != nil {
+		return err
+	}
+	if err = m.setActiveEntities(archID, active); err != nil {
+		return err
+	}
+	archivedIDs, err := m.getArchivedEntities(archID)
+	if err != nil {
+		return err
+	}
+	archivedIDs.ids = append(archivedIDs.ids, id)
+	if err = m.setArchivedEntities(archID, archivedIDs); err != nil {
+		return err
+	}
+	if _, err := m.entityIDToOriginArchID.Get(id); err != nil {
+		if err = m.entityIDToOriginArchID.Set(id, archID); err != nil {
+			return err
+		}
+	}
+	if err = m.entityIDToArchID.Delete(id); err != nil {
+		return err
+	}
+	for _, comp := range comps {
+		key := compKey{comp.ID(), id}
+		if err = m.compValues.Delete(key); err != nil {
+			return err
+		}
+		if err = m.compValuesToDelete.Set(key, true); err != nil {
+			return err
+		}
+	}
+	delete(m.pendingRehydrate, id)
+	m.pendingArchive[id] = bz
+	return nil
+}
+
+// rehydrateEntity moves the given entity from the archive back into its archetype. A redis.Nil error is returned if
+// the entity is not archived.
+func (m *EntityCommandBuffer) rehydrateEntity(id types.EntityID) (types.ArchetypeID, error) {
+	bz, ok := m.pendingArchive[id]
+	if !ok {
+		var err error
+		bz, err = m.dbStorage.GetBytes(m.ctx(), storageArchivedEntityKey(id))
+		if err != nil {
+			return 0, err
+		}
+	}
+	archived, err := decodeArchivedEntity(m.cipher, id, bz)
+	if err != nil {
+		return 0, err
+	}
+	for typeID, raw := range archived.Components {
+		cType, err := m.typeToComponent.Get(typeID)
+		if err != nil {
+			return 0, eris.Wrap(iterators.ErrComponentMismatchWithSavedState, "")
+		}
+		value, err := cType.Decode(raw)
+		if err != nil {
+			return 0, err
+		}
+		if err = m.compValues.Set(compKey{typeID, id}, value); err != nil {
+			return 0, err
+		}
+	}
+
+	archivedIDs, err := m.getArchivedEntities(archived.ArchID)
+	if err != nil {
+		return 0, err
+	}
+	if err = archivedIDs.swapRemove(id); err != nil {
+		return 0, err
+	}
+	if err = m.setArchivedEntities(archived.ArchID, archivedIDs); err != nil {
+		return 0, err
+	}
+	active, err := m.getActiveEntities(archived.ArchID)
+	if err != nil {
+		return 0, err
+	}
+	active.ids = append(active.ids, id)
+	if err = m.setActiveEntities(archived.ArchID, active); err != nil {
+		return 0, err
+	}
+	if _, err := m.entityIDToOriginArchID.Get(id); err != nil {
+		if err = m.entityIDToOriginArchID.Set(id, archivedArchetypeID); err != nil {
+			return 0, err
+		}
+	}
+	if err = m.entityIDToArchID.Set(id, archived.ArchID); err != nil {
+		return 0, err
+	}
+	delete(m.pendingArchive, id)
+	m.pendingRehydrate[id] = true
+	m.touchEntity(id)
+	return archived.ArchID, nil
+}
+
+// addArchiveChangesToPipe adds the entities that were archived or rehydrated during the tick, and the last accesses
+// that were recorded, to the redis pipe.
+func (m *EntityCommandBuffer) addArchiveChangesToPipe(ctx context.Context, pipe PrimitiveStorage[string]) error {
+	for id := range m.pendingRehydrate {
+		if err := pipe.Delete(ctx, storageArchivedEntityKey(id)); err != nil {
+			return eris.Wrap(err, "")
+		}
+	}
+	for id, bz := range m.pendingArchive {
+		if err := pipe.Set(ctx, storageArchivedEntityKey(id), bz); err != nil {
+			return eris.Wrap(err, "")
+		}
+	}
+	archIDs, err := m.archivedEntities.Keys()
+	if err != nil {
+		return err
+	}
+	for _, archID := range archIDs {
+		archived, err := m.archivedEntities.Get(archID)
+		if err != nil {
+			return err
+		}
+		if !archived.modified {
+			continue
+		}
+		key := storageArchivedEntityIDsKey(archID)
+		if len(archived.ids) == 0 {
+			err = pipe.Delete(ctx, key)
+		} else {
+			var bz []byte
+			if bz, err = codec.Encode(archived.ids); err != nil {
+				return err
+			}
+			err = pipe.Set(ctx, key, bz)
+		}
+		if err != nil {
+			return eris.Wrap(err, "")
+		}
+	}
+
+	if m.isAccessStartPending {
+		if err := pipe.Set(ctx, storageLastAccessStartKey(), m.accessClock); err != nil {
+			return eris.Wrap(err, "")
+		}
+	}
+	for id, last := range m.pendingLastAccess {
+		if err := pipe.Set(ctx, storageLastAccessKey(id), last); err != nil {
+			return eris.Wrap(err, "")
+		}
+	}
+	for id := range m.lastAccessToDelete {
+		if err := pipe.Delete(ctx, storageLastAccessKey(id)); err != nil {
+			return eris.Wrap(err, "")
+		}
+	}
+	return nil
+}
+
+// commitLastAccess applies the last accesses that were committed with the tick to the loaded ones.
+func (m *EntityCommandBuffer) commitLastAccess() {
+	if m.lastAccess == nil {
+		return
+	}
+	for id, last := range m.pendingLastAccess {
+		m.lastAccess[id] = last
+	}
+	for id := range m.lastAccessToDelete {
+		delete(m.lastAccess, id)
+	}
+	clear(m.pendingLastAccess)
+	clear(m.lastAccessToDelete)
+	m.isAccessStartPending = false
+}
+
+// discardPendingArchiveChanges forgets the entities that were archived or rehydrated during the tick, and the
+// accesses that were recorded. The archetype and component changes that came with them are discarded with the rest of
+// the pending changes.
+func (m *EntityCommandBuffer) discardPendingArchiveChanges() error {
+	clear(m.pendingArchive)
+	clear(m.pendingRehydrate)
+	clear(m.touchedEntities)
+	clear(m.pendingLastAccess)
+	clear(m.lastAccessToDelete)
+	if m.isAccessStartPending {
+		// The loaded last accesses assume that archival started at this tick, so they must be loaded again.
+		m.lastAccess = nil
+		m.isAccessStartPending = false
+	}
+	return m.archivedEntities.Clear()
+}
+
+// getArchivedEntity reads an archived entity from storage. A redis.Nil error is returned if it is not archived.
+func getArchivedEntity(
+	ctx context.Context, storage PrimitiveStorage[string], cipher *codec.Cipher, id types.EntityID,
+) (archivedEntity, error) {
+	bz, err := storage.GetBytes(ctx, storageArchivedEntityKey(id))
+	if err != nil {
+		return archivedEntity{}, err
+	}
+	return decodeArchivedEntity(cipher, id, bz)
+}
+
+// decodeArchivedEntity decrypts and decodes the stored archived entity with the given ID.
+func decodeArchivedEntity(cipher *codec.Cipher, id types.EntityID, bz []byte) (archivedEntity, error) {
+	bz, err := cipher.Open(bz, storageArchivedEntityKey(id))
+	if err != nil {
+		return archivedEntity{}, err
+	}
+	return codec.Decode[archivedEntity](bz)
+}
@@ -0,0 +1,146 @@
+package gamestate_test
+
+import (
+	"context"
+	"testing"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal/types"
+)
+
+func TestInactiveEntityIsArchivedAndRehydrated(t *testing.T) {
+	ctx := context.Background()
+	manager, client := newCmdBufferAndRedisClientForTest(t, nil)
+	ids, err := manager.CreateManyEntities(2, fooComp)
+	assert.NilError(t, err)
+	active, dormant := ids[0], ids[1]
+	assert.NilError(t, manager.SetComponentForEntity(fooComp, active, Foo{Value: 1}))
+	assert.NilError(t, manager.SetComponentForEntity(fooComp, dormant, Foo{Value: 2}))
+	assert.NilError(t, manager.FinalizeTick(ctx))
+	archID, err := manager.GetArchIDForComponents([]types.ComponentMetadata{fooComp})
+	assert.NilError(t, err)
+
+	// Both entities are accessed on the first call, and only the active one is accessed afterwards.
+	for i := 0; i < 2; i++ {
+		count, err := manager.ArchiveInactiveEntities(2)
+		assert.NilError(t, err)
+		assert.Equal(t, 0, count)
+		assert.NilError(t, manager.FinalizeTick(ctx))
+		_, err = manager.GetComponentForEntity(fooComp, active)
+		assert.NilError(t, err)
+	}
+	count, err := manager.ArchiveInactiveEntities(2)
+	assert.NilError(t, err)
+	assert.Equal(t, 1, count)
+	assert.NilError(t, manager.FinalizeTick(ctx))
+
+	archived, err := manager.IsEntityArchived(dormant)
+	assert.NilError(t, err)
+	assert.Assert(t, archived)
+
+	// Counts and queries include archived entities without rehydrating them.
+	count, err = manager.CountEntitiesForArchID(archID)
+	assert.NilError(t, err)
+	assert.Equal(t, 2, count)
+	inArchetype, err := manager.ToReadOnly().GetEntitiesForArchID(archID)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []types.EntityID{active, dormant}, inArchetype)
+	value, err := manager.ToReadOnly().GetComponentForEntity(fooComp, dormant)
+	assert.NilError(t, err)
+	assert.Equal(t, Foo{Value: 2}, value)
+	archived, err = manager.IsEntityArchived(dormant)
+	assert.NilError(t, err)
+	assert.Assert(t, archived)
+
+	// Accessing the entity by ID moves it back into its archetype.
+	value, err = manager.GetComponentForEntity(fooComp, dormant)
+	assert.NilError(t, err)
+	assert.Equal(t, Foo{Value: 2}, value)
+	assert.NilError(t, manager.FinalizeTick(ctx))
+
+	reloaded, _ := newCmdBufferAndRedisClientForTest(t, client)
+	archived, err = reloaded.IsEntityArchived(dormant)
+	assert.NilError(t, err)
+	assert.Assert(t, !archived)
+	inArchetype, err = reloaded.GetEntitiesForArchID(archID)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []types.EntityID{active, dormant}, inArchetype)
+	value, err = reloaded.GetComponentForEntity(fooComp, dormant)
+	assert.NilError(t, err)
+	assert.Equal(t, Foo{Value: 2}, value)
+}
+
+func TestDiscardedArchivalIsUndone(t *testing.T) {
+	ctx := context.Background()
+	manager := newCmdBufferForTest(t)
+	id, err := manager.CreateEntity(fooComp)
+	assert.NilError(t, err)
+	assert.NilError(t, manager.FinalizeTick(ctx))
+
+	count, err := manager.ArchiveInactiveEntities(0)
+	assert.NilError(t, err)
+	assert.Equal(t, 1, count)
+	assert.NilError(t, manager.DiscardPending())
+
+	archived, err := manager.IsEntityArchived(id)
+	assert.NilError(t, err)
+	assert.Assert(t, !archived)
+	comps, err := manager.GetComponentTypesForEntity(id)
+	assert.NilError(t, err)
+	assert.Equal(t, 1, len(comps))
+}
+
+func TestSearchedArchetypeIsRehydrated(t *testing.T) {
+	ctx := context.Background()
+	manager := newCmdBufferForTest(t)
+	id, err := manager.CreateEntity(fooComp)
+	assert.NilError(t, err)
+	assert.NilError(t, manager.FinalizeTick(ctx))
+	count, err := manager.ArchiveInactiveEntities(0)
+	assert.NilError(t, err)
+	assert.Equal(t, 1, count)
+	assert.NilError(t, manager.FinalizeTick(ctx))
+
+	archID, err := manager.GetArchIDForComponents([]types.ComponentMetadata{fooComp})
+	assert.NilError(t, err)
+	inArchetype, err := manager.GetEntitiesForArchID(archID)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []types.EntityID{id}, inArchetype)
+	archived, err := manager.IsEntityArchived(id)
+	assert.NilError(t, err)
+	assert.Assert(t, !archived)
+	values, err := manager.GetComponentsForArchID(fooComp, archID, inArchetype)
+	assert.NilError(t, err)
+	assert.Equal(t, 1, len(values))
+}
+
+func TestLastAccessIsCommitted(t *testing.T) {
+	ctx := context.Background()
+	manager, client := newCmdBufferAndRedisClientForTest(t, nil)
+	ids, err := manager.CreateManyEntities(2, fooComp)
+	assert.NilError(t, err)
+	active, dormant := ids[0], ids[1]
+	assert.NilError(t, manager.FinalizeTick(ctx))
+	count, err := manager.ArchiveInactiveEntities(2)
+	assert.NilError(t, err)
+	assert.Equal(t, 0, count)
+	assert.NilError(t, manager.FinalizeTick(ctx))
+
+	// Another instance that takes over archives the same entities at the same tick.
+	reloaded, _ := newCmdBufferAndRedisClientForTest(t, client)
+	reloaded.TrackEntityAccess()
+	for i := 0; i < 2; i++ {
+		_, err = reloaded.GetComponentForEntity(fooComp, active)
+		assert.NilError(t, err)
+		count, err = reloaded.ArchiveInactiveEntities(2)
+		assert.NilError(t, err)
+		assert.Equal(t, i, count)
+		assert.NilError(t, reloaded.FinalizeTick(ctx))
+	}
+	archived, err := reloaded.IsEntityArchived(dormant)
+	assert.NilError(t, err)
+	assert.Assert(t, archived)
+	archived, err = reloaded.IsEntityArchived(active)
+	assert.NilError(t, err)
+	assert.Assert(t, !archived)
+}
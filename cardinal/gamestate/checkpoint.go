@@ -0,0 +1,289 @@
+package gamestate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal/types"
+)
+
+var (
+	ErrCheckpointNotFound = errors.New("checkpoint not found")
+	ErrInvalidCheckpoint  = errors.New("invalid checkpoint name")
+	ErrPendingChanges     = errors.New("state has pending changes")
+)
+
+// CheckpointInfo describes a saved checkpoint.
+type CheckpointInfo struct {
+	Name string `json:"name"`
+	// Tick is the number of ticks that had completed when the checkpoint was saved.
+	Tick uint64 `json:"tick"`
+}
+
+// checkpointRecord is the value stored for each checkpoint in the checkpoint index.
+type checkpointRecord struct {
+	Tick uint64   `json:"tick"`
+	Keys []string `json:"keys"`
+}
+
+// SaveCheckpoint copies the committed game state into a checkpoint with the given name, replacing any existing
+// checkpoint with that name. Tick log entries and state diffs are not part of a checkpoint. It must be called between
+// ticks.
+func (m *EntityCommandBuffer) SaveCheckpoint(name string) (CheckpointInfo, error) {
+	if err := validateCheckpointName(name); err != nil {
+		return CheckpointInfo{}, err
+	}
+	if err := m.checkNoPendingChanges(); err != nil {
+		return CheckpointInfo{}, err
+	}
+	ctx := context.Background()
+	_, tick, err := m.GetTickNumbers()
+	if err != nil {
+		return CheckpointInfo{}, err
+	}
+	index, err := m.loadCheckpointIndex(ctx)
+	if err != nil {
+		return CheckpointInfo{}, err
+	}
+	keys, err := m.stateKeys(ctx)
+	if err != nil {
+		return CheckpointInfo{}, err
+	}
+
+	pipe, err := m.dbStorage.StartTransaction(ctx)
+	if err != nil {
+		return CheckpointInfo{}, err
+	}
+	if old, ok := index[name]; ok {
+		for _, key := range old.Keys {
+			if err = pipe.Delete(ctx, storageCheckpointKey(name, key)); err != nil {
+				return CheckpointInfo{}, eris.Wrap(err, "")
+			}
+		}
+	}
+	for _, key := range keys {
+		if err = m.copyStateKey(ctx, pipe, key, key, storageCheckpointKey(name, key)); err != nil {
+			return CheckpointInfo{}, err
+		}
+	}
+	index[name] = checkpointRecord{Tick: tick, Keys: keys}
+	if err = setCheckpointIndex(ctx, pipe, index); err != nil {
+		return CheckpointInfo{}, err
+	}
+	if err = pipe.EndTransaction(ctx); err != nil {
+		return CheckpointInfo{}, eris.Wrap(err, "")
+	}
+	return CheckpointInfo{Name: name, Tick: tick}, nil
+}
+
+// ListCheckpoints returns all saved checkpoints, sorted by name.
+func (m *EntityCommandBuffer) ListCheckpoints() ([]CheckpointInfo, error) {
+	index, err := m.loadCheckpointIndex(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	infos := make([]CheckpointInfo, 0, len(index))
+	for name, record := range index {
+		infos = append(infos, CheckpointInfo{Name: name, Tick: record.Tick})
+	}
+	slices.SortFunc(infos, func(a, b CheckpointInfo) int { return strings.Compare(a.Name, b.Name) })
+	return infos, nil
+}
+
+// DeleteCheckpoint deletes the checkpoint with the given name.
+func (m *EntityCommandBuffer) DeleteCheckpoint(name string) error {
+	ctx := context.Background()
+	index, err := m.loadCheckpointIndex(ctx)
+	if err != nil {
+		return err
+	}
+	record, ok := index[name]
+	if !ok {
+		return eris.Wrapf(ErrCheckpointNotFound, "checkpoint %q", name)
+	}
+	pipe, err := m.dbStorage.StartTransaction(ctx)
+	if err != nil {
+		return err
+	}
+	for _, key := range record.Keys {
+		if err = pipe.Delete(ctx, storageCheckpointKey(name, key)); err != nil {
+			return eris.Wrap(err, "")
+		}
+	}
+	delete(index, name)
+	if err = setCheckpointIndex(ctx, pipe, index); err != nil {
+		return err
+	}
+	return eris.Wrap(pipe.EndTransaction(ctx), "")
+}
+
+// RestoreCheckpoint atomically replaces the committed game state with the state saved in the given checkpoint. Tick
+// log entries and state diffs of the ticks that are rolled back are deleted, since those ticks will be run again. It
+// must be called between ticks. The checkpoint is kept so it can be restored again.
+func (m *EntityCommandBuffer) RestoreCheckpoint(name string) (CheckpointInfo, error) {
+	if err := m.checkNoPendingChanges(); err != nil {
+		return CheckpointInfo{}, err
+	}
+	ctx := context.Background()
+	index, err := m.loadCheckpointIndex(ctx)
+	if err != nil {
+		return CheckpointInfo{}, err
+	}
+	record, ok := index[name]
+	if !ok {
+		return CheckpointInfo{}, eris.Wrapf(ErrCheckpointNotFound, "checkpoint %q", name)
+	}
+	current, err := m.stateKeys(ctx)
+	if err != nil {
+		return CheckpointInfo{}, err
+	}
+	rolledBack, err := m.tickKeysFrom(ctx, record.Tick)
+	if err != nil {
+		return CheckpointInfo{}, err
+	}
+
+	pipe, err := m.dbStorage.StartTransaction(ctx)
+	if err != nil {
+		return CheckpointInfo{}, err
+	}
+	m.waitForStateReads()
+	for _, key := range slices.Concat(current, rolledBack) {
+		if err = pipe.Delete(ctx, key); err != nil {
+			return CheckpointInfo{}, eris.Wrap(err, "")
+		}
+	}
+	for _, key := range record.Keys {
+		if err = m.copyStateKey(ctx, pipe, key, storageCheckpointKey(name, key), key); err != nil {
+			return CheckpointInfo{}, eris.Wrapf(err, "checkpoint %q", name)
+		}
+	}
+	if err = pipe.EndTransaction(ctx); err != nil {
+		return CheckpointInfo{}, eris.Wrap(err, "")
+	}
+
+	// Everything that was cached from the previous state must be loaded again.
+	m.entityIDToArchID = NewMapStorage[types.EntityID, types.ArchetypeID]()
+	m.archIDToComps = NewMapStorage[types.ArchetypeID, []types.ComponentMetadata]()
+	m.lastAccess = nil
+	if err = m.DiscardPending(); err != nil {
+		return CheckpointInfo{}, err
+	}
+	if m.typeToComponent != nil {
+		if err = m.loadArchIDs(); err != nil {
+			return CheckpointInfo{}, err
+		}
+	}
+	return CheckpointInfo{Name: name, Tick: record.Tick}, nil
+}
+
+// copyStateKey adds a write of the committed value of the key from to the key to to the pipe. The given state key is
+// the key that from or to are a copy of, which tells whether they hold a set of the index storage rather than a string.
+func (m *EntityCommandBuffer) copyStateKey(
+	ctx context.Context, pipe PrimitiveStorage[string], key, from, to string,
+) error {
+	if strings.HasPrefix(key, storageIndexSetPrefix) {
+		members, err := m.dbStorage.GetMembers(ctx, from)
+		if err != nil {
+			return eris.Wrapf(err, "failed to read %q", from)
+		}
+		return eris.Wrap(pipe.AddMembers(ctx, to, members...), "")
+	}
+	value, err := m.dbStorage.GetBytes(ctx, from)
+	if err != nil {
+		return eris.Wrapf(err, "failed to read %q", from)
+	}
+	return eris.Wrap(pipe.Set(ctx, to, value), "")
+}
+
+// checkNoPendingChanges returns an error if there are buffered state changes that have not been committed. Copying
+// the committed state while changes are pending would produce a checkpoint that does not match what systems see.
+func (m *EntityCommandBuffer) checkNoPendingChanges() error {
+	if m.compValues.Len() > 0 || m.compValuesToDelete.Len() > 0 || m.entityIDToOriginArchID.Len() > 0 ||
+		m.pendingEntityIDs > 0 || len(m.pendingArchIDs) > 0 || m.rawValues.Len() > 0 || m.rawValuesToDelete.Len() > 0 ||
+		len(m.pendingOutbox) > 0 || len(m.pendingArchive) > 0 || len(m.pendingRehydrate) > 0 ||
+		len(m.pendingInputAcks) > 0 || len(m.pendingIndexValues) > 0 || len(m.pendingIndexMembers) > 0 {
+		return eris.Wrap(ErrPendingChanges, "checkpoints can only be used between ticks")
+	}
+	return nil
+}
+
+// stateKeys returns the keys of the committed game state, excluding checkpoints, tick log entries, state diffs, the
+// outbox and state commitments. Rolling back must not make the outbox deliver acknowledged messages again, nor forget state roots that
+// were already submitted.
+func (m *EntityCommandBuffer) stateKeys(ctx context.Context) ([]string, error) {
+	keys, err := m.dbStorage.Keys(ctx)
+	if err != nil {
+		return nil, eris.Wrap(err, "")
+	}
+	stateKeys := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if !strings.HasPrefix(key, storagePrefix) ||
+			strings.HasPrefix(key, storageCheckpointPrefix) ||
+			strings.HasPrefix(key, storageTickLogPrefix) ||
+			strings.HasPrefix(key, storageStateDiffPrefix) ||
+			strings.HasPrefix(key, storageOutboxPrefix) ||
+			strings.HasPrefix(key, storageStateCommitmentPrefix) {
+			continue
+		}
+		stateKeys = append(stateKeys, key)
+	}
+	slices.Sort(stateKeys)
+	return stateKeys, nil
+}
+
+// tickKeysFrom returns the keys of the tick log entries and state diffs of the given tick and all later ticks.
+func (m *EntityCommandBuffer) tickKeysFrom(ctx context.Context, from uint64) ([]string, error) {
+	keys, err := m.dbStorage.Keys(ctx)
+	if err != nil {
+		return nil, eris.Wrap(err, "")
+	}
+	var tickKeys []string
+	for _, key := range keys {
+		var tick uint64
+		if _, err = fmt.Sscanf(key, storageTickLogPrefix+"TICK-%d", &tick); err != nil {
+			if _, err = fmt.Sscanf(key, storageStateDiffPrefix+"TICK-%d", &tick); err != nil {
+				continue
+			}
+		}
+		if tick >= from {
+			tickKeys = append(tickKeys, key)
+		}
+	}
+	return tickKeys, nil
+}
+
+func (m *EntityCommandBuffer) loadCheckpointIndex(ctx context.Context) (map[string]checkpointRecord, error) {
+	index := map[string]checkpointRecord{}
+	bz, err := m.dbStorage.GetBytes(ctx, storageCheckpointIndexKey())
+	if errors.Is(err, redis.Nil) {
+		return index, nil
+	} else if err != nil {
+		return nil, eris.Wrap(err, "failed to load checkpoint index")
+	}
+	if err = json.Unmarshal(bz, &index); err != nil {
+		return nil, eris.Wrap(err, "failed to decode checkpoint index")
+	}
+	return index, nil
+}
+
+func setCheckpointIndex(ctx context.Context, pipe PrimitiveStorage[string], index map[string]checkpointRecord) error {
+	bz, err := json.Marshal(index)
+	if err != nil {
+		return eris.Wrap(err, "failed to encode checkpoint index")
+	}
+	return eris.Wrap(pipe.Set(ctx, storageCheckpointIndexKey(), bz), "")
+}
+
+func validateCheckpointName(name string) error {
+	if name == "" || strings.ContainsAny(name, ": \t\n") {
+		return eris.Wrapf(ErrInvalidCheckpoint, "%q must be non-empty and must not contain colons or whitespace", name)
+	}
+	return nil
+}
@@ -0,0 +1,368 @@
+package gamestate
+
+import (
+	"cmp"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal/merkle"
+	"pkg.world.dev/world-engine/cardinal/types"
+)
+
+var (
+	ErrStateCommitmentNotFound = errors.New("state commitment not found")
+	ErrStateLeafNotFound       = errors.New("entity has no such component in the state commitment")
+)
+
+// StateLeaf is a leaf of the merkle tree of a state commitment: the value of a component of an entity.
+type StateLeaf struct {
+	Entity    types.EntityID `json:"entity"`
+	Component string         `json:"component"`
+	// Value is the component value, encoded as JSON.
+	Value json.RawMessage `json:"value" swaggertype:"object"`
+}
+
+// Hash returns the leaf hash of the leaf. The hashed data is the entity ID as an 8 byte big endian integer, followed by
+// the length of the component name as an 8 byte big endian integer, the component name, and the JSON value.
+func (l StateLeaf) Hash() merkle.Hash {
+	data := binary.BigEndian.AppendUint64(nil, uint64(l.Entity))
+	data = binary.BigEndian.AppendUint64(data, uint64(len(l.Component)))
+	data = append(data, l.Component...)
+	return merkle.LeafHash(append(data, l.Value...))
+}
+
+// StateCommitment is the merkle root of the game state at the end of a range of ticks. The leaves of the tree are the
+// component values of all entities, sorted by entity ID and component name.
+type StateCommitment struct {
+	StartTick uint64 `json:"startTick"`
+	EndTick   uint64 `json:"endTick"`
+	// Root is the hex encoded merkle root.
+	Root merkle.Hash `json:"root" swaggertype:"string"`
+}
+
+// StateProof proves that an entity had a component value at the end of the range of ticks of a state commitment.
+type StateProof struct {
+	Commitment StateCommitment `json:"commitment"`
+	Leaf       StateLeaf       `json:"leaf"`
+	Proof      merkle.Proof    `json:"proof"`
+}
+
+// Verify reports whether the proof shows that the leaf is part of the state of the commitment.
+func (p StateProof) Verify() bool {
+	return p.Proof.Verify(p.Commitment.Root, p.Leaf.Hash())
+}
+
+// DefaultStateCommitmentsKept is the number of most recent state commitments whose leaves are kept when no other
+// number is given.
+const DefaultStateCommitmentsKept = 100
+
+// SaveStateCommitment computes the state commitment of the ticks that completed since the last one, and saves its
+// leaves so that proofs can be generated for it. It must be called between ticks. See StartStateCommitment.
+func (m *EntityCommandBuffer) SaveStateCommitment(keep int) (StateCommitment, error) {
+	finish, err := m.StartStateCommitment(keep)
+	if err != nil {
+		return StateCommitment{}, err
+	}
+	return finish()
+}
+
+// StartStateCommitment starts the state commitment of the ticks that completed since the last one. It must be called
+// between ticks, and only lists the components of the entities; the returned function reads their values, computes
+// the commitment and saves its leaves, and may run concurrently with the next ticks. Those ticks are not committed
+// until the values have been read, so the commitment holds the state at the end of the range. Only the given number
+// of most recent commitments are kept; zero or less keeps DefaultStateCommitmentsKept of them.
+//
+// The returned function must be called, since ticks wait for it to read the values. A commitment that is started
+// before the function of the previous one has returned waits for it.
+func (m *EntityCommandBuffer) StartStateCommitment(keep int) (func() (StateCommitment, error), error) {
+	if err := m.checkNoPendingChanges(); err != nil {
+		return nil, err
+	}
+	if keep <= 0 {
+		keep = DefaultStateCommitmentsKept
+	}
+	_, tick, err := m.GetTickNumbers()
+	if err != nil {
+		return nil, err
+	}
+	if tick == 0 {
+		return nil, eris.New("no tick has completed yet")
+	}
+	m.stateCommitmentMu.Lock()
+	snapshot, err := m.snapshotState(context.Background(), tick-1)
+	if err != nil {
+		m.stateCommitmentMu.Unlock()
+		return nil, err
+	}
+	m.stateReads.Add(1)
+	return func() (StateCommitment, error) {
+		defer m.stateCommitmentMu.Unlock()
+		return m.saveStateCommitment(context.Background(), snapshot, keep)
+	}, nil
+}
+
+// snapshotState lists the components of the active entities and the archived entities whose values make up the
+// state commitment that ends at the given tick.
+func (m *EntityCommandBuffer) snapshotState(ctx context.Context, endTick uint64) (stateSnapshot, error) {
+	index, err := m.loadStateCommitmentIndex(ctx)
+	if err != nil {
+		return stateSnapshot{}, err
+	}
+	snapshot := stateSnapshot{
+		commitment:     StateCommitment{EndTick: endTick},
+		index:          index,
+		componentTypes: map[types.ComponentID]types.ComponentMetadata{},
+	}
+	if len(index) > 0 {
+		last := index[len(index)-1]
+		if last.EndTick >= endTick {
+			return stateSnapshot{}, eris.Errorf("state up to tick %d is already committed", last.EndTick)
+		}
+		snapshot.commitment.StartTick = last.EndTick + 1
+	}
+	for archID := types.ArchetypeID(0); int(archID) < m.ArchetypeCount(); archID++ {
+		comps, err := m.GetComponentTypesForArchID(archID)
+		if err != nil {
+			return stateSnapshot{}, err
+		}
+		for _, cType := range comps {
+			snapshot.componentTypes[cType.ID()] = cType
+		}
+		active, err := m.getActiveEntities(archID)
+		if err != nil {
+			return stateSnapshot{}, err
+		}
+		for _, id := range active.ids {
+			for _, cType := range comps {
+				snapshot.components = append(snapshot.components, compKey{cType.ID(), id})
+			}
+		}
+		archived, err := m.getArchivedEntities(archID)
+		if err != nil {
+			return stateSnapshot{}, err
+		}
+		snapshot.archived = append(snapshot.archived, archived.ids...)
+	}
+	return snapshot, nil
+}
+
+// saveStateCommitment reads the values of a snapshot, and saves the leaves of its commitment with the index of the
+// commitments that are kept.
+func (m *EntityCommandBuffer) saveStateCommitment(
+	ctx context.Context, snapshot stateSnapshot, keep int,
+) (StateCommitment, error) {
+	leaves, err := m.stateLeaves(ctx, snapshot)
+	if err != nil {
+		return StateCommitment{}, err
+	}
+	commitment := snapshot.commitment
+	commitment.Root = merkle.Root(leafHashes(leaves))
+
+	bz, err := json.Marshal(leaves)
+	if err != nil {
+		return StateCommitment{}, eris.Wrap(err, "failed to encode state leaves")
+	}
+	if bz, err = m.cipher.Seal(bz, storageStateCommitmentLeavesKey(commitment.EndTick)); err != nil {
+		return StateCommitment{}, err
+	}
+	pipe, err := m.dbStorage.StartTransaction(ctx)
+	if err != nil {
+		return StateCommitment{}, err
+	}
+	if err = pipe.Set(ctx, storageStateCommitmentLeavesKey(commitment.EndTick), bz); err != nil {
+		return StateCommitment{}, eris.Wrap(err, "")
+	}
+	index := append(snapshot.index, commitment)
+	if len(index) > keep {
+		for _, pruned := range index[:len(index)-keep] {
+			if err = pipe.Delete(ctx, storageStateCommitmentLeavesKey(pruned.EndTick)); err != nil {
+				return StateCommitment{}, eris.Wrap(err, "")
+			}
+		}
+		index = index[len(index)-keep:]
+	}
+	if err = setStateCommitmentIndex(ctx, pipe, index); err != nil {
+		return StateCommitment{}, err
+	}
+	if err = pipe.EndTransaction(ctx); err != nil {
+		return StateCommitment{}, eris.Wrap(err, "")
+	}
+	return commitment, nil
+}
+
+// ListStateCommitments returns the state commitments that can still be proven, sorted by tick.
+func (m *EntityCommandBuffer) ListStateCommitments() ([]StateCommitment, error) {
+	return m.loadStateCommitmentIndex(context.Background())
+}
+
+// ProveState returns the proof of the value that the given component of the entity had in the state commitment whose
+// range of ticks contains the given tick.
+func (m *EntityCommandBuffer) ProveState(tick uint64, id types.EntityID, component string) (StateProof, error) {
+	ctx := context.Background()
+	index, err := m.loadStateCommitmentIndex(ctx)
+	if err != nil {
+		return StateProof{}, err
+	}
+	i := slices.IndexFunc(index, func(c StateCommitment) bool { return c.StartTick <= tick && tick <= c.EndTick })
+	if i < 0 {
+		return StateProof{}, eris.Wrapf(ErrStateCommitmentNotFound, "no state commitment covers tick %d", tick)
+	}
+	commitment := index[i]
+	bz, err := m.dbStorage.GetBytes(ctx, storageStateCommitmentLeavesKey(commitment.EndTick))
+	if err != nil {
+		return StateProof{}, eris.Wrapf(err, "failed to load the leaves of the state commitment of tick %d", tick)
+	}
+	if bz, err = m.cipher.Open(bz, storageStateCommitmentLeavesKey(commitment.EndTick)); err != nil {
+		return StateProof{}, err
+	}
+	var leaves []StateLeaf
+	if err = json.Unmarshal(bz, &leaves); err != nil {
+		return StateProof{}, eris.Wrap(err, "failed to decode state leaves")
+	}
+	pos, found := slices.BinarySearchFunc(leaves, StateLeaf{Entity: id, Component: component}, compareStateLeaves)
+	if !found {
+		return StateProof{}, eris.Wrapf(ErrStateLeafNotFound, "entity %d, component %q", id, component)
+	}
+	proof, err := merkle.Prove(leafHashes(leaves), pos)
+	if err != nil {
+		return StateProof{}, err
+	}
+	return StateProof{Commitment: commitment, Leaf: leaves[pos], Proof: proof}, nil
+}
+
+// stateSnapshot is what a state commitment reads once its tick has completed: the components of the active entities
+// and the archived entities, with the component types they use.
+type stateSnapshot struct {
+	commitment StateCommitment
+	// index is the index of the state commitments before this one.
+	index          []StateCommitment
+	components     []compKey
+	archived       []types.EntityID
+	componentTypes map[types.ComponentID]types.ComponentMetadata
+}
+
+// stateLeaves reads the values of the snapshot in batches and returns its leaves, sorted by entity ID and component
+// name. Components that were never set are not stored, so their leaves hold the default value. The next tick can be
+// committed once the values have been read.
+func (m *EntityCommandBuffer) stateLeaves(ctx context.Context, snapshot stateSnapshot) ([]StateLeaf, error) {
+	values, archived, err := m.readStateSnapshot(ctx, snapshot)
+	if err != nil {
+		return nil, err
+	}
+	leaves := make([]StateLeaf, 0, len(snapshot.components))
+	for _, key := range snapshot.components {
+		leaf, err := m.stateLeaf(snapshot.componentTypes, key.entityID, key.typeID, values[key])
+		if err != nil {
+			return nil, err
+		}
+		leaves = append(leaves, leaf)
+	}
+	for i, id := range snapshot.archived {
+		if archived[i] == nil {
+			continue
+		}
+		entity, err := decodeArchivedEntity(m.cipher, id, archived[i])
+		if err != nil {
+			return nil, err
+		}
+		for typeID, raw := range entity.Components {
+			leaf, err := m.stateLeaf(snapshot.componentTypes, id, typeID, raw)
+			if err != nil {
+				return nil, err
+			}
+			leaves = append(leaves, leaf)
+		}
+	}
+	slices.SortFunc(leaves, compareStateLeaves)
+	return leaves, nil
+}
+
+// readStateSnapshot reads the component values and the archived entities of the snapshot, and then lets the next tick
+// be committed.
+func (m *EntityCommandBuffer) readStateSnapshot(
+	ctx context.Context, snapshot stateSnapshot,
+) (map[compKey][]byte, [][]byte, error) {
+	defer m.stateReads.Done()
+	values, err := m.getManyComponentBytes(ctx, snapshot.components)
+	if err != nil {
+		return nil, nil, err
+	}
+	archived := make([][]byte, 0, len(snapshot.archived))
+	for ids := snapshot.archived; len(ids) > 0; {
+		batch := ids[:min(len(ids), maxKeysPerRead)]
+		ids = ids[len(batch):]
+		keys := make([]string, len(batch))
+		for i, id := range batch {
+			keys[i] = storageArchivedEntityKey(id)
+		}
+		bzs, err := m.dbStorage.GetManyBytes(ctx, keys)
+		if err != nil {
+			return nil, nil, eris.Wrap(err, "failed to read archived entities")
+		}
+		archived = append(archived, bzs...)
+	}
+	return values, archived, nil
+}
+
+// waitForStateReads blocks until the state commitments that were started have read their values, so that committing
+// the changes of a tick doesn't change the state they commit to.
+func (m *EntityCommandBuffer) waitForStateReads() {
+	m.stateReads.Wait()
+}
+
+// stateLeaf decodes a stored component value and encodes it as JSON, so that the leaf doesn't depend on the codec of
+// the component.
+func (m *EntityCommandBuffer) stateLeaf(
+	cTypes map[types.ComponentID]types.ComponentMetadata, id types.EntityID, typeID types.ComponentID, bz []byte,
+) (StateLeaf, error) {
+	cType, ok := cTypes[typeID]
+	if !ok {
+		return StateLeaf{}, eris.Errorf("unknown component type %d", typeID)
+	}
+	jsonValue, err := m.componentJSON(cType, id, bz)
+	if err != nil {
+		return StateLeaf{}, err
+	}
+	return StateLeaf{Entity: id, Component: cType.Name(), Value: jsonValue}, nil
+}
+
+func (m *EntityCommandBuffer) loadStateCommitmentIndex(ctx context.Context) ([]StateCommitment, error) {
+	index := []StateCommitment{}
+	bz, err := m.dbStorage.GetBytes(ctx, storageStateCommitmentIndexKey())
+	if errors.Is(err, redis.Nil) {
+		return index, nil
+	} else if err != nil {
+		return nil, eris.Wrap(err, "failed to load state commitment index")
+	}
+	if err = json.Unmarshal(bz, &index); err != nil {
+		return nil, eris.Wrap(err, "failed to decode state commitment index")
+	}
+	return index, nil
+}
+
+func setStateCommitmentIndex(ctx context.Context, pipe PrimitiveStorage[string], index []StateCommitment) error {
+	bz, err := json.Marshal(index)
+	if err != nil {
+		return eris.Wrap(err, "failed to encode state commitment index")
+	}
+	return eris.Wrap(pipe.Set(ctx, storageStateCommitmentIndexKey(), bz), "")
+}
+
+func leafHashes(leaves []StateLeaf) []merkle.Hash {
+	hashes := make([]merkle.Hash, len(leaves))
+	for i, leaf := range leaves {
+		hashes[i] = leaf.Hash()
+	}
+	return hashes
+}
+
+func compareStateLeaves(a, b StateLeaf) int {
+	return cmp.Or(cmp.Compare(a.Entity, b.Entity), strings.Compare(a.Component, b.Component))
+}
@@ -0,0 +1,193 @@
+package gamestate_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal/codec"
+	"pkg.world.dev/world-engine/cardinal/gamestate"
+)
+
+func TestStateCommitmentsProveComponentValues(t *testing.T) {
+	ctx := context.Background()
+	manager := newCmdBufferForTest(t)
+	first, err := manager.CreateEntity(fooComp, barComp)
+	assert.NilError(t, err)
+	second, err := manager.CreateEntity(fooComp)
+	assert.NilError(t, err)
+	assert.NilError(t, manager.SetComponentForEntity(fooComp, first, Foo{Value: 1}))
+	assert.NilError(t, manager.SetComponentForEntity(barComp, first, Bar{Value: 2}))
+	assert.NilError(t, manager.SetComponentForEntity(fooComp, second, Foo{Value: 3}))
+	assert.NilError(t, manager.FinalizeTick(ctx))
+	assert.NilError(t, manager.FinalizeTick(ctx))
+
+	commitment, err := manager.SaveStateCommitment(0)
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(0), commitment.StartTick)
+	assert.Equal(t, uint64(1), commitment.EndTick)
+
+	proof, err := manager.ProveState(0, first, "bar")
+	assert.NilError(t, err)
+	assert.Equal(t, commitment, proof.Commitment)
+	assert.Equal(t, `{"Value":2}`, string(proof.Leaf.Value))
+	assert.Check(t, proof.Verify())
+
+	// A proof for a value the entity never had doesn't verify.
+	forged := proof
+	forged.Leaf.Value = json.RawMessage(`{"Value":5}`)
+	assert.Check(t, !forged.Verify())
+
+	// The next commitment covers the following ticks, and the proofs of the previous one still hold for its value.
+	assert.NilError(t, manager.SetComponentForEntity(barComp, first, Bar{Value: 5}))
+	assert.NilError(t, manager.FinalizeTick(ctx))
+	next, err := manager.SaveStateCommitment(0)
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(2), next.StartTick)
+	assert.Equal(t, uint64(2), next.EndTick)
+	assert.Check(t, next.Root != commitment.Root)
+	proof, err = manager.ProveState(2, first, "bar")
+	assert.NilError(t, err)
+	assert.Equal(t, `{"Value":5}`, string(proof.Leaf.Value))
+	assert.Check(t, proof.Verify())
+	proof, err = manager.ProveState(1, first, "bar")
+	assert.NilError(t, err)
+	assert.Equal(t, `{"Value":2}`, string(proof.Leaf.Value))
+	assert.Check(t, proof.Verify())
+
+	_, err = manager.ProveState(3, first, "bar")
+	assert.ErrorIs(t, err, gamestate.ErrStateCommitmentNotFound)
+	_, err = manager.ProveState(2, second, "bar")
+	assert.ErrorIs(t, err, gamestate.ErrStateLeafNotFound)
+	_, err = manager.SaveStateCommitment(0)
+	assert.ErrorContains(t, err, "already committed")
+}
+
+func TestStateCommitmentsArePruned(t *testing.T) {
+	ctx := context.Background()
+	manager := newCmdBufferForTest(t)
+	id, err := manager.CreateEntity(fooComp)
+	assert.NilError(t, err)
+	for i := 0; i < 3; i++ {
+		assert.NilError(t, manager.FinalizeTick(ctx))
+		_, err = manager.SaveStateCommitment(2)
+		assert.NilError(t, err)
+	}
+	commitments, err := manager.ListStateCommitments()
+	assert.NilError(t, err)
+	assert.Equal(t, 2, len(commitments))
+	assert.Equal(t, uint64(1), commitments[0].EndTick)
+	assert.Equal(t, uint64(2), commitments[1].EndTick)
+
+	_, err = manager.ProveState(0, id, "foo")
+	assert.ErrorIs(t, err, gamestate.ErrStateCommitmentNotFound)
+	proof, err := manager.ProveState(1, id, "foo")
+	assert.NilError(t, err)
+	assert.Check(t, proof.Verify())
+}
+
+func TestStateCommitmentsKeepTheDefaultNumber(t *testing.T) {
+	ctx := context.Background()
+	manager := newCmdBufferForTest(t)
+	_, err := manager.CreateEntity(fooComp)
+	assert.NilError(t, err)
+	for i := 0; i <= gamestate.DefaultStateCommitmentsKept; i++ {
+		assert.NilError(t, manager.FinalizeTick(ctx))
+		_, err = manager.SaveStateCommitment(0)
+		assert.NilError(t, err)
+	}
+	commitments, err := manager.ListStateCommitments()
+	assert.NilError(t, err)
+	assert.Equal(t, gamestate.DefaultStateCommitmentsKept, len(commitments))
+	assert.Equal(t, uint64(1), commitments[0].EndTick)
+}
+
+func TestStateCommitmentReadsTheStateBeforeTheNextTickIsCommitted(t *testing.T) {
+	ctx := context.Background()
+	manager := newCmdBufferForTest(t)
+	id, err := manager.CreateEntity(fooComp)
+	assert.NilError(t, err)
+	assert.NilError(t, manager.SetComponentForEntity(fooComp, id, Foo{Value: 1}))
+	assert.NilError(t, manager.FinalizeTick(ctx))
+
+	finish, err := manager.StartStateCommitment(0)
+	assert.NilError(t, err)
+	assert.NilError(t, manager.SetComponentForEntity(fooComp, id, Foo{Value: 2}))
+	committed := make(chan error, 1)
+	go func() {
+		committed <- manager.FinalizeTick(ctx)
+	}()
+	select {
+	case <-committed:
+		t.Fatal("the tick was committed before the state commitment read the state")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	commitment, err := finish()
+	assert.NilError(t, err)
+	assert.NilError(t, <-committed)
+	assert.Equal(t, uint64(0), commitment.EndTick)
+	proof, err := manager.ProveState(0, id, "foo")
+	assert.NilError(t, err)
+	assert.Equal(t, `{"Value":1}`, string(proof.Leaf.Value))
+	assert.Check(t, proof.Verify())
+}
+
+func TestArchivedEntitiesAreCommitted(t *testing.T) {
+	ctx := context.Background()
+	manager := newCmdBufferForTest(t)
+	id, err := manager.CreateEntity(fooComp)
+	assert.NilError(t, err)
+	assert.NilError(t, manager.SetComponentForEntity(fooComp, id, Foo{Value: 7}))
+	assert.NilError(t, manager.FinalizeTick(ctx))
+	count, err := manager.ArchiveInactiveEntities(0)
+	assert.NilError(t, err)
+	assert.Equal(t, 1, count)
+	assert.NilError(t, manager.FinalizeTick(ctx))
+
+	_, err = manager.SaveStateCommitment(0)
+	assert.NilError(t, err)
+	proof, err := manager.ProveState(1, id, "foo")
+	assert.NilError(t, err)
+	assert.Equal(t, `{"Value":7}`, string(proof.Leaf.Value))
+	assert.Check(t, proof.Verify())
+}
+
+func TestArchivesAndStateLeavesAreEncrypted(t *testing.T) {
+	ctx := context.Background()
+	manager, client := newCmdBufferAndRedisClientForTest(t, nil)
+	manager.SetEncryption(codec.NewStaticKey(bytes.Repeat([]byte{1}, 32)))
+	id, err := manager.CreateEntity(fooComp)
+	assert.NilError(t, err)
+	assert.NilError(t, manager.SetComponentForEntity(fooComp, id, Foo{Value: 1234}))
+	assert.NilError(t, manager.FinalizeTick(ctx))
+	count, err := manager.ArchiveInactiveEntities(0)
+	assert.NilError(t, err)
+	assert.Equal(t, 1, count)
+	assert.NilError(t, manager.FinalizeTick(ctx))
+	_, err = manager.SaveStateCommitment(0)
+	assert.NilError(t, err)
+
+	keys, err := client.Keys(ctx, "*").Result()
+	assert.NilError(t, err)
+	for _, key := range keys {
+		if !strings.Contains(key, "ARCHIVED-ENTITY:") && !strings.Contains(key, "STATE-COMMITMENT:TICK-") {
+			continue
+		}
+		value, err := client.Get(ctx, key).Bytes()
+		assert.NilError(t, err)
+		assert.Assert(t, !bytes.Contains(value, []byte("1234")), "%q is stored unencrypted", key)
+	}
+
+	// The archived entity is still proven and rehydrated.
+	proof, err := manager.ProveState(1, id, "foo")
+	assert.NilError(t, err)
+	assert.Equal(t, `{"Value":1234}`, string(proof.Leaf.Value))
+	value, err := manager.GetComponentForEntity(fooComp, id)
+	assert.NilError(t, err)
+	assert.Equal(t, Foo{Value: 1234}, value)
+}
@@ -0,0 +1,378 @@
+package gamestate
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal/types"
+)
+
+// MaxStateDiffTicks is the maximum number of ticks that one call of StateDiff merges, so that diffing a long range of
+// ticks can't stall the storage.
+const MaxStateDiffTicks = 10_000
+
+var (
+	ErrStateDiffNotAvailable = errors.New("state diff not available")
+	ErrInvalidStateDiffRange = errors.New("invalid range of ticks to diff")
+)
+
+// ComponentChange is a component value of an entity that changed.
+type ComponentChange struct {
+	Entity    types.EntityID `json:"entity"`
+	Component string         `json:"component"`
+	// Old is the value before the change, encoded as JSON, or null if the entity didn't have the component.
+	Old json.RawMessage `json:"old" swaggertype:"object"`
+	// New is the value after the change, encoded as JSON, or null if the entity no longer has the component.
+	New json.RawMessage `json:"new" swaggertype:"object"`
+}
+
+// StateDiff is the change that the ticks from FromTick up to, but not including, ToTick made to the game state. The
+// entities are sorted by ID, and the changes by entity ID and component name. Entities that were created and destroyed
+// within the range of ticks are left out.
+type StateDiff struct {
+	FromTick  uint64            `json:"fromTick"`
+	ToTick    uint64            `json:"toTick"`
+	Created   []types.EntityID  `json:"created"`
+	Destroyed []types.EntityID  `json:"destroyed"`
+	Changed   []ComponentChange `json:"changed"`
+}
+
+func newStateDiff(fromTick, toTick uint64) StateDiff {
+	return StateDiff{
+		FromTick:  fromTick,
+		ToTick:    toTick,
+		Created:   []types.EntityID{},
+		Destroyed: []types.EntityID{},
+		Changed:   []ComponentChange{},
+	}
+}
+
+// RecordStateDiffs makes FinalizeTick commit the diff of every tick with the rest of the tick's state changes, so that
+// StateDiff can report the changes of a range of ticks. Only the diffs of the given number of most recent ticks are
+// kept; zero keeps all of them.
+func (m *EntityCommandBuffer) RecordStateDiffs(keep uint64) {
+	m.recordStateDiffs = true
+	m.stateDiffsKept = keep
+}
+
+// StateDiff returns the change that the committed ticks from fromTick up to, but not including, toTick made to the
+// game state. ErrStateDiffNotAvailable is returned if the diff of one of the ticks was not recorded, because the tick
+// has not completed yet, completed while diffs were not recorded, or its diff was deleted.
+func (m *EntityCommandBuffer) StateDiff(fromTick, toTick uint64) (StateDiff, error) {
+	if toTick < fromTick {
+		return StateDiff{}, eris.Wrapf(ErrInvalidStateDiffRange, "tick %d is after tick %d", fromTick, toTick)
+	}
+	if toTick-fromTick > MaxStateDiffTicks {
+		return StateDiff{}, eris.Wrapf(ErrInvalidStateDiffRange, "at most %d ticks can be diffed", MaxStateDiffTicks)
+	}
+	start, err := m.GetStateDiffStart()
+	if err != nil {
+		return StateDiff{}, err
+	}
+	if fromTick < start {
+		return StateDiff{}, eris.Wrapf(ErrStateDiffNotAvailable, "the diffs before tick %d were deleted", start)
+	}
+
+	ctx := context.Background()
+	diff := newStateDiff(fromTick, toTick)
+	created := map[types.EntityID]bool{}
+	destroyed := map[types.EntityID]bool{}
+	changes := map[types.EntityID]map[string]*ComponentChange{}
+	for tick := fromTick; tick < toTick; tick++ {
+		bz, err := m.dbStorage.GetBytes(ctx, storageStateDiffKey(tick))
+		if errors.Is(err, redis.Nil) {
+			return StateDiff{}, eris.Wrapf(ErrStateDiffNotAvailable, "tick %d", tick)
+		} else if err != nil {
+			return StateDiff{}, err
+		}
+		var tickDiff StateDiff
+		if err = json.Unmarshal(bz, &tickDiff); err != nil {
+			return StateDiff{}, eris.Wrapf(err, "failed to decode the state diff of tick %d", tick)
+		}
+		for _, id := range tickDiff.Created {
+			created[id] = true
+		}
+		for _, id := range tickDiff.Destroyed {
+			if created[id] {
+				delete(created, id)
+				continue
+			}
+			destroyed[id] = true
+		}
+		for _, change := range tickDiff.Changed {
+			if changes[change.Entity] == nil {
+				changes[change.Entity] = map[string]*ComponentChange{}
+			}
+			if merged, ok := changes[change.Entity][change.Component]; ok {
+				merged.New = change.New
+			} else {
+				changes[change.Entity][change.Component] = &change
+			}
+		}
+	}
+
+	for id := range created {
+		diff.Created = append(diff.Created, id)
+	}
+	for id := range destroyed {
+		diff.Destroyed = append(diff.Destroyed, id)
+	}
+	for _, byComponent := range changes {
+		for _, change := range byComponent {
+			// A value that was changed back, or a component of an entity that was created and destroyed within the
+			// range, didn't change.
+			if !bytes.Equal(change.Old, change.New) {
+				diff.Changed = append(diff.Changed, *change)
+			}
+		}
+	}
+	sortStateDiff(&diff)
+	return diff, nil
+}
+
+// GetStateDiffStart returns the first tick whose state diff has not been deleted because of the retention of
+// RecordStateDiffs.
+func (m *EntityCommandBuffer) GetStateDiffStart() (uint64, error) {
+	start, err := m.dbStorage.GetUInt64(context.Background(), storageStateDiffStartKey())
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	} else if err != nil {
+		return 0, eris.Wrap(err, "")
+	}
+	return start, nil
+}
+
+// addStateDiffToPipe adds the diff of the tick that is being finalized, and the deletion of the diff that is no longer
+// kept, to the redis pipe. It must run before the pending component changes are added to the pipe, since it compares
+// them with the committed values.
+func (m *EntityCommandBuffer) addStateDiffToPipe(ctx context.Context, pipe PrimitiveStorage[string]) error {
+	if !m.recordStateDiffs {
+		return nil
+	}
+	_, tick, err := m.GetTickNumbers()
+	if err != nil {
+		return err
+	}
+	diff, err := m.pendingStateDiff(ctx, tick)
+	if err != nil {
+		return err
+	}
+	bz, err := json.Marshal(diff)
+	if err != nil {
+		return eris.Wrap(err, "failed to encode state diff")
+	}
+	if err = pipe.Set(ctx, storageStateDiffKey(tick), bz); err != nil {
+		return eris.Wrap(err, "")
+	}
+	if keep := m.stateDiffsKept; keep > 0 && tick >= keep {
+		if err = pipe.Delete(ctx, storageStateDiffKey(tick-keep)); err != nil {
+			return eris.Wrap(err, "")
+		}
+		return eris.Wrap(pipe.Set(ctx, storageStateDiffStartKey(), tick-keep+1), "")
+	}
+	return nil
+}
+
+// diffSlot is a component of an entity whose value may have changed during the tick.
+type diffSlot struct {
+	cType types.ComponentMetadata
+	// hadBefore and hasAfter report whether the entity had the component before the tick, and has it after the tick.
+	hadBefore, hasAfter bool
+	// archived is the value of the component before the tick if the entity was rehydrated from the archive.
+	archived json.RawMessage
+}
+
+// pendingStateDiff returns the change that the pending state changes make to the committed game state, as the diff of
+// the given tick. Moving entities in and out of the archive doesn't change them.
+func (m *EntityCommandBuffer) pendingStateDiff(ctx context.Context, tick uint64) (StateDiff, error) {
+	diff := newStateDiff(tick, tick+1)
+	slots := map[compKey]*diffSlot{}
+	skipped := map[types.EntityID]bool{}
+
+	// The entities that moved between archetypes, including the ones that were created and removed
+	moved, err := m.entityIDToOriginArchID.Keys()
+	if err != nil {
+		return StateDiff{}, err
+	}
+	for _, id := range moved {
+		if _, ok := m.pendingArchive[id]; ok {
+			skipped[id] = true
+			continue
+		}
+		originArchID, err := m.entityIDToOriginArchID.Get(id)
+		if err != nil {
+			return StateDiff{}, err
+		}
+		archID, err := m.entityIDToArchID.Get(id)
+		exists := err == nil
+		var before []types.ComponentMetadata
+		var archived map[types.ComponentID]json.RawMessage
+		switch originArchID {
+		case doesNotExistArchetypeID:
+			if !exists {
+				skipped[id] = true
+				continue
+			}
+			diff.Created = append(diff.Created, id)
+		case archivedArchetypeID:
+			entity, err := getArchivedEntity(ctx, m.dbStorage, m.cipher, id)
+			if err != nil {
+				return StateDiff{}, err
+			}
+			archived = entity.Components
+			if before, err = m.GetComponentTypesForArchID(entity.ArchID); err != nil {
+				return StateDiff{}, err
+			}
+		default:
+			if before, err = m.GetComponentTypesForArchID(originArchID); err != nil {
+				return StateDiff{}, err
+			}
+		}
+		var after []types.ComponentMetadata
+		if exists {
+			if after, err = m.GetComponentTypesForArchID(archID); err != nil {
+				return StateDiff{}, err
+			}
+		} else {
+			diff.Destroyed = append(diff.Destroyed, id)
+		}
+		for _, cType := range before {
+			slots[compKey{cType.ID(), id}] = &diffSlot{cType: cType, hadBefore: true, archived: archived[cType.ID()]}
+		}
+		for _, cType := range after {
+			key := compKey{cType.ID(), id}
+			if slots[key] == nil {
+				slots[key] = &diffSlot{cType: cType}
+			}
+			slots[key].hasAfter = true
+		}
+	}
+
+	// The components that were read or set, of the entities that stayed in their archetype
+	keys, err := m.compValues.Keys()
+	if err != nil {
+		return StateDiff{}, err
+	}
+	for _, key := range keys {
+		if skipped[key.entityID] || slots[key] != nil {
+			continue
+		}
+		cType, err := m.typeToComponent.Get(key.typeID)
+		if err != nil {
+			return StateDiff{}, err
+		}
+		slots[key] = &diffSlot{cType: cType, hadBefore: true, hasAfter: true}
+	}
+
+	var reads []compKey
+	for key, slot := range slots {
+		if slot.hadBefore && slot.archived == nil {
+			reads = append(reads, key)
+		}
+	}
+	committed, err := m.getManyComponentBytes(ctx, reads)
+	if err != nil {
+		return StateDiff{}, err
+	}
+	for key, slot := range slots {
+		value, err := m.compValues.Get(key)
+		cached := err == nil
+		// A component that the entity kept, and that wasn't read or set, kept its committed value
+		if slot.hadBefore && slot.hasAfter && !cached {
+			continue
+		}
+		var oldValue, newValue json.RawMessage
+		if slot.hadBefore {
+			bz := slot.archived
+			if bz == nil {
+				bz = committed[key]
+			}
+			if oldValue, err = m.componentJSON(slot.cType, key.entityID, bz); err != nil {
+				return StateDiff{}, err
+			}
+		}
+		if slot.hasAfter && cached {
+			if newValue, err = json.Marshal(value); err != nil {
+				return StateDiff{}, eris.Wrapf(err, "failed to encode the value of component %q", slot.cType.Name())
+			}
+		} else if slot.hasAfter {
+			// A component that was added without setting it has its default value
+			if newValue, err = m.componentJSON(slot.cType, key.entityID, nil); err != nil {
+				return StateDiff{}, err
+			}
+		}
+		if bytes.Equal(oldValue, newValue) {
+			continue
+		}
+		diff.Changed = append(diff.Changed, ComponentChange{
+			Entity:    key.entityID,
+			Component: slot.cType.Name(),
+			Old:       oldValue,
+			New:       newValue,
+		})
+	}
+	sortStateDiff(&diff)
+	return diff, nil
+}
+
+// getManyComponentBytes fetches the committed values of the given components from storage in batches. Components that
+// were never set are missing from the returned map.
+func (m *EntityCommandBuffer) getManyComponentBytes(ctx context.Context, keys []compKey) (map[compKey][]byte, error) {
+	values := make(map[compKey][]byte, len(keys))
+	for len(keys) > 0 {
+		batch := keys[:min(len(keys), maxKeysPerRead)]
+		keys = keys[len(batch):]
+		storageKeys := make([]string, len(batch))
+		for i, key := range batch {
+			storageKeys[i] = storageComponentKey(key.typeID, key.entityID)
+		}
+		bzs, err := m.dbStorage.GetManyBytes(ctx, storageKeys)
+		if err != nil {
+			return nil, err
+		}
+		for i, bz := range bzs {
+			if bz != nil {
+				values[batch[i]] = bz
+			}
+		}
+	}
+	return values, nil
+}
+
+// componentJSON decodes a stored component value and encodes it as JSON, so that it doesn't depend on the codec of the
+// component. A nil value is the default value of the component. The value is stored for the given entity.
+func (m *EntityCommandBuffer) componentJSON(
+	cType types.ComponentMetadata, id types.EntityID, bz []byte,
+) (json.RawMessage, error) {
+	if bz == nil {
+		var err error
+		if bz, err = cType.New(); err != nil {
+			return nil, err
+		}
+	}
+	value, err := cType.DecodeFor(id, bz)
+	if err != nil {
+		return nil, err
+	}
+	jsonValue, err := json.Marshal(value)
+	if err != nil {
+		return nil, eris.Wrapf(err, "failed to encode the value of component %q", cType.Name())
+	}
+	return jsonValue, nil
+}
+
+func sortStateDiff(diff *StateDiff) {
+	slices.Sort(diff.Created)
+	slices.Sort(diff.Destroyed)
+	slices.SortFunc(diff.Changed, func(a, b ComponentChange) int {
+		return cmp.Or(cmp.Compare(a.Entity, b.Entity), strings.Compare(a.Component, b.Component))
+	})
+}
@@ -0,0 +1,121 @@
+package gamestate_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal/gamestate"
+	"pkg.world.dev/world-engine/cardinal/types"
+)
+
+func TestStateDiffsReportChangesBetweenTicks(t *testing.T) {
+	ctx := context.Background()
+	manager := newCmdBufferForTest(t)
+	manager.RecordStateDiffs(0)
+
+	// Tick 0 creates two entities, and an entity that is removed within the tick.
+	first, err := manager.CreateEntity(fooComp, barComp)
+	assert.NilError(t, err)
+	second, err := manager.CreateEntity(fooComp)
+	assert.NilError(t, err)
+	transient, err := manager.CreateEntity(fooComp)
+	assert.NilError(t, err)
+	assert.NilError(t, manager.SetComponentForEntity(fooComp, first, Foo{Value: 1}))
+	assert.NilError(t, manager.SetComponentForEntity(barComp, first, Bar{Value: 2}))
+	assert.NilError(t, manager.RemoveEntity(transient))
+	assert.NilError(t, manager.FinalizeTick(ctx))
+
+	// Tick 1 reads a component without changing it, changes another one and adds a component.
+	_, err = manager.GetComponentForEntity(fooComp, first)
+	assert.NilError(t, err)
+	assert.NilError(t, manager.SetComponentForEntity(barComp, first, Bar{Value: 5}))
+	assert.NilError(t, manager.AddComponentToEntity(barComp, second))
+	assert.NilError(t, manager.FinalizeTick(ctx))
+
+	// Tick 2 removes an entity.
+	assert.NilError(t, manager.SetComponentForEntity(fooComp, first, Foo{Value: 3}))
+	assert.NilError(t, manager.RemoveEntity(second))
+	assert.NilError(t, manager.FinalizeTick(ctx))
+
+	testCases := []struct {
+		from, to  uint64
+		created   []types.EntityID
+		destroyed []types.EntityID
+		changed   string
+	}{
+		{
+			from: 0, to: 1, created: []types.EntityID{first, second}, destroyed: []types.EntityID{},
+			changed: `[{"entity":0,"component":"bar","old":null,"new":{"Value":2}},` +
+				`{"entity":0,"component":"foo","old":null,"new":{"Value":1}},` +
+				`{"entity":1,"component":"foo","old":null,"new":{"Value":0}}]`,
+		},
+		{
+			from: 1, to: 2, created: []types.EntityID{}, destroyed: []types.EntityID{},
+			changed: `[{"entity":0,"component":"bar","old":{"Value":2},"new":{"Value":5}},` +
+				`{"entity":1,"component":"bar","old":null,"new":{"Value":0}}]`,
+		},
+		{
+			from: 1, to: 3, created: []types.EntityID{}, destroyed: []types.EntityID{second},
+			changed: `[{"entity":0,"component":"bar","old":{"Value":2},"new":{"Value":5}},` +
+				`{"entity":0,"component":"foo","old":{"Value":1},"new":{"Value":3}},` +
+				`{"entity":1,"component":"foo","old":{"Value":0},"new":null}]`,
+		},
+		{
+			// The entity that was created and destroyed within the range is left out.
+			from: 0, to: 3, created: []types.EntityID{first}, destroyed: []types.EntityID{},
+			changed: `[{"entity":0,"component":"bar","old":null,"new":{"Value":5}},` +
+				`{"entity":0,"component":"foo","old":null,"new":{"Value":3}}]`,
+		},
+		{
+			from: 2, to: 2, created: []types.EntityID{}, destroyed: []types.EntityID{}, changed: `[]`,
+		},
+	}
+	for _, tc := range testCases {
+		diff, err := manager.StateDiff(tc.from, tc.to)
+		assert.NilError(t, err)
+		assert.Equal(t, tc.from, diff.FromTick)
+		assert.Equal(t, tc.to, diff.ToTick)
+		assert.DeepEqual(t, tc.created, diff.Created)
+		assert.DeepEqual(t, tc.destroyed, diff.Destroyed)
+		changed, err := json.Marshal(diff.Changed)
+		assert.NilError(t, err)
+		assert.Equal(t, tc.changed, string(changed))
+	}
+
+	_, err = manager.StateDiff(2, 4)
+	assert.ErrorIs(t, err, gamestate.ErrStateDiffNotAvailable)
+	_, err = manager.StateDiff(2, 1)
+	assert.ErrorIs(t, err, gamestate.ErrInvalidStateDiffRange)
+}
+
+func TestStateDiffsAreDeletedAfterRetention(t *testing.T) {
+	ctx := context.Background()
+	manager := newCmdBufferForTest(t)
+	// The first tick completes before diffs are recorded.
+	id, err := manager.CreateEntity(fooComp)
+	assert.NilError(t, err)
+	assert.NilError(t, manager.FinalizeTick(ctx))
+	manager.RecordStateDiffs(2)
+	for i := 1; i <= 3; i++ {
+		assert.NilError(t, manager.SetComponentForEntity(fooComp, id, Foo{Value: i}))
+		assert.NilError(t, manager.FinalizeTick(ctx))
+	}
+
+	start, err := manager.GetStateDiffStart()
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(2), start)
+	_, err = manager.StateDiff(1, 4)
+	assert.ErrorIs(t, err, gamestate.ErrStateDiffNotAvailable)
+	diff, err := manager.StateDiff(2, 4)
+	assert.NilError(t, err)
+	assert.Equal(t, 1, len(diff.Changed))
+	assert.Equal(t, `{"Value":1}`, string(diff.Changed[0].Old))
+	assert.Equal(t, `{"Value":3}`, string(diff.Changed[0].New))
+
+	unrecorded := newCmdBufferForTest(t)
+	assert.NilError(t, unrecorded.FinalizeTick(ctx))
+	_, err = unrecorded.StateDiff(0, 1)
+	assert.ErrorIs(t, err, gamestate.ErrStateDiffNotAvailable)
+}
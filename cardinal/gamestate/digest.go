@@ -0,0 +1,174 @@
+package gamestate
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/binary"
+	"hash"
+	"slices"
+)
+
+// Sections of a PendingDigest.
+const (
+	DigestComponents = "components"
+	DigestEntities   = "entities"
+	DigestRawStorage = "raw storage"
+	DigestOutbox     = "outbox"
+)
+
+// PendingDigest is a digest of the pending state changes of an EntityCommandBuffer, keyed by the kind of change.
+type PendingDigest map[string][sha256.Size]byte
+
+// PendingDigest returns a digest of the pending state changes, e.g. to check that two runs of the same tick against the
+// same committed state make the same changes. The digest doesn't depend on the order in which the changes were made,
+// except where that order is persisted, e.g. in the order of the entities of an archetype. Component values that were
+// only read are part of the digest, since they are buffered like the values that were written.
+func (m *EntityCommandBuffer) PendingDigest() (PendingDigest, error) {
+	digest := PendingDigest{}
+	sections := []struct {
+		name   string
+		method func(h hash.Hash) error
+	}{
+		{DigestComponents, m.digestComponents},
+		{DigestEntities, m.digestEntities},
+		{DigestRawStorage, m.digestRawValues},
+		{DigestOutbox, m.digestOutbox},
+	}
+	for _, section := range sections {
+		h := sha256.New()
+		if err := section.method(h); err != nil {
+			return nil, err
+		}
+		digest[section.name] = [sha256.Size]byte(h.Sum(nil))
+	}
+	return digest, nil
+}
+
+func (m *EntityCommandBuffer) digestComponents(h hash.Hash) error {
+	keys, err := m.compValues.Keys()
+	if err != nil {
+		return err
+	}
+	slices.SortFunc(keys, compareCompKeys)
+	for _, key := range keys {
+		cType, err := m.typeToComponent.Get(key.typeID)
+		if err != nil {
+			return err
+		}
+		value, err := m.compValues.Get(key)
+		if err != nil {
+			return err
+		}
+		bz, err := cType.Encode(value)
+		if err != nil {
+			return err
+		}
+		writeUints(h, uint64(key.typeID), uint64(key.entityID))
+		writeBytes(h, bz)
+	}
+
+	deleted, err := m.compValuesToDelete.Keys()
+	if err != nil {
+		return err
+	}
+	slices.SortFunc(deleted, compareCompKeys)
+	for _, key := range deleted {
+		if isDeleted, err := m.compValuesToDelete.Get(key); err != nil {
+			return err
+		} else if isDeleted {
+			writeUints(h, uint64(key.typeID), uint64(key.entityID))
+		}
+	}
+	return nil
+}
+
+func (m *EntityCommandBuffer) digestEntities(h hash.Hash) error {
+	writeUints(h, m.pendingEntityIDs)
+	for _, archID := range m.pendingArchIDs {
+		writeUints(h, uint64(archID))
+	}
+
+	moved, err := m.entityIDToOriginArchID.Keys()
+	if err != nil {
+		return err
+	}
+	slices.Sort(moved)
+	for _, id := range moved {
+		archID, err := m.entityIDToArchID.Get(id)
+		if err != nil {
+			// The entity was removed
+			archID = doesNotExistArchetypeID
+		}
+		writeUints(h, uint64(id), uint64(archID))
+	}
+
+	archIDs, err := m.activeEntities.Keys()
+	if err != nil {
+		return err
+	}
+	slices.Sort(archIDs)
+	for _, archID := range archIDs {
+		active, err := m.activeEntities.Get(archID)
+		if err != nil {
+			return err
+		}
+		if !active.modified {
+			continue
+		}
+		writeUints(h, uint64(archID), uint64(len(active.ids)))
+		for _, id := range active.ids {
+			writeUints(h, uint64(id))
+		}
+	}
+	return nil
+}
+
+func (m *EntityCommandBuffer) digestRawValues(h hash.Hash) error {
+	keys, err := m.rawValues.Keys()
+	if err != nil {
+		return err
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		value, err := m.rawValues.Get(key)
+		if err != nil {
+			return err
+		}
+		writeBytes(h, []byte(key))
+		writeBytes(h, value)
+	}
+
+	deleted, err := m.rawValuesToDelete.Keys()
+	if err != nil {
+		return err
+	}
+	slices.Sort(deleted)
+	for _, key := range deleted {
+		writeBytes(h, []byte(key))
+	}
+	return nil
+}
+
+func (m *EntityCommandBuffer) digestOutbox(h hash.Hash) error {
+	for _, msg := range m.pendingOutbox {
+		writeBytes(h, []byte(msg.Contract))
+		writeBytes(h, msg.Payload)
+	}
+	return nil
+}
+
+func compareCompKeys(a, b compKey) int {
+	return cmp.Or(cmp.Compare(a.typeID, b.typeID), cmp.Compare(a.entityID, b.entityID))
+}
+
+func writeUints(h hash.Hash, values ...uint64) {
+	for _, v := range values {
+		_ = binary.Write(h, binary.BigEndian, v)
+	}
+}
+
+// writeBytes writes the length of the value before the value, so that consecutive values can't be confused.
+func writeBytes(h hash.Hash, value []byte) {
+	writeUints(h, uint64(len(value)))
+	_, _ = h.Write(value)
+}
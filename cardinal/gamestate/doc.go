@@ -54,17 +54,73 @@ value:	JSON serialized bytes that can be deserialized to a map of archetype.ID t
 what archetype IDs have already been assigned and what groups of components each archetype ID corresponds to. This field
 must be loaded into memory before any entity creation or component addition/removals take place.
 
+key:	fmt.Sprintf("ECB:RAW:%s", key)
+value:	Arbitrary bytes written through the raw storage API. The key always starts with the namespace chosen by the
+game (e.g. "ECB:RAW:pathfinding:grid-0"). Raw values are buffered and committed in the same atomic transaction as
+component data, so they are consistent with the rest of the state after a recovery.
+
+key:	fmt.Sprintf("ECB:INDEX:%s", key)
+value:	Bytes of an entry of an index that the engine maintains over components, e.g. the entity that has a key of a
+unique index. Like raw values, they are committed with the tick, but they don't count towards the raw storage quota.
+
+key:	fmt.Sprintf("ECB:INDEX-SET:%s", key)
+value:	A redis set of the members of an index entry, e.g. the IDs of the entities whose component has a value of a
+field index. Members are added and removed one at a time, so large sets are not rewritten on every change.
+
+key:	fmt.Sprintf("ECB:INPUT-ACK:%s", personaTag)
+value:	JSON serialized bytes of the last transaction of the persona that was applied: its hash, its nonce and the tick
+it was applied at. It is committed in the same transaction as the state changes of that tick, so the state of the tick
+always reflects the acknowledged input.
+
+key:	fmt.Sprintf("ECB:ARCHIVED-ENTITY:ENTITY-ID-%d", entityID)
+value:	JSON serialized bytes of an entity that was archived because it was not accessed for a while: its archetype ID and
+the JSON value of each of its components. An archived entity has no ECB:ARCHETYPE-ID and ECB:COMPONENT-VALUE keys and is
+not in any ECB:ACTIVE-ENTITY-IDS list. Accessing it by ID, or searching its archetype, moves it back into its archetype
+and deletes this key.
+
+key:	fmt.Sprintf("ECB:ARCHIVED-ENTITY-IDS:ARCHETYPE-ID-%d", archetypeID)
+value:	JSON serialized bytes of the IDs of the archived entities of the archetype. Counts of entities include them.
+
+key:	fmt.Sprintf("ECB:LAST-ACCESS:ENTITY-ID-%d", entityID)
+value:	The tick at which the entity was last accessed, when entities are archived. It is committed with the tick, so that
+the same entities are archived when ticks are replayed. Entities without this key were last accessed at the tick stored
+in ECB:LAST-ACCESS:START.
+
+key:	fmt.Sprintf("ECB:TICK-LOG:TICK-%d", tick)
+value:	Serialized bytes of the event log entry (events and receipts) for the matching tick. This key is only written when
+the event log is enabled. The entry is committed in the same transaction as the tick's state changes, so a tick that
+has completed always has a log entry.
+
+key:	fmt.Sprintf("ECB:STATE-DIFF:TICK-%d", tick)
+value:	JSON serialized bytes of the state diff of the matching tick: the entities that it created and destroyed, and the
+JSON value of every component that it changed, before and after the change. This key is only written when state diffs
+are recorded, in the same transaction as the tick's state changes. "ECB:STATE-DIFF:START" is the first tick whose diff
+has not been deleted.
+
 key: 	"ECB:START-TICK"
 value:  An integer that represents the last tick that was started.
 
 key: 	"ECB:END-TICK"
 value: 	An integer that represents the last tick that was successfully completed.
 
+key: 	"ECB:TICK-TIMESTAMP"
+value:  An integer that represents the UNIX timestamp of the last tick that was started. It is saved with the pending
+transactions so that an interrupted tick is run again with the same timestamp.
+
 key: 	"ECB:PENDING-TRANSACTIONS"
 value:  JSON serialized bytes that can be deserialized to a list of transactions. These are the transactions that were
 processed in the last started tick. This data is only relevant when the START-TICK number does not match the END-TICK
 number.
 
+key:	"ECB:CHECKPOINTS"
+value:	JSON serialized bytes that can be deserialized to a map of checkpoint names to the tick at which the checkpoint was
+saved and the list of keys that were copied into it.
+
+key:	fmt.Sprintf("ECB:CHECKPOINT:%s:%s", name, key)
+value:	The value that key had when the named checkpoint was saved. Every key that starts with "ECB:" is copied into a
+checkpoint, except for checkpoints themselves, tick log entries and state diffs. Restoring a checkpoint replaces all of those keys in
+a single transaction.
+
 # In-memory storage model
 
 The in-memory data model roughly matches the model that is stored in redis, but there are some differences:
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"sync"
 
 	"github.com/redis/go-redis/v9"
 	"github.com/rotisserie/eris"
@@ -22,6 +23,9 @@ var (
 	doesNotExistArchetypeID = types.ArchetypeID(-1)
 )
 
+// maxKeysPerRead is the maximum number of keys that are fetched from storage in a single batch.
+const maxKeysPerRead = 1000
+
 var _ Manager = &EntityCommandBuffer{}
 
 type EntityCommandBuffer struct {
@@ -44,6 +48,68 @@ type EntityCommandBuffer struct {
 
 	archIDToComps  VolatileStorage[types.ArchetypeID, []types.ComponentMetadata]
 	pendingArchIDs []types.ArchetypeID
+
+	// Raw key/value storage that lives alongside the ECS data. See raw.go.
+	rawValues         VolatileStorage[string, []byte]
+	rawValuesToDelete VolatileStorage[string, bool]
+	rawWrites         int
+	rawQuota          RawStorageQuota
+
+	// The changes of the index storage that will be committed with the current tick. A nil value deletes its key, and
+	// the members of a set are added if true and removed if false. See index.go.
+	pendingIndexValues  map[string][]byte
+	pendingIndexMembers map[string]map[string]bool
+
+	// cipher encrypts the values that hold game data outside of components before they are stored. It is nil unless
+	// SetEncryption is called.
+	cipher *codec.Cipher
+
+	// pendingTickLog is the event log entry that will be committed with the current tick. See ticklog.go.
+	pendingTickLog *tickLogEntry
+	// pendingTickLogPrune are the event log entries that will be deleted with the current tick.
+	pendingTickLogPrune *tickLogPrune
+
+	// recordStateDiffs makes every tick commit its state diff, and stateDiffsKept is the number of diffs that are kept.
+	// See diff.go.
+	recordStateDiffs bool
+	stateDiffsKept   uint64
+
+	// Messages to EVM contracts that will be committed with the current tick. See outbox.go.
+	pendingOutbox     []OutboxMessage
+	nextOutboxIDSaved uint64
+	isOutboxIDLoaded  bool
+
+	// The last input of each persona that was applied during the current tick. See input_ack.go.
+	pendingInputAcks map[string]InputAck
+
+	// tickStartWrites are added to the transaction that starts the next tick. See AddTickStartWrite.
+	tickStartWrites []func(ctx context.Context, pipe PrimitiveStorage[string]) error
+
+	// tickCtx is the context of the tick that is running. The storage operations of the tick use it, so that they
+	// respect its deadline and are cancelled with it. It is nil between ticks. See StartNextTick.
+	tickCtx context.Context
+
+	// Entities that were archived or rehydrated during the current tick, and the archived entities of each archetype.
+	// See archive.go.
+	pendingArchive   map[types.EntityID][]byte
+	pendingRehydrate map[types.EntityID]bool
+	archivedEntities VolatileStorage[types.ArchetypeID, activeEntities]
+	// trackAccess makes the entities that are accessed during the current tick recorded in touchedEntities.
+	trackAccess     bool
+	touchedEntities map[types.EntityID]bool
+	// lastAccess is the committed tick at which each entity in an archetype was last accessed, and accessClock is the
+	// current tick. lastAccess is nil until archival is used. The last accesses that will be committed with the current
+	// tick are in pendingLastAccess and lastAccessToDelete.
+	lastAccess           map[types.EntityID]uint64
+	accessClock          uint64
+	pendingLastAccess    map[types.EntityID]uint64
+	lastAccessToDelete   map[types.EntityID]bool
+	isAccessStartPending bool
+
+	// stateCommitmentMu is held while a state commitment is computed, and stateReads counts the state commitments that
+	// have not read their values yet. See commitment.go.
+	stateCommitmentMu sync.Mutex
+	stateReads        sync.WaitGroup
 }
 
 // NewEntityCommandBuffer creates a new command buffer manager that is able to queue up a series of states changes and
@@ -60,6 +126,21 @@ func NewEntityCommandBuffer(storage PrimitiveStorage[string]) (*EntityCommandBuf
 		entityIDToArchID:       NewMapStorage[types.EntityID, types.ArchetypeID](),
 		entityIDToOriginArchID: NewMapStorage[types.EntityID, types.ArchetypeID](),
 
+		rawValues:         NewMapStorage[string, []byte](),
+		rawValuesToDelete: NewMapStorage[string, bool](),
+		rawQuota:          DefaultRawStorageQuota,
+
+		pendingIndexValues:  map[string][]byte{},
+		pendingIndexMembers: map[string]map[string]bool{},
+
+		pendingArchive:     map[types.EntityID][]byte{},
+		pendingRehydrate:   map[types.EntityID]bool{},
+		archivedEntities:   NewMapStorage[types.ArchetypeID, activeEntities](),
+		touchedEntities:    map[types.EntityID]bool{},
+		pendingLastAccess:  map[types.EntityID]uint64{},
+		lastAccessToDelete: map[types.EntityID]bool{},
+		pendingInputAcks:   map[string]InputAck{},
+
 		// This field cannot be set until RegisterComponents is called
 		typeToComponent: nil,
 	}
@@ -85,6 +166,10 @@ func (m *EntityCommandBuffer) DiscardPending() error {
 	if err != nil {
 		return err
 	}
+	err = m.compValuesToDelete.Clear()
+	if err != nil {
+		return err
+	}
 
 	// Any entity archetypes movements need to be undone
 	err = m.activeEntities.Clear()
@@ -116,7 +201,16 @@ func (m *EntityCommandBuffer) DiscardPending() error {
 		}
 	}
 	m.pendingArchIDs = m.pendingArchIDs[:0]
-	return nil
+	m.pendingTickLog = nil
+	m.pendingTickLogPrune = nil
+	m.pendingOutbox = nil
+	m.isOutboxIDLoaded = false
+	clear(m.pendingInputAcks)
+	m.discardPendingIndexChanges()
+	if err = m.discardPendingArchiveChanges(); err != nil {
+		return err
+	}
+	return m.discardPendingRawValues()
 }
 
 // RemoveEntity removes the given entity from the ECS data model.
@@ -205,6 +299,7 @@ func (m *EntityCommandBuffer) CreateManyEntities(num int, comps ...types.Compone
 		}
 		active.ids = append(active.ids, currID)
 		active.modified = true
+		m.touchEntity(currID)
 		ecslog.Entity(&log.Logger, zerolog.DebugLevel, currID, archID, comps)
 	}
 	err = m.setActiveEntities(archID, active)
@@ -233,7 +328,7 @@ func (m *EntityCommandBuffer) SetComponentForEntity(
 
 // GetComponentForEntity returns the saved component data for the given entity.
 func (m *EntityCommandBuffer) GetComponentForEntity(cType types.ComponentMetadata, id types.EntityID) (any, error) {
-	ctx := context.Background()
+	ctx := m.ctx()
 	key := compKey{cType.ID(), id}
 	value, err := m.compValues.Get(key)
 	if err == nil {
@@ -247,28 +342,146 @@ func (m *EntityCommandBuffer) GetComponentForEntity(cType types.ComponentMetadat
 	if !filter.MatchComponentMetadata(comps, cType) {
 		return nil, eris.Wrap(iterators.ErrComponentNotOnEntity, "")
 	}
+	// Looking up the archetype of an archived entity rehydrates it with its archived component values
+	if value, err = m.compValues.Get(key); err == nil {
+		return value, nil
+	}
 
-	// Fetch the value from storage
-	redisKey := storageComponentKey(cType.ID(), id)
+	bz, err := m.getComponentBytes(ctx, cType, id)
+	if err != nil {
+		return nil, err
+	}
+	value, err = cType.DecodeFor(id, bz)
+	if err != nil {
+		return nil, err
+	}
+	return value, m.compValues.Set(key, value)
+}
 
-	bz, err := m.dbStorage.GetBytes(ctx, redisKey)
+// GetComponentFieldsForEntity returns the component of the entity with only the given fields decoded. Systems that
+// scan many entities but only read a few small fields of a large component use it to skip decoding the rest. The
+// partial value is not cached, since the next GetComponentForEntity must return the whole value; a value that was
+// already decoded in this tick is returned whole.
+func (m *EntityCommandBuffer) GetComponentFieldsForEntity(
+	cType types.ComponentMetadata, id types.EntityID, fields []string,
+) (any, error) {
+	comps, err := m.GetComponentTypesForEntity(id)
 	if err != nil {
-		// todo: this is redis specific, should be changed to a general error on storage
-		// todo: RedisStorage needs to be modified to return this general error when a redis.Nil is detected.
-		if !errors.Is(err, redis.Nil) {
-			return nil, err
+		return nil, err
+	}
+	if !filter.MatchComponentMetadata(comps, cType) {
+		return nil, eris.Wrap(iterators.ErrComponentNotOnEntity, "")
+	}
+	if value, err := m.compValues.Get(compKey{cType.ID(), id}); err == nil {
+		return value, nil
+	}
+	bz, err := m.getComponentBytes(m.ctx(), cType, id)
+	if err != nil {
+		return nil, err
+	}
+	return cType.DecodeFieldsFor(id, bz, fields)
+}
+
+// GetComponentsForArchID returns the component of each of the given entities, which must belong to the archetype. The
+// values that were not read or set in the current tick are fetched from storage in batches and cached.
+func (m *EntityCommandBuffer) GetComponentsForArchID(
+	cType types.ComponentMetadata, archID types.ArchetypeID, ids []types.EntityID,
+) ([]any, error) {
+	if err := m.checkArchetypeHasComponent(archID, cType); err != nil {
+		return nil, err
+	}
+	values := make([]any, len(ids))
+	var missing []int
+	for i, id := range ids {
+		m.touchEntity(id)
+		value, err := m.compValues.Get(compKey{cType.ID(), id})
+		if err != nil {
+			missing = append(missing, i)
+			continue
 		}
-		// This value has never been set. Make a default value.
-		bz, err = cType.New()
+		values[i] = value
+	}
+
+	ctx := m.ctx()
+	for len(missing) > 0 {
+		batch := missing[:min(len(missing), maxKeysPerRead)]
+		missing = missing[len(batch):]
+		keys := make([]string, len(batch))
+		for j, i := range batch {
+			keys[j] = storageComponentKey(cType.ID(), ids[i])
+		}
+		bzs, err := m.dbStorage.GetManyBytes(ctx, keys)
 		if err != nil {
 			return nil, err
 		}
+		for j, i := range batch {
+			bz := bzs[j]
+			if bz == nil {
+				// This value has never been set. Make a default value.
+				if bz, err = cType.New(); err != nil {
+					return nil, err
+				}
+			}
+			if values[i], err = cType.DecodeFor(ids[i], bz); err != nil {
+				return nil, err
+			}
+			if err = m.compValues.Set(compKey{cType.ID(), ids[i]}, values[i]); err != nil {
+				return nil, err
+			}
+		}
 	}
-	value, err = cType.Decode(bz)
+	return values, nil
+}
+
+// SetComponentsForArchID sets the component of each of the given entities, which must belong to the archetype, to
+// the value at the same index. Unlike SetComponentForEntity, the archetype is only checked once.
+func (m *EntityCommandBuffer) SetComponentsForArchID(
+	cType types.ComponentMetadata, archID types.ArchetypeID, ids []types.EntityID, values []any,
+) error {
+	if len(ids) != len(values) {
+		return eris.Errorf("got %d values for %d entities", len(values), len(ids))
+	}
+	if err := m.checkArchetypeHasComponent(archID, cType); err != nil {
+		return err
+	}
+	for i, id := range ids {
+		m.touchEntity(id)
+		if err := m.compValues.Set(compKey{cType.ID(), id}, values[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *EntityCommandBuffer) checkArchetypeHasComponent(
+	archID types.ArchetypeID, cType types.ComponentMetadata,
+) error {
+	comps, err := m.GetComponentTypesForArchID(archID)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	return value, m.compValues.Set(key, value)
+	if !filter.MatchComponentMetadata(comps, cType) {
+		return eris.Wrap(iterators.ErrComponentNotOnEntity, "")
+	}
+	return nil
+}
+
+// getComponentBytes fetches the encoded value of the component of the entity from storage, or the encoded default
+// value if the component was never set.
+func (m *EntityCommandBuffer) getComponentBytes(
+	ctx context.Context, cType types.ComponentMetadata, id types.EntityID,
+) ([]byte, error) {
+	bz, err := m.dbStorage.GetBytes(ctx, storageComponentKey(cType.ID(), id))
+	if err != nil {
+		// todo: this is redis specific, should be changed to a general error on storage
+		// todo: RedisStorage needs to be modified to return this general error when a redis.Nil is detected.
+		if !errors.Is(err, redis.Nil) {
+			return nil, err
+		}
+		// This value has never been set. Make a default value.
+		return cType.New()
+	}
+	return bz, nil
 }
 
 // GetComponentForEntityInRawJSON returns the saved component data as JSON encoded bytes for the given entity.
@@ -279,7 +492,7 @@ func (m *EntityCommandBuffer) GetComponentForEntityInRawJSON(cType types.Compone
 	if err != nil {
 		return nil, err
 	}
-	return cType.Encode(value)
+	return codec.Encode(value)
 }
 
 // AddComponentToEntity adds the given component to the given entity. An error is returned if the entity
@@ -391,8 +604,12 @@ func (m *EntityCommandBuffer) GetArchIDForComponents(components []types.Componen
 	return 0, eris.Wrap(ErrArchetypeNotFound, "")
 }
 
-// GetEntitiesForArchID returns all the entities that currently belong to the given archetype EntityID.
+// GetEntitiesForArchID returns all the entities that currently belong to the given archetype EntityID. Its archived
+// entities are rehydrated, so that they can be read and written along with the others.
 func (m *EntityCommandBuffer) GetEntitiesForArchID(archID types.ArchetypeID) ([]types.EntityID, error) {
+	if err := m.rehydrateArchetype(archID); err != nil {
+		return nil, err
+	}
 	active, err := m.getActiveEntities(archID)
 	if err != nil {
 		return nil, err
@@ -400,6 +617,20 @@ func (m *EntityCommandBuffer) GetEntitiesForArchID(archID types.ArchetypeID) ([]
 	return active.ids, nil
 }
 
+// CountEntitiesForArchID returns the number of entities of the archetype, including its archived entities, without
+// rehydrating them.
+func (m *EntityCommandBuffer) CountEntitiesForArchID(archID types.ArchetypeID) (int, error) {
+	active, err := m.getActiveEntities(archID)
+	if err != nil {
+		return 0, err
+	}
+	archived, err := m.getArchivedEntities(archID)
+	if err != nil {
+		return 0, err
+	}
+	return len(active.ids) + len(archived.ids), nil
+}
+
 // SearchFrom returns an ArchetypeIterator based on a component filter. The iterator will iterate over all archetypes
 // that match the given filter.
 func (m *EntityCommandBuffer) SearchFrom(filter filter.ComponentFilter, start int) *iterators.ArchetypeIterator {
@@ -436,14 +667,38 @@ func (m *EntityCommandBuffer) Close() error {
 	return err
 }
 
-// getArchetypeForEntity returns the archetype EntityID for the given entity EntityID.
+// ctx returns the context of the running tick, or the background context between ticks.
+func (m *EntityCommandBuffer) ctx() context.Context {
+	if m.tickCtx == nil {
+		return context.Background()
+	}
+	return m.tickCtx
+}
+
+// getArchetypeForEntity returns the archetype EntityID for the given entity EntityID. Archived entities are
+// rehydrated.
 func (m *EntityCommandBuffer) getArchetypeForEntity(id types.EntityID) (types.ArchetypeID, error) {
 	archID, err := m.entityIDToArchID.Get(id)
 	if err == nil {
+		m.touchEntity(id)
 		return archID, nil
 	}
+	// An entity that was archived during this tick is still in its archetype in storage
+	if _, ok := m.pendingArchive[id]; ok {
+		return m.rehydrateEntity(id)
+	}
+	// An entity that was removed during this tick is still in storage until the tick is committed
+	if _, err := m.entityIDToOriginArchID.Get(id); err == nil {
+		return 0, eris.Wrap(redis.Nil, iterators.ErrEntityDoesNotExist.Error())
+	}
 	key := storageArchetypeIDForEntityID(id)
-	num, err := m.dbStorage.GetInt(context.Background(), key)
+	num, err := m.dbStorage.GetInt(m.ctx(), key)
+	if errors.Is(err, redis.Nil) {
+		archID, err = m.rehydrateEntity(id)
+		if err == nil {
+			return archID, nil
+		}
+	}
 	if err != nil {
 		// todo: Make redis.Nil a general error on storage
 		if errors.Is(err, redis.Nil) {
@@ -456,6 +711,7 @@ func (m *EntityCommandBuffer) getArchetypeForEntity(id types.EntityID) (types.Ar
 	if err != nil {
 		return 0, err
 	}
+	m.touchEntity(id)
 	return archID, nil
 }
 
@@ -463,7 +719,7 @@ func (m *EntityCommandBuffer) getArchetypeForEntity(id types.EntityID) (types.Ar
 func (m *EntityCommandBuffer) nextEntityID() (types.EntityID, error) {
 	if !m.isEntityIDLoaded {
 		// The next valid entity EntityID needs to be loaded from dbStorage.
-		ctx := context.Background()
+		ctx := m.ctx()
 		nextID, err := m.dbStorage.GetUInt64(ctx, storageNextEntityIDKey())
 		err = eris.Wrap(err, "")
 		if err != nil {
@@ -515,7 +771,7 @@ func (m *EntityCommandBuffer) getActiveEntities(archID types.ArchetypeID) (activ
 	if err == nil {
 		return active, nil
 	}
-	ctx := context.Background()
+	ctx := m.ctx()
 	key := storageActiveEntityIDKey(archID)
 	bz, err := m.dbStorage.GetBytes(ctx, key)
 	err = eris.Wrap(err, "")
@@ -441,6 +441,21 @@ func TestEntityCanBeRemoved(t *testing.T) {
 	}
 }
 
+func TestCommittedEntityCannotBeReadAfterItIsRemoved(t *testing.T) {
+	ctx := context.Background()
+	manager := newCmdBufferForTest(t)
+
+	id, err := manager.CreateEntity(fooComp)
+	assert.NilError(t, err)
+	assert.NilError(t, manager.SetComponentForEntity(fooComp, id, Foo{Value: 1}))
+	assert.NilError(t, manager.FinalizeTick(ctx))
+
+	// The entity is still in storage until the tick is committed, but it must not be readable anymore.
+	assert.NilError(t, manager.RemoveEntity(id))
+	_, err = manager.GetComponentForEntity(fooComp, id)
+	assert.Check(t, err != nil)
+}
+
 func TestMovedEntitiesCanBeFoundInNewArchetype(t *testing.T) {
 	manager := newCmdBufferForTest(t)
 
@@ -0,0 +1,214 @@
+package gamestate
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal/codec"
+)
+
+// The index storage holds the entries of the indexes that the engine maintains over components. Like raw storage, its
+// changes are buffered and committed with the rest of the tick, but it is internal to the engine: its writes don't
+// count towards the raw storage quota, and its values are not limited in size. Besides values, it holds sets of
+// members, which are stored as redis sets, so that adding a member to a large set doesn't rewrite the whole set.
+
+// GetIndexValue returns the value stored at the given index key, including the changes buffered in the current tick.
+// The boolean is false if no value has been set.
+func (m *EntityCommandBuffer) GetIndexValue(key string) ([]byte, bool, error) {
+	if value, ok := m.pendingIndexValues[key]; ok {
+		return bytes.Clone(value), value != nil, nil
+	}
+	return getIndexValueFromStorage(m.ctx(), m.dbStorage, m.cipher, key)
+}
+
+// SetIndexValue sets the value for the given index key. Like raw values, it is only committed to the DB when the tick
+// is finalized.
+func (m *EntityCommandBuffer) SetIndexValue(key string, value []byte) error {
+	if key == "" {
+		return eris.New("index key must not be empty")
+	}
+	if value == nil {
+		value = []byte{}
+	}
+	m.pendingIndexValues[key] = bytes.Clone(value)
+	return nil
+}
+
+// DeleteIndexValue removes the value for the given index key when the tick is finalized.
+func (m *EntityCommandBuffer) DeleteIndexValue(key string) error {
+	if key == "" {
+		return eris.New("index key must not be empty")
+	}
+	// A nil value marks a deletion
+	m.pendingIndexValues[key] = nil
+	return nil
+}
+
+// GetIndexMembers returns the members of the set at the given index key, including the changes buffered in the
+// current tick, in ascending order.
+func (m *EntityCommandBuffer) GetIndexMembers(key string) ([]string, error) {
+	members, err := getIndexMembersFromStorage(m.ctx(), m.dbStorage, key)
+	if err != nil {
+		return nil, err
+	}
+	pending, ok := m.pendingIndexMembers[key]
+	if !ok {
+		return members, nil
+	}
+	members = slices.DeleteFunc(members, func(member string) bool {
+		added, changed := pending[member]
+		return changed && !added
+	})
+	for member, added := range pending {
+		if added && !slices.Contains(members, member) {
+			members = append(members, member)
+		}
+	}
+	slices.Sort(members)
+	return members, nil
+}
+
+// AddIndexMember adds the member to the set at the given index key when the tick is finalized.
+func (m *EntityCommandBuffer) AddIndexMember(key, member string) error {
+	return m.changeIndexMember(key, member, true)
+}
+
+// RemoveIndexMember removes the member from the set at the given index key when the tick is finalized. A set whose
+// last member is removed is deleted.
+func (m *EntityCommandBuffer) RemoveIndexMember(key, member string) error {
+	return m.changeIndexMember(key, member, false)
+}
+
+func (m *EntityCommandBuffer) changeIndexMember(key, member string, added bool) error {
+	if key == "" {
+		return eris.New("index key must not be empty")
+	}
+	pending, ok := m.pendingIndexMembers[key]
+	if !ok {
+		pending = map[string]bool{}
+		m.pendingIndexMembers[key] = pending
+	}
+	pending[member] = added
+	return nil
+}
+
+// FlushIndexes commits the buffered changes of the index storage on their own, e.g. to add the entities that existed
+// before an index was registered to it in batches. It must be called between ticks.
+func (m *EntityCommandBuffer) FlushIndexes(ctx context.Context) error {
+	if len(m.pendingIndexValues) == 0 && len(m.pendingIndexMembers) == 0 {
+		return nil
+	}
+	pipe, err := m.dbStorage.StartTransaction(ctx)
+	if err != nil {
+		return err
+	}
+	if err = m.addIndexChangesToPipe(ctx, pipe); err != nil {
+		return err
+	}
+	if err = pipe.EndTransaction(ctx); err != nil {
+		return eris.Wrap(err, "")
+	}
+	m.discardPendingIndexChanges()
+	return nil
+}
+
+// discardPendingIndexChanges drops all buffered index storage changes.
+func (m *EntityCommandBuffer) discardPendingIndexChanges() {
+	clear(m.pendingIndexValues)
+	clear(m.pendingIndexMembers)
+}
+
+// addIndexChangesToPipe adds the buffered index storage changes to the redis pipe, in the order of their keys.
+func (m *EntityCommandBuffer) addIndexChangesToPipe(ctx context.Context, pipe PrimitiveStorage[string]) error {
+	keys := make([]string, 0, len(m.pendingIndexValues))
+	for key := range m.pendingIndexValues {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		value := m.pendingIndexValues[key]
+		if value == nil {
+			if err := pipe.Delete(ctx, storageIndexKey(key)); err != nil {
+				return eris.Wrap(err, "")
+			}
+			continue
+		}
+		value, err := m.cipher.Seal(value, storageIndexKey(key))
+		if err != nil {
+			return err
+		}
+		if err = pipe.Set(ctx, storageIndexKey(key), value); err != nil {
+			return eris.Wrap(err, "")
+		}
+	}
+
+	keys = keys[:0]
+	for key := range m.pendingIndexMembers {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		var added, removed []string
+		for member, add := range m.pendingIndexMembers[key] {
+			if add {
+				added = append(added, member)
+			} else {
+				removed = append(removed, member)
+			}
+		}
+		slices.Sort(added)
+		slices.Sort(removed)
+		if len(removed) > 0 {
+			if err := pipe.RemoveMembers(ctx, storageIndexSetKey(key), removed...); err != nil {
+				return eris.Wrap(err, "")
+			}
+		}
+		if len(added) > 0 {
+			if err := pipe.AddMembers(ctx, storageIndexSetKey(key), added...); err != nil {
+				return eris.Wrap(err, "")
+			}
+		}
+	}
+	return nil
+}
+
+// GetIndexValue returns the committed value for the given index key.
+func (r *readOnlyManager) GetIndexValue(key string) ([]byte, bool, error) {
+	return getIndexValueFromStorage(context.Background(), r.storage, r.cipher, key)
+}
+
+// GetIndexMembers returns the committed members of the set at the given index key, in ascending order.
+func (r *readOnlyManager) GetIndexMembers(key string) ([]string, error) {
+	return getIndexMembersFromStorage(context.Background(), r.storage, key)
+}
+
+func getIndexValueFromStorage(
+	ctx context.Context, storage PrimitiveStorage[string], cipher *codec.Cipher, key string,
+) ([]byte, bool, error) {
+	bz, err := storage.GetBytes(ctx, storageIndexKey(key))
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	value, err := cipher.Open(bz, storageIndexKey(key))
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+func getIndexMembersFromStorage(ctx context.Context, storage PrimitiveStorage[string], key string) ([]string, error) {
+	members, err := storage.GetMembers(ctx, storageIndexSetKey(key))
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(members)
+	return members, nil
+}
@@ -0,0 +1,82 @@
+package gamestate
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal/codec"
+	"pkg.world.dev/world-engine/cardinal/types"
+)
+
+// InputAck is the last transaction of a persona that was applied, and the tick it was applied at. Clients that predict
+// the outcome of their inputs reconcile their prediction with the state of that tick.
+type InputAck struct {
+	PersonaTag string       `json:"personaTag"`
+	Tick       uint64       `json:"tick"`
+	TxHash     types.TxHash `json:"txHash"`
+	Nonce      uint64       `json:"nonce"`
+}
+
+// AckInputs buffers the given input acknowledgements. They replace the previous acknowledgement of their persona when
+// the tick is finalized.
+func (m *EntityCommandBuffer) AckInputs(acks []InputAck) error {
+	for _, ack := range acks {
+		if ack.PersonaTag == "" {
+			return eris.New("an input acknowledgement must have a persona tag")
+		}
+		m.pendingInputAcks[ack.PersonaTag] = ack
+	}
+	return nil
+}
+
+// GetInputAck returns the last input acknowledgement of the given persona, including the ones buffered in the current
+// tick. The boolean is false if no input of the persona was ever applied.
+func (m *EntityCommandBuffer) GetInputAck(personaTag string) (InputAck, bool, error) {
+	if ack, ok := m.pendingInputAcks[personaTag]; ok {
+		return ack, true, nil
+	}
+	return getInputAck(m.ctx(), m.dbStorage, personaTag)
+}
+
+// addInputAcksToPipe adds the buffered input acknowledgements to the redis pipe, in the order of their persona tags.
+func (m *EntityCommandBuffer) addInputAcksToPipe(ctx context.Context, pipe PrimitiveStorage[string]) error {
+	tags := make([]string, 0, len(m.pendingInputAcks))
+	for tag := range m.pendingInputAcks {
+		tags = append(tags, tag)
+	}
+	slices.Sort(tags)
+	for _, tag := range tags {
+		bz, err := codec.Encode(m.pendingInputAcks[tag])
+		if err != nil {
+			return err
+		}
+		if err := pipe.Set(ctx, storageInputAckKey(tag), bz); err != nil {
+			return eris.Wrap(err, "")
+		}
+	}
+	return nil
+}
+
+// GetInputAck returns the committed input acknowledgement of the given persona.
+func (r *readOnlyManager) GetInputAck(personaTag string) (InputAck, bool, error) {
+	return getInputAck(context.Background(), r.storage, personaTag)
+}
+
+func getInputAck(ctx context.Context, storage PrimitiveStorage[string], personaTag string) (InputAck, bool, error) {
+	bz, err := storage.GetBytes(ctx, storageInputAckKey(personaTag))
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return InputAck{}, false, nil
+		}
+		return InputAck{}, false, err
+	}
+	ack, err := codec.Decode[InputAck](bz)
+	if err != nil {
+		return InputAck{}, false, err
+	}
+	return ack, true, nil
+}
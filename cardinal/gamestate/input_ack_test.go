@@ -0,0 +1,40 @@
+package gamestate_test
+
+import (
+	"context"
+	"testing"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal/gamestate"
+)
+
+func TestInputAcksAreCommittedWithTheTick(t *testing.T) {
+	ctx := context.Background()
+	manager := newCmdBufferForTest(t)
+	ack := gamestate.InputAck{PersonaTag: "alice", Tick: 3, TxHash: "0xabc", Nonce: 7}
+	assert.NilError(t, manager.AckInputs([]gamestate.InputAck{ack}))
+
+	// The pending acknowledgement is visible to the tick, but not to readers of the committed state.
+	got, found, err := manager.GetInputAck("alice")
+	assert.NilError(t, err)
+	assert.Check(t, found)
+	assert.Equal(t, ack, got)
+	_, found, err = manager.ToReadOnly().GetInputAck("alice")
+	assert.NilError(t, err)
+	assert.Check(t, !found)
+
+	assert.NilError(t, manager.FinalizeTick(ctx))
+	got, found, err = manager.ToReadOnly().GetInputAck("alice")
+	assert.NilError(t, err)
+	assert.Check(t, found)
+	assert.Equal(t, ack, got)
+
+	// Discarded acknowledgements are never committed.
+	assert.NilError(t, manager.AckInputs([]gamestate.InputAck{{PersonaTag: "bob", Tick: 4}}))
+	assert.NilError(t, manager.DiscardPending())
+	_, found, err = manager.GetInputAck("bob")
+	assert.NilError(t, err)
+	assert.Check(t, !found)
+
+	assert.Check(t, manager.AckInputs([]gamestate.InputAck{{Tick: 4}}) != nil)
+}
@@ -6,6 +6,23 @@ import (
 	"pkg.world.dev/world-engine/cardinal/types"
 )
 
+const (
+	// storagePrefix is the prefix of every key that stores game state.
+	storagePrefix = "ECB:"
+	// storageTickLogPrefix is the prefix of the keys that store tick log entries.
+	storageTickLogPrefix = "ECB:TICK-LOG:"
+	// storageCheckpointPrefix is the prefix of the keys that store checkpoints, including the checkpoint index.
+	storageCheckpointPrefix = "ECB:CHECKPOINT"
+	// storageOutboxPrefix is the prefix of the keys that store the EVM outbox.
+	storageOutboxPrefix = "ECB:OUTBOX:"
+	// storageStateCommitmentPrefix is the prefix of the keys that store state commitments.
+	storageStateCommitmentPrefix = "ECB:STATE-COMMITMENT:"
+	// storageStateDiffPrefix is the prefix of the keys that store the state diffs of ticks.
+	storageStateDiffPrefix = "ECB:STATE-DIFF:"
+	// storageIndexSetPrefix is the prefix of the keys that store the sets of the index storage.
+	storageIndexSetPrefix = "ECB:INDEX-SET:"
+)
+
 // storageComponentKey is the key that maps an entity ID and a specific component ID to the value of that component.
 func storageComponentKey(typeID types.ComponentID, id types.EntityID) string {
 	return fmt.Sprintf("ECB:COMPONENT-VALUE:TYPE-ID-%d:ENTITY-ID-%d", typeID, id)
@@ -39,6 +56,12 @@ func storageArchIDsToCompTypesKey() string {
 	return "ECB:ARCHETYPE-ID-TO-COMPONENT-TYPES"
 }
 
+// storageMessageIDsKey is the key that stores the ID of every message that was ever registered, keyed by the full
+// name of the message.
+func storageMessageIDsKey() string {
+	return "ECB:MESSAGE-IDS"
+}
+
 func storageStartTickKey() string {
 	return "ECB:START-TICK"
 }
@@ -47,6 +70,111 @@ func storageEndTickKey() string {
 	return "ECB:END-TICK"
 }
 
+// storageTickLogKey is the key that stores the event log entry (events and receipts) of a completed tick.
+func storageTickLogKey(tick uint64) string {
+	return fmt.Sprintf(storageTickLogPrefix+"TICK-%d", tick)
+}
+
+// storageTickLogStartKey is the key that stores the first tick whose event log entry has not been pruned.
+func storageTickLogStartKey() string {
+	return storageTickLogPrefix + "START"
+}
+
+// storageTickTimestampKey is the key that stores the timestamp of the last tick that was started.
+func storageTickTimestampKey() string {
+	return "ECB:TICK-TIMESTAMP"
+}
+
 func storagePendingTransactionKey() string {
 	return "ECB:PENDING-TRANSACTIONS"
 }
+
+// storageRawKey is the key that stores an arbitrary value written through the raw storage API. The given key is
+// expected to already include the caller's namespace.
+func storageRawKey(key string) string {
+	return "ECB:RAW:" + key
+}
+
+// storageIndexKey is the key that stores a value of the index storage. See index.go.
+func storageIndexKey(key string) string {
+	return "ECB:INDEX:" + key
+}
+
+// storageIndexSetKey is the key of a set of the index storage. It is the only kind of game state key that doesn't hold
+// a string.
+func storageIndexSetKey(key string) string {
+	return storageIndexSetPrefix + key
+}
+
+// storageInputAckKey is the key that stores the last input of the given persona that was applied.
+func storageInputAckKey(personaTag string) string {
+	return "ECB:INPUT-ACK:" + personaTag
+}
+
+// storageCheckpointIndexKey is the key that stores the names of all saved checkpoints and the keys saved in each one.
+func storageCheckpointIndexKey() string {
+	return storageCheckpointPrefix + "S"
+}
+
+// storageCheckpointKey is the key that stores the value that the given key had when the named checkpoint was saved.
+func storageCheckpointKey(name, key string) string {
+	return storageCheckpointPrefix + ":" + name + ":" + key
+}
+
+// storageArchivedEntityKey is the key that stores an entity that was evicted from its archetype, along with all of its
+// component values.
+func storageArchivedEntityKey(id types.EntityID) string {
+	return fmt.Sprintf("ECB:ARCHIVED-ENTITY:ENTITY-ID-%d", id)
+}
+
+// storageArchivedEntityIDsKey is the key that stores the archived entities of the given archetype.
+func storageArchivedEntityIDsKey(archID types.ArchetypeID) string {
+	return fmt.Sprintf("ECB:ARCHIVED-ENTITY-IDS:ARCHETYPE-ID-%d", archID)
+}
+
+// storageLastAccessKey is the key that stores the tick at which the given entity was last accessed, when entities are
+// archived.
+func storageLastAccessKey(id types.EntityID) string {
+	return fmt.Sprintf("ECB:LAST-ACCESS:ENTITY-ID-%d", id)
+}
+
+// storageLastAccessStartKey is the key that stores the tick at which the last accesses of entities started to be
+// recorded. Entities without a last access were last accessed at that tick.
+func storageLastAccessStartKey() string {
+	return "ECB:LAST-ACCESS:START"
+}
+
+// storageOutboxMessageKey is the key that stores an outbox message that has not been acknowledged yet.
+func storageOutboxMessageKey(id uint64) string {
+	return fmt.Sprintf(storageOutboxPrefix+"MESSAGE-%d", id)
+}
+
+// storageOutboxNextIDKey is the key that stores the ID of the next message added to the outbox.
+func storageOutboxNextIDKey() string {
+	return storageOutboxPrefix + "NEXT-ID"
+}
+
+// storageOutboxAckedIDKey is the key that stores the ID up to which the base shard has acknowledged outbox messages.
+func storageOutboxAckedIDKey() string {
+	return storageOutboxPrefix + "ACKED-ID"
+}
+
+// storageStateCommitmentIndexKey is the key that stores the state commitments that can still be proven.
+func storageStateCommitmentIndexKey() string {
+	return storageStateCommitmentPrefix + "INDEX"
+}
+
+// storageStateCommitmentLeavesKey is the key that stores the leaves of the state commitment of the given end tick.
+func storageStateCommitmentLeavesKey(endTick uint64) string {
+	return fmt.Sprintf(storageStateCommitmentPrefix+"TICK-%d", endTick)
+}
+
+// storageStateDiffKey is the key that stores the state diff of a completed tick.
+func storageStateDiffKey(tick uint64) string {
+	return fmt.Sprintf(storageStateDiffPrefix+"TICK-%d", tick)
+}
+
+// storageStateDiffStartKey is the key that stores the first tick whose state diff has not been deleted.
+func storageStateDiffStartKey() string {
+	return storageStateDiffPrefix + "START"
+}
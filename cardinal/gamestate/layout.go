@@ -0,0 +1,178 @@
+package gamestate
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// WideArchetypeComponents is the number of components from which AnalyzeLayout reports an archetype as wide.
+const WideArchetypeComponents = 16
+
+// QueryPattern is a set of components that the searches of a system filter on together.
+type QueryPattern struct {
+	System string `json:"system"`
+	// Components are the names of the components that an archetype must have to match the searches, in name order.
+	Components []string `json:"components"`
+	// Searches is the number of times that searches with the pattern were evaluated.
+	Searches int `json:"searches"`
+	// Archetypes is the number of archetypes with entities that have all the components of the pattern, and Entities is
+	// the number of their entities. It is filled in by AnalyzeLayout.
+	Archetypes int `json:"archetypes"`
+	Entities   int `json:"entities"`
+}
+
+// ComponentLayout describes how the entities that have a component are spread over archetypes.
+type ComponentLayout struct {
+	Name string `json:"name"`
+	// Archetypes is the number of archetypes with entities that have the component. An entity is stored with all of
+	// its components, so a component that is spread over many archetypes is read from many places by a search.
+	Archetypes int `json:"archetypes"`
+	Entities   int `json:"entities"`
+	// Searches is the number of recorded searches that filter on the component.
+	Searches int `json:"searches"`
+}
+
+// ComponentGroup is a set of components that the recorded searches always filter on together, so that they could be
+// merged into one component, or kept in entities of their own.
+type ComponentGroup struct {
+	// Components are in name order.
+	Components []string `json:"components"`
+	// Searches is the number of recorded searches that filter on the components of the group.
+	Searches int `json:"searches"`
+}
+
+// LayoutReport describes the fragmentation of the game state into archetypes, and how the recorded searches use the
+// components. Archetypes whose entities were all removed are ignored.
+type LayoutReport struct {
+	Archetypes int `json:"archetypes"`
+	Entities   int `json:"entities"`
+	// MeanComponents is the mean number of components of an entity.
+	MeanComponents float64 `json:"meanComponents"`
+	// Components are ordered by the number of archetypes they are spread over, the most fragmented first.
+	Components []ComponentLayout `json:"components"`
+	// WideArchetypes are the archetypes with at least WideArchetypeComponents components, the widest first.
+	WideArchetypes []ArchetypeStat `json:"wideArchetypes"`
+	// Patterns are the recorded query patterns, ordered by the number of searches.
+	Patterns []QueryPattern `json:"patterns"`
+	// Groups are the suggested grouping of the components that are filtered on, ordered by the number of searches.
+	// Components that no entity has are left out, since there is nothing to regroup.
+	// Each group is the set of components that appear in exactly the same query patterns.
+	Groups []ComponentGroup `json:"groups"`
+	// Unqueried are the components of entities that no recorded search filters on. Moving them out of wide entities
+	// makes the archetypes that the searches match narrower.
+	Unqueried []string `json:"unqueried"`
+}
+
+// AnalyzeLayout reports the fragmentation of the archetypes, and suggests a grouping of components from the query
+// patterns. A pattern matches an archetype if the archetype has all of its components, which overestimates the matches
+// of patterns of searches with exact filters.
+func AnalyzeLayout(stats []ArchetypeStat, patterns []QueryPattern) LayoutReport {
+	report := LayoutReport{
+		Components:     []ComponentLayout{},
+		WideArchetypes: []ArchetypeStat{},
+		Patterns:       make([]QueryPattern, 0, len(patterns)),
+		Groups:         []ComponentGroup{},
+		Unqueried:      []string{},
+	}
+	components := map[string]*ComponentLayout{}
+	totalComponents := 0
+	for _, stat := range stats {
+		if stat.EntityCount == 0 {
+			continue
+		}
+		report.Archetypes++
+		report.Entities += stat.EntityCount
+		totalComponents += stat.EntityCount * len(stat.Components)
+		for _, name := range stat.Components {
+			c, ok := components[name]
+			if !ok {
+				c = &ComponentLayout{Name: name}
+				components[name] = c
+			}
+			c.Archetypes++
+			c.Entities += stat.EntityCount
+		}
+		if len(stat.Components) >= WideArchetypeComponents {
+			report.WideArchetypes = append(report.WideArchetypes, stat)
+		}
+	}
+	if report.Entities > 0 {
+		report.MeanComponents = float64(totalComponents) / float64(report.Entities)
+	}
+	slices.SortStableFunc(report.WideArchetypes, func(a, b ArchetypeStat) int {
+		return len(b.Components) - len(a.Components)
+	})
+
+	// signatures are the indexes of the patterns that filter on each component
+	signatures := map[string][]int{}
+	for i, pattern := range patterns {
+		for _, stat := range stats {
+			if stat.EntityCount > 0 && containsAll(stat.Components, pattern.Components) {
+				pattern.Archetypes++
+				pattern.Entities += stat.EntityCount
+			}
+		}
+		for _, name := range pattern.Components {
+			signatures[name] = append(signatures[name], i)
+			if c, ok := components[name]; ok {
+				c.Searches += pattern.Searches
+			}
+		}
+		report.Patterns = append(report.Patterns, pattern)
+	}
+	slices.SortStableFunc(report.Patterns, func(a, b QueryPattern) int {
+		return b.Searches - a.Searches
+	})
+
+	groups := map[string]*ComponentGroup{}
+	for name, signature := range signatures {
+		if _, ok := components[name]; !ok {
+			continue
+		}
+		key := fmt.Sprint(signature)
+		group, ok := groups[key]
+		if !ok {
+			group = &ComponentGroup{}
+			for _, i := range signature {
+				group.Searches += patterns[i].Searches
+			}
+			groups[key] = group
+		}
+		group.Components = append(group.Components, name)
+	}
+	for _, group := range groups {
+		slices.Sort(group.Components)
+		report.Groups = append(report.Groups, *group)
+	}
+	slices.SortFunc(report.Groups, func(a, b ComponentGroup) int {
+		if a.Searches != b.Searches {
+			return b.Searches - a.Searches
+		}
+		return strings.Compare(a.Components[0], b.Components[0])
+	})
+
+	for _, c := range components {
+		report.Components = append(report.Components, *c)
+		if len(signatures[c.Name]) == 0 {
+			report.Unqueried = append(report.Unqueried, c.Name)
+		}
+	}
+	slices.SortFunc(report.Components, func(a, b ComponentLayout) int {
+		if a.Archetypes != b.Archetypes {
+			return b.Archetypes - a.Archetypes
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	slices.Sort(report.Unqueried)
+	return report
+}
+
+func containsAll(components, wanted []string) bool {
+	for _, name := range wanted {
+		if !slices.Contains(components, name) {
+			return false
+		}
+	}
+	return true
+}
@@ -14,6 +14,9 @@ type Reader interface {
 	// One Component One Entity
 	GetComponentForEntity(cType types.ComponentMetadata, id types.EntityID) (any, error)
 	GetComponentForEntityInRawJSON(cType types.ComponentMetadata, id types.EntityID) (json.RawMessage, error)
+	// GetComponentFieldsForEntity returns the component of the entity with only the given fields decoded. A value that
+	// was already decoded in the current tick is returned whole.
+	GetComponentFieldsForEntity(cType types.ComponentMetadata, id types.EntityID, fields []string) (any, error)
 
 	// Many Components One Entity
 	GetComponentTypesForEntity(id types.EntityID) ([]types.ComponentMetadata, error)
@@ -23,7 +26,24 @@ type Reader interface {
 	GetArchIDForComponents(components []types.ComponentMetadata) (types.ArchetypeID, error)
 
 	// One Archetype Many Entities
+	// GetEntitiesForArchID returns the entities of the archetype, including its archived entities.
 	GetEntitiesForArchID(archID types.ArchetypeID) ([]types.EntityID, error)
+	// CountEntitiesForArchID returns the number of entities of the archetype, including its archived entities, without
+	// loading them.
+	CountEntitiesForArchID(archID types.ArchetypeID) (int, error)
+	// GetComponentsForArchID returns the component of each of the given entities, which must belong to the archetype.
+	// The values are fetched from storage in batches instead of one entity at a time.
+	GetComponentsForArchID(cType types.ComponentMetadata, archID types.ArchetypeID, ids []types.EntityID) ([]any, error)
+
+	// Raw Storage
+	GetRawValue(key string) ([]byte, error)
+
+	// Index Storage
+	GetIndexValue(key string) ([]byte, bool, error)
+	GetIndexMembers(key string) ([]string, error)
+
+	// Input Acknowledgements
+	GetInputAck(personaTag string) (InputAck, bool, error)
 
 	// Misc
 	SearchFrom(filter filter.ComponentFilter, start int) *iterators.ArchetypeIterator
@@ -43,22 +63,54 @@ type Writer interface {
 	AddComponentToEntity(cType types.ComponentMetadata, id types.EntityID) error
 	RemoveComponentFromEntity(cType types.ComponentMetadata, id types.EntityID) error
 
+	// One Component Many Entities
+	SetComponentsForArchID(
+		cType types.ComponentMetadata, archID types.ArchetypeID, ids []types.EntityID, values []any) error
+
+	// Raw Storage
+	SetRawValue(key string, value []byte) error
+	DeleteRawValue(key string) error
+	DiscardRawValue(key string) error
+
+	// Index Storage
+	SetIndexValue(key string, value []byte) error
+	DeleteIndexValue(key string) error
+	AddIndexMember(key, member string) error
+	RemoveIndexMember(key, member string) error
+	FlushIndexes(ctx context.Context) error
+
 	// Misc
 	Close() error
 	RegisterComponents([]types.ComponentMetadata) error
+	RegisterMessages([]types.Message) error
 }
 
 type TickStorage interface {
 	GetTickNumbers() (start, end uint64, err error)
-	StartNextTick(txs []types.Message, pool *txpool.TxPool) error
+	StartNextTick(ctx context.Context, txs []types.Message, pool *txpool.TxPool, timestamp uint64) error
+	AddTickStartWrite(write func(ctx context.Context, pipe PrimitiveStorage[string]) error)
+	GetTickTimestamp() (uint64, error)
 	FinalizeTick(ctx context.Context) error
 	Recover(txs []types.Message) (*txpool.TxPool, error)
+	SetTickLog(tick uint64, entry []byte) error
+	GetTickLog(tick uint64) ([]byte, error)
+	GetTickLogStart() (uint64, error)
+	PruneTickLog(before uint64) error
+	AckInputs(acks []InputAck) error
+}
+
+// OutboxStorage stores the messages that systems emit to EVM contracts until the base shard acknowledges them.
+type OutboxStorage interface {
+	EnqueueOutboxMessage(tick uint64, contract string, payload []byte) (OutboxMessage, error)
+	GetOutboxMessages(limit int) ([]OutboxMessage, error)
+	AckOutboxMessages(id uint64) error
 }
 
 // Manager represents all the methods required to track Component, Entity, and Archetype information
 // which powers the ECS dbStorage layer.
 type Manager interface {
 	TickStorage
+	OutboxStorage
 	Reader
 	Writer
 	ToReadOnly() Reader
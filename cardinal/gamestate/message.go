@@ -0,0 +1,59 @@
+package gamestate
+
+import (
+	"context"
+	"errors"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal/codec"
+	"pkg.world.dev/world-engine/cardinal/types"
+)
+
+var ErrMessageIDMismatch = errors.New("registered message IDs do not match with the saved state")
+
+// RegisterMessages checks that the registered messages have the IDs that were saved when the messages were first
+// registered, and saves the IDs of new messages. Message IDs are assigned in registration order, and transactions
+// that were sequenced by the base shard refer to messages by ID, so a message must never change its ID, and the ID of
+// a message that is no longer registered must never be reused by another message.
+func (m *EntityCommandBuffer) RegisterMessages(msgs []types.Message) error {
+	ctx := context.Background()
+	saved := map[string]types.MessageID{}
+	bz, err := m.dbStorage.GetBytes(ctx, storageMessageIDsKey())
+	if err == nil {
+		if saved, err = codec.Decode[map[string]types.MessageID](bz); err != nil {
+			return err
+		}
+	} else if !errors.Is(err, redis.Nil) {
+		return err
+	}
+
+	savedNames := make(map[types.MessageID]string, len(saved))
+	for name, id := range saved {
+		savedNames[id] = name
+	}
+	changed := false
+	for _, msg := range msgs {
+		name, id := msg.FullName(), msg.ID()
+		if savedID, ok := saved[name]; ok && savedID != id {
+			return eris.Wrapf(ErrMessageIDMismatch, "message %q has ID %d, but was saved with ID %d; "+
+				"register the messages in their original order", name, id, savedID)
+		}
+		if savedName, ok := savedNames[id]; ok && savedName != name {
+			return eris.Wrapf(ErrMessageIDMismatch, "message %q has ID %d, which was saved for message %q; "+
+				"register the messages in their original order", name, id, savedName)
+		}
+		if _, ok := saved[name]; !ok {
+			saved[name], savedNames[id] = id, name
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	if bz, err = codec.Encode(saved); err != nil {
+		return err
+	}
+	return eris.Wrap(m.dbStorage.Set(ctx, storageMessageIDsKey(), bz), "")
+}
@@ -0,0 +1,120 @@
+package gamestate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rotisserie/eris"
+)
+
+// OutboxMessage is a message that a system emitted to an EVM contract. Messages are committed with the tick that
+// emitted them, and stay in the outbox until the base shard acknowledges them, so each message is delivered at least
+// once even if Cardinal or the base shard restarts in between.
+type OutboxMessage struct {
+	// ID increases by one for every message, so the base shard can acknowledge all messages up to an ID at once and
+	// drop messages it has already received.
+	ID       uint64
+	Tick     uint64
+	Contract string
+	Payload  []byte
+}
+
+// EnqueueOutboxMessage buffers a message to the given EVM contract. The message is committed to the DB in the same
+// transaction as the rest of the tick's state changes when FinalizeTick is called.
+func (m *EntityCommandBuffer) EnqueueOutboxMessage(tick uint64, contract string, payload []byte) (OutboxMessage, error) {
+	if !m.isOutboxIDLoaded {
+		nextID, err := m.loadOutboxCounter(m.ctx(), storageOutboxNextIDKey())
+		if err != nil {
+			return OutboxMessage{}, err
+		}
+		// IDs start at 1, so that an acknowledged ID of 0 means that no message was acknowledged.
+		m.nextOutboxIDSaved = max(nextID, 1)
+		m.isOutboxIDLoaded = true
+	}
+	msg := OutboxMessage{
+		ID:       m.nextOutboxIDSaved + uint64(len(m.pendingOutbox)),
+		Tick:     tick,
+		Contract: contract,
+		Payload:  append([]byte(nil), payload...),
+	}
+	m.pendingOutbox = append(m.pendingOutbox, msg)
+	return msg, nil
+}
+
+// GetOutboxMessages returns up to limit committed messages that have not been acknowledged yet, oldest first.
+func (m *EntityCommandBuffer) GetOutboxMessages(limit int) ([]OutboxMessage, error) {
+	ctx := m.ctx()
+	nextID, err := m.loadOutboxCounter(ctx, storageOutboxNextIDKey())
+	if err != nil {
+		return nil, err
+	}
+	ackedID, err := m.loadOutboxCounter(ctx, storageOutboxAckedIDKey())
+	if err != nil {
+		return nil, err
+	}
+	var msgs []OutboxMessage
+	for id := ackedID + 1; id < nextID && len(msgs) < limit; id++ {
+		bz, err := m.dbStorage.GetBytes(ctx, storageOutboxMessageKey(id))
+		if errors.Is(err, redis.Nil) {
+			continue
+		} else if err != nil {
+			return nil, eris.Wrap(err, "")
+		}
+		var msg OutboxMessage
+		if err = json.Unmarshal(bz, &msg); err != nil {
+			return nil, eris.Wrapf(err, "failed to unmarshal outbox message %d", id)
+		}
+		msgs = append(msgs, msg)
+	}
+	return msgs, nil
+}
+
+// AckOutboxMessages removes all messages up to and including the given ID from the outbox. Acknowledging an ID that
+// is lower than a previously acknowledged ID has no effect.
+func (m *EntityCommandBuffer) AckOutboxMessages(id uint64) error {
+	ctx := m.ctx()
+	ackedID, err := m.loadOutboxCounter(ctx, storageOutboxAckedIDKey())
+	if err != nil {
+		return err
+	}
+	if id <= ackedID {
+		return nil
+	}
+	// Messages are never written outside FinalizeTick, so these deletes can't conflict with a tick in progress.
+	for msgID := ackedID + 1; msgID <= id; msgID++ {
+		if err = m.dbStorage.Delete(ctx, storageOutboxMessageKey(msgID)); err != nil {
+			return eris.Wrap(err, "")
+		}
+	}
+	return eris.Wrap(m.dbStorage.Set(ctx, storageOutboxAckedIDKey(), id), "")
+}
+
+func (m *EntityCommandBuffer) loadOutboxCounter(ctx context.Context, key string) (uint64, error) {
+	value, err := m.dbStorage.GetUInt64(ctx, key)
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	} else if err != nil {
+		return 0, eris.Wrap(err, "")
+	}
+	return value, nil
+}
+
+// addOutboxToPipe adds the messages emitted during the tick (if any) to the redis pipe.
+func (m *EntityCommandBuffer) addOutboxToPipe(ctx context.Context, pipe PrimitiveStorage[string]) error {
+	if len(m.pendingOutbox) == 0 {
+		return nil
+	}
+	for _, msg := range m.pendingOutbox {
+		bz, err := json.Marshal(msg)
+		if err != nil {
+			return eris.Wrap(err, "")
+		}
+		if err = pipe.Set(ctx, storageOutboxMessageKey(msg.ID), bz); err != nil {
+			return eris.Wrap(err, "")
+		}
+	}
+	nextID := m.pendingOutbox[len(m.pendingOutbox)-1].ID + 1
+	return eris.Wrap(pipe.Set(ctx, storageOutboxNextIDKey(), nextID), "")
+}
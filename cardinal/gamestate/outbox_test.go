@@ -0,0 +1,51 @@
+package gamestate_test
+
+import (
+	"context"
+	"testing"
+
+	"pkg.world.dev/world-engine/assert"
+)
+
+func TestOutboxMessagesAreDeliveredUntilAcknowledged(t *testing.T) {
+	manager, client := newCmdBufferAndRedisClientForTest(t, nil)
+	ctx := context.Background()
+
+	// Messages are not visible until the tick that emitted them is finalized.
+	first, err := manager.EnqueueOutboxMessage(1, "0xabc", []byte("first"))
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(1), first.ID)
+	msgs, err := manager.GetOutboxMessages(10)
+	assert.NilError(t, err)
+	assert.Len(t, msgs, 0)
+
+	// Discarded messages don't use up IDs.
+	assert.NilError(t, manager.DiscardPending())
+	first, err = manager.EnqueueOutboxMessage(1, "0xabc", []byte("first"))
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(1), first.ID)
+	_, err = manager.EnqueueOutboxMessage(1, "0xdef", []byte("second"))
+	assert.NilError(t, err)
+	assert.NilError(t, manager.FinalizeTick(ctx))
+
+	// A fresh command buffer on the same DB continues where the last one stopped.
+	manager, _ = newCmdBufferAndRedisClientForTest(t, client)
+	third, err := manager.EnqueueOutboxMessage(2, "0xabc", []byte("third"))
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(3), third.ID)
+	assert.NilError(t, manager.FinalizeTick(ctx))
+
+	msgs, err = manager.GetOutboxMessages(2)
+	assert.NilError(t, err)
+	assert.Len(t, msgs, 2)
+	assert.DeepEqual(t, first, msgs[0])
+	assert.Equal(t, "0xdef", msgs[1].Contract)
+
+	assert.NilError(t, manager.AckOutboxMessages(2))
+	// Stale acknowledgements are ignored.
+	assert.NilError(t, manager.AckOutboxMessages(1))
+	msgs, err = manager.GetOutboxMessages(10)
+	assert.NilError(t, err)
+	assert.Len(t, msgs, 1)
+	assert.DeepEqual(t, third, msgs[0])
+}
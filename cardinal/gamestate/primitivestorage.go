@@ -14,11 +14,22 @@ type PrimitiveStorage[K comparable] interface {
 	GetInt(ctx context.Context, key K) (int, error)
 	GetBool(ctx context.Context, key K) (bool, error)
 	GetBytes(ctx context.Context, key K) ([]byte, error)
+	// GetManyBytes returns the values of the keys in a single round trip. The value of a key that is not set is nil.
+	GetManyBytes(ctx context.Context, keys []K) ([][]byte, error)
 	Get(ctx context.Context, key K) (any, error)
 	Set(ctx context.Context, key K, value any) error
 	Incr(ctx context.Context, key K) error
 	Decr(ctx context.Context, key K) error
 	Delete(ctx context.Context, key K) error
+	// DeleteFields deletes the given fields of the hash stored at the key.
+	DeleteFields(ctx context.Context, key K, fields ...string) error
+	// GetMembers returns the members of the set stored at the key, in no particular order. A key that is not set is an
+	// empty set.
+	GetMembers(ctx context.Context, key K) ([]string, error)
+	// AddMembers adds the given members to the set stored at the key.
+	AddMembers(ctx context.Context, key K, members ...string) error
+	// RemoveMembers removes the given members from the set stored at the key, and deletes the set if it becomes empty.
+	RemoveMembers(ctx context.Context, key K, members ...string) error
 	StartTransaction(ctx context.Context) (Transaction[K], error)
 	EndTransaction(ctx context.Context) error
 	Close(ctx context.Context) error
@@ -0,0 +1,171 @@
+package gamestate
+
+import (
+	"bytes"
+	"context"
+	"errors"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal/codec"
+)
+
+var (
+	ErrRawValueNotFound        = errors.New("raw storage value not found")
+	ErrRawStorageQuotaExceeded = errors.New("raw storage quota exceeded")
+
+	// DefaultRawStorageQuota is the quota used by an EntityCommandBuffer unless SetRawStorageQuota is called.
+	DefaultRawStorageQuota = RawStorageQuota{
+		MaxKeyLength:     256,       //nolint:gomnd // default limit
+		MaxValueSize:     64 * 1024, //nolint:gomnd // default limit
+		MaxWritesPerTick: 10_000,    //nolint:gomnd // default limit
+	}
+)
+
+// RawStorageQuota limits how the raw storage API can be used in a single tick. A zero value for any field
+// disables that particular limit.
+type RawStorageQuota struct {
+	// MaxKeyLength is the maximum length (in bytes) of a single key, including its namespace.
+	MaxKeyLength int
+	// MaxValueSize is the maximum size (in bytes) of a single value.
+	MaxValueSize int
+	// MaxWritesPerTick is the maximum number of sets and deletes that can be performed in a single tick.
+	MaxWritesPerTick int
+}
+
+// SetEncryption encrypts the game data that is stored outside of components with the given keys: raw storage values,
+// archived entities, tick log entries, which hold the events of the ticks, and the leaves of state commitments. Values
+// that were stored before encryption was enabled can still be read. Components are encrypted by their codec, see
+// codec.Encrypted.
+func (m *EntityCommandBuffer) SetEncryption(keys codec.KeyProvider) {
+	m.cipher = codec.NewCipher(keys)
+}
+
+// SetRawStorageQuota replaces the quota that is enforced on raw storage writes.
+func (m *EntityCommandBuffer) SetRawStorageQuota(quota RawStorageQuota) {
+	m.rawQuota = quota
+}
+
+// GetRawValue returns the value stored at the given raw key. Pending writes made during the current tick are
+// visible. ErrRawValueNotFound is returned if no value has been set.
+func (m *EntityCommandBuffer) GetRawValue(key string) ([]byte, error) {
+	if _, err := m.rawValuesToDelete.Get(key); err == nil {
+		return nil, eris.Wrap(ErrRawValueNotFound, key)
+	}
+	if value, err := m.rawValues.Get(key); err == nil {
+		return bytes.Clone(value), nil
+	}
+	return getRawValueFromStorage(m.ctx(), m.dbStorage, m.cipher, key)
+}
+
+// SetRawValue sets the value for the given raw key. The change is buffered along with all other state changes and
+// is only committed to the DB when the tick is finalized.
+func (m *EntityCommandBuffer) SetRawValue(key string, value []byte) error {
+	if err := m.checkRawQuota(key, len(value)); err != nil {
+		return err
+	}
+	m.rawWrites++
+	if err := m.rawValuesToDelete.Delete(key); err != nil {
+		return err
+	}
+	return m.rawValues.Set(key, bytes.Clone(value))
+}
+
+// DeleteRawValue removes the value for the given raw key. Like SetRawValue, the deletion is only committed to the DB
+// when the tick is finalized.
+func (m *EntityCommandBuffer) DeleteRawValue(key string) error {
+	if err := m.checkRawQuota(key, 0); err != nil {
+		return err
+	}
+	m.rawWrites++
+	if err := m.rawValues.Delete(key); err != nil {
+		return err
+	}
+	return m.rawValuesToDelete.Set(key, true)
+}
+
+// DiscardRawValue drops the change buffered for the given raw key in the current tick, so that the key has its
+// committed value again, e.g. to undo writes that belong to entities whose creation failed. Discarding is not a write:
+// it doesn't count towards the raw storage quota, but the writes it drops still do.
+func (m *EntityCommandBuffer) DiscardRawValue(key string) error {
+	if err := m.rawValues.Delete(key); err != nil {
+		return err
+	}
+	return m.rawValuesToDelete.Delete(key)
+}
+
+func (m *EntityCommandBuffer) checkRawQuota(key string, valueSize int) error {
+	if key == "" {
+		return eris.New("raw storage key must not be empty")
+	}
+	q := m.rawQuota
+	if q.MaxKeyLength > 0 && len(key) > q.MaxKeyLength {
+		return eris.Wrapf(ErrRawStorageQuotaExceeded, "key %q is longer than %d bytes", key, q.MaxKeyLength)
+	}
+	if q.MaxValueSize > 0 && valueSize > q.MaxValueSize {
+		return eris.Wrapf(ErrRawStorageQuotaExceeded, "value for key %q is larger than %d bytes", key, q.MaxValueSize)
+	}
+	if q.MaxWritesPerTick > 0 && m.rawWrites >= q.MaxWritesPerTick {
+		return eris.Wrapf(ErrRawStorageQuotaExceeded, "more than %d raw writes in a single tick", q.MaxWritesPerTick)
+	}
+	return nil
+}
+
+// discardPendingRawValues drops all buffered raw storage changes.
+func (m *EntityCommandBuffer) discardPendingRawValues() error {
+	m.rawWrites = 0
+	if err := m.rawValues.Clear(); err != nil {
+		return err
+	}
+	return m.rawValuesToDelete.Clear()
+}
+
+// addRawValueChangesToPipe adds the buffered raw storage sets and deletes to the redis pipe.
+func (m *EntityCommandBuffer) addRawValueChangesToPipe(ctx context.Context, pipe PrimitiveStorage[string]) error {
+	keysToDelete, err := m.rawValuesToDelete.Keys()
+	if err != nil {
+		return err
+	}
+	for _, key := range keysToDelete {
+		if err := pipe.Delete(ctx, storageRawKey(key)); err != nil {
+			return eris.Wrap(err, "")
+		}
+	}
+	keys, err := m.rawValues.Keys()
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		value, err := m.rawValues.Get(key)
+		if err != nil {
+			return err
+		}
+		if value, err = m.cipher.Seal(value, storageRawKey(key)); err != nil {
+			return err
+		}
+		if err := pipe.Set(ctx, storageRawKey(key), value); err != nil {
+			return eris.Wrap(err, "")
+		}
+	}
+	return nil
+}
+
+// GetRawValue returns the committed value for the given raw key.
+func (r *readOnlyManager) GetRawValue(key string) ([]byte, error) {
+	return getRawValueFromStorage(context.Background(), r.storage, r.cipher, key)
+}
+
+func getRawValueFromStorage(
+	ctx context.Context, storage PrimitiveStorage[string], cipher *codec.Cipher, key string,
+) ([]byte, error) {
+	bz, err := storage.GetBytes(ctx, storageRawKey(key))
+	if err != nil {
+		// todo: make redis.Nil a general error on storage.
+		if errors.Is(err, redis.Nil) {
+			return nil, eris.Wrap(ErrRawValueNotFound, key)
+		}
+		return nil, err
+	}
+	return cipher.Open(bz, storageRawKey(key))
+}
@@ -0,0 +1,68 @@
+package gamestate_test
+
+import (
+	"context"
+	"testing"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal/gamestate"
+)
+
+func TestRawValuesAreOnlyCommittedOnFinalizeTick(t *testing.T) {
+	manager, client := newCmdBufferAndRedisClientForTest(t, nil)
+	ctx := context.Background()
+
+	_, err := manager.GetRawValue("ns:key")
+	assert.ErrorIs(t, err, gamestate.ErrRawValueNotFound)
+
+	assert.NilError(t, manager.SetRawValue("ns:key", []byte("hello")))
+	got, err := manager.GetRawValue("ns:key")
+	assert.NilError(t, err)
+	assert.Equal(t, "hello", string(got))
+
+	// Discarding the pending changes should drop the raw write.
+	assert.NilError(t, manager.DiscardPending())
+	_, err = manager.GetRawValue("ns:key")
+	assert.ErrorIs(t, err, gamestate.ErrRawValueNotFound)
+
+	assert.NilError(t, manager.SetRawValue("ns:key", []byte("world")))
+	assert.NilError(t, manager.FinalizeTick(ctx))
+
+	// A fresh command buffer on the same DB should see the committed value.
+	manager, _ = newCmdBufferAndRedisClientForTest(t, client)
+	got, err = manager.GetRawValue("ns:key")
+	assert.NilError(t, err)
+	assert.Equal(t, "world", string(got))
+
+	assert.NilError(t, manager.DeleteRawValue("ns:key"))
+	_, err = manager.GetRawValue("ns:key")
+	assert.ErrorIs(t, err, gamestate.ErrRawValueNotFound)
+	assert.NilError(t, manager.FinalizeTick(ctx))
+
+	_, err = manager.ToReadOnly().GetRawValue("ns:key")
+	assert.ErrorIs(t, err, gamestate.ErrRawValueNotFound)
+}
+
+func TestRawStorageQuotaIsEnforced(t *testing.T) {
+	manager := newCmdBufferForTest(t)
+	manager.SetRawStorageQuota(gamestate.RawStorageQuota{
+		MaxKeyLength:     8,
+		MaxValueSize:     4,
+		MaxWritesPerTick: 2,
+	})
+
+	err := manager.SetRawValue("too-long-key", []byte("a"))
+	assert.ErrorIs(t, err, gamestate.ErrRawStorageQuotaExceeded)
+
+	err = manager.SetRawValue("key", []byte("too big"))
+	assert.ErrorIs(t, err, gamestate.ErrRawStorageQuotaExceeded)
+
+	assert.NilError(t, manager.SetRawValue("a", []byte("1")))
+	assert.NilError(t, manager.SetRawValue("b", []byte("2")))
+	err = manager.SetRawValue("c", []byte("3"))
+	assert.ErrorIs(t, err, gamestate.ErrRawStorageQuotaExceeded)
+
+	// The write budget is reset at the end of the tick.
+	assert.NilError(t, manager.FinalizeTick(context.Background()))
+	assert.NilError(t, manager.SetRawValue("c", []byte("3")))
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 
+	"github.com/redis/go-redis/v9"
 	"github.com/rotisserie/eris"
 
 	"pkg.world.dev/world-engine/cardinal/codec"
@@ -23,6 +24,7 @@ type readOnlyManager struct {
 	storage         PrimitiveStorage[string]
 	typeToComponent VolatileStorage[types.ComponentID, types.ComponentMetadata]
 	archIDToComps   VolatileStorage[types.ArchetypeID, []types.ComponentMetadata]
+	cipher          *codec.Cipher
 }
 
 func (m *EntityCommandBuffer) ToReadOnly() Reader {
@@ -30,6 +32,7 @@ func (m *EntityCommandBuffer) ToReadOnly() Reader {
 		storage:         m.dbStorage,
 		typeToComponent: m.typeToComponent,
 		archIDToComps:   m.archIDToComps,
+		cipher:          m.cipher,
 	}
 }
 
@@ -50,19 +53,91 @@ func (r *readOnlyManager) refreshArchIDToCompTypes() error {
 func (r *readOnlyManager) GetComponentForEntity(
 	cType types.ComponentMetadata, id types.EntityID,
 ) (any, error) {
-	bz, err := r.GetComponentForEntityInRawJSON(cType, id)
+	bz, err := r.getComponentBytes(cType, id)
 	if err != nil {
 		return nil, err
 	}
-	return cType.Decode(bz)
+	return cType.DecodeFor(id, bz)
 }
 
+func (r *readOnlyManager) GetComponentFieldsForEntity(
+	cType types.ComponentMetadata, id types.EntityID, fields []string,
+) (any, error) {
+	bz, err := r.getComponentBytes(cType, id)
+	if err != nil {
+		return nil, err
+	}
+	return cType.DecodeFieldsFor(id, bz, fields)
+}
+
+// GetComponentForEntityInRawJSON returns the component of an entity as JSON, also if the component is stored with
+// another codec.
 func (r *readOnlyManager) GetComponentForEntityInRawJSON(
 	cType types.ComponentMetadata, id types.EntityID,
 ) (json.RawMessage, error) {
+	bz, err := r.getComponentBytes(cType, id)
+	if err != nil || codec.IsJSON(bz) {
+		return bz, err
+	}
+	value, err := cType.DecodeFor(id, bz)
+	if err != nil {
+		return nil, err
+	}
+	return codec.Encode(value)
+}
+
+func (r *readOnlyManager) GetComponentsForArchID(
+	cType types.ComponentMetadata, archID types.ArchetypeID, ids []types.EntityID,
+) ([]any, error) {
+	comps, err := r.getComponentsForArchID(archID)
+	if err != nil {
+		return nil, err
+	}
+	if !filter.MatchComponentMetadata(comps, cType) {
+		return nil, eris.Wrap(iterators.ErrComponentNotOnEntity, "")
+	}
+	ctx := context.Background()
+	values := make([]any, 0, len(ids))
+	for len(values) < len(ids) {
+		batch := ids[len(values):min(len(ids), len(values)+maxKeysPerRead)]
+		keys := make([]string, len(batch))
+		for i, id := range batch {
+			keys[i] = storageComponentKey(cType.ID(), id)
+		}
+		bzs, err := r.storage.GetManyBytes(ctx, keys)
+		if err != nil {
+			return nil, err
+		}
+		for i, bz := range bzs {
+			if bz == nil {
+				// The entity may be archived.
+				if bz, err = r.getComponentBytes(cType, batch[i]); err != nil {
+					return nil, err
+				}
+			}
+			value, err := cType.DecodeFor(batch[i], bz)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+		}
+	}
+	return values, nil
+}
+
+// getComponentBytes returns the component of an entity as it is stored.
+func (r *readOnlyManager) getComponentBytes(cType types.ComponentMetadata, id types.EntityID) ([]byte, error) {
 	ctx := context.Background()
 	key := storageComponentKey(cType.ID(), id)
 	res, err := r.storage.GetBytes(ctx, key)
+	if errors.Is(err, redis.Nil) {
+		// Archived entities are read from the archive, since a read-only manager can't rehydrate them.
+		if archived, archErr := getArchivedEntity(ctx, r.storage, r.cipher, id); archErr == nil {
+			if bz, ok := archived.Components[cType.ID()]; ok {
+				return bz, nil
+			}
+		}
+	}
 	return res, eris.Wrap(err, "")
 }
 
@@ -85,6 +160,11 @@ func (r *readOnlyManager) GetComponentTypesForEntity(id types.EntityID) ([]types
 
 	archIDKey := storageArchetypeIDForEntityID(id)
 	num, err := r.storage.GetInt(ctx, archIDKey)
+	if errors.Is(err, redis.Nil) {
+		if archived, archErr := getArchivedEntity(ctx, r.storage, r.cipher, id); archErr == nil {
+			return r.getComponentsForArchID(archived.ArchID)
+		}
+	}
 	if err != nil {
 		return nil, eris.Wrap(err, "")
 	}
@@ -134,21 +214,40 @@ func (r *readOnlyManager) GetArchIDForComponents(
 	return 0, eris.New("arch EntityID for components not found")
 }
 
+// GetEntitiesForArchID returns the entities of the archetype, followed by its archived entities.
 func (r *readOnlyManager) GetEntitiesForArchID(archID types.ArchetypeID) ([]types.EntityID, error) {
 	ctx := context.Background()
-	key := storageActiveEntityIDKey(archID)
-	bz, err := r.storage.GetBytes(ctx, key)
+	keys := []string{storageActiveEntityIDKey(archID), storageArchivedEntityIDsKey(archID)}
+	bzs, err := r.storage.GetManyBytes(ctx, keys)
 	if err != nil {
+		return nil, err
+	}
+	if bzs[0] == nil && bzs[1] == nil {
 		// No entities were found for this archetype EntityID
-		return nil, eris.Wrap(err, "")
+		return nil, eris.Wrap(redis.Nil, "")
 	}
-	ids, err := codec.Decode[[]types.EntityID](bz)
-	if err != nil {
-		return nil, err
+	var ids []types.EntityID
+	for _, bz := range bzs {
+		if bz == nil {
+			continue
+		}
+		part, err := codec.Decode[[]types.EntityID](bz)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, part...)
 	}
 	return ids, nil
 }
 
+func (r *readOnlyManager) CountEntitiesForArchID(archID types.ArchetypeID) (int, error) {
+	ids, err := r.GetEntitiesForArchID(archID)
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return 0, err
+	}
+	return len(ids), nil
+}
+
 func (r *readOnlyManager) SearchFrom(filter filter.ComponentFilter, start int) *iterators.ArchetypeIterator {
 	itr := &iterators.ArchetypeIterator{}
 	if err := r.refreshArchIDToCompTypes(); err != nil {
@@ -2,20 +2,32 @@ package gamestate
 
 import (
 	"context"
+	"errors"
 
 	"github.com/redis/go-redis/v9"
 	"github.com/rotisserie/eris"
-	"gopkg.in/DataDog/dd-trace-go.v1/ddtrace/tracer"
 
 	"pkg.world.dev/world-engine/cardinal/codec"
 	"pkg.world.dev/world-engine/cardinal/iterators"
+	"pkg.world.dev/world-engine/cardinal/tracing"
 	"pkg.world.dev/world-engine/cardinal/types"
 )
 
 var _ PrimitiveStorage[string] = &RedisStorage{}
 
+// ErrFenced is returned when a transaction of a fenced storage is committed after the key of its fence no longer holds
+// the value of the fence. Nothing is written then. See RedisStorage.Fence.
+var ErrFenced = errors.New("the fence of the storage no longer holds")
+
 type RedisStorage struct {
 	currentClient redis.Cmdable
+	// fence makes transactions only commit while its key holds its value. It is nil unless Fence was called.
+	fence *fence
+}
+
+type fence struct {
+	key   string
+	value string
 }
 
 func (r *RedisStorage) GetFloat64(ctx context.Context, key string) (float64, error) {
@@ -72,6 +84,27 @@ func (r *RedisStorage) GetBytes(ctx context.Context, key string) ([]byte, error)
 	return bz, nil
 }
 
+func (r *RedisStorage) GetManyBytes(ctx context.Context, keys []string) ([][]byte, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	res, err := r.currentClient.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, eris.Wrap(err, "")
+	}
+	values := make([][]byte, len(res))
+	for i, v := range res {
+		switch v := v.(type) {
+		case nil:
+		case string:
+			values[i] = []byte(v)
+		default:
+			return nil, eris.Errorf("unexpected value of type %T for key %q", v, keys[i])
+		}
+	}
+	return values, nil
+}
+
 func (r *RedisStorage) Set(ctx context.Context, key string, value any) error {
 	return eris.Wrap(r.currentClient.Set(ctx, key, value, 0).Err(), "")
 }
@@ -96,6 +129,26 @@ func (r *RedisStorage) Delete(ctx context.Context, key string) error {
 	return eris.Wrap(r.currentClient.Del(ctx, key).Err(), "")
 }
 
+func (r *RedisStorage) DeleteFields(ctx context.Context, key string, fields ...string) error {
+	return eris.Wrap(r.currentClient.HDel(ctx, key, fields...).Err(), "")
+}
+
+func (r *RedisStorage) GetMembers(ctx context.Context, key string) ([]string, error) {
+	members, err := r.currentClient.SMembers(ctx, key).Result()
+	if err != nil {
+		return nil, eris.Wrap(err, "")
+	}
+	return members, nil
+}
+
+func (r *RedisStorage) AddMembers(ctx context.Context, key string, members ...string) error {
+	return eris.Wrap(r.currentClient.SAdd(ctx, key, toArgs(members)...).Err(), "")
+}
+
+func (r *RedisStorage) RemoveMembers(ctx context.Context, key string, members ...string) error {
+	return eris.Wrap(r.currentClient.SRem(ctx, key, toArgs(members)...).Err(), "")
+}
+
 func (r *RedisStorage) Close(ctx context.Context) error {
 	return eris.Wrap(r.currentClient.Shutdown(ctx).Err(), "")
 }
@@ -108,7 +161,18 @@ func (r *RedisStorage) Clear(ctx context.Context) error {
 	return eris.Wrap(r.currentClient.FlushAll(ctx).Err(), "")
 }
 
+// Fence makes the transactions of the storage only commit while the given key holds the given value, e.g. so that a
+// world instance that lost the ownership of its namespace can't commit a tick after another instance took over. The
+// value is compared in the same script that applies the writes, so a transaction whose fence changes during the commit
+// fails too, while a change of the expiration of the key, e.g. the renewal of a lease, doesn't affect it.
+func (r *RedisStorage) Fence(key, value string) {
+	r.fence = &fence{key: key, value: value}
+}
+
 func (r *RedisStorage) StartTransaction(_ context.Context) (Transaction[string], error) {
+	if r.fence != nil {
+		return &fencedTransaction{RedisStorage: *r}, nil
+	}
 	pipeline := r.currentClient.TxPipeline()
 	redisTransaction := NewRedisPrimitiveStorage(pipeline)
 	return &redisTransaction, nil
@@ -123,6 +187,99 @@ func (r *RedisStorage) EndTransaction(ctx context.Context) error {
 	return eris.Wrap(err, "")
 }
 
+// fencedWriteScript applies the writes of a fenced transaction if the key of the fence, KEYS[1], holds the value of the
+// fence, ARGV[1]. Every other key is written by a command whose name and number of arguments precede its arguments in
+// ARGV. Nothing is written, and 0 is returned, if the fence no longer holds.
+var fencedWriteScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) ~= ARGV[1] then
+	return 0
+end
+local arg = 2
+for i = 2, #KEYS do
+	local n = tonumber(ARGV[arg + 1])
+	redis.call(ARGV[arg], KEYS[i], unpack(ARGV, arg + 2, arg + 1 + n))
+	arg = arg + 2 + n
+end
+return 1
+`)
+
+// fencedTransaction holds the writes of a transaction of a fenced storage until it is committed, since they are applied
+// by the script that checks the fence.
+type fencedTransaction struct {
+	RedisStorage
+	keys []string
+	args []any
+}
+
+func (t *fencedTransaction) Set(_ context.Context, key string, value any) error {
+	return t.write("SET", key, value)
+}
+
+func (t *fencedTransaction) Incr(_ context.Context, key string) error {
+	return t.write("INCR", key)
+}
+
+func (t *fencedTransaction) Decr(_ context.Context, key string) error {
+	return t.write("DECR", key)
+}
+
+func (t *fencedTransaction) Delete(_ context.Context, key string) error {
+	return t.write("DEL", key)
+}
+
+func (t *fencedTransaction) DeleteFields(_ context.Context, key string, fields ...string) error {
+	return t.writeBatches("HDEL", key, fields)
+}
+
+func (t *fencedTransaction) AddMembers(_ context.Context, key string, members ...string) error {
+	return t.writeBatches("SADD", key, members)
+}
+
+func (t *fencedTransaction) RemoveMembers(_ context.Context, key string, members ...string) error {
+	return t.writeBatches("SREM", key, members)
+}
+
+// writeBatches writes a command whose arguments are the given strings, splitting them over several commands. The
+// script unpacks the arguments of a command onto the stack of the Lua interpreter, which is limited.
+func (t *fencedTransaction) writeBatches(cmd, key string, args []string) error {
+	for len(args) > 0 {
+		batch := args[:min(len(args), maxKeysPerRead)]
+		args = args[len(batch):]
+		if err := t.write(cmd, key, toArgs(batch)...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *fencedTransaction) write(cmd, key string, args ...any) error {
+	t.keys = append(t.keys, key)
+	t.args = append(append(t.args, cmd, len(args)), args...)
+	return nil
+}
+
+// EndTransaction atomically applies the writes of the transaction, if the key of the fence holds its value.
+func (t *fencedTransaction) EndTransaction(ctx context.Context) error {
+	keys := append([]string{t.fence.key}, t.keys...)
+	args := append([]any{t.fence.value}, t.args...)
+	applied, err := fencedWriteScript.Run(ctx, t.currentClient, keys, args...).Int()
+	if err != nil {
+		return eris.Wrap(err, "")
+	}
+	if applied == 0 {
+		return eris.Wrapf(ErrFenced, "key %q no longer holds %q", t.fence.key, t.fence.value)
+	}
+	return nil
+}
+
+func toArgs(values []string) []any {
+	args := make([]any, len(values))
+	for i, value := range values {
+		args[i] = value
+	}
+	return args
+}
+
 func NewRedisPrimitiveStorage(client redis.Cmdable) RedisStorage {
 	return RedisStorage{
 		currentClient: client,
@@ -146,21 +303,27 @@ func (m *EntityCommandBuffer) makePipeOfRedisCommands(ctx context.Context) (Prim
 		name   string
 		method func(ctx context.Context, pipe PrimitiveStorage[string]) error
 	}{
+		{"state_diff", m.addStateDiffToPipe},
 		{"component_changes", m.addComponentChangesToPipe},
 		{"next_entity_id", m.addNextEntityIDToPipe},
 		{"pending_arch_ids", m.addPendingArchIDsToPipe},
 		{"entity_id_to_arch_id", m.addEntityIDToArchIDToPipe},
 		{"active_entity_ids", m.addActiveEntityIDsToPipe},
+		{"raw_values", m.addRawValueChangesToPipe},
+		{"indexes", m.addIndexChangesToPipe},
+		{"input_acks", m.addInputAcksToPipe},
+		{"tick_log", m.addTickLogToPipe},
+		{"outbox", m.addOutboxToPipe},
+		{"archive", m.addArchiveChangesToPipe},
 	}
 
 	for _, operation := range operations {
-		var pipeSpan tracer.Span
-		pipeSpan, ctx = tracer.StartSpanFromContext(ctx, "tick.span."+operation.name)
-		if err := operation.method(ctx, pipe); err != nil {
-			pipeSpan.Finish(tracer.WithError(err))
+		opCtx, span := tracing.Tracer().Start(ctx, "cardinal.storage."+operation.name)
+		err := operation.method(opCtx, pipe)
+		tracing.End(span, err)
+		if err != nil {
 			return nil, eris.Wrapf(err, "failed to run step %q", operation.name)
 		}
-		pipeSpan.Finish()
 	}
 	return pipe, nil
 }
@@ -246,7 +409,7 @@ func (m *EntityCommandBuffer) addComponentChangesToPipe(ctx context.Context, pip
 		if err != nil {
 			return err
 		}
-		bz, err := cType.Encode(value)
+		bz, err := cType.EncodeFor(key.entityID, value)
 		if err != nil {
 			return err
 		}
@@ -269,7 +432,9 @@ func (m *EntityCommandBuffer) loadArchIDs() error {
 		// Nothing is saved in the DB. Leave the m.archIDToComps field unchanged
 		return nil
 	}
-	if m.archIDToComps.Len() > 0 {
+	// The saved mapping includes every archetype that was already committed, e.g. when a standby that served reads
+	// takes the lead, so only archetypes that are not saved yet would be lost.
+	if len(m.pendingArchIDs) > 0 {
 		return eris.New("assigned archetype ArchetypeID is about to be overwritten by something from dbStorage")
 	}
 	m.archIDToComps = archIDToComps
@@ -7,6 +7,7 @@ package gamestate
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/alicebob/miniredis/v2"
 	"github.com/redis/go-redis/v9"
@@ -82,3 +83,68 @@ func TestComponentValuesAreDeletedFromRedis(t *testing.T) {
 	err = client.Get(ctx, key).Err()
 	assert.ErrorIs(t, err, redis.Nil)
 }
+
+func TestFencedStorageOnlyCommitsWhileTheFenceHolds(t *testing.T) {
+	ctx := context.Background()
+	s := miniredis.RunT(t)
+	store := NewRedisPrimitiveStorage(redis.NewClient(&redis.Options{Addr: s.Addr()}))
+	store.Fence("OWNER", "instance-1")
+	assert.NilError(t, s.Set("OWNER", "instance-1"))
+
+	pipe, err := store.StartTransaction(ctx)
+	assert.NilError(t, err)
+	assert.NilError(t, pipe.Set(ctx, "a", 1))
+	assert.NilError(t, pipe.Incr(ctx, "b"))
+	assert.NilError(t, pipe.EndTransaction(ctx))
+	value, err := store.GetInt(ctx, "a")
+	assert.NilError(t, err)
+	assert.Equal(t, 1, value)
+
+	// Another instance took over, so nothing is written.
+	assert.NilError(t, s.Set("OWNER", "instance-2"))
+	pipe, err = store.StartTransaction(ctx)
+	assert.NilError(t, err)
+	assert.NilError(t, pipe.Set(ctx, "a", 2))
+	assert.NilError(t, pipe.Delete(ctx, "b"))
+	assert.ErrorIs(t, pipe.EndTransaction(ctx), ErrFenced)
+	value, err = store.GetInt(ctx, "a")
+	assert.NilError(t, err)
+	assert.Equal(t, 1, value)
+	assert.Check(t, s.Exists("b"))
+}
+
+func TestFencedStorageCommitsWhileTheFenceIsRenewed(t *testing.T) {
+	ctx := context.Background()
+	s := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	store := NewRedisPrimitiveStorage(client)
+	store.Fence("OWNER", "instance-1")
+	assert.NilError(t, s.Set("OWNER", "instance-1"))
+
+	// The owner renews the lease on its key while it commits.
+	stop, stopped := make(chan struct{}), make(chan struct{})
+	go func() {
+		defer close(stopped)
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				assert.Check(t, client.PExpire(ctx, "OWNER", time.Minute).Err())
+			}
+		}
+	}()
+	const commits = 200
+	for i := 0; i < commits; i++ {
+		pipe, err := store.StartTransaction(ctx)
+		assert.NilError(t, err)
+		assert.NilError(t, pipe.Incr(ctx, "ticks"))
+		assert.NilError(t, pipe.DeleteFields(ctx, "queue", "a", "b"))
+		assert.NilError(t, pipe.EndTransaction(ctx))
+	}
+	close(stop)
+	<-stopped
+	value, err := store.GetInt(ctx, "ticks")
+	assert.NilError(t, err)
+	assert.Equal(t, commits, value)
+}
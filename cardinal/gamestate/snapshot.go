@@ -0,0 +1,139 @@
+package gamestate
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal/codec"
+	"pkg.world.dev/world-engine/cardinal/search/filter"
+	"pkg.world.dev/world-engine/cardinal/types"
+)
+
+// maxSnapshotAttempts is the number of times Snapshot reads the state before it gives up because a tick was committed
+// during every read.
+const maxSnapshotAttempts = 5
+
+var ErrSnapshotConflict = errors.New("ticks were committed during every attempt to snapshot the state")
+
+// SnapshotEntity is an entity of a snapshot, with the JSON value of each of its components keyed by component name.
+type SnapshotEntity struct {
+	ID         types.EntityID             `json:"id"`
+	Components map[string]json.RawMessage `json:"components" swaggertype:"object"`
+}
+
+// Snapshot is the committed game state at the start of Tick, i.e. after the ticks before Tick completed. Applying
+// StateDiff(Tick, t) to a snapshot gives the state at the start of tick t. The entities are sorted by ID.
+type Snapshot struct {
+	Tick     uint64           `json:"tick"`
+	Entities []SnapshotEntity `json:"entities"`
+}
+
+// Snapshot returns the committed state of the entities that have all of the given components, with only the values of
+// those components. Without components, every entity is returned whole. Like searches, the snapshot doesn't include
+// archived entities.
+//
+// The state is read from storage without stopping the ticks. A tick that is committed while the state is read makes
+// the snapshot start over, so that it never mixes the state of two ticks; ErrSnapshotConflict is returned if that
+// happens on every attempt.
+func (m *EntityCommandBuffer) Snapshot(components []types.ComponentMetadata) (Snapshot, error) {
+	ctx := context.Background()
+	for attempt := 0; attempt < maxSnapshotAttempts; attempt++ {
+		_, before, err := m.GetTickNumbers()
+		if err != nil {
+			return Snapshot{}, err
+		}
+		entities, err := m.readSnapshotEntities(ctx, components)
+		if err != nil {
+			return Snapshot{}, err
+		}
+		_, after, err := m.GetTickNumbers()
+		if err != nil {
+			return Snapshot{}, err
+		}
+		if before == after {
+			return Snapshot{Tick: after, Entities: entities}, nil
+		}
+	}
+	return Snapshot{}, eris.Wrapf(ErrSnapshotConflict, "%d attempts", maxSnapshotAttempts)
+}
+
+// readSnapshotEntities reads the entities of a snapshot from storage. It only reads committed state, so it doesn't
+// depend on the tick that may be running.
+func (m *EntityCommandBuffer) readSnapshotEntities(
+	ctx context.Context, components []types.ComponentMetadata,
+) ([]SnapshotEntity, error) {
+	entities := []SnapshotEntity{}
+	archIDToComps, ok, err := getArchIDToCompTypesFromRedis(m.dbStorage, m.typeToComponent)
+	if err != nil || !ok {
+		return entities, err
+	}
+	archIDs, err := archIDToComps.Keys()
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(archIDs)
+	for _, archID := range archIDs {
+		archComps, err := archIDToComps.Get(archID)
+		if err != nil {
+			return nil, err
+		}
+		selected := archComps
+		if len(components) > 0 {
+			if slices.ContainsFunc(components, func(c types.ComponentMetadata) bool {
+				return !filter.MatchComponentMetadata(archComps, c)
+			}) {
+				continue
+			}
+			selected = components
+		}
+		ids, err := m.getCommittedEntitiesForArchID(ctx, archID)
+		if err != nil {
+			return nil, err
+		}
+		keys := make([]compKey, 0, len(ids)*len(selected))
+		for _, id := range ids {
+			for _, cType := range selected {
+				keys = append(keys, compKey{cType.ID(), id})
+			}
+		}
+		values, err := m.getManyComponentBytes(ctx, keys)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range ids {
+			entity := SnapshotEntity{ID: id, Components: make(map[string]json.RawMessage, len(selected))}
+			for _, cType := range selected {
+				// A component that was added without setting it has its default value
+				value, err := m.componentJSON(cType, id, values[compKey{cType.ID(), id}])
+				if err != nil {
+					return nil, err
+				}
+				entity.Components[cType.Name()] = value
+			}
+			entities = append(entities, entity)
+		}
+	}
+	slices.SortFunc(entities, func(a, b SnapshotEntity) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+	return entities, nil
+}
+
+// getCommittedEntitiesForArchID returns the committed list of the entities of the given archetype.
+func (m *EntityCommandBuffer) getCommittedEntitiesForArchID(
+	ctx context.Context, archID types.ArchetypeID,
+) ([]types.EntityID, error) {
+	bz, err := m.dbStorage.GetBytes(ctx, storageActiveEntityIDKey(archID))
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return codec.Decode[[]types.EntityID](bz)
+}
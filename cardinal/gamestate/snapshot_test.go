@@ -0,0 +1,44 @@
+package gamestate_test
+
+import (
+	"context"
+	"testing"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal/types"
+)
+
+func TestSnapshotReturnsTheCommittedState(t *testing.T) {
+	ctx := context.Background()
+	manager := newCmdBufferForTest(t)
+	first, err := manager.CreateEntity(fooComp, barComp)
+	assert.NilError(t, err)
+	second, err := manager.CreateEntity(fooComp)
+	assert.NilError(t, err)
+	assert.NilError(t, manager.SetComponentForEntity(fooComp, first, Foo{Value: 1}))
+	assert.NilError(t, manager.SetComponentForEntity(barComp, first, Bar{Value: 2}))
+	assert.NilError(t, manager.FinalizeTick(ctx))
+
+	// Pending changes are not part of the snapshot.
+	_, err = manager.CreateEntity(fooComp)
+	assert.NilError(t, err)
+	assert.NilError(t, manager.SetComponentForEntity(fooComp, second, Foo{Value: 3}))
+
+	snapshot, err := manager.Snapshot(nil)
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(1), snapshot.Tick)
+	assert.Equal(t, 2, len(snapshot.Entities))
+	assert.Equal(t, first, snapshot.Entities[0].ID)
+	assert.Equal(t, `{"Value":1}`, string(snapshot.Entities[0].Components["foo"]))
+	assert.Equal(t, `{"Value":2}`, string(snapshot.Entities[0].Components["bar"]))
+	assert.Equal(t, second, snapshot.Entities[1].ID)
+	assert.Equal(t, `{"Value":0}`, string(snapshot.Entities[1].Components["foo"]))
+
+	// Only the entities with all of the components are included, with only those components.
+	snapshot, err = manager.Snapshot([]types.ComponentMetadata{barComp})
+	assert.NilError(t, err)
+	assert.Equal(t, 1, len(snapshot.Entities))
+	assert.Equal(t, first, snapshot.Entities[0].ID)
+	assert.Equal(t, 1, len(snapshot.Entities[0].Components))
+	assert.Equal(t, `{"Value":2}`, string(snapshot.Entities[0].Components["bar"]))
+}
@@ -0,0 +1,76 @@
+package gamestate
+
+import (
+	"errors"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal/types"
+)
+
+// ArchetypeStatsSampleSize is the number of entities of each archetype whose components are read to approximate the
+// memory that the archetype uses.
+const ArchetypeStatsSampleSize = 100
+
+// ArchetypeStat describes the entities of one archetype.
+type ArchetypeStat struct {
+	ID types.ArchetypeID `json:"id"`
+	// Components are the names of the components of the archetype, in component ID order.
+	Components  []string `json:"components"`
+	EntityCount int      `json:"entityCount"`
+	// ApproxBytes is the approximate size of the component values of all the entities of the archetype. It is
+	// extrapolated from the JSON encoded components of up to ArchetypeStatsSampleSize entities, so it is neither the
+	// size in storage of components that use another codec, nor the memory that the storage uses for its keys.
+	ApproxBytes int64 `json:"approxBytes"`
+}
+
+// GetArchetypeStats returns the stats of every archetype of the given reader, in archetype ID order. Archetypes whose
+// entities were all removed are included with an entity count of 0. Archived entities are counted.
+func GetArchetypeStats(r Reader) ([]ArchetypeStat, error) {
+	count := r.ArchetypeCount()
+	stats := make([]ArchetypeStat, 0, count)
+	for i := 0; i < count; i++ {
+		archID := types.ArchetypeID(i)
+		comps, err := r.GetComponentTypesForArchID(archID)
+		if err != nil {
+			return nil, err
+		}
+		stat := ArchetypeStat{ID: archID, Components: make([]string, 0, len(comps))}
+		for _, c := range comps {
+			stat.Components = append(stat.Components, c.Name())
+		}
+
+		ids, err := r.GetEntitiesForArchID(archID)
+		if err != nil && !errors.Is(eris.Cause(err), redis.Nil) {
+			return nil, err
+		}
+		stat.EntityCount = len(ids)
+		if stat.ApproxBytes, err = approxArchetypeBytes(r, comps, ids); err != nil {
+			return nil, err
+		}
+		stats = append(stats, stat)
+	}
+	return stats, nil
+}
+
+// approxArchetypeBytes extrapolates the size of the components of the given entities from a sample of them. The
+// sample is spread evenly over the entities, so that it doesn't only cover the oldest ones.
+func approxArchetypeBytes(r Reader, comps []types.ComponentMetadata, ids []types.EntityID) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	sampleSize := min(len(ids), ArchetypeStatsSampleSize)
+	var sampled int64
+	for i := 0; i < sampleSize; i++ {
+		id := ids[i*len(ids)/sampleSize]
+		for _, c := range comps {
+			bz, err := r.GetComponentForEntityInRawJSON(c, id)
+			if err != nil {
+				return 0, err
+			}
+			sampled += int64(len(bz))
+		}
+	}
+	return sampled * int64(len(ids)) / int64(sampleSize), nil
+}
@@ -6,10 +6,10 @@ import (
 
 	"github.com/redis/go-redis/v9"
 	"github.com/rotisserie/eris"
-	"gopkg.in/DataDog/dd-trace-go.v1/ddtrace/tracer"
 
 	"pkg.world.dev/world-engine/cardinal/codec"
 	"pkg.world.dev/world-engine/cardinal/statsd"
+	"pkg.world.dev/world-engine/cardinal/tracing"
 	"pkg.world.dev/world-engine/cardinal/types"
 	"pkg.world.dev/world-engine/cardinal/types/txpool"
 	"pkg.world.dev/world-engine/sign"
@@ -49,10 +49,23 @@ func (m *EntityCommandBuffer) GetTickNumbers() (start, end uint64, err error) {
 	return start, end, nil
 }
 
-// StartNextTick saves the given transactions to the DB and sets the tick trackers to indicate we are in the middle
-// of a tick. While transactions are saved to the DB, no state changes take place at this time.
-func (m *EntityCommandBuffer) StartNextTick(txs []types.Message, pool *txpool.TxPool) error {
-	ctx := context.Background()
+// StartNextTick saves the given transactions and the timestamp of the tick to the DB and sets the tick trackers to
+// indicate we are in the middle of a tick. While transactions are saved to the DB, no state changes take place at this
+// time. The storage operations of the tick use ctx until the tick is finalized, so a tick whose context is done fails
+// instead of waiting for an unresponsive DB. A tick that was started but not finalized can be started again.
+func (m *EntityCommandBuffer) StartNextTick(
+	ctx context.Context, txs []types.Message, pool *txpool.TxPool, timestamp uint64,
+) error {
+	m.tickCtx = ctx
+	// The started tick is the one after the last finalized tick, so that starting a tick again doesn't leave the tick
+	// trackers apart after it is finalized
+	end, err := m.dbStorage.GetUInt64(ctx, storageEndTickKey())
+	err = eris.Wrap(err, "")
+	if eris.Is(eris.Cause(err), redis.Nil) {
+		end = 0
+	} else if err != nil {
+		return err
+	}
 	pipe, err := m.dbStorage.StartTransaction(ctx)
 	if err != nil {
 		return err
@@ -60,20 +73,53 @@ func (m *EntityCommandBuffer) StartNextTick(txs []types.Message, pool *txpool.Tx
 	if err := addPendingTransactionToPipe(ctx, pipe, txs, pool); err != nil {
 		return err
 	}
+	writes := m.tickStartWrites
+	m.tickStartWrites = nil
+	for _, write := range writes {
+		if err := write(ctx, pipe); err != nil {
+			return err
+		}
+	}
+
+	if err := pipe.Set(ctx, storageTickTimestampKey(), timestamp); err != nil {
+		return eris.Wrap(err, "")
+	}
 
-	if err := pipe.Incr(ctx, storageStartTickKey()); err != nil {
+	if err := pipe.Set(ctx, storageStartTickKey(), end+1); err != nil {
 		return eris.Wrap(err, "")
 	}
 	return eris.Wrap(pipe.EndTransaction(ctx), "")
 }
 
+// AddTickStartWrite adds a write to the transaction that starts the next tick, so that it is committed atomically with
+// the pending transactions of the tick, e.g. to remove the transactions from the queue they were taken from. The write
+// is dropped if the tick fails to start.
+func (m *EntityCommandBuffer) AddTickStartWrite(write func(ctx context.Context, pipe PrimitiveStorage[string]) error) {
+	m.tickStartWrites = append(m.tickStartWrites, write)
+}
+
+// GetTickTimestamp returns the timestamp of the last tick that was started. When the last tick did not complete, this
+// is the timestamp that must be used to run it again. Zero is returned if no tick has been started yet.
+func (m *EntityCommandBuffer) GetTickTimestamp() (uint64, error) {
+	timestamp, err := m.dbStorage.GetUInt64(context.Background(), storageTickTimestampKey())
+	err = eris.Wrap(err, "")
+	if eris.Is(eris.Cause(err), redis.Nil) {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+	return timestamp, nil
+}
+
 // FinalizeTick combines all pending state changes into a single multi/exec redis transactions and commits them
 // to the DB.
-func (m *EntityCommandBuffer) FinalizeTick(ctx context.Context) error {
-	var span tracer.Span
-	span, ctx = tracer.StartSpanFromContext(ctx, "tick.span.finalize")
+func (m *EntityCommandBuffer) FinalizeTick(ctx context.Context) (err error) {
+	defer func() {
+		m.tickCtx = nil
+	}()
+	ctx, span := tracing.Tracer().Start(ctx, "cardinal.storage.finalize")
 	defer func() {
-		span.Finish()
+		tracing.End(span, err)
 	}()
 	makePipeStartTime := time.Now()
 	pipe, err := m.makePipeOfRedisCommands(ctx)
@@ -85,13 +131,25 @@ func (m *EntityCommandBuffer) FinalizeTick(ctx context.Context) error {
 	}
 	statsd.EmitTickStat(makePipeStartTime, "pipe_make")
 	flushStartTime := time.Now()
+	m.waitForStateReads()
+	_, execSpan := tracing.Tracer().Start(ctx, "cardinal.storage.pipe_exec")
 	err = pipe.EndTransaction(ctx)
+	tracing.End(execSpan, err)
 	statsd.EmitTickStat(flushStartTime, "pipe_exec")
 	if err != nil {
 		return eris.Wrap(err, "")
 	}
 
 	m.pendingArchIDs = nil
+	m.commitLastAccess()
+	m.accessClock++
+	return m.DiscardPending()
+}
+
+// AbortTick discards the pending state changes of a tick that failed before it was finalized, so that the tick can be
+// started again.
+func (m *EntityCommandBuffer) AbortTick() error {
+	m.tickCtx = nil
 	return m.DiscardPending()
 }
 
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal/gamestate"
 	"pkg.world.dev/world-engine/cardinal/message"
 	"pkg.world.dev/world-engine/cardinal/testutils"
 	"pkg.world.dev/world-engine/cardinal/types"
@@ -30,7 +31,7 @@ func TestCanSaveAndRecoverTransactions(t *testing.T) {
 	sig := testutils.UniqueSignature()
 	_ = originalPool.AddTransaction(msgAlpha.ID(), MsgIn{100}, sig)
 
-	assert.NilError(t, manager.StartNextTick(msgs, originalPool))
+	assert.NilError(t, manager.StartNextTick(context.Background(), msgs, originalPool, 1234))
 
 	// Pretend some problem was encountered here. Make sure we can recover the transactions from redis.
 	manager, _ = newCmdBufferAndRedisClientForTest(t, client)
@@ -38,9 +39,39 @@ func TestCanSaveAndRecoverTransactions(t *testing.T) {
 	assert.NilError(t, err)
 
 	assert.Equal(t, gotPool.GetAmountOfTxs(), originalPool.GetAmountOfTxs())
+	timestamp, err := manager.GetTickTimestamp()
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(1234), timestamp)
 
 	// Make sure we can finalize the tick
-	assert.NilError(t, manager.StartNextTick(msgs, gotPool))
+	assert.NilError(t, manager.StartNextTick(context.Background(), msgs, gotPool, timestamp))
+	assert.NilError(t, manager.FinalizeTick(context.Background()))
+}
+
+func TestStorageOperationsOfTickAreCancelledWithIt(t *testing.T) {
+	manager, client := newCmdBufferAndRedisClientForTest(t, nil)
+	id, err := manager.CreateEntity(fooComp)
+	assert.NilError(t, err)
+	assert.NilError(t, manager.SetComponentForEntity(fooComp, id, Foo{Value: 7}))
+	assert.NilError(t, manager.FinalizeTick(context.Background()))
+
+	// A new manager has to read the entity from storage
+	manager, _ = newCmdBufferAndRedisClientForTest(t, client)
+	ctx, cancel := context.WithCancel(context.Background())
+	assert.NilError(t, manager.StartNextTick(ctx, nil, txpool.New(), 1))
+	cancel()
+	_, err = manager.GetComponentForEntity(fooComp, id)
+	assert.ErrorIs(t, err, context.Canceled)
+	_, err = manager.CreateEntity(fooComp)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.ErrorIs(t, manager.FinalizeTick(ctx), context.Canceled)
+
+	// The context of the cancelled tick is not used by the next one
+	assert.NilError(t, manager.DiscardPending())
+	assert.NilError(t, manager.StartNextTick(context.Background(), nil, txpool.New(), 2))
+	got, err := manager.GetComponentForEntity(fooComp, id)
+	assert.NilError(t, err)
+	assert.Equal(t, Foo{Value: 7}, got)
 	assert.NilError(t, manager.FinalizeTick(context.Background()))
 }
 
@@ -50,3 +81,60 @@ func TestErrorWhenRecoveringNoTransactions(t *testing.T) {
 	// Recover should fail when no transactions have previously been saved to the DB.
 	assert.Check(t, err != nil)
 }
+
+func TestTickLogIsCommittedWithTheTick(t *testing.T) {
+	manager := newCmdBufferForTest(t)
+	ctx := context.Background()
+
+	assert.NilError(t, manager.SetTickLog(0, []byte("discarded")))
+	assert.NilError(t, manager.DiscardPending())
+	assert.NilError(t, manager.FinalizeTick(ctx))
+	_, err := manager.GetTickLog(0)
+	assert.ErrorIs(t, err, gamestate.ErrTickLogNotFound)
+
+	assert.NilError(t, manager.SetTickLog(1, []byte("entry")))
+	// The entry is not visible until the tick is finalized.
+	_, err = manager.GetTickLog(1)
+	assert.ErrorIs(t, err, gamestate.ErrTickLogNotFound)
+	assert.NilError(t, manager.FinalizeTick(ctx))
+
+	got, err := manager.GetTickLog(1)
+	assert.NilError(t, err)
+	assert.Equal(t, "entry", string(got))
+}
+
+func TestTickLogIsPrunedWithTheTick(t *testing.T) {
+	manager := newCmdBufferForTest(t)
+	ctx := context.Background()
+
+	for tick := uint64(0); tick < 5; tick++ {
+		assert.NilError(t, manager.SetTickLog(tick, []byte("entry")))
+		assert.NilError(t, manager.FinalizeTick(ctx))
+	}
+	start, err := manager.GetTickLogStart()
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(0), start)
+
+	assert.NilError(t, manager.PruneTickLog(3))
+	// The entries are not deleted until the tick is finalized.
+	_, err = manager.GetTickLog(0)
+	assert.NilError(t, err)
+	assert.NilError(t, manager.FinalizeTick(ctx))
+
+	for tick := uint64(0); tick < 3; tick++ {
+		_, err = manager.GetTickLog(tick)
+		assert.ErrorIs(t, err, gamestate.ErrTickLogNotFound)
+	}
+	_, err = manager.GetTickLog(3)
+	assert.NilError(t, err)
+	start, err = manager.GetTickLogStart()
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(3), start)
+
+	// Pruning ticks that were already pruned does nothing.
+	assert.NilError(t, manager.PruneTickLog(2))
+	assert.NilError(t, manager.FinalizeTick(ctx))
+	start, err = manager.GetTickLogStart()
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(3), start)
+}
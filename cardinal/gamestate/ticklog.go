@@ -0,0 +1,98 @@
+package gamestate
+
+import (
+	"bytes"
+	"context"
+	"errors"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rotisserie/eris"
+)
+
+// MaxTickLogPrunesPerTick is the maximum number of entries that PruneTickLog deletes in one tick, so that limiting the
+// retention of a long log spreads the deletions over many ticks.
+const MaxTickLogPrunesPerTick = 1000
+
+var ErrTickLogNotFound = errors.New("tick log entry not found")
+
+type tickLogEntry struct {
+	tick  uint64
+	entry []byte
+}
+
+// tickLogPrune is the range of ticks [from, to) whose entries are deleted when the tick is committed.
+type tickLogPrune struct {
+	from, to uint64
+}
+
+// SetTickLog buffers the event log entry for the given tick. The entry is committed to the DB in the same
+// transaction as the rest of the tick's state changes when FinalizeTick is called.
+func (m *EntityCommandBuffer) SetTickLog(tick uint64, entry []byte) error {
+	m.pendingTickLog = &tickLogEntry{
+		tick:  tick,
+		entry: bytes.Clone(entry),
+	}
+	return nil
+}
+
+// GetTickLog returns the committed event log entry for the given tick. ErrTickLogNotFound is returned if the tick
+// has not completed yet, or if it completed while the event log was disabled.
+func (m *EntityCommandBuffer) GetTickLog(tick uint64) ([]byte, error) {
+	bz, err := m.dbStorage.GetBytes(m.ctx(), storageTickLogKey(tick))
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, eris.Wrapf(ErrTickLogNotFound, "tick %d", tick)
+		}
+		return nil, err
+	}
+	return m.cipher.Open(bz, storageTickLogKey(tick))
+}
+
+// GetTickLogStart returns the first tick whose event log entry has not been pruned by PruneTickLog.
+func (m *EntityCommandBuffer) GetTickLogStart() (uint64, error) {
+	start, err := m.dbStorage.GetUInt64(m.ctx(), storageTickLogStartKey())
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	} else if err != nil {
+		return 0, eris.Wrap(err, "")
+	}
+	return start, nil
+}
+
+// PruneTickLog buffers the deletion of the event log entries of the ticks before the given tick. At most
+// MaxTickLogPrunesPerTick entries are deleted, starting with the oldest. The deletion is committed to the DB in the
+// same transaction as the rest of the tick's state changes when FinalizeTick is called.
+func (m *EntityCommandBuffer) PruneTickLog(before uint64) error {
+	start, err := m.GetTickLogStart()
+	if err != nil {
+		return err
+	}
+	if before <= start {
+		m.pendingTickLogPrune = nil
+		return nil
+	}
+	m.pendingTickLogPrune = &tickLogPrune{from: start, to: min(before, start+MaxTickLogPrunesPerTick)}
+	return nil
+}
+
+// addTickLogToPipe adds the pending tick log entry and prune (if any) to the redis pipe.
+func (m *EntityCommandBuffer) addTickLogToPipe(ctx context.Context, pipe PrimitiveStorage[string]) error {
+	if m.pendingTickLog != nil {
+		entry, err := m.cipher.Seal(m.pendingTickLog.entry, storageTickLogKey(m.pendingTickLog.tick))
+		if err != nil {
+			return err
+		}
+		if err = pipe.Set(ctx, storageTickLogKey(m.pendingTickLog.tick), entry); err != nil {
+			return eris.Wrap(err, "")
+		}
+	}
+	if prune := m.pendingTickLogPrune; prune != nil {
+		for tick := prune.from; tick < prune.to; tick++ {
+			if err := pipe.Delete(ctx, storageTickLogKey(tick)); err != nil {
+				return eris.Wrap(err, "")
+			}
+		}
+		return eris.Wrap(pipe.Set(ctx, storageTickLogStartKey(), prune.to), "")
+	}
+	return nil
+}
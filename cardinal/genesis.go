@@ -0,0 +1,202 @@
+package cardinal
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/rotisserie/eris"
+	"gopkg.in/yaml.v3"
+
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+// genesisSystemName is the name of the system that creates the entities of the genesis file.
+const genesisSystemName = "cardinal.genesis"
+
+// genesisFile is the content of a genesis file, see WithGenesisFile.
+type genesisFile struct {
+	Entities []genesisEntity `json:"entities"`
+}
+
+// genesisEntity describes entities that are created with the same component values.
+type genesisEntity struct {
+	// Count is the number of entities that are created. It defaults to 1.
+	Count int `json:"count"`
+	// Components are the values of the components of the entities, keyed by component name. Fields that are not set
+	// keep their default value.
+	Components map[string]json.RawMessage `json:"components"`
+}
+
+// genesisSpawn is a genesisEntity whose components were validated and decoded.
+type genesisSpawn struct {
+	count      int
+	components []types.Component
+}
+
+// loadGenesis reads the genesis file at path, and decodes the component values of its entities. The file can be JSON
+// or YAML. Every component must be registered, and every field of a component value must be a field of its schema.
+func (w *World) loadGenesis(path string) ([]genesisSpawn, error) {
+	bz, err := os.ReadFile(path)
+	if err != nil {
+		return nil, eris.Wrap(err, "failed to read genesis file")
+	}
+	// YAML is a superset of JSON, so both are read as YAML and converted to JSON to decode the component values
+	var raw any
+	if err = yaml.Unmarshal(bz, &raw); err != nil {
+		return nil, eris.Wrapf(err, "genesis file %s is not valid JSON or YAML", path)
+	}
+	if bz, err = json.Marshal(raw); err != nil {
+		return nil, eris.Wrapf(err, "genesis file %s can't be converted to JSON", path)
+	}
+	dec := json.NewDecoder(bytes.NewReader(bz))
+	dec.DisallowUnknownFields()
+	var file genesisFile
+	if err = dec.Decode(&file); err != nil {
+		return nil, eris.Wrapf(err, "genesis file %s is invalid", path)
+	}
+
+	spawns := make([]genesisSpawn, 0, len(file.Entities))
+	for i, entity := range file.Entities {
+		spawn, err := w.decodeGenesisEntity(entity)
+		if err != nil {
+			return nil, eris.Wrapf(err, "entity %d of genesis file %s is invalid", i, path)
+		}
+		spawns = append(spawns, spawn)
+	}
+	return spawns, nil
+}
+
+func (w *World) decodeGenesisEntity(entity genesisEntity) (genesisSpawn, error) {
+	spawn := genesisSpawn{count: entity.Count}
+	if spawn.count == 0 {
+		spawn.count = 1
+	} else if spawn.count < 0 {
+		return spawn, eris.Errorf("count must not be negative, got %d", spawn.count)
+	}
+	if len(entity.Components) == 0 {
+		return spawn, eris.New("at least one component is required")
+	}
+
+	// Components are decoded in a fixed order, so that the same file always creates the same entities
+	names := make([]string, 0, len(entity.Components))
+	for name := range entity.Components {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		metadata, err := w.GetComponentByName(name)
+		if err != nil {
+			return spawn, eris.Wrapf(err, "component %q is not registered", name)
+		}
+		comp, err := decodeGenesisComponent(metadata, entity.Components[name])
+		if err != nil {
+			return spawn, eris.Wrapf(err, "component %q", name)
+		}
+		spawn.components = append(spawn.components, comp)
+	}
+	return spawn, nil
+}
+
+// decodeGenesisComponent decodes a component value of a genesis file on top of the default value of the component.
+func decodeGenesisComponent(metadata types.ComponentMetadata, value json.RawMessage) (types.Component, error) {
+	var fields map[string]any
+	if err := json.Unmarshal(value, &fields); err != nil {
+		return nil, eris.Wrap(err, "value must be an object")
+	}
+	var schema jsonSchema
+	if err := json.Unmarshal(metadata.GetSchema(), &schema); err != nil {
+		return nil, eris.Wrap(err, "failed to read the schema of the component")
+	}
+	if err := schema.checkFields(schema.Definitions, fields, ""); err != nil {
+		return nil, err
+	}
+
+	bz, err := metadata.New()
+	if err != nil {
+		return nil, err
+	}
+	defaultValue, err := metadata.Decode(bz)
+	if err != nil {
+		return nil, err
+	}
+	if bz, err = json.Marshal(defaultValue); err != nil {
+		return nil, eris.Wrap(err, "")
+	}
+	var merged map[string]any
+	if err = json.Unmarshal(bz, &merged); err != nil {
+		return nil, eris.Wrap(err, "")
+	}
+	for field, fieldValue := range fields {
+		merged[field] = fieldValue
+	}
+	if bz, err = json.Marshal(merged); err != nil {
+		return nil, eris.Wrap(err, "")
+	}
+	return metadata.Decode(bz)
+}
+
+// genesisSystem returns the system that creates the entities of the genesis file at tick 0.
+func genesisSystem(spawns []genesisSpawn) System {
+	return func(wCtx engine.Context) error {
+		for _, spawn := range spawns {
+			if _, err := CreateMany(wCtx, spawn.count, spawn.components...); err != nil {
+				return eris.Wrap(err, "failed to create genesis entities")
+			}
+		}
+		return nil
+	}
+}
+
+// jsonSchema is the part of a schema generated by jsonschema.Reflect that describes which fields an object has.
+type jsonSchema struct {
+	Ref                  string                 `json:"$ref"`
+	Properties           map[string]*jsonSchema `json:"properties"`
+	AdditionalProperties json.RawMessage        `json:"additionalProperties"`
+	Items                *jsonSchema            `json:"items"`
+	Definitions          map[string]*jsonSchema `json:"$defs"`
+}
+
+// checkFields returns an error if the value, or a value nested in it, has a field that is not in the schema. The types
+// of the fields are checked when the value is decoded.
+func (s *jsonSchema) checkFields(definitions map[string]*jsonSchema, value any, path string) error {
+	if s.Ref != "" {
+		def, ok := definitions[strings.TrimPrefix(s.Ref, "#/$defs/")]
+		if !ok {
+			return nil
+		}
+		s = def
+	}
+	switch v := value.(type) {
+	case map[string]any:
+		if s.Properties == nil {
+			return nil
+		}
+		for field, fieldValue := range v {
+			prop, ok := s.Properties[field]
+			if !ok {
+				if string(s.AdditionalProperties) == "false" {
+					return eris.Errorf("unknown field %q", path+field)
+				}
+				continue
+			}
+			if err := prop.checkFields(definitions, fieldValue, path+field+"."); err != nil {
+				return err
+			}
+		}
+	case []any:
+		if s.Items == nil {
+			return nil
+		}
+		for i, item := range v {
+			if err := s.Items.checkFields(definitions, item, fmt.Sprintf("%s%d.", path, i)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+package cardinal_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/search/filter"
+	"pkg.world.dev/world-engine/cardinal/testutils"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+type GenesisTile struct {
+	Kind string          `json:"kind"`
+	Pos  GenesisPosition `json:"pos"`
+}
+
+type GenesisPosition struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+func (GenesisTile) Name() string { return "genesis_tile" }
+
+const genesisYAML = `
+entities:
+  - count: 3
+    components:
+      genesis_tile: {kind: grass, pos: {x: 1, y: 2}}
+  - components:
+      genesis_tile: {kind: water}
+      default_health: {HP: 5}
+`
+
+func writeGenesisFile(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "genesis.yaml")
+	assert.NilError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func newGenesisFixture(t *testing.T, redis *miniredis.Miniredis, path string) *testutils.TestFixture {
+	tf := testutils.NewTestFixture(t, redis, cardinal.WithGenesisFile(path))
+	assert.NilError(t, cardinal.RegisterComponent[GenesisTile](tf.World))
+	assert.NilError(t, cardinal.RegisterComponent[DefaultHealth](tf.World))
+	return tf
+}
+
+func countGenesisTiles(t *testing.T, wCtx engine.Context) map[string]int {
+	kinds := map[string]int{}
+	err := cardinal.NewSearch().Entity(filter.Contains(filter.Component[GenesisTile]())).Each(wCtx,
+		func(id types.EntityID) bool {
+			tile, err := cardinal.GetComponent[GenesisTile](wCtx, id)
+			assert.NilError(t, err)
+			kinds[tile.Kind]++
+			return true
+		})
+	assert.NilError(t, err)
+	return kinds
+}
+
+func TestGenesisEntitiesAreCreatedBeforeInitSystems(t *testing.T) {
+	tf := newGenesisFixture(t, nil, writeGenesisFile(t, genesisYAML))
+	var seenByInit map[string]int
+	err := cardinal.RegisterInitSystems(tf.World, func(wCtx engine.Context) error {
+		seenByInit = countGenesisTiles(t, wCtx)
+		return nil
+	})
+	assert.NilError(t, err)
+	tf.DoTick()
+
+	want := map[string]int{"grass": 3, "water": 1}
+	assert.DeepEqual(t, want, seenByInit)
+	wCtx := cardinal.NewReadOnlyWorldContext(tf.World)
+	assert.DeepEqual(t, want, countGenesisTiles(t, wCtx))
+
+	var health *DefaultHealth
+	err = cardinal.NewSearch().Entity(filter.Contains(filter.Component[DefaultHealth]())).Each(wCtx,
+		func(id types.EntityID) bool {
+			health, err = cardinal.GetComponent[DefaultHealth](wCtx, id)
+			assert.NilError(t, err)
+			return true
+		})
+	assert.NilError(t, err)
+	// Fields that are not set in the genesis file keep their default value.
+	assert.Equal(t, DefaultHealth{HP: 5, Max: 100}, *health)
+}
+
+func TestGenesisEntitiesAreOnlyCreatedOnce(t *testing.T) {
+	path := writeGenesisFile(t, genesisYAML)
+	tf1 := newGenesisFixture(t, nil, path)
+	tf1.DoTick()
+
+	// Restarting the world doesn't create the entities again.
+	tf1.Shutdown()
+	tf2 := newGenesisFixture(t, tf1.Redis, path)
+	tf2.DoTick()
+	assert.DeepEqual(t, map[string]int{"grass": 3, "water": 1},
+		countGenesisTiles(t, cardinal.NewReadOnlyWorldContext(tf2.World)))
+}
+
+func TestGenesisFileIsValidatedAgainstComponentSchemas(t *testing.T) {
+	testCases := []struct {
+		name    string
+		genesis string
+		wantErr string
+	}{
+		{
+			name:    "unknown component",
+			genesis: "entities: [{components: {meow: {}}}]",
+			wantErr: `component "meow" is not registered`,
+		},
+		{
+			name:    "unknown field",
+			genesis: "entities: [{components: {genesis_tile: {kind: grass, color: green}}}]",
+			wantErr: `unknown field "color"`,
+		},
+		{
+			name:    "unknown nested field",
+			genesis: "entities: [{components: {genesis_tile: {pos: {x: 1, z: 2}}}}]",
+			wantErr: `unknown field "pos.z"`,
+		},
+		{
+			name:    "wrong type",
+			genesis: "entities: [{components: {genesis_tile: {kind: 5}}}]",
+			wantErr: `component "genesis_tile"`,
+		},
+		{
+			name:    "no components",
+			genesis: "entities: [{count: 2}]",
+			wantErr: "at least one component is required",
+		},
+		{
+			name:    "unknown entity field",
+			genesis: "entities: [{amount: 2, components: {genesis_tile: {}}}]",
+			wantErr: "amount",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tf := newGenesisFixture(t, nil, writeGenesisFile(t, tc.genesis))
+			err := tf.World.StartGame()
+			assert.Check(t, err != nil && strings.Contains(err.Error(), tc.wantErr), "got %v", err)
+		})
+	}
+}
@@ -0,0 +1,53 @@
+// Package inbox contains the components, messages and queries of Cardinal's inbox plugin, which keeps a persistent
+// inbox of messages for every persona: mail, trade offers, notifications, or anything else a game's systems want to
+// tell a player. Systems append messages to the inbox of a persona, clients list them with the list query and
+// acknowledge the ones they have shown with the ack message, and the plugin removes messages according to its
+// retention policy. Every inbox message is an entity, so inboxes are committed, checkpointed and recovered with the
+// rest of the game state.
+//
+// The plugin is registered with cardinal.NewInboxPlugin.
+package inbox
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// Message is a message in the inbox of a persona.
+type Message struct {
+	Recipient string `json:"recipient"`
+	// Kind tells clients how to show the message, e.g. "mail", "trade-offer" or "notification".
+	Kind string `json:"kind"`
+	// From is the sender of the message, e.g. the persona tag of a player or the name of a system.
+	From    string `json:"from"`
+	Subject string `json:"subject"`
+	// Body is the game specific content of the message, encoded as JSON.
+	Body json.RawMessage `json:"body,omitempty"`
+	// SentAt is the tick in which the message was added to the inbox.
+	SentAt uint64 `json:"sentAt"`
+	Acked  bool   `json:"acked"`
+	// AckedAt is the tick in which the recipient acknowledged the message.
+	AckedAt uint64 `json:"ackedAt"`
+}
+
+func (Message) Name() string {
+	return "InboxMessage"
+}
+
+// IsFor returns true if the message is in the inbox of the persona.
+func (m Message) IsFor(personaTag string) bool {
+	// Persona tags are compared the same way the persona plugin does, which is case-insensitively.
+	return strings.EqualFold(m.Recipient, personaTag)
+}
+
+// Retention is the policy by which the inbox plugin removes messages. A zero field disables that part of the policy,
+// and the zero Retention keeps every message forever.
+type Retention struct {
+	// MaxAge is the number of ticks after which a message is removed, whether it was acknowledged or not.
+	MaxAge uint64
+	// AckedMaxAge is the number of ticks after its acknowledgment after which a message is removed.
+	AckedMaxAge uint64
+	// MaxMessages is the number of messages that the inbox of a persona holds. The oldest messages are removed when
+	// there are more.
+	MaxMessages int
+}
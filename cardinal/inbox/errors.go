@@ -0,0 +1,10 @@
+package inbox
+
+import (
+	"errors"
+)
+
+var (
+	ErrMessageNotFound = errors.New("inbox message not found")
+	ErrNotRecipient    = errors.New("persona is not the recipient of the inbox message")
+)
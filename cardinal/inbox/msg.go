@@ -0,0 +1,18 @@
+package inbox
+
+import (
+	"pkg.world.dev/world-engine/cardinal/types"
+)
+
+const AckMessageName = "ack"
+
+// Ack acknowledges messages in the inbox of the persona that signs it, e.g. once a client has shown them. All
+// messages must be in the persona's inbox, otherwise none is acknowledged.
+type Ack struct {
+	IDs []types.EntityID `json:"ids"`
+}
+
+type AckResult struct {
+	// Acked is the number of messages that were not acknowledged before.
+	Acked int `json:"acked"`
+}
@@ -0,0 +1,28 @@
+package inbox
+
+import (
+	"pkg.world.dev/world-engine/cardinal/types"
+)
+
+const ListQueryName = "list"
+
+// ListRequest is the request body of the inbox list query.
+type ListRequest struct {
+	PersonaTag string `json:"personaTag"`
+	// Unacked only returns the messages that have not been acknowledged.
+	Unacked bool `json:"unacked"`
+	// Kind filters the messages by kind. Messages of all kinds are returned if it is empty.
+	Kind string `json:"kind"`
+}
+
+// ListResponse holds the messages of the inbox, oldest first.
+type ListResponse struct {
+	Messages []MessageInfo `json:"messages"`
+	// Unacked is the number of messages in the inbox that have not been acknowledged, of any kind.
+	Unacked int `json:"unacked"`
+}
+
+type MessageInfo struct {
+	ID      types.EntityID `json:"id"`
+	Message Message        `json:"message"`
+}
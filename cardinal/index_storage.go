@@ -0,0 +1,108 @@
+package cardinal
+
+import (
+	"context"
+	"slices"
+	"strconv"
+
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+// indexBuildBatchSize is the number of entities that are added to an index before the entries are committed, when the
+// entities that existed before the index was registered are added to it.
+const indexBuildBatchSize = 1000
+
+// indexStorage reads and writes the entries of the unique and field indexes under a prefix. The entries are committed
+// with the rest of the tick, like raw storage values, but they don't count towards the raw storage quota of the game
+// and are not limited in size.
+type indexStorage struct {
+	wCtx   engine.Context
+	prefix string
+}
+
+func newIndexStorage(wCtx engine.Context, prefix string) *indexStorage {
+	return &indexStorage{wCtx: wCtx, prefix: prefix + ":"}
+}
+
+func (s *indexStorage) get(key string) (value []byte, ok bool, err error) {
+	defer func() { panicOnFatalError(s.wCtx, err) }()
+	return s.wCtx.StoreReader().GetIndexValue(s.prefix + key)
+}
+
+func (s *indexStorage) set(key string, value []byte) (err error) {
+	defer func() { panicOnFatalError(s.wCtx, err) }()
+	if s.wCtx.IsReadOnly() {
+		return ErrEntityMutationOnReadOnly
+	}
+	return s.wCtx.StoreManager().SetIndexValue(s.prefix+key, value)
+}
+
+func (s *indexStorage) delete(key string) (err error) {
+	defer func() { panicOnFatalError(s.wCtx, err) }()
+	if s.wCtx.IsReadOnly() {
+		return ErrEntityMutationOnReadOnly
+	}
+	return s.wCtx.StoreManager().DeleteIndexValue(s.prefix + key)
+}
+
+// entities returns the entities in the set at the given key, in ascending order.
+func (s *indexStorage) entities(key string) (ids []types.EntityID, err error) {
+	defer func() { panicOnFatalError(s.wCtx, err) }()
+	members, err := s.wCtx.StoreReader().GetIndexMembers(s.prefix + key)
+	if err != nil {
+		return nil, err
+	}
+	ids = make([]types.EntityID, 0, len(members))
+	for _, member := range members {
+		n, err := strconv.ParseUint(member, 10, 64)
+		if err != nil {
+			return nil, eris.Wrapf(err, "invalid entity %q in index entry %q", member, key)
+		}
+		ids = append(ids, types.EntityID(n))
+	}
+	slices.Sort(ids)
+	return ids, nil
+}
+
+func (s *indexStorage) addEntity(key string, id types.EntityID) (err error) {
+	defer func() { panicOnFatalError(s.wCtx, err) }()
+	if s.wCtx.IsReadOnly() {
+		return ErrEntityMutationOnReadOnly
+	}
+	return s.wCtx.StoreManager().AddIndexMember(s.prefix+key, strconv.FormatUint(uint64(id), 10))
+}
+
+func (s *indexStorage) removeEntity(key string, id types.EntityID) (err error) {
+	defer func() { panicOnFatalError(s.wCtx, err) }()
+	if s.wCtx.IsReadOnly() {
+		return ErrEntityMutationOnReadOnly
+	}
+	return s.wCtx.StoreManager().RemoveIndexMember(s.prefix+key, strconv.FormatUint(uint64(id), 10))
+}
+
+// buildIndex adds every entity that each visits to an index with add, and commits the entries in batches, so that the
+// entries of a large world don't pile up in memory before they are committed. It must be called between ticks.
+func buildIndex(
+	wCtx engine.Context,
+	each func(wCtx engine.Context, fn func(id types.EntityID, value any) error) error,
+	add func(id types.EntityID, value any) error,
+) error {
+	n := 0
+	err := each(wCtx, func(id types.EntityID, value any) error {
+		if err := add(id, value); err != nil {
+			return err
+		}
+		n++
+		if n%indexBuildBatchSize == 0 {
+			return wCtx.StoreManager().FlushIndexes(context.Background())
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return wCtx.StoreManager().FlushIndexes(context.Background())
+}
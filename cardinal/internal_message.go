@@ -0,0 +1,20 @@
+package cardinal
+
+import (
+	"reflect"
+
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+// InternalMessages returns the messages of type T that were emitted with EmitInternal by the systems that ran before
+// in the current tick, in the order in which they were emitted. This lets a system pass derived data, e.g. the
+// collisions that it detected, to later systems without creating entities or transactions for it. T must be the
+// concrete type of the emitted messages; a message emitted as a *T is not returned for T.
+func InternalMessages[T any](wCtx engine.Context) []T {
+	msgs := wCtx.InternalMessages(reflect.TypeOf((*T)(nil)).Elem())
+	out := make([]T, 0, len(msgs))
+	for _, msg := range msgs {
+		out = append(out, msg.(T)) //nolint:errcheck // messages are stored by their type
+	}
+	return out
+}
@@ -0,0 +1,26 @@
+// Package leaderboard contains the components, queries and rankings of Cardinal's leaderboard plugin, which ranks the
+// scores of personas on any number of boards. Scores are part of the game state and are changed by systems with
+// cardinal.SetScore, cardinal.AddScore and cardinal.RemoveScore. The sorted rankings are updated incrementally at the
+// end of every tick, so the top-N and around-me queries never sort a board.
+//
+// The plugin is registered with cardinal.NewLeaderboardPlugin.
+package leaderboard
+
+// Entry is the score of a persona on a board. There is at most one entry per board and persona.
+type Entry struct {
+	Board      string `json:"board"`
+	PersonaTag string `json:"personaTag"`
+	Score      int64  `json:"score"`
+}
+
+func (Entry) Name() string {
+	return "LeaderboardEntry"
+}
+
+// Rank is the position of a persona on a board. Higher scores rank first, and personas with the same score are ranked
+// by persona tag, so every persona has a distinct rank. The first rank is 1.
+type Rank struct {
+	Rank       int    `json:"rank"`
+	PersonaTag string `json:"personaTag"`
+	Score      int64  `json:"score"`
+}
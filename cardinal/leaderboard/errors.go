@@ -0,0 +1,10 @@
+package leaderboard
+
+import (
+	"errors"
+)
+
+var (
+	ErrNotRanked    = errors.New("persona is not ranked on the board")
+	ErrInvalidBoard = errors.New("invalid board")
+)
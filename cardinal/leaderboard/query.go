@@ -0,0 +1,31 @@
+package leaderboard
+
+const (
+	TopQueryName    = "top"
+	AroundQueryName = "around"
+
+	// MaxRanks is the maximum number of ranks that a query returns.
+	MaxRanks = 100
+)
+
+// TopRequest is the request body of the top query, which returns the best ranks of a board.
+type TopRequest struct {
+	Board string `json:"board"`
+	// Offset is the number of ranks to skip, e.g. 100 to get the ranks after the first 100.
+	Offset int `json:"offset"`
+	// Limit is the number of ranks to return. It is capped at MaxRanks, which is also used if it is 0.
+	Limit int `json:"limit"`
+}
+
+// AroundRequest is the request body of the around query, which returns the rank of a persona and the ranks next to it.
+type AroundRequest struct {
+	Board      string `json:"board"`
+	PersonaTag string `json:"personaTag"`
+	// Radius is the number of ranks above and below the persona to return. It is capped so that at most MaxRanks
+	// ranks are returned.
+	Radius int `json:"radius"`
+}
+
+type RanksResponse struct {
+	Ranks []Rank `json:"ranks"`
+}
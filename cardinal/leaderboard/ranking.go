@@ -0,0 +1,163 @@
+package leaderboard
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/rotisserie/eris"
+)
+
+// Rankings holds the sorted rankings of the boards. They mirror the committed Entry components: they are reset from
+// all entries when the world starts, and the changes of every tick are applied once the tick is committed.
+type Rankings interface {
+	// Reset replaces the rankings of all boards with the given entries.
+	Reset(ctx context.Context, entries []Entry) error
+	// Apply applies the changes of a tick.
+	Apply(ctx context.Context, changes []Change) error
+	// Top returns at most limit ranks of the board, starting after the first offset ranks.
+	Top(ctx context.Context, board string, offset, limit int) ([]Rank, error)
+	// Around returns the rank of the persona on the board, and up to radius ranks above and below it. It fails with
+	// ErrNotRanked if the persona has no score on the board.
+	Around(ctx context.Context, board, personaTag string, radius int) ([]Rank, error)
+}
+
+// Change is a change of the score of a persona on a board.
+type Change struct {
+	Board      string
+	PersonaTag string
+	Score      int64
+	// Removed is true if the persona was removed from the board.
+	Removed bool
+}
+
+var _ Rankings = (*memoryRankings)(nil)
+
+// memoryRankings keeps every board as a sorted slice, so that a change only moves the entries between the old and the
+// new position of the persona.
+type memoryRankings struct {
+	mux    sync.RWMutex
+	boards map[string]*memoryBoard
+}
+
+type memoryBoard struct {
+	sorted []member
+	scores map[string]int64
+}
+
+type member struct {
+	personaTag string
+	score      int64
+}
+
+// NewMemoryRankings returns rankings that are kept in memory.
+func NewMemoryRankings() Rankings {
+	return &memoryRankings{boards: map[string]*memoryBoard{}}
+}
+
+func (r *memoryRankings) Reset(_ context.Context, entries []Entry) error {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	r.boards = map[string]*memoryBoard{}
+	for _, entry := range entries {
+		r.board(entry.Board).set(entry.PersonaTag, entry.Score)
+	}
+	return nil
+}
+
+func (r *memoryRankings) Apply(_ context.Context, changes []Change) error {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	for _, change := range changes {
+		b := r.board(change.Board)
+		if change.Removed {
+			b.remove(change.PersonaTag)
+		} else {
+			b.set(change.PersonaTag, change.Score)
+		}
+		if len(b.sorted) == 0 {
+			delete(r.boards, change.Board)
+		}
+	}
+	return nil
+}
+
+func (r *memoryRankings) Top(_ context.Context, board string, offset, limit int) ([]Rank, error) {
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+	b, ok := r.boards[board]
+	if !ok || offset >= len(b.sorted) {
+		return []Rank{}, nil
+	}
+	return b.ranks(offset, min(offset+limit, len(b.sorted))), nil
+}
+
+func (r *memoryRankings) Around(_ context.Context, board, personaTag string, radius int) ([]Rank, error) {
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+	b, ok := r.boards[board]
+	if !ok {
+		return nil, eris.Wrapf(ErrNotRanked, "%s on %s", personaTag, board)
+	}
+	i, ok := b.index(personaTag)
+	if !ok {
+		return nil, eris.Wrapf(ErrNotRanked, "%s on %s", personaTag, board)
+	}
+	return b.ranks(max(0, i-radius), min(i+radius+1, len(b.sorted))), nil
+}
+
+func (r *memoryRankings) board(name string) *memoryBoard {
+	b, ok := r.boards[name]
+	if !ok {
+		b = &memoryBoard{scores: map[string]int64{}}
+		r.boards[name] = b
+	}
+	return b
+}
+
+func (b *memoryBoard) set(personaTag string, score int64) {
+	if old, ok := b.scores[personaTag]; ok {
+		if old == score {
+			return
+		}
+		b.remove(personaTag)
+	}
+	m := member{personaTag: personaTag, score: score}
+	i, _ := slices.BinarySearchFunc(b.sorted, m, compareMembers)
+	b.sorted = slices.Insert(b.sorted, i, m)
+	b.scores[personaTag] = score
+}
+
+func (b *memoryBoard) remove(personaTag string) {
+	i, ok := b.index(personaTag)
+	if !ok {
+		return
+	}
+	b.sorted = slices.Delete(b.sorted, i, i+1)
+	delete(b.scores, personaTag)
+}
+
+// index returns the index of the persona in the sorted members.
+func (b *memoryBoard) index(personaTag string) (int, bool) {
+	score, ok := b.scores[personaTag]
+	if !ok {
+		return 0, false
+	}
+	return slices.BinarySearchFunc(b.sorted, member{personaTag: personaTag, score: score}, compareMembers)
+}
+
+// ranks returns the ranks of the sorted members from start up to end.
+func (b *memoryBoard) ranks(start, end int) []Rank {
+	ranks := make([]Rank, 0, end-start)
+	for i := start; i < end; i++ {
+		ranks = append(ranks, Rank{Rank: i + 1, PersonaTag: b.sorted[i].personaTag, Score: b.sorted[i].score})
+	}
+	return ranks
+}
+
+// compareMembers orders members by descending score, and members with the same score by persona tag.
+func compareMembers(a, b member) int {
+	return cmp.Or(cmp.Compare(b.score, a.score), strings.Compare(a.personaTag, b.personaTag))
+}
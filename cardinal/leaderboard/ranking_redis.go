@@ -0,0 +1,108 @@
+package leaderboard
+
+import (
+	"context"
+	"errors"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rotisserie/eris"
+)
+
+const (
+	// redisBoardsKey is the redis set of the boards that have a sorted set.
+	redisBoardsKey = "LEADERBOARD:BOARDS"
+	// redisBoardKeyPrefix is the prefix of the redis sorted set of a board.
+	redisBoardKeyPrefix = "LEADERBOARD:BOARD:"
+)
+
+var _ Rankings = (*redisRankings)(nil)
+
+// redisRankings keeps every board as a redis sorted set, so that the rankings don't take up memory in the world and can
+// be read directly from redis by other services. Sorted set scores are floats, so scores are only ranked exactly up to
+// 2^53 in magnitude.
+type redisRankings struct {
+	client *redis.Client
+}
+
+// NewRedisRankings returns rankings that are kept in redis sorted sets.
+func NewRedisRankings(client *redis.Client) Rankings {
+	return &redisRankings{client: client}
+}
+
+func (r *redisRankings) Reset(ctx context.Context, entries []Entry) error {
+	boards, err := r.client.SMembers(ctx, redisBoardsKey).Result()
+	if err != nil {
+		return eris.Wrap(err, "failed to list leaderboards")
+	}
+	_, err = r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, board := range boards {
+			pipe.Del(ctx, redisBoardKey(board))
+		}
+		pipe.Del(ctx, redisBoardsKey)
+		for _, entry := range entries {
+			pipe.ZAdd(ctx, redisBoardKey(entry.Board), redisMember(entry.PersonaTag, entry.Score))
+			pipe.SAdd(ctx, redisBoardsKey, entry.Board)
+		}
+		return nil
+	})
+	return eris.Wrap(err, "failed to reset leaderboards")
+}
+
+func (r *redisRankings) Apply(ctx context.Context, changes []Change) error {
+	if len(changes) == 0 {
+		return nil
+	}
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, change := range changes {
+			if change.Removed {
+				pipe.ZRem(ctx, redisBoardKey(change.Board), change.PersonaTag)
+				continue
+			}
+			pipe.ZAdd(ctx, redisBoardKey(change.Board), redisMember(change.PersonaTag, change.Score))
+			pipe.SAdd(ctx, redisBoardsKey, change.Board)
+		}
+		return nil
+	})
+	return eris.Wrap(err, "failed to update leaderboards")
+}
+
+func (r *redisRankings) Top(ctx context.Context, board string, offset, limit int) ([]Rank, error) {
+	if limit <= 0 {
+		return []Rank{}, nil
+	}
+	return r.ranks(ctx, board, int64(offset), int64(offset+limit-1))
+}
+
+func (r *redisRankings) Around(ctx context.Context, board, personaTag string, radius int) ([]Rank, error) {
+	i, err := r.client.ZRank(ctx, redisBoardKey(board), personaTag).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, eris.Wrapf(ErrNotRanked, "%s on %s", personaTag, board)
+	} else if err != nil {
+		return nil, eris.Wrap(err, "failed to look up rank")
+	}
+	return r.ranks(ctx, board, max(0, i-int64(radius)), i+int64(radius))
+}
+
+// ranks returns the ranks of the board from start up to and including stop.
+func (r *redisRankings) ranks(ctx context.Context, board string, start, stop int64) ([]Rank, error) {
+	members, err := r.client.ZRangeWithScores(ctx, redisBoardKey(board), start, stop).Result()
+	if err != nil {
+		return nil, eris.Wrap(err, "failed to read leaderboard")
+	}
+	ranks := make([]Rank, 0, len(members))
+	for i, m := range members {
+		personaTag, _ := m.Member.(string)
+		ranks = append(ranks, Rank{Rank: int(start) + i + 1, PersonaTag: personaTag, Score: -int64(m.Score)})
+	}
+	return ranks, nil
+}
+
+func redisBoardKey(board string) string {
+	return redisBoardKeyPrefix + board
+}
+
+// redisMember returns the sorted set member of a score. Sorted sets are in ascending order, and members with the same
+// score are in lexicographic order, so the score is negated to rank higher scores first.
+func redisMember(personaTag string, score int64) redis.Z {
+	return redis.Z{Score: -float64(score), Member: personaTag}
+}
@@ -0,0 +1,89 @@
+package cardinal
+
+import (
+	"context"
+	"slices"
+
+	"github.com/rotisserie/eris"
+	"github.com/rs/zerolog/log"
+
+	"pkg.world.dev/world-engine/cardinal/worldstage"
+)
+
+// LifecycleStage is a transition in the life of a world that lifecycle hooks can be registered for.
+type LifecycleStage string
+
+const (
+	// LifecycleGameStateLoaded happens once the game state was loaded from storage when the world starts, and the
+	// pending transactions were recovered, but before the world accepts transactions.
+	LifecycleGameStateLoaded LifecycleStage = "game_state_loaded"
+	// LifecycleWorldStarted happens when the world starts ticking.
+	LifecycleWorldStarted LifecycleStage = "world_started"
+	// LifecycleTickStarted happens before the systems of a tick run.
+	LifecycleTickStarted LifecycleStage = "tick_started"
+	// LifecycleTickEnded happens once a tick was committed.
+	LifecycleTickEnded LifecycleStage = "tick_ended"
+	// LifecyclePaused happens when the world is paused. See World.Pause.
+	LifecyclePaused LifecycleStage = "paused"
+	// LifecycleResumed happens when a paused world is resumed. See World.Resume.
+	LifecycleResumed LifecycleStage = "resumed"
+	// LifecycleShutdown happens when the world shuts down, after its last tick and before its storage connection is
+	// closed.
+	LifecycleShutdown LifecycleStage = "shutdown"
+)
+
+var lifecycleStages = []LifecycleStage{
+	LifecycleGameStateLoaded,
+	LifecycleWorldStarted,
+	LifecycleTickStarted,
+	LifecycleTickEnded,
+	LifecyclePaused,
+	LifecycleResumed,
+	LifecycleShutdown,
+}
+
+// LifecycleEvent describes the transition that a lifecycle hook is called for.
+type LifecycleEvent struct {
+	Stage LifecycleStage
+	// Tick is the tick that started or ended for LifecycleTickStarted and LifecycleTickEnded, and the current tick
+	// otherwise.
+	Tick uint64
+}
+
+// LifecycleHook is called when the world goes through the lifecycle stage it was registered for.
+type LifecycleHook func(ctx context.Context, event LifecycleEvent) error
+
+// RegisterLifecycleHook registers a hook that is called whenever the world goes through the given lifecycle stage,
+// e.g. to warm caches once the game state is loaded, to announce that the world started, or to flush leaderboards at
+// the end of every tick, without changing the game's systems. Hooks of a stage are called in the order in which they
+// were registered. An error returned by a hook is logged and doesn't stop the world.
+//
+// Hooks are called synchronously: the hooks of LifecycleTickStarted and LifecycleTickEnded delay the tick, so slow work
+// should be handed off to a goroutine. Hooks must not change the game state.
+func RegisterLifecycleHook(w *World, stage LifecycleStage, hook LifecycleHook) error {
+	if w.worldStage.Current() != worldstage.Init {
+		return eris.Errorf(
+			"world state is %s, expected %s to register lifecycle hooks",
+			w.worldStage.Current(),
+			worldstage.Init,
+		)
+	}
+	if !slices.Contains(lifecycleStages, stage) {
+		return eris.Errorf("unknown lifecycle stage %q", stage)
+	}
+	if hook == nil {
+		return eris.New("lifecycle hook must not be nil")
+	}
+	w.lifecycleHooks[stage] = append(w.lifecycleHooks[stage], hook)
+	return nil
+}
+
+// runLifecycleHooks calls the hooks of the given stage.
+func (w *World) runLifecycleHooks(ctx context.Context, stage LifecycleStage, tick uint64) {
+	event := LifecycleEvent{Stage: stage, Tick: tick}
+	for _, hook := range w.lifecycleHooks[stage] {
+		if err := hook(ctx, event); err != nil {
+			log.Error().Err(err).Str("stage", string(stage)).Uint64("tick", tick).Msg("Lifecycle hook failed.")
+		}
+	}
+}
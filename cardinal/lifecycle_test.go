@@ -0,0 +1,94 @@
+package cardinal_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/testutils"
+)
+
+func TestLifecycleHooksAreCalledOnTransitions(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil)
+	world := tf.World
+
+	var (
+		mu     sync.Mutex
+		events []cardinal.LifecycleEvent
+	)
+	record := func(_ context.Context, event cardinal.LifecycleEvent) error {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, event)
+		return nil
+	}
+	for _, stage := range []cardinal.LifecycleStage{
+		cardinal.LifecycleGameStateLoaded,
+		cardinal.LifecycleWorldStarted,
+		cardinal.LifecycleTickStarted,
+		cardinal.LifecycleTickEnded,
+		cardinal.LifecyclePaused,
+		cardinal.LifecycleResumed,
+	} {
+		assert.NilError(t, cardinal.RegisterLifecycleHook(world, stage, record))
+	}
+	// A failing hook doesn't stop the world or the hooks that are registered after it.
+	assert.NilError(t, cardinal.RegisterLifecycleHook(world, cardinal.LifecycleTickEnded,
+		func(context.Context, cardinal.LifecycleEvent) error {
+			return errors.New("leaderboard is unavailable")
+		}))
+	assert.NilError(t, cardinal.RegisterLifecycleHook(world, cardinal.LifecycleTickEnded, record))
+
+	tf.DoTick()
+	assert.NilError(t, world.Pause())
+	// Pausing a paused world is not a transition.
+	assert.NilError(t, world.Pause())
+	assert.NilError(t, world.Resume())
+	tf.DoTick()
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.DeepEqual(t, []cardinal.LifecycleEvent{
+		{Stage: cardinal.LifecycleGameStateLoaded, Tick: 0},
+		{Stage: cardinal.LifecycleWorldStarted, Tick: 0},
+		{Stage: cardinal.LifecycleTickStarted, Tick: 0},
+		{Stage: cardinal.LifecycleTickEnded, Tick: 0},
+		{Stage: cardinal.LifecycleTickEnded, Tick: 0},
+		{Stage: cardinal.LifecyclePaused, Tick: 1},
+		{Stage: cardinal.LifecycleResumed, Tick: 1},
+		{Stage: cardinal.LifecycleTickStarted, Tick: 1},
+		{Stage: cardinal.LifecycleTickEnded, Tick: 1},
+		{Stage: cardinal.LifecycleTickEnded, Tick: 1},
+	}, events)
+}
+
+func TestLifecycleShutdownHookIsCalled(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil)
+	world := tf.World
+
+	shutdownTicks := make(chan uint64, 1)
+	assert.NilError(t, cardinal.RegisterLifecycleHook(world, cardinal.LifecycleShutdown,
+		func(_ context.Context, event cardinal.LifecycleEvent) error {
+			shutdownTicks <- event.Tick
+			return nil
+		}))
+	tf.DoTick()
+	assert.NilError(t, world.Shutdown(context.Background()))
+	assert.Equal(t, uint64(1), <-shutdownTicks)
+}
+
+func TestRegisterLifecycleHookErrors(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil)
+	world := tf.World
+	noop := func(context.Context, cardinal.LifecycleEvent) error { return nil }
+
+	assert.ErrorContains(t, cardinal.RegisterLifecycleHook(world, "no-such-stage", noop), "unknown lifecycle stage")
+	assert.ErrorContains(t, cardinal.RegisterLifecycleHook(world, cardinal.LifecycleWorldStarted, nil), "nil")
+
+	tf.StartWorld()
+	assert.ErrorContains(t, cardinal.RegisterLifecycleHook(world, cardinal.LifecycleWorldStarted, noop),
+		"to register lifecycle hooks")
+}
@@ -0,0 +1,26 @@
+package cardinal
+
+import (
+	"github.com/alicebob/miniredis/v2"
+	"github.com/rs/zerolog/log"
+
+	"pkg.world.dev/world-engine/cardinal/storage/redis"
+)
+
+// localPersistence saves the in-memory redis that a world started with WithLocalPersistence runs on to a file.
+type localPersistence struct {
+	redis *miniredis.Miniredis
+	path  string
+}
+
+// save writes the in-memory redis to the file. It is called after every tick and when the world is shut down, when
+// no tick is in progress, so the file always holds the state of a completed tick. Errors are logged instead of
+// stopping the world, since the file is only meant for local development.
+func (p *localPersistence) save() {
+	if p == nil {
+		return
+	}
+	if err := redis.SaveSnapshot(p.redis, p.path); err != nil {
+		log.Error().Err(err).Str("path", p.path).Msg("failed to save the world to disk")
+	}
+}
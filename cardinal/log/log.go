@@ -2,6 +2,7 @@ package log
 
 import (
 	"sort"
+	"strconv"
 
 	"github.com/rs/zerolog"
 
@@ -101,3 +102,23 @@ func CreateTraceLogger(logger *zerolog.Logger, traceID string) *zerolog.Logger {
 	newLogger := logger.With().Str("trace_id", traceID).Logger()
 	return &newLogger
 }
+
+// CreateTickLogger creates a Sub Logger with the entry {"tick" : tick}.
+func CreateTickLogger(logger *zerolog.Logger, tick uint64) *zerolog.Logger {
+	newLogger := logger.With().Uint64("tick", tick).Logger()
+	return &newLogger
+}
+
+// CreateTxLogger creates a Sub Logger with the entries {"tx_hash" : txHash, "persona" : personaTag}, which tag the logs
+// of the processing of a transaction.
+func CreateTxLogger(logger *zerolog.Logger, txHash types.TxHash, personaTag string) *zerolog.Logger {
+	newLogger := logger.With().Str("tx_hash", string(txHash)).Str("persona", personaTag).Logger()
+	return &newLogger
+}
+
+// CreateEntityLogger creates a Sub Logger with the entry {"entity_id" : entityID}. The entity ID is a string, like in
+// the logs of entity updates.
+func CreateEntityLogger(logger *zerolog.Logger, entityID types.EntityID) *zerolog.Logger {
+	newLogger := logger.With().Str("entity_id", strconv.FormatUint(uint64(entityID), 10)).Logger()
+	return &newLogger
+}
@@ -2,6 +2,7 @@ package log_test
 
 import (
 	"bytes"
+	"encoding/json"
 	"strings"
 	"testing"
 	"time"
@@ -12,10 +13,12 @@ import (
 	"pkg.world.dev/world-engine/assert"
 	"pkg.world.dev/world-engine/cardinal"
 	"pkg.world.dev/world-engine/cardinal/log"
+	"pkg.world.dev/world-engine/cardinal/message"
 	"pkg.world.dev/world-engine/cardinal/search/filter"
 	"pkg.world.dev/world-engine/cardinal/testutils"
 	"pkg.world.dev/world-engine/cardinal/types"
 	"pkg.world.dev/world-engine/cardinal/types/engine"
+	"pkg.world.dev/world-engine/sign"
 )
 
 type SendEnergyTx struct {
@@ -180,7 +183,8 @@ func TestWorldLogger(t *testing.T) {
 				"component_name":"EnergyComp",
 				"component_id":2,
 				"message":"entity updated",
-				"system":"log_test.testSystemWarningTrigger"
+				"system":"log_test.testSystemWarningTrigger",
+				"tick":0
 			}`, logStrings[2],
 	)
 
@@ -220,3 +224,56 @@ func TestWorldLogger(t *testing.T) {
 			}`, entityCreationStrings[1],
 	)
 }
+
+func sendEnergySystem(wCtx engine.Context) error {
+	wCtx.Logger().Info().Msg("sending energy")
+	return cardinal.EachMessage[SendEnergyTx, SendEnergyTxResult](wCtx,
+		func(tx message.TxData[SendEnergyTx]) (SendEnergyTxResult, error) {
+			cardinal.EntityLogger(wCtx, 7).Info().Msg("energy sent")
+			return SendEnergyTxResult{}, nil
+		})
+}
+
+func TestSystemLogsAreTaggedWithTickSystemAndTransaction(t *testing.T) {
+	var buf bytes.Buffer
+	bufLogger := zerolog.New(&buf)
+	tf := testutils.NewTestFixture(t, nil, cardinal.WithCustomLogger(bufLogger))
+	world := tf.World
+	assert.NilError(t, cardinal.RegisterMessage[SendEnergyTx, SendEnergyTxResult](world, "send-energy"))
+	assert.NilError(t, cardinal.RegisterSystems(world, sendEnergySystem))
+	tf.StartWorld()
+	tf.DoTick()
+	sendEnergy, ok := world.GetMessageByFullName("game.send-energy")
+	assert.True(t, ok)
+	txHash := tf.AddTransaction(sendEnergy.ID(), SendEnergyTx{}, &sign.Transaction{PersonaTag: "alice"})
+	buf.Reset()
+	tf.DoTick()
+
+	logs := map[string]string{}
+	for _, line := range strings.Split(buf.String(), "\n") {
+		var entry map[string]any
+		if json.Unmarshal([]byte(line), &entry) == nil {
+			if msg, ok := entry["message"].(string); ok {
+				logs[msg] = line
+			}
+		}
+	}
+	require.JSONEq(t, `{
+		"level":"info",
+		"tick":1,
+		"system":"log_test.sendEnergySystem",
+		"message":"sending energy"
+	}`, logs["sending energy"])
+	require.JSONEq(t, `{
+		"level":"info",
+		"tick":1,
+		"system":"log_test.sendEnergySystem",
+		"tx_hash":"`+string(txHash)+`",
+		"persona":"alice",
+		"entity_id":"7",
+		"message":"energy sent"
+	}`, logs["energy sent"])
+	// The tags of the previous tick and systems are not repeated
+	assert.Equal(t, 1, strings.Count(logs["energy sent"], `"system"`))
+	assert.Equal(t, 1, strings.Count(logs["energy sent"], `"tick"`))
+}
@@ -0,0 +1,134 @@
+// Package merkle implements the binary merkle trees that Cardinal uses to commit to its game state. The trees are built
+// like the trees of Certificate Transparency (RFC 6962): leaves and inner nodes are hashed with SHA-256 behind a
+// different prefix byte, so that a leaf can't be passed off as an inner node, and a node without a sibling is moved up
+// to the next level unchanged. A proof that a leaf is part of a tree can therefore be verified by any client that
+// implements RFC 6962 audit paths.
+package merkle
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/rotisserie/eris"
+)
+
+const (
+	leafPrefix = 0x00
+	nodePrefix = 0x01
+)
+
+// Hash is the hash of a leaf or a node of a tree. It is encoded as a hex string in JSON.
+type Hash [sha256.Size]byte
+
+func (h Hash) String() string {
+	return hex.EncodeToString(h[:])
+}
+
+func (h Hash) MarshalText() ([]byte, error) {
+	return []byte(h.String()), nil
+}
+
+func (h *Hash) UnmarshalText(text []byte) error {
+	bz, err := hex.DecodeString(string(text))
+	if err != nil {
+		return eris.Wrap(err, "invalid hash")
+	}
+	if len(bz) != len(h) {
+		return eris.Errorf("hash must be %d bytes, got %d", len(h), len(bz))
+	}
+	copy(h[:], bz)
+	return nil
+}
+
+// Proof is the audit path of a leaf: the hashes of the siblings of the nodes on the way from the leaf to the root.
+type Proof struct {
+	// Index is the position of the leaf in the tree.
+	Index uint64 `json:"index"`
+	// Size is the number of leaves in the tree.
+	Size uint64 `json:"size"`
+	Path []Hash `json:"path" swaggertype:"array,string"`
+}
+
+// LeafHash returns the hash of the leaf with the given data.
+func LeafHash(data []byte) Hash {
+	h := sha256.New()
+	h.Write([]byte{leafPrefix})
+	h.Write(data)
+	return Hash(h.Sum(nil))
+}
+
+func nodeHash(left, right Hash) Hash {
+	h := sha256.New()
+	h.Write([]byte{nodePrefix})
+	h.Write(left[:])
+	h.Write(right[:])
+	return Hash(h.Sum(nil))
+}
+
+// Root returns the root of the tree of the given leaf hashes. The root of an empty tree is the hash of no data.
+func Root(leaves []Hash) Hash {
+	if len(leaves) == 0 {
+		return sha256.Sum256(nil)
+	}
+	level := leaves
+	for len(level) > 1 {
+		level = nextLevel(level)
+	}
+	return level[0]
+}
+
+// Prove returns the proof that the leaf at the given index is part of the tree of the given leaf hashes.
+func Prove(leaves []Hash, index int) (Proof, error) {
+	if index < 0 || index >= len(leaves) {
+		return Proof{}, eris.Errorf("leaf %d is not part of a tree of %d leaves", index, len(leaves))
+	}
+	proof := Proof{Index: uint64(index), Size: uint64(len(leaves))}
+	level := leaves
+	for i := index; len(level) > 1; i /= 2 {
+		if sibling := i ^ 1; sibling < len(level) {
+			proof.Path = append(proof.Path, level[sibling])
+		}
+		level = nextLevel(level)
+	}
+	return proof, nil
+}
+
+// Verify reports whether the proof shows that the leaf is part of the tree with the given root. It follows the
+// verification of inclusion proofs of RFC 9162, section 2.1.3.2.
+func (p Proof) Verify(root, leaf Hash) bool {
+	if p.Index >= p.Size {
+		return false
+	}
+	fn, sn := p.Index, p.Size-1
+	r := leaf
+	for _, h := range p.Path {
+		if sn == 0 {
+			return false
+		}
+		if fn%2 == 1 || fn == sn {
+			r = nodeHash(h, r)
+			for fn%2 == 0 && fn != 0 {
+				fn /= 2
+				sn /= 2
+			}
+		} else {
+			r = nodeHash(r, h)
+		}
+		fn /= 2
+		sn /= 2
+	}
+	return sn == 0 && r == root
+}
+
+// nextLevel hashes the nodes of a level in pairs. The last node of a level with an odd number of nodes is moved up
+// unchanged.
+func nextLevel(level []Hash) []Hash {
+	next := make([]Hash, 0, (len(level)+1)/2)
+	for i := 0; i+1 < len(level); i += 2 {
+		next = append(next, nodeHash(level[i], level[i+1]))
+	}
+	if len(level)%2 == 1 {
+		next = append(next, level[len(level)-1])
+	}
+	return next
+}
@@ -0,0 +1,74 @@
+package merkle_test
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal/merkle"
+)
+
+func leaves(n int) []merkle.Hash {
+	hashes := make([]merkle.Hash, n)
+	for i := range hashes {
+		hashes[i] = merkle.LeafHash([]byte(fmt.Sprint(i)))
+	}
+	return hashes
+}
+
+// rfc6962Root computes the root with the recursive definition of RFC 6962, section 2.1.
+func rfc6962Root(hashes []merkle.Hash) merkle.Hash {
+	if len(hashes) == 1 {
+		return hashes[0]
+	}
+	k := 1
+	for k*2 < len(hashes) {
+		k *= 2
+	}
+	left, right := rfc6962Root(hashes[:k]), rfc6962Root(hashes[k:])
+	return sha256.Sum256(append(append([]byte{1}, left[:]...), right[:]...))
+}
+
+func TestRootMatchesRFC6962(t *testing.T) {
+	assert.Equal(t, merkle.Hash(sha256.Sum256(nil)), merkle.Root(nil))
+	for n := 1; n <= 20; n++ {
+		assert.Equal(t, rfc6962Root(leaves(n)), merkle.Root(leaves(n)), "tree of %d leaves", n)
+	}
+}
+
+func TestProofsVerify(t *testing.T) {
+	for n := 1; n <= 20; n++ {
+		hashes := leaves(n)
+		root := merkle.Root(hashes)
+		for i := range hashes {
+			proof, err := merkle.Prove(hashes, i)
+			assert.NilError(t, err)
+			assert.Check(t, proof.Verify(root, hashes[i]), "leaf %d of %d", i, n)
+			// The proof doesn't hold for another leaf, position or tree.
+			assert.Check(t, !proof.Verify(root, merkle.LeafHash([]byte("other"))))
+			assert.Check(t, !proof.Verify(merkle.Root(leaves(n+1)), hashes[i]))
+			if n > 1 {
+				moved := proof
+				moved.Index = uint64((i + 1) % n)
+				assert.Check(t, !moved.Verify(root, hashes[i]))
+			}
+		}
+	}
+	_, err := merkle.Prove(leaves(3), 3)
+	assert.ErrorContains(t, err, "not part of a tree")
+}
+
+func TestHashIsHexInJSON(t *testing.T) {
+	proof, err := merkle.Prove(leaves(2), 0)
+	assert.NilError(t, err)
+	bz, err := json.Marshal(proof)
+	assert.NilError(t, err)
+	assert.Equal(t, fmt.Sprintf(`{"index":0,"size":2,"path":["%s"]}`, leaves(2)[1]), string(bz))
+
+	var decoded merkle.Proof
+	assert.NilError(t, json.Unmarshal(bz, &decoded))
+	assert.DeepEqual(t, proof, decoded)
+	assert.ErrorContains(t, json.Unmarshal([]byte(`{"path":["abcd"]}`), &decoded), "hash must be 32 bytes")
+}
@@ -11,6 +11,9 @@ import (
 
 	"pkg.world.dev/world-engine/cardinal/abi"
 	"pkg.world.dev/world-engine/cardinal/codec"
+	ecslog "pkg.world.dev/world-engine/cardinal/log"
+	"pkg.world.dev/world-engine/cardinal/sdk"
+	"pkg.world.dev/world-engine/cardinal/server/utils"
 	"pkg.world.dev/world-engine/cardinal/types"
 	"pkg.world.dev/world-engine/cardinal/types/engine"
 	"pkg.world.dev/world-engine/sign"
@@ -41,6 +44,18 @@ type MessageType[In, Out any] struct { //nolint:revive // this is fine for now.
 	group      string
 	inEVMType  *ethereumAbi.Type
 	outEVMType *ethereumAbi.Type
+	adminOnly  bool
+	// legacyInputs are the inputs of the message that are accepted from clients of older versions of the HTTP API,
+	// keyed by API version.
+	legacyInputs map[string]legacyInput[In]
+	// authorizer rejects transactions of the message before they are queued. See SetAuthorizer.
+	authorizer func(tx *sign.Transaction, msg In) error
+}
+
+// legacyInput is an earlier schema of the input of a message. See WithLegacyInput.
+type legacyInput[In any] struct {
+	decode func([]byte) (In, error)
+	fields map[string]any
 }
 
 // NewMessageType creates a new message type. It accepts two generic type parameters: the first for the message input,
@@ -82,6 +97,10 @@ func (t *MessageType[In, Out]) IsEVMCompatible() bool {
 	return t.inEVMType != nil && t.outEVMType != nil
 }
 
+func (t *MessageType[In, Out]) IsAdminOnly() bool {
+	return t.adminOnly
+}
+
 func (t *MessageType[In, Out]) ID() types.MessageID {
 	if !t.isIDSet {
 		panic(fmt.Sprintf("id on msg %q is not set", t.name))
@@ -130,8 +149,14 @@ func (t *MessageType[In, Out]) GetReceipt(wCtx engine.Context, hash types.TxHash
 	return value, errs, true
 }
 
+// Each calls fn with every transaction of the message in the tick, and records its result or error in the receipt of
+// the transaction. The logs of fn, and of the cardinal functions it calls, are tagged with the hash and the persona of
+// the transaction.
 func (t *MessageType[In, Out]) Each(wCtx engine.Context, fn func(TxData[In]) (Out, error)) {
+	logger := *wCtx.Logger()
+	defer wCtx.SetLogger(logger)
 	for _, txData := range t.In(wCtx) {
+		wCtx.SetLogger(*ecslog.CreateTxLogger(&logger, txData.Hash, txData.Tx.PersonaTag))
 		if result, err := fn(txData); err != nil {
 			err = eris.Wrap(err, "")
 			wCtx.Logger().Err(err).Msgf("tx %s from %s encountered an error with message=%+v and stack trace:\n %s",
@@ -171,6 +196,29 @@ func (t *MessageType[In, Out]) Decode(bytes []byte) (any, error) {
 	return codec.Decode[In](bytes)
 }
 
+// DecodeForAPIVersion decodes a message that was sent to the given version of the HTTP API. If the message has a
+// legacy input for that version, the bytes are decoded as the legacy input and upgraded to the "In" type. Unlike
+// Decode, it rejects payloads that don't match the input, see ValidatePayload.
+func (t *MessageType[In, Out]) DecodeForAPIVersion(apiVersion string, bytes []byte) (any, error) {
+	legacy, ok := t.legacyInputs[apiVersion]
+	if !ok {
+		if err := ValidatePayload[In](bytes); err != nil {
+			return nil, err
+		}
+		return t.Decode(bytes)
+	}
+	return legacy.decode(bytes)
+}
+
+// GetLegacyInFieldInformation returns the fields of the legacy inputs of the message, keyed by API version.
+func (t *MessageType[In, Out]) GetLegacyInFieldInformation() map[string]map[string]any {
+	fields := make(map[string]map[string]any, len(t.legacyInputs))
+	for version, legacy := range t.legacyInputs {
+		fields[version] = legacy.fields
+	}
+	return fields
+}
+
 // ABIEncode encodes the input to the message's matching evm type. If the input is not either of the message's
 // evm types, an error is returned.
 func (t *MessageType[In, Out]) ABIEncode(v any) ([]byte, error) {
@@ -216,6 +264,68 @@ func (t *MessageType[In, Out]) DecodeEVMBytes(bz []byte) (any, error) {
 	return input, nil
 }
 
+// EVMFixtures returns the ABI fixtures of sample values of the message's "In" and "Out" types.
+func (t *MessageType[In, Out]) EVMFixtures() ([]abi.Fixture, error) {
+	if !t.IsEVMCompatible() {
+		return nil, eris.Wrap(ErrEVMTypeNotSet, "")
+	}
+	in, err := abi.NewSampleFixture[In](t.FullName() + " input")
+	if err != nil {
+		return nil, err
+	}
+	out, err := abi.NewSampleFixture[Out](t.FullName() + " output")
+	if err != nil {
+		return nil, err
+	}
+	return []abi.Fixture{in, out}, nil
+}
+
+// EVMBinding returns the binding of the message, from which the EVM bindings of the world are generated.
+func (t *MessageType[In, Out]) EVMBinding() (abi.Binding, error) {
+	if !t.IsEVMCompatible() {
+		return abi.Binding{}, eris.Wrap(ErrEVMTypeNotSet, "")
+	}
+	return abi.Binding{
+		Name: t.FullName(),
+		In:   reflect.TypeOf(new(In)).Elem(),
+		Out:  reflect.TypeOf(new(Out)).Elem(),
+	}, nil
+}
+
+// ClientEndpoint returns the endpoint of the message, from which the client SDKs of the world are generated.
+func (t *MessageType[In, Out]) ClientEndpoint() sdk.Endpoint {
+	return sdk.Endpoint{
+		Kind:  sdk.KindMessage,
+		Group: t.group,
+		Name:  t.name,
+		URL:   utils.GetTxURL(t.group, t.name),
+		In:    reflect.TypeOf(new(In)).Elem(),
+		Out:   reflect.TypeOf(new(Out)).Elem(),
+	}
+}
+
+// SetAuthorizer sets a function that is called with every transaction of the message, after its signature was
+// verified and before it is queued for the next tick. If fn returns an error, the transaction is rejected and never
+// reaches the systems, which saves tick time on transactions that are obviously invalid or not allowed, e.g. a move to
+// a position outside the map. fn must not depend on the game state, since it runs outside of ticks and concurrently
+// with them.
+func (t *MessageType[In, Out]) SetAuthorizer(fn func(tx *sign.Transaction, msg In) error) {
+	t.authorizer = fn
+}
+
+// Authorize returns the error of the authorizer of the message for the given transaction, or nil if the transaction
+// is authorized or the message has no authorizer.
+func (t *MessageType[In, Out]) Authorize(tx *sign.Transaction, msg any) error {
+	if t.authorizer == nil {
+		return nil
+	}
+	in, ok := msg.(In)
+	if !ok {
+		return eris.Errorf("expected message of type %T, got %T", in, msg)
+	}
+	return t.authorizer(tx, in)
+}
+
 // GetInFieldInformation returns a map of the fields of the message's "In" type and it's field types.
 func (t *MessageType[In, Out]) GetInFieldInformation() map[string]any {
 	return types.GetFieldInformation(reflect.TypeOf(new(In)).Elem())
@@ -240,6 +350,52 @@ func WithMsgEVMSupport[In, Out any]() MessageOption[In, Out] {
 	}
 }
 
+// WithAdminOnly makes the message privileged: transactions of this message are only accepted if they are signed by one
+// of the admin signers of the world (see cardinal.WithAdminSigners), and can not be sent from the EVM. Use it for
+// operator actions such as granting currency or banning a player, so that systems don't need to check the sender.
+func WithAdminOnly[In, Out any]() MessageOption[In, Out] {
+	return func(mt *MessageType[In, Out]) {
+		mt.adminOnly = true
+	}
+}
+
+// WithAuthorizer sets the authorizer of the message, which can reject transactions before they are queued. See
+// MessageType.SetAuthorizer.
+func WithAuthorizer[In, Out any](fn func(tx *sign.Transaction, msg In) error) MessageOption[In, Out] {
+	return func(mt *MessageType[In, Out]) {
+		mt.SetAuthorizer(fn)
+	}
+}
+
+// WithLegacyInput keeps game clients that use an older version of the HTTP API working after the schema of the
+// message's input changed. Payloads sent to the given API version are decoded as Legacy and converted to the current
+// input with upgrade, so systems only ever see the current input. Legacy is inferred from upgrade, e.g.
+// message.WithLegacyInput[MoveMsg, MoveResult]("v1", upgradeMoveV1).
+func WithLegacyInput[In, Out, Legacy any](apiVersion string, upgrade func(Legacy) (In, error)) MessageOption[In, Out] {
+	if !isStruct[Legacy]() {
+		panic(fmt.Sprintf("Invalid legacy input for API version %q: the legacy input must be a struct", apiVersion))
+	}
+	return func(mt *MessageType[In, Out]) {
+		if mt.legacyInputs == nil {
+			mt.legacyInputs = make(map[string]legacyInput[In])
+		}
+		mt.legacyInputs[apiVersion] = legacyInput[In]{
+			decode: func(bytes []byte) (In, error) {
+				var in In
+				if err := ValidatePayload[Legacy](bytes); err != nil {
+					return in, err
+				}
+				legacy, err := codec.Decode[Legacy](bytes)
+				if err != nil {
+					return in, err
+				}
+				return upgrade(legacy)
+			},
+			fields: types.GetFieldInformation(reflect.TypeOf(new(Legacy)).Elem()),
+		}
+	}
+}
+
 // WithCustomMessageGroup sets a custom group for the message.
 // By default, messages are registered under the "game" group which maps it to the /tx/game/:txType route.
 // This option allows you to set a custom group, which allow you to register the message
@@ -1,6 +1,7 @@
 package message
 
 import (
+	"fmt"
 	"testing"
 
 	"pkg.world.dev/world-engine/assert"
@@ -91,6 +92,25 @@ func TestMessageFullName(t *testing.T) {
 	assert.Equal(t, withGroup.FullName(), "bar.foo")
 }
 
+func TestAuthorize(t *testing.T) {
+	type MoveMsg struct {
+		X int
+	}
+	noAuthorizer := NewMessageType[MoveMsg, EmptyMsgResult]("move")
+	assert.NilError(t, noAuthorizer.Authorize(&sign.Transaction{PersonaTag: "foo"}, MoveMsg{X: -1}))
+
+	msg := NewMessageType[MoveMsg, EmptyMsgResult]("move",
+		WithAuthorizer[MoveMsg, EmptyMsgResult](func(tx *sign.Transaction, msg MoveMsg) error {
+			if msg.X < 0 {
+				return fmt.Errorf("%s can't move to %d", tx.PersonaTag, msg.X)
+			}
+			return nil
+		}))
+	assert.NilError(t, msg.Authorize(&sign.Transaction{PersonaTag: "foo"}, MoveMsg{X: 1}))
+	assert.ErrorContains(t, msg.Authorize(&sign.Transaction{PersonaTag: "foo"}, MoveMsg{X: -1}), "foo can't move to -1")
+	assert.IsError(t, msg.Authorize(&sign.Transaction{PersonaTag: "foo"}, EmptyMsgResult{}))
+}
+
 func TestIsValidMessageText(t *testing.T) {
 	testCases := []struct {
 		testName       string
@@ -0,0 +1,237 @@
+package message
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	// validateTag is the struct tag that marks the fields of a message input that must be set, as in
+	// `validate:"required"`.
+	validateTag = "validate"
+	requiredTag = "required"
+)
+
+// FieldError is a problem with a field of a message payload.
+type FieldError struct {
+	// Field is the path of the field in the payload, e.g. "target.id".
+	Field   string
+	Message string
+}
+
+func (e FieldError) String() string {
+	return e.Field + ": " + e.Message
+}
+
+// PayloadError is returned when a message payload doesn't match the input of the message. It lists every problem with
+// the payload, so that a client can fix them all at once.
+type PayloadError struct {
+	Fields []FieldError
+}
+
+func (e *PayloadError) Error() string {
+	problems := make([]string, 0, len(e.Fields))
+	for _, field := range e.Fields {
+		problems = append(problems, field.String())
+	}
+	return "invalid message payload: " + strings.Join(problems, "; ")
+}
+
+var (
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// ValidatePayload checks that the JSON payload bz can be decoded into T without losing data: every field of the
+// payload must be a field of T and have the type of that field, and the fields of T that are tagged with
+// `validate:"required"` must be set and not null. Otherwise, it returns a *PayloadError with a message for every
+// problem. Fields of nested structs are checked too, unless their type decodes itself from JSON.
+func ValidatePayload[T any](bz []byte) error {
+	var fields []FieldError
+	validateValue(bz, reflect.TypeOf(new(T)).Elem(), "", &fields)
+	if len(fields) > 0 {
+		return &PayloadError{Fields: fields}
+	}
+	return nil
+}
+
+// validateValue records the problems of the JSON value raw, which is decoded into a value of type t at path.
+func validateValue(raw json.RawMessage, t reflect.Type, path string, fields *[]FieldError) {
+	if isNull(raw) {
+		return
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) && isStructType(t.Elem()) {
+		var elems []json.RawMessage
+		if err := json.Unmarshal(raw, &elems); err != nil {
+			*fields = append(*fields, FieldError{Field: fieldPath(path), Message: typeErrorMessage(err, t)})
+			return
+		}
+		for i, elem := range elems {
+			validateValue(elem, t.Elem(), path+"["+strconv.Itoa(i)+"]", fields)
+		}
+		return
+	}
+	if !isStructType(t) {
+		if err := json.Unmarshal(raw, reflect.New(t).Interface()); err != nil {
+			*fields = append(*fields, FieldError{Field: fieldPath(path), Message: typeErrorMessage(err, t)})
+		}
+		return
+	}
+
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &object); err != nil {
+		*fields = append(*fields, FieldError{Field: fieldPath(path), Message: typeErrorMessage(err, t)})
+		return
+	}
+	known := jsonFields(t)
+	seen := map[string]bool{}
+	for _, key := range sortedKeys(object) {
+		field, ok := lookupField(known, key)
+		if !ok {
+			*fields = append(*fields, FieldError{Field: joinPath(path, key), Message: "unknown field"})
+			continue
+		}
+		if !isNull(object[key]) {
+			seen[field.name] = true
+		}
+		validateValue(object[key], field.typ, joinPath(path, field.name), fields)
+	}
+	for _, field := range known {
+		if field.required && !seen[field.name] {
+			*fields = append(*fields, FieldError{Field: joinPath(path, field.name), Message: "is required"})
+		}
+	}
+}
+
+// jsonField is a field of a struct as it appears in JSON.
+type jsonField struct {
+	name     string
+	typ      reflect.Type
+	required bool
+}
+
+// jsonFields returns the fields of a struct that encoding/json decodes, including the fields of embedded structs.
+func jsonFields(t reflect.Type) []jsonField {
+	var fields []jsonField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		ft := f.Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if f.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			fields = append(fields, jsonFields(ft)...)
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields = append(fields, jsonField{name: name, typ: f.Type, required: f.Tag.Get(validateTag) == requiredTag})
+	}
+	return fields
+}
+
+// lookupField finds the field that a JSON key is decoded into. Like encoding/json, an exact match is preferred, and
+// keys are otherwise matched case-insensitively.
+func lookupField(fields []jsonField, key string) (jsonField, bool) {
+	for _, field := range fields {
+		if field.name == key {
+			return field, true
+		}
+	}
+	for _, field := range fields {
+		if strings.EqualFold(field.name, key) {
+			return field, true
+		}
+	}
+	return jsonField{}, false
+}
+
+// isStructType returns true if t, or the type it points to, is a struct whose fields are decoded one by one.
+func isStructType(t reflect.Type) bool {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	p := reflect.PointerTo(t)
+	return !p.Implements(jsonUnmarshalerType) && !p.Implements(textUnmarshalerType)
+}
+
+func isNull(raw json.RawMessage) bool {
+	return bytes.Equal(bytes.TrimSpace(raw), []byte("null"))
+}
+
+// typeErrorMessage describes why a JSON value could not be decoded into a value of type t.
+func typeErrorMessage(err error, t reflect.Type) string {
+	if typeErr, ok := err.(*json.UnmarshalTypeError); ok { //nolint:errorlint // json returns it unwrapped
+		return fmt.Sprintf("must be %s, not %s", jsonKind(typeErr.Type), typeErr.Value)
+	}
+	if _, ok := err.(*json.SyntaxError); ok { //nolint:errorlint // json returns it unwrapped
+		return "is not valid JSON"
+	}
+	return fmt.Sprintf("must be %s: %v", jsonKind(t), err)
+}
+
+// jsonKind describes the JSON values that a Go type is decoded from.
+func jsonKind(t reflect.Type) string {
+	switch t.Kind() { //nolint:exhaustive // the other kinds are described by their Go type
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return "an integer"
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "a non-negative integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.String:
+		return "a string"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	case reflect.Map, reflect.Struct:
+		return "an object"
+	default:
+		return "a " + t.String()
+	}
+}
+
+func fieldPath(path string) string {
+	if path == "" {
+		return "(root)"
+	}
+	return path
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func sortedKeys(object map[string]json.RawMessage) []string {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
@@ -0,0 +1,69 @@
+package message
+
+import (
+	"errors"
+	"testing"
+
+	"pkg.world.dev/world-engine/assert"
+)
+
+type Position struct {
+	X, Y int
+}
+
+type Waypoint struct {
+	Position
+	Label string `json:"label"`
+}
+
+type MoveMsg struct {
+	Direction string     `json:"direction" validate:"required"`
+	Speed     uint8      `json:"speed"`
+	Target    *Position  `json:"target"`
+	Path      []Waypoint `json:"path,omitempty"`
+	Ignored   string     `json:"-"`
+}
+
+func TestValidatePayloadAcceptsMatchingPayloads(t *testing.T) {
+	for _, payload := range []string{
+		`{"direction":"up"}`,
+		`{"Direction":"up","speed":3,"target":{"X":1,"Y":2},"path":[{"X":1,"label":"a"}]}`,
+		`{"direction":"up","target":null,"path":null}`,
+	} {
+		assert.NilError(t, ValidatePayload[MoveMsg]([]byte(payload)), payload)
+	}
+}
+
+func TestValidatePayloadReportsEveryProblem(t *testing.T) {
+	err := ValidatePayload[MoveMsg]([]byte(
+		`{"speed":-1,"target":{"X":"left","Z":0},"path":[{"label":7}],"Ignored":"x","color":"red"}`,
+	))
+	var payloadErr *PayloadError
+	assert.Assert(t, errors.As(err, &payloadErr))
+	assert.DeepEqual(t, []FieldError{
+		{Field: "Ignored", Message: "unknown field"},
+		{Field: "color", Message: "unknown field"},
+		{Field: "path[0].label", Message: "must be a string, not number"},
+		{Field: "speed", Message: "must be a non-negative integer, not number -1"},
+		{Field: "target.X", Message: "must be an integer, not string"},
+		{Field: "target.Z", Message: "unknown field"},
+		{Field: "direction", Message: "is required"},
+	}, payloadErr.Fields)
+	assert.ErrorContains(t, err, "invalid message payload: Ignored: unknown field; color: unknown field")
+
+	err = ValidatePayload[MoveMsg]([]byte(`{"direction":null}`))
+	assert.ErrorContains(t, err, "direction: is required")
+	err = ValidatePayload[MoveMsg]([]byte(`["up"]`))
+	assert.ErrorContains(t, err, "(root): must be an object, not array")
+}
+
+func TestDecodeForAPIVersionRejectsInvalidPayloads(t *testing.T) {
+	msg := NewMessageType[MoveMsg, EmptyMsgResult]("move")
+	_, err := msg.DecodeForAPIVersion("v2", []byte(`{"direction":"up","sped":3}`))
+	assert.ErrorContains(t, err, "sped: unknown field")
+
+	// Decode stays lenient, since it decodes messages that were already accepted
+	decoded, err := msg.Decode([]byte(`{"direction":"up","sped":3}`))
+	assert.NilError(t, err)
+	assert.Equal(t, "up", decoded.(MoveMsg).Direction)
+}
@@ -8,20 +8,46 @@ import (
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 
+	"pkg.world.dev/world-engine/cardinal/admin"
+	"pkg.world.dev/world-engine/cardinal/codec"
+	"pkg.world.dev/world-engine/cardinal/eventlog"
+	"pkg.world.dev/world-engine/cardinal/faults"
 	"pkg.world.dev/world-engine/cardinal/gamestate"
 	"pkg.world.dev/world-engine/cardinal/receipt"
 	"pkg.world.dev/world-engine/cardinal/router"
 	"pkg.world.dev/world-engine/cardinal/server"
+	"pkg.world.dev/world-engine/cardinal/storage/redis"
+	shard "pkg.world.dev/world-engine/rift/shard/v2"
 )
 
 // WorldOption represents an option that can be used to augment how the cardinal.World will be run.
 type WorldOption struct {
 	serverOption   server.Option
 	cardinalOption Option
+	config         *Config
+	profile        Profile
+	faults         *faults.Injector
 }
 
 type Option func(*World)
 
+// WithConfig makes NewWorld use the given config, e.g. one returned by LoadConfig, instead of loading it from the
+// environment. The config is validated when the world is created.
+func WithConfig(cfg Config) WorldOption {
+	return WorldOption{
+		config: &cfg,
+	}
+}
+
+// WithProfile makes NewWorld use the defaults of the given profile, unless CARDINAL_PROFILE selects another one. The
+// config values of the profile are only applied when the config is loaded from the environment, a config set with
+// WithConfig is used as is. The other options passed to NewWorld override the options of the profile.
+func WithProfile(profile Profile) WorldOption {
+	return WorldOption{
+		profile: profile,
+	}
+}
+
 // WithPort sets the port that the HTTP server will run on.
 func WithPort(port string) WorldOption {
 	return WorldOption{
@@ -47,9 +73,60 @@ func WithDisableSignatureVerification() WorldOption {
 	}
 }
 
-// WithTickChannel sets the channel that will be used to decide when world.doTick is executed. If unset, a loop interval
-// of 1 second will be set. To set some other time, use: WithTickChannel(time.Tick(<some-duration>)). Tests can pass
-// in a channel controlled by the test for fine-grained control over when ticks are executed.
+// WithAdminSigners sets the addresses that can sign transactions of admin-only messages (see message.WithAdminOnly).
+// Admin signers can also be set with the CARDINAL_ADMIN_SIGNERS environment variable.
+func WithAdminSigners(addresses ...string) WorldOption {
+	return WorldOption{
+		serverOption: server.WithAdminSigners(addresses...),
+	}
+}
+
+// WithReplyLimits sets the maximum size of the replies of reads, over HTTP and from the EVM, so that a single read
+// can't serialize an unbounded amount of game state. List replies (CQL, debug state, receipts) are truncated and carry
+// the cursor to read the rest. Other replies that are too large are rejected. The default limits are
+// handler.DefaultMaxReplyBytes and handler.DefaultMaxReplyItems.
+func WithReplyLimits(limits server.ReplyLimits) WorldOption {
+	return WorldOption{
+		serverOption: server.WithReplyLimits(limits),
+		cardinalOption: func(world *World) {
+			world.maxReplyBytes = limits.MaxBytes
+		},
+	}
+}
+
+// WithHTTPAuth requires the requests to the HTTP server to be authenticated, except for the public routes: with an API
+// key for server-to-server clients such as Nakama and indexers, or with a JWT for game clients that access the server
+// directly. Without it, anyone who can reach the port can submit transactions. API keys and a JWT secret can also be
+// set with CARDINAL_API_KEYS and CARDINAL_JWT_SECRET, along with the issuer and audience of the JWTs with
+// CARDINAL_JWT_ISSUER and CARDINAL_JWT_AUDIENCE, which this option overrides.
+func WithHTTPAuth(auth server.Auth) WorldOption {
+	return WorldOption{
+		serverOption: server.WithAuth(auth),
+	}
+}
+
+// WithHTTPLimits sets the origins of the browser-based games that can call the HTTP server, the maximum size of request
+// bodies, and the number of requests that a client IP can make per window. See server.HTTPLimits. The origins can
+// also be set with CARDINAL_CORS_ORIGINS, which this option overrides.
+func WithHTTPLimits(limits server.HTTPLimits) WorldOption {
+	return WorldOption{
+		serverOption: server.WithHTTPLimits(limits),
+	}
+}
+
+// WithAPIVersionPolicy sets the deprecation and sunset policy of a version of the HTTP API, e.g. to announce to game
+// clients that still use server.APIVersionV1 when it will stop being served. Use server.Unversioned to set the policy
+// of the routes that are served without a version prefix.
+func WithAPIVersionPolicy(version string, policy server.VersionPolicy) WorldOption {
+	return WorldOption{
+		serverOption: server.WithVersionPolicy(version, policy),
+	}
+}
+
+// WithTickChannel sets the channel that will be used to decide when world.doTick is executed. If unset, ticks are
+// scheduled at CARDINAL_TICK_RATE and late ticks catch up according to CARDINAL_TICK_DRIFT_MODE. Tests can pass
+// in a channel controlled by the test for fine-grained control over when ticks are executed. Tick drift is only
+// reported for channels that send the time at which each tick was scheduled, as time.Tick does.
 func WithTickChannel(ch <-chan time.Time) WorldOption {
 	return WorldOption{
 		cardinalOption: func(world *World) {
@@ -68,6 +145,368 @@ func WithTickDoneChannel(ch chan<- uint64) WorldOption {
 	}
 }
 
+// WithEventLog enables the event log gRPC service on the given port (eventlog.DefaultPort is used if the port is
+// empty). Once enabled, the events and receipts of every tick are persisted so that indexers can stream them from any
+// tick onward.
+func WithEventLog(port string) WorldOption {
+	return WorldOption{
+		cardinalOption: func(world *World) {
+			if port == "" {
+				port = eventlog.DefaultPort
+			}
+			world.eventLog = eventlog.NewServer(world, port)
+			world.recordEvents = true
+		},
+	}
+}
+
+// WithEventHistory records the events of every tick, so that clients and indexers that reconnect can catch up on the
+// events they missed with GetEventHistory or the /events?from_tick=N endpoint. The events of a tick are deleted once
+// they are older than the retention allows. The event log service of WithEventLog reads the same history.
+func WithEventHistory(retention EventRetention) WorldOption {
+	return WorldOption{
+		cardinalOption: func(world *World) {
+			world.recordEvents = true
+			world.eventRetention = retention
+		},
+	}
+}
+
+// WithAdminService enables the admin gRPC service on the given port (admin.DefaultPort is used if the port is empty).
+// Every call to the service must carry the given token. The world fails to start if the token is empty.
+func WithAdminService(port, token string) WorldOption {
+	return WorldOption{
+		cardinalOption: func(world *World) {
+			if port == "" {
+				port = admin.DefaultPort
+			}
+			world.adminServer = admin.NewServer(world, port, token)
+		},
+	}
+}
+
+// WithRandSeed sets the world seed used by engine.Context.Rand. By default, the seed is derived from the namespace.
+// Every shard that must produce the same game state (e.g. replicas, or a shard restored from the base shard) must use
+// the same seed.
+func WithRandSeed(seed uint64) WorldOption {
+	return WorldOption{
+		cardinalOption: func(world *World) {
+			world.randSeed = seed
+		},
+	}
+}
+
+// WithStrictMode fails a tick with ErrNondeterministicSystem if a goroutine spawned by a system is still running once
+// all systems have returned, since its writes could land in any later tick, or if a system reads the wall clock or the
+// global random source through the Now and GlobalRand shims. Direct calls to time.Now or the global math/rand
+// functions can't be intercepted at runtime; World.LintSystems checks the sources of the systems for them and is meant
+// to be run from the tests of the game. Strict mode can also be enabled with CARDINAL_STRICT_MODE=true.
+func WithStrictMode() WorldOption {
+	return WorldOption{
+		cardinalOption: func(world *World) {
+			world.SystemManager.setStrictMode(true)
+		},
+	}
+}
+
+// WithDeterminismAudit runs every tick twice against the same game state, and fails the tick with
+// ErrNondeterministicSystem, naming the first system whose changes diverged, if the two runs change the game state,
+// the receipts or the events differently. This catches nondeterminism that World.LintSystems can't see, e.g. systems
+// that depend on the iteration order of a map that is built elsewhere, or keep state outside of components. It doubles
+// the time spent in systems, so it is meant for development and testing. The audit can also be enabled with
+// CARDINAL_DETERMINISM_AUDIT=true.
+func WithDeterminismAudit() WorldOption {
+	return WorldOption{
+		cardinalOption: func(world *World) {
+			world.enableDeterminismAudit()
+		},
+	}
+}
+
+// WithGenesisFile creates the entities of a JSON or YAML file at tick 0, before the init systems run, e.g. the tiles of
+// a map that designers edit by hand. Every entity lists the values of its components by component name, and can be
+// repeated with a count. Fields that are not set keep their default value. The file is validated against the schemas of
+// the registered components every time the world starts, but its entities are only created once. The file can also be
+// set with CARDINAL_GENESIS_FILE.
+func WithGenesisFile(path string) WorldOption {
+	return WorldOption{
+		cardinalOption: func(world *World) {
+			world.genesisFile = path
+		},
+	}
+}
+
+// WithPprof serves the runtime profiles of net/http/pprof under /debug/pprof/ on the HTTP server of the world. The
+// endpoints aren't authenticated, so they should only be reachable by operators. To profile whole ticks without the
+// time between them, use the ProfileTicks RPC of the admin service instead.
+func WithPprof() WorldOption {
+	return WorldOption{
+		serverOption: server.WithPprof(),
+	}
+}
+
+// WithCQLFilters allows the CQL queries of the /cql endpoint to compare the fields of components in a WHERE clause,
+// e.g. to find the players with little health while debugging. Such queries read every entity of their filter
+// expression, so they are meant for development and tooling. They can also be allowed with CARDINAL_CQL_FILTERS=true.
+func WithCQLFilters() WorldOption {
+	return WorldOption{
+		serverOption: server.WithCQLFilters(),
+	}
+}
+
+// WithStepDebugger starts the world paused, so that it only ticks when the Step RPC of the admin service is called, and
+// lets the SetBreakpoints RPC stop a tick before or after any system. While a tick is stopped, the GetTickState RPC
+// returns the game state including the changes that the systems of the tick made so far, which helps to find bugs in
+// the order of systems. A resumed world ticks on its own again, but still stops at breakpoints. The debugger is meant
+// for development; it requires the admin service to be enabled with CARDINAL_ADMIN_TOKEN.
+func WithStepDebugger() WorldOption {
+	return WorldOption{
+		cardinalOption: func(world *World) {
+			world.enableStepDebugger()
+		},
+	}
+}
+
+// WithSystemBudget enables a watchdog that logs a warning, and emits a slow_system metric, when a system exceeds its
+// time budget for several consecutive ticks. The warning includes the number of searches that the system evaluated and
+// the archetypes and entities they matched, which helps to find the search that made the system slow.
+func WithSystemBudget(budget SystemBudget) WorldOption {
+	return WorldOption{
+		cardinalOption: func(world *World) {
+			world.SystemManager.setSystemBudget(budget)
+		},
+	}
+}
+
+// WithTickTimeout bounds the time that a tick may take. The context of the tick is cancelled when the timeout expires,
+// so that the storage operations of the tick, as well as the submission of its transactions to the base shard, fail
+// instead of hanging the tick while the storage is unresponsive. A tick that fails stops the world like any other
+// failed tick, and is recovered when the world is restarted. Systems are not interrupted, so a system that doesn't use
+// the storage can still exceed the timeout.
+func WithTickTimeout(timeout time.Duration) WorldOption {
+	return WorldOption{
+		cardinalOption: func(world *World) {
+			world.tickTimeout = timeout
+		},
+	}
+}
+
+// WithFaultInjection makes the storage and the tick loop of the world fail and slow down as the injector is told to,
+// e.g. to check that the world recovers from a tick whose commit was torn. See the faults package. It is only meant
+// for tests.
+func WithFaultInjection(inj *faults.Injector) WorldOption {
+	return WorldOption{
+		faults: inj,
+	}
+}
+
+// WithStorageBreaker makes the world stop ticking, instead of failing, while its storage is unavailable. See
+// StorageBreaker.
+func WithStorageBreaker(breaker StorageBreaker) WorldOption {
+	return WorldOption{
+		cardinalOption: func(world *World) {
+			world.storageBreaker = newStorageBreaker(breaker)
+		},
+	}
+}
+
+// WithComponentCodec sets the codec that components are stored with, e.g. codec.MsgPack, which is more compact and
+// faster to encode than the default codec.JSON. A component can use another codec with component.WithCodec. Changing
+// the codec of an existing world is safe: components that were stored with the previous codec are still read, and are
+// stored with the new codec the next time they are set.
+func WithComponentCodec(c codec.Codec) WorldOption {
+	return WorldOption{
+		cardinalOption: func(world *World) {
+			world.componentCodec = c
+		},
+	}
+}
+
+// WithComponentEncryption encrypts every component with AES-GCM before it is stored, with the keys of the given
+// provider, e.g. one that fetches the keys from a KMS. It takes precedence over CARDINAL_ENCRYPTION_KEY. Like changing
+// the codec, enabling encryption for an existing world is safe: components that were stored unencrypted are still
+// read, and are encrypted the next time they are set. Raw storage values, archived entities, tick log entries and the
+// leaves of state commitments are encrypted with the same keys. See codec.Encrypted.
+func WithComponentEncryption(keys codec.KeyProvider) WorldOption {
+	return WorldOption{
+		cardinalOption: func(world *World) {
+			world.componentEncryption = keys
+		},
+	}
+}
+
+// WithRawStorageQuota overrides the limits that are enforced on the RawStorage API. See gamestate.RawStorageQuota for
+// details on each limit.
+func WithRawStorageQuota(quota gamestate.RawStorageQuota) WorldOption {
+	return WorldOption{
+		cardinalOption: func(world *World) {
+			world.rawStorageQuota = &quota
+		},
+	}
+}
+
+// WithEntityQuota limits how many entities a persona can own, how many entities a single system can create per tick,
+// and how many entities and components the world can hold. Creations that would exceed the quota fail with
+// ErrPersonaEntityQuotaExceeded, ErrSystemEntityQuotaExceeded, ErrWorldEntityCapExceeded or
+// ErrWorldComponentCapExceeded.
+func WithEntityQuota(quota EntityQuota) WorldOption {
+	return WorldOption{
+		cardinalOption: func(world *World) {
+			world.entityQuota.quota = quota
+		},
+	}
+}
+
+// WithEntityArchival moves entities that have not been accessed for the given number of ticks out of their archetype
+// into an archive, so that persistent worlds don't load millions of dormant entities on every tick. An archived entity
+// is rehydrated the first time a system accesses it by ID or searches its archetype, and queries read it from the
+// archive. Counts include archived entities without rehydrating them. This suits entities that the game looks up by
+// ID, such as the characters of players that are offline, in archetypes that systems don't search on every tick.
+func WithEntityArchival(inactiveTicks uint64) WorldOption {
+	return WorldOption{
+		cardinalOption: func(world *World) {
+			world.archiveAfter = inactiveTicks
+		},
+	}
+}
+
+// WithAutoCheckpoint saves the game state as the AutoCheckpointName checkpoint every given number of ticks, replacing
+// the previous one, so that the world can be rolled back with RollbackToCheckpoint after a bad deploy. Zero disables
+// automatic checkpoints, which is the default.
+func WithAutoCheckpoint(everyTicks uint64) WorldOption {
+	return WorldOption{
+		cardinalOption: func(world *World) {
+			world.autoCheckpointTicks = everyTicks
+		},
+	}
+}
+
+// WithStateCommitments commits to the game state every given number of ticks: the component values of all entities
+// are hashed into a merkle tree, and its root is submitted to the base shard, so that anyone can check a proof from
+// World.ProveState that an entity had a component value without replaying the shard. Commitments are computed in the
+// background, so they don't delay the ticks. The leaves of the keep most recent commitments are kept to generate
+// proofs; zero keeps gamestate.DefaultStateCommitmentsKept of them. Zero ticks disables state commitments, which is
+// the default.
+func WithStateCommitments(everyTicks uint64, keep int) WorldOption {
+	return WorldOption{
+		cardinalOption: func(world *World) {
+			world.stateCommitmentTicks = everyTicks
+			world.stateCommitmentsKept = keep
+		},
+	}
+}
+
+// WithStateDiffs records the changes that every tick makes to the game state, so that World.Diff can report the
+// entities that were created and destroyed, and the component values that changed, between two ticks. The diffs of the
+// keep most recent ticks are kept; zero keeps all of them.
+func WithStateDiffs(keep uint64) WorldOption {
+	return WorldOption{
+		cardinalOption: func(world *World) {
+			world.recordStateDiffs = true
+			world.stateDiffsKept = keep
+		},
+	}
+}
+
+// WithIdempotencyWindow sets how long the idempotency key of a submitted transaction is remembered. Retries with the
+// same key within the window are answered with the original transaction instead of being executed again. The default
+// is DefaultIdempotencyWindow.
+func WithIdempotencyWindow(window time.Duration) WorldOption {
+	return WorldOption{
+		cardinalOption: func(world *World) {
+			world.idempotencyWindow = window
+		},
+	}
+}
+
+// WithReplicatedTxQueue makes the world add the transactions submitted to it to a queue in redis that is shared by
+// every world instance in the same namespace, instead of its own transaction pool. The instance that owns the
+// namespace takes the transactions from the queue at the start of every tick, so that a primary and a standby can both
+// accept transactions, and a transaction that is submitted to both is executed once. This includes the transactions
+// that the base shard sends. A transaction that can't be queued is rejected rather than added to the pool of the
+// instance. The hashes of the transactions taken from the queue are remembered for the given window; zero uses
+// DefaultReplicatedTxWindow.
+func WithReplicatedTxQueue(window time.Duration) WorldOption {
+	return WorldOption{
+		cardinalOption: func(world *World) {
+			if window == 0 {
+				window = DefaultReplicatedTxWindow
+			}
+			world.replicatedTxs = true
+			world.replicatedTxWindow = window
+		},
+	}
+}
+
+// WithLeaderElection runs the world as one of several instances that share its namespace, of which only the leader
+// ticks. An instance becomes the leader by acquiring a lease on the namespace in redis, which it renews while it runs.
+// The other instances are standbys: StartGame blocks until the lease of the leader expired, e.g. because the leader
+// failed, and then resumes the game from the last committed tick. Meanwhile, a standby is served like a read replica,
+// see WithReadReplica, that accepts transactions. The lease expires after the given time without renewal; zero uses
+// DefaultNamespaceLease. Transactions are added to the queue shared by the instances, see WithReplicatedTxQueue, so
+// that the transactions that the leader accepted but didn't execute yet are executed by the next leader.
+func WithLeaderElection(lease time.Duration) WorldOption {
+	return WorldOption{
+		cardinalOption: func(world *World) {
+			if lease == 0 {
+				lease = DefaultNamespaceLease
+			}
+			world.leaderElection = true
+			world.lease = newNamespaceLease(lease)
+			if !world.replicatedTxs {
+				world.replicatedTxs = true
+				world.replicatedTxWindow = DefaultReplicatedTxWindow
+			}
+		},
+	}
+}
+
+// WithReadReplica runs the world as a read replica of the world instance that ticks in the same namespace. A read
+// replica never ticks nor writes the game state: it serves queries from the state that the other instance committed,
+// to take query traffic off it. Every response carries the last committed tick and how long ago the replica saw it, in
+// the Cardinal-Replica-Tick and Cardinal-Replica-Staleness headers. Requests are rejected with 503 Service Unavailable
+// while the replica hasn't seen a new tick for longer than maxStaleness; zero disables the bound. Transactions are
+// rejected, unless they are forwarded to the ticking instance with WithReplicatedTxQueue. Receipts are only served by
+// the instance that executed the transactions.
+func WithReadReplica(maxStaleness time.Duration) WorldOption {
+	return WorldOption{
+		cardinalOption: func(world *World) {
+			world.readReplica = &readReplica{maxStaleness: maxStaleness}
+		},
+	}
+}
+
+// WithCoSignTimeout sets how long a co-signed transaction waits for the signatures of its co-signers before it is
+// dropped. The default is DefaultCoSignTimeout.
+func WithCoSignTimeout(timeout time.Duration) WorldOption {
+	return WorldOption{
+		cardinalOption: func(world *World) {
+			world.coSignTimeout = timeout
+		},
+	}
+}
+
+// WithBackPressure makes the world reject new transactions while its transaction queue is full or its ticks are too
+// far behind their schedule. Rejected submissions are answered with a types.RetryAfter: HTTP submissions with a 503
+// status and a Retry-After header, and EVM messages with the RetryAfter field of their response.
+func WithBackPressure(bp BackPressure) WorldOption {
+	return WorldOption{
+		cardinalOption: func(world *World) {
+			world.backPressure = bp
+		},
+	}
+}
+
+// WithExperimentalHotReload allows the systems of the world to be replaced while it is running with ReloadSystems and
+// LoadSystemsPlugin. This is experimental: a reloaded world does not replay its past ticks the way they were executed.
+func WithExperimentalHotReload() WorldOption {
+	return WorldOption{
+		cardinalOption: func(world *World) {
+			world.hotReload = true
+		},
+	}
+}
+
 func WithStoreManager(s gamestate.Manager) WorldOption {
 	return WorldOption{
 		cardinalOption: func(world *World) {
@@ -95,6 +534,30 @@ func WithMockRedis() WorldOption {
 	return WorldOption{}
 }
 
+// WithLocalPersistence runs the World with an embedded miniredis instance, like WithMockRedis, that is saved to the
+// file at path after every tick and restored from it when the World is created again. This lets a game be developed
+// against a persistent world without running Redis. It should only be used for local development.
+func WithLocalPersistence(path string) WorldOption {
+	mr := miniredis.NewMiniRedis()
+	if err := redis.LoadSnapshot(mr, path); err != nil {
+		log.Fatal().Err(err).Msg("failed to restore the world from disk")
+	}
+	if err := mr.Start(); err != nil {
+		log.Fatal().Err(err).Msg("failed to start miniredis")
+	}
+	log.Debug().Msgf("miniredis started at %s, persisted to %s", mr.Addr(), path)
+
+	if err := os.Setenv("REDIS_ADDRESS", mr.Addr()); err != nil {
+		log.Fatal().Err(err).Msg("unable to set REDIS_ADDRESS")
+	}
+
+	return WorldOption{
+		cardinalOption: func(world *World) {
+			world.localPersistence = &localPersistence{redis: mr, path: path}
+		},
+	}
+}
+
 func WithCustomLogger(logger zerolog.Logger) WorldOption {
 	return WorldOption{
 		cardinalOption: func(_ *World) {
@@ -111,6 +574,18 @@ func WithCustomRouter(rtr router.Router) WorldOption {
 	}
 }
 
+// WithShardSequencer connects the world to the base shard through the given client of its sequencer instead of
+// dialing it, e.g. an in-process fake of the base shard in tests. The world runs in rollup mode: the transactions of
+// every tick are submitted to the sequencer, and the world recovers the ticks that it doesn't have from it on startup.
+func WithShardSequencer(sequencer shard.TransactionHandlerClient) WorldOption {
+	return WorldOption{
+		cardinalOption: func(world *World) {
+			world.rollupEnabled = true
+			world.router = router.NewWithSequencer(world.Namespace(), sequencer, "", world)
+		},
+	}
+}
+
 func WithPrettyLog() WorldOption {
 	return WorldOption{
 		cardinalOption: func(_ *World) {
@@ -0,0 +1,36 @@
+package component
+
+import "slices"
+
+// SessionKeysComponent holds the session keys that a persona authorized with the authorize-session-key message. It is
+// kept on its own entity, so that it doesn't change the archetype of the persona's SignerComponent entity.
+type SessionKeysComponent struct {
+	PersonaTag string
+	Keys       []SessionKey
+}
+
+func (SessionKeysComponent) Name() string {
+	return "SessionKeysComponent"
+}
+
+// SessionKey is a key that may sign some of the transactions of a persona until it expires.
+type SessionKey struct {
+	Address string
+	// Messages are the full names of the messages that the key may sign. Empty means every message.
+	Messages      []string
+	ExpiresAtTick uint64
+	ExpiresAt     uint64
+}
+
+// Expired returns true if the key is no longer valid at the given tick and UNIX timestamp.
+func (k SessionKey) Expired(tick, timestamp uint64) bool {
+	return (k.ExpiresAtTick != 0 && tick >= k.ExpiresAtTick) || (k.ExpiresAt != 0 && timestamp >= k.ExpiresAt)
+}
+
+// Allows returns true if the key may sign the given message at the given tick and UNIX timestamp.
+func (k SessionKey) Allows(msgFullName string, tick, timestamp uint64) bool {
+	if k.Expired(tick, timestamp) {
+		return false
+	}
+	return len(k.Messages) == 0 || slices.Contains(k.Messages, msgFullName)
+}
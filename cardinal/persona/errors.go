@@ -5,4 +5,7 @@ import "errors"
 var (
 	ErrPersonaTagHasNoSigner        = errors.New("persona tag does not have a signer")
 	ErrCreatePersonaTxsNotProcessed = errors.New("create persona txs have not been processed for the given tick")
+	// ErrSessionKeyNotAuthorized is the error of a transaction that was dropped because the session key that signed it
+	// was revoked or expired before the transaction was executed.
+	ErrSessionKeyNotAuthorized = errors.New("session key is no longer authorized to sign the transaction")
 )
@@ -0,0 +1,33 @@
+package msg
+
+var (
+	AuthorizeSessionKeyMessageName = "authorize-session-key"
+	RevokeSessionKeyMessageName    = "revoke-session-key"
+)
+
+// AuthorizeSessionKey lets a persona authorize a temporary key to sign transactions on its behalf, e.g. so that a
+// game client can submit moves without asking the user's wallet to sign each of them. It must be signed by the
+// persona's signer. The key expires at ExpiresAtTick and/or ExpiresAt, at least one of which must be set.
+type AuthorizeSessionKey struct {
+	Address string `json:"address"`
+	// Messages are the full names (e.g. "game.move") of the messages that the key may sign. If it is empty, the key may
+	// sign every message except for the persona messages.
+	Messages []string `json:"messages,omitempty"`
+	// ExpiresAtTick is the first tick at which the key is no longer valid. 0 means no tick limit.
+	ExpiresAtTick uint64 `json:"expiresAtTick,omitempty"`
+	// ExpiresAt is the UNIX timestamp, in seconds, at which the key is no longer valid. 0 means no time limit.
+	ExpiresAt uint64 `json:"expiresAt,omitempty"`
+}
+
+type AuthorizeSessionKeyResult struct {
+	Success bool `json:"success"`
+}
+
+// RevokeSessionKey revokes a session key of the persona before it expires. It must be signed by the persona's signer.
+type RevokeSessionKey struct {
+	Address string `json:"address"`
+}
+
+type RevokeSessionKeyResult struct {
+	Success bool `json:"success"`
+}
@@ -1,12 +1,18 @@
 package persona_test
 
 import (
+	"crypto/ecdsa"
 	"fmt"
+	"slices"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
 
 	"pkg.world.dev/world-engine/assert"
 	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/message"
 	"pkg.world.dev/world-engine/cardinal/persona"
 	"pkg.world.dev/world-engine/cardinal/persona/component"
 	"pkg.world.dev/world-engine/cardinal/persona/msg"
@@ -14,6 +20,7 @@ import (
 	"pkg.world.dev/world-engine/cardinal/search/filter"
 	"pkg.world.dev/world-engine/cardinal/testutils"
 	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
 	"pkg.world.dev/world-engine/sign"
 )
 
@@ -283,6 +290,195 @@ func TestQuerySignerUnknown(t *testing.T) {
 	assert.Equal(t, response.Status, personaQuery.PersonaStatusUnknown)
 }
 
+func TestSessionKeys(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil)
+	world := tf.World
+	world.RegisterPlugin(cardinal.NewSessionKeyPlugin())
+	tf.StartWorld()
+
+	personaTag := "CoolMage"
+	tf.CreatePersona(personaTag, "123_456")
+
+	authorizeMsg, ok := world.GetMessageByFullName("persona.authorize-session-key")
+	assert.True(t, ok)
+	moveKey := "0xd5e099c71b797516c10ed0f0d895f429c2781142"
+	anyKey := "0x8d2b6e3d6c2c2e8b16a9f8ce0b4fb1b1e6c0a0f1"
+	// Every transaction gets its own nonce, since transactions with the same hash share a receipt
+	nonce := uint64(0)
+	sig := func() *sign.Transaction {
+		nonce++
+		return &sign.Transaction{PersonaTag: personaTag, Nonce: nonce}
+	}
+	wantOK := []types.TxHash{
+		tf.AddTransaction(authorizeMsg.ID(), msg.AuthorizeSessionKey{
+			Address:       moveKey,
+			Messages:      []string{"game.move"},
+			ExpiresAtTick: world.CurrentTick() + 10,
+		}, sig()),
+		tf.AddTransaction(authorizeMsg.ID(), msg.AuthorizeSessionKey{
+			Address:   anyKey,
+			ExpiresAt: uint64(time.Now().Add(time.Hour).Unix()),
+		}, sig()),
+	}
+	wantErr := []types.TxHash{
+		// Session keys can't sign persona messages
+		tf.AddTransaction(authorizeMsg.ID(), msg.AuthorizeSessionKey{
+			Address:       anyKey,
+			Messages:      []string{"persona.authorize-session-key"},
+			ExpiresAtTick: world.CurrentTick() + 10,
+		}, sig()),
+		// Session keys must expire
+		tf.AddTransaction(authorizeMsg.ID(), msg.AuthorizeSessionKey{Address: anyKey}, sig()),
+		tf.AddTransaction(authorizeMsg.ID(), msg.AuthorizeSessionKey{
+			Address:       anyKey,
+			ExpiresAtTick: world.CurrentTick(),
+		}, sig()),
+		tf.AddTransaction(authorizeMsg.ID(), msg.AuthorizeSessionKey{
+			Address:       "INVALID ADDRESS",
+			ExpiresAtTick: world.CurrentTick() + 10,
+		}, sig()),
+	}
+	tf.DoTick()
+	receipts, err := world.GetTransactionReceiptsForTick(world.CurrentTick() - 1)
+	assert.NilError(t, err)
+	for _, r := range receipts {
+		assert.Equal(t, slices.Contains(wantErr, r.TxHash), len(r.Errs) > 0)
+	}
+	assert.Equal(t, len(wantOK)+len(wantErr), len(receipts))
+
+	keys, err := world.GetSessionKeysForPersonaTag(personaTag, "game.move")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []string{moveKey, anyKey}, keys)
+	keys, err = world.GetSessionKeysForPersonaTag(personaTag, "game.attack")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []string{anyKey}, keys)
+	keys, err = world.GetSessionKeysForPersonaTag(personaTag, "game.authorize-persona-address")
+	assert.NilError(t, err)
+	assert.Len(t, keys, 0)
+
+	revokeMsg, ok := world.GetMessageByFullName("persona.revoke-session-key")
+	assert.True(t, ok)
+	tf.AddTransaction(revokeMsg.ID(), msg.RevokeSessionKey{Address: anyKey}, sig())
+	tf.DoTick()
+	keys, err = world.GetSessionKeysForPersonaTag(personaTag, "game.attack")
+	assert.NilError(t, err)
+	assert.Len(t, keys, 0)
+
+	// The move key expires at its tick
+	for world.CurrentTick() < 11 {
+		tf.DoTick()
+	}
+	keys, err = world.GetSessionKeysForPersonaTag(personaTag, "game.move")
+	assert.NilError(t, err)
+	assert.Len(t, keys, 0)
+}
+
+func TestSessionKeysAreLimited(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil)
+	world := tf.World
+	world.RegisterPlugin(cardinal.NewSessionKeyPlugin())
+	tf.StartWorld()
+
+	personaTag := "CoolMage"
+	tf.CreatePersona(personaTag, "123_456")
+	authorizeMsg, ok := world.GetMessageByFullName("persona.authorize-session-key")
+	assert.True(t, ok)
+	for i := 0; i <= persona.MaximumSessionKeys; i++ {
+		tf.AddTransaction(authorizeMsg.ID(), msg.AuthorizeSessionKey{
+			Address:       fmt.Sprintf("0x%040x", i+1),
+			ExpiresAtTick: world.CurrentTick() + 2,
+		}, &sign.Transaction{PersonaTag: personaTag})
+	}
+	tf.DoTick()
+	keys, err := world.GetSessionKeysForPersonaTag(personaTag, "game.move")
+	assert.NilError(t, err)
+	assert.Len(t, keys, persona.MaximumSessionKeys)
+
+	// Expired keys are pruned when a new key is authorized
+	tf.DoTick()
+	tf.AddTransaction(authorizeMsg.ID(), msg.AuthorizeSessionKey{
+		Address:       fmt.Sprintf("0x%040x", 0),
+		ExpiresAtTick: world.CurrentTick() + 2,
+	}, &sign.Transaction{PersonaTag: personaTag})
+	tf.DoTick()
+	keys, err = world.GetSessionKeysForPersonaTag(personaTag, "game.move")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []string{fmt.Sprintf("0x%040x", 0)}, keys)
+}
+
+type Move struct {
+	Direction string
+}
+
+type MoveResult struct{}
+
+func TestQueuedTransactionsOfRevokedSessionKeysAreDropped(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil)
+	world := tf.World
+	assert.NilError(t, cardinal.RegisterMessage[Move, MoveResult](world, "move"))
+	assert.NilError(t, cardinal.RegisterSystems(world, func(wCtx engine.Context) error {
+		return cardinal.EachMessage[Move, MoveResult](wCtx, func(message.TxData[Move]) (MoveResult, error) {
+			return MoveResult{}, nil
+		})
+	}))
+	world.RegisterPlugin(cardinal.NewSessionKeyPlugin())
+	tf.StartWorld()
+
+	personaTag := "CoolMage"
+	signerKey, err := crypto.GenerateKey()
+	assert.NilError(t, err)
+	revokedKey, err := crypto.GenerateKey()
+	assert.NilError(t, err)
+	sessionKey, err := crypto.GenerateKey()
+	assert.NilError(t, err)
+	address := func(key *ecdsa.PrivateKey) string {
+		return crypto.PubkeyToAddress(key.PublicKey).Hex()
+	}
+	tf.CreatePersona(personaTag, address(signerKey))
+
+	authorizeMsg, ok := world.GetMessageByFullName("persona.authorize-session-key")
+	assert.True(t, ok)
+	for i, key := range []*ecdsa.PrivateKey{revokedKey, sessionKey} {
+		tf.AddTransaction(authorizeMsg.ID(), msg.AuthorizeSessionKey{
+			Address:       address(key),
+			ExpiresAtTick: world.CurrentTick() + 10,
+		}, &sign.Transaction{PersonaTag: personaTag, Nonce: uint64(i)})
+	}
+	tf.DoTick()
+	revokeMsg, ok := world.GetMessageByFullName("persona.revoke-session-key")
+	assert.True(t, ok)
+	tf.AddTransaction(revokeMsg.ID(), msg.RevokeSessionKey{Address: address(revokedKey)},
+		&sign.Transaction{PersonaTag: personaTag, Nonce: 2})
+	tf.DoTick()
+
+	// The transactions were accepted while the revoked key was still authorized, and waited for the next tick
+	moveMsg, ok := world.GetMessageByFullName("game.move")
+	assert.True(t, ok)
+	nonce := uint64(0)
+	move := func(key *ecdsa.PrivateKey) types.TxHash {
+		nonce++
+		tx, err := sign.NewTransaction(key, personaTag, world.Namespace(), nonce, Move{Direction: "up"})
+		assert.NilError(t, err)
+		return tf.AddTransaction(moveMsg.ID(), Move{Direction: "up"}, tx)
+	}
+	dropped := move(revokedKey)
+	wantOK := []types.TxHash{move(sessionKey), move(signerKey)}
+	tf.DoTick()
+
+	receipts, err := world.GetTransactionReceiptsForTick(world.CurrentTick() - 1)
+	assert.NilError(t, err)
+	assert.Equal(t, 3, len(receipts))
+	for _, r := range receipts {
+		if r.TxHash == dropped {
+			assert.Equal(t, 1, len(r.Errs))
+			assert.ErrorIs(t, r.Errs[0], persona.ErrSessionKeyNotAuthorized)
+		} else {
+			assert.Check(t, slices.Contains(wantOK, r.TxHash))
+			assert.Equal(t, 0, len(r.Errs))
+		}
+	}
+}
+
 func getSigners(t *testing.T, world *cardinal.World) []*component.SignerComponent {
 	wCtx := cardinal.NewWorldContext(world)
 	var signers = make([]*component.SignerComponent, 0)
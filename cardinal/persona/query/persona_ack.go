@@ -0,0 +1,34 @@
+package query
+
+import (
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+// PersonaAckQueryRequest is the desired request body for the query-persona-ack endpoint.
+type PersonaAckQueryRequest struct {
+	PersonaTag string `json:"personaTag"`
+}
+
+// PersonaAckQueryResponse is used as the response body for the query-persona-ack endpoint. It is the last transaction
+// of the persona that was applied and the tick it was applied at, which clients use to reconcile their predicted state
+// with the state of that tick. Found is false if no transaction of the persona was applied yet.
+type PersonaAckQueryResponse struct {
+	Found  bool         `json:"found"`
+	Tick   uint64       `json:"tick"`
+	TxHash types.TxHash `json:"txHash"`
+	Nonce  uint64       `json:"nonce"`
+}
+
+func PersonaAckQuery(wCtx engine.Context, req *PersonaAckQueryRequest) (*PersonaAckQueryResponse, error) {
+	ack, found, err := wCtx.StoreReader().GetInputAck(req.PersonaTag)
+	if err != nil {
+		return nil, err
+	}
+	return &PersonaAckQueryResponse{
+		Found:  found,
+		Tick:   ack.Tick,
+		TxHash: ack.TxHash,
+		Nonce:  ack.Nonce,
+	}, nil
+}
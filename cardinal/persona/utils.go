@@ -2,11 +2,15 @@ package persona
 
 import (
 	"regexp"
+	"strings"
 )
 
 const (
 	MinimumPersonaTagLength = 3
 	MaximumPersonaTagLength = 16
+
+	// MaximumSessionKeys is the number of unexpired session keys that a persona may have at once.
+	MaximumSessionKeys = 16
 )
 
 var (
@@ -21,3 +25,9 @@ func IsValidPersonaTag(s string) bool {
 	}
 	return personaTagRegexp.MatchString(s)
 }
+
+// SessionKeyMaySign returns false for the messages that only the signer of a persona may sign, i.e. the messages that
+// create a persona or change who may sign for it.
+func SessionKeyMaySign(msgFullName string) bool {
+	return !strings.HasPrefix(msgFullName, "persona.") && msgFullName != "game.authorize-persona-address"
+}
@@ -0,0 +1,201 @@
+package cardinal
+
+import (
+	"cmp"
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal/inbox"
+	"pkg.world.dev/world-engine/cardinal/message"
+	querylib "pkg.world.dev/world-engine/cardinal/query"
+	"pkg.world.dev/world-engine/cardinal/search"
+	"pkg.world.dev/world-engine/cardinal/search/filter"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+const inboxGroup = "inbox"
+
+var _ Plugin = (*inboxPlugin)(nil)
+
+type inboxPlugin struct {
+	retention inbox.Retention
+}
+
+// NewInboxPlugin returns the inbox plugin, which keeps a persistent inbox of messages for every persona. Systems add
+// messages with SendInboxMessage, and clients read them with the inbox list query and acknowledge them with the inbox
+// ack message. Messages are removed according to the retention policy. Register it with World.RegisterPlugin before
+// starting the game.
+func NewInboxPlugin(retention inbox.Retention) Plugin {
+	return &inboxPlugin{retention: retention}
+}
+
+func (p *inboxPlugin) Register(world *World) error {
+	return errors.Join(
+		RegisterComponent[inbox.Message](world),
+		RegisterMessage[inbox.Ack, inbox.AckResult](
+			world,
+			inbox.AckMessageName,
+			message.WithCustomMessageGroup[inbox.Ack, inbox.AckResult](inboxGroup)),
+		RegisterQuery[inbox.ListRequest, inbox.ListResponse](
+			world,
+			inbox.ListQueryName,
+			listInboxQuery,
+			querylib.WithCustomQueryGroup[inbox.ListRequest, inbox.ListResponse](inboxGroup)),
+		// Acknowledgments are handled before the retention policy is applied, so a message acknowledged in this tick
+		// is kept for the full AckedMaxAge.
+		RegisterSystems(world, ackInboxSystem, p.inboxRetentionSystem),
+	)
+}
+
+// SendInboxMessage adds the message to the inbox of its recipient and returns the ID of the message. The message is
+// sent in the current tick, and is not acknowledged yet. An "inbox-message" event is emitted, so that a connected
+// client can show the message right away.
+func SendInboxMessage(wCtx engine.Context, msg inbox.Message) (types.EntityID, error) {
+	if msg.Recipient == "" {
+		return 0, eris.New("inbox message has no recipient")
+	}
+	msg.SentAt = wCtx.CurrentTick()
+	msg.Acked, msg.AckedAt = false, 0
+	id, err := Create(wCtx, msg)
+	if err != nil {
+		return 0, err
+	}
+	err = wCtx.EmitEvent(map[string]any{
+		"event":     "inbox-message",
+		"id":        id,
+		"recipient": msg.Recipient,
+		"kind":      msg.Kind,
+	})
+	return id, err
+}
+
+// -----------------------------------------------------------------------------
+// Inbox Systems
+// -----------------------------------------------------------------------------
+
+// ackInboxSystem acknowledges the messages of every ack message. Every message is checked before any is acknowledged,
+// so that an ack with a foreign or removed message changes nothing.
+func ackInboxSystem(wCtx engine.Context) error {
+	return EachMessage[inbox.Ack, inbox.AckResult](
+		wCtx,
+		func(txData message.TxData[inbox.Ack]) (result inbox.AckResult, err error) {
+			msgs := make([]*inbox.Message, len(txData.Msg.IDs))
+			for i, id := range txData.Msg.IDs {
+				msg, err := GetComponent[inbox.Message](wCtx, id)
+				if err != nil {
+					return result, eris.Wrapf(inbox.ErrMessageNotFound, "message %d", id)
+				}
+				if !msg.IsFor(txData.Tx.PersonaTag) {
+					return result, eris.Wrapf(inbox.ErrNotRecipient, "message %d", id)
+				}
+				msgs[i] = msg
+			}
+			acked := map[types.EntityID]bool{}
+			for i, msg := range msgs {
+				id := txData.Msg.IDs[i]
+				if msg.Acked || acked[id] {
+					continue
+				}
+				msg.Acked, msg.AckedAt = true, wCtx.CurrentTick()
+				if err = SetComponent[inbox.Message](wCtx, id, msg); err != nil {
+					return result, err
+				}
+				acked[id] = true
+				result.Acked++
+			}
+			return result, nil
+		},
+	)
+}
+
+// inboxRetentionSystem removes the messages that the retention policy doesn't keep.
+func (p *inboxPlugin) inboxRetentionSystem(wCtx engine.Context) error {
+	if p.retention == (inbox.Retention{}) {
+		return nil
+	}
+	msgs, err := inboxMessages(wCtx, func(inbox.Message) bool { return true })
+	if err != nil {
+		return err
+	}
+	tick := wCtx.CurrentTick()
+	kept := map[string]int{}
+	var removed []types.EntityID
+	// Messages are visited newest first, so that the oldest messages of a full inbox are removed.
+	for i := len(msgs) - 1; i >= 0; i-- {
+		info := msgs[i]
+		msg := info.Message
+		recipient := strings.ToLower(msg.Recipient)
+		switch {
+		case p.retention.MaxAge > 0 && tick-msg.SentAt >= p.retention.MaxAge,
+			p.retention.AckedMaxAge > 0 && msg.Acked && tick-msg.AckedAt >= p.retention.AckedMaxAge,
+			p.retention.MaxMessages > 0 && kept[recipient] >= p.retention.MaxMessages:
+			removed = append(removed, info.ID)
+		default:
+			kept[recipient]++
+		}
+	}
+	for _, id := range removed {
+		if err = Remove(wCtx, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+// Inbox Query
+// -----------------------------------------------------------------------------
+
+func listInboxQuery(wCtx engine.Context, req *inbox.ListRequest) (*inbox.ListResponse, error) {
+	msgs, err := inboxMessages(wCtx, func(msg inbox.Message) bool { return msg.IsFor(req.PersonaTag) })
+	if err != nil {
+		return nil, err
+	}
+	res := &inbox.ListResponse{Messages: []inbox.MessageInfo{}}
+	for _, info := range msgs {
+		if !info.Message.Acked {
+			res.Unacked++
+		}
+		if (req.Unacked && info.Message.Acked) || (req.Kind != "" && info.Message.Kind != req.Kind) {
+			continue
+		}
+		res.Messages = append(res.Messages, info)
+	}
+	return res, nil
+}
+
+// -----------------------------------------------------------------------------
+// Inbox Helpers
+// -----------------------------------------------------------------------------
+
+// inboxMessages returns the inbox messages that match, oldest first.
+func inboxMessages(wCtx engine.Context, match func(inbox.Message) bool) ([]inbox.MessageInfo, error) {
+	var msgs []inbox.MessageInfo
+	var errs []error
+	err := search.NewSearch().Entity(filter.Exact(filter.Component[inbox.Message]())).
+		Each(wCtx, func(id types.EntityID) bool {
+			msg, err := GetComponent[inbox.Message](wCtx, id)
+			if err != nil {
+				errs = append(errs, err)
+				return false
+			}
+			if match(*msg) {
+				msgs = append(msgs, inbox.MessageInfo{ID: id, Message: *msg})
+			}
+			return true
+		})
+	if err != nil {
+		return nil, err
+	}
+	if len(errs) != 0 {
+		return nil, errors.Join(errs...)
+	}
+	slices.SortFunc(msgs, func(a, b inbox.MessageInfo) int {
+		return cmp.Or(cmp.Compare(a.Message.SentAt, b.Message.SentAt), cmp.Compare(a.ID, b.ID))
+	})
+	return msgs, nil
+}
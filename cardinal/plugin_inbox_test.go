@@ -0,0 +1,110 @@
+package cardinal_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/inbox"
+	"pkg.world.dev/world-engine/cardinal/testutils"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+func sendMail(t *testing.T, wCtx engine.Context, recipient, subject string) types.EntityID {
+	id, err := cardinal.SendInboxMessage(wCtx, inbox.Message{
+		Recipient: recipient,
+		Kind:      "mail",
+		From:      "system",
+		Subject:   subject,
+		Body:      json.RawMessage(`{"gold":5}`),
+	})
+	assert.NilError(t, err)
+	return id
+}
+
+func listInbox(tf *testutils.TestFixture, req inbox.ListRequest) *inbox.ListResponse {
+	res := tf.Post("query/inbox/"+inbox.ListQueryName, req)
+	defer res.Body.Close()
+	assert.Equal(tf, 200, res.StatusCode)
+	var list inbox.ListResponse
+	assert.NilError(tf, json.NewDecoder(res.Body).Decode(&list))
+	return &list
+}
+
+func inboxSubjects(tf *testutils.TestFixture, personaTag string) []string {
+	subjects := []string{}
+	for _, info := range listInbox(tf, inbox.ListRequest{PersonaTag: personaTag}).Messages {
+		subjects = append(subjects, info.Message.Subject)
+	}
+	return subjects
+}
+
+func TestInboxMessagesCanBeListedAndAcked(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil)
+	tf.World.RegisterPlugin(cardinal.NewInboxPlugin(inbox.Retention{}))
+	tf.DoTick()
+	wCtx := cardinal.NewWorldContext(tf.World)
+	ackName := "inbox." + inbox.AckMessageName
+
+	welcome := sendMail(t, wCtx, "alice", "welcome")
+	reward := sendMail(t, wCtx, "Alice", "reward")
+	sendMail(t, wCtx, "bob", "welcome")
+	tf.DoTick()
+
+	list := listInbox(tf, inbox.ListRequest{PersonaTag: "alice"})
+	assert.Equal(t, 2, list.Unacked)
+	assert.Equal(t, 2, len(list.Messages))
+	assert.Equal(t, welcome, list.Messages[0].ID)
+	assert.Equal(t, `{"gold":5}`, string(list.Messages[0].Message.Body))
+
+	// Only the recipient can acknowledge a message, and an ack with a foreign message changes nothing.
+	rec := tf.SendTransaction(ackName, inbox.Ack{IDs: []types.EntityID{welcome}}, "bob")
+	assert.ErrorIs(t, rec.Errs[0], inbox.ErrNotRecipient)
+	rec = tf.SendTransaction(ackName, inbox.Ack{IDs: []types.EntityID{welcome, welcome + 100}}, "alice")
+	assert.Len(t, rec.Errs, 1)
+	assert.Equal(t, 2, listInbox(tf, inbox.ListRequest{PersonaTag: "alice"}).Unacked)
+
+	rec = tf.SendTransaction(ackName, inbox.Ack{IDs: []types.EntityID{welcome, welcome}}, "alice")
+	assert.Len(t, rec.Errs, 0)
+	result, ok := rec.Result.(inbox.AckResult)
+	assert.Assert(t, ok)
+	assert.Equal(t, 1, result.Acked)
+	list = listInbox(tf, inbox.ListRequest{PersonaTag: "alice", Unacked: true})
+	assert.Equal(t, 1, list.Unacked)
+	assert.Equal(t, 1, len(list.Messages))
+	assert.Equal(t, reward, list.Messages[0].ID)
+}
+
+func TestInboxRetention(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil)
+	tf.World.RegisterPlugin(cardinal.NewInboxPlugin(inbox.Retention{MaxAge: 10, AckedMaxAge: 2, MaxMessages: 2}))
+	tf.DoTick()
+	wCtx := cardinal.NewWorldContext(tf.World)
+	ackName := "inbox." + inbox.AckMessageName
+
+	first := sendMail(t, wCtx, "alice", "first")
+	tf.DoTick()
+	sendMail(t, wCtx, "alice", "second")
+	tf.DoTick()
+	sendMail(t, wCtx, "alice", "third")
+	tf.DoTick()
+	// The oldest message is removed from a full inbox.
+	assert.DeepEqual(t, []string{"second", "third"}, inboxSubjects(tf, "alice"))
+	rec := tf.SendTransaction(ackName, inbox.Ack{IDs: []types.EntityID{first}}, "alice")
+	assert.ErrorIs(t, rec.Errs[0], inbox.ErrMessageNotFound)
+
+	// Acknowledged messages are removed sooner than the others.
+	second := listInbox(tf, inbox.ListRequest{PersonaTag: "alice"}).Messages[0].ID
+	tf.SendTransaction(ackName, inbox.Ack{IDs: []types.EntityID{second}}, "alice")
+	tf.DoTick()
+	assert.DeepEqual(t, []string{"second", "third"}, inboxSubjects(tf, "alice"))
+	tf.DoTick()
+	assert.DeepEqual(t, []string{"third"}, inboxSubjects(tf, "alice"))
+
+	for i := 0; i < 10; i++ {
+		tf.DoTick()
+	}
+	assert.DeepEqual(t, []string{}, inboxSubjects(tf, "alice"))
+}
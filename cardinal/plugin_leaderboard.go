@@ -0,0 +1,280 @@
+package cardinal
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal/leaderboard"
+	querylib "pkg.world.dev/world-engine/cardinal/query"
+	"pkg.world.dev/world-engine/cardinal/search"
+	"pkg.world.dev/world-engine/cardinal/search/filter"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+const (
+	leaderboardGroup = "leaderboard"
+	// leaderboardIndex is the unique index of the leaderboard entries, keyed by board and persona tag.
+	leaderboardIndex = "leaderboard-entry"
+)
+
+var _ Plugin = (*Leaderboard)(nil)
+
+// Leaderboard is the leaderboard plugin. See NewLeaderboardPlugin.
+type Leaderboard struct {
+	useRedis bool
+	rankings leaderboard.Rankings
+
+	mux sync.Mutex
+	// pending are the score changes that have not been applied to the rankings yet, keyed by board and persona tag.
+	pending map[leaderboardKey]leaderboard.Change
+	// syncedTick is the tick whose changes are applied next. The rankings are reset if another tick ends, e.g. after
+	// a rollback.
+	syncedTick uint64
+}
+
+type leaderboardKey struct {
+	board      string
+	personaTag string
+}
+
+// LeaderboardOption configures the leaderboard plugin.
+type LeaderboardOption func(*Leaderboard)
+
+// WithLeaderboardRedis keeps the rankings in redis sorted sets instead of in the memory of the world, e.g. for boards
+// with millions of personas, or so that other services can read the rankings from redis. Sorted sets rank scores
+// exactly only up to 2^53 in magnitude.
+func WithLeaderboardRedis() LeaderboardOption {
+	return func(l *Leaderboard) {
+		l.useRedis = true
+	}
+}
+
+// NewLeaderboardPlugin returns the leaderboard plugin, which ranks the scores of personas on boards, e.g. "kills" or
+// "season-3". Systems change scores with the SetScore, AddScore and RemoveScore methods of the plugin, and clients read
+// the rankings with the leaderboard top and around queries. The rankings are sorted incrementally once the tick that
+// changed the scores is committed, so queries see the scores of the last completed tick and never sort a board.
+// Register it with World.RegisterPlugin before starting the game.
+func NewLeaderboardPlugin(opts ...LeaderboardOption) *Leaderboard {
+	l := &Leaderboard{pending: map[leaderboardKey]leaderboard.Change{}}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+func (l *Leaderboard) Register(world *World) error {
+	if l.useRedis {
+		l.rankings = leaderboard.NewRedisRankings(world.redisStorage.Client)
+	} else {
+		l.rankings = leaderboard.NewMemoryRankings()
+	}
+	reset := func(ctx context.Context, event LifecycleEvent) error {
+		return l.reset(ctx, world, event.Tick)
+	}
+	if err := RegisterComponent[leaderboard.Entry](world); err != nil {
+		return err
+	}
+	return errors.Join(
+		RegisterUniqueIndex[leaderboard.Entry](world, leaderboardIndex, func(e leaderboard.Entry) string {
+			return leaderboardIndexKey(e.Board, e.PersonaTag)
+		}),
+		RegisterQuery[leaderboard.TopRequest, leaderboard.RanksResponse](
+			world,
+			leaderboard.TopQueryName,
+			l.topQuery,
+			querylib.WithCustomQueryGroup[leaderboard.TopRequest, leaderboard.RanksResponse](leaderboardGroup)),
+		RegisterQuery[leaderboard.AroundRequest, leaderboard.RanksResponse](
+			world,
+			leaderboard.AroundQueryName,
+			l.aroundQuery,
+			querylib.WithCustomQueryGroup[leaderboard.AroundRequest, leaderboard.RanksResponse](leaderboardGroup)),
+		RegisterLifecycleHook(world, LifecycleGameStateLoaded, reset),
+		RegisterLifecycleHook(world, LifecycleTickEnded, func(ctx context.Context, event LifecycleEvent) error {
+			return l.sync(ctx, world, event.Tick)
+		}),
+	)
+}
+
+// SetScore sets the score of the persona on the board.
+func (l *Leaderboard) SetScore(wCtx engine.Context, board, personaTag string, score int64) error {
+	if board == "" {
+		return eris.Wrap(leaderboard.ErrInvalidBoard, "board must not be empty")
+	}
+	id, entry, err := getLeaderboardEntry(wCtx, board, personaTag)
+	if err != nil {
+		return err
+	}
+	if entry == nil {
+		entry = &leaderboard.Entry{Board: board, PersonaTag: personaTag, Score: score}
+		if _, err = Create(wCtx, *entry); err != nil {
+			return err
+		}
+	} else {
+		entry.Score = score
+		if err = SetComponent[leaderboard.Entry](wCtx, id, entry); err != nil {
+			return err
+		}
+	}
+	l.record(leaderboard.Change{Board: board, PersonaTag: entry.PersonaTag, Score: score})
+	return nil
+}
+
+// AddScore adds delta to the score of the persona on the board and returns the new score. A persona without a score
+// on the board starts at 0.
+func (l *Leaderboard) AddScore(wCtx engine.Context, board, personaTag string, delta int64) (int64, error) {
+	score, _, err := l.GetScore(wCtx, board, personaTag)
+	if err != nil {
+		return 0, err
+	}
+	score += delta
+	return score, l.SetScore(wCtx, board, personaTag, score)
+}
+
+// GetScore returns the score of the persona on the board. If the persona has no score on the board, ok is false.
+func (l *Leaderboard) GetScore(wCtx engine.Context, board, personaTag string) (score int64, ok bool, err error) {
+	_, entry, err := getLeaderboardEntry(wCtx, board, personaTag)
+	if err != nil || entry == nil {
+		return 0, false, err
+	}
+	return entry.Score, true, nil
+}
+
+// RemoveScore removes the persona from the board. Removing a persona that has no score on the board does nothing.
+func (l *Leaderboard) RemoveScore(wCtx engine.Context, board, personaTag string) error {
+	id, entry, err := getLeaderboardEntry(wCtx, board, personaTag)
+	if err != nil || entry == nil {
+		return err
+	}
+	if err = Remove(wCtx, id); err != nil {
+		return err
+	}
+	l.record(leaderboard.Change{Board: board, PersonaTag: entry.PersonaTag, Removed: true})
+	return nil
+}
+
+// record remembers a change of a score, so that it is applied to the rankings once the tick is committed. Only the
+// last change of a score in a tick is kept.
+func (l *Leaderboard) record(change leaderboard.Change) {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+	l.pending[leaderboardKey{board: change.Board, personaTag: change.PersonaTag}] = change
+}
+
+// sync applies the changes of the tick that ended to the rankings. If the rankings are not at that tick, e.g. because
+// the world was rolled back to a checkpoint, they are reset from the game state instead.
+func (l *Leaderboard) sync(ctx context.Context, world *World, tick uint64) error {
+	l.mux.Lock()
+	if tick != l.syncedTick {
+		l.mux.Unlock()
+		return l.reset(ctx, world, tick+1)
+	}
+	changes := make([]leaderboard.Change, 0, len(l.pending))
+	for _, change := range l.pending {
+		changes = append(changes, change)
+	}
+	clear(l.pending)
+	l.syncedTick = tick + 1
+	l.mux.Unlock()
+
+	// The rankings don't depend on the order of the changes, but redis receives them in a deterministic order.
+	slices.SortFunc(changes, func(a, b leaderboard.Change) int {
+		if c := strings.Compare(a.Board, b.Board); c != 0 {
+			return c
+		}
+		return strings.Compare(a.PersonaTag, b.PersonaTag)
+	})
+	return l.rankings.Apply(ctx, changes)
+}
+
+// reset replaces the rankings with the committed scores. nextTick is the tick whose changes are applied next.
+func (l *Leaderboard) reset(ctx context.Context, world *World, nextTick uint64) error {
+	l.mux.Lock()
+	clear(l.pending)
+	l.syncedTick = nextTick
+	l.mux.Unlock()
+
+	wCtx := NewReadOnlyWorldContext(world)
+	var entries []leaderboard.Entry
+	var errs []error
+	err := search.NewSearch().Entity(filter.Exact(filter.Component[leaderboard.Entry]())).
+		Each(wCtx, func(id types.EntityID) bool {
+			entry, err := GetComponent[leaderboard.Entry](wCtx, id)
+			if err != nil {
+				errs = append(errs, err)
+				return false
+			}
+			entries = append(entries, *entry)
+			return true
+		})
+	if err = errors.Join(append(errs, err)...); err != nil {
+		return eris.Wrap(err, "failed to load leaderboard entries")
+	}
+	return l.rankings.Reset(ctx, entries)
+}
+
+// -----------------------------------------------------------------------------
+// Leaderboard Queries
+// -----------------------------------------------------------------------------
+
+func (l *Leaderboard) topQuery(
+	wCtx engine.Context, req *leaderboard.TopRequest,
+) (*leaderboard.RanksResponse, error) {
+	limit := req.Limit
+	if limit <= 0 || limit > leaderboard.MaxRanks {
+		limit = leaderboard.MaxRanks
+	}
+	ranks, err := l.rankings.Top(context.Background(), req.Board, max(0, req.Offset), limit)
+	if err != nil {
+		return nil, err
+	}
+	return &leaderboard.RanksResponse{Ranks: ranks}, nil
+}
+
+func (l *Leaderboard) aroundQuery(
+	wCtx engine.Context, req *leaderboard.AroundRequest,
+) (*leaderboard.RanksResponse, error) {
+	radius := min(max(0, req.Radius), (leaderboard.MaxRanks-1)/2) //nolint:gomnd // ranks above and below
+	_, entry, err := getLeaderboardEntry(wCtx, req.Board, req.PersonaTag)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, eris.Wrapf(leaderboard.ErrNotRanked, "%s on %s", req.PersonaTag, req.Board)
+	}
+	ranks, err := l.rankings.Around(context.Background(), req.Board, entry.PersonaTag, radius)
+	if err != nil {
+		return nil, err
+	}
+	return &leaderboard.RanksResponse{Ranks: ranks}, nil
+}
+
+// -----------------------------------------------------------------------------
+// Leaderboard Helpers
+// -----------------------------------------------------------------------------
+
+// getLeaderboardEntry returns the entry of the persona on the board. If the persona has no entry, a nil entry is
+// returned.
+func getLeaderboardEntry(
+	wCtx engine.Context, board, personaTag string,
+) (types.EntityID, *leaderboard.Entry, error) {
+	id, ok, err := FindByIndex(wCtx, leaderboardIndex, leaderboardIndexKey(board, personaTag))
+	if err != nil || !ok {
+		return 0, nil, err
+	}
+	entry, err := GetComponent[leaderboard.Entry](wCtx, id)
+	if err != nil {
+		return 0, nil, err
+	}
+	return id, entry, nil
+}
+
+// leaderboardIndexKey is the key of an entry in the unique index. Persona tags are case-insensitive.
+func leaderboardIndexKey(board, personaTag string) string {
+	return board + "/" + strings.ToLower(personaTag)
+}
@@ -0,0 +1,136 @@
+package cardinal_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/leaderboard"
+	"pkg.world.dev/world-engine/cardinal/testutils"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+func queryRanks(tf *testutils.TestFixture, name string, req any) ([]leaderboard.Rank, int) {
+	res := tf.Post("query/leaderboard/"+name, req)
+	defer res.Body.Close()
+	bz, err := io.ReadAll(res.Body)
+	assert.NilError(tf, err)
+	if res.StatusCode != http.StatusOK {
+		return nil, res.StatusCode
+	}
+	var ranks leaderboard.RanksResponse
+	assert.NilError(tf, json.Unmarshal(bz, &ranks))
+	return ranks.Ranks, res.StatusCode
+}
+
+func topRanks(tf *testutils.TestFixture, board string, offset, limit int) []leaderboard.Rank {
+	ranks, status := queryRanks(tf, leaderboard.TopQueryName,
+		leaderboard.TopRequest{Board: board, Offset: offset, Limit: limit})
+	assert.Equal(tf, http.StatusOK, status)
+	return ranks
+}
+
+func TestLeaderboardRanksScores(t *testing.T) {
+	for name, opts := range map[string][]cardinal.LeaderboardOption{
+		"memory": nil,
+		"redis":  {cardinal.WithLeaderboardRedis()},
+	} {
+		t.Run(name, func(t *testing.T) {
+			tf := testutils.NewTestFixture(t, nil)
+			lb := cardinal.NewLeaderboardPlugin(opts...)
+			tf.World.RegisterPlugin(lb)
+			// update is run by a system in the next tick.
+			var update func(wCtx engine.Context) error
+			assert.NilError(t, cardinal.RegisterSystems(tf.World, func(wCtx engine.Context) error {
+				if update == nil {
+					return nil
+				}
+				defer func() { update = nil }()
+				return update(wCtx)
+			}))
+			tf.StartWorld()
+
+			update = func(wCtx engine.Context) error {
+				for persona, score := range map[string]int64{"alice": 10, "bob": 30, "carol": 20, "dave": 20} {
+					if err := lb.SetScore(wCtx, "kills", persona, score); err != nil {
+						return err
+					}
+				}
+				return lb.SetScore(wCtx, "deaths", "alice", 1)
+			}
+			tf.DoTick()
+			assert.DeepEqual(t, []leaderboard.Rank{
+				{Rank: 1, PersonaTag: "bob", Score: 30},
+				{Rank: 2, PersonaTag: "carol", Score: 20},
+				{Rank: 3, PersonaTag: "dave", Score: 20},
+				{Rank: 4, PersonaTag: "alice", Score: 10},
+			}, topRanks(tf, "kills", 0, 0))
+			assert.DeepEqual(t, []leaderboard.Rank{{Rank: 2, PersonaTag: "carol", Score: 20}}, topRanks(tf, "kills", 1, 1))
+			assert.Equal(t, 1, len(topRanks(tf, "deaths", 0, 0)))
+
+			var score int64
+			update = func(wCtx engine.Context) error {
+				var err error
+				if score, err = lb.AddScore(wCtx, "kills", "Alice", 25); err != nil {
+					return err
+				}
+				return lb.RemoveScore(wCtx, "kills", "bob")
+			}
+			tf.DoTick()
+			assert.Equal(t, int64(35), score)
+			assert.DeepEqual(t, []leaderboard.Rank{
+				{Rank: 1, PersonaTag: "alice", Score: 35},
+				{Rank: 2, PersonaTag: "carol", Score: 20},
+				{Rank: 3, PersonaTag: "dave", Score: 20},
+			}, topRanks(tf, "kills", 0, 10))
+
+			ranks, status := queryRanks(tf, leaderboard.AroundQueryName,
+				leaderboard.AroundRequest{Board: "kills", PersonaTag: "DAVE", Radius: 1})
+			assert.Equal(t, http.StatusOK, status)
+			assert.DeepEqual(t, []leaderboard.Rank{
+				{Rank: 2, PersonaTag: "carol", Score: 20},
+				{Rank: 3, PersonaTag: "dave", Score: 20},
+			}, ranks)
+			_, status = queryRanks(tf, leaderboard.AroundQueryName,
+				leaderboard.AroundRequest{Board: "kills", PersonaTag: "bob", Radius: 1})
+			assert.Assert(t, status != http.StatusOK)
+		})
+	}
+}
+
+func TestLeaderboardIsResetAfterRollback(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil)
+	lb := cardinal.NewLeaderboardPlugin()
+	tf.World.RegisterPlugin(lb)
+	// update is run by a system in the next tick.
+	var update func(wCtx engine.Context) error
+	assert.NilError(t, cardinal.RegisterSystems(tf.World, func(wCtx engine.Context) error {
+		if update == nil {
+			return nil
+		}
+		defer func() { update = nil }()
+		return update(wCtx)
+	}))
+	tf.StartWorld()
+
+	update = func(wCtx engine.Context) error {
+		return lb.SetScore(wCtx, "kills", "alice", 10)
+	}
+	tf.DoTick()
+	_, err := tf.World.SaveCheckpoint("before")
+	assert.NilError(t, err)
+	update = func(wCtx engine.Context) error {
+		_, err := lb.AddScore(wCtx, "kills", "alice", 25)
+		return err
+	}
+	tf.DoTick()
+	assert.DeepEqual(t, []leaderboard.Rank{{Rank: 1, PersonaTag: "alice", Score: 35}}, topRanks(tf, "kills", 0, 0))
+
+	_, err = tf.World.RollbackToCheckpoint("before")
+	assert.NilError(t, err)
+	tf.DoTick()
+	assert.DeepEqual(t, []leaderboard.Rank{{Rank: 1, PersonaTag: "alice", Score: 10}}, topRanks(tf, "kills", 0, 0))
+}
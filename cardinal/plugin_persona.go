@@ -3,6 +3,7 @@ package cardinal
 import (
 	"errors"
 	"strings"
+	"sync"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/rotisserie/eris"
@@ -27,17 +28,23 @@ var (
 	// These global variables are used to quickly identify already-created persona tags. The map should exactly match
 	// the persona tag information stored in the ECS layer. When Cardinal restarts, this map needs to be rebuilt.
 	//
-	// globalPersonaTagToAddressIndex keeps track of the mapping of persona-tags->signer-address so it doesn't need to
-	// be recomputed each tick.
-	globalPersonaTagToAddressIndex personaIndex
-	// tickOfPersonaTagToAddressIndex is the tick that the globalPersonaTagToAddressIndex was built on. In normal usage,
-	// wCtx.CurrentTick should always be greater than this number, but during tests the currentTick will be reset.
-	// Tracking this number at the global is easier than updating each test to reset these global value.
-	tickOfPersonaTagToAddressIndex uint64
+	// globalPersonaIndexes keeps track of the mapping of persona-tags->signer-address of each world (keyed by
+	// namespace, since a WorldManager can host several worlds in one process) so it doesn't need to be recomputed
+	// each tick.
+	globalPersonaIndexes   = map[string]*personaIndexState{}
+	globalPersonaIndexesMu sync.Mutex
 )
 
 type personaIndex = map[string]personaIndexEntry
 
+type personaIndexState struct {
+	index personaIndex
+	// tick is the tick that the index was built on. In normal usage, wCtx.CurrentTick should always be greater than
+	// this number, but during tests the currentTick will be reset. Tracking this number is easier than updating each
+	// test to reset the index.
+	tick uint64
+}
+
 type personaIndexEntry struct {
 	SignerAddress string
 	EntityID      types.EntityID
@@ -77,6 +84,12 @@ func (p *personaPlugin) RegisterQueries(world *World) error {
 	if err != nil {
 		return err
 	}
+	err = RegisterQuery[query.PersonaAckQueryRequest, query.PersonaAckQueryResponse](world, "ack",
+		query.PersonaAckQuery,
+		querylib.WithCustomQueryGroup[query.PersonaAckQueryRequest, query.PersonaAckQueryResponse]("persona"))
+	if err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -117,7 +130,8 @@ func (p *personaPlugin) RegisterMessages(world *World) error {
 // users who want to interact with the game via smart contract can link their EVM address to their persona tag, enabling
 // them to mutate their owned state from the context of the EVM.
 func authorizePersonaAddressSystem(wCtx engine.Context) error {
-	if err := buildGlobalPersonaIndex(wCtx); err != nil {
+	personaTagToAddressIndex, err := buildGlobalPersonaIndex(wCtx)
+	if err != nil {
 		return err
 	}
 	return EachMessage[msg.AuthorizePersonaAddress, msg.AuthorizePersonaAddressResult](
@@ -130,7 +144,7 @@ func authorizePersonaAddressSystem(wCtx engine.Context) error {
 
 			// Check if the Persona Tag exists
 			lowerPersona := strings.ToLower(tx.PersonaTag)
-			data, ok := globalPersonaTagToAddressIndex[lowerPersona]
+			data, ok := personaTagToAddressIndex[lowerPersona]
 			if !ok {
 				return result, eris.Errorf("persona %s does not exist", tx.PersonaTag)
 			}
@@ -170,7 +184,8 @@ func authorizePersonaAddressSystem(wCtx engine.Context) error {
 // createPersonaSystem is a system that will associate persona tags with signature addresses. Each persona tag
 // may have at most 1 signer, so additional attempts to register a signer with a persona tag will be ignored.
 func createPersonaSystem(wCtx engine.Context) error {
-	if err := buildGlobalPersonaIndex(wCtx); err != nil {
+	personaTagToAddressIndex, err := buildGlobalPersonaIndex(wCtx)
+	if err != nil {
 		return err
 	}
 	return EachMessage[msg.CreatePersona, msg.CreatePersonaResult](
@@ -190,7 +205,7 @@ func createPersonaSystem(wCtx engine.Context) error {
 
 			// Temporarily convert tag to lowercase to check against mapping of lowercase tags
 			lowerPersona := strings.ToLower(txMsg.PersonaTag)
-			if _, ok := globalPersonaTagToAddressIndex[lowerPersona]; ok {
+			if _, ok := personaTagToAddressIndex[lowerPersona]; ok {
 				// This PersonaTag has already been registered. Don't do anything
 				err = eris.Errorf("persona tag %s has already been registered", txMsg.PersonaTag)
 				return result, err
@@ -208,7 +223,7 @@ func createPersonaSystem(wCtx engine.Context) error {
 			); err != nil {
 				return result, eris.Wrap(err, "")
 			}
-			globalPersonaTagToAddressIndex[lowerPersona] = personaIndexEntry{
+			personaTagToAddressIndex[lowerPersona] = personaIndexEntry{
 				SignerAddress: txMsg.SignerAddress,
 				EntityID:      id,
 			}
@@ -222,13 +237,18 @@ func createPersonaSystem(wCtx engine.Context) error {
 // Persona Index
 // -----------------------------------------------------------------------------
 
-func buildGlobalPersonaIndex(wCtx engine.Context) error {
+// buildGlobalPersonaIndex returns the persona index of the world, building it first if needed.
+func buildGlobalPersonaIndex(wCtx engine.Context) (personaIndex, error) {
+	globalPersonaIndexesMu.Lock()
+	defer globalPersonaIndexesMu.Unlock()
+
 	// Rebuild the index if we haven't built it yet OR if we're in test and the CurrentTick has been reset.
-	if globalPersonaTagToAddressIndex != nil && tickOfPersonaTagToAddressIndex < wCtx.CurrentTick() {
-		return nil
+	state, ok := globalPersonaIndexes[wCtx.Namespace()]
+	if ok && state.tick < wCtx.CurrentTick() {
+		return state.index, nil
 	}
-	tickOfPersonaTagToAddressIndex = wCtx.CurrentTick()
-	globalPersonaTagToAddressIndex = map[string]personaIndexEntry{}
+	state = &personaIndexState{index: personaIndex{}, tick: wCtx.CurrentTick()}
+	globalPersonaIndexes[wCtx.Namespace()] = state
 	var errs []error
 	s := search.NewSearch().Entity(filter.Exact(filter.Component[component.SignerComponent]()))
 	err := s.Each(wCtx,
@@ -239,7 +259,7 @@ func buildGlobalPersonaIndex(wCtx engine.Context) error {
 				return true
 			}
 			lowerPersona := strings.ToLower(sc.PersonaTag)
-			globalPersonaTagToAddressIndex[lowerPersona] = personaIndexEntry{
+			state.index[lowerPersona] = personaIndexEntry{
 				SignerAddress: sc.SignerAddress,
 				EntityID:      id,
 			}
@@ -247,10 +267,17 @@ func buildGlobalPersonaIndex(wCtx engine.Context) error {
 		},
 	)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if len(errs) != 0 {
-		return errors.Join(errs...)
+		return nil, errors.Join(errs...)
 	}
-	return nil
+	return state.index, nil
+}
+
+// forgetPersonaIndex drops the persona index of a world that is no longer running.
+func forgetPersonaIndex(namespace string) {
+	globalPersonaIndexesMu.Lock()
+	defer globalPersonaIndexesMu.Unlock()
+	delete(globalPersonaIndexes, namespace)
 }
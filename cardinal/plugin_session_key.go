@@ -0,0 +1,226 @@
+package cardinal
+
+import (
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal/iterators"
+	"pkg.world.dev/world-engine/cardinal/message"
+	"pkg.world.dev/world-engine/cardinal/persona"
+	"pkg.world.dev/world-engine/cardinal/persona/component"
+	"pkg.world.dev/world-engine/cardinal/persona/msg"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+	"pkg.world.dev/world-engine/cardinal/types/txpool"
+)
+
+// sessionKeysIndex is the unique index of the entities that hold the session keys of each persona, keyed by the
+// lowercase persona tag.
+const sessionKeysIndex = "session-keys"
+
+var _ Plugin = (*sessionKeyPlugin)(nil)
+
+type sessionKeyPlugin struct{}
+
+// NewSessionKeyPlugin returns the session key plugin, which lets personas authorize expiring, scoped keys that may sign
+// some of their transactions (see msg.AuthorizeSessionKey). Register it with World.RegisterPlugin after the game's
+// components and messages, so that it doesn't change their IDs.
+func NewSessionKeyPlugin() Plugin {
+	return &sessionKeyPlugin{}
+}
+
+func (p *sessionKeyPlugin) Register(world *World) error {
+	err := errors.Join(
+		RegisterComponent[component.SessionKeysComponent](world),
+		RegisterMessage[msg.AuthorizeSessionKey, msg.AuthorizeSessionKeyResult](
+			world,
+			msg.AuthorizeSessionKeyMessageName,
+			message.WithCustomMessageGroup[msg.AuthorizeSessionKey, msg.AuthorizeSessionKeyResult]("persona")),
+		RegisterMessage[msg.RevokeSessionKey, msg.RevokeSessionKeyResult](
+			world,
+			msg.RevokeSessionKeyMessageName,
+			message.WithCustomMessageGroup[msg.RevokeSessionKey, msg.RevokeSessionKeyResult]("persona")),
+		RegisterSystems(world, authorizeSessionKeySystem, revokeSessionKeySystem),
+	)
+	if err != nil {
+		return err
+	}
+	err = RegisterUniqueIndex[component.SessionKeysComponent](world, sessionKeysIndex,
+		func(k component.SessionKeysComponent) string {
+			return strings.ToLower(k.PersonaTag)
+		})
+	if err != nil {
+		return err
+	}
+	world.sessionKeys = true
+	return nil
+}
+
+// authorizeSessionKeySystem lets a persona authorize a session key that may sign some of its transactions until it
+// expires. The server accepts a transaction that is signed by an unexpired session key of its persona as if it was
+// signed by the persona's signer. Expired keys are pruned whenever a new key is authorized.
+func authorizeSessionKeySystem(wCtx engine.Context) error {
+	personaTagToAddressIndex, err := buildGlobalPersonaIndex(wCtx)
+	if err != nil {
+		return err
+	}
+	return EachMessage[msg.AuthorizeSessionKey, msg.AuthorizeSessionKeyResult](
+		wCtx,
+		func(txData message.TxData[msg.AuthorizeSessionKey]) (result msg.AuthorizeSessionKeyResult, err error) {
+			txMsg, tx := txData.Msg, txData.Tx
+			if _, ok := personaTagToAddressIndex[strings.ToLower(tx.PersonaTag)]; !ok {
+				return result, eris.Errorf("persona %s does not exist", tx.PersonaTag)
+			}
+			address := strings.ToLower(strings.ReplaceAll(txMsg.Address, " ", ""))
+			if !common.IsHexAddress(address) {
+				return result, eris.Errorf("session key address %s is invalid", txMsg.Address)
+			}
+			if txMsg.ExpiresAtTick == 0 && txMsg.ExpiresAt == 0 {
+				return result, eris.New("session key must expire at a tick or a timestamp")
+			}
+			key := component.SessionKey{
+				Address:       address,
+				Messages:      txMsg.Messages,
+				ExpiresAtTick: txMsg.ExpiresAtTick,
+				ExpiresAt:     txMsg.ExpiresAt,
+			}
+			if key.Expired(wCtx.CurrentTick(), wCtx.Timestamp()) {
+				return result, eris.New("session key has already expired")
+			}
+			for _, name := range key.Messages {
+				if !persona.SessionKeyMaySign(name) {
+					return result, eris.Errorf("session keys may not sign %s messages", name)
+				}
+			}
+
+			id, keys, err := getSessionKeys(wCtx, tx.PersonaTag)
+			if err != nil {
+				return result, err
+			}
+			// Re-authorizing a key replaces its scope and expiry
+			keys.Keys = slices.DeleteFunc(keys.Keys, func(k component.SessionKey) bool {
+				return k.Address == address || k.Expired(wCtx.CurrentTick(), wCtx.Timestamp())
+			})
+			if len(keys.Keys) >= persona.MaximumSessionKeys {
+				return result, eris.Errorf("persona %s already has %d session keys", tx.PersonaTag, len(keys.Keys))
+			}
+			keys.Keys = append(keys.Keys, key)
+			if id == iterators.BadID {
+				_, err = Create(wCtx, *keys)
+			} else {
+				err = SetComponent[component.SessionKeysComponent](wCtx, id, keys)
+			}
+			if err != nil {
+				return result, eris.Wrap(err, "unable to store session key")
+			}
+			result.Success = true
+			return result, nil
+		},
+	)
+}
+
+// revokeSessionKeySystem removes a session key of a persona before it expires. The transactions signed by the key that
+// are still queued are dropped when the next tick starts.
+func revokeSessionKeySystem(wCtx engine.Context) error {
+	return EachMessage[msg.RevokeSessionKey, msg.RevokeSessionKeyResult](
+		wCtx,
+		func(txData message.TxData[msg.RevokeSessionKey]) (result msg.RevokeSessionKeyResult, err error) {
+			txMsg, tx := txData.Msg, txData.Tx
+			address := strings.ToLower(strings.ReplaceAll(txMsg.Address, " ", ""))
+			id, keys, err := getSessionKeys(wCtx, tx.PersonaTag)
+			if err != nil {
+				return result, err
+			}
+			n := len(keys.Keys)
+			keys.Keys = slices.DeleteFunc(keys.Keys, func(k component.SessionKey) bool {
+				return k.Address == address
+			})
+			if id == iterators.BadID || len(keys.Keys) == n {
+				return result, eris.Errorf("persona %s has no session key %s", tx.PersonaTag, txMsg.Address)
+			}
+			if err = SetComponent[component.SessionKeysComponent](wCtx, id, keys); err != nil {
+				return result, eris.Wrap(err, "unable to remove session key")
+			}
+			result.Success = true
+			return result, nil
+		},
+	)
+}
+
+// getSessionKeys returns the session keys of the persona, and the entity that holds them. If the persona never
+// authorized a session key, an empty component and iterators.BadID are returned.
+func getSessionKeys(wCtx engine.Context, personaTag string) (types.EntityID, *component.SessionKeysComponent, error) {
+	id, ok, err := FindByIndex(wCtx, sessionKeysIndex, strings.ToLower(personaTag))
+	if err != nil {
+		return iterators.BadID, nil, err
+	}
+	if !ok {
+		return iterators.BadID, &component.SessionKeysComponent{PersonaTag: personaTag}, nil
+	}
+	keys, err := GetComponent[component.SessionKeysComponent](wCtx, id)
+	if err != nil {
+		return iterators.BadID, nil, err
+	}
+	return id, keys, nil
+}
+
+// removeRevokedSessionKeyTxs removes the transactions that were signed by a session key that may no longer sign them,
+// because the key was revoked or expired while they were queued, from the pool, and returns them. The keys committed by
+// the previous tick are checked, so the same transactions are removed when the tick is replayed.
+func (w *World) removeRevokedSessionKeyTxs(txPool *txpool.TxPool, timestamp uint64) ([]txpool.TxData, error) {
+	if !w.sessionKeys || txPool.GetAmountOfTxs() == 0 {
+		return nil, nil
+	}
+	wCtx := NewReadOnlyWorldContext(w)
+	personaIndex, err := buildGlobalPersonaIndex(wCtx)
+	if err != nil {
+		return nil, err
+	}
+	// The keys of each persona, or nil if the persona never authorized a session key
+	personaKeys := map[string]*component.SessionKeysComponent{}
+	var errs []error
+	revoked := txPool.RemoveFunc(func(tx txpool.TxData) bool {
+		if tx.EVMSourceTxHash != "" || tx.Tx.IsSystemTransaction() {
+			return false
+		}
+		msgType, ok := w.GetMessageByID(tx.MsgID)
+		if !ok || msgType.IsAdminOnly() || !persona.SessionKeyMaySign(msgType.FullName()) {
+			return false
+		}
+		personaTag := strings.ToLower(tx.Tx.PersonaTag)
+		keys, ok := personaKeys[personaTag]
+		if !ok {
+			id, found, err := getSessionKeys(wCtx, personaTag)
+			if err != nil {
+				errs = append(errs, err)
+				return false
+			}
+			if id != iterators.BadID {
+				keys = found
+			}
+			personaKeys[personaTag] = keys
+		}
+		if keys == nil {
+			return false
+		}
+		// Transactions whose signature can't be recovered were accepted without signature verification
+		signer, err := tx.Tx.Signer()
+		if err != nil {
+			return false
+		}
+		if entry, ok := personaIndex[personaTag]; ok && common.HexToAddress(entry.SignerAddress) == signer {
+			return false
+		}
+		for _, key := range keys.Keys {
+			if common.HexToAddress(key.Address) == signer {
+				return !key.Allows(msgType.FullName(), w.CurrentTick(), timestamp)
+			}
+		}
+		return true
+	})
+	return revoked, errors.Join(errs...)
+}
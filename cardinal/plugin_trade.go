@@ -0,0 +1,508 @@
+package cardinal
+
+import (
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal/iterators"
+	"pkg.world.dev/world-engine/cardinal/message"
+	"pkg.world.dev/world-engine/cardinal/persona/component"
+	querylib "pkg.world.dev/world-engine/cardinal/query"
+	"pkg.world.dev/world-engine/cardinal/search"
+	"pkg.world.dev/world-engine/cardinal/search/filter"
+	"pkg.world.dev/world-engine/cardinal/trade"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+	"pkg.world.dev/world-engine/cardinal/wallet"
+)
+
+const (
+	// DefaultTradeAcceptWindow is the number of ticks a counterparty has to accept a trade if NewTradePlugin is given 0.
+	DefaultTradeAcceptWindow = 100
+
+	tradeGroup = "trade"
+)
+
+var _ Plugin = (*tradePlugin)(nil)
+
+type tradePlugin struct {
+	acceptWindow uint64
+}
+
+// NewTradePlugin returns the trade plugin, which lets personas trade items (entities they own, see ClaimEntity) and
+// currency (held in the wallets of the wallet plugin, see NewWalletPlugin, which must be registered as well) with each
+// other. The items and currency a persona offers are held in escrow until the trade is accepted, cancelled or expires.
+// A proposed trade must be accepted within acceptWindow ticks. Register it with World.RegisterPlugin before starting
+// the game.
+func NewTradePlugin(acceptWindow uint64) Plugin {
+	if acceptWindow == 0 {
+		acceptWindow = DefaultTradeAcceptWindow
+	}
+	return &tradePlugin{acceptWindow: acceptWindow}
+}
+
+func (p *tradePlugin) Register(world *World) error {
+	return errors.Join(
+		RegisterComponent[trade.Trade](world),
+		RegisterMessage[trade.ProposeTrade, trade.ProposeTradeResult](
+			world,
+			trade.ProposeTradeMessageName,
+			message.WithCustomMessageGroup[trade.ProposeTrade, trade.ProposeTradeResult](tradeGroup)),
+		RegisterMessage[trade.AcceptTrade, trade.AcceptTradeResult](
+			world,
+			trade.AcceptTradeMessageName,
+			message.WithCustomMessageGroup[trade.AcceptTrade, trade.AcceptTradeResult](tradeGroup)),
+		RegisterMessage[trade.CancelTrade, trade.CancelTradeResult](
+			world,
+			trade.CancelTradeMessageName,
+			message.WithCustomMessageGroup[trade.CancelTrade, trade.CancelTradeResult](tradeGroup)),
+		RegisterQuery[trade.ListTradesRequest, trade.ListTradesResponse](
+			world,
+			trade.ListTradesQueryName,
+			listTradesQuery,
+			querylib.WithCustomQueryGroup[trade.ListTradesRequest, trade.ListTradesResponse](tradeGroup)),
+		// Trades are expired before messages are handled, so a trade can't be accepted after its last tick.
+		RegisterSystems(world, expireTradesSystem, p.proposeTradeSystem, acceptTradeSystem, cancelTradeSystem),
+	)
+}
+
+// -----------------------------------------------------------------------------
+// Trade Systems
+// -----------------------------------------------------------------------------
+
+// proposeTradeSystem creates a pending trade for every valid propose-trade message, and moves the items and currency
+// the proposer offers into the escrow of the trade, so that they can't be traded or spent twice. The counterparty's
+// side is validated when the trade is accepted.
+func (p *tradePlugin) proposeTradeSystem(wCtx engine.Context) error {
+	personaIndex, err := buildGlobalPersonaIndex(wCtx)
+	if err != nil {
+		return err
+	}
+	return EachMessage[trade.ProposeTrade, trade.ProposeTradeResult](
+		wCtx,
+		func(txData message.TxData[trade.ProposeTrade]) (result trade.ProposeTradeResult, err error) {
+			txMsg, proposer := txData.Msg, txData.Tx.PersonaTag
+			entry, ok := personaIndex[strings.ToLower(txMsg.Counterparty)]
+			if !ok {
+				return result, eris.Errorf("persona %s does not exist", txMsg.Counterparty)
+			}
+			if strings.EqualFold(proposer, txMsg.Counterparty) {
+				return result, eris.Wrap(trade.ErrInvalidOffer, "cannot trade with yourself")
+			}
+			if err = validateOffer(txMsg.Give); err != nil {
+				return result, err
+			}
+			if err = validateOffer(txMsg.Want); err != nil {
+				return result, err
+			}
+			if len(txMsg.Give.Items)+len(txMsg.Give.Currency)+len(txMsg.Want.Items)+len(txMsg.Want.Currency) == 0 {
+				return result, eris.Wrap(trade.ErrInvalidOffer, "trade is empty")
+			}
+			if err = checkCanGive(wCtx, proposer, txMsg.Give); err != nil {
+				return result, err
+			}
+			// Items are owned under the persona tag as it was registered, so the counterparty is stored that way.
+			signer, err := GetComponent[component.SignerComponent](wCtx, entry.EntityID)
+			if err != nil {
+				return result, err
+			}
+
+			window := p.acceptWindow
+			if txMsg.AcceptWithinTicks != 0 && txMsg.AcceptWithinTicks < window {
+				window = txMsg.AcceptWithinTicks
+			}
+			t := trade.Trade{
+				Proposer:     proposer,
+				Counterparty: signer.PersonaTag,
+				Give:         txMsg.Give,
+				Want:         txMsg.Want,
+				ExpiresAt:    wCtx.CurrentTick() + window,
+				Status:       trade.StatusPending,
+			}
+			id, err := Create(wCtx, t)
+			if err != nil {
+				return result, eris.Wrap(err, "")
+			}
+			if err = escrowOffer(wCtx, id, &t); err != nil {
+				return result, errors.Join(err, Remove(wCtx, id))
+			}
+			return trade.ProposeTradeResult{TradeID: id, ExpiresAt: t.ExpiresAt}, nil
+		},
+	)
+}
+
+// acceptTradeSystem completes every trade that is accepted by its counterparty: the proposer receives what the
+// counterparty gives, and the counterparty receives what is held in escrow. The counterparty's side is validated before
+// anything is changed, and a change that fails anyway rolls back the changes that were already made, so a trade is
+// either applied completely or not at all.
+func acceptTradeSystem(wCtx engine.Context) error {
+	return EachMessage[trade.AcceptTrade, trade.AcceptTradeResult](
+		wCtx,
+		func(txData message.TxData[trade.AcceptTrade]) (result trade.AcceptTradeResult, err error) {
+			id := txData.Msg.TradeID
+			t, err := getPendingTrade(wCtx, id)
+			if err != nil {
+				return result, err
+			}
+			if !strings.EqualFold(txData.Tx.PersonaTag, t.Counterparty) {
+				return result, eris.Wrapf(trade.ErrNotParty, "only %s can accept the trade", t.Counterparty)
+			}
+			if wCtx.CurrentTick() > t.ExpiresAt {
+				return result, eris.Wrapf(trade.ErrTradeExpired, "trade expired at tick %d", t.ExpiresAt)
+			}
+			if err = checkCanGive(wCtx, t.Counterparty, t.Want); err != nil {
+				return result, err
+			}
+			if err = checkCanReceive(wCtx, t.Proposer, t.Want, 0); err != nil {
+				return result, err
+			}
+			if err = checkCanReceive(wCtx, t.Counterparty, t.Give, len(t.Want.Items)); err != nil {
+				return result, err
+			}
+
+			if err = applyTrade(wCtx, id, t); err != nil {
+				return result, err
+			}
+			t.Status = trade.StatusCompleted
+			t.CompletedAt = wCtx.CurrentTick()
+			if err = SetComponent[trade.Trade](wCtx, id, t); err != nil {
+				return result, err
+			}
+
+			result.Receipt = trade.Receipt{
+				TradeID:              id,
+				Tick:                 t.CompletedAt,
+				Proposer:             t.Proposer,
+				Counterparty:         t.Counterparty,
+				ProposerReceived:     t.Want,
+				CounterpartyReceived: t.Give,
+			}
+			err = wCtx.EmitEvent(map[string]any{"event": "trade-completed", "receipt": result.Receipt})
+			return result, err
+		},
+	)
+}
+
+// cancelTradeSystem removes the pending trades that are cancelled by either side, and returns what is held in escrow
+// to the proposer.
+func cancelTradeSystem(wCtx engine.Context) error {
+	return EachMessage[trade.CancelTrade, trade.CancelTradeResult](
+		wCtx,
+		func(txData message.TxData[trade.CancelTrade]) (result trade.CancelTradeResult, err error) {
+			t, err := getPendingTrade(wCtx, txData.Msg.TradeID)
+			if err != nil {
+				return result, err
+			}
+			if !t.Involves(txData.Tx.PersonaTag) {
+				return result, eris.Wrap(trade.ErrNotParty, "")
+			}
+			if err = returnEscrow(wCtx, txData.Msg.TradeID, t); err != nil {
+				return result, err
+			}
+			if err = Remove(wCtx, txData.Msg.TradeID); err != nil {
+				return result, err
+			}
+			err = wCtx.EmitEvent(map[string]any{
+				"event":       "trade-cancelled",
+				"tradeID":     txData.Msg.TradeID,
+				"cancelledBy": txData.Tx.PersonaTag,
+			})
+			return trade.CancelTradeResult{Success: err == nil}, err
+		},
+	)
+}
+
+// expireTradesSystem removes the pending trades whose accept window has passed, and returns what is held in escrow to
+// the proposer.
+func expireTradesSystem(wCtx engine.Context) error {
+	var expired []types.EntityID
+	err := search.NewSearch().Entity(filter.Exact(filter.Component[trade.Trade]())).
+		Where(FilterFunction[trade.Trade](func(t trade.Trade) bool {
+			return t.Status == trade.StatusPending && wCtx.CurrentTick() > t.ExpiresAt
+		})).
+		Each(wCtx, func(id types.EntityID) bool {
+			expired = append(expired, id)
+			return true
+		})
+	if err != nil {
+		return err
+	}
+	for _, id := range expired {
+		t, err := GetComponent[trade.Trade](wCtx, id)
+		if err != nil {
+			return err
+		}
+		if err = returnEscrow(wCtx, id, t); err != nil {
+			return err
+		}
+		if err = Remove(wCtx, id); err != nil {
+			return err
+		}
+		if err = wCtx.EmitEvent(map[string]any{"event": "trade-expired", "tradeID": id}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+// Trade Query
+// -----------------------------------------------------------------------------
+
+func listTradesQuery(wCtx engine.Context, req *trade.ListTradesRequest) (*trade.ListTradesResponse, error) {
+	res := &trade.ListTradesResponse{Trades: []trade.TradeInfo{}}
+	var errs []error
+	err := search.NewSearch().Entity(filter.Exact(filter.Component[trade.Trade]())).
+		Each(wCtx, func(id types.EntityID) bool {
+			t, err := GetComponent[trade.Trade](wCtx, id)
+			if err != nil {
+				errs = append(errs, err)
+				return false
+			}
+			if t.Involves(req.PersonaTag) && (req.Status == "" || t.Status == req.Status) {
+				res.Trades = append(res.Trades, trade.TradeInfo{ID: id, Trade: *t})
+			}
+			return true
+		})
+	if err != nil {
+		return nil, err
+	}
+	if len(errs) != 0 {
+		return nil, errors.Join(errs...)
+	}
+	return res, nil
+}
+
+// -----------------------------------------------------------------------------
+// Trade Helpers
+// -----------------------------------------------------------------------------
+
+func getPendingTrade(wCtx engine.Context, id types.EntityID) (*trade.Trade, error) {
+	t, err := GetComponent[trade.Trade](wCtx, id)
+	if err != nil || t.Status != trade.StatusPending {
+		return nil, eris.Wrapf(trade.ErrTradeNotFound, "trade %d", id)
+	}
+	return t, nil
+}
+
+func validateOffer(offer trade.Offer) error {
+	sorted := slices.Clone(offer.Items)
+	slices.Sort(sorted)
+	if len(slices.Compact(sorted)) != len(offer.Items) {
+		return eris.Wrap(trade.ErrInvalidOffer, "an item is offered more than once")
+	}
+	for _, currency := range sortedCurrencies(offer.Currency) {
+		if offer.Currency[currency] == 0 {
+			return eris.Wrapf(trade.ErrInvalidOffer, "amount of %s must be positive", currency)
+		}
+	}
+	return nil
+}
+
+// checkCanGive returns an error if the persona does not own all items of the offer or does not have enough currency.
+func checkCanGive(wCtx engine.Context, personaTag string, offer trade.Offer) error {
+	for _, item := range offer.Items {
+		owns, err := OwnsEntity(wCtx, personaTag, item)
+		if err != nil {
+			return err
+		}
+		if !owns {
+			return eris.Wrapf(trade.ErrNotOwner, "%s does not own item %d", personaTag, item)
+		}
+	}
+	if len(offer.Currency) == 0 {
+		return nil
+	}
+	_, w, err := getPersonaWallet(wCtx, personaTag)
+	if err != nil {
+		return err
+	}
+	for _, currency := range sortedCurrencies(offer.Currency) {
+		if amount := offer.Currency[currency]; w.Balances[currency] < amount {
+			return eris.Wrapf(trade.ErrInsufficientBalance, "%s has less than %d %s", personaTag, amount, currency)
+		}
+	}
+	return nil
+}
+
+// checkCanReceive returns an error if the persona would own more entities than its entity quota allows after
+// receiving the offer in exchange for the given number of items. A persona that receives currency but has no wallet yet
+// needs room for the wallet as well.
+func checkCanReceive(wCtx engine.Context, personaTag string, receive trade.Offer, giveItems int) error {
+	num := len(receive.Items) - giveItems
+	if len(receive.Currency) != 0 {
+		id, _, err := getPersonaWallet(wCtx, personaTag)
+		if err != nil {
+			return err
+		}
+		if id == iterators.BadID {
+			num++
+		}
+	}
+	if num <= 0 {
+		return nil
+	}
+	return wCtx.ReservePersonaEntityQuota(personaTag, num)
+}
+
+// escrowOffer moves the items and currency that the proposer offers into the escrow of the trade. The offer must have
+// been validated with checkCanGive.
+func escrowOffer(wCtx engine.Context, id types.EntityID, t *trade.Trade) (err error) {
+	var changes tradeChanges
+	defer func() { err = changes.rollback(err) }()
+
+	for _, item := range t.Give.Items {
+		if err = changes.moveItem(wCtx, t.Proposer, trade.EscrowOwner(id), item); err != nil {
+			return err
+		}
+	}
+	for _, currency := range sortedCurrencies(t.Give.Currency) {
+		if err = changes.debit(wCtx, t.Proposer, currency, t.Give.Currency[currency]); err != nil {
+			return err
+		}
+	}
+	return changes.commit(wCtx)
+}
+
+// returnEscrow gives the items and currency held in the escrow of the trade back to the proposer. Escrowed items that
+// were removed in the meantime are skipped. The items are returned even if the proposer has reached its entity quota
+// in the meantime, since they were the proposer's before.
+func returnEscrow(wCtx engine.Context, id types.EntityID, t *trade.Trade) (err error) {
+	var changes tradeChanges
+	defer func() { err = changes.rollback(err) }()
+
+	for _, item := range t.Give.Items {
+		var owned bool
+		if owned, err = OwnsEntity(wCtx, trade.EscrowOwner(id), item); err != nil {
+			return err
+		}
+		if !owned {
+			continue
+		}
+		if err = changes.moveItem(wCtx, trade.EscrowOwner(id), t.Proposer, item); err != nil {
+			return err
+		}
+	}
+	for _, currency := range sortedCurrencies(t.Give.Currency) {
+		if err = changes.credit(wCtx, t.Proposer, currency, t.Give.Currency[currency]); err != nil {
+			return err
+		}
+	}
+	return changes.commit(wCtx)
+}
+
+// applyTrade gives what the counterparty gives to the proposer, and what is held in escrow to the counterparty. The
+// trade must have been validated with checkCanGive and checkCanReceive.
+func applyTrade(wCtx engine.Context, id types.EntityID, t *trade.Trade) (err error) {
+	var changes tradeChanges
+	defer func() { err = changes.rollback(err) }()
+
+	for _, item := range t.Give.Items {
+		if err = changes.moveItem(wCtx, trade.EscrowOwner(id), t.Counterparty, item); err != nil {
+			if eris.Is(err, ErrEntityNotOwned) {
+				err = eris.Wrapf(trade.ErrNotOwner, "item %d is no longer held in escrow", item)
+			}
+			return err
+		}
+	}
+	for _, item := range t.Want.Items {
+		if err = changes.moveItem(wCtx, t.Counterparty, t.Proposer, item); err != nil {
+			return err
+		}
+	}
+	for _, currency := range sortedCurrencies(t.Want.Currency) {
+		if err = changes.debit(wCtx, t.Counterparty, currency, t.Want.Currency[currency]); err != nil {
+			return err
+		}
+		if err = changes.credit(wCtx, t.Proposer, currency, t.Want.Currency[currency]); err != nil {
+			return err
+		}
+	}
+	for _, currency := range sortedCurrencies(t.Give.Currency) {
+		if err = changes.credit(wCtx, t.Counterparty, currency, t.Give.Currency[currency]); err != nil {
+			return err
+		}
+	}
+	return changes.commit(wCtx)
+}
+
+// tradeChanges records the changes that are made to the owners of items and to wallets while a part of a trade is
+// applied, so that the changes that were already made can be undone if a later one fails. The wallet events of the
+// changes are only emitted once all changes were made.
+type tradeChanges struct {
+	undo   []func() error
+	events []walletEvent
+}
+
+type walletEvent struct {
+	eventType  string
+	personaTag string
+	currency   string
+	amount     uint64
+}
+
+func (c *tradeChanges) moveItem(wCtx engine.Context, from, to string, item types.EntityID) error {
+	if err := transferEntity(wCtx, from, to, item); err != nil {
+		return err
+	}
+	c.undo = append(c.undo, func() error { return transferEntity(wCtx, to, from, item) })
+	return nil
+}
+
+func (c *tradeChanges) debit(wCtx engine.Context, personaTag, currency string, amount uint64) error {
+	if err := debitWallet(wCtx, personaTag, currency, amount); err != nil {
+		return err
+	}
+	c.undo = append(c.undo, func() error {
+		_, err := creditWallet(wCtx, personaTag, currency, amount)
+		return err
+	})
+	c.events = append(c.events, walletEvent{wallet.SpendEventType, personaTag, currency, amount})
+	return nil
+}
+
+func (c *tradeChanges) credit(wCtx engine.Context, personaTag, currency string, amount uint64) error {
+	if _, err := creditWallet(wCtx, personaTag, currency, amount); err != nil {
+		return err
+	}
+	c.undo = append(c.undo, func() error { return debitWallet(wCtx, personaTag, currency, amount) })
+	c.events = append(c.events, walletEvent{wallet.EarnEventType, personaTag, currency, amount})
+	return nil
+}
+
+// commit emits the wallet events of the changes.
+func (c *tradeChanges) commit(wCtx engine.Context) error {
+	for _, event := range c.events {
+		if err := emitWalletEvent(wCtx, event.eventType, event.personaTag, event.currency, event.amount); err != nil {
+			return err
+		}
+	}
+	c.undo = nil
+	return nil
+}
+
+// rollback undoes the changes in reverse order if err is not nil, and returns err along with the errors of the undo.
+func (c *tradeChanges) rollback(err error) error {
+	if err == nil {
+		return nil
+	}
+	for i := len(c.undo) - 1; i >= 0; i-- {
+		if undoErr := c.undo[i](); undoErr != nil {
+			err = errors.Join(err, eris.Wrap(undoErr, "failed to roll back the trade"))
+		}
+	}
+	return err
+}
+
+// sortedCurrencies returns the currencies of the map in a deterministic order.
+func sortedCurrencies(currencies map[string]uint64) []string {
+	names := make([]string, 0, len(currencies))
+	for name := range currencies {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
@@ -0,0 +1,191 @@
+package cardinal_test
+
+import (
+	"testing"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/testutils"
+	"pkg.world.dev/world-engine/cardinal/trade"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+// newTradeWorld returns a world with the trade and wallet plugins, in which alice owns a sword and bob has 50 gold.
+func newTradeWorld(t *testing.T, acceptWindow uint64, opts ...cardinal.WorldOption) (
+	*testutils.TestFixture, types.EntityID,
+) {
+	tf := testutils.NewTestFixture(t, nil, opts...)
+	assert.NilError(t, cardinal.RegisterComponent[Foo](tf.World))
+	tf.World.RegisterPlugin(cardinal.NewWalletPlugin())
+	tf.World.RegisterPlugin(cardinal.NewTradePlugin(acceptWindow))
+	tf.StartWorld()
+	tf.CreatePersona("alice", "alice-address")
+	tf.CreatePersona("bob", "bob-address")
+
+	wCtx := cardinal.NewWorldContext(tf.World)
+	sword, err := cardinal.CreateForPersona(wCtx, "alice", Foo{})
+	assert.NilError(t, err)
+	_, err = cardinal.CreditCurrency(wCtx, "bob", "gold", 50)
+	assert.NilError(t, err)
+	tf.DoTick()
+	return tf, sword
+}
+
+func proposeTrade(tf *testutils.TestFixture, proposer string, msg trade.ProposeTrade) types.EntityID {
+	rec := tf.SendTransaction("trade."+trade.ProposeTradeMessageName, msg, proposer)
+	assert.Len(tf, rec.Errs, 0)
+	result, ok := rec.Result.(trade.ProposeTradeResult)
+	assert.Assert(tf, ok)
+	return result.TradeID
+}
+
+func assertItemOwner(t *testing.T, wCtx engine.Context, item types.EntityID, owner string) {
+	got, ok, err := cardinal.GetEntityOwner(wCtx, item)
+	assert.NilError(t, err)
+	assert.Check(t, ok)
+	assert.Equal(t, owner, got)
+}
+
+func assertGold(t *testing.T, wCtx engine.Context, personaTag string, want uint64) {
+	balance, err := cardinal.GetCurrencyBalance(wCtx, personaTag, "gold")
+	assert.NilError(t, err)
+	assert.Equal(t, want, balance)
+}
+
+func TestTradeSwapsItemsAndCurrency(t *testing.T) {
+	tf, sword := newTradeWorld(t, 10)
+	wCtx := cardinal.NewWorldContext(tf.World)
+
+	tradeID := proposeTrade(tf, "alice", trade.ProposeTrade{
+		Counterparty: "bob",
+		Give:         trade.Offer{Items: []types.EntityID{sword}},
+		Want:         trade.Offer{Currency: map[string]uint64{"gold": 30}},
+	})
+	// The sword is held in escrow until bob accepts.
+	assertItemOwner(t, wCtx, sword, trade.EscrowOwner(tradeID))
+
+	// Only the counterparty can accept the trade.
+	rec := tf.SendTransaction("trade."+trade.AcceptTradeMessageName, trade.AcceptTrade{TradeID: tradeID}, "alice")
+	assert.ErrorIs(t, rec.Errs[0], trade.ErrNotParty)
+
+	rec = tf.SendTransaction("trade."+trade.AcceptTradeMessageName, trade.AcceptTrade{TradeID: tradeID}, "bob")
+	assert.Len(t, rec.Errs, 0)
+	result, ok := rec.Result.(trade.AcceptTradeResult)
+	assert.Assert(t, ok)
+	assert.Equal(t, "alice", result.Receipt.Proposer)
+	assert.DeepEqual(t, []types.EntityID{sword}, result.Receipt.CounterpartyReceived.Items)
+
+	assertItemOwner(t, wCtx, sword, "bob")
+	assertGold(t, wCtx, "alice", 30)
+	assertGold(t, wCtx, "bob", 20)
+
+	// A completed trade can't be accepted again, but both personas can look it up.
+	rec = tf.SendTransaction("trade."+trade.AcceptTradeMessageName, trade.AcceptTrade{TradeID: tradeID}, "bob")
+	assert.ErrorIs(t, rec.Errs[0], trade.ErrTradeNotFound)
+	listTrades, err := tf.World.GetQueryByName(trade.ListTradesQueryName)
+	assert.NilError(t, err)
+	for _, personaTag := range []string{"alice", "bob"} {
+		res, err := listTrades.HandleQuery(cardinal.NewReadOnlyWorldContext(tf.World),
+			trade.ListTradesRequest{PersonaTag: personaTag, Status: trade.StatusCompleted})
+		assert.NilError(t, err)
+		trades, ok := res.(*trade.ListTradesResponse)
+		assert.Assert(t, ok)
+		assert.Len(t, trades.Trades, 1)
+	}
+}
+
+func TestTradeHoldsTheOfferInEscrow(t *testing.T) {
+	tf, sword := newTradeWorld(t, 10)
+	wCtx := cardinal.NewWorldContext(tf.World)
+
+	// bob can't offer an item he doesn't own.
+	rec := tf.SendTransaction("trade."+trade.ProposeTradeMessageName, trade.ProposeTrade{
+		Counterparty: "alice",
+		Give:         trade.Offer{Items: []types.EntityID{sword}},
+	}, "bob")
+	assert.ErrorIs(t, rec.Errs[0], trade.ErrNotOwner)
+
+	// Once alice offers the sword to bob, she can't offer it again or give it away.
+	proposeTrade(tf, "alice", trade.ProposeTrade{
+		Counterparty: "bob",
+		Give:         trade.Offer{Items: []types.EntityID{sword}},
+		Want:         trade.Offer{Currency: map[string]uint64{"gold": 10}},
+	})
+	rec = tf.SendTransaction("trade."+trade.ProposeTradeMessageName, trade.ProposeTrade{
+		Counterparty: "bob",
+		Give:         trade.Offer{Items: []types.EntityID{sword}},
+	}, "alice")
+	assert.ErrorIs(t, rec.Errs[0], trade.ErrNotOwner)
+	assert.ErrorIs(t, cardinal.ReleaseEntity(wCtx, "alice", sword), cardinal.ErrEntityNotOwned)
+
+	// The gold bob offers is taken from his wallet until the trade is done.
+	tradeID := proposeTrade(tf, "bob", trade.ProposeTrade{
+		Counterparty: "alice",
+		Give:         trade.Offer{Currency: map[string]uint64{"gold": 40}},
+	})
+	assertGold(t, wCtx, "bob", 10)
+	rec = tf.SendTransaction("trade."+trade.ProposeTradeMessageName, trade.ProposeTrade{
+		Counterparty: "alice",
+		Give:         trade.Offer{Currency: map[string]uint64{"gold": 40}},
+	}, "bob")
+	assert.ErrorIs(t, rec.Errs[0], trade.ErrInsufficientBalance)
+
+	rec = tf.SendTransaction("trade."+trade.AcceptTradeMessageName, trade.AcceptTrade{TradeID: tradeID}, "alice")
+	assert.Len(t, rec.Errs, 0)
+	assertGold(t, wCtx, "alice", 40)
+	assertGold(t, wCtx, "bob", 10)
+}
+
+func TestTradeIsNotAppliedWhenTheCounterpartyCantReceiveIt(t *testing.T) {
+	// bob's wallet is the only entity his quota allows, so he can't receive the sword.
+	tf, sword := newTradeWorld(t, 10, cardinal.WithEntityQuota(cardinal.EntityQuota{MaxEntitiesPerPersona: 1}))
+	wCtx := cardinal.NewWorldContext(tf.World)
+
+	tradeID := proposeTrade(tf, "alice", trade.ProposeTrade{
+		Counterparty: "bob",
+		Give:         trade.Offer{Items: []types.EntityID{sword}},
+		Want:         trade.Offer{Currency: map[string]uint64{"gold": 30}},
+	})
+	rec := tf.SendTransaction("trade."+trade.AcceptTradeMessageName, trade.AcceptTrade{TradeID: tradeID}, "bob")
+	assert.ErrorIs(t, rec.Errs[0], cardinal.ErrPersonaEntityQuotaExceeded)
+	assertItemOwner(t, wCtx, sword, trade.EscrowOwner(tradeID))
+	assertGold(t, wCtx, "alice", 0)
+	assertGold(t, wCtx, "bob", 50)
+}
+
+func TestTradeExpires(t *testing.T) {
+	tf, sword := newTradeWorld(t, 2)
+	wCtx := cardinal.NewWorldContext(tf.World)
+
+	tradeID := proposeTrade(tf, "alice", trade.ProposeTrade{
+		Counterparty: "bob",
+		Give:         trade.Offer{Items: []types.EntityID{sword}},
+	})
+	tf.DoTick()
+	tf.DoTick()
+
+	rec := tf.SendTransaction("trade."+trade.AcceptTradeMessageName, trade.AcceptTrade{TradeID: tradeID}, "bob")
+	assert.ErrorIs(t, rec.Errs[0], trade.ErrTradeNotFound)
+	assertItemOwner(t, wCtx, sword, "alice")
+}
+
+func TestTradeCanBeDeclined(t *testing.T) {
+	tf, sword := newTradeWorld(t, 10)
+	wCtx := cardinal.NewWorldContext(tf.World)
+
+	tradeID := proposeTrade(tf, "bob", trade.ProposeTrade{
+		Counterparty: "alice",
+		Give:         trade.Offer{Currency: map[string]uint64{"gold": 20}},
+		Want:         trade.Offer{Items: []types.EntityID{sword}},
+	})
+	assertGold(t, wCtx, "bob", 30)
+
+	rec := tf.SendTransaction("trade."+trade.CancelTradeMessageName, trade.CancelTrade{TradeID: tradeID}, "alice")
+	assert.Len(t, rec.Errs, 0)
+	assertGold(t, wCtx, "bob", 50)
+	assertItemOwner(t, wCtx, sword, "alice")
+
+	rec = tf.SendTransaction("trade."+trade.AcceptTradeMessageName, trade.AcceptTrade{TradeID: tradeID}, "alice")
+	assert.ErrorIs(t, rec.Errs[0], trade.ErrTradeNotFound)
+}
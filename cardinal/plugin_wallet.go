@@ -0,0 +1,242 @@
+package cardinal
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal/iterators"
+	"pkg.world.dev/world-engine/cardinal/message"
+	querylib "pkg.world.dev/world-engine/cardinal/query"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+	"pkg.world.dev/world-engine/cardinal/wallet"
+)
+
+const (
+	walletGroup = "wallet"
+
+	// walletNamespace is the raw storage namespace of the wallet plugin. It holds the journal IDs of the credits that
+	// were applied, and the number of wallet events so far, which makes up the journal IDs of the events.
+	walletNamespace = "cardinal-wallet"
+	journalCountKey = "journal-count"
+)
+
+var _ Plugin = (*walletPlugin)(nil)
+
+type walletPlugin struct{}
+
+// NewWalletPlugin returns the wallet plugin, which keeps the soft currency balances of personas in wallet.Wallet
+// components. Currency is added with the admin-only credit message or CreditCurrency, spent with SpendCurrency and read
+// with the balance query or GetCurrencyBalance. The trade plugin keeps the currency it trades in the same wallets. The
+// wallet bridge of Nakama sends credits, so its signer must be one of the admin signers of the world (see
+// WithAdminSigners). Register it with World.RegisterPlugin before starting the game.
+func NewWalletPlugin() Plugin {
+	return &walletPlugin{}
+}
+
+func (p *walletPlugin) Register(world *World) error {
+	return errors.Join(
+		RegisterComponent[wallet.Wallet](world),
+		RegisterMessage[wallet.Credit, wallet.CreditResult](
+			world,
+			wallet.CreditMessageName,
+			message.WithCustomMessageGroup[wallet.Credit, wallet.CreditResult](walletGroup),
+			message.WithAdminOnly[wallet.Credit, wallet.CreditResult]()),
+		RegisterQuery[wallet.BalanceRequest, wallet.BalanceResponse](
+			world,
+			wallet.BalanceQueryName,
+			walletBalanceQuery,
+			querylib.WithCustomQueryGroup[wallet.BalanceRequest, wallet.BalanceResponse](walletGroup)),
+		RegisterSystems(world, creditWalletSystem),
+	)
+}
+
+// CreditCurrency adds amount to the balance of the given currency in the wallet of the persona, creating the wallet if
+// the persona doesn't have one yet, and emits a wallet.Event, so that the wallet bridge of Nakama adds the same amount
+// to the persona's Nakama wallet. It returns the new balance. The wallet is an entity owned by the persona, so creating
+// it counts towards the persona's entity quota.
+func CreditCurrency(wCtx engine.Context, personaTag, currency string, amount uint64) (uint64, error) {
+	if amount == 0 {
+		return GetCurrencyBalance(wCtx, personaTag, currency)
+	}
+	balance, err := creditWallet(wCtx, personaTag, currency, amount)
+	if err != nil {
+		return 0, err
+	}
+	return balance, emitWalletEvent(wCtx, wallet.EarnEventType, personaTag, currency, amount)
+}
+
+// SpendCurrency takes amount from the balance of the given currency in the wallet of the persona, and emits a
+// wallet.Event, so that the wallet bridge of Nakama deducts the same amount from the persona's Nakama wallet.
+// wallet.ErrInsufficientBalance is returned if the balance is less than amount.
+func SpendCurrency(wCtx engine.Context, personaTag, currency string, amount uint64) error {
+	if amount == 0 {
+		return nil
+	}
+	if err := debitWallet(wCtx, personaTag, currency, amount); err != nil {
+		return err
+	}
+	return emitWalletEvent(wCtx, wallet.SpendEventType, personaTag, currency, amount)
+}
+
+// GetCurrencyBalance returns the balance of the given currency in the wallet of the persona.
+func GetCurrencyBalance(wCtx engine.Context, personaTag, currency string) (uint64, error) {
+	_, w, err := getPersonaWallet(wCtx, personaTag)
+	if err != nil {
+		return 0, err
+	}
+	return w.Balances[currency], nil
+}
+
+// -----------------------------------------------------------------------------
+// Wallet System
+// -----------------------------------------------------------------------------
+
+// creditWalletSystem applies every credit message whose journal ID has not been applied before.
+func creditWalletSystem(wCtx engine.Context) error {
+	personaIndex, err := buildGlobalPersonaIndex(wCtx)
+	if err != nil {
+		return err
+	}
+	store, err := NewRawStorage(wCtx, walletNamespace)
+	if err != nil {
+		return err
+	}
+	return EachMessage[wallet.Credit, wallet.CreditResult](
+		wCtx,
+		func(txData message.TxData[wallet.Credit]) (result wallet.CreditResult, err error) {
+			credit := txData.Msg
+			if credit.JournalID == "" || credit.Currency == "" || credit.Amount == 0 {
+				return result, eris.Wrap(wallet.ErrInvalidCredit, "journal ID, currency and amount must be set")
+			}
+			_, applied, err := store.Get(creditKey(credit.JournalID))
+			if err != nil {
+				return result, err
+			}
+			if applied {
+				result.Duplicate = true
+				result.Balance, err = GetCurrencyBalance(wCtx, credit.PersonaTag, credit.Currency)
+				return result, err
+			}
+			if _, ok := personaIndex[strings.ToLower(credit.PersonaTag)]; !ok {
+				return result, eris.Errorf("persona %s does not exist", credit.PersonaTag)
+			}
+			// Nakama has already credited the persona's Nakama wallet, so no wallet event is emitted.
+			if result.Balance, err = creditWallet(wCtx, credit.PersonaTag, credit.Currency, credit.Amount); err != nil {
+				return result, err
+			}
+			err = store.Set(creditKey(credit.JournalID), []byte(strconv.FormatUint(wCtx.CurrentTick(), 10)))
+			return result, err
+		},
+	)
+}
+
+// -----------------------------------------------------------------------------
+// Wallet Query
+// -----------------------------------------------------------------------------
+
+func walletBalanceQuery(wCtx engine.Context, req *wallet.BalanceRequest) (*wallet.BalanceResponse, error) {
+	balance, err := GetCurrencyBalance(wCtx, req.PersonaTag, req.Currency)
+	if err != nil {
+		return nil, err
+	}
+	return &wallet.BalanceResponse{Balance: balance}, nil
+}
+
+// -----------------------------------------------------------------------------
+// Wallet Helpers
+// -----------------------------------------------------------------------------
+
+// getPersonaWallet returns the wallet among the entities owned by the persona. If the persona doesn't have a wallet, an
+// empty wallet and BadID are returned.
+func getPersonaWallet(wCtx engine.Context, personaTag string) (types.EntityID, *wallet.Wallet, error) {
+	owned, err := GetOwnedEntities(wCtx, personaTag)
+	if err != nil {
+		return iterators.BadID, nil, err
+	}
+	for _, id := range owned {
+		w, err := GetComponent[wallet.Wallet](wCtx, id)
+		if eris.Is(err, ErrComponentNotOnEntity) {
+			continue
+		} else if err != nil {
+			return iterators.BadID, nil, err
+		}
+		if w.Balances == nil {
+			w.Balances = map[string]uint64{}
+		}
+		return id, w, nil
+	}
+	return iterators.BadID, &wallet.Wallet{Balances: map[string]uint64{}}, nil
+}
+
+// creditWallet adds amount to the balance of the given currency in the wallet of the persona, creating the wallet if
+// the persona doesn't have one yet, and returns the new balance.
+func creditWallet(wCtx engine.Context, personaTag, currency string, amount uint64) (uint64, error) {
+	id, w, err := getPersonaWallet(wCtx, personaTag)
+	if err != nil {
+		return 0, err
+	}
+	balance := w.Balances[currency]
+	if balance+amount < balance {
+		return 0, eris.Wrapf(wallet.ErrBalanceOverflow, "the %s balance of %s", currency, personaTag)
+	}
+	w.Balances[currency] = balance + amount
+	if id == iterators.BadID {
+		_, err = CreateForPersona(wCtx, personaTag, *w)
+	} else {
+		err = SetComponent[wallet.Wallet](wCtx, id, w)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return balance + amount, nil
+}
+
+// debitWallet takes amount from the balance of the given currency in the wallet of the persona.
+// wallet.ErrInsufficientBalance is returned if the balance is less than amount.
+func debitWallet(wCtx engine.Context, personaTag, currency string, amount uint64) error {
+	id, w, err := getPersonaWallet(wCtx, personaTag)
+	if err != nil {
+		return err
+	}
+	if balance := w.Balances[currency]; id == iterators.BadID || balance < amount {
+		return eris.Wrapf(wallet.ErrInsufficientBalance, "%s has %d %s, needs %d", personaTag, balance, currency, amount)
+	}
+	w.Balances[currency] -= amount
+	return SetComponent[wallet.Wallet](wCtx, id, w)
+}
+
+// emitWalletEvent emits a wallet.Event with a new journal ID. Events are numbered in the order they are emitted, which
+// is the same on every replay of the ticks.
+func emitWalletEvent(wCtx engine.Context, eventType, personaTag, currency string, amount uint64) error {
+	store, err := NewRawStorage(wCtx, walletNamespace)
+	if err != nil {
+		return err
+	}
+	var count uint64
+	if value, ok, err := store.Get(journalCountKey); err != nil {
+		return err
+	} else if ok {
+		if count, err = strconv.ParseUint(string(value), 10, 64); err != nil {
+			return eris.Wrap(err, "invalid number of wallet events")
+		}
+	}
+	count++
+	if err = store.Set(journalCountKey, []byte(strconv.FormatUint(count, 10))); err != nil {
+		return err
+	}
+	return wCtx.EmitEvent(map[string]any{
+		"event":      eventType,
+		"journalId":  wCtx.Namespace() + ":" + eventType + ":" + strconv.FormatUint(count, 10),
+		"personaTag": personaTag,
+		"currency":   currency,
+		"amount":     amount,
+	})
+}
+
+func creditKey(journalID string) string {
+	return "credit-" + journalID
+}
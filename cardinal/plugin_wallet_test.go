@@ -0,0 +1,56 @@
+package cardinal_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/testutils"
+	"pkg.world.dev/world-engine/cardinal/wallet"
+	"pkg.world.dev/world-engine/sign"
+)
+
+func TestWalletCreditsAreAppliedOnce(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil)
+	tf.World.RegisterPlugin(cardinal.NewWalletPlugin())
+	tf.StartWorld()
+	tf.CreatePersona("alice", "alice-address")
+	credit := "wallet." + wallet.CreditMessageName
+
+	purchase := wallet.Credit{JournalID: "iap:1", PersonaTag: "alice", Currency: "gold", Amount: 50}
+	rec := tf.SendTransaction(credit, purchase, sign.SystemPersonaTag)
+	assert.Len(t, rec.Errs, 0)
+	assert.Equal(t, wallet.CreditResult{Balance: 50}, rec.Result)
+
+	// Nakama sends a credit again when it doesn't know whether it was applied.
+	rec = tf.SendTransaction(credit, purchase, sign.SystemPersonaTag)
+	assert.Len(t, rec.Errs, 0)
+	assert.Equal(t, wallet.CreditResult{Balance: 50, Duplicate: true}, rec.Result)
+
+	rec = tf.SendTransaction(credit,
+		wallet.Credit{JournalID: "iap:2", PersonaTag: "nobody", Currency: "gold", Amount: 5}, sign.SystemPersonaTag)
+	assert.Len(t, rec.Errs, 1)
+	rec = tf.SendTransaction(credit,
+		wallet.Credit{JournalID: "iap:3", PersonaTag: "alice", Currency: "gold"}, sign.SystemPersonaTag)
+	assert.ErrorIs(t, rec.Errs[0], wallet.ErrInvalidCredit)
+
+	wCtx := cardinal.NewWorldContext(tf.World)
+	assert.ErrorIs(t, cardinal.SpendCurrency(wCtx, "alice", "gold", 60), wallet.ErrInsufficientBalance)
+	assert.ErrorIs(t, cardinal.SpendCurrency(wCtx, "bob", "gold", 1), wallet.ErrInsufficientBalance)
+	assert.NilError(t, cardinal.SpendCurrency(wCtx, "alice", "gold", 20))
+	tf.DoTick()
+
+	// The wallet is owned by the persona.
+	owned, err := cardinal.GetOwnedEntities(wCtx, "alice")
+	assert.NilError(t, err)
+	assert.Len(t, owned, 1)
+
+	res := tf.Post("query/wallet/"+wallet.BalanceQueryName, wallet.BalanceRequest{PersonaTag: "alice", Currency: "gold"})
+	defer res.Body.Close()
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	var balance wallet.BalanceResponse
+	assert.NilError(t, json.NewDecoder(res.Body).Decode(&balance))
+	assert.Equal(t, uint64(30), balance.Balance)
+}
@@ -0,0 +1,89 @@
+package cardinal
+
+import (
+	"slices"
+
+	"github.com/rotisserie/eris"
+)
+
+// Profile is a named bundle of defaults for the environment that a world runs in. It is selected with WithProfile or
+// the CARDINAL_PROFILE environment variable, so that operators pick one profile instead of tuning every knob.
+type Profile string
+
+const (
+	// ProfileDev enables strict determinism checks, CQL filters, and verbose, human-readable logging.
+	ProfileDev Profile = "dev"
+	// ProfileStaging enables telemetry and the entity limits of ProfileProd, so that staging behaves like production.
+	ProfileStaging Profile = "staging"
+	// ProfileProd enables telemetry, entity limits, and automatic checkpoints every DefaultAutoCheckpointTicks ticks.
+	ProfileProd Profile = "prod"
+
+	// DefaultAutoCheckpointTicks is the number of ticks between the automatic checkpoints of ProfileProd.
+	DefaultAutoCheckpointTicks = 3600
+	// DefaultMaxEntitiesPerSystemPerTick is the number of entities that a system can create in a tick in the staging
+	// and prod profiles.
+	DefaultMaxEntitiesPerSystemPerTick = 10_000
+)
+
+// profileDefaults are the defaults that a profile bundles.
+type profileDefaults struct {
+	// config sets the config values of the profile. They are applied on top of the default config, so the config file,
+	// environment variables and flags still override them.
+	config func(cfg *Config)
+	// options are applied before the options passed to NewWorld, which override them.
+	options []WorldOption
+}
+
+var profiles = map[Profile]profileDefaults{
+	ProfileDev: {
+		config: func(cfg *Config) {
+			cfg.CardinalStrictMode = true
+			cfg.CardinalCQLFilters = true
+			cfg.CardinalLogLevel = "debug"
+			cfg.CardinalLogPretty = true
+		},
+	},
+	ProfileStaging: {
+		config: func(cfg *Config) {
+			cfg.TelemetryEnabled = true
+		},
+		options: []WorldOption{
+			WithEntityQuota(EntityQuota{MaxEntitiesPerSystemPerTick: DefaultMaxEntitiesPerSystemPerTick}),
+		},
+	},
+	ProfileProd: {
+		config: func(cfg *Config) {
+			cfg.TelemetryEnabled = true
+		},
+		options: []WorldOption{
+			WithEntityQuota(EntityQuota{MaxEntitiesPerSystemPerTick: DefaultMaxEntitiesPerSystemPerTick}),
+			WithAutoCheckpoint(DefaultAutoCheckpointTicks),
+		},
+	},
+}
+
+// Validate returns an error if the profile is not one of the known profiles. The empty profile is valid and bundles
+// no defaults.
+func (p Profile) Validate() error {
+	if _, ok := profiles[p]; !ok && p != "" {
+		known := make([]string, 0, len(profiles))
+		for name := range profiles {
+			known = append(known, string(name))
+		}
+		slices.Sort(known)
+		return eris.Errorf("unknown profile %q, must be one of %v", p, known)
+	}
+	return nil
+}
+
+// apply sets the config values of the profile.
+func (p Profile) apply(cfg *Config) {
+	if defaults, ok := profiles[p]; ok && defaults.config != nil {
+		defaults.config(cfg)
+	}
+}
+
+// options returns the world options of the profile.
+func (p Profile) options() []WorldOption {
+	return profiles[p].options
+}
@@ -0,0 +1,32 @@
+package cardinal
+
+import (
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+
+	"pkg.world.dev/world-engine/assert"
+)
+
+func TestWithProfile_AppliesTheOptionsOfTheProfile(t *testing.T) {
+	miniRedis := miniredis.RunT(t)
+	t.Setenv("REDIS_ADDRESS", miniRedis.Addr())
+
+	world, err := NewWorld(WithProfile(ProfileProd), WithPort(getOpenPort(t)))
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(DefaultAutoCheckpointTicks), world.autoCheckpointTicks)
+	assert.Equal(t, DefaultMaxEntitiesPerSystemPerTick, world.entityQuota.quota.MaxEntitiesPerSystemPerTick)
+
+	// The options passed to NewWorld override the ones of the profile.
+	world, err = NewWorld(WithProfile(ProfileProd), WithAutoCheckpoint(0), WithPort(getOpenPort(t)))
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(0), world.autoCheckpointTicks)
+
+	// A config set with WithConfig is used as is, with the options of its profile.
+	cfg := DefaultConfig()
+	cfg.RedisAddress = miniRedis.Addr()
+	cfg.CardinalProfile = string(ProfileDev)
+	world, err = NewWorld(WithConfig(cfg), WithProfile(ProfileProd), WithPort(getOpenPort(t)))
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(0), world.autoCheckpointTicks)
+}
@@ -9,6 +9,8 @@ import (
 
 	"pkg.world.dev/world-engine/cardinal/abi"
 	"pkg.world.dev/world-engine/cardinal/message"
+	"pkg.world.dev/world-engine/cardinal/sdk"
+	"pkg.world.dev/world-engine/cardinal/server/utils"
 	"pkg.world.dev/world-engine/cardinal/types"
 	"pkg.world.dev/world-engine/cardinal/types/engine"
 )
@@ -21,15 +23,16 @@ type queryType[Request any, Reply any] struct {
 	name       string
 	group      string
 	handler    func(wCtx engine.Context, req *Request) (*Reply, error)
+	evmSupport bool
 	requestABI *ethereumAbi.Type
 	replyABI   *ethereumAbi.Type
 }
 
+// WithQueryEVMSupport makes the query callable from the EVM. The ABI types of the request and reply are derived from
+// the Request and Reply structs when the query is created.
 func WithQueryEVMSupport[Request, Reply any]() Option[Request, Reply] {
 	return func(qt *queryType[Request, Reply]) {
-		if err := qt.generateABIBindings(); err != nil {
-			panic(err)
-		}
+		qt.evmSupport = true
 	}
 }
 
@@ -60,6 +63,11 @@ func NewQueryType[Request any, Reply any](
 	for _, opt := range opts {
 		opt(r)
 	}
+	if r.evmSupport {
+		if err = r.generateABIBindings(); err != nil {
+			return nil, eris.Wrapf(err, "invalid query %s", name)
+		}
+	}
 
 	return r, nil
 }
@@ -202,11 +210,57 @@ func (r *queryType[Request, Reply]) EncodeAsABI(input any) ([]byte, error) {
 	return bz, nil
 }
 
+// EVMFixtures returns the ABI fixtures of sample values of the query's request and reply types.
+func (r *queryType[Request, Reply]) EVMFixtures() ([]abi.Fixture, error) {
+	if !r.IsEVMCompatible() {
+		return nil, eris.Wrap(message.ErrEVMTypeNotSet, "")
+	}
+	name := r.group + "." + r.name
+	req, err := abi.NewSampleFixture[Request](name + " request")
+	if err != nil {
+		return nil, err
+	}
+	reply, err := abi.NewSampleFixture[Reply](name + " reply")
+	if err != nil {
+		return nil, err
+	}
+	return []abi.Fixture{req, reply}, nil
+}
+
+// EVMBinding returns the binding of the query, from which the EVM bindings of the world are generated.
+func (r *queryType[Request, Reply]) EVMBinding() (abi.Binding, error) {
+	if !r.IsEVMCompatible() {
+		return abi.Binding{}, eris.Wrap(message.ErrEVMTypeNotSet, "")
+	}
+	return abi.Binding{
+		Name: r.group + "." + r.name,
+		In:   reflect.TypeOf(new(Request)).Elem(),
+		Out:  reflect.TypeOf(new(Reply)).Elem(),
+	}, nil
+}
+
+// ClientEndpoint returns the endpoint of the query, from which the client SDKs of the world are generated.
+func (r *queryType[Request, Reply]) ClientEndpoint() sdk.Endpoint {
+	return sdk.Endpoint{
+		Kind:  sdk.KindQuery,
+		Group: r.group,
+		Name:  r.name,
+		URL:   utils.GetQueryURL(r.group, r.name),
+		In:    reflect.TypeOf(new(Request)).Elem(),
+		Out:   reflect.TypeOf(new(Reply)).Elem(),
+	}
+}
+
 // GetRequestFieldInformation returns the field information for the request struct.
 func (r *queryType[Request, Reply]) GetRequestFieldInformation() map[string]any {
 	return types.GetFieldInformation(reflect.TypeOf(new(Request)).Elem())
 }
 
+// GetReplyFieldInformation returns the field information for the reply struct.
+func (r *queryType[Request, Reply]) GetReplyFieldInformation() map[string]any {
+	return types.GetFieldInformation(reflect.TypeOf(new(Reply)).Elem())
+}
+
 func validateQuery[Request any, Reply any](
 	name string,
 	handler func(wCtx engine.Context, req *Request) (*Reply, error),
@@ -228,7 +282,7 @@ func validateQuery[Request any, Reply any](
 		reqValid = true
 	}
 	repType := reflect.TypeOf(rep)
-	repKind := reqType.Kind()
+	repKind := repType.Kind()
 	repValid := false
 	if (repKind == reflect.Pointer && repType.Elem().Kind() == reflect.Struct) ||
 		repKind == reflect.Struct {
@@ -6,6 +6,7 @@ import (
 
 	"pkg.world.dev/world-engine/assert"
 	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/abi"
 	"pkg.world.dev/world-engine/cardinal/query"
 	"pkg.world.dev/world-engine/cardinal/search/filter"
 	"pkg.world.dev/world-engine/cardinal/testutils"
@@ -219,3 +220,32 @@ func TestErrOnNoNameOrHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestQueryFieldInformation(t *testing.T) {
+	q, err := query.NewQueryType[QueryHealthRequest, QueryHealthResponse]("query_health", handleQueryHealth)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, map[string]any{"Min": "int"}, q.GetRequestFieldInformation())
+	assert.DeepEqual(t, map[string]any{"IDs": "[]types.EntityID"}, q.GetReplyFieldInformation())
+}
+
+func TestQueryEVMSupportRejectsUnsupportedTypes(t *testing.T) {
+	type FooRequest struct {
+		Score float64
+	}
+	type FooReply struct{}
+	_, err := query.NewQueryType[FooRequest, FooReply](
+		"foo",
+		func(engine.Context, *FooRequest) (*FooReply, error) { return &FooReply{}, nil },
+		query.WithQueryEVMSupport[FooRequest, FooReply](),
+	)
+	assert.ErrorIs(t, err, abi.ErrUnsupportedType)
+}
+
+func TestQueryReplyMustBeStruct(t *testing.T) {
+	type FooRequest struct{}
+	_, err := query.NewQueryType[FooRequest, []int](
+		"foo",
+		func(engine.Context, *FooRequest) (*[]int, error) { return nil, nil },
+	)
+	assert.ErrorContains(t, err, "the Request and Reply generics must be both structs")
+}
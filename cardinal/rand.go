@@ -0,0 +1,30 @@
+package cardinal
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"hash/fnv"
+	"math/rand/v2"
+)
+
+// defaultRandSeed derives the world seed from the namespace so that every shard with the same namespace (e.g. a
+// shard that is restored from a snapshot or replayed from the base shard) generates the same random numbers.
+func defaultRandSeed(namespace string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(namespace))
+	return h.Sum64()
+}
+
+// newTickRand returns a PRNG that is seeded from the world seed, the tick number and the name of the running
+// system. Two systems in the same tick (or the same system in two different ticks) get independent streams, but
+// replaying a tick always produces the same sequence.
+func newTickRand(worldSeed uint64, tick uint64, systemName string) *rand.Rand {
+	var buf [16]byte
+	binary.BigEndian.PutUint64(buf[:8], worldSeed)
+	binary.BigEndian.PutUint64(buf[8:], tick)
+	h := sha256.New()
+	_, _ = h.Write(buf[:])
+	_, _ = h.Write([]byte(systemName))
+	sum := h.Sum(nil)
+	return rand.New(rand.NewPCG(binary.BigEndian.Uint64(sum[:8]), binary.BigEndian.Uint64(sum[8:16])))
+}
@@ -0,0 +1,63 @@
+package cardinal_test
+
+import (
+	"testing"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/testutils"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+// recordRandomNumbers runs a world for the given number of ticks and returns the first random number that each of
+// the two registered systems draws in every tick.
+func recordRandomNumbers(t *testing.T, ticks int, opts ...cardinal.WorldOption) (first, second []uint64) {
+	tf := testutils.NewTestFixture(t, nil, opts...)
+	err := cardinal.RegisterSystems(tf.World,
+		func(wCtx engine.Context) error {
+			first = append(first, wCtx.Rand().Uint64())
+			return nil
+		},
+		func(wCtx engine.Context) error {
+			second = append(second, wCtx.Rand().Uint64())
+			return nil
+		},
+	)
+	assert.NilError(t, err)
+	tf.StartWorld()
+	for i := 0; i < ticks; i++ {
+		tf.DoTick()
+	}
+	return first, second
+}
+
+func TestRandIsDeterministicAcrossWorlds(t *testing.T) {
+	firstA, secondA := recordRandomNumbers(t, 5)
+	firstB, secondB := recordRandomNumbers(t, 5)
+	assert.DeepEqual(t, firstA, firstB)
+	assert.DeepEqual(t, secondA, secondB)
+
+	// Each tick and each system gets its own stream.
+	assert.Assert(t, firstA[0] != firstA[1])
+	assert.Assert(t, firstA[0] != secondA[0])
+}
+
+func TestRandDependsOnWorldSeed(t *testing.T) {
+	firstA, _ := recordRandomNumbers(t, 1, cardinal.WithRandSeed(1))
+	firstB, _ := recordRandomNumbers(t, 1, cardinal.WithRandSeed(2))
+	assert.Assert(t, firstA[0] != firstB[0])
+}
+
+func TestRandStreamIsStableWithinASystem(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil)
+	var draws []uint64
+	err := cardinal.RegisterSystems(tf.World, func(wCtx engine.Context) error {
+		draws = append(draws, wCtx.Rand().Uint64(), wCtx.Rand().Uint64())
+		return nil
+	})
+	assert.NilError(t, err)
+	tf.StartWorld()
+	tf.DoTick()
+	// Calling Rand twice in the same system must continue the stream rather than reseed it.
+	assert.Assert(t, draws[0] != draws[1])
+}
@@ -0,0 +1,85 @@
+package cardinal
+
+import (
+	"errors"
+
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal/gamestate"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+var (
+	ErrRawValueNotFound        = gamestate.ErrRawValueNotFound
+	ErrRawStorageQuotaExceeded = gamestate.ErrRawStorageQuotaExceeded
+)
+
+// RawStorage is a low-level key/value store for game data that does not fit into the component model (e.g. spatial
+// grids or pathfinding caches). Keys are scoped to the namespace given to NewRawStorage, and writes are buffered and
+// committed atomically with the rest of the tick's state changes, so raw data stays consistent with component data
+// across recoveries and replays. Games should use this instead of talking to the Redis client directly.
+type RawStorage struct {
+	wCtx   engine.Context
+	prefix string
+}
+
+// NewRawStorage returns a RawStorage that reads and writes keys under the given namespace. The namespace must be
+// alphanumeric (hyphens are allowed).
+//
+// Usage:
+//
+//	store, err := cardinal.NewRawStorage(wCtx, "pathfinding")
+//	err = store.Set("grid-0", gridBytes)
+func NewRawStorage(wCtx engine.Context, namespace string) (*RawStorage, error) {
+	if err := Namespace(namespace).Validate(); err != nil {
+		return nil, eris.Wrapf(err, "invalid raw storage namespace %q", namespace)
+	}
+	return &RawStorage{
+		wCtx:   wCtx,
+		prefix: namespace + ":",
+	}, nil
+}
+
+// Get returns the value stored at the given key. If no value has been set, ok will be false.
+func (r *RawStorage) Get(key string) (value []byte, ok bool, err error) {
+	defer func() { panicOnFatalError(r.wCtx, err) }()
+
+	value, err = r.wCtx.StoreReader().GetRawValue(r.prefix + key)
+	if errors.Is(err, ErrRawValueNotFound) {
+		return nil, false, nil
+	} else if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+// Set sets the value at the given key. ErrRawStorageQuotaExceeded is returned if the key or value is too large, or if
+// too many raw writes have been made in the current tick.
+func (r *RawStorage) Set(key string, value []byte) (err error) {
+	defer func() { panicOnFatalError(r.wCtx, err) }()
+
+	if r.wCtx.IsReadOnly() {
+		return ErrEntityMutationOnReadOnly
+	}
+	return r.wCtx.StoreManager().SetRawValue(r.prefix+key, value)
+}
+
+// Delete removes the value at the given key. Deleting a key that does not exist is not an error.
+func (r *RawStorage) Delete(key string) (err error) {
+	defer func() { panicOnFatalError(r.wCtx, err) }()
+
+	if r.wCtx.IsReadOnly() {
+		return ErrEntityMutationOnReadOnly
+	}
+	return r.wCtx.StoreManager().DeleteRawValue(r.prefix + key)
+}
+
+// discard drops the change made to the given key in the current tick, without counting towards the raw storage quota.
+func (r *RawStorage) discard(key string) (err error) {
+	defer func() { panicOnFatalError(r.wCtx, err) }()
+
+	if r.wCtx.IsReadOnly() {
+		return ErrEntityMutationOnReadOnly
+	}
+	return r.wCtx.StoreManager().DiscardRawValue(r.prefix + key)
+}
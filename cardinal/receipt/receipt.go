@@ -15,6 +15,16 @@ import (
 var (
 	ErrTickHasNotBeenProcessed = errors.New("tick is still in progress")
 	ErrOldTickHasBeenDiscarded = errors.New("the requested tick has been discarded due to age")
+	// ErrTransactionExpired is the error of a transaction that was dropped because it was not executed before the
+	// tick at which it expires.
+	ErrTransactionExpired = errors.New("transaction expired before it was executed")
+)
+
+// Statuses of a receipt, see Receipt.Status.
+const (
+	StatusSuccess = "success"
+	StatusFailed  = "failed"
+	StatusExpired = "expired"
 )
 
 // History keeps track of transaction "receipts" (the result of a transaction and any associated errors) for some number
@@ -26,13 +36,29 @@ type History struct {
 	history []map[types.TxHash]Receipt
 }
 
-// Receipt contains a transaction hash, an arbitrary result, and a list of errors.
+// Receipt contains a transaction hash, the tick at which the transaction was applied, an arbitrary result, and a list
+// of errors.
 type Receipt struct {
 	TxHash types.TxHash
+	Tick   uint64
 	Result any
 	Errs   []error
 }
 
+// Status returns StatusExpired if the transaction expired before it was executed, StatusFailed if it has errors, and
+// StatusSuccess otherwise.
+func (r Receipt) Status() string {
+	for _, err := range r.Errs {
+		if errors.Is(err, ErrTransactionExpired) {
+			return StatusExpired
+		}
+	}
+	if len(r.Errs) > 0 {
+		return StatusFailed
+	}
+	return StatusSuccess
+}
+
 func (r Receipt) MarshalJSON() ([]byte, error) {
 	errStrings := make([]string, len(r.Errs))
 	for i, err := range r.Errs {
@@ -41,10 +67,14 @@ func (r Receipt) MarshalJSON() ([]byte, error) {
 
 	return codec.Encode(struct {
 		TxHash types.TxHash `json:"txHash"`
+		Tick   uint64       `json:"tick"`
+		Status string       `json:"status"`
 		Result any          `json:"result"`
 		Errs   []string     `json:"errors"`
 	}{
 		TxHash: r.TxHash,
+		Tick:   r.Tick,
+		Status: r.Status(),
 		Result: r.Result,
 		Errs:   errStrings,
 	})
@@ -83,12 +113,19 @@ func (h *History) SetTick(tick uint64) {
 	h.currTick.Store(tick)
 }
 
+// DiscardCurrentTick drops the errors and results that have been recorded so far in the current tick.
+func (h *History) DiscardCurrentTick() {
+	h.history[h.currTick.Load()%h.ticksToStore] = map[types.TxHash]Receipt{}
+}
+
 // AddError associates the given error with the given transaction hash. Calling this multiple times will append
 // the error any previously added errors.
 func (h *History) AddError(hash types.TxHash, err error) {
-	tick := int(h.currTick.Load() % h.ticksToStore)
+	currTick := h.currTick.Load()
+	tick := int(currTick % h.ticksToStore)
 	rec := h.history[tick][hash]
 	rec.TxHash = hash
+	rec.Tick = currTick
 	rec.Errs = append(rec.Errs, err)
 	h.history[tick][hash] = rec
 }
@@ -96,9 +133,11 @@ func (h *History) AddError(hash types.TxHash, err error) {
 // SetResult sets the given transaction hash to the given result. Calling this multiple times will replace any previous
 // results.
 func (h *History) SetResult(hash types.TxHash, result any) {
-	tick := int(h.currTick.Load() % h.ticksToStore)
+	currTick := h.currTick.Load()
+	tick := int(currTick % h.ticksToStore)
 	rec := h.history[tick][hash]
 	rec.TxHash = hash
+	rec.Tick = currTick
 	rec.Result = result
 	h.history[tick][hash] = rec
 }
@@ -111,6 +150,16 @@ func (h *History) GetReceipt(hash types.TxHash) (Receipt, bool) {
 	return rec, ok
 }
 
+// GetReceiptsForCurrentTick gets all receipts that have been recorded so far in the current tick.
+func (h *History) GetReceiptsForCurrentTick() []Receipt {
+	mod := h.currTick.Load() % h.ticksToStore
+	recs := make([]Receipt, 0, len(h.history[mod]))
+	for _, rec := range h.history[mod] {
+		recs = append(recs, rec)
+	}
+	return recs
+}
+
 // GetReceiptsForTick gets all receipts for the given tick. If the tick is still active, or if the tick is too
 // far in the past, an error is returned.
 func (h *History) GetReceiptsForTick(tick uint64) ([]Receipt, error) {
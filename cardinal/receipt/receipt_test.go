@@ -170,3 +170,34 @@ func TestReceipt_ReceiptErrorsArePresentInJSON(t *testing.T) {
 	assert.Contains(t, body, receiptResult)
 	assert.Contains(t, body, receiptError)
 }
+
+func TestReceiptStatus(t *testing.T) {
+	assert.Equal(t, StatusSuccess, Receipt{Result: "ok"}.Status())
+	assert.Equal(t, StatusFailed, Receipt{Errs: []error{errors.New("some error")}}.Status())
+	expired := Receipt{Errs: []error{eris.Wrap(ErrTransactionExpired, "expired at tick 10")}}
+	assert.Equal(t, StatusExpired, expired.Status())
+
+	bz, err := codec.Encode(expired)
+	assert.NilError(t, err)
+	assert.Contains(t, string(bz), `"status":"expired"`)
+}
+
+func TestReceiptHasTheTickItWasAppliedAt(t *testing.T) {
+	rh := NewHistory(10, 5)
+	first, second := txHash(t), txHash(t)
+	rh.SetResult(first, "ok")
+	rh.NextTick()
+	rh.AddError(second, errors.New("some error"))
+
+	recs, err := rh.GetReceiptsForTick(10)
+	assert.NilError(t, err)
+	assert.Equal(t, 1, len(recs))
+	assert.Equal(t, uint64(10), recs[0].Tick)
+	rec, ok := rh.GetReceipt(second)
+	assert.Check(t, ok)
+	assert.Equal(t, uint64(11), rec.Tick)
+
+	bz, err := codec.Encode(rec)
+	assert.NilError(t, err)
+	assert.Contains(t, string(bz), `"tick":11`)
+}
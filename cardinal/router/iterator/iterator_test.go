@@ -40,6 +40,16 @@ func (m *mockQuerier) Submit(_ context.Context, _ *shard.SubmitTransactionsReque
 	panic("intentionally not implemented. this is a mock.")
 }
 
+func (m *mockQuerier) DeliverOutbox(_ context.Context, _ *shard.DeliverOutboxRequest, _ ...grpc.CallOption) (
+	*shard.DeliverOutboxResponse, error) {
+	panic("intentionally not implemented. this is a mock.")
+}
+
+func (m *mockQuerier) SubmitStateRoot(_ context.Context, _ *shard.SubmitStateRootRequest, _ ...grpc.CallOption) (
+	*shard.SubmitStateRootResponse, error) {
+	panic("intentionally not implemented. this is a mock.")
+}
+
 // this mock will return its error, if set, otherwise, it will return whatever is in ret[i], where i represents the
 // amount of times this was called.
 func (m *mockQuerier) QueryTransactions(
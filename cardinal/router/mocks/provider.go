@@ -5,11 +5,13 @@
 package mocks
 
 import (
+	context "context"
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
 	component "pkg.world.dev/world-engine/cardinal/persona/component"
 	types "pkg.world.dev/world-engine/cardinal/types"
+	engine "pkg.world.dev/world-engine/cardinal/types/engine"
 	sign "pkg.world.dev/world-engine/sign"
 )
 
@@ -37,18 +39,33 @@ func (m *MockProvider) EXPECT() *MockProviderMockRecorder {
 }
 
 // AddEVMTransaction mocks base method.
-func (m *MockProvider) AddEVMTransaction(id types.MessageID, msgValue any, tx *sign.Transaction, evmTxHash string) (uint64, types.TxHash) {
+func (m *MockProvider) AddEVMTransaction(ctx context.Context, id types.MessageID, msgValue any, tx *sign.Transaction, evmTxHash string) (uint64, types.TxHash, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AddEVMTransaction", id, msgValue, tx, evmTxHash)
+	ret := m.ctrl.Call(m, "AddEVMTransaction", ctx, id, msgValue, tx, evmTxHash)
 	ret0, _ := ret[0].(uint64)
 	ret1, _ := ret[1].(types.TxHash)
-	return ret0, ret1
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
 }
 
 // AddEVMTransaction indicates an expected call of AddEVMTransaction.
-func (mr *MockProviderMockRecorder) AddEVMTransaction(id, msgValue, tx, evmTxHash interface{}) *gomock.Call {
+func (mr *MockProviderMockRecorder) AddEVMTransaction(ctx, id, msgValue, tx, evmTxHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddEVMTransaction", reflect.TypeOf((*MockProvider)(nil).AddEVMTransaction), ctx, id, msgValue, tx, evmTxHash)
+}
+
+// CheckBackPressure mocks base method.
+func (m *MockProvider) CheckBackPressure() *types.RetryAfter {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckBackPressure")
+	ret0, _ := ret[0].(*types.RetryAfter)
+	return ret0
+}
+
+// CheckBackPressure indicates an expected call of CheckBackPressure.
+func (mr *MockProviderMockRecorder) CheckBackPressure() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddEVMTransaction", reflect.TypeOf((*MockProvider)(nil).AddEVMTransaction), id, msgValue, tx, evmTxHash)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckBackPressure", reflect.TypeOf((*MockProvider)(nil).CheckBackPressure))
 }
 
 // ConsumeEVMMsgResult mocks base method.
@@ -68,6 +85,20 @@ func (mr *MockProviderMockRecorder) ConsumeEVMMsgResult(evmTxHash interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumeEVMMsgResult", reflect.TypeOf((*MockProvider)(nil).ConsumeEVMMsgResult), evmTxHash)
 }
 
+// CurrentTick mocks base method.
+func (m *MockProvider) CurrentTick() uint64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CurrentTick")
+	ret0, _ := ret[0].(uint64)
+	return ret0
+}
+
+// CurrentTick indicates an expected call of CurrentTick.
+func (mr *MockProviderMockRecorder) CurrentTick() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CurrentTick", reflect.TypeOf((*MockProvider)(nil).CurrentTick))
+}
+
 // GetMessageByFullName mocks base method.
 func (m *MockProvider) GetMessageByFullName(arg0 string) (types.Message, bool) {
 	m.ctrl.T.Helper()
@@ -98,6 +129,34 @@ func (mr *MockProviderMockRecorder) GetMessageByID(id interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMessageByID", reflect.TypeOf((*MockProvider)(nil).GetMessageByID), id)
 }
 
+// GetRegisteredMessages mocks base method.
+func (m *MockProvider) GetRegisteredMessages() []types.Message {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRegisteredMessages")
+	ret0, _ := ret[0].([]types.Message)
+	return ret0
+}
+
+// GetRegisteredMessages indicates an expected call of GetRegisteredMessages.
+func (mr *MockProviderMockRecorder) GetRegisteredMessages() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRegisteredMessages", reflect.TypeOf((*MockProvider)(nil).GetRegisteredMessages))
+}
+
+// GetRegisteredQueries mocks base method.
+func (m *MockProvider) GetRegisteredQueries() []engine.Query {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRegisteredQueries")
+	ret0, _ := ret[0].([]engine.Query)
+	return ret0
+}
+
+// GetRegisteredQueries indicates an expected call of GetRegisteredQueries.
+func (mr *MockProviderMockRecorder) GetRegisteredQueries() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRegisteredQueries", reflect.TypeOf((*MockProvider)(nil).GetRegisteredQueries))
+}
+
 // GetSignerComponentForPersona mocks base method.
 func (m *MockProvider) GetSignerComponentForPersona(arg0 string) (*component.SignerComponent, error) {
 	m.ctrl.T.Helper()
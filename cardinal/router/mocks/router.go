@@ -9,6 +9,7 @@ import (
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
+	gamestate "pkg.world.dev/world-engine/cardinal/gamestate"
 	iterator "pkg.world.dev/world-engine/cardinal/router/iterator"
 	txpool "pkg.world.dev/world-engine/cardinal/types/txpool"
 )
@@ -76,6 +77,35 @@ func (mr *MockRouterMockRecorder) Start() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Start", reflect.TypeOf((*MockRouter)(nil).Start))
 }
 
+// DeliverOutbox mocks base method.
+func (m *MockRouter) DeliverOutbox(ctx context.Context, msgs []gamestate.OutboxMessage) (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeliverOutbox", ctx, msgs)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeliverOutbox indicates an expected call of DeliverOutbox.
+func (mr *MockRouterMockRecorder) DeliverOutbox(ctx, msgs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeliverOutbox", reflect.TypeOf((*MockRouter)(nil).DeliverOutbox), ctx, msgs)
+}
+
+// SubmitStateRoot mocks base method.
+func (m *MockRouter) SubmitStateRoot(ctx context.Context, startTick, endTick uint64, root []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubmitStateRoot", ctx, startTick, endTick, root)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SubmitStateRoot indicates an expected call of SubmitStateRoot.
+func (mr *MockRouterMockRecorder) SubmitStateRoot(ctx, startTick, endTick, root interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubmitStateRoot", reflect.TypeOf((*MockRouter)(nil).SubmitStateRoot), ctx, startTick, endTick, root)
+}
+
 // SubmitTxBlob mocks base method.
 func (m *MockRouter) SubmitTxBlob(ctx context.Context, processedTxs txpool.TxMap, epoch, unixTimestamp uint64) error {
 	m.ctrl.T.Helper()
@@ -0,0 +1,25 @@
+package router
+
+import (
+	"crypto/ed25519"
+	"crypto/tls"
+)
+
+type Option func(r *router)
+
+// WithAttestationKey makes the EVM gRPC server reject the messages that are not attested with the base shard router's
+// attestation key, so that only the base shard can send transactions to the game shard on behalf of EVM accounts.
+func WithAttestationKey(key ed25519.PublicKey) Option {
+	return func(r *router) {
+		r.attestationKey = key
+	}
+}
+
+// WithTLSConfig makes the EVM gRPC server accept TLS connections with the given config. Use
+// credentials.NewServerTLSConfig with a CA to also require client certificates from the base shard router, and to
+// rotate the certificates without restarting the server.
+func WithTLSConfig(cfg *tls.Config) Option {
+	return func(r *router) {
+		r.tlsConfig = cfg
+	}
+}
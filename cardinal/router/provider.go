@@ -1,8 +1,11 @@
 package router
 
 import (
+	"context"
+
 	"pkg.world.dev/world-engine/cardinal/persona/component"
 	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
 	"pkg.world.dev/world-engine/sign"
 )
 
@@ -13,12 +16,16 @@ import (
 type Provider interface {
 	GetMessageByFullName(string) (types.Message, bool)
 	GetMessageByID(id types.MessageID) (types.Message, bool)
+	GetRegisteredMessages() []types.Message
+	GetRegisteredQueries() []engine.Query
 	HandleEVMQuery(name string, abiRequest []byte) ([]byte, error)
 	GetSignerComponentForPersona(string) (*component.SignerComponent, error)
 	WaitForNextTick() bool
+	CurrentTick() uint64
+	CheckBackPressure() *types.RetryAfter
 
-	AddEVMTransaction(id types.MessageID, msgValue any, tx *sign.Transaction, evmTxHash string) (
-		tick uint64, txHash types.TxHash,
+	AddEVMTransaction(ctx context.Context, id types.MessageID, msgValue any, tx *sign.Transaction, evmTxHash string) (
+		tick uint64, txHash types.TxHash, err error,
 	)
 	ConsumeEVMMsgResult(evmTxHash string) ([]byte, []error, string, bool)
 }
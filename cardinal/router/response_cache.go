@@ -0,0 +1,79 @@
+package router
+
+import (
+	"sync"
+	"time"
+
+	routerv1 "pkg.world.dev/world-engine/rift/router/v1"
+)
+
+// DefaultResponseWindow is how long the response to a SendMessage request is remembered after it was sent.
+const DefaultResponseWindow = 10 * time.Minute
+
+// responseCache deduplicates SendMessage requests by the hash of the EVM tx that sent them. The base shard retries
+// requests whose response it did not receive; a retry must be answered with the response of the original request
+// instead of the message being executed a second time.
+type responseCache struct {
+	mu      sync.Mutex
+	window  time.Duration
+	entries map[string]*cachedResponse
+	// order holds the hashes of completed entries from oldest to newest, so that expired entries can be evicted
+	// without scanning the whole map.
+	order []string
+}
+
+type cachedResponse struct {
+	// done is closed once res is set.
+	done    chan struct{}
+	res     *routerv1.SendMessageResponse
+	expires time.Time
+}
+
+func newResponseCache(window time.Duration) *responseCache {
+	return &responseCache{
+		window:  window,
+		entries: map[string]*cachedResponse{},
+	}
+}
+
+// do returns the response to the request with the given EVM tx hash. The first request with a hash is answered by
+// calling send. Requests with the same hash that arrive while send is running wait for its response, and requests
+// that arrive within the window after it returned get the same response. Responses that tell the base shard to retry
+// later are not remembered, since the message was not executed.
+func (c *responseCache) do(evmTxHash string, send func() *routerv1.SendMessageResponse) *routerv1.SendMessageResponse {
+	c.mu.Lock()
+	c.evictExpired(time.Now())
+	if entry, ok := c.entries[evmTxHash]; ok {
+		c.mu.Unlock()
+		<-entry.done
+		return entry.res
+	}
+	entry := &cachedResponse{done: make(chan struct{})}
+	c.entries[evmTxHash] = entry
+	c.mu.Unlock()
+
+	entry.res = send()
+
+	c.mu.Lock()
+	if entry.res.GetRetryAfter() != nil {
+		delete(c.entries, evmTxHash)
+	} else {
+		entry.expires = time.Now().Add(c.window)
+		c.order = append(c.order, evmTxHash)
+	}
+	c.mu.Unlock()
+	close(entry.done)
+	return entry.res
+}
+
+// evictExpired must be called with c.mu held.
+func (c *responseCache) evictExpired(now time.Time) {
+	i := 0
+	for ; i < len(c.order); i++ {
+		if now.Before(c.entries[c.order[i]].expires) {
+			break
+		}
+		delete(c.entries, c.order[i])
+	}
+	c.order = c.order[i:]
+}
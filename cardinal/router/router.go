@@ -2,13 +2,18 @@ package router
 
 import (
 	"context"
+	"crypto/ed25519"
+	"crypto/tls"
 	"net"
 
 	"github.com/rotisserie/eris"
 	zerolog "github.com/rs/zerolog/log"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
+	grpccredentials "google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 
+	"pkg.world.dev/world-engine/cardinal/gamestate"
 	"pkg.world.dev/world-engine/cardinal/router/iterator"
 	"pkg.world.dev/world-engine/cardinal/types/txpool"
 	"pkg.world.dev/world-engine/rift/credentials"
@@ -30,9 +35,11 @@ var _ Router = (*router)(nil)
 //   - Receiving API requests from EVM smart contracts on the base shard.
 //   - Sending transactions to the base shard's game sequencer.
 //   - Querying transactions from the base shard to rebuild game state.
+//   - Delivering the messages that systems emitted to EVM contracts.
 type Router interface {
 	// RegisterGameShard registers this game shard to the base shard. This is ONLY needed so that the base shard can
-	// route requests from the EVM to this game shard by using its namespace.
+	// route requests from the EVM to this game shard by using its namespace. The registration includes the current
+	// tick and a hash of the messages and queries that are available to the EVM.
 	RegisterGameShard(context.Context) error
 
 	// SubmitTxBlob submits transactions processed in a tick to the base shard.
@@ -45,6 +52,14 @@ type Router interface {
 
 	TransactionIterator() iterator.Iterator
 
+	// DeliverOutbox hands outbox messages to the base shard and returns the ID up to which the base shard has stored
+	// this game shard's messages on chain.
+	DeliverOutbox(ctx context.Context, msgs []gamestate.OutboxMessage) (ackedID uint64, err error)
+
+	// SubmitStateRoot submits the merkle root of the game state at the end of the given range of ticks to the base
+	// shard.
+	SubmitStateRoot(ctx context.Context, startTick, endTick uint64, root []byte) error
+
 	// Shutdown gracefully stops the EVM gRPC handler.
 	Shutdown()
 	// Start serves the EVM gRPC server.
@@ -60,29 +75,69 @@ type router struct {
 	serverAddr string
 	port       string
 	routerKey  string
+	// attestationKey is the public key that verifies the attestations of the messages from the base shard router. If
+	// it is nil, messages are not required to be attested.
+	attestationKey ed25519.PublicKey
+	// tlsConfig secures the connections to the EVM gRPC server. If it is nil, the server accepts plaintext connections.
+	tlsConfig *tls.Config
 }
 
-func New(namespace, sequencerAddr, routerKey string, provider Provider) (Router, error) {
-	rtr := &router{namespace: namespace, port: defaultPort, provider: provider, routerKey: routerKey}
-
+func New(namespace, sequencerAddr, routerKey string, provider Provider, opts ...Option) (Router, error) {
 	conn, err := grpc.Dial(
 		sequencerAddr,
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
 		grpc.WithPerRPCCredentials(credentials.NewTokenCredential(routerKey)),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
 	)
 	if err != nil {
 		return nil, eris.Wrapf(err, "error dialing shard seqeuncer address at %q", sequencerAddr)
 	}
-	rtr.ShardSequencer = shard.NewTransactionHandlerClient(conn)
-	rtr.server = newEvmServer(provider, routerKey)
+	return newRouter(namespace, shard.NewTransactionHandlerClient(conn), routerKey, defaultPort, provider,
+		opts...), nil
+}
+
+// NewWithSequencer creates a router that talks to the base shard through the given client of its sequencer, instead
+// of dialing it, e.g. an in-process fake of the base shard in tests. Its EVM server listens on a free port, which is
+// registered with the base shard.
+func NewWithSequencer(
+	namespace string, sequencer shard.TransactionHandlerClient, routerKey string, provider Provider, opts ...Option,
+) Router {
+	return newRouter(namespace, sequencer, routerKey, "0", provider, opts...)
+}
+
+func newRouter(
+	namespace string, sequencer shard.TransactionHandlerClient, routerKey, port string, provider Provider,
+	opts ...Option,
+) *router {
+	rtr := &router{
+		namespace:      namespace,
+		port:           port,
+		provider:       provider,
+		routerKey:      routerKey,
+		ShardSequencer: sequencer,
+	}
+	for _, opt := range opts {
+		opt(rtr)
+	}
+	var serverOpts []grpc.ServerOption
+	if rtr.tlsConfig != nil {
+		serverOpts = append(serverOpts, grpc.Creds(grpccredentials.NewTLS(rtr.tlsConfig)))
+	}
+	rtr.server = newEvmServer(provider, namespace, routerKey, rtr.attestationKey, serverOpts...)
 	routerv1.RegisterMsgServer(rtr.server.grpcServer, rtr.server)
-	return rtr, nil
+	return rtr
 }
 
 func (r *router) RegisterGameShard(ctx context.Context) error {
-	_, err := r.ShardSequencer.RegisterGameShard(ctx, &shard.RegisterGameShardRequest{
+	schemaHash, err := r.server.schemaHash(ctx)
+	if err != nil {
+		return eris.Wrap(err, "failed to compute schema hash")
+	}
+	_, err = r.ShardSequencer.RegisterGameShard(ctx, &shard.RegisterGameShardRequest{
 		Namespace:     r.namespace,
 		RouterAddress: r.serverAddr,
+		GenesisTick:   r.provider.CurrentTick(),
+		SchemaHash:    schemaHash,
 	})
 	return err
 }
@@ -118,6 +173,36 @@ func (r *router) SubmitTxBlob(
 	return eris.Wrap(err, "")
 }
 
+func (r *router) DeliverOutbox(ctx context.Context, msgs []gamestate.OutboxMessage) (uint64, error) {
+	req := &shard.DeliverOutboxRequest{
+		Namespace: r.namespace,
+		Messages:  make([]*shard.OutboxMessage, 0, len(msgs)),
+	}
+	for _, msg := range msgs {
+		req.Messages = append(req.Messages, &shard.OutboxMessage{
+			Id:       msg.ID,
+			Tick:     msg.Tick,
+			Contract: msg.Contract,
+			Payload:  msg.Payload,
+		})
+	}
+	res, err := r.ShardSequencer.DeliverOutbox(ctx, req)
+	if err != nil {
+		return 0, eris.Wrap(err, "")
+	}
+	return res.GetAckedId(), nil
+}
+
+func (r *router) SubmitStateRoot(ctx context.Context, startTick, endTick uint64, root []byte) error {
+	_, err := r.ShardSequencer.SubmitStateRoot(ctx, &shard.SubmitStateRootRequest{
+		Namespace: r.namespace,
+		StartTick: startTick,
+		EndTick:   endTick,
+		StateRoot: root,
+	})
+	return eris.Wrap(err, "")
+}
+
 func (r *router) TransactionIterator() iterator.Iterator {
 	return iterator.New(r.provider.GetMessageByID, r.namespace, r.ShardSequencer)
 }
@@ -1,16 +1,27 @@
 package router
 
 import (
+	"cmp"
 	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"reflect"
 	"slices"
+	"time"
 
+	ethabi "github.com/ethereum/go-ethereum/accounts/abi"
 	zerolog "github.com/rs/zerolog/log"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 
+	"pkg.world.dev/world-engine/cardinal/abi"
+	"pkg.world.dev/world-engine/cardinal/types"
 	"pkg.world.dev/world-engine/rift/credentials"
 	routerv1 "pkg.world.dev/world-engine/rift/router/v1"
 	"pkg.world.dev/world-engine/sign"
@@ -24,8 +35,12 @@ const (
 	CodeUnauthorized
 	CodeUnsupportedMessage
 	CodeInvalidFormat
+	CodeRetryLater
 )
 
+// addRetryAfter is how long the base shard waits before it sends a message again that the game shard failed to add.
+const addRetryAfter = time.Second
+
 var _ routerv1.MsgServer = (*evmServer)(nil)
 
 type evmServer struct {
@@ -33,19 +48,36 @@ type evmServer struct {
 
 	provider   Provider
 	grpcServer *grpc.Server
+	namespace  string
 	routerKey  string
+	// attestationKey verifies that messages were sent by the base shard router. See credentials.VerifyAttestation.
+	attestationKey ed25519.PublicKey
+	responses      *responseCache
 }
 
-func newEvmServer(p Provider, routerKey string) *evmServer {
+func newEvmServer(
+	p Provider, namespace, routerKey string, attestationKey ed25519.PublicKey, opts ...grpc.ServerOption,
+) *evmServer {
 	e := &evmServer{
-		provider:  p,
-		routerKey: routerKey,
+		provider:       p,
+		namespace:      namespace,
+		routerKey:      routerKey,
+		attestationKey: attestationKey,
+		responses:      newResponseCache(DefaultResponseWindow),
 	}
-	e.grpcServer = grpc.NewServer(grpc.UnaryInterceptor(e.serverCallInterceptor))
+	opts = append(opts,
+		grpc.UnaryInterceptor(e.serverCallInterceptor),
+		// Continue the trace of the EVM tx, so the tick that executes the message links to it.
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+	)
+	e.grpcServer = grpc.NewServer(opts...)
 	return e
 }
 
-// serverCallInterceptor catches calls to handlers and ensures they have the right secret key.
+// serverCallInterceptor catches calls to handlers and ensures they have the right secret key. When the server has an
+// attestation key, messages must also carry an attestation from the base shard router. The secret key is shared by
+// everything that talks to the base shard, while only the router can sign attestations, so the attestation proves that
+// the message comes from the chain rather than from anyone on the network who learned the secret key.
 func (e *evmServer) serverCallInterceptor(
 	ctx context.Context,
 	req any,
@@ -53,7 +85,8 @@ func (e *evmServer) serverCallInterceptor(
 	handler grpc.UnaryHandler,
 ) (resp any, err error) {
 	// we only want to guard the SendMessage method. not the query shard method.
-	if _, ok := req.(*routerv1.SendMessageRequest); !ok {
+	msg, ok := req.(*routerv1.SendMessageRequest)
+	if !ok {
 		return handler(ctx, req)
 	}
 
@@ -66,13 +99,32 @@ func (e *evmServer) serverCallInterceptor(
 		return nil, status.Errorf(codes.Unauthenticated, "invalid %s", credentials.TokenKey)
 	}
 
+	if e.attestationKey != nil {
+		err = credentials.VerifyAttestation(e.attestationKey, e.namespace, msg, time.Now(),
+			credentials.DefaultAttestationMaxAge)
+		if err != nil {
+			zerolog.Logger.Warn().Err(err).Str("evm_tx_hash", msg.GetEvmTxHash()).Msg("rejected unattested message")
+			return nil, err
+		}
+	}
+
 	return handler(ctx, req)
 }
 
-// SendMessage is the grpcServer impl that receives SendMessage requests from the base shard client.
+// SendMessage is the grpcServer impl that receives SendMessage requests from the base shard client. Retries of a
+// request, identified by the EVM tx hash, are answered with the response to the original request.
 func (e *evmServer) SendMessage(
-	_ context.Context, req *routerv1.SendMessageRequest,
+	ctx context.Context, req *routerv1.SendMessageRequest,
 ) (*routerv1.SendMessageResponse, error) {
+	if req.GetEvmTxHash() == "" {
+		return e.sendMessage(ctx, req), nil
+	}
+	return e.responses.do(req.GetEvmTxHash(), func() *routerv1.SendMessageResponse {
+		return e.sendMessage(ctx, req)
+	}), nil
+}
+
+func (e *evmServer) sendMessage(ctx context.Context, req *routerv1.SendMessageRequest) *routerv1.SendMessageResponse {
 	// first we check if we can extract the transaction associated with the id
 	msgType, exists := e.provider.GetMessageByFullName(req.GetMessageId())
 	if !exists || !msgType.IsEVMCompatible() {
@@ -84,7 +136,16 @@ func (e *evmServer) SendMessage(
 				Error(),
 			EvmTxHash: req.GetEvmTxHash(),
 			Code:      CodeUnsupportedMessage,
-		}, nil
+		}
+	}
+
+	// admin messages must be signed by an admin signer, which a message from a contract can't be
+	if msgType.IsAdminOnly() {
+		return &routerv1.SendMessageResponse{
+			Errs:      fmt.Sprintf("message %s can only be sent by an admin signer", req.GetMessageId()),
+			EvmTxHash: req.GetEvmTxHash(),
+			Code:      CodeUnsupportedMessage,
+		}
 	}
 
 	// decode the evm bytes into the transaction
@@ -95,7 +156,7 @@ func (e *evmServer) SendMessage(
 				Error(),
 			EvmTxHash: req.GetEvmTxHash(),
 			Code:      CodeInvalidFormat,
-		}, nil
+		}
 	}
 
 	// get the signer component for the persona tag the request wants to use, and check if the evm address in the
@@ -107,7 +168,7 @@ func (e *evmServer) SendMessage(
 				Error(),
 			EvmTxHash: req.GetEvmTxHash(),
 			Code:      CodeUnauthorized,
-		}, nil
+		}
 	}
 	if !slices.Contains(signer.AuthorizedAddresses, req.GetSender()) {
 		return &routerv1.SendMessageResponse{
@@ -115,13 +176,39 @@ func (e *evmServer) SendMessage(
 				Error(),
 			EvmTxHash: req.GetEvmTxHash(),
 			Code:      CodeUnauthorized,
-		}, nil
+		}
 	}
 
 	// since we are injecting the msgValue directly, all we need is the persona tag in the signed payload.
 	// the sig checking happens in the grpcServer's Handler, not in ecs.Engine.
 	sig := &sign.Transaction{PersonaTag: req.GetPersonaTag()}
-	e.provider.AddEVMTransaction(msgType.ID(), msgValue, sig, req.GetEvmTxHash())
+	if err = msgType.Authorize(sig, msgValue); err != nil {
+		return &routerv1.SendMessageResponse{
+			Errs:      fmt.Sprintf("message %s was not authorized: %v", req.GetMessageId(), err),
+			EvmTxHash: req.GetEvmTxHash(),
+			Code:      CodeUnauthorized,
+		}
+	}
+
+	// turn the message away while the game shard is overloaded. the response tells the base shard when to retry.
+	if retry := e.provider.CheckBackPressure(); retry != nil {
+		return &routerv1.SendMessageResponse{
+			Errs:       fmt.Sprintf("game shard is overloaded (%s), retry after %s", retry.Reason, retry.After),
+			EvmTxHash:  req.GetEvmTxHash(),
+			Code:       CodeRetryLater,
+			RetryAfter: retryAfterProto(retry),
+		}
+	}
+
+	// responses that ask to retry are not cached, so the base shard sends the message again once it can be added.
+	if _, _, err = e.provider.AddEVMTransaction(ctx, msgType.ID(), msgValue, sig, req.GetEvmTxHash()); err != nil {
+		return &routerv1.SendMessageResponse{
+			Errs:       fmt.Sprintf("failed to add message %s: %v", req.GetMessageId(), err),
+			EvmTxHash:  req.GetEvmTxHash(),
+			Code:       CodeRetryLater,
+			RetryAfter: retryAfterProto(&types.RetryAfter{Reason: types.RetryReasonStorageOutage, After: addRetryAfter}),
+		}
+	}
 
 	// wait for the next tick so the msgValue gets processed
 	success := e.provider.WaitForNextTick()
@@ -129,7 +216,7 @@ func (e *evmServer) SendMessage(
 		return &routerv1.SendMessageResponse{
 			EvmTxHash: req.GetEvmTxHash(),
 			Code:      CodeServerUnresponsive,
-		}, nil
+		}
 	}
 
 	// check for the msgValue receipt.
@@ -138,7 +225,7 @@ func (e *evmServer) SendMessage(
 		return &routerv1.SendMessageResponse{
 			EvmTxHash: req.GetEvmTxHash(),
 			Code:      CodeNoResult,
-		}, nil
+		}
 	}
 
 	// we got a receipt, so lets clean it up and return it.
@@ -153,7 +240,19 @@ func (e *evmServer) SendMessage(
 		Result:    result,
 		EvmTxHash: evmTxHash,
 		Code:      code,
-	}, nil
+	}
+}
+
+func retryAfterProto(retry *types.RetryAfter) *routerv1.RetryAfter {
+	return &routerv1.RetryAfter{
+		Reason:       retry.Reason,
+		RetryAfterMs: uint64(retry.After.Milliseconds()),
+		QueuedTxs:    uint64(retry.QueuedTxs),
+		MaxQueuedTxs: uint64(retry.MaxQueuedTxs),
+		QueueFill:    retry.QueueFill,
+		TickLagMs:    uint64(retry.TickLag.Milliseconds()),
+		MaxTickLagMs: uint64(retry.MaxTickLag.Milliseconds()),
+	}
 }
 
 // QueryShard is the grpcServer impl that answers query requests from the base shard client.
@@ -169,3 +268,99 @@ func (e *evmServer) QueryShard(_ context.Context, req *routerv1.QueryShardReques
 	zerolog.Logger.Debug().Msgf("sending back reply: %v", reply)
 	return &routerv1.QueryShardResponse{Response: reply}, nil
 }
+
+// GetCatalog is the grpcServer impl that returns the messages and queries with EVM support and their ABI types.
+func (e *evmServer) GetCatalog(_ context.Context, _ *routerv1.GetCatalogRequest) (*routerv1.GetCatalogResponse, error) {
+	res := &routerv1.GetCatalogResponse{}
+	for _, msg := range e.provider.GetRegisteredMessages() {
+		if !msg.IsEVMCompatible() || msg.IsAdminOnly() {
+			continue
+		}
+		in, out, err := bindingTuples(msg)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "message %s: %v", msg.FullName(), err)
+		}
+		res.Messages = append(res.Messages, &routerv1.MessageDescriptor{
+			Name:   msg.FullName(),
+			Id:     uint64(msg.ID()),
+			Input:  in,
+			Output: out,
+		})
+	}
+	for _, q := range e.provider.GetRegisteredQueries() {
+		if !q.IsEVMCompatible() {
+			continue
+		}
+		req, reply, err := bindingTuples(q)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "query %s: %v", q.Name(), err)
+		}
+		res.Queries = append(res.Queries, &routerv1.QueryDescriptor{
+			Resource: q.Name(),
+			Request:  req,
+			Reply:    reply,
+		})
+	}
+	slices.SortFunc(res.Messages, func(a, b *routerv1.MessageDescriptor) int {
+		return cmp.Compare(a.GetName(), b.GetName())
+	})
+	slices.SortFunc(res.Queries, func(a, b *routerv1.QueryDescriptor) int {
+		return cmp.Compare(a.GetResource(), b.GetResource())
+	})
+	return res, nil
+}
+
+// schemaHash returns the hex encoded SHA-256 hash of the catalog, which changes whenever a message or query is added
+// to or removed from the EVM, or its ABI types change.
+func (e *evmServer) schemaHash(ctx context.Context) (string, error) {
+	catalog, err := e.GetCatalog(ctx, &routerv1.GetCatalogRequest{})
+	if err != nil {
+		return "", err
+	}
+	bz, err := proto.MarshalOptions{Deterministic: true}.Marshal(catalog)
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.Sum256(bz)
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// bindingTuples returns the ABI tuples of the input and output types of a message or query.
+func bindingTuples(source any) (in, out *routerv1.AbiTuple, err error) {
+	bs, ok := source.(abi.BindingSource)
+	if !ok {
+		return nil, nil, errors.New("ABI types are not available")
+	}
+	binding, err := bs.EVMBinding()
+	if err != nil {
+		return nil, nil, err
+	}
+	if in, err = abiTuple(binding.In); err != nil {
+		return nil, nil, err
+	}
+	out, err = abiTuple(binding.Out)
+	return in, out, err
+}
+
+func abiTuple(rt reflect.Type) (*routerv1.AbiTuple, error) {
+	at, err := abi.GenerateABIType(reflect.New(rt).Elem().Interface())
+	if err != nil {
+		return nil, err
+	}
+	args, err := abi.GenerateABIArguments(rt)
+	if err != nil {
+		return nil, err
+	}
+	return &routerv1.AbiTuple{Type: at.String(), Components: abiArguments(args)}, nil
+}
+
+func abiArguments(args []ethabi.ArgumentMarshaling) []*routerv1.AbiArgument {
+	if len(args) == 0 {
+		return nil
+	}
+	res := make([]*routerv1.AbiArgument, len(args))
+	for i, arg := range args {
+		res[i] = &routerv1.AbiArgument{Name: arg.Name, Type: arg.Type, Components: abiArguments(arg.Components)}
+	}
+	return res
+}
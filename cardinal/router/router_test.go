@@ -2,18 +2,28 @@ package router
 
 import (
 	"context"
+	"crypto/ed25519"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 
 	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal/message"
 	"pkg.world.dev/world-engine/cardinal/persona/component"
+	"pkg.world.dev/world-engine/cardinal/query"
 	"pkg.world.dev/world-engine/cardinal/router/mocks"
 	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+	"pkg.world.dev/world-engine/rift/credentials"
 	routerv1 "pkg.world.dev/world-engine/rift/router/v1"
 	shard "pkg.world.dev/world-engine/rift/shard/v2"
 	"pkg.world.dev/world-engine/sign"
@@ -27,6 +37,7 @@ type mockMsg struct {
 	id             types.MessageID
 	msgValue       any
 	decodeEVMBytes func() ([]byte, error)
+	authorize      func(tx *sign.Transaction) error
 }
 
 func (f *mockMsg) SetID(id types.MessageID) error {
@@ -57,6 +68,10 @@ func (f *mockMsg) Decode(bytes []byte) (any, error) {
 	return f.msgValue, err
 }
 
+func (f *mockMsg) DecodeForAPIVersion(_ string, bytes []byte) (any, error) {
+	return f.Decode(bytes)
+}
+
 func (f *mockMsg) DecodeEVMBytes(_ []byte) (any, error) {
 	return f.decodeEVMBytes()
 }
@@ -69,10 +84,25 @@ func (f *mockMsg) IsEVMCompatible() bool {
 	return f.evmCompat
 }
 
+func (f *mockMsg) IsAdminOnly() bool {
+	return false
+}
+
+func (f *mockMsg) Authorize(tx *sign.Transaction, _ any) error {
+	if f.authorize == nil {
+		return nil
+	}
+	return f.authorize(tx)
+}
+
 func (f *mockMsg) GetInFieldInformation() map[string]any {
 	return map[string]any{"foo": "bar"}
 }
 
+func (f *mockMsg) GetLegacyInFieldInformation() map[string]map[string]any {
+	return nil
+}
+
 var _ shard.TransactionHandlerClient = &fakeTxHandler{}
 
 type fakeTxHandler struct {
@@ -104,6 +134,22 @@ func (f *fakeTxHandler) QueryTransactions(
 	panic("intentionally not implemented. this is a mock")
 }
 
+func (f *fakeTxHandler) DeliverOutbox(
+	_ context.Context,
+	_ *shard.DeliverOutboxRequest,
+	_ ...grpc.CallOption,
+) (*shard.DeliverOutboxResponse, error) {
+	panic("intentionally not implemented. this is a mock")
+}
+
+func (f *fakeTxHandler) SubmitStateRoot(
+	_ context.Context,
+	_ *shard.SubmitStateRootRequest,
+	_ ...grpc.CallOption,
+) (*shard.SubmitStateRootResponse, error) {
+	panic("intentionally not implemented. this is a mock")
+}
+
 func TestRouter_SendMessage_NonCompatibleEVMMessage(t *testing.T) {
 	rtr, provider := getTestRouterAndProvider(t)
 	msg := &mockMsg{evmCompat: false}
@@ -178,7 +224,10 @@ func TestRouter_SendMessage_ResultDoesNotExist(t *testing.T) {
 		GetSignerComponentForPersona(persona).
 		Return(&component.SignerComponent{AuthorizedAddresses: []string{sender}}, nil).
 		Times(1)
-	provider.EXPECT().AddEVMTransaction(msg.id, msgValue, &sign.Transaction{PersonaTag: persona}, evmTxHash).Times(1)
+	provider.EXPECT().CheckBackPressure().Return(nil).Times(1)
+	provider.EXPECT().
+		AddEVMTransaction(gomock.Any(), msg.id, msgValue, &sign.Transaction{PersonaTag: persona}, evmTxHash).
+		Times(1)
 	provider.EXPECT().WaitForNextTick().Return(true).Times(1)
 	provider.EXPECT().ConsumeEVMMsgResult(evmTxHash).Return(nil, nil, "", false).Times(1)
 
@@ -187,6 +236,54 @@ func TestRouter_SendMessage_ResultDoesNotExist(t *testing.T) {
 	assert.Equal(t, res.GetCode(), CodeNoResult)
 }
 
+func TestRouter_SendMessage_RetryWhenTheMessageCantBeAdded(t *testing.T) {
+	router, provider := getTestRouterAndProvider(t)
+	msgValue := []byte("hello")
+	msg := &mockMsg{
+		id: 5, evmCompat: true, decodeEVMBytes: func() ([]byte, error) {
+			return msgValue, nil
+		},
+	}
+	msgName := "foo"
+	sender := "0xtyler"
+	persona := "tyler"
+	evmTxHash := "0xFooBarBaz"
+
+	req := &routerv1.SendMessageRequest{
+		Sender:     sender,
+		MessageId:  msgName,
+		PersonaTag: persona,
+		EvmTxHash:  evmTxHash,
+	}
+
+	provider.EXPECT().GetMessageByFullName(msgName).Return(msg, true).Times(2)
+	provider.EXPECT().
+		GetSignerComponentForPersona(persona).
+		Return(&component.SignerComponent{AuthorizedAddresses: []string{sender}}, nil).
+		Times(2)
+	provider.EXPECT().CheckBackPressure().Return(nil).Times(2)
+	gomock.InOrder(
+		provider.EXPECT().
+			AddEVMTransaction(gomock.Any(), msg.id, msgValue, &sign.Transaction{PersonaTag: persona}, evmTxHash).
+			Return(uint64(0), types.TxHash(""), errors.New("connection refused")),
+		provider.EXPECT().
+			AddEVMTransaction(gomock.Any(), msg.id, msgValue, &sign.Transaction{PersonaTag: persona}, evmTxHash).
+			Return(uint64(1), types.TxHash("0x1"), nil),
+	)
+	provider.EXPECT().WaitForNextTick().Return(true).Times(1)
+	provider.EXPECT().ConsumeEVMMsgResult(evmTxHash).Return([]byte("response"), nil, evmTxHash, true).Times(1)
+
+	res, err := router.server.SendMessage(context.Background(), req)
+	assert.NilError(t, err)
+	assert.Equal(t, res.GetCode(), CodeRetryLater)
+	assert.Check(t, res.GetRetryAfter() != nil)
+
+	// The retry is added, since the message wasn't.
+	res, err = router.server.SendMessage(context.Background(), req)
+	assert.NilError(t, err)
+	assert.Equal(t, res.GetCode(), CodeSuccess)
+}
+
 func TestRouter_SendMessage_TxSuccess(t *testing.T) {
 	router, provider := getTestRouterAndProvider(t)
 	msgValue := []byte("hello")
@@ -212,7 +309,10 @@ func TestRouter_SendMessage_TxSuccess(t *testing.T) {
 		GetSignerComponentForPersona(persona).
 		Return(&component.SignerComponent{AuthorizedAddresses: []string{sender}}, nil).
 		Times(1)
-	provider.EXPECT().AddEVMTransaction(msg.id, msgValue, &sign.Transaction{PersonaTag: persona}, evmTxHash).Times(1)
+	provider.EXPECT().CheckBackPressure().Return(nil).Times(1)
+	provider.EXPECT().
+		AddEVMTransaction(gomock.Any(), msg.id, msgValue, &sign.Transaction{PersonaTag: persona}, evmTxHash).
+		Times(1)
 	provider.EXPECT().WaitForNextTick().Return(true).Times(1)
 	provider.EXPECT().ConsumeEVMMsgResult(evmTxHash).Return([]byte("response"), nil, evmTxHash, true).Times(1)
 
@@ -221,6 +321,103 @@ func TestRouter_SendMessage_TxSuccess(t *testing.T) {
 	assert.Equal(t, res.GetCode(), CodeSuccess)
 }
 
+func TestRouter_SendMessage_RetryReturnsOriginalResponse(t *testing.T) {
+	router, provider := getTestRouterAndProvider(t)
+	msgValue := []byte("hello")
+	msg := &mockMsg{
+		id: 5, evmCompat: true, decodeEVMBytes: func() ([]byte, error) {
+			return msgValue, nil
+		},
+	}
+	msgName := "foo"
+	sender := "0xtyler"
+	persona := "tyler"
+	evmTxHash := "0xFooBarBaz"
+
+	req := &routerv1.SendMessageRequest{
+		Sender:     sender,
+		PersonaTag: persona,
+		MessageId:  msgName,
+		EvmTxHash:  evmTxHash,
+	}
+
+	// The message is only added to the world once, no matter how often the request is retried.
+	provider.EXPECT().GetMessageByFullName(msgName).Return(msg, true).Times(1)
+	provider.EXPECT().
+		GetSignerComponentForPersona(persona).
+		Return(&component.SignerComponent{AuthorizedAddresses: []string{sender}}, nil).
+		Times(1)
+	provider.EXPECT().CheckBackPressure().Return(nil).Times(1)
+	provider.EXPECT().
+		AddEVMTransaction(gomock.Any(), msg.id, msgValue, &sign.Transaction{PersonaTag: persona}, evmTxHash).
+		Times(1)
+	provider.EXPECT().WaitForNextTick().Return(true).Times(1)
+	provider.EXPECT().ConsumeEVMMsgResult(evmTxHash).Return([]byte("response"), nil, evmTxHash, true).Times(1)
+
+	for range 3 {
+		res, err := router.server.SendMessage(context.Background(), req)
+		assert.NilError(t, err)
+		assert.Equal(t, res.GetCode(), CodeSuccess)
+		assert.DeepEqual(t, res.GetResult(), []byte("response"))
+	}
+}
+
+func TestRouter_SendMessage_RetryLaterWhenOverloaded(t *testing.T) {
+	router, provider := getTestRouterAndProvider(t)
+	msgValue := []byte("hello")
+	msg := &mockMsg{
+		id: 5, evmCompat: true, decodeEVMBytes: func() ([]byte, error) {
+			return msgValue, nil
+		},
+	}
+	msgName := "foo"
+	sender := "0xtyler"
+	persona := "tyler"
+	evmTxHash := "0xFooBarBaz"
+
+	req := &routerv1.SendMessageRequest{
+		Sender:     sender,
+		PersonaTag: persona,
+		MessageId:  msgName,
+		EvmTxHash:  evmTxHash,
+	}
+
+	provider.EXPECT().GetMessageByFullName(msgName).Return(msg, true).Times(2)
+	provider.EXPECT().
+		GetSignerComponentForPersona(persona).
+		Return(&component.SignerComponent{AuthorizedAddresses: []string{sender}}, nil).
+		Times(2)
+	provider.EXPECT().CheckBackPressure().Return(&types.RetryAfter{
+		Reason:       types.RetryReasonQueueFull,
+		After:        time.Second,
+		QueuedTxs:    10,
+		MaxQueuedTxs: 10,
+		QueueFill:    1,
+	}).Times(1)
+
+	res, err := router.server.SendMessage(context.Background(), req)
+	assert.NilError(t, err)
+	assert.Equal(t, res.GetCode(), CodeRetryLater)
+	assert.Equal(t, res.GetEvmTxHash(), evmTxHash)
+	assert.Equal(t, res.GetRetryAfter().GetReason(), types.RetryReasonQueueFull)
+	assert.Equal(t, res.GetRetryAfter().GetRetryAfterMs(), uint64(1000))
+	assert.Equal(t, res.GetRetryAfter().GetQueuedTxs(), uint64(10))
+	assert.Equal(t, res.GetRetryAfter().GetQueueFill(), float64(1))
+
+	// The retry is not answered with the first response, since the message was not executed.
+	provider.EXPECT().CheckBackPressure().Return(nil).Times(1)
+	provider.EXPECT().
+		AddEVMTransaction(gomock.Any(), msg.id, msgValue, &sign.Transaction{PersonaTag: persona}, evmTxHash).
+		Times(1)
+	provider.EXPECT().WaitForNextTick().Return(true).Times(1)
+	provider.EXPECT().ConsumeEVMMsgResult(evmTxHash).Return([]byte("response"), nil, evmTxHash, true).Times(1)
+
+	res, err = router.server.SendMessage(context.Background(), req)
+	assert.NilError(t, err)
+	assert.Equal(t, res.GetCode(), CodeSuccess)
+	assert.Check(t, res.GetRetryAfter() == nil)
+}
+
 func TestRouter_SendMessage_NoAuthorizedAddress(t *testing.T) {
 	router, provider := getTestRouterAndProvider(t)
 	msgValue := []byte("hello")
@@ -252,6 +449,43 @@ func TestRouter_SendMessage_NoAuthorizedAddress(t *testing.T) {
 	assert.Equal(t, res.GetCode(), CodeUnauthorized)
 }
 
+func TestRouter_SendMessage_RejectedByAuthorizer(t *testing.T) {
+	router, provider := getTestRouterAndProvider(t)
+	msgValue := []byte("hello")
+	persona := "tyler"
+	msg := &mockMsg{
+		id: 5, evmCompat: true, decodeEVMBytes: func() ([]byte, error) {
+			return msgValue, nil
+		},
+		authorize: func(tx *sign.Transaction) error {
+			if tx.PersonaTag == persona {
+				return errors.New("tyler may not do this")
+			}
+			return nil
+		},
+	}
+	msgName := "foo"
+	sender := "0xtyler"
+
+	req := &routerv1.SendMessageRequest{
+		Sender:     sender,
+		MessageId:  msgName,
+		PersonaTag: persona,
+		EvmTxHash:  "0xFooBarBaz",
+	}
+
+	provider.EXPECT().GetMessageByFullName(msgName).Return(msg, true).Times(1)
+	provider.EXPECT().
+		GetSignerComponentForPersona(persona).
+		Return(&component.SignerComponent{AuthorizedAddresses: []string{sender}}, nil).
+		Times(1)
+
+	res, err := router.server.SendMessage(context.Background(), req)
+	assert.NilError(t, err)
+	assert.Equal(t, res.GetCode(), CodeUnauthorized)
+	assert.Check(t, strings.Contains(res.GetErrs(), "tyler may not do this"))
+}
+
 func TestRouter_SendMessage_TxFailed(t *testing.T) {
 	router, provider := getTestRouterAndProvider(t)
 	msgValue := []byte("hello")
@@ -277,7 +511,10 @@ func TestRouter_SendMessage_TxFailed(t *testing.T) {
 		GetSignerComponentForPersona(persona).
 		Return(&component.SignerComponent{AuthorizedAddresses: []string{sender}}, nil).
 		Times(1)
-	provider.EXPECT().AddEVMTransaction(msg.id, msgValue, &sign.Transaction{PersonaTag: persona}, evmTxHash).Times(1)
+	provider.EXPECT().CheckBackPressure().Return(nil).Times(1)
+	provider.EXPECT().
+		AddEVMTransaction(gomock.Any(), msg.id, msgValue, &sign.Transaction{PersonaTag: persona}, evmTxHash).
+		Times(1)
 	provider.EXPECT().WaitForNextTick().Return(true).Times(1)
 	provider.EXPECT().
 		ConsumeEVMMsgResult(evmTxHash).
@@ -289,22 +526,119 @@ func TestRouter_SendMessage_TxFailed(t *testing.T) {
 	assert.Equal(t, res.GetCode(), CodeTxFailed)
 }
 
+type catalogPosition struct {
+	X, Y int64
+}
+
+type catalogMove struct {
+	Direction string
+	Path      []catalogPosition
+}
+
+type catalogMoveResult struct {
+	Position catalogPosition
+}
+
+func TestRouter_SendMessage_RequiresAttestation(t *testing.T) {
+	pubKey, key, err := ed25519.GenerateKey(nil)
+	assert.NilError(t, err)
+	server := newEvmServer(nil, "cardinal", "secret", pubKey)
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(credentials.TokenKey, "secret"))
+	handled := 0
+	handler := func(context.Context, any) (any, error) {
+		handled++
+		return &routerv1.SendMessageResponse{}, nil
+	}
+	newReq := func() *routerv1.SendMessageRequest {
+		return &routerv1.SendMessageRequest{Sender: "0xabc", MessageId: "foo", EvmTxHash: "0x1", Message: []byte("hi")}
+	}
+
+	// Messages without an attestation are rejected, even with the right router key.
+	_, err = server.serverCallInterceptor(ctx, newReq(), nil, handler)
+	assert.Equal(t, status.Code(err), codes.Unauthenticated)
+
+	// So are messages attested for another game shard.
+	req := newReq()
+	req.Attestation = credentials.Attest(key, "other", req, time.Now())
+	_, err = server.serverCallInterceptor(ctx, req, nil, handler)
+	assert.Equal(t, status.Code(err), codes.Unauthenticated)
+	assert.Equal(t, handled, 0)
+
+	req = newReq()
+	req.Attestation = credentials.Attest(key, "cardinal", req, time.Now())
+	_, err = server.serverCallInterceptor(ctx, req, nil, handler)
+	assert.NilError(t, err)
+	assert.Equal(t, handled, 1)
+
+	// Queries don't need attestations.
+	_, err = server.serverCallInterceptor(ctx, &routerv1.QueryShardRequest{}, nil, handler)
+	assert.NilError(t, err)
+	assert.Equal(t, handled, 2)
+}
+
+func TestRouter_GetCatalog(t *testing.T) {
+	rtr, provider := getTestRouterAndProvider(t)
+	move := message.NewMessageType[catalogMove, catalogMoveResult]("move",
+		message.WithMsgEVMSupport[catalogMove, catalogMoveResult]())
+	assert.NilError(t, move.SetID(7))
+	noEVM := message.NewMessageType[catalogMove, catalogMoveResult]("no-evm")
+	qry, err := query.NewQueryType[catalogPosition, catalogMoveResult]("position",
+		func(engine.Context, *catalogPosition) (*catalogMoveResult, error) { return nil, nil },
+		query.WithQueryEVMSupport[catalogPosition, catalogMoveResult]())
+	assert.NilError(t, err)
+	provider.EXPECT().GetRegisteredMessages().Return([]types.Message{noEVM, move}).Times(1)
+	provider.EXPECT().GetRegisteredQueries().Return([]engine.Query{qry}).Times(1)
+
+	res, err := rtr.server.GetCatalog(context.Background(), &routerv1.GetCatalogRequest{})
+	assert.NilError(t, err)
+	assert.Len(t, res.GetMessages(), 1)
+	msg := res.GetMessages()[0]
+	assert.Equal(t, "game.move", msg.GetName())
+	assert.Equal(t, uint64(7), msg.GetId())
+	assert.Equal(t, "(string,(int64,int64)[])", msg.GetInput().GetType())
+	path := msg.GetInput().GetComponents()[1]
+	assert.Equal(t, "Path", path.GetName())
+	assert.Equal(t, "tuple[]", path.GetType())
+	assert.Equal(t, "Y", path.GetComponents()[1].GetName())
+	assert.Equal(t, "((int64,int64))", msg.GetOutput().GetType())
+
+	assert.Len(t, res.GetQueries(), 1)
+	assert.Equal(t, "position", res.GetQueries()[0].GetResource())
+	assert.Equal(t, "(int64,int64)", res.GetQueries()[0].GetRequest().GetType())
+}
+
 func TestRegisterCalledWithCorrectParams(t *testing.T) {
-	rtr, _ := getTestRouterAndProvider(t)
+	rtr, provider := getTestRouterAndProvider(t)
 	rtr.namespace = "foobar"
 	rtr.serverAddr = "meow:9000"
 	txHandler := &fakeTxHandler{}
 	rtr.ShardSequencer = txHandler
+	provider.EXPECT().CurrentTick().Return(uint64(42)).Times(1)
+	provider.EXPECT().GetRegisteredMessages().Return(nil).Times(1)
+	provider.EXPECT().GetRegisteredQueries().Return(nil).Times(1)
 	err := rtr.RegisterGameShard(context.Background())
 	assert.NilError(t, err)
 
 	assert.Equal(t, txHandler.req.GetNamespace(), rtr.namespace)
 	assert.Equal(t, txHandler.req.GetRouterAddress(), rtr.serverAddr)
+	assert.Equal(t, txHandler.req.GetGenesisTick(), uint64(42))
+	assert.Len(t, txHandler.req.GetSchemaHash(), 64)
+
+	// the schema hash changes when the game shard registers a message with EVM support
+	emptySchemaHash := txHandler.req.GetSchemaHash()
+	move := message.NewMessageType[catalogMove, catalogMoveResult]("move",
+		message.WithMsgEVMSupport[catalogMove, catalogMoveResult]())
+	assert.NilError(t, move.SetID(1))
+	provider.EXPECT().GetRegisteredMessages().Return([]types.Message{move}).Times(1)
+	provider.EXPECT().GetRegisteredQueries().Return(nil).Times(1)
+	schemaHash, err := rtr.server.schemaHash(context.Background())
+	assert.NilError(t, err)
+	assert.Check(t, schemaHash != emptySchemaHash)
 }
 
 func getTestRouterAndProvider(t *testing.T) (*router, *mocks.MockProvider) {
 	ctrl := gomock.NewController(t)
 	provider := mocks.NewMockProvider(ctrl)
 
-	return &router{provider: provider, server: newEvmServer(provider, "")}, provider
+	return &router{provider: provider, server: newEvmServer(provider, "", "", nil)}, provider
 }
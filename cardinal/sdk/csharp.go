@@ -0,0 +1,207 @@
+package sdk
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/rotisserie/eris"
+)
+
+// WriteCSharp writes a C# file for Unity with a class for every struct of the endpoints, and a CardinalClient class
+// with a method for every endpoint, in the given C# namespace. Messages are sent as transactions that are signed like
+// the sign package signs them, by methods named after the message, e.g. SendGameMove(signer, msg). Queries are run by
+// methods like QueryGameLocation(request). The file depends on Newtonsoft.Json for JSON, and on Nethereum for hashing
+// and signing.
+func WriteCSharp(w io.Writer, namespace string, endpoints []Endpoint) error {
+	endpoints = sortEndpoints(endpoints)
+	g := &csGenerator{namer: newNamer(csReservedNames...)}
+	type signature struct{ in, out string }
+	signatures := make([]signature, len(endpoints))
+	for i, e := range endpoints {
+		prefix := pascalCase(e.Group) + pascalCase(e.Name)
+		in, err := g.typeOf(e.In, prefix+"Input")
+		if err != nil {
+			return eris.Wrapf(err, "%s %s input", e.Kind, e.FullName())
+		}
+		out, err := g.typeOf(e.Out, prefix+"Output")
+		if err != nil {
+			return eris.Wrapf(err, "%s %s output", e.Kind, e.FullName())
+		}
+		signatures[i] = signature{in: in, out: out}
+	}
+
+	var out strings.Builder
+	out.WriteString("// Code generated by cardinal. DO NOT EDIT.\n\n")
+	out.WriteString(csUsings)
+	fmt.Fprintf(&out, "\nnamespace %s\n{\n", namespace)
+	out.WriteString(csRuntime)
+	// Declaring a class can name new structs, which are appended to the order
+	for i := 0; i < len(g.order); i++ {
+		if err := g.declareClass(&out, g.order[i]); err != nil {
+			return err
+		}
+	}
+
+	out.WriteString("\n    /// <summary>A client of the world, with a method for every message and query.</summary>\n")
+	out.WriteString("    public class CardinalClient : CardinalClientBase\n    {\n")
+	out.WriteString("        public CardinalClient(ClientConfig config) : base(config)\n        {\n        }\n")
+	for i, e := range endpoints {
+		method := pascalCase(e.Group) + pascalCase(e.Name)
+		out.WriteString("\n")
+		if e.Kind == KindMessage {
+			fmt.Fprintf(&out, "        /// <summary>Signs and sends a %s transaction.</summary>\n", e.FullName())
+			fmt.Fprintf(&out, "        public Task<PostTransactionResponse> Send%s(Signer signer, %s msg, "+
+				"TransactionOptions options = null) =>\n            SendTransaction(%q, signer, msg, options);\n",
+				method, signatures[i].in, e.URL)
+		} else {
+			fmt.Fprintf(&out, "        /// <summary>Runs the %s query.</summary>\n", e.FullName())
+			fmt.Fprintf(&out, "        public Task<%s> Query%s(%s request) =>\n            RunQuery<%s>(%q, request);\n",
+				signatures[i].out, method, signatures[i].in, signatures[i].out, e.URL)
+		}
+	}
+	out.WriteString("    }\n}\n")
+
+	_, err := io.WriteString(w, out.String())
+	return eris.Wrap(err, "")
+}
+
+// csGenerator maps Go types to C# types, and names the classes of the structs it encounters.
+type csGenerator struct {
+	namer
+}
+
+// typeOf returns the C# type that a value of type rt is decoded into. Anonymous structs are declared as classes named
+// after hint.
+func (g *csGenerator) typeOf(rt reflect.Type, hint string) (string, error) {
+	if rt.Kind() == reflect.Pointer {
+		elem, err := g.typeOf(rt.Elem(), hint)
+		if err != nil {
+			return "", err
+		}
+		if isCSValueType(rt.Elem()) {
+			return elem + "?", nil
+		}
+		return elem, nil
+	}
+	switch {
+	case rt == bigIntType:
+		return "BigInteger", nil
+	case implements(rt, textMarshalerType):
+		return "string", nil
+	case implements(rt, jsonMarshalerType):
+		return "JToken", nil
+	}
+
+	switch rt.Kind() { //nolint:exhaustive // the other kinds can't be encoded as JSON
+	case reflect.Bool:
+		return "bool", nil
+	case reflect.Int, reflect.Int64:
+		return "long", nil
+	case reflect.Int8:
+		return "sbyte", nil
+	case reflect.Int16:
+		return "short", nil
+	case reflect.Int32:
+		return "int", nil
+	case reflect.Uint, reflect.Uint64, reflect.Uintptr:
+		return "ulong", nil
+	case reflect.Uint8:
+		return "byte", nil
+	case reflect.Uint16:
+		return "ushort", nil
+	case reflect.Uint32:
+		return "uint", nil
+	case reflect.Float32:
+		return "float", nil
+	case reflect.Float64:
+		return "double", nil
+	case reflect.String:
+		return "string", nil
+	case reflect.Interface:
+		return "JToken", nil
+	case reflect.Slice, reflect.Array:
+		if rt.Kind() == reflect.Slice && rt.Elem().Kind() == reflect.Uint8 {
+			// Byte slices are base64 encoded, like Newtonsoft.Json encodes byte arrays
+			return "byte[]", nil
+		}
+		elem, err := g.typeOf(rt.Elem(), hint+"Item")
+		if err != nil {
+			return "", err
+		}
+		return "List<" + elem + ">", nil
+	case reflect.Map:
+		if !isJSONKey(rt.Key()) {
+			return "", eris.Errorf("map keys of type %s can't be encoded as JSON", rt.Key())
+		}
+		elem, err := g.typeOf(rt.Elem(), hint+"Value")
+		if err != nil {
+			return "", err
+		}
+		return "Dictionary<string, " + elem + ">", nil
+	case reflect.Struct:
+		if rt.Name() == "" {
+			return g.name(rt, hint), nil
+		}
+		return g.name(rt, rt.Name()), nil
+	default:
+		return "", eris.Errorf("%s can't be encoded as JSON", rt)
+	}
+}
+
+// isCSValueType returns true if the C# type of rt is a value type, which is only nullable as a Nullable<T>.
+func isCSValueType(rt reflect.Type) bool {
+	if rt == bigIntType {
+		return true
+	}
+	if implements(rt, textMarshalerType) || implements(rt, jsonMarshalerType) {
+		return false
+	}
+	switch rt.Kind() { //nolint:exhaustive // the other kinds are classes
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint,
+		reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr, reflect.Float32,
+		reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
+
+// declareClass writes the class of a struct. Its fields are named after the JSON names of the struct's fields, in
+// PascalCase.
+func (g *csGenerator) declareClass(out *strings.Builder, rt reflect.Type) error {
+	className := g.names[rt]
+	fmt.Fprintf(out, "\n    [Serializable]\n    public class %s\n    {\n", className)
+	// A member can't have the name of its class
+	used := map[string]bool{className: true}
+	for _, field := range jsonFields(rt) {
+		typ := "string"
+		if !field.quoted {
+			var err error
+			if typ, err = g.typeOf(field.typ, className+pascalCase(field.name)); err != nil {
+				return eris.Wrapf(err, "field %s.%s", rt, field.name)
+			}
+		}
+		base := identifierReplacer.Replace(pascalCase(field.name))
+		if base == "" || base[0] >= '0' && base[0] <= '9' {
+			base = "_" + base
+		}
+		name := base
+		for i := 2; used[name]; i++ {
+			name = base + strconv.Itoa(i)
+		}
+		used[name] = true
+
+		if field.omitEmpty {
+			fmt.Fprintf(out, "        [JsonProperty(%q, DefaultValueHandling = DefaultValueHandling.Ignore)]\n",
+				field.name)
+		} else {
+			fmt.Fprintf(out, "        [JsonProperty(%q)]\n", field.name)
+		}
+		fmt.Fprintf(out, "        public %s %s;\n", typ, name)
+	}
+	out.WriteString("    }\n")
+	return nil
+}
@@ -0,0 +1,246 @@
+package sdk
+
+const csUsings = `using System;
+using System.Collections.Generic;
+using System.Globalization;
+using System.Linq;
+using System.Net.Http;
+using System.Net.Http.Headers;
+using System.Numerics;
+using System.Text;
+using System.Threading.Tasks;
+using Nethereum.Signer;
+using Nethereum.Util;
+using Newtonsoft.Json;
+using Newtonsoft.Json.Linq;
+`
+
+// csReservedNames are the names of the types that csRuntime declares.
+var csReservedNames = []string{
+	"CardinalClient", "CardinalClientBase", "CardinalException", "ClientConfig", "PostTransactionResponse", "Signer",
+	"TransactionOptions", "Transactions",
+}
+
+// csRuntime is the part of the C# SDK that doesn't depend on the endpoints: the configuration of the client, the
+// signing of transactions and the HTTP requests. Like in tsRuntime, the hash of a transaction is computed like
+// sign.Transaction.populateHash computes it, and the body is sent exactly as it was hashed.
+const csRuntime = `    /// <summary>The configuration of a CardinalClient.</summary>
+    public class ClientConfig
+    {
+        /// <summary>The URL of the server, optionally with an API version, e.g. "https://example.com/v2".</summary>
+        public string BaseUrl;
+        /// <summary>The namespace of the world, which is part of the signature of every transaction.</summary>
+        public string Namespace;
+        /// <summary>An API key of a server-to-server client, sent in the Cardinal-API-Key header.</summary>
+        public string ApiKey;
+        /// <summary>A JWT of a game client, sent as a bearer token.</summary>
+        public string Token;
+        /// <summary>The HTTP client that sends the requests. Defaults to a shared client.</summary>
+        public HttpClient HttpClient;
+    }
+
+    /// <summary>
+    /// The persona that sends transactions, and the hex encoded secp256k1 private key of its signer. A Signer hands out
+    /// the nonces of its key, so use a single Signer per key.
+    /// </summary>
+    public class Signer
+    {
+        public readonly string PersonaTag;
+        public readonly string PrivateKey;
+        private readonly object nonceLock = new object();
+        private ulong lastNonce;
+
+        public Signer(string personaTag, string privateKey)
+        {
+            PersonaTag = personaTag;
+            PrivateKey = privateKey;
+        }
+
+        /// <summary>Returns a nonce that the signer has not used before, derived from the current time.</summary>
+        public ulong NextNonce()
+        {
+            lock (nonceLock)
+            {
+                var now = (ulong)DateTimeOffset.UtcNow.ToUnixTimeMilliseconds();
+                lastNonce = Math.Max(lastNonce + 1, now);
+                return lastNonce;
+            }
+        }
+    }
+
+    public class TransactionOptions
+    {
+        /// <summary>Overrides the persona tag of the signer, e.g. with SystemPersonaTag to create a persona.</summary>
+        public string PersonaTag;
+        /// <summary>A nonce that the signer has not used before. Defaults to Signer.NextNonce().</summary>
+        public ulong? Nonce;
+        /// <summary>The tick from which the transaction is no longer executed, or 0 if it doesn't expire.</summary>
+        public ulong ExpiresAtTick;
+        /// <summary>The other personas that must sign the transaction before it is executed.</summary>
+        public List<string> CoSigners;
+        /// <summary>A key that deduplicates retries of the same submission.</summary>
+        public string IdempotencyKey;
+    }
+
+    public class PostTransactionResponse
+    {
+        public string TxHash;
+        public ulong Tick;
+        public bool Pending;
+    }
+
+    /// <summary>The error of a request that the server rejected.</summary>
+    public class CardinalException : Exception
+    {
+        public readonly int Status;
+
+        public CardinalException(int status, string message) : base(message)
+        {
+            Status = status;
+        }
+    }
+
+    /// <summary>Encodes, hashes and signs transactions like Cardinal's sign package.</summary>
+    public static class Transactions
+    {
+        /// <summary>The persona tag of transactions that are not sent by a persona, e.g. to create one.</summary>
+        public const string SystemPersonaTag = "SystemPersonaTag";
+
+        public static readonly JsonSerializerSettings Settings = new JsonSerializerSettings
+        {
+            DateParseHandling = DateParseHandling.None,
+        };
+
+        private static readonly JsonSerializer Serializer = JsonSerializer.Create(Settings);
+
+        /// <summary>Encodes a message body as JSON with sorted keys.</summary>
+        public static string CanonicalJson(object value)
+        {
+            var token = value == null ? JValue.CreateNull() : JToken.FromObject(value, Serializer);
+            return SortKeys(token).ToString(Formatting.None);
+        }
+
+        private static JToken SortKeys(JToken token)
+        {
+            switch (token)
+            {
+                case JObject obj:
+                    var sorted = new JObject();
+                    foreach (var property in obj.Properties().OrderBy(p => p.Name, StringComparer.Ordinal))
+                    {
+                        sorted.Add(property.Name, SortKeys(property.Value));
+                    }
+                    return sorted;
+                case JArray array:
+                    return new JArray(array.Select(SortKeys));
+                default:
+                    return token;
+            }
+        }
+
+        /// <summary>Returns the hash of a transaction, which is what its signers sign.</summary>
+        public static byte[] Hash(
+            string personaTag, string ns, ulong nonce, string body, ulong expiresAtTick, IList<string> coSigners)
+        {
+            var data = new StringBuilder()
+                .Append(personaTag)
+                .Append(ns)
+                .Append(nonce.ToString(CultureInfo.InvariantCulture))
+                .Append(body);
+            if (expiresAtTick != 0)
+            {
+                data.Append("expiresAtTick:").Append(expiresAtTick.ToString(CultureInfo.InvariantCulture));
+            }
+            if (coSigners != null && coSigners.Count > 0)
+            {
+                data.Append("coSigners:").Append(string.Join(",", coSigners));
+            }
+            return Sha3Keccack.Current.CalculateHash(Encoding.UTF8.GetBytes(data.ToString()));
+        }
+
+        /// <summary>Signs a transaction and returns the JSON that is posted to the server.</summary>
+        public static string Sign(
+            string personaTag, string ns, ulong nonce, string body, ulong expiresAtTick, IList<string> coSigners,
+            string privateKey)
+        {
+            var hash = Hash(personaTag, ns, nonce, body, expiresAtTick, coSigners);
+            var signature = EthECDSASignature.CreateStringSignature(new EthECKey(privateKey).SignAndCalculateV(hash));
+            var json = new StringBuilder()
+                .Append("{\"personaTag\":").Append(JsonConvert.ToString(personaTag))
+                .Append(",\"namespace\":").Append(JsonConvert.ToString(ns))
+                .Append(",\"nonce\":").Append(nonce.ToString(CultureInfo.InvariantCulture))
+                .Append(",\"signature\":").Append(JsonConvert.ToString(signature))
+                .Append(",\"body\":").Append(body);
+            if (expiresAtTick != 0)
+            {
+                json.Append(",\"expiresAtTick\":").Append(expiresAtTick.ToString(CultureInfo.InvariantCulture));
+            }
+            if (coSigners != null && coSigners.Count > 0)
+            {
+                json.Append(",\"coSigners\":").Append(JsonConvert.SerializeObject(coSigners));
+            }
+            return json.Append('}').ToString();
+        }
+    }
+
+    public class CardinalClientBase
+    {
+        private static readonly HttpClient DefaultHttpClient = new HttpClient();
+
+        public readonly ClientConfig Config;
+
+        protected CardinalClientBase(ClientConfig config)
+        {
+            Config = config;
+        }
+
+        protected Task<PostTransactionResponse> SendTransaction(
+            string url, Signer signer, object msg, TransactionOptions options)
+        {
+            options = options ?? new TransactionOptions();
+            var tx = Transactions.Sign(
+                options.PersonaTag ?? signer.PersonaTag,
+                Config.Namespace,
+                options.Nonce ?? signer.NextNonce(),
+                Transactions.CanonicalJson(msg),
+                options.ExpiresAtTick,
+                options.CoSigners,
+                signer.PrivateKey);
+            return Post<PostTransactionResponse>(url, tx, options.IdempotencyKey);
+        }
+
+        protected Task<TReply> RunQuery<TReply>(string url, object request)
+        {
+            return Post<TReply>(url, JsonConvert.SerializeObject(request, Transactions.Settings), null);
+        }
+
+        private async Task<T> Post<T>(string url, string body, string idempotencyKey)
+        {
+            using (var request = new HttpRequestMessage(HttpMethod.Post, Config.BaseUrl.TrimEnd('/') + url))
+            {
+                request.Content = new StringContent(body, Encoding.UTF8, "application/json");
+                if (!string.IsNullOrEmpty(Config.ApiKey))
+                {
+                    request.Headers.Add("Cardinal-API-Key", Config.ApiKey);
+                }
+                if (!string.IsNullOrEmpty(Config.Token))
+                {
+                    request.Headers.Authorization = new AuthenticationHeaderValue("Bearer", Config.Token);
+                }
+                if (!string.IsNullOrEmpty(idempotencyKey))
+                {
+                    request.Headers.Add("Idempotency-Key", idempotencyKey);
+                }
+                using (var response = await (Config.HttpClient ?? DefaultHttpClient).SendAsync(request))
+                {
+                    var text = await response.Content.ReadAsStringAsync();
+                    if (!response.IsSuccessStatusCode)
+                    {
+                        throw new CardinalException((int)response.StatusCode, text);
+                    }
+                    return JsonConvert.DeserializeObject<T>(text, Transactions.Settings);
+                }
+            }
+        }
+    }
+`
@@ -0,0 +1,53 @@
+package sdk_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal/sdk"
+)
+
+type Signer struct {
+	Address string `json:"address"`
+	Nested  struct {
+		Level uint8 `json:"level"`
+	} `json:"nested"`
+}
+
+func TestWriteCSharp(t *testing.T) {
+	var sb strings.Builder
+	assert.NilError(t, sdk.WriteCSharp(&sb, "Game.Cardinal", []sdk.Endpoint{
+		{Kind: sdk.KindQuery, Group: "game", Name: "location", URL: "/query/game/location",
+			In: reflect.TypeOf(LocationRequest{}), Out: reflect.TypeOf(LocationReply{})},
+		{Kind: sdk.KindMessage, Group: "game", Name: "move-to", URL: "/tx/game/move-to",
+			In: reflect.TypeOf(Move{}), Out: reflect.TypeOf(MoveResult{})},
+		{Kind: sdk.KindQuery, Group: "game", Name: "signer", URL: "/query/game/signer",
+			In: reflect.TypeOf(LocationRequest{}), Out: reflect.TypeOf(Signer{})},
+	}))
+
+	cs := sb.String()
+	assert.Assert(t, strings.HasPrefix(cs, "// Code generated by cardinal. DO NOT EDIT.\n"))
+	assert.Contains(t, cs, "namespace Game.Cardinal\n{\n")
+	assert.Contains(t, cs, "    public class Move\n    {\n"+
+		"        [JsonProperty(\"X\")]\n        public long X;\n"+
+		"        [JsonProperty(\"Y\")]\n        public long Y;\n"+
+		"        [JsonProperty(\"direction\")]\n        public string Direction;\n"+
+		"        [JsonProperty(\"target\", DefaultValueHandling = DefaultValueHandling.Ignore)]\n"+
+		"        public Position Target;\n"+
+		"        [JsonProperty(\"path\")]\n        public List<Position> Path;\n"+
+		"        [JsonProperty(\"tags\")]\n        public Dictionary<string, string> Tags;\n"+
+		"        [JsonProperty(\"at\")]\n        public string At;\n"+
+		"        [JsonProperty(\"energy\")]\n        public string Energy;\n    }\n")
+	assert.Contains(t, cs, "        [JsonProperty(\"Position\")]\n        public Position Position;\n")
+	// Structs don't take the names of the SDK's types, and anonymous structs are named after their field
+	assert.Contains(t, cs, "    public class Signer2\n")
+	assert.Contains(t, cs, "        public Signer2Nested Nested;\n")
+	assert.Contains(t, cs, "    public class Signer2Nested\n    {\n        [JsonProperty(\"level\")]\n"+
+		"        public byte Level;\n    }\n")
+	assert.Contains(t, cs, "        public Task<PostTransactionResponse> SendGameMoveTo(Signer signer, Move msg, "+
+		"TransactionOptions options = null) =>\n            SendTransaction(\"/tx/game/move-to\", signer, msg, options);\n")
+	assert.Contains(t, cs, "        public Task<LocationReply> QueryGameLocation(LocationRequest request) =>\n"+
+		"            RunQuery<LocationReply>(\"/query/game/location\", request);\n")
+}
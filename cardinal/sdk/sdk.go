@@ -0,0 +1,190 @@
+// Package sdk generates client SDKs for the messages and queries of a world, so that clients call typed functions
+// instead of hand-writing requests against the HTTP server.
+package sdk
+
+import (
+	"encoding"
+	"encoding/json"
+	"math/big"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Kind is the kind of an endpoint.
+type Kind string
+
+const (
+	KindMessage Kind = "message"
+	KindQuery   Kind = "query"
+)
+
+// Endpoint describes a message or query that clients call over HTTP.
+type Endpoint struct {
+	Kind  Kind
+	Group string
+	Name  string
+	// URL is the path of the endpoint without the API version prefix, e.g. "/tx/game/move".
+	URL string
+	// In is the Go type of the message input or query request.
+	In reflect.Type
+	// Out is the Go type of the message output or query reply.
+	Out reflect.Type
+}
+
+// FullName returns the fully qualified name of the endpoint, e.g. "game.move".
+func (e Endpoint) FullName() string {
+	return e.Group + "." + e.Name
+}
+
+// EndpointSource is implemented by the messages and queries that clients can call.
+type EndpointSource interface {
+	ClientEndpoint() Endpoint
+}
+
+var (
+	bigIntType         = reflect.TypeOf(big.Int{})
+	jsonMarshalerType  = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType  = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	identifierReplacer = strings.NewReplacer("[", "_", "]", "", ".", "_", "/", "_", ",", "_", "*", "", " ", "")
+)
+
+// sortEndpoints returns a copy of the endpoints, with the messages before the queries and sorted by name.
+func sortEndpoints(endpoints []Endpoint) []Endpoint {
+	endpoints = slices.Clone(endpoints)
+	sort.Slice(endpoints, func(i, j int) bool {
+		if endpoints[i].Kind != endpoints[j].Kind {
+			return endpoints[i].Kind < endpoints[j].Kind
+		}
+		return endpoints[i].FullName() < endpoints[j].FullName()
+	})
+	return endpoints
+}
+
+// namer names the types that the structs of the endpoints are generated as.
+type namer struct {
+	names map[reflect.Type]string
+	used  map[string]bool
+	// order holds the named structs in the order in which they were encountered.
+	order []reflect.Type
+}
+
+// newNamer returns a namer that doesn't give structs the reserved names, which are the names of the types of the SDK.
+func newNamer(reserved ...string) namer {
+	n := namer{names: map[reflect.Type]string{}, used: map[string]bool{}}
+	for _, name := range reserved {
+		n.used[name] = true
+	}
+	return n
+}
+
+// name returns the name of the type of a struct, based on the given name. Structs of different packages that have the
+// same name are told apart by a number.
+func (n *namer) name(rt reflect.Type, base string) string {
+	if name, ok := n.names[rt]; ok {
+		return name
+	}
+	base = identifierReplacer.Replace(base)
+	name := base
+	for i := 2; n.used[name]; i++ {
+		name = base + strconv.Itoa(i)
+	}
+	n.used[name] = true
+	n.names[rt] = name
+	n.order = append(n.order, rt)
+	return name
+}
+
+// implements returns true if rt, or a pointer to rt, implements iface.
+func implements(rt, iface reflect.Type) bool {
+	return rt.Implements(iface) || reflect.PointerTo(rt).Implements(iface)
+}
+
+// isJSONKey returns true if encoding/json can encode a map with keys of type rt.
+func isJSONKey(rt reflect.Type) bool {
+	switch rt.Kind() { //nolint:exhaustive // the other kinds can only be keys if they are text marshalers
+	case reflect.String, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	default:
+		return rt.Implements(textMarshalerType)
+	}
+}
+
+// jsonField is a field of a struct as it appears in JSON.
+type jsonField struct {
+	name      string
+	typ       reflect.Type
+	omitEmpty bool
+	// quoted is true for fields with the ",string" option, which are encoded as strings.
+	quoted bool
+}
+
+// jsonFields returns the fields of a struct that encoding/json encodes, including the fields of embedded structs.
+func jsonFields(rt reflect.Type) []jsonField {
+	var fields []jsonField
+	for i := 0; i < rt.NumField(); i++ {
+		f := rt.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		ft := f.Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if f.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			fields = append(fields, jsonFields(ft)...)
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		options := strings.Split(opts, ",")
+		fields = append(fields, jsonField{
+			name:      name,
+			typ:       f.Type,
+			omitEmpty: slices.Contains(options, "omitempty"),
+			quoted:    slices.Contains(options, "string"),
+		})
+	}
+	return fields
+}
+
+// camelCase turns a name like "create-persona" into "createPersona".
+func camelCase(name string) string {
+	var out strings.Builder
+	upper := false
+	for i, r := range name {
+		switch {
+		case r == '-' || r == '_' || r == '.' || r == ' ':
+			upper = out.Len() > 0
+		case upper:
+			out.WriteRune(unicode.ToUpper(r))
+			upper = false
+		case i == 0:
+			out.WriteRune(unicode.ToLower(r))
+		default:
+			out.WriteRune(r)
+		}
+	}
+	return out.String()
+}
+
+// pascalCase turns a name like "create-persona" into "CreatePersona".
+func pascalCase(name string) string {
+	name = camelCase(name)
+	if name == "" {
+		return name
+	}
+	r, size := utf8.DecodeRuneInString(name)
+	return string(unicode.ToUpper(r)) + name[size:]
+}
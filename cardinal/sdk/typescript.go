@@ -0,0 +1,210 @@
+package sdk
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/rotisserie/eris"
+)
+
+// WriteTypeScript writes a TypeScript module with an interface for every struct of the endpoints, and a
+// CardinalClient class with a typed function for every endpoint. Messages are sent as transactions that are signed
+// like the sign package signs them, and are grouped under client.tx, e.g. client.tx.game.move(signer, msg). Queries
+// are grouped under client.query, e.g. client.query.game.location(request). The module depends on ethers v6 for
+// hashing and signing.
+func WriteTypeScript(w io.Writer, endpoints []Endpoint) error {
+	endpoints = sortEndpoints(endpoints)
+	g := &tsGenerator{namer: newNamer(tsReservedNames...)}
+	type signature struct{ in, out string }
+	signatures := make([]signature, len(endpoints))
+	for i, e := range endpoints {
+		in, err := g.typeOf(e.In)
+		if err != nil {
+			return eris.Wrapf(err, "%s %s input", e.Kind, e.FullName())
+		}
+		out, err := g.typeOf(e.Out)
+		if err != nil {
+			return eris.Wrapf(err, "%s %s output", e.Kind, e.FullName())
+		}
+		signatures[i] = signature{in: in, out: out}
+	}
+
+	var out strings.Builder
+	out.WriteString("// Code generated by cardinal. DO NOT EDIT.\n\n")
+	out.WriteString(tsRuntime)
+	// Declaring an interface can name new structs, which are appended to the order
+	for i := 0; i < len(g.order); i++ {
+		if err := g.declareInterface(&out, g.order[i]); err != nil {
+			return err
+		}
+	}
+
+	out.WriteString("\nexport class CardinalClient extends CardinalClientBase {\n")
+	for _, kind := range []Kind{KindMessage, KindQuery} {
+		property := "tx"
+		if kind == KindQuery {
+			property = "query"
+		}
+		fmt.Fprintf(&out, "  readonly %s = {\n", property)
+		group := ""
+		for i, e := range endpoints {
+			if e.Kind != kind {
+				continue
+			}
+			if e.Group != group {
+				if group != "" {
+					out.WriteString("    },\n")
+				}
+				group = e.Group
+				fmt.Fprintf(&out, "    %s: {\n", tsPropertyName(camelCase(group)))
+			}
+			if kind == KindMessage {
+				fmt.Fprintf(&out, "      /** Signs and sends a %s transaction. */\n", e.FullName())
+				fmt.Fprintf(&out, "      %s: (signer: Signer, msg: %s, options?: TransactionOptions): "+
+					"Promise<PostTransactionResponse> =>\n        this.sendTransaction(%q, signer, msg, options),\n",
+					tsPropertyName(camelCase(e.Name)), signatures[i].in, e.URL)
+			} else {
+				fmt.Fprintf(&out, "      /** Runs the %s query. */\n", e.FullName())
+				fmt.Fprintf(&out, "      %s: (request: %s): Promise<%s> => this.runQuery(%q, request),\n",
+					tsPropertyName(camelCase(e.Name)), signatures[i].in, signatures[i].out, e.URL)
+			}
+		}
+		if group != "" {
+			out.WriteString("    },\n")
+		}
+		out.WriteString("  };\n")
+		if kind == KindMessage {
+			out.WriteString("\n")
+		}
+	}
+	out.WriteString("}\n")
+
+	_, err := io.WriteString(w, out.String())
+	return eris.Wrap(err, "")
+}
+
+// tsGenerator maps Go types to TypeScript types, and names the interfaces of the structs it encounters.
+type tsGenerator struct {
+	namer
+}
+
+// typeOf returns the TypeScript type of the JSON encoding of a value of type rt.
+func (g *tsGenerator) typeOf(rt reflect.Type) (string, error) {
+	if rt.Kind() == reflect.Pointer {
+		elem, err := g.typeOf(rt.Elem())
+		if err != nil {
+			return "", err
+		}
+		return elem + " | null", nil
+	}
+	switch {
+	case rt == bigIntType:
+		return "number", nil
+	case implements(rt, textMarshalerType):
+		// Text marshalers are strings, even if they implement json.Marshaler too, like time.Time
+		return "string", nil
+	case implements(rt, jsonMarshalerType):
+		return "unknown", nil
+	}
+
+	switch rt.Kind() { //nolint:exhaustive // the other kinds can't be encoded as JSON
+	case reflect.Bool:
+		return "boolean", nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return "number", nil
+	case reflect.String:
+		return "string", nil
+	case reflect.Interface:
+		return "unknown", nil
+	case reflect.Slice, reflect.Array:
+		if rt.Kind() == reflect.Slice && rt.Elem().Kind() == reflect.Uint8 {
+			// Byte slices are base64 encoded
+			return "string", nil
+		}
+		elem, err := g.typeOf(rt.Elem())
+		if err != nil {
+			return "", err
+		}
+		if strings.Contains(elem, " ") {
+			elem = "(" + elem + ")"
+		}
+		return elem + "[]", nil
+	case reflect.Map:
+		if !isJSONKey(rt.Key()) {
+			return "", eris.Errorf("map keys of type %s can't be encoded as JSON", rt.Key())
+		}
+		elem, err := g.typeOf(rt.Elem())
+		if err != nil {
+			return "", err
+		}
+		return "Record<string, " + elem + ">", nil
+	case reflect.Struct:
+		if rt.Name() == "" {
+			var out strings.Builder
+			if err := g.writeFields(&out, rt, ""); err != nil {
+				return "", err
+			}
+			if out.Len() == 0 {
+				return "Record<string, never>", nil
+			}
+			return "{ " + out.String() + "}", nil
+		}
+		return g.name(rt, rt.Name()), nil
+	default:
+		return "", eris.Errorf("%s can't be encoded as JSON", rt)
+	}
+}
+
+// declareInterface writes the interface of a named struct.
+func (g *tsGenerator) declareInterface(out *strings.Builder, rt reflect.Type) error {
+	fmt.Fprintf(out, "\nexport interface %s {\n", g.names[rt])
+	if err := g.writeFields(out, rt, "  "); err != nil {
+		return err
+	}
+	out.WriteString("}\n")
+	return nil
+}
+
+// writeFields writes the fields of a struct as they appear in its JSON encoding. Fields are written on their own line
+// if indent is set, and inline otherwise.
+func (g *tsGenerator) writeFields(out *strings.Builder, rt reflect.Type, indent string) error {
+	for _, field := range jsonFields(rt) {
+		typ := "string"
+		if !field.quoted {
+			var err error
+			if typ, err = g.typeOf(field.typ); err != nil {
+				return eris.Wrapf(err, "field %s.%s", rt, field.name)
+			}
+		}
+		optional := ""
+		if field.omitEmpty {
+			optional = "?"
+		}
+		if indent == "" {
+			fmt.Fprintf(out, "%s%s: %s; ", tsPropertyName(field.name), optional, typ)
+		} else {
+			fmt.Fprintf(out, "%s%s%s: %s;\n", indent, tsPropertyName(field.name), optional, typ)
+		}
+	}
+	return nil
+}
+
+// tsPropertyName quotes a property name that is not a valid TypeScript identifier.
+func tsPropertyName(name string) string {
+	for i, r := range name {
+		if r == '_' || r == '$' || unicode.IsLetter(r) || i > 0 && unicode.IsDigit(r) {
+			continue
+		}
+		return strconv.Quote(name)
+	}
+	if name == "" {
+		return `""`
+	}
+	return name
+}
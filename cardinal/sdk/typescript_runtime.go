@@ -0,0 +1,184 @@
+package sdk
+
+// tsReservedNames are the names of the types that tsRuntime declares.
+var tsReservedNames = []string{
+	"CardinalClient", "CardinalClientBase", "CardinalError", "ClientConfig", "PostTransactionResponse", "Signer",
+	"TransactionOptions", "UnsignedTransaction",
+}
+
+// tsRuntime is the part of the TypeScript SDK that doesn't depend on the endpoints: the configuration of the client,
+// the signing of transactions and the HTTP requests. The hash of a transaction is computed like
+// sign.Transaction.populateHash computes it, and the body is sent exactly as it was hashed.
+const tsRuntime = `import { SigningKey, concat, keccak256, toUtf8Bytes } from "ethers";
+
+/** The persona tag of transactions that are not sent by a persona, e.g. the ones that create a persona. */
+export const SYSTEM_PERSONA_TAG = "SystemPersonaTag";
+
+export interface ClientConfig {
+  /** The URL of the Cardinal server, optionally with an API version, e.g. "https://game.example.com/v2". */
+  baseUrl: string;
+  /** The namespace of the world, which is part of the signature of every transaction. */
+  namespace: string;
+  /** An API key of a server-to-server client, sent in the Cardinal-API-Key header. */
+  apiKey?: string;
+  /** A JWT of a game client, sent as a bearer token. */
+  token?: string;
+  /** The fetch function to use, e.g. a polyfill. Defaults to the global fetch. */
+  fetch?: typeof fetch;
+}
+
+/** The persona that sends a transaction, and the hex encoded secp256k1 private key of its signer. */
+export interface Signer {
+  personaTag: string;
+  privateKey: string;
+}
+
+export interface TransactionOptions {
+  /** Overrides the persona tag of the signer, e.g. with SYSTEM_PERSONA_TAG to create a persona. */
+  personaTag?: string;
+  /** A nonce that the signer has not used before. Defaults to a nonce derived from the current time. */
+  nonce?: number;
+  /** The tick from which the transaction is no longer executed. */
+  expiresAtTick?: number;
+  /** The other personas that must sign the transaction before it is executed. */
+  coSigners?: string[];
+  /** A key that deduplicates retries of the same submission. */
+  idempotencyKey?: string;
+}
+
+export interface PostTransactionResponse {
+  TxHash: string;
+  Tick: number;
+  Pending: boolean;
+}
+
+/** The error of a request that the server rejected. */
+export class CardinalError extends Error {
+  constructor(
+    readonly status: number,
+    message: string,
+  ) {
+    super(message);
+    this.name = "CardinalError";
+  }
+}
+
+function sortKeys(value: unknown): unknown {
+  if (Array.isArray(value)) {
+    return value.map(sortKeys);
+  }
+  if (value !== null && typeof value === "object") {
+    const sorted: Record<string, unknown> = {};
+    for (const key of Object.keys(value).sort()) {
+      sorted[key] = sortKeys((value as Record<string, unknown>)[key]);
+    }
+    return sorted;
+  }
+  return value;
+}
+
+/** Encodes a message body like Go does: with sorted keys, and with <, > and & escaped. */
+export function canonicalJSON(value: unknown): string {
+  return JSON.stringify(sortKeys(value)).replace(
+    /[<>&\u2028\u2029]/g,
+    (c) => "\\u" + c.charCodeAt(0).toString(16).padStart(4, "0"),
+  );
+}
+
+export interface UnsignedTransaction {
+  personaTag: string;
+  namespace: string;
+  nonce: number;
+  /** The JSON encoded message, exactly as it is sent. */
+  body: string;
+  expiresAtTick?: number;
+  coSigners?: string[];
+}
+
+/** Returns the hex encoded hash of a transaction, which is what its signers sign. */
+export function transactionHash(tx: UnsignedTransaction): string {
+  const fields = [
+    toUtf8Bytes(tx.personaTag),
+    toUtf8Bytes(tx.namespace),
+    toUtf8Bytes(String(tx.nonce)),
+    toUtf8Bytes(tx.body),
+  ];
+  if (tx.expiresAtTick) {
+    fields.push(toUtf8Bytes("expiresAtTick:" + tx.expiresAtTick));
+  }
+  if (tx.coSigners && tx.coSigners.length > 0) {
+    fields.push(toUtf8Bytes("coSigners:" + tx.coSigners.join(",")));
+  }
+  return keccak256(concat(fields));
+}
+
+/** Signs a transaction and returns the JSON that is posted to the server. */
+export function signTransaction(tx: UnsignedTransaction, privateKey: string): string {
+  const signature = new SigningKey(privateKey).sign(transactionHash(tx)).serialized;
+  let json =
+    '{"personaTag":' + JSON.stringify(tx.personaTag) +
+    ',"namespace":' + JSON.stringify(tx.namespace) +
+    ',"nonce":' + tx.nonce +
+    ',"signature":' + JSON.stringify(signature) +
+    ',"body":' + tx.body;
+  if (tx.expiresAtTick) {
+    json += ',"expiresAtTick":' + tx.expiresAtTick;
+  }
+  if (tx.coSigners && tx.coSigners.length > 0) {
+    json += ',"coSigners":' + JSON.stringify(tx.coSigners);
+  }
+  return json + "}";
+}
+
+export class CardinalClientBase {
+  private lastNonce = 0;
+
+  constructor(readonly config: ClientConfig) {}
+
+  protected async sendTransaction(
+    url: string,
+    signer: Signer,
+    msg: unknown,
+    options: TransactionOptions = {},
+  ): Promise<PostTransactionResponse> {
+    const tx: UnsignedTransaction = {
+      personaTag: options.personaTag ?? signer.personaTag,
+      namespace: this.config.namespace,
+      nonce: options.nonce ?? this.nextNonce(),
+      body: canonicalJSON(msg),
+      expiresAtTick: options.expiresAtTick,
+      coSigners: options.coSigners,
+    };
+    const headers: Record<string, string> = {};
+    if (options.idempotencyKey) {
+      headers["Idempotency-Key"] = options.idempotencyKey;
+    }
+    return this.post(url, signTransaction(tx, signer.privateKey), headers) as Promise<PostTransactionResponse>;
+  }
+
+  protected async runQuery(url: string, request: unknown): Promise<any> {
+    return this.post(url, JSON.stringify(request), {});
+  }
+
+  private nextNonce(): number {
+    this.lastNonce = Math.max(this.lastNonce + 1, Date.now());
+    return this.lastNonce;
+  }
+
+  private async post(url: string, body: string, headers: Record<string, string>): Promise<unknown> {
+    headers["Content-Type"] = "application/json";
+    if (this.config.apiKey) {
+      headers["Cardinal-API-Key"] = this.config.apiKey;
+    }
+    if (this.config.token) {
+      headers["Authorization"] = "Bearer " + this.config.token;
+    }
+    const doFetch = this.config.fetch ?? fetch;
+    const res = await doFetch(this.config.baseUrl.replace(/\/+$/, "") + url, { method: "POST", headers, body });
+    if (!res.ok) {
+      throw new CardinalError(res.status, await res.text());
+    }
+    return res.json();
+  }
+}
+`
@@ -0,0 +1,68 @@
+package sdk_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal/sdk"
+)
+
+type Position struct {
+	X, Y int
+}
+
+type Move struct {
+	Position
+	Direction string            `json:"direction"`
+	Target    *Position         `json:"target,omitempty"`
+	Path      []Position        `json:"path"`
+	Tags      map[string]string `json:"tags"`
+	At        time.Time         `json:"at"`
+	Energy    int64             `json:"energy,string"`
+	Ignored   string            `json:"-"`
+}
+
+type MoveResult struct {
+	Moved bool `json:"moved"`
+}
+
+type LocationRequest struct {
+	ID string `json:"id"`
+}
+
+type LocationReply struct {
+	Position Position
+	Visible  []*Position `json:"visible"`
+}
+
+func TestWriteTypeScript(t *testing.T) {
+	var sb strings.Builder
+	assert.NilError(t, sdk.WriteTypeScript(&sb, []sdk.Endpoint{
+		{Kind: sdk.KindQuery, Group: "game", Name: "location", URL: "/query/game/location",
+			In: reflect.TypeOf(LocationRequest{}), Out: reflect.TypeOf(LocationReply{})},
+		{Kind: sdk.KindMessage, Group: "game", Name: "move-to", URL: "/tx/game/move-to",
+			In: reflect.TypeOf(Move{}), Out: reflect.TypeOf(MoveResult{})},
+	}))
+
+	ts := sb.String()
+	assert.Assert(t, strings.HasPrefix(ts, "// Code generated by cardinal. DO NOT EDIT.\n"))
+	assert.Contains(t, ts, "export interface Move {\n  X: number;\n  Y: number;\n  direction: string;\n"+
+		"  target?: Position | null;\n  path: Position[];\n  tags: Record<string, string>;\n  at: string;\n"+
+		"  energy: string;\n}")
+	assert.Contains(t, ts, "export interface Position {\n  X: number;\n  Y: number;\n}")
+	assert.Contains(t, ts, "export interface MoveResult {\n  moved: boolean;\n}")
+	assert.Contains(t, ts, "export interface LocationReply {\n  Position: Position;\n  visible: (Position | null)[];\n}")
+	assert.Contains(t, ts, "  readonly tx = {\n    game: {\n      /** Signs and sends a game.move-to transaction. */\n"+
+		"      moveTo: (signer: Signer, msg: Move, options?: TransactionOptions): Promise<PostTransactionResponse> =>\n"+
+		"        this.sendTransaction(\"/tx/game/move-to\", signer, msg, options),\n    },\n  };")
+	assert.Contains(t, ts, "      location: (request: LocationRequest): Promise<LocationReply> => "+
+		"this.runQuery(\"/query/game/location\", request),")
+
+	type unsupported struct{ C chan int }
+	err := sdk.WriteTypeScript(&sb, []sdk.Endpoint{{Kind: sdk.KindQuery, Group: "game", Name: "bad",
+		In: reflect.TypeOf(unsupported{}), Out: reflect.TypeOf(LocationReply{})}})
+	assert.ErrorContains(t, err, "can't be encoded as JSON")
+}
@@ -0,0 +1,87 @@
+package search
+
+import (
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal/search/filter"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+// FieldSearch is a search for the entities whose component field is equal to a value. See WhereEq.
+type FieldSearch struct {
+	component filter.ComponentWrapper
+	field     string
+	value     any
+}
+
+// WhereEq returns a search for the entities whose field of the given component is equal to value. The entities are
+// looked up in the index of the field, which must have been registered with cardinal.RegisterFieldIndex, so the search
+// doesn't visit the entities whose field has another value. Like the other searches, it can be combined with And, Or
+// and Not.
+//
+// Usage:
+//
+//	members, err := search.WhereEq(filter.Component[Guild](), "ID", guildID).Collect(wCtx)
+func WhereEq(component filter.ComponentWrapper, field string, value any) Searchable {
+	return &FieldSearch{component: component, field: field, value: value}
+}
+
+func (s *FieldSearch) Collect(eCtx engine.Context) ([]types.EntityID, error) {
+	c, err := eCtx.GetComponentByName(s.component.Component.Name())
+	if err != nil {
+		return nil, err
+	}
+	ids, err := eCtx.FindByField(c, s.field, s.value)
+	if err != nil {
+		return nil, err
+	}
+	eCtx.RecordSearch(0, len(ids))
+	return ids, nil
+}
+
+func (s *FieldSearch) Each(eCtx engine.Context, callback CallbackFn) error {
+	ids, err := s.Collect(eCtx)
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if !callback(id) {
+			return nil
+		}
+	}
+	return nil
+}
+
+func (s *FieldSearch) First(eCtx engine.Context) (types.EntityID, error) {
+	ids, err := s.Collect(eCtx)
+	if err != nil {
+		return 0, err
+	}
+	if len(ids) == 0 {
+		return 0, eris.New("No search results")
+	}
+	return ids[0], nil
+}
+
+func (s *FieldSearch) MustFirst(eCtx engine.Context) types.EntityID {
+	id, err := s.First(eCtx)
+	if err != nil {
+		panic("No search results")
+	}
+	return id
+}
+
+func (s *FieldSearch) Count(eCtx engine.Context) (int, error) {
+	ids, err := s.Collect(eCtx)
+	if err != nil {
+		return 0, err
+	}
+	return len(ids), nil
+}
+
+// evaluateSearch returns the archetypes that have the component, which include the archetypes of all the entities that
+// the search finds.
+func (s *FieldSearch) evaluateSearch(eCtx engine.Context) []types.ArchetypeID {
+	return NewSearch().Entity(filter.Contains(s.component)).evaluateSearch(eCtx)
+}
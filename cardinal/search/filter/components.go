@@ -0,0 +1,35 @@
+package filter
+
+import (
+	"slices"
+)
+
+// ComponentNames returns the names of the components that the filter matches archetypes on, in name order. The
+// components that are excluded with Not are left out, since a search doesn't read them.
+func ComponentNames(f ComponentFilter) []string {
+	names := appendComponentNames([]string{}, f)
+	slices.Sort(names)
+	return slices.Compact(names)
+}
+
+func appendComponentNames(names []string, f ComponentFilter) []string {
+	switch f := f.(type) {
+	case *contains:
+		for _, c := range f.components {
+			names = append(names, c.Name())
+		}
+	case exact:
+		for _, c := range f.components {
+			names = append(names, c.Name())
+		}
+	case *and:
+		for _, sub := range f.filters {
+			names = appendComponentNames(names, sub)
+		}
+	case *or:
+		for _, sub := range f.filters {
+			names = appendComponentNames(names, sub)
+		}
+	}
+	return names
+}
@@ -42,6 +42,32 @@ func ComponentFilter[T types.Component](f func(comp T) bool) filterFn {
 	}
 }
 
+// ComponentFieldsFilter is like ComponentFilter, but only decodes the given fields of the component before passing it
+// to f. All other fields are left at their zero value.
+//
+//revive:disable-next-line:unexported-return
+func ComponentFieldsFilter[T types.Component](fields []string, f func(comp T) bool) filterFn {
+	return func(wCtx engine.Context, id types.EntityID) (bool, error) {
+		var t T
+		c, err := wCtx.GetComponentByName(t.Name())
+		if err != nil {
+			return false, err
+		}
+		compValue, err := wCtx.StoreReader().GetComponentFieldsForEntity(c, id, fields)
+		if err != nil {
+			return false, err
+		}
+		switch comp := compValue.(type) {
+		case T:
+			return f(comp), nil
+		case *T:
+			return f(*comp), nil
+		default:
+			return false, eris.New("no result found.")
+		}
+	}
+}
+
 //revive:disable-next-line:unexported-return
 func AndFilter(fns ...filterFn) filterFn {
 	return func(wCtx engine.Context, id types.EntityID) (bool, error) {
@@ -13,6 +13,9 @@ import (
 
 type CallbackFn func(types.EntityID) bool
 
+// BatchCallbackFn is called with entities that all belong to the archetype.
+type BatchCallbackFn func(archID types.ArchetypeID, ids []types.EntityID) bool
+
 type cache struct {
 	archetypes []types.ArchetypeID
 	seen       int
@@ -28,6 +31,8 @@ type Search struct {
 	archMatches             *cache
 	filter                  filter.ComponentFilter
 	componentPropertyFilter filterFn
+	// pattern are the names of the components of the filter, which are recorded as the query pattern of the search.
+	pattern []string
 }
 
 // interfaces restrict order of operations.
@@ -90,6 +95,7 @@ type searchBuilder interface {
 type EntitySearch interface {
 	Searchable
 	Where(componentFilter filterFn) EntitySearch
+	EachBatch(eCtx engine.Context, callback BatchCallbackFn) error
 }
 
 type Searchable interface {
@@ -118,6 +124,7 @@ func NewLegacySearch(componentFilter filter.ComponentFilter) EntitySearch {
 
 func (s *Search) Entity(componentFilter filter.ComponentFilter) EntitySearch {
 	s.filter = componentFilter
+	s.pattern = nil
 	return s
 }
 
@@ -143,6 +150,8 @@ func (s *Search) Each(eCtx engine.Context, callback CallbackFn) (err error) {
 	defer func() { defer panicOnFatalError(eCtx, err) }()
 
 	result := s.evaluateSearch(eCtx)
+	visited := 0
+	defer func() { eCtx.RecordSearch(len(result), visited) }()
 	iter := iterators.NewEntityIterator(0, eCtx.StoreReader(), result)
 	for iter.HasNext() {
 		entities, err := iter.Next()
@@ -150,6 +159,7 @@ func (s *Search) Each(eCtx engine.Context, callback CallbackFn) (err error) {
 			return err
 		}
 		for _, id := range entities {
+			visited++
 			var filterValue bool
 			if s.componentPropertyFilter != nil {
 				filterValue, err = s.componentPropertyFilter(eCtx, id)
@@ -171,6 +181,39 @@ func (s *Search) Each(eCtx engine.Context, callback CallbackFn) (err error) {
 	return nil
 }
 
+// EachBatch iterates over all entities that match the search, one archetype at a time. Since all entities of a batch
+// have the same components, callers can read and write their components in bulk. To stop the iteration, return false
+// to the callback.
+func (s *Search) EachBatch(eCtx engine.Context, callback BatchCallbackFn) (err error) {
+	defer func() { defer panicOnFatalError(eCtx, err) }()
+
+	result := s.evaluateSearch(eCtx)
+	visited := 0
+	defer func() { eCtx.RecordSearch(len(result), visited) }()
+	for _, archID := range result {
+		entities, err := eCtx.StoreReader().GetEntitiesForArchID(archID)
+		if err != nil {
+			return err
+		}
+		// The entities are copied, since the callback may change the entities of the archetype
+		ids := make([]types.EntityID, 0, len(entities))
+		for _, id := range entities {
+			visited++
+			if s.componentPropertyFilter != nil {
+				filterValue, err := s.componentPropertyFilter(eCtx, id)
+				if err != nil || !filterValue {
+					continue
+				}
+			}
+			ids = append(ids, id)
+		}
+		if len(ids) > 0 && !callback(archID, ids) {
+			return nil
+		}
+	}
+	return nil
+}
+
 func fastSortIDs(ids []types.EntityID) {
 	slices.Sort(ids)
 }
@@ -189,10 +232,19 @@ func (s *Search) Collect(eCtx engine.Context) ([]types.EntityID, error) {
 }
 
 // Count returns the number of entities that match the search.
+// If the search has no Where clause, the count is computed from the archetype sizes without loading any entity or
+// component data.
 func (s *Search) Count(eCtx engine.Context) (ret int, err error) {
 	defer func() { defer panicOnFatalError(eCtx, err) }()
 
 	result := s.evaluateSearch(eCtx)
+	if s.componentPropertyFilter == nil {
+		// Counting by archetype sizes doesn't visit any entity
+		eCtx.RecordSearch(len(result), 0)
+		return countArchetypeEntities(eCtx, result)
+	}
+	visited := 0
+	defer func() { eCtx.RecordSearch(len(result), visited) }()
 	iter := iterators.NewEntityIterator(0, eCtx.StoreReader(), result)
 	for iter.HasNext() {
 		entities, err := iter.Next()
@@ -200,6 +252,7 @@ func (s *Search) Count(eCtx engine.Context) (ret int, err error) {
 			return 0, err
 		}
 		for _, id := range entities {
+			visited++
 			var filterValue bool
 			if s.componentPropertyFilter != nil {
 				filterValue, err = s.componentPropertyFilter(eCtx, id)
@@ -217,11 +270,25 @@ func (s *Search) Count(eCtx engine.Context) (ret int, err error) {
 	return ret, nil
 }
 
-// First returns the first entity that matches the search.
+func countArchetypeEntities(eCtx engine.Context, archIDs []types.ArchetypeID) (int, error) {
+	count := 0
+	for _, archID := range archIDs {
+		n, err := eCtx.StoreReader().CountEntitiesForArchID(archID)
+		if err != nil {
+			return 0, err
+		}
+		count += n
+	}
+	return count, nil
+}
+
+// First returns the first entity that matches the search. Iteration stops as soon as a matching entity is found.
 func (s *Search) First(eCtx engine.Context) (id types.EntityID, err error) {
 	defer func() { defer panicOnFatalError(eCtx, err) }()
 
 	result := s.evaluateSearch(eCtx)
+	visited := 0
+	defer func() { eCtx.RecordSearch(len(result), visited) }()
 	iter := iterators.NewEntityIterator(0, eCtx.StoreReader(), result)
 	if !iter.HasNext() {
 		return iterators.BadID, eris.Wrap(err, "")
@@ -232,6 +299,7 @@ func (s *Search) First(eCtx engine.Context) (id types.EntityID, err error) {
 			return 0, err
 		}
 		for _, id := range entities {
+			visited++
 			var filterValue bool
 			if s.componentPropertyFilter != nil {
 				filterValue, err = s.componentPropertyFilter(eCtx, id)
@@ -263,5 +331,9 @@ func (s *Search) evaluateSearch(eCtx engine.Context) []types.ArchetypeID {
 		cache.archetypes = append(cache.archetypes, it.Next())
 	}
 	cache.seen = eCtx.StoreReader().ArchetypeCount()
+	if s.pattern == nil {
+		s.pattern = filter.ComponentNames(s.filter)
+	}
+	eCtx.RecordQueryPattern(s.pattern)
 	return cache.archetypes
 }
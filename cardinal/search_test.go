@@ -1,6 +1,7 @@
 package cardinal_test
 
 import (
+	"errors"
 	"testing"
 
 	"pkg.world.dev/world-engine/assert"
@@ -499,3 +500,154 @@ func TestWhereClauseOnSearch(t *testing.T) {
 	assert.NilError(t, err)
 	assert.Equal(t, amt, 40)
 }
+
+func TestSearchFirstStopsAtFirstMatch(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil)
+	world := tf.World
+	assert.NilError(t, cardinal.RegisterComponent[AlphaTest](world))
+	assert.NilError(t, cardinal.RegisterComponent[BetaTest](world))
+
+	tf.StartWorld()
+
+	worldCtx := cardinal.NewWorldContext(world)
+	_, err := cardinal.CreateMany(worldCtx, 10, AlphaTest{})
+	assert.NilError(t, err)
+	_, err = cardinal.CreateMany(worldCtx, 10, AlphaTest{}, BetaTest{})
+	assert.NilError(t, err)
+
+	amt, err := cardinal.NewSearch().Entity(filter.Contains(filter.Component[AlphaTest]())).Count(worldCtx)
+	assert.NilError(t, err)
+	assert.Equal(t, amt, 20)
+
+	visited := 0
+	_, err = cardinal.NewSearch().Entity(filter.Contains(filter.Component[AlphaTest]())).
+		Where(func(_ engine.Context, _ types.EntityID) (bool, error) {
+			visited++
+			return true, nil
+		}).First(worldCtx)
+	assert.NilError(t, err)
+	assert.Equal(t, visited, 1)
+}
+
+type Waypoints struct {
+	X    int
+	Y    int
+	Path []int
+}
+
+func (Waypoints) Name() string {
+	return "waypoints"
+}
+
+func TestSearchWithFieldProjection(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil)
+	world := tf.World
+	assert.NilError(t, cardinal.RegisterComponent[Waypoints](world))
+	tf.StartWorld()
+
+	worldCtx := cardinal.NewWorldContext(world)
+	near, err := cardinal.Create(worldCtx, Waypoints{X: 1, Y: 1, Path: []int{1, 2, 3}})
+	assert.NilError(t, err)
+	_, err = cardinal.Create(worldCtx, Waypoints{X: 50, Y: 50, Path: []int{4, 5, 6}})
+	assert.NilError(t, err)
+	tf.DoTick()
+
+	// Only the selected fields are decoded.
+	ids, err := cardinal.NewSearch().Entity(filter.Contains(filter.Component[Waypoints]())).
+		Where(cardinal.FilterFieldsFunction[Waypoints]([]string{"X", "Y"}, func(w Waypoints) bool {
+			assert.Check(t, w.Path == nil)
+			return w.X < 10 && w.Y < 10
+		})).Collect(worldCtx)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []types.EntityID{near}, ids)
+
+	waypoints, err := cardinal.GetComponentFields[Waypoints](worldCtx, near, "X")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, Waypoints{X: 1}, *waypoints)
+
+	// A component that was already decoded in this tick is returned whole.
+	_, err = cardinal.GetComponent[Waypoints](worldCtx, near)
+	assert.NilError(t, err)
+	waypoints, err = cardinal.GetComponentFields[Waypoints](worldCtx, near, "X")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []int{1, 2, 3}, waypoints.Path)
+}
+
+func TestUpdateComponentsOfSearchResults(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil)
+	world := tf.World
+	assert.NilError(t, cardinal.RegisterComponent[Health](world))
+	assert.NilError(t, cardinal.RegisterComponent[AlphaTest](world))
+	tf.StartWorld()
+
+	wCtx := cardinal.NewWorldContext(world)
+	healthOnly, err := cardinal.CreateMany(wCtx, 3, Health{Value: 10})
+	assert.NilError(t, err)
+	withAlpha, err := cardinal.CreateMany(wCtx, 2, Health{Value: 20}, AlphaTest{})
+	assert.NilError(t, err)
+	tf.DoTick()
+
+	healthIs := func(ids []types.EntityID, want int) {
+		for _, id := range ids {
+			health, err := cardinal.GetComponent[Health](wCtx, id)
+			assert.NilError(t, err)
+			assert.Equal(t, want, health.Value)
+		}
+	}
+	hasHealth := cardinal.NewSearch().Entity(filter.Contains(filter.Component[Health]()))
+	visited := 0
+	assert.NilError(t, cardinal.Update[Health](wCtx, hasHealth, func(_ types.EntityID, health *Health) error {
+		visited++
+		health.Value--
+		return nil
+	}))
+	assert.Equal(t, 5, visited)
+	healthIs(healthOnly, 9)
+	healthIs(withAlpha, 19)
+
+	// Where clauses filter the updated entities.
+	fullHealth := hasHealth.Where(cardinal.FilterFunction[Health](func(health Health) bool {
+		return health.Value > 10
+	}))
+	assert.NilError(t, cardinal.Update2[Health, AlphaTest](wCtx, fullHealth,
+		func(_ types.EntityID, health *Health, alpha *AlphaTest) error {
+			health.Value = 0
+			alpha.Name1 = "updated"
+			return nil
+		}))
+	healthIs(healthOnly, 9)
+	healthIs(withAlpha, 0)
+	alpha, err := cardinal.GetComponent[AlphaTest](wCtx, withAlpha[1])
+	assert.NilError(t, err)
+	assert.Equal(t, "updated", alpha.Name1)
+
+	// The update is stored when the tick is committed.
+	tf.DoTick()
+	healthIs(healthOnly, 9)
+	healthIs(withAlpha, 0)
+
+	// All matching entities must have the components.
+	err = cardinal.Update2[Health, AlphaTest](wCtx, hasHealth, func(types.EntityID, *Health, *AlphaTest) error {
+		return nil
+	})
+	assert.ErrorIs(t, err, cardinal.ErrComponentNotOnEntity)
+
+	// An error of fn stops the update, and the entities before it stay updated.
+	errStop := errors.New("stop")
+	visited = 0
+	err = cardinal.Update[Health](wCtx, hasHealth, func(_ types.EntityID, health *Health) error {
+		if visited++; visited == 2 {
+			return errStop
+		}
+		health.Value = 100
+		return nil
+	})
+	assert.ErrorIs(t, err, errStop)
+	assert.Equal(t, 2, visited)
+	healthIs(healthOnly[:1], 100)
+	healthIs(healthOnly[1:], 9)
+
+	err = cardinal.Update[Health](cardinal.NewReadOnlyWorldContext(world), hasHealth,
+		func(types.EntityID, *Health) error { return nil })
+	assert.ErrorIs(t, err, cardinal.ErrEntityMutationOnReadOnly)
+}
@@ -0,0 +1,199 @@
+package server
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rotisserie/eris"
+)
+
+const (
+	// APIKeyHeader is the header in which server-to-server clients send their API key.
+	APIKeyHeader = "Cardinal-API-Key"
+	// AccessTokenParam is the query parameter in which clients that can't set headers, e.g. browsers opening the
+	// events websocket, send their API key or JWT instead.
+	AccessTokenParam = "access_token"
+)
+
+// DefaultPublicRoutes are the routes that are served without authentication if Auth.PublicRoutes is nil. They only
+// describe the server, so that load balancers and clients can check it before they authenticate.
+var DefaultPublicRoutes = []string{"/health", "/versions", "/world", "/swagger/*"}
+
+var (
+	errMissingCredentials = eris.New("missing API key or bearer token")
+	errInvalidAPIKey      = eris.New("invalid API key")
+	errInvalidToken       = eris.New("invalid bearer token")
+)
+
+// Auth configures the authentication of the requests to the HTTP server. A request to a route that is not public must
+// carry either one of the API keys in APIKeyHeader, or a JWT that is valid for JWT in an "Authorization: Bearer"
+// header. Requests that don't are rejected with 401 Unauthorized.
+type Auth struct {
+	// APIKeys are the keys of the server-to-server clients, e.g. Nakama and indexers.
+	APIKeys []string
+	// JWT validates the tokens of the game clients that access the server directly. Nil disables JWTs.
+	JWT *JWTAuth
+	// PublicRoutes are the paths of the routes that are served without authentication, without their version prefix.
+	// A path that ends with "*" matches every path with that prefix. Nil means DefaultPublicRoutes, an empty slice
+	// makes every route private.
+	PublicRoutes []string
+}
+
+// JWTAuth validates JWTs that are signed with HMAC-SHA256 (HS256), e.g. by the authentication service of the game.
+// The token must not be expired, and its "iss" and "aud" claims must match Issuer and Audience if they are set.
+type JWTAuth struct {
+	Secret   []byte
+	Issuer   string
+	Audience string
+	// Leeway is the clock skew that is tolerated when checking the "exp" and "nbf" claims.
+	Leeway time.Duration
+}
+
+// WithAuth requires the requests to the routes that are not public to be authenticated. See Auth.
+func WithAuth(auth Auth) Option {
+	return func(s *Server) {
+		if auth.PublicRoutes == nil {
+			auth.PublicRoutes = DefaultPublicRoutes
+		}
+		// An empty key would authenticate the requests without one
+		auth.APIKeys = slices.DeleteFunc(slices.Clone(auth.APIKeys), func(key string) bool { return key == "" })
+		s.config.auth = &auth
+	}
+}
+
+// authenticate rejects the requests to private routes that carry neither a valid API key nor a valid JWT.
+func (s *Server) authenticate(ctx *fiber.Ctx) error {
+	auth := s.config.auth
+	if auth.isPublic(ctx.Path()) {
+		return ctx.Next()
+	}
+	if err := auth.check(ctx); err != nil {
+		ctx.Set(fiber.HeaderWWWAuthenticate, `Bearer realm="cardinal"`)
+		return fiber.NewError(http.StatusUnauthorized, err.Error())
+	}
+	return ctx.Next()
+}
+
+// isPublic returns true if the route at path is served without authentication.
+func (a *Auth) isPublic(path string) bool {
+	for _, version := range []string{APIVersionV1, APIVersionV2} {
+		if rest, ok := strings.CutPrefix(path, "/"+version+"/"); ok {
+			path = "/" + rest
+			break
+		}
+	}
+	return slices.ContainsFunc(a.PublicRoutes, func(route string) bool {
+		if prefix, ok := strings.CutSuffix(route, "*"); ok {
+			return strings.HasPrefix(path, prefix)
+		}
+		return path == route
+	})
+}
+
+// check checks the credentials of a request.
+func (a *Auth) check(ctx *fiber.Ctx) error {
+	key := ctx.Get(APIKeyHeader)
+	token, hasToken := strings.CutPrefix(ctx.Get(fiber.HeaderAuthorization), "Bearer ")
+	if key == "" && !hasToken {
+		// Websocket clients send either kind of credential as a query parameter
+		if param := ctx.Query(AccessTokenParam); param != "" {
+			if a.isAPIKey(param) {
+				return nil
+			}
+			token, hasToken = param, true
+		}
+	}
+	switch {
+	case key != "":
+		if !a.isAPIKey(key) {
+			return errInvalidAPIKey
+		}
+		return nil
+	case hasToken && a.JWT != nil:
+		return a.JWT.validate(token, time.Now())
+	case hasToken:
+		return errInvalidToken
+	default:
+		return errMissingCredentials
+	}
+}
+
+func (a *Auth) isAPIKey(key string) bool {
+	return slices.ContainsFunc(a.APIKeys, func(apiKey string) bool {
+		return subtle.ConstantTimeCompare([]byte(apiKey), []byte(key)) == 1
+	})
+}
+
+// jwtClaims are the registered claims of a JWT that are validated. The audience can be a string or an array.
+type jwtClaims struct {
+	Issuer    string          `json:"iss"`
+	Audience  json.RawMessage `json:"aud"`
+	ExpiresAt *int64          `json:"exp"`
+	NotBefore *int64          `json:"nbf"`
+}
+
+// validate checks the signature and the claims of a JWT at the given time.
+func (j *JWTAuth) validate(token string, now time.Time) error {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 { //nolint:gomnd // header, payload and signature
+		return errInvalidToken
+	}
+	var header struct {
+		Alg string `json:"alg"`
+	}
+	if err := decodeJWTPart(parts[0], &header); err != nil || header.Alg != "HS256" {
+		return eris.Wrap(errInvalidToken, "the token must be signed with HS256")
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return errInvalidToken
+	}
+	mac := hmac.New(sha256.New, j.Secret)
+	mac.Write([]byte(parts[0] + "." + parts[1]))
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return eris.Wrap(errInvalidToken, "invalid signature")
+	}
+
+	var claims jwtClaims
+	if err = decodeJWTPart(parts[1], &claims); err != nil {
+		return errInvalidToken
+	}
+	if claims.ExpiresAt == nil || now.After(time.Unix(*claims.ExpiresAt, 0).Add(j.Leeway)) {
+		return eris.Wrap(errInvalidToken, "the token is expired")
+	}
+	if claims.NotBefore != nil && now.Add(j.Leeway).Before(time.Unix(*claims.NotBefore, 0)) {
+		return eris.Wrap(errInvalidToken, "the token is not valid yet")
+	}
+	if j.Issuer != "" && claims.Issuer != j.Issuer {
+		return eris.Wrap(errInvalidToken, "unexpected issuer")
+	}
+	if j.Audience != "" && !claims.hasAudience(j.Audience) {
+		return eris.Wrap(errInvalidToken, "unexpected audience")
+	}
+	return nil
+}
+
+func (c *jwtClaims) hasAudience(audience string) bool {
+	var single string
+	if json.Unmarshal(c.Audience, &single) == nil {
+		return single == audience
+	}
+	var many []string
+	return json.Unmarshal(c.Audience, &many) == nil && slices.Contains(many, audience)
+}
+
+func decodeJWTPart(part string, v any) error {
+	bz, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		return eris.Wrap(err, "")
+	}
+	return eris.Wrap(json.Unmarshal(bz, v), "")
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 
 	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/gamestate"
 	"pkg.world.dev/world-engine/cardinal/server/handler"
 )
 
@@ -58,3 +59,75 @@ func (s *ServerTestSuite) TestDebugStateQuery_NoState() {
 
 	s.Require().Equal(len(results), 0)
 }
+
+func (s *ServerTestSuite) TestDebugArchetypes() {
+	s.setupWorld()
+	s.fixture.DoTick()
+	const wantLocations = 7
+
+	wCtx := cardinal.NewWorldContext(s.world)
+	_, err := cardinal.CreateMany(wCtx, wantLocations, LocationComponent{X: 10, Y: 20})
+	s.Require().NoError(err)
+	s.fixture.DoTick()
+
+	res := s.fixture.Get("debug/archetypes")
+	s.Require().Equal(res.StatusCode, 200)
+	var results handler.DebugArchetypesResponse
+	s.Require().NoError(json.NewDecoder(res.Body).Decode(&results))
+
+	found := false
+	for _, stat := range results {
+		if len(stat.Components) != 1 || stat.Components[0] != "location" {
+			continue
+		}
+		found = true
+		s.Require().Equal(wantLocations, stat.EntityCount)
+		bz, err := json.Marshal(LocationComponent{X: 10, Y: 20})
+		s.Require().NoError(err)
+		s.Require().Equal(int64(wantLocations*len(bz)), stat.ApproxBytes)
+	}
+	s.Require().True(found)
+}
+
+func (s *ServerTestSuite) TestDebugLayout() {
+	s.setupWorld(cardinal.WithLayoutProfiling())
+	s.fixture.DoTick()
+	const wantLocations = 3
+
+	wCtx := cardinal.NewWorldContext(s.world)
+	_, err := cardinal.CreateMany(wCtx, wantLocations, LocationComponent{})
+	s.Require().NoError(err)
+	s.fixture.DoTick()
+
+	res := s.fixture.Get("debug/layout")
+	s.Require().Equal(res.StatusCode, 200)
+	var report gamestate.LayoutReport
+	s.Require().NoError(json.NewDecoder(res.Body).Decode(&report))
+	found := false
+	for _, c := range report.Components {
+		if c.Name == "location" {
+			found = true
+			s.Require().Equal(wantLocations, c.Entities)
+			s.Require().Equal(0, c.Searches)
+		}
+	}
+	s.Require().True(found)
+	s.Require().Contains(report.Unqueried, "location")
+}
+
+func (s *ServerTestSuite) TestDebugSystems() {
+	s.setupWorld()
+	s.fixture.DoTick()
+
+	res := s.fixture.Get("debug/systems")
+	s.Require().Equal(res.StatusCode, 200)
+	var results handler.DebugSystemsResponse
+	s.Require().NoError(json.NewDecoder(res.Body).Decode(&results))
+
+	s.Require().NotEmpty(results)
+	for _, system := range results {
+		s.Require().NotEmpty(system.Name)
+		s.Require().Equal(uint64(1), system.Every)
+		s.Require().False(system.Disabled)
+	}
+}
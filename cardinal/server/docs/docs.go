@@ -17,7 +17,7 @@ const docTemplate = `{
     "paths": {
         "/cql": {
             "post": {
-                "description": "Executes a CQL (Cardinal Query Language) query",
+                "description": "Executes a CQL (Cardinal Query Language) query. Comparisons of component fields in a WHERE clause\nare only allowed if CQL filters are enabled.",
                 "consumes": [
                     "application/json"
                 ],
@@ -48,17 +48,91 @@ const docTemplate = `{
                         "schema": {
                             "type": "string"
                         }
+                    },
+                    "403": {
+                        "description": "CQL filters are not enabled",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/debug/archetypes": {
+            "get": {
+                "description": "Retrieves the components, the number of entities and the approximate size in bytes of every archetype\nin the game state",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Retrieves the stats of every archetype in the game state",
+                "responses": {
+                    "200": {
+                        "description": "List of all archetypes",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/gamestate.ArchetypeStat"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/debug/config": {
+            "get": {
+                "description": "Retrieves the config values of the world keyed by their environment variable, with secrets redacted",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Retrieves the config of the world",
+                "responses": {
+                    "200": {
+                        "description": "Config of the world",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {}
+                        }
+                    }
+                }
+            }
+        },
+        "/debug/layout": {
+            "get": {
+                "description": "Retrieves how the entities are spread over archetypes, the query patterns of systems and the\nsuggested grouping of components. Query patterns are only recorded if layout profiling is enabled",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Retrieves the archetype layout of the game state",
+                "responses": {
+                    "200": {
+                        "description": "Layout of the game state",
+                        "schema": {
+                            "$ref": "#/definitions/gamestate.LayoutReport"
+                        }
                     }
                 }
             }
         },
         "/debug/state": {
             "post": {
-                "description": "Retrieves a list of all entities in the game state",
+                "description": "Retrieves a list of all entities in the game state\nThe list is truncated to the reply limits of the server, in which case the Total-Count and Next-Cursor\nheaders are set",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "summary": "Retrieves a list of all entities in the game state",
+                "parameters": [
+                    {
+                        "description": "Cursor of a truncated reply",
+                        "name": "DebugStateRequest",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/handler.DebugStateRequest"
+                        }
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "List of all entities",
@@ -72,26 +146,78 @@ const docTemplate = `{
                 }
             }
         },
+        "/debug/systems": {
+            "get": {
+                "description": "Retrieves the systems of the world in the order in which they run, with the ticks that they run at and\nwhether they are disabled",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Retrieves the schedule of every system",
+                "responses": {
+                    "200": {
+                        "description": "List of all systems",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/types.SystemInfo"
+                            }
+                        }
+                    }
+                }
+            }
+        },
         "/events": {
             "get": {
-                "description": "Establishes a new websocket connection to retrieve system events",
+                "description": "With from_tick, returns the recorded events of the ticks from from_tick onward, so that clients that\nreconnect can catch up on the events they missed. Without it, the request must be a websocket upgrade.",
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Establishes a new websocket connection to retrieve system events",
+                "summary": "Retrieves the events of past ticks, or establishes a websocket connection to retrieve new events",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tick to read the event history from",
+                        "name": "from_tick",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "101": {
                         "description": "Switch protocol to ws",
                         "schema": {
                             "type": "string"
                         }
+                    },
+                    "200": {
+                        "description": "Events of the ticks from from_tick onward",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ListEventsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid from_tick",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Event history is not enabled",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "410": {
+                        "description": "Events of from_tick are no longer available",
+                        "schema": {
+                            "type": "string"
+                        }
                     }
                 }
             }
         },
         "/health": {
             "get": {
-                "description": "Retrieves the status of the server and game loop",
+                "description": "Retrieves the status of the server and game loop. The status is 503 while the world doesn't tick\nbecause its storage is unavailable",
                 "produces": [
                     "application/json"
                 ],
@@ -102,6 +228,12 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/handler.GetHealthResponse"
                         }
+                    },
+                    "503": {
+                        "description": "Storage outage",
+                        "schema": {
+                            "$ref": "#/definitions/handler.GetHealthResponse"
+                        }
                     }
                 }
             }
@@ -238,6 +370,210 @@ const docTemplate = `{
                 }
             }
         },
+        "/state/commitments": {
+            "get": {
+                "description": "Retrieves the merkle roots of the game state that were submitted to the base shard, for the ranges of\nticks that proofs can still be generated for",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Retrieves the state commitments of the game state",
+                "responses": {
+                    "200": {
+                        "description": "List of state commitments, sorted by tick",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/gamestate.StateCommitment"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/state/diff": {
+            "post": {
+                "description": "Returns the entities that were created and destroyed, and the component values that changed, in the\nticks from fromTick up to, but not including, toTick",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Retrieves the changes to the game state between two ticks",
+                "parameters": [
+                    {
+                        "description": "Range of ticks to diff",
+                        "name": "stateDiff",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.StateDiffRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Created and destroyed entities and changed component values",
+                        "schema": {
+                            "$ref": "#/definitions/gamestate.StateDiff"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body or range of ticks",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "The diff of a tick of the range is not available",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/state/proof": {
+            "post": {
+                "description": "Returns a merkle proof that the entity had the component value at the end of the range of ticks of\nthe state commitment that contains the given tick, which can be verified against the state root of\nthe range on the base shard",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Proves the value of a component of an entity",
+                "parameters": [
+                    {
+                        "description": "Tick, entity and component to prove",
+                        "name": "stateProof",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.StateProofRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Leaf, state commitment and merkle proof",
+                        "schema": {
+                            "$ref": "#/definitions/gamestate.StateProof"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body or missing component",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "No state commitment or component value to prove",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/state/stream": {
+            "get": {
+                "description": "Sends a snapshot of the entities in the filter, followed by the change that every tick makes to them,\nso that spectators and late joiners can sync without pausing the world. The world must record state\ndiffs. The messages are StateSnapshotMessage, StateDiffMessage and StateStreamErrorMessage, which is\nsent before the server closes the stream, e.g. when the spectator fell too far behind.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Streams the game state to spectators over a websocket connection",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Comma separated names of the components the entities must have and that are sent",
+                        "name": "components",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated ranges of fields, e.g. position.x:0:100,position.y:0:100",
+                        "name": "region",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "101": {
+                        "description": "Switch protocol to ws",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid filter",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "State diffs are not recorded",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "426": {
+                        "description": "Not a websocket upgrade",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/tx/cosign": {
+            "post": {
+                "description": "Adds the signature of a co-signer to a transaction that is waiting for its co-signers",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Co-signs a transaction",
+                "parameters": [
+                    {
+                        "description": "Hash of the transaction and signature of the co-signer",
+                        "name": "coSign",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.CoSignRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Transaction hash and tick, and whether it is still pending",
+                        "schema": {
+                            "$ref": "#/definitions/handler.PostTransactionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request parameter or expired transaction",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Persona tag of a signer is banned",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Transaction is not waiting for co-signatures",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
         "/tx/game/{txName}": {
             "post": {
                 "description": "Submits a transaction",
@@ -278,6 +614,12 @@ const docTemplate = `{
                         "schema": {
                             "type": "string"
                         }
+                    },
+                    "503": {
+                        "description": "The world is overloaded, retry after the Retry-After header",
+                        "schema": {
+                            "$ref": "#/definitions/types.RetryAfter"
+                        }
                     }
                 }
             }
@@ -315,20 +657,26 @@ const docTemplate = `{
                         "schema": {
                             "type": "string"
                         }
+                    },
+                    "503": {
+                        "description": "The world is overloaded, retry after the Retry-After header",
+                        "schema": {
+                            "$ref": "#/definitions/types.RetryAfter"
+                        }
                     }
                 }
             }
         },
-        "/tx/{txGroup}/{txName}": {
+        "/tx/simulate/{txGroup}/{txName}": {
             "post": {
-                "description": "Submits a transaction",
+                "description": "Runs the next tick with the transaction against the current game state, and returns the receipt and\nthe events that the transaction would have, without changing the game state. The signature of the\ntransaction is not verified and its nonce is not used, so the same transaction can be submitted\nafterward.",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Submits a transaction",
+                "summary": "Simulates a transaction",
                 "parameters": [
                     {
                         "type": "string",
@@ -345,7 +693,7 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Transaction details \u0026 message to be submitted",
+                        "description": "Transaction details \u0026 message to be simulated",
                         "name": "txBody",
                         "in": "body",
                         "required": true,
@@ -356,9 +704,9 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Transaction hash and tick",
+                        "description": "Receipt and events of the simulated transaction",
                         "schema": {
-                            "$ref": "#/definitions/handler.PostTransactionResponse"
+                            "$ref": "#/definitions/handler.SimulateTransactionResponse"
                         }
                     },
                     "400": {
@@ -366,6 +714,98 @@ const docTemplate = `{
                         "schema": {
                             "type": "string"
                         }
+                    },
+                    "403": {
+                        "description": "Not authorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Message type not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/tx/{txGroup}/{txName}": {
+            "post": {
+                "description": "Submits a transaction",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Submits a transaction",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Message group",
+                        "name": "txGroup",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of a registered message",
+                        "name": "txName",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Transaction details \u0026 message to be submitted",
+                        "name": "txBody",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.Transaction"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Transaction hash and tick",
+                        "schema": {
+                            "$ref": "#/definitions/handler.PostTransactionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request parameter",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Persona tag is banned, admin message not signed by an admin, or not authorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "The world is overloaded, retry after the Retry-After header",
+                        "schema": {
+                            "$ref": "#/definitions/types.RetryAfter"
+                        }
+                    }
+                }
+            }
+        },
+        "/versions": {
+            "get": {
+                "description": "Retrieves the versions of the API that are served, their deprecation and sunset times, and the\nschema hashes of the registered messages, so that clients can check that they are compatible",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Retrieves the supported versions of the API",
+                "responses": {
+                    "200": {
+                        "description": "Supported versions and message schema hashes",
+                        "schema": {
+                            "$ref": "#/definitions/handler.GetVersionsResponse"
+                        }
                     }
                 }
             }
@@ -398,11 +838,257 @@ const docTemplate = `{
         }
     },
     "definitions": {
+        "gamestate.ArchetypeStat": {
+            "type": "object",
+            "properties": {
+                "approxBytes": {
+                    "description": "ApproxBytes is the approximate size of the component values of all the entities of the archetype. It is\nextrapolated from the JSON encoded components of up to ArchetypeStatsSampleSize entities, so it is neither the\nsize in storage of components that use another codec, nor the memory that the storage uses for its keys.",
+                    "type": "integer"
+                },
+                "components": {
+                    "description": "Components are the names of the components of the archetype, in component ID order.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "entityCount": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "gamestate.ComponentChange": {
+            "type": "object",
+            "properties": {
+                "component": {
+                    "type": "string"
+                },
+                "entity": {
+                    "type": "integer"
+                },
+                "new": {
+                    "description": "New is the value after the change, encoded as JSON, or null if the entity no longer has the component.",
+                    "type": "object"
+                },
+                "old": {
+                    "description": "Old is the value before the change, encoded as JSON, or null if the entity didn't have the component.",
+                    "type": "object"
+                }
+            }
+        },
+        "gamestate.ComponentGroup": {
+            "type": "object",
+            "properties": {
+                "components": {
+                    "description": "Components are in name order.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "searches": {
+                    "description": "Searches is the number of recorded searches that filter on the components of the group.",
+                    "type": "integer"
+                }
+            }
+        },
+        "gamestate.ComponentLayout": {
+            "type": "object",
+            "properties": {
+                "archetypes": {
+                    "description": "Archetypes is the number of archetypes with entities that have the component. An entity is stored with all of\nits components, so a component that is spread over many archetypes is read from many places by a search.",
+                    "type": "integer"
+                },
+                "entities": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "searches": {
+                    "description": "Searches is the number of recorded searches that filter on the component.",
+                    "type": "integer"
+                }
+            }
+        },
+        "gamestate.LayoutReport": {
+            "type": "object",
+            "properties": {
+                "archetypes": {
+                    "type": "integer"
+                },
+                "components": {
+                    "description": "Components are ordered by the number of archetypes they are spread over, the most fragmented first.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/gamestate.ComponentLayout"
+                    }
+                },
+                "entities": {
+                    "type": "integer"
+                },
+                "groups": {
+                    "description": "Groups are the suggested grouping of the components that are filtered on, ordered by the number of searches.\nEach group is the set of components that appear in exactly the same query patterns.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/gamestate.ComponentGroup"
+                    }
+                },
+                "meanComponents": {
+                    "description": "MeanComponents is the mean number of components of an entity.",
+                    "type": "number"
+                },
+                "patterns": {
+                    "description": "Patterns are the recorded query patterns, ordered by the number of searches.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/gamestate.QueryPattern"
+                    }
+                },
+                "unqueried": {
+                    "description": "Unqueried are the components of entities that no recorded search filters on. Moving them out of wide entities\nmakes the archetypes that the searches match narrower.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "wideArchetypes": {
+                    "description": "WideArchetypes are the archetypes with at least WideArchetypeComponents components, the widest first.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/gamestate.ArchetypeStat"
+                    }
+                }
+            }
+        },
+        "gamestate.QueryPattern": {
+            "type": "object",
+            "properties": {
+                "archetypes": {
+                    "description": "Archetypes is the number of archetypes with entities that have all the components of the pattern, and Entities is\nthe number of their entities. It is filled in by AnalyzeLayout.",
+                    "type": "integer"
+                },
+                "components": {
+                    "description": "Components are the names of the components that an archetype must have to match the searches, in name order.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "entities": {
+                    "type": "integer"
+                },
+                "searches": {
+                    "description": "Searches is the number of times that searches with the pattern were evaluated.",
+                    "type": "integer"
+                },
+                "system": {
+                    "type": "string"
+                }
+            }
+        },
+        "gamestate.StateCommitment": {
+            "type": "object",
+            "properties": {
+                "endTick": {
+                    "type": "integer"
+                },
+                "root": {
+                    "description": "Root is the hex encoded merkle root.",
+                    "type": "string"
+                },
+                "startTick": {
+                    "type": "integer"
+                }
+            }
+        },
+        "gamestate.StateDiff": {
+            "type": "object",
+            "properties": {
+                "changed": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/gamestate.ComponentChange"
+                    }
+                },
+                "created": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "destroyed": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "fromTick": {
+                    "type": "integer"
+                },
+                "toTick": {
+                    "type": "integer"
+                }
+            }
+        },
+        "gamestate.StateLeaf": {
+            "type": "object",
+            "properties": {
+                "component": {
+                    "type": "string"
+                },
+                "entity": {
+                    "type": "integer"
+                },
+                "value": {
+                    "description": "Value is the component value, encoded as JSON.",
+                    "type": "object"
+                }
+            }
+        },
+        "gamestate.StateProof": {
+            "type": "object",
+            "properties": {
+                "commitment": {
+                    "$ref": "#/definitions/gamestate.StateCommitment"
+                },
+                "leaf": {
+                    "$ref": "#/definitions/gamestate.StateLeaf"
+                },
+                "proof": {
+                    "$ref": "#/definitions/merkle.Proof"
+                }
+            }
+        },
+        "handler.APIVersionInfo": {
+            "type": "object",
+            "properties": {
+                "deprecated": {
+                    "type": "string"
+                },
+                "successor": {
+                    "type": "string"
+                },
+                "sunset": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "string"
+                }
+            }
+        },
         "handler.CQLQueryRequest": {
             "type": "object",
             "properties": {
                 "cql": {
                     "type": "string"
+                },
+                "cursor": {
+                    "description": "Cursor is the NextCursor of a truncated reply, to read the results that did not fit in it.",
+                    "type": "string"
                 }
             }
         },
@@ -414,6 +1100,39 @@ const docTemplate = `{
                     "items": {
                         "$ref": "#/definitions/handler.cqlData"
                     }
+                },
+                "truncation": {
+                    "description": "Truncation is set if not all results fit in the reply.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/handler.Truncation"
+                        }
+                    ]
+                }
+            }
+        },
+        "handler.CoSignRequest": {
+            "type": "object",
+            "properties": {
+                "personaTag": {
+                    "type": "string"
+                },
+                "signature": {
+                    "description": "Signature is the hex encoded signature of the hash by the signer of the persona.",
+                    "type": "string"
+                },
+                "txHash": {
+                    "description": "TxHash is the hash of the co-signed transaction, as returned when it was submitted.",
+                    "type": "string"
+                }
+            }
+        },
+        "handler.DebugStateRequest": {
+            "type": "object",
+            "properties": {
+                "cursor": {
+                    "description": "Cursor is the value of the Next-Cursor header of a truncated reply, to read the entities that did not fit in it.",
+                    "type": "string"
                 }
             }
         },
@@ -429,6 +1148,11 @@ const docTemplate = `{
                     "description": "name of the message or query",
                     "type": "string"
                 },
+                "reply": {
+                    "description": "variable name and type of the query reply",
+                    "type": "object",
+                    "additionalProperties": {}
+                },
                 "url": {
                     "type": "string"
                 }
@@ -442,6 +1166,43 @@ const docTemplate = `{
                 },
                 "isServerRunning": {
                     "type": "boolean"
+                },
+                "storage": {
+                    "description": "Storage is the health of the storage, as seen by the storage circuit breaker of the world.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/types.StorageHealth"
+                        }
+                    ]
+                }
+            }
+        },
+        "handler.GetVersionsResponse": {
+            "type": "object",
+            "properties": {
+                "latest": {
+                    "description": "Latest is the newest version of the API.",
+                    "type": "string"
+                },
+                "messages": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.MessageSchema"
+                    }
+                },
+                "unversioned": {
+                    "description": "Unversioned describes the routes that are served without a version prefix. Its version is the version that\nthey are an alias of.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/handler.APIVersionInfo"
+                        }
+                    ]
+                },
+                "versions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.APIVersionInfo"
+                    }
                 }
             }
         },
@@ -472,6 +1233,23 @@ const docTemplate = `{
                 }
             }
         },
+        "handler.ListEventsResponse": {
+            "type": "object",
+            "properties": {
+                "endTick": {
+                    "type": "integer"
+                },
+                "startTick": {
+                    "type": "integer"
+                },
+                "ticks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.TickEvents"
+                    }
+                }
+            }
+        },
         "handler.ListTxReceiptsRequest": {
             "type": "object",
             "properties": {
@@ -494,12 +1272,39 @@ const docTemplate = `{
                 },
                 "startTick": {
                     "type": "integer"
+                },
+                "truncation": {
+                    "$ref": "#/definitions/handler.Truncation"
+                }
+            }
+        },
+        "handler.MessageSchema": {
+            "type": "object",
+            "properties": {
+                "legacySchemaHashes": {
+                    "description": "LegacySchemaHashes are the hashes of the legacy inputs that are accepted from clients of older API versions,\nkeyed by API version.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "description": "full name of the message",
+                    "type": "string"
+                },
+                "schemaHash": {
+                    "description": "SchemaHash is the hash of the fields of the message's input. It changes whenever the input's schema changes.",
+                    "type": "string"
                 }
             }
         },
         "handler.PostTransactionResponse": {
             "type": "object",
             "properties": {
+                "pending": {
+                    "description": "Pending is true if the transaction is waiting for the signatures of its co-signers. It is executed once they\nwere added with /tx/cosign.",
+                    "type": "boolean"
+                },
                 "tick": {
                     "type": "integer"
                 },
@@ -518,6 +1323,9 @@ const docTemplate = `{
                     }
                 },
                 "result": {},
+                "status": {
+                    "type": "string"
+                },
                 "tick": {
                     "type": "integer"
                 },
@@ -526,6 +1334,52 @@ const docTemplate = `{
                 }
             }
         },
+        "handler.SimulateTransactionResponse": {
+            "type": "object",
+            "properties": {
+                "events": {
+                    "description": "Events are the events that the tick would emit, in the order they would be emitted.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "receipt": {
+                    "description": "Receipt is the receipt that the transaction would have. Its tick is the tick that the transaction was simulated\nin.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/handler.ReceiptEntry"
+                        }
+                    ]
+                }
+            }
+        },
+        "handler.StateDiffRequest": {
+            "type": "object",
+            "properties": {
+                "fromTick": {
+                    "type": "integer"
+                },
+                "toTick": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handler.StateProofRequest": {
+            "type": "object",
+            "properties": {
+                "component": {
+                    "type": "string"
+                },
+                "entityId": {
+                    "type": "integer"
+                },
+                "tick": {
+                    "description": "Tick is any tick of the range of ticks of the state commitment to prove the value in.",
+                    "type": "integer"
+                }
+            }
+        },
         "handler.Transaction": {
             "type": "object",
             "properties": {
@@ -533,6 +1387,24 @@ const docTemplate = `{
                     "description": "json string",
                     "type": "object"
                 },
+                "coSignatures": {
+                    "description": "CoSignatures maps the co-signers that signed the transaction to their hex encoded signature of the hash. They\nare not part of the hash, so they can be collected after the transaction was signed. See CoSign.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "coSigners": {
+                    "description": "CoSigners are the other personas that must sign the transaction before it is executed, e.g. the other player of\na trade. They are part of the signed hash.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "expiresAtTick": {
+                    "description": "ExpiresAtTick is the tick from which the transaction is no longer executed, or 0 if it doesn't expire. A\ntransaction that is still waiting to be executed at this tick is dropped, so that a world that fell behind does\nnot act on stale input. It is part of the signed hash.",
+                    "type": "integer"
+                },
                 "hash": {
                     "type": "string"
                 },
@@ -551,6 +1423,23 @@ const docTemplate = `{
                 }
             }
         },
+        "handler.Truncation": {
+            "type": "object",
+            "properties": {
+                "nextCursor": {
+                    "description": "NextCursor is the cursor of the first item that was not returned.",
+                    "type": "string"
+                },
+                "returned": {
+                    "description": "Returned is the number of items in this reply.",
+                    "type": "integer"
+                },
+                "total": {
+                    "description": "Total is the number of items in the whole list.",
+                    "type": "integer"
+                }
+            }
+        },
         "handler.cqlData": {
             "type": "object",
             "properties": {
@@ -572,6 +1461,153 @@ const docTemplate = `{
                     "type": "integer"
                 }
             }
+        },
+        "merkle.Proof": {
+            "type": "object",
+            "properties": {
+                "index": {
+                    "description": "Index is the position of the leaf in the tree.",
+                    "type": "integer"
+                },
+                "path": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "size": {
+                    "description": "Size is the number of leaves in the tree.",
+                    "type": "integer"
+                }
+            }
+        },
+        "time.Duration": {
+            "type": "integer",
+            "format": "int64",
+            "enum": [
+                -9223372036854775808,
+                9223372036854775807,
+                1,
+                1000,
+                1000000,
+                1000000000,
+                60000000000,
+                3600000000000
+            ],
+            "x-enum-varnames": [
+                "minDuration",
+                "maxDuration",
+                "Nanosecond",
+                "Microsecond",
+                "Millisecond",
+                "Second",
+                "Minute",
+                "Hour"
+            ]
+        },
+        "types.RetryAfter": {
+            "type": "object",
+            "properties": {
+                "maxQueuedTxs": {
+                    "type": "integer"
+                },
+                "maxTickLagNs": {
+                    "$ref": "#/definitions/time.Duration"
+                },
+                "queueFill": {
+                    "type": "number"
+                },
+                "queuedTxs": {
+                    "description": "QueuedTxs is the number of transactions waiting for the next tick, and MaxQueuedTxs is the number at which new\ntransactions are rejected. QueueFill is QueuedTxs divided by MaxQueuedTxs. MaxQueuedTxs and QueueFill are 0 if\nthe queue is not limited.",
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "retryAfterNs": {
+                    "description": "After is how long to wait before submitting the transaction again.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/time.Duration"
+                        }
+                    ]
+                },
+                "tickLagNs": {
+                    "description": "TickLag is how far the ticks are behind their schedule, and MaxTickLag is the lag at which new transactions are\nrejected. MaxTickLag is 0 if the lag is not limited.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/time.Duration"
+                        }
+                    ]
+                }
+            }
+        },
+        "types.StorageHealth": {
+            "type": "object",
+            "properties": {
+                "consecutiveErrors": {
+                    "description": "ConsecutiveErrors is the number of consecutive ticks that failed with a storage error, and LastError the error of\nthe last one.",
+                    "type": "integer"
+                },
+                "lastError": {
+                    "type": "string"
+                },
+                "nextRetry": {
+                    "type": "string"
+                },
+                "outage": {
+                    "description": "Outage is true while the breaker is open, i.e. while the world doesn't tick because its storage failed.",
+                    "type": "boolean"
+                },
+                "outageSince": {
+                    "description": "OutageSince is the time at which the breaker opened, and NextRetry the time at which the failed tick is run again.\nBoth are zero unless Outage is true.",
+                    "type": "string"
+                }
+            }
+        },
+        "types.SystemInfo": {
+            "type": "object",
+            "properties": {
+                "disabled": {
+                    "description": "Disabled is true for systems that are skipped until they are enabled again.",
+                    "type": "boolean"
+                },
+                "every": {
+                    "description": "Every and Offset are the schedule of the system: it runs at the ticks whose remainder when divided by Every is\nOffset. Systems that run at every tick have an Every of 1.",
+                    "type": "integer"
+                },
+                "init": {
+                    "description": "Init is true for systems that only run at tick 0.",
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "offset": {
+                    "type": "integer"
+                }
+            }
+        },
+        "types.TickEvents": {
+            "type": "object",
+            "properties": {
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "type": "array",
+                        "items": {
+                            "type": "integer"
+                        }
+                    }
+                },
+                "tick": {
+                    "type": "integer"
+                },
+                "timestamp": {
+                    "description": "Timestamp is the UNIX timestamp (in seconds) of the tick.",
+                    "type": "integer"
+                }
+            }
         }
     }
 }`
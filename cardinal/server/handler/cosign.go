@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/rotisserie/eris"
+
+	servertypes "pkg.world.dev/world-engine/cardinal/server/types"
+	"pkg.world.dev/world-engine/cardinal/types"
+)
+
+// CoSignRequest is the signature of a co-signer of a pending co-signed transaction.
+type CoSignRequest struct {
+	// TxHash is the hash of the co-signed transaction, as returned when it was submitted.
+	TxHash     string `json:"txHash"`
+	PersonaTag string `json:"personaTag"`
+	// Signature is the hex encoded signature of the hash by the signer of the persona.
+	Signature string `json:"signature"`
+}
+
+// PostCoSignature godoc
+//
+//	@Summary      Co-signs a transaction
+//	@Description  Adds the signature of a co-signer to a transaction that is waiting for its co-signers
+//	@Accept       application/json
+//	@Produce      application/json
+//	@Param        coSign  body      CoSignRequest            true  "Hash of the transaction and signature of the co-signer"
+//	@Success      200     {object}  PostTransactionResponse  "Transaction hash and tick, and whether it is still pending"
+//	@Failure      400     {string}  string                   "Invalid request parameter or expired transaction"
+//	@Failure      403     {string}  string                   "Persona tag of a signer is banned"
+//	@Failure      404     {string}  string                   "Transaction is not waiting for co-signatures"
+//	@Router       /tx/cosign [post]
+func PostCoSignature(provider servertypes.Provider, disableSigVerification bool) func(*fiber.Ctx) error {
+	return func(ctx *fiber.Ctx) error {
+		req := new(CoSignRequest)
+		if err := ctx.BodyParser(req); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "failed to parse request body: "+err.Error())
+		}
+		if req.TxHash == "" || req.PersonaTag == "" {
+			return fiber.NewError(fiber.StatusBadRequest, "txHash and personaTag are required")
+		}
+
+		txHash := types.TxHash(req.TxHash)
+		tx, ok, err := provider.GetPendingCoSignedTransaction(txHash)
+		if err != nil {
+			return fiber.NewError(fiber.StatusInternalServerError, "failed to get transaction: "+err.Error())
+		} else if !ok {
+			return fiber.NewError(fiber.StatusNotFound, "transaction is not waiting for co-signatures")
+		}
+		if tx.CoSignatures == nil {
+			tx.CoSignatures = map[string]string{}
+		}
+		tx.CoSignatures[req.PersonaTag] = req.Signature
+		if err := validateCoSignature(provider, disableSigVerification, tx, req.PersonaTag); err != nil {
+			return err
+		}
+
+		tick, pending, err := provider.AddCoSignature(ctx.UserContext(), txHash, req.PersonaTag, req.Signature)
+		switch {
+		case eris.Is(err, types.ErrCoSignedTransactionNotFound):
+			// The transaction timed out or was completed by another co-signature since it was looked up
+			return fiber.NewError(fiber.StatusNotFound, "failed to add co-signature: "+err.Error())
+		case eris.Is(err, types.ErrCoSignedTransactionExpired):
+			return fiber.NewError(fiber.StatusBadRequest, "failed to add co-signature: "+err.Error())
+		case eris.Is(err, types.ErrCoSignerBanned):
+			return fiber.NewError(fiber.StatusForbidden, "failed to add co-signature: "+err.Error())
+		case err != nil:
+			return fiber.NewError(fiber.StatusInternalServerError, "failed to add co-signature: "+err.Error())
+		}
+		return ctx.JSON(&PostTransactionResponse{
+			TxHash:  req.TxHash,
+			Tick:    tick,
+			Pending: pending,
+		})
+	}
+}
+
+// validateCoSignature validates that the co-signature of the given persona is signed by the signer of the persona.
+func validateCoSignature(
+	provider servertypes.Provider, disableSigVerification bool, tx *Transaction, personaTag string,
+) error {
+	if !tx.IsCoSigner(personaTag) {
+		return fiber.NewError(fiber.StatusBadRequest,
+			"persona tag "+personaTag+" is not a co-signer of the transaction")
+	}
+	banned, err := provider.IsPersonaBanned(personaTag)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "failed to check persona ban: "+err.Error())
+	} else if banned {
+		return fiber.NewError(fiber.StatusForbidden, "persona tag "+personaTag+" is banned")
+	}
+	if disableSigVerification {
+		return nil
+	}
+	signerAddress, err := provider.GetSignerForPersonaTag(personaTag, 0)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "could not get signer for co-signer: "+err.Error())
+	}
+	if err = tx.VerifyCoSignature(personaTag, signerAddress); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "failed to validate co-signature: "+err.Error())
+	}
+	return nil
+}
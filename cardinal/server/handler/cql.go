@@ -13,6 +13,8 @@ import (
 
 type CQLQueryRequest struct {
 	CQL string
+	// Cursor is the NextCursor of a truncated reply, to read the results that did not fit in it.
+	Cursor string
 }
 
 type cqlData struct {
@@ -22,24 +24,34 @@ type cqlData struct {
 
 type CQLQueryResponse struct {
 	Results []cqlData `json:"results"`
+	// Truncation is set if not all results fit in the reply.
+	Truncation *Truncation `json:"truncation,omitempty"`
 }
 
 // PostCQL godoc
 //
 //	@Summary      Executes a CQL (Cardinal Query Language) query
-//	@Description  Executes a CQL (Cardinal Query Language) query
+//	@Description  Executes a CQL (Cardinal Query Language) query. Comparisons of component fields in a WHERE clause
+//	@Description  are only allowed if CQL filters are enabled.
 //	@Accept       application/json
 //	@Produce      application/json
 //	@Param        cql  body      CQLQueryRequest   true  "CQL query to be executed"
 //	@Success      200  {object}  CQLQueryResponse  "Results of the executed CQL query"
 //	@Failure      400  {string}  string            "Invalid request parameters"
+//	@Failure      403  {string}  string            "CQL filters are not enabled"
 //	@Router       /cql [post]
-func PostCQL(provider servertypes.Provider) func(*fiber.Ctx) error { //nolint:gocognit // to refactor later
+func PostCQL( //nolint:gocognit // to refactor later
+	provider servertypes.Provider, limits ReplyLimits, filtersEnabled bool,
+) func(*fiber.Ctx) error {
 	return func(ctx *fiber.Ctx) error {
 		req := new(CQLQueryRequest)
 		if err := ctx.BodyParser(req); err != nil {
 			return err
 		}
+		page, err := newPager(limits, req.Cursor)
+		if err != nil {
+			return err
+		}
 
 		// getComponentByName is a wrapper function that casts component.ComponentMetadata from ctx.GetComponentByName
 		// to types.Component
@@ -51,16 +63,37 @@ func PostCQL(provider servertypes.Provider) func(*fiber.Ctx) error { //nolint:go
 			return comp, nil
 		}
 
-		// Parse the CQL string into a filter
-		resultFilter, err := cql.Parse(req.CQL, getComponentByName)
+		// Parse the CQL string into a query
+		query, err := cql.ParseQuery(req.CQL, getComponentByName)
 		if err != nil {
 			return fiber.NewError(fiber.StatusBadRequest, err.Error())
 		}
+		if query.HasWhere() && !filtersEnabled {
+			return fiber.NewError(fiber.StatusForbidden, "CQL filters on component fields are not enabled")
+		}
 
 		result := make([]cqlData, 0)
+		total := 0
 		var eachError error
-		searchErr := provider.Search(resultFilter).Each(provider.GetReadOnlyCtx(),
+		searchErr := provider.Search(query.Filter).Each(provider.GetReadOnlyCtx(),
 			func(id types.EntityID) bool {
+				if query.Limit > 0 && total >= query.Limit {
+					return false
+				}
+				if query.HasWhere() {
+					matched, err := matchCQLQuery(provider, query, id)
+					if err != nil {
+						eachError = err
+						return false
+					}
+					if !matched {
+						return true
+					}
+				}
+				total++
+				if page.full || page.skip() {
+					return true
+				}
 				components, err := provider.StoreReader().GetComponentTypesForEntity(id)
 				if err != nil {
 					eachError = err
@@ -71,6 +104,7 @@ func PostCQL(provider servertypes.Provider) func(*fiber.Ctx) error { //nolint:go
 					Data: make([]json.RawMessage, 0),
 				}
 
+				size := 0
 				for _, c := range components {
 					data, err := provider.StoreReader().GetComponentForEntityInRawJSON(c, id)
 					if err != nil {
@@ -78,8 +112,11 @@ func PostCQL(provider servertypes.Provider) func(*fiber.Ctx) error { //nolint:go
 						return false
 					}
 					resultElement.Data = append(resultElement.Data, data)
+					size += len(data)
+				}
+				if page.add(size) {
+					result = append(result, resultElement)
 				}
-				result = append(result, resultElement)
 				return true
 			},
 		)
@@ -90,6 +127,23 @@ func PostCQL(provider servertypes.Provider) func(*fiber.Ctx) error { //nolint:go
 			return fiber.NewError(fiber.StatusInternalServerError, eachError.Error())
 		}
 
-		return ctx.JSON(CQLQueryResponse{Results: result})
+		return ctx.JSON(CQLQueryResponse{Results: result, Truncation: page.truncation(total)})
 	}
 }
+
+// matchCQLQuery reports whether the components of the entity match the WHERE clause of the query.
+func matchCQLQuery(provider servertypes.Provider, query *cql.Query, id types.EntityID) (bool, error) {
+	components, err := provider.StoreReader().GetComponentTypesForEntity(id)
+	if err != nil {
+		return false, err
+	}
+	return query.Match(func(name string) ([]byte, bool, error) {
+		for _, c := range components {
+			if c.Name() == name {
+				data, err := provider.StoreReader().GetComponentForEntityInRawJSON(c, id)
+				return data, err == nil, err
+			}
+		}
+		return nil, false, nil
+	})
+}
@@ -0,0 +1,269 @@
+//nolint:govet // there is too much issues with incompatible struct tags
+package cql
+
+import (
+	"cmp"
+	"encoding/json"
+
+	"github.com/alecthomas/participle/v2"
+	"github.com/alecthomas/participle/v2/lexer"
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal/search/filter"
+)
+
+var (
+	queryLexer = lexer.MustSimple([]lexer.SimpleRule{
+		{Name: "String", Pattern: `"(\\.|[^"\\])*"`},
+		{Name: "Number", Pattern: `-?\d+(\.\d+)?`},
+		{Name: "Ident", Pattern: `[a-zA-Z_]\w*`},
+		{Name: "Comparator", Pattern: `<=|>=|!=|[=<>]`},
+		{Name: "Punct", Pattern: `[(),.&|!]`},
+		{Name: "Whitespace", Pattern: `\s+`},
+	})
+	internalQueryParser = participle.MustBuild[cqlQuery](
+		participle.Lexer(queryLexer),
+		participle.Unquote("String"),
+		participle.Elide("Whitespace"),
+	)
+)
+
+// Query is a parsed CQL query. Besides the filter expression, a query can compare the fields of components in a
+// WHERE clause, and limit the number of results, e.g.
+//
+//	CONTAINS(health, position) WHERE health.hp < 50 & !(position.x = 0) LIMIT 10
+type Query struct {
+	// Filter selects the entities by the components they have.
+	Filter filter.ComponentFilter
+	// Limit is the maximum number of results, or 0 if the number of results is not limited.
+	Limit int
+
+	where *cqlPredicate
+}
+
+type cqlQuery struct {
+	Term  *cqlTerm      `@@`
+	Where *cqlPredicate `("WHERE" @@)?`
+	Limit *int          `("LIMIT" @Number)?`
+}
+
+type cqlPredicate struct {
+	Left  *cqlCondition     `@@`
+	Right []*cqlOpCondition `@@*`
+}
+
+type cqlOpCondition struct {
+	Operator  cqlOperator   `@("&" | "|")`
+	Condition *cqlCondition `@@`
+}
+
+type cqlCondition struct {
+	Not          *cqlCondition  `"!" @@`
+	Comparison   *cqlComparison `| @@`
+	Subpredicate *cqlPredicate  `| "(" @@ ")"`
+}
+
+type cqlComparison struct {
+	Component  string      `@Ident`
+	Field      []string    `("." @Ident)+`
+	Comparator string      `@Comparator`
+	Value      *cqlLiteral `@@`
+}
+
+type cqlLiteral struct {
+	String *string  `@String`
+	Number *float64 `| @Number`
+	Bool   *cqlBool `| @("true" | "false")`
+	Null   bool     `| @"null"`
+}
+
+type cqlBool bool
+
+func (b *cqlBool) Capture(values []string) error {
+	*b = values[0] == "true"
+	return nil
+}
+
+// HasWhere reports whether the query has a WHERE clause, so Match has to be called for every entity of the filter.
+func (q *Query) HasWhere() bool {
+	return q.where != nil
+}
+
+// Match reports whether an entity matches the WHERE clause of the query. component returns the JSON value of the
+// component with the given name, or false if the entity does not have it. Comparisons of components that the entity
+// does not have, or of fields that the component does not have, are false.
+func (q *Query) Match(component func(name string) ([]byte, bool, error)) (bool, error) {
+	if q.where == nil {
+		return true, nil
+	}
+	m := &matcher{component: component, values: map[string]any{}}
+	return m.predicate(q.where)
+}
+
+// ParseQuery parses a CQL query. Every component in the query must be known to stringToComponent.
+func ParseQuery(cqlText string, stringToComponent componentByName) (*Query, error) {
+	parsed, err := internalQueryParser.ParseString("", cqlText)
+	if err != nil {
+		return nil, eris.Wrap(err, "failed to parse CQL string")
+	}
+	query := &Query{where: parsed.Where}
+	if query.Filter, err = termToComponentFilter(parsed.Term, stringToComponent); err != nil {
+		return nil, err
+	}
+	if parsed.Where != nil {
+		if err = validatePredicate(parsed.Where, stringToComponent); err != nil {
+			return nil, err
+		}
+	}
+	if parsed.Limit != nil {
+		if *parsed.Limit <= 0 {
+			return nil, eris.Errorf("LIMIT must be positive, got %d", *parsed.Limit)
+		}
+		query.Limit = *parsed.Limit
+	}
+	return query, nil
+}
+
+func validatePredicate(p *cqlPredicate, stringToComponent componentByName) error {
+	conditions := []*cqlCondition{p.Left}
+	for _, r := range p.Right {
+		conditions = append(conditions, r.Condition)
+	}
+	for _, c := range conditions {
+		var err error
+		switch {
+		case c.Not != nil:
+			err = validatePredicate(&cqlPredicate{Left: c.Not}, stringToComponent)
+		case c.Subpredicate != nil:
+			err = validatePredicate(c.Subpredicate, stringToComponent)
+		case c.Comparison != nil:
+			err = validateComparison(c.Comparison, stringToComponent)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateComparison(c *cqlComparison, stringToComponent componentByName) error {
+	if _, err := stringToComponent(c.Component); err != nil {
+		return eris.Wrap(err, "")
+	}
+	if (c.Value.Bool != nil || c.Value.Null) && c.Comparator != "=" && c.Comparator != "!=" {
+		return eris.Errorf("%s can't be compared with booleans or null", c.Comparator)
+	}
+	return nil
+}
+
+// matcher evaluates the WHERE clause of a query for one entity. Every component is decoded at most once.
+type matcher struct {
+	component func(name string) ([]byte, bool, error)
+	// values are the decoded components, or nil if the entity does not have the component.
+	values map[string]any
+}
+
+func (m *matcher) predicate(p *cqlPredicate) (bool, error) {
+	acc, err := m.condition(p.Left)
+	if err != nil {
+		return false, err
+	}
+	for _, r := range p.Right {
+		ok, err := m.condition(r.Condition)
+		if err != nil {
+			return false, err
+		}
+		switch r.Operator {
+		case opAnd:
+			acc = acc && ok
+		case opOr:
+			acc = acc || ok
+		default:
+			return false, eris.New("invalid operator")
+		}
+	}
+	return acc, nil
+}
+
+func (m *matcher) condition(c *cqlCondition) (bool, error) {
+	switch {
+	case c.Not != nil:
+		ok, err := m.condition(c.Not)
+		return !ok, err
+	case c.Subpredicate != nil:
+		return m.predicate(c.Subpredicate)
+	case c.Comparison != nil:
+		return m.comparison(c.Comparison)
+	}
+	return false, eris.New("unknown error during evaluation of the WHERE clause")
+}
+
+func (m *matcher) comparison(c *cqlComparison) (bool, error) {
+	value, ok := m.values[c.Component]
+	if !ok {
+		bz, found, err := m.component(c.Component)
+		if err != nil {
+			return false, err
+		}
+		if found {
+			if err = json.Unmarshal(bz, &value); err != nil {
+				return false, eris.Wrapf(err, "failed to decode component %q", c.Component)
+			}
+		}
+		m.values[c.Component] = value
+	}
+	if value == nil {
+		return false, nil
+	}
+	for _, field := range c.Field {
+		fields, ok := value.(map[string]any)
+		if !ok {
+			return false, nil
+		}
+		if value, ok = fields[field]; !ok {
+			return false, nil
+		}
+	}
+	return compare(value, c.Comparator, c.Value), nil
+}
+
+// compare compares the value of a field with a literal. Values of another type than the literal are only unequal.
+func compare(value any, comparator string, literal *cqlLiteral) bool {
+	var order int
+	switch v := value.(type) {
+	case float64:
+		if literal.Number == nil {
+			return comparator == "!="
+		}
+		order = cmp.Compare(v, *literal.Number)
+	case string:
+		if literal.String == nil {
+			return comparator == "!="
+		}
+		order = cmp.Compare(v, *literal.String)
+	case bool:
+		if literal.Bool == nil {
+			return comparator == "!="
+		}
+		return (v == bool(*literal.Bool)) == (comparator == "=")
+	case nil:
+		return literal.Null == (comparator == "=")
+	default:
+		return comparator == "!="
+	}
+	switch comparator {
+	case "=":
+		return order == 0
+	case "!=":
+		return order != 0
+	case "<":
+		return order < 0
+	case "<=":
+		return order <= 0
+	case ">":
+		return order > 0
+	case ">=":
+		return order >= 0
+	}
+	return false
+}
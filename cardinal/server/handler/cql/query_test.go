@@ -0,0 +1,95 @@
+package cql
+
+import (
+	"testing"
+
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal/types"
+)
+
+func knownComponents(names ...string) componentByName {
+	return func(name string) (types.Component, error) {
+		for _, n := range names {
+			if n == name {
+				return EmptyComponent{}, nil
+			}
+		}
+		return nil, eris.Errorf("component %q does not exist", name)
+	}
+}
+
+func TestQueryMatchesComponentFields(t *testing.T) {
+	components := map[string]string{
+		"health": `{"hp": 40, "max": 100, "dead": false}`,
+		"player": `{"name": "bob", "pos": {"x": 1, "y": -2}, "guild": null}`,
+	}
+	component := func(name string) ([]byte, bool, error) {
+		bz, ok := components[name]
+		return []byte(bz), ok, nil
+	}
+	stringToComponent := knownComponents("health", "player", "energy")
+
+	testCases := []struct {
+		where string
+		want  bool
+	}{
+		{where: "health.hp < 50", want: true},
+		{where: "health.hp >= 50", want: false},
+		{where: "health.hp = 40 & health.max != 40", want: true},
+		{where: `player.name = "bob"`, want: true},
+		{where: `player.name > "alice"`, want: true},
+		{where: "player.pos.y <= -2", want: true},
+		{where: "player.pos.x > 1.5", want: false},
+		{where: "health.dead = false", want: true},
+		{where: "health.dead != true", want: true},
+		{where: "player.guild = null", want: true},
+		{where: "!(player.guild = null)", want: false},
+		// Fields with another type than the value are only unequal.
+		{where: `health.hp = "40"`, want: false},
+		{where: `health.hp != "40"`, want: true},
+		// Comparisons of missing components and fields are false.
+		{where: "energy.value = 0", want: false},
+		{where: "health.mana != 0", want: false},
+		{where: "player.name.first = 0", want: false},
+		// Operators are evaluated from left to right.
+		{where: "health.hp = 0 & health.hp = 0 | health.hp = 40", want: true},
+		{where: "health.hp = 0 & (health.hp = 0 | health.hp = 40)", want: false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.where, func(t *testing.T) {
+			query, err := ParseQuery("CONTAINS(health) WHERE "+tc.where, stringToComponent)
+			assert.NilError(t, err)
+			assert.Check(t, query.HasWhere())
+			got, err := query.Match(component)
+			assert.NilError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestParseQuery(t *testing.T) {
+	stringToComponent := knownComponents("health")
+
+	query, err := ParseQuery("CONTAINS(health) LIMIT 3", stringToComponent)
+	assert.NilError(t, err)
+	assert.Equal(t, 3, query.Limit)
+	assert.Check(t, !query.HasWhere())
+
+	query, err = ParseQuery("ALL()", stringToComponent)
+	assert.NilError(t, err)
+	assert.Equal(t, 0, query.Limit)
+
+	for _, invalid := range []string{
+		"CONTAINS(health) WHERE mana.value = 1",
+		"CONTAINS(health) WHERE health.hp < true",
+		"CONTAINS(health) WHERE health.hp",
+		"CONTAINS(health) WHERE hp = 1",
+		"CONTAINS(health) LIMIT 0",
+		"CONTAINS(health) LIMIT many",
+	} {
+		_, err = ParseQuery(invalid, stringToComponent)
+		assert.Check(t, err != nil, "expected %q to be invalid", invalid)
+	}
+}
@@ -2,15 +2,20 @@ package handler
 
 import (
 	"encoding/json"
+	"strconv"
 
 	"github.com/gofiber/fiber/v2"
 
+	"pkg.world.dev/world-engine/cardinal/gamestate"
 	"pkg.world.dev/world-engine/cardinal/search/filter"
 	servertypes "pkg.world.dev/world-engine/cardinal/server/types"
 	"pkg.world.dev/world-engine/cardinal/types"
 )
 
-type DebugStateRequest struct{}
+type DebugStateRequest struct {
+	// Cursor is the value of the Next-Cursor header of a truncated reply, to read the entities that did not fit in it.
+	Cursor string
+}
 
 type debugStateElement struct {
 	ID         types.EntityID             `json:"id"`
@@ -19,20 +24,47 @@ type debugStateElement struct {
 
 type DebugStateResponse []debugStateElement
 
+const (
+	// TotalCountHeader is set on truncated list replies whose body is a list, to the number of items in the whole list.
+	TotalCountHeader = "Total-Count"
+	// NextCursorHeader is set on truncated list replies whose body is a list, to the cursor of the first item that was
+	// not returned.
+	NextCursorHeader = "Next-Cursor"
+)
+
 // GetDebugState godoc
 //
 // @Summary      Retrieves a list of all entities in the game state
 // @Description  Retrieves a list of all entities in the game state
+// @Description  The list is truncated to the reply limits of the server, in which case the Total-Count and Next-Cursor
+// @Description  headers are set
+// @Accept       application/json
 // @Produce      application/json
+// @Param        DebugStateRequest  body      DebugStateRequest  false  "Cursor of a truncated reply"
 // @Success      200  {object}  DebugStateResponse "List of all entities"
 // @Router       /debug/state [post]
-func GetDebugState(provider servertypes.Provider) func(*fiber.Ctx) error {
+func GetDebugState(provider servertypes.Provider, limits ReplyLimits) func(*fiber.Ctx) error {
 	return func(ctx *fiber.Ctx) error {
+		req := new(DebugStateRequest)
+		if len(ctx.Body()) > 0 {
+			if err := ctx.BodyParser(req); err != nil {
+				return err
+			}
+		}
+		page, err := newPager(limits, req.Cursor)
+		if err != nil {
+			return err
+		}
 		result := make(DebugStateResponse, 0)
+		total := 0
 		s := provider.Search(filter.All())
 		var eachClosureErr error
 		searchEachErr := s.Each(provider.GetReadOnlyCtx(),
 			func(id types.EntityID) bool {
+				total++
+				if page.full || page.skip() {
+					return true
+				}
 				var components []types.ComponentMetadata
 				components, eachClosureErr = provider.StoreReader().GetComponentTypesForEntity(id)
 				if eachClosureErr != nil {
@@ -42,6 +74,7 @@ func GetDebugState(provider servertypes.Provider) func(*fiber.Ctx) error {
 					ID:         id,
 					Components: make(map[string]json.RawMessage),
 				}
+				size := 0
 				for _, c := range components {
 					var data json.RawMessage
 					data, eachClosureErr = provider.StoreReader().GetComponentForEntityInRawJSON(c, id)
@@ -49,8 +82,11 @@ func GetDebugState(provider servertypes.Provider) func(*fiber.Ctx) error {
 						return false
 					}
 					resultElement.Components[c.Name()] = data
+					size += len(c.Name()) + len(data)
+				}
+				if page.add(size) {
+					result = append(result, resultElement)
 				}
-				result = append(result, resultElement)
 				return true
 			},
 		)
@@ -61,6 +97,82 @@ func GetDebugState(provider servertypes.Provider) func(*fiber.Ctx) error {
 			return searchEachErr
 		}
 
+		if truncation := page.truncation(total); truncation != nil {
+			ctx.Set(TotalCountHeader, strconv.Itoa(truncation.Total))
+			ctx.Set(NextCursorHeader, truncation.NextCursor)
+		}
 		return ctx.JSON(&result)
 	}
 }
+
+// DebugArchetypesResponse is the list of the archetypes of the game state.
+type DebugArchetypesResponse []gamestate.ArchetypeStat
+
+// GetDebugArchetypes godoc
+//
+// @Summary      Retrieves the stats of every archetype in the game state
+// @Description  Retrieves the components, the number of entities and the approximate size in bytes of every archetype
+// @Description  in the game state
+// @Produce      application/json
+// @Success      200  {object}  DebugArchetypesResponse "List of all archetypes"
+// @Router       /debug/archetypes [get]
+func GetDebugArchetypes(provider servertypes.Provider) func(*fiber.Ctx) error {
+	return func(ctx *fiber.Ctx) error {
+		stats, err := provider.ArchetypeStats()
+		if err != nil {
+			return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+		}
+		return ctx.JSON(DebugArchetypesResponse(stats))
+	}
+}
+
+// GetDebugLayout godoc
+//
+// @Summary      Retrieves the archetype layout of the game state
+// @Description  Retrieves how the entities are spread over archetypes, the query patterns of systems and the
+// @Description  suggested grouping of components. Query patterns are only recorded if layout profiling is enabled
+// @Produce      application/json
+// @Success      200  {object}  gamestate.LayoutReport "Layout of the game state"
+// @Router       /debug/layout [get]
+func GetDebugLayout(provider servertypes.Provider) func(*fiber.Ctx) error {
+	return func(ctx *fiber.Ctx) error {
+		report, err := provider.LayoutReport()
+		if err != nil {
+			return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+		}
+		return ctx.JSON(report)
+	}
+}
+
+// DebugSystemsResponse is the list of the systems of the world.
+type DebugSystemsResponse []types.SystemInfo
+
+// GetDebugSystems godoc
+//
+// @Summary      Retrieves the schedule of every system
+// @Description  Retrieves the systems of the world in the order in which they run, with the ticks that they run at and
+// @Description  whether they are disabled
+// @Produce      application/json
+// @Success      200  {object}  DebugSystemsResponse "List of all systems"
+// @Router       /debug/systems [get]
+func GetDebugSystems(provider servertypes.Provider) func(*fiber.Ctx) error {
+	return func(ctx *fiber.Ctx) error {
+		return ctx.JSON(DebugSystemsResponse(provider.GetSystemSchedule()))
+	}
+}
+
+// GetDebugConfig godoc
+//
+// @Summary      Retrieves the config of the world
+// @Description  Retrieves the config values of the world keyed by their environment variable, with secrets redacted
+// @Produce      application/json
+// @Success      200  {object}  map[string]any  "Config of the world"
+// @Router       /debug/config [get]
+func GetDebugConfig(cfg map[string]any) func(*fiber.Ctx) error {
+	if cfg == nil {
+		cfg = map[string]any{}
+	}
+	return func(ctx *fiber.Ctx) error {
+		return ctx.JSON(cfg)
+	}
+}
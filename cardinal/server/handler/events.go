@@ -1,22 +1,83 @@
 package handler
 
 import (
+	"strconv"
+
+	"github.com/goccy/go-json"
 	"github.com/gofiber/contrib/socketio"
 	"github.com/gofiber/contrib/websocket"
 	"github.com/gofiber/fiber/v2"
+	"github.com/rotisserie/eris"
 	"github.com/rs/zerolog/log"
+
+	servertypes "pkg.world.dev/world-engine/cardinal/server/types"
+	"pkg.world.dev/world-engine/cardinal/types"
 )
 
-// WebSocketEvents godoc
+// ListEventsResponse returns the events of the ticks in [StartTick, EndTick) that emitted events. To read the rest of
+// the event history, send the request again with EndTick as from_tick. EndTick is the current tick once the whole
+// history was read.
+type ListEventsResponse struct {
+	StartTick uint64             `json:"startTick"`
+	EndTick   uint64             `json:"endTick"`
+	Ticks     []types.TickEvents `json:"ticks"`
+}
+
+// GetEventHistory godoc
 //
-//	@Summary      Establishes a new websocket connection to retrieve system events
-//	@Description  Establishes a new websocket connection to retrieve system events
+//	@Summary      Retrieves the events of past ticks, or establishes a websocket connection to retrieve new events
+//	@Description  With from_tick, returns the recorded events of the ticks from from_tick onward, so that clients that
+//	@Description  reconnect can catch up on the events they missed. Without it, the request must be a websocket upgrade.
 //	@Produce      application/json
-//	@Success      101  {string}  string  "Switch protocol to ws"
+//	@Param        from_tick  query     integer             false  "Tick to read the event history from"
+//	@Success      101        {string}  string              "Switch protocol to ws"
+//	@Success      200        {object}  ListEventsResponse  "Events of the ticks from from_tick onward"
+//	@Failure      400        {string}  string              "Invalid from_tick"
+//	@Failure      404        {string}  string              "Event history is not enabled"
+//	@Failure      410        {string}  string              "Events of from_tick are no longer available"
 //	@Router       /events [get]
-func WebSocketEvents() func(c *fiber.Ctx) error {
-	return socketio.New(func(_ *socketio.Websocket) {
+func GetEventHistory(provider servertypes.Provider, limits ReplyLimits) func(*fiber.Ctx) error {
+	return func(ctx *fiber.Ctx) error {
+		if allowed, _ := ctx.Locals("allowed").(bool); allowed {
+			return ctx.Next()
+		}
+		fromTick, err := strconv.ParseUint(ctx.Query("from_tick"), 10, 64)
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "invalid from_tick: "+err.Error())
+		}
+		ticks, endTick, err := provider.GetEventHistory(fromTick, limits.MaxItems)
+		switch {
+		case eris.Is(err, types.ErrEventHistoryDisabled):
+			return fiber.NewError(fiber.StatusNotFound, types.ErrEventHistoryDisabled.Error())
+		case eris.Is(err, types.ErrEventsNotAvailable):
+			return fiber.NewError(fiber.StatusGone, err.Error())
+		case err != nil:
+			return fiber.NewError(fiber.StatusInternalServerError, "failed to read event history: "+err.Error())
+		}
+
+		// The events of a tick are either all returned or not at all, so that EndTick can be used as the cursor.
+		reply := ListEventsResponse{StartTick: fromTick, EndTick: endTick, Ticks: make([]types.TickEvents, 0, len(ticks))}
+		page := &pager{limits: limits}
+		for _, tick := range ticks {
+			bz, err := json.Marshal(tick)
+			if err != nil {
+				return fiber.NewError(fiber.StatusInternalServerError, "failed to encode events: "+err.Error())
+			}
+			if !page.add(len(bz)) {
+				reply.EndTick = tick.Tick
+				break
+			}
+			reply.Ticks = append(reply.Ticks, tick)
+		}
+		return ctx.JSON(reply)
+	}
+}
+
+// WebSocketEvents establishes a new websocket connection to retrieve system events.
+func WebSocketEvents(onConnect func(kws *socketio.Websocket)) func(c *fiber.Ctx) error {
+	return socketio.New(func(kws *socketio.Websocket) {
 		log.Debug().Msg("new websocket connection established")
+		onConnect(kws)
 	})
 }
 
@@ -27,5 +88,9 @@ func WebSocketUpgrader(c *fiber.Ctx) error {
 		c.Locals("allowed", true)
 		return c.Next()
 	}
+	// Requests for the event history are answered by GetEventHistory
+	if c.Query("from_tick") != "" {
+		return c.Next()
+	}
 	return fiber.ErrUpgradeRequired
 }
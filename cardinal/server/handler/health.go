@@ -2,26 +2,38 @@ package handler
 
 import (
 	"github.com/gofiber/fiber/v2"
+
+	servertypes "pkg.world.dev/world-engine/cardinal/server/types"
+	"pkg.world.dev/world-engine/cardinal/types"
 )
 
 type GetHealthResponse struct {
 	IsServerRunning   bool `json:"isServerRunning"`
 	IsGameLoopRunning bool `json:"isGameLoopRunning"`
+	// Storage is the health of the storage, as seen by the storage circuit breaker of the world.
+	Storage types.StorageHealth `json:"storage"`
 }
 
 // GetHealth godoc
 //
 //	@Summary      Retrieves the status of the server and game loop
-//	@Description  Retrieves the status of the server and game loop
+//	@Description  Retrieves the status of the server and game loop. The status is 503 while the world doesn't tick
+//	@Description  because its storage is unavailable
 //	@Produce      application/json
 //	@Success      200  {object}  GetHealthResponse  "Server and game loop status"
+//	@Failure      503  {object}  GetHealthResponse  "Storage outage"
 //	@Router       /health [get]
-func GetHealth() func(c *fiber.Ctx) error {
+func GetHealth(provider servertypes.Provider) func(c *fiber.Ctx) error {
 	return func(ctx *fiber.Ctx) error {
+		storage := provider.StorageHealth()
+		if storage.Outage {
+			ctx.Status(fiber.StatusServiceUnavailable)
+		}
 		return ctx.JSON(GetHealthResponse{
 			IsServerRunning: true,
 			// TODO(scott): reconsider whether we need this. Intuitively server running implies game loop running.
-			IsGameLoopRunning: true,
+			IsGameLoopRunning: !storage.Outage,
+			Storage:           storage,
 		})
 	}
 }
@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	// DefaultMaxReplyBytes is the default maximum size of the body of a read reply.
+	DefaultMaxReplyBytes = 16 << 20
+	// DefaultMaxReplyItems is the default maximum number of items in a list reply.
+	DefaultMaxReplyItems = 1000
+)
+
+var (
+	ErrReplyTooLarge = errors.New("reply is larger than the maximum reply size")
+	ErrInvalidCursor = errors.New("invalid cursor")
+)
+
+// ReplyLimits bounds the size of the replies of read handlers, so that a single read can't serialize an unbounded
+// amount of game state and stall the server.
+type ReplyLimits struct {
+	// MaxBytes is the maximum size of a reply body. List replies are truncated to fit, other replies are rejected.
+	MaxBytes int
+	// MaxItems is the maximum number of items in a list reply.
+	MaxItems int
+}
+
+// Truncation describes a list reply that did not fit in the reply limits. The rest of the list can be read by sending
+// the same request again with NextCursor as its cursor.
+type Truncation struct {
+	// Total is the number of items in the whole list.
+	Total int `json:"total"`
+	// Returned is the number of items in this reply.
+	Returned int `json:"returned"`
+	// NextCursor is the cursor of the first item that was not returned.
+	NextCursor string `json:"nextCursor"`
+}
+
+// checkReplySize returns an error if a reply of the given size is larger than the limits allow.
+func (l ReplyLimits) checkReplySize(size int) error {
+	if l.MaxBytes > 0 && size > l.MaxBytes {
+		return fiber.NewError(fiber.StatusBadRequest,
+			ErrReplyTooLarge.Error()+": "+strconv.Itoa(size)+" > "+strconv.Itoa(l.MaxBytes)+" bytes")
+	}
+	return nil
+}
+
+// pager selects the items of a list reply that fit in the reply limits, starting at a cursor. The cursor is the
+// position of an item in the list.
+type pager struct {
+	limits   ReplyLimits
+	start    int
+	position int
+	returned int
+	bytes    int
+	full     bool
+}
+
+func newPager(limits ReplyLimits, cursor string) (*pager, error) {
+	p := &pager{limits: limits}
+	if cursor != "" {
+		start, err := strconv.Atoi(cursor)
+		if err != nil || start < 0 {
+			return nil, fiber.NewError(fiber.StatusBadRequest, ErrInvalidCursor.Error())
+		}
+		p.start = start
+	}
+	return p, nil
+}
+
+// skip reports whether the next item of the list comes before the cursor.
+func (p *pager) skip() bool {
+	if p.position < p.start {
+		p.position++
+		return true
+	}
+	return false
+}
+
+// add reports whether the next item, whose encoded size is given, fits in the reply. Once an item does not fit, no
+// other item is added. The first item is always added, so that a list can be read even if one item is too large.
+func (p *pager) add(size int) bool {
+	return p.addMany(1, size)
+}
+
+// addMany reports whether the next items, whose total encoded size is given, fit in the reply. They are either all
+// added or none are.
+func (p *pager) addMany(items, size int) bool {
+	if p.full {
+		return false
+	}
+	if p.returned > 0 && ((p.limits.MaxItems > 0 && p.returned+items > p.limits.MaxItems) ||
+		(p.limits.MaxBytes > 0 && p.bytes+size > p.limits.MaxBytes)) {
+		p.full = true
+		return false
+	}
+	p.position += items
+	p.returned += items
+	p.bytes += size
+	return true
+}
+
+// truncation returns the truncation metadata of the reply, or nil if the whole list (after the cursor) was returned.
+func (p *pager) truncation(total int) *Truncation {
+	if !p.full {
+		return nil
+	}
+	return &Truncation{
+		Total:      total,
+		Returned:   p.returned,
+		NextCursor: strconv.Itoa(p.position),
+	}
+}
@@ -16,9 +16,11 @@ import (
 //	@Param        queryName   path      string  true  "Name of a registered query"
 //	@Param        queryBody   body      object  true  "Query to be executed"
 //	@Success      200         {object}  object  "Results of the executed query"
-//	@Failure      400         {string}  string  "Invalid request parameters"
+//	@Failure      400         {string}  string  "Invalid request parameters, or reply larger than the maximum size"
 //	@Router       /query/{queryGroup}/{queryName} [post]
-func PostQuery(queries map[string]map[string]engine.Query, wCtx engine.Context) func(*fiber.Ctx) error {
+func PostQuery(
+	queries map[string]map[string]engine.Query, wCtx engine.Context, limits ReplyLimits,
+) func(*fiber.Ctx) error {
 	return func(ctx *fiber.Ctx) error {
 		query, ok := queries[ctx.Params("group")][ctx.Params("name")]
 		if !ok {
@@ -30,6 +32,9 @@ func PostQuery(queries map[string]map[string]engine.Query, wCtx engine.Context)
 		if err != nil {
 			return fiber.NewError(fiber.StatusBadRequest, "encountered an error in query: "+err.Error())
 		}
+		if err = limits.checkReplySize(len(resBz)); err != nil {
+			return err
+		}
 
 		return ctx.Send(resBz)
 	}
@@ -47,6 +52,8 @@ func PostQuery(queries map[string]map[string]engine.Query, wCtx engine.Context)
 //	@Success      200         {object}  object  "Results of the executed query"
 //	@Failure      400         {string}  string  "Invalid request parameters"
 //	@Router       /query/game/{queryName} [post]
-func PostGameQuery(queries map[string]map[string]engine.Query, wCtx engine.Context) func(*fiber.Ctx) error {
-	return PostQuery(queries, wCtx)
+func PostGameQuery(
+	queries map[string]map[string]engine.Query, wCtx engine.Context, limits ReplyLimits,
+) func(*fiber.Ctx) error {
+	return PostQuery(queries, wCtx, limits)
 }
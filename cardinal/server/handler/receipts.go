@@ -1,6 +1,9 @@
 package handler
 
 import (
+	"strconv"
+
+	"github.com/goccy/go-json"
 	"github.com/gofiber/fiber/v2"
 
 	"pkg.world.dev/world-engine/cardinal/types/engine"
@@ -14,16 +17,22 @@ type ListTxReceiptsRequest struct {
 // StartTick and open on EndTick: i.e. [StartTick, EndTick)
 // Meaning StartTick is included and EndTick is not. To iterate over all ticks in the future, use the returned
 // EndTick as the StartTick in the next request. If StartTick == EndTick, the receipts list will be empty.
+// If the receipts of all ticks don't fit in the reply limits, EndTick is moved back to the first tick that did not fit,
+// and Truncation is set.
 type ListTxReceiptsResponse struct {
-	StartTick uint64         `json:"startTick"`
-	EndTick   uint64         `json:"endTick"`
-	Receipts  []ReceiptEntry `json:"receipts"`
+	StartTick  uint64         `json:"startTick"`
+	EndTick    uint64         `json:"endTick"`
+	Receipts   []ReceiptEntry `json:"receipts"`
+	Truncation *Truncation    `json:"truncation,omitempty"`
 }
 
-// ReceiptEntry represents a single transaction receipt. It contains an ID, a result, and a list of errors.
+// ReceiptEntry represents a single transaction receipt. It contains an ID, a status, a result, and a list of errors.
+// The status is one of "success", "failed", or "expired" if the transaction was dropped because it was not executed
+// before the tick at which it expires.
 type ReceiptEntry struct {
 	TxHash string   `json:"txHash"`
 	Tick   uint64   `json:"tick"`
+	Status string   `json:"status"`
 	Result any      `json:"result"`
 	Errors []string `json:"errors"`
 }
@@ -38,7 +47,7 @@ type ReceiptEntry struct {
 //	@Success      200                    {object}  ListTxReceiptsResponse "List of receipts"
 //	@Failure      400                    {string}  string                 "Invalid request body"
 //	@Router       /query/receipts/list [post]
-func GetReceipts(wCtx engine.Context) func(*fiber.Ctx) error {
+func GetReceipts(wCtx engine.Context, limits ReplyLimits) func(*fiber.Ctx) error {
 	return func(ctx *fiber.Ctx) error {
 		req := new(ListTxReceiptsRequest)
 		if err := ctx.BodyParser(req); err != nil {
@@ -61,19 +70,46 @@ func GetReceipts(wCtx engine.Context) func(*fiber.Ctx) error {
 			reply.StartTick = req.StartTick
 		}
 
+		// The receipts of a tick are either all returned or not at all, so that EndTick can be used as the cursor.
+		page := &pager{limits: limits}
+		total := 0
+		endTick := reply.EndTick
 		for t := reply.StartTick; t < reply.EndTick; t++ {
 			currReceipts, err := wCtx.GetTransactionReceiptsForTick(t)
 			if err != nil || len(currReceipts) == 0 {
 				continue
 			}
+			total += len(currReceipts)
+			if page.full {
+				continue
+			}
+			entries := make([]ReceiptEntry, 0, len(currReceipts))
+			size := 0
 			for _, r := range currReceipts {
-				reply.Receipts = append(reply.Receipts, ReceiptEntry{
+				entry := ReceiptEntry{
 					TxHash: string(r.TxHash),
 					Tick:   t,
+					Status: r.Status(),
 					Result: r.Result,
 					Errors: convertErrorsToStrings(r.Errs),
-				})
+				}
+				bz, err := json.Marshal(entry)
+				if err != nil {
+					return fiber.NewError(fiber.StatusInternalServerError, "failed to encode receipt: "+err.Error())
+				}
+				size += len(bz)
+				entries = append(entries, entry)
 			}
+			if !page.addMany(len(entries), size) {
+				endTick = t
+				continue
+			}
+			reply.Receipts = append(reply.Receipts, entries...)
+		}
+		if truncation := page.truncation(total); truncation != nil {
+			reply.EndTick = endTick
+			truncation.NextCursor = strconv.FormatUint(endTick, 10)
+			reply.Truncation = truncation
 		}
 		return ctx.JSON(reply)
 	}
@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	servertypes "pkg.world.dev/world-engine/cardinal/server/types"
+	"pkg.world.dev/world-engine/cardinal/types"
+)
+
+// SimulateTransactionResponse is the outcome that a transaction would have if it was executed in the next tick.
+type SimulateTransactionResponse struct {
+	// Receipt is the receipt that the transaction would have. Its tick is the tick that the transaction was simulated
+	// in.
+	Receipt ReceiptEntry `json:"receipt"`
+	// Events are the events that the tick would emit, in the order they would be emitted.
+	Events []string `json:"events"`
+}
+
+// PostSimulateTransaction godoc
+//
+//	@Summary      Simulates a transaction
+//	@Description  Runs the next tick with the transaction against the current game state, and returns the receipt and
+//	@Description  the events that the transaction would have, without changing the game state. The signature of the
+//	@Description  transaction is not verified and its nonce is not used, so the same transaction can be submitted
+//	@Description  afterward.
+//	@Accept       application/json
+//	@Produce      application/json
+//	@Param        txGroup  path      string                       true  "Message group"
+//	@Param        txName   path      string                       true  "Name of a registered message"
+//	@Param        txBody   body      Transaction                  true  "Transaction details & message to be simulated"
+//	@Success      200      {object}  SimulateTransactionResponse  "Receipt and events of the simulated transaction"
+//	@Failure      400      {string}  string                       "Invalid request parameter"
+//	@Failure      403      {string}  string                       "Not authorized"
+//	@Failure      404      {string}  string                       "Message type not found"
+//	@Router       /tx/simulate/{txGroup}/{txName} [post]
+func PostSimulateTransaction(
+	provider servertypes.Provider, msgs map[string]map[string]types.Message, apiVersion string,
+) func(*fiber.Ctx) error {
+	return func(ctx *fiber.Ctx) error {
+		msgType, ok := msgs[ctx.Params("group")][ctx.Params("name")]
+		if !ok {
+			return fiber.NewError(fiber.StatusNotFound, "message type not found")
+		}
+
+		tx := new(Transaction)
+		if err := ctx.BodyParser(tx); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "failed to parse request body: "+err.Error())
+		}
+		if err := validateTx(tx); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "invalid transaction payload: "+err.Error())
+		}
+		msg, err := msgType.DecodeForAPIVersion(apiVersion, tx.Body)
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "failed to decode message from transaction: "+err.Error())
+		}
+		if err = msgType.Authorize(tx, msg); err != nil {
+			return fiber.NewError(fiber.StatusForbidden, "transaction was not authorized: "+err.Error())
+		}
+
+		tick, rec, events, err := provider.SimulateTransaction(ctx.UserContext(), msgType.ID(), msg, tx)
+		if err != nil {
+			return fiber.NewError(fiber.StatusInternalServerError, "failed to simulate transaction: "+err.Error())
+		}
+		res := SimulateTransactionResponse{
+			Receipt: ReceiptEntry{
+				TxHash: string(rec.TxHash),
+				Tick:   tick,
+				Status: rec.Status(),
+				Result: rec.Result,
+				Errors: make([]string, 0, len(rec.Errs)),
+			},
+			Events: make([]string, 0, len(events)),
+		}
+		for _, err := range rec.Errs {
+			res.Receipt.Errors = append(res.Receipt.Errors, err.Error())
+		}
+		for _, event := range events {
+			res.Events = append(res.Events, string(event))
+		}
+		return ctx.JSON(&res)
+	}
+}
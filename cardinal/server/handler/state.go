@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal/gamestate"
+	servertypes "pkg.world.dev/world-engine/cardinal/server/types"
+	"pkg.world.dev/world-engine/cardinal/types"
+)
+
+// StateCommitmentsResponse is the list of the state commitments that can still be proven.
+type StateCommitmentsResponse []gamestate.StateCommitment
+
+// StateProofRequest is the component value of an entity to prove.
+type StateProofRequest struct {
+	// Tick is any tick of the range of ticks of the state commitment to prove the value in.
+	Tick      uint64         `json:"tick"`
+	EntityID  types.EntityID `json:"entityId"`
+	Component string         `json:"component"`
+}
+
+// StateDiffRequest is the range of ticks to diff: the ticks from FromTick up to, but not including, ToTick.
+type StateDiffRequest struct {
+	FromTick uint64 `json:"fromTick"`
+	ToTick   uint64 `json:"toTick"`
+}
+
+// GetStateCommitments godoc
+//
+//	@Summary      Retrieves the state commitments of the game state
+//	@Description  Retrieves the merkle roots of the game state that were submitted to the base shard, for the ranges of
+//	@Description  ticks that proofs can still be generated for
+//	@Produce      application/json
+//	@Success      200  {object}  StateCommitmentsResponse  "List of state commitments, sorted by tick"
+//	@Router       /state/commitments [get]
+func GetStateCommitments(provider servertypes.Provider) func(*fiber.Ctx) error {
+	return func(ctx *fiber.Ctx) error {
+		commitments, err := provider.StateCommitments()
+		if err != nil {
+			return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+		}
+		return ctx.JSON(StateCommitmentsResponse(commitments))
+	}
+}
+
+// PostStateProof godoc
+//
+//	@Summary      Proves the value of a component of an entity
+//	@Description  Returns a merkle proof that the entity had the component value at the end of the range of ticks of
+//	@Description  the state commitment that contains the given tick, which can be verified against the state root of
+//	@Description  the range on the base shard
+//	@Accept       application/json
+//	@Produce      application/json
+//	@Param        stateProof  body      StateProofRequest     true  "Tick, entity and component to prove"
+//	@Success      200         {object}  gamestate.StateProof  "Leaf, state commitment and merkle proof"
+//	@Failure      400         {string}  string                "Invalid request body or missing component"
+//	@Failure      404         {string}  string                "No state commitment or component value to prove"
+//	@Router       /state/proof [post]
+func PostStateProof(provider servertypes.Provider) func(*fiber.Ctx) error {
+	return func(ctx *fiber.Ctx) error {
+		req := new(StateProofRequest)
+		if err := ctx.BodyParser(req); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "failed to parse request body: "+err.Error())
+		}
+		if req.Component == "" {
+			return fiber.NewError(fiber.StatusBadRequest, "component is required")
+		}
+		proof, err := provider.ProveState(req.Tick, req.EntityID, req.Component)
+		switch {
+		case eris.Is(err, gamestate.ErrStateCommitmentNotFound), eris.Is(err, gamestate.ErrStateLeafNotFound):
+			return fiber.NewError(fiber.StatusNotFound, err.Error())
+		case err != nil:
+			return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+		}
+		return ctx.JSON(&proof)
+	}
+}
+
+// PostStateDiff godoc
+//
+//	@Summary      Retrieves the changes to the game state between two ticks
+//	@Description  Returns the entities that were created and destroyed, and the component values that changed, in the
+//	@Description  ticks from fromTick up to, but not including, toTick
+//	@Accept       application/json
+//	@Produce      application/json
+//	@Param        stateDiff  body      StateDiffRequest     true  "Range of ticks to diff"
+//	@Success      200        {object}  gamestate.StateDiff  "Created and destroyed entities and changed component values"
+//	@Failure      400        {string}  string               "Invalid request body or range of ticks"
+//	@Failure      404        {string}  string               "The diff of a tick of the range is not available"
+//	@Router       /state/diff [post]
+func PostStateDiff(provider servertypes.Provider) func(*fiber.Ctx) error {
+	return func(ctx *fiber.Ctx) error {
+		req := new(StateDiffRequest)
+		if err := ctx.BodyParser(req); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "failed to parse request body: "+err.Error())
+		}
+		diff, err := provider.Diff(req.FromTick, req.ToTick)
+		switch {
+		case eris.Is(err, gamestate.ErrInvalidStateDiffRange):
+			return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		case eris.Is(err, gamestate.ErrStateDiffNotAvailable):
+			return fiber.NewError(fiber.StatusNotFound, err.Error())
+		case err != nil:
+			return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+		}
+		return ctx.JSON(&diff)
+	}
+}
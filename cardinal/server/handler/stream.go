@@ -0,0 +1,393 @@
+package handler
+
+import (
+	"encoding/json"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/contrib/socketio"
+	"github.com/gofiber/contrib/websocket"
+	"github.com/gofiber/fiber/v2"
+	"github.com/rotisserie/eris"
+	"github.com/rs/zerolog/log"
+
+	"pkg.world.dev/world-engine/cardinal/gamestate"
+	servertypes "pkg.world.dev/world-engine/cardinal/server/types"
+	"pkg.world.dev/world-engine/cardinal/types"
+)
+
+const (
+	StateStreamSnapshot = "snapshot"
+	StateStreamDiff     = "diff"
+	StateStreamError    = "error"
+
+	// spectatorFilterLocal is the key of the filter of a spectator in the locals of its websocket connection.
+	spectatorFilterLocal = "spectatorFilter"
+)
+
+// SpectatorFilter selects the entities and the components that a spectator of the state stream receives.
+type SpectatorFilter struct {
+	// Components are the components that the entities must have, and the only ones that are sent. If it is empty,
+	// every entity is sent with all of its components.
+	Components []string
+	// Region are the ranges that numeric fields of the entities must be in, e.g. to only send the entities in an area
+	// of the map. The entities must have the components of the fields.
+	Region []FieldRange
+}
+
+// FieldRange is the closed range [Min, Max] of a numeric field of a component. Field is a dot separated path for the
+// fields of nested objects.
+type FieldRange struct {
+	Component string
+	Field     string
+	Min, Max  float64
+}
+
+// StateSnapshotMessage is the first message of the state stream: the entities in the filter of the spectator at the
+// start of Tick.
+type StateSnapshotMessage struct {
+	Type     string                     `json:"type"`
+	Tick     uint64                     `json:"tick"`
+	Entities []gamestate.SnapshotEntity `json:"entities"`
+}
+
+// StateDiffMessage is sent to the spectators of the state stream after ticks complete. It is the change that the ticks
+// from FromTick up to, but not including, ToTick made to the entities in the filter of the spectator. Entered are the
+// entities that were created or moved into the filter, with all of their components in the filter, and Left are the
+// ones that were destroyed or moved out of it. Changed are the changes of the entities that stayed in the filter.
+type StateDiffMessage struct {
+	Type     string                      `json:"type"`
+	FromTick uint64                      `json:"fromTick"`
+	ToTick   uint64                      `json:"toTick"`
+	Entered  []gamestate.SnapshotEntity  `json:"entered"`
+	Left     []types.EntityID            `json:"left"`
+	Changed  []gamestate.ComponentChange `json:"changed"`
+}
+
+// StateStreamErrorMessage is the last message of a state stream that the server closes because of an error.
+type StateStreamErrorMessage struct {
+	Type  string `json:"type"`
+	Error string `json:"error"`
+}
+
+// Spectator is a websocket connection of the state stream. It knows which entities it has been sent, so that each
+// diff only tells it about the entities that entered and left its filter.
+type Spectator struct {
+	kws    *socketio.Websocket
+	filter SpectatorFilter
+	// tick is the first tick whose diff hasn't been sent yet.
+	tick    uint64
+	visible map[types.EntityID]bool
+}
+
+// GetStateStream godoc
+//
+//	@Summary      Streams the game state to spectators over a websocket connection
+//	@Description  Sends a snapshot of the entities in the filter, followed by the change that every tick makes to them,
+//	@Description  so that spectators and late joiners can sync without pausing the world. The world must record state
+//	@Description  diffs. The messages are StateSnapshotMessage, StateDiffMessage and StateStreamErrorMessage, which is
+//	@Description  sent before the server closes the stream, e.g. when the spectator fell too far behind.
+//	@Produce      application/json
+//	@Param        components  query     string  false  "Comma separated names of the components the entities must have and that are sent"
+//	@Param        region      query     string  false  "Comma separated ranges of fields, e.g. position.x:0:100,position.y:0:100"
+//	@Success      101         {string}  string  "Switch protocol to ws"
+//	@Failure      400         {string}  string  "Invalid filter"
+//	@Failure      404         {string}  string  "State diffs are not recorded"
+//	@Failure      426         {string}  string  "Not a websocket upgrade"
+//	@Router       /state/stream [get]
+func GetStateStream(provider servertypes.Provider) func(*fiber.Ctx) error {
+	return func(ctx *fiber.Ctx) error {
+		if !websocket.IsWebSocketUpgrade(ctx) {
+			return fiber.ErrUpgradeRequired
+		}
+		if !provider.RecordsStateDiffs() {
+			return fiber.NewError(fiber.StatusNotFound, "state diffs are not recorded")
+		}
+		filter, err := ParseSpectatorFilter(provider, ctx.Query("components"), ctx.Query("region"))
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		}
+		ctx.Locals(spectatorFilterLocal, filter)
+		return ctx.Next()
+	}
+}
+
+// WebSocketStateStream sends the snapshot of the state stream to new spectators, and then gives them to onConnect, which
+// sends them the diffs of the following ticks.
+func WebSocketStateStream(provider servertypes.Provider, onConnect func(*Spectator)) func(*fiber.Ctx) error {
+	return socketio.New(func(kws *socketio.Websocket) {
+		filter, _ := kws.Locals(spectatorFilterLocal).(SpectatorFilter)
+		spectator := &Spectator{kws: kws, filter: filter, visible: map[types.EntityID]bool{}}
+		snapshot, err := provider.Snapshot(filter.snapshotComponents())
+		if err != nil {
+			spectator.Close(err)
+			return
+		}
+		msg := StateSnapshotMessage{Type: StateStreamSnapshot, Tick: snapshot.Tick,
+			Entities: make([]gamestate.SnapshotEntity, 0, len(snapshot.Entities))}
+		for _, entity := range snapshot.Entities {
+			if filter.matches(entity.Components) {
+				msg.Entities = append(msg.Entities, filter.selected(entity))
+				spectator.visible[entity.ID] = true
+			}
+		}
+		if err = spectator.emit(msg); err != nil {
+			spectator.Close(err)
+			return
+		}
+		spectator.tick = snapshot.Tick
+		log.Debug().Uint64("tick", snapshot.Tick).Msg("new spectator of the state stream")
+		onConnect(spectator)
+	})
+}
+
+// Tick returns the first tick whose diff hasn't been sent to the spectator yet.
+func (s *Spectator) Tick() uint64 {
+	return s.tick
+}
+
+// IsAlive reports whether the connection of the spectator is still open.
+func (s *Spectator) IsAlive() bool {
+	return s.kws.IsAlive()
+}
+
+// Close sends the error to the spectator and closes its connection.
+func (s *Spectator) Close(err error) {
+	if emitErr := s.emit(StateStreamErrorMessage{Type: StateStreamError, Error: err.Error()}); emitErr != nil {
+		log.Warn().Err(emitErr).Msg("failed to send error to spectator")
+	}
+	s.kws.Emit([]byte(""), socketio.CloseMessage)
+}
+
+// SendDiff sends the part of the given diff that is in the filter of the spectator. The diff must start at the tick of
+// the spectator, and the state of the store must be the state at the end of the diff, so that the entities that enter
+// the filter are sent with their current values.
+func (s *Spectator) SendDiff(provider servertypes.Provider, diff gamestate.StateDiff) error {
+	if diff.FromTick != s.tick {
+		return eris.Errorf("the diff starts at tick %d instead of tick %d", diff.FromTick, s.tick)
+	}
+	msg := StateDiffMessage{
+		Type:     StateStreamDiff,
+		FromTick: diff.FromTick,
+		ToTick:   diff.ToTick,
+		Entered:  []gamestate.SnapshotEntity{},
+		Left:     []types.EntityID{},
+		Changed:  []gamestate.ComponentChange{},
+	}
+	changes := map[types.EntityID][]gamestate.ComponentChange{}
+	touched := slices.Clone(diff.Created)
+	for _, change := range diff.Changed {
+		if len(changes[change.Entity]) == 0 {
+			touched = append(touched, change.Entity)
+		}
+		changes[change.Entity] = append(changes[change.Entity], change)
+	}
+	for _, id := range diff.Destroyed {
+		if s.visible[id] {
+			msg.Left = append(msg.Left, id)
+			delete(s.visible, id)
+		}
+	}
+	slices.Sort(touched)
+	touched = slices.Compact(touched)
+	for _, id := range touched {
+		if slices.Contains(diff.Destroyed, id) {
+			continue
+		}
+		if s.visible[id] && !s.filter.mayChangeMembership(changes[id]) {
+			for _, change := range changes[id] {
+				if s.filter.selects(change.Component) {
+					msg.Changed = append(msg.Changed, change)
+				}
+			}
+			continue
+		}
+		entity, matches, err := s.filter.currentEntity(provider, id)
+		if err != nil {
+			return err
+		}
+		switch {
+		case matches && !s.visible[id]:
+			msg.Entered = append(msg.Entered, entity)
+			s.visible[id] = true
+		case !matches && s.visible[id]:
+			msg.Left = append(msg.Left, id)
+			delete(s.visible, id)
+		case matches:
+			for _, change := range changes[id] {
+				if s.filter.selects(change.Component) {
+					msg.Changed = append(msg.Changed, change)
+				}
+			}
+		}
+	}
+	slices.Sort(msg.Left)
+	if err := s.emit(msg); err != nil {
+		return err
+	}
+	s.tick = diff.ToTick
+	return nil
+}
+
+func (s *Spectator) emit(msg any) error {
+	bz, err := json.Marshal(msg)
+	if err != nil {
+		return eris.Wrap(err, "failed to encode state stream message")
+	}
+	s.kws.Emit(bz)
+	return nil
+}
+
+// ParseSpectatorFilter parses the comma separated component names and field ranges of the filter of a spectator. A
+// field range is the component name and the path of the field, separated by a dot, followed by the minimum and the
+// maximum of the field, separated by colons, e.g. "position.x:0:100".
+func ParseSpectatorFilter(provider servertypes.Provider, components, region string) (SpectatorFilter, error) {
+	var filter SpectatorFilter
+	if components != "" {
+		filter.Components = strings.Split(components, ",")
+	}
+	for _, name := range filter.Components {
+		if _, err := provider.GetComponentByName(name); err != nil {
+			return SpectatorFilter{}, err
+		}
+	}
+	if region == "" {
+		return filter, nil
+	}
+	for _, term := range strings.Split(region, ",") {
+		parts := strings.Split(term, ":")
+		if len(parts) != 3 { //nolint:gomnd // field, min and max
+			return SpectatorFilter{}, eris.Errorf("field range %q must be of the form component.field:min:max", term)
+		}
+		component, field, ok := strings.Cut(parts[0], ".")
+		if !ok || field == "" {
+			return SpectatorFilter{}, eris.Errorf("field range %q must name a field of a component", term)
+		}
+		if _, err := provider.GetComponentByName(component); err != nil {
+			return SpectatorFilter{}, err
+		}
+		minValue, err := strconv.ParseFloat(parts[1], 64)
+		if err != nil {
+			return SpectatorFilter{}, eris.Wrapf(err, "invalid minimum of field range %q", term)
+		}
+		maxValue, err := strconv.ParseFloat(parts[2], 64)
+		if err != nil {
+			return SpectatorFilter{}, eris.Wrapf(err, "invalid maximum of field range %q", term)
+		}
+		if minValue > maxValue {
+			return SpectatorFilter{}, eris.Errorf("the minimum of field range %q is greater than its maximum", term)
+		}
+		filter.Region = append(filter.Region, FieldRange{Component: component, Field: field, Min: minValue,
+			Max: maxValue})
+	}
+	return filter, nil
+}
+
+// snapshotComponents returns the components that a snapshot must include to apply the filter: the components of the
+// filter and the ones of its region, or none if the filter includes all components.
+func (f SpectatorFilter) snapshotComponents() []string {
+	if len(f.Components) == 0 {
+		return nil
+	}
+	names := slices.Clone(f.Components)
+	for _, r := range f.Region {
+		if !slices.Contains(names, r.Component) {
+			names = append(names, r.Component)
+		}
+	}
+	return names
+}
+
+// selects reports whether the given component is sent to the spectator.
+func (f SpectatorFilter) selects(component string) bool {
+	return len(f.Components) == 0 || slices.Contains(f.Components, component)
+}
+
+// matches reports whether an entity with the given component values is in the filter.
+func (f SpectatorFilter) matches(components map[string]json.RawMessage) bool {
+	for _, name := range f.Components {
+		if _, ok := components[name]; !ok {
+			return false
+		}
+	}
+	for _, r := range f.Region {
+		value, ok := components[r.Component]
+		if !ok || !r.contains(value) {
+			return false
+		}
+	}
+	return true
+}
+
+// selected returns the entity with only the components that are sent to the spectator.
+func (f SpectatorFilter) selected(entity gamestate.SnapshotEntity) gamestate.SnapshotEntity {
+	for name := range entity.Components {
+		if !f.selects(name) {
+			delete(entity.Components, name)
+		}
+	}
+	return entity
+}
+
+// mayChangeMembership reports whether the given changes of an entity may have moved it in or out of the filter: the
+// changes of the fields of the region, and the addition and removal of the components of the filter.
+func (f SpectatorFilter) mayChangeMembership(changes []gamestate.ComponentChange) bool {
+	for _, change := range changes {
+		if slices.ContainsFunc(f.Region, func(r FieldRange) bool { return r.Component == change.Component }) {
+			return true
+		}
+		if slices.Contains(f.Components, change.Component) && (isNullJSON(change.Old) || isNullJSON(change.New)) {
+			return true
+		}
+	}
+	return false
+}
+
+// currentEntity reads the components of the entity in the filter from the store, and reports whether it is in the
+// filter.
+func (f SpectatorFilter) currentEntity(provider servertypes.Provider, id types.EntityID) (
+	gamestate.SnapshotEntity, bool, error,
+) {
+	entity := gamestate.SnapshotEntity{ID: id, Components: map[string]json.RawMessage{}}
+	cTypes, err := provider.StoreReader().GetComponentTypesForEntity(id)
+	if err != nil {
+		return entity, false, err
+	}
+	needed := f.snapshotComponents()
+	for _, cType := range cTypes {
+		if needed != nil && !slices.Contains(needed, cType.Name()) {
+			continue
+		}
+		value, err := provider.StoreReader().GetComponentForEntityInRawJSON(cType, id)
+		if err != nil {
+			return entity, false, err
+		}
+		entity.Components[cType.Name()] = value
+	}
+	if !f.matches(entity.Components) {
+		return entity, false, nil
+	}
+	return f.selected(entity), true, nil
+}
+
+// contains reports whether the field of the given component value is a number in the range.
+func (r FieldRange) contains(component json.RawMessage) bool {
+	var value any
+	if err := json.Unmarshal(component, &value); err != nil {
+		return false
+	}
+	for _, key := range strings.Split(r.Field, ".") {
+		fields, ok := value.(map[string]any)
+		if !ok {
+			return false
+		}
+		value = fields[key]
+	}
+	number, ok := value.(float64)
+	return ok && number >= r.Min && number <= r.Max
+}
+
+func isNullJSON(value json.RawMessage) bool {
+	return len(value) == 0 || string(value) == "null"
+}
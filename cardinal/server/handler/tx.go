@@ -3,12 +3,17 @@ package handler
 import (
 	"errors"
 	"fmt"
+	"math"
+	"strconv"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/rotisserie/eris"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 
 	personaMsg "pkg.world.dev/world-engine/cardinal/persona/msg"
 	servertypes "pkg.world.dev/world-engine/cardinal/server/types"
+	"pkg.world.dev/world-engine/cardinal/tracing"
 	"pkg.world.dev/world-engine/cardinal/types"
 	"pkg.world.dev/world-engine/sign"
 )
@@ -18,12 +23,28 @@ var (
 	ErrWrongNamespace             = errors.New("incorrect namespace")
 	ErrSystemTransactionRequired  = errors.New("system transaction required")
 	ErrSystemTransactionForbidden = errors.New("system transaction forbidden")
+	ErrIdempotencyKeyTooLong      = errors.New("idempotency key is too long")
+	ErrNotAdminSigner             = errors.New("message must be signed by an admin signer")
+	ErrCoSignedIdempotencyKey     = errors.New("co-signed transactions can't be submitted with an idempotency key")
+)
+
+const (
+	// IdempotencyKeyHeader carries an optional client-generated key that identifies a transaction submission. Retries
+	// with the same key are answered with the transaction hash and tick of the first submission.
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader is set to "true" on responses to retried submissions.
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLength = 255
 )
 
 // PostTransactionResponse is the HTTP response for a successful transaction submission
 type PostTransactionResponse struct {
 	TxHash string
 	Tick   uint64
+	// Pending is true if the transaction is waiting for the signatures of its co-signers. It is executed once they
+	// were added with /tx/cosign.
+	Pending bool
 }
 
 type Transaction = sign.Transaction
@@ -37,11 +58,15 @@ type Transaction = sign.Transaction
 //	@Param        txGroup  path      string                   true  "Message group"
 //	@Param        txName   path      string                   true  "Name of a registered message"
 //	@Param        txBody   body      Transaction              true  "Transaction details & message to be submitted"
+//	@Param        Idempotency-Key  header  string  false  "Client-generated key that deduplicates retried submissions"
 //	@Success      200      {object}  PostTransactionResponse  "Transaction hash and tick"
 //	@Failure      400      {string}  string                   "Invalid request parameter"
+//	@Failure      403      {string}  string                   "Persona tag is banned, admin message not signed by an admin, or not authorized"
+//	@Failure      503      {object}  types.RetryAfter         "The world is overloaded, retry after the Retry-After header"
 //	@Router       /tx/{txGroup}/{txName} [post]
 func PostTransaction(
 	provider servertypes.Provider, msgs map[string]map[string]types.Message, disableSigVerification bool,
+	adminSigners []string, apiVersion string,
 ) func(*fiber.Ctx) error {
 	return func(ctx *fiber.Ctx) error {
 		msgType, ok := msgs[ctx.Params("group")][ctx.Params("name")]
@@ -49,6 +74,13 @@ func PostTransaction(
 			return fiber.NewError(fiber.StatusNotFound, "message type not found")
 		}
 
+		// The span of the tick that executes this transaction links to this span
+		spanCtx, span := tracing.Tracer().Start(ctx.UserContext(), "cardinal.tx.submit",
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(attribute.String("message", msgType.FullName())),
+		)
+		defer span.End()
+
 		// Parse the request body into a sign.Transaction struct
 		tx := new(Transaction)
 		if err := ctx.BodyParser(tx); err != nil {
@@ -60,13 +92,56 @@ func PostTransaction(
 			return fiber.NewError(fiber.StatusBadRequest, "invalid transaction payload: "+err.Error())
 		}
 
-		// Decode the message from the transaction
-		msg, err := msgType.Decode(tx.Body)
+		// Transactions that would expire before they are executed are rejected right away
+		if tick := provider.GetReadOnlyCtx().CurrentTick(); tx.IsExpired(tick) {
+			return fiber.NewError(fiber.StatusBadRequest,
+				fmt.Sprintf("transaction expired at tick %d, the current tick is %d", tx.ExpiresAtTick, tick))
+		}
+
+		// Reject transactions from personas that were banned through the admin service
+		banned, err := provider.IsPersonaBanned(tx.PersonaTag)
+		if err != nil {
+			return fiber.NewError(fiber.StatusInternalServerError, "failed to check persona ban: "+err.Error())
+		} else if banned {
+			return fiber.NewError(fiber.StatusForbidden, "persona tag is banned")
+		}
+
+		// A retry of a submission that was already accepted is answered with the original transaction. This must
+		// happen before the signature is verified, since the nonce of the retried transaction has already been used.
+		idempotencyKey := ctx.Get(IdempotencyKeyHeader)
+		if len(idempotencyKey) > maxIdempotencyKeyLength {
+			return fiber.NewError(fiber.StatusBadRequest, ErrIdempotencyKeyTooLong.Error())
+		}
+		if idempotencyKey != "" && len(tx.CoSigners) > 0 {
+			return fiber.NewError(fiber.StatusBadRequest, ErrCoSignedIdempotencyKey.Error())
+		}
+		if idempotencyKey != "" {
+			tick, hash, found, err := provider.LookupIdempotencyKey(tx.PersonaTag, idempotencyKey)
+			if err != nil {
+				return fiber.NewError(fiber.StatusInternalServerError, "failed to look up idempotency key: "+err.Error())
+			} else if found {
+				return replayTransaction(ctx, span, tick, hash)
+			}
+		}
+
+		// Turn the transaction away while the world is overloaded. This must happen before the signature is verified,
+		// so that the nonce of the transaction is still unused when it is retried.
+		if retry := provider.CheckBackPressure(); retry != nil {
+			return retryLater(ctx, span, retry)
+		}
+
+		// Decode the message from the transaction. Clients of older API versions may send a legacy input, which is
+		// upgraded to the current input of the message.
+		msg, err := msgType.DecodeForAPIVersion(apiVersion, tx.Body)
 		if err != nil {
-			return fiber.NewError(fiber.StatusBadRequest, "failed to decode message from transaction")
+			return fiber.NewError(fiber.StatusBadRequest, "failed to decode message from transaction: "+err.Error())
 		}
 
-		if !disableSigVerification {
+		if !disableSigVerification && msgType.IsAdminOnly() {
+			if err = validateAdminSignature(provider, adminSigners, tx); err != nil {
+				return err
+			}
+		} else if !disableSigVerification {
 			var signerAddress string
 			// TODO(scott): don't hardcode this
 			if msgType.Name() == "create-persona" {
@@ -75,14 +150,52 @@ func PostTransaction(
 				signerAddress = createPersonaMsg.SignerAddress
 			}
 
-			if err = lookupSignerAndValidateSignature(provider, signerAddress, tx); err != nil {
+			if err = lookupSignerAndValidateSignature(provider, msgType.FullName(), signerAddress, tx); err != nil {
 				return err
 			}
 		}
 
+		// Reject transactions that the authorizer of the message does not allow, so they don't take up tick time
+		if err = msgType.Authorize(tx, msg); err != nil {
+			return fiber.NewError(fiber.StatusForbidden, "transaction was not authorized: "+err.Error())
+		}
+
+		// Co-signed transactions are held back until all their co-signers signed them
+		if len(tx.CoSigners) > 0 {
+			for personaTag := range tx.CoSignatures {
+				if err = validateCoSignature(provider, disableSigVerification, tx, personaTag); err != nil {
+					return err
+				}
+			}
+			tick, hash, pending, err := provider.AddCoSignedTransaction(spanCtx, msgType.ID(), msg, tx)
+			if err != nil {
+				return fiber.NewError(fiber.StatusInternalServerError, "failed to add transaction: "+err.Error())
+			}
+			span.SetAttributes(attribute.String("tx_hash", string(hash)), attribute.Bool("co_sign_pending", pending))
+			return ctx.JSON(&PostTransactionResponse{
+				TxHash:  string(hash),
+				Tick:    tick,
+				Pending: pending,
+			})
+		}
+
 		// Add the transaction to the engine
 		// TODO(scott): this should just deal with txpool instead of having to go through engine
-		tick, hash := provider.AddTransaction(msgType.ID(), msg, tx)
+		var tick uint64
+		var hash types.TxHash
+		if idempotencyKey != "" {
+			var duplicate bool
+			tick, hash, duplicate, err = provider.AddIdempotentTransaction(spanCtx, idempotencyKey, msgType.ID(), msg, tx)
+			if err != nil {
+				return fiber.NewError(fiber.StatusInternalServerError, "failed to add transaction: "+err.Error())
+			} else if duplicate {
+				// A concurrent retry was accepted between the lookup above and now
+				return replayTransaction(ctx, span, tick, hash)
+			}
+		} else if tick, hash, err = provider.AddTransactionWithContext(spanCtx, msgType.ID(), msg, tx); err != nil {
+			return fiber.NewError(fiber.StatusInternalServerError, "failed to add transaction: "+err.Error())
+		}
+		span.SetAttributes(attribute.String("tx_hash", string(hash)), attribute.Int64("tick", int64(tick)))
 
 		return ctx.JSON(&PostTransactionResponse{
 			TxHash: string(hash),
@@ -91,6 +204,25 @@ func PostTransaction(
 	}
 }
 
+// replayTransaction responds to a retried submission with the transaction that was accepted the first time.
+func replayTransaction(ctx *fiber.Ctx, span trace.Span, tick uint64, hash types.TxHash) error {
+	span.SetAttributes(attribute.String("tx_hash", string(hash)), attribute.Int64("tick", int64(tick)),
+		attribute.Bool("idempotent_replay", true))
+	ctx.Set(IdempotentReplayedHeader, "true")
+	return ctx.JSON(&PostTransactionResponse{
+		TxHash: string(hash),
+		Tick:   tick,
+	})
+}
+
+// retryLater responds to a submission that the world did not accept because of its back pressure, with a 503 status,
+// a Retry-After header in seconds, and the RetryAfter as the body.
+func retryLater(ctx *fiber.Ctx, span trace.Span, retry *types.RetryAfter) error {
+	span.SetAttributes(attribute.String("back_pressure", retry.Reason))
+	ctx.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(retry.After.Seconds()))))
+	return ctx.Status(fiber.StatusServiceUnavailable).JSON(retry)
+}
+
 // NOTE: duplication for cleaner swagger docs
 // PostTransaction godoc
 //
@@ -100,13 +232,16 @@ func PostTransaction(
 //	@Produce      application/json
 //	@Param        txName  path      string                   true  "Name of a registered message"
 //	@Param        txBody  body      Transaction              true  "Transaction details & message to be submitted"
+//	@Param        Idempotency-Key  header  string  false  "Client-generated key that deduplicates retried submissions"
 //	@Success      200     {object}  PostTransactionResponse  "Transaction hash and tick"
 //	@Failure      400     {string}  string                   "Invalid request parameter"
+//	@Failure      503     {object}  types.RetryAfter         "The world is overloaded, retry after the Retry-After header"
 //	@Router       /tx/game/{txName} [post]
 func PostGameTransaction(
 	provider servertypes.Provider, msgs map[string]map[string]types.Message, disableSigVerification bool,
+	adminSigners []string, apiVersion string,
 ) func(*fiber.Ctx) error {
-	return PostTransaction(provider, msgs, disableSigVerification)
+	return PostTransaction(provider, msgs, disableSigVerification, adminSigners, apiVersion)
 }
 
 // NOTE: duplication for cleaner swagger docs
@@ -119,23 +254,37 @@ func PostGameTransaction(
 //	@Param        txBody  body      Transaction              true  "Transaction details & message to be submitted"
 //	@Success      200     {object}  PostTransactionResponse  "Transaction hash and tick"
 //	@Failure      400     {string}  string                   "Invalid request parameter"
+//	@Failure      503     {object}  types.RetryAfter         "The world is overloaded, retry after the Retry-After header"
 //	@Router       /tx/persona/create-persona [post]
 func PostPersonaTransaction(
 	provider servertypes.Provider, msgs map[string]map[string]types.Message, disableSigVerification bool,
+	adminSigners []string, apiVersion string,
 ) func(*fiber.Ctx) error {
-	return PostTransaction(provider, msgs, disableSigVerification)
+	return PostTransaction(provider, msgs, disableSigVerification, adminSigners, apiVersion)
 }
 
-func lookupSignerAndValidateSignature(provider servertypes.Provider, signerAddress string, tx *Transaction) error {
+func lookupSignerAndValidateSignature(
+	provider servertypes.Provider, msgFullName string, signerAddress string, tx *Transaction,
+) error {
 	var err error
-	if signerAddress == "" {
+	lookedUp := signerAddress == ""
+	if lookedUp {
 		signerAddress, err = provider.GetSignerForPersonaTag(tx.PersonaTag, 0)
 		if err != nil {
 			return fiber.NewError(fiber.StatusBadRequest, "could not get signer for persona: "+err.Error())
 		}
 	}
-	if err = validateSignature(tx, signerAddress, provider.Namespace(),
-		tx.IsSystemTransaction()); err != nil {
+	err = validateSignature(tx, signerAddress, provider.Namespace(), tx.IsSystemTransaction())
+	// A transaction that isn't signed by the persona's signer may be signed by one of its session keys
+	if lookedUp && eris.Is(err, sign.ErrSignatureValidationFailed) {
+		sessionKey, found, lookupErr := findSessionKey(provider, msgFullName, tx)
+		if lookupErr != nil {
+			return fiber.NewError(fiber.StatusInternalServerError, "failed to get session keys: "+lookupErr.Error())
+		} else if found {
+			signerAddress, err = sessionKey, nil
+		}
+	}
+	if err != nil {
 		return fiber.NewError(fiber.StatusBadRequest, "failed to validate transaction: "+err.Error())
 	}
 	// TODO(scott): this should be refactored; it should be the responsibility of the engine tx processor
@@ -146,13 +295,46 @@ func lookupSignerAndValidateSignature(provider servertypes.Provider, signerAddre
 	return nil
 }
 
+// findSessionKey returns the session key of the persona that signed the transaction, if it may sign the message. Each
+// session key has its own nonces.
+func findSessionKey(provider servertypes.Provider, msgFullName string, tx *Transaction) (string, bool, error) {
+	sessionKeys, err := provider.GetSessionKeysForPersonaTag(tx.PersonaTag, msgFullName)
+	if err != nil {
+		return "", false, err
+	}
+	for _, sessionKey := range sessionKeys {
+		if tx.Verify(sessionKey) == nil {
+			return sessionKey, true, nil
+		}
+	}
+	return "", false, nil
+}
+
+// validateAdminSignature validates that the transaction of an admin-only message is signed by one of the admin signers,
+// rather than by the signer of its persona.
+func validateAdminSignature(provider servertypes.Provider, adminSigners []string, tx *Transaction) error {
+	for _, signerAddress := range adminSigners {
+		err := validateSignature(tx, signerAddress, provider.Namespace(), tx.IsSystemTransaction())
+		if eris.Is(err, ErrWrongNamespace) {
+			return fiber.NewError(fiber.StatusBadRequest, "failed to validate transaction: "+err.Error())
+		} else if err != nil {
+			continue
+		}
+		if err = provider.UseNonce(signerAddress, tx.Nonce); err != nil {
+			return fiber.NewError(fiber.StatusInternalServerError, "failed to use nonce: "+err.Error())
+		}
+		return nil
+	}
+	return fiber.NewError(fiber.StatusForbidden, ErrNotAdminSigner.Error())
+}
+
 // validateTx validates the transaction payload
 func validateTx(tx *Transaction) error {
 	// TODO(scott): we should use the validator package here
 	if tx.PersonaTag == "" {
 		return ErrNoPersonaTag
 	}
-	return nil
+	return tx.ValidateCoSigners()
 }
 
 // validateSignature validates that the signature of transaction is valid
@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal/types"
+)
+
+type GetVersionsResponse struct {
+	// Unversioned describes the routes that are served without a version prefix. Its version is the version that
+	// they are an alias of.
+	Unversioned APIVersionInfo `json:"unversioned"`
+	// Latest is the newest version of the API.
+	Latest   string           `json:"latest"`
+	Versions []APIVersionInfo `json:"versions"`
+	Messages []MessageSchema  `json:"messages"`
+}
+
+type APIVersionInfo struct {
+	Version    string     `json:"version"`
+	Deprecated *time.Time `json:"deprecated,omitempty"`
+	Sunset     *time.Time `json:"sunset,omitempty"`
+	Successor  string     `json:"successor,omitempty"`
+}
+
+type MessageSchema struct {
+	Name string `json:"name"` // full name of the message
+	// SchemaHash is the hash of the fields of the message's input. It changes whenever the input's schema changes.
+	SchemaHash string `json:"schemaHash"`
+	// LegacySchemaHashes are the hashes of the legacy inputs that are accepted from clients of older API versions,
+	// keyed by API version.
+	LegacySchemaHashes map[string]string `json:"legacySchemaHashes,omitempty"`
+}
+
+// GetVersions godoc
+//
+//	@Summary      Retrieves the supported versions of the API
+//	@Description  Retrieves the versions of the API that are served, their deprecation and sunset times, and the
+//	@Description  schema hashes of the registered messages, so that clients can check that they are compatible
+//	@Produce      application/json
+//	@Success      200  {object}  GetVersionsResponse  "Supported versions and message schema hashes"
+//	@Router       /versions [get]
+func GetVersions(
+	versions []APIVersionInfo, unversioned APIVersionInfo, latest string, messages []types.Message,
+) func(*fiber.Ctx) error {
+	schemas := make([]MessageSchema, 0, len(messages))
+	var schemaErr error
+	for _, msg := range messages {
+		schema, err := messageSchema(msg)
+		if err != nil {
+			schemaErr = err
+			break
+		}
+		schemas = append(schemas, schema)
+	}
+
+	return func(ctx *fiber.Ctx) error {
+		if schemaErr != nil {
+			return fiber.NewError(fiber.StatusInternalServerError, "failed to hash message schemas: "+schemaErr.Error())
+		}
+		return ctx.JSON(GetVersionsResponse{
+			Unversioned: unversioned,
+			Latest:      latest,
+			Versions:    versions,
+			Messages:    schemas,
+		})
+	}
+}
+
+func messageSchema(msg types.Message) (MessageSchema, error) {
+	hash, err := schemaHash(msg.GetInFieldInformation())
+	if err != nil {
+		return MessageSchema{}, err
+	}
+	schema := MessageSchema{Name: msg.FullName(), SchemaHash: hash}
+	for version, fields := range msg.GetLegacyInFieldInformation() {
+		if schema.LegacySchemaHashes == nil {
+			schema.LegacySchemaHashes = make(map[string]string)
+		}
+		if schema.LegacySchemaHashes[version], err = schemaHash(fields); err != nil {
+			return MessageSchema{}, err
+		}
+	}
+	return schema, nil
+}
+
+// schemaHash returns the hex encoded sha256 hash of the JSON encoding of the fields, in which the keys are sorted.
+func schemaHash(fields map[string]any) (string, error) {
+	bz, err := json.Marshal(fields)
+	if err != nil {
+		return "", eris.Wrap(err, "")
+	}
+	hash := sha256.Sum256(bz)
+	return hex.EncodeToString(hash[:]), nil
+}
@@ -18,8 +18,9 @@ type GetWorldResponse struct {
 }
 
 type FieldDetail struct {
-	Name   string         `json:"name"`   // name of the message or query
-	Fields map[string]any `json:"fields"` // variable name and type
+	Name   string         `json:"name"`            // name of the message or query
+	Fields map[string]any `json:"fields"`          // variable name and type
+	Reply  map[string]any `json:"reply,omitempty"` // variable name and type of the query reply
 	URL    string         `json:"url,omitempty"`
 }
 
@@ -64,6 +65,7 @@ func GetWorld(
 		queriesFields = append(queriesFields, FieldDetail{
 			Name:   query.Name(),
 			Fields: query.GetRequestFieldInformation(),
+			Reply:  query.GetReplyFieldInformation(),
 			URL:    utils.GetQueryURL(query.Group(), query.Name()),
 		})
 	}
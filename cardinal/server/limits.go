@@ -0,0 +1,91 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/cors"
+	"github.com/gofiber/fiber/v2/middleware/limiter"
+)
+
+const (
+	// DefaultMaxBodyBytes is the size above which request bodies are rejected if HTTPLimits.MaxBodyBytes is not set.
+	DefaultMaxBodyBytes = fiber.DefaultBodyLimit
+	// DefaultRateWindow is the window of HTTPLimits.RateLimit if HTTPLimits.RateWindow is not set.
+	DefaultRateWindow = time.Minute
+)
+
+// HTTPLimits restricts who can call the HTTP server and how much. Zero values keep the defaults: every origin is
+// allowed, bodies are limited to DefaultMaxBodyBytes, and requests are not rate limited.
+type HTTPLimits struct {
+	// AllowedOrigins are the origins of the browser-based games that can call the server, e.g.
+	// "https://game.example.com". Browsers refuse to share the replies with pages of other origins.
+	AllowedOrigins []string
+	// MaxBodyBytes is the size above which request bodies are rejected with 413 Request Entity Too Large, before they
+	// are decoded.
+	MaxBodyBytes int
+	// RateLimit is the number of requests that a client IP can make per RateWindow. Further requests are rejected
+	// with 429 Too Many Requests and a Retry-After header. The health checks are not limited.
+	RateLimit  int
+	RateWindow time.Duration
+	// ProxyHeader is the header that carries the IP of the client, e.g. "X-Forwarded-For", if the server runs behind
+	// a reverse proxy. Only set it if the proxy overwrites the header, since clients can set it to any IP.
+	ProxyHeader string
+}
+
+// WithHTTPLimits sets the CORS origins, the maximum body size and the rate limit of the server. See HTTPLimits.
+func WithHTTPLimits(limits HTTPLimits) Option {
+	return func(s *Server) {
+		s.config.limits = limits
+	}
+}
+
+// fiberConfig returns the config of the fiber app that enforces the limits.
+func (l HTTPLimits) fiberConfig() fiber.Config {
+	cfg := fiber.Config{
+		Network:     "tcp", // Enable server listening on both ipv4 & ipv6 (default: ipv4 only)
+		BodyLimit:   DefaultMaxBodyBytes,
+		ProxyHeader: l.ProxyHeader,
+	}
+	if l.MaxBodyBytes > 0 {
+		cfg.BodyLimit = l.MaxBodyBytes
+	}
+	return cfg
+}
+
+// cors returns the CORS middleware of the server. The headers that tell clients to retry later, or which API version
+// served them, are exposed to the browsers.
+func (l HTTPLimits) cors() fiber.Handler {
+	cfg := cors.Config{
+		ExposeHeaders: strings.Join([]string{
+			fiber.HeaderRetryAfter, APIVersionHeader, ReplicaTickHeader, ReplicaStalenessHeader,
+		}, ","),
+	}
+	if len(l.AllowedOrigins) > 0 {
+		cfg.AllowOrigins = strings.Join(l.AllowedOrigins, ",")
+	}
+	return cors.New(cfg)
+}
+
+// rateLimiter returns the middleware that limits the requests per client IP, or nil if they are not limited.
+func (l HTTPLimits) rateLimiter() fiber.Handler {
+	if l.RateLimit <= 0 {
+		return nil
+	}
+	window := l.RateWindow
+	if window <= 0 {
+		window = DefaultRateWindow
+	}
+	return limiter.New(limiter.Config{
+		Max:        l.RateLimit,
+		Expiration: window,
+		Next: func(ctx *fiber.Ctx) bool {
+			return strings.HasSuffix(ctx.Path(), "/health")
+		},
+		LimitReached: func(*fiber.Ctx) error {
+			return fiber.NewError(http.StatusTooManyRequests, "too many requests, retry later")
+		},
+	})
+}
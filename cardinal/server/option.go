@@ -16,9 +16,55 @@ func DisableSignatureVerification() Option {
 	}
 }
 
+// WithAdminSigners sets the addresses that sign the transactions of admin-only messages. Admin-only messages are
+// rejected if no admin signer is set.
+func WithAdminSigners(addresses ...string) Option {
+	return func(s *Server) {
+		s.config.adminSigners = append(s.config.adminSigners, addresses...)
+	}
+}
+
+// WithReplyLimits sets the maximum size of read replies. List replies that are larger are truncated and carry the
+// cursor to read the rest, other replies are rejected. A limit of zero disables it.
+func WithReplyLimits(limits ReplyLimits) Option {
+	return func(s *Server) {
+		s.config.replyLimits = limits
+	}
+}
+
+// WithDebugConfig sets the config that is served by the /debug/config endpoint. Secrets must be redacted from it.
+func WithDebugConfig(cfg map[string]any) Option {
+	return func(s *Server) {
+		s.config.debugConfig = cfg
+	}
+}
+
+// WithPprof serves the runtime profiles of net/http/pprof under /debug/pprof/.
+func WithPprof() Option {
+	return func(s *Server) {
+		s.config.isPprofEnabled = true
+	}
+}
+
+// WithCQLFilters allows CQL queries to compare the fields of components in a WHERE clause. Such queries read every
+// entity of their filter expression, so they are meant for debugging and tooling.
+func WithCQLFilters() Option {
+	return func(s *Server) {
+		s.config.isCQLFiltersEnabled = true
+	}
+}
+
 // DisableSwagger allows to disable the swagger setup of the server.
 func DisableSwagger() Option {
 	return func(s *Server) {
 		s.config.isSwaggerDisabled = true
 	}
 }
+
+// WithVersionPolicy sets the deprecation and sunset policy of a version of the HTTP API. Use Unversioned to set the
+// policy of the routes that are served without a version prefix.
+func WithVersionPolicy(version string, policy VersionPolicy) Option {
+	return func(s *Server) {
+		s.config.versionPolicies[version] = policy
+	}
+}
@@ -32,9 +32,11 @@ func (s *ServerTestSuite) TestReceiptsQuery() {
 
 	fooMsg, ok := world.GetMessageByFullName("game." + msgName)
 	s.Require().True(ok)
-	_, txHash1 := world.AddTransaction(fooMsg.ID(), fooIn{}, &sign.Transaction{PersonaTag: "alpha"})
+	_, txHash1, err := world.AddTransaction(fooMsg.ID(), fooIn{}, &sign.Transaction{PersonaTag: "alpha"})
+	s.Require().NoError(err)
 	s.fixture.DoTick()
-	_, txHash2 := world.AddTransaction(fooMsg.ID(), fooIn{}, &sign.Transaction{PersonaTag: "beta"})
+	_, txHash2, err := world.AddTransaction(fooMsg.ID(), fooIn{}, &sign.Transaction{PersonaTag: "beta"})
+	s.Require().NoError(err)
 	s.fixture.DoTick()
 
 	s.Require().NotEqual(txHash1, txHash2)
@@ -52,6 +54,7 @@ func (s *ServerTestSuite) TestReceiptsQuery() {
 	expectedReceipt1 := handler.ReceiptEntry{
 		TxHash: string(txHash1),
 		Tick:   0,
+		Status: "success",
 		Result: fooOut{Y: 4},
 		Errors: nil,
 	}
@@ -60,6 +63,7 @@ func (s *ServerTestSuite) TestReceiptsQuery() {
 	expectedReceipt2 := handler.ReceiptEntry{
 		TxHash: string(txHash2),
 		Tick:   1,
+		Status: "failed",
 		Result: nil,
 		Errors: []string{wantErrorMessage},
 	}
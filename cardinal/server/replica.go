@@ -0,0 +1,59 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	// ReplicaTickHeader is set by read replicas to the last tick that was committed by the instance that ticks.
+	ReplicaTickHeader = "Cardinal-Replica-Tick"
+	// ReplicaStalenessHeader is set by read replicas to how long ago they saw the last committed tick, in
+	// milliseconds. The state that is served is at most that much behind the instance that ticks.
+	ReplicaStalenessHeader = "Cardinal-Replica-Staleness"
+)
+
+// ReadReplica configures the server of a read replica, which serves the game state that another world instance
+// commits without ticking itself.
+type ReadReplica struct {
+	// Staleness returns the last committed tick, and how long ago the replica saw it.
+	Staleness func() (tick uint64, staleness time.Duration)
+	// MaxStaleness is the staleness above which requests are rejected with 503 Service Unavailable, so that clients
+	// and load balancers turn to another instance. Zero disables the bound.
+	MaxStaleness time.Duration
+	// AcceptsTransactions is true if the replica forwards transactions to the instance that ticks. Otherwise,
+	// transactions are rejected with 405 Method Not Allowed.
+	AcceptsTransactions bool
+}
+
+// WithReadReplica serves the routes of a read replica: every response carries the tick and staleness of the served
+// state, see ReplicaTickHeader and ReplicaStalenessHeader.
+func WithReadReplica(replica ReadReplica) Option {
+	return func(s *Server) {
+		s.config.readReplica = &replica
+	}
+}
+
+// replicaStaleness sets the staleness headers of a read replica, and rejects requests if the replica is too stale.
+func (s *Server) replicaStaleness(ctx *fiber.Ctx) error {
+	tick, staleness := s.config.readReplica.Staleness()
+	ctx.Set(ReplicaTickHeader, strconv.FormatUint(tick, 10))
+	ctx.Set(ReplicaStalenessHeader, strconv.FormatInt(staleness.Milliseconds(), 10))
+	if maxStaleness := s.config.readReplica.MaxStaleness; maxStaleness > 0 && staleness > maxStaleness {
+		ctx.Set(fiber.HeaderRetryAfter, "1")
+		return fiber.NewError(http.StatusServiceUnavailable, "read replica is "+staleness.String()+
+			" behind, more than "+maxStaleness.String())
+	}
+	return ctx.Next()
+}
+
+// acceptsTransactions rejects transactions that are submitted to a read replica that doesn't forward them.
+func (s *Server) acceptsTransactions(ctx *fiber.Ctx) error {
+	if s.config.readReplica != nil && !s.config.readReplica.AcceptsTransactions {
+		return fiber.NewError(http.StatusMethodNotAllowed, "read replicas don't accept transactions")
+	}
+	return ctx.Next()
+}
@@ -3,16 +3,21 @@ package server
 import (
 	"encoding/json"
 	"os"
+	"slices"
+	"sync"
 
 	"github.com/gofiber/contrib/socketio"
 	"github.com/gofiber/fiber/v2"
-	"github.com/gofiber/fiber/v2/middleware/cors"
+	"github.com/gofiber/fiber/v2/middleware/pprof"
 	"github.com/gofiber/swagger"
 	"github.com/rotisserie/eris"
 	"github.com/rs/zerolog/log"
+	"github.com/valyala/fasthttp"
 
+	"pkg.world.dev/world-engine/cardinal/gamestate"
 	"pkg.world.dev/world-engine/cardinal/server/handler"
 	servertypes "pkg.world.dev/world-engine/cardinal/server/types"
+	"pkg.world.dev/world-engine/cardinal/tracing"
 	"pkg.world.dev/world-engine/cardinal/types"
 	"pkg.world.dev/world-engine/cardinal/types/engine"
 
@@ -23,15 +28,40 @@ const (
 	DefaultPort = "4040"
 )
 
+// ReplyLimits bounds the size of the replies of read requests. See handler.ReplyLimits.
+type ReplyLimits = handler.ReplyLimits
+
 type config struct {
 	port                            string
 	isSignatureVerificationDisabled bool
 	isSwaggerDisabled               bool
+	isPprofEnabled                  bool
+	isCQLFiltersEnabled             bool
+	versionPolicies                 map[string]VersionPolicy
+	adminSigners                    []string
+	replyLimits                     ReplyLimits
+	debugConfig                     map[string]any
+	// readReplica is set if the server belongs to a read replica. See WithReadReplica.
+	readReplica *ReadReplica
+	// auth is set if the requests to private routes must be authenticated. See WithAuth.
+	auth *Auth
+	// limits are the CORS origins, body size and rate limits of the server. See WithHTTPLimits.
+	limits HTTPLimits
 }
 
 type Server struct {
-	app    *fiber.App
-	config config
+	app      *fiber.App
+	config   config
+	provider servertypes.Provider
+
+	// sockets are the websocket connections that were established with this server. Events are only broadcast to
+	// these connections, so that several servers can run in the same process without leaking events to each other.
+	socketsMu sync.Mutex
+	sockets   []*socketio.Websocket
+
+	// spectators are the connections of the state stream, which are sent the diff of every tick instead of events.
+	spectatorsMu sync.Mutex
+	spectators   []*handler.Spectator
 }
 
 // New returns an HTTP server with handlers for all QueryTypes and MessageTypes.
@@ -39,24 +69,50 @@ func New(
 	provider servertypes.Provider, wCtx engine.Context, components []types.ComponentMetadata,
 	messages []types.Message, queries []engine.Query, opts ...Option,
 ) (*Server, error) {
-	app := fiber.New(fiber.Config{
-		Network: "tcp", // Enable server listening on both ipv4 & ipv6 (default: ipv4 only)
-	})
-
 	s := &Server{
-		app: app,
+		provider: provider,
 		config: config{
 			port:                            DefaultPort,
 			isSignatureVerificationDisabled: false,
 			isSwaggerDisabled:               false,
+			versionPolicies:                 map[string]VersionPolicy{},
+			replyLimits: ReplyLimits{
+				MaxBytes: handler.DefaultMaxReplyBytes,
+				MaxItems: handler.DefaultMaxReplyItems,
+			},
 		},
 	}
 	for _, opt := range opts {
 		opt(s)
 	}
+	app := fiber.New(s.config.limits.fiberConfig())
+	s.app = app
 
 	// Enable CORS
-	app.Use(cors.New())
+	app.Use(s.config.limits.cors())
+
+	// Limit the requests per client IP
+	if rateLimiter := s.config.limits.rateLimiter(); rateLimiter != nil {
+		app.Use(rateLimiter)
+	}
+
+	// Continue the traces of clients that send a W3C trace context
+	app.Use(tracing.ExtractHTTPContext)
+
+	// Reject the unauthenticated requests to private routes
+	if s.config.auth != nil {
+		app.Use(s.authenticate)
+	}
+
+	// Report how stale the state of a read replica is
+	if s.config.readReplica != nil {
+		app.Use(s.replicaStaleness)
+	}
+
+	// Route: /debug/pprof/
+	if s.config.isPprofEnabled {
+		app.Use(pprof.New())
+	}
 
 	// Register routes
 	s.setupRoutes(provider, wCtx, messages, queries, components)
@@ -82,21 +138,86 @@ func (s *Server) Serve() error {
 	return nil
 }
 
+// Handler returns the request handler of the server. It can be used to serve this server's routes from another
+// HTTP server instead of calling Serve.
+func (s *Server) Handler() fasthttp.RequestHandler {
+	return s.app.Handler()
+}
+
 func (s *Server) BroadcastEvent(event any) error {
 	eventBz, err := json.Marshal(event)
 	if err != nil {
 		return err
 	}
-	socketio.Broadcast(eventBz)
+	for _, kws := range s.liveSockets() {
+		kws.Emit(eventBz)
+	}
 	return nil
 }
 
+func (s *Server) addSocket(kws *socketio.Websocket) {
+	s.socketsMu.Lock()
+	defer s.socketsMu.Unlock()
+	s.sockets = append(s.sockets, kws)
+}
+
+// liveSockets returns the connections that are still open and forgets the ones that have been closed.
+func (s *Server) liveSockets() []*socketio.Websocket {
+	s.socketsMu.Lock()
+	defer s.socketsMu.Unlock()
+	s.sockets = slices.DeleteFunc(s.sockets, func(kws *socketio.Websocket) bool { return !kws.IsAlive() })
+	return slices.Clone(s.sockets)
+}
+
+func (s *Server) addSpectator(spectator *handler.Spectator) {
+	s.spectatorsMu.Lock()
+	defer s.spectatorsMu.Unlock()
+	s.spectators = append(s.spectators, spectator)
+}
+
+// StreamStateDiffs sends the spectators of the state stream the diff of the ticks up to, but not including, the given
+// tick. It must be called between ticks, so that the entities that enter the filter of a spectator are sent with the
+// state at the given tick. A spectator whose diff can't be sent, e.g. because it fell so far behind that the diffs it
+// needs were deleted, is disconnected.
+func (s *Server) StreamStateDiffs(tick uint64) {
+	s.spectatorsMu.Lock()
+	s.spectators = slices.DeleteFunc(s.spectators, func(spectator *handler.Spectator) bool {
+		return !spectator.IsAlive()
+	})
+	spectators := slices.Clone(s.spectators)
+	s.spectatorsMu.Unlock()
+
+	// Spectators usually wait for the same ticks, so each diff is read once
+	diffs := map[uint64]gamestate.StateDiff{}
+	for _, spectator := range spectators {
+		if spectator.Tick() >= tick {
+			continue
+		}
+		diff, ok := diffs[spectator.Tick()]
+		if !ok {
+			var err error
+			if diff, err = s.provider.Diff(spectator.Tick(), tick); err != nil {
+				log.Warn().Err(err).Uint64("tick", spectator.Tick()).Msg("disconnecting spectator of the state stream")
+				spectator.Close(err)
+				continue
+			}
+			diffs[spectator.Tick()] = diff
+		}
+		if err := spectator.SendDiff(s.provider, diff); err != nil {
+			log.Warn().Err(err).Uint64("tick", spectator.Tick()).Msg("disconnecting spectator of the state stream")
+			spectator.Close(err)
+		}
+	}
+}
+
 // Shutdown gracefully shuts down the server and closes all active websocket connections.
 func (s *Server) Shutdown() error {
 	log.Info().Msg("Shutting down server")
 
 	// Close websocket connections
-	socketio.Broadcast([]byte(""), socketio.CloseMessage)
+	for _, kws := range s.liveSockets() {
+		kws.Emit([]byte(""), socketio.CloseMessage)
+	}
 	socketio.Fire(socketio.EventClose, nil)
 
 	// Gracefully shutdown Fiber server
@@ -152,28 +273,90 @@ func (s *Server) setupRoutes(
 		s.app.Get("/swagger/*", swagger.HandlerDefault)
 	}
 
+	// Every version is served under its own prefix, so that clients can be migrated from one version to the next
+	// while both are served.
+	versions := []apiVersion{
+		{
+			name: APIVersionV1,
+			register: func(r fiber.Router, version fiber.Handler) {
+				s.setupVersionRoutes(r, APIVersionV1, version, provider, wCtx, messages, queries, components,
+					queryIndex, msgIndex)
+			},
+		},
+		{
+			name: APIVersionV2,
+			register: func(r fiber.Router, version fiber.Handler) {
+				s.setupVersionRoutes(r, APIVersionV2, version, provider, wCtx, messages, queries, components,
+					queryIndex, msgIndex)
+			},
+		},
+	}
+	versionInfos := make([]handler.APIVersionInfo, 0, len(versions))
+	for _, v := range versions {
+		v.register(s.app.Group("/"+v.name), s.versionHandler(v.name, v.name))
+		if v.name == CurrentAPIVersion {
+			v.register(s.app, s.versionHandler(Unversioned, v.name))
+		}
+		versionInfos = append(versionInfos, s.versionInfo(v.name, v.name))
+	}
+
+	// Route: /versions
+	// Not versioned itself, so that clients of any version, including sunset ones, can negotiate a version.
+	s.app.Get("/versions", handler.GetVersions(
+		versionInfos, s.versionInfo(Unversioned, CurrentAPIVersion), LatestAPIVersion, messages))
+}
+
+// setupVersionRoutes registers the routes of the given version of the API. The versions only differ in how message
+// payloads are decoded, see APIVersionV2.
+func (s *Server) setupVersionRoutes(
+	r fiber.Router, apiVersion string, version fiber.Handler, provider servertypes.Provider, wCtx engine.Context,
+	messages []types.Message, queries []engine.Query, components []types.ComponentMetadata,
+	queryIndex map[string]map[string]engine.Query, msgIndex map[string]map[string]types.Message,
+) {
 	// Route: /events/
-	s.app.Use("/events", handler.WebSocketUpgrader)
-	s.app.Get("/events", handler.WebSocketEvents())
+	r.Use("/events", version, handler.WebSocketUpgrader)
+	r.Get("/events", handler.GetEventHistory(provider, s.config.replyLimits), handler.WebSocketEvents(s.addSocket))
 
 	// Route: /world
-	s.app.Get("/world", handler.GetWorld(components, messages, queries, wCtx.Namespace()))
+	r.Get("/world", version, handler.GetWorld(components, messages, queries, wCtx.Namespace()))
 
 	// Route: /...
-	s.app.Get("/health", handler.GetHealth())
+	r.Get("/health", version, handler.GetHealth(provider))
 
 	// Route: /query/...
-	query := s.app.Group("/query")
-	query.Post("/receipts/list", handler.GetReceipts(wCtx))
-	query.Post("/:group/:name", handler.PostQuery(queryIndex, wCtx))
+	r.Post("/query/receipts/list", version, handler.GetReceipts(wCtx, s.config.replyLimits))
+	r.Post("/query/:group/:name", version, handler.PostQuery(queryIndex, wCtx, s.config.replyLimits))
 
 	// Route: /tx/...
-	tx := s.app.Group("/tx")
-	tx.Post("/:group/:name", handler.PostTransaction(provider, msgIndex, s.config.isSignatureVerificationDisabled))
+	r.Post("/tx/cosign", version, s.acceptsTransactions,
+		handler.PostCoSignature(provider, s.config.isSignatureVerificationDisabled))
+	r.Post("/tx/simulate/:group/:name", version, handler.PostSimulateTransaction(provider, msgIndex, apiVersion))
+	r.Post("/tx/:group/:name", version, s.acceptsTransactions,
+		handler.PostTransaction(provider, msgIndex, s.config.isSignatureVerificationDisabled, s.config.adminSigners,
+			apiVersion))
 
 	// Route: /cql
-	s.app.Post("/cql", handler.PostCQL(provider))
+	r.Post("/cql", version, handler.PostCQL(provider, s.config.replyLimits, s.config.isCQLFiltersEnabled))
+
+	// Route: /state/...
+	r.Get("/state/commitments", version, handler.GetStateCommitments(provider))
+	r.Post("/state/proof", version, handler.PostStateProof(provider))
+	r.Post("/state/diff", version, handler.PostStateDiff(provider))
+	r.Get("/state/stream", version, handler.GetStateStream(provider),
+		handler.WebSocketStateStream(provider, s.addSpectator))
 
 	// Route: /debug/state
-	s.app.Post("/debug/state", handler.GetDebugState(provider))
+	r.Post("/debug/state", version, handler.GetDebugState(provider, s.config.replyLimits))
+
+	// Route: /debug/archetypes
+	r.Get("/debug/archetypes", version, handler.GetDebugArchetypes(provider))
+
+	// Route: /debug/layout
+	r.Get("/debug/layout", version, handler.GetDebugLayout(provider))
+
+	// Route: /debug/systems
+	r.Get("/debug/systems", version, handler.GetDebugSystems(provider))
+
+	// Route: /debug/config
+	r.Get("/debug/config", version, handler.GetDebugConfig(s.config.debugConfig))
 }
@@ -1,7 +1,11 @@
 package server_test
 
 import (
+	"context"
 	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -12,6 +16,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/gofiber/fiber/v2"
 	"github.com/stretchr/testify/suite"
@@ -22,6 +27,7 @@ import (
 	"pkg.world.dev/world-engine/cardinal/message"
 	"pkg.world.dev/world-engine/cardinal/persona/msg"
 	"pkg.world.dev/world-engine/cardinal/query"
+	"pkg.world.dev/world-engine/cardinal/server"
 	"pkg.world.dev/world-engine/cardinal/server/handler"
 	"pkg.world.dev/world-engine/cardinal/server/utils"
 	"pkg.world.dev/world-engine/cardinal/testutils"
@@ -75,7 +81,7 @@ func (s *ServerTestSuite) SetupTest() {
 
 // TearDownTest runs after each test in the suite.
 func (s *ServerTestSuite) TearDownTest() {
-	s.Require().NoError(s.fixture.World.Shutdown())
+	s.Require().NoError(s.fixture.World.Shutdown(context.Background()))
 }
 
 // TestCanClaimPersonaSendGameTxAndQueryGame tests that you can claim a persona, send a tx, and then query.
@@ -218,6 +224,238 @@ func (s *ServerTestSuite) TestQueryCustomGroup() {
 	s.Require().True(called)
 }
 
+func (s *ServerTestSuite) TestVersionedRoutes() {
+	deprecated := time.Now().Add(-time.Hour)
+	sunset := time.Now().Add(24 * time.Hour)
+	s.setupWorld(cardinal.WithAPIVersionPolicy(server.Unversioned, server.VersionPolicy{
+		Deprecated: deprecated,
+		Sunset:     sunset,
+		Successor:  server.APIVersionV1,
+	}))
+	s.fixture.DoTick()
+
+	// The current version is served both with and without a version prefix.
+	res := s.fixture.Get("/v1/health")
+	s.Require().Equal(fiber.StatusOK, res.StatusCode)
+	s.Require().Equal(server.APIVersionV1, res.Header.Get(server.APIVersionHeader))
+	s.Require().Empty(res.Header.Get("Deprecation"))
+
+	// Clients that do not use a version prefix are told to migrate.
+	res = s.fixture.Get("/health")
+	s.Require().Equal(fiber.StatusOK, res.StatusCode)
+	s.Require().Equal(server.APIVersionV1, res.Header.Get(server.APIVersionHeader))
+	s.Require().Equal(fmt.Sprintf("@%d", deprecated.Unix()), res.Header.Get("Deprecation"))
+	s.Require().Equal(sunset.UTC().Format(http.TimeFormat), res.Header.Get("Sunset"))
+	s.Require().Equal(`</v1>; rel="successor-version"`, res.Header.Get("Link"))
+
+	res = s.fixture.Post("/v1"+utils.GetQueryURL("game", "location"), QueryLocationRequest{Persona: "nobody"})
+	s.Require().NotEqual(fiber.StatusNotFound, res.StatusCode)
+}
+
+func (s *ServerTestSuite) TestSunsetVersionIsGone() {
+	s.setupWorld(cardinal.WithAPIVersionPolicy(server.Unversioned, server.VersionPolicy{
+		Sunset: time.Now().Add(-time.Hour),
+	}))
+	s.fixture.DoTick()
+
+	res := s.fixture.Get("/health")
+	s.Require().Equal(fiber.StatusGone, res.StatusCode)
+	res = s.fixture.Get("/v1/health")
+	s.Require().Equal(fiber.StatusOK, res.StatusCode)
+}
+
+func (s *ServerTestSuite) TestPrivateRoutesRequireAuthentication() {
+	secret := []byte("secret")
+	s.setupWorld(cardinal.WithHTTPAuth(server.Auth{
+		APIKeys: []string{"nakama-key"},
+		JWT:     &server.JWTAuth{Secret: secret, Audience: "game"},
+	}))
+	s.fixture.DoTick()
+	queryURL := utils.GetQueryURL("game", "location")
+	query := func(path string, header http.Header) int {
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodPost,
+			"http://"+s.fixture.BaseURL+path, strings.NewReader(`{"Persona":"nobody"}`))
+		s.Require().NoError(err)
+		req.Header = header
+		req.Header.Set("Content-Type", "application/json")
+		res, err := http.DefaultClient.Do(req)
+		s.Require().NoError(err)
+		s.Require().NoError(res.Body.Close())
+		return res.StatusCode
+	}
+	bearer := func(claims map[string]any) http.Header {
+		return http.Header{"Authorization": {"Bearer " + signJWT(s.T(), secret, claims)}}
+	}
+	exp := time.Now().Add(time.Hour).Unix()
+
+	// Public routes are served without credentials
+	s.Require().Equal(fiber.StatusOK, s.fixture.Get("/health").StatusCode)
+	s.Require().Equal(fiber.StatusOK, s.fixture.Get("/v1/world").StatusCode)
+
+	s.Require().Equal(fiber.StatusUnauthorized, query(queryURL, http.Header{}))
+	s.Require().Equal(fiber.StatusUnauthorized, query("/v2"+queryURL, http.Header{}))
+	s.Require().Equal(fiber.StatusUnauthorized, query(queryURL, http.Header{server.APIKeyHeader: {"wrong-key"}}))
+	s.Require().Equal(fiber.StatusUnauthorized, query(queryURL, bearer(map[string]any{"aud": "game"})))
+	s.Require().Equal(fiber.StatusUnauthorized,
+		query(queryURL, bearer(map[string]any{"aud": "game", "exp": time.Now().Add(-time.Hour).Unix()})))
+	s.Require().Equal(fiber.StatusUnauthorized, query(queryURL, bearer(map[string]any{"aud": "other", "exp": exp})))
+	s.Require().Equal(fiber.StatusUnauthorized, query(queryURL,
+		http.Header{"Authorization": {"Bearer " + signJWT(s.T(), []byte("other"), map[string]any{"exp": exp})}}))
+
+	// The query fails because the persona has no location, but the request is authenticated
+	s.Require().Equal(fiber.StatusBadRequest, query(queryURL, http.Header{server.APIKeyHeader: {"nakama-key"}}))
+	s.Require().Equal(fiber.StatusBadRequest,
+		query(queryURL, bearer(map[string]any{"aud": []string{"game"}, "exp": exp})))
+	s.Require().Equal(fiber.StatusBadRequest, query(queryURL+"?"+server.AccessTokenParam+"=nakama-key", http.Header{}))
+}
+
+func (s *ServerTestSuite) TestCORSOriginsAndBodySizeAreLimited() {
+	s.setupWorld(cardinal.WithHTTPLimits(server.HTTPLimits{
+		AllowedOrigins: []string{"https://game.example.com"},
+		MaxBodyBytes:   1024,
+	}))
+	s.fixture.DoTick()
+	getFrom := func(origin string) *http.Response {
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodGet,
+			"http://"+s.fixture.BaseURL+"/world", nil)
+		s.Require().NoError(err)
+		req.Header.Set("Origin", origin)
+		res, err := http.DefaultClient.Do(req)
+		s.Require().NoError(err)
+		s.Require().NoError(res.Body.Close())
+		return res
+	}
+
+	res := getFrom("https://game.example.com")
+	s.Require().Equal(fiber.StatusOK, res.StatusCode)
+	s.Require().Equal("https://game.example.com", res.Header.Get(fiber.HeaderAccessControlAllowOrigin))
+	s.Require().Empty(getFrom("https://evil.example.com").Header.Get(fiber.HeaderAccessControlAllowOrigin))
+
+	res = s.fixture.Post(utils.GetQueryURL("game", "location"),
+		QueryLocationRequest{Persona: strings.Repeat("a", 2048)})
+	s.Require().Equal(fiber.StatusRequestEntityTooLarge, res.StatusCode)
+	res = s.fixture.Post(utils.GetQueryURL("game", "location"), QueryLocationRequest{Persona: "nobody"})
+	s.Require().Equal(fiber.StatusBadRequest, res.StatusCode)
+}
+
+func (s *ServerTestSuite) TestRequestsAreRateLimitedPerIP() {
+	s.setupWorld(cardinal.WithHTTPLimits(server.HTTPLimits{RateLimit: 3, RateWindow: time.Minute}))
+	s.fixture.DoTick()
+
+	for i := 0; i < 3; i++ {
+		s.Require().Equal(fiber.StatusOK, s.fixture.Get("/world").StatusCode)
+	}
+	res := s.fixture.Get("/world")
+	s.Require().Equal(fiber.StatusTooManyRequests, res.StatusCode)
+	s.Require().NotEmpty(res.Header.Get(fiber.HeaderRetryAfter))
+	// Health checks are not limited
+	s.Require().Equal(fiber.StatusOK, s.fixture.Get("/health").StatusCode)
+}
+
+// signJWT returns a JWT with the given claims, signed with HS256.
+func signJWT(t *testing.T, secret []byte, claims map[string]any) string {
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+	payload, err := json.Marshal(claims)
+	assert.NilError(t, err)
+	unsigned := header + "." + base64.RawURLEncoding.EncodeToString(payload)
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(unsigned))
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+type TurnMsgInput struct {
+	Degrees int
+}
+
+// TurnMsgInputV1 is the input of the turn message that clients of API version v1 send.
+type TurnMsgInputV1 struct {
+	Left bool
+}
+
+type TurnMsgOutput struct{}
+
+// setupTurnMessage registers a turn message whose input changed after API version v1, and returns the inputs that its
+// system received.
+func (s *ServerTestSuite) setupTurnMessage() *[]TurnMsgInput {
+	var received []TurnMsgInput
+	err := cardinal.RegisterMessage[TurnMsgInput, TurnMsgOutput](s.world, "turn",
+		message.WithLegacyInput[TurnMsgInput, TurnMsgOutput](server.APIVersionV1,
+			func(legacy TurnMsgInputV1) (TurnMsgInput, error) {
+				if legacy.Left {
+					return TurnMsgInput{Degrees: -90}, nil
+				}
+				return TurnMsgInput{Degrees: 90}, nil
+			}))
+	s.Require().NoError(err)
+	err = cardinal.RegisterSystems(s.world, func(wCtx engine.Context) error {
+		return cardinal.EachMessage[TurnMsgInput, TurnMsgOutput](wCtx,
+			func(tx message.TxData[TurnMsgInput]) (TurnMsgOutput, error) {
+				received = append(received, tx.Msg)
+				return TurnMsgOutput{}, nil
+			})
+	})
+	s.Require().NoError(err)
+	return &received
+}
+
+func (s *ServerTestSuite) TestLegacyMessageInputIsUpgraded() {
+	s.setupWorld(cardinal.WithDisableSignatureVerification())
+	received := s.setupTurnMessage()
+	s.fixture.DoTick()
+
+	// Clients of v1, including those that don't use a version prefix, send the legacy input.
+	for _, url := range []string{"/v1" + utils.GetTxURL("game", "turn"), utils.GetTxURL("game", "turn")} {
+		tx, err := sign.NewTransaction(s.privateKey, "some-persona", s.world.Namespace(), s.nonce,
+			TurnMsgInputV1{Left: true})
+		s.Require().NoError(err)
+		res := s.fixture.Post(url, tx)
+		s.Require().Equal(fiber.StatusOK, res.StatusCode, s.readBody(res.Body))
+		s.nonce++
+	}
+	tx, err := sign.NewTransaction(s.privateKey, "some-persona", s.world.Namespace(), s.nonce,
+		TurnMsgInput{Degrees: 45})
+	s.Require().NoError(err)
+	res := s.fixture.Post("/v2"+utils.GetTxURL("game", "turn"), tx)
+	s.Require().Equal(fiber.StatusOK, res.StatusCode, s.readBody(res.Body))
+	s.Require().Equal(server.APIVersionV2, res.Header.Get(server.APIVersionHeader))
+	s.fixture.DoTick()
+
+	s.Require().ElementsMatch([]TurnMsgInput{{Degrees: -90}, {Degrees: -90}, {Degrees: 45}}, *received)
+}
+
+func (s *ServerTestSuite) TestGetVersions() {
+	sunset := time.Now().Add(24 * time.Hour)
+	s.setupWorld(cardinal.WithAPIVersionPolicy(server.APIVersionV1, server.VersionPolicy{
+		Sunset:    sunset,
+		Successor: server.APIVersionV2,
+	}))
+	s.setupTurnMessage()
+	s.fixture.DoTick()
+
+	res := s.fixture.Get("/versions")
+	s.Require().Equal(fiber.StatusOK, res.StatusCode)
+	var result handler.GetVersionsResponse
+	s.Require().NoError(json.Unmarshal([]byte(s.readBody(res.Body)), &result))
+	s.Require().Equal(server.LatestAPIVersion, result.Latest)
+	s.Require().Equal(server.CurrentAPIVersion, result.Unversioned.Version)
+	s.Require().Len(result.Versions, 2)
+	s.Require().Equal(server.APIVersionV1, result.Versions[0].Version)
+	s.Require().Equal(server.APIVersionV2, result.Versions[0].Successor)
+	s.Require().NotNil(result.Versions[0].Sunset)
+	s.Require().Equal(sunset.Unix(), result.Versions[0].Sunset.Unix())
+	s.Require().Equal(server.APIVersionV2, result.Versions[1].Version)
+	s.Require().Nil(result.Versions[1].Sunset)
+
+	i := slices.IndexFunc(result.Messages, func(schema handler.MessageSchema) bool {
+		return schema.Name == "game.turn"
+	})
+	s.Require().NotEqual(-1, i)
+	turn := result.Messages[i]
+	s.Require().NotEmpty(turn.SchemaHash)
+	s.Require().Len(turn.LegacySchemaHashes, 1)
+	s.Require().NotEqual(turn.SchemaHash, turn.LegacySchemaHashes[server.APIVersionV1])
+}
+
 func (s *ServerTestSuite) TestMissingSignerAddressIsOKWhenSigVerificationIsDisabled() {
 	t := s.T()
 	s.setupWorld(cardinal.WithDisableSignatureVerification())
@@ -248,6 +486,310 @@ func (s *ServerTestSuite) TestSignerAddressIsRequiredWhenSigVerificationIsDisabl
 	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
 }
 
+func (s *ServerTestSuite) TestAdminMessageRequiresAdminSigner() {
+	adminKey, err := crypto.GenerateKey()
+	s.Require().NoError(err)
+	s.setupWorld(cardinal.WithAdminSigners(crypto.PubkeyToAddress(adminKey.PublicKey).Hex()))
+	type GrantGold struct{ Amount int }
+	type GrantGoldResult struct{}
+	s.Require().NoError(cardinal.RegisterMessage[GrantGold, GrantGoldResult](s.world, "grant-gold",
+		message.WithAdminOnly[GrantGold, GrantGoldResult]()))
+	s.fixture.DoTick()
+	persona := s.CreateRandomPersona()
+	url := utils.GetTxURL("game", "grant-gold")
+
+	// The persona's own signature is not enough.
+	tx, err := sign.NewTransaction(s.privateKey, persona, s.world.Namespace(), s.nonce, GrantGold{Amount: 10})
+	s.Require().NoError(err)
+	res := s.fixture.Post(url, tx)
+	s.Require().Equal(fiber.StatusForbidden, res.StatusCode, s.readBody(res.Body))
+
+	tx, err = sign.NewTransaction(adminKey, persona, s.world.Namespace(), 0, GrantGold{Amount: 10})
+	s.Require().NoError(err)
+	res = s.fixture.Post(url, tx)
+	s.Require().Equal(fiber.StatusOK, res.StatusCode, s.readBody(res.Body))
+	// Execute the queued transaction, so that the world is not shut down with it still in the pool
+	s.fixture.DoTick()
+}
+
+func (s *ServerTestSuite) TestSessionKeyCanSignScopedMessages() {
+	s.setupWorld()
+	s.world.RegisterPlugin(cardinal.NewSessionKeyPlugin())
+	s.fixture.DoTick()
+	persona := s.CreateRandomPersona()
+	sessionKey, err := crypto.GenerateKey()
+	s.Require().NoError(err)
+	authorizeMsg, ok := s.world.GetMessageByFullName("persona." + msg.AuthorizeSessionKeyMessageName)
+	s.Require().True(ok)
+	s.runTx(persona, authorizeMsg, msg.AuthorizeSessionKey{
+		Address:       crypto.PubkeyToAddress(sessionKey.PublicKey).Hex(),
+		Messages:      []string{"game." + moveMsgName},
+		ExpiresAtTick: s.world.CurrentTick() + 3,
+	})
+
+	// The session key has its own nonces.
+	moveURL := utils.GetTxURL("game", moveMsgName)
+	tx, err := sign.NewTransaction(sessionKey, persona, s.world.Namespace(), 0, MoveMsgInput{Direction: "up"})
+	s.Require().NoError(err)
+	res := s.fixture.Post(moveURL, tx)
+	s.Require().Equal(fiber.StatusOK, res.StatusCode, s.readBody(res.Body))
+	s.fixture.DoTick()
+
+	// The session key may only sign the messages it was authorized for.
+	tx, err = sign.NewTransaction(sessionKey, persona, s.world.Namespace(), 1,
+		msg.AuthorizePersonaAddress{Address: s.signerAddr})
+	s.Require().NoError(err)
+	res = s.fixture.Post(utils.GetTxURL("game", "authorize-persona-address"), tx)
+	s.Require().Equal(fiber.StatusBadRequest, res.StatusCode, s.readBody(res.Body))
+
+	// The session key expires.
+	s.fixture.DoTick()
+	tx, err = sign.NewTransaction(sessionKey, persona, s.world.Namespace(), 2, MoveMsgInput{Direction: "up"})
+	s.Require().NoError(err)
+	res = s.fixture.Post(moveURL, tx)
+	s.Require().Equal(fiber.StatusBadRequest, res.StatusCode, s.readBody(res.Body))
+}
+
+func (s *ServerTestSuite) TestTransactionsAreRejectedWhenTheQueueIsFull() {
+	s.setupWorld(cardinal.WithBackPressure(cardinal.BackPressure{MaxQueuedTxs: 1}))
+	s.fixture.DoTick()
+	persona := s.CreateRandomPersona()
+	s.createPersona(persona)
+	moveURL := utils.GetTxURL("game", moveMsgName)
+
+	tx, err := sign.NewTransaction(s.privateKey, persona, s.world.Namespace(), s.nonce, MoveMsgInput{Direction: "up"})
+	s.Require().NoError(err)
+	res := s.fixture.Post(moveURL, tx)
+	s.Require().Equal(fiber.StatusOK, res.StatusCode, s.readBody(res.Body))
+	s.nonce++
+
+	tx, err = sign.NewTransaction(s.privateKey, persona, s.world.Namespace(), s.nonce, MoveMsgInput{Direction: "up"})
+	s.Require().NoError(err)
+	res = s.fixture.Post(moveURL, tx)
+	s.Require().Equal(fiber.StatusServiceUnavailable, res.StatusCode)
+	s.Require().Equal("1", res.Header.Get(fiber.HeaderRetryAfter))
+	var retry types.RetryAfter
+	s.Require().NoError(json.Unmarshal([]byte(s.readBody(res.Body)), &retry))
+	s.Require().Equal(types.RetryReasonQueueFull, retry.Reason)
+	s.Require().Equal(time.Second, retry.After)
+	s.Require().Equal(1, retry.QueuedTxs)
+	s.Require().Equal(1, retry.MaxQueuedTxs)
+	s.Require().InDelta(1, retry.QueueFill, 0)
+
+	// The rejected transaction is accepted after the next tick emptied the queue, since its nonce was not used.
+	s.fixture.DoTick()
+	res = s.fixture.Post(moveURL, tx)
+	s.Require().Equal(fiber.StatusOK, res.StatusCode, s.readBody(res.Body))
+	s.fixture.DoTick()
+	s.nonce++
+
+	res = s.fixture.Post("query/game/location", QueryLocationRequest{Persona: persona})
+	var loc LocationComponent
+	s.Require().NoError(json.Unmarshal([]byte(s.readBody(res.Body)), &loc))
+	s.Require().Equal(LocationComponent{0, 2}, loc)
+}
+
+func (s *ServerTestSuite) TestTransactionsAreRejectedByTheAuthorizerOfTheMessage() {
+	s.setupWorld()
+	msg, ok := s.world.GetMessageByFullName("game." + moveMsgName)
+	s.Require().True(ok)
+	moveMsg, ok := msg.(*message.MessageType[MoveMsgInput, MoveMessageOutput])
+	s.Require().True(ok)
+	moveMsg.SetAuthorizer(func(_ *sign.Transaction, msg MoveMsgInput) error {
+		if msg.Direction != "up" && msg.Direction != "down" && msg.Direction != "left" && msg.Direction != "right" {
+			return fmt.Errorf("unknown direction %q", msg.Direction)
+		}
+		return nil
+	})
+	s.fixture.DoTick()
+	persona := s.CreateRandomPersona()
+	s.createPersona(persona)
+
+	tx, err := sign.NewTransaction(s.privateKey, persona, s.world.Namespace(), s.nonce, MoveMsgInput{Direction: "north"})
+	s.Require().NoError(err)
+	res := s.fixture.Post(utils.GetTxURL("game", moveMsgName), tx)
+	s.Require().Equal(fiber.StatusForbidden, res.StatusCode)
+	s.Require().Contains(s.readBody(res.Body), `unknown direction "north"`)
+	s.nonce++
+
+	s.runTx(persona, msg, MoveMsgInput{Direction: "up"})
+	res = s.fixture.Post("query/game/location", QueryLocationRequest{Persona: persona})
+	var loc LocationComponent
+	s.Require().NoError(json.Unmarshal([]byte(s.readBody(res.Body)), &loc))
+	s.Require().Equal(LocationComponent{0, 1}, loc)
+}
+
+func (s *ServerTestSuite) TestExpiredTransactionsAreRejected() {
+	s.setupWorld()
+	s.fixture.DoTick()
+	persona := s.CreateRandomPersona()
+	s.createPersona(persona)
+	msg, ok := s.world.GetMessageByFullName("game." + moveMsgName)
+	s.Require().True(ok)
+
+	// The transaction would be executed in the current tick, at which it has already expired
+	tick := s.world.CurrentTick()
+	tx, err := sign.NewExpiringTransaction(s.privateKey, persona, s.world.Namespace(), s.nonce, tick,
+		MoveMsgInput{Direction: "up"})
+	s.Require().NoError(err)
+	res := s.fixture.Post(utils.GetTxURL(msg.Group(), msg.Name()), tx)
+	s.Require().Equal(fiber.StatusBadRequest, res.StatusCode)
+	s.Require().Contains(s.readBody(res.Body), "transaction expired at tick")
+
+	tx, err = sign.NewExpiringTransaction(s.privateKey, persona, s.world.Namespace(), s.nonce, tick+1,
+		MoveMsgInput{Direction: "up"})
+	s.Require().NoError(err)
+	res = s.fixture.Post(utils.GetTxURL(msg.Group(), msg.Name()), tx)
+	s.Require().Equal(fiber.StatusOK, res.StatusCode, s.readBody(res.Body))
+	s.fixture.DoTick()
+	s.nonce++
+
+	res = s.fixture.Post("query/game/location", QueryLocationRequest{Persona: persona})
+	var loc LocationComponent
+	s.Require().NoError(json.Unmarshal([]byte(s.readBody(res.Body)), &loc))
+	s.Require().Equal(LocationComponent{0, 1}, loc)
+}
+
+func (s *ServerTestSuite) TestCoSignedTransactionsWaitForTheirCoSigners() {
+	s.setupWorld()
+	s.fixture.DoTick()
+	alice := s.CreateRandomPersona()
+	bob := s.CreateRandomPersona()
+	carol := s.CreateRandomPersona()
+	msg, ok := s.world.GetMessageByFullName("game." + moveMsgName)
+	s.Require().True(ok)
+
+	tx, err := sign.NewCoSignedTransaction(s.privateKey, alice, s.world.Namespace(), s.nonce, []string{bob, carol},
+		MoveMsgInput{Direction: "up"})
+	s.Require().NoError(err)
+	res := s.fixture.Post(utils.GetTxURL(msg.Group(), msg.Name()), tx)
+	body := s.readBody(res.Body)
+	s.Require().Equal(fiber.StatusOK, res.StatusCode, body)
+	var reply handler.PostTransactionResponse
+	s.Require().NoError(json.Unmarshal([]byte(body), &reply))
+	s.Require().True(reply.Pending)
+	s.Require().Equal(tx.HashHex(), reply.TxHash)
+	s.nonce++
+
+	// The transaction is not executed while bob and carol haven't signed it
+	s.fixture.DoTick()
+	res = s.fixture.Post("query/game/location", QueryLocationRequest{Persona: alice})
+	s.Require().NotEqual(fiber.StatusOK, res.StatusCode)
+
+	otherKey, err := crypto.GenerateKey()
+	s.Require().NoError(err)
+	for _, invalid := range []struct {
+		key        *ecdsa.PrivateKey
+		personaTag string
+		wantStatus int
+	}{
+		{key: otherKey, personaTag: bob, wantStatus: fiber.StatusBadRequest},
+		{key: s.privateKey, personaTag: alice, wantStatus: fiber.StatusBadRequest},
+	} {
+		res = s.fixture.Post("tx/cosign", s.coSign(tx, invalid.key, invalid.personaTag))
+		s.Require().Equal(invalid.wantStatus, res.StatusCode, s.readBody(res.Body))
+	}
+	res = s.fixture.Post("tx/cosign", handler.CoSignRequest{TxHash: "0x1234", PersonaTag: bob})
+	s.Require().Equal(fiber.StatusNotFound, res.StatusCode)
+
+	// The transaction is still held after bob signed it, until carol signs it too
+	res = s.fixture.Post("tx/cosign", s.coSign(tx, s.privateKey, bob))
+	body = s.readBody(res.Body)
+	s.Require().Equal(fiber.StatusOK, res.StatusCode, body)
+	s.Require().NoError(json.Unmarshal([]byte(body), &reply))
+	s.Require().True(reply.Pending)
+	s.fixture.DoTick()
+	res = s.fixture.Post("query/game/location", QueryLocationRequest{Persona: alice})
+	s.Require().NotEqual(fiber.StatusOK, res.StatusCode)
+
+	res = s.fixture.Post("tx/cosign", s.coSign(tx, s.privateKey, carol))
+	body = s.readBody(res.Body)
+	s.Require().Equal(fiber.StatusOK, res.StatusCode, body)
+	s.Require().NoError(json.Unmarshal([]byte(body), &reply))
+	s.Require().False(reply.Pending)
+	s.fixture.DoTick()
+
+	res = s.fixture.Post("query/game/location", QueryLocationRequest{Persona: alice})
+	var loc LocationComponent
+	s.Require().NoError(json.Unmarshal([]byte(s.readBody(res.Body)), &loc))
+	s.Require().Equal(LocationComponent{0, 1}, loc)
+
+	// The transaction is no longer waiting for co-signatures
+	res = s.fixture.Post("tx/cosign", s.coSign(tx, s.privateKey, bob))
+	s.Require().Equal(fiber.StatusNotFound, res.StatusCode)
+}
+
+func (s *ServerTestSuite) TestCoSignedTransactionsTimeOut() {
+	s.setupWorld(cardinal.WithCoSignTimeout(time.Millisecond))
+	s.fixture.DoTick()
+	alice := s.CreateRandomPersona()
+	bob := s.CreateRandomPersona()
+	msg, ok := s.world.GetMessageByFullName("game." + moveMsgName)
+	s.Require().True(ok)
+
+	tx, err := sign.NewCoSignedTransaction(s.privateKey, alice, s.world.Namespace(), s.nonce, []string{bob},
+		MoveMsgInput{Direction: "up"})
+	s.Require().NoError(err)
+	res := s.fixture.Post(utils.GetTxURL(msg.Group(), msg.Name()), tx)
+	s.Require().Equal(fiber.StatusOK, res.StatusCode, s.readBody(res.Body))
+	s.nonce++
+
+	// Pending transactions are kept in redis, which forgets them once the timeout has passed
+	s.fixture.Redis.FastForward(10 * time.Millisecond)
+	res = s.fixture.Post("tx/cosign", s.coSign(tx, s.privateKey, bob))
+	s.Require().Equal(fiber.StatusNotFound, res.StatusCode)
+}
+
+// coSign returns the request that adds the signature of the given co-signer to the transaction.
+func (s *ServerTestSuite) coSign(tx *sign.Transaction, key *ecdsa.PrivateKey, personaTag string) handler.CoSignRequest {
+	signature, err := crypto.Sign(tx.Hash.Bytes(), key)
+	s.Require().NoError(err)
+	return handler.CoSignRequest{
+		TxHash:     tx.HashHex(),
+		PersonaTag: personaTag,
+		Signature:  common.Bytes2Hex(signature),
+	}
+}
+
+func (s *ServerTestSuite) TestCanReadTheEventHistoryFromATick() {
+	s.setupWorld(cardinal.WithEventHistory(cardinal.EventRetention{Ticks: 2}))
+	err := cardinal.RegisterSystems(s.world, func(wCtx engine.Context) error {
+		return wCtx.EmitEvent(map[string]any{"tick": wCtx.CurrentTick()})
+	})
+	s.Require().NoError(err)
+	for i := 0; i < 3; i++ {
+		s.fixture.DoTick()
+	}
+
+	res := s.fixture.Get("/events?from_tick=1")
+	s.Require().Equal(fiber.StatusOK, res.StatusCode)
+	var reply handler.ListEventsResponse
+	s.Require().NoError(json.Unmarshal([]byte(s.readBody(res.Body)), &reply))
+	s.Require().Equal(uint64(1), reply.StartTick)
+	s.Require().Equal(uint64(3), reply.EndTick)
+	s.Require().Len(reply.Ticks, 2)
+	for i, tick := range reply.Ticks {
+		s.Require().Equal(uint64(i+1), tick.Tick)
+		s.Require().Len(tick.Events, 1)
+		s.Require().JSONEq(fmt.Sprintf(`{"tick":%d}`, i+1), string(tick.Events[0]))
+	}
+
+	// The events of tick 0 were pruned by the retention.
+	res = s.fixture.Get("/events?from_tick=0")
+	s.Require().Equal(fiber.StatusGone, res.StatusCode)
+
+	res = s.fixture.Get("/events?from_tick=meow")
+	s.Require().Equal(fiber.StatusBadRequest, res.StatusCode)
+}
+
+func (s *ServerTestSuite) TestEventHistoryIsNotFoundWhenItIsDisabled() {
+	s.setupWorld()
+	s.fixture.DoTick()
+
+	res := s.fixture.Get("/events?from_tick=0")
+	s.Require().Equal(fiber.StatusNotFound, res.StatusCode)
+}
+
 // Creates a transaction with the given message, and runs it in a tick.
 func (s *ServerTestSuite) runTx(personaTag string, msg types.Message, payload any) {
 	tx, err := sign.NewTransaction(s.privateKey, personaTag, s.world.Namespace(), s.nonce, payload)
@@ -376,6 +918,83 @@ func (s *ServerTestSuite) TestCQL() {
 	s.Require().Len(result.Results, 10)
 }
 
+func (s *ServerTestSuite) TestCQL_Where() {
+	s.setupWorld(cardinal.WithCQLFilters())
+	s.fixture.DoTick()
+
+	wCtx := cardinal.NewWorldContext(s.world)
+	for i := 0; i < 10; i++ {
+		_, err := cardinal.Create(wCtx, LocationComponent{X: uint64(i), Y: uint64(i % 2)})
+		assert.NilError(s.T(), err)
+	}
+
+	s.fixture.DoTick()
+
+	req := handler.CQLQueryRequest{CQL: "CONTAINS(location) WHERE location.X >= 5 & location.Y = 1"}
+	res := s.fixture.Post("/cql", req)
+	s.Require().Equal(fiber.StatusOK, res.StatusCode)
+	var result handler.CQLQueryResponse
+	s.Require().NoError(json.Unmarshal([]byte(s.readBody(res.Body)), &result))
+	s.Require().Len(result.Results, 3)
+	for _, r := range result.Results {
+		var loc LocationComponent
+		s.Require().NoError(json.Unmarshal(r.Data[0], &loc))
+		s.Require().GreaterOrEqual(loc.X, uint64(5))
+		s.Require().Equal(uint64(1), loc.Y)
+	}
+
+	res = s.fixture.Post("/cql", handler.CQLQueryRequest{CQL: "CONTAINS(location) WHERE location.Y = 0 LIMIT 2"})
+	s.Require().Equal(fiber.StatusOK, res.StatusCode)
+	s.Require().NoError(json.Unmarshal([]byte(s.readBody(res.Body)), &result))
+	s.Require().Len(result.Results, 2)
+}
+
+func (s *ServerTestSuite) TestCQL_WhereIsForbiddenUnlessFiltersAreEnabled() {
+	s.setupWorld()
+	s.fixture.DoTick()
+
+	res := s.fixture.Post("/cql", handler.CQLQueryRequest{CQL: "CONTAINS(location) WHERE location.X = 1"})
+	s.Require().Equal(fiber.StatusForbidden, res.StatusCode)
+
+	res = s.fixture.Post("/cql", handler.CQLQueryRequest{CQL: "CONTAINS(location) LIMIT 1"})
+	s.Require().Equal(fiber.StatusOK, res.StatusCode)
+}
+
+func (s *ServerTestSuite) TestCQL_Truncated() {
+	s.setupWorld(cardinal.WithReplyLimits(server.ReplyLimits{MaxItems: 4}))
+	s.fixture.DoTick()
+
+	wCtx := cardinal.NewWorldContext(s.world)
+	_, err := cardinal.CreateMany(wCtx, 10, LocationComponent{})
+	assert.NilError(s.T(), err)
+
+	s.fixture.DoTick()
+
+	seen := map[types.EntityID]bool{}
+	req := handler.CQLQueryRequest{CQL: "CONTAINS(location)"}
+	for pages := 1; ; pages++ {
+		res := s.fixture.Post("/cql", req)
+		s.Require().Equal(fiber.StatusOK, res.StatusCode)
+		var result handler.CQLQueryResponse
+		s.Require().NoError(json.Unmarshal([]byte(s.readBody(res.Body)), &result))
+		for _, r := range result.Results {
+			s.Require().False(seen[r.ID])
+			seen[r.ID] = true
+		}
+		if result.Truncation == nil {
+			s.Require().Equal(3, pages)
+			break
+		}
+		s.Require().Equal(10, result.Truncation.Total)
+		s.Require().Equal(4, result.Truncation.Returned)
+		req.Cursor = result.Truncation.NextCursor
+	}
+	s.Require().Len(seen, 10)
+
+	res := s.fixture.Post("/cql", handler.CQLQueryRequest{CQL: "CONTAINS(location)", Cursor: "meow"})
+	s.Require().Equal(fiber.StatusBadRequest, res.StatusCode)
+}
+
 func (s *ServerTestSuite) TestCQL_InvalidFormat() {
 	s.setupWorld()
 	s.fixture.DoTick()
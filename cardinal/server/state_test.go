@@ -0,0 +1,129 @@
+package server_test
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/gamestate"
+	"pkg.world.dev/world-engine/cardinal/server/handler"
+	"pkg.world.dev/world-engine/cardinal/types"
+)
+
+func (s *ServerTestSuite) TestStateProof() {
+	s.setupWorld(cardinal.WithStateCommitments(2, 0))
+	s.fixture.DoTick()
+
+	wCtx := cardinal.NewWorldContext(s.world)
+	id, err := cardinal.Create(wCtx, LocationComponent{X: 3, Y: 4})
+	s.Require().NoError(err)
+	s.fixture.DoTick()
+	// The commitment is saved in the background.
+	s.Require().Eventually(func() bool {
+		commitments, err := s.world.StateCommitments()
+		return err == nil && len(commitments) == 1
+	}, time.Second, 10*time.Millisecond)
+
+	res := s.fixture.Get("state/commitments")
+	s.Require().Equal(res.StatusCode, 200)
+	var commitments handler.StateCommitmentsResponse
+	s.Require().NoError(json.NewDecoder(res.Body).Decode(&commitments))
+	s.Require().Len(commitments, 1)
+	s.Require().Equal(uint64(0), commitments[0].StartTick)
+	s.Require().Equal(uint64(1), commitments[0].EndTick)
+
+	res = s.fixture.Post("state/proof", handler.StateProofRequest{Tick: 1, EntityID: id, Component: "location"})
+	s.Require().Equal(res.StatusCode, 200)
+	var proof gamestate.StateProof
+	s.Require().NoError(json.NewDecoder(res.Body).Decode(&proof))
+	s.Require().Equal(commitments[0], proof.Commitment)
+	s.Require().JSONEq(`{"X":3,"Y":4}`, string(proof.Leaf.Value))
+	s.Require().True(proof.Verify())
+
+	// No commitment covers the current tick yet.
+	res = s.fixture.Post("state/proof", handler.StateProofRequest{Tick: 2, EntityID: id, Component: "location"})
+	s.Require().Equal(res.StatusCode, 404)
+	res = s.fixture.Post("state/proof", handler.StateProofRequest{Tick: 1, EntityID: id + 1, Component: "location"})
+	s.Require().Equal(res.StatusCode, 404)
+}
+
+func (s *ServerTestSuite) TestStateDiff() {
+	s.setupWorld(cardinal.WithStateDiffs(0))
+	s.fixture.DoTick()
+
+	wCtx := cardinal.NewWorldContext(s.world)
+	id, err := cardinal.Create(wCtx, LocationComponent{X: 3, Y: 4})
+	s.Require().NoError(err)
+	s.fixture.DoTick()
+
+	res := s.fixture.Post("state/diff", handler.StateDiffRequest{FromTick: 0, ToTick: 2})
+	s.Require().Equal(res.StatusCode, 200)
+	var diff gamestate.StateDiff
+	s.Require().NoError(json.NewDecoder(res.Body).Decode(&diff))
+	s.Require().Equal([]types.EntityID{id}, diff.Created)
+	s.Require().Len(diff.Changed, 1)
+	s.Require().Equal("location", diff.Changed[0].Component)
+	s.Require().JSONEq(`{"X":3,"Y":4}`, string(diff.Changed[0].New))
+
+	// The current tick has not completed yet.
+	res = s.fixture.Post("state/diff", handler.StateDiffRequest{FromTick: 0, ToTick: 3})
+	s.Require().Equal(res.StatusCode, 404)
+	res = s.fixture.Post("state/diff", handler.StateDiffRequest{FromTick: 2, ToTick: 1})
+	s.Require().Equal(res.StatusCode, 400)
+}
+
+func (s *ServerTestSuite) TestStateStream() {
+	s.setupWorld(cardinal.WithStateDiffs(0))
+	s.fixture.DoTick()
+	wCtx := cardinal.NewWorldContext(s.world)
+	inside, err := cardinal.Create(wCtx, LocationComponent{X: 3, Y: 4})
+	s.Require().NoError(err)
+	outside, err := cardinal.Create(wCtx, LocationComponent{X: 50, Y: 4})
+	s.Require().NoError(err)
+	s.fixture.DoTick()
+
+	url := wsURL(s.fixture.BaseURL, "state/stream?components=location&region=location.X:0:10")
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	s.Require().NoError(err)
+	defer conn.Close()
+	var snapshot handler.StateSnapshotMessage
+	s.Require().NoError(conn.ReadJSON(&snapshot))
+	s.Require().Equal(handler.StateStreamSnapshot, snapshot.Type)
+	s.Require().Equal(uint64(2), snapshot.Tick)
+	s.Require().Len(snapshot.Entities, 1)
+	s.Require().Equal(inside, snapshot.Entities[0].ID)
+	s.Require().JSONEq(`{"X":3,"Y":4}`, string(snapshot.Entities[0].Components["location"]))
+
+	// The entities swap places, so one leaves the region and the other one enters it.
+	wCtx = cardinal.NewWorldContext(s.world)
+	s.Require().NoError(cardinal.SetComponent(wCtx, inside, &LocationComponent{X: 50, Y: 4}))
+	s.Require().NoError(cardinal.SetComponent(wCtx, outside, &LocationComponent{X: 5, Y: 4}))
+	s.fixture.DoTick()
+	var diff handler.StateDiffMessage
+	s.Require().NoError(conn.ReadJSON(&diff))
+	s.Require().Equal(handler.StateStreamDiff, diff.Type)
+	s.Require().Equal(uint64(2), diff.FromTick)
+	s.Require().Equal(uint64(3), diff.ToTick)
+	s.Require().Equal([]types.EntityID{inside}, diff.Left)
+	s.Require().Len(diff.Entered, 1)
+	s.Require().Equal(outside, diff.Entered[0].ID)
+	s.Require().JSONEq(`{"X":5,"Y":4}`, string(diff.Entered[0].Components["location"]))
+	s.Require().Empty(diff.Changed)
+
+	// The entity in the region moves within it.
+	wCtx = cardinal.NewWorldContext(s.world)
+	s.Require().NoError(cardinal.SetComponent(wCtx, outside, &LocationComponent{X: 6, Y: 4}))
+	s.fixture.DoTick()
+	s.Require().NoError(conn.ReadJSON(&diff))
+	s.Require().Equal(uint64(3), diff.FromTick)
+	s.Require().Empty(diff.Entered)
+	s.Require().Empty(diff.Left)
+	s.Require().Len(diff.Changed, 1)
+	s.Require().JSONEq(`{"X":6,"Y":4}`, string(diff.Changed[0].New))
+
+	_, res, err := websocket.DefaultDialer.Dial(wsURL(s.fixture.BaseURL, "state/stream?region=location.X:10:0"), nil)
+	s.Require().Error(err)
+	s.Require().Equal(400, res.StatusCode)
+}
@@ -1,7 +1,10 @@
 package types
 
 import (
+	"context"
+
 	"pkg.world.dev/world-engine/cardinal/gamestate"
+	"pkg.world.dev/world-engine/cardinal/receipt"
 	"pkg.world.dev/world-engine/cardinal/search"
 	"pkg.world.dev/world-engine/cardinal/search/filter"
 	"pkg.world.dev/world-engine/cardinal/types"
@@ -12,10 +15,39 @@ import (
 type Provider interface {
 	UseNonce(signerAddress string, nonce uint64) error
 	GetSignerForPersonaTag(personaTag string, tick uint64) (addr string, err error)
-	AddTransaction(id types.MessageID, v any, sig *sign.Transaction) (uint64, types.TxHash)
+	GetSessionKeysForPersonaTag(personaTag, msgFullName string) ([]string, error)
+	IsPersonaBanned(personaTag string) (bool, error)
+	AddTransactionWithContext(ctx context.Context, id types.MessageID, v any, sig *sign.Transaction) (
+		uint64, types.TxHash, error,
+	)
+	LookupIdempotencyKey(personaTag, key string) (tick uint64, txHash types.TxHash, found bool, err error)
+	AddIdempotentTransaction(ctx context.Context, key string, id types.MessageID, v any, sig *sign.Transaction) (
+		tick uint64, txHash types.TxHash, duplicate bool, err error,
+	)
+	AddCoSignedTransaction(ctx context.Context, id types.MessageID, v any, sig *sign.Transaction) (
+		tick uint64, txHash types.TxHash, pending bool, err error,
+	)
+	GetPendingCoSignedTransaction(txHash types.TxHash) (*sign.Transaction, bool, error)
+	AddCoSignature(ctx context.Context, txHash types.TxHash, personaTag, signature string) (
+		tick uint64, pending bool, err error,
+	)
+	SimulateTransaction(ctx context.Context, id types.MessageID, v any, sig *sign.Transaction) (
+		tick uint64, rec receipt.Receipt, events [][]byte, err error,
+	)
+	CheckBackPressure() *types.RetryAfter
+	StorageHealth() types.StorageHealth
 	Namespace() string
 	GetComponentByName(name string) (types.ComponentMetadata, error)
 	Search(filter filter.ComponentFilter) search.EntitySearch
 	StoreReader() gamestate.Reader
+	ArchetypeStats() ([]gamestate.ArchetypeStat, error)
+	LayoutReport() (gamestate.LayoutReport, error)
+	GetSystemSchedule() []types.SystemInfo
 	GetReadOnlyCtx() engine.Context
+	GetEventHistory(fromTick uint64, limit int) (ticks []types.TickEvents, endTick uint64, err error)
+	StateCommitments() ([]gamestate.StateCommitment, error)
+	ProveState(tick uint64, id types.EntityID, component string) (gamestate.StateProof, error)
+	Diff(fromTick, toTick uint64) (gamestate.StateDiff, error)
+	RecordsStateDiffs() bool
+	Snapshot(components []string) (gamestate.Snapshot, error)
 }
@@ -0,0 +1,92 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"pkg.world.dev/world-engine/cardinal/server/handler"
+)
+
+const (
+	// APIVersionV1 is the first version of the HTTP API. Its routes are served under /v1.
+	APIVersionV1 = "v1"
+	// APIVersionV2 serves the same routes as APIVersionV1, but message payloads are always decoded as the current input
+	// of the message. Payloads sent to APIVersionV1 may be in a legacy schema, see message.WithLegacyInput.
+	APIVersionV2 = "v2"
+	// CurrentAPIVersion is the version that the unversioned routes (e.g. /tx/game/move) are an alias of. It stays at
+	// APIVersionV1 so that clients that predate versioning keep working.
+	CurrentAPIVersion = APIVersionV1
+	// LatestAPIVersion is the newest version of the HTTP API.
+	LatestAPIVersion = APIVersionV2
+	// Unversioned identifies the unversioned routes when setting a VersionPolicy, so that clients that do not use a
+	// version prefix yet can be told to migrate to one.
+	Unversioned = ""
+
+	// APIVersionHeader is set on every response to the version of the API that served the request.
+	APIVersionHeader = "API-Version"
+)
+
+// VersionPolicy announces the retirement of a version of the HTTP API to the clients that still use it. Since live game
+// clients cannot all update at once, a deprecated version keeps being served next to its successor until it sunsets.
+type VersionPolicy struct {
+	// Deprecated is the time from which the version is deprecated. Responses of a deprecated version carry a
+	// Deprecation header (RFC 9745).
+	Deprecated time.Time
+	// Sunset is the time after which the version is no longer served. Until then, responses carry a Sunset header
+	// (RFC 8594). Afterwards, requests fail with 410 Gone.
+	Sunset time.Time
+	// Successor is the version that clients should migrate to. It is advertised in a Link header.
+	Successor string
+}
+
+// apiVersion is a version of the HTTP API that the server serves under its own path prefix.
+type apiVersion struct {
+	name     string
+	register func(r fiber.Router, version fiber.Handler)
+}
+
+// versionHandler returns a handler that is run before every route of the given version. It sets the version headers
+// and rejects requests to versions that have sunset.
+func (s *Server) versionHandler(version, served string) fiber.Handler {
+	policy, hasPolicy := s.config.versionPolicies[version]
+	return func(ctx *fiber.Ctx) error {
+		ctx.Set(APIVersionHeader, served)
+		if !hasPolicy {
+			return ctx.Next()
+		}
+		now := time.Now()
+		if !policy.Sunset.IsZero() && !now.Before(policy.Sunset) {
+			return fiber.NewError(http.StatusGone, "API version "+served+" is no longer served")
+		}
+		if !policy.Deprecated.IsZero() && !now.Before(policy.Deprecated) {
+			ctx.Set("Deprecation", "@"+strconv.FormatInt(policy.Deprecated.Unix(), 10))
+		}
+		if !policy.Sunset.IsZero() {
+			ctx.Set("Sunset", policy.Sunset.UTC().Format(http.TimeFormat))
+		}
+		if policy.Successor != "" {
+			ctx.Set("Link", `</`+policy.Successor+`>; rel="successor-version"`)
+		}
+		return ctx.Next()
+	}
+}
+
+// versionInfo returns the version and the retirement policy of the version, as reported by the /versions endpoint.
+func (s *Server) versionInfo(version, served string) handler.APIVersionInfo {
+	info := handler.APIVersionInfo{Version: served}
+	policy, ok := s.config.versionPolicies[version]
+	if !ok {
+		return info
+	}
+	if !policy.Deprecated.IsZero() {
+		info.Deprecated = &policy.Deprecated
+	}
+	if !policy.Sunset.IsZero() {
+		info.Sunset = &policy.Sunset
+	}
+	info.Successor = policy.Successor
+	return info
+}
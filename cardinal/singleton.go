@@ -0,0 +1,113 @@
+package cardinal
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+// singletonNamespace is the raw storage namespace that maps the name of every singleton to the entity that holds it.
+const singletonNamespace = "cardinal-singleton"
+
+// Singleton is a global resource of the game, e.g. the phase of a match or the weather, that is held by a single
+// entity. The entity is created and looked up by the engine, so systems don't need to create a dummy entity and search
+// for it. The value is stored like any other component: T must be registered with RegisterComponent, and the entity
+// shows up in searches and queries for T.
+type Singleton[T types.Component] struct {
+	name string
+}
+
+// NewSingleton returns the singleton with the given name, which is usually declared at the package level. Singletons
+// with the same name share their value.
+//
+// Usage:
+//
+//	var matchConfig = cardinal.NewSingleton[MatchConfig]("match_config")
+//
+//	func PhaseSystem(wCtx engine.Context) error {
+//		config, ok, err := matchConfig.Get(wCtx)
+//		...
+//		return matchConfig.Set(wCtx, config)
+//	}
+func NewSingleton[T types.Component](name string) *Singleton[T] {
+	return &Singleton[T]{name: name}
+}
+
+// Name returns the name of the singleton.
+func (s *Singleton[T]) Name() string {
+	return s.name
+}
+
+// Entity returns the entity that holds the singleton. If the singleton has not been set, ok will be false.
+func (s *Singleton[T]) Entity(wCtx engine.Context) (id types.EntityID, ok bool, err error) {
+	store, err := s.store(wCtx)
+	if err != nil {
+		return 0, false, err
+	}
+	value, ok, err := store.Get(s.name)
+	if err != nil || !ok {
+		return 0, false, err
+	}
+	n, err := strconv.ParseUint(string(value), 10, 64)
+	if err != nil {
+		return 0, false, eris.Wrapf(err, "invalid entity of singleton %q", s.name)
+	}
+	return types.EntityID(n), true, nil
+}
+
+// Get returns the value of the singleton. If the singleton has not been set, or its entity was removed, ok will be false
+// and the zero value is returned.
+func (s *Singleton[T]) Get(wCtx engine.Context) (value T, ok bool, err error) {
+	id, ok, err := s.Entity(wCtx)
+	if err != nil || !ok {
+		return value, false, err
+	}
+	comp, err := GetComponent[T](wCtx, id)
+	if errors.Is(err, ErrEntityDoesNotExist) || errors.Is(err, ErrComponentNotOnEntity) {
+		return value, false, nil
+	} else if err != nil {
+		return value, false, err
+	}
+	return *comp, true, nil
+}
+
+// Set sets the value of the singleton. The entity that holds the singleton is created when it is set for the first
+// time, or again if it was removed or no longer has the component.
+func (s *Singleton[T]) Set(wCtx engine.Context, value T) (err error) {
+	defer func() { panicOnFatalError(wCtx, err) }()
+
+	if wCtx.IsReadOnly() {
+		return ErrEntityMutationOnReadOnly
+	}
+	id, ok, err := s.Entity(wCtx)
+	if err != nil {
+		return err
+	}
+	if ok {
+		err = SetComponent[T](wCtx, id, &value)
+		if !errors.Is(err, ErrEntityDoesNotExist) && !errors.Is(err, ErrComponentNotOnEntity) {
+			return err
+		}
+	}
+
+	id, err = Create(wCtx, value)
+	if err != nil {
+		return err
+	}
+	store, err := s.store(wCtx)
+	if err != nil {
+		return err
+	}
+	return store.Set(s.name, []byte(strconv.FormatUint(uint64(id), 10)))
+}
+
+func (s *Singleton[T]) store(wCtx engine.Context) (*RawStorage, error) {
+	if s.name == "" {
+		return nil, eris.New("singleton name must not be empty")
+	}
+	return NewRawStorage(wCtx, singletonNamespace)
+}
@@ -0,0 +1,82 @@
+package cardinal_test
+
+import (
+	"testing"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/search/filter"
+	"pkg.world.dev/world-engine/cardinal/testutils"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+type MatchConfig struct {
+	Phase string
+	Round int
+}
+
+func (MatchConfig) Name() string { return "MatchConfig" }
+
+var matchConfig = cardinal.NewSingleton[MatchConfig]("match_config")
+
+func nextRoundSystem(wCtx engine.Context) error {
+	config, ok, err := matchConfig.Get(wCtx)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		config.Phase = "lobby"
+	}
+	config.Round++
+	return matchConfig.Set(wCtx, config)
+}
+
+func TestSingletonIsHeldByASingleEntity(t *testing.T) {
+	tf1 := testutils.NewTestFixture(t, nil)
+	assert.NilError(t, cardinal.RegisterComponent[MatchConfig](tf1.World))
+	assert.NilError(t, cardinal.RegisterSystems(tf1.World, nextRoundSystem))
+	tf1.StartWorld()
+	readOnly := cardinal.NewReadOnlyWorldContext(tf1.World)
+	_, ok, err := matchConfig.Get(readOnly)
+	assert.NilError(t, err)
+	assert.Check(t, !ok)
+	tf1.DoTick()
+	tf1.DoTick()
+
+	config, ok, err := matchConfig.Get(readOnly)
+	assert.NilError(t, err)
+	assert.Check(t, ok)
+	assert.Equal(t, MatchConfig{Phase: "lobby", Round: 2}, config)
+	count, err := cardinal.NewSearch().Entity(filter.Contains(filter.Component[MatchConfig]())).Count(readOnly)
+	assert.NilError(t, err)
+	assert.Equal(t, 1, count)
+
+	// The singleton is restored with the world.
+	tf1.Shutdown()
+	tf2 := testutils.NewTestFixture(t, tf1.Redis)
+	assert.NilError(t, cardinal.RegisterComponent[MatchConfig](tf2.World))
+	assert.NilError(t, cardinal.RegisterSystems(tf2.World, nextRoundSystem))
+	tf2.StartWorld()
+	tf2.DoTick()
+	readOnly = cardinal.NewReadOnlyWorldContext(tf2.World)
+	config, _, err = matchConfig.Get(readOnly)
+	assert.NilError(t, err)
+	assert.Equal(t, MatchConfig{Phase: "lobby", Round: 3}, config)
+	assert.ErrorIs(t, matchConfig.Set(readOnly, config), cardinal.ErrEntityMutationOnReadOnly)
+
+	// A singleton whose entity was removed is created again.
+	wCtx := cardinal.NewWorldContext(tf2.World)
+	id, _, err := matchConfig.Entity(wCtx)
+	assert.NilError(t, err)
+	assert.NilError(t, cardinal.Remove(wCtx, id))
+	_, ok, err = matchConfig.Get(wCtx)
+	assert.NilError(t, err)
+	assert.Check(t, !ok)
+	assert.NilError(t, matchConfig.Set(wCtx, MatchConfig{Phase: "ended"}))
+	config, _, err = matchConfig.Get(wCtx)
+	assert.NilError(t, err)
+	assert.Equal(t, "ended", config.Phase)
+	newID, _, err := matchConfig.Entity(wCtx)
+	assert.NilError(t, err)
+	assert.Check(t, newID != id)
+}
@@ -1,6 +1,7 @@
 package cardinal_test
 
 import (
+	"path/filepath"
 	"testing"
 
 	"github.com/alicebob/miniredis/v2"
@@ -74,6 +75,7 @@ func TestErrorWhenSavedArchetypesDoNotMatchComponentTypes(t *testing.T) {
 	tf1.DoTick()
 
 	// Too few components registered
+	tf1.Shutdown()
 	tf2 := testutils.NewTestFixture(t, tf1.Redis)
 	err = tf2.World.StartGame() // We start this manually instead of tf2.StartWorld() because StartWorld panics on err
 	assert.ErrorContains(t, err, iterators.ErrComponentMismatchWithSavedState.Error())
@@ -86,6 +88,7 @@ func TestErrorWhenSavedArchetypesDoNotMatchComponentTypes(t *testing.T) {
 	tf3.StartWorld()
 
 	// Just the right number of components registered
+	tf3.Shutdown()
 	tf4 := testutils.NewTestFixture(t, tf1.Redis)
 	world4 := tf4.World
 	assert.NilError(t, cardinal.RegisterComponent[FoundAlphaNum](world4))
@@ -113,6 +116,7 @@ func TestArchetypeIDIsConsistentAfterSaveAndLoad(t *testing.T) {
 	tf1.DoTick()
 
 	// Make a second instance of the engine using the same storage.
+	tf1.Shutdown()
 	tf2 := testutils.NewTestFixture(t, tf1.Redis)
 	world2 := tf2.World
 	assert.NilError(t, cardinal.RegisterComponent[NumberComponent](world2))
@@ -166,6 +170,7 @@ func TestCanRecoverArchetypeInformationAfterLoad(t *testing.T) {
 
 	// Create a brand new engine, but use the original redis store. We should be able to load
 	// the game state from the redis store (including archetype indices).
+	tf1.Shutdown()
 	tf2 := testutils.NewTestFixture(t, mr)
 	world2 := tf2.World
 	// The ordering of registering these components is important. It must match the ordering above.
@@ -189,8 +194,9 @@ func TestCanRecoverArchetypeInformationAfterLoad(t *testing.T) {
 
 	// Save and load again to make sure the "two" engine correctly saves its state even though
 	// it never cardinal.Created any entities
-	tf1.DoTick()
+	tf2.DoTick()
 
+	tf2.Shutdown()
 	tf3 := testutils.NewTestFixture(t, mr)
 	world3 := tf3.World
 	// Again, the ordering of registering these components is important. It must match the ordering above
@@ -256,6 +262,7 @@ func TestCanReloadState(t *testing.T) {
 	tf1.DoTick()
 
 	// Make a new engine, using the original redis DB that (hopefully) has our data
+	tf1.Shutdown()
 	tf2 := testutils.NewTestFixture(t, tf1.Redis)
 	world2 := tf2.World
 	assert.NilError(t, cardinal.RegisterComponent[OneBetaNum](world2))
@@ -279,6 +286,35 @@ func TestCanReloadState(t *testing.T) {
 	assert.Equal(t, 10, count)
 }
 
+func TestCanReloadStateFromDisk(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "world.snapshot")
+	var ids []types.EntityID
+
+	t.Run("first run saves the world", func(t *testing.T) {
+		tf := testutils.NewPersistentTestFixture(t, path)
+		assert.NilError(t, cardinal.RegisterComponent[NumberComponent](tf.World))
+		tf.StartWorld()
+		var err error
+		ids, err = cardinal.CreateMany(cardinal.NewWorldContext(tf.World), 3, NumberComponent{Num: 7})
+		assert.NilError(t, err)
+		tf.DoTick()
+		tf.DoTick()
+	})
+
+	t.Run("second run restores the world", func(t *testing.T) {
+		tf := testutils.NewPersistentTestFixture(t, path)
+		assert.NilError(t, cardinal.RegisterComponent[NumberComponent](tf.World))
+		tf.StartWorld()
+		assert.Equal(t, uint64(2), tf.World.CurrentTick())
+		wCtx := cardinal.NewWorldContext(tf.World)
+		for _, id := range ids {
+			num, err := cardinal.GetComponent[NumberComponent](wCtx, id)
+			assert.NilError(t, err)
+			assert.Equal(t, 7, num.Num)
+		}
+	})
+}
+
 func TestEngineTickAndHistoryTickMatch(t *testing.T) {
 	// Ensure that across multiple reloads, getting the transaction receipts for a tick
 	// that is still in the tx receipt history window will not return any errors.
@@ -373,3 +409,40 @@ func TestSearchEarlyTermination(t *testing.T) {
 	)
 	assert.Equal(t, count, total)
 }
+
+func TestMessageIDsMustMatchSavedState(t *testing.T) {
+	type MsgA struct{}
+	type MsgB struct{}
+	type MsgC struct{}
+	type Result struct{}
+	register := map[string]func(*cardinal.World) error{
+		"a": func(w *cardinal.World) error { return cardinal.RegisterMessage[MsgA, Result](w, "msg-a") },
+		"b": func(w *cardinal.World) error { return cardinal.RegisterMessage[MsgB, Result](w, "msg-b") },
+		"c": func(w *cardinal.World) error { return cardinal.RegisterMessage[MsgC, Result](w, "msg-c") },
+	}
+	newFixture := func(tf *testutils.TestFixture, msgs ...string) *testutils.TestFixture {
+		tf.Shutdown()
+		tf = testutils.NewTestFixture(t, tf.Redis)
+		for _, msg := range msgs {
+			assert.NilError(t, register[msg](tf.World))
+		}
+		return tf
+	}
+
+	tf1 := testutils.NewTestFixture(t, nil)
+	assert.NilError(t, register["a"](tf1.World))
+	assert.NilError(t, register["b"](tf1.World))
+	tf1.StartWorld()
+
+	// Reordering the messages would change their IDs.
+	// We start this manually instead of StartWorld() because StartWorld panics on err
+	err := newFixture(tf1, "b", "a").World.StartGame()
+	assert.ErrorIs(t, err, cardinal.ErrMessageIDMismatch)
+
+	// The ID of a removed message can't be reused by another message.
+	err = newFixture(tf1, "a", "c").World.StartGame()
+	assert.ErrorIs(t, err, cardinal.ErrMessageIDMismatch)
+
+	// New messages can be added after the existing ones.
+	newFixture(tf1, "a", "b", "c").StartWorld()
+}
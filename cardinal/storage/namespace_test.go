@@ -0,0 +1,139 @@
+package storage_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal/storage/redis"
+)
+
+func newNamespacedStorage(s *miniredis.Miniredis, namespace string) redis.Storage {
+	rs := redis.NewRedisStorage(redis.Options{Addr: s.Addr()}, namespace)
+	rs.SetKeyPrefix(redis.NamespaceKeyPrefix(namespace))
+	return rs
+}
+
+func TestNamespaceOwnership(t *testing.T) {
+	ctx := context.Background()
+	s := miniredis.RunT(t)
+	rs := newNamespacedStorage(s, Namespace)
+
+	assert.NilError(t, rs.ClaimNamespace(ctx, "instance-1", time.Minute))
+
+	// Another instance can't claim the namespace while it is owned.
+	assert.ErrorIs(t, rs.ClaimNamespace(ctx, "instance-2", time.Minute), redis.ErrNamespaceInUse)
+	owns, err := rs.OwnsNamespace(ctx, "instance-1")
+	assert.NilError(t, err)
+	assert.Check(t, owns)
+
+	// Only the owner can release the namespace.
+	assert.NilError(t, rs.ReleaseNamespace(ctx, "instance-2"))
+	owns, err = rs.OwnsNamespace(ctx, "instance-1")
+	assert.NilError(t, err)
+	assert.Check(t, owns)
+	assert.NilError(t, rs.ReleaseNamespace(ctx, "instance-1"))
+	owns, err = rs.OwnsNamespace(ctx, "instance-1")
+	assert.NilError(t, err)
+	assert.Check(t, !owns)
+	assert.NilError(t, rs.ClaimNamespace(ctx, "instance-2", time.Minute))
+
+	// A lease that is renewed doesn't expire, but another instance can claim the namespace once it expired.
+	s.FastForward(40 * time.Second)
+	renewed, err := rs.RenewNamespace(ctx, "instance-2", time.Minute)
+	assert.NilError(t, err)
+	assert.Check(t, renewed)
+	s.FastForward(40 * time.Second)
+	assert.ErrorIs(t, rs.ClaimNamespace(ctx, "instance-3", time.Minute), redis.ErrNamespaceInUse)
+	s.FastForward(time.Minute)
+	assert.NilError(t, rs.ClaimNamespace(ctx, "instance-3", time.Minute))
+	renewed, err = rs.RenewNamespace(ctx, "instance-2", time.Minute)
+	assert.NilError(t, err)
+	assert.Check(t, !renewed)
+}
+
+func TestListAndPurgeNamespaces(t *testing.T) {
+	ctx := context.Background()
+	s := miniredis.RunT(t)
+	game1 := newNamespacedStorage(s, "game-1")
+	game2 := newNamespacedStorage(s, "game-2")
+	namespaces := redis.NewNamespaces(redis.Options{Addr: s.Addr()})
+
+	assert.NilError(t, game1.ClaimNamespace(ctx, "instance-1", time.Minute))
+	assert.NilError(t, game1.UseNonce("some-address", 1))
+	assert.NilError(t, game2.ClaimNamespace(ctx, "instance-2", time.Minute))
+	assert.NilError(t, game2.ReleaseNamespace(ctx, "instance-2"))
+
+	infos, err := namespaces.List(ctx)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []redis.NamespaceInfo{
+		{Name: "game-1", Keys: 3, Owner: "instance-1"},
+		{Name: "game-2", Keys: 1, Owner: ""},
+	}, infos)
+
+	_, err = namespaces.Purge(ctx, "game-1", false)
+	assert.ErrorIs(t, err, redis.ErrNamespaceInUse)
+
+	deleted, err := namespaces.Purge(ctx, "game-2", false)
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(1), deleted)
+	deleted, err = namespaces.Purge(ctx, "game-1", true)
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(3), deleted)
+	assert.Equal(t, 0, len(s.Keys()))
+}
+
+func TestMigrateLegacyKeys(t *testing.T) {
+	ctx := context.Background()
+	s := miniredis.RunT(t)
+	assert.NilError(t, s.Set("ECB:NEXT-ENTITY-ID", "10"))
+	assert.NilError(t, s.Set("USED_NONCES_some-address", "1"))
+	namespaces := redis.NewNamespaces(redis.Options{Addr: s.Addr()})
+
+	// Keys of namespaces that were claimed by a world are left alone, even if they look like legacy keys.
+	admin := newNamespacedStorage(s, "ADMIN")
+	assert.NilError(t, admin.ClaimNamespace(ctx, "instance-1", time.Minute))
+
+	moved, err := namespaces.MigrateLegacyKeys(ctx, Namespace)
+	assert.NilError(t, err)
+	assert.Equal(t, 2, moved)
+	value, err := s.Get(Namespace + ":ECB:NEXT-ENTITY-ID")
+	assert.NilError(t, err)
+	assert.Equal(t, "10", value)
+	assert.Check(t, s.Exists("ADMIN:CARDINAL:OWNER"))
+
+	// A namespace that already has keys is not migrated again.
+	assert.NilError(t, s.Set("ECB:NEXT-ENTITY-ID", "20"))
+	moved, err = namespaces.MigrateLegacyKeys(ctx, Namespace)
+	assert.NilError(t, err)
+	assert.Equal(t, 0, moved)
+}
+
+func TestInterruptedMigrationOfLegacyKeysIsResumed(t *testing.T) {
+	ctx := context.Background()
+	s := miniredis.RunT(t)
+	assert.NilError(t, s.Set("ECB:NEXT-ENTITY-ID", "10"))
+	assert.NilError(t, s.Set("ECB:ARCHETYPES", "[]"))
+	namespaces := redis.NewNamespaces(redis.Options{Addr: s.Addr()})
+
+	// The namespace looks like a legacy key, and the migration was interrupted after moving one of the keys.
+	assert.NilError(t, s.Set("ECB:CARDINAL:MIGRATION", "in-progress"))
+	s.Del("ECB:NEXT-ENTITY-ID")
+	assert.NilError(t, s.Set("ECB:ECB:NEXT-ENTITY-ID", "10"))
+
+	moved, err := namespaces.MigrateLegacyKeys(ctx, "ECB")
+	assert.NilError(t, err)
+	assert.Equal(t, 1, moved)
+	value, err := s.Get("ECB:ECB:NEXT-ENTITY-ID")
+	assert.NilError(t, err)
+	assert.Equal(t, "10", value)
+	assert.Check(t, s.Exists("ECB:ECB:ARCHETYPES"))
+	assert.Check(t, !s.Exists("ECB:ARCHETYPES"))
+
+	moved, err = namespaces.MigrateLegacyKeys(ctx, "ECB")
+	assert.NilError(t, err)
+	assert.Equal(t, 0, moved)
+}
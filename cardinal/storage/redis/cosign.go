@@ -0,0 +1,123 @@
+package redis
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rotisserie/eris"
+)
+
+const (
+	coSignedTxField        = "tx"
+	coSignatureFieldPrefix = "cosig:"
+)
+
+// addCoSignatureScript adds the signature of a co-signer to a pending co-signed transaction and returns all fields of
+// the transaction. Nothing is written, and nil is returned, if the transaction timed out or was completed, since HSET
+// would otherwise create a transaction without a timeout.
+var addCoSignatureScript = redis.NewScript(`
+if redis.call("EXISTS", KEYS[1]) == 0 then
+	return false
+end
+redis.call("HSET", KEYS[1], ARGV[1], ARGV[2])
+return redis.call("HGETALL", KEYS[1])
+`)
+
+// PendingCoSignedTx is a co-signed transaction that is waiting for the signatures of its co-signers.
+type PendingCoSignedTx struct {
+	// Data is the encoded transaction, without the signatures of its co-signers.
+	Data []byte
+	// CoSignatures are the signatures of the co-signers, by persona tag.
+	CoSignatures map[string]string
+}
+
+// CoSignStorage holds the co-signed transactions that are waiting for the signatures of their co-signers. They are
+// shared by the replicas of a world, so a co-signature can be added through any replica, and survive restarts.
+type CoSignStorage struct {
+	Client *redis.Client
+}
+
+func NewCoSignStorage(client *redis.Client) CoSignStorage {
+	return CoSignStorage{
+		Client: client,
+	}
+}
+
+// SavePendingCoSignedTx stores a co-signed transaction until its co-signers signed it. It is forgotten once the timeout
+// has passed.
+func (r *CoSignStorage) SavePendingCoSignedTx(txHash string, tx PendingCoSignedTx, timeout time.Duration) error {
+	ctx := context.Background()
+	key := r.coSignedTxKey(txHash)
+	fields := make([]any, 0, 2+2*len(tx.CoSignatures)) //nolint:gomnd // field and value pairs
+	fields = append(fields, coSignedTxField, tx.Data)
+	for personaTag, signature := range tx.CoSignatures {
+		fields = append(fields, coSignatureFieldPrefix+personaTag, signature)
+	}
+	_, err := r.Client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, key)
+		pipe.HSet(ctx, key, fields...)
+		pipe.PExpire(ctx, key, timeout)
+		return nil
+	})
+	return eris.Wrap(err, "")
+}
+
+// GetPendingCoSignedTx returns the co-signed transaction with the given hash. The boolean is false if it is not
+// waiting for co-signatures, because it is unknown, was completed, or timed out.
+func (r *CoSignStorage) GetPendingCoSignedTx(txHash string) (PendingCoSignedTx, bool, error) {
+	fields, err := r.Client.HGetAll(context.Background(), r.coSignedTxKey(txHash)).Result()
+	if err != nil {
+		return PendingCoSignedTx{}, false, eris.Wrap(err, "")
+	}
+	return pendingCoSignedTx(fields)
+}
+
+// AddCoSignature adds the signature of the given co-signer to a pending co-signed transaction and returns the
+// transaction with all signatures that were added so far. The boolean is false if the transaction is not waiting for
+// co-signatures.
+func (r *CoSignStorage) AddCoSignature(txHash, personaTag, signature string) (PendingCoSignedTx, bool, error) {
+	ctx := context.Background()
+	keys := []string{r.coSignedTxKey(txHash)}
+	res, err := addCoSignatureScript.Run(ctx, r.Client, keys, coSignatureFieldPrefix+personaTag, signature).
+		StringSlice()
+	if eris.Is(err, redis.Nil) {
+		return PendingCoSignedTx{}, false, nil
+	} else if err != nil {
+		return PendingCoSignedTx{}, false, eris.Wrap(err, "")
+	}
+	fields := make(map[string]string, len(res)/2) //nolint:gomnd // field and value pairs
+	for i := 0; i+1 < len(res); i += 2 {
+		fields[res[i]] = res[i+1]
+	}
+	return pendingCoSignedTx(fields)
+}
+
+// ClaimPendingCoSignedTx forgets a co-signed transaction that is complete, or that is dropped. Only one caller claims
+// the transaction, so it is added to the transaction pool once, however many replicas completed it concurrently. The
+// boolean is false if the transaction was already claimed or timed out.
+func (r *CoSignStorage) ClaimPendingCoSignedTx(txHash string) (bool, error) {
+	deleted, err := r.Client.Del(context.Background(), r.coSignedTxKey(txHash)).Result()
+	if err != nil {
+		return false, eris.Wrap(err, "")
+	}
+	return deleted == 1, nil
+}
+
+func pendingCoSignedTx(fields map[string]string) (PendingCoSignedTx, bool, error) {
+	if len(fields) == 0 {
+		return PendingCoSignedTx{}, false, nil
+	}
+	data, ok := fields[coSignedTxField]
+	if !ok {
+		return PendingCoSignedTx{}, false, eris.New("pending co-signed transaction has no data")
+	}
+	tx := PendingCoSignedTx{Data: []byte(data), CoSignatures: map[string]string{}}
+	for field, value := range fields {
+		if personaTag, ok := strings.CutPrefix(field, coSignatureFieldPrefix); ok {
+			tx.CoSignatures[personaTag] = value
+		}
+	}
+	return tx, true, nil
+}
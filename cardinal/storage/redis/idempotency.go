@@ -0,0 +1,79 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rotisserie/eris"
+)
+
+// IdempotentTx is the transaction that was submitted with an idempotency key. Retries of the submission with the same
+// key are answered with it instead of being added to the transaction pool again.
+type IdempotentTx struct {
+	TxHash string
+	Tick   uint64
+}
+
+type IdempotencyStorage struct {
+	Client *redis.Client
+}
+
+func NewIdempotencyStorage(client *redis.Client) IdempotencyStorage {
+	return IdempotencyStorage{
+		Client: client,
+	}
+}
+
+// GetIdempotentTx returns the transaction that the persona submitted with the given idempotency key. The boolean is
+// false if the key has not been used, or if it was used longer ago than the window it was claimed with.
+func (r *IdempotencyStorage) GetIdempotentTx(personaTag, key string) (IdempotentTx, bool, error) {
+	ctx := context.Background()
+	bz, err := r.Client.Get(ctx, r.idempotencyKey(personaTag, key)).Bytes()
+	if eris.Is(err, redis.Nil) {
+		return IdempotentTx{}, false, nil
+	} else if err != nil {
+		return IdempotentTx{}, false, eris.Wrap(err, "")
+	}
+	var tx IdempotentTx
+	if err = json.Unmarshal(bz, &tx); err != nil {
+		return IdempotentTx{}, false, eris.Wrap(err, "failed to unmarshal idempotent transaction")
+	}
+	return tx, true, nil
+}
+
+// ClaimIdempotencyKey atomically records tx as the transaction the persona submitted with the given idempotency key.
+// The key is forgotten once the window has passed. If the key was already claimed, nothing is written and the
+// transaction it was claimed with is returned with a false boolean.
+func (r *IdempotencyStorage) ClaimIdempotencyKey(
+	personaTag, key string, tx IdempotentTx, window time.Duration,
+) (IdempotentTx, bool, error) {
+	ctx := context.Background()
+	bz, err := json.Marshal(tx)
+	if err != nil {
+		return IdempotentTx{}, false, eris.Wrap(err, "failed to marshal idempotent transaction")
+	}
+	claimed, err := r.Client.SetNX(ctx, r.idempotencyKey(personaTag, key), bz, window).Result()
+	if err != nil {
+		return IdempotentTx{}, false, eris.Wrap(err, "")
+	}
+	if claimed {
+		return tx, true, nil
+	}
+	existing, found, err := r.GetIdempotentTx(personaTag, key)
+	if err != nil {
+		return IdempotentTx{}, false, err
+	}
+	if !found {
+		// The key expired between SetNX and Get; the window is over, so this is a new submission.
+		return r.ClaimIdempotencyKey(personaTag, key, tx, window)
+	}
+	return existing, false, nil
+}
+
+// ReleaseIdempotencyKey forgets the transaction that the persona submitted with the given idempotency key, e.g. because
+// it could not be added after the key was claimed, so that a retry of the submission is added.
+func (r *IdempotencyStorage) ReleaseIdempotencyKey(personaTag, key string) error {
+	return eris.Wrap(r.Client.Del(context.Background(), r.idempotencyKey(personaTag, key)).Err(), "")
+}
@@ -1,6 +1,47 @@
 package redis
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
+
+/*
+	IDEMPOTENCY STORAGE: PERSONA_TAG + KEY -> The transaction that was submitted with an idempotency key.
+	JSON encoded IdempotentTx that expires after the idempotency window
+*/
+
+func (r *IdempotencyStorage) idempotencyKey(personaTag, key string) string {
+	// Persona tags are case-insensitive.
+	return fmt.Sprintf("IDEMPOTENCY_KEY_%s_%s", strings.ToLower(personaTag), key)
+}
+
+/*
+	REPLICATED TX STORAGE: The queue of transactions that is shared by the replicas of a world, as a hash of encoded
+	transactions by tx hash, the sequence that orders the queued transactions, and a marker that expires after the
+	dedup window for every transaction taken from it.
+*/
+
+func (r *ReplicatedTxStorage) replicatedTxQueueKey() string {
+	return "REPLICATED_TX_QUEUE"
+}
+
+func (r *ReplicatedTxStorage) replicatedTxSeqKey() string {
+	return "REPLICATED_TX_SEQ"
+}
+
+func (r *ReplicatedTxStorage) replicatedTxTakenKey(txHash string) string {
+	return fmt.Sprintf("REPLICATED_TX_TAKEN_%s", txHash)
+}
+
+/*
+	CO-SIGN STORAGE: TX_HASH -> A co-signed transaction that is waiting for the signatures of its co-signers.
+	Hash of the encoded transaction and of the signatures of its co-signers by persona tag, that expires after the
+	co-sign timeout
+*/
+
+func (r *CoSignStorage) coSignedTxKey(txHash string) string {
+	return fmt.Sprintf("COSIGN_PENDING_TX_%s", txHash)
+}
 
 /*
 	NONCE STORAGE:      ADDRESS_TO_NONCE -> Nonce used for verifying signatures.
@@ -14,3 +55,22 @@ func (r *NonceStorage) nonceSetKey(str string) string {
 func (r *SchemaStorage) schemaStorageKey() string {
 	return "COMPONENT_NAME_TO_SCHEMA_DATA"
 }
+
+/*
+	NAMESPACE OWNERSHIP: The world instance that owns the namespace, and a marker that records that the namespace
+	exists, so that the namespaces in a database can be listed.
+*/
+
+func namespaceOwnerKey() string {
+	return "CARDINAL:OWNER"
+}
+
+func namespaceMarkerKey() string {
+	return "CARDINAL:NAMESPACE"
+}
+
+// namespaceMigrationKey records the progress of moving legacy keys into the namespace. See
+// Namespaces.MigrateLegacyKeys.
+func namespaceMigrationKey() string {
+	return "CARDINAL:MIGRATION"
+}
@@ -0,0 +1,328 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rotisserie/eris"
+)
+
+// purgeBatchSize is the number of keys that are scanned and deleted at a time when a namespace is purged.
+const purgeBatchSize = 1000
+
+var ErrNamespaceInUse = errors.New("namespace is owned by a running world")
+
+// legacyKeyPatterns match the keys that worlds stored before their keys were prefixed with their namespace.
+var legacyKeyPatterns = []string{
+	"ECB:*",
+	"USED_NONCES_*",
+	"IDEMPOTENCY_KEY_*",
+	"ADMIN:*",
+	"COMPONENT_NAME_TO_SCHEMA_DATA",
+}
+
+// NamespaceInfo describes a namespace in the database.
+type NamespaceInfo struct {
+	// Name is the namespace of the world.
+	Name string
+	// Keys is the number of keys stored under the namespace.
+	Keys uint64
+	// Owner is the instance of the world that owns the namespace, or empty if no world is running.
+	Owner string
+}
+
+// releaseNamespaceScript deletes the owner of the namespace if it is the given instance, in one step, so that an
+// instance can't release the namespace after another instance claimed it.
+var releaseNamespaceScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// ClaimNamespace makes the given world instance the owner of the storage's namespace for the given time. It fails with
+// ErrNamespaceInUse if another instance owns the namespace, so that two worlds pointed at the same namespace can't
+// interleave their writes. The lease must be extended with RenewNamespace before it expires.
+func (r *Storage) ClaimNamespace(ctx context.Context, instance string, ttl time.Duration) error {
+	if err := r.Client.Set(ctx, namespaceMarkerKey(), r.Namespace, 0).Err(); err != nil {
+		return eris.Wrap(err, "failed to register namespace")
+	}
+	claimed, err := r.Client.SetNX(ctx, namespaceOwnerKey(), instance, ttl).Result()
+	if err != nil {
+		return eris.Wrap(err, "failed to claim namespace")
+	}
+	if claimed {
+		return nil
+	}
+	owner, err := r.Client.Get(ctx, namespaceOwnerKey()).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return eris.Wrap(err, "failed to look up the owner of the namespace")
+	}
+	return eris.Wrapf(ErrNamespaceInUse, "namespace %q is owned by %s", r.Namespace, owner)
+}
+
+// OwnsNamespace returns true if the given world instance is the owner of the storage's namespace.
+func (r *Storage) OwnsNamespace(ctx context.Context, instance string) (bool, error) {
+	owner, err := r.Client.Get(ctx, namespaceOwnerKey()).Result()
+	if errors.Is(err, redis.Nil) {
+		return false, nil
+	} else if err != nil {
+		return false, eris.Wrap(err, "failed to look up the owner of the namespace")
+	}
+	return owner == instance, nil
+}
+
+// ReleaseNamespace removes the ownership of the given world instance, unless another instance has claimed the
+// namespace since.
+func (r *Storage) ReleaseNamespace(ctx context.Context, instance string) error {
+	err := releaseNamespaceScript.Run(ctx, r.Client, []string{namespaceOwnerKey()}, instance).Err()
+	return eris.Wrap(err, "failed to release namespace")
+}
+
+// RenewNamespace extends the lease of the given world instance on the storage's namespace by the given time. It
+// returns false if the instance no longer owns the namespace, because its lease expired or another instance claimed
+// it. The lease is not claimed again then, since another instance may have taken over in the meantime.
+func (r *Storage) RenewNamespace(ctx context.Context, instance string, ttl time.Duration) (bool, error) {
+	renewed := false
+	err := r.Client.Watch(ctx, func(tx *redis.Tx) error {
+		owner, err := tx.Get(ctx, namespaceOwnerKey()).Result()
+		if errors.Is(err, redis.Nil) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if owner != instance {
+			return nil
+		}
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.PExpire(ctx, namespaceOwnerKey(), ttl)
+			return nil
+		})
+		renewed = err == nil
+		return err
+	}, namespaceOwnerKey())
+	if errors.Is(err, redis.TxFailedErr) {
+		// The owner changed while the lease was renewed
+		return false, nil
+	} else if err != nil {
+		return false, eris.Wrap(err, "failed to renew namespace lease")
+	}
+	return renewed, nil
+}
+
+// NamespaceOwnerKey is the key that holds the world instance that owns the storage's namespace. The transactions that
+// commit the ticks of a world are fenced with it. See gamestate.RedisStorage.Fence.
+func NamespaceOwnerKey() string {
+	return namespaceOwnerKey()
+}
+
+// Namespaces manages the namespaces of the worlds that share a redis database. Unlike the client of a Storage, its
+// client doesn't prefix keys, so it sees the keys of every namespace.
+type Namespaces struct {
+	client *redis.Client
+}
+
+// NewNamespaces returns a Namespaces that connects to the database with the given options.
+func NewNamespaces(options Options) *Namespaces {
+	return &Namespaces{client: redis.NewClient(&options)}
+}
+
+func (n *Namespaces) Close() error {
+	return eris.Wrap(n.client.Close(), "")
+}
+
+// List returns the namespaces that have been claimed by a world, sorted by name.
+func (n *Namespaces) List(ctx context.Context) ([]NamespaceInfo, error) {
+	markers, err := n.scan(ctx, "*:"+namespaceMarkerKey())
+	if err != nil {
+		return nil, eris.Wrap(err, "failed to list namespaces")
+	}
+	infos := make([]NamespaceInfo, 0, len(markers))
+	for _, marker := range markers {
+		info := NamespaceInfo{Name: strings.TrimSuffix(marker, ":"+namespaceMarkerKey())}
+		keys, err := n.keys(ctx, info.Name)
+		if err != nil {
+			return nil, err
+		}
+		info.Keys = uint64(len(keys))
+		info.Owner, err = n.client.Get(ctx, prefixed(info.Name, namespaceOwnerKey())).Result()
+		if err != nil && !errors.Is(err, redis.Nil) {
+			return nil, eris.Wrapf(err, "failed to look up the owner of namespace %q", info.Name)
+		}
+		infos = append(infos, info)
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Name < infos[j].Name
+	})
+	return infos, nil
+}
+
+// Purge deletes all keys of the namespace and returns the number of deleted keys. It fails with ErrNamespaceInUse if
+// a world instance owns the namespace, unless force is true.
+func (n *Namespaces) Purge(ctx context.Context, namespace string, force bool) (uint64, error) {
+	owner, err := n.client.Get(ctx, prefixed(namespace, namespaceOwnerKey())).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return 0, eris.Wrapf(err, "failed to look up the owner of namespace %q", namespace)
+	}
+	if owner != "" && !force {
+		return 0, eris.Wrapf(ErrNamespaceInUse, "namespace %q is owned by %s", namespace, owner)
+	}
+	var deleted uint64
+	iter := n.client.Scan(ctx, 0, namespacePattern(namespace), purgeBatchSize).Iterator()
+	batch := make([]string, 0, purgeBatchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		num, err := n.client.Del(ctx, batch...).Result()
+		deleted += uint64(num)
+		batch = batch[:0]
+		return eris.Wrapf(err, "failed to purge namespace %q", namespace)
+	}
+	for iter.Next(ctx) {
+		batch = append(batch, iter.Val())
+		if len(batch) == purgeBatchSize {
+			if err = flush(); err != nil {
+				return deleted, err
+			}
+		}
+	}
+	if err = iter.Err(); err != nil {
+		return deleted, eris.Wrapf(err, "failed to scan namespace %q", namespace)
+	}
+	return deleted, flush()
+}
+
+const (
+	migrationInProgress = "in-progress"
+	migrationDone       = "done"
+)
+
+// moveKeyScript renames a key if it still exists, so that a key that was already moved by an interrupted migration is
+// skipped instead of failing the migration.
+var moveKeyScript = redis.NewScript(`
+if redis.call("EXISTS", KEYS[1]) == 1 then
+	redis.call("RENAME", KEYS[1], KEYS[2])
+	return 1
+end
+return 0
+`)
+
+// MigrateLegacyKeys moves the keys that a world stored before keys were prefixed with the namespace into the given
+// namespace, and returns the number of moved keys. Nothing is moved if the namespace already has keys, since the
+// legacy keys then belong to another world, or were already migrated.
+//
+// The progress of the migration is recorded in the namespace before the first key is moved, so a migration that was
+// interrupted, e.g. because the world crashed, is resumed by the next call instead of leaving the keys split between
+// the namespace and the legacy keys.
+func (n *Namespaces) MigrateLegacyKeys(ctx context.Context, namespace string) (int, error) {
+	migrationKey := prefixed(namespace, namespaceMigrationKey())
+	state, err := n.client.Get(ctx, migrationKey).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return 0, eris.Wrapf(err, "failed to look up the key migration of namespace %q", namespace)
+	}
+	switch state {
+	case migrationDone:
+		return 0, nil
+	case migrationInProgress:
+	default:
+		existing, err := n.keys(ctx, namespace)
+		if err != nil {
+			return 0, err
+		}
+		if len(existing) > 0 {
+			return 0, nil
+		}
+		if err = n.client.Set(ctx, migrationKey, migrationInProgress, 0).Err(); err != nil {
+			return 0, eris.Wrapf(err, "failed to start the key migration of namespace %q", namespace)
+		}
+	}
+
+	claimed, err := n.List(ctx)
+	if err != nil {
+		return 0, err
+	}
+	moved := 0
+	for _, pattern := range legacyKeyPatterns {
+		keys, err := n.scan(ctx, pattern)
+		if err != nil {
+			return moved, eris.Wrap(err, "failed to look up legacy keys")
+		}
+		for _, key := range keys {
+			if key == migrationKey || inNamespace(key, claimed) || isMigratedKey(key, namespace) {
+				continue
+			}
+			num, err := moveKeyScript.Run(ctx, n.client, []string{key, prefixed(namespace, key)}).Int()
+			if err != nil {
+				return moved, eris.Wrapf(err, "failed to move legacy key %q to namespace %q", key, namespace)
+			}
+			moved += num
+		}
+	}
+	if err = n.client.Set(ctx, migrationKey, migrationDone, 0).Err(); err != nil {
+		return moved, eris.Wrapf(err, "failed to finish the key migration of namespace %q", namespace)
+	}
+	return moved, nil
+}
+
+func (n *Namespaces) keys(ctx context.Context, namespace string) ([]string, error) {
+	keys, err := n.scan(ctx, namespacePattern(namespace))
+	return keys, eris.Wrapf(err, "failed to look up keys of namespace %q", namespace)
+}
+
+// scan returns the keys that match the pattern. Unlike KEYS, SCAN doesn't block the database while a large keyspace is
+// searched.
+func (n *Namespaces) scan(ctx context.Context, pattern string) ([]string, error) {
+	var keys []string
+	iter := n.client.Scan(ctx, 0, pattern, purgeBatchSize).Iterator()
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+	}
+	return keys, iter.Err()
+}
+
+// NamespaceKeyPrefix is the prefix of the keys of the given namespace. See Storage.SetKeyPrefix.
+func NamespaceKeyPrefix(namespace string) string {
+	return namespace + ":"
+}
+
+func prefixed(namespace, key string) string {
+	return NamespaceKeyPrefix(namespace) + key
+}
+
+func namespacePattern(namespace string) string {
+	return NamespaceKeyPrefix(namespace) + "*"
+}
+
+// inNamespace reports if the key belongs to one of the given namespaces, e.g. to a world whose namespace is ECB.
+func inNamespace(key string, namespaces []NamespaceInfo) bool {
+	for _, ns := range namespaces {
+		if strings.HasPrefix(key, NamespaceKeyPrefix(ns.Name)) {
+			return true
+		}
+	}
+	return false
+}
+
+// isMigratedKey reports if the key is a legacy key that was already moved into the namespace, which looks like a legacy
+// key itself if the namespace does, e.g. ECB.
+func isMigratedKey(key, namespace string) bool {
+	unprefixed, ok := strings.CutPrefix(key, NamespaceKeyPrefix(namespace))
+	if !ok {
+		return false
+	}
+	for _, pattern := range legacyKeyPatterns {
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+			if strings.HasPrefix(unprefixed, prefix) {
+				return true
+			}
+		} else if unprefixed == pattern {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,95 @@
+package redis
+
+import (
+	"context"
+	"net"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var _ redis.Hook = keyPrefixHook{}
+
+// keyPrefixHook prepends a prefix to the keys of every command sent through a client. This allows several worlds to
+// share one redis database without their keys colliding.
+type keyPrefixHook struct {
+	prefix string
+}
+
+// SetKeyPrefix makes every key read or written through this storage start with the given prefix. It must be called
+// before the storage is used.
+func (r *Storage) SetKeyPrefix(prefix string) {
+	r.Client.AddHook(keyPrefixHook{prefix: prefix})
+}
+
+func (h keyPrefixHook) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return next(ctx, network, addr)
+	}
+}
+
+func (h keyPrefixHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		h.addPrefix(cmd)
+		err := next(ctx, cmd)
+		h.trimPrefix(cmd)
+		return err
+	}
+}
+
+func (h keyPrefixHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		for _, cmd := range cmds {
+			h.addPrefix(cmd)
+		}
+		err := next(ctx, cmds)
+		for _, cmd := range cmds {
+			h.trimPrefix(cmd)
+		}
+		return err
+	}
+}
+
+func (h keyPrefixHook) addPrefix(cmd redis.Cmder) {
+	args := cmd.Args()
+	if len(args) < 2 { //nolint:gomnd // commands without a key
+		return
+	}
+	switch strings.ToLower(cmd.Name()) {
+	case "del", "exists", "unlink", "mget":
+		for i := 1; i < len(args); i++ {
+			args[i] = h.prefixed(args[i])
+		}
+	case "eval", "evalsha":
+		// The keys of a script follow the script and the number of keys
+		numKeys, ok := args[2].(int)
+		for i := 3; ok && i < 3+numKeys && i < len(args); i++ {
+			args[i] = h.prefixed(args[i])
+		}
+	case "hello", "auth", "client", "select", "ping", "info", "multi", "exec", "flushall", "flushdb", "shutdown":
+	default:
+		// All other commands used by cardinal (including KEYS, whose first argument is a pattern) take a single key as
+		// their first argument.
+		args[1] = h.prefixed(args[1])
+	}
+}
+
+// trimPrefix removes the prefix from the keys returned by KEYS, so callers never see it.
+func (h keyPrefixHook) trimPrefix(cmd redis.Cmder) {
+	keysCmd, ok := cmd.(*redis.StringSliceCmd)
+	if !ok || strings.ToLower(cmd.Name()) != "keys" {
+		return
+	}
+	keys := keysCmd.Val()
+	for i, key := range keys {
+		keys[i] = strings.TrimPrefix(key, h.prefix)
+	}
+	keysCmd.SetVal(keys)
+}
+
+func (h keyPrefixHook) prefixed(arg any) any {
+	if key, ok := arg.(string); ok {
+		return h.prefix + key
+	}
+	return arg
+}
@@ -0,0 +1,105 @@
+package redis
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rotisserie/eris"
+)
+
+// enqueueReplicatedTxScript adds a transaction to the queue, unless it is already queued or was taken from the queue
+// within the dedup window. The transaction is stored with the next number of the queue's sequence, so that the queued
+// transactions are ordered the same way whichever replica received them, regardless of the replicas' clocks.
+var enqueueReplicatedTxScript = redis.NewScript(`
+if redis.call("EXISTS", KEYS[2]) == 1 or redis.call("HEXISTS", KEYS[1], ARGV[1]) == 1 then
+	return 0
+end
+local seq = redis.call("INCR", KEYS[3])
+redis.call("HSET", KEYS[1], ARGV[1], seq .. ":" .. ARGV[2])
+return 1
+`)
+
+// ReplicatedTxStorage is the queue of transactions that is shared by the replicas of a world. Any replica adds the
+// transactions it receives to the queue, and the replica that ticks takes them from it, so that a transaction that is
+// submitted to several replicas is executed once.
+type ReplicatedTxStorage struct {
+	Client *redis.Client
+}
+
+// ReplicatedTx is an encoded transaction in the queue that is shared by the replicas of a world.
+type ReplicatedTx struct {
+	TxHash string
+	// Seq is the position of the transaction in the queue.
+	Seq  uint64
+	Data []byte
+}
+
+func NewReplicatedTxStorage(client *redis.Client) ReplicatedTxStorage {
+	return ReplicatedTxStorage{
+		Client: client,
+	}
+}
+
+// EnqueueReplicatedTx adds the encoded transaction with the given hash to the shared queue. The boolean is false if a
+// transaction with the same hash is already queued, or was taken from the queue within the dedup window it was taken
+// with.
+func (r *ReplicatedTxStorage) EnqueueReplicatedTx(txHash string, bz []byte) (bool, error) {
+	ctx := context.Background()
+	keys := []string{r.replicatedTxQueueKey(), r.replicatedTxTakenKey(txHash), r.replicatedTxSeqKey()}
+	added, err := enqueueReplicatedTxScript.Run(ctx, r.Client, keys, txHash, bz).Int()
+	if err != nil {
+		return false, eris.Wrap(err, "")
+	}
+	return added == 1, nil
+}
+
+// TakeReplicatedTxs returns the transactions of the shared queue in the order they were queued in. Their hashes are
+// remembered for the given window, so that a transaction that is submitted again is not queued again. The transactions
+// stay in the queue until their hashes are deleted from the hash at ReplicatedTxQueueKey, in the transaction that
+// persists the tick they are executed in, so that a replica that fails before then doesn't lose them. Only the replica
+// that ticks may take transactions from the queue.
+func (r *ReplicatedTxStorage) TakeReplicatedTxs(window time.Duration) ([]ReplicatedTx, error) {
+	ctx := context.Background()
+	queued, err := r.Client.HGetAll(ctx, r.replicatedTxQueueKey()).Result()
+	if err != nil {
+		return nil, eris.Wrap(err, "")
+	}
+	txs := make([]ReplicatedTx, 0, len(queued))
+	for txHash, value := range queued {
+		seq, bz, ok := strings.Cut(value, ":")
+		if !ok {
+			return nil, eris.Errorf("replicated transaction %s has no sequence number", txHash)
+		}
+		tx := ReplicatedTx{TxHash: txHash, Data: []byte(bz)}
+		if tx.Seq, err = strconv.ParseUint(seq, 10, 64); err != nil {
+			return nil, eris.Wrapf(err, "invalid sequence number of replicated transaction %s", txHash)
+		}
+		txs = append(txs, tx)
+	}
+	sort.Slice(txs, func(i, j int) bool {
+		return txs[i].Seq < txs[j].Seq
+	})
+	if len(txs) == 0 {
+		return txs, nil
+	}
+	_, err = r.Client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, tx := range txs {
+			pipe.Set(ctx, r.replicatedTxTakenKey(tx.TxHash), 1, window)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, eris.Wrap(err, "")
+	}
+	return txs, nil
+}
+
+// ReplicatedTxQueueKey is the key of the hash that holds the shared queue. Transactions that were taken from the queue
+// are removed by deleting their hashes from it.
+func (r *ReplicatedTxStorage) ReplicatedTxQueueKey() string {
+	return r.replicatedTxQueueKey()
+}
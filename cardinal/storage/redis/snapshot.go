@@ -0,0 +1,147 @@
+package redis
+
+import (
+	"encoding/gob"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/rotisserie/eris"
+)
+
+// snapshotVersion is incremented when the format of snapshot files changes.
+const snapshotVersion = 1
+
+// snapshot is the content of a file written by SaveSnapshot. It is encoded with gob rather than JSON, since values
+// can be binary, e.g. components that are stored as MessagePack.
+type snapshot struct {
+	Version int
+	Keys    []snapshotKey
+}
+
+// snapshotKey is a key of a miniredis instance and its value. Only the field for the type of the key is set.
+type snapshotKey struct {
+	Key    string
+	Type   string
+	TTL    time.Duration
+	String string
+	Hash   map[string]string
+	List   []string
+	Set    []string
+	ZSet   map[string]float64
+}
+
+// SaveSnapshot writes every key of the miniredis instance to the file at path, so that a world that runs on an
+// in-memory redis can be restored with LoadSnapshot after it is restarted. The file is replaced atomically, so it holds
+// either the previous or the new snapshot if the process is killed while saving.
+func SaveSnapshot(mr *miniredis.Miniredis, path string) error {
+	snap := snapshot{Version: snapshotVersion}
+	for _, key := range mr.Keys() {
+		sk, err := snapshotOf(mr, key)
+		if err != nil {
+			return err
+		}
+		snap.Keys = append(snap.Keys, sk)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return eris.Wrap(err, "failed to create snapshot file")
+	}
+	defer os.Remove(tmp.Name())
+	if err := gob.NewEncoder(tmp).Encode(snap); err != nil {
+		_ = tmp.Close()
+		return eris.Wrap(err, "failed to write snapshot")
+	}
+	if err := tmp.Close(); err != nil {
+		return eris.Wrap(err, "failed to write snapshot")
+	}
+	return eris.Wrap(os.Rename(tmp.Name(), path), "failed to replace snapshot file")
+}
+
+// LoadSnapshot stores the keys of the snapshot file at path in the miniredis instance, replacing keys with the same
+// name. It does nothing if the file does not exist, so a world that is started for the first time starts empty.
+func LoadSnapshot(mr *miniredis.Miniredis, path string) error {
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return eris.Wrap(err, "failed to open snapshot file")
+	}
+	defer f.Close()
+
+	var snap snapshot
+	if err := gob.NewDecoder(f).Decode(&snap); err != nil {
+		return eris.Wrapf(err, "failed to read snapshot file %q", path)
+	}
+	if snap.Version != snapshotVersion {
+		return eris.Errorf("snapshot file %q has version %d, expected %d", path, snap.Version, snapshotVersion)
+	}
+	for _, sk := range snap.Keys {
+		if err := restoreKey(mr, sk); err != nil {
+			return eris.Wrapf(err, "failed to restore key %q", sk.Key)
+		}
+	}
+	return nil
+}
+
+func snapshotOf(mr *miniredis.Miniredis, key string) (snapshotKey, error) {
+	sk := snapshotKey{Key: key, Type: mr.Type(key), TTL: mr.TTL(key)}
+	var err error
+	switch sk.Type {
+	case "string":
+		sk.String, err = mr.Get(key)
+	case "hash":
+		var fields []string
+		fields, err = mr.HKeys(key)
+		sk.Hash = make(map[string]string, len(fields))
+		for _, field := range fields {
+			sk.Hash[field] = mr.HGet(key, field)
+		}
+	case "list":
+		sk.List, err = mr.List(key)
+	case "set":
+		sk.Set, err = mr.Members(key)
+	case "zset":
+		sk.ZSet, err = mr.SortedSet(key)
+	default:
+		return sk, eris.Errorf("key %q has type %q, which can't be saved in a snapshot", key, sk.Type)
+	}
+	return sk, eris.Wrapf(err, "failed to read key %q", key)
+}
+
+func restoreKey(mr *miniredis.Miniredis, sk snapshotKey) error {
+	mr.Del(sk.Key)
+	var err error
+	switch sk.Type {
+	case "string":
+		err = mr.Set(sk.Key, sk.String)
+	case "hash":
+		for field, value := range sk.Hash {
+			mr.HSet(sk.Key, field, value)
+		}
+	case "list":
+		_, err = mr.Push(sk.Key, sk.List...)
+	case "set":
+		_, err = mr.SetAdd(sk.Key, sk.Set...)
+	case "zset":
+		for member, score := range sk.ZSet {
+			if _, err = mr.ZAdd(sk.Key, score, member); err != nil {
+				break
+			}
+		}
+	default:
+		err = eris.Errorf("unknown type %q", sk.Type)
+	}
+	if err != nil {
+		return err
+	}
+	if sk.TTL > 0 {
+		mr.SetTTL(sk.Key, sk.TTL)
+	}
+	return nil
+}
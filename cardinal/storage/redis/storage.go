@@ -14,6 +14,9 @@ type Storage struct {
 	Log       zerolog.Logger
 	NonceStorage
 	SchemaStorage
+	IdempotencyStorage
+	ReplicatedTxStorage
+	CoSignStorage
 }
 
 type Options = redis.Options
@@ -21,11 +24,14 @@ type Options = redis.Options
 func NewRedisStorage(options Options, namespace string) Storage {
 	client := redis.NewClient(&options)
 	return Storage{
-		Namespace:     namespace,
-		Client:        client,
-		Log:           zerolog.New(os.Stdout),
-		NonceStorage:  NewNonceStorage(client),
-		SchemaStorage: NewSchemaStorage(client),
+		Namespace:           namespace,
+		Client:              client,
+		Log:                 zerolog.New(os.Stdout),
+		NonceStorage:        NewNonceStorage(client),
+		SchemaStorage:       NewSchemaStorage(client),
+		IdempotencyStorage:  NewIdempotencyStorage(client),
+		ReplicatedTxStorage: NewReplicatedTxStorage(client),
+		CoSignStorage:       NewCoSignStorage(client),
 	}
 }
 
@@ -0,0 +1,52 @@
+package storage_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal/storage/redis"
+)
+
+func TestReplicatedTxsAreTakenInTheOrderTheyWereQueued(t *testing.T) {
+	s := miniredis.RunT(t)
+	rs := newNamespacedStorage(s, Namespace)
+
+	for _, txHash := range []string{"0xc", "0xa", "0xb"} {
+		added, err := rs.EnqueueReplicatedTx(txHash, []byte("tx "+txHash))
+		assert.NilError(t, err)
+		assert.Check(t, added)
+	}
+	added, err := rs.EnqueueReplicatedTx("0xa", []byte("tx 0xa"))
+	assert.NilError(t, err)
+	assert.Check(t, !added)
+
+	txs, err := rs.TakeReplicatedTxs(time.Minute)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []redis.ReplicatedTx{
+		{TxHash: "0xc", Seq: 1, Data: []byte("tx 0xc")},
+		{TxHash: "0xa", Seq: 2, Data: []byte("tx 0xa")},
+		{TxHash: "0xb", Seq: 3, Data: []byte("tx 0xb")},
+	}, txs)
+
+	// Taken transactions stay in the queue until they are removed, but are not queued again.
+	txs, err = rs.TakeReplicatedTxs(time.Minute)
+	assert.NilError(t, err)
+	assert.Equal(t, 3, len(txs))
+	assert.NilError(t, rs.Client.HDel(context.Background(), rs.ReplicatedTxQueueKey(), "0xc", "0xa").Err())
+	added, err = rs.EnqueueReplicatedTx("0xc", []byte("tx 0xc"))
+	assert.NilError(t, err)
+	assert.Check(t, !added)
+	txs, err = rs.TakeReplicatedTxs(time.Minute)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []redis.ReplicatedTx{{TxHash: "0xb", Seq: 3, Data: []byte("tx 0xb")}}, txs)
+
+	// A transaction can be queued again once the window passed.
+	s.FastForward(2 * time.Minute)
+	added, err = rs.EnqueueReplicatedTx("0xc", []byte("tx 0xc"))
+	assert.NilError(t, err)
+	assert.Check(t, added)
+}
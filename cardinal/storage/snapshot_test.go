@@ -0,0 +1,47 @@
+package storage_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal/storage/redis"
+)
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snapshot")
+	src := miniredis.RunT(t)
+	assert.NilError(t, src.Set("string", "\xff\x00binary"))
+	src.SetTTL("string", time.Minute)
+	src.HSet("hash", "a", "1", "b", "2")
+	_, err := src.Push("list", "x", "y", "z")
+	assert.NilError(t, err)
+	_, err = src.SetAdd("set", "m", "n")
+	assert.NilError(t, err)
+	_, err = src.ZAdd("zset", 1.5, "p")
+	assert.NilError(t, err)
+	assert.NilError(t, redis.SaveSnapshot(src, path))
+
+	dst := miniredis.RunT(t)
+	assert.NilError(t, dst.Set("string", "overwritten"))
+	assert.NilError(t, redis.LoadSnapshot(dst, path))
+
+	assert.DeepEqual(t, src.Keys(), dst.Keys())
+	dst.CheckGet(t, "string", "\xff\x00binary")
+	assert.Equal(t, time.Minute, dst.TTL("string"))
+	assert.Equal(t, "2", dst.HGet("hash", "b"))
+	dst.CheckList(t, "list", "x", "y", "z")
+	dst.CheckSet(t, "set", "m", "n")
+	score, err := dst.ZScore("zset", "p")
+	assert.NilError(t, err)
+	assert.Equal(t, 1.5, score)
+}
+
+func TestLoadSnapshotOfMissingFileIsEmpty(t *testing.T) {
+	mr := miniredis.RunT(t)
+	assert.NilError(t, redis.LoadSnapshot(mr, filepath.Join(t.TempDir(), "missing")))
+	assert.Len(t, mr.Keys(), 0)
+}
@@ -0,0 +1,233 @@
+package cardinal
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rotisserie/eris"
+	"github.com/rs/zerolog/log"
+
+	"pkg.world.dev/world-engine/cardinal/gamestate"
+	"pkg.world.dev/world-engine/cardinal/statsd"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/txpool"
+)
+
+const (
+	DefaultStorageBreakerThreshold  = 3
+	DefaultStorageBreakerMinBackoff = time.Second
+	DefaultStorageBreakerMaxBackoff = time.Minute
+)
+
+// StorageBreaker configures the circuit breaker that stops the world from ticking while its storage is unavailable.
+// A tick that fails with a storage error, e.g. because redis is unreachable or a storage operation exceeded the tick
+// timeout, doesn't stop the world. Its changes are discarded and it is run again, with the same transactions and
+// timestamp, at the next scheduled tick. Once Threshold consecutive ticks failed, the breaker opens: the world reports
+// the outage through its health status, rejects new transactions, and only runs the failed tick again after a backoff
+// that grows with every failed retry. The breaker closes when the tick succeeds. Zero values are replaced by defaults.
+type StorageBreaker struct {
+	// Threshold is the number of consecutive ticks that must fail with a storage error for the breaker to open.
+	Threshold int
+	// MinBackoff is the time between the opening of the breaker and the first retry. It doubles after every failed
+	// retry, up to MaxBackoff.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+	// OnOpen is called with the error of the last failed tick when the breaker opens, e.g. to alert the operators.
+	OnOpen func(err error)
+	// OnClose is called with the duration of the outage when the breaker closes.
+	OnClose func(outage time.Duration)
+}
+
+// storageBreaker is the state of the storage circuit breaker. See WithStorageBreaker.
+type storageBreaker struct {
+	config StorageBreaker
+	// backoff is the time until the next retry once the breaker is open.
+	backoff time.Duration
+	// pending are the transactions and the timestamp of the tick that is running, which are kept until the tick
+	// succeeds so that it can be run again. It is only used by the game loop.
+	pending *pendingTick
+
+	// health is read by the HTTP server, so it is guarded by mu.
+	mu     sync.Mutex
+	health types.StorageHealth
+}
+
+type pendingTick struct {
+	txPool    *txpool.TxPool
+	timestamp uint64
+}
+
+func newStorageBreaker(config StorageBreaker) *storageBreaker {
+	if config.Threshold <= 0 {
+		config.Threshold = DefaultStorageBreakerThreshold
+	}
+	if config.MinBackoff <= 0 {
+		config.MinBackoff = DefaultStorageBreakerMinBackoff
+	}
+	if config.MaxBackoff < config.MinBackoff {
+		config.MaxBackoff = max(DefaultStorageBreakerMaxBackoff, config.MinBackoff)
+	}
+	return &storageBreaker{config: config}
+}
+
+// StorageHealth returns the health of the storage of the world. The storage is always reported as healthy unless the
+// world was created with WithStorageBreaker.
+func (w *World) StorageHealth() types.StorageHealth {
+	if w.storageBreaker == nil {
+		return types.StorageHealth{}
+	}
+	return w.storageBreaker.getHealth()
+}
+
+// takeTickTransactions takes the transactions of the next tick from the pool. If the last tick failed with a storage
+// error, its transactions are returned instead, since the tick is run again.
+func (w *World) takeTickTransactions(timestamp uint64) *txpool.TxPool {
+	b := w.storageBreaker
+	if b == nil {
+		return w.txPool.CopyTransactions()
+	}
+	if b.pending == nil {
+		b.pending = &pendingTick{txPool: w.txPool.CopyTransactions(), timestamp: timestamp}
+	}
+	// The tick removes the expired transactions from its pool, so it gets a copy
+	return b.pending.txPool.Clone()
+}
+
+// tickTimestamp returns the timestamp of the next tick, which is the timestamp of the last tick if it failed with a
+// storage error.
+func (w *World) tickTimestamp(now time.Time) uint64 {
+	if b := w.storageBreaker; b != nil && b.pending != nil {
+		return b.pending.timestamp
+	}
+	return uint64(now.Unix())
+}
+
+// skipTickForStorage returns true if the tick must be skipped because the breaker is open and the failed tick must not
+// be retried yet.
+func (w *World) skipTickForStorage(now time.Time) bool {
+	b := w.storageBreaker
+	if b == nil {
+		return false
+	}
+	health := b.getHealth()
+	return health.Outage && now.Before(health.NextRetry)
+}
+
+// handleStorageError records that the tick failed with the given error and discards its changes, if it is a storage
+// error. It returns false if the error is not handled by the breaker.
+func (w *World) handleStorageError(err error) bool {
+	b := w.storageBreaker
+	if b == nil || !isStorageError(err) {
+		return false
+	}
+	ecb, ok := w.entityStore.(*gamestate.EntityCommandBuffer)
+	if !ok {
+		return false
+	}
+	if abortErr := ecb.AbortTick(); abortErr != nil {
+		log.Error().Err(abortErr).Msg("failed to discard the changes of the failed tick")
+		return false
+	}
+	if abortErr := w.discardSystemChanges(ecb, 0); abortErr != nil {
+		log.Error().Err(abortErr).Msg("failed to discard the changes of the failed tick")
+		return false
+	}
+	b.tickFailed(w.CurrentTick(), err, time.Now())
+	return true
+}
+
+// checkFailedTickCommitted handles a tick that failed with a storage error but was committed anyway, e.g. because
+// the connection was lost after the commit was sent. Such a tick must not be run again, so the world moves on to the
+// next tick. The receipts and events of the tick are lost, and its transactions are not submitted to the base shard.
+func (w *World) checkFailedTickCommitted() error {
+	b := w.storageBreaker
+	if b == nil || b.pending == nil {
+		return nil
+	}
+	_, end, err := w.entityStore.GetTickNumbers()
+	if err != nil {
+		return err
+	}
+	if end <= w.CurrentTick() {
+		return nil
+	}
+	log.Warn().Uint64("tick", w.CurrentTick()).
+		Msg("the tick that failed with a storage error was committed, its receipts and events are lost")
+	w.tick.Add(1)
+	w.receiptHistory.NextTick()
+	b.tickSucceeded(time.Now())
+	return nil
+}
+
+func (b *storageBreaker) getHealth() types.StorageHealth {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.health
+}
+
+// tickFailed counts a failed tick, and opens the breaker once the threshold is reached.
+func (b *storageBreaker) tickFailed(tick uint64, err error, now time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.health.ConsecutiveErrors++
+	b.health.LastError = err.Error()
+	if b.health.Outage {
+		// Only the failed retries are logged while the breaker is open, which are spaced by the backoff
+		b.backoff = min(2*b.backoff, b.config.MaxBackoff)
+		b.health.NextRetry = now.Add(b.backoff)
+		log.Warn().Err(err).Uint64("tick", tick).Dur("next_retry", b.backoff).Msg("storage is still unavailable")
+		return
+	}
+	if b.health.ConsecutiveErrors < b.config.Threshold {
+		log.Warn().Err(err).Uint64("tick", tick).Int("consecutive_errors", b.health.ConsecutiveErrors).
+			Msg("tick failed with a storage error, it will be run again")
+		return
+	}
+	b.backoff = b.config.MinBackoff
+	b.health.Outage = true
+	b.health.OutageSince = now
+	b.health.NextRetry = now.Add(b.backoff)
+	log.Error().Err(err).Uint64("tick", tick).Int("consecutive_errors", b.health.ConsecutiveErrors).
+		Msg("storage is unavailable, the world stops ticking until it recovers")
+	if statsdErr := statsd.Client().Count("storage_outage", 1, nil, 1); statsdErr != nil {
+		log.Warn().Err(statsdErr).Msg("failed to emit storage outage")
+	}
+	if b.config.OnOpen != nil {
+		b.config.OnOpen(err)
+	}
+}
+
+// tickSucceeded closes the breaker and forgets the transactions of the tick.
+func (b *storageBreaker) tickSucceeded(now time.Time) {
+	b.pending = nil
+	b.mu.Lock()
+	health := b.health
+	b.health = types.StorageHealth{}
+	b.mu.Unlock()
+	if !health.Outage {
+		return
+	}
+	outage := now.Sub(health.OutageSince)
+	log.Info().Dur("outage", outage).Msg("storage recovered, the world resumes ticking")
+	if b.config.OnClose != nil {
+		b.config.OnClose(outage)
+	}
+}
+
+// isStorageError returns true if the error was caused by the storage being unavailable, as opposed to an error of the
+// game logic or corrupted data.
+func isStorageError(err error) bool {
+	for _, e := range []error{err, eris.Cause(err)} {
+		var netErr net.Error
+		if errors.As(e, &netErr) || errors.Is(e, io.EOF) || errors.Is(e, io.ErrUnexpectedEOF) ||
+			errors.Is(e, context.DeadlineExceeded) || errors.Is(e, redis.ErrClosed) {
+			return true
+		}
+	}
+	return false
+}
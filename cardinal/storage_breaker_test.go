@@ -0,0 +1,85 @@
+package cardinal_test
+
+import (
+	"testing"
+	"time"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/message"
+	"pkg.world.dev/world-engine/cardinal/testutils"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+type HeartbeatMsg struct{}
+
+func TestStorageBreakerPausesTickingDuringStorageOutage(t *testing.T) {
+	opened, closed := 0, 0
+	tf := testutils.NewTestFixture(t, nil, cardinal.WithStorageBreaker(cardinal.StorageBreaker{
+		Threshold:  2,
+		MinBackoff: 10 * time.Millisecond,
+		MaxBackoff: 20 * time.Millisecond,
+		OnOpen:     func(error) { opened++ },
+		OnClose:    func(time.Duration) { closed++ },
+	}))
+	world := tf.World
+	assert.NilError(t, cardinal.RegisterMessage[HeartbeatMsg, HeartbeatMsg](world, "heartbeat"))
+	var executed []types.TxHash
+	assert.NilError(t, cardinal.RegisterSystems(world, func(wCtx engine.Context) error {
+		return cardinal.EachMessage[HeartbeatMsg, HeartbeatMsg](wCtx,
+			func(tx message.TxData[HeartbeatMsg]) (HeartbeatMsg, error) {
+				executed = append(executed, tx.Hash)
+				return HeartbeatMsg{}, nil
+			})
+	}))
+	tf.StartWorld()
+	tf.DoTick()
+	heartbeat, ok := world.GetMessageByFullName("game.heartbeat")
+	assert.True(t, ok)
+	txHash := tf.AddTransaction(heartbeat.ID(), HeartbeatMsg{}, testutils.UniqueSignature())
+
+	// waitForErrors ticks until the given number of consecutive ticks failed. A failed tick is not reported on the
+	// tick done channel.
+	waitForErrors := func(want int) {
+		for i := 0; world.StorageHealth().ConsecutiveErrors < want; i++ {
+			assert.Assert(t, i < 100, "the ticks did not fail")
+			tf.StartTickCh <- time.Now()
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	tf.Redis.Close()
+	waitForErrors(1)
+	assert.Assert(t, !world.StorageHealth().Outage)
+	assert.Assert(t, world.CheckBackPressure() == nil)
+
+	waitForErrors(2)
+	health := world.StorageHealth()
+	assert.Assert(t, health.Outage)
+	assert.Assert(t, health.LastError != "")
+	assert.Assert(t, !health.OutageSince.IsZero())
+	assert.Equal(t, 1, opened)
+	retry := world.CheckBackPressure()
+	assert.Assert(t, retry != nil)
+	assert.Equal(t, types.RetryReasonStorageOutage, retry.Reason)
+	assert.Equal(t, uint64(1), world.CurrentTick())
+	assert.Equal(t, 0, len(executed))
+
+	// The failed tick is run again once the storage recovers, with its transactions
+	assert.NilError(t, tf.Redis.Restart())
+	done := false
+	for i := 0; !done; i++ {
+		assert.Assert(t, i < 100, "the world did not resume ticking")
+		select {
+		case tf.StartTickCh <- time.Now():
+		case <-tf.DoneTickCh:
+			done = true
+		}
+	}
+	assert.Equal(t, uint64(2), world.CurrentTick())
+	assert.Equal(t, types.StorageHealth{}, world.StorageHealth())
+	assert.Equal(t, 1, closed)
+	assert.Assert(t, world.CheckBackPressure() == nil)
+	assert.DeepEqual(t, []types.TxHash{txHash}, executed)
+}
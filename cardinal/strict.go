@@ -0,0 +1,456 @@
+package cardinal
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"runtime/pprof"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rotisserie/eris"
+)
+
+// systemLabel is the pprof label attached to goroutines spawned by a system when strict mode is enabled. Goroutines
+// inherit the labels of their parent, which lets us find goroutines that outlive the tick that started them.
+const systemLabel = "cardinal_system"
+
+const (
+	leakCheckAttempts = 10
+	leakCheckInterval = time.Millisecond
+)
+
+var (
+	// ErrNondeterministicSystem is returned when a system calls an API that can make the game state diverge between two
+	// runs of the same tick. See World.LintSystems and WithStrictMode.
+	ErrNondeterministicSystem = eris.New("system is not deterministic")
+
+	// ErrSystemSourceUnavailable is returned by World.LintSystems when the source of a system can't be read, e.g.
+	// because the binary runs without its sources.
+	ErrSystemSourceUnavailable = eris.New("system source is unavailable")
+
+	// bannedTimeFuncs are the functions of the time package that read the wall clock.
+	bannedTimeFuncs = map[string]bool{"Now": true, "Since": true, "Until": true}
+
+	// allowedRandFuncs are the functions of math/rand that do not touch the global source.
+	allowedRandFuncs = map[string]bool{
+		"New": true, "NewSource": true, "NewPCG": true, "NewChaCha8": true, "NewZipf": true,
+	}
+
+	// engineMapMethods are methods of engine types that return a map. Ranging over their result visits the entries in a
+	// random order.
+	engineMapMethods = map[string]bool{"Transactions": true, "GetInFieldInformation": true}
+
+	parsedFiles sync.Map // map[string]*parsedFile
+
+	// WallClock is the clock read by Now. It defaults to time.Now and can be replaced before the world is started, e.g.
+	// by tests that need a fixed time.
+	WallClock = time.Now
+
+	// GlobalRandSource is the source of the generators returned by GlobalRand. It defaults to the global source of
+	// math/rand/v2 and can be replaced before the world is started, e.g. by tests that need a fixed sequence.
+	GlobalRandSource rand.Source = globalRandSource{}
+)
+
+// maxStrictStackDepth is the number of frames that are searched for a system run in strict mode when Now or GlobalRand
+// is called.
+const maxStrictStackDepth = 256
+
+// Now returns the time of WallClock. Code that is shared between systems and the rest of a game, and that needs the
+// wall clock outside of systems, should read it through Now rather than time.Now: in strict mode, a call to Now from a
+// system panics and fails the tick with ErrNondeterministicSystem, since the time differs when the tick is replayed.
+// Systems use engine.Context.Timestamp instead.
+func Now() time.Time {
+	failStrictSystem("cardinal.Now reads the wall clock, use engine.Context.Timestamp instead")
+	return WallClock()
+}
+
+// GlobalRand returns a generator that draws from GlobalRandSource. Like Now, it is meant for code that is shared
+// between systems and the rest of a game: in strict mode, a call to GlobalRand from a system panics and fails the tick
+// with ErrNondeterministicSystem, since the numbers differ when the tick is replayed. Systems use engine.Context.Rand
+// instead.
+func GlobalRand() *rand.Rand {
+	failStrictSystem("cardinal.GlobalRand uses the global source, use engine.Context.Rand instead")
+	return rand.New(GlobalRandSource)
+}
+
+type globalRandSource struct{}
+
+func (globalRandSource) Uint64() uint64 {
+	return rand.Uint64()
+}
+
+// strictViolation is the panic value with which Now and GlobalRand abort a system that runs in strict mode. runStrict
+// recovers it and returns ErrNondeterministicSystem.
+type strictViolation struct {
+	reason string
+}
+
+// failStrictSystem panics with a strictViolation if it is called from a system that runs in strict mode, i.e. if
+// runStrict is on the stack of the calling goroutine. Goroutines spawned by the system are not checked, since
+// checkGoroutineLeaks already fails the ticks they outlive.
+func failStrictSystem(reason string) {
+	pcs := make([]uintptr, maxStrictStackDepth)
+	// Skip runtime.Callers, failStrictSystem and the shim
+	n := runtime.Callers(3, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	runStrictEntry := reflect.ValueOf(runStrict).Pointer()
+	for {
+		frame, more := frames.Next()
+		if frame.Entry == runStrictEntry {
+			panic(strictViolation{reason: reason})
+		}
+		if !more {
+			return
+		}
+	}
+}
+
+type parsedFile struct {
+	fset *token.FileSet
+	file *ast.File
+}
+
+// LintSystems statically inspects the sources of the registered systems, and of the functions of their package that
+// they call, for APIs that can make the game state diverge when a tick is replayed: reading the wall clock, using the
+// global math/rand source, spawning goroutines, and ranging over maps. It returns ErrNondeterministicSystem listing
+// every violation, or ErrSystemSourceUnavailable if the source of a system can't be read.
+//
+// This is a lint meant to be run from the tests of a game (see testutils.CheckDeterministicSystems), where the sources
+// are available; it is not run by the world. Calls through function values, methods and other packages are not
+// followed, see WithDeterminismAudit for a check that catches nondeterminism at runtime.
+func (w *World) LintSystems() error {
+	var errs []error
+	for _, sys := range w.SystemManager.registeredSystemTypes() {
+		if err := lintSystem(sys.Name, sys.Fn); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// lintSystem returns ErrNondeterministicSystem if the system, or a function of its package that it calls, uses an API
+// that is banned by LintSystems.
+func lintSystem(systemName string, fn System) error {
+	f := runtime.FuncForPC(reflect.ValueOf(fn).Pointer())
+	if f == nil {
+		return eris.Wrapf(ErrSystemSourceUnavailable, "system %s", systemName)
+	}
+	filename, line := f.FileLine(f.Entry())
+	pf, err := parseSourceFile(filename)
+	if err != nil {
+		return eris.Wrapf(ErrSystemSourceUnavailable, "system %s: %v", systemName, err)
+	}
+	body := findFuncBody(pf, line)
+	if body == nil {
+		return eris.Wrapf(ErrSystemSourceUnavailable, "system %s: no function starts at %s:%d", systemName,
+			filename, line)
+	}
+
+	l := &linter{funcs: packageFuncs(filename, pf), visited: map[*ast.BlockStmt]bool{}}
+	l.inspect(pf, body)
+	if len(l.violations) > 0 {
+		return eris.Wrapf(ErrNondeterministicSystem, "system %s:\n\t%s", systemName,
+			strings.Join(l.violations, "\n\t"))
+	}
+	return nil
+}
+
+func parseSourceFile(filename string) (*parsedFile, error) {
+	if pf, ok := parsedFiles.Load(filename); ok {
+		return pf.(*parsedFile), nil //nolint:errcheck // only *parsedFile is stored
+	}
+	src, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, eris.Wrap(err, "")
+	}
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, src, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, eris.Wrap(err, "")
+	}
+	pf := &parsedFile{fset: fset, file: file}
+	parsedFiles.Store(filename, pf)
+	return pf, nil
+}
+
+// packageFunc is a function declared at the top level of a package, with the file it is declared in.
+type packageFunc struct {
+	pf   *parsedFile
+	decl *ast.FuncDecl
+}
+
+// packageFuncs returns the functions that are declared in the package of the given file, by name. Files of the
+// directory that can't be parsed, or that belong to another package (e.g. an external test package), are skipped.
+func packageFuncs(filename string, pf *parsedFile) map[string]packageFunc {
+	funcs := map[string]packageFunc{}
+	files, _ := filepath.Glob(filepath.Join(filepath.Dir(filename), "*.go"))
+	for _, name := range files {
+		other, err := parseSourceFile(name)
+		if err != nil || other.file.Name.Name != pf.file.Name.Name {
+			continue
+		}
+		for _, decl := range other.file.Decls {
+			if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv == nil && fn.Body != nil {
+				funcs[fn.Name.Name] = packageFunc{pf: other, decl: fn}
+			}
+		}
+	}
+	return funcs
+}
+
+// findFuncBody returns the body of the innermost function declaration or literal that starts at the given line.
+func findFuncBody(pf *parsedFile, line int) *ast.BlockStmt {
+	var body *ast.BlockStmt
+	ast.Inspect(pf.file, func(n ast.Node) bool {
+		if n == nil {
+			return false
+		}
+		if pf.fset.Position(n.Pos()).Line > line || pf.fset.Position(n.End()).Line < line {
+			return false
+		}
+		switch fn := n.(type) {
+		case *ast.FuncDecl:
+			if fn.Body != nil && pf.fset.Position(fn.Pos()).Line == line {
+				body = fn.Body
+			}
+		case *ast.FuncLit:
+			if pf.fset.Position(fn.Pos()).Line == line {
+				body = fn.Body
+			}
+		}
+		return true
+	})
+	return body
+}
+
+// linter collects the violations of a system and of the functions of its package that it calls.
+type linter struct {
+	funcs      map[string]packageFunc
+	visited    map[*ast.BlockStmt]bool
+	violations []string
+}
+
+// inspect adds the violations of the function body, which is declared in the given file, and follows the calls to the
+// functions of the package.
+func (l *linter) inspect(pf *parsedFile, body *ast.BlockStmt) {
+	if l.visited[body] {
+		return
+	}
+	l.visited[body] = true
+	l.violations = append(l.violations, findViolations(pf, body)...)
+	ast.Inspect(body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		if ident, ok := call.Fun.(*ast.Ident); ok {
+			if fn, ok := l.funcs[ident.Name]; ok {
+				l.inspect(fn.pf, fn.decl.Body)
+			}
+		}
+		return true
+	})
+}
+
+func findViolations(pf *parsedFile, body *ast.BlockStmt) []string {
+	imports := map[string]string{}
+	for _, imp := range pf.file.Imports {
+		path, err := strconv.Unquote(imp.Path.Value)
+		if err != nil {
+			continue
+		}
+		name := path[strings.LastIndex(path, "/")+1:]
+		if path == "math/rand/v2" {
+			name = "rand"
+		}
+		if imp.Name != nil {
+			name = imp.Name.Name
+		}
+		imports[name] = path
+	}
+	maps := mapVariables(body)
+
+	var violations []string
+	report := func(n ast.Node, format string, args ...any) {
+		pos := pf.fset.Position(n.Pos())
+		violations = append(violations, fmt.Sprintf("%s:%d: ", pos.Filename, pos.Line)+fmt.Sprintf(format, args...))
+	}
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.GoStmt:
+			report(node, "goroutines must not be spawned from systems")
+		case *ast.CallExpr:
+			sel, ok := node.Fun.(*ast.SelectorExpr)
+			if !ok {
+				break
+			}
+			pkg, ok := sel.X.(*ast.Ident)
+			if !ok {
+				break
+			}
+			switch imports[pkg.Name] {
+			case "time":
+				if bannedTimeFuncs[sel.Sel.Name] {
+					report(node, "time.%s reads the wall clock, use engine.Context.Timestamp instead", sel.Sel.Name)
+				}
+			case "math/rand", "math/rand/v2":
+				if !allowedRandFuncs[sel.Sel.Name] {
+					report(node, "rand.%s uses the global source, use engine.Context.Rand instead", sel.Sel.Name)
+				}
+			}
+		case *ast.RangeStmt:
+			if isMapExpr(node.X, maps) {
+				report(node, "map iteration order is random, iterate over sorted keys instead")
+			}
+		}
+		return true
+	})
+	return violations
+}
+
+// mapVariables returns the names of the variables in the function body that are evidently maps, either because they
+// are declared with a map type or initialized with a map literal or make(map...).
+func mapVariables(body *ast.BlockStmt) map[string]bool {
+	maps := map[string]bool{}
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.ValueSpec:
+			for i, name := range node.Names {
+				if _, ok := node.Type.(*ast.MapType); ok {
+					maps[name.Name] = true
+				} else if i < len(node.Values) && isMapExpr(node.Values[i], nil) {
+					maps[name.Name] = true
+				}
+			}
+		case *ast.AssignStmt:
+			if len(node.Lhs) != len(node.Rhs) {
+				break
+			}
+			for i, lhs := range node.Lhs {
+				if ident, ok := lhs.(*ast.Ident); ok && isMapExpr(node.Rhs[i], maps) {
+					maps[ident.Name] = true
+				}
+			}
+		}
+		return true
+	})
+	return maps
+}
+
+func isMapExpr(expr ast.Expr, maps map[string]bool) bool {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		return maps[e.Name]
+	case *ast.ParenExpr:
+		return isMapExpr(e.X, maps)
+	case *ast.CompositeLit:
+		_, ok := e.Type.(*ast.MapType)
+		return ok
+	case *ast.CallExpr:
+		if ident, ok := e.Fun.(*ast.Ident); ok && ident.Name == "make" && len(e.Args) > 0 {
+			_, ok = e.Args[0].(*ast.MapType)
+			return ok
+		}
+		if sel, ok := e.Fun.(*ast.SelectorExpr); ok {
+			return engineMapMethods[sel.Sel.Name]
+		}
+	}
+	return false
+}
+
+// runStrict runs the system with a pprof label so that goroutines it spawns can be traced back to it. It returns
+// ErrNondeterministicSystem if the system calls Now or GlobalRand, which look for the frame of runStrict on the stack.
+//
+//go:noinline
+func runStrict(ctx context.Context, sys systemType, run func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			violation, ok := r.(strictViolation)
+			if !ok {
+				panic(r)
+			}
+			err = eris.Wrapf(ErrNondeterministicSystem, "system %s: %s", sys.Name, violation.reason)
+		}
+	}()
+	pprof.Do(ctx, pprof.Labels(systemLabel, sys.Name), func(context.Context) {
+		err = run()
+	})
+	return err
+}
+
+// checkGoroutineLeaks fails if a goroutine spawned by a system is still running after all systems have returned.
+// Goroutines that are about to exit are given a short grace period to avoid false positives.
+func checkGoroutineLeaks(systems []systemType) error {
+	var leaks map[string]int
+	var err error
+	for i := 0; i < leakCheckAttempts; i++ {
+		leaks, err = leakedGoroutines(systems)
+		if err != nil || len(leaks) == 0 {
+			return err
+		}
+		time.Sleep(leakCheckInterval)
+	}
+	names := make([]string, 0, len(leaks))
+	for name := range leaks {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for i, name := range names {
+		names[i] = fmt.Sprintf("%s (%d goroutines)", name, leaks[name])
+	}
+	return eris.Wrapf(ErrNondeterministicSystem, "goroutines outlived the tick: %s", strings.Join(names, ", "))
+}
+
+// leakedGoroutines returns the number of live goroutines that were spawned by each of the given systems.
+func leakedGoroutines(systems []systemType) (map[string]int, error) {
+	var buf bytes.Buffer
+	if err := pprof.Lookup("goroutine").WriteTo(&buf, 1); err != nil {
+		return nil, eris.Wrap(err, "failed to read goroutine profile")
+	}
+
+	// With debug=1, each group of identical goroutines starts with "<count> @ <pcs>" and is followed by an optional
+	// "# labels: {...}" line.
+	leaks := map[string]int{}
+	count := 0
+	labelPrefix := strconv.Quote(systemLabel) + ":"
+	scanner := bufio.NewScanner(&buf)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), 1<<20) //nolint:gomnd // stack lines can be long
+	for scanner.Scan() {
+		text := scanner.Text()
+		if n, _, ok := strings.Cut(text, " @ "); ok {
+			count, _ = strconv.Atoi(n)
+			continue
+		}
+		labels, ok := strings.CutPrefix(text, "# labels: ")
+		if !ok {
+			continue
+		}
+		_, rest, ok := strings.Cut(labels, labelPrefix)
+		if !ok {
+			continue
+		}
+		name, err := strconv.QuotedPrefix(rest)
+		if err != nil {
+			continue
+		}
+		name, _ = strconv.Unquote(name)
+		if slices.ContainsFunc(systems, func(s systemType) bool { return s.Name == name }) {
+			leaks[name] += count
+		}
+	}
+	return leaks, eris.Wrap(scanner.Err(), "")
+}
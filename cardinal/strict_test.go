@@ -0,0 +1,182 @@
+package cardinal
+
+import (
+	"context"
+	"math/rand"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+	"pkg.world.dev/world-engine/cardinal/worldstage"
+)
+
+func newStrictWorld(t *testing.T) *World {
+	miniRedis := miniredis.RunT(t)
+	t.Setenv("REDIS_ADDRESS", miniRedis.Addr())
+	world, err := NewWorld(WithStrictMode(), WithTickChannel(make(chan time.Time)), WithPort(getOpenPort(t)))
+	assert.NilError(t, err)
+	return world
+}
+
+func startStrictWorld(t *testing.T, world *World) {
+	go func() {
+		assert.NilError(t, world.StartGame())
+	}()
+	<-world.worldStage.NotifyOnStage(worldstage.Running)
+	t.Cleanup(func() {
+		assert.NilError(t, world.Shutdown(context.Background()))
+	})
+}
+
+func wallClockSystem(wCtx engine.Context) error {
+	wCtx.Logger().Info().Msgf("now: %v", time.Now())
+	return nil
+}
+
+func globalRandSystem(wCtx engine.Context) error {
+	wCtx.Logger().Info().Msgf("roll: %d", rand.Intn(6)) //nolint:gosec // testing the strict mode check
+	return nil
+}
+
+func mapRangeSystem(wCtx engine.Context) error {
+	counts := map[string]int{"a": 1}
+	for name := range counts {
+		wCtx.Logger().Info().Msg(name)
+	}
+	return nil
+}
+
+func goroutineSystem(engine.Context) error {
+	go func() {}()
+	return nil
+}
+
+func deterministicSystem(wCtx engine.Context) error {
+	for _, name := range sortedKeys(map[string]int{"a": 1, "b": 2}) {
+		wCtx.Logger().Info().Msg(name)
+	}
+	_ = time.Unix(int64(wCtx.Timestamp()), 0)
+	_ = wCtx.Rand().IntN(6) //nolint:gomnd // dice roll
+	return nil
+}
+
+func sortedKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func TestLintSystemsRejectsNondeterministicSystems(t *testing.T) {
+	for _, sys := range []System{wallClockSystem, globalRandSystem, mapRangeSystem, goroutineSystem} {
+		world := newStrictWorld(t)
+		assert.NilError(t, RegisterSystems(world, sys))
+		assert.ErrorIs(t, world.LintSystems(), ErrNondeterministicSystem)
+	}
+
+	world := newStrictWorld(t)
+	assert.NilError(t, RegisterSystems(world, deterministicSystem))
+	assert.NilError(t, world.LintSystems())
+}
+
+// rollDice is called from a system, so the lint must follow the call to find the global math/rand source.
+func rollDice() int {
+	return rand.Intn(6) //nolint:gosec // testing the lint
+}
+
+func TestLintSystemsFollowsCallsToFunctionsOfThePackage(t *testing.T) {
+	world := newStrictWorld(t)
+	assert.NilError(t, RegisterSystems(world, func(wCtx engine.Context) error {
+		wCtx.Logger().Info().Msgf("roll: %d", rollDice())
+		return nil
+	}))
+	err := world.LintSystems()
+	assert.ErrorIs(t, err, ErrNondeterministicSystem)
+	assert.ErrorContains(t, err, "rand.Intn uses the global source")
+}
+
+// startWorker is called from a system, so that the goroutine is spawned in another function than the system.
+func startWorker(stop <-chan struct{}) {
+	go func() {
+		<-stop
+	}()
+}
+
+func TestStrictModeFailsTickWhenGoroutineOutlivesIt(t *testing.T) {
+	world := newStrictWorld(t)
+	stop := make(chan struct{})
+	defer close(stop)
+	assert.NilError(t, RegisterSystems(world, func(engine.Context) error {
+		startWorker(stop)
+		return nil
+	}))
+	startStrictWorld(t, world)
+
+	err := world.doTick(context.Background(), uint64(time.Now().Unix()))
+	assert.ErrorIs(t, err, ErrNondeterministicSystem)
+}
+
+func TestStrictModeAllowsGoroutinesThatFinishWithinTheTick(t *testing.T) {
+	world := newStrictWorld(t)
+	assert.NilError(t, RegisterSystems(world, func(engine.Context) error {
+		stop := make(chan struct{})
+		startWorker(stop)
+		close(stop)
+		return nil
+	}))
+	startStrictWorld(t, world)
+
+	assert.NilError(t, world.doTick(context.Background(), uint64(time.Now().Unix())))
+}
+
+func TestStrictModeFailsTickWhenSystemCallsTheShims(t *testing.T) {
+	testCases := []struct {
+		name   string
+		system System
+	}{
+		{name: "wall clock", system: func(wCtx engine.Context) error {
+			wCtx.Logger().Info().Msgf("now: %v", Now())
+			return nil
+		}},
+		{name: "global rand", system: func(wCtx engine.Context) error {
+			wCtx.Logger().Info().Msgf("roll: %d", GlobalRand().IntN(6)) //nolint:gomnd // dice roll
+			return nil
+		}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			world := newStrictWorld(t)
+			assert.NilError(t, RegisterSystems(world, tc.system))
+			startStrictWorld(t, world)
+
+			err := world.doTick(context.Background(), uint64(time.Now().Unix()))
+			assert.ErrorIs(t, err, ErrNondeterministicSystem)
+		})
+	}
+}
+
+func TestShimsCanBeCalledOutsideOfStrictSystems(t *testing.T) {
+	fixed := time.Unix(1700000000, 0)
+	defer func(clock func() time.Time) { WallClock = clock }(WallClock)
+	WallClock = func() time.Time { return fixed }
+	assert.Equal(t, fixed, Now())
+	_ = GlobalRand().Uint64()
+
+	// The shims only fail the tick in strict mode
+	miniRedis := miniredis.RunT(t)
+	t.Setenv("REDIS_ADDRESS", miniRedis.Addr())
+	world, err := NewWorld(WithTickChannel(make(chan time.Time)), WithPort(getOpenPort(t)))
+	assert.NilError(t, err)
+	assert.NilError(t, RegisterSystems(world, func(engine.Context) error {
+		_ = Now()
+		return nil
+	}))
+	startStrictWorld(t, world)
+	assert.NilError(t, world.doTick(context.Background(), uint64(time.Now().Unix())))
+}
@@ -1,15 +1,22 @@
 package cardinal
 
 import (
+	"context"
 	"path/filepath"
 	"reflect"
 	"runtime"
 	"slices"
+	"sync"
 	"time"
 
 	"github.com/rotisserie/eris"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 
+	ecslog "pkg.world.dev/world-engine/cardinal/log"
 	"pkg.world.dev/world-engine/cardinal/statsd"
+	"pkg.world.dev/world-engine/cardinal/tracing"
+	"pkg.world.dev/world-engine/cardinal/types"
 	"pkg.world.dev/world-engine/cardinal/types/engine"
 )
 
@@ -24,8 +31,9 @@ type System func(ctx engine.Context) error
 
 // systemType is an internal entry used to track registered systems.
 type systemType struct {
-	Name string
-	Fn   System
+	Name     string
+	Fn       System
+	Schedule SystemSchedule
 }
 
 type SystemManager interface {
@@ -36,10 +44,25 @@ type SystemManager interface {
 	// If no system is currently running, it returns an empty string.
 	GetCurrentSystem() string
 
+	// GetDisabledSystems returns the names of the systems that are disabled.
+	GetDisabledSystems() []string
+
+	// GetSystemSchedule returns the registered systems in the order in which they run, with their schedules.
+	GetSystemSchedule() []types.SystemInfo
+
 	// These methods are intentionally made private to avoid other
 	// packages from trying to modify the system manager in the middle of a tick.
-	registerSystems(isInit bool, systems ...System) error
-	runSystems(wCtx engine.Context) error
+	registerSystems(isInit bool, schedule SystemSchedule, systems ...System) error
+	runSystems(ctx context.Context, wCtx engine.Context) error
+	registeredSystemTypes() []systemType
+	setStrictMode(enabled bool)
+	setSystemBudget(budget SystemBudget)
+	setAfterSystem(fn func(system string) error)
+	setBreakHook(fn func(system string, after bool))
+	setGenesis(fn System)
+	recordSearch(archetypes, entities int)
+	setSystemEnabled(name string, enabled bool) error
+	replaceSystems(replacements map[string]System) error
 }
 
 type systemManager struct {
@@ -50,6 +73,28 @@ type systemManager struct {
 
 	// currentSystem is the name of the system that is currently running.
 	currentSystem string
+
+	// strictMode enables the runtime determinism checks on systems. See WithStrictMode.
+	strictMode bool
+
+	// disabledSystems are skipped by runSystems. They can be changed while the game loop is running (e.g. through the
+	// admin service), so they are guarded by disabledMu.
+	disabledSystems map[string]bool
+	disabledMu      sync.RWMutex
+
+	// watchdog reports systems that exceed their budget. It is nil unless WithSystemBudget is used.
+	watchdog *systemWatchdog
+
+	// afterSystem is called after each system that ran successfully. It is nil unless WithDeterminismAudit is used.
+	afterSystem func(system string) error
+
+	// breakHook is called before and after each system. It blocks while the tick is stopped at a breakpoint. It is nil
+	// unless WithStepDebugger is used.
+	breakHook func(system string, after bool)
+
+	// genesis creates the entities of the genesis file at tick 0, before the init systems run. It is nil unless a
+	// genesis file is set.
+	genesis System
 }
 
 func newSystemManager() SystemManager {
@@ -57,15 +102,17 @@ func newSystemManager() SystemManager {
 		registeredSystems:     make([]systemType, 0),
 		registeredInitSystems: make([]systemType, 0),
 		currentSystem:         noActiveSystemName,
+		disabledSystems:       map[string]bool{},
 	}
 	return sm
 }
 
 // RegisterSystems registers multiple systems with the system manager.
 // There can only be one system with a given name, which is derived from the function name.
-// If isInit is true, the system will only be executed once at tick 0.
+// If isInit is true, the system will only be executed once at tick 0. Otherwise, it is executed at the ticks of the
+// schedule.
 // If there is a duplicate system name, an error will be returned and none of the systems will be registered.
-func (m *systemManager) registerSystems(isInit bool, systemFuncs ...System) error {
+func (m *systemManager) registerSystems(isInit bool, schedule SystemSchedule, systemFuncs ...System) error {
 	// We create a list of systemType structs to register, and then register them in one go to ensure all or nothing.
 	systemToRegister := make([]systemType, 0, len(systemFuncs))
 
@@ -93,7 +140,7 @@ func (m *systemManager) registerSystems(isInit bool, systemFuncs ...System) erro
 			return eris.Errorf("System %q is already registered", systemName)
 		}
 
-		systemToRegister = append(systemToRegister, systemType{Name: systemName, Fn: systemFunc})
+		systemToRegister = append(systemToRegister, systemType{Name: systemName, Fn: systemFunc, Schedule: schedule})
 	}
 
 	if isInit {
@@ -106,13 +153,23 @@ func (m *systemManager) registerSystems(isInit bool, systemFuncs ...System) erro
 }
 
 // RunSystems runs all the registered system in the order that they were registered.
-func (m *systemManager) runSystems(wCtx engine.Context) error {
+func (m *systemManager) runSystems(ctx context.Context, wCtx engine.Context) error {
 	var systemsToRun []systemType
 	if wCtx.CurrentTick() == 0 {
 		systemsToRun = slices.Concat(m.registeredInitSystems, m.registeredSystems)
+		if m.genesis != nil {
+			systemsToRun = slices.Concat([]systemType{{Name: genesisSystemName, Fn: m.genesis}}, systemsToRun)
+		}
 	} else {
 		systemsToRun = m.registeredSystems
 	}
+	systemsToRun = m.scheduledAt(wCtx.CurrentTick(), m.withoutDisabled(systemsToRun))
+
+	// Every log line of the systems is tagged with the tick. The logger of the context is restored afterward, so that
+	// the tags don't pile up when the context is reused.
+	logger := *wCtx.Logger()
+	defer wCtx.SetLogger(logger)
+	tickLogger := ecslog.CreateTickLogger(&logger, wCtx.CurrentTick())
 
 	allSystemStartTime := time.Now()
 	for _, sys := range systemsToRun {
@@ -120,11 +177,24 @@ func (m *systemManager) runSystems(wCtx engine.Context) error {
 		m.currentSystem = sys.Name
 
 		// Inject the system name into the logger
-		wCtx.SetLogger(wCtx.Logger().With().Str("system", sys.Name).Logger())
+		wCtx.SetLogger(*ecslog.CreateSystemLogger(tickLogger, sys.Name))
+
+		if m.breakHook != nil {
+			m.breakHook(sys.Name, false)
+		}
 
 		// Executes the system function that the user registered
 		systemStartTime := time.Now()
-		err := sys.Fn(wCtx)
+		_, span := tracing.Tracer().Start(ctx, "cardinal.system",
+			trace.WithAttributes(attribute.String("system", sys.Name)),
+		)
+		var err error
+		if m.strictMode {
+			err = runStrict(context.Background(), sys, func() error { return sys.Fn(wCtx) })
+		} else {
+			err = sys.Fn(wCtx)
+		}
+		tracing.End(span, err)
 		if err != nil {
 			m.currentSystem = ""
 			return eris.Wrapf(err, "System %s generated an error", sys.Name)
@@ -132,11 +202,32 @@ func (m *systemManager) runSystems(wCtx engine.Context) error {
 
 		// Emit the total time it took to run `systemName`
 		statsd.EmitTickStat(systemStartTime, sys.Name)
+		if m.watchdog != nil {
+			if err := m.watchdog.observe(wCtx, sys.Name, time.Since(systemStartTime)); err != nil {
+				m.currentSystem = ""
+				return err
+			}
+		}
+		if m.afterSystem != nil {
+			if err := m.afterSystem(sys.Name); err != nil {
+				m.currentSystem = ""
+				return err
+			}
+		}
+		if m.breakHook != nil {
+			m.breakHook(sys.Name, true)
+		}
 	}
 
 	// Indicate that no system is currently running
 	m.currentSystem = noActiveSystemName
 
+	if m.strictMode {
+		if err := checkGoroutineLeaks(systemsToRun); err != nil {
+			return err
+		}
+	}
+
 	// Emit the total time it took to run all systems
 	statsd.EmitTickStat(allSystemStartTime, "all_systems")
 
@@ -152,6 +243,123 @@ func (m *systemManager) GetRegisteredSystems() []string {
 	return sysNames
 }
 
+func (m *systemManager) GetSystemSchedule() []types.SystemInfo {
+	m.disabledMu.RLock()
+	defer m.disabledMu.RUnlock()
+	infos := make([]types.SystemInfo, 0, len(m.registeredInitSystems)+len(m.registeredSystems))
+	for _, sys := range m.registeredInitSystems {
+		infos = append(infos, types.SystemInfo{Name: sys.Name, Init: true, Every: 1, Disabled: m.disabledSystems[sys.Name]})
+	}
+	for _, sys := range m.registeredSystems {
+		infos = append(infos, types.SystemInfo{
+			Name:     sys.Name,
+			Every:    sys.Schedule.Every,
+			Offset:   sys.Schedule.Offset,
+			Disabled: m.disabledSystems[sys.Name],
+		})
+	}
+	return infos
+}
+
 func (m *systemManager) GetCurrentSystem() string {
 	return m.currentSystem
 }
+
+// registeredSystemTypes returns the registered init systems and systems.
+func (m *systemManager) registeredSystemTypes() []systemType {
+	return slices.Concat(m.registeredInitSystems, m.registeredSystems)
+}
+
+func (m *systemManager) setStrictMode(enabled bool) {
+	m.strictMode = enabled
+}
+
+func (m *systemManager) setSystemBudget(budget SystemBudget) {
+	m.watchdog = newSystemWatchdog(budget)
+}
+
+func (m *systemManager) setAfterSystem(fn func(system string) error) {
+	m.afterSystem = fn
+}
+
+func (m *systemManager) setBreakHook(fn func(system string, after bool)) {
+	m.breakHook = fn
+}
+
+func (m *systemManager) setGenesis(fn System) {
+	m.genesis = fn
+}
+
+// recordSearch adds a search to the statistics that are reported when the running system exceeds its budget. Searches
+// that are evaluated outside of a system, e.g. by queries, are ignored.
+func (m *systemManager) recordSearch(archetypes, entities int) {
+	if m.watchdog == nil || m.currentSystem == noActiveSystemName {
+		return
+	}
+	m.watchdog.recordSearch(archetypes, entities)
+}
+
+func (m *systemManager) GetDisabledSystems() []string {
+	m.disabledMu.RLock()
+	defer m.disabledMu.RUnlock()
+	names := make([]string, 0, len(m.disabledSystems))
+	for name := range m.disabledSystems {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+func (m *systemManager) setSystemEnabled(name string, enabled bool) error {
+	if !slices.Contains(m.GetRegisteredSystems(), name) {
+		return eris.Wrapf(ErrSystemNotFound, "system %q", name)
+	}
+	m.disabledMu.Lock()
+	defer m.disabledMu.Unlock()
+	if enabled {
+		delete(m.disabledSystems, name)
+	} else {
+		m.disabledSystems[name] = true
+	}
+	return nil
+}
+
+// replaceSystems swaps the functions of registered systems, keeping their names, order and enabled state. Either all
+// systems are replaced, or none are if any of them is not registered.
+func (m *systemManager) replaceSystems(replacements map[string]System) error {
+	for name, fn := range replacements {
+		if fn == nil {
+			return eris.Errorf("replacement of system %q must not be nil", name)
+		}
+		if !slices.Contains(m.GetRegisteredSystems(), name) {
+			return eris.Wrapf(ErrSystemNotFound, "system %q", name)
+		}
+	}
+	for _, systems := range [][]systemType{m.registeredSystems, m.registeredInitSystems} {
+		for i := range systems {
+			if fn, ok := replacements[systems[i].Name]; ok {
+				systems[i].Fn = fn
+			}
+		}
+	}
+	return nil
+}
+
+// withoutDisabled returns the given systems without the ones that are disabled.
+func (m *systemManager) withoutDisabled(systems []systemType) []systemType {
+	m.disabledMu.RLock()
+	defer m.disabledMu.RUnlock()
+	if len(m.disabledSystems) == 0 {
+		return systems
+	}
+	return slices.DeleteFunc(slices.Clone(systems), func(s systemType) bool { return m.disabledSystems[s.Name] })
+}
+
+// scheduledAt returns the given systems without the ones that are not scheduled to run at the given tick. Init systems
+// always run.
+func (m *systemManager) scheduledAt(tick uint64, systems []systemType) []systemType {
+	if !slices.ContainsFunc(systems, func(s systemType) bool { return !s.Schedule.runsAt(tick) }) {
+		return systems
+	}
+	return slices.DeleteFunc(slices.Clone(systems), func(s systemType) bool { return !s.Schedule.runsAt(tick) })
+}
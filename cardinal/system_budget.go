@@ -0,0 +1,107 @@
+package cardinal
+
+import (
+	"time"
+
+	"pkg.world.dev/world-engine/cardinal/statsd"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+// SlowSystemEvent is emitted when a system exceeds its budget for SystemBudget.Ticks consecutive ticks, if
+// SystemBudget.EmitEvent is set.
+const SlowSystemEvent = "slow-system"
+
+// SystemBudget configures the watchdog that detects systems that are too slow for the tick rate of the world. See
+// WithSystemBudget.
+type SystemBudget struct {
+	// Default is the time that each system may take per tick. Zero means that only the systems in PerSystem are checked.
+	Default time.Duration
+	// PerSystem overrides Default for the systems with the given names.
+	PerSystem map[string]time.Duration
+	// Ticks is the number of consecutive ticks in which a system must exceed its budget before an alert is raised, so
+	// that a single slow tick, e.g. caused by a GC pause, is not reported. Values below 1 mean 1.
+	Ticks int
+	// EmitEvent also emits a SlowSystemEvent to websocket subscribers when an alert is raised.
+	EmitEvent bool
+}
+
+// budget returns the budget of the given system, or zero if the system is not checked.
+func (b SystemBudget) budget(system string) time.Duration {
+	if d, ok := b.PerSystem[system]; ok {
+		return d
+	}
+	return b.Default
+}
+
+// searchStats counts the searches that the running system evaluated. They are reported with a slow system alert,
+// since searches that match many archetypes or entities are the usual cause of a slow system.
+type searchStats struct {
+	searches   int
+	archetypes int
+	entities   int
+}
+
+// systemWatchdog raises an alert when a system exceeds its budget for several consecutive ticks.
+type systemWatchdog struct {
+	budget SystemBudget
+	// overBudget is the number of consecutive ticks in which each system exceeded its budget.
+	overBudget map[string]int
+	stats      searchStats
+}
+
+func newSystemWatchdog(budget SystemBudget) *systemWatchdog {
+	if budget.Ticks < 1 {
+		budget.Ticks = 1
+	}
+	return &systemWatchdog{
+		budget:     budget,
+		overBudget: map[string]int{},
+	}
+}
+
+// recordSearch adds a search to the statistics of the running system.
+func (w *systemWatchdog) recordSearch(archetypes, entities int) {
+	w.stats.searches++
+	w.stats.archetypes += archetypes
+	w.stats.entities += entities
+}
+
+// observe checks the duration of a system that just ran and resets the search statistics for the next system. While a
+// system stays over its budget, an alert is raised every SystemBudget.Ticks ticks.
+func (w *systemWatchdog) observe(wCtx engine.Context, system string, duration time.Duration) error {
+	stats := w.stats
+	w.stats = searchStats{}
+
+	budget := w.budget.budget(system)
+	if budget <= 0 || duration <= budget {
+		delete(w.overBudget, system)
+		return nil
+	}
+	w.overBudget[system]++
+	ticks := w.overBudget[system]
+	if ticks%w.budget.Ticks != 0 {
+		return nil
+	}
+
+	wCtx.Logger().Warn().
+		Uint64("tick", wCtx.CurrentTick()).
+		Dur("duration", duration).Dur("budget", budget).Int("consecutive_ticks", ticks).
+		Int("searches", stats.searches).Int("archetypes", stats.archetypes).Int("entities", stats.entities).
+		Msg("system exceeded its tick budget")
+	if err := statsd.Client().Incr("slow_system", []string{"system:" + system}, 1); err != nil {
+		wCtx.Logger().Warn().Err(err).Msg("failed to emit slow system stat")
+	}
+	if !w.budget.EmitEvent {
+		return nil
+	}
+	return wCtx.EmitEvent(map[string]any{
+		"event":            SlowSystemEvent,
+		"system":           system,
+		"durationMs":       duration.Milliseconds(),
+		"budgetMs":         budget.Milliseconds(),
+		"consecutiveTicks": ticks,
+		"searches":         stats.searches,
+		"archetypes":       stats.archetypes,
+		"entities":         stats.entities,
+	})
+}
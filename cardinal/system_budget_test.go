@@ -0,0 +1,59 @@
+package cardinal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/rs/zerolog"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal/types/engine/mocks"
+)
+
+func TestSystemWatchdogAlertsWhenSystemStaysOverBudget(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	wCtx := mocks.NewMockContext(ctrl)
+	logger := zerolog.Nop()
+	wCtx.EXPECT().Logger().Return(&logger).AnyTimes()
+	wCtx.EXPECT().CurrentTick().Return(uint64(7)).AnyTimes()
+	var events []map[string]any
+	wCtx.EXPECT().EmitEvent(gomock.Any()).DoAndReturn(func(event map[string]any) error {
+		events = append(events, event)
+		return nil
+	}).AnyTimes()
+
+	w := newSystemWatchdog(SystemBudget{
+		Default:   10 * time.Millisecond,
+		PerSystem: map[string]time.Duration{"cardinal.Pathfinding": time.Second},
+		Ticks:     2,
+		EmitEvent: true,
+	})
+	slow := 20 * time.Millisecond
+
+	// A single slow tick is not reported, and a fast tick resets the count.
+	assert.NilError(t, w.observe(wCtx, "cardinal.Movement", slow))
+	assert.NilError(t, w.observe(wCtx, "cardinal.Movement", time.Millisecond))
+	assert.NilError(t, w.observe(wCtx, "cardinal.Movement", slow))
+	assert.Equal(t, 0, len(events))
+
+	// The searches of the system are reported with the alert.
+	w.recordSearch(3, 1000)
+	w.recordSearch(1, 10)
+	assert.NilError(t, w.observe(wCtx, "cardinal.Movement", slow))
+	assert.DeepEqual(t, []map[string]any{{
+		"event":            SlowSystemEvent,
+		"system":           "cardinal.Movement",
+		"durationMs":       int64(20),
+		"budgetMs":         int64(10),
+		"consecutiveTicks": 2,
+		"searches":         2,
+		"archetypes":       4,
+		"entities":         1010,
+	}}, events)
+
+	// Systems with their own budget are checked against it.
+	assert.NilError(t, w.observe(wCtx, "cardinal.Pathfinding", slow))
+	assert.NilError(t, w.observe(wCtx, "cardinal.Pathfinding", slow))
+	assert.Equal(t, 1, len(events))
+}
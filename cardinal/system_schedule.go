@@ -0,0 +1,58 @@
+package cardinal
+
+import (
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal/worldstage"
+)
+
+// SystemSchedule spreads an expensive system, e.g. pathfinding or a persistence flush, across ticks: the system runs
+// every Every ticks, at the ticks whose remainder when divided by Every is Offset. Systems with the same Every and
+// different Offsets never run in the same tick. The zero value runs the system at every tick.
+type SystemSchedule struct {
+	Every  uint64
+	Offset uint64
+}
+
+// everyTick is the schedule of systems that run at every tick.
+var everyTick = SystemSchedule{Every: 1}
+
+// validate checks the schedule and returns it with Every set to 1 if it is zero.
+func (s SystemSchedule) validate() (SystemSchedule, error) {
+	if s.Every == 0 {
+		s.Every = 1
+	}
+	if s.Offset >= s.Every {
+		return s, eris.Errorf("offset %d of system schedule must be less than every %d", s.Offset, s.Every)
+	}
+	return s, nil
+}
+
+// runsAt reports whether a system with the schedule runs at the given tick.
+func (s SystemSchedule) runsAt(tick uint64) bool {
+	return s.Every <= 1 || tick%s.Every == s.Offset
+}
+
+// RegisterScheduledSystems registers systems that run on the given schedule rather than at every tick. Like the systems
+// registered with RegisterSystems, they run in the order in which they were registered, in the ticks that they are
+// scheduled for.
+//
+// Usage:
+//
+//	// PathfindingSystem runs at ticks 0, 4, 8, ... and FlushSystem at ticks 2, 6, 10, ...
+//	err := cardinal.RegisterScheduledSystems(w, cardinal.SystemSchedule{Every: 4}, PathfindingSystem)
+//	err = cardinal.RegisterScheduledSystems(w, cardinal.SystemSchedule{Every: 4, Offset: 2}, FlushSystem)
+func RegisterScheduledSystems(w *World, schedule SystemSchedule, sys ...System) error {
+	if w.worldStage.Current() != worldstage.Init {
+		return eris.Errorf(
+			"world state is %s, expected %s to register systems",
+			w.worldStage.Current(),
+			worldstage.Init,
+		)
+	}
+	schedule, err := schedule.validate()
+	if err != nil {
+		return err
+	}
+	return w.SystemManager.registerSystems(false, schedule, sys...)
+}
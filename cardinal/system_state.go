@@ -0,0 +1,99 @@
+package cardinal
+
+import (
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal/codec"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+// systemStateNamespacePrefix is the prefix of the raw storage namespaces of system states.
+const systemStateNamespacePrefix = "cardinal-system-"
+
+// systemStateKey is the raw storage key that the value of a system state is stored at.
+const systemStateKey = "state"
+
+// SystemState is a typed value that is private to a system, for bookkeeping that does not belong in the ECS, e.g. the
+// cursor of a spawner or the cache of an AI planner. It is kept in raw storage, so changes are committed with the rest
+// of the tick and the state is restored when the world is restarted on the same game state.
+type SystemState[T any] struct {
+	name string
+}
+
+// NewSystemState returns the state with the given name, which is usually declared next to the system that uses it.
+// States with the same name share their value. The name must be alphanumeric (hyphens are allowed).
+//
+// Usage:
+//
+//	var spawnerCursor = cardinal.NewSystemState[SpawnerCursor]("spawner")
+//
+//	func SpawnerSystem(wCtx engine.Context) error {
+//		cursor, err := spawnerCursor.Get(wCtx)
+//		...
+//		return spawnerCursor.Set(wCtx, cursor)
+//	}
+func NewSystemState[T any](name string) *SystemState[T] {
+	return &SystemState[T]{name: name}
+}
+
+// Name returns the name of the state.
+func (s *SystemState[T]) Name() string {
+	return s.name
+}
+
+// Get returns the value of the state. If the state has not been set, ok will be false and the zero value is returned.
+func (s *SystemState[T]) Get(wCtx engine.Context) (value T, ok bool, err error) {
+	store, err := s.store(wCtx)
+	if err != nil {
+		return value, false, err
+	}
+	bz, ok, err := store.Get(systemStateKey)
+	if err != nil || !ok {
+		return value, false, err
+	}
+	value, err = codec.Decode[T](bz)
+	if err != nil {
+		return value, false, eris.Wrapf(err, "failed to decode system state %q", s.name)
+	}
+	return value, true, nil
+}
+
+// Set sets the value of the state. Like the other raw storage writes, it counts towards the raw storage quota.
+func (s *SystemState[T]) Set(wCtx engine.Context, value T) error {
+	store, err := s.store(wCtx)
+	if err != nil {
+		return err
+	}
+	bz, err := codec.Encode(value)
+	if err != nil {
+		return eris.Wrapf(err, "failed to encode system state %q", s.name)
+	}
+	return store.Set(systemStateKey, bz)
+}
+
+// Update sets the state to the value returned by fn, which is called with the current value of the state, or the zero
+// value if it has not been set.
+func (s *SystemState[T]) Update(wCtx engine.Context, fn func(T) T) error {
+	value, _, err := s.Get(wCtx)
+	if err != nil {
+		return err
+	}
+	return s.Set(wCtx, fn(value))
+}
+
+// Clear removes the value of the state.
+func (s *SystemState[T]) Clear(wCtx engine.Context) error {
+	store, err := s.store(wCtx)
+	if err != nil {
+		return err
+	}
+	return store.Delete(systemStateKey)
+}
+
+func (s *SystemState[T]) store(wCtx engine.Context) (*RawStorage, error) {
+	store, err := NewRawStorage(wCtx, systemStateNamespacePrefix+s.name)
+	if err != nil {
+		return nil, eris.Wrapf(err, "invalid system state name %q", s.name)
+	}
+	return store, nil
+}
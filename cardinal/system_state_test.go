@@ -0,0 +1,64 @@
+package cardinal_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/testutils"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+type SpawnerCursor struct {
+	Next   int
+	Queued []string
+}
+
+var spawnerCursor = cardinal.NewSystemState[SpawnerCursor]("spawner")
+
+func spawnerSystem(wCtx engine.Context) error {
+	return spawnerCursor.Update(wCtx, func(cursor SpawnerCursor) SpawnerCursor {
+		cursor.Next++
+		cursor.Queued = append(cursor.Queued, "orc")
+		return cursor
+	})
+}
+
+func TestSystemStateIsRestoredWithTheWorld(t *testing.T) {
+	tf1 := testutils.NewTestFixture(t, nil)
+	assert.NilError(t, cardinal.RegisterSystems(tf1.World, spawnerSystem))
+	tf1.StartWorld()
+	readOnly := cardinal.NewReadOnlyWorldContext(tf1.World)
+	_, ok, err := spawnerCursor.Get(readOnly)
+	assert.NilError(t, err)
+	assert.Check(t, !ok)
+	tf1.DoTick()
+	tf1.DoTick()
+
+	// The state is restored when the world is restarted, and the system continues from it.
+	tf1.Shutdown()
+	tf2 := testutils.NewTestFixture(t, tf1.Redis)
+	assert.NilError(t, cardinal.RegisterSystems(tf2.World, spawnerSystem))
+	tf2.StartWorld()
+	readOnly = cardinal.NewReadOnlyWorldContext(tf2.World)
+	cursor, ok, err := spawnerCursor.Get(readOnly)
+	assert.NilError(t, err)
+	assert.Check(t, ok)
+	assert.DeepEqual(t, SpawnerCursor{Next: 2, Queued: []string{"orc", "orc"}}, cursor)
+	tf2.DoTick()
+	cursor, _, err = spawnerCursor.Get(readOnly)
+	assert.NilError(t, err)
+	assert.Equal(t, 3, cursor.Next)
+
+	assert.Check(t, errors.Is(spawnerCursor.Set(readOnly, SpawnerCursor{}), cardinal.ErrEntityMutationOnReadOnly))
+	wCtx := cardinal.NewWorldContext(tf2.World)
+	assert.NilError(t, spawnerCursor.Clear(wCtx))
+	_, ok, err = spawnerCursor.Get(wCtx)
+	assert.NilError(t, err)
+	assert.Check(t, !ok)
+
+	err = cardinal.NewSystemState[int]("not a name").Set(wCtx, 1)
+	assert.Check(t, err != nil && strings.Contains(err.Error(), "invalid system state name"))
+}
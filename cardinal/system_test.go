@@ -104,3 +104,70 @@ func TestInitSystemRunsOnce(t *testing.T) {
 	assert.Equal(t, count, 1)
 	assert.Equal(t, count2, 2)
 }
+
+func TestScheduledSystemsRunAtTheirTicks(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil)
+	w := tf.World
+	var pathfinding, flush, movement []uint64
+	pathfindingSystem := func(wCtx engine.Context) error {
+		pathfinding = append(pathfinding, wCtx.CurrentTick())
+		return nil
+	}
+	flushSystem := func(wCtx engine.Context) error {
+		flush = append(flush, wCtx.CurrentTick())
+		return nil
+	}
+	movementSystem := func(wCtx engine.Context) error {
+		movement = append(movement, wCtx.CurrentTick())
+		return nil
+	}
+	assert.NilError(t, cardinal.RegisterScheduledSystems(w, cardinal.SystemSchedule{Every: 3}, pathfindingSystem))
+	assert.NilError(t, cardinal.RegisterScheduledSystems(w, cardinal.SystemSchedule{Every: 3, Offset: 2}, flushSystem))
+	assert.NilError(t, cardinal.RegisterSystems(w, movementSystem))
+	err := cardinal.RegisterScheduledSystems(w, cardinal.SystemSchedule{Every: 3, Offset: 3}, HealthSystem)
+	assert.ErrorContains(t, err, "must be less than")
+
+	for i := 0; i < 7; i++ {
+		tf.DoTick()
+	}
+	assert.DeepEqual(t, []uint64{0, 3, 6}, pathfinding)
+	assert.DeepEqual(t, []uint64{2, 5}, flush)
+	assert.DeepEqual(t, []uint64{0, 1, 2, 3, 4, 5, 6}, movement)
+
+	// The schedule lists the systems in the order in which they run, after the systems of the built-in plugins.
+	schedule := w.GetSystemSchedule()
+	n := len(schedule)
+	assert.Equal(t, types.SystemInfo{Name: schedule[n-3].Name, Every: 3}, schedule[n-3])
+	assert.Equal(t, types.SystemInfo{Name: schedule[n-2].Name, Every: 3, Offset: 2}, schedule[n-2])
+	assert.Equal(t, types.SystemInfo{Name: schedule[n-1].Name, Every: 1}, schedule[n-1])
+}
+
+type collision struct {
+	A, B types.EntityID
+}
+
+func TestInternalMessagesArePassedToLaterSystemsInTheSameTick(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil)
+	var received [][]collision
+	detect := func(wCtx engine.Context) error {
+		tick := types.EntityID(wCtx.CurrentTick())
+		if err := wCtx.EmitInternal(collision{A: tick, B: tick + 1}); err != nil {
+			return err
+		}
+		// Messages of other types are not returned.
+		return wCtx.EmitInternal(&collision{A: tick, B: tick})
+	}
+	resolve := func(wCtx engine.Context) error {
+		received = append(received, cardinal.InternalMessages[collision](wCtx))
+		return nil
+	}
+	assert.NilError(t, cardinal.RegisterSystems(tf.World, detect, resolve))
+	tf.DoTick()
+	tf.DoTick()
+
+	// Messages are dropped at the end of each tick.
+	assert.DeepEqual(t, [][]collision{{{A: 0, B: 1}}, {{A: 1, B: 2}}}, received)
+
+	readOnly := cardinal.NewReadOnlyWorldContext(tf.World)
+	assert.ErrorIs(t, readOnly.EmitInternal(collision{}), cardinal.ErrEntityMutationOnReadOnly)
+}
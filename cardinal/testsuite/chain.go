@@ -0,0 +1,228 @@
+package testsuite
+
+import (
+	"context"
+	"encoding/binary"
+	"slices"
+	"sync"
+
+	"github.com/rotisserie/eris"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+
+	shard "pkg.world.dev/world-engine/rift/shard/v2"
+)
+
+var _ shard.TransactionHandlerClient = (*FakeChain)(nil)
+
+// FakeChain is an in-process fake of the sequencer of the base shard. It implements the gRPC client that the router
+// of a world talks to, and keeps everything that worlds submit to it in memory, by namespace. A world that is
+// connected to a chain that already has transactions of its namespace recovers its game state from them on startup,
+// like a world in rollup mode recovers from the base shard.
+type FakeChain struct {
+	mu sync.Mutex
+	// outage is returned by every call while it is set. See SetOutage.
+	outage error
+
+	shards     map[string]*shard.RegisterGameShardRequest
+	epochs     map[string][]*shard.Epoch
+	outbox     map[string][]*shard.OutboxMessage
+	stateRoots map[string][]*shard.SubmitStateRootRequest
+}
+
+// NewFakeChain returns an empty chain.
+func NewFakeChain() *FakeChain {
+	return &FakeChain{
+		shards:     map[string]*shard.RegisterGameShardRequest{},
+		epochs:     map[string][]*shard.Epoch{},
+		outbox:     map[string][]*shard.OutboxMessage{},
+		stateRoots: map[string][]*shard.SubmitStateRootRequest{},
+	}
+}
+
+// SetOutage makes every call to the chain fail with err, e.g. to test how a world copes with an outage of the base
+// shard. A nil err ends the outage.
+func (c *FakeChain) SetOutage(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.outage = err
+}
+
+// Registration returns the registration of the game shard of the namespace.
+func (c *FakeChain) Registration(namespace string) (*shard.RegisterGameShardRequest, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	req, ok := c.shards[namespace]
+	return req, ok
+}
+
+// Transactions returns the transactions of the namespace that were sequenced in the tick, ordered by message ID.
+func (c *FakeChain) Transactions(namespace string, tick uint64) ([]*shard.Transaction, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	i, found := c.findEpoch(namespace, tick)
+	if !found {
+		return nil, nil
+	}
+	txs := make([]*shard.Transaction, 0, len(c.epochs[namespace][i].GetTxs()))
+	for _, data := range c.epochs[namespace][i].GetTxs() {
+		tx := new(shard.Transaction)
+		if err := proto.Unmarshal(data.GetGameShardTransaction(), tx); err != nil {
+			return nil, eris.Wrap(err, "failed to unmarshal transaction")
+		}
+		txs = append(txs, tx)
+	}
+	return txs, nil
+}
+
+// SequencedTicks returns the ticks of the namespace that have transactions on the chain, in order.
+func (c *FakeChain) SequencedTicks(namespace string) []uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ticks := make([]uint64, 0, len(c.epochs[namespace]))
+	for _, epoch := range c.epochs[namespace] {
+		ticks = append(ticks, epoch.GetEpoch())
+	}
+	return ticks
+}
+
+// OutboxMessages returns the messages to EVM contracts that the namespace delivered, in order of their IDs.
+func (c *FakeChain) OutboxMessages(namespace string) []*shard.OutboxMessage {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return slices.Clone(c.outbox[namespace])
+}
+
+// StateRoots returns the state roots that the namespace submitted, in the order they were submitted.
+func (c *FakeChain) StateRoots(namespace string) []*shard.SubmitStateRootRequest {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return slices.Clone(c.stateRoots[namespace])
+}
+
+func (c *FakeChain) RegisterGameShard(
+	_ context.Context, in *shard.RegisterGameShardRequest, _ ...grpc.CallOption,
+) (*shard.RegisterGameShardResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.outage != nil {
+		return nil, c.outage
+	}
+	c.shards[in.GetNamespace()] = in
+	return &shard.RegisterGameShardResponse{}, nil
+}
+
+// Submit sequences the transactions of a tick. Ticks without transactions are not stored, like on the base shard.
+func (c *FakeChain) Submit(
+	_ context.Context, in *shard.SubmitTransactionsRequest, _ ...grpc.CallOption,
+) (*shard.SubmitTransactionsResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.outage != nil {
+		return nil, c.outage
+	}
+	msgIDs := make([]uint64, 0, len(in.GetTransactions()))
+	for msgID := range in.GetTransactions() {
+		msgIDs = append(msgIDs, msgID)
+	}
+	slices.Sort(msgIDs)
+	epoch := &shard.Epoch{Epoch: in.GetEpoch(), UnixTimestamp: in.GetUnixTimestamp()}
+	for _, msgID := range msgIDs {
+		for _, tx := range in.GetTransactions()[msgID].GetTxs() {
+			bz, err := proto.Marshal(tx)
+			if err != nil {
+				return nil, eris.Wrap(err, "failed to marshal transaction")
+			}
+			epoch.Txs = append(epoch.Txs, &shard.TxData{TxId: msgID, GameShardTransaction: bz})
+		}
+	}
+	if len(epoch.Txs) == 0 {
+		return &shard.SubmitTransactionsResponse{}, nil
+	}
+
+	namespace := in.GetNamespace()
+	i, found := c.findEpoch(namespace, epoch.GetEpoch())
+	if found {
+		c.epochs[namespace][i] = epoch
+	} else {
+		c.epochs[namespace] = slices.Insert(c.epochs[namespace], i, epoch)
+	}
+	return &shard.SubmitTransactionsResponse{}, nil
+}
+
+// QueryTransactions returns the sequenced ticks of the namespace, starting at the tick in the key of the page.
+func (c *FakeChain) QueryTransactions(
+	_ context.Context, in *shard.QueryTransactionsRequest, _ ...grpc.CallOption,
+) (*shard.QueryTransactionsResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.outage != nil {
+		return nil, c.outage
+	}
+	var start uint64
+	if key := in.GetPage().GetKey(); len(key) == 8 { //nolint:gomnd // the key is a big endian tick
+		start = binary.BigEndian.Uint64(key)
+	}
+	limit := int(in.GetPage().GetLimit())
+	epochs := c.epochs[in.GetNamespace()]
+	i, _ := c.findEpoch(in.GetNamespace(), start)
+	res := &shard.QueryTransactionsResponse{Page: &shard.PageResponse{}}
+	for ; i < len(epochs) && (limit == 0 || len(res.Epochs) < limit); i++ {
+		res.Epochs = append(res.Epochs, epochs[i])
+	}
+	if i < len(epochs) {
+		res.Page.Key = binary.BigEndian.AppendUint64(nil, epochs[i].GetEpoch())
+	}
+	return res, nil
+}
+
+// DeliverOutbox receives outbox messages, dropping the ones that were already received, and acknowledges all of them.
+func (c *FakeChain) DeliverOutbox(
+	_ context.Context, in *shard.DeliverOutboxRequest, _ ...grpc.CallOption,
+) (*shard.DeliverOutboxResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.outage != nil {
+		return nil, c.outage
+	}
+	namespace := in.GetNamespace()
+	var ackedID uint64
+	if received := c.outbox[namespace]; len(received) > 0 {
+		ackedID = received[len(received)-1].GetId()
+	}
+	for _, msg := range in.GetMessages() {
+		// Message IDs start at 1
+		if msg.GetId() <= ackedID {
+			continue
+		}
+		c.outbox[namespace] = append(c.outbox[namespace], msg)
+		ackedID = msg.GetId()
+	}
+	return &shard.DeliverOutboxResponse{AckedId: ackedID}, nil
+}
+
+func (c *FakeChain) SubmitStateRoot(
+	_ context.Context, in *shard.SubmitStateRootRequest, _ ...grpc.CallOption,
+) (*shard.SubmitStateRootResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.outage != nil {
+		return nil, c.outage
+	}
+	c.stateRoots[in.GetNamespace()] = append(c.stateRoots[in.GetNamespace()], in)
+	return &shard.SubmitStateRootResponse{}, nil
+}
+
+// findEpoch returns the index of the epoch of the tick in the epochs of the namespace, or the index at which it would
+// be inserted.
+func (c *FakeChain) findEpoch(namespace string, tick uint64) (int, bool) {
+	return slices.BinarySearchFunc(c.epochs[namespace], tick, func(epoch *shard.Epoch, tick uint64) int {
+		switch {
+		case epoch.GetEpoch() < tick:
+			return -1
+		case epoch.GetEpoch() > tick:
+			return 1
+		}
+		return 0
+	})
+}
@@ -0,0 +1,188 @@
+package testsuite
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/gorilla/websocket"
+	"gotest.tools/v3/assert"
+
+	"pkg.world.dev/world-engine/cardinal/persona/msg"
+	"pkg.world.dev/world-engine/cardinal/server/handler"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/sign"
+)
+
+// DefaultTickTimeout is how long FakeNakama.WaitForTick waits for the results of a tick.
+const DefaultTickTimeout = 5 * time.Second
+
+// TickResults are the results of a tick, as the world broadcasts them to Nakama.
+type TickResults struct {
+	Tick     uint64
+	Receipts []Receipt
+	Events   [][]byte
+}
+
+// Receipt is the receipt of a transaction, as the world broadcasts it to Nakama.
+type Receipt struct {
+	TxHash types.TxHash    `json:"txHash"`
+	Tick   uint64          `json:"tick"`
+	Status string          `json:"status"`
+	Result json.RawMessage `json:"result"`
+	Errors []string        `json:"errors"`
+}
+
+// Receipt returns the receipt of the transaction, if it was executed in the tick.
+func (r TickResults) Receipt(hash types.TxHash) (Receipt, bool) {
+	for _, rec := range r.Receipts {
+		if rec.TxHash == hash {
+			return rec, true
+		}
+	}
+	return Receipt{}, false
+}
+
+// FakeNakama is an in-process stand-in for the Nakama relay. Like Nakama, it creates the personas of its users with
+// itself as their signer, signs their transactions with its own key and submits them to the HTTP server of a world,
+// and listens to the results of the ticks that the world broadcasts.
+type FakeNakama struct {
+	t         testing.TB
+	baseURL   string
+	namespace string
+	key       *ecdsa.PrivateKey
+	conn      *websocket.Conn
+
+	mu      sync.Mutex
+	nonce   uint64
+	results map[uint64]TickResults
+}
+
+// NewFakeNakama connects a fake Nakama to the world that serves the given address, e.g. "localhost:4040". It is
+// disconnected at the end of the test.
+func NewFakeNakama(t testing.TB, addr, namespace string) *FakeNakama {
+	key, err := crypto.GenerateKey()
+	assert.NilError(t, err)
+	conn, _, err := websocket.DefaultDialer.Dial("ws://"+addr+"/events", nil) //nolint:bodyclose // closed by Close
+	assert.NilError(t, err)
+	n := &FakeNakama{
+		t:         t,
+		baseURL:   "http://" + addr,
+		namespace: namespace,
+		key:       key,
+		conn:      conn,
+		results:   map[uint64]TickResults{},
+	}
+	go n.listen()
+	t.Cleanup(func() {
+		_ = n.conn.Close()
+	})
+	return n
+}
+
+// listen collects the results of ticks until the connection is closed.
+func (n *FakeNakama) listen() {
+	for {
+		_, bz, err := n.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		var results TickResults
+		if err = json.Unmarshal(bz, &results); err != nil {
+			continue
+		}
+		n.mu.Lock()
+		n.results[results.Tick] = results
+		n.mu.Unlock()
+	}
+}
+
+// SignerAddress returns the address that signs the transactions of the personas of the fake Nakama.
+func (n *FakeNakama) SignerAddress() string {
+	return crypto.PubkeyToAddress(n.key.PublicKey).Hex()
+}
+
+// CreatePersona submits the creation of a persona with the fake Nakama as its signer.
+func (n *FakeNakama) CreatePersona(personaTag string) types.TxHash {
+	tx, err := sign.NewSystemTransaction(n.key, n.namespace, n.nextNonce(), msg.CreatePersona{
+		PersonaTag:    personaTag,
+		SignerAddress: n.SignerAddress(),
+	})
+	assert.NilError(n.t, err)
+	return n.submit("tx/persona/"+msg.CreatePersonaMessageName, tx)
+}
+
+// SendTransaction submits a message of the persona, e.g. SendTransaction("alice", "game.move", Move{...}). The
+// persona must have been created with CreatePersona.
+func (n *FakeNakama) SendTransaction(personaTag, fullName string, message any) types.TxHash {
+	tx, err := sign.NewTransaction(n.key, personaTag, n.namespace, n.nextNonce(), message)
+	assert.NilError(n.t, err)
+	group, name, ok := strings.Cut(fullName, ".")
+	assert.Assert(n.t, ok, "message name %q has no group", fullName)
+	return n.submit("tx/"+group+"/"+name, tx)
+}
+
+// Query sends the request to the query, e.g. Query("game.health", HealthRequest{...}, &health), and decodes its reply
+// into reply.
+func (n *FakeNakama) Query(fullName string, request, reply any) {
+	group, name, ok := strings.Cut(fullName, ".")
+	assert.Assert(n.t, ok, "query name %q has no group", fullName)
+	n.post("query/"+group+"/"+name, request, reply)
+}
+
+// WaitForTick returns the results of the tick, once the world broadcast them.
+func (n *FakeNakama) WaitForTick(tick uint64) TickResults {
+	deadline := time.Now().Add(DefaultTickTimeout)
+	for time.Now().Before(deadline) {
+		n.mu.Lock()
+		results, ok := n.results[tick]
+		n.mu.Unlock()
+		if ok {
+			return results
+		}
+		time.Sleep(10 * time.Millisecond) //nolint:gomnd // its for testing its ok.
+	}
+	n.t.Fatalf("timeout while waiting for the results of tick %d", tick)
+	return TickResults{}
+}
+
+func (n *FakeNakama) nextNonce() uint64 {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.nonce++
+	return n.nonce
+}
+
+func (n *FakeNakama) submit(path string, tx *sign.Transaction) types.TxHash {
+	var res handler.PostTransactionResponse
+	n.post(path, tx, &res)
+	return types.TxHash(res.TxHash)
+}
+
+// post sends the payload to the world and decodes the reply. The test fails unless the world replies with 200 OK.
+func (n *FakeNakama) post(path string, payload, reply any) {
+	bz, err := json.Marshal(payload)
+	assert.NilError(n.t, err)
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, n.baseURL+"/"+path,
+		bytes.NewReader(bz))
+	assert.NilError(n.t, err)
+	req.Header.Add("Content-Type", "application/json")
+	res, err := http.DefaultClient.Do(req)
+	assert.NilError(n.t, err)
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	assert.NilError(n.t, err)
+	assert.Equal(n.t, http.StatusOK, res.StatusCode, fmt.Sprintf("POST /%s: %s", path, body))
+	if reply != nil {
+		assert.NilError(n.t, json.Unmarshal(body, reply))
+	}
+}
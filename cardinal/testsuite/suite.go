@@ -0,0 +1,135 @@
+// Package testsuite runs a world end to end in the process of a test, without docker-compose: the world is connected
+// to a FakeChain instead of the base shard, and a FakeNakama submits the transactions of personas through its HTTP
+// server like the Nakama relay does.
+//
+//	chain := testsuite.NewFakeChain()
+//	s := testsuite.New(t, chain)
+//	// Register components, messages, queries and systems with s.World
+//	s.Start()
+//	s.Tick(s.Nakama.CreatePersona("alice"))
+//	hash := s.Nakama.SendTransaction("alice", "game.move", Move{Direction: "up"})
+//	results := s.Tick(hash)
+//	s.AssertSucceeded(results, hash, &reply)
+package testsuite
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/receipt"
+	"pkg.world.dev/world-engine/cardinal/testutils"
+	"pkg.world.dev/world-engine/cardinal/types"
+)
+
+// Suite is a world that is connected to a fake chain and a fake Nakama.
+type Suite struct {
+	*testutils.TestFixture
+	Chain *FakeChain
+	// Nakama is connected to the world when it is started.
+	Nakama *FakeNakama
+}
+
+// New creates a world that is connected to the chain, and shuts it down at the end of the test. A new chain is used if
+// chain is nil. If the chain has transactions of the namespace of the world, the world recovers them when it is
+// started, e.g. to test that the game state can be rebuilt from the base shard.
+func New(t testing.TB, chain *FakeChain, opts ...cardinal.WorldOption) *Suite {
+	if chain == nil {
+		chain = NewFakeChain()
+	}
+	opts = append([]cardinal.WorldOption{cardinal.WithShardSequencer(chain)}, opts...)
+	return &Suite{
+		TestFixture: testutils.NewTestFixture(t, nil, opts...),
+		Chain:       chain,
+	}
+}
+
+// Start starts the world and connects the fake Nakama to it. Components, messages, queries and systems must be
+// registered before.
+func (s *Suite) Start() {
+	s.StartWorld()
+	if s.Nakama == nil {
+		s.Nakama = NewFakeNakama(s, s.BaseURL, s.World.Namespace())
+	}
+}
+
+// Tick runs a tick and returns its results, once they were broadcast to the fake Nakama. The test fails if any of the
+// given transactions was not executed in the tick.
+func (s *Suite) Tick(hashes ...types.TxHash) TickResults {
+	s.Start()
+	tick := s.World.CurrentTick()
+	s.DoTick()
+	results := s.Nakama.WaitForTick(tick)
+	for _, hash := range hashes {
+		_, ok := results.Receipt(hash)
+		assert.Assert(s, ok, "transaction %s was not executed in tick %d", hash, tick)
+	}
+	return results
+}
+
+// Ticks runs n ticks and returns the results of the last one.
+func (s *Suite) Ticks(n int) TickResults {
+	var results TickResults
+	for i := 0; i < n; i++ {
+		results = s.Tick()
+	}
+	return results
+}
+
+// AssertSucceeded asserts that the transaction was executed in the tick without errors, and decodes its result into
+// result unless it is nil.
+func (s *Suite) AssertSucceeded(results TickResults, hash types.TxHash, result any) {
+	rec, ok := results.Receipt(hash)
+	assert.Assert(s, ok, "transaction %s was not executed in tick %d", hash, results.Tick)
+	assert.Equal(s, receipt.StatusSuccess, rec.Status, "transaction %s failed: %v", hash, rec.Errors)
+	if result != nil {
+		assert.NilError(s, json.Unmarshal(rec.Result, result))
+	}
+}
+
+// AssertFailed asserts that the transaction was executed in the tick, and failed with an error that contains errText.
+func (s *Suite) AssertFailed(results TickResults, hash types.TxHash, errText string) {
+	rec, ok := results.Receipt(hash)
+	assert.Assert(s, ok, "transaction %s was not executed in tick %d", hash, results.Tick)
+	assert.Equal(s, receipt.StatusFailed, rec.Status, "transaction %s did not fail", hash)
+	for _, err := range rec.Errors {
+		if strings.Contains(err, errText) {
+			return
+		}
+	}
+	s.Fatalf("transaction %s did not fail with %q: %v", hash, errText, rec.Errors)
+}
+
+// AssertEvent asserts that the tick emitted an event with the given fields, e.g. AssertEvent(results,
+// map[string]any{"event": "moved"}), and returns the first such event. Fields are compared by their JSON encoding.
+func (s *Suite) AssertEvent(results TickResults, fields map[string]any) map[string]any {
+	for _, bz := range results.Events {
+		var event map[string]any
+		if json.Unmarshal(bz, &event) != nil {
+			continue
+		}
+		if matchesFields(event, fields) {
+			return event
+		}
+	}
+	s.Fatalf("tick %d emitted no event with %v", results.Tick, fields)
+	return nil
+}
+
+func matchesFields(event, fields map[string]any) bool {
+	for key, want := range fields {
+		got, ok := event[key]
+		if !ok {
+			return false
+		}
+		if gotBz, err := json.Marshal(got); err != nil {
+			return false
+		} else if wantBz, err := json.Marshal(want); err != nil || string(gotBz) != string(wantBz) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,128 @@
+package testsuite_test
+
+import (
+	"errors"
+	"testing"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/message"
+	"pkg.world.dev/world-engine/cardinal/search"
+	"pkg.world.dev/world-engine/cardinal/search/filter"
+	"pkg.world.dev/world-engine/cardinal/testsuite"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+type Score struct {
+	PersonaTag string
+	Points     int
+}
+
+func (Score) Name() string { return "Score" }
+
+type ScoreMsg struct {
+	Points int
+}
+
+type ScoreRequest struct {
+	PersonaTag string
+}
+
+// newScoreSuite returns a suite whose world keeps a score for every persona.
+func newScoreSuite(t *testing.T, chain *testsuite.FakeChain) *testsuite.Suite {
+	s := testsuite.New(t, chain)
+	world := s.World
+	assert.NilError(t, cardinal.RegisterComponent[Score](world))
+	assert.NilError(t, cardinal.RegisterMessage[ScoreMsg, Score](world, "score"))
+	findScore := func(wCtx engine.Context, personaTag string) (types.EntityID, *Score, error) {
+		var found types.EntityID
+		var score *Score
+		err := search.NewSearch().Entity(filter.Exact(filter.Component[Score]())).
+			Each(wCtx, func(id types.EntityID) bool {
+				candidate, err := cardinal.GetComponent[Score](wCtx, id)
+				if err == nil && candidate.PersonaTag == personaTag {
+					found, score = id, candidate
+					return false
+				}
+				return true
+			})
+		return found, score, err
+	}
+	assert.NilError(t, cardinal.RegisterQuery[ScoreRequest, Score](world, "score",
+		func(wCtx engine.Context, req *ScoreRequest) (*Score, error) {
+			_, score, err := findScore(wCtx, req.PersonaTag)
+			if score == nil && err == nil {
+				return &Score{PersonaTag: req.PersonaTag}, nil
+			}
+			return score, err
+		}))
+	assert.NilError(t, cardinal.RegisterSystems(world, func(wCtx engine.Context) error {
+		return cardinal.EachMessage[ScoreMsg, Score](wCtx, func(tx message.TxData[ScoreMsg]) (Score, error) {
+			if tx.Msg.Points <= 0 {
+				return Score{}, errors.New("points must be positive")
+			}
+			id, score, err := findScore(wCtx, tx.Tx.PersonaTag)
+			if err != nil {
+				return Score{}, err
+			}
+			if score == nil {
+				score = &Score{PersonaTag: tx.Tx.PersonaTag}
+				if id, err = cardinal.Create(wCtx, *score); err != nil {
+					return Score{}, err
+				}
+			}
+			score.Points += tx.Msg.Points
+			if err = cardinal.SetComponent[Score](wCtx, id, score); err != nil {
+				return Score{}, err
+			}
+			return *score, wCtx.EmitEvent(map[string]any{"event": "scored", "persona": score.PersonaTag})
+		})
+	}))
+	s.Start()
+	return s
+}
+
+func TestSuiteRunsTransactionsOfNakamaPersonas(t *testing.T) {
+	s := newScoreSuite(t, nil)
+	s.Tick(s.Nakama.CreatePersona("alice"))
+
+	hash := s.Nakama.SendTransaction("alice", "game.score", ScoreMsg{Points: 3})
+	results := s.Tick(hash)
+	var score Score
+	s.AssertSucceeded(results, hash, &score)
+	assert.Equal(t, 3, score.Points)
+	event := s.AssertEvent(results, map[string]any{"event": "scored"})
+	assert.Equal(t, "alice", event["persona"])
+
+	hash = s.Nakama.SendTransaction("alice", "game.score", ScoreMsg{Points: -1})
+	s.AssertFailed(s.Tick(hash), hash, "points must be positive")
+
+	s.Nakama.Query("game.score", ScoreRequest{PersonaTag: "alice"}, &score)
+	assert.Equal(t, 3, score.Points)
+
+	// The world registered itself with the chain, and its transactions were sequenced
+	_, ok := s.Chain.Registration(s.World.Namespace())
+	assert.Assert(t, ok)
+	txs, err := s.Chain.Transactions(s.World.Namespace(), results.Tick)
+	assert.NilError(t, err)
+	assert.Equal(t, 1, len(txs))
+	assert.Equal(t, "alice", txs[0].GetPersonaTag())
+}
+
+func TestSuiteRecoversFromChain(t *testing.T) {
+	chain := testsuite.NewFakeChain()
+	s := newScoreSuite(t, chain)
+	s.Tick(s.Nakama.CreatePersona("alice"))
+	s.Tick(s.Nakama.SendTransaction("alice", "game.score", ScoreMsg{Points: 2}))
+	s.Ticks(2)
+	s.Tick(s.Nakama.SendTransaction("alice", "game.score", ScoreMsg{Points: 5}))
+	assert.Equal(t, 3, len(chain.SequencedTicks(s.World.Namespace())))
+
+	// Another world with empty storage rebuilds the game state from the chain
+	recovered := newScoreSuite(t, chain)
+	assert.Equal(t, s.World.CurrentTick(), recovered.World.CurrentTick())
+	var score Score
+	recovered.Nakama.Query("game.score", ScoreRequest{PersonaTag: "alice"}, &score)
+	assert.Equal(t, 7, score.Points)
+}
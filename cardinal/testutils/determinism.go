@@ -0,0 +1,17 @@
+package testutils
+
+import (
+	"testing"
+
+	"pkg.world.dev/world-engine/cardinal"
+)
+
+// CheckDeterministicSystems fails the test if a system registered in the world, or a function of its package that it
+// calls, reads the wall clock, uses the global math/rand source, spawns goroutines or ranges over maps. See
+// cardinal.World.LintSystems.
+func CheckDeterministicSystems(t testing.TB, world *cardinal.World) {
+	t.Helper()
+	if err := world.LintSystems(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,115 @@
+package testutils
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/abi"
+)
+
+const (
+	// EVMFixturesFile is the name of the file in which CheckEVMFixtures stores the fixtures.
+	EVMFixturesFile = "evm_fixtures.json"
+	// EVMConformanceFile is the name of the Foundry test that CheckEVMFixtures generates for Solidity developers.
+	EVMConformanceFile = "WorldConformance.t.sol"
+	// UpdateEVMFixturesEnv is the environment variable that makes CheckEVMFixtures overwrite the committed fixtures.
+	UpdateEVMFixturesEnv = "UPDATE_EVM_FIXTURES"
+
+	// EVMBindingsGoFile is the name of the Go file in which CheckEVMBindings registers the ABI types of the world.
+	EVMBindingsGoFile = "evm_bindings.go"
+	// EVMBindingsSolidityFile is the name of the Solidity file in which CheckEVMBindings declares the structs and the
+	// EVMBindingsInterface interface of the world.
+	EVMBindingsSolidityFile = "IWorld.sol"
+	// EVMBindingsInterface is the name of the generated Solidity interface.
+	EVMBindingsInterface = "IWorld"
+	// UpdateEVMBindingsEnv is the environment variable that makes CheckEVMBindings overwrite the generated bindings.
+	UpdateEVMBindingsEnv = "UPDATE_EVM_BINDINGS"
+)
+
+// CheckEVMFixtures fails the test if the ABI encoding of the world's messages and queries differs from the fixtures
+// committed in dir. The fixtures, and a Foundry conformance test that checks the structs of the game's contracts
+// against them, are written to dir when they don't exist yet or when UPDATE_EVM_FIXTURES is set.
+func CheckEVMFixtures(t testing.TB, world *cardinal.World, dir string) {
+	t.Helper()
+	got, err := world.EVMFixtures()
+	if err != nil {
+		t.Fatalf("failed to generate EVM fixtures: %v", err)
+	}
+	path := filepath.Join(dir, EVMFixturesFile)
+	want, err := abi.ReadFixtures(path)
+	if errors.Is(err, os.ErrNotExist) || os.Getenv(UpdateEVMFixturesEnv) != "" {
+		writeEVMFixtures(t, dir, got)
+		return
+	} else if err != nil {
+		t.Fatal(err)
+	}
+	if err = abi.CompareFixtures(want, got); err != nil {
+		t.Fatalf("%v\nrun the test with %s=1 to update the fixtures if the change is intended", err,
+			UpdateEVMFixturesEnv)
+	}
+}
+
+func writeEVMFixtures(t testing.TB, dir string, fixtures []abi.Fixture) {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := abi.WriteFixtures(filepath.Join(dir, EVMFixturesFile), fixtures); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Create(filepath.Join(dir, EVMConformanceFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if err = abi.WriteSolidityConformance(f, "WorldConformanceTest", fixtures); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// CheckEVMBindings fails the test if the EVM bindings of the world's messages and queries differ from the ones
+// generated in dir. The bindings are a Go file of package pkgName that registers the ABI types of the message and query
+// structs, and a Solidity file with matching structs and an interface for the game's contracts. They are written to dir
+// when they don't exist yet or when UPDATE_EVM_BINDINGS is set, e.g. from a go:generate directive:
+//
+//	//go:generate env UPDATE_EVM_BINDINGS=1 go test -run TestEVMBindings .
+//
+// The world in the test must not import the generated package, since the stale types it registers would make
+// registering the changed messages fail. Import it from main.go instead.
+func CheckEVMBindings(t testing.TB, world *cardinal.World, dir, pkgName string) {
+	t.Helper()
+	bindings, err := world.EVMBindings()
+	if err != nil {
+		t.Fatalf("failed to collect EVM bindings: %v", err)
+	}
+	var goSrc, solSrc bytes.Buffer
+	if err = abi.WriteGoBindings(&goSrc, pkgName, bindings); err != nil {
+		t.Fatalf("failed to generate Go bindings: %v", err)
+	}
+	if err = abi.WriteSolidityBindings(&solSrc, EVMBindingsInterface, bindings); err != nil {
+		t.Fatalf("failed to generate Solidity bindings: %v", err)
+	}
+	files := map[string][]byte{EVMBindingsGoFile: goSrc.Bytes(), EVMBindingsSolidityFile: solSrc.Bytes()}
+
+	update := os.Getenv(UpdateEVMBindingsEnv) != ""
+	for name, want := range files {
+		got, err := os.ReadFile(filepath.Join(dir, name))
+		switch {
+		case errors.Is(err, os.ErrNotExist) || update:
+			if err = os.MkdirAll(dir, 0o755); err != nil {
+				t.Fatal(err)
+			}
+			if err = os.WriteFile(filepath.Join(dir, name), want, 0o600); err != nil {
+				t.Fatal(err)
+			}
+		case err != nil:
+			t.Fatal(err)
+		case !bytes.Equal(got, want):
+			t.Errorf("%s is out of date\nrun the test with %s=1 to regenerate the bindings", name, UpdateEVMBindingsEnv)
+		}
+	}
+}
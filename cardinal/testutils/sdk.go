@@ -0,0 +1,72 @@
+package testutils
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/sdk"
+)
+
+const (
+	// TypeScriptSDKFile is the name of the file in which CheckTypeScriptSDK generates the TypeScript SDK.
+	TypeScriptSDKFile = "cardinal.ts"
+	// UpdateTypeScriptSDKEnv is the environment variable that makes CheckTypeScriptSDK overwrite the generated SDK.
+	UpdateTypeScriptSDKEnv = "UPDATE_TS_SDK"
+
+	// CSharpSDKFile is the name of the file in which CheckCSharpSDK generates the C# SDK.
+	CSharpSDKFile = "CardinalClient.cs"
+	// UpdateCSharpSDKEnv is the environment variable that makes CheckCSharpSDK overwrite the generated SDK.
+	UpdateCSharpSDKEnv = "UPDATE_CS_SDK"
+)
+
+// CheckTypeScriptSDK fails the test if the TypeScript SDK of the world's messages and queries differs from the one
+// generated in dir. The SDK is written to dir when it doesn't exist yet or when UPDATE_TS_SDK is set, e.g. from a
+// go:generate directive:
+//
+//	//go:generate env UPDATE_TS_SDK=1 go test -run TestTypeScriptSDK .
+//
+// Point dir at the web client, so that it is updated with the world and can't drift from it.
+func CheckTypeScriptSDK(t testing.TB, world *cardinal.World, dir string) {
+	t.Helper()
+	var want bytes.Buffer
+	if err := sdk.WriteTypeScript(&want, world.ClientEndpoints()); err != nil {
+		t.Fatalf("failed to generate the TypeScript SDK: %v", err)
+	}
+	checkGeneratedSDK(t, filepath.Join(dir, TypeScriptSDKFile), want.Bytes(), UpdateTypeScriptSDKEnv)
+}
+
+// CheckCSharpSDK is CheckTypeScriptSDK for the C# SDK of Unity clients, which is generated in the given C# namespace.
+// It is written to dir when it doesn't exist yet or when UPDATE_CS_SDK is set. Point dir at a folder of the Unity
+// project's Assets, which has to reference the Newtonsoft.Json and Nethereum packages.
+func CheckCSharpSDK(t testing.TB, world *cardinal.World, dir, namespace string) {
+	t.Helper()
+	var want bytes.Buffer
+	if err := sdk.WriteCSharp(&want, namespace, world.ClientEndpoints()); err != nil {
+		t.Fatalf("failed to generate the C# SDK: %v", err)
+	}
+	checkGeneratedSDK(t, filepath.Join(dir, CSharpSDKFile), want.Bytes(), UpdateCSharpSDKEnv)
+}
+
+// checkGeneratedSDK fails the test if the file at path differs from want, and overwrites it with want if it doesn't
+// exist yet or if the update environment variable is set.
+func checkGeneratedSDK(t testing.TB, path string, want []byte, updateEnv string) {
+	t.Helper()
+	got, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist) || os.Getenv(updateEnv) != "":
+		if err = os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err = os.WriteFile(path, want, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	case err != nil:
+		t.Fatal(err)
+	case !bytes.Equal(got, want):
+		t.Errorf("%s is out of date\nrun the test with %s=1 to regenerate the SDK", filepath.Base(path), updateEnv)
+	}
+}
@@ -19,6 +19,8 @@ import (
 
 	"pkg.world.dev/world-engine/cardinal"
 	"pkg.world.dev/world-engine/cardinal/persona/msg"
+	"pkg.world.dev/world-engine/cardinal/receipt"
+	"pkg.world.dev/world-engine/cardinal/storage/redis"
 	"pkg.world.dev/world-engine/cardinal/types"
 	"pkg.world.dev/world-engine/sign"
 )
@@ -33,10 +35,11 @@ type TestFixture struct {
 	World   *cardinal.World
 	Redis   *miniredis.Miniredis
 
-	StartTickCh chan time.Time
-	DoneTickCh  chan uint64
-	doCleanup   func()
-	startOnce   *sync.Once
+	StartTickCh  chan time.Time
+	DoneTickCh   chan uint64
+	doCleanup    func()
+	startOnce    *sync.Once
+	shutdownOnce *sync.Once
 }
 
 // NewTestFixture creates a test fixture with user defined port for Cardinal integration tests.
@@ -73,9 +76,10 @@ func NewTestFixture(t testing.TB, redis *miniredis.Miniredis, opts ...cardinal.W
 		World:   world,
 		Redis:   redis,
 
-		StartTickCh: startTickCh,
-		DoneTickCh:  doneTickCh,
-		startOnce:   &sync.Once{},
+		StartTickCh:  startTickCh,
+		DoneTickCh:   doneTickCh,
+		startOnce:    &sync.Once{},
+		shutdownOnce: &sync.Once{},
 		// Only register this method with t.Cleanup if the game server is actually started
 		doCleanup: func() {
 			// First, make sure completed ticks will never be blocked
@@ -84,13 +88,26 @@ func NewTestFixture(t testing.TB, redis *miniredis.Miniredis, opts ...cardinal.W
 				}
 			}()
 			// Next, shut down the world
-			assert.NilError(t, world.Shutdown())
+			assert.NilError(t, world.Shutdown(context.Background()))
 			// The world is shut down; No more ticks will be started
 			close(startTickCh)
 		},
 	}
 }
 
+// NewPersistentTestFixture creates a test fixture whose in-memory redis is restored from the file at path, and saved
+// to it when the test ends. Fixtures that are created with the same path later continue with the saved game state.
+func NewPersistentTestFixture(t testing.TB, path string, opts ...cardinal.WorldOption) *TestFixture {
+	mr := miniredis.RunT(t)
+	assert.NilError(t, redis.LoadSnapshot(mr, path))
+	tf := NewTestFixture(t, mr, opts...)
+	// Cleanups run in reverse order, so the snapshot is saved after the world was shut down by StartWorld's cleanup
+	t.Cleanup(func() {
+		assert.NilError(t, redis.SaveSnapshot(mr, path))
+	})
+	return tf
+}
+
 // StartWorld starts the game world and registers a cleanup function that will shut down
 // the cardinal World at the end of the test. Components/Systems/Queries, etc should
 // be registered before calling this function.
@@ -116,8 +133,19 @@ func (t *TestFixture) StartWorld() {
 				time.Sleep(10 * time.Millisecond) //nolint:gomnd // its for testing its ok.
 			}
 		}
-		t.Cleanup(t.doCleanup)
+		t.Cleanup(t.Shutdown)
+	})
+}
+
+// Shutdown shuts the world down, if it was started. A world refuses to start while another world owns its namespace, so
+// a fixture that is created with the redis of another fixture to restart the game can only be started after the other
+// fixture was shut down.
+func (t *TestFixture) Shutdown() {
+	t.startOnce.Do(func() {
+		// A world that was never started has nothing to shut down
+		t.shutdownOnce.Do(func() {})
 	})
+	t.shutdownOnce.Do(t.doCleanup)
 }
 
 // DoTick executes one game tick and blocks until the tick is complete. StartWorld is automatically called if it was
@@ -164,10 +192,29 @@ func (t *TestFixture) AddTransaction(txID types.MessageID, tx any, sigs ...*sign
 	if len(sigs) > 0 {
 		sig = sigs[0]
 	}
-	_, id := t.World.AddTransaction(txID, tx, sig)
+	_, id, err := t.World.AddTransaction(txID, tx, sig)
+	assert.NilError(t, err)
 	return id
 }
 
+// SendTransaction adds a transaction of the message with the given full name (e.g. "game.move") signed by the
+// persona, executes a tick and returns the receipt of the transaction.
+func (t *TestFixture) SendTransaction(msgFullName string, msg any, personaTag string) receipt.Receipt {
+	msgType, ok := t.World.GetMessageByFullName(msgFullName)
+	assert.Assert(t, ok, "message with name %q not registered in World", msgFullName)
+	txHash := t.AddTransaction(msgType.ID(), msg, &sign.Transaction{PersonaTag: personaTag})
+	t.DoTick()
+	receipts, err := t.World.GetTransactionReceiptsForTick(t.World.CurrentTick() - 1)
+	assert.NilError(t, err)
+	for _, rec := range receipts {
+		if rec.TxHash == txHash {
+			return rec
+		}
+	}
+	t.Fatalf("no receipt for transaction %s of message %q", txHash, msgFullName)
+	return receipt.Receipt{}
+}
+
 func (t *TestFixture) CreatePersona(personaTag, signerAddr string) {
 	personaMsg := msg.CreatePersona{
 		PersonaTag:    personaTag,
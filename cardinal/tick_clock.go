@@ -0,0 +1,187 @@
+package cardinal
+
+import (
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	querylib "pkg.world.dev/world-engine/cardinal/query"
+	"pkg.world.dev/world-engine/cardinal/statsd"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+// DriftMode is how the world catches up when ticks start later than scheduled, e.g. because a tick took longer than
+// the tick interval or the process was paused.
+type DriftMode string
+
+const (
+	// DriftJump skips the ticks that were missed and keeps the next ticks on the original interval boundaries. Ticks
+	// are never closer than the tick interval, but the number of ticks falls behind wall clock time by the skipped
+	// intervals. This is the default.
+	DriftJump DriftMode = "jump"
+	// DriftSlew keeps every tick and catches up by starting late ticks sooner, by at most MaxDriftSlew of the tick
+	// interval, until they are back on schedule. The number of ticks stays in line with wall clock time, so durations
+	// that are measured in ticks (cooldowns, physics steps) stay correct over time.
+	DriftSlew DriftMode = "slew"
+
+	// MaxDriftSlew is the fraction of the tick interval by which DriftSlew starts late ticks sooner.
+	MaxDriftSlew = 0.5
+	// driftTolerance is the lateness that is considered timer jitter, which DriftSlew does not count as catching up.
+	driftTolerance = time.Millisecond
+
+	// TickDriftQueryName is the name of the query that returns the TickDriftStats of the world, in the "world" group.
+	TickDriftQueryName = "tick-drift"
+	worldQueryGroup    = "world"
+)
+
+// TickDriftStats describes how far the ticks of the world are from their schedule.
+type TickDriftStats struct {
+	Mode     DriftMode     `json:"mode"`
+	Interval time.Duration `json:"intervalNs"`
+	// Ticks is the number of ticks that were started since the world started.
+	Ticks uint64 `json:"ticks"`
+	// LastScheduled and LastStarted are the times at which the last tick was scheduled to start and actually started.
+	LastScheduled time.Time `json:"lastScheduled"`
+	LastStarted   time.Time `json:"lastStarted"`
+	// LastDrift is how late the last tick started, and MaxDrift is the largest LastDrift since the world started.
+	LastDrift time.Duration `json:"lastDriftNs"`
+	MaxDrift  time.Duration `json:"maxDriftNs"`
+	// AccumulatedDrift is how far the ticks are behind wall clock time: the time since the first tick, minus the time
+	// that the ticks since the first tick account for. Game logic that counts ticks to measure time is off by this much.
+	AccumulatedDrift time.Duration `json:"accumulatedDriftNs"`
+	// SkippedTicks is the number of ticks that DriftJump skipped.
+	SkippedTicks uint64 `json:"skippedTicks"`
+	// CatchUpTicks is the number of ticks that DriftSlew started sooner than the tick interval.
+	CatchUpTicks uint64 `json:"catchUpTicks"`
+}
+
+// tickClock schedules the ticks of the world and measures how late they start.
+type tickClock struct {
+	interval time.Duration
+	mode     DriftMode
+
+	mu sync.Mutex
+	// first is the scheduled start of the first tick.
+	first time.Time
+	stats TickDriftStats
+}
+
+func newTickClock(interval time.Duration, mode DriftMode) *tickClock {
+	return &tickClock{
+		interval: interval,
+		mode:     mode,
+		stats:    TickDriftStats{Mode: mode, Interval: interval},
+	}
+}
+
+// start sends the scheduled start time of every tick on the returned channel, until stop is closed. The channel is
+// unbuffered, so a tick is only scheduled once the previous one started.
+func (c *tickClock) start(stop <-chan struct{}) <-chan time.Time {
+	ticks := make(chan time.Time)
+	go func() {
+		next := time.Now().Add(c.interval)
+		timer := time.NewTimer(c.interval)
+		defer timer.Stop()
+		for {
+			select {
+			case <-timer.C:
+			case <-stop:
+				return
+			}
+			select {
+			case ticks <- next:
+			case <-stop:
+				return
+			}
+			var wait time.Duration
+			next, wait = c.schedule(next, time.Now())
+			timer.Reset(wait)
+		}
+	}()
+	return ticks
+}
+
+// schedule returns the scheduled start of the tick after the one that was scheduled at prev and started at now, and
+// how long to wait for it.
+func (c *tickClock) schedule(prev, now time.Time) (time.Time, time.Duration) {
+	next := prev.Add(c.interval)
+	if c.mode == DriftSlew {
+		minWait := time.Duration(float64(c.interval) * (1 - MaxDriftSlew))
+		wait := max(next.Sub(now), minWait)
+		if wait < c.interval-driftTolerance {
+			c.mu.Lock()
+			c.stats.CatchUpTicks++
+			c.mu.Unlock()
+		}
+		return next, wait
+	}
+	if late := now.Sub(prev); late >= c.interval {
+		missed := late / c.interval
+		next = next.Add(missed * c.interval)
+		c.mu.Lock()
+		c.stats.SkippedTicks += uint64(missed)
+		c.mu.Unlock()
+	}
+	return next, next.Sub(now)
+}
+
+// recordStart records that the tick scheduled at the given time started now, and emits the drift to statsd.
+func (c *tickClock) recordStart(scheduled, now time.Time) {
+	if scheduled.IsZero() {
+		// Tick channels set with WithTickChannel don't have to carry the scheduled time.
+		return
+	}
+	c.mu.Lock()
+	if c.stats.Ticks == 0 {
+		c.first = scheduled
+	}
+	drift := max(now.Sub(scheduled), 0)
+	c.stats.Ticks++
+	c.stats.LastScheduled = scheduled
+	c.stats.LastStarted = now
+	c.stats.LastDrift = drift
+	c.stats.MaxDrift = max(c.stats.MaxDrift, drift)
+	c.stats.AccumulatedDrift = now.Sub(c.first) - time.Duration(c.stats.Ticks-1)*c.interval
+	accumulated := c.stats.AccumulatedDrift
+	c.mu.Unlock()
+
+	if err := statsd.Client().Timing("tick_drift", drift, nil, 1); err != nil {
+		log.Warn().Err(err).Msg("failed to emit tick drift")
+	}
+	if err := statsd.Client().Gauge("tick_accumulated_drift_ms", float64(accumulated.Milliseconds()), nil,
+		1); err != nil {
+		log.Warn().Err(err).Msg("failed to emit accumulated tick drift")
+	}
+}
+
+// lag returns how far the ticks are behind their schedule at the given time: how late the last tick started, or how
+// late the next tick is if it should have started already, e.g. because the last tick is still running.
+func (c *tickClock) lag(now time.Time) time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.stats.Ticks == 0 {
+		return 0
+	}
+	return max(c.stats.LastDrift, now.Sub(c.stats.LastScheduled.Add(c.interval)))
+}
+
+// TickDrift returns how far the ticks of the world are from their schedule.
+func (w *World) TickDrift() TickDriftStats {
+	w.tickClock.mu.Lock()
+	defer w.tickClock.mu.Unlock()
+	return w.tickClock.stats
+}
+
+type TickDriftRequest struct{}
+
+// registerTickDriftQuery registers the query that serves the TickDriftStats of the world.
+func registerTickDriftQuery(w *World) error {
+	return RegisterQuery[TickDriftRequest, TickDriftStats](w, TickDriftQueryName,
+		func(engine.Context, *TickDriftRequest) (*TickDriftStats, error) {
+			stats := w.TickDrift()
+			return &stats, nil
+		},
+		querylib.WithCustomQueryGroup[TickDriftRequest, TickDriftStats](worldQueryGroup),
+	)
+}
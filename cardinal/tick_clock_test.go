@@ -0,0 +1,62 @@
+package cardinal
+
+import (
+	"testing"
+	"time"
+
+	"pkg.world.dev/world-engine/assert"
+)
+
+func TestTickClock_JumpSkipsMissedTicks(t *testing.T) {
+	c := newTickClock(time.Second, DriftJump)
+	start := time.Unix(1000, 0)
+
+	// A tick that started on time is followed by the next interval.
+	next, wait := c.schedule(start, start.Add(100*time.Millisecond))
+	assert.Equal(t, next, start.Add(time.Second))
+	assert.Equal(t, wait, 900*time.Millisecond)
+
+	// A tick that started 2.5 intervals late skips the 2 ticks it missed.
+	next, wait = c.schedule(start, start.Add(2500*time.Millisecond))
+	assert.Equal(t, next, start.Add(3*time.Second))
+	assert.Equal(t, wait, 500*time.Millisecond)
+	assert.Equal(t, c.stats.SkippedTicks, uint64(2))
+	assert.Equal(t, c.stats.CatchUpTicks, uint64(0))
+}
+
+func TestTickClock_SlewCatchesUpWithinLimit(t *testing.T) {
+	c := newTickClock(time.Second, DriftSlew)
+	start := time.Unix(1000, 0)
+
+	// Every tick is kept, and a late tick is followed sooner, but never sooner than MaxDriftSlew allows.
+	next, wait := c.schedule(start, start.Add(2500*time.Millisecond))
+	assert.Equal(t, next, start.Add(time.Second))
+	assert.Equal(t, wait, 500*time.Millisecond)
+	next, wait = c.schedule(next, start.Add(1200*time.Millisecond))
+	assert.Equal(t, next, start.Add(2*time.Second))
+	assert.Equal(t, wait, 800*time.Millisecond)
+	assert.Equal(t, c.stats.CatchUpTicks, uint64(2))
+	assert.Equal(t, c.stats.SkippedTicks, uint64(0))
+
+	// Timer jitter is not counted as catching up.
+	_, wait = c.schedule(next, next.Add(time.Microsecond))
+	assert.Equal(t, wait, time.Second-time.Microsecond)
+	assert.Equal(t, c.stats.CatchUpTicks, uint64(2))
+}
+
+func TestTickClock_RecordStart(t *testing.T) {
+	c := newTickClock(time.Second, DriftJump)
+	start := time.Unix(1000, 0)
+
+	c.recordStart(start, start.Add(10*time.Millisecond))
+	c.recordStart(start.Add(time.Second), start.Add(1300*time.Millisecond))
+	// The tick scheduled at 2s was skipped.
+	c.recordStart(start.Add(3*time.Second), start.Add(3020*time.Millisecond))
+	// Ticks sent without a scheduled time are not recorded.
+	c.recordStart(time.Time{}, start.Add(4*time.Second))
+
+	assert.Equal(t, c.stats.Ticks, uint64(3))
+	assert.Equal(t, c.stats.LastDrift, 20*time.Millisecond)
+	assert.Equal(t, c.stats.MaxDrift, 300*time.Millisecond)
+	assert.Equal(t, c.stats.AccumulatedDrift, time.Second+20*time.Millisecond)
+}
@@ -0,0 +1,120 @@
+package cardinal
+
+import (
+	"bytes"
+	"runtime"
+	"runtime/pprof"
+	"sync"
+	"time"
+
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal/admin"
+)
+
+// MaxProfiledTicks is the number of ticks that ProfileTicks can profile at once.
+const MaxProfiledTicks = 100
+
+var ErrProfiling = admin.ErrProfiling
+
+// tickProfiler captures CPU and allocation profiles of ticks. See World.ProfileTicks.
+type tickProfiler struct {
+	mu sync.Mutex
+	// remaining is the number of ticks that are still to be profiled.
+	remaining int
+	profiles  []admin.TickProfile
+	// err is the error that stopped profiling early.
+	err error
+
+	// The state of the tick that is being profiled. active is false if no tick is being profiled.
+	active    bool
+	current   admin.TickProfile
+	cpu       *bytes.Buffer
+	memBefore runtime.MemStats
+	startTime time.Time
+}
+
+// ProfileTicks captures a CPU profile and allocation profiles of each of the next ticks, until the given number of ticks
+// was profiled. The profiles start and stop at the boundaries of the ticks, so they don't include the time in which the
+// world waits for the next tick. The garbage collector is run at both boundaries of a profiled tick, so that the
+// allocation profiles are up to date. The profiles of a previous call are discarded.
+func (w *World) ProfileTicks(ticks int) error {
+	if ticks < 1 || ticks > MaxProfiledTicks {
+		return eris.Wrapf(admin.ErrInvalidValue, "ticks must be between 1 and %d", MaxProfiledTicks)
+	}
+	p := w.tickProfiler
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.remaining > 0 {
+		return eris.Wrapf(ErrProfiling, "%d ticks remaining", p.remaining)
+	}
+	p.remaining, p.profiles, p.err = ticks, nil, nil
+	return nil
+}
+
+// GetTickProfiles returns the number of ticks that are still to be profiled, and the profiles of the ticks that were
+// profiled since the last call to ProfileTicks. The error is set if profiling stopped early.
+func (w *World) GetTickProfiles() (int, []admin.TickProfile, error) {
+	p := w.tickProfiler
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.remaining, p.profiles, p.err
+}
+
+// tickStarted starts profiling the tick if ticks are to be profiled.
+func (p *tickProfiler) tickStarted() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.remaining == 0 {
+		return
+	}
+	runtime.GC()
+	var allocs bytes.Buffer
+	if err := pprof.Lookup("allocs").WriteTo(&allocs, 0); err != nil {
+		p.stop(eris.Wrap(err, "failed to write allocation profile"))
+		return
+	}
+	p.cpu = new(bytes.Buffer)
+	if err := pprof.StartCPUProfile(p.cpu); err != nil {
+		p.stop(eris.Wrap(err, "failed to start CPU profile"))
+		return
+	}
+	p.current = admin.TickProfile{AllocsBefore: allocs.Bytes()}
+	runtime.ReadMemStats(&p.memBefore)
+	p.startTime = time.Now()
+	p.active = true
+}
+
+// tickEnded stops profiling the tick that ended and keeps its profiles.
+func (p *tickProfiler) tickEnded(tick uint64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.active {
+		return
+	}
+	p.active = false
+	duration := time.Since(p.startTime)
+	pprof.StopCPUProfile()
+	var memAfter runtime.MemStats
+	runtime.ReadMemStats(&memAfter)
+	runtime.GC()
+	var allocs bytes.Buffer
+	if err := pprof.Lookup("allocs").WriteTo(&allocs, 0); err != nil {
+		p.stop(eris.Wrap(err, "failed to write allocation profile"))
+		return
+	}
+
+	p.current.Tick = tick
+	p.current.Duration = duration
+	p.current.CPU = p.cpu.Bytes()
+	p.current.AllocsAfter = allocs.Bytes()
+	p.current.AllocBytes = memAfter.TotalAlloc - p.memBefore.TotalAlloc
+	p.current.Allocs = memAfter.Mallocs - p.memBefore.Mallocs
+	p.profiles = append(p.profiles, p.current)
+	p.remaining--
+}
+
+// stop stops profiling because of err.
+func (p *tickProfiler) stop(err error) {
+	p.remaining, p.err = 0, err
+}
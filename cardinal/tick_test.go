@@ -1,12 +1,20 @@
 package cardinal_test
 
 import (
+	"context"
 	"testing"
 
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
 	"pkg.world.dev/world-engine/assert"
 	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/persona/msg"
 	"pkg.world.dev/world-engine/cardinal/search/filter"
 	"pkg.world.dev/world-engine/cardinal/testutils"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+	"pkg.world.dev/world-engine/sign"
 )
 
 type ScalarComponentAlpha struct {
@@ -37,6 +45,7 @@ func TestTickHappyPath(t *testing.T) {
 
 	assert.Equal(t, uint64(10), world1.CurrentTick())
 
+	tf1.Shutdown()
 	tf2 := testutils.NewTestFixture(t, tf1.Redis)
 	world2 := tf2.World
 	assert.NilError(t, cardinal.RegisterComponent[EnergyComponent](world2))
@@ -83,3 +92,69 @@ func TestCanModifyArchetypeAndGetEntity(t *testing.T) {
 	assert.NilError(t, cardinal.RemoveComponentFrom[Beta](wCtx, wantID))
 	verifyCanFindEntity()
 }
+
+func TestTimestampIsRestoredAfterRestart(t *testing.T) {
+	tf1 := testutils.NewTestFixture(t, nil)
+	var seen []uint64
+	recordTimestamp := func(wCtx engine.Context) error {
+		seen = append(seen, wCtx.Timestamp())
+		return nil
+	}
+	assert.NilError(t, cardinal.RegisterSystems(tf1.World, recordTimestamp, func(wCtx engine.Context) error {
+		return recordTimestamp(wCtx)
+	}))
+	tf1.DoTick()
+
+	// Every system in the tick must see the same timestamp.
+	assert.Equal(t, 2, len(seen))
+	assert.Equal(t, seen[0], seen[1])
+	assert.Check(t, seen[0] > 0)
+
+	tf1.Shutdown()
+	tf2 := testutils.NewTestFixture(t, tf1.Redis)
+	tf2.StartWorld()
+	assert.Equal(t, seen[0], cardinal.NewWorldContext(tf2.World).Timestamp())
+}
+
+func TestTickSpanLinksToSubmittedTransactions(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	tf := testutils.NewTestFixture(t, nil)
+	world := tf.World
+	assert.NilError(t, cardinal.RegisterComponent[Health](world))
+	assert.NilError(t, cardinal.RegisterSystems(world, HealthSystem))
+	tf.DoTick()
+
+	createPersona, ok := world.GetMessageByFullName("persona." + msg.CreatePersonaMessageName)
+	assert.Assert(t, ok)
+	ctx, submitSpan := otel.Tracer("test").Start(context.Background(), "submit")
+	world.AddTransactionWithContext(ctx, createPersona.ID(), msg.CreatePersona{
+		PersonaTag:    "foo",
+		SignerAddress: "bar",
+	}, &sign.Transaction{PersonaTag: "foo"})
+	submitSpan.End()
+	tf.DoTick()
+
+	var tickSpan sdktrace.ReadOnlySpan
+	for _, span := range recorder.Ended() {
+		if span.Name() == "cardinal.tick" {
+			tickSpan = span
+		}
+	}
+	assert.Assert(t, tickSpan != nil)
+	assert.Equal(t, 1, len(tickSpan.Links()))
+	assert.Equal(t, submitSpan.SpanContext().SpanID(), tickSpan.Links()[0].SpanContext.SpanID())
+
+	// Systems and storage operations are children of the tick span.
+	children := map[string]bool{}
+	for _, span := range recorder.Ended() {
+		if span.Parent().SpanID() == tickSpan.SpanContext().SpanID() {
+			children[span.Name()] = true
+		}
+	}
+	assert.Assert(t, children["cardinal.system"])
+	assert.Assert(t, children["cardinal.storage.finalize"])
+}
@@ -0,0 +1,95 @@
+// Package tracing emits OpenTelemetry traces of Cardinal's game loop: a span for every tick, a child span for every
+// system and storage operation of the tick, and a span for every submitted transaction. The span of a tick links to
+// the spans of the transactions it executed, so a slow tick can be traced back to the requests that caused it.
+//
+// Until Init is called, all spans are recorded by OpenTelemetry's global no-op tracer provider.
+package tracing
+
+import (
+	"context"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rotisserie/eris"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	instrumentationName = "pkg.world.dev/world-engine/cardinal"
+	serviceName         = "cardinal"
+)
+
+// Tracer returns the tracer all of Cardinal's spans are started with.
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// Init exports spans over OTLP/gRPC to the collector at the given address (e.g. an OpenTelemetry collector or a
+// DataDog agent with OTLP ingestion enabled). The tags have the same "<key>:<value>" format as statsd tags and are
+// added to every span. The returned function flushes the spans that have not been exported yet and must be called
+// on shutdown.
+func Init(ctx context.Context, otlpAddress string, tags []string) (func(context.Context) error, error) {
+	exporter, err := otlptracegrpc.New(ctx,
+		otlptracegrpc.WithEndpoint(otlpAddress),
+		otlptracegrpc.WithInsecure(),
+	)
+	if err != nil {
+		return nil, eris.Wrap(err, "failed to create OTLP trace exporter")
+	}
+
+	attrs := []attribute.KeyValue{semconv.ServiceName(serviceName)}
+	for _, tag := range tags {
+		key, value, _ := strings.Cut(tag, ":")
+		attrs = append(attrs, attribute.String(key, value))
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(attrs...)),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+	return provider.Shutdown, nil
+}
+
+// ExtractHTTPContext is a fiber middleware that continues the trace of the client that sent the request, if the
+// request carries a W3C trace context. Handlers start their spans from ctx.UserContext().
+func ExtractHTTPContext(ctx *fiber.Ctx) error {
+	carrier := propagation.MapCarrier{}
+	ctx.Request().Header.VisitAll(func(key, value []byte) {
+		carrier.Set(strings.ToLower(string(key)), string(value))
+	})
+	ctx.SetUserContext(otel.GetTextMapPropagator().Extract(ctx.UserContext(), carrier))
+	return ctx.Next()
+}
+
+// LinkTo returns links to the given span contexts, skipping the ones that are not valid.
+func LinkTo(spanContexts ...trace.SpanContext) []trace.Link {
+	links := make([]trace.Link, 0, len(spanContexts))
+	for _, sc := range spanContexts {
+		if sc.IsValid() {
+			links = append(links, trace.Link{SpanContext: sc})
+		}
+	}
+	return links
+}
+
+// End ends the span, recording err on it if it is not nil.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
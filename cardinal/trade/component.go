@@ -0,0 +1,58 @@
+// Package trade contains the components, messages and queries of Cardinal's trade plugin, which lets two personas
+// swap items and currency. A persona proposes a trade to a counterparty, the counterparty accepts it within a number
+// of ticks, and the items and currency of both sides change hands in the same tick. Items are entities owned by a
+// persona (see cardinal.ClaimEntity) and currency is held in the wallets of the wallet plugin. What the proposer offers
+// is held in escrow until the trade is accepted, cancelled or expires, so it can't be traded or spent twice. The
+// counterparty's side is checked when the trade is accepted.
+//
+// The plugin is registered with cardinal.NewTradePlugin.
+package trade
+
+import (
+	"strconv"
+
+	"pkg.world.dev/world-engine/cardinal/types"
+)
+
+const (
+	StatusPending   = "pending"
+	StatusCompleted = "completed"
+)
+
+// EscrowOwner is the owner of the items held in escrow for the trade with the given ID (see cardinal.GetEntityOwner).
+// Persona tags can't contain slashes, so it is never the tag of a persona.
+func EscrowOwner(tradeID types.EntityID) string {
+	return "trade-escrow/" + strconv.FormatUint(uint64(tradeID), 10)
+}
+
+// Offer is what one side of a trade gives to the other side.
+type Offer struct {
+	Items    []types.EntityID  `json:"items"`
+	Currency map[string]uint64 `json:"currency"`
+}
+
+// Trade is a trade that Proposer proposed to Counterparty. A pending trade is removed when it is cancelled or
+// expires, and its escrow is returned to the proposer. A completed trade is kept as the receipt of the trade for both
+// personas.
+type Trade struct {
+	Proposer     string `json:"proposer"`
+	Counterparty string `json:"counterparty"`
+	// Give is what the proposer gives to the counterparty.
+	Give Offer `json:"give"`
+	// Want is what the counterparty gives to the proposer.
+	Want Offer `json:"want"`
+	// ExpiresAt is the last tick in which the trade can be accepted.
+	ExpiresAt uint64 `json:"expiresAt"`
+	Status    string `json:"status"`
+	// CompletedAt is the tick in which the trade was accepted.
+	CompletedAt uint64 `json:"completedAt"`
+}
+
+func (Trade) Name() string {
+	return "Trade"
+}
+
+// Involves returns true if the persona tag is one of the two sides of the trade.
+func (t Trade) Involves(personaTag string) bool {
+	return samePersona(t.Proposer, personaTag) || samePersona(t.Counterparty, personaTag)
+}
@@ -0,0 +1,20 @@
+package trade
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	ErrTradeNotFound       = errors.New("trade not found")
+	ErrTradeExpired        = errors.New("trade has expired")
+	ErrNotOwner            = errors.New("item is not owned by the persona")
+	ErrInsufficientBalance = errors.New("insufficient balance")
+	ErrNotParty            = errors.New("persona is not a party of the trade")
+	ErrInvalidOffer        = errors.New("invalid offer")
+)
+
+// samePersona compares persona tags the same way the persona plugin does, which is case-insensitively.
+func samePersona(a, b string) bool {
+	return strings.EqualFold(a, b)
+}
@@ -0,0 +1,58 @@
+package trade
+
+import (
+	"pkg.world.dev/world-engine/cardinal/types"
+)
+
+const (
+	ProposeTradeMessageName = "propose-trade"
+	AcceptTradeMessageName  = "accept-trade"
+	CancelTradeMessageName  = "cancel-trade"
+)
+
+// ProposeTrade proposes a trade to the counterparty. The persona that signs the message is the proposer.
+type ProposeTrade struct {
+	Counterparty string `json:"counterparty"`
+	Give         Offer  `json:"give"`
+	Want         Offer  `json:"want"`
+	// AcceptWithinTicks is the number of ticks the counterparty has to accept the trade. It is capped by the accept
+	// window of the plugin, which is also used if it is 0.
+	AcceptWithinTicks uint64 `json:"acceptWithinTicks"`
+}
+
+type ProposeTradeResult struct {
+	TradeID   types.EntityID `json:"tradeID"`
+	ExpiresAt uint64         `json:"expiresAt"`
+}
+
+// AcceptTrade accepts a trade. It must be signed by the counterparty of the trade.
+type AcceptTrade struct {
+	TradeID types.EntityID `json:"tradeID"`
+}
+
+type AcceptTradeResult struct {
+	Receipt Receipt `json:"receipt"`
+}
+
+// CancelTrade cancels a pending trade. It can be signed by either side of the trade, so the counterparty can use it
+// to decline a trade.
+type CancelTrade struct {
+	TradeID types.EntityID `json:"tradeID"`
+}
+
+type CancelTradeResult struct {
+	Success bool `json:"success"`
+}
+
+// Receipt describes a completed trade. It is the result of the accept-trade message and is emitted as a
+// "trade-completed" event, so that both personas learn about the trade.
+type Receipt struct {
+	TradeID      types.EntityID `json:"tradeID"`
+	Tick         uint64         `json:"tick"`
+	Proposer     string         `json:"proposer"`
+	Counterparty string         `json:"counterparty"`
+	// ProposerReceived is what the proposer received, i.e. the Want offer of the trade.
+	ProposerReceived Offer `json:"proposerReceived"`
+	// CounterpartyReceived is what the counterparty received, i.e. the Give offer of the trade.
+	CounterpartyReceived Offer `json:"counterpartyReceived"`
+}
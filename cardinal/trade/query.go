@@ -0,0 +1,23 @@
+package trade
+
+import (
+	"pkg.world.dev/world-engine/cardinal/types"
+)
+
+const ListTradesQueryName = "list"
+
+// ListTradesRequest is the request body of the trade list query.
+type ListTradesRequest struct {
+	PersonaTag string `json:"personaTag"`
+	// Status filters the trades by status. All trades are returned if it is empty.
+	Status string `json:"status"`
+}
+
+type ListTradesResponse struct {
+	Trades []TradeInfo `json:"trades"`
+}
+
+type TradeInfo struct {
+	ID    types.EntityID `json:"id"`
+	Trade Trade          `json:"trade"`
+}
@@ -0,0 +1,206 @@
+package cardinal
+
+import (
+	"encoding/json"
+	"slices"
+
+	"github.com/rotisserie/eris"
+	"github.com/rs/zerolog/log"
+
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+	"pkg.world.dev/world-engine/cardinal/worldstage"
+)
+
+// MaxTriggerRounds is the number of times triggers are evaluated in a single tick. Trigger handlers can change
+// components that other triggers watch, so the changes they make are evaluated again, up to this many times.
+const MaxTriggerRounds = 8
+
+// TriggerHandler is called when a trigger fires, with the entity and the component value that satisfied the condition.
+type TriggerHandler[T types.Component] func(wCtx engine.Context, id types.EntityID, value T) error
+
+// trigger is a type-erased trigger registered with RegisterTrigger.
+type trigger struct {
+	name   string
+	cType  types.ComponentMetadata
+	fires  func(before, after any) (bool, error)
+	handle func(wCtx engine.Context, id types.EntityID, value any) error
+}
+
+// componentChange is a component of an entity that changed during the tick.
+type componentChange struct {
+	// before is the encoded value of the component before its first change in the tick. It is nil if the component
+	// was added during the tick.
+	before json.RawMessage
+}
+
+// triggerManager tracks the changes of the components that triggers watch, and runs the triggers at the end of the
+// tick.
+type triggerManager struct {
+	triggers []trigger
+	watched  map[types.ComponentID]bool
+	changes  map[types.ComponentID]map[types.EntityID]componentChange
+}
+
+func newTriggerManager() *triggerManager {
+	return &triggerManager{
+		watched: map[types.ComponentID]bool{},
+		changes: map[types.ComponentID]map[types.EntityID]componentChange{},
+	}
+}
+
+// RegisterTrigger registers a trigger that fires when a component value starts satisfying the given condition, e.g.
+// when Health drops to 0 or when a city's population exceeds 1000. Triggers are evaluated at the end of every tick
+// against the components that changed during the tick, so games don't need systems that scan all entities for
+// threshold crossings.
+//
+// A trigger fires once when the condition goes from false to true, and again only after the condition has been false.
+// A component that is added to an entity (or an entity that is created) with a value that satisfies the condition also
+// fires. Triggers run in the order they are registered, for entities in increasing ID order, so they are deterministic.
+func RegisterTrigger[T types.Component](
+	w *World, name string, condition func(T) bool, handler TriggerHandler[T],
+) error {
+	if w.worldStage.Current() != worldstage.Init {
+		return eris.Errorf(
+			"world state is %s, expected %s to register triggers",
+			w.worldStage.Current(),
+			worldstage.Init,
+		)
+	}
+	var t T
+	c, err := w.GetComponentByName(t.Name())
+	if err != nil {
+		return eris.Wrapf(err, "trigger %q watches a component that is not registered", name)
+	}
+	for _, existing := range w.triggers.triggers {
+		if existing.name == name {
+			return eris.Errorf("trigger %q is already registered", name)
+		}
+	}
+
+	satisfied := func(value any) (bool, error) {
+		comp, err := componentValue[T](value)
+		if err != nil {
+			return false, err
+		}
+		return condition(*comp), nil
+	}
+	w.triggers.triggers = append(w.triggers.triggers, trigger{
+		name:  name,
+		cType: c,
+		fires: func(before, after any) (bool, error) {
+			if before != nil {
+				wasSatisfied, err := satisfied(before)
+				if err != nil || wasSatisfied {
+					return false, err
+				}
+			}
+			return satisfied(after)
+		},
+		handle: func(wCtx engine.Context, id types.EntityID, value any) error {
+			comp, err := componentValue[T](value)
+			if err != nil {
+				return err
+			}
+			return handler(wCtx, id, *comp)
+		},
+	})
+	w.triggers.watched[c.ID()] = true
+	return nil
+}
+
+// componentValue converts a value from the entity store to the component type.
+func componentValue[T types.Component](value any) (*T, error) {
+	switch v := value.(type) {
+	case T:
+		return &v, nil
+	case *T:
+		return v, nil
+	default:
+		var t T
+		return nil, eris.Errorf("value of component %q has unexpected type %T", t.Name(), value)
+	}
+}
+
+// trackComponentChange records that the given component of the entity is about to change. Only the first change in a
+// tick is recorded, so triggers compare the value at the start of the tick with the value at the end of it.
+func (w *World) trackComponentChange(cType types.ComponentMetadata, id types.EntityID, added bool) error {
+	m := w.triggers
+	if !m.watched[cType.ID()] {
+		return nil
+	}
+	changed, ok := m.changes[cType.ID()]
+	if !ok {
+		changed = map[types.EntityID]componentChange{}
+		m.changes[cType.ID()] = changed
+	}
+	if _, ok := changed[id]; ok {
+		return nil
+	}
+	var change componentChange
+	if !added {
+		// The value is encoded, since systems can modify the stored component in place.
+		before, err := w.entityStore.GetComponentForEntityInRawJSON(cType, id)
+		if err != nil {
+			return err
+		}
+		change.before = before
+	}
+	changed[id] = change
+	return nil
+}
+
+// runTriggers runs the triggers whose condition became true during the tick.
+func (w *World) runTriggers(wCtx engine.Context) error {
+	m := w.triggers
+	for round := 0; len(m.changes) > 0; round++ {
+		if round == MaxTriggerRounds {
+			log.Error().Int("rounds", MaxTriggerRounds).Msg("triggers kept changing watched components, " +
+				"the remaining changes are not evaluated")
+			clear(m.changes)
+			return nil
+		}
+		changes := m.changes
+		m.changes = map[types.ComponentID]map[types.EntityID]componentChange{}
+		for _, t := range m.triggers {
+			if err := w.runTrigger(wCtx, t, changes[t.cType.ID()]); err != nil {
+				return eris.Wrapf(err, "trigger %s generated an error", t.name)
+			}
+		}
+	}
+	return nil
+}
+
+func (w *World) runTrigger(
+	wCtx engine.Context, t trigger, changed map[types.EntityID]componentChange,
+) error {
+	ids := make([]types.EntityID, 0, len(changed))
+	for id := range changed {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	for _, id := range ids {
+		after, err := w.entityStore.GetComponentForEntity(t.cType, id)
+		if err != nil {
+			// The entity or the component was removed during the tick.
+			continue
+		}
+		var before any
+		if bz := changed[id].before; bz != nil {
+			if before, err = t.cType.Decode(bz); err != nil {
+				return err
+			}
+		}
+		fires, err := t.fires(before, after)
+		if err != nil {
+			return err
+		}
+		if !fires {
+			continue
+		}
+		if err = t.handle(wCtx, id, after); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package cardinal_test
+
+import (
+	"testing"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/testutils"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+func TestTriggerFiresWhenThresholdIsCrossed(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil)
+	world := tf.World
+	assert.NilError(t, cardinal.RegisterComponent[Health](world))
+	assert.NilError(t, cardinal.RegisterSystems(world, HealthSystem))
+
+	var fired []types.EntityID
+	assert.NilError(t, cardinal.RegisterTrigger[Health](world, "healed", func(h Health) bool {
+		return h.Value >= 3
+	}, func(wCtx engine.Context, id types.EntityID, h Health) error {
+		fired = append(fired, id)
+		return wCtx.EmitEvent(map[string]any{"healed": id, "health": h.Value})
+	}))
+
+	tf.StartWorld()
+	wCtx := cardinal.NewWorldContext(world)
+	weak, err := cardinal.Create(wCtx, Health{Value: 0})
+	assert.NilError(t, err)
+	strong, err := cardinal.Create(wCtx, Health{Value: 10})
+	assert.NilError(t, err)
+
+	// The strong entity is created above the threshold, and the weak one reaches it after three ticks.
+	tf.DoTick()
+	assert.DeepEqual(t, []types.EntityID{strong}, fired)
+	tf.DoTick()
+	tf.DoTick()
+	assert.DeepEqual(t, []types.EntityID{strong, weak}, fired)
+
+	// Staying above the threshold does not fire again, until the value drops below it and crosses it again.
+	tf.DoTick()
+	assert.Equal(t, 2, len(fired))
+	assert.NilError(t, cardinal.SetComponent[Health](wCtx, weak, &Health{Value: 0}))
+	tf.DoTick()
+	tf.DoTick()
+	tf.DoTick()
+	assert.DeepEqual(t, []types.EntityID{strong, weak, weak}, fired)
+}
+
+func TestTriggerRequiresRegisteredComponent(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil)
+	err := cardinal.RegisterTrigger[Health](tf.World, "healed", func(Health) bool { return true },
+		func(engine.Context, types.EntityID, Health) error { return nil })
+	assert.IsError(t, err)
+}
@@ -18,6 +18,13 @@ type Component interface {
 	Name() string
 }
 
+// ComponentDefaulter can be implemented by components that start with other values than their zero value, e.g. a
+// Health component that starts at 100. Components that are created with their zero value, or added to an entity with
+// AddComponentTo, are initialized with Default() instead.
+type ComponentDefaulter[T Component] interface {
+	Default() T
+}
+
 // ComponentMetadata wraps the user-defined Component struct and provides functionalities that is used internally
 // in the engine.
 type ComponentMetadata interface { //revive:disable-line:exported
@@ -27,8 +34,18 @@ type ComponentMetadata interface { //revive:disable-line:exported
 	ID() ComponentID
 	// New returns the marshaled bytes of the default value for the component struct.
 	New() ([]byte, error)
+	// DefaultValue returns the default value for the component struct, and false if it has no default value.
+	DefaultValue() (Component, bool)
 	Encode(any) ([]byte, error)
 	Decode([]byte) (Component, error)
+	// DecodeFields decodes only the given fields of the component, and leaves all other fields at their zero value.
+	DecodeFields(bz []byte, fields []string) (Component, error)
+	// EncodeFor, DecodeFor and DecodeFieldsFor are like Encode, Decode and DecodeFields for the stored value of the
+	// component of the given entity. Encrypted values are bound to the component and the entity, so a value that is
+	// copied to another entity can't be decoded.
+	EncodeFor(id EntityID, v any) ([]byte, error)
+	DecodeFor(id EntityID, bz []byte) (Component, error)
+	DecodeFieldsFor(id EntityID, bz []byte, fields []string) (Component, error)
 	GetSchema() []byte
 	ValidateAgainstSchema(targetSchema []byte) error
 
@@ -0,0 +1,15 @@
+package types
+
+import "errors"
+
+var (
+	// ErrCoSignedTransactionNotFound is returned when a co-signature is added to a transaction that is not waiting for
+	// co-signatures, because it is unknown, complete, or timed out.
+	ErrCoSignedTransactionNotFound = errors.New("co-signed transaction not found")
+	// ErrCoSignedTransactionExpired is returned when a co-signature is added to a transaction that expired while it was
+	// waiting for its co-signers. The transaction is dropped.
+	ErrCoSignedTransactionExpired = errors.New("co-signed transaction expired")
+	// ErrCoSignerBanned is returned when a co-signed transaction is completed, but its signer or one of its co-signers
+	// was banned while it was waiting for its co-signers. The transaction is dropped.
+	ErrCoSignerBanned = errors.New("a signer of the co-signed transaction is banned")
+)
@@ -1,6 +1,7 @@
 package engine
 
 import (
+	"math/rand/v2"
 	"reflect"
 
 	"github.com/rs/zerolog"
@@ -14,7 +15,8 @@ import (
 
 //go:generate mockgen -source=context.go -package mocks -destination=mocks/context.go
 type Context interface {
-	// Timestamp returns the UNIX timestamp of the tick.
+	// Timestamp returns the UNIX timestamp recorded at the start of the tick. Use this instead of time.Now so that every
+	// system in the tick sees the same time and replays produce identical results.
 	Timestamp() uint64
 	// CurrentTick returns the current tick.
 	CurrentTick() uint64
@@ -25,8 +27,21 @@ type Context interface {
 	// EmitStringEvent emits a string event that will be broadcast to all websocket subscribers.
 	// This method is provided for backwards compatability. EmitEvent should be used for most cases.
 	EmitStringEvent(string) error
+	// EmitToEVM sends a message to the EVM contract at the given hex address. The message is committed with the tick
+	// and is delivered to the base shard's router at least once, after the tick completes.
+	EmitToEVM(contract string, payload []byte) error
+	// EmitInternal passes a message to the systems that run after the current one in the same tick, which read it with
+	// cardinal.InternalMessages. Internal messages are neither persisted nor sent to clients, and are dropped at the end
+	// of the tick.
+	EmitInternal(msg any) error
 	// Namespace returns the namespace of the world.
 	Namespace() string
+	// Rand returns a deterministic PRNG seeded from the world seed, the current tick and the name of the running
+	// system. Use this instead of math/rand so that randomness is reproducible when a tick is replayed.
+	Rand() *rand.Rand
+	// GetOwnedEntities returns the live entities owned by the given persona, in ascending order. Entities are owned when
+	// they are created with cardinal.CreateForPersona or claimed with cardinal.ClaimEntity.
+	GetOwnedEntities(personaTag string) ([]types.EntityID, error)
 
 	// For internal use.
 
@@ -40,7 +55,40 @@ type Context interface {
 	GetSignerForPersonaTag(personaTag string, tick uint64) (addr string, err error)
 	GetTransactionReceiptsForTick(tick uint64) ([]receipt.Receipt, error)
 	ReceiptHistorySize() uint64
-	AddTransaction(id types.MessageID, v any, sig *sign.Transaction) (uint64, types.TxHash)
+	// ReserveEntityQuota records that the running system is about to create num entities with a total of components
+	// components (owned by personaTag, if it is not empty), and fails if this would exceed the world's entity quota.
+	ReserveEntityQuota(personaTag string, num, components int) error
+	// ReservePersonaEntityQuota records that personaTag is about to claim num existing entities, and fails if this would
+	// exceed the world's entity quota.
+	ReservePersonaEntityQuota(personaTag string, num int) error
+	// ReleaseEntityQuota records that num entities with a total of components components have been removed.
+	ReleaseEntityQuota(num, components int) error
+	// CancelEntityQuota gives back a reservation made with ReserveEntityQuota whose entities could not be created after
+	// all, including their share of the quota of the running system.
+	CancelEntityQuota(num, components int) error
+	// TrackComponentChange records that the given component of the entity is about to be set, or has just been added,
+	// so that the triggers watching the component are evaluated at the end of the tick.
+	TrackComponentChange(cType types.ComponentMetadata, id types.EntityID, added bool) error
+	// IndexComponent records the value that the given component of the entity is about to be set to in the unique and
+	// field indexes of the component, or removes the entity from them if value is nil. It fails without changing
+	// anything if another entity already has the same key in one of the unique indexes.
+	IndexComponent(cType types.ComponentMetadata, id types.EntityID, value any) error
+	// FindByField returns the entities, in ascending order, whose field of the given component is equal to value,
+	// using the index of the field. See cardinal.RegisterFieldIndex.
+	FindByField(cType types.ComponentMetadata, field string, value any) ([]types.EntityID, error)
+	// DestroyEntity records that the entity is to be removed at the end of the tick. See cardinal.Destroy.
+	DestroyEntity(id types.EntityID)
+	// IsEntityDestroyed returns true if the entity is to be removed at the end of the tick.
+	IsEntityDestroyed(id types.EntityID) bool
+	// RecordSearch records that a search evaluated by the running system matched the given number of archetypes and
+	// visited the given number of entities. See cardinal.WithSystemBudget.
+	RecordSearch(archetypes, entities int)
+	// RecordQueryPattern records that a search evaluated by the running system filters on the given components. See
+	// cardinal.WithLayoutProfiling.
+	RecordQueryPattern(components []string)
+	// InternalMessages returns the internal messages of the given type that were emitted in the current tick.
+	InternalMessages(typ reflect.Type) []any
+	AddTransaction(id types.MessageID, v any, sig *sign.Transaction) (uint64, types.TxHash, error)
 	IsWorldReady() bool
 	StoreReader() gamestate.Reader
 	StoreManager() gamestate.Manager
@@ -5,6 +5,7 @@
 package mocks
 
 import (
+	rand "math/rand/v2"
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
@@ -52,12 +53,13 @@ func (mr *MockContextMockRecorder) AddMessageError(id, err interface{}) *gomock.
 }
 
 // AddTransaction mocks base method.
-func (m *MockContext) AddTransaction(id types.MessageID, v any, sig *sign.Transaction) (uint64, types.TxHash) {
+func (m *MockContext) AddTransaction(id types.MessageID, v any, sig *sign.Transaction) (uint64, types.TxHash, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "AddTransaction", id, v, sig)
 	ret0, _ := ret[0].(uint64)
 	ret1, _ := ret[1].(types.TxHash)
-	return ret0, ret1
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
 }
 
 // AddTransaction indicates an expected call of AddTransaction.
@@ -66,6 +68,20 @@ func (mr *MockContextMockRecorder) AddTransaction(id, v, sig interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddTransaction", reflect.TypeOf((*MockContext)(nil).AddTransaction), id, v, sig)
 }
 
+// CancelEntityQuota mocks base method.
+func (m *MockContext) CancelEntityQuota(num, components int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelEntityQuota", num, components)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelEntityQuota indicates an expected call of CancelEntityQuota.
+func (mr *MockContextMockRecorder) CancelEntityQuota(num, components interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelEntityQuota", reflect.TypeOf((*MockContext)(nil).CancelEntityQuota), num, components)
+}
+
 // CurrentTick mocks base method.
 func (m *MockContext) CurrentTick() uint64 {
 	m.ctrl.T.Helper()
@@ -80,6 +96,18 @@ func (mr *MockContextMockRecorder) CurrentTick() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CurrentTick", reflect.TypeOf((*MockContext)(nil).CurrentTick))
 }
 
+// DestroyEntity mocks base method.
+func (m *MockContext) DestroyEntity(id types.EntityID) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "DestroyEntity", id)
+}
+
+// DestroyEntity indicates an expected call of DestroyEntity.
+func (mr *MockContextMockRecorder) DestroyEntity(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DestroyEntity", reflect.TypeOf((*MockContext)(nil).DestroyEntity), id)
+}
+
 // EmitEvent mocks base method.
 func (m *MockContext) EmitEvent(arg0 map[string]any) error {
 	m.ctrl.T.Helper()
@@ -94,6 +122,20 @@ func (mr *MockContextMockRecorder) EmitEvent(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EmitEvent", reflect.TypeOf((*MockContext)(nil).EmitEvent), arg0)
 }
 
+// EmitInternal mocks base method.
+func (m *MockContext) EmitInternal(msg any) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EmitInternal", msg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EmitInternal indicates an expected call of EmitInternal.
+func (mr *MockContextMockRecorder) EmitInternal(msg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EmitInternal", reflect.TypeOf((*MockContext)(nil).EmitInternal), msg)
+}
+
 // EmitStringEvent mocks base method.
 func (m *MockContext) EmitStringEvent(arg0 string) error {
 	m.ctrl.T.Helper()
@@ -108,6 +150,35 @@ func (mr *MockContextMockRecorder) EmitStringEvent(arg0 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EmitStringEvent", reflect.TypeOf((*MockContext)(nil).EmitStringEvent), arg0)
 }
 
+// EmitToEVM mocks base method.
+func (m *MockContext) EmitToEVM(contract string, payload []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EmitToEVM", contract, payload)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EmitToEVM indicates an expected call of EmitToEVM.
+func (mr *MockContextMockRecorder) EmitToEVM(contract, payload interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EmitToEVM", reflect.TypeOf((*MockContext)(nil).EmitToEVM), contract, payload)
+}
+
+// FindByField mocks base method.
+func (m *MockContext) FindByField(cType types.ComponentMetadata, field string, value any) ([]types.EntityID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByField", cType, field, value)
+	ret0, _ := ret[0].([]types.EntityID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByField indicates an expected call of FindByField.
+func (mr *MockContextMockRecorder) FindByField(cType, field, value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByField", reflect.TypeOf((*MockContext)(nil).FindByField), cType, field, value)
+}
+
 // GetComponentByName mocks base method.
 func (m *MockContext) GetComponentByName(name string) (types.ComponentMetadata, error) {
 	m.ctrl.T.Helper()
@@ -138,6 +209,21 @@ func (mr *MockContextMockRecorder) GetMessageByType(mType interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMessageByType", reflect.TypeOf((*MockContext)(nil).GetMessageByType), mType)
 }
 
+// GetOwnedEntities mocks base method.
+func (m *MockContext) GetOwnedEntities(personaTag string) ([]types.EntityID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOwnedEntities", personaTag)
+	ret0, _ := ret[0].([]types.EntityID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOwnedEntities indicates an expected call of GetOwnedEntities.
+func (mr *MockContextMockRecorder) GetOwnedEntities(personaTag interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOwnedEntities", reflect.TypeOf((*MockContext)(nil).GetOwnedEntities), personaTag)
+}
+
 // GetSignerForPersonaTag mocks base method.
 func (m *MockContext) GetSignerForPersonaTag(personaTag string, tick uint64) (string, error) {
 	m.ctrl.T.Helper()
@@ -198,6 +284,48 @@ func (mr *MockContextMockRecorder) GetTxPool() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTxPool", reflect.TypeOf((*MockContext)(nil).GetTxPool))
 }
 
+// IndexComponent mocks base method.
+func (m *MockContext) IndexComponent(cType types.ComponentMetadata, id types.EntityID, value any) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IndexComponent", cType, id, value)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// IndexComponent indicates an expected call of IndexComponent.
+func (mr *MockContextMockRecorder) IndexComponent(cType, id, value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IndexComponent", reflect.TypeOf((*MockContext)(nil).IndexComponent), cType, id, value)
+}
+
+// InternalMessages mocks base method.
+func (m *MockContext) InternalMessages(typ reflect.Type) []any {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InternalMessages", typ)
+	ret0, _ := ret[0].([]any)
+	return ret0
+}
+
+// InternalMessages indicates an expected call of InternalMessages.
+func (mr *MockContextMockRecorder) InternalMessages(typ interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InternalMessages", reflect.TypeOf((*MockContext)(nil).InternalMessages), typ)
+}
+
+// IsEntityDestroyed mocks base method.
+func (m *MockContext) IsEntityDestroyed(id types.EntityID) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsEntityDestroyed", id)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsEntityDestroyed indicates an expected call of IsEntityDestroyed.
+func (mr *MockContextMockRecorder) IsEntityDestroyed(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsEntityDestroyed", reflect.TypeOf((*MockContext)(nil).IsEntityDestroyed), id)
+}
+
 // IsReadOnly mocks base method.
 func (m *MockContext) IsReadOnly() bool {
 	m.ctrl.T.Helper()
@@ -254,6 +382,20 @@ func (mr *MockContextMockRecorder) Namespace() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Namespace", reflect.TypeOf((*MockContext)(nil).Namespace))
 }
 
+// Rand mocks base method.
+func (m *MockContext) Rand() *rand.Rand {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rand")
+	ret0, _ := ret[0].(*rand.Rand)
+	return ret0
+}
+
+// Rand indicates an expected call of Rand.
+func (mr *MockContextMockRecorder) Rand() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rand", reflect.TypeOf((*MockContext)(nil).Rand))
+}
+
 // ReceiptHistorySize mocks base method.
 func (m *MockContext) ReceiptHistorySize() uint64 {
 	m.ctrl.T.Helper()
@@ -268,6 +410,72 @@ func (mr *MockContextMockRecorder) ReceiptHistorySize() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReceiptHistorySize", reflect.TypeOf((*MockContext)(nil).ReceiptHistorySize))
 }
 
+// RecordQueryPattern mocks base method.
+func (m *MockContext) RecordQueryPattern(components []string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RecordQueryPattern", components)
+}
+
+// RecordQueryPattern indicates an expected call of RecordQueryPattern.
+func (mr *MockContextMockRecorder) RecordQueryPattern(components interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordQueryPattern", reflect.TypeOf((*MockContext)(nil).RecordQueryPattern), components)
+}
+
+// RecordSearch mocks base method.
+func (m *MockContext) RecordSearch(archetypes, entities int) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RecordSearch", archetypes, entities)
+}
+
+// RecordSearch indicates an expected call of RecordSearch.
+func (mr *MockContextMockRecorder) RecordSearch(archetypes, entities interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordSearch", reflect.TypeOf((*MockContext)(nil).RecordSearch), archetypes, entities)
+}
+
+// ReleaseEntityQuota mocks base method.
+func (m *MockContext) ReleaseEntityQuota(num, components int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseEntityQuota", num, components)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReleaseEntityQuota indicates an expected call of ReleaseEntityQuota.
+func (mr *MockContextMockRecorder) ReleaseEntityQuota(num, components interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseEntityQuota", reflect.TypeOf((*MockContext)(nil).ReleaseEntityQuota), num, components)
+}
+
+// ReserveEntityQuota mocks base method.
+func (m *MockContext) ReserveEntityQuota(personaTag string, num, components int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReserveEntityQuota", personaTag, num, components)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReserveEntityQuota indicates an expected call of ReserveEntityQuota.
+func (mr *MockContextMockRecorder) ReserveEntityQuota(personaTag, num, components interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReserveEntityQuota", reflect.TypeOf((*MockContext)(nil).ReserveEntityQuota), personaTag, num, components)
+}
+
+// ReservePersonaEntityQuota mocks base method.
+func (m *MockContext) ReservePersonaEntityQuota(personaTag string, num int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReservePersonaEntityQuota", personaTag, num)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReservePersonaEntityQuota indicates an expected call of ReservePersonaEntityQuota.
+func (mr *MockContextMockRecorder) ReservePersonaEntityQuota(personaTag, num interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReservePersonaEntityQuota", reflect.TypeOf((*MockContext)(nil).ReservePersonaEntityQuota), personaTag, num)
+}
+
 // SetLogger mocks base method.
 func (m *MockContext) SetLogger(logger zerolog.Logger) {
 	m.ctrl.T.Helper()
@@ -333,3 +541,17 @@ func (mr *MockContextMockRecorder) Timestamp() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Timestamp", reflect.TypeOf((*MockContext)(nil).Timestamp))
 }
+
+// TrackComponentChange mocks base method.
+func (m *MockContext) TrackComponentChange(cType types.ComponentMetadata, id types.EntityID, added bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TrackComponentChange", cType, id, added)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TrackComponentChange indicates an expected call of TrackComponentChange.
+func (mr *MockContextMockRecorder) TrackComponentChange(cType, id, added interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TrackComponentChange", reflect.TypeOf((*MockContext)(nil).TrackComponentChange), cType, id, added)
+}
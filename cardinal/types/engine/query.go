@@ -22,4 +22,6 @@ type Query interface {
 	IsEVMCompatible() bool
 	// GetRequestFieldInformation returns a map of the fields of the query's request type and their types.
 	GetRequestFieldInformation() map[string]any
+	// GetReplyFieldInformation returns a map of the fields of the query's reply type and their types.
+	GetReplyFieldInformation() map[string]any
 }
@@ -0,0 +1,19 @@
+package types
+
+import "errors"
+
+var (
+	// ErrEventHistoryDisabled is returned when the event history is read from a world that does not record it.
+	ErrEventHistoryDisabled = errors.New("event history is not enabled")
+	// ErrEventsNotAvailable is returned when the events of a tick are read after they were pruned by the retention of
+	// the event history, or if the tick completed before the event history was enabled.
+	ErrEventsNotAvailable = errors.New("events of the tick are not available")
+)
+
+// TickEvents are the events that were emitted during a completed tick, in the order they were emitted.
+type TickEvents struct {
+	Tick uint64 `json:"tick"`
+	// Timestamp is the UNIX timestamp (in seconds) of the tick.
+	Timestamp uint64   `json:"timestamp"`
+	Events    [][]byte `json:"events"`
+}
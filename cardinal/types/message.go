@@ -1,5 +1,7 @@
 package types
 
+import "pkg.world.dev/world-engine/sign"
+
 type Message interface {
 	SetID(MessageID) error
 	Name() string
@@ -11,6 +13,9 @@ type Message interface {
 	ID() MessageID
 	Encode(any) ([]byte, error)
 	Decode([]byte) (any, error)
+	// DecodeForAPIVersion decodes a message that was sent to the given version of the HTTP API, upgrading it from its
+	// legacy input if the message has one for that version.
+	DecodeForAPIVersion(apiVersion string, bz []byte) (any, error)
 	// DecodeEVMBytes decodes ABI encoded bytes into the message's input type.
 	DecodeEVMBytes([]byte) (any, error)
 	// ABIEncode encodes the given type in ABI encoding, given that the input is the message type's input or output
@@ -18,9 +23,15 @@ type Message interface {
 	ABIEncode(any) ([]byte, error)
 	// IsEVMCompatible reports if this message can be sent from the EVM.
 	IsEVMCompatible() bool
+	// IsAdminOnly reports if this message must be signed by one of the world's admin signers.
+	IsAdminOnly() bool
+	// Authorize returns an error if the transaction of the decoded message is rejected by the message's authorizer.
+	Authorize(tx *sign.Transaction, msg any) error
 
 	// GetInFieldInformation returns a map of the fields of the message's "In" type and it's field types.
 	GetInFieldInformation() map[string]any
+	// GetLegacyInFieldInformation returns the fields of the message's legacy inputs, keyed by API version.
+	GetLegacyInFieldInformation() map[string]map[string]any
 }
 
 // MessageID represents a message's id.
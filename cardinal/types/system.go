@@ -0,0 +1,14 @@
+package types
+
+// SystemInfo describes a registered system and when it runs.
+type SystemInfo struct {
+	Name string `json:"name"`
+	// Init is true for systems that only run at tick 0.
+	Init bool `json:"init"`
+	// Every and Offset are the schedule of the system: it runs at the ticks whose remainder when divided by Every is
+	// Offset. Systems that run at every tick have an Every of 1.
+	Every  uint64 `json:"every"`
+	Offset uint64 `json:"offset"`
+	// Disabled is true for systems that are skipped until they are enabled again.
+	Disabled bool `json:"disabled"`
+}
@@ -1,3 +1,47 @@
 package types
 
+import "time"
+
 type TxHash string
+
+// Reasons for which a world does not accept new transactions.
+const (
+	// RetryReasonQueueFull means that the number of transactions waiting for the next tick reached its limit.
+	RetryReasonQueueFull = "queue-full"
+	// RetryReasonTickLag means that the ticks of the world are too far behind their schedule.
+	RetryReasonTickLag = "tick-lag"
+	// RetryReasonStorageOutage means that the world stopped ticking because its storage is unavailable.
+	RetryReasonStorageOutage = "storage-outage"
+)
+
+// RetryAfter tells the submitter of a transaction that the world is not accepting new transactions right now, because
+// more work is queued than it can keep up with, and when to submit the transaction again.
+type RetryAfter struct {
+	Reason string `json:"reason"`
+	// After is how long to wait before submitting the transaction again.
+	After time.Duration `json:"retryAfterNs"`
+	// QueuedTxs is the number of transactions waiting for the next tick, and MaxQueuedTxs is the number at which new
+	// transactions are rejected. QueueFill is QueuedTxs divided by MaxQueuedTxs. MaxQueuedTxs and QueueFill are 0 if
+	// the queue is not limited.
+	QueuedTxs    int     `json:"queuedTxs"`
+	MaxQueuedTxs int     `json:"maxQueuedTxs"`
+	QueueFill    float64 `json:"queueFill"`
+	// TickLag is how far the ticks are behind their schedule, and MaxTickLag is the lag at which new transactions are
+	// rejected. MaxTickLag is 0 if the lag is not limited.
+	TickLag    time.Duration `json:"tickLagNs"`
+	MaxTickLag time.Duration `json:"maxTickLagNs"`
+}
+
+// StorageHealth is the health of the storage of a world, as seen by its storage circuit breaker.
+type StorageHealth struct {
+	// Outage is true while the breaker is open, i.e. while the world doesn't tick because its storage failed.
+	Outage bool `json:"outage"`
+	// ConsecutiveErrors is the number of consecutive ticks that failed with a storage error, and LastError the error of
+	// the last one.
+	ConsecutiveErrors int    `json:"consecutiveErrors"`
+	LastError         string `json:"lastError,omitempty"`
+	// OutageSince is the time at which the breaker opened, and NextRetry the time at which the failed tick is run again.
+	// Both are zero unless Outage is true.
+	OutageSince time.Time `json:"outageSince"`
+	NextRetry   time.Time `json:"nextRetry"`
+}
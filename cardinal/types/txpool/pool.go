@@ -1,7 +1,12 @@
 package txpool
 
 import (
+	"context"
+	"slices"
 	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
 
 	"pkg.world.dev/world-engine/cardinal/types"
 	"pkg.world.dev/world-engine/sign"
@@ -16,6 +21,19 @@ type TxData struct {
 	Tx     *sign.Transaction
 	// EVMSourceTxHash is the tx hash of the EVM tx that triggered this tx.
 	EVMSourceTxHash string
+	// SpanContext is the span the transaction was submitted in. The span of the tick that executes the transaction
+	// links to it.
+	SpanContext trace.SpanContext
+	// AddedAt is the time at which the transaction was added to the pool.
+	AddedAt time.Time
+}
+
+// MessageStats are the stats of the pending transactions of a message. See TxPool.Stats.
+type MessageStats struct {
+	MsgID   types.MessageID
+	Pending int
+	// OldestAddedAt is the time at which the oldest pending transaction was added.
+	OldestAddedAt time.Time
 }
 
 type TxPool struct {
@@ -54,14 +72,25 @@ func (t *TxPool) GetEVMTxs() []TxData {
 }
 
 func (t *TxPool) AddTransaction(id types.MessageID, v any, sig *sign.Transaction) types.TxHash {
-	return t.addTransaction(id, v, sig, "")
+	return t.AddTransactionWithContext(context.Background(), id, v, sig)
+}
+
+// AddTransactionWithContext adds a transaction that was submitted in the span of ctx.
+func (t *TxPool) AddTransactionWithContext(
+	ctx context.Context, id types.MessageID, v any, sig *sign.Transaction,
+) types.TxHash {
+	return t.addTransaction(ctx, id, v, sig, "")
 }
 
-func (t *TxPool) AddEVMTransaction(id types.MessageID, v any, sig *sign.Transaction, evmTxHash string) types.TxHash {
-	return t.addTransaction(id, v, sig, evmTxHash)
+func (t *TxPool) AddEVMTransaction(
+	ctx context.Context, id types.MessageID, v any, sig *sign.Transaction, evmTxHash string,
+) types.TxHash {
+	return t.addTransaction(ctx, id, v, sig, evmTxHash)
 }
 
-func (t *TxPool) addTransaction(id types.MessageID, v any, sig *sign.Transaction, evmTxHash string) types.TxHash {
+func (t *TxPool) addTransaction(
+	ctx context.Context, id types.MessageID, v any, sig *sign.Transaction, evmTxHash string,
+) types.TxHash {
 	t.mux.Lock()
 	defer t.mux.Unlock()
 	txHash := types.TxHash(sig.HashHex())
@@ -71,6 +100,8 @@ func (t *TxPool) addTransaction(id types.MessageID, v any, sig *sign.Transaction
 		Msg:             v,
 		Tx:              sig,
 		EVMSourceTxHash: evmTxHash,
+		SpanContext:     trace.SpanContextFromContext(ctx),
+		AddedAt:         time.Now(),
 	})
 	t.txsInPool++
 	return txHash
@@ -89,11 +120,90 @@ func (t *TxPool) CopyTransactions() *TxPool {
 	return &cpy
 }
 
+// Clone returns a copy of the pool that doesn't share any state with it.
+func (t *TxPool) Clone() *TxPool {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	m := make(TxMap, len(t.m))
+	for id, txs := range t.m {
+		m[id] = slices.Clone(txs)
+	}
+	return &TxPool{m: m, txsInPool: t.txsInPool, mux: &sync.Mutex{}}
+}
+
 func (t *TxPool) reset() {
 	t.m = TxMap{}
 	t.txsInPool = 0
 }
 
+// Stats returns the stats of every message that has pending transactions.
+func (t *TxPool) Stats() []MessageStats {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	stats := make([]MessageStats, 0, len(t.m))
+	for id, txs := range t.m {
+		if len(txs) == 0 {
+			continue
+		}
+		// Transactions are appended, so the first one is the oldest
+		stats = append(stats, MessageStats{MsgID: id, Pending: len(txs), OldestAddedAt: txs[0].AddedAt})
+	}
+	return stats
+}
+
+// Drain removes the pending transactions of the given message from the pool and returns them.
+func (t *TxPool) Drain(id types.MessageID) []TxData {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	txs := t.m[id]
+	delete(t.m, id)
+	t.txsInPool -= len(txs)
+	return txs
+}
+
+// RemoveExpired removes the transactions that must not be executed in the given tick anymore from the pool, and
+// returns them. See sign.Transaction.ExpiresAtTick.
+func (t *TxPool) RemoveExpired(tick uint64) []TxData {
+	return t.RemoveFunc(func(tx TxData) bool {
+		return tx.Tx.IsExpired(tick)
+	})
+}
+
+// RemoveFunc removes the transactions for which remove returns true from the pool, and returns them.
+func (t *TxPool) RemoveFunc(remove func(tx TxData) bool) []TxData {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	var removed []TxData
+	for id, txs := range t.m {
+		kept := txs[:0]
+		for _, tx := range txs {
+			if remove(tx) {
+				removed = append(removed, tx)
+			} else {
+				kept = append(kept, tx)
+			}
+		}
+		if len(kept) == 0 {
+			delete(t.m, id)
+		} else {
+			t.m[id] = kept
+		}
+		t.txsInPool -= len(txs) - len(kept)
+	}
+	return removed
+}
+
 func (t *TxPool) ForID(id types.MessageID) []TxData {
 	return t.m[id]
 }
+
+// SpanContexts returns the spans the transactions in the pool were submitted in.
+func (t *TxPool) SpanContexts() []trace.SpanContext {
+	var spanContexts []trace.SpanContext
+	for _, txs := range t.m {
+		for _, tx := range txs {
+			spanContexts = append(spanContexts, tx.SpanContext)
+		}
+	}
+	return spanContexts
+}
@@ -0,0 +1,210 @@
+package cardinal
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+var (
+	ErrUnknownVariant      = errors.New("variant is not part of the union")
+	ErrNonExhaustiveMatch  = errors.New("match does not handle every variant of the union")
+	ErrInvalidUnionVariant = errors.New("invalid union variants")
+)
+
+// Union holds the value of exactly one variant of a closed set of components. It lets several variants (e.g. Weapon
+// = Sword|Bow|Staff) share one component slot, so entities with different variants stay in the same archetype instead
+// of each combination of optional components creating a new one.
+//
+// A union component is declared by embedding Union and listing its variants:
+//
+//	type Weapon struct{ cardinal.Union }
+//
+//	func (Weapon) Name() string { return "weapon" }
+//
+//	func (Weapon) Variants() []types.Component { return []types.Component{Sword{}, Bow{}, Staff{}} }
+type Union struct {
+	Variant string          `json:"variant"`
+	Value   json.RawMessage `json:"value"`
+}
+
+// UnionComponent is a component that embeds Union.
+type UnionComponent interface {
+	types.Component
+	// Variants returns the closed set of components that the union can hold. Variant names must be unique.
+	Variants() []types.Component
+	union() Union
+}
+
+// unionPointer is satisfied by pointers to types that embed Union.
+type unionPointer[U UnionComponent] interface {
+	*U
+	setUnion(u Union)
+}
+
+// VariantName returns the name of the variant that the union holds.
+func (u Union) VariantName() string {
+	return u.Variant
+}
+
+func (u Union) union() Union {
+	return u
+}
+
+func (u *Union) setUnion(v Union) {
+	*u = v
+}
+
+// RegisterUnion registers a union component. The variants of the union are not registered as components of their
+// own; they only exist inside the union's slot.
+func RegisterUnion[U UnionComponent](w *World) error {
+	var u U
+	if err := validateVariants(u); err != nil {
+		return err
+	}
+	return RegisterComponent[U](w)
+}
+
+// NewUnion returns a union component that holds the given variant.
+//
+// Usage:
+//
+//	weapon, err := cardinal.NewUnion[Weapon](Sword{Damage: 3})
+//	id, err := cardinal.Create(wCtx, weapon)
+func NewUnion[U UnionComponent, PU unionPointer[U]](variant types.Component) (U, error) {
+	var u U
+	if !hasVariant(u, variant) {
+		return u, eris.Wrapf(ErrUnknownVariant, "%q is not a variant of union %q", variant.Name(), u.Name())
+	}
+	value, err := json.Marshal(variant)
+	if err != nil {
+		return u, eris.Wrapf(err, "failed to marshal variant %q", variant.Name())
+	}
+	PU(&u).setUnion(Union{Variant: variant.Name(), Value: value})
+	return u, nil
+}
+
+// SetVariant replaces the value of the union component on the given entity with the given variant.
+func SetVariant[U UnionComponent, PU unionPointer[U]](
+	wCtx engine.Context, id types.EntityID, variant types.Component,
+) error {
+	u, err := NewUnion[U, PU](variant)
+	if err != nil {
+		return err
+	}
+	return SetComponent[U](wCtx, id, &u)
+}
+
+// AsVariant returns the value of the union if it holds the variant V. If it holds another variant, ok will be false.
+func AsVariant[V types.Component, U UnionComponent](u U) (variant *V, ok bool, err error) {
+	var v V
+	if !hasVariant(u, v) {
+		return nil, false, eris.Wrapf(ErrUnknownVariant, "%q is not a variant of union %q", v.Name(), u.Name())
+	}
+	if u.union().Variant != v.Name() {
+		return nil, false, nil
+	}
+	variant = new(V)
+	if err = json.Unmarshal(u.union().Value, variant); err != nil {
+		return nil, false, eris.Wrapf(err, "failed to unmarshal variant %q", v.Name())
+	}
+	return variant, true, nil
+}
+
+// VariantCase handles one variant of a union in Match. Use On to create one.
+type VariantCase struct {
+	variant string
+	handle  func(value json.RawMessage) error
+}
+
+// On returns a VariantCase that calls fn when the union holds the variant V.
+func On[V types.Component](fn func(V) error) VariantCase {
+	var v V
+	return VariantCase{
+		variant: v.Name(),
+		handle: func(value json.RawMessage) error {
+			var variant V
+			if err := json.Unmarshal(value, &variant); err != nil {
+				return eris.Wrapf(err, "failed to unmarshal variant %q", variant.Name())
+			}
+			return fn(variant)
+		},
+	}
+}
+
+// Match calls the case that handles the variant the union holds. The cases must handle every variant of the union,
+// otherwise ErrNonExhaustiveMatch is returned without calling any case, regardless of the variant the union holds.
+// This means that adding a variant to a union makes every match that does not handle it fail right away.
+//
+// Usage:
+//
+//	err := cardinal.Match(weapon,
+//		cardinal.On(func(s Sword) error { ... }),
+//		cardinal.On(func(b Bow) error { ... }),
+//		cardinal.On(func(s Staff) error { ... }),
+//	)
+func Match[U UnionComponent](u U, cases ...VariantCase) error {
+	handlers := make(map[string]VariantCase, len(cases))
+	for _, c := range cases {
+		if !slices.ContainsFunc(u.Variants(), func(v types.Component) bool { return v.Name() == c.variant }) {
+			return eris.Wrapf(ErrUnknownVariant, "%q is not a variant of union %q", c.variant, u.Name())
+		}
+		handlers[c.variant] = c
+	}
+	var missing []string
+	for _, v := range u.Variants() {
+		if _, ok := handlers[v.Name()]; !ok {
+			missing = append(missing, v.Name())
+		}
+	}
+	if len(missing) > 0 {
+		return eris.Wrapf(ErrNonExhaustiveMatch, "union %q: missing %s", u.Name(), strings.Join(missing, ", "))
+	}
+
+	c, ok := handlers[u.union().Variant]
+	if !ok {
+		return eris.Wrapf(ErrUnknownVariant, "union %q holds unknown variant %q", u.Name(), u.union().Variant)
+	}
+	return c.handle(u.union().Value)
+}
+
+// FilterVariant returns a search filter that only matches entities whose union component U holds the variant V.
+//
+// Usage:
+//
+//	cardinal.NewSearch().Entity(filter.Contains(filter.Component[Weapon]())).
+//		Where(cardinal.FilterVariant[Sword, Weapon]())
+func FilterVariant[V types.Component, U UnionComponent]() func(ctx engine.Context, id types.EntityID) (bool, error) {
+	var v V
+	return FilterFunction[U](func(u U) bool {
+		return u.union().Variant == v.Name()
+	})
+}
+
+func hasVariant(u UnionComponent, variant types.Component) bool {
+	return slices.ContainsFunc(u.Variants(), func(v types.Component) bool {
+		return v.Name() == variant.Name() && reflect.TypeOf(v) == reflect.TypeOf(variant)
+	})
+}
+
+func validateVariants(u UnionComponent) error {
+	variants := u.Variants()
+	if len(variants) == 0 {
+		return eris.Wrapf(ErrInvalidUnionVariant, "union %q has no variants", u.Name())
+	}
+	seen := make(map[string]bool, len(variants))
+	for _, v := range variants {
+		if seen[v.Name()] {
+			return eris.Wrapf(ErrInvalidUnionVariant, "union %q has duplicate variant %q", u.Name(), v.Name())
+		}
+		seen[v.Name()] = true
+	}
+	return nil
+}
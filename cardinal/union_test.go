@@ -0,0 +1,110 @@
+package cardinal_test
+
+import (
+	"testing"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/search/filter"
+	"pkg.world.dev/world-engine/cardinal/testutils"
+	"pkg.world.dev/world-engine/cardinal/types"
+)
+
+type Sword struct{ Damage int }
+
+type Bow struct{ Range int }
+
+type Staff struct{ Mana int }
+
+func (Sword) Name() string { return "sword" }
+func (Bow) Name() string   { return "bow" }
+func (Staff) Name() string { return "staff" }
+
+type Weapon struct{ cardinal.Union }
+
+func (Weapon) Name() string { return "weapon" }
+
+func (Weapon) Variants() []types.Component { return []types.Component{Sword{}, Bow{}, Staff{}} }
+
+type EmptyUnion struct{ cardinal.Union }
+
+func (EmptyUnion) Name() string { return "empty-union" }
+
+func (EmptyUnion) Variants() []types.Component { return nil }
+
+func TestUnionVariantsShareOneComponentSlot(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil)
+	world := tf.World
+	assert.NilError(t, cardinal.RegisterUnion[Weapon](world))
+	assert.ErrorIs(t, cardinal.RegisterUnion[EmptyUnion](world), cardinal.ErrInvalidUnionVariant)
+	tf.StartWorld()
+	wCtx := cardinal.NewWorldContext(world)
+
+	for _, variant := range []types.Component{Sword{Damage: 3}, Bow{Range: 10}, Staff{Mana: 7}} {
+		weapon, err := cardinal.NewUnion[Weapon](variant)
+		assert.NilError(t, err)
+		_, err = cardinal.Create(wCtx, weapon)
+		assert.NilError(t, err)
+	}
+	_, err := cardinal.NewUnion[Weapon](Health{})
+	assert.ErrorIs(t, err, cardinal.ErrUnknownVariant)
+
+	// All variants live in the same archetype.
+	count, err := cardinal.NewSearch().Entity(filter.Exact(filter.Component[Weapon]())).Count(wCtx)
+	assert.NilError(t, err)
+	assert.Equal(t, 3, count)
+
+	bows, err := cardinal.NewSearch().Entity(filter.Contains(filter.Component[Weapon]())).
+		Where(cardinal.FilterVariant[Bow, Weapon]()).Collect(wCtx)
+	assert.NilError(t, err)
+	assert.Equal(t, 1, len(bows))
+
+	weapon, err := cardinal.GetComponent[Weapon](wCtx, bows[0])
+	assert.NilError(t, err)
+	assert.Equal(t, "bow", weapon.VariantName())
+	bow, ok, err := cardinal.AsVariant[Bow](*weapon)
+	assert.NilError(t, err)
+	assert.Assert(t, ok)
+	assert.Equal(t, 10, bow.Range)
+	_, ok, err = cardinal.AsVariant[Sword](*weapon)
+	assert.NilError(t, err)
+	assert.Assert(t, !ok)
+
+	// Replacing the variant keeps the entity in the same slot.
+	assert.NilError(t, cardinal.SetVariant[Weapon](wCtx, bows[0], Staff{Mana: 1}))
+	weapon, err = cardinal.GetComponent[Weapon](wCtx, bows[0])
+	assert.NilError(t, err)
+	assert.Equal(t, "staff", weapon.VariantName())
+}
+
+func TestMatchMustBeExhaustive(t *testing.T) {
+	weapon, err := cardinal.NewUnion[Weapon](Staff{Mana: 5})
+	assert.NilError(t, err)
+
+	var mana int
+	err = cardinal.Match(weapon,
+		cardinal.On(func(Sword) error { return nil }),
+		cardinal.On(func(Bow) error { return nil }),
+		cardinal.On(func(s Staff) error {
+			mana = s.Mana
+			return nil
+		}),
+	)
+	assert.NilError(t, err)
+	assert.Equal(t, 5, mana)
+
+	// The match fails even though the union holds a handled variant.
+	err = cardinal.Match(weapon,
+		cardinal.On(func(Sword) error { return nil }),
+		cardinal.On(func(Staff) error { return nil }),
+	)
+	assert.ErrorIs(t, err, cardinal.ErrNonExhaustiveMatch)
+
+	err = cardinal.Match(weapon,
+		cardinal.On(func(Sword) error { return nil }),
+		cardinal.On(func(Bow) error { return nil }),
+		cardinal.On(func(Staff) error { return nil }),
+		cardinal.On(func(Health) error { return nil }),
+	)
+	assert.ErrorIs(t, err, cardinal.ErrUnknownVariant)
+}
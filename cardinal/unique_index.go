@@ -0,0 +1,228 @@
+package cardinal
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal/search"
+	"pkg.world.dev/world-engine/cardinal/search/filter"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+	"pkg.world.dev/world-engine/cardinal/worldstage"
+)
+
+// uniqueIndexPrefix is the prefix of the keys of unique indexes in the index storage. Index entries are committed with
+// the rest of the tick, so the index can't get out of sync with the components it covers.
+const uniqueIndexPrefix = "unique-"
+
+// uniqueIndexBuiltKey is set in the entries of an index once the entities that existed before the index was registered
+// have been added to it.
+const uniqueIndexBuiltKey = "built"
+
+var ErrUniqueIndexViolation = errors.New("unique index violation")
+
+// uniqueIndex is a type-erased index registered with RegisterUniqueIndex.
+type uniqueIndex struct {
+	name string
+	key  func(value any) (string, error)
+	// each calls fn with every entity that has the component of the index.
+	each func(wCtx engine.Context, fn func(id types.EntityID, value any) error) error
+}
+
+// RegisterUniqueIndex registers an index that ensures that no two entities have a component of type T with the same
+// key, e.g. the handle of a Username component. The index is maintained by the engine: creating an entity, or setting,
+// updating or adding a component, with a key that another entity already has fails with ErrUniqueIndexViolation, and
+// the entity can be looked up by its key with FindByIndex. Components whose key is empty are not indexed.
+//
+// Index entries are committed with the rest of the tick, but they don't count towards the raw storage quota of the
+// game. Entities that already exist when an index is registered are added to it when the world starts.
+//
+// Usage:
+//
+//	err := cardinal.RegisterUniqueIndex[Username](w, "username", func(u Username) string { return u.Handle })
+//	id, ok, err := cardinal.FindByIndex(wCtx, "username", "bob")
+func RegisterUniqueIndex[T types.Component](w *World, name string, key func(T) string) error {
+	if w.worldStage.Current() != worldstage.Init {
+		return eris.Errorf(
+			"world state is %s, expected %s to register unique indexes",
+			w.worldStage.Current(),
+			worldstage.Init,
+		)
+	}
+	if err := Namespace(uniqueIndexPrefix + name).Validate(); err != nil {
+		return eris.Wrapf(err, "invalid unique index name %q", name)
+	}
+	var t T
+	c, err := w.GetComponentByName(t.Name())
+	if err != nil {
+		return eris.Wrapf(err, "unique index %q covers a component that is not registered", name)
+	}
+	for _, indexes := range w.uniqueIndexes {
+		for _, existing := range indexes {
+			if existing.name == name {
+				return eris.Errorf("unique index %q is already registered", name)
+			}
+		}
+	}
+
+	w.uniqueIndexes[c.ID()] = append(w.uniqueIndexes[c.ID()], uniqueIndex{
+		name: name,
+		key: func(value any) (string, error) {
+			comp, err := componentValue[T](value)
+			if err != nil {
+				return "", err
+			}
+			return key(*comp), nil
+		},
+		each: func(wCtx engine.Context, fn func(id types.EntityID, value any) error) error {
+			var errs []error
+			err := search.NewSearch().Entity(filter.Contains(filter.Component[T]())).
+				Each(wCtx, func(id types.EntityID) bool {
+					comp, err := GetComponent[T](wCtx, id)
+					if err == nil {
+						err = fn(id, *comp)
+					}
+					if err != nil {
+						errs = append(errs, err)
+						return false
+					}
+					return true
+				})
+			return errors.Join(append(errs, err)...)
+		},
+	})
+	return nil
+}
+
+// FindByIndex returns the entity whose key in the given unique index is key. If no entity has the key, ok will be
+// false.
+func FindByIndex(wCtx engine.Context, index string, key string) (id types.EntityID, ok bool, err error) {
+	value, ok, err := newIndexStorage(wCtx, uniqueIndexPrefix+index).get(uniqueIndexKey(key))
+	if err != nil || !ok {
+		return 0, false, err
+	}
+	n, err := strconv.ParseUint(string(value), 10, 64)
+	if err != nil {
+		return 0, false, eris.Wrapf(err, "invalid entry for key %q in unique index %q", key, index)
+	}
+	return types.EntityID(n), true, nil
+}
+
+// indexComponent updates the unique and field indexes of the given component for the value that the component of the
+// entity is about to be set to, or removes the entity from them if value is nil. Nothing is changed if the value
+// violates any of the unique indexes.
+func (w *World) indexComponent(wCtx engine.Context, cType types.ComponentMetadata, id types.EntityID, value any) error {
+	indexes := w.uniqueIndexes[cType.ID()]
+	keys := make([]string, len(indexes))
+	if value != nil {
+		for i, index := range indexes {
+			key, err := index.check(wCtx, id, value)
+			if err != nil {
+				return err
+			}
+			keys[i] = key
+		}
+	}
+	for i, index := range indexes {
+		if err := setIndexEntry(wCtx, index.name, id, keys[i]); err != nil {
+			return err
+		}
+	}
+	return w.indexFields(wCtx, cType, id, value)
+}
+
+// check returns the key of the value, and an ErrUniqueIndexViolation error if another entity already has the key.
+func (index uniqueIndex) check(wCtx engine.Context, id types.EntityID, value any) (string, error) {
+	key, err := index.key(value)
+	if err != nil || key == "" {
+		return "", err
+	}
+	owner, ok, err := FindByIndex(wCtx, index.name, key)
+	if err != nil {
+		return "", err
+	}
+	if ok && owner != id {
+		return "", eris.Wrapf(ErrUniqueIndexViolation, "key %q of unique index %q is already used by entity %d",
+			key, index.name, owner)
+	}
+	return key, nil
+}
+
+// setIndexEntry sets the key of the entity in the given index, replacing its previous key. An empty key removes the
+// entity from the index.
+func setIndexEntry(wCtx engine.Context, index string, id types.EntityID, key string) error {
+	store := newIndexStorage(wCtx, uniqueIndexPrefix+index)
+	prev, ok, err := store.get(entityIndexKey(id))
+	if err != nil {
+		return err
+	}
+	if ok && string(prev) == key {
+		return nil
+	}
+	if ok {
+		if err = store.delete(uniqueIndexKey(string(prev))); err != nil {
+			return err
+		}
+	}
+	if key == "" {
+		if !ok {
+			return nil
+		}
+		return store.delete(entityIndexKey(id))
+	}
+	if err = store.set(uniqueIndexKey(key), []byte(strconv.FormatUint(uint64(id), 10))); err != nil {
+		return err
+	}
+	return store.set(entityIndexKey(id), []byte(key))
+}
+
+// buildUniqueIndexes adds the entities that existed before an index was registered to it, in batches that are committed
+// on their own. It fails if two of them have the same key, which has to be fixed by the game before the index can be
+// used. An index whose build was interrupted is built again from the start.
+func (w *World) buildUniqueIndexes(wCtx engine.Context) error {
+	for _, indexes := range w.uniqueIndexes {
+		for _, index := range indexes {
+			store := newIndexStorage(wCtx, uniqueIndexPrefix+index.name)
+			_, built, err := store.get(uniqueIndexBuiltKey)
+			if err != nil {
+				return err
+			}
+			if built {
+				continue
+			}
+			err = buildIndex(wCtx, index.each, func(id types.EntityID, value any) error {
+				key, err := index.check(wCtx, id, value)
+				if err != nil {
+					return err
+				}
+				return setIndexEntry(wCtx, index.name, id, key)
+			})
+			if err != nil {
+				return eris.Wrapf(err, "failed to build unique index %q", index.name)
+			}
+			if err = store.set(uniqueIndexBuiltKey, []byte{1}); err != nil {
+				return err
+			}
+			if err = wCtx.StoreManager().FlushIndexes(context.Background()); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// uniqueIndexKey is the index storage key of an index key. Keys are hashed, so that long keys don't make long storage
+// keys.
+func uniqueIndexKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return "key-" + hex.EncodeToString(sum[:])
+}
+
+func entityIndexKey(id types.EntityID) string {
+	return "entity-" + strconv.FormatUint(uint64(id), 10)
+}
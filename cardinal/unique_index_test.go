@@ -0,0 +1,135 @@
+package cardinal_test
+
+import (
+	"strconv"
+	"testing"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/testutils"
+)
+
+type Username struct {
+	Handle string
+}
+
+func (Username) Name() string {
+	return "username"
+}
+
+func TestUniqueIndexRejectsDuplicateKeys(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil)
+	world := tf.World
+	assert.NilError(t, cardinal.RegisterComponent[Username](world))
+	assert.NilError(t, cardinal.RegisterComponent[Health](world))
+	assert.NilError(t, cardinal.RegisterUniqueIndex[Username](world, "username", func(u Username) string {
+		return u.Handle
+	}))
+	assert.IsError(t, cardinal.RegisterUniqueIndex[Username](world, "username", func(u Username) string {
+		return u.Handle
+	}))
+	tf.StartWorld()
+
+	wCtx := cardinal.NewWorldContext(world)
+	bob, err := cardinal.Create(wCtx, Username{Handle: "bob"}, Health{})
+	assert.NilError(t, err)
+	_, err = cardinal.Create(wCtx, Username{Handle: "bob"})
+	assert.ErrorIs(t, err, cardinal.ErrUniqueIndexViolation)
+	// Entities without a key don't conflict.
+	_, err = cardinal.CreateMany(wCtx, 2, Username{})
+	assert.NilError(t, err)
+	// A batch that conflicts with itself creates nothing.
+	_, err = cardinal.CreateMany(wCtx, 2, Username{Handle: "twin"})
+	assert.ErrorIs(t, err, cardinal.ErrUniqueIndexViolation)
+	_, ok, err := cardinal.FindByIndex(wCtx, "username", "twin")
+	assert.NilError(t, err)
+	assert.False(t, ok)
+
+	id, ok, err := cardinal.FindByIndex(wCtx, "username", "bob")
+	assert.NilError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, id, bob)
+
+	alice, err := cardinal.Create(wCtx, Username{Handle: "alice"})
+	assert.NilError(t, err)
+	err = cardinal.SetComponent[Username](wCtx, alice, &Username{Handle: "bob"})
+	assert.ErrorIs(t, err, cardinal.ErrUniqueIndexViolation)
+	name, err := cardinal.GetComponent[Username](wCtx, alice)
+	assert.NilError(t, err)
+	assert.Equal(t, name.Handle, "alice")
+
+	// Renaming frees the old key.
+	assert.NilError(t, cardinal.UpdateComponent[Username](wCtx, bob, func(u *Username) *Username {
+		u.Handle = "robert"
+		return u
+	}))
+	assert.NilError(t, cardinal.SetComponent[Username](wCtx, alice, &Username{Handle: "bob"}))
+	id, ok, err = cardinal.FindByIndex(wCtx, "username", "bob")
+	assert.NilError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, id, alice)
+
+	// Removing the component or the entity frees the key.
+	assert.NilError(t, cardinal.RemoveComponentFrom[Username](wCtx, bob))
+	assert.NilError(t, cardinal.Remove(wCtx, alice))
+	for _, key := range []string{"robert", "bob"} {
+		_, ok, err = cardinal.FindByIndex(wCtx, "username", key)
+		assert.NilError(t, err)
+		assert.False(t, ok)
+	}
+
+	// The index is committed with the tick.
+	carol, err := cardinal.Create(wCtx, Username{Handle: "carol"})
+	assert.NilError(t, err)
+	tf.DoTick()
+	id, ok, err = cardinal.FindByIndex(cardinal.NewReadOnlyWorldContext(world), "username", "carol")
+	assert.NilError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, id, carol)
+}
+
+func TestUniqueIndexCoversMoreEntitiesThanTheRawStorageQuota(t *testing.T) {
+	// More entities than raw storage writes are allowed in a tick.
+	const numEntities = 10_001
+	tf := testutils.NewTestFixture(t, nil)
+	assert.NilError(t, cardinal.RegisterComponent[Username](tf.World))
+	tf.StartWorld()
+	wCtx := cardinal.NewWorldContext(tf.World)
+	for i := 0; i < numEntities; i++ {
+		_, err := cardinal.Create(wCtx, Username{Handle: "old-" + strconv.Itoa(i)})
+		assert.NilError(t, err)
+	}
+	tf.DoTick()
+
+	// Restart the world with an index over the entities that already exist.
+	tf.Shutdown()
+	tf = testutils.NewTestFixture(t, tf.Redis)
+	assert.NilError(t, cardinal.RegisterComponent[Username](tf.World))
+	assert.NilError(t, cardinal.RegisterUniqueIndex[Username](tf.World, "username", func(u Username) string {
+		return u.Handle
+	}))
+	tf.StartWorld()
+	wCtx = cardinal.NewWorldContext(tf.World)
+	for _, i := range []int{0, numEntities - 1} {
+		_, ok, err := cardinal.FindByIndex(wCtx, "username", "old-"+strconv.Itoa(i))
+		assert.NilError(t, err)
+		assert.True(t, ok)
+	}
+	_, err := cardinal.Create(wCtx, Username{Handle: "old-0"})
+	assert.ErrorIs(t, err, cardinal.ErrUniqueIndexViolation)
+
+	// Indexing as many new entities in a single tick doesn't exhaust the raw storage quota either.
+	for i := 0; i < numEntities; i++ {
+		_, err = cardinal.Create(wCtx, Username{Handle: "new-" + strconv.Itoa(i)})
+		assert.NilError(t, err)
+	}
+	tf.DoTick()
+	id, ok, err := cardinal.FindByIndex(
+		cardinal.NewReadOnlyWorldContext(tf.World), "username", "new-"+strconv.Itoa(numEntities-1),
+	)
+	assert.NilError(t, err)
+	assert.True(t, ok)
+	name, err := cardinal.GetComponent[Username](wCtx, id)
+	assert.NilError(t, err)
+	assert.Equal(t, name.Handle, "new-"+strconv.Itoa(numEntities-1))
+}
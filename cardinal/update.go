@@ -0,0 +1,143 @@
+package cardinal
+
+import (
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal/search"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+// Update calls fn with the component of type A of every entity that matches the search, and stores the component
+// after fn changed it. Instead of a GetComponent and a SetComponent call for each entity, the components of all
+// matching entities of an archetype are read and written at once, which is much faster for systems that update many
+// entities.
+//
+// Usage:
+//
+//	cardinal.Update[Health](wCtx, cardinal.NewSearch().Entity(filter.Contains(filter.Component[Health]())),
+//		func(id types.EntityID, health *Health) error {
+//			health.HP--
+//			return nil
+//		})
+//
+// fn must not change the components of other entities that match the search. If fn returns an error, the update
+// stops and the error is returned. The entities that were updated before stay updated.
+func Update[A types.Component](
+	wCtx engine.Context, s search.EntitySearch, fn func(id types.EntityID, a *A) error,
+) error {
+	var a A
+	return update(wCtx, s, []string{a.Name()}, func(id types.EntityID, comps []any) error {
+		compA, err := componentPointer[A](comps, 0)
+		if err != nil {
+			return err
+		}
+		return fn(id, compA)
+	})
+}
+
+// Update2 is like Update, but calls fn with the components of type A and B of every entity that matches the search.
+func Update2[A, B types.Component](
+	wCtx engine.Context, s search.EntitySearch, fn func(id types.EntityID, a *A, b *B) error,
+) error {
+	var a A
+	var b B
+	return update(wCtx, s, []string{a.Name(), b.Name()}, func(id types.EntityID, comps []any) error {
+		compA, err := componentPointer[A](comps, 0)
+		if err != nil {
+			return err
+		}
+		compB, err := componentPointer[B](comps, 1)
+		if err != nil {
+			return err
+		}
+		return fn(id, compA, compB)
+	})
+}
+
+// update calls fn with the named components of every entity that matches the search, and stores the components that
+// fn leaves in comps. Errors of fn are returned as they are, while storage errors are checked by panicOnFatalError.
+func update(
+	wCtx engine.Context, s search.EntitySearch, names []string, fn func(id types.EntityID, comps []any) error,
+) error {
+	fnErr, err := updateBatches(wCtx, s, names, fn)
+	if err != nil {
+		return err
+	}
+	return fnErr
+}
+
+func updateBatches(
+	wCtx engine.Context, s search.EntitySearch, names []string, fn func(id types.EntityID, comps []any) error,
+) (fnErr error, err error) {
+	defer func() { panicOnFatalError(wCtx, err) }()
+
+	if wCtx.IsReadOnly() {
+		return nil, ErrEntityMutationOnReadOnly
+	}
+	cTypes := make([]types.ComponentMetadata, len(names))
+	for i, name := range names {
+		if cTypes[i], err = wCtx.GetComponentByName(name); err != nil {
+			return nil, err
+		}
+	}
+
+	var storeErr error
+	err = s.EachBatch(wCtx, func(archID types.ArchetypeID, ids []types.EntityID) bool {
+		columns := make([][]any, len(cTypes))
+		for i, c := range cTypes {
+			if columns[i], storeErr = wCtx.StoreManager().GetComponentsForArchID(c, archID, ids); storeErr != nil {
+				return false
+			}
+		}
+		comps := make([]any, len(cTypes))
+		updated := 0
+	entities:
+		for j, id := range ids {
+			for i, c := range cTypes {
+				if storeErr = wCtx.TrackComponentChange(c, id, false); storeErr != nil {
+					break entities
+				}
+				comps[i] = columns[i][j]
+			}
+			if fnErr = fn(id, comps); fnErr != nil {
+				break
+			}
+			for i, c := range cTypes {
+				if storeErr = wCtx.IndexComponent(c, id, comps[i]); storeErr != nil {
+					break entities
+				}
+			}
+			for i := range cTypes {
+				columns[i][j] = comps[i]
+			}
+			updated++
+		}
+		// The entities that were updated before an error are stored too, since fn can change components in place
+		for i, c := range cTypes {
+			err := wCtx.StoreManager().SetComponentsForArchID(c, archID, ids[:updated], columns[i][:updated])
+			if storeErr == nil {
+				storeErr = err
+			}
+		}
+		return storeErr == nil && fnErr == nil
+	})
+	if err == nil {
+		err = storeErr
+	}
+	return fnErr, err
+}
+
+// componentPointer replaces the component at comps[i] with a pointer to it, which fn can change.
+func componentPointer[T types.Component](comps []any, i int) (*T, error) {
+	switch comp := comps[i].(type) {
+	case *T:
+		return comp, nil
+	case T:
+		comps[i] = &comp
+		return &comp, nil
+	default:
+		var t T
+		return nil, eris.Errorf("component %q has unexpected type %T", t.Name(), comp)
+	}
+}
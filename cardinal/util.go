@@ -1,8 +1,11 @@
 package cardinal
 
 import (
+	"errors"
+
 	"github.com/rotisserie/eris"
 
+	"pkg.world.dev/world-engine/cardinal/faults"
 	"pkg.world.dev/world-engine/cardinal/server"
 	"pkg.world.dev/world-engine/cardinal/types/engine"
 )
@@ -12,6 +15,14 @@ var NonFatalError = []error{
 	ErrComponentNotOnEntity,
 	ErrComponentAlreadyOnEntity,
 	ErrEntityMustHaveAtLeastOneComponent,
+	ErrRawStorageQuotaExceeded,
+	ErrPersonaEntityQuotaExceeded,
+	ErrEntityAlreadyOwned,
+	ErrEntityNotOwned,
+	ErrSystemEntityQuotaExceeded,
+	ErrWorldEntityCapExceeded,
+	ErrWorldComponentCapExceeded,
+	ErrUniqueIndexViolation,
 }
 
 // separateOptions separates the given options into ecs options, server options, and cardinal (this package) options.
@@ -32,6 +43,26 @@ func separateOptions(opts []WorldOption) (
 	return serverOptions, cardinalOptions
 }
 
+// faultInjector returns the injector of the last WithFaultInjection option, if any.
+func faultInjector(opts []WorldOption) *faults.Injector {
+	var inj *faults.Injector
+	for _, opt := range opts {
+		if opt.faults != nil {
+			inj = opt.faults
+		}
+	}
+	return inj
+}
+
+// withCleanup returns err together with the errors of the cleanup that ran because of it. If the cleanup succeeded, err is
+// returned as is, so that isFatalError still recognizes it.
+func withCleanup(err error, cleanupErrs ...error) error {
+	if cleanupErr := errors.Join(cleanupErrs...); cleanupErr != nil {
+		return errors.Join(err, cleanupErr)
+	}
+	return err
+}
+
 // panicOnFatalError is a helper function to panic on non-deterministic errors (i.e. Redis error).
 func panicOnFatalError(wCtx engine.Context, err error) {
 	if err != nil && !wCtx.IsReadOnly() && isFatalError(err) {
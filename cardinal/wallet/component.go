@@ -0,0 +1,18 @@
+// Package wallet contains the components, messages and queries of Cardinal's wallet plugin, which keeps the soft
+// currency balances of personas. Currency enters the game with the credit message, which Nakama's wallet bridge sends
+// when it validates an in-app purchase. Systems spend and reward currency with cardinal.SpendCurrency and
+// cardinal.CreditCurrency, which emit an Event so that the bridge applies the same change to the player's Nakama
+// wallet. The balance query lets the bridge compare both sides when it reconciles a wallet.
+//
+// The plugin is registered with cardinal.NewWalletPlugin.
+package wallet
+
+// Wallet holds the currency balances of a persona, keyed by currency name. The wallet of a persona is an entity that
+// the persona owns (see cardinal.ClaimEntity), which is created the first time currency is credited to the persona.
+type Wallet struct {
+	Balances map[string]uint64 `json:"balances"`
+}
+
+func (Wallet) Name() string {
+	return "Wallet"
+}
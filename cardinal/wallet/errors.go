@@ -0,0 +1,11 @@
+package wallet
+
+import (
+	"errors"
+)
+
+var (
+	ErrInsufficientBalance = errors.New("insufficient balance")
+	ErrInvalidCredit       = errors.New("invalid credit")
+	ErrBalanceOverflow     = errors.New("balance would overflow")
+)
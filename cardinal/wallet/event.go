@@ -0,0 +1,21 @@
+package wallet
+
+const (
+	// SpendEventType is the event of an Event that takes currency from a persona.
+	SpendEventType = "wallet-spend"
+	// EarnEventType is the event of an Event that gives currency to a persona.
+	EarnEventType = "wallet-earn"
+)
+
+// Event is emitted when a system changes the balance of a persona with cardinal.SpendCurrency or
+// cardinal.CreditCurrency, or when currency changes hands in a trade. JournalID is unique for every change, so that
+// the change is applied only once to the persona's Nakama wallet. Credits sent by Nakama don't emit an Event, since
+// Nakama has already applied them.
+type Event struct {
+	// Event is SpendEventType or EarnEventType.
+	Event      string `json:"event"`
+	JournalID  string `json:"journalId"`
+	PersonaTag string `json:"personaTag"`
+	Currency   string `json:"currency"`
+	Amount     uint64 `json:"amount"`
+}
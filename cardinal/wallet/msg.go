@@ -0,0 +1,20 @@
+package wallet
+
+const CreditMessageName = "credit"
+
+// Credit adds currency to the wallet of a persona. It is an admin-only message, which is sent by Nakama's wallet bridge
+// for validated in-app purchases. JournalID identifies the credit: a credit with a journal ID that was already applied
+// changes nothing, so the bridge can safely send a credit again when it doesn't know whether it was applied.
+type Credit struct {
+	JournalID  string `json:"journalId"`
+	PersonaTag string `json:"personaTag"`
+	Currency   string `json:"currency"`
+	Amount     uint64 `json:"amount"`
+}
+
+type CreditResult struct {
+	// Balance is the balance of the currency after the credit.
+	Balance uint64 `json:"balance"`
+	// Duplicate is true if the credit had already been applied, in which case nothing was changed.
+	Duplicate bool `json:"duplicate"`
+}
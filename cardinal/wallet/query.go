@@ -0,0 +1,13 @@
+package wallet
+
+const BalanceQueryName = "balance"
+
+// BalanceRequest is the request body of the wallet balance query.
+type BalanceRequest struct {
+	PersonaTag string `json:"personaTag"`
+	Currency   string `json:"currency"`
+}
+
+type BalanceResponse struct {
+	Balance uint64 `json:"balance"`
+}
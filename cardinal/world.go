@@ -8,62 +8,147 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"slices"
+	"sync"
 	"sync/atomic"
 	"syscall"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/rotisserie/eris"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
-	"gopkg.in/DataDog/dd-trace-go.v1/ddtrace/tracer"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 
+	"pkg.world.dev/world-engine/cardinal/admin"
+	"pkg.world.dev/world-engine/cardinal/codec"
 	"pkg.world.dev/world-engine/cardinal/component"
+	"pkg.world.dev/world-engine/cardinal/eventlog"
+	"pkg.world.dev/world-engine/cardinal/faults"
 	"pkg.world.dev/world-engine/cardinal/gamestate"
 	ecslog "pkg.world.dev/world-engine/cardinal/log"
 	"pkg.world.dev/world-engine/cardinal/message"
+	"pkg.world.dev/world-engine/cardinal/persona"
 	"pkg.world.dev/world-engine/cardinal/query"
 	"pkg.world.dev/world-engine/cardinal/receipt"
 	"pkg.world.dev/world-engine/cardinal/router"
 	"pkg.world.dev/world-engine/cardinal/search"
 	"pkg.world.dev/world-engine/cardinal/search/filter"
 	"pkg.world.dev/world-engine/cardinal/server"
+	"pkg.world.dev/world-engine/cardinal/server/handler"
 	servertypes "pkg.world.dev/world-engine/cardinal/server/types"
 	"pkg.world.dev/world-engine/cardinal/statsd"
 	"pkg.world.dev/world-engine/cardinal/storage/redis"
+	"pkg.world.dev/world-engine/cardinal/tracing"
 	"pkg.world.dev/world-engine/cardinal/types"
 	"pkg.world.dev/world-engine/cardinal/types/engine"
 	"pkg.world.dev/world-engine/cardinal/types/txpool"
 	"pkg.world.dev/world-engine/cardinal/worldstage"
+	"pkg.world.dev/world-engine/rift/credentials"
 	"pkg.world.dev/world-engine/sign"
 )
 
 const (
 	DefaultHistoricalTicksToStore = 10
 	RedisDialTimeOut              = 15
+	// DefaultShutdownTimeout is how long the world waits for its last ticks when it is shut down by a signal. It is
+	// shorter than the usual grace period of container orchestrators, after which the process is killed.
+	DefaultShutdownTimeout = 20 * time.Second
+
+	// tickAbortGracePeriod is how long Shutdown waits for the in-flight tick to stop once it has been aborted.
+	tickAbortGracePeriod = 5 * time.Second
+	// shutdownCleanupTimeout bounds the cleanup that Shutdown still runs once its context is done.
+	shutdownCleanupTimeout = 5 * time.Second
 )
 
 var _ router.Provider = &World{}      //nolint:exhaustruct
 var _ servertypes.Provider = &World{} //nolint:exhaustruct
 
+// ErrMessageIDMismatch is returned by StartGame if the messages are not registered in the order in which they were
+// registered when the game state was created, so that their IDs would change.
+var ErrMessageIDMismatch = gamestate.ErrMessageIDMismatch
+
 type World struct {
 	SystemManager
 
 	namespace     Namespace
 	rollupEnabled bool
+	// evmServerEnabled reports whether the router serves the gRPC server that the base shard forwards EVM messages to.
+	evmServerEnabled bool
+	randSeed         uint64
+	// managed is true when the world is hosted by a WorldManager, which serves its HTTP routes and handles shutdown.
+	managed bool
+
+	// instanceID identifies this instance of the world as the owner of its namespace. See ClaimNamespace.
+	instanceID string
+	// readReplica is set if the world serves the state that another instance commits without ticking. See
+	// WithReadReplica.
+	readReplica *readReplica
+	// lease is the lease of the world on its namespace, which it owns while it runs. See ClaimNamespace.
+	lease *namespaceLease
+	// leaderElection makes the world a standby until it owns the lease of its namespace. See WithLeaderElection.
+	leaderElection bool
 
 	// Storage
-	redisStorage *redis.Storage
-	entityStore  gamestate.Manager
+	redisStorage    *redis.Storage
+	namespaces      *redis.Namespaces
+	entityStore     gamestate.Manager
+	rawStorageQuota *gamestate.RawStorageQuota
+	entityQuota     *entityQuotaTracker
+	// archiveAfter is the number of ticks after which an entity that was not accessed is archived. Zero disables it.
+	archiveAfter uint64
+	// idempotencyWindow is how long idempotency keys of submitted transactions are remembered.
+	idempotencyWindow time.Duration
+	// replicatedTxs is true if submitted transactions are added to the queue shared by the replicas of the world
+	// instead of the transaction pool, and replicatedTxWindow is how long transactions taken from it are remembered.
+	// See WithReplicatedTxQueue.
+	replicatedTxs      bool
+	replicatedTxWindow time.Duration
+	// replicatedTxsInPool are the hashes of the transactions that were taken from the replicated queue into the pool,
+	// and are removed from the queue when the tick they are executed in starts.
+	replicatedTxsInPool map[string]struct{}
+	// sessionKeys is true if the session key plugin is registered. See NewSessionKeyPlugin.
+	sessionKeys bool
+	// coSignTimeout is how long co-signed transactions wait for the signatures of their co-signers.
+	coSignTimeout time.Duration
+	// backPressure limits the transactions that the world accepts. See CheckBackPressure.
+	backPressure BackPressure
+	// componentCodec is the codec that components are stored with, unless they are registered with another one.
+	componentCodec codec.Codec
+	// componentEncryption supplies the keys that components are encrypted with. It is nil unless encryption is enabled
+	// with WithComponentEncryption or CARDINAL_ENCRYPTION_KEY.
+	componentEncryption codec.KeyProvider
+	// recordEvents is true if the events and receipts of every tick are recorded in the tick log, which is read by the
+	// event log service and GetEventHistory.
+	recordEvents bool
+	// eventRetention limits how long the tick log is kept. See WithEventHistory.
+	eventRetention EventRetention
+	// localPersistence saves the in-memory redis of the world to a file. It is nil unless WithLocalPersistence is used.
+	localPersistence *localPersistence
+	// genesisFile is the path of the file with the entities that are created at tick 0. See WithGenesisFile.
+	genesisFile string
 
 	// Networking
 	server        *server.Server
 	serverOptions []server.Option
+	eventLog      *eventlog.Server
+	// maxReplyBytes is the maximum size of a reply to a query from the EVM. Zero disables the limit.
+	maxReplyBytes int
+	adminServer   *admin.Server
+
+	// Telemetry
+	// shutdownTracing flushes the spans that have not been exported yet. It is nil if tracing is not enabled.
+	shutdownTracing func(context.Context) error
 
 	// Core modules
 	worldStage       *worldstage.Manager
 	msgManager       *message.Manager
 	componentManager *component.Manager
 	queryManager     *query.Manager
+	triggers         *triggerManager
+	uniqueIndexes    map[types.ComponentID][]uniqueIndex
+	fieldIndexes     map[types.ComponentID][]fieldIndex
 	router           router.Router
 	txPool           *txpool.TxPool
 
@@ -75,21 +160,88 @@ type World struct {
 	tick            *atomic.Uint64
 	timestamp       *atomic.Uint64
 	tickResults     *TickResults
+	tickClock       *tickClock
 	tickChannel     <-chan time.Time
 	tickDoneChannel chan<- uint64
+	// tickTimeout is the time after which the context of a tick is cancelled. Zero disables it. See WithTickTimeout.
+	tickTimeout time.Duration
+	// storageBreaker stops the world from ticking while its storage is unavailable. It is nil unless
+	// WithStorageBreaker is used.
+	storageBreaker *storageBreaker
 	// addChannelWaitingForNextTick accepts a channel which will be closed after a tick has been completed.
 	addChannelWaitingForNextTick chan chan struct{}
+	// paused makes the game loop skip ticks until the world is resumed. See Pause.
+	paused *atomic.Bool
+	// betweenTicks accepts functions that the game loop runs between two ticks. See runBetweenTicks.
+	betweenTicks chan func()
+	// stopGameLoop receives the context of Shutdown once the world stopped accepting transactions. See Shutdown.
+	stopGameLoop chan context.Context
+	// tickCtx is the context of the game loop's ticks. Shutdown cancels it with abortTick if the in-flight tick doesn't
+	// finish in time.
+	tickCtx   context.Context
+	abortTick context.CancelFunc
+	// hotReload allows the systems to be replaced while the world is running. See ReloadSystems.
+	hotReload bool
+	// lifecycleHooks are the hooks registered with RegisterLifecycleHook, by stage.
+	lifecycleHooks map[LifecycleStage][]LifecycleHook
+	// determinismAudit runs every tick twice. It is nil unless WithDeterminismAudit is used.
+	determinismAudit *determinismAudit
+	// debugger stops ticks at breakpoints. It is nil unless WithStepDebugger is used.
+	debugger *stepDebugger
+	// tickProfiler captures the profiles of ticks. See ProfileTicks.
+	tickProfiler *tickProfiler
+	// layoutProfiler records the query patterns of systems. It is nil unless WithLayoutProfiling is used.
+	layoutProfiler *layoutProfiler
+	// destroyed are the entities that are removed at the end of the tick. See Destroy.
+	destroyed map[types.EntityID]bool
+
+	// autoCheckpointTicks is the number of ticks between automatic checkpoints. See WithAutoCheckpoint.
+	autoCheckpointTicks uint64
+	// stateCommitmentTicks is the number of ticks between state commitments, and stateCommitmentsKept the number of
+	// commitments that can be proven. See WithStateCommitments.
+	stateCommitmentTicks uint64
+	stateCommitmentsKept int
+	// stateCommitments counts the state commitments that are computed in the background.
+	stateCommitments sync.WaitGroup
+	// recordStateDiffs makes every tick record its state diff, and stateDiffsKept is the number of diffs that are kept.
+	// See WithStateDiffs.
+	recordStateDiffs bool
+	stateDiffsKept   uint64
+	// faults injects faults into the tick loop and the storage. It is nil unless WithFaultInjection is used.
+	faults *faults.Injector
 }
 
 // NewWorld creates a new World object using Redis as the storage layer
 func NewWorld(opts ...WorldOption) (*World, error) {
-	serverOptions, cardinalOptions := separateOptions(opts)
-
 	// Load config. Fallback value is used if it's not set.
-	cfg, err := loadWorldConfig()
+	cfg, err := worldConfig(opts)
 	if err != nil {
 		return nil, eris.Wrap(err, "Failed to load config to start world")
 	}
+	return newWorld(cfg, opts...)
+}
+
+// newWorld creates a new World object from the given config. Every redis key of the world is prefixed with its
+// namespace, so that the world can share a redis database with other worlds.
+func newWorld(cfg *WorldConfig, opts ...WorldOption) (*World, error) {
+	opts = append(slices.Clone(Profile(cfg.CardinalProfile).options()), opts...)
+	serverOptions, cardinalOptions := separateOptions(opts)
+	if signers := cfg.adminSigners(); len(signers) > 0 {
+		serverOptions = append(serverOptions, server.WithAdminSigners(signers...))
+	}
+	serverOptions = append(serverOptions, server.WithDebugConfig(cfg.Redacted()))
+	if cfg.CardinalCQLFilters {
+		serverOptions = append(serverOptions, server.WithCQLFilters())
+	}
+	if origins := cfg.corsOrigins(); len(origins) > 0 {
+		// Goes first, so that the limits set with WithHTTPLimits take precedence
+		serverOptions = append([]server.Option{server.WithHTTPLimits(server.HTTPLimits{AllowedOrigins: origins})},
+			serverOptions...)
+	}
+	if auth := cfg.httpAuth(); auth != nil {
+		// Goes first, so that the authentication set with WithHTTPAuth takes precedence
+		serverOptions = append([]server.Option{server.WithAuth(*auth)}, serverOptions...)
+	}
 
 	if cfg.CardinalRollupEnabled {
 		log.Info().Msgf("Creating a new Cardinal world in rollup mode")
@@ -98,15 +250,25 @@ func NewWorld(opts ...WorldOption) (*World, error) {
 			"If you intended to run this for production use, set CARDINAL_ROLLUP=true")
 	}
 
-	redisMetaStore := redis.NewRedisStorage(redis.Options{
+	redisOptions := redis.Options{
 		Addr:        cfg.RedisAddress,
 		Password:    cfg.RedisPassword,
 		DB:          0,                              // use default DB
 		DialTimeout: RedisDialTimeOut * time.Second, // Increase startup dial timeout
-	}, cfg.CardinalNamespace)
+	}
+	redisMetaStore := redis.NewRedisStorage(redisOptions, cfg.CardinalNamespace)
+	redisMetaStore.SetKeyPrefix(redis.NamespaceKeyPrefix(cfg.CardinalNamespace))
 
+	// The ticks of the world are only committed while it owns its namespace
+	instanceID := uuid.NewString()
 	redisStore := gamestate.NewRedisPrimitiveStorage(redisMetaStore.Client)
-	entityCommandBuffer, err := gamestate.NewEntityCommandBuffer(&redisStore)
+	redisStore.Fence(redis.NamespaceOwnerKey(), instanceID)
+	var store gamestate.PrimitiveStorage[string] = &redisStore
+	injector := faultInjector(opts)
+	if injector != nil {
+		store = faults.WrapStorage(store, injector)
+	}
+	entityCommandBuffer, err := gamestate.NewEntityCommandBuffer(store)
 	if err != nil {
 		return nil, err
 	}
@@ -114,16 +276,28 @@ func NewWorld(opts ...WorldOption) (*World, error) {
 	tick := new(atomic.Uint64)
 
 	world := &World{
-		namespace:     Namespace(cfg.CardinalNamespace),
-		rollupEnabled: cfg.CardinalRollupEnabled,
+		namespace:           Namespace(cfg.CardinalNamespace),
+		rollupEnabled:       cfg.CardinalRollupEnabled,
+		evmServerEnabled:    cfg.CardinalEVMServerEnabled,
+		randSeed:            defaultRandSeed(cfg.CardinalNamespace),
+		instanceID:          instanceID,
+		lease:               newNamespaceLease(DefaultNamespaceLease),
+		replicatedTxsInPool: map[string]struct{}{},
 
 		// Storage
 		redisStorage: &redisMetaStore,
+		namespaces:   redis.NewNamespaces(redisOptions),
 		entityStore:  entityCommandBuffer,
+		entityQuota:  newEntityQuotaTracker(),
+		faults:       injector,
+
+		idempotencyWindow: DefaultIdempotencyWindow,
+		coSignTimeout:     DefaultCoSignTimeout,
 
 		// Networking
 		server:        nil, // Will be initialized in StartGame
 		serverOptions: serverOptions,
+		maxReplyBytes: handler.DefaultMaxReplyBytes,
 
 		// Core modules
 		worldStage:       worldstage.NewManager(),
@@ -131,6 +305,9 @@ func NewWorld(opts ...WorldOption) (*World, error) {
 		SystemManager:    newSystemManager(),
 		componentManager: component.NewManager(&redisMetaStore),
 		queryManager:     query.NewManager(),
+		triggers:         newTriggerManager(),
+		uniqueIndexes:    map[types.ComponentID][]uniqueIndex{},
+		fieldIndexes:     map[types.ComponentID][]fieldIndex{},
 		router:           nil, // Will be set if run mode is production or its injected via options
 		txPool:           txpool.New(),
 
@@ -142,18 +319,59 @@ func NewWorld(opts ...WorldOption) (*World, error) {
 		tick:                         tick,
 		timestamp:                    new(atomic.Uint64),
 		tickResults:                  NewTickResults(tick.Load()),
-		tickChannel:                  time.Tick(time.Second), //nolint:staticcheck // its ok.
-		tickDoneChannel:              nil,                    // Will be injected via options
+		tickClock:                    newTickClock(cfg.tickInterval(), DriftMode(cfg.CardinalTickDriftMode)),
+		tickChannel:                  nil, // Will be set to the tick clock when the game starts, unless injected
+		tickDoneChannel:              nil, // Will be injected via options
 		addChannelWaitingForNextTick: make(chan chan struct{}),
+		paused:                       new(atomic.Bool),
+		betweenTicks:                 make(chan func()),
+		stopGameLoop:                 make(chan context.Context, 1),
+		lifecycleHooks:               map[LifecycleStage][]LifecycleHook{},
+		tickProfiler:                 &tickProfiler{},
+		destroyed:                    map[types.EntityID]bool{},
+		genesisFile:                  cfg.CardinalGenesisFile,
+	}
+	world.tickCtx, world.abortTick = context.WithCancel(context.Background())
+
+	if cfg.CardinalStrictMode {
+		world.SystemManager.setStrictMode(true)
+	}
+	if cfg.CardinalDeterminismAudit {
+		world.enableDeterminismAudit()
+	}
+	if cfg.CardinalAdminToken != "" {
+		world.adminServer = admin.NewServer(world, admin.DefaultPort, cfg.CardinalAdminToken)
 	}
 
 	// Initialize shard router if running in rollup mode
 	if cfg.CardinalRollupEnabled {
+		var routerOpts []router.Option
+		if cfg.BaseShardAttestationKey == "" {
+			log.Warn().Msg("BASE_SHARD_ATTESTATION_KEY is not set, messages from the base shard are not verified")
+		} else {
+			key, err := credentials.ParseAttestationPublicKey(cfg.BaseShardAttestationKey)
+			if err != nil {
+				return nil, err
+			}
+			routerOpts = append(routerOpts, router.WithAttestationKey(key))
+		}
+		if cfg.CardinalEVMServerCertPath != "" {
+			tlsConfig, err := credentials.NewServerTLSConfig(credentials.TLSFiles{
+				CertPath: cfg.CardinalEVMServerCertPath,
+				KeyPath:  cfg.CardinalEVMServerKeyPath,
+				CAPath:   cfg.CardinalEVMServerCAPath,
+			})
+			if err != nil {
+				return nil, err
+			}
+			routerOpts = append(routerOpts, router.WithTLSConfig(tlsConfig))
+		}
 		world.router, err = router.New(
 			cfg.CardinalNamespace,
 			cfg.BaseShardSequencerAddress,
 			cfg.BaseShardRouterKey,
 			world,
+			routerOpts...,
 		)
 		if err != nil {
 			return nil, eris.Wrap(err, "Failed to initialize shard router")
@@ -165,7 +383,38 @@ func NewWorld(opts ...WorldOption) (*World, error) {
 		opt(world)
 	}
 
+	if world.archiveAfter > 0 {
+		if err = world.trackEntityAccess(); err != nil {
+			return nil, err
+		}
+	}
+
+	// The key provider set with WithComponentEncryption takes precedence over the key in the config.
+	if world.componentEncryption == nil && cfg.CardinalEncryptionKey != "" {
+		key, err := cfg.encryptionKey()
+		if err != nil {
+			return nil, err
+		}
+		world.componentEncryption = codec.NewStaticKey(key)
+	}
+	// The codec of every component encrypts its values with the keys of this world, see RegisterComponent. The rest of
+	// the game data is encrypted by the store manager; a custom store manager stores it itself.
+	if ecb, ok := world.entityStore.(*gamestate.EntityCommandBuffer); ok && world.componentEncryption != nil {
+		ecb.SetEncryption(world.componentEncryption)
+	}
+
+	// Keys must be migrated before the plugins and components register their schemas in the namespace. Worlds hosted
+	// by a WorldManager have always stored their keys under their name, so they have no legacy keys.
+	if !world.managed {
+		if err = world.migrateLegacyKeys(); err != nil {
+			return nil, err
+		}
+	}
+
 	world.RegisterPlugin(newPersonaPlugin())
+	if err = registerTickDriftQuery(world); err != nil {
+		return nil, err
+	}
 
 	var metricTags []string
 	metricTags = append(metricTags, "cardinal_namespace:"+cfg.CardinalNamespace)
@@ -176,12 +425,19 @@ func NewWorld(opts ...WorldOption) (*World, error) {
 			if err = statsd.Init(cfg.TelemetryStatsdAddress, cfg.TelemetryTraceAddress, metricTags); err != nil {
 				return nil, eris.Wrap(err, "failed to init statsd telemetry")
 			}
-		} else {
+		} else if cfg.TelemetryOTLPAddress == "" {
 			log.Logger.Warn().Msg(
 				"TELEMETRY_ENABLED=true but TELEMETRY_STATSD_ADDRESS and TELEMETRY_TRACE_ADDRESS are not set. " +
 					"Telemetry data will not be submitted to the agent.",
 			)
 		}
+
+		if cfg.TelemetryOTLPAddress != "" {
+			world.shutdownTracing, err = tracing.Init(context.Background(), cfg.TelemetryOTLPAddress, metricTags)
+			if err != nil {
+				return nil, eris.Wrap(err, "failed to init OpenTelemetry tracing")
+			}
+		}
 	}
 
 	return world, nil
@@ -213,20 +469,65 @@ func (w *World) doTick(ctx context.Context, timestamp uint64) (err error) {
 	// current system that is running.
 	defer w.handleTickPanic()
 
-	var span tracer.Span
-	span, ctx = tracer.StartSpanFromContext(ctx, "cardinal.span.tick")
+	// The namespace is checked before the transactions are taken from the pool, so that no transaction is lost if
+	// another world instance took over
+	if err := w.checkNamespaceOwner(); err != nil {
+		return err
+	}
+
+	// Only the instance that owns the namespace takes the transactions that its replicas received
+	w.takeReplicatedTxs()
+
+	// Copy the transactions from the pool so that we can safely modify the pool while the tick is running.
+	txPool := w.takeTickTransactions(timestamp)
+	// Transactions that expired while they were waiting are dropped before the pool is persisted, so they are never
+	// executed, not even when the tick is recovered
+	expired := txPool.RemoveExpired(w.CurrentTick())
+	// So are the transactions that were signed by session keys that were revoked or expired while they were waiting
+	revoked, err := w.removeRevokedSessionKeyTxs(txPool, timestamp)
+	if err != nil {
+		return err
+	}
+
+	if w.tickTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, w.tickTimeout)
+		defer cancel()
+		defer func() {
+			if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				err = eris.Wrapf(err, "tick %d exceeded its timeout of %s", w.CurrentTick(), w.tickTimeout)
+			}
+		}()
+	}
+
+	var span trace.Span
+	ctx, span = tracing.Tracer().Start(ctx, "cardinal.tick",
+		trace.WithLinks(tracing.LinkTo(txPool.SpanContexts()...)...),
+		trace.WithAttributes(
+			attribute.String("namespace", w.Namespace()),
+			attribute.Int64("tick", int64(w.CurrentTick())),
+			attribute.Int("num_of_txs", txPool.GetAmountOfTxs()),
+		),
+	)
 	defer func() {
-		span.Finish()
+		tracing.End(span, err)
 	}()
 
+	w.tickProfiler.tickStarted()
 	log.Info().Int("tick", int(w.CurrentTick())).Msg("Tick started")
+	w.runLifecycleHooks(ctx, LifecycleTickStarted, w.CurrentTick())
 
-	// Copy the transactions from the pool so that we can safely modify the pool while the tick is running.
-	txPool := w.txPool.CopyTransactions()
+	if err := w.faults.Inject(ctx, faults.TickStart); err != nil {
+		return err
+	}
 
-	if err := w.entityStore.StartNextTick(w.msgManager.GetRegisteredMessages(), txPool); err != nil {
+	// The timestamp is persisted with the pending transactions so that replaying an interrupted tick sees the same time.
+	// The replicated transactions of the tick are removed from their queue in the same transaction.
+	replicated := w.removeReplicatedTxs(txPool, slices.Concat(expired, revoked))
+	if err := w.entityStore.StartNextTick(ctx, w.msgManager.GetRegisteredMessages(), txPool, timestamp); err != nil {
 		return err
 	}
+	w.forgetReplicatedTxs(replicated)
 
 	// Store the timestamp for this tick
 	w.timestamp.Store(timestamp)
@@ -236,7 +537,56 @@ func (w *World) doTick(ctx context.Context, timestamp uint64) (err error) {
 
 	// Run all registered systems.
 	// This will run the registered init systems if the current tick is 0
-	if err := w.SystemManager.runSystems(wCtx); err != nil {
+	if w.determinismAudit != nil {
+		if err := w.runAuditedSystems(ctx, wCtx, txPool); err != nil {
+			return err
+		}
+	} else if err := w.SystemManager.runSystems(ctx, wCtx); err != nil {
+		return err
+	}
+
+	// The receipts of the expired transactions are recorded after the systems ran, since the determinism audit
+	// discards the receipts of the tick between the runs of the systems
+	for _, tx := range expired {
+		w.receiptHistory.AddError(tx.TxHash, eris.Wrapf(receipt.ErrTransactionExpired, "expired at tick %d",
+			tx.Tx.ExpiresAtTick))
+	}
+	for _, tx := range revoked {
+		w.receiptHistory.AddError(tx.TxHash, eris.Wrap(persona.ErrSessionKeyNotAuthorized, ""))
+	}
+
+	// Run the triggers of the components that changed during the tick
+	if err := w.runTriggers(wCtx); err != nil {
+		return err
+	}
+
+	// Remove the entities that were destroyed during the tick, now that no system or trigger can observe them anymore
+	if err := w.removeDestroyedEntities(wCtx); err != nil {
+		return err
+	}
+
+	if w.archiveAfter > 0 {
+		if err := w.archiveInactiveEntities(); err != nil {
+			return err
+		}
+	}
+
+	// The inputs of the tick are acknowledged in the same transaction as the state changes they caused
+	if err := w.ackInputs(txPool); err != nil {
+		return err
+	}
+
+	// Record the tick's events and receipts so they are committed atomically with the tick's state changes
+	if w.recordEvents {
+		if err := w.recordTickLog(timestamp); err != nil {
+			return err
+		}
+		if err := w.pruneEventHistory(timestamp); err != nil {
+			return err
+		}
+	}
+
+	if err := w.faults.Inject(ctx, faults.TickSystems); err != nil {
 		return err
 	}
 
@@ -245,8 +595,17 @@ func (w *World) doTick(ctx context.Context, timestamp uint64) (err error) {
 		return err
 	}
 	statsd.EmitTickStat(finalizeTickStartTime, "finalize")
+	if err := w.faults.Inject(ctx, faults.TickCommitted); err != nil {
+		return err
+	}
+	// The tick is committed, so it is not run again
+	if w.storageBreaker != nil {
+		w.storageBreaker.tickSucceeded(time.Now())
+	}
 
 	w.setEvmResults(txPool.GetEVMTxs())
+	// The EVM is also told about the transactions it sent that expired
+	w.setEvmResults(expired)
 
 	// Handle tx data blob submission
 	// Only submit transactions when the following criteria is satisfied:
@@ -257,11 +616,15 @@ func (w *World) doTick(ctx context.Context, timestamp uint64) (err error) {
 		if err != nil {
 			return fmt.Errorf("failed to submit transactions to base shard: %w", err)
 		}
+		w.deliverOutbox(ctx)
 	}
 
 	// Increment the tick
 	w.tick.Add(1)
 	w.receiptHistory.NextTick() // todo(scott): use channels
+	if w.eventLog != nil {
+		w.eventLog.NotifyTickDone()
+	}
 
 	// Populate world.TickResults for the current tick and emit it as an Event
 	flushEventStart := time.Now()
@@ -271,6 +634,9 @@ func (w *World) doTick(ctx context.Context, timestamp uint64) (err error) {
 	// Clear the TickResults for this tick in preparation for the next Tick
 	w.tickResults.Clear()
 
+	w.runLifecycleHooks(ctx, LifecycleTickEnded, w.CurrentTick()-1)
+	w.tickProfiler.tickEnded(w.CurrentTick() - 1)
+
 	statsd.EmitTickStat(startTime, "full_tick")
 	if err := statsd.Client().Count("num_of_txs", int64(txPool.GetAmountOfTxs()), nil, 1); err != nil {
 		log.Warn().Msgf("failed to emit count stat:%v", err)
@@ -279,6 +645,26 @@ func (w *World) doTick(ctx context.Context, timestamp uint64) (err error) {
 	return nil
 }
 
+// removeDestroyedEntities removes the entities that were destroyed during the tick, in ID order.
+func (w *World) removeDestroyedEntities(wCtx engine.Context) error {
+	if len(w.destroyed) == 0 {
+		return nil
+	}
+	ids := make([]types.EntityID, 0, len(w.destroyed))
+	for id := range w.destroyed {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	clear(w.destroyed)
+	for _, id := range ids {
+		// The entity may have been removed with Remove after it was destroyed
+		if err := Remove(wCtx, id); err != nil && !eris.Is(err, ErrEntityDoesNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
 // StartGame starts running the world game loop. Each time a message arrives on the tickChannel, a world tick is
 // attempted. In addition, an HTTP server (listening on the given port) is created so that game messages can be sent
 // to this world. After StartGame is called, RegisterComponent, registerMessagesByName,
@@ -291,9 +677,35 @@ func (w *World) StartGame() error {
 		return errors.New("game has already been started")
 	}
 
+	if w.readReplica != nil {
+		return w.startReadReplica()
+	}
+
+	// The world only writes to its namespace while it owns it. A standby waits until the leader fails before it loads
+	// the game state, which the leader is still changing. Meanwhile, it accepts transactions for the leader
+	if w.leaderElection {
+		stopStandbyServer, err := w.startStandbyServer()
+		if err != nil {
+			return err
+		}
+		leads := w.waitForLeadership()
+		stopStandbyServer()
+		if !leads {
+			w.worldStage.Store(worldstage.ShutDown)
+			return nil
+		}
+	} else if err := w.claimNamespace(); err != nil {
+		return err
+	}
+	defer w.resignNamespace()
+
 	// TODO(scott): entityStore.RegisterComponents is ambiguous with cardinal.RegisterComponent.
 	//  We should probably rename this to LoadComponents or osmething.
-	if err := w.entityStore.RegisterComponents(w.componentManager.GetComponents()); err != nil {
+	err := w.entityStore.RegisterComponents(w.componentManager.GetComponents())
+	if err == nil {
+		err = w.entityStore.RegisterMessages(w.msgManager.GetRegisteredMessages())
+	}
+	if err != nil {
 		closeErr := w.entityStore.Close()
 		if closeErr != nil {
 			return eris.Wrap(err, closeErr.Error())
@@ -301,8 +713,38 @@ func (w *World) StartGame() error {
 		return err
 	}
 
-	// Start router if it is set
-	if w.router != nil {
+	if w.rawStorageQuota != nil {
+		ecb, ok := w.entityStore.(*gamestate.EntityCommandBuffer)
+		if !ok {
+			return eris.New("raw storage quota can only be set when using the default store manager")
+		}
+		ecb.SetRawStorageQuota(*w.rawStorageQuota)
+	}
+
+	if w.recordStateDiffs {
+		ecb, ok := w.entityStore.(*gamestate.EntityCommandBuffer)
+		if !ok {
+			return eris.New("state diffs can only be recorded when using the default store manager")
+		}
+		ecb.RecordStateDiffs(w.stateDiffsKept)
+	}
+
+	// Start event log server if it is set
+	if w.eventLog != nil {
+		if err := w.eventLog.Start(); err != nil {
+			return eris.Wrap(err, "failed to start event log service")
+		}
+	}
+
+	// Start admin server if it is set
+	if w.adminServer != nil {
+		if err := w.adminServer.Start(); err != nil {
+			return eris.Wrap(err, "failed to start admin service")
+		}
+	}
+
+	// Start router if it is set, unless its EVM server is disabled
+	if w.router != nil && w.evmServerEnabled {
 		if err := w.router.Start(); err != nil {
 			return eris.Wrap(err, "failed to start router service")
 		}
@@ -311,9 +753,18 @@ func (w *World) StartGame() error {
 		}
 	}
 
+	// The genesis file is loaded before recovery, in case tick 0 is replayed. Its entities are only created at tick 0.
+	if w.genesisFile != "" {
+		spawns, err := w.loadGenesis(w.genesisFile)
+		if err != nil {
+			return err
+		}
+		w.SystemManager.setGenesis(genesisSystem(spawns))
+	}
+
 	w.worldStage.Store(worldstage.Recovering)
 	// Recover pending transactions from redis
-	err := w.recoverAndExecutePendingTxs()
+	err = w.recoverAndExecutePendingTxs()
 	if err != nil {
 		return err
 	}
@@ -324,7 +775,14 @@ func (w *World) StartGame() error {
 			return eris.Wrap(err, "failed to recover from chain")
 		}
 	}
+	if err := w.buildUniqueIndexes(NewWorldContext(w)); err != nil {
+		return err
+	}
+	if err := w.buildFieldIndexes(NewWorldContext(w)); err != nil {
+		return err
+	}
 	w.worldStage.Store(worldstage.Ready)
+	w.runLifecycleHooks(context.Background(), LifecycleGameStateLoaded, w.CurrentTick())
 
 	// TODO(scott): i find this manual tracking and incrementing of the tick very footgunny. Why can't we just
 	//  use a reliable source of truth for the tick? It's not clear to me why we need to manually increment the
@@ -358,17 +816,27 @@ func (w *World) StartGame() error {
 	// Log world info
 	ecslog.World(&log.Logger, w, zerolog.InfoLevel)
 
+	// The hooks run before the world is marked as running, so that they have run once IsGameRunning returns true
+	w.runLifecycleHooks(context.Background(), LifecycleWorldStarted, w.CurrentTick())
+
 	// Game stage: Ready -> Running
 	w.worldStage.Store(worldstage.Running)
 
 	// Start the game loop
-	w.startGameLoop(context.Background(), w.tickChannel, w.tickDoneChannel)
+	tickChannel := w.tickChannel
+	if tickChannel == nil {
+		tickChannel = w.tickClock.start(w.worldStage.NotifyOnStage(worldstage.ShutDown))
+	}
+	w.startGameLoop(w.tickCtx, tickChannel, w.tickDoneChannel)
 
-	// Start the server
-	w.startServer()
+	// Worlds hosted by a WorldManager are served by the manager's HTTP server and are shut down by the manager
+	if !w.managed {
+		// Start the server
+		w.startServer()
 
-	// handle shutdown via a signal
-	w.handleShutdown()
+		// handle shutdown via a signal
+		w.handleShutdown()
+	}
 	<-w.worldStage.NotifyOnStage(worldstage.ShutDown)
 	return err
 }
@@ -390,17 +858,21 @@ func (w *World) startGameLoop(ctx context.Context, tickStart <-chan time.Time, t
 	loop:
 		for {
 			select {
-			case _, ok := <-tickStart:
+			case scheduled, ok := <-tickStart:
 				if !ok {
 					panic("tickStart channel has been closed; tick rate is now unbounded.")
 				}
+				if w.paused.Load() {
+					continue
+				}
+				w.tickClock.recordStart(scheduled, time.Now())
 				w.tickTheEngine(ctx, tickDone)
 				closeAllChannels(waitingChs)
 				waitingChs = waitingChs[:0]
-			case <-w.worldStage.NotifyOnStage(worldstage.ShuttingDown):
+			case shutdownCtx := <-w.stopGameLoop:
 				w.drainChannelsWaitingForNextTick()
 				closeAllChannels(waitingChs)
-				if w.txPool.GetAmountOfTxs() > 0 {
+				if w.txPool.GetAmountOfTxs() > 0 && shutdownCtx.Err() == nil {
 					// immediately tick if pool is not empty to process all txs if queue is not empty.
 					w.tickTheEngine(ctx, tickDone)
 					if tickDone != nil {
@@ -410,6 +882,8 @@ func (w *World) startGameLoop(ctx context.Context, tickStart <-chan time.Time, t
 				break loop
 			case ch := <-w.addChannelWaitingForNextTick:
 				waitingChs = append(waitingChs, ch)
+			case fn := <-w.betweenTicks:
+				fn()
 			}
 		}
 		w.worldStage.Store(worldstage.ShutDown)
@@ -417,11 +891,36 @@ func (w *World) startGameLoop(ctx context.Context, tickStart <-chan time.Time, t
 }
 
 func (w *World) tickTheEngine(ctx context.Context, tickDone chan<- uint64) {
+	if w.debugger != nil {
+		defer w.debugger.tickEnded()
+	}
+	if w.skipTickForStorage(time.Now()) {
+		return
+	}
+	err := w.checkFailedTickCommitted()
 	currTick := w.CurrentTick()
 	// this is the final point where errors bubble up and hit a panic. There are other places where this occurs
 	// but this is the highest terminal point.
 	// the panic may point you to here, (or the tick function) but the real stack trace is in the error message.
-	err := w.doTick(ctx, uint64(time.Now().Unix()))
+	if err == nil {
+		err = w.doTick(ctx, w.tickTimestamp(time.Now()))
+	}
+	if err != nil && ctx.Err() != nil {
+		// Shutdown aborted the tick, which is not committed, so the world resumes from the previous tick when it is
+		// restarted
+		log.Error().Err(err).Msgf("Tick %d was aborted by the shutdown.", currTick)
+		return
+	}
+	if err != nil && w.handleStorageError(err) {
+		return
+	}
+	if errors.Is(err, ErrNamespaceTakenOver) || errors.Is(err, gamestate.ErrFenced) {
+		log.Error().Err(err).Msg("Another world instance is running in the same namespace. Shutting down.")
+		if w.worldStage.Current() == worldstage.Running {
+			go w.shutdownAfterTakeover()
+		}
+		return
+	}
 	if err != nil {
 		bytes, errMarshal := json.Marshal(eris.ToJSON(err, true))
 		if errMarshal != nil {
@@ -429,6 +928,10 @@ func (w *World) tickTheEngine(ctx context.Context, tickDone chan<- uint64) {
 		}
 		panic(string(bytes))
 	}
+	w.saveAutoCheckpoint()
+	w.saveStateCommitment(ctx)
+	w.streamStateDiffs()
+	w.localPersistence.save()
 	if tickDone != nil {
 		tickDone <- currTick
 	}
@@ -438,40 +941,131 @@ func (w *World) IsGameRunning() bool {
 	return w.worldStage.Current() == worldstage.Running
 }
 
-func (w *World) Shutdown() error {
+// Shutdown gracefully shuts down the world. It first stops the HTTP and EVM servers so that no new transactions are
+// accepted, then lets the in-flight tick finish and executes the transactions that are still queued in a final tick,
+// and finally releases the namespace and closes the storage connection.
+//
+// If ctx is done before the ticks finish, the final tick is skipped and the in-flight tick is aborted: its context is
+// cancelled, so that it fails at its next storage operation instead of being committed. Ticks are committed
+// atomically, so the world resumes from the last committed tick when it is restarted. The rest of the shutdown still
+// runs, within shutdownCleanupTimeout, and the timeout is returned along with the errors of the cleanup.
+func (w *World) Shutdown(ctx context.Context) error {
 	log.Info().Msg("Shutting down game loop.")
 	ok := w.worldStage.CompareAndSwap(worldstage.Running, worldstage.ShuttingDown)
 	if !ok {
 		select {
 		case <-w.worldStage.NotifyOnStage(worldstage.ShuttingDown):
-			// Some other goroutine has already started the shutdown process. Wait until the world is
-			// actually shut down.
-			<-w.worldStage.NotifyOnStage(worldstage.ShutDown)
+			// Some other goroutine has already started the shutdown process, and cleans up once the world is shut down.
+			// Wait until the world is actually shut down.
+			select {
+			case <-w.worldStage.NotifyOnStage(worldstage.ShutDown):
+			case <-ctx.Done():
+				return eris.Wrap(ctx.Err(), "timed out waiting for the world to shut down")
+			}
 			return nil
 		default:
 		}
+		if w.leaderElection && w.worldStage.Current() == worldstage.Starting {
+			// A standby that is waiting to become the leader has nothing to stop yet but its storage connection
+			w.lease.stopRenewal()
+			var timeoutErr error
+			select {
+			case <-w.worldStage.NotifyOnStage(worldstage.ShutDown):
+			case <-ctx.Done():
+				timeoutErr = eris.Wrap(ctx.Err(), "timed out waiting for the standby to stop")
+				var cancel context.CancelFunc
+				ctx, cancel = cleanupContext(ctx)
+				defer cancel()
+			}
+			return errors.Join(timeoutErr, w.closeStorage(ctx))
+		}
 		return errors.New("shutdown attempted before the world was started")
 	}
 
+	// Stop accepting transactions, so that every transaction that was accepted is executed before the world stops.
+	var serverErr error
+	if w.server != nil {
+		if serverErr = w.server.Shutdown(); serverErr != nil {
+			log.Error().Err(serverErr).Msg("Failed to shut down server.")
+		}
+	}
+	if w.router != nil && w.evmServerEnabled {
+		w.router.Shutdown()
+	}
+
+	// A tick that is stopped at a breakpoint would keep the game loop from stopping
+	if w.debugger != nil {
+		w.debugger.release()
+	}
+
 	// Block until the world has stopped ticking
-	<-w.worldStage.NotifyOnStage(worldstage.ShutDown)
+	w.stopGameLoop <- ctx
+	var timeoutErr error
+	select {
+	case <-w.worldStage.NotifyOnStage(worldstage.ShutDown):
+	case <-ctx.Done():
+		timeoutErr = eris.Wrap(ctx.Err(), "timed out waiting for the in-flight tick to finish")
+		log.Error().Err(timeoutErr).Msg("Aborting the in-flight tick.")
+		w.abortTick()
+		// Systems are not interrupted, so the tick may only stop at its next storage operation
+		select {
+		case <-w.worldStage.NotifyOnStage(worldstage.ShutDown):
+		case <-time.After(tickAbortGracePeriod):
+			log.Warn().Msg("The aborted tick is still running, shutting down anyway.")
+		}
+		var cancel context.CancelFunc
+		ctx, cancel = cleanupContext(ctx)
+		defer cancel()
+	}
+	w.runLifecycleHooks(ctx, LifecycleShutdown, w.CurrentTick())
+	w.stateCommitments.Wait()
 
-	if w.server != nil {
-		if err := w.server.Shutdown(); err != nil {
-			return err
+	if w.eventLog != nil {
+		w.eventLog.Shutdown()
+	}
+
+	if w.adminServer != nil {
+		w.adminServer.Shutdown()
+	}
+
+	var tracingErr error
+	if w.shutdownTracing != nil {
+		if tracingErr = w.shutdownTracing(ctx); tracingErr != nil {
+			log.Error().Err(tracingErr).Msg("Failed to flush traces.")
 		}
 	}
 
 	log.Info().Msg("Successfully shut down game loop.")
+	w.localPersistence.save()
+	return errors.Join(timeoutErr, serverErr, tracingErr, w.closeStorage(ctx))
+}
+
+// cleanupContext returns the context for the cleanup of a shutdown whose context is done, which keeps the values of
+// ctx but gets a new deadline.
+func cleanupContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.WithoutCancel(ctx), shutdownCleanupTimeout)
+}
+
+// closeStorage releases the namespace of the world and closes its storage connections.
+func (w *World) closeStorage(ctx context.Context) error {
+	var errs []error
+	if err := w.redisStorage.ReleaseNamespace(ctx, w.instanceID); err != nil {
+		log.Error().Err(err).Msg("Failed to release namespace.")
+		errs = append(errs, err)
+	}
 	log.Info().Msg("Closing storage connection.")
-	err := w.redisStorage.Close()
-	if err != nil {
+	if err := w.namespaces.Close(); err != nil {
 		log.Error().Err(err).Msg("Failed to close storage connection.")
-		return err
+		errs = append(errs, err)
 	}
-	log.Info().Msg("Successfully closed storage connection.")
-
-	return nil
+	if err := w.redisStorage.Close(); err != nil {
+		log.Error().Err(err).Msg("Failed to close storage connection.")
+		errs = append(errs, err)
+	}
+	if len(errs) == 0 {
+		log.Info().Msg("Successfully closed storage connection.")
+	}
+	return errors.Join(errs...)
 }
 
 func (w *World) handleShutdown() {
@@ -480,7 +1074,9 @@ func (w *World) handleShutdown() {
 		signal.Notify(signalChannel, syscall.SIGINT, syscall.SIGTERM)
 		for sig := range signalChannel {
 			if sig == syscall.SIGINT || sig == syscall.SIGTERM {
-				err := w.Shutdown()
+				ctx, cancel := context.WithTimeout(context.Background(), DefaultShutdownTimeout)
+				err := w.Shutdown(ctx)
+				cancel()
 				if err != nil {
 					log.Err(err).Msgf("There was an error during shutdown.")
 				}
@@ -528,26 +1124,53 @@ func (w *World) drainChannelsWaitingForNextTick() {
 // Instead, use a MessageType.AddTransaction to ensure type consistency. Returns the tick this transaction will be
 // executed in.
 func (w *World) AddTransaction(id types.MessageID, v any, sig *sign.Transaction) (
-	tick uint64, txHash types.TxHash,
+	tick uint64, txHash types.TxHash, err error,
+) {
+	return w.AddTransactionWithContext(context.Background(), id, v, sig)
+}
+
+// AddTransactionWithContext is like AddTransaction, but the span of the tick that executes the transaction links to
+// the span in ctx, so the tick can be found from the trace of the request that submitted the transaction.
+//
+// With WithReplicatedTxQueue, the transaction is added to the queue shared by the instances of the world instead, and
+// an error is returned if it can't be queued. It is never added to the pool of this instance, since the instance that
+// ticks could execute it as well if it was submitted to it too.
+func (w *World) AddTransactionWithContext(ctx context.Context, id types.MessageID, v any, sig *sign.Transaction) (
+	tick uint64, txHash types.TxHash, err error,
 ) {
 	// TODO: There's no locking between getting the tick and adding the transaction, so there's no guarantee that this
 	// transaction is actually added to the returned tick.
 	tick = w.CurrentTick()
-	txHash = w.txPool.AddTransaction(id, v, sig)
-	return tick, txHash
+	if w.replicatedTxs {
+		if err = w.enqueueReplicatedTx(id, v, sig, ""); err != nil {
+			return 0, "", err
+		}
+		return tick, types.TxHash(sig.HashHex()), nil
+	}
+	txHash = w.txPool.AddTransactionWithContext(ctx, id, v, sig)
+	return tick, txHash, nil
 }
 
+// AddEVMTransaction adds a transaction that was sent by an EVM transaction on the base shard, whose result is kept
+// for the base shard under the hash of the EVM transaction. See AddTransactionWithContext.
 func (w *World) AddEVMTransaction(
+	ctx context.Context,
 	id types.MessageID,
 	v any,
 	sig *sign.Transaction,
 	evmTxHash string,
 ) (
-	tick uint64, txHash types.TxHash,
+	tick uint64, txHash types.TxHash, err error,
 ) {
 	tick = w.CurrentTick()
-	txHash = w.txPool.AddEVMTransaction(id, v, sig, evmTxHash)
-	return tick, txHash
+	if w.replicatedTxs {
+		if err = w.enqueueReplicatedTx(id, v, sig, evmTxHash); err != nil {
+			return 0, "", err
+		}
+		return tick, types.TxHash(sig.HashHex()), nil
+	}
+	txHash = w.txPool.AddEVMTransaction(ctx, id, v, sig, evmTxHash)
+	return tick, txHash, nil
 }
 
 func (w *World) UseNonce(signerAddress string, nonce uint64) error {
@@ -587,7 +1210,15 @@ func (w *World) HandleEVMQuery(name string, abiRequest []byte) ([]byte, error) {
 		return nil, err
 	}
 
-	return qry.EncodeEVMReply(reply)
+	bz, err := qry.EncodeEVMReply(reply)
+	if err != nil {
+		return nil, err
+	}
+	if w.maxReplyBytes > 0 && len(bz) > w.maxReplyBytes {
+		return nil, eris.Wrapf(handler.ErrReplyTooLarge, "reply to %s is %d bytes, the limit is %d bytes", name, len(bz),
+			w.maxReplyBytes)
+	}
+	return bz, nil
 }
 
 func (w *World) Search(filter filter.ComponentFilter) search.EntitySearch {
@@ -598,6 +1229,13 @@ func (w *World) StoreReader() gamestate.Reader {
 	return w.entityStore.ToReadOnly()
 }
 
+// ArchetypeStats returns the component set, the number of entities and the approximate size of every archetype in the
+// committed game state. It reads the components of a sample of the entities of every archetype, so it is meant for
+// capacity planning and leak hunting rather than for systems.
+func (w *World) ArchetypeStats() ([]gamestate.ArchetypeStat, error) {
+	return gamestate.GetArchetypeStats(w.StoreReader())
+}
+
 func (w *World) GetRegisteredQueries() []engine.Query {
 	return w.queryManager.GetRegisteredQueries()
 }
@@ -629,6 +1267,25 @@ func (w *World) GetComponentByName(name string) (types.ComponentMetadata, error)
 	return w.componentManager.GetComponentByName(name)
 }
 
+// GetTickLog returns the serialized event log entry of a completed tick.
+func (w *World) GetTickLog(tick uint64) ([]byte, error) {
+	return w.entityStore.GetTickLog(tick)
+}
+
+// recordTickLog buffers the event log entry of the current tick in the entity store.
+func (w *World) recordTickLog(timestamp uint64) error {
+	entry, err := eventlog.NewTickEntry(
+		w.CurrentTick(),
+		timestamp,
+		w.tickResults.Events,
+		w.receiptHistory.GetReceiptsForCurrentTick(),
+	)
+	if err != nil {
+		return err
+	}
+	return w.entityStore.SetTickLog(w.CurrentTick(), entry)
+}
+
 func (w *World) populateAndBroadcastTickResults() {
 	receipts, err := w.receiptHistory.GetReceiptsForTick(w.CurrentTick() - 1)
 	if err != nil {
@@ -637,6 +1294,11 @@ func (w *World) populateAndBroadcastTickResults() {
 	w.tickResults.SetReceipts(receipts)
 	w.tickResults.SetTick(w.CurrentTick() - 1)
 
+	// Recovery can run ticks before StartGame has created the server, in which case there are no clients yet.
+	if w.server == nil {
+		return
+	}
+
 	// Broadcast the tick results to all clients
 	err = w.server.BroadcastEvent(w.tickResults)
 	if err != nil {
@@ -0,0 +1,317 @@
+package cardinal
+
+import (
+	"context"
+	"slices"
+	"strconv"
+
+	"github.com/rotisserie/eris"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+
+	"pkg.world.dev/world-engine/cardinal/admin"
+	"pkg.world.dev/world-engine/cardinal/gamestate"
+	"pkg.world.dev/world-engine/cardinal/storage/redis"
+	"pkg.world.dev/world-engine/cardinal/types/txpool"
+	"pkg.world.dev/world-engine/cardinal/worldstage"
+)
+
+const (
+	// ConfigKeyLogLevel changes the global log level. Valid values are the same as for CARDINAL_LOG_LEVEL.
+	ConfigKeyLogLevel = "CARDINAL_LOG_LEVEL"
+	// ConfigKeyMaxEntitiesPerPersona changes EntityQuota.MaxEntitiesPerPersona.
+	ConfigKeyMaxEntitiesPerPersona = "ENTITY_QUOTA_MAX_PER_PERSONA"
+	// ConfigKeyMaxEntitiesPerSystemPerTick changes EntityQuota.MaxEntitiesPerSystemPerTick.
+	ConfigKeyMaxEntitiesPerSystemPerTick = "ENTITY_QUOTA_MAX_PER_SYSTEM_PER_TICK"
+	// ConfigKeyMaxEntities changes EntityQuota.MaxEntities.
+	ConfigKeyMaxEntities = "ENTITY_QUOTA_MAX_ENTITIES"
+	// ConfigKeyMaxComponents changes EntityQuota.MaxComponents.
+	ConfigKeyMaxComponents = "ENTITY_QUOTA_MAX_COMPONENTS"
+
+	// AutoCheckpointName is the name of the checkpoint that is saved by WithAutoCheckpoint.
+	AutoCheckpointName = "auto"
+
+	// bannedPersonasKey is the redis hash that maps banned persona tags to the reason for the ban. It is not part of
+	// the game state, so bans survive a rollback.
+	bannedPersonasKey = "ADMIN:BANNED-PERSONAS"
+)
+
+var (
+	ErrWorldNotRunning    = admin.ErrNotRunning
+	ErrSystemNotFound     = admin.ErrSystemNotFound
+	ErrUnknownConfigKey   = admin.ErrUnknownConfigKey
+	ErrCheckpointNotFound = gamestate.ErrCheckpointNotFound
+	ErrMessageNotFound    = admin.ErrMessageNotFound
+)
+
+var _ admin.Provider = &World{} //nolint:exhaustruct
+
+// Pause stops the game loop from ticking until Resume is called. A tick that is in progress is completed first.
+// Transactions are still accepted while the world is paused and are processed once it resumes.
+func (w *World) Pause() error {
+	if !w.IsGameRunning() {
+		return eris.Wrap(ErrWorldNotRunning, "cannot pause")
+	}
+	if w.paused.CompareAndSwap(false, true) {
+		w.runLifecycleHooks(context.Background(), LifecyclePaused, w.CurrentTick())
+	}
+	return nil
+}
+
+// Resume continues ticking a paused world.
+func (w *World) Resume() error {
+	if !w.IsGameRunning() {
+		return eris.Wrap(ErrWorldNotRunning, "cannot resume")
+	}
+	if w.paused.CompareAndSwap(true, false) {
+		w.runLifecycleHooks(context.Background(), LifecycleResumed, w.CurrentTick())
+	}
+	return nil
+}
+
+// IsPaused returns true if the world is paused.
+func (w *World) IsPaused() bool {
+	return w.paused.Load()
+}
+
+// SaveCheckpoint saves the current game state as a checkpoint with the given name, replacing any existing checkpoint
+// with that name. The checkpoint is saved between two ticks.
+func (w *World) SaveCheckpoint(name string) (gamestate.CheckpointInfo, error) {
+	ecb, err := w.checkpointStore()
+	if err != nil {
+		return gamestate.CheckpointInfo{}, err
+	}
+	var info gamestate.CheckpointInfo
+	err = w.runBetweenTicks(func() error {
+		info, err = ecb.SaveCheckpoint(name)
+		return err
+	})
+	return info, err
+}
+
+// ListCheckpoints returns all saved checkpoints, sorted by name.
+func (w *World) ListCheckpoints() ([]gamestate.CheckpointInfo, error) {
+	ecb, err := w.checkpointStore()
+	if err != nil {
+		return nil, err
+	}
+	return ecb.ListCheckpoints()
+}
+
+// DeleteCheckpoint deletes the checkpoint with the given name.
+func (w *World) DeleteCheckpoint(name string) error {
+	ecb, err := w.checkpointStore()
+	if err != nil {
+		return err
+	}
+	return w.runBetweenTicks(func() error {
+		return ecb.DeleteCheckpoint(name)
+	})
+}
+
+// RollbackToCheckpoint replaces the game state with the state saved in the given checkpoint. The world continues
+// ticking from the tick at which the checkpoint was saved. Rolling back is not supported in rollup mode, since the
+// transactions of the rolled back ticks have already been submitted to the base shard.
+func (w *World) RollbackToCheckpoint(name string) (gamestate.CheckpointInfo, error) {
+	if w.router != nil {
+		return gamestate.CheckpointInfo{}, eris.New("cannot roll back a world that runs in rollup mode")
+	}
+	ecb, err := w.checkpointStore()
+	if err != nil {
+		return gamestate.CheckpointInfo{}, err
+	}
+	var info gamestate.CheckpointInfo
+	err = w.runBetweenTicks(func() error {
+		info, err = ecb.RestoreCheckpoint(name)
+		if err != nil {
+			return err
+		}
+		timestamp, err := ecb.GetTickTimestamp()
+		if err != nil {
+			return err
+		}
+		w.tick.Store(info.Tick)
+		w.timestamp.Store(timestamp)
+		w.receiptHistory.SetTick(info.Tick)
+		w.entityQuota.reset()
+		clear(w.destroyed)
+		forgetPersonaIndex(w.Namespace())
+		return nil
+	})
+	return info, err
+}
+
+// EnableSystem enables a system that was disabled with DisableSystem.
+func (w *World) EnableSystem(name string) error {
+	return w.SystemManager.setSystemEnabled(name, true)
+}
+
+// DisableSystem makes the world skip the system with the given name until it is enabled again. Disabled systems are
+// not persisted; all systems are enabled when the world restarts.
+func (w *World) DisableSystem(name string) error {
+	return w.SystemManager.setSystemEnabled(name, false)
+}
+
+// UpdateConfig changes a config value of the running world. Only the ConfigKey* values can be updated.
+func (w *World) UpdateConfig(key, value string) error {
+	switch key {
+	case ConfigKeyLogLevel:
+		if !slices.Contains(validLogLevels, value) {
+			return eris.Wrapf(admin.ErrInvalidValue, "%s must be one of %v", key, validLogLevels)
+		}
+		level, err := zerolog.ParseLevel(value)
+		if err != nil {
+			return eris.Wrapf(admin.ErrInvalidValue, "%s: %v", key, err)
+		}
+		zerolog.SetGlobalLevel(level)
+		return nil
+	case ConfigKeyMaxEntitiesPerPersona, ConfigKeyMaxEntitiesPerSystemPerTick, ConfigKeyMaxEntities,
+		ConfigKeyMaxComponents:
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 0 {
+			return eris.Wrapf(admin.ErrInvalidValue, "%s must be a non-negative integer", key)
+		}
+		// The quota is read by systems, so it is only changed between ticks.
+		return w.runBetweenTicks(func() error {
+			quota := &w.entityQuota.quota
+			switch key {
+			case ConfigKeyMaxEntitiesPerPersona:
+				quota.MaxEntitiesPerPersona = limit
+			case ConfigKeyMaxEntitiesPerSystemPerTick:
+				quota.MaxEntitiesPerSystemPerTick = limit
+			case ConfigKeyMaxEntities:
+				quota.MaxEntities = limit
+			case ConfigKeyMaxComponents:
+				quota.MaxComponents = limit
+			}
+			return nil
+		})
+	default:
+		return eris.Wrapf(ErrUnknownConfigKey, "%q", key)
+	}
+}
+
+// BanPersona makes the world reject all transactions signed by the given persona tag.
+func (w *World) BanPersona(personaTag, reason string) error {
+	err := w.redisStorage.Client.HSet(context.Background(), bannedPersonasKey, personaTag, reason).Err()
+	return eris.Wrapf(err, "failed to ban persona %q", personaTag)
+}
+
+// UnbanPersona lifts the ban of the given persona tag.
+func (w *World) UnbanPersona(personaTag string) error {
+	err := w.redisStorage.Client.HDel(context.Background(), bannedPersonasKey, personaTag).Err()
+	return eris.Wrapf(err, "failed to unban persona %q", personaTag)
+}
+
+// ListBans returns the banned persona tags and the reason for each ban.
+func (w *World) ListBans() (map[string]string, error) {
+	bans, err := w.redisStorage.Client.HGetAll(context.Background(), bannedPersonasKey).Result()
+	if err != nil {
+		return nil, eris.Wrap(err, "failed to list bans")
+	}
+	return bans, nil
+}
+
+// IsPersonaBanned returns true if the given persona tag is banned.
+func (w *World) IsPersonaBanned(personaTag string) (bool, error) {
+	banned, err := w.redisStorage.Client.HExists(context.Background(), bannedPersonasKey, personaTag).Result()
+	if err != nil {
+		return false, eris.Wrapf(err, "failed to look up ban of persona %q", personaTag)
+	}
+	return banned, nil
+}
+
+// ListNamespaces returns the namespaces of all worlds that store their state in the same redis database as this world.
+func (w *World) ListNamespaces() ([]redis.NamespaceInfo, error) {
+	return w.namespaces.List(context.Background())
+}
+
+// PurgeNamespace deletes all keys of another world's namespace, e.g. of a world that was decommissioned, and returns
+// the number of deleted keys. A namespace that is owned by a running world is only purged if force is true. The
+// namespace of this world can never be purged.
+func (w *World) PurgeNamespace(namespace string, force bool) (uint64, error) {
+	if err := Namespace(namespace).Validate(); err != nil {
+		return 0, eris.Wrapf(admin.ErrInvalidValue, "%q is not a valid namespace", namespace)
+	}
+	if namespace == w.Namespace() {
+		return 0, eris.Wrap(ErrNamespaceInUse, "cannot purge the namespace of this world")
+	}
+	keys, err := w.namespaces.Purge(context.Background(), namespace, force)
+	if err != nil {
+		return keys, err
+	}
+	log.Warn().Uint64("keys", keys).Msgf("Purged namespace %q", namespace)
+	return keys, nil
+}
+
+// GetTxQueueStats returns the number of transactions of every message that wait for the next tick, and when the oldest
+// of them was submitted.
+func (w *World) GetTxQueueStats() []admin.TxQueueStat {
+	poolStats := w.txPool.Stats()
+	stats := make([]admin.TxQueueStat, 0, len(poolStats))
+	for _, stat := range poolStats {
+		msg, ok := w.GetMessageByID(stat.MsgID)
+		if !ok {
+			continue
+		}
+		stats = append(stats, admin.TxQueueStat{
+			Message:       msg.FullName(),
+			Pending:       stat.Pending,
+			OldestAddedAt: stat.OldestAddedAt,
+		})
+	}
+	return stats
+}
+
+// DrainTxQueue drops the transactions of the given message that wait for the next tick and returns them. The dropped
+// transactions are never executed, so they get no receipt.
+func (w *World) DrainTxQueue(msgFullName string) ([]txpool.TxData, error) {
+	msg, ok := w.GetMessageByFullName(msgFullName)
+	if !ok {
+		return nil, eris.Wrapf(ErrMessageNotFound, "%q", msgFullName)
+	}
+	txs := w.txPool.Drain(msg.ID())
+	if len(txs) > 0 {
+		log.Warn().Int("dropped", len(txs)).Msgf("Drained the pending transactions of message %q", msgFullName)
+	}
+	return txs, nil
+}
+
+// checkpointStore returns the entity store as an EntityCommandBuffer, which is the only store that supports
+// checkpoints.
+func (w *World) checkpointStore() (*gamestate.EntityCommandBuffer, error) {
+	ecb, ok := w.entityStore.(*gamestate.EntityCommandBuffer)
+	if !ok {
+		return nil, eris.New("checkpoints can only be used with the default store manager")
+	}
+	return ecb, nil
+}
+
+// saveAutoCheckpoint saves the AutoCheckpointName checkpoint if the tick that just completed is a multiple of the
+// automatic checkpoint interval. Failures are logged, since a missed checkpoint must not stop the game.
+func (w *World) saveAutoCheckpoint() {
+	if w.autoCheckpointTicks == 0 || w.CurrentTick()%w.autoCheckpointTicks != 0 {
+		return
+	}
+	ecb, err := w.checkpointStore()
+	if err == nil {
+		_, err = ecb.SaveCheckpoint(AutoCheckpointName)
+	}
+	if err != nil {
+		log.Error().Err(err).Uint64("tick", w.CurrentTick()).Msg("failed to save automatic checkpoint")
+	}
+}
+
+// runBetweenTicks runs fn on the game loop while no tick is in progress and returns its error.
+func (w *World) runBetweenTicks(fn func() error) error {
+	if !w.IsGameRunning() {
+		return eris.Wrap(ErrWorldNotRunning, "")
+	}
+	done := make(chan error, 1)
+	select {
+	case w.betweenTicks <- func() { done <- fn() }:
+	case <-w.worldStage.NotifyOnStage(worldstage.ShuttingDown):
+		return eris.Wrap(ErrWorldNotRunning, "world is shutting down")
+	}
+	return <-done
+}
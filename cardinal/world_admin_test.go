@@ -0,0 +1,298 @@
+package cardinal_test
+
+import (
+	"testing"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/admin"
+	"pkg.world.dev/world-engine/cardinal/message"
+	"pkg.world.dev/world-engine/cardinal/search/filter"
+	"pkg.world.dev/world-engine/cardinal/testutils"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+	"pkg.world.dev/world-engine/sign"
+)
+
+func TestPauseAndResume(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil)
+	world := tf.World
+
+	assert.ErrorIs(t, world.Pause(), cardinal.ErrWorldNotRunning)
+
+	tf.DoTick()
+	assert.NilError(t, world.Pause())
+	assert.Assert(t, world.IsPaused())
+	assert.NilError(t, world.Resume())
+	assert.Assert(t, !world.IsPaused())
+	tf.DoTick()
+	assert.Equal(t, uint64(2), world.CurrentTick())
+}
+
+func TestDisabledSystemIsSkipped(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil)
+	world := tf.World
+	assert.NilError(t, cardinal.RegisterComponent[Health](world))
+	assert.NilError(t, cardinal.RegisterSystems(world, HealthSystem))
+
+	tf.StartWorld()
+	wCtx := cardinal.NewWorldContext(world)
+	id, err := cardinal.Create(wCtx, Health{})
+	assert.NilError(t, err)
+	tf.DoTick()
+	assertHealth(t, wCtx, id, 1)
+
+	assert.ErrorIs(t, world.DisableSystem("no-such-system"), cardinal.ErrSystemNotFound)
+	assert.NilError(t, world.DisableSystem("cardinal_test.HealthSystem"))
+	assert.DeepEqual(t, []string{"cardinal_test.HealthSystem"}, world.GetDisabledSystems())
+	tf.DoTick()
+	assertHealth(t, wCtx, id, 1)
+
+	assert.NilError(t, world.EnableSystem("cardinal_test.HealthSystem"))
+	assert.Equal(t, 0, len(world.GetDisabledSystems()))
+	tf.DoTick()
+	assertHealth(t, wCtx, id, 2)
+}
+
+func TestReloadSystemKeepsState(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil, cardinal.WithExperimentalHotReload())
+	world := tf.World
+	assert.NilError(t, cardinal.RegisterComponent[Health](world))
+	assert.NilError(t, cardinal.RegisterSystems(world, HealthSystem))
+
+	tf.StartWorld()
+	wCtx := cardinal.NewWorldContext(world)
+	id, err := cardinal.Create(wCtx, Health{})
+	assert.NilError(t, err)
+	tf.DoTick()
+	assertHealth(t, wCtx, id, 1)
+
+	regen := func(wCtx engine.Context) error {
+		return cardinal.UpdateComponent[Health](wCtx, id, func(h *Health) *Health {
+			h.Value += 10
+			return h
+		})
+	}
+	err = world.ReloadSystems(map[string]cardinal.System{"no-such-system": regen})
+	assert.ErrorIs(t, err, cardinal.ErrSystemNotFound)
+	assert.NilError(t, world.ReloadSystems(map[string]cardinal.System{"cardinal_test.HealthSystem": regen}))
+	tf.DoTick()
+	assertHealth(t, wCtx, id, 11)
+}
+
+func TestReloadSystemRequiresOptIn(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil)
+	assert.NilError(t, cardinal.RegisterSystems(tf.World, HealthSystem))
+	tf.StartWorld()
+	err := tf.World.ReloadSystems(map[string]cardinal.System{"cardinal_test.HealthSystem": HealthSystem})
+	assert.ErrorIs(t, err, cardinal.ErrHotReloadDisabled)
+}
+
+func TestRollbackToCheckpoint(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil)
+	world := tf.World
+	assert.NilError(t, cardinal.RegisterComponent[Health](world))
+	assert.NilError(t, cardinal.RegisterSystems(world, HealthSystem))
+
+	tf.StartWorld()
+	wCtx := cardinal.NewWorldContext(world)
+	// Reads go through a read-only context, since values read through wCtx stay buffered until the next tick.
+	readOnly := cardinal.NewReadOnlyWorldContext(world)
+	id, err := cardinal.Create(wCtx, Health{})
+	assert.NilError(t, err)
+	tf.DoTick()
+	tf.DoTick()
+	assertHealth(t, readOnly, id, 2)
+
+	info, err := world.SaveCheckpoint("before-event")
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(2), info.Tick)
+
+	_, err = world.SaveCheckpoint("not a valid name")
+	assert.IsError(t, err)
+
+	// Entities created after the checkpoint are gone after the rollback.
+	_, err = cardinal.Create(wCtx, Health{Value: 100})
+	assert.NilError(t, err)
+	tf.DoTick()
+	tf.DoTick()
+	assert.Equal(t, uint64(4), world.CurrentTick())
+
+	info, err = world.RollbackToCheckpoint("before-event")
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(2), info.Tick)
+	assert.Equal(t, uint64(2), world.CurrentTick())
+	assertHealth(t, readOnly, id, 2)
+	count, err := cardinal.NewSearch().Entity(filter.Exact(filter.Component[Health]())).Count(wCtx)
+	assert.NilError(t, err)
+	assert.Equal(t, 1, count)
+
+	// The world keeps ticking from the checkpoint.
+	tf.DoTick()
+	assert.Equal(t, uint64(3), world.CurrentTick())
+	assertHealth(t, readOnly, id, 3)
+
+	checkpoints, err := world.ListCheckpoints()
+	assert.NilError(t, err)
+	assert.Equal(t, 1, len(checkpoints))
+	assert.NilError(t, world.DeleteCheckpoint("before-event"))
+	assert.ErrorIs(t, world.DeleteCheckpoint("before-event"), cardinal.ErrCheckpointNotFound)
+	_, err = world.RollbackToCheckpoint("before-event")
+	assert.ErrorIs(t, err, cardinal.ErrCheckpointNotFound)
+}
+
+func TestUpdateConfig(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil)
+	world := tf.World
+	tf.StartWorld()
+
+	assert.ErrorIs(t, world.UpdateConfig("REDIS_ADDRESS", "localhost:1234"), cardinal.ErrUnknownConfigKey)
+	assert.IsError(t, world.UpdateConfig(cardinal.ConfigKeyMaxEntitiesPerPersona, "-1"))
+	assert.NilError(t, world.UpdateConfig(cardinal.ConfigKeyMaxEntitiesPerPersona, "10"))
+}
+
+func TestBanPersona(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil)
+	world := tf.World
+
+	assert.NilError(t, world.BanPersona("griefer", "spamming"))
+	banned, err := world.IsPersonaBanned("griefer")
+	assert.NilError(t, err)
+	assert.Assert(t, banned)
+	bans, err := world.ListBans()
+	assert.NilError(t, err)
+	assert.DeepEqual(t, map[string]string{"griefer": "spamming"}, bans)
+
+	assert.NilError(t, world.UnbanPersona("griefer"))
+	banned, err = world.IsPersonaBanned("griefer")
+	assert.NilError(t, err)
+	assert.Assert(t, !banned)
+}
+
+func TestDrainTxQueue(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil)
+	world := tf.World
+	type SpamMsg struct{}
+	type SpamResult struct{}
+	assert.NilError(t, cardinal.RegisterMessage[SpamMsg, SpamResult](world, "spam"))
+	executed := 0
+	assert.NilError(t, cardinal.RegisterSystems(world, func(wCtx engine.Context) error {
+		return cardinal.EachMessage[SpamMsg, SpamResult](wCtx, func(message.TxData[SpamMsg]) (SpamResult, error) {
+			executed++
+			return SpamResult{}, nil
+		})
+	}))
+	tf.StartWorld()
+
+	spam, ok := world.GetMessageByFullName("game.spam")
+	assert.Assert(t, ok)
+	tf.AddTransaction(spam.ID(), SpamMsg{}, &sign.Transaction{PersonaTag: "griefer", Nonce: 1})
+	tf.AddTransaction(spam.ID(), SpamMsg{}, &sign.Transaction{PersonaTag: "griefer", Nonce: 2})
+	stats := world.GetTxQueueStats()
+	assert.Equal(t, 1, len(stats))
+	assert.Equal(t, "game.spam", stats[0].Message)
+	assert.Equal(t, 2, stats[0].Pending)
+	assert.Assert(t, !stats[0].OldestAddedAt.IsZero())
+
+	_, err := world.DrainTxQueue("game.no-such-message")
+	assert.ErrorIs(t, err, cardinal.ErrMessageNotFound)
+	txs, err := world.DrainTxQueue("game.spam")
+	assert.NilError(t, err)
+	assert.Equal(t, 2, len(txs))
+	assert.Equal(t, uint64(1), txs[0].Tx.Nonce)
+	assert.Equal(t, 0, len(world.GetTxQueueStats()))
+
+	tf.DoTick()
+	assert.Equal(t, 0, executed)
+}
+
+func TestStepDebuggerStopsAtBreakpoints(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil, cardinal.WithStepDebugger())
+	world := tf.World
+	assert.NilError(t, cardinal.RegisterComponent[Health](world))
+	assert.NilError(t, cardinal.RegisterSystems(world, HealthSystem))
+
+	tf.StartWorld()
+	assert.Assert(t, world.IsPaused())
+	wCtx := cardinal.NewWorldContext(world)
+	id, err := cardinal.Create(wCtx, Health{})
+	assert.NilError(t, err)
+
+	breakpoint := admin.Breakpoint{System: "cardinal_test.HealthSystem", After: true}
+	assert.ErrorIs(t, world.SetBreakpoints([]admin.Breakpoint{{System: "no-such-system"}}), cardinal.ErrSystemNotFound)
+	assert.NilError(t, world.SetBreakpoints([]admin.Breakpoint{breakpoint}))
+	_, err = world.Continue()
+	assert.ErrorIs(t, err, cardinal.ErrNoTickStopped)
+
+	// The tick stops after the system, and its uncommitted changes can be inspected.
+	st, err := world.Step()
+	assert.NilError(t, err)
+	assert.DeepEqual(t, admin.DebugStatus{Tick: 0, StoppedAt: &breakpoint}, st)
+	_, entities, err := world.GetTickState()
+	assert.NilError(t, err)
+	assert.Equal(t, 1, len(entities))
+	assert.Equal(t, id, entities[0].ID)
+	assert.Equal(t, `{"Value":1}`, string(entities[0].Components[Health{}.Name()]))
+	_, err = world.Step()
+	assert.ErrorIs(t, err, cardinal.ErrTickStopped)
+
+	st, err = world.Continue()
+	assert.NilError(t, err)
+	assert.DeepEqual(t, admin.DebugStatus{Tick: 0}, st)
+	assert.Equal(t, uint64(1), world.CurrentTick())
+
+	// Without breakpoints, a step runs a whole tick.
+	assert.NilError(t, world.SetBreakpoints(nil))
+	st, err = world.Step()
+	assert.NilError(t, err)
+	assert.DeepEqual(t, admin.DebugStatus{Tick: 1}, st)
+	assertHealth(t, wCtx, id, 2)
+
+	assert.NilError(t, world.Resume())
+	_, err = world.Step()
+	assert.ErrorIs(t, err, cardinal.ErrNotPaused)
+}
+
+func TestProfileTicks(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil)
+	world := tf.World
+	tf.StartWorld()
+
+	assert.ErrorIs(t, world.ProfileTicks(0), admin.ErrInvalidValue)
+	assert.ErrorIs(t, world.ProfileTicks(cardinal.MaxProfiledTicks+1), admin.ErrInvalidValue)
+	assert.NilError(t, world.ProfileTicks(2))
+	assert.ErrorIs(t, world.ProfileTicks(1), cardinal.ErrProfiling)
+
+	first := world.CurrentTick()
+	tf.DoTick()
+	remaining, profiles, err := world.GetTickProfiles()
+	assert.NilError(t, err)
+	assert.Equal(t, 1, remaining)
+	assert.Equal(t, 1, len(profiles))
+
+	tf.DoTick()
+	tf.DoTick()
+	remaining, profiles, err = world.GetTickProfiles()
+	assert.NilError(t, err)
+	assert.Equal(t, 0, remaining)
+	assert.Equal(t, 2, len(profiles))
+	for i, p := range profiles {
+		assert.Equal(t, first+uint64(i), p.Tick)
+		assert.Assert(t, len(p.CPU) > 0)
+		assert.Assert(t, len(p.AllocsBefore) > 0)
+		assert.Assert(t, len(p.AllocsAfter) > 0)
+	}
+
+	// Profiling can be started again once it is done.
+	assert.NilError(t, world.ProfileTicks(1))
+	_, profiles, err = world.GetTickProfiles()
+	assert.NilError(t, err)
+	assert.Equal(t, 0, len(profiles))
+}
+
+func assertHealth(t *testing.T, wCtx cardinal.WorldContext, id types.EntityID, want int) {
+	t.Helper()
+	health, err := cardinal.GetComponent[Health](wCtx, id)
+	assert.NilError(t, err)
+	assert.Equal(t, want, health.Value)
+}
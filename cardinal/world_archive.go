@@ -0,0 +1,47 @@
+package cardinal
+
+import (
+	"github.com/rotisserie/eris"
+	"github.com/rs/zerolog/log"
+
+	"pkg.world.dev/world-engine/cardinal/gamestate"
+	"pkg.world.dev/world-engine/cardinal/types"
+)
+
+// trackEntityAccess makes the entity store record the entities that are accessed during each tick, so that the
+// inactive ones can be archived.
+func (w *World) trackEntityAccess() error {
+	ecb, ok := w.entityStore.(*gamestate.EntityCommandBuffer)
+	if !ok {
+		return eris.New("entity archival can only be used with the default store manager")
+	}
+	ecb.TrackEntityAccess()
+	return nil
+}
+
+// archiveInactiveEntities archives the entities that have not been accessed for w.archiveAfter ticks. It runs at the
+// end of every tick, so the archival is committed with the tick.
+func (w *World) archiveInactiveEntities() error {
+	ecb, ok := w.entityStore.(*gamestate.EntityCommandBuffer)
+	if !ok {
+		return eris.New("entity archival can only be used with the default store manager")
+	}
+	count, err := ecb.ArchiveInactiveEntities(w.archiveAfter)
+	if err != nil {
+		return eris.Wrap(err, "failed to archive inactive entities")
+	}
+	if count > 0 {
+		log.Debug().Int("count", count).Uint64("tick", w.CurrentTick()).Msg("archived inactive entities")
+	}
+	return nil
+}
+
+// IsEntityArchived reports whether the given entity has been moved to the archive because it was inactive. See
+// WithEntityArchival.
+func (w *World) IsEntityArchived(id types.EntityID) (bool, error) {
+	ecb, ok := w.entityStore.(*gamestate.EntityCommandBuffer)
+	if !ok {
+		return false, nil
+	}
+	return ecb.IsEntityArchived(id)
+}
@@ -0,0 +1,22 @@
+package cardinal
+
+import (
+	"pkg.world.dev/world-engine/cardinal/sdk"
+)
+
+// ClientEndpoints returns the endpoints of every message and query, from which client SDKs are generated. See
+// testutils.CheckTypeScriptSDK to generate the TypeScript SDK of the world.
+func (w *World) ClientEndpoints() []sdk.Endpoint {
+	var endpoints []sdk.Endpoint
+	for _, msg := range w.GetRegisteredMessages() {
+		if source, ok := msg.(sdk.EndpointSource); ok {
+			endpoints = append(endpoints, source.ClientEndpoint())
+		}
+	}
+	for _, q := range w.GetRegisteredQueries() {
+		if source, ok := q.(sdk.EndpointSource); ok {
+			endpoints = append(endpoints, source.ClientEndpoint())
+		}
+	}
+	return endpoints
+}
@@ -1,8 +1,11 @@
 package cardinal
 
 import (
+	"math/rand/v2"
 	"reflect"
 
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/rotisserie/eris"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 
@@ -23,6 +26,15 @@ type worldContext struct {
 	txPool   *txpool.TxPool
 	logger   *zerolog.Logger
 	readOnly bool
+
+	// rng is lazily created by Rand and is reused until the tick or the running system changes.
+	rng       *rand.Rand
+	rngTick   uint64
+	rngSystem string
+
+	// internalMessages holds the messages emitted with EmitInternal in internalTick, by type.
+	internalMessages map[reflect.Type][]any
+	internalTick     uint64
 }
 
 func newWorldContextForTick(world *World, txPool *txpool.TxPool) engine.Context {
@@ -52,7 +64,8 @@ func NewReadOnlyWorldContext(world *World) engine.Context {
 	}
 }
 
-// Timestamp returns the UNIX timestamp of the tick.
+// Timestamp returns the UNIX timestamp recorded at the start of the tick. Every system in the tick sees the same value,
+// and the value is persisted so that replaying the tick produces the same result.
 func (ctx *worldContext) Timestamp() uint64 {
 	return ctx.world.timestamp.Load()
 }
@@ -103,6 +116,59 @@ func (ctx *worldContext) EmitStringEvent(e string) error {
 	return ctx.world.tickResults.AddStringEvent(e)
 }
 
+func (ctx *worldContext) EmitToEVM(contract string, payload []byte) error {
+	if ctx.readOnly {
+		return ErrEntityMutationOnReadOnly
+	}
+	if !common.IsHexAddress(contract) {
+		return eris.Wrapf(ErrInvalidContractAddress, "%q", contract)
+	}
+	_, err := ctx.world.entityStore.EnqueueOutboxMessage(ctx.CurrentTick(), contract, payload)
+	return err
+}
+
+func (ctx *worldContext) EmitInternal(msg any) error {
+	if ctx.readOnly {
+		return ErrEntityMutationOnReadOnly
+	}
+	if msg == nil {
+		return eris.New("cannot emit a nil internal message")
+	}
+	ctx.dropStaleInternalMessages()
+	typ := reflect.TypeOf(msg)
+	ctx.internalMessages[typ] = append(ctx.internalMessages[typ], msg)
+	return nil
+}
+
+func (ctx *worldContext) InternalMessages(typ reflect.Type) []any {
+	ctx.dropStaleInternalMessages()
+	return ctx.internalMessages[typ]
+}
+
+// dropStaleInternalMessages drops the internal messages that were emitted in an earlier tick. The context of a tick
+// is discarded at the end of the tick anyway, but contexts created with NewWorldContext outlive ticks.
+func (ctx *worldContext) dropStaleInternalMessages() {
+	if tick := ctx.CurrentTick(); ctx.internalMessages == nil || ctx.internalTick != tick {
+		ctx.internalMessages = map[reflect.Type][]any{}
+		ctx.internalTick = tick
+	}
+}
+
+func (ctx *worldContext) RecordSearch(archetypes, entities int) {
+	// Queries are evaluated concurrently with the tick, so only the searches of systems are recorded
+	if ctx.readOnly {
+		return
+	}
+	ctx.world.SystemManager.recordSearch(archetypes, entities)
+}
+
+func (ctx *worldContext) RecordQueryPattern(components []string) {
+	if ctx.readOnly || ctx.world.layoutProfiler == nil {
+		return
+	}
+	ctx.world.layoutProfiler.record(ctx.world.GetCurrentSystem(), components)
+}
+
 func (ctx *worldContext) GetSignerForPersonaTag(personaTag string, tick uint64) (addr string, err error) {
 	return ctx.world.GetSignerForPersonaTag(personaTag, tick)
 }
@@ -119,7 +185,78 @@ func (ctx *worldContext) Namespace() string {
 	return ctx.world.Namespace()
 }
 
-func (ctx *worldContext) AddTransaction(id types.MessageID, v any, sig *sign.Transaction) (uint64, types.TxHash) {
+func (ctx *worldContext) Rand() *rand.Rand {
+	tick := ctx.CurrentTick()
+	system := ctx.world.GetCurrentSystem()
+	if ctx.rng == nil || ctx.rngTick != tick || ctx.rngSystem != system {
+		ctx.rng = newTickRand(ctx.world.randSeed, tick, system)
+		ctx.rngTick = tick
+		ctx.rngSystem = system
+	}
+	return ctx.rng
+}
+
+func (ctx *worldContext) GetOwnedEntities(personaTag string) ([]types.EntityID, error) {
+	return GetOwnedEntities(ctx, personaTag)
+}
+
+func (ctx *worldContext) ReserveEntityQuota(personaTag string, num, components int) error {
+	quota := ctx.world.entityQuota
+	if err := quota.reservePersona(ctx, personaTag, num); err != nil {
+		return err
+	}
+	system := ctx.world.GetCurrentSystem()
+	if err := quota.checkSystem(ctx.CurrentTick(), system, num); err != nil {
+		return err
+	}
+	if err := quota.reserveCapacity(ctx, num, components); err != nil {
+		return err
+	}
+	quota.commitSystem(system, num)
+	return nil
+}
+
+func (ctx *worldContext) ReservePersonaEntityQuota(personaTag string, num int) error {
+	return ctx.world.entityQuota.reservePersona(ctx, personaTag, num)
+}
+
+func (ctx *worldContext) ReleaseEntityQuota(num, components int) error {
+	return ctx.world.entityQuota.release(ctx, num, components)
+}
+
+func (ctx *worldContext) CancelEntityQuota(num, components int) error {
+	quota := ctx.world.entityQuota
+	quota.cancelSystem(ctx.world.GetCurrentSystem(), num)
+	return quota.release(ctx, num, components)
+}
+
+func (ctx *worldContext) TrackComponentChange(cType types.ComponentMetadata, id types.EntityID, added bool) error {
+	return ctx.world.trackComponentChange(cType, id, added)
+}
+
+func (ctx *worldContext) IndexComponent(cType types.ComponentMetadata, id types.EntityID, value any) error {
+	return ctx.world.indexComponent(ctx, cType, id, value)
+}
+
+func (ctx *worldContext) FindByField(cType types.ComponentMetadata, field string, value any) ([]types.EntityID, error) {
+	return ctx.world.findByField(ctx, cType, field, value)
+}
+
+func (ctx *worldContext) DestroyEntity(id types.EntityID) {
+	ctx.world.destroyed[id] = true
+}
+
+func (ctx *worldContext) IsEntityDestroyed(id types.EntityID) bool {
+	// Read only contexts see the committed state, in which destroyed entities are already removed
+	if ctx.IsReadOnly() {
+		return false
+	}
+	return ctx.world.destroyed[id]
+}
+
+func (ctx *worldContext) AddTransaction(id types.MessageID, v any, sig *sign.Transaction) (
+	uint64, types.TxHash, error,
+) {
 	return ctx.world.AddTransaction(id, v, sig)
 }
 
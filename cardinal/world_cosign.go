@@ -0,0 +1,133 @@
+package cardinal
+
+import (
+	"context"
+	"time"
+
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal/storage/redis"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/sign"
+)
+
+// DefaultCoSignTimeout is how long a co-signed transaction waits for the signatures of its co-signers before it is
+// dropped.
+const DefaultCoSignTimeout = 5 * time.Minute
+
+// ErrCoSignedTransactionNotFound is returned when a co-signature is added to a transaction that is not waiting for
+// co-signatures, because it is unknown, complete, or timed out.
+var ErrCoSignedTransactionNotFound = types.ErrCoSignedTransactionNotFound
+
+// Co-signed transactions that are waiting for the signatures of their co-signers are kept in redis, so that they
+// survive restarts and a co-signature can be added through any replica of the world. They are only added to the
+// transaction pool once they are complete.
+
+// AddCoSignedTransaction is like AddTransactionWithContext, but a transaction that is still missing signatures of its
+// co-signers is held back until they are added with AddCoSignature, and pending is true. The co-signatures that the
+// transaction already has must have been verified.
+func (w *World) AddCoSignedTransaction(ctx context.Context, id types.MessageID, v any, sig *sign.Transaction) (
+	tick uint64, txHash types.TxHash, pending bool, err error,
+) {
+	if len(sig.MissingCoSigners()) == 0 {
+		tick, txHash, err = w.AddTransactionWithContext(ctx, id, v, sig)
+		return tick, txHash, false, err
+	}
+	txHash = types.TxHash(sig.HashHex())
+	// The co-signatures are stored apart from the transaction, so that co-signers can add them concurrently
+	tx := *sig
+	tx.CoSignatures = nil
+	bz, err := w.encodeTx(id, v, &tx, "")
+	if err != nil {
+		return 0, "", false, err
+	}
+	pendingTx := redis.PendingCoSignedTx{Data: bz, CoSignatures: sig.CoSignatures}
+	if err = w.redisStorage.SavePendingCoSignedTx(string(txHash), pendingTx, w.coSignTimeout); err != nil {
+		return 0, "", false, eris.Wrap(err, "failed to save pending co-signed transaction")
+	}
+	return w.CurrentTick(), txHash, true, nil
+}
+
+// GetPendingCoSignedTransaction returns the co-signed transaction with the given hash, with the co-signatures that
+// were added so far, if it is waiting for the signatures of its co-signers.
+func (w *World) GetPendingCoSignedTransaction(txHash types.TxHash) (*sign.Transaction, bool, error) {
+	_, _, tx, ok, err := w.getPendingCoSignedTx(txHash)
+	return tx, ok, err
+}
+
+// AddCoSignature adds the verified signature of a co-signer to a pending co-signed transaction. Once the transaction
+// has the signatures of all its co-signers, it is added to the transaction pool and pending is false.
+// ErrCoSignedTransactionNotFound is returned if the transaction is not waiting for co-signatures. A transaction that
+// expired, or whose signer or co-signers were banned, while it was waiting for co-signatures is dropped, and
+// types.ErrCoSignedTransactionExpired or types.ErrCoSignerBanned is returned.
+func (w *World) AddCoSignature(ctx context.Context, txHash types.TxHash, personaTag, signature string) (
+	tick uint64, pending bool, err error,
+) {
+	id, msg, tx, ok, err := w.getPendingCoSignedTx(txHash)
+	if err != nil {
+		return 0, false, err
+	} else if !ok {
+		return 0, false, eris.Wrapf(ErrCoSignedTransactionNotFound, "transaction %s", txHash)
+	}
+	if !tx.IsCoSigner(personaTag) {
+		return 0, false, eris.Wrapf(sign.ErrNotCoSigner, "%q", personaTag)
+	}
+	if tick = w.CurrentTick(); tx.IsExpired(tick) {
+		if _, err = w.redisStorage.ClaimPendingCoSignedTx(string(txHash)); err != nil {
+			return 0, false, eris.Wrap(err, "failed to drop expired co-signed transaction")
+		}
+		return 0, false, eris.Wrapf(types.ErrCoSignedTransactionExpired, "transaction %s expired at tick %d",
+			txHash, tx.ExpiresAtTick)
+	}
+
+	pendingTx, ok, err := w.redisStorage.AddCoSignature(string(txHash), personaTag, signature)
+	if err != nil {
+		return 0, false, eris.Wrap(err, "failed to add co-signature")
+	} else if !ok {
+		return 0, false, eris.Wrapf(ErrCoSignedTransactionNotFound, "transaction %s", txHash)
+	}
+	tx.CoSignatures = pendingTx.CoSignatures
+	if len(tx.MissingCoSigners()) > 0 {
+		return tick, true, nil
+	}
+
+	// Only the co-signature that claims the complete transaction adds it to the pool
+	claimed, err := w.redisStorage.ClaimPendingCoSignedTx(string(txHash))
+	if err != nil {
+		return 0, false, eris.Wrap(err, "failed to claim co-signed transaction")
+	} else if !claimed {
+		return 0, false, eris.Wrapf(ErrCoSignedTransactionNotFound, "transaction %s", txHash)
+	}
+	// The signer and co-signers that signed before could have been banned while the transaction was waiting
+	for _, signer := range append([]string{tx.PersonaTag}, tx.CoSigners...) {
+		banned, err := w.IsPersonaBanned(signer)
+		if err != nil {
+			return 0, false, err
+		} else if banned {
+			return 0, false, eris.Wrapf(types.ErrCoSignerBanned, "%q", signer)
+		}
+	}
+	if tick, _, err = w.AddTransactionWithContext(ctx, id, msg, tx); err != nil {
+		return 0, false, err
+	}
+	return tick, false, nil
+}
+
+// getPendingCoSignedTx returns the pending co-signed transaction with the given hash, and its decoded message.
+func (w *World) getPendingCoSignedTx(txHash types.TxHash) (
+	id types.MessageID, msg any, tx *sign.Transaction, ok bool, err error,
+) {
+	pendingTx, ok, err := w.redisStorage.GetPendingCoSignedTx(string(txHash))
+	if err != nil {
+		return 0, nil, nil, false, eris.Wrap(err, "failed to get pending co-signed transaction")
+	} else if !ok {
+		return 0, nil, nil, false, nil
+	}
+	decoded, msg, err := w.decodeTx(pendingTx.Data)
+	if err != nil {
+		return 0, nil, nil, false, err
+	}
+	tx = decoded.Tx
+	tx.CoSignatures = pendingTx.CoSignatures
+	return decoded.TypeID, msg, tx, true, nil
+}
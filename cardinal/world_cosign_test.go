@@ -0,0 +1,81 @@
+package cardinal_test
+
+import (
+	"context"
+	"testing"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/message"
+	"pkg.world.dev/world-engine/cardinal/testutils"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+	"pkg.world.dev/world-engine/sign"
+)
+
+// newCoSignFixture returns a started world that counts the executed move messages.
+func newCoSignFixture(t *testing.T, tf *testutils.TestFixture) (*testutils.TestFixture, types.MessageID, *int) {
+	if tf == nil {
+		tf = testutils.NewTestFixture(t, nil)
+	} else {
+		tf.Shutdown()
+		tf = testutils.NewTestFixture(t, tf.Redis)
+	}
+	assert.NilError(t, cardinal.RegisterMessage[MoveMsg, MoveMsg](tf.World, "move"))
+	executed := 0
+	assert.NilError(t, cardinal.RegisterSystems(tf.World, func(wCtx engine.Context) error {
+		return cardinal.EachMessage[MoveMsg, MoveMsg](wCtx, func(tx message.TxData[MoveMsg]) (MoveMsg, error) {
+			executed++
+			return tx.Msg, nil
+		})
+	}))
+	tf.StartWorld()
+	msgType, ok := tf.World.GetMessageByFullName("game.move")
+	assert.Assert(t, ok)
+	return tf, msgType.ID(), &executed
+}
+
+func TestPendingCoSignedTransactionSurvivesRestarts(t *testing.T) {
+	tf, id, _ := newCoSignFixture(t, nil)
+	ctx := context.Background()
+	tx := &sign.Transaction{PersonaTag: "alice", Nonce: 1, CoSigners: []string{"bob", "carol"}}
+	_, hash, pending, err := tf.World.AddCoSignedTransaction(ctx, id, MoveMsg{Direction: "up"}, tx)
+	assert.NilError(t, err)
+	assert.Assert(t, pending)
+	_, pending, err = tf.World.AddCoSignature(ctx, hash, "bob", "bob-signature")
+	assert.NilError(t, err)
+	assert.Assert(t, pending)
+
+	tf, _, executed := newCoSignFixture(t, tf)
+	got, ok, err := tf.World.GetPendingCoSignedTransaction(hash)
+	assert.NilError(t, err)
+	assert.Assert(t, ok)
+	assert.DeepEqual(t, []string{"carol"}, got.MissingCoSigners())
+	_, pending, err = tf.World.AddCoSignature(ctx, hash, "carol", "carol-signature")
+	assert.NilError(t, err)
+	assert.Assert(t, !pending)
+	tf.DoTick()
+	assert.Equal(t, 1, *executed)
+
+	_, _, err = tf.World.AddCoSignature(ctx, hash, "carol", "carol-signature")
+	assert.ErrorIs(t, err, cardinal.ErrCoSignedTransactionNotFound)
+}
+
+func TestCoSignedTransactionOfBannedSignerIsDropped(t *testing.T) {
+	tf, id, executed := newCoSignFixture(t, nil)
+	ctx := context.Background()
+	tx := &sign.Transaction{PersonaTag: "alice", Nonce: 1, CoSigners: []string{"bob"}}
+	_, hash, pending, err := tf.World.AddCoSignedTransaction(ctx, id, MoveMsg{Direction: "up"}, tx)
+	assert.NilError(t, err)
+	assert.Assert(t, pending)
+
+	// alice is banned after she submitted the transaction, but before bob signed it
+	assert.NilError(t, tf.World.BanPersona("alice", "cheating"))
+	_, _, err = tf.World.AddCoSignature(ctx, hash, "bob", "bob-signature")
+	assert.ErrorIs(t, err, types.ErrCoSignerBanned)
+	tf.DoTick()
+	assert.Equal(t, 0, *executed)
+	_, ok, err := tf.World.GetPendingCoSignedTransaction(hash)
+	assert.NilError(t, err)
+	assert.Assert(t, !ok)
+}
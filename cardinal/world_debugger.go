@@ -0,0 +1,243 @@
+package cardinal
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"sync"
+
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal/admin"
+	"pkg.world.dev/world-engine/cardinal/search/filter"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+	"pkg.world.dev/world-engine/cardinal/worldstage"
+)
+
+var (
+	ErrDebuggerDisabled = admin.ErrDebuggerDisabled
+	ErrNotPaused        = admin.ErrNotPaused
+	ErrTickStopped      = admin.ErrTickStopped
+	ErrNoTickStopped    = admin.ErrNoTickStopped
+)
+
+// stepDebugger stops ticks at breakpoints. See WithStepDebugger.
+type stepDebugger struct {
+	// commandMu serializes Step and Continue, so that each of them waits for the tick it started or continued.
+	commandMu sync.Mutex
+
+	mu          sync.Mutex
+	breakpoints map[admin.Breakpoint]bool
+	// stoppedTick is the tick that is stopped at stoppedAt.
+	stoppedTick uint64
+	// stoppedAt is the breakpoint at which the running tick is stopped. It is nil if no tick is stopped.
+	stoppedAt *admin.Breakpoint
+	// resume continues the stopped tick.
+	resume chan struct{}
+	// changed is closed, and replaced, whenever a tick stops at a breakpoint or completes.
+	changed chan struct{}
+}
+
+func (w *World) enableStepDebugger() {
+	d := &stepDebugger{
+		breakpoints: map[admin.Breakpoint]bool{},
+		resume:      make(chan struct{}),
+		changed:     make(chan struct{}),
+	}
+	w.debugger = d
+	w.paused.Store(true)
+	w.SystemManager.setBreakHook(func(system string, after bool) {
+		// Recovery ticks run before the admin service can step them, so they never stop.
+		if w.worldStage.Current() == worldstage.Running {
+			d.breakAt(w.CurrentTick(), admin.Breakpoint{System: system, After: after})
+		}
+	})
+}
+
+// SetBreakpoints replaces the breakpoints of the step debugger. A tick that reaches a breakpoint stops before or after
+// its system until Continue is called.
+func (w *World) SetBreakpoints(breakpoints []admin.Breakpoint) error {
+	d, err := w.stepDebugger()
+	if err != nil {
+		return err
+	}
+	systems := w.SystemManager.GetRegisteredSystems()
+	for _, bp := range breakpoints {
+		if !slices.Contains(systems, bp.System) {
+			return eris.Wrapf(ErrSystemNotFound, "system %q", bp.System)
+		}
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	clear(d.breakpoints)
+	for _, bp := range breakpoints {
+		d.breakpoints[bp] = true
+	}
+	return nil
+}
+
+// Step runs the next tick of the paused world. It returns once the tick completes or stops at a breakpoint.
+func (w *World) Step() (admin.DebugStatus, error) {
+	d, err := w.stepDebugger()
+	if err != nil {
+		return admin.DebugStatus{}, err
+	}
+	if !w.IsPaused() {
+		return admin.DebugStatus{}, eris.Wrap(ErrNotPaused, "cannot step a ticking world")
+	}
+	d.commandMu.Lock()
+	defer d.commandMu.Unlock()
+	d.mu.Lock()
+	if d.stoppedAt != nil {
+		d.mu.Unlock()
+		return admin.DebugStatus{}, eris.Wrap(ErrTickStopped, "continue the tick before stepping")
+	}
+	changed := d.changed
+	d.mu.Unlock()
+
+	select {
+	case w.betweenTicks <- func() { w.tickTheEngine(context.Background(), nil) }:
+	case <-w.worldStage.NotifyOnStage(worldstage.ShuttingDown):
+		return admin.DebugStatus{}, eris.Wrap(ErrWorldNotRunning, "world is shutting down")
+	}
+	<-changed
+	return d.status(w), nil
+}
+
+// Continue resumes the tick that is stopped at a breakpoint. It returns once the tick completes or stops at the next
+// breakpoint.
+func (w *World) Continue() (admin.DebugStatus, error) {
+	d, err := w.stepDebugger()
+	if err != nil {
+		return admin.DebugStatus{}, err
+	}
+	d.commandMu.Lock()
+	defer d.commandMu.Unlock()
+	d.mu.Lock()
+	if d.stoppedAt == nil {
+		d.mu.Unlock()
+		return admin.DebugStatus{}, eris.Wrap(ErrNoTickStopped, "")
+	}
+	d.stoppedAt = nil
+	changed := d.changed
+	d.mu.Unlock()
+
+	d.resume <- struct{}{}
+	<-changed
+	return d.status(w), nil
+}
+
+// GetTickState returns all entities and their components, sorted by entity ID. While a tick is stopped at a
+// breakpoint, the state includes the changes that the systems of the tick made so far.
+func (w *World) GetTickState() (admin.DebugStatus, []admin.EntityState, error) {
+	d, err := w.stepDebugger()
+	if err != nil {
+		return admin.DebugStatus{}, nil, err
+	}
+	// The lock keeps the stopped tick from being continued while its state is read.
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	st := d.statusLocked(w)
+	wCtx := NewReadOnlyWorldContext(w)
+	if st.StoppedAt != nil {
+		// The stopped tick is blocked, so its uncommitted changes can be read without racing its systems.
+		wCtx = NewWorldContext(w)
+	}
+	entities, err := w.entityStates(wCtx)
+	return st, entities, err
+}
+
+func (w *World) stepDebugger() (*stepDebugger, error) {
+	if w.debugger == nil {
+		return nil, eris.Wrap(ErrDebuggerDisabled, "use WithStepDebugger")
+	}
+	if !w.IsGameRunning() {
+		return nil, eris.Wrap(ErrWorldNotRunning, "")
+	}
+	return w.debugger, nil
+}
+
+// entityStates returns all entities and their components in the state that wCtx reads.
+func (w *World) entityStates(wCtx engine.Context) ([]admin.EntityState, error) {
+	var entities []admin.EntityState
+	var eachErr error
+	err := w.Search(filter.All()).Each(wCtx, func(id types.EntityID) bool {
+		var comps []types.ComponentMetadata
+		comps, eachErr = wCtx.StoreReader().GetComponentTypesForEntity(id)
+		if eachErr != nil {
+			return false
+		}
+		entity := admin.EntityState{ID: id, Components: make(map[string]json.RawMessage, len(comps))}
+		for _, c := range comps {
+			var data json.RawMessage
+			if data, eachErr = wCtx.StoreReader().GetComponentForEntityInRawJSON(c, id); eachErr != nil {
+				return false
+			}
+			entity.Components[c.Name()] = data
+		}
+		entities = append(entities, entity)
+		return true
+	})
+	if err = errors.Join(err, eachErr); err != nil {
+		return nil, err
+	}
+	slices.SortFunc(entities, func(a, b admin.EntityState) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+	return entities, nil
+}
+
+// breakAt stops the running tick if there is a breakpoint at bp, and blocks until the tick is continued.
+func (d *stepDebugger) breakAt(tick uint64, bp admin.Breakpoint) {
+	d.mu.Lock()
+	if !d.breakpoints[bp] {
+		d.mu.Unlock()
+		return
+	}
+	d.stoppedTick, d.stoppedAt = tick, &bp
+	d.notifyLocked()
+	d.mu.Unlock()
+	<-d.resume
+}
+
+// tickEnded is called by the game loop after every tick.
+func (d *stepDebugger) tickEnded() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.notifyLocked()
+}
+
+// release removes all breakpoints and continues a stopped tick, so that the game loop can shut down.
+func (d *stepDebugger) release() {
+	d.mu.Lock()
+	clear(d.breakpoints)
+	stopped := d.stoppedAt != nil
+	d.stoppedAt = nil
+	d.mu.Unlock()
+	if stopped {
+		d.resume <- struct{}{}
+	}
+}
+
+func (d *stepDebugger) notifyLocked() {
+	close(d.changed)
+	d.changed = make(chan struct{})
+}
+
+func (d *stepDebugger) status(w *World) admin.DebugStatus {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.statusLocked(w)
+}
+
+// statusLocked returns the stopped tick and its breakpoint, or the last tick that completed if no tick is stopped.
+func (d *stepDebugger) statusLocked(w *World) admin.DebugStatus {
+	if d.stoppedAt != nil {
+		bp := *d.stoppedAt
+		return admin.DebugStatus{Tick: d.stoppedTick, StoppedAt: &bp}
+	}
+	return admin.DebugStatus{Tick: max(w.CurrentTick(), 1) - 1}
+}
@@ -0,0 +1,77 @@
+package cardinal
+
+import (
+	"sort"
+
+	"pkg.world.dev/world-engine/cardinal/abi"
+)
+
+// EVMFixtures returns the ABI fixtures of the input and output types of every message and query with EVM support,
+// sorted by name. See testutils.CheckEVMFixtures to compare them with committed fixtures in tests.
+func (w *World) EVMFixtures() ([]abi.Fixture, error) {
+	var fixtures []abi.Fixture
+	add := func(source any) error {
+		fs, ok := source.(abi.FixtureSource)
+		if !ok {
+			return nil
+		}
+		f, err := fs.EVMFixtures()
+		if err != nil {
+			return err
+		}
+		fixtures = append(fixtures, f...)
+		return nil
+	}
+	for _, msg := range w.GetRegisteredMessages() {
+		if !msg.IsEVMCompatible() {
+			continue
+		}
+		if err := add(msg); err != nil {
+			return nil, err
+		}
+	}
+	for _, q := range w.GetRegisteredQueries() {
+		if !q.IsEVMCompatible() {
+			continue
+		}
+		if err := add(q); err != nil {
+			return nil, err
+		}
+	}
+	sort.Slice(fixtures, func(i, j int) bool {
+		return fixtures[i].Name < fixtures[j].Name
+	})
+	return fixtures, nil
+}
+
+// EVMBindings returns the bindings of every message and query with EVM support, sorted by name. See
+// testutils.CheckEVMBindings to generate the Go and Solidity code of the bindings.
+func (w *World) EVMBindings() ([]abi.Binding, error) {
+	var sources []any
+	for _, msg := range w.GetRegisteredMessages() {
+		if msg.IsEVMCompatible() {
+			sources = append(sources, msg)
+		}
+	}
+	for _, q := range w.GetRegisteredQueries() {
+		if q.IsEVMCompatible() {
+			sources = append(sources, q)
+		}
+	}
+	bindings := make([]abi.Binding, 0, len(sources))
+	for _, source := range sources {
+		bs, ok := source.(abi.BindingSource)
+		if !ok {
+			continue
+		}
+		b, err := bs.EVMBinding()
+		if err != nil {
+			return nil, err
+		}
+		bindings = append(bindings, b)
+	}
+	sort.Slice(bindings, func(i, j int) bool {
+		return bindings[i].Name < bindings[j].Name
+	})
+	return bindings, nil
+}
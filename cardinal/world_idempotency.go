@@ -0,0 +1,57 @@
+package cardinal
+
+import (
+	"context"
+	"time"
+
+	"github.com/rotisserie/eris"
+	"github.com/rs/zerolog/log"
+
+	"pkg.world.dev/world-engine/cardinal/storage/redis"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/sign"
+)
+
+// DefaultIdempotencyWindow is how long an idempotency key is remembered after the transaction it was first submitted
+// with. Retries with the same key within the window are answered with the original transaction.
+const DefaultIdempotencyWindow = 10 * time.Minute
+
+// LookupIdempotencyKey returns the tick and hash of the transaction that the persona submitted with the given
+// idempotency key within the idempotency window. found is false if there is no such transaction.
+func (w *World) LookupIdempotencyKey(personaTag, key string) (
+	tick uint64, txHash types.TxHash, found bool, err error,
+) {
+	tx, found, err := w.redisStorage.GetIdempotentTx(personaTag, key)
+	if err != nil {
+		return 0, "", false, eris.Wrap(err, "failed to look up idempotency key")
+	}
+	return tx.Tick, types.TxHash(tx.TxHash), found, nil
+}
+
+// AddIdempotentTransaction is like AddTransactionWithContext, but the transaction is only added if the persona that
+// signed it has not submitted a transaction with the same idempotency key within the idempotency window. Otherwise,
+// the transaction is dropped, duplicate is true, and the tick and hash of the original transaction are returned. This
+// allows clients to retry a submission whose response they never received without the message being executed twice.
+func (w *World) AddIdempotentTransaction(
+	ctx context.Context, key string, id types.MessageID, v any, sig *sign.Transaction,
+) (tick uint64, txHash types.TxHash, duplicate bool, err error) {
+	tick = w.CurrentTick()
+	txHash = types.TxHash(sig.HashHex())
+	original, claimed, err := w.redisStorage.ClaimIdempotencyKey(
+		sig.PersonaTag, key, redis.IdempotentTx{TxHash: string(txHash), Tick: tick}, w.idempotencyWindow,
+	)
+	if err != nil {
+		return 0, "", false, eris.Wrap(err, "failed to claim idempotency key")
+	}
+	if !claimed {
+		return original.Tick, types.TxHash(original.TxHash), true, nil
+	}
+	if _, txHash, err = w.AddTransactionWithContext(ctx, id, v, sig); err != nil {
+		// A retry must be added, since this submission wasn't
+		if releaseErr := w.redisStorage.ReleaseIdempotencyKey(sig.PersonaTag, key); releaseErr != nil {
+			log.Error().Err(releaseErr).Str("key", key).Msg("failed to release idempotency key")
+		}
+		return 0, "", false, err
+	}
+	return tick, txHash, false, nil
+}
@@ -0,0 +1,67 @@
+package cardinal_test
+
+import (
+	"context"
+	"testing"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/message"
+	"pkg.world.dev/world-engine/cardinal/testutils"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+	"pkg.world.dev/world-engine/sign"
+)
+
+type MoveMsg struct {
+	Direction string
+}
+
+func TestIdempotentTransactionIsOnlyExecutedOnce(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil)
+	world := tf.World
+	assert.NilError(t, cardinal.RegisterMessage[MoveMsg, MoveMsg](world, "move"))
+	executed := 0
+	assert.NilError(t, cardinal.RegisterSystems(world, func(wCtx engine.Context) error {
+		return cardinal.EachMessage[MoveMsg, MoveMsg](wCtx, func(tx message.TxData[MoveMsg]) (MoveMsg, error) {
+			executed++
+			return tx.Msg, nil
+		})
+	}))
+	tf.StartWorld()
+	msgType, ok := world.GetMessageByFullName("game.move")
+	assert.Assert(t, ok)
+
+	// The client retries with a freshly signed transaction, so only the idempotency key identifies the retry.
+	submit := func(personaTag string, nonce uint64) (uint64, string, bool) {
+		tick, hash, duplicate, err := world.AddIdempotentTransaction(context.Background(), "move-1", msgType.ID(),
+			MoveMsg{Direction: "up"}, &sign.Transaction{PersonaTag: personaTag, Nonce: nonce})
+		assert.NilError(t, err)
+		return tick, string(hash), duplicate
+	}
+
+	tick, hash, duplicate := submit("alice", 1)
+	assert.Assert(t, !duplicate)
+	retryTick, retryHash, duplicate := submit("alice", 2)
+	assert.Assert(t, duplicate)
+	assert.Equal(t, tick, retryTick)
+	assert.Equal(t, hash, retryHash)
+	tf.DoTick()
+	assert.Equal(t, 1, executed)
+
+	// Retries after the transaction was executed get the original transaction too.
+	retryTick, retryHash, duplicate = submit("ALICE", 3)
+	assert.Assert(t, duplicate)
+	assert.Equal(t, tick, retryTick)
+	assert.Equal(t, hash, retryHash)
+	foundTick, foundHash, found, err := world.LookupIdempotencyKey("alice", "move-1")
+	assert.NilError(t, err)
+	assert.Assert(t, found)
+	assert.Equal(t, tick, foundTick)
+	assert.Equal(t, hash, string(foundHash))
+
+	// Idempotency keys are scoped to the persona.
+	_, _, duplicate = submit("bob", 1)
+	assert.Assert(t, !duplicate)
+	tf.DoTick()
+	assert.Equal(t, 2, executed)
+}
@@ -0,0 +1,47 @@
+package cardinal
+
+import (
+	"slices"
+	"strings"
+
+	"pkg.world.dev/world-engine/cardinal/gamestate"
+	"pkg.world.dev/world-engine/cardinal/types/txpool"
+)
+
+// ackInputs buffers the acknowledgement of the last transaction of each persona in the tick, so that clients that
+// predict the outcome of their inputs know the tick whose state reflects them. The transaction with the highest nonce
+// of a persona is its last one, and ties are broken by hash so that the acknowledgement doesn't depend on the order of
+// the pool. Transactions without a persona tag and system transactions, e.g. the creation of personas, are not inputs
+// of a persona.
+func (w *World) ackInputs(txPool *txpool.TxPool) error {
+	tick := w.CurrentTick()
+	last := map[string]gamestate.InputAck{}
+	for _, txs := range txPool.Transactions() {
+		for _, tx := range txs {
+			if tx.Tx == nil || tx.Tx.PersonaTag == "" || tx.Tx.IsSystemTransaction() {
+				continue
+			}
+			if ack, ok := last[tx.Tx.PersonaTag]; ok && (ack.Nonce > tx.Tx.Nonce ||
+				ack.Nonce == tx.Tx.Nonce && ack.TxHash > tx.TxHash) {
+				continue
+			}
+			last[tx.Tx.PersonaTag] = gamestate.InputAck{
+				PersonaTag: tx.Tx.PersonaTag,
+				Tick:       tick,
+				TxHash:     tx.TxHash,
+				Nonce:      tx.Tx.Nonce,
+			}
+		}
+	}
+	if len(last) == 0 {
+		return nil
+	}
+	acks := make([]gamestate.InputAck, 0, len(last))
+	for _, ack := range last {
+		acks = append(acks, ack)
+	}
+	slices.SortFunc(acks, func(a, b gamestate.InputAck) int {
+		return strings.Compare(a.PersonaTag, b.PersonaTag)
+	})
+	return w.entityStore.AckInputs(acks)
+}
@@ -0,0 +1,57 @@
+package cardinal_test
+
+import (
+	"testing"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/message"
+	personaQuery "pkg.world.dev/world-engine/cardinal/persona/query"
+	"pkg.world.dev/world-engine/cardinal/testutils"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+	"pkg.world.dev/world-engine/sign"
+)
+
+func TestInputsAreAcknowledgedWithTheTickTheyWereAppliedAt(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil)
+	world := tf.World
+	assert.NilError(t, cardinal.RegisterMessage[MoveMsg, MoveMsg](world, "move"))
+	assert.NilError(t, cardinal.RegisterSystems(world, func(wCtx engine.Context) error {
+		return cardinal.EachMessage[MoveMsg, MoveMsg](wCtx, func(tx message.TxData[MoveMsg]) (MoveMsg, error) {
+			return tx.Msg, nil
+		})
+	}))
+	msgType, ok := world.GetMessageByFullName("game.move")
+	assert.Assert(t, ok)
+	tf.DoTick()
+
+	tf.AddTransaction(msgType.ID(), MoveMsg{Direction: "up"}, &sign.Transaction{PersonaTag: "alice", Nonce: 1})
+	last := tf.AddTransaction(msgType.ID(), MoveMsg{Direction: "down"}, &sign.Transaction{PersonaTag: "alice", Nonce: 2})
+	tf.DoTick()
+	// A tick without inputs of the persona doesn't change its acknowledgement.
+	tf.AddTransaction(msgType.ID(), MoveMsg{Direction: "up"}, &sign.Transaction{PersonaTag: "bob", Nonce: 1})
+	tf.DoTick()
+
+	receipts, err := world.GetTransactionReceiptsForTick(1)
+	assert.NilError(t, err)
+	assert.Equal(t, 2, len(receipts))
+	for _, rec := range receipts {
+		assert.Equal(t, uint64(1), rec.Tick)
+	}
+
+	query, err := world.GetQueryByName("ack")
+	assert.NilError(t, err)
+	testCases := []struct {
+		personaTag string
+		want       personaQuery.PersonaAckQueryResponse
+	}{
+		{"alice", personaQuery.PersonaAckQueryResponse{Found: true, Tick: 1, TxHash: last, Nonce: 2}},
+		{"carol", personaQuery.PersonaAckQueryResponse{}},
+	}
+	for _, tc := range testCases {
+		res, err := query.HandleQuery(cardinal.NewReadOnlyWorldContext(world),
+			&personaQuery.PersonaAckQueryRequest{PersonaTag: tc.personaTag})
+		assert.NilError(t, err)
+		assert.Equal(t, tc.want, *res.(*personaQuery.PersonaAckQueryResponse))
+	}
+}
@@ -0,0 +1,74 @@
+package cardinal
+
+import (
+	"slices"
+	"strings"
+	"sync"
+
+	"pkg.world.dev/world-engine/cardinal/gamestate"
+)
+
+// layoutProfiler counts the searches of systems by query pattern. See WithLayoutProfiling.
+type layoutProfiler struct {
+	mu sync.Mutex
+	// patterns are keyed by the system and the components of the pattern.
+	patterns map[string]*gamestate.QueryPattern
+}
+
+// WithLayoutProfiling records the components that the searches of systems filter on, so that LayoutReport can suggest
+// which components to group together. Searches of queries are not recorded.
+func WithLayoutProfiling() WorldOption {
+	return WorldOption{
+		cardinalOption: func(world *World) {
+			world.layoutProfiler = &layoutProfiler{patterns: map[string]*gamestate.QueryPattern{}}
+		},
+	}
+}
+
+// LayoutReport reports how the entities of the committed game state are spread over archetypes, and, if the world was
+// created with WithLayoutProfiling, how the searches of systems use their components and which components could be
+// grouped together. Like ArchetypeStats, it reads a sample of the entities of every archetype.
+func (w *World) LayoutReport() (gamestate.LayoutReport, error) {
+	stats, err := w.ArchetypeStats()
+	if err != nil {
+		return gamestate.LayoutReport{}, err
+	}
+	var patterns []gamestate.QueryPattern
+	if w.layoutProfiler != nil {
+		patterns = w.layoutProfiler.snapshot()
+	}
+	return gamestate.AnalyzeLayout(stats, patterns), nil
+}
+
+func (p *layoutProfiler) record(system string, components []string) {
+	if system == noActiveSystemName || len(components) == 0 {
+		return
+	}
+	key := system + "\x00" + strings.Join(components, "\x00")
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	pattern, ok := p.patterns[key]
+	if !ok {
+		pattern = &gamestate.QueryPattern{System: system, Components: components}
+		p.patterns[key] = pattern
+	}
+	pattern.Searches++
+}
+
+// snapshot returns the recorded patterns, ordered by system and components.
+func (p *layoutProfiler) snapshot() []gamestate.QueryPattern {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	keys := make([]string, 0, len(p.patterns))
+	for key := range p.patterns {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	patterns := make([]gamestate.QueryPattern, 0, len(keys))
+	for _, key := range keys {
+		pattern := *p.patterns[key]
+		pattern.Components = slices.Clone(pattern.Components)
+		patterns = append(patterns, pattern)
+	}
+	return patterns
+}
@@ -0,0 +1,113 @@
+package cardinal_test
+
+import (
+	"strings"
+	"testing"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/gamestate"
+	"pkg.world.dev/world-engine/cardinal/search/filter"
+	"pkg.world.dev/world-engine/cardinal/testutils"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+func TestLayoutReportSuggestsComponentGroups(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil, cardinal.WithLayoutProfiling())
+	world := tf.World
+	assert.NilError(t, cardinal.RegisterComponent[Foo](world))
+	assert.NilError(t, cardinal.RegisterComponent[Bar](world))
+	assert.NilError(t, cardinal.RegisterComponent[Qux](world))
+	assert.NilError(t, cardinal.RegisterComponent[Health](world))
+	assert.NilError(t, cardinal.RegisterInitSystems(world, func(wCtx engine.Context) error {
+		if _, err := cardinal.CreateMany(wCtx, 3, Health{}, Foo{}); err != nil {
+			return err
+		}
+		if _, err := cardinal.CreateMany(wCtx, 2, Health{}, Bar{}); err != nil {
+			return err
+		}
+		_, err := cardinal.Create(wCtx, Qux{})
+		return err
+	}))
+	ticks := 0
+	assert.NilError(t, cardinal.RegisterSystems(world, func(wCtx engine.Context) error {
+		ticks++
+		each := func(types.EntityID) bool { return true }
+		err := cardinal.NewSearch().Entity(filter.Contains(filter.Component[Health]())).Each(wCtx, each)
+		if err != nil {
+			return err
+		}
+		return cardinal.NewSearch().
+			Entity(filter.Contains(filter.Component[Health](), filter.Component[Foo]())).Each(wCtx, each)
+	}))
+	tf.StartWorld()
+	tf.DoTick()
+	tf.DoTick()
+
+	// Searches of queries are not recorded
+	_, err := cardinal.NewSearch().Entity(filter.Contains(filter.Component[Qux]())).
+		Count(cardinal.NewReadOnlyWorldContext(world))
+	assert.NilError(t, err)
+
+	report, err := world.LayoutReport()
+	assert.NilError(t, err)
+	assert.Equal(t, 3, report.Archetypes)
+	assert.Equal(t, 6, report.Entities)
+	assert.Equal(t, 11.0/6, report.MeanComponents)
+	assert.DeepEqual(t, []gamestate.ComponentLayout{
+		{Name: "health", Archetypes: 2, Entities: 5, Searches: 2 * ticks},
+		{Name: "bar", Archetypes: 1, Entities: 2},
+		{Name: "foo", Archetypes: 1, Entities: 3, Searches: ticks},
+		{Name: "qux", Archetypes: 1, Entities: 1},
+	}, report.Components)
+	assert.Equal(t, 0, len(report.WideArchetypes))
+
+	// The built-in persona systems search too, so only the patterns of the system above are checked.
+	var patterns []gamestate.QueryPattern
+	for _, pattern := range report.Patterns {
+		if strings.HasPrefix(pattern.System, "cardinal_test.") {
+			patterns = append(patterns, pattern)
+		}
+	}
+	assert.Equal(t, 2, len(patterns))
+	for _, pattern := range patterns {
+		assert.Equal(t, ticks, pattern.Searches)
+	}
+	assert.DeepEqual(t, []string{"foo", "health"}, patterns[0].Components)
+	assert.Equal(t, 1, patterns[0].Archetypes)
+	assert.Equal(t, 3, patterns[0].Entities)
+	assert.DeepEqual(t, []string{"health"}, patterns[1].Components)
+	assert.Equal(t, 2, patterns[1].Archetypes)
+	assert.Equal(t, 5, patterns[1].Entities)
+
+	assert.DeepEqual(t, []gamestate.ComponentGroup{
+		{Components: []string{"health"}, Searches: 2 * ticks},
+		{Components: []string{"foo"}, Searches: ticks},
+	}, report.Groups)
+	assert.DeepEqual(t, []string{"bar", "qux"}, report.Unqueried)
+}
+
+func TestLayoutReportWithoutProfiling(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil)
+	world := tf.World
+	assert.NilError(t, cardinal.RegisterComponent[Health](world))
+	assert.NilError(t, cardinal.RegisterInitSystems(world, func(wCtx engine.Context) error {
+		_, err := cardinal.CreateMany(wCtx, 4, Health{})
+		return err
+	}))
+	assert.NilError(t, cardinal.RegisterSystems(world, func(wCtx engine.Context) error {
+		_, err := cardinal.NewSearch().Entity(filter.Contains(filter.Component[Health]())).Count(wCtx)
+		return err
+	}))
+	tf.StartWorld()
+	tf.DoTick()
+
+	report, err := world.LayoutReport()
+	assert.NilError(t, err)
+	assert.Equal(t, 1, report.Archetypes)
+	assert.Equal(t, 4, report.Entities)
+	assert.Equal(t, 0, len(report.Patterns))
+	assert.Equal(t, 0, len(report.Groups))
+	assert.DeepEqual(t, []string{"health"}, report.Unqueried)
+}
@@ -0,0 +1,89 @@
+package cardinal
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"pkg.world.dev/world-engine/cardinal/server"
+)
+
+// waitForLeadership blocks until this world instance owns its namespace, which it keeps renewing its lease on
+// afterward. The instance that owns the namespace is the leader, and the other instances that share it are standbys,
+// which try to claim it three times per lease. It returns false if the world was shut down while it was a standby.
+func (w *World) waitForLeadership() bool {
+	for {
+		err := w.claimNamespace()
+		if err == nil {
+			log.Info().Msgf("This world instance is the leader of namespace %q.", w.Namespace())
+			return true
+		} else if !errors.Is(err, ErrNamespaceInUse) {
+			log.Warn().Err(err).Msg("Failed to claim the namespace.")
+		}
+		select {
+		case <-w.lease.stop:
+			return false
+		case <-time.After(w.lease.renewInterval()):
+		}
+	}
+}
+
+// startStandbyServer serves a standby like a read replica while it waits to become the leader: queries are served from
+// the state that the leader commits, and transactions are added to the queue shared with the leader, which executes
+// them. The returned function stops the server, so that the leader can serve its own once it has loaded the game
+// state. Worlds hosted by a WorldManager are only served once they lead.
+func (w *World) startStandbyServer() (func(), error) {
+	if w.managed {
+		return func() {}, nil
+	}
+	// Messages are not registered with the store, since that would save their IDs.
+	if err := w.entityStore.RegisterComponents(w.componentManager.GetComponents()); err != nil {
+		return nil, err
+	}
+	replica := &readReplica{}
+	if err := w.followCommittedTick(replica); err != nil {
+		return nil, err
+	}
+	serverOptions := append(slices.Clone(w.serverOptions), server.WithReadReplica(server.ReadReplica{
+		Staleness:           replica.staleness,
+		AcceptsTransactions: true,
+	}))
+	srv, err := server.New(w,
+		NewReadOnlyWorldContext(w), w.GetRegisteredComponents(), w.GetRegisteredMessages(),
+		w.GetRegisteredQueries(), serverOptions...)
+	if err != nil {
+		return nil, err
+	}
+	go func() {
+		if err := srv.Serve(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Error().Err(err).Msg("the server of the standby has failed")
+		}
+	}()
+
+	stop, stopped := make(chan struct{}), make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(w.lease.renewInterval())
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				if err := w.followCommittedTick(replica); err != nil {
+					log.Warn().Err(err).Msg("Failed to load the last committed tick.")
+				}
+			}
+		}
+	}()
+	return func() {
+		close(stop)
+		<-stopped
+		if err := srv.Shutdown(); err != nil {
+			log.Error().Err(err).Msg("Failed to shut down the server of the standby.")
+		}
+	}, nil
+}
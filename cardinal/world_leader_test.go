@@ -0,0 +1,175 @@
+package cardinal_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/message"
+	"pkg.world.dev/world-engine/cardinal/server/handler"
+	"pkg.world.dev/world-engine/cardinal/testutils"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+	"pkg.world.dev/world-engine/sign"
+)
+
+func TestStandbyTakesOverWhenTheLeaderFails(t *testing.T) {
+	const lease = 300 * time.Millisecond
+	var executed []string
+	var id types.EntityID
+	register := func(world *cardinal.World) {
+		assert.NilError(t, cardinal.RegisterComponent[Health](world))
+		assert.NilError(t, cardinal.RegisterMessage[MoveMsg, MoveMsg](world, "move"))
+		assert.NilError(t, cardinal.RegisterSystems(world, func(wCtx engine.Context) error {
+			if wCtx.CurrentTick() == 0 {
+				var err error
+				id, err = cardinal.Create(wCtx, Health{Value: 10})
+				return err
+			}
+			return cardinal.EachMessage[MoveMsg, MoveMsg](wCtx, func(tx message.TxData[MoveMsg]) (MoveMsg, error) {
+				executed = append(executed, tx.Msg.Direction)
+				return tx.Msg, cardinal.UpdateComponent[Health](wCtx, id, func(h *Health) *Health {
+					h.Value++
+					return h
+				})
+			})
+		}))
+	}
+	leader := testutils.NewTestFixture(t, nil, cardinal.WithLeaderElection(lease))
+	register(leader.World)
+	standby := testutils.NewTestFixture(t, leader.Redis, cardinal.WithLeaderElection(lease))
+	register(standby.World)
+
+	msgType, ok := leader.World.GetMessageByFullName("game.move")
+	assert.Assert(t, ok)
+	leader.DoTick()
+	leader.AddTransaction(msgType.ID(), MoveMsg{Direction: "up"}, &sign.Transaction{PersonaTag: "alice", Nonce: 1})
+	leader.DoTick()
+
+	// A transaction that the leader accepted, but didn't execute before it failed.
+	leader.AddTransaction(msgType.ID(), MoveMsg{Direction: "down"}, &sign.Transaction{PersonaTag: "alice", Nonce: 2})
+	go func() {
+		// The standby keeps waiting while the leader renews its lease.
+		time.Sleep(3 * lease)
+		assert.Check(t, leader.World.IsGameRunning())
+		assert.Check(t, !standby.World.IsGameRunning())
+		// The leader fails, and its lease expires.
+		leader.Redis.Del(leader.World.Namespace() + ":CARDINAL:OWNER")
+	}()
+	standby.StartWorld()
+	assert.Equal(t, uint64(2), standby.World.CurrentTick())
+
+	// The leader notices that it lost its lease, and stops.
+	timeout := time.After(5 * time.Second)
+	for leader.World.IsGameRunning() {
+		select {
+		case <-timeout:
+			t.Fatal("timeout while waiting for the leader to stop")
+		default:
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	standby.DoTick()
+	assert.DeepEqual(t, []string{"up", "down"}, executed)
+	health, err := cardinal.GetComponent[Health](cardinal.NewReadOnlyWorldContext(standby.World), id)
+	assert.NilError(t, err)
+	assert.Equal(t, 12, health.Value)
+}
+
+func TestStandbyCanBeShutDownBeforeItLeads(t *testing.T) {
+	leader := testutils.NewTestFixture(t, nil, cardinal.WithLeaderElection(0))
+	leader.DoTick()
+	standby := testutils.NewTestFixture(t, leader.Redis, cardinal.WithLeaderElection(0))
+
+	started := make(chan error)
+	go func() {
+		started <- standby.World.StartGame()
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	// Wait until the standby is waiting for the lease.
+	for standby.World.Shutdown(ctx) != nil {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.NilError(t, <-started)
+	assert.Equal(t, uint64(1), leader.World.CurrentTick())
+}
+
+func TestStandbyAcceptsTransactionsForTheLeader(t *testing.T) {
+	var executed []string
+	register := func(world *cardinal.World) {
+		assert.NilError(t, cardinal.RegisterMessage[MoveMsg, MoveMsg](world, "move"))
+		assert.NilError(t, cardinal.RegisterSystems(world, func(wCtx engine.Context) error {
+			return cardinal.EachMessage[MoveMsg, MoveMsg](wCtx, func(tx message.TxData[MoveMsg]) (MoveMsg, error) {
+				executed = append(executed, tx.Msg.Direction)
+				return tx.Msg, nil
+			})
+		}))
+	}
+	opts := []cardinal.WorldOption{cardinal.WithLeaderElection(0), cardinal.WithDisableSignatureVerification()}
+	leader := testutils.NewTestFixture(t, nil, opts...)
+	register(leader.World)
+	leader.DoTick()
+	standby := testutils.NewTestFixture(t, leader.Redis, opts...)
+	register(standby.World)
+	started := make(chan error)
+	go func() {
+		started <- standby.World.StartGame()
+	}()
+
+	submit := func(baseURL string) (*http.Response, error) {
+		bz, err := json.Marshal(map[string]any{"personaTag": "alice", "nonce": 1, "body": MoveMsg{Direction: "up"}})
+		assert.NilError(t, err)
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodPost,
+			"http://"+baseURL+"/tx/game/move", bytes.NewReader(bz))
+		assert.NilError(t, err)
+		req.Header.Add("Content-Type", "application/json")
+		return http.DefaultClient.Do(req)
+	}
+	txHash := func(res *http.Response) string {
+		defer res.Body.Close()
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		var body handler.PostTransactionResponse
+		assert.NilError(t, json.NewDecoder(res.Body).Decode(&body))
+		return body.TxHash
+	}
+	// The standby serves transactions while it waits for the lease of the leader.
+	var res *http.Response
+	assert.Eventually(t, func() bool {
+		var err error
+		res, err = submit(standby.BaseURL)
+		return err == nil
+	}, 5*time.Second, 10*time.Millisecond)
+	hash := txHash(res)
+	// A client that didn't get a response from the standby submits the same transaction to the leader.
+	res, err := submit(leader.BaseURL)
+	assert.NilError(t, err)
+	assert.Equal(t, hash, txHash(res))
+
+	// The base shard sends a transaction again if it didn't get a response.
+	msgType, ok := leader.World.GetMessageByFullName("game.move")
+	assert.Assert(t, ok)
+	for i := 0; i < 2; i++ {
+		_, _, err = leader.World.AddEVMTransaction(context.Background(), msgType.ID(), MoveMsg{Direction: "left"},
+			&sign.Transaction{PersonaTag: "bob", Nonce: 1}, "0xabc")
+		assert.NilError(t, err)
+	}
+
+	leader.DoTick()
+	assert.DeepEqual(t, []string{"up", "left"}, executed)
+	_, _, evmTxHash, ok := leader.World.ConsumeEVMMsgResult("0xabc")
+	assert.Check(t, ok)
+	assert.Equal(t, "0xabc", evmTxHash)
+	assert.Check(t, !standby.World.IsGameRunning())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	assert.NilError(t, standby.World.Shutdown(ctx))
+	assert.NilError(t, <-started)
+}
@@ -0,0 +1,308 @@
+package cardinal
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rotisserie/eris"
+	"github.com/rs/zerolog/log"
+
+	"pkg.world.dev/world-engine/cardinal/server"
+)
+
+const (
+	// worldsRoutePrefix is the route prefix under which the HTTP routes of every hosted world are served, e.g. the
+	// /tx/... routes of the world "match-1" are served at /worlds/match-1/tx/...
+	worldsRoutePrefix = "/worlds"
+
+	worldStartTimeout = 10 * time.Second
+)
+
+var (
+	ErrWorldAlreadyExists = errors.New("world already exists")
+	ErrWorldNotFound      = errors.New("world not found")
+)
+
+// createWorldRequest is the body of a POST /worlds request.
+type createWorldRequest struct {
+	Name string `json:"name"`
+}
+
+// WorldInfo describes a world hosted by a WorldManager.
+type WorldInfo struct {
+	Name string `json:"name"`
+	Tick uint64 `json:"tick"`
+}
+
+// WorldManager hosts several isolated worlds in a single process (e.g. one world per match or region). Each world has
+// its own tick loop, its own redis key prefix (the world name is used as its namespace), and its HTTP routes are
+// served by the manager under /worlds/<name>/.
+type WorldManager struct {
+	cfg  WorldConfig
+	port string
+	app  *fiber.App
+
+	mu     sync.Mutex
+	worlds map[string]*World
+
+	// instanceAPI is nil unless the instance API was enabled with EnableInstanceAPI.
+	instanceAPI *instanceAPI
+}
+
+// instanceAPI holds what is needed to create worlds on behalf of HTTP clients (e.g. a Nakama match handler).
+type instanceAPI struct {
+	token string
+	setup func(*World) error
+	opts  []WorldOption
+}
+
+// NewWorldManager returns a WorldManager that serves the HTTP routes of its worlds on the given port
+// (server.DefaultPort is used if the port is empty). The config of every world is loaded from the environment, like
+// NewWorld, except for the namespace which is set to the name of the world.
+func NewWorldManager(port string) (*WorldManager, error) {
+	cfg, err := loadWorldConfig("")
+	if err != nil {
+		return nil, eris.Wrap(err, "Failed to load config to start world manager")
+	}
+	if port == "" {
+		port = server.DefaultPort
+	}
+
+	m := &WorldManager{
+		cfg:    *cfg,
+		port:   port,
+		app:    fiber.New(fiber.Config{Network: "tcp"}),
+		worlds: map[string]*World{},
+	}
+	m.app.Get(worldsRoutePrefix, m.handleListWorlds)
+	// The instance routes must be registered before the catch-all route below, which would otherwise match them.
+	m.app.Post(worldsRoutePrefix, m.handleCreateWorld)
+	m.app.Delete(worldsRoutePrefix+"/:name", m.handleStopWorld)
+	m.app.All(worldsRoutePrefix+"/:name/*", m.handleWorldRequest)
+	return m, nil
+}
+
+// EnableInstanceAPI lets HTTP clients create and stop worlds, e.g. so that a game server can spin up one world per
+// match and tear it down when the match ends:
+//
+//	POST   /worlds        {"name": "<name>"}  creates and starts a world with the given setup and options
+//	DELETE /worlds/<name>                     stops the world
+//
+// If token is not empty, requests must carry it in an "Authorization: Bearer <token>" header. The instance routes
+// respond with 404 until this method is called.
+func (m *WorldManager) EnableInstanceAPI(token string, setup func(*World) error, opts ...WorldOption) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.instanceAPI = &instanceAPI{token: token, setup: setup, opts: opts}
+}
+
+// Serve serves the HTTP routes of all hosted worlds, blocking the calling thread.
+func (m *WorldManager) Serve() error {
+	log.Info().Msgf("world manager serving on port %s", m.port)
+	if err := m.app.Listen(":" + m.port); err != nil {
+		return eris.Wrap(err, "error starting world manager server")
+	}
+	return nil
+}
+
+// CreateWorld creates a world with the given name and starts it. The setup function is called before the world is
+// started and should register the world's components, messages, queries, and systems. The name must be a valid
+// namespace and must not be used by another world hosted by this manager.
+func (m *WorldManager) CreateWorld(name string, setup func(*World) error, opts ...WorldOption) (*World, error) {
+	if err := Namespace(name).Validate(); err != nil {
+		return nil, eris.Wrapf(err, "invalid world name %q", name)
+	}
+
+	// Reserve the name while the world is starting so that other worlds can be managed in the meantime.
+	m.mu.Lock()
+	if _, ok := m.worlds[name]; ok {
+		m.mu.Unlock()
+		return nil, eris.Wrapf(ErrWorldAlreadyExists, "world %q", name)
+	}
+	m.worlds[name] = nil
+	m.mu.Unlock()
+
+	world, err := m.startWorld(name, setup, opts...)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err != nil {
+		delete(m.worlds, name)
+		return nil, err
+	}
+	m.worlds[name] = world
+	return world, nil
+}
+
+func (m *WorldManager) startWorld(name string, setup func(*World) error, opts ...WorldOption) (*World, error) {
+	cfg := m.cfg
+	cfg.CardinalNamespace = name
+	opts = append(slices.Clip(opts), WorldOption{
+		cardinalOption: func(world *World) {
+			world.managed = true
+		},
+	})
+	world, err := newWorld(&cfg, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if setup != nil {
+		if err = setup(world); err != nil {
+			return nil, errors.Join(eris.Wrapf(err, "failed to set up world %q", name), world.namespaces.Close(),
+				world.redisStorage.Close())
+		}
+	}
+
+	startErr := make(chan error, 1)
+	go func() {
+		startErr <- world.StartGame()
+	}()
+	timeout := time.After(worldStartTimeout)
+	for !world.IsGameRunning() {
+		select {
+		case err = <-startErr:
+			return nil, eris.Wrapf(err, "failed to start world %q", name)
+		case <-timeout:
+			return nil, eris.Errorf("timed out while starting world %q", name)
+		case <-time.After(10 * time.Millisecond): //nolint:gomnd // polling interval
+		}
+	}
+	return world, nil
+}
+
+// StopWorld shuts down the world with the given name and stops hosting it. The world's state is kept in redis, so a
+// world with the same name can be created again later to resume it.
+func (m *WorldManager) StopWorld(name string) error {
+	m.mu.Lock()
+	world, ok := m.worlds[name]
+	if ok && world != nil {
+		delete(m.worlds, name)
+	}
+	m.mu.Unlock()
+	if !ok || world == nil {
+		return eris.Wrapf(ErrWorldNotFound, "world %q", name)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultShutdownTimeout)
+	defer cancel()
+	if err := world.Shutdown(ctx); err != nil {
+		return eris.Wrapf(err, "failed to shut down world %q", name)
+	}
+	forgetPersonaIndex(name)
+	return nil
+}
+
+// GetWorld returns the hosted world with the given name.
+func (m *WorldManager) GetWorld(name string) (*World, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	world, ok := m.worlds[name]
+	return world, ok && world != nil
+}
+
+// ListWorlds returns the worlds hosted by the manager, sorted by name.
+func (m *WorldManager) ListWorlds() []WorldInfo {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	infos := make([]WorldInfo, 0, len(m.worlds))
+	for name, world := range m.worlds {
+		if world == nil {
+			// The world is still starting
+			continue
+		}
+		infos = append(infos, WorldInfo{Name: name, Tick: world.CurrentTick()})
+	}
+	slices.SortFunc(infos, func(a, b WorldInfo) int { return strings.Compare(a.Name, b.Name) })
+	return infos
+}
+
+// Shutdown stops all hosted worlds and the manager's HTTP server.
+func (m *WorldManager) Shutdown() error {
+	var errs []error
+	for _, info := range m.ListWorlds() {
+		if err := m.StopWorld(info.Name); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := m.app.Shutdown(); err != nil {
+		errs = append(errs, eris.Wrap(err, "error shutting down world manager server"))
+	}
+	return errors.Join(errs...)
+}
+
+// Test sends the request to the manager's HTTP server without listening on a port. This is useful in tests.
+func (m *WorldManager) Test(req *http.Request) (*http.Response, error) {
+	return m.app.Test(req, -1)
+}
+
+func (m *WorldManager) handleListWorlds(c *fiber.Ctx) error {
+	return c.JSON(m.ListWorlds())
+}
+
+// handleWorldRequest forwards /worlds/<name>/<path> to the /<path> route of the world's own server.
+func (m *WorldManager) handleWorldRequest(c *fiber.Ctx) error {
+	world, ok := m.GetWorld(c.Params("name"))
+	if !ok || world.server == nil {
+		return fiber.NewError(http.StatusNotFound, "world not found")
+	}
+	c.Path("/" + c.Params("*"))
+	world.server.Handler()(c.Context())
+	return nil
+}
+
+// authorizeInstanceRequest returns the instance API if it is enabled and the request carries the admin token.
+func (m *WorldManager) authorizeInstanceRequest(c *fiber.Ctx) (*instanceAPI, error) {
+	m.mu.Lock()
+	api := m.instanceAPI
+	m.mu.Unlock()
+	if api == nil {
+		return nil, fiber.ErrNotFound
+	}
+	if api.token != "" {
+		got := strings.TrimPrefix(c.Get(fiber.HeaderAuthorization), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(got), []byte(api.token)) != 1 {
+			return nil, fiber.ErrUnauthorized
+		}
+	}
+	return api, nil
+}
+
+func (m *WorldManager) handleCreateWorld(c *fiber.Ctx) error {
+	api, err := m.authorizeInstanceRequest(c)
+	if err != nil {
+		return err
+	}
+	var req createWorldRequest
+	if err = c.BodyParser(&req); err != nil {
+		return fiber.NewError(http.StatusBadRequest, "failed to decode request body")
+	}
+	if err = Namespace(req.Name).Validate(); err != nil {
+		return fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+	world, err := m.CreateWorld(req.Name, api.setup, api.opts...)
+	if errors.Is(err, ErrWorldAlreadyExists) {
+		return fiber.NewError(http.StatusConflict, err.Error())
+	} else if err != nil {
+		return fiber.NewError(http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(WorldInfo{Name: req.Name, Tick: world.CurrentTick()})
+}
+
+func (m *WorldManager) handleStopWorld(c *fiber.Ctx) error {
+	if _, err := m.authorizeInstanceRequest(c); err != nil {
+		return err
+	}
+	err := m.StopWorld(c.Params("name"))
+	if errors.Is(err, ErrWorldNotFound) {
+		return fiber.NewError(http.StatusNotFound, err.Error())
+	} else if err != nil {
+		return fiber.NewError(http.StatusInternalServerError, err.Error())
+	}
+	return c.SendStatus(http.StatusOK)
+}
@@ -0,0 +1,102 @@
+package cardinal_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal"
+)
+
+func TestWorldManagerHostsIsolatedWorlds(t *testing.T) {
+	miniRedis := miniredis.RunT(t)
+	t.Setenv("REDIS_ADDRESS", miniRedis.Addr())
+
+	manager, err := cardinal.NewWorldManager("")
+	assert.NilError(t, err)
+	t.Cleanup(func() { assert.NilError(t, manager.Shutdown()) })
+
+	tickChs := map[string]chan time.Time{}
+	doneChs := map[string]chan uint64{}
+	for _, name := range []string{"match-b", "match-a"} {
+		tickChs[name] = make(chan time.Time)
+		doneChs[name] = make(chan uint64)
+		_, err = manager.CreateWorld(name, func(world *cardinal.World) error {
+			return cardinal.RegisterComponent[Health](world)
+		}, cardinal.WithTickChannel(tickChs[name]), cardinal.WithTickDoneChannel(doneChs[name]))
+		assert.NilError(t, err)
+	}
+
+	_, err = manager.CreateWorld("match-a", nil)
+	assert.ErrorIs(t, err, cardinal.ErrWorldAlreadyExists)
+
+	// Each world has its own tick loop.
+	for i := 0; i < 2; i++ {
+		tickChs["match-a"] <- time.Now()
+		<-doneChs["match-a"]
+	}
+	assert.DeepEqual(t, []cardinal.WorldInfo{
+		{Name: "match-a", Tick: 2},
+		{Name: "match-b", Tick: 0},
+	}, manager.ListWorlds())
+
+	// Each world stores its state under its own key prefix.
+	for _, key := range miniRedis.Keys() {
+		assert.Check(t, strings.HasPrefix(key, "match-a:") || strings.HasPrefix(key, "match-b:"), key)
+	}
+
+	// Each world is served under its own route prefix.
+	res, err := manager.Test(httptest.NewRequest("GET", "/worlds/match-b/health", nil))
+	assert.NilError(t, err)
+	assert.Equal(t, 200, res.StatusCode)
+	res, err = manager.Test(httptest.NewRequest("GET", "/worlds/match-c/health", nil))
+	assert.NilError(t, err)
+	assert.Equal(t, 404, res.StatusCode)
+
+	assert.NilError(t, manager.StopWorld("match-a"))
+	assert.ErrorIs(t, manager.StopWorld("match-a"), cardinal.ErrWorldNotFound)
+	assert.DeepEqual(t, []cardinal.WorldInfo{{Name: "match-b", Tick: 0}}, manager.ListWorlds())
+}
+
+func TestWorldManagerInstanceAPI(t *testing.T) {
+	miniRedis := miniredis.RunT(t)
+	t.Setenv("REDIS_ADDRESS", miniRedis.Addr())
+
+	manager, err := cardinal.NewWorldManager("")
+	assert.NilError(t, err)
+	t.Cleanup(func() { assert.NilError(t, manager.Shutdown()) })
+
+	send := func(method, target, body, token string) int {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		res, err := manager.Test(req)
+		assert.NilError(t, err)
+		return res.StatusCode
+	}
+
+	// The instance routes do not exist until they are enabled.
+	assert.Equal(t, http.StatusNotFound, send("POST", "/worlds", `{"name":"match-1"}`, "secret"))
+
+	manager.EnableInstanceAPI("secret", func(world *cardinal.World) error {
+		return cardinal.RegisterComponent[Health](world)
+	})
+	assert.Equal(t, http.StatusUnauthorized, send("POST", "/worlds", `{"name":"match-1"}`, "wrong"))
+	assert.Equal(t, http.StatusBadRequest, send("POST", "/worlds", `{"name":"match_1"}`, "secret"))
+	assert.Equal(t, http.StatusOK, send("POST", "/worlds", `{"name":"match-1"}`, "secret"))
+	assert.Equal(t, http.StatusConflict, send("POST", "/worlds", `{"name":"match-1"}`, "secret"))
+	assert.DeepEqual(t, []cardinal.WorldInfo{{Name: "match-1", Tick: 0}}, manager.ListWorlds())
+	assert.Equal(t, http.StatusOK, send("GET", "/worlds/match-1/health", "", ""))
+
+	assert.Equal(t, http.StatusUnauthorized, send("DELETE", "/worlds/match-1", "", ""))
+	assert.Equal(t, http.StatusOK, send("DELETE", "/worlds/match-1", "", "secret"))
+	assert.Equal(t, http.StatusNotFound, send("DELETE", "/worlds/match-1", "", "secret"))
+	assert.DeepEqual(t, []cardinal.WorldInfo{}, manager.ListWorlds())
+}
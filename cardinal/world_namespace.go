@@ -0,0 +1,165 @@
+package cardinal
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rotisserie/eris"
+	"github.com/rs/zerolog/log"
+
+	"pkg.world.dev/world-engine/cardinal/storage/redis"
+	"pkg.world.dev/world-engine/cardinal/worldstage"
+)
+
+// DefaultNamespaceLease is how long a world instance owns its namespace without renewing its lease. A world that
+// crashed keeps other instances from starting in its namespace until its lease expired.
+const DefaultNamespaceLease = 10 * time.Second
+
+var (
+	// ErrNamespaceTakenOver is returned by a tick when another world instance has claimed the namespace of the world.
+	// The world stops ticking and shuts down, so that the two instances don't interleave their writes.
+	ErrNamespaceTakenOver = errors.New("namespace was claimed by another world instance")
+	ErrNamespaceInUse     = redis.ErrNamespaceInUse
+)
+
+// namespaceLease is the lease of a world instance on its namespace.
+type namespaceLease struct {
+	// ttl is how long the namespace is owned without renewing the lease. The lease is renewed three times per ttl.
+	ttl time.Duration
+	// validUntil is when the lease expires unless it is renewed, in unix nanoseconds. It is counted from before the
+	// lease was claimed or renewed, so the world never assumes that it owns the namespace for longer than redis does.
+	validUntil atomic.Int64
+	// stop is closed when the world stops renewing the lease.
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+func newNamespaceLease(ttl time.Duration) *namespaceLease {
+	return &namespaceLease{
+		ttl:  ttl,
+		stop: make(chan struct{}),
+	}
+}
+
+func (l *namespaceLease) renewInterval() time.Duration {
+	return l.ttl / 3 //nolint:gomnd // three attempts per lease
+}
+
+// extend records that the lease was claimed or renewed at the given time.
+func (l *namespaceLease) extend(at time.Time) {
+	l.validUntil.Store(at.Add(l.ttl).UnixNano())
+}
+
+func (l *namespaceLease) expired(now time.Time) bool {
+	return now.UnixNano() >= l.validUntil.Load()
+}
+
+func (l *namespaceLease) stopRenewal() {
+	l.stopOnce.Do(func() {
+		close(l.stop)
+	})
+}
+
+// migrateLegacyKeys moves the keys that were stored before keys were prefixed with the namespace of the world into its
+// namespace. It must run before any key is written, since a namespace that already has keys is never migrated.
+func (w *World) migrateLegacyKeys() error {
+	moved, err := w.namespaces.MigrateLegacyKeys(context.Background(), w.Namespace())
+	if err != nil {
+		return err
+	}
+	if moved > 0 {
+		log.Info().Int("keys", moved).Msgf("Moved unprefixed keys into namespace %q", w.Namespace())
+	}
+	return nil
+}
+
+// claimNamespace makes this world instance the owner of its namespace, and keeps renewing its lease on the namespace
+// until the world shuts down. It fails with ErrNamespaceInUse if another world instance owns the namespace.
+func (w *World) claimNamespace() error {
+	claimedAt := time.Now()
+	if err := w.redisStorage.ClaimNamespace(context.Background(), w.instanceID, w.lease.ttl); err != nil {
+		return err
+	}
+	w.lease.extend(claimedAt)
+	go w.renewNamespace()
+	return nil
+}
+
+// renewNamespace renews the lease of the world on its namespace until the world shuts down. A world that lost its
+// lease, or couldn't renew it before it expired, e.g. because it couldn't reach redis, shuts down, since another world
+// instance may have taken over. It stops ticking as soon as the lease expired, see checkNamespaceOwner.
+func (w *World) renewNamespace() {
+	ticker := time.NewTicker(w.lease.renewInterval())
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.lease.stop:
+			return
+		case <-w.worldStage.NotifyOnStage(worldstage.ShuttingDown):
+			return
+		case <-w.worldStage.NotifyOnStage(worldstage.ShutDown):
+			return
+		case <-ticker.C:
+		}
+		renewedAt := time.Now()
+		renewed, err := w.redisStorage.RenewNamespace(context.Background(), w.instanceID, w.lease.ttl)
+		switch {
+		case err != nil && !w.lease.expired(time.Now()):
+			log.Warn().Err(err).Msg("Failed to renew the lease of the namespace.")
+			continue
+		case err != nil:
+			log.Error().Err(err).Msgf("This world instance couldn't renew the lease of namespace %q before it "+
+				"expired. Shutting down.", w.Namespace())
+		case !renewed:
+			log.Error().Msgf("This world instance lost the lease of namespace %q. Shutting down.", w.Namespace())
+		default:
+			w.lease.extend(renewedAt)
+			continue
+		}
+		if w.worldStage.Current() == worldstage.Running {
+			go w.shutdownAfterTakeover()
+		}
+		return
+	}
+}
+
+// resignNamespace stops renewing the lease of the world when StartGame returns. If the world failed to start, the
+// lease is released, so that another world instance can start in the namespace right away.
+func (w *World) resignNamespace() {
+	w.lease.stopRenewal()
+	if w.worldStage.Current() == worldstage.ShutDown {
+		// Shutdown released the lease already
+		return
+	}
+	if err := w.redisStorage.ReleaseNamespace(context.Background(), w.instanceID); err != nil {
+		log.Error().Err(err).Msg("Failed to release namespace.")
+	}
+}
+
+// checkNamespaceOwner returns ErrNamespaceTakenOver if another world instance has claimed the namespace of the world,
+// or may have claimed it because the lease of the world expired before it could be renewed.
+func (w *World) checkNamespaceOwner() error {
+	if w.lease.expired(time.Now()) {
+		return eris.Wrapf(ErrNamespaceTakenOver, "the lease on namespace %q expired", w.Namespace())
+	}
+	owns, err := w.redisStorage.OwnsNamespace(context.Background(), w.instanceID)
+	if err != nil {
+		return err
+	}
+	if !owns {
+		return eris.Wrapf(ErrNamespaceTakenOver, "namespace %q", w.Namespace())
+	}
+	return nil
+}
+
+// shutdownAfterTakeover shuts the world down after another world instance claimed its namespace.
+func (w *World) shutdownAfterTakeover() {
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultShutdownTimeout)
+	defer cancel()
+	if err := w.Shutdown(ctx); err != nil {
+		log.Error().Err(err).Msg("There was an error during shutdown.")
+	}
+}
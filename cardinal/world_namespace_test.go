@@ -0,0 +1,96 @@
+package cardinal_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/storage/redis"
+	"pkg.world.dev/world-engine/cardinal/testutils"
+)
+
+func TestWorldStoresKeysInItsNamespace(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil)
+	assert.NilError(t, cardinal.RegisterComponent[Health](tf.World))
+	tf.DoTick()
+
+	keys := tf.Redis.Keys()
+	assert.Check(t, len(keys) > 0)
+	for _, key := range keys {
+		assert.Check(t, strings.HasPrefix(key, tf.World.Namespace()+":"), key)
+	}
+}
+
+func TestWorldRefusesToStartWhileItsNamespaceIsInUse(t *testing.T) {
+	tf1 := testutils.NewTestFixture(t, nil)
+	tf1.DoTick()
+
+	// A second world in the same namespace doesn't start, instead of interleaving its writes with the first world.
+	tf2 := testutils.NewTestFixture(t, tf1.Redis)
+	assert.ErrorIs(t, tf2.World.StartGame(), cardinal.ErrNamespaceInUse)
+	tf1.DoTick()
+	assert.Equal(t, uint64(2), tf1.World.CurrentTick())
+
+	// It starts once the first world shut down, and released the namespace.
+	tf1.Shutdown()
+	tf3 := testutils.NewTestFixture(t, tf1.Redis)
+	tf3.DoTick()
+	assert.Equal(t, uint64(3), tf3.World.CurrentTick())
+}
+
+func TestWorldStopsWhenItLosesItsNamespace(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil)
+	tf.DoTick()
+
+	// The lease of the world expired, e.g. because it couldn't reach redis, and another world took over.
+	tf.Redis.Set(tf.World.Namespace()+":CARDINAL:OWNER", "another-instance")
+	tf.StartTickCh <- time.Now()
+	timeout := time.After(5 * time.Second)
+	for tf.World.IsGameRunning() {
+		select {
+		case <-timeout:
+			t.Fatal("timeout while waiting for the world to stop")
+		default:
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+	assert.Equal(t, uint64(1), tf.World.CurrentTick())
+}
+
+func TestListAndPurgeNamespaces(t *testing.T) {
+	t.Setenv("CARDINAL_NAMESPACE", "game-1")
+	tf1 := testutils.NewTestFixture(t, nil)
+	tf1.DoTick()
+	t.Setenv("CARDINAL_NAMESPACE", "game-2")
+	tf2 := testutils.NewTestFixture(t, tf1.Redis)
+	tf2.DoTick()
+
+	namespaces, err := tf2.World.ListNamespaces()
+	assert.NilError(t, err)
+	assert.Equal(t, 2, len(namespaces))
+	assert.Equal(t, "game-1", namespaces[0].Name)
+	assert.Check(t, namespaces[0].Keys > 0)
+	assert.Check(t, namespaces[0].Owner != "")
+
+	// Namespaces of running worlds are only purged when forced, and a world never purges its own namespace.
+	_, err = tf2.World.PurgeNamespace("game-1", false)
+	assert.ErrorIs(t, err, cardinal.ErrNamespaceInUse)
+	_, err = tf2.World.PurgeNamespace("game-2", true)
+	assert.ErrorIs(t, err, cardinal.ErrNamespaceInUse)
+	_, err = tf2.World.PurgeNamespace("game-*", true)
+	assert.IsError(t, err)
+
+	keys, err := tf2.World.PurgeNamespace("game-1", true)
+	assert.NilError(t, err)
+	assert.Equal(t, namespaces[0].Keys, keys)
+	for _, key := range tf1.Redis.Keys() {
+		assert.Check(t, strings.HasPrefix(key, "game-2:"), key)
+	}
+
+	namespaces, err = tf2.World.ListNamespaces()
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []redis.NamespaceInfo{{Name: "game-2", Keys: namespaces[0].Keys, Owner: namespaces[0].Owner}},
+		namespaces)
+}
@@ -0,0 +1,36 @@
+package cardinal
+
+import (
+	"context"
+	"errors"
+
+	"github.com/rs/zerolog/log"
+)
+
+// outboxBatchSize is the maximum number of outbox messages handed to the base shard after a tick.
+const outboxBatchSize = 256
+
+var ErrInvalidContractAddress = errors.New("invalid EVM contract address")
+
+// deliverOutbox hands the messages that systems emitted with EmitToEVM to the base shard, and forgets the ones the
+// base shard acknowledged. Messages that are not acknowledged are sent again after the next tick, so a base shard
+// outage delays delivery without stopping the game loop. Message IDs are deterministic, so the base shard can drop the
+// messages it has already received, including the ones emitted again when ticks are replayed during recovery.
+func (w *World) deliverOutbox(ctx context.Context) {
+	msgs, err := w.entityStore.GetOutboxMessages(outboxBatchSize)
+	if err != nil {
+		log.Warn().Err(err).Msg("failed to load outbox messages")
+		return
+	}
+	if len(msgs) == 0 {
+		return
+	}
+	ackedID, err := w.router.DeliverOutbox(ctx, msgs)
+	if err != nil {
+		log.Warn().Err(err).Int("num_of_msgs", len(msgs)).Msg("failed to deliver outbox messages, will retry")
+		return
+	}
+	if err = w.entityStore.AckOutboxMessages(ackedID); err != nil {
+		log.Warn().Err(err).Uint64("acked_id", ackedID).Msg("failed to remove acknowledged outbox messages")
+	}
+}
@@ -0,0 +1,53 @@
+package cardinal_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/gamestate"
+	"pkg.world.dev/world-engine/cardinal/router/mocks"
+	"pkg.world.dev/world-engine/cardinal/testutils"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+const outboxContract = "0x5FbDB2315678afecb367f032d93F642f64180aa3"
+
+func TestOutboxMessagesAreRetriedUntilAcknowledged(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	rtr := mocks.NewMockRouter(ctrl)
+	tf := testutils.NewTestFixture(t, nil, cardinal.WithCustomRouter(rtr))
+	world := tf.World
+
+	assert.NilError(t, cardinal.RegisterSystems(world, func(wCtx engine.Context) error {
+		if wCtx.CurrentTick() != 0 {
+			return nil
+		}
+		assert.ErrorIs(t, wCtx.EmitToEVM("not-an-address", nil), cardinal.ErrInvalidContractAddress)
+		return wCtx.EmitToEVM(outboxContract, []byte("hello"))
+	}))
+
+	want := []gamestate.OutboxMessage{{ID: 1, Tick: 0, Contract: outboxContract, Payload: []byte("hello")}}
+	rtr.EXPECT().Start().AnyTimes()
+	rtr.EXPECT().RegisterGameShard(gomock.Any()).Times(1)
+	rtr.EXPECT().Shutdown().AnyTimes()
+	rtr.EXPECT().SubmitTxBlob(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+	gomock.InOrder(
+		// The base shard is unreachable, so the message stays in the outbox...
+		rtr.EXPECT().DeliverOutbox(gomock.Any(), want).Return(uint64(0), errors.New("connection refused")),
+		// ...and is delivered again after the next tick.
+		rtr.EXPECT().DeliverOutbox(gomock.Any(), want).Return(uint64(1), nil),
+	)
+	tf.DoTick()
+	tf.DoTick()
+	// Acknowledged messages are not delivered again.
+	tf.DoTick()
+
+	assert.ErrorIs(t,
+		cardinal.NewReadOnlyWorldContext(world).EmitToEVM(outboxContract, nil),
+		cardinal.ErrEntityMutationOnReadOnly,
+	)
+}
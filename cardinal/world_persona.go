@@ -2,9 +2,11 @@ package cardinal
 
 import (
 	"errors"
+	"time"
 
 	"github.com/rotisserie/eris"
 
+	"pkg.world.dev/world-engine/cardinal/iterators"
 	"pkg.world.dev/world-engine/cardinal/persona"
 	"pkg.world.dev/world-engine/cardinal/persona/component"
 	"pkg.world.dev/world-engine/cardinal/search"
@@ -74,3 +76,24 @@ func (w *World) GetSignerComponentForPersona(personaTag string) (*component.Sign
 	}
 	return sc, nil
 }
+
+// GetSessionKeysForPersonaTag returns the addresses of the session keys of the given persona that may sign the given
+// message now, i.e. at the current tick and time. Messages that only the persona's signer may sign have no session
+// keys, and neither do personas of worlds without the session key plugin.
+func (w *World) GetSessionKeysForPersonaTag(personaTag, msgFullName string) ([]string, error) {
+	if !w.sessionKeys || !persona.SessionKeyMaySign(msgFullName) {
+		return nil, nil
+	}
+	id, keys, err := getSessionKeys(NewReadOnlyWorldContext(w), personaTag)
+	if err != nil || id == iterators.BadID {
+		return nil, err
+	}
+	var addrs []string
+	now := uint64(time.Now().Unix())
+	for _, key := range keys.Keys {
+		if key.Allows(msgFullName, w.CurrentTick(), now) {
+			addrs = append(addrs, key.Address)
+		}
+	}
+	return addrs, nil
+}
@@ -134,6 +134,7 @@ func TestCreatePersonaSystem_WhenCardinalIsRestarted_PersonaTagsAreStillRegister
 	// There should now be 10 persona tags registered.
 
 	// Simulate a cardinal restart by creating a new test fixture with the same redis DB.
+	tf.Shutdown()
 	tf = testutils.NewTestFixture(t, tf.Redis)
 	assert.NilError(t, cardinal.RegisterSystems(tf.World, emitNumberOfPersonaTagsSystem))
 
@@ -0,0 +1,131 @@
+package cardinal
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+
+	ecslog "pkg.world.dev/world-engine/cardinal/log"
+	"pkg.world.dev/world-engine/cardinal/server"
+	"pkg.world.dev/world-engine/cardinal/worldstage"
+)
+
+// readReplica tracks the game state that a read replica serves. See WithReadReplica.
+type readReplica struct {
+	maxStaleness time.Duration
+
+	mu sync.Mutex
+	// tick is the last tick that was committed by the instance that ticks, and seenAt is when the replica saw it.
+	tick   uint64
+	seenAt time.Time
+}
+
+// staleness returns the last committed tick, and how long ago the replica saw it.
+func (r *readReplica) staleness() (uint64, time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.tick, time.Since(r.seenAt)
+}
+
+// observe records the last committed tick. It returns true if the tick is new.
+func (r *readReplica) observe(tick uint64) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if tick == r.tick && !r.seenAt.IsZero() {
+		return false
+	}
+	r.tick, r.seenAt = tick, time.Now()
+	return true
+}
+
+// startReadReplica serves the game state that another world instance commits to the storage, without ticking or
+// writing to it. The state is read from the storage on every request, so it is as recent as the last committed tick.
+func (w *World) startReadReplica() error {
+	// Messages are not registered with the store, since that would save their IDs
+	if err := w.entityStore.RegisterComponents(w.componentManager.GetComponents()); err != nil {
+		return err
+	}
+	if err := w.followCommittedTick(w.readReplica); err != nil {
+		return err
+	}
+	w.worldStage.Store(worldstage.Ready)
+
+	serverOptions := append(slices.Clone(w.serverOptions), server.WithReadReplica(server.ReadReplica{
+		Staleness:           w.readReplica.staleness,
+		MaxStaleness:        w.readReplica.maxStaleness,
+		AcceptsTransactions: w.replicatedTxs,
+	}))
+	var err error
+	w.server, err = server.New(w,
+		NewReadOnlyWorldContext(w), w.GetRegisteredComponents(), w.GetRegisteredMessages(),
+		w.GetRegisteredQueries(), serverOptions...)
+	if err != nil {
+		return err
+	}
+	ecslog.World(&log.Logger, w, zerolog.InfoLevel)
+	log.Info().Msgf("Serving namespace %q as a read replica.", w.Namespace())
+
+	// The lifecycle hooks of the start of the world are not run, since they may change the game state
+	w.worldStage.Store(worldstage.Running)
+
+	tickChannel := w.tickChannel
+	if tickChannel == nil {
+		tickChannel = w.tickClock.start(w.worldStage.NotifyOnStage(worldstage.ShutDown))
+	}
+	w.startReplicaLoop(tickChannel, w.tickDoneChannel)
+	if !w.managed {
+		w.startServer()
+		w.handleShutdown()
+	}
+	<-w.worldStage.NotifyOnStage(worldstage.ShutDown)
+	return nil
+}
+
+// followCommittedTick loads the last tick that was committed by the instance that ticks.
+func (w *World) followCommittedTick(replica *readReplica) error {
+	_, end, err := w.entityStore.GetTickNumbers()
+	if err != nil {
+		return err
+	}
+	if replica.observe(end) {
+		w.tick.Store(end)
+		w.receiptHistory.SetTick(end)
+	}
+	return nil
+}
+
+// startReplicaLoop follows the committed tick on the schedule of the ticks, until the world is shut down.
+func (w *World) startReplicaLoop(tickStart <-chan time.Time, tickDone chan<- uint64) {
+	go func() {
+		var waitingChs []chan struct{}
+	loop:
+		for {
+			select {
+			case <-tickStart:
+				if err := w.followCommittedTick(w.readReplica); err != nil {
+					log.Warn().Err(err).Msg("Failed to load the last committed tick.")
+				}
+				closeAllChannels(waitingChs)
+				waitingChs = waitingChs[:0]
+				if tickDone != nil {
+					tickDone <- w.CurrentTick()
+				}
+			case <-w.stopGameLoop:
+				w.drainChannelsWaitingForNextTick()
+				closeAllChannels(waitingChs)
+				if tickDone != nil {
+					close(tickDone)
+				}
+				break loop
+			case ch := <-w.addChannelWaitingForNextTick:
+				waitingChs = append(waitingChs, ch)
+			case fn := <-w.betweenTicks:
+				fn()
+			}
+		}
+		w.worldStage.Store(worldstage.ShutDown)
+	}()
+}
@@ -0,0 +1,88 @@
+package cardinal_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/server"
+	"pkg.world.dev/world-engine/cardinal/testutils"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+type HealthRequest struct {
+	ID types.EntityID
+}
+
+func TestReadReplicaServesCommittedState(t *testing.T) {
+	const maxStaleness = 200 * time.Millisecond
+	var id types.EntityID
+	register := func(world *cardinal.World) {
+		assert.NilError(t, cardinal.RegisterComponent[Health](world))
+		assert.NilError(t, cardinal.RegisterMessage[MoveMsg, MoveMsg](world, "move"))
+		assert.NilError(t, cardinal.RegisterQuery[HealthRequest, Health](world, "health",
+			func(wCtx engine.Context, req *HealthRequest) (*Health, error) {
+				return cardinal.GetComponent[Health](wCtx, req.ID)
+			}))
+		assert.NilError(t, cardinal.RegisterSystems(world, func(wCtx engine.Context) error {
+			if wCtx.CurrentTick() == 0 {
+				var err error
+				id, err = cardinal.Create(wCtx, Health{Value: 0})
+				return err
+			}
+			return cardinal.UpdateComponent[Health](wCtx, id, func(h *Health) *Health {
+				h.Value++
+				return h
+			})
+		}))
+	}
+	leader := testutils.NewTestFixture(t, nil)
+	register(leader.World)
+	leader.DoTick()
+	leader.DoTick()
+	replica := testutils.NewTestFixture(t, leader.Redis, cardinal.WithReadReplica(maxStaleness))
+	register(replica.World)
+	replica.StartWorld()
+
+	queryHealth := func() (*http.Response, Health) {
+		res := replica.Post("query/game/health", HealthRequest{ID: id})
+		defer res.Body.Close()
+		var health Health
+		if res.StatusCode == http.StatusOK {
+			assert.NilError(t, json.NewDecoder(res.Body).Decode(&health))
+		}
+		return res, health
+	}
+	res, health := queryHealth()
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, 1, health.Value)
+	assert.Equal(t, "2", res.Header.Get(server.ReplicaTickHeader))
+	assert.Check(t, res.Header.Get(server.ReplicaStalenessHeader) != "")
+
+	// The replica doesn't tick, but follows the ticks of the leader.
+	leader.DoTick()
+	replica.DoTick()
+	assert.Equal(t, uint64(3), replica.World.CurrentTick())
+	res, health = queryHealth()
+	assert.Equal(t, "3", res.Header.Get(server.ReplicaTickHeader))
+	assert.Equal(t, 2, health.Value)
+
+	// Requests are rejected once the replica hasn't seen a new tick for longer than the staleness bound.
+	time.Sleep(2 * maxStaleness)
+	res, _ = queryHealth()
+	assert.Equal(t, http.StatusServiceUnavailable, res.StatusCode)
+	leader.DoTick()
+	replica.DoTick()
+	res, health = queryHealth()
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, 3, health.Value)
+
+	// Transactions are only accepted by the leader.
+	res = replica.Post("tx/game/move", map[string]any{"personaTag": "alice", "body": MoveMsg{Direction: "up"}})
+	defer res.Body.Close()
+	assert.Equal(t, http.StatusMethodNotAllowed, res.StatusCode)
+}
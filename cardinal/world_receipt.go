@@ -33,6 +33,9 @@ func (w *World) GetEVMMsgReceipt(evmTxHash string) (EVMTxReceipt, bool) {
 func (w *World) setEvmResults(txs []txpool.TxData) {
 	// iterate over all EVM originated transactions
 	for _, tx := range txs {
+		if tx.EVMSourceTxHash == "" {
+			continue
+		}
 		// see if tx has a receipt. sometimes it won't because:
 		// The system isn't using TxIterators && never explicitly called SetResult.
 		rec, ok := w.receiptHistory.GetReceipt(tx.TxHash)
@@ -2,7 +2,6 @@ package cardinal
 
 import (
 	"context"
-	"time"
 
 	"github.com/rotisserie/eris"
 
@@ -10,13 +9,20 @@ import (
 )
 
 // recoverAndExecutePendingTxs checks whether the last tick is successfully completed. If not, it will recover
-// the pending transactions.
+// the pending transactions and run the tick again with the timestamp that was recorded when it was first started.
 func (w *World) recoverAndExecutePendingTxs() error {
 	start, end, err := w.entityStore.GetTickNumbers()
 	if err != nil {
 		return err
 	}
 	w.tick.Store(end)
+
+	timestamp, err := w.entityStore.GetTickTimestamp()
+	if err != nil {
+		return err
+	}
+	w.timestamp.Store(timestamp)
+
 	// We successfully completed the last tick. Everything is fine
 	if start == end {
 		return nil
@@ -33,7 +39,7 @@ func (w *World) recoverAndExecutePendingTxs() error {
 		// TODO(scott): this is hacky, but i dont want to fix this now because it's PR scope creep.
 		//  but we ideally don't want to treat this as a special tick and should just let it execute normally
 		//  from the game loop.
-		if err = w.doTick(context.Background(), uint64(time.Now().Unix())); err != nil {
+		if err = w.doTick(context.Background(), timestamp); err != nil {
 			return err
 		}
 	}
@@ -61,7 +67,8 @@ func (w *World) RecoverFromChain(ctx context.Context) error {
 		}
 
 		for _, batch := range batches {
-			w.AddTransaction(batch.MsgID, batch.MsgValue, batch.Tx)
+			// The transactions of the ticks that are replayed are executed by this instance, which is the one that ticks
+			w.txPool.AddTransaction(batch.MsgID, batch.MsgValue, batch.Tx)
 		}
 
 		if err := w.doTick(ctx, timestamp); err != nil {
@@ -88,6 +88,7 @@ func TestWorldRecovery(t *testing.T) {
 				router.EXPECT().TransactionIterator().Return(iter).Times(1)
 				router.EXPECT().Start().Times(1)
 				router.EXPECT().RegisterGameShard(gomock.Any()).Times(1)
+				router.EXPECT().Shutdown().AnyTimes()
 				router.EXPECT().
 					SubmitTxBlob(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 					Return(nil).AnyTimes()
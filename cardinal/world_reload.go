@@ -0,0 +1,70 @@
+package cardinal
+
+import (
+	"errors"
+	"plugin"
+
+	"github.com/rotisserie/eris"
+	"github.com/rs/zerolog/log"
+)
+
+// SystemsPluginSymbol is the name of the variable that a systems plugin must export. It must be of type
+// map[string]cardinal.System, mapping the names of registered systems to their new implementation.
+const SystemsPluginSymbol = "Systems"
+
+var (
+	ErrHotReloadDisabled = errors.New("hot reload of systems is not enabled")
+	ErrInvalidPlugin     = errors.New("invalid systems plugin")
+)
+
+// ReloadSystems replaces the implementation of registered systems at the next tick boundary, without restarting the
+// world. The game state is kept, since it lives in the entity store and not in the systems. The systems keep their
+// names, position in the tick and enabled state. Either all systems are replaced, or none are.
+//
+// This is experimental and must be enabled with WithExperimentalHotReload. Replacing the logic of systems changes the
+// outcome of replaying the transactions of previous ticks, so it should not be used by worlds that are recovered from
+// the base shard.
+func (w *World) ReloadSystems(replacements map[string]System) error {
+	if !w.hotReload {
+		return eris.Wrap(ErrHotReloadDisabled, "")
+	}
+	err := w.runBetweenTicks(func() error {
+		return w.SystemManager.replaceSystems(replacements)
+	})
+	if err != nil {
+		return err
+	}
+	for name := range replacements {
+		log.Info().Str("system", name).Uint64("tick", w.CurrentTick()).Msg("system reloaded")
+	}
+	return nil
+}
+
+// LoadSystemsPlugin opens a Go plugin and replaces the registered systems with the ones it exports, as ReloadSystems
+// does. The plugin must be built with `go build -buildmode=plugin` against the same version of the game and its
+// dependencies, and export a SystemsPluginSymbol variable:
+//
+//	var Systems = map[string]cardinal.System{
+//		"systems.RegenSystem": RegenSystem,
+//	}
+//
+// A plugin can not be unloaded, so every reload keeps the code of the previous version in memory.
+func (w *World) LoadSystemsPlugin(path string) error {
+	if !w.hotReload {
+		return eris.Wrap(ErrHotReloadDisabled, "")
+	}
+	p, err := plugin.Open(path)
+	if err != nil {
+		return eris.Wrapf(err, "failed to open systems plugin %s", path)
+	}
+	sym, err := p.Lookup(SystemsPluginSymbol)
+	if err != nil {
+		return eris.Wrapf(ErrInvalidPlugin, "%s does not export %s", path, SystemsPluginSymbol)
+	}
+	systems, ok := sym.(*map[string]System)
+	if !ok {
+		return eris.Wrapf(ErrInvalidPlugin, "%s.%s is a %T, expected map[string]cardinal.System", path,
+			SystemsPluginSymbol, sym)
+	}
+	return w.ReloadSystems(*systems)
+}
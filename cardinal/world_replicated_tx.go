@@ -0,0 +1,145 @@
+package cardinal
+
+import (
+	"context"
+	"time"
+
+	"github.com/rotisserie/eris"
+	"github.com/rs/zerolog/log"
+
+	"pkg.world.dev/world-engine/cardinal/codec"
+	"pkg.world.dev/world-engine/cardinal/gamestate"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/txpool"
+	"pkg.world.dev/world-engine/sign"
+)
+
+// DefaultReplicatedTxWindow is how long the hash of a transaction that was taken from the queue shared by the replicas
+// of a world is remembered. The same transaction submitted again within the window is not executed again.
+const DefaultReplicatedTxWindow = 10 * time.Minute
+
+// replicatedTx is a transaction in the queue shared by the replicas of a world, or a pending co-signed transaction.
+type replicatedTx struct {
+	TypeID types.MessageID
+	Data   []byte
+	Tx     *sign.Transaction
+	// EVMTxHash is the hash of the EVM transaction that sent the transaction, if it was sent by the base shard.
+	EVMTxHash string
+}
+
+// enqueueReplicatedTx adds a transaction to the queue shared by the replicas of the world. A transaction that is
+// already queued, or was already taken by the replica that ticks, is not queued again.
+func (w *World) enqueueReplicatedTx(id types.MessageID, v any, sig *sign.Transaction, evmTxHash string) error {
+	// The hash is computed before the transaction is encoded, so that the replica that ticks gets the same hash
+	txHash := sig.HashHex()
+	bz, err := w.encodeTx(id, v, sig, evmTxHash)
+	if err != nil {
+		return err
+	}
+	_, err = w.redisStorage.EnqueueReplicatedTx(txHash, bz)
+	return eris.Wrap(err, "failed to enqueue replicated transaction")
+}
+
+// encodeTx encodes a transaction and its message, so that it can be stored outside the transaction pool.
+func (w *World) encodeTx(id types.MessageID, v any, sig *sign.Transaction, evmTxHash string) ([]byte, error) {
+	msgType, ok := w.GetMessageByID(id)
+	if !ok {
+		return nil, eris.Errorf("message with id %d is not registered", id)
+	}
+	data, err := msgType.Encode(v)
+	if err != nil {
+		return nil, err
+	}
+	return codec.Encode(replicatedTx{TypeID: id, Data: data, Tx: sig, EVMTxHash: evmTxHash})
+}
+
+// decodeTx decodes a transaction and its message that were encoded with encodeTx.
+func (w *World) decodeTx(bz []byte) (replicatedTx, any, error) {
+	tx, err := codec.Decode[replicatedTx](bz)
+	if err != nil {
+		return replicatedTx{}, nil, err
+	}
+	msgType, ok := w.GetMessageByID(tx.TypeID)
+	if !ok {
+		return replicatedTx{}, nil, eris.Errorf("message with id %d is not registered", tx.TypeID)
+	}
+	msg, err := msgType.Decode(tx.Data)
+	if err != nil {
+		return replicatedTx{}, nil, err
+	}
+	return tx, msg, nil
+}
+
+// takeReplicatedTxs moves the transactions of the queue shared by the replicas of the world into the transaction pool,
+// in the order they were queued in. It is called by the replica that ticks, before it takes the transactions of the
+// next tick from the pool. A failure is logged and leaves the transactions in the queue for the next tick.
+func (w *World) takeReplicatedTxs() {
+	if !w.replicatedTxs {
+		return
+	}
+	queued, err := w.redisStorage.TakeReplicatedTxs(w.replicatedTxWindow)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to take replicated transactions")
+		return
+	}
+	for _, queuedTx := range queued {
+		// A transaction stays in the queue until the tick it is executed in was started
+		if _, ok := w.replicatedTxsInPool[queuedTx.TxHash]; ok {
+			continue
+		}
+		tx, msg, err := w.decodeTx(queuedTx.Data)
+		if err != nil {
+			log.Error().Err(err).Str("tx_hash", queuedTx.TxHash).Msg("dropping replicated transaction that can't be decoded")
+			continue
+		}
+		// Transactions that were not sent by the base shard have no EVM transaction hash
+		w.txPool.AddEVMTransaction(context.Background(), tx.TypeID, msg, tx.Tx, tx.EVMTxHash)
+		w.replicatedTxsInPool[queuedTx.TxHash] = struct{}{}
+	}
+}
+
+// removeReplicatedTxs removes the transactions of the tick that were taken from the queue shared by the replicas of the
+// world from the queue, in the same transaction that persists them as the pending transactions of the tick. A replica
+// that fails before the tick started leaves them in the queue, and one that fails after recovers them with the tick.
+// The transactions that expired in the pool are removed as well, since they are never executed. It returns the hashes
+// of the removed transactions.
+func (w *World) removeReplicatedTxs(txPool *txpool.TxPool, expired []txpool.TxData) []string {
+	hashes := w.replicatedTxHashes(txPool, expired)
+	if len(hashes) == 0 {
+		return nil
+	}
+	queueKey := w.redisStorage.ReplicatedTxQueueKey()
+	w.entityStore.AddTickStartWrite(func(ctx context.Context, pipe gamestate.PrimitiveStorage[string]) error {
+		return pipe.DeleteFields(ctx, queueKey, hashes...)
+	})
+	return hashes
+}
+
+// forgetReplicatedTxs forgets the transactions of a tick that started, since they were removed from the queue shared by
+// the replicas of the world.
+func (w *World) forgetReplicatedTxs(hashes []string) {
+	for _, txHash := range hashes {
+		delete(w.replicatedTxsInPool, txHash)
+	}
+}
+
+// replicatedTxHashes returns the hashes of the transactions in the pool and of the expired transactions that were taken
+// from the queue shared by the replicas of the world.
+func (w *World) replicatedTxHashes(txPool *txpool.TxPool, expired []txpool.TxData) []string {
+	if len(w.replicatedTxsInPool) == 0 {
+		return nil
+	}
+	var hashes []string
+	add := func(txs []txpool.TxData) {
+		for _, tx := range txs {
+			if _, ok := w.replicatedTxsInPool[string(tx.TxHash)]; ok {
+				hashes = append(hashes, string(tx.TxHash))
+			}
+		}
+	}
+	for _, txs := range txPool.Transactions() {
+		add(txs)
+	}
+	add(expired)
+	return hashes
+}
@@ -0,0 +1,49 @@
+package cardinal_test
+
+import (
+	"testing"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/message"
+	"pkg.world.dev/world-engine/cardinal/testutils"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+	"pkg.world.dev/world-engine/sign"
+)
+
+func TestReplicatedTransactionIsExecutedOnceByTheActiveReplica(t *testing.T) {
+	var executed []string
+	register := func(world *cardinal.World) {
+		assert.NilError(t, cardinal.RegisterMessage[MoveMsg, MoveMsg](world, "move"))
+		assert.NilError(t, cardinal.RegisterSystems(world, func(wCtx engine.Context) error {
+			return cardinal.EachMessage[MoveMsg, MoveMsg](wCtx, func(tx message.TxData[MoveMsg]) (MoveMsg, error) {
+				executed = append(executed, tx.Msg.Direction)
+				return tx.Msg, nil
+			})
+		}))
+	}
+	// The standby accepts transactions, but never ticks.
+	standby := testutils.NewTestFixture(t, nil, cardinal.WithReplicatedTxQueue(0))
+	register(standby.World)
+	primary := testutils.NewTestFixture(t, standby.Redis, cardinal.WithReplicatedTxQueue(0))
+	register(primary.World)
+	primary.StartWorld()
+	msgType, ok := primary.World.GetMessageByFullName("game.move")
+	assert.Assert(t, ok)
+
+	up := &sign.Transaction{PersonaTag: "alice", Nonce: 1}
+	down := &sign.Transaction{PersonaTag: "alice", Nonce: 2}
+	hash := standby.AddTransaction(msgType.ID(), MoveMsg{Direction: "up"}, up)
+	// A client that didn't get a response from the standby submits the same transaction to the primary.
+	assert.Equal(t, hash, primary.AddTransaction(msgType.ID(), MoveMsg{Direction: "up"}, up))
+	primary.AddTransaction(msgType.ID(), MoveMsg{Direction: "down"}, down)
+	primary.DoTick()
+	assert.DeepEqual(t, []string{"up", "down"}, executed)
+	// The transactions were removed from the queue when the tick started.
+	assert.Check(t, !primary.Redis.Exists(primary.World.Namespace()+":REPLICATED_TX_QUEUE"))
+
+	// Transactions that were already executed are not executed again.
+	standby.AddTransaction(msgType.ID(), MoveMsg{Direction: "up"}, up)
+	primary.DoTick()
+	assert.Equal(t, 2, len(executed))
+}
@@ -0,0 +1,88 @@
+package cardinal
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/rotisserie/eris"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"pkg.world.dev/world-engine/cardinal/gamestate"
+	"pkg.world.dev/world-engine/cardinal/receipt"
+	"pkg.world.dev/world-engine/cardinal/tracing"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/txpool"
+	"pkg.world.dev/world-engine/sign"
+)
+
+// SimulateTransaction runs the systems of the next tick with the transaction as its only transaction, and returns the
+// tick, the receipt of the transaction and the events that the tick would emit. The changes of the tick are discarded
+// afterward, so the game state is not changed. The tick runs against the committed game state, so the transaction may
+// have another outcome once it is submitted, e.g. if other transactions are executed before it. The signature of the
+// transaction is not verified, and its nonce is not used.
+func (w *World) SimulateTransaction(ctx context.Context, id types.MessageID, v any, sig *sign.Transaction) (
+	tick uint64, rec receipt.Receipt, events [][]byte, err error,
+) {
+	if w.readReplica != nil {
+		return 0, rec, nil, eris.New("read replicas don't run systems, so they can't simulate transactions")
+	}
+	err = w.runBetweenTicks(func() error {
+		tick = w.CurrentTick()
+		var err error
+		rec, events, err = w.simulateTick(ctx, id, v, sig)
+		return err
+	})
+	return tick, rec, events, err
+}
+
+// simulateTick runs the systems and triggers of the next tick with the transaction, and discards their changes.
+func (w *World) simulateTick(ctx context.Context, id types.MessageID, v any, sig *sign.Transaction) (
+	rec receipt.Receipt, events [][]byte, err error,
+) {
+	ecb, ok := w.entityStore.(*gamestate.EntityCommandBuffer)
+	if !ok {
+		return rec, nil, eris.New("transactions can only be simulated with the default store manager")
+	}
+	txPool := txpool.New()
+	hash := txPool.AddTransactionWithContext(ctx, id, v, sig)
+
+	ctx, span := tracing.Tracer().Start(ctx, "cardinal.simulate",
+		trace.WithAttributes(
+			attribute.String("tx_hash", string(hash)),
+			attribute.Int64("tick", int64(w.CurrentTick())),
+		),
+	)
+	defer func() {
+		tracing.End(span, err)
+	}()
+
+	eventOffset := len(w.tickResults.Events)
+	timestamp := w.timestamp.Load()
+	w.timestamp.Store(uint64(time.Now().Unix()))
+	defer func() {
+		// A system that panics while simulating the transaction must not take the world down with it
+		if r := recover(); r != nil {
+			err = eris.Errorf("system %s panicked: %v", w.SystemManager.GetCurrentSystem(), r)
+		}
+		w.timestamp.Store(timestamp)
+		if discardErr := w.discardSystemChanges(ecb, eventOffset); discardErr != nil && err == nil {
+			err = discardErr
+		}
+	}()
+
+	wCtx := newWorldContextForTick(w, txPool)
+	if err = w.SystemManager.runSystems(ctx, wCtx); err != nil {
+		return rec, nil, err
+	}
+	if err = w.runTriggers(wCtx); err != nil {
+		return rec, nil, err
+	}
+	rec, ok = w.receiptHistory.GetReceipt(hash)
+	if !ok {
+		// No system handled the message of the transaction
+		rec = receipt.Receipt{TxHash: hash, Tick: w.CurrentTick()}
+	}
+	return rec, slices.Clone(w.tickResults.Events[eventOffset:]), nil
+}
@@ -0,0 +1,91 @@
+package cardinal_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/message"
+	"pkg.world.dev/world-engine/cardinal/receipt"
+	"pkg.world.dev/world-engine/cardinal/server/handler"
+	"pkg.world.dev/world-engine/cardinal/testutils"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+	"pkg.world.dev/world-engine/sign"
+)
+
+type DamageMsg struct {
+	ID     types.EntityID
+	Amount int
+}
+
+func TestSimulateTransactionDoesNotChangeGameState(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil)
+	world := tf.World
+	assert.NilError(t, cardinal.RegisterComponent[Health](world))
+	assert.NilError(t, cardinal.RegisterMessage[DamageMsg, Health](world, "damage"))
+	var id types.EntityID
+	assert.NilError(t, cardinal.RegisterInitSystems(world, func(wCtx engine.Context) error {
+		var err error
+		id, err = cardinal.Create(wCtx, Health{Value: 10})
+		return err
+	}))
+	assert.NilError(t, cardinal.RegisterSystems(world, func(wCtx engine.Context) error {
+		return cardinal.EachMessage[DamageMsg, Health](wCtx, func(tx message.TxData[DamageMsg]) (Health, error) {
+			health, err := cardinal.GetComponent[Health](wCtx, tx.Msg.ID)
+			if err != nil {
+				return Health{}, err
+			}
+			if health.Value < tx.Msg.Amount {
+				return Health{}, errors.New("not enough health")
+			}
+			health.Value -= tx.Msg.Amount
+			if err = cardinal.SetComponent[Health](wCtx, tx.Msg.ID, health); err != nil {
+				return Health{}, err
+			}
+			return *health, wCtx.EmitEvent(map[string]any{"event": "damage", "value": health.Value})
+		})
+	}))
+	tf.StartWorld()
+	tf.DoTick()
+
+	simulate := func(amount int) handler.SimulateTransactionResponse {
+		res := tf.Post("tx/simulate/game/damage",
+			map[string]any{"personaTag": "alice", "body": DamageMsg{ID: id, Amount: amount}})
+		defer res.Body.Close()
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		var sim handler.SimulateTransactionResponse
+		assert.NilError(t, json.NewDecoder(res.Body).Decode(&sim))
+		return sim
+	}
+	assertHealth := func(want int) {
+		health, err := cardinal.GetComponent[Health](cardinal.NewReadOnlyWorldContext(world), id)
+		assert.NilError(t, err)
+		assert.Equal(t, want, health.Value)
+	}
+
+	sim := simulate(3)
+	assert.Equal(t, world.CurrentTick(), sim.Receipt.Tick)
+	assert.Equal(t, receipt.StatusSuccess, sim.Receipt.Status)
+	assert.DeepEqual(t, map[string]any{"Value": float64(7)}, sim.Receipt.Result)
+	assert.DeepEqual(t, []string{`{"event":"damage","value":7}`}, sim.Events)
+	assertHealth(10)
+	// The changes of the simulated tick are not committed by the next tick either
+	tf.DoTick()
+	assertHealth(10)
+
+	sim = simulate(20)
+	assert.Equal(t, receipt.StatusFailed, sim.Receipt.Status)
+	assert.DeepEqual(t, []string{"not enough health"}, sim.Receipt.Errors)
+	assert.Equal(t, 0, len(sim.Events))
+
+	// The simulated transaction can still be submitted
+	msgType, ok := world.GetMessageByFullName("game.damage")
+	assert.Assert(t, ok)
+	tf.AddTransaction(msgType.ID(), DamageMsg{ID: id, Amount: 3}, &sign.Transaction{PersonaTag: "alice"})
+	tf.DoTick()
+	assertHealth(7)
+}
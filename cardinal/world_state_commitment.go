@@ -0,0 +1,69 @@
+package cardinal
+
+import (
+	"context"
+
+	"github.com/rs/zerolog/log"
+
+	"pkg.world.dev/world-engine/cardinal/gamestate"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/worldstage"
+)
+
+// StateCommitments returns the state commitments that can still be proven, sorted by tick. See WithStateCommitments.
+func (w *World) StateCommitments() ([]gamestate.StateCommitment, error) {
+	ecb, err := w.checkpointStore()
+	if err != nil {
+		return nil, err
+	}
+	return ecb.ListStateCommitments()
+}
+
+// ProveState returns a merkle proof that the entity had the given value of the named component at the end of the range
+// of ticks of the state commitment that contains the given tick. The proof can be checked against the state root that
+// was submitted to the base shard for that range, without trusting this game shard.
+func (w *World) ProveState(tick uint64, id types.EntityID, component string) (gamestate.StateProof, error) {
+	ecb, err := w.checkpointStore()
+	if err != nil {
+		return gamestate.StateProof{}, err
+	}
+	return ecb.ProveState(tick, id, component)
+}
+
+// saveStateCommitment commits to the game state if the tick that just completed is a multiple of the state commitment
+// interval, and submits the state root to the base shard. The commitment is computed in the background, so that it
+// doesn't delay the tick. Failures are logged, since a missed commitment must not stop the game; the next commitment
+// covers the ticks of the missed one.
+func (w *World) saveStateCommitment(ctx context.Context) {
+	if w.stateCommitmentTicks == 0 || w.CurrentTick()%w.stateCommitmentTicks != 0 {
+		return
+	}
+	ecb, err := w.checkpointStore()
+	if err != nil {
+		log.Error().Err(err).Msg("failed to save state commitment")
+		return
+	}
+	finish, err := ecb.StartStateCommitment(w.stateCommitmentsKept)
+	if err != nil {
+		log.Error().Err(err).Uint64("tick", w.CurrentTick()).Msg("failed to save state commitment")
+		return
+	}
+	// State roots of replayed ticks were already submitted.
+	submit := w.router != nil && w.worldStage.Current() != worldstage.Recovering
+	w.stateCommitments.Add(1)
+	go func() {
+		defer w.stateCommitments.Done()
+		commitment, err := finish()
+		if err != nil {
+			log.Error().Err(err).Msg("failed to save state commitment")
+			return
+		}
+		if !submit {
+			return
+		}
+		err = w.router.SubmitStateRoot(ctx, commitment.StartTick, commitment.EndTick, commitment.Root[:])
+		if err != nil {
+			log.Warn().Err(err).Uint64("end_tick", commitment.EndTick).Msg("failed to submit state root to base shard")
+		}
+	}()
+}
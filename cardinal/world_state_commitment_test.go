@@ -0,0 +1,72 @@
+package cardinal_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/router/mocks"
+	"pkg.world.dev/world-engine/cardinal/testutils"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+func TestStateRootsAreSubmittedToBaseShard(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	rtr := mocks.NewMockRouter(ctrl)
+	tf := testutils.NewTestFixture(t, nil, cardinal.WithCustomRouter(rtr), cardinal.WithStateCommitments(2, 1))
+	world := tf.World
+	assert.NilError(t, cardinal.RegisterComponent[Health](world))
+
+	var id types.EntityID
+	assert.NilError(t, cardinal.RegisterSystems(world, func(wCtx engine.Context) error {
+		var err error
+		if wCtx.CurrentTick() == 0 {
+			id, err = cardinal.Create(wCtx, Health{Value: 10})
+		} else {
+			err = cardinal.SetComponent(wCtx, id, &Health{Value: int(wCtx.CurrentTick())})
+		}
+		return err
+	}))
+
+	rtr.EXPECT().Start().AnyTimes()
+	rtr.EXPECT().RegisterGameShard(gomock.Any()).Times(1)
+	rtr.EXPECT().Shutdown().AnyTimes()
+	rtr.EXPECT().SubmitTxBlob(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+	// State roots are submitted in the background once the commitments are saved.
+	roots := make(chan []byte, 2)
+	gomock.InOrder(
+		// A failed submission doesn't stop the game.
+		rtr.EXPECT().SubmitStateRoot(gomock.Any(), uint64(0), uint64(1), gomock.Any()).
+			DoAndReturn(func(_ any, _, _ uint64, root []byte) error {
+				roots <- root
+				return errors.New("connection refused")
+			}),
+		rtr.EXPECT().SubmitStateRoot(gomock.Any(), uint64(2), uint64(3), gomock.Any()).
+			DoAndReturn(func(_ any, _, _ uint64, root []byte) error {
+				roots <- root
+				return nil
+			}),
+	)
+	for i := 0; i < 4; i++ {
+		tf.DoTick()
+	}
+	<-roots
+	root := <-roots
+
+	// Only the last commitment is kept.
+	commitments, err := world.StateCommitments()
+	assert.NilError(t, err)
+	assert.Equal(t, 1, len(commitments))
+	assert.DeepEqual(t, root, commitments[0].Root[:])
+
+	proof, err := world.ProveState(2, id, Health{}.Name())
+	assert.NilError(t, err)
+	assert.Equal(t, `{"Value":3}`, string(proof.Leaf.Value))
+	assert.Check(t, proof.Verify())
+	_, err = world.ProveState(1, id, Health{}.Name())
+	assert.ErrorContains(t, err, "state commitment not found")
+}
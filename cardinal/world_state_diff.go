@@ -0,0 +1,24 @@
+package cardinal
+
+import (
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal/gamestate"
+)
+
+// Diff returns the entities that were created and destroyed, and the component values that changed, from the start of
+// fromTick to the end of the tick before toTick, so that Diff(t, t+1) is the change made by tick t. Diffs can be
+// chained: the diff from a to c is the diff from a to b followed by the diff from b to c. The diffs of ticks are only
+// recorded with WithStateDiffs; gamestate.ErrStateDiffNotAvailable is returned for ticks whose diff was not recorded,
+// or was already deleted.
+func (w *World) Diff(fromTick, toTick uint64) (gamestate.StateDiff, error) {
+	if toTick > w.CurrentTick() {
+		return gamestate.StateDiff{}, eris.Wrapf(gamestate.ErrStateDiffNotAvailable,
+			"tick %d has not completed yet", toTick-1)
+	}
+	ecb, err := w.checkpointStore()
+	if err != nil {
+		return gamestate.StateDiff{}, err
+	}
+	return ecb.StateDiff(fromTick, toTick)
+}
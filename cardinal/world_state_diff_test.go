@@ -0,0 +1,58 @@
+package cardinal_test
+
+import (
+	"testing"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/gamestate"
+	"pkg.world.dev/world-engine/cardinal/testutils"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+func TestDiffReportsChangesBetweenTicks(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil, cardinal.WithStateDiffs(0))
+	world := tf.World
+	assert.NilError(t, cardinal.RegisterComponent[Health](world))
+
+	var id types.EntityID
+	assert.NilError(t, cardinal.RegisterSystems(world, func(wCtx engine.Context) error {
+		var err error
+		switch wCtx.CurrentTick() {
+		case 0:
+			id, err = cardinal.Create(wCtx, Health{Value: 10})
+		case 2:
+			err = cardinal.Remove(wCtx, id)
+		default:
+			err = cardinal.SetComponent(wCtx, id, &Health{Value: 5})
+		}
+		return err
+	}))
+	for i := 0; i < 3; i++ {
+		tf.DoTick()
+	}
+
+	diff, err := world.Diff(0, 1)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []types.EntityID{id}, diff.Created)
+	assert.Equal(t, 1, len(diff.Changed))
+	assert.Equal(t, "health", diff.Changed[0].Component)
+	assert.Equal(t, `{"Value":10}`, string(diff.Changed[0].New))
+
+	diff, err = world.Diff(1, 3)
+	assert.NilError(t, err)
+	assert.Equal(t, 0, len(diff.Created))
+	assert.DeepEqual(t, []types.EntityID{id}, diff.Destroyed)
+	assert.Equal(t, 1, len(diff.Changed))
+	assert.Equal(t, `{"Value":10}`, string(diff.Changed[0].Old))
+	assert.Equal(t, "null", string(diff.Changed[0].New))
+
+	// The entity was created and destroyed within the whole range.
+	diff, err = world.Diff(0, 3)
+	assert.NilError(t, err)
+	assert.Equal(t, 0, len(diff.Created)+len(diff.Destroyed)+len(diff.Changed))
+
+	_, err = world.Diff(0, 4)
+	assert.ErrorIs(t, err, gamestate.ErrStateDiffNotAvailable)
+}
@@ -0,0 +1,39 @@
+package cardinal
+
+import (
+	"pkg.world.dev/world-engine/cardinal/gamestate"
+	"pkg.world.dev/world-engine/cardinal/types"
+)
+
+// Snapshot returns the committed state of the entities that have all of the named components, with only the values of
+// those components, or of every entity if no component is named. The snapshot is the state at the start of its tick, so
+// that spectators can follow it with Diff(snapshot.Tick, ...). It is read without stopping the world.
+func (w *World) Snapshot(components []string) (gamestate.Snapshot, error) {
+	cTypes := make([]types.ComponentMetadata, 0, len(components))
+	for _, name := range components {
+		cType, err := w.GetComponentByName(name)
+		if err != nil {
+			return gamestate.Snapshot{}, err
+		}
+		cTypes = append(cTypes, cType)
+	}
+	ecb, err := w.checkpointStore()
+	if err != nil {
+		return gamestate.Snapshot{}, err
+	}
+	return ecb.Snapshot(cTypes)
+}
+
+// RecordsStateDiffs reports whether the diff of every tick is recorded, which Diff and the state stream of the server
+// depend on. See WithStateDiffs.
+func (w *World) RecordsStateDiffs() bool {
+	return w.recordStateDiffs
+}
+
+// streamStateDiffs sends the diff of the tick that just completed to the spectators of the server's state stream.
+func (w *World) streamStateDiffs() {
+	if w.server == nil || !w.recordStateDiffs {
+		return
+	}
+	w.server.StreamStateDiffs(w.CurrentTick())
+}
@@ -9,10 +9,12 @@ import (
 	"io"
 	"net"
 	"reflect"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
 	"github.com/rotisserie/eris"
 	"github.com/rs/zerolog"
 
@@ -60,7 +62,7 @@ func TestIfPanicMessageLogged(t *testing.T) {
 	}()
 	<-world.worldStage.NotifyOnStage(worldstage.Running)
 	defer func() {
-		assert.NilError(t, world.Shutdown())
+		assert.NilError(t, world.Shutdown(context.Background()))
 	}()
 
 	ctx := context.Background()
@@ -183,7 +185,7 @@ func TestCanRecoverStateAfterFailedArchetypeChange(t *testing.T) {
 			assert.Equal(t, 5, s.Val)
 		}
 
-		assert.NilError(t, world.Shutdown())
+		assert.NilError(t, world.Shutdown(context.Background()))
 	}
 
 	miniRedis.Close()
@@ -282,7 +284,7 @@ func TestCanRecoverTransactionsFromFailedSystemRun(t *testing.T) {
 			assert.Equal(t, float64(4666), fetchPower())
 		}
 
-		assert.NilError(t, world.Shutdown())
+		assert.NilError(t, world.Shutdown(context.Background()))
 	}
 	rs.Close()
 }
@@ -352,7 +354,7 @@ func TestCanIdentifyAndFixSystemError(t *testing.T) {
 	err = doTickCapturePanic(ctx, world)
 	assert.ErrorContains(t, err, errorSystem.Error())
 
-	assert.NilError(t, world.Shutdown())
+	assert.NilError(t, world.Shutdown(context.Background()))
 
 	// Set up a new engine using the same storage layer
 	world2, err := NewWorld(WithPort(getOpenPort(t)))
@@ -392,7 +394,7 @@ func TestCanIdentifyAndFixSystemError(t *testing.T) {
 	assert.NilError(t, err)
 	assert.Equal(t, 4, p1.Power)
 
-	assert.NilError(t, world2.Shutdown())
+	assert.NilError(t, world2.Shutdown(context.Background()))
 }
 
 type Foo struct{}
@@ -499,7 +501,7 @@ func TestSystemsPanicOnRedisError(t *testing.T) {
 			}()
 			<-world.worldStage.NotifyOnStage(worldstage.Running)
 			defer func() {
-				assert.NilError(t, world.Shutdown())
+				assert.NilError(t, world.Shutdown(context.Background()))
 			}()
 
 			// The first tick sets up the entity
@@ -553,6 +555,103 @@ func getMessage[In any, Out any](wCtx engine.Context) (*message.MessageType[In,
 	return res, nil
 }
 
+func TestShutdownExecutesQueuedTransactions(t *testing.T) {
+	testCases := []struct {
+		name         string
+		cancelled    bool
+		wantExecuted int
+	}{
+		{name: "queued transactions are executed in a final tick", cancelled: false, wantExecuted: 1},
+		{name: "the final tick is skipped when the context is done", cancelled: true, wantExecuted: 0},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rs := miniredis.RunT(t)
+			t.Setenv("REDIS_ADDRESS", rs.Addr())
+
+			type LastWishMsg struct{ Wish string }
+			world, err := NewWorld(WithTickChannel(make(chan time.Time)), WithPort(getOpenPort(t)))
+			assert.NilError(t, err)
+			assert.NilError(t, RegisterMessage[LastWishMsg, LastWishMsg](world, "last-wish"))
+			executed := 0
+			err = RegisterSystems(world, func(wCtx engine.Context) error {
+				return EachMessage[LastWishMsg, LastWishMsg](wCtx,
+					func(tx message.TxData[LastWishMsg]) (LastWishMsg, error) {
+						executed++
+						return tx.Msg, nil
+					})
+			})
+			assert.NilError(t, err)
+			go func() {
+				assert.NilError(t, world.StartGame())
+			}()
+			<-world.worldStage.NotifyOnStage(worldstage.Running)
+
+			// The tick channel never fires, so the transaction is still queued when the world is shut down.
+			msg, ok := world.GetMessageByFullName("game.last-wish")
+			assert.True(t, ok)
+			world.AddTransaction(msg.ID(), LastWishMsg{Wish: "one more tick"}, &sign.Transaction{PersonaTag: "foo"})
+
+			ctx, cancel := context.WithCancel(context.Background())
+			if tc.cancelled {
+				cancel()
+			} else {
+				defer cancel()
+			}
+			err = world.Shutdown(ctx)
+			if !tc.cancelled {
+				assert.NilError(t, err)
+			}
+			<-world.worldStage.NotifyOnStage(worldstage.ShutDown)
+			assert.Equal(t, tc.wantExecuted, executed)
+		})
+	}
+}
+
+func TestShutdownAbortsTheInFlightTickOnTimeout(t *testing.T) {
+	rs := miniredis.RunT(t)
+	t.Setenv("REDIS_ADDRESS", rs.Addr())
+
+	tickCh := make(chan time.Time)
+	world, err := NewWorld(WithTickChannel(tickCh), WithPort(getOpenPort(t)))
+	assert.NilError(t, err)
+	var block atomic.Bool
+	ticking := make(chan struct{})
+	release := make(chan struct{})
+	err = RegisterSystems(world, func(engine.Context) error {
+		if block.Load() {
+			close(ticking)
+			<-release
+		}
+		return nil
+	})
+	assert.NilError(t, err)
+	go func() {
+		assert.NilError(t, world.StartGame())
+	}()
+	<-world.worldStage.NotifyOnStage(worldstage.Running)
+	block.Store(true)
+	tickCh <- time.Now()
+	<-ticking
+	tick := world.CurrentTick()
+
+	// The system is stuck until the tick is aborted, after which the tick fails to be committed
+	go func() {
+		<-world.tickCtx.Done()
+		close(release)
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err = world.Shutdown(ctx)
+	assert.IsError(t, err)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+
+	<-world.worldStage.NotifyOnStage(worldstage.ShutDown)
+	assert.Equal(t, tick, world.CurrentTick())
+	// The storage is closed even though the shutdown timed out
+	assert.True(t, errors.Is(world.redisStorage.Client.Ping(context.Background()).Err(), redis.ErrClosed))
+}
+
 func getOpenPort(t testing.TB) string {
 	l, err := net.Listen("tcp", "127.0.0.1:0")
 	defer func() {
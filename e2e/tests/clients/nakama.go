@@ -41,6 +41,7 @@ type Event struct {
 
 type Receipt struct {
 	TxHash string         `json:"txHash"`
+	Tick   uint64         `json:"tick"`
 	Result map[string]any `json:"result"`
 	Errors []string       `json:"errors"`
 }
@@ -21,6 +21,7 @@ const _ = grpc.SupportPackageIsVersion7
 const (
 	QueryService_Namespaces_FullMethodName = "/namespace.v1.QueryService/Namespaces"
 	QueryService_Address_FullMethodName    = "/namespace.v1.QueryService/Address"
+	QueryService_Params_FullMethodName     = "/namespace.v1.QueryService/Params"
 )
 
 // QueryServiceClient is the client API for QueryService service.
@@ -31,6 +32,8 @@ type QueryServiceClient interface {
 	Namespaces(ctx context.Context, in *NamespacesRequest, opts ...grpc.CallOption) (*NamespacesResponse, error)
 	// Address returns the address for a given namespace.
 	Address(ctx context.Context, in *AddressRequest, opts ...grpc.CallOption) (*AddressResponse, error)
+	// Params returns the parameters of the router.
+	Params(ctx context.Context, in *ParamsRequest, opts ...grpc.CallOption) (*ParamsResponse, error)
 }
 
 type queryServiceClient struct {
@@ -59,6 +62,15 @@ func (c *queryServiceClient) Address(ctx context.Context, in *AddressRequest, op
 	return out, nil
 }
 
+func (c *queryServiceClient) Params(ctx context.Context, in *ParamsRequest, opts ...grpc.CallOption) (*ParamsResponse, error) {
+	out := new(ParamsResponse)
+	err := c.cc.Invoke(ctx, QueryService_Params_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // QueryServiceServer is the server API for QueryService service.
 // All implementations must embed UnimplementedQueryServiceServer
 // for forward compatibility
@@ -67,6 +79,8 @@ type QueryServiceServer interface {
 	Namespaces(context.Context, *NamespacesRequest) (*NamespacesResponse, error)
 	// Address returns the address for a given namespace.
 	Address(context.Context, *AddressRequest) (*AddressResponse, error)
+	// Params returns the parameters of the router.
+	Params(context.Context, *ParamsRequest) (*ParamsResponse, error)
 	mustEmbedUnimplementedQueryServiceServer()
 }
 
@@ -80,6 +94,9 @@ func (UnimplementedQueryServiceServer) Namespaces(context.Context, *NamespacesRe
 func (UnimplementedQueryServiceServer) Address(context.Context, *AddressRequest) (*AddressResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Address not implemented")
 }
+func (UnimplementedQueryServiceServer) Params(context.Context, *ParamsRequest) (*ParamsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Params not implemented")
+}
 func (UnimplementedQueryServiceServer) mustEmbedUnimplementedQueryServiceServer() {}
 
 // UnsafeQueryServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -129,6 +146,24 @@ func _QueryService_Address_Handler(srv interface{}, ctx context.Context, dec fun
 	return interceptor(ctx, in, info, handler)
 }
 
+func _QueryService_Params_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ParamsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServiceServer).Params(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: QueryService_Params_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServiceServer).Params(ctx, req.(*ParamsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // QueryService_ServiceDesc is the grpc.ServiceDesc for QueryService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -144,6 +179,10 @@ var QueryService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Address",
 			Handler:    _QueryService_Address_Handler,
 		},
+		{
+			MethodName: "Params",
+			Handler:    _QueryService_Params_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "namespace/v1/query.proto",
@@ -19,7 +19,10 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	Query_Transactions_FullMethodName = "/shard.v1.Query/Transactions"
+	Query_Transactions_FullMethodName   = "/shard.v1.Query/Transactions"
+	Query_StateRoots_FullMethodName     = "/shard.v1.Query/StateRoots"
+	Query_StateRoot_FullMethodName      = "/shard.v1.Query/StateRoot"
+	Query_RoutedMessages_FullMethodName = "/shard.v1.Query/RoutedMessages"
 )
 
 // QueryClient is the client API for Query service.
@@ -27,6 +30,13 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type QueryClient interface {
 	Transactions(ctx context.Context, in *QueryTransactionsRequest, opts ...grpc.CallOption) (*QueryTransactionsResponse, error)
+	// StateRoots returns the state roots submitted by a game shard, sorted by tick.
+	StateRoots(ctx context.Context, in *QueryStateRootsRequest, opts ...grpc.CallOption) (*QueryStateRootsResponse, error)
+	// StateRoot returns the state root of the range of ticks that contains the given tick.
+	StateRoot(ctx context.Context, in *QueryStateRootRequest, opts ...grpc.CallOption) (*QueryStateRootResponse, error)
+	// RoutedMessages returns the messages that the router sent to the game shard of a namespace, sorted by the height of
+	// the block their result was stored in. Messages are kept for 100000 blocks.
+	RoutedMessages(ctx context.Context, in *QueryRoutedMessagesRequest, opts ...grpc.CallOption) (*QueryRoutedMessagesResponse, error)
 }
 
 type queryClient struct {
@@ -46,11 +56,45 @@ func (c *queryClient) Transactions(ctx context.Context, in *QueryTransactionsReq
 	return out, nil
 }
 
+func (c *queryClient) StateRoots(ctx context.Context, in *QueryStateRootsRequest, opts ...grpc.CallOption) (*QueryStateRootsResponse, error) {
+	out := new(QueryStateRootsResponse)
+	err := c.cc.Invoke(ctx, Query_StateRoots_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *queryClient) StateRoot(ctx context.Context, in *QueryStateRootRequest, opts ...grpc.CallOption) (*QueryStateRootResponse, error) {
+	out := new(QueryStateRootResponse)
+	err := c.cc.Invoke(ctx, Query_StateRoot_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *queryClient) RoutedMessages(ctx context.Context, in *QueryRoutedMessagesRequest, opts ...grpc.CallOption) (*QueryRoutedMessagesResponse, error) {
+	out := new(QueryRoutedMessagesResponse)
+	err := c.cc.Invoke(ctx, Query_RoutedMessages_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // QueryServer is the server API for Query service.
 // All implementations must embed UnimplementedQueryServer
 // for forward compatibility
 type QueryServer interface {
 	Transactions(context.Context, *QueryTransactionsRequest) (*QueryTransactionsResponse, error)
+	// StateRoots returns the state roots submitted by a game shard, sorted by tick.
+	StateRoots(context.Context, *QueryStateRootsRequest) (*QueryStateRootsResponse, error)
+	// StateRoot returns the state root of the range of ticks that contains the given tick.
+	StateRoot(context.Context, *QueryStateRootRequest) (*QueryStateRootResponse, error)
+	// RoutedMessages returns the messages that the router sent to the game shard of a namespace, sorted by the height of
+	// the block their result was stored in. Messages are kept for 100000 blocks.
+	RoutedMessages(context.Context, *QueryRoutedMessagesRequest) (*QueryRoutedMessagesResponse, error)
 	mustEmbedUnimplementedQueryServer()
 }
 
@@ -61,6 +105,15 @@ type UnimplementedQueryServer struct {
 func (UnimplementedQueryServer) Transactions(context.Context, *QueryTransactionsRequest) (*QueryTransactionsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Transactions not implemented")
 }
+func (UnimplementedQueryServer) StateRoots(context.Context, *QueryStateRootsRequest) (*QueryStateRootsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StateRoots not implemented")
+}
+func (UnimplementedQueryServer) StateRoot(context.Context, *QueryStateRootRequest) (*QueryStateRootResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StateRoot not implemented")
+}
+func (UnimplementedQueryServer) RoutedMessages(context.Context, *QueryRoutedMessagesRequest) (*QueryRoutedMessagesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RoutedMessages not implemented")
+}
 func (UnimplementedQueryServer) mustEmbedUnimplementedQueryServer() {}
 
 // UnsafeQueryServer may be embedded to opt out of forward compatibility for this service.
@@ -92,6 +145,60 @@ func _Query_Transactions_Handler(srv interface{}, ctx context.Context, dec func(
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Query_StateRoots_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryStateRootsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServer).StateRoots(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Query_StateRoots_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServer).StateRoots(ctx, req.(*QueryStateRootsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Query_StateRoot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryStateRootRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServer).StateRoot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Query_StateRoot_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServer).StateRoot(ctx, req.(*QueryStateRootRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Query_RoutedMessages_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryRoutedMessagesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServer).RoutedMessages(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Query_RoutedMessages_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServer).RoutedMessages(ctx, req.(*QueryRoutedMessagesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Query_ServiceDesc is the grpc.ServiceDesc for Query service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -103,6 +210,18 @@ var Query_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Transactions",
 			Handler:    _Query_Transactions_Handler,
 		},
+		{
+			MethodName: "StateRoots",
+			Handler:    _Query_StateRoots_Handler,
+		},
+		{
+			MethodName: "StateRoot",
+			Handler:    _Query_StateRoot_Handler,
+		},
+		{
+			MethodName: "RoutedMessages",
+			Handler:    _Query_RoutedMessages_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "shard/v1/query.proto",
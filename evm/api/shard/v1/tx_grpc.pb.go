@@ -19,7 +19,8 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	Msg_SubmitShardTx_FullMethodName = "/shard.v1.Msg/SubmitShardTx"
+	Msg_SubmitShardTx_FullMethodName   = "/shard.v1.Msg/SubmitShardTx"
+	Msg_SubmitStateRoot_FullMethodName = "/shard.v1.Msg/SubmitStateRoot"
 )
 
 // MsgClient is the client API for Msg service.
@@ -27,6 +28,7 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type MsgClient interface {
 	SubmitShardTx(ctx context.Context, in *SubmitShardTxRequest, opts ...grpc.CallOption) (*SubmitShardTxResponse, error)
+	SubmitStateRoot(ctx context.Context, in *SubmitStateRootRequest, opts ...grpc.CallOption) (*SubmitStateRootResponse, error)
 }
 
 type msgClient struct {
@@ -46,11 +48,21 @@ func (c *msgClient) SubmitShardTx(ctx context.Context, in *SubmitShardTxRequest,
 	return out, nil
 }
 
+func (c *msgClient) SubmitStateRoot(ctx context.Context, in *SubmitStateRootRequest, opts ...grpc.CallOption) (*SubmitStateRootResponse, error) {
+	out := new(SubmitStateRootResponse)
+	err := c.cc.Invoke(ctx, Msg_SubmitStateRoot_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // MsgServer is the server API for Msg service.
 // All implementations must embed UnimplementedMsgServer
 // for forward compatibility
 type MsgServer interface {
 	SubmitShardTx(context.Context, *SubmitShardTxRequest) (*SubmitShardTxResponse, error)
+	SubmitStateRoot(context.Context, *SubmitStateRootRequest) (*SubmitStateRootResponse, error)
 	mustEmbedUnimplementedMsgServer()
 }
 
@@ -61,6 +73,9 @@ type UnimplementedMsgServer struct {
 func (UnimplementedMsgServer) SubmitShardTx(context.Context, *SubmitShardTxRequest) (*SubmitShardTxResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SubmitShardTx not implemented")
 }
+func (UnimplementedMsgServer) SubmitStateRoot(context.Context, *SubmitStateRootRequest) (*SubmitStateRootResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SubmitStateRoot not implemented")
+}
 func (UnimplementedMsgServer) mustEmbedUnimplementedMsgServer() {}
 
 // UnsafeMsgServer may be embedded to opt out of forward compatibility for this service.
@@ -92,6 +107,24 @@ func _Msg_SubmitShardTx_Handler(srv interface{}, ctx context.Context, dec func(i
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Msg_SubmitStateRoot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SubmitStateRootRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MsgServer).SubmitStateRoot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Msg_SubmitStateRoot_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MsgServer).SubmitStateRoot(ctx, req.(*SubmitStateRootRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Msg_ServiceDesc is the grpc.ServiceDesc for Msg service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -103,6 +136,10 @@ var Msg_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "SubmitShardTx",
 			Handler:    _Msg_SubmitShardTx_Handler,
 		},
+		{
+			MethodName: "SubmitStateRoot",
+			Handler:    _Msg_SubmitStateRoot_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "shard/v1/tx.proto",
@@ -53,8 +53,10 @@ import (
 	mintkeeper "github.com/cosmos/cosmos-sdk/x/mint/keeper"
 	slashingkeeper "github.com/cosmos/cosmos-sdk/x/slashing/keeper"
 	stakingkeeper "github.com/cosmos/cosmos-sdk/x/staking/keeper"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/rotisserie/eris"
 	zerolog "github.com/rs/zerolog/log"
+	"google.golang.org/protobuf/proto"
 	evmv1alpha1 "pkg.berachain.dev/polaris/cosmos/api/polaris/evm/v1alpha1"
 	evmconfig "pkg.berachain.dev/polaris/cosmos/config"
 	ethcryptocodec "pkg.berachain.dev/polaris/cosmos/crypto/codec"
@@ -67,6 +69,8 @@ import (
 	"pkg.world.dev/world-engine/evm/sequencer"
 	namespacekeeper "pkg.world.dev/world-engine/evm/x/namespace/keeper"
 	shardkeeper "pkg.world.dev/world-engine/evm/x/shard/keeper"
+	shardtypes "pkg.world.dev/world-engine/evm/x/shard/types"
+	routerv1 "pkg.world.dev/world-engine/rift/router/v1"
 )
 
 var (
@@ -110,6 +114,10 @@ type App struct {
 	// plugins
 	Router         router.Router
 	ShardSequencer *sequencer.Sequencer
+
+	// registeredNamespaces are the namespaces that were registered in the previous block. Their undelivered messages
+	// are redriven once the new address of their game shard is committed.
+	registeredNamespaces []string
 }
 
 //nolint:gochecknoinits // from sdk.
@@ -216,9 +224,10 @@ func NewApp(
 }
 
 func (app *App) preBlocker(ctx sdk.Context, _ *types.RequestFinalizeBlock) (*sdk.ResponsePreBlock, error) {
-	txs, inits := app.ShardSequencer.FlushMessages()
+	txs, inits, roots := app.ShardSequencer.FlushMessages()
 
 	// first register all the game shard requests
+	registered := make([]string, 0, len(inits))
 	for _, initMsg := range inits {
 		zerolog.Debug().Msgf("registering %q to %q", initMsg.Namespace.ShardName, initMsg.Namespace.ShardAddress)
 		handler := app.MsgServiceRouter().Handler(initMsg)
@@ -226,6 +235,19 @@ func (app *App) preBlocker(ctx sdk.Context, _ *types.RequestFinalizeBlock) (*sdk
 		if err != nil {
 			return nil, eris.Wrapf(err, "failed to register namespace %q", initMsg.Namespace.ShardName)
 		}
+		registered = append(registered, initMsg.Namespace.ShardName)
+	}
+
+	redrive := app.registeredNamespaces
+	app.registeredNamespaces = registered
+	if err := app.syncUndeliveredMessages(ctx, redrive); err != nil {
+		return nil, err
+	}
+	if err := app.syncRoutedMessages(ctx); err != nil {
+		return nil, err
+	}
+	if err := app.syncOutboxMessages(ctx); err != nil {
+		return nil, err
 	}
 
 	// then sequence the game shard txs
@@ -243,9 +265,92 @@ func (app *App) preBlocker(ctx sdk.Context, _ *types.RequestFinalizeBlock) (*sdk
 		}
 		app.Logger().Debug("successfully sequenced %d game shard txs", numTxs)
 	}
+
+	// finally store the state roots. a game shard that submits an invalid state root only loses that state root.
+	for _, root := range roots {
+		handler := app.MsgServiceRouter().Handler(root)
+		if _, err := handler(ctx, root); err != nil {
+			zerolog.Error().Err(err).Msgf("failed to store state root of %q", root.Namespace)
+		}
+	}
 	return resPreBlock, nil
 }
 
+// syncUndeliveredMessages stores the messages that the router could not deliver to their game shards on chain, and
+// redrives the stored messages of the given namespaces, whose game shards have registered their address again.
+func (app *App) syncUndeliveredMessages(ctx sdk.Context, redrive []string) error {
+	for _, msg := range app.Router.FlushUndelivered() {
+		bz, err := proto.Marshal(msg.SendMessageRequest)
+		if err != nil {
+			return eris.Wrapf(err, "failed to store undelivered message of EVM tx %q", msg.GetEvmTxHash())
+		}
+		app.ShardKeeper.SaveUndeliveredMessage(ctx, msg.Namespace, msg.GetEvmTxHash(), bz)
+	}
+	var msgs []router.UndeliveredMessage
+	for _, ns := range redrive {
+		for _, bz := range app.ShardKeeper.TakeUndeliveredMessages(ctx, ns) {
+			req := new(routerv1.SendMessageRequest)
+			if err := proto.Unmarshal(bz, req); err != nil {
+				return eris.Wrapf(err, "failed to read undelivered message of namespace %q", ns)
+			}
+			msgs = append(msgs, router.UndeliveredMessage{Namespace: ns, SendMessageRequest: req})
+		}
+	}
+	if len(msgs) > 0 {
+		app.Logger().Info("redriving undelivered messages", "num_of_msgs", len(msgs))
+		app.Router.Redrive(msgs)
+	}
+	return nil
+}
+
+// syncRoutedMessages stores the messages that the router got a result for on chain, so that they can be looked up with
+// the RoutedMessages query of the x/shard module.
+func (app *App) syncRoutedMessages(ctx sdk.Context) error {
+	for _, msg := range app.Router.FlushRouted() {
+		routed := &shardtypes.RoutedMessage{
+			EvmTxHash:   msg.GetEvmTxHash(),
+			Sender:      msg.GetSender(),
+			PersonaTag:  msg.GetPersonaTag(),
+			MessageId:   msg.GetMessageId(),
+			Message:     msg.GetMessage(),
+			Code:        msg.Result.GetCode(),
+			Errs:        msg.Result.GetErrs(),
+			Result:      msg.Result.GetResult(),
+			BlockHeight: ctx.BlockHeight(),
+		}
+		if err := app.ShardKeeper.SaveRoutedMessage(ctx, msg.Namespace, routed); err != nil {
+			return eris.Wrapf(err, "failed to store routed message of EVM tx %q", msg.GetEvmTxHash())
+		}
+	}
+	return nil
+}
+
+// syncOutboxMessages stores the messages that game shards delivered to the sequencer on chain, where contracts consume
+// them through the router precompile. The sequencer only acknowledges messages once they are stored in a committed
+// block, so game shards deliver them again until then, and the keeper drops the copies.
+func (app *App) syncOutboxMessages(ctx sdk.Context) error {
+	for _, req := range app.ShardSequencer.FlushOutbox() {
+		ns := req.GetNamespace()
+		for _, msg := range req.GetMessages() {
+			if !common.IsHexAddress(msg.GetContract()) {
+				// acknowledge the message anyway. redelivering it won't make the address valid.
+				zerolog.Warn().Msgf("dropping outbox message %d from %q to invalid contract %q",
+					msg.GetId(), ns, msg.GetContract())
+				if msg.GetId() > app.ShardKeeper.OutboxAckedID(ctx, ns) {
+					app.ShardKeeper.AckOutboxMessage(ctx, ns, msg.GetId())
+				}
+				continue
+			}
+			bz, err := proto.Marshal(msg)
+			if err != nil {
+				return eris.Wrapf(err, "failed to store outbox message %d of namespace %q", msg.GetId(), ns)
+			}
+			app.ShardKeeper.SaveOutboxMessage(ctx, ns, msg.GetId(), common.HexToAddress(msg.GetContract()).Bytes(), bz)
+		}
+	}
+	return nil
+}
+
 // Name returns the name of the App.
 func (app *App) Name() string { return app.BaseApp.Name() }
 
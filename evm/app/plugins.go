@@ -1,10 +1,16 @@
 package app
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"cosmossdk.io/log"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/ethereum/go-ethereum/common"
 
 	"pkg.world.dev/world-engine/evm/router"
 	"pkg.world.dev/world-engine/evm/sequencer"
@@ -24,8 +30,95 @@ func (app *App) setPlugins(logger log.Logger) {
 		sequencerOpts = append(sequencerOpts, sequencer.WithRouterKey(routerKey))
 		routerOpts = append(routerOpts, router.WithRouterKey(routerKey))
 	}
+	if attestationKey := os.Getenv("BASE_SHARD_ROUTER_ATTESTATION_KEY"); attestationKey == "" {
+		app.Logger().Debug("WARNING: messages to game shards are not attested. " +
+			"No BASE_SHARD_ROUTER_ATTESTATION_KEY provided")
+	} else {
+		key, err := credentials.ParseAttestationKey(attestationKey)
+		if err != nil {
+			panic(fmt.Errorf("invalid BASE_SHARD_ROUTER_ATTESTATION_KEY: %w", err))
+		}
+		routerOpts = append(routerOpts, router.WithAttestationKey(key))
+	}
+	certPath, caPath := os.Getenv("ROUTER_CLIENT_CERT_PATH"), os.Getenv("ROUTER_CA_PATH")
+	if certPath != "" || caPath != "" {
+		tlsConfig, err := credentials.NewClientTLSConfig(credentials.TLSFiles{
+			CertPath: certPath,
+			KeyPath:  os.Getenv("ROUTER_CLIENT_KEY_PATH"),
+			CAPath:   caPath,
+		})
+		if err != nil {
+			panic(fmt.Errorf("invalid router TLS config: %w", err))
+		}
+		routerOpts = append(routerOpts, router.WithTLSConfig(tlsConfig))
+	}
+	routerOpts = append(routerOpts, deliveryOptionsFromEnv()...)
+	routerOpts = append(routerOpts, router.WithMessageFeeSource(app.BankKeeper, app.messageFees))
+	routerOpts = append(routerOpts, router.WithOutboxStore(app.ShardKeeper))
+	app.Router = router.NewRouter(logger, app.CreateQueryContext, app.NamespaceKeeper.Address, routerOpts...)
+
 	app.ShardSequencer = sequencer.New(app.ShardKeeper, app.CreateQueryContext, sequencerOpts...)
 	app.ShardSequencer.Serve()
+}
 
-	app.Router = router.NewRouter(logger, app.CreateQueryContext, app.NamespaceKeeper.Address, routerOpts...)
+// deliveryOptionsFromEnv reads the retry policy and the delivery timeouts of the router from the environment:
+//
+//	ROUTER_MAX_DELIVERY_ATTEMPTS=5
+//	ROUTER_DELIVERY_TIMEOUT=30s
+//	ROUTER_NAMESPACE_DELIVERY_TIMEOUTS=darkforest-west1=1m,other=10s
+func deliveryOptionsFromEnv() []router.Option {
+	var opts []router.Option
+	if v := os.Getenv("ROUTER_MAX_DELIVERY_ATTEMPTS"); v != "" {
+		attempts, err := strconv.Atoi(v)
+		if err != nil || attempts < 1 {
+			panic(fmt.Errorf("invalid ROUTER_MAX_DELIVERY_ATTEMPTS %q: must be a positive integer", v))
+		}
+		policy := router.DefaultRetryPolicy()
+		policy.MaxAttempts = attempts
+		opts = append(opts, router.WithRetryPolicy(policy))
+	}
+	if v := os.Getenv("ROUTER_DELIVERY_TIMEOUT"); v != "" {
+		opts = append(opts, router.WithDeliveryTimeout(mustParseTimeout("ROUTER_DELIVERY_TIMEOUT", v)))
+	}
+	if v := os.Getenv("ROUTER_NAMESPACE_DELIVERY_TIMEOUTS"); v != "" {
+		for _, entry := range strings.Split(v, ",") {
+			ns, timeout, ok := strings.Cut(entry, "=")
+			if !ok {
+				panic(fmt.Errorf("invalid ROUTER_NAMESPACE_DELIVERY_TIMEOUTS entry %q: expected namespace=timeout", entry))
+			}
+			opts = append(opts, router.WithNamespaceDeliveryTimeout(strings.TrimSpace(ns),
+				mustParseTimeout("ROUTER_NAMESPACE_DELIVERY_TIMEOUTS", timeout)))
+		}
+	}
+	return opts
+}
+
+// messageFees returns the fees of the messages sent to game shards, from the params of the namespace module. The
+// params are changed with governance proposals.
+func (app *App) messageFees(ctx context.Context) (router.FeeTable, error) {
+	params, err := app.NamespaceKeeper.GetParams(ctx)
+	if err != nil {
+		return router.FeeTable{}, err
+	}
+	table := router.FeeTable{
+		DefaultFee:    params.MessageFee,
+		NamespaceFees: make(map[string]sdk.Coins, len(params.NamespaceMessageFees)),
+		GasPerMessage: params.GasPerMessage,
+		GasPerByte:    params.GasPerByte,
+	}
+	for _, nsFee := range params.NamespaceMessageFees {
+		table.NamespaceFees[nsFee.Namespace] = nsFee.Fee
+	}
+	for _, addr := range params.FeeExemptSenders {
+		table.Exempt = append(table.Exempt, common.HexToAddress(addr))
+	}
+	return table, nil
+}
+
+func mustParseTimeout(name, v string) time.Duration {
+	d, err := time.ParseDuration(strings.TrimSpace(v))
+	if err != nil || d <= 0 {
+		panic(fmt.Errorf("invalid %s %q: must be a positive duration", name, v))
+	}
+	return d
 }
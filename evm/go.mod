@@ -2,6 +2,9 @@ module pkg.world.dev/world-engine/evm
 
 go 1.22.1
 
+// local modules that are changed together with this one
+replace pkg.world.dev/world-engine/rift => ../rift
+
 // external, necessary replacements
 replace (
 	github.com/cockroachdb/pebble => github.com/cockroachdb/pebble v0.0.0-20230928194634-aa077af62593
@@ -31,7 +31,7 @@ var (
 
 // RouterMetaData contains all meta data concerning the Router contract.
 var RouterMetaData = &bind.MetaData{
-	ABI: "[{\"type\":\"function\",\"name\":\"messageResult\",\"inputs\":[{\"name\":\"txHash\",\"type\":\"string\",\"internalType\":\"string\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bytes\",\"internalType\":\"bytes\"},{\"name\":\"\",\"type\":\"string\",\"internalType\":\"string\"},{\"name\":\"\",\"type\":\"uint32\",\"internalType\":\"uint32\"}],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"query\",\"inputs\":[{\"name\":\"request\",\"type\":\"bytes\",\"internalType\":\"bytes\"},{\"name\":\"resource\",\"type\":\"string\",\"internalType\":\"string\"},{\"name\":\"namespace\",\"type\":\"string\",\"internalType\":\"string\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bytes\",\"internalType\":\"bytes\"}],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"sendMessage\",\"inputs\":[{\"name\":\"personaTag\",\"type\":\"string\",\"internalType\":\"string\"},{\"name\":\"message\",\"type\":\"bytes\",\"internalType\":\"bytes\"},{\"name\":\"messageID\",\"type\":\"string\",\"internalType\":\"string\"},{\"name\":\"namespace\",\"type\":\"string\",\"internalType\":\"string\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bool\",\"internalType\":\"bool\"}],\"stateMutability\":\"nonpayable\"}]",
+	ABI: "[{\"type\":\"function\",\"name\":\"consumeOutbox\",\"inputs\":[{\"name\":\"limit\",\"type\":\"uint32\",\"internalType\":\"uint32\"}],\"outputs\":[{\"name\":\"namespaces\",\"type\":\"string[]\",\"internalType\":\"string[]\"},{\"name\":\"ids\",\"type\":\"uint64[]\",\"internalType\":\"uint64[]\"},{\"name\":\"ticks\",\"type\":\"uint64[]\",\"internalType\":\"uint64[]\"},{\"name\":\"payloads\",\"type\":\"bytes[]\",\"internalType\":\"bytes[]\"}],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"messageResult\",\"inputs\":[{\"name\":\"txHash\",\"type\":\"string\",\"internalType\":\"string\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bytes\",\"internalType\":\"bytes\"},{\"name\":\"\",\"type\":\"string\",\"internalType\":\"string\"},{\"name\":\"\",\"type\":\"uint32\",\"internalType\":\"uint32\"}],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"query\",\"inputs\":[{\"name\":\"request\",\"type\":\"bytes\",\"internalType\":\"bytes\"},{\"name\":\"resource\",\"type\":\"string\",\"internalType\":\"string\"},{\"name\":\"namespace\",\"type\":\"string\",\"internalType\":\"string\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bytes\",\"internalType\":\"bytes\"}],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"sendMessage\",\"inputs\":[{\"name\":\"personaTag\",\"type\":\"string\",\"internalType\":\"string\"},{\"name\":\"message\",\"type\":\"bytes\",\"internalType\":\"bytes\"},{\"name\":\"messageID\",\"type\":\"string\",\"internalType\":\"string\"},{\"name\":\"namespace\",\"type\":\"string\",\"internalType\":\"string\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bool\",\"internalType\":\"bool\"}],\"stateMutability\":\"nonpayable\"}]",
 }
 
 // RouterABI is the input ABI used to generate the binding from.
@@ -180,6 +180,27 @@ func (_Router *RouterTransactorRaw) Transact(opts *bind.TransactOpts, method str
 	return _Router.Contract.contract.Transact(opts, method, params...)
 }
 
+// ConsumeOutbox is a paid mutator transaction binding the contract method 0xa392b761.
+//
+// Solidity: function consumeOutbox(uint32 limit) returns(string[] namespaces, uint64[] ids, uint64[] ticks, bytes[] payloads)
+func (_Router *RouterTransactor) ConsumeOutbox(opts *bind.TransactOpts, limit uint32) (*types.Transaction, error) {
+	return _Router.contract.Transact(opts, "consumeOutbox", limit)
+}
+
+// ConsumeOutbox is a paid mutator transaction binding the contract method 0xa392b761.
+//
+// Solidity: function consumeOutbox(uint32 limit) returns(string[] namespaces, uint64[] ids, uint64[] ticks, bytes[] payloads)
+func (_Router *RouterSession) ConsumeOutbox(limit uint32) (*types.Transaction, error) {
+	return _Router.Contract.ConsumeOutbox(&_Router.TransactOpts, limit)
+}
+
+// ConsumeOutbox is a paid mutator transaction binding the contract method 0xa392b761.
+//
+// Solidity: function consumeOutbox(uint32 limit) returns(string[] namespaces, uint64[] ids, uint64[] ticks, bytes[] payloads)
+func (_Router *RouterTransactorSession) ConsumeOutbox(limit uint32) (*types.Transaction, error) {
+	return _Router.Contract.ConsumeOutbox(&_Router.TransactOpts, limit)
+}
+
 // MessageResult is a paid mutator transaction binding the contract method 0xeb8ba34e.
 //
 // Solidity: function messageResult(string txHash) returns(bytes, string, uint32)
@@ -8,4 +8,10 @@ interface IRouter {
     function query(bytes memory request, string memory resource, string memory namespace)
         external
         returns (bytes memory);
+
+    // consumeOutbox removes and returns up to limit messages that game shard systems emitted to the calling contract,
+    // sorted by namespace and by id. A limit of 0 returns all messages.
+    function consumeOutbox(uint32 limit)
+        external
+        returns (string[] memory namespaces, uint64[] memory ids, uint64[] memory ticks, bytes[] memory payloads);
 }
@@ -71,3 +71,29 @@ func (c *Contract) Query(
 	log.Debug().Msgf("got query request for %s", namespace)
 	return c.rtr.Query(ctx, request, resource, namespace)
 }
+
+// ConsumeOutbox implements the consumeOutbox precompile function in router.sol. The messages are removed from the
+// chain, unless the EVM transaction reverts.
+func (c *Contract) ConsumeOutbox(
+	ctx context.Context,
+	limit uint32,
+) ([]string, []uint64, []uint64, [][]byte, error) {
+	pCtx := vm.UnwrapPolarContext(ctx)
+	msgs, err := c.rtr.ConsumeOutboxMessages(ctx, pCtx.MsgSender(), int(limit))
+	if err != nil {
+		log.Logger.Err(err).Msg("failed to consume outbox messages")
+		return nil, nil, nil, nil, err
+	}
+	namespaces := make([]string, 0, len(msgs))
+	ids := make([]uint64, 0, len(msgs))
+	ticks := make([]uint64, 0, len(msgs))
+	payloads := make([][]byte, 0, len(msgs))
+	for _, msg := range msgs {
+		namespaces = append(namespaces, msg.Namespace)
+		ids = append(ids, msg.GetId())
+		ticks = append(ticks, msg.GetTick())
+		payloads = append(payloads, msg.GetPayload())
+	}
+	log.Logger.Debug().Msgf("consumed %d outbox messages of %s", len(msgs), pCtx.MsgSender().String())
+	return namespaces, ids, ticks, payloads, nil
+}
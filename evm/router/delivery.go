@@ -0,0 +1,157 @@
+package router
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"pkg.world.dev/world-engine/rift/credentials"
+	routerv1 "pkg.world.dev/world-engine/rift/router/v1"
+)
+
+const (
+	defaultMaxAttempts     = 5
+	defaultInitialBackoff  = 100 * time.Millisecond
+	defaultMaxBackoff      = 5 * time.Second
+	defaultBackoffFactor   = 2
+	defaultDeliveryTimeout = 30 * time.Second
+)
+
+// RetryPolicy controls how often, and how fast, the router retries sending a message to a game shard. The backoff
+// between attempts starts at InitialBackoff and is multiplied by Multiplier after every attempt, up to MaxBackoff.
+type RetryPolicy struct {
+	// MaxAttempts is the number of times a message is sent before it is given up on. Values below 1 mean 1.
+	MaxAttempts int
+	// InitialBackoff is the time to wait before the first retry.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the time to wait between two attempts.
+	MaxBackoff time.Duration
+	// Multiplier is the factor by which the backoff grows after every attempt.
+	Multiplier float64
+}
+
+// DefaultRetryPolicy returns the retry policy that is used unless WithRetryPolicy is passed to NewRouter.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    defaultMaxAttempts,
+		InitialBackoff: defaultInitialBackoff,
+		MaxBackoff:     defaultMaxBackoff,
+		Multiplier:     defaultBackoffFactor,
+	}
+}
+
+// backoff returns the time to wait before the given retry, starting at 1.
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	d := p.InitialBackoff
+	for i := 1; i < retry && d < p.MaxBackoff; i++ {
+		d = time.Duration(float64(d) * p.Multiplier)
+	}
+	if p.MaxBackoff > 0 && d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+	return d
+}
+
+// UndeliveredMessage is a message that the router gave up sending to its game shard. Undelivered messages are stored
+// on chain, and are sent again when the game shard registers its address again, e.g. after a restart.
+type UndeliveredMessage struct {
+	// Namespace is the namespace of the game shard that the message is sent to.
+	Namespace string
+	*routerv1.SendMessageRequest
+}
+
+// isRetryable reports if a failed call to a game shard may succeed when it is retried. Errors that the game shard
+// returned for the message itself, such as an invalid signature, fail the same way every time.
+func isRetryable(err error) bool {
+	switch status.Code(err) { //nolint:exhaustive // all other codes are not retryable
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Aborted:
+		return true
+	default:
+		return false
+	}
+}
+
+// deliveryTimeout returns the time that the router spends delivering a message to the given namespace, including
+// retries.
+func (r *routerImpl) deliveryTimeout(namespace string) time.Duration {
+	if d, ok := r.namespaceTimeouts[namespace]; ok {
+		return d
+	}
+	return r.defaultTimeout
+}
+
+// deliver sends the message to the game shard, retrying according to the retry policy until it succeeds, fails with
+// an error that can't be retried, or the delivery timeout of the namespace expires. A game shard that is overloaded
+// answers with a RetryAfter instead of executing the message; such messages are retried, but not before the time that
+// the game shard asked for.
+func (r *routerImpl) deliver(
+	client routerv1.MsgClient,
+	namespace string,
+	msg *routerv1.SendMessageRequest,
+) (*routerv1.SendMessageResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), r.deliveryTimeout(namespace))
+	defer cancel()
+	var res *routerv1.SendMessageResponse
+	var err error
+	for attempt := 1; ; attempt++ {
+		res, err = client.SendMessage(ctx, r.attest(namespace, msg))
+		wait := r.retryPolicy.backoff(attempt)
+		if retryAfter := res.GetRetryAfter(); err == nil && retryAfter != nil {
+			err = status.Error(codes.ResourceExhausted, res.GetErrs())
+			wait = max(wait, time.Duration(retryAfter.GetRetryAfterMs())*time.Millisecond)
+			res = nil
+		}
+		if err == nil || !isRetryable(err) || attempt >= r.retryPolicy.MaxAttempts {
+			return res, err
+		}
+		r.logger.Debug("retrying message to game shard",
+			"namespace", namespace,
+			"evm_tx_hash", msg.GetEvmTxHash(),
+			"attempt", attempt,
+			"error", err,
+		)
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, err
+		}
+	}
+}
+
+// attest returns a copy of the message signed with the attestation key of the router, so that the game shard can
+// verify that the message comes from the base shard. The message is signed again on every attempt, so that retries
+// and redriven messages don't carry expired attestations.
+func (r *routerImpl) attest(namespace string, msg *routerv1.SendMessageRequest) *routerv1.SendMessageRequest {
+	if r.attestationKey == nil {
+		return msg
+	}
+	attested, _ := proto.Clone(msg).(*routerv1.SendMessageRequest)
+	attested.Attestation = credentials.Attest(r.attestationKey, namespace, attested, time.Now())
+	return attested
+}
+
+// undeliveredQueue holds the messages that could not be delivered until they are flushed to the chain.
+type undeliveredQueue struct {
+	mut  sync.Mutex
+	msgs []UndeliveredMessage
+}
+
+func (q *undeliveredQueue) add(namespace string, msg *routerv1.SendMessageRequest) {
+	q.mut.Lock()
+	defer q.mut.Unlock()
+	q.msgs = append(q.msgs, UndeliveredMessage{Namespace: namespace, SendMessageRequest: msg})
+}
+
+func (q *undeliveredQueue) flush() []UndeliveredMessage {
+	q.mut.Lock()
+	defer q.mut.Unlock()
+	msgs := q.msgs
+	q.msgs = nil
+	return msgs
+}
@@ -0,0 +1,202 @@
+package router
+
+import (
+	"context"
+	"crypto/ed25519"
+	"testing"
+	"time"
+
+	"cosmossdk.io/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gotest.tools/v3/assert"
+
+	"pkg.world.dev/world-engine/rift/credentials"
+	routerv1 "pkg.world.dev/world-engine/rift/router/v1"
+)
+
+// flakyClient fails the first calls to SendMessage with the given errors.
+type flakyClient struct {
+	errs  []error
+	calls int
+}
+
+func (c *flakyClient) SendMessage(
+	_ context.Context, in *routerv1.SendMessageRequest, _ ...grpc.CallOption,
+) (*routerv1.SendMessageResponse, error) {
+	c.calls++
+	if c.calls <= len(c.errs) {
+		return nil, c.errs[c.calls-1]
+	}
+	return &routerv1.SendMessageResponse{EvmTxHash: in.GetEvmTxHash()}, nil
+}
+
+func (c *flakyClient) QueryShard(
+	context.Context, *routerv1.QueryShardRequest, ...grpc.CallOption,
+) (*routerv1.QueryShardResponse, error) {
+	return nil, nil
+}
+
+func (c *flakyClient) GetCatalog(
+	context.Context, *routerv1.GetCatalogRequest, ...grpc.CallOption,
+) (*routerv1.GetCatalogResponse, error) {
+	return nil, nil
+}
+
+// overloadedClient answers the first calls to SendMessage with a RetryAfter.
+type overloadedClient struct {
+	flakyClient
+	overloadedCalls int
+	retryAfter      time.Duration
+}
+
+func (c *overloadedClient) SendMessage(
+	ctx context.Context, in *routerv1.SendMessageRequest, opts ...grpc.CallOption,
+) (*routerv1.SendMessageResponse, error) {
+	if c.calls < c.overloadedCalls {
+		c.calls++
+		return &routerv1.SendMessageResponse{
+			EvmTxHash: in.GetEvmTxHash(),
+			Errs:      "game shard is overloaded",
+			RetryAfter: &routerv1.RetryAfter{
+				Reason:       "queue-full",
+				RetryAfterMs: uint64(c.retryAfter.Milliseconds()),
+			},
+		}, nil
+	}
+	return c.flakyClient.SendMessage(ctx, in, opts...)
+}
+
+func newTestRouter(t *testing.T, opts ...Option) *routerImpl {
+	opts = append([]Option{WithRetryPolicy(RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     2 * time.Millisecond,
+		Multiplier:     2,
+	})}, opts...)
+	r, ok := NewRouter(log.NewTestLogger(t), mockQueryCtx, mockGetAddr, opts...).(*routerImpl)
+	assert.Equal(t, ok, true)
+	return r
+}
+
+func TestDeliverRetriesTransientErrors(t *testing.T) {
+	r := newTestRouter(t)
+	unavailable := status.Error(codes.Unavailable, "shard is restarting")
+	msg := &routerv1.SendMessageRequest{EvmTxHash: "0xabc"}
+
+	client := &flakyClient{errs: []error{unavailable, unavailable}}
+	res, err := r.deliver(client, "cardinal", msg)
+	assert.NilError(t, err)
+	assert.Equal(t, res.GetEvmTxHash(), "0xabc")
+	assert.Equal(t, client.calls, 3)
+
+	// The router gives up after MaxAttempts.
+	client = &flakyClient{errs: []error{unavailable, unavailable, unavailable}}
+	_, err = r.deliver(client, "cardinal", msg)
+	assert.Equal(t, status.Code(err), codes.Unavailable)
+	assert.Equal(t, client.calls, 3)
+
+	// Errors about the message itself are not retried.
+	client = &flakyClient{errs: []error{status.Error(codes.InvalidArgument, "bad signature")}}
+	_, err = r.deliver(client, "cardinal", msg)
+	assert.Equal(t, status.Code(err), codes.InvalidArgument)
+	assert.Equal(t, client.calls, 1)
+}
+
+func TestDeliverWaitsForOverloadedGameShards(t *testing.T) {
+	r := newTestRouter(t)
+	msg := &routerv1.SendMessageRequest{EvmTxHash: "0xabc"}
+
+	client := &overloadedClient{overloadedCalls: 2, retryAfter: 20 * time.Millisecond}
+	start := time.Now()
+	res, err := r.deliver(client, "cardinal", msg)
+	assert.NilError(t, err)
+	assert.Equal(t, res.GetEvmTxHash(), "0xabc")
+	assert.Assert(t, res.GetRetryAfter() == nil)
+	assert.Equal(t, client.calls, 3)
+	// The router waited as long as the game shard asked for, rather than its own, shorter, backoff.
+	assert.Assert(t, time.Since(start) >= 40*time.Millisecond)
+
+	// A game shard that stays overloaded fails the delivery, so the message is delivered again later.
+	client = &overloadedClient{overloadedCalls: 3, retryAfter: time.Millisecond}
+	_, err = r.deliver(client, "cardinal", msg)
+	assert.Equal(t, status.Code(err), codes.ResourceExhausted)
+	assert.Equal(t, client.calls, 3)
+}
+
+func TestDeliverStopsAtTheNamespaceTimeout(t *testing.T) {
+	r := newTestRouter(t,
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 100, InitialBackoff: time.Hour, MaxBackoff: time.Hour}),
+		WithNamespaceDeliveryTimeout("slow", 10*time.Millisecond),
+	)
+	assert.Equal(t, r.deliveryTimeout("other"), defaultDeliveryTimeout)
+
+	client := &flakyClient{errs: []error{status.Error(codes.Unavailable, "down")}}
+	start := time.Now()
+	_, err := r.deliver(client, "slow", &routerv1.SendMessageRequest{})
+	assert.Equal(t, status.Code(err), codes.Unavailable)
+	assert.Equal(t, client.calls, 1)
+	assert.Assert(t, time.Since(start) < time.Minute)
+}
+
+// recordingClient keeps the requests sent to the game shard.
+type recordingClient struct {
+	flakyClient
+	reqs []*routerv1.SendMessageRequest
+}
+
+func (c *recordingClient) SendMessage(
+	ctx context.Context, in *routerv1.SendMessageRequest, opts ...grpc.CallOption,
+) (*routerv1.SendMessageResponse, error) {
+	c.reqs = append(c.reqs, in)
+	return c.flakyClient.SendMessage(ctx, in, opts...)
+}
+
+func TestDeliverAttestsEveryAttempt(t *testing.T) {
+	_, key, err := ed25519.GenerateKey(nil)
+	assert.NilError(t, err)
+	r := newTestRouter(t, WithAttestationKey(key))
+	msg := &routerv1.SendMessageRequest{EvmTxHash: "0xabc", Message: []byte("hello")}
+
+	client := &recordingClient{flakyClient: flakyClient{errs: []error{status.Error(codes.Unavailable, "down")}}}
+	_, err = r.deliver(client, "cardinal", msg)
+	assert.NilError(t, err)
+	assert.Equal(t, len(client.reqs), 2)
+	for _, req := range client.reqs {
+		assert.NilError(t, credentials.VerifyAttestation(key.Public().(ed25519.PublicKey), "cardinal", req, time.Now(),
+			credentials.DefaultAttestationMaxAge))
+	}
+	// The message that is kept by the router, e.g. to be stored as undelivered, is not signed.
+	assert.Assert(t, msg.GetAttestation() == nil)
+
+	// Without a key, messages are sent as they are.
+	r = newTestRouter(t)
+	client = &recordingClient{}
+	_, err = r.deliver(client, "cardinal", msg)
+	assert.NilError(t, err)
+	assert.Assert(t, client.reqs[0].GetAttestation() == nil)
+}
+
+func TestUndeliveredMessagesAreFlushedOnce(t *testing.T) {
+	r := newTestRouter(t)
+	r.giveUp("cardinal", &routerv1.SendMessageRequest{EvmTxHash: "0xabc"}, CodeServerError, "down")
+
+	res, ok := r.resultStore.Result("0xabc")
+	assert.Equal(t, ok, true)
+	assert.Equal(t, res.GetCode(), uint32(CodeServerError))
+
+	msgs := r.FlushUndelivered()
+	assert.Equal(t, len(msgs), 1)
+	assert.Equal(t, msgs[0].Namespace, "cardinal")
+	assert.Equal(t, msgs[0].GetEvmTxHash(), "0xabc")
+	assert.Equal(t, len(r.FlushUndelivered()), 0)
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	p := RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second, Multiplier: 2}
+	assert.Equal(t, p.backoff(1), 100*time.Millisecond)
+	assert.Equal(t, p.backoff(2), 200*time.Millisecond)
+	assert.Equal(t, p.backoff(3), 400*time.Millisecond)
+	assert.Equal(t, p.backoff(10), time.Second)
+}
@@ -0,0 +1,101 @@
+package router
+
+import (
+	"context"
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// FeeKeeper moves the fees of routed messages from the contracts that send them to the fee collector. The bank keeper
+// implements it.
+type FeeKeeper interface {
+	SendCoinsFromAccountToModule(ctx context.Context, senderAddr sdk.AccAddress, recipientModule string,
+		amt sdk.Coins) error
+}
+
+// GetFeeTableFn returns the fee table in effect in the given context.
+type GetFeeTableFn func(ctx context.Context) (FeeTable, error)
+
+// FeeTable is the price of sending a message to a game shard. Messages are charged when they are queued, inside the
+// EVM transaction that sends them, so a transaction that can't pay reverts and its message is never sent.
+type FeeTable struct {
+	// DefaultFee is charged for the messages of the namespaces that are not in NamespaceFees.
+	DefaultFee sdk.Coins
+	// NamespaceFees overrides the fee of the messages sent to the game shard of a namespace.
+	NamespaceFees map[string]sdk.Coins
+	// GasPerMessage is the gas consumed by every message, on top of the gas of the precompile call.
+	GasPerMessage uint64
+	// GasPerByte is the gas consumed by every byte of a message.
+	GasPerByte uint64
+	// Exempt are the contracts that send messages without paying fees or gas, e.g. the contracts of the game itself.
+	Exempt []common.Address
+}
+
+// Fee returns the fee of a message sent to the game shard of the given namespace.
+func (t FeeTable) Fee(namespace string) sdk.Coins {
+	if fee, ok := t.NamespaceFees[namespace]; ok {
+		return fee
+	}
+	return t.DefaultFee
+}
+
+// Validate returns an error if a fee of the table is not a valid amount of coins.
+func (t FeeTable) Validate() error {
+	if err := t.DefaultFee.Validate(); err != nil {
+		return fmt.Errorf("invalid default message fee: %w", err)
+	}
+	for ns, fee := range t.NamespaceFees {
+		if err := fee.Validate(); err != nil {
+			return fmt.Errorf("invalid message fee for namespace %q: %w", ns, err)
+		}
+	}
+	return nil
+}
+
+func (t FeeTable) isExempt(sender common.Address) bool {
+	for _, addr := range t.Exempt {
+		if addr == sender {
+			return true
+		}
+	}
+	return false
+}
+
+// chargeMessage consumes the gas of a message and moves its fee from the sender to the fee collector. ctx must be the
+// context of the precompile call, so that the charges are reverted along with the EVM transaction.
+func (r *routerImpl) chargeMessage(ctx context.Context, namespace string, sender common.Address, msg []byte) error {
+	if r.getFees == nil {
+		return nil
+	}
+	fees, err := r.getFees(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get the message fees: %w", err)
+	}
+	if fees.isExempt(sender) {
+		return nil
+	}
+	gas := fees.GasPerMessage + fees.GasPerByte*uint64(len(msg))
+	fee := fees.Fee(namespace)
+	if gas == 0 && fee.IsZero() {
+		return nil
+	}
+	sdkCtx := sdk.UnwrapSDKContext(ctx)
+	if gas > 0 {
+		sdkCtx.GasMeter().ConsumeGas(gas, "routed game shard message")
+	}
+	if fee.IsZero() {
+		return nil
+	}
+	if r.feeKeeper == nil {
+		return fmt.Errorf("cannot charge the message fee for namespace %q: no fee keeper", namespace)
+	}
+	err = r.feeKeeper.SendCoinsFromAccountToModule(sdkCtx, sdk.AccAddress(sender.Bytes()),
+		authtypes.FeeCollectorName, fee)
+	if err != nil {
+		return fmt.Errorf("failed to charge the message fee of %s for namespace %q: %w", fee, namespace, err)
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"cosmossdk.io/log"
+	storetypes "cosmossdk.io/store/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	"github.com/ethereum/go-ethereum/common"
+	"gotest.tools/v3/assert"
+)
+
+type feePayment struct {
+	sender sdk.AccAddress
+	module string
+	amount sdk.Coins
+}
+
+type mockFeeKeeper struct {
+	payments []feePayment
+	err      error
+}
+
+func (k *mockFeeKeeper) SendCoinsFromAccountToModule(
+	_ context.Context, senderAddr sdk.AccAddress, recipientModule string, amt sdk.Coins,
+) error {
+	if k.err != nil {
+		return k.err
+	}
+	k.payments = append(k.payments, feePayment{senderAddr, recipientModule, amt})
+	return nil
+}
+
+func TestSendMessageChargesTheFeeOfItsNamespace(t *testing.T) {
+	keeper := &mockFeeKeeper{}
+	exempt := common.HexToAddress("0x356833c4666fFB6bFccbF8D600fa7282290dE073")
+	r := NewRouter(log.NewTestLogger(t), mockQueryCtx, mockGetAddr, WithMessageFees(keeper, FeeTable{
+		DefaultFee:    sdk.NewCoins(sdk.NewInt64Coin("uworld", 10)),
+		NamespaceFees: map[string]sdk.Coins{"premium": sdk.NewCoins(sdk.NewInt64Coin("uworld", 50))},
+		GasPerMessage: 1000,
+		GasPerByte:    10,
+		Exempt:        []common.Address{exempt},
+	}))
+	gasMeter := storetypes.NewGasMeter(1_000_000)
+	ctx := sdk.Context{}.WithContext(context.Background()).WithGasMeter(gasMeter)
+	sender := common.HexToAddress("0x61d2B2315605660c3855C8BE139B82e0635E13E3")
+
+	assert.NilError(t, r.SendMessage(ctx, "foo", "cardinal", sender.String(), "tx1", []byte("hello")))
+	assert.NilError(t, r.SendMessage(ctx, "foo", "premium", sender.String(), "tx2", []byte("hello")))
+	assert.NilError(t, r.SendMessage(ctx, "foo", "premium", exempt.String(), "tx3", []byte("hello")))
+
+	// The payments are compared field by field, since coin amounts can't be compared with DeepEqual.
+	wantFees := []sdk.Coins{
+		sdk.NewCoins(sdk.NewInt64Coin("uworld", 10)),
+		sdk.NewCoins(sdk.NewInt64Coin("uworld", 50)),
+	}
+	assert.Equal(t, len(keeper.payments), len(wantFees))
+	for i, payment := range keeper.payments {
+		assert.Check(t, payment.sender.Equals(sdk.AccAddress(sender.Bytes())), "payment %d", i)
+		assert.Equal(t, payment.module, authtypes.FeeCollectorName)
+		assert.Check(t, payment.amount.Equal(wantFees[i]), "payment %d: got %s", i, payment.amount)
+	}
+	// The exempt sender doesn't consume gas either.
+	assert.Equal(t, gasMeter.GasConsumed(), storetypes.Gas(2*(1000+10*len("hello"))))
+}
+
+func TestSendMessageIsNotQueuedWhenTheFeeCannotBePaid(t *testing.T) {
+	keeper := &mockFeeKeeper{err: errors.New("insufficient funds")}
+	r := NewRouter(log.NewTestLogger(t), mockQueryCtx, mockGetAddr, WithMessageFees(keeper, FeeTable{
+		DefaultFee: sdk.NewCoins(sdk.NewInt64Coin("uworld", 10)),
+	}))
+	router, ok := r.(*routerImpl)
+	assert.Equal(t, ok, true)
+	ctx := sdk.Context{}.WithContext(context.Background()).WithGasMeter(storetypes.NewInfiniteGasMeter())
+	sender := common.HexToAddress("0x61d2B2315605660c3855C8BE139B82e0635E13E3")
+
+	err := router.SendMessage(ctx, "foo", "cardinal", sender.String(), "tx1", []byte("hello"))
+	assert.ErrorContains(t, err, "insufficient funds")
+	assert.Equal(t, router.queue.IsSet(sender), false)
+}
+
+func TestFeeTableValidate(t *testing.T) {
+	valid := FeeTable{
+		DefaultFee:    sdk.NewCoins(sdk.NewInt64Coin("uworld", 10)),
+		NamespaceFees: map[string]sdk.Coins{"free": nil},
+	}
+	assert.NilError(t, valid.Validate())
+
+	// Coins must be sorted by denomination.
+	unsorted := sdk.Coins{sdk.NewInt64Coin("uworld", 1), sdk.NewInt64Coin("stake", 1)}
+	invalid := FeeTable{NamespaceFees: map[string]sdk.Coins{"bad": unsorted}}
+	assert.ErrorContains(t, invalid.Validate(), `namespace "bad"`)
+}
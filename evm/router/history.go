@@ -0,0 +1,37 @@
+package router
+
+import (
+	"sync"
+
+	routerv1 "pkg.world.dev/world-engine/rift/router/v1"
+)
+
+// RoutedMessage is a message that the router sent to its game shard, along with its result. Routed messages are stored
+// on chain, so that explorers and game dashboards can look up the cross-shard actions of a game.
+type RoutedMessage struct {
+	// Namespace is the namespace of the game shard that the message was sent to.
+	Namespace string
+	*routerv1.SendMessageRequest
+	// Result is the result of the message. Messages that could not be delivered have the router's error as result.
+	Result *routerv1.SendMessageResponse
+}
+
+// routedQueue holds the messages that got a result until they are flushed to the chain.
+type routedQueue struct {
+	mut  sync.Mutex
+	msgs []RoutedMessage
+}
+
+func (q *routedQueue) add(namespace string, msg *routerv1.SendMessageRequest, res *routerv1.SendMessageResponse) {
+	q.mut.Lock()
+	defer q.mut.Unlock()
+	q.msgs = append(q.msgs, RoutedMessage{Namespace: namespace, SendMessageRequest: msg, Result: res})
+}
+
+func (q *routedQueue) flush() []RoutedMessage {
+	q.mut.Lock()
+	defer q.mut.Unlock()
+	msgs := q.msgs
+	q.msgs = nil
+	return msgs
+}
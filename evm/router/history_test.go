@@ -0,0 +1,26 @@
+package router
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	routerv1 "pkg.world.dev/world-engine/rift/router/v1"
+)
+
+func TestRoutedMessagesAreFlushedOnce(t *testing.T) {
+	r := newTestRouter(t)
+	msg := &routerv1.SendMessageRequest{EvmTxHash: "0xabc", Sender: "0xfoo", MessageId: "move"}
+	r.giveUp("cardinal", msg, CodeServerError, "down")
+	// a redriven message gets a second result.
+	r.routed.add("cardinal", msg, &routerv1.SendMessageResponse{EvmTxHash: "0xabc", Result: []byte("ok")})
+
+	msgs := r.FlushRouted()
+	assert.Equal(t, len(msgs), 2)
+	assert.Equal(t, msgs[0].Namespace, "cardinal")
+	assert.Equal(t, msgs[0].GetEvmTxHash(), "0xabc")
+	assert.Equal(t, msgs[0].Result.GetCode(), uint32(CodeServerError))
+	assert.Equal(t, msgs[0].Result.GetErrs(), "down")
+	assert.Equal(t, msgs[1].Result.GetCode(), uint32(0))
+	assert.Equal(t, len(r.FlushRouted()), 0)
+}
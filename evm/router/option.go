@@ -1,5 +1,12 @@
 package router
 
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/tls"
+	"time"
+)
+
 type Option func(r *routerImpl)
 
 // WithRouterKey sets the router routerKey for the game shard <> base shard communications.
@@ -8,3 +15,67 @@ func WithRouterKey(key string) Option {
 		r.routerKey = key
 	}
 }
+
+// WithRetryPolicy sets how the router retries sending messages to game shards. See DefaultRetryPolicy for the default.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(r *routerImpl) {
+		r.retryPolicy = policy
+	}
+}
+
+// WithDeliveryTimeout sets the time that the router spends delivering a message to a game shard, including retries,
+// before the message is stored as undelivered. It defaults to 30 seconds.
+func WithDeliveryTimeout(timeout time.Duration) Option {
+	return func(r *routerImpl) {
+		r.defaultTimeout = timeout
+	}
+}
+
+// WithNamespaceDeliveryTimeout overrides the delivery timeout for the game shard of the given namespace.
+func WithNamespaceDeliveryTimeout(namespace string, timeout time.Duration) Option {
+	return func(r *routerImpl) {
+		r.namespaceTimeouts[namespace] = timeout
+	}
+}
+
+// WithMessageFees makes the contracts that send messages to game shards pay the fees and the gas of the given table.
+// The fees are moved to the fee collector by the keeper. By default, messages are free.
+func WithMessageFees(keeper FeeKeeper, table FeeTable) Option {
+	return WithMessageFeeSource(keeper, func(context.Context) (FeeTable, error) {
+		return table, nil
+	})
+}
+
+// WithMessageFeeSource is like WithMessageFees, but reads the fee table whenever a message is sent, e.g. from the
+// parameters of a module, so that the fees can change while the chain runs.
+func WithMessageFeeSource(keeper FeeKeeper, getFees GetFeeTableFn) Option {
+	return func(r *routerImpl) {
+		r.feeKeeper = keeper
+		r.getFees = getFees
+	}
+}
+
+// WithAttestationKey makes the router sign the messages it sends to game shards, so that game shards configured with the
+// public key can reject messages that don't come from the base shard. See credentials.Attest.
+func WithAttestationKey(key ed25519.PrivateKey) Option {
+	return func(r *routerImpl) {
+		r.attestationKey = key
+	}
+}
+
+// WithTLSConfig makes the router connect to game shards over TLS with the given config, e.g. to present a client
+// certificate to game shards that require one. See credentials.NewClientTLSConfig. By default, connections are not
+// encrypted.
+func WithTLSConfig(cfg *tls.Config) Option {
+	return func(r *routerImpl) {
+		r.tlsConfig = cfg
+	}
+}
+
+// WithOutboxStore makes the messages that game shards emit to EVM contracts available to the contracts through
+// ConsumeOutboxMessages. Without a store, consuming messages fails.
+func WithOutboxStore(store OutboxStore) Option {
+	return func(r *routerImpl) {
+		r.outbox = store
+	}
+}
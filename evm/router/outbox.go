@@ -0,0 +1,44 @@
+package router
+
+import (
+	"context"
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/ethereum/go-ethereum/common"
+	"google.golang.org/protobuf/proto"
+
+	"pkg.world.dev/world-engine/evm/x/shard/keeper"
+	shard "pkg.world.dev/world-engine/rift/shard/v2"
+)
+
+// OutboxMessage is a message that a game shard system emitted to an EVM contract.
+type OutboxMessage struct {
+	// Namespace is the namespace of the game shard that emitted the message.
+	Namespace string
+	*shard.OutboxMessage
+}
+
+// OutboxStore holds the messages that game shards emitted to EVM contracts until the contracts consume them. It is
+// implemented by the x/shard keeper, which stores the messages on chain when the sequencer receives them.
+type OutboxStore interface {
+	TakeOutboxMessages(ctx sdk.Context, contract []byte, limit int) []keeper.OutboxMessage
+}
+
+// consumeOutbox removes and returns up to limit messages destined for the contract from the outbox store. ctx must be
+// the context of the precompile call, so that the messages are put back if the EVM transaction reverts.
+func (r *routerImpl) consumeOutbox(ctx context.Context, contract common.Address, limit int) ([]OutboxMessage, error) {
+	if r.outbox == nil {
+		return nil, fmt.Errorf("cannot consume the outbox of contract %s: no outbox store", contract)
+	}
+	stored := r.outbox.TakeOutboxMessages(sdk.UnwrapSDKContext(ctx), contract.Bytes(), limit)
+	msgs := make([]OutboxMessage, 0, len(stored))
+	for _, s := range stored {
+		msg := new(shard.OutboxMessage)
+		if err := proto.Unmarshal(s.Message, msg); err != nil {
+			return nil, fmt.Errorf("failed to read outbox message of namespace %q: %w", s.Namespace, err)
+		}
+		msgs = append(msgs, OutboxMessage{Namespace: s.Namespace, OutboxMessage: msg})
+	}
+	return msgs, nil
+}
@@ -0,0 +1,58 @@
+package router
+
+import (
+	"context"
+	"testing"
+
+	"cosmossdk.io/log"
+	storetypes "cosmossdk.io/store/types"
+	"github.com/cosmos/cosmos-sdk/runtime"
+	"github.com/cosmos/cosmos-sdk/testutil"
+	"github.com/ethereum/go-ethereum/common"
+	"google.golang.org/protobuf/proto"
+	"gotest.tools/v3/assert"
+
+	"pkg.world.dev/world-engine/evm/x/shard/keeper"
+	shardtypes "pkg.world.dev/world-engine/evm/x/shard/types"
+	shard "pkg.world.dev/world-engine/rift/shard/v2"
+)
+
+func TestConsumeOutboxMessagesTakesStoredMessages(t *testing.T) {
+	key := storetypes.NewKVStoreKey(shardtypes.ModuleName)
+	ctx := testutil.DefaultContextWithDB(t, key, storetypes.NewTransientStoreKey("transient_test")).Ctx
+	k := keeper.NewKeeper(runtime.NewKVStoreService(key), "foo")
+	r := NewRouter(log.NewTestLogger(t), mockQueryCtx, mockGetAddr, WithOutboxStore(k))
+
+	contract := common.HexToAddress("0x5FbDB2315678afecb367f032d93F642f64180aa3")
+	save := func(ns string, msg *shard.OutboxMessage) {
+		bz, err := proto.Marshal(msg)
+		assert.NilError(t, err)
+		k.SaveOutboxMessage(ctx, ns, msg.GetId(), contract.Bytes(), bz)
+	}
+	save("foo", &shard.OutboxMessage{Id: 1, Tick: 5, Contract: contract.String(), Payload: []byte("first")})
+	save("foo", &shard.OutboxMessage{Id: 2, Tick: 6, Contract: contract.String(), Payload: []byte("second")})
+	save("bar", &shard.OutboxMessage{Id: 1, Tick: 2, Contract: contract.String(), Payload: []byte("other")})
+
+	got, err := r.ConsumeOutboxMessages(ctx, contract, 2)
+	assert.NilError(t, err)
+	assert.Equal(t, len(got), 2)
+	assert.Equal(t, got[0].Namespace, "bar")
+	assert.Equal(t, got[1].Namespace, "foo")
+	assert.Equal(t, got[1].GetId(), uint64(1))
+	assert.Equal(t, string(got[1].GetPayload()), "first")
+
+	got, err = r.ConsumeOutboxMessages(ctx, contract, 0)
+	assert.NilError(t, err)
+	assert.Equal(t, len(got), 1)
+	assert.Equal(t, got[0].GetTick(), uint64(6))
+
+	got, err = r.ConsumeOutboxMessages(ctx, contract, 0)
+	assert.NilError(t, err)
+	assert.Equal(t, len(got), 0)
+}
+
+func TestConsumeOutboxMessagesFailsWithoutAStore(t *testing.T) {
+	r := NewRouter(log.NewTestLogger(t), mockQueryCtx, mockGetAddr)
+	_, err := r.ConsumeOutboxMessages(context.Background(), common.Address{}, 0)
+	assert.ErrorContains(t, err, "no outbox store")
+}
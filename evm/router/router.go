@@ -2,6 +2,8 @@ package router
 
 import (
 	"context"
+	"crypto/ed25519"
+	"crypto/tls"
 	"fmt"
 	"strings"
 	"time"
@@ -11,6 +13,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	ethtypes "github.com/ethereum/go-ethereum/core/types"
 	"google.golang.org/grpc"
+	grpccredentials "google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 	"pkg.berachain.dev/polaris/eth/core/types"
 
@@ -31,7 +34,8 @@ var (
 
 // Router defines the methods required to interact with a game shard. The methods are invoked from EVM smart contracts.
 type Router interface {
-	// SendMessage queues a message to be sent to a game shard.
+	// SendMessage queues a message to be sent to a game shard. The sender pays the fee of the message, unless it is
+	// exempt. See WithMessageFeeSource.
 	SendMessage(_ context.Context, personaTag, namespace, sender, msgID string, msg []byte) error
 	// Query queries a game shard.
 	Query(ctx context.Context, request []byte, resource, namespace string) ([]byte, error)
@@ -54,6 +58,19 @@ type Router interface {
 	// an EVM transaction. Consider a user who calls the contract 0xFoo two times in one block. How do we know which
 	// one called the Router? TODO: work with polaris to find a solution to this problem.
 	PostBlockHook(types.Transactions, types.Receipts, types.Signer)
+	// ConsumeOutboxMessages removes and returns up to limit messages that game shards emitted to the given contract,
+	// sorted by namespace and by ID. A limit of 0 returns all messages. The messages are stored on chain, see
+	// WithOutboxStore.
+	ConsumeOutboxMessages(ctx context.Context, contract common.Address, limit int) ([]OutboxMessage, error)
+	// FlushUndelivered removes and returns the messages that could not be delivered to their game shard since the
+	// last call, so that they can be stored on chain.
+	FlushUndelivered() []UndeliveredMessage
+	// Redrive sends undelivered messages to their game shards again. Messages that fail again are returned by the
+	// next call to FlushUndelivered.
+	Redrive(msgs []UndeliveredMessage)
+	// FlushRouted removes and returns the messages that got a result since the last call, so that the history of
+	// routed messages can be stored on chain. A message that is redriven is returned once for every result.
+	FlushRouted() []RoutedMessage
 }
 
 type GetQueryCtxFn func(height int64, prove bool) (sdk.Context, error)
@@ -68,12 +85,22 @@ type routerImpl struct {
 	queue  *msgQueue
 
 	resultStore ResultStorage
+	outbox      OutboxStore
+	undelivered *undeliveredQueue
+	routed      *routedQueue
 
 	getQueryCtx GetQueryCtxFn
 	getAddr     GetAddressFn
 
 	// opts
-	routerKey string
+	routerKey         string
+	retryPolicy       RetryPolicy
+	defaultTimeout    time.Duration
+	namespaceTimeouts map[string]time.Duration
+	feeKeeper         FeeKeeper
+	getFees           GetFeeTableFn
+	attestationKey    ed25519.PrivateKey
+	tlsConfig         *tls.Config
 }
 
 // NewRouter returns a Router.
@@ -82,8 +109,14 @@ func NewRouter(logger log.Logger, ctxGetter GetQueryCtxFn, addrGetter GetAddress
 		logger:      logger,
 		queue:       newMsgQueue(),
 		resultStore: NewMemoryResultStorage(defaultStorageTimeout),
+		undelivered: &undeliveredQueue{},
+		routed:      &routedQueue{},
 		getQueryCtx: ctxGetter,
 		getAddr:     addrGetter,
+
+		retryPolicy:       DefaultRetryPolicy(),
+		defaultTimeout:    defaultDeliveryTimeout,
+		namespaceTimeouts: make(map[string]time.Duration),
 	}
 	for _, opt := range opts {
 		opt(r)
@@ -137,24 +170,22 @@ func (r *routerImpl) dispatchMessage(sender common.Address, txHash common.Hash)
 	r.logger.Info("found cross-shard message in queue", "tx_hash", txHash.String())
 	msg := gameShardTx.msg
 	msg.Sender = strings.ToLower(msg.GetSender()) // normalize the request
-	namespace := gameShardTx.namespace
 	msg.EvmTxHash = txHash.String()
+	r.send(gameShardTx.namespace, msg)
+}
+
+// send sends the message to the game shard of the namespace in a new Go routine, and stores the result in the
+// ephemeral result storage and the history of routed messages. Messages that can't be delivered, even after retrying, are added to the undelivered queue.
+func (r *routerImpl) send(namespace string, msg *routerv1.SendMessageRequest) {
 	r.logger.Info("attempting to get client connection")
 	client, err := r.getConnectionForNamespace(namespace)
 	if err != nil {
-		r.logger.Error("failed to get client connection")
-		r.resultStore.SetResult(
-			&routerv1.SendMessageResponse{
-				EvmTxHash: msg.GetEvmTxHash(),
-				Code:      CodeConnectionError,
-				Errs:      "error getting game shard gRPC connection: " + err.Error(),
-			},
-		)
 		r.logger.Error("error getting game shard gRPC connection", "error", err, "namespace", namespace)
+		r.giveUp(namespace, msg, CodeConnectionError, "error getting game shard gRPC connection: "+err.Error())
 		return
 	}
 	r.logger.Info("Sending tx to game shard",
-		"evm_tx_hash", txHash.String(),
+		"evm_tx_hash", msg.GetEvmTxHash(),
 		"namespace", namespace,
 		"sender", msg.GetSender(),
 		"msg_id", msg.GetMessageId(),
@@ -162,29 +193,40 @@ func (r *routerImpl) dispatchMessage(sender common.Address, txHash common.Hash)
 
 	// send the message in a new goroutine. we do this so that we don't make tx inclusion slower.
 	go func() {
-		res, err := client.SendMessage(context.Background(), msg)
+		res, err := r.deliver(client, namespace, msg)
 		if err != nil {
-			r.resultStore.SetResult(
-				&routerv1.SendMessageResponse{
-					EvmTxHash: msg.GetEvmTxHash(),
-					Code:      CodeServerError,
-					Errs:      err.Error(),
-				},
-			)
 			r.logger.Error("failed to send message to game shard", "error", err)
+			r.giveUp(namespace, msg, CodeServerError, err.Error())
 			return
 		}
 		r.logger.Info("successfully sent message to game shard", "result", res.String())
 		r.resultStore.SetResult(res)
+		r.routed.add(namespace, msg, res)
 	}()
 }
 
-func (r *routerImpl) SendMessage(_ context.Context, personaTag, namespace, sender, msgID string, msg []byte) error {
+// giveUp stores the error as the result of the message and adds the message to the undelivered queue.
+func (r *routerImpl) giveUp(namespace string, msg *routerv1.SendMessageRequest, code uint32, errs string) {
+	res := &routerv1.SendMessageResponse{
+		EvmTxHash: msg.GetEvmTxHash(),
+		Code:      code,
+		Errs:      errs,
+	}
+	r.resultStore.SetResult(res)
+	r.routed.add(namespace, msg, res)
+	r.undelivered.add(namespace, msg)
+}
+
+func (r *routerImpl) SendMessage(ctx context.Context, personaTag, namespace, sender, msgID string, msg []byte) error {
 	r.logger.Info("received SendMessage request",
 		"namespace", namespace,
 		"sender", sender,
 		"msgID", msgID,
 	)
+	if err := r.chargeMessage(ctx, namespace, common.HexToAddress(sender), msg); err != nil {
+		r.logger.Error("failed to charge message", "error", err.Error())
+		return err
+	}
 	req := &routerv1.SendMessageRequest{
 		Sender:     sender,
 		PersonaTag: personaTag,
@@ -240,9 +282,13 @@ func (r *routerImpl) getConnectionForNamespace(ns string) (routerv1.MsgClient, e
 		return nil, err
 	}
 	addr := res.Address
+	transportCreds := insecure.NewCredentials()
+	if r.tlsConfig != nil {
+		transportCreds = grpccredentials.NewTLS(r.tlsConfig)
+	}
 	conn, err := grpc.Dial(
 		addr,
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithTransportCredentials(transportCreds),
 		grpc.WithPerRPCCredentials(credentials.NewTokenCredential(r.routerKey)),
 	)
 	if err != nil {
@@ -250,3 +296,29 @@ func (r *routerImpl) getConnectionForNamespace(ns string) (routerv1.MsgClient, e
 	}
 	return routerv1.NewMsgClient(conn), nil
 }
+
+func (r *routerImpl) ConsumeOutboxMessages(
+	ctx context.Context, contract common.Address, limit int,
+) ([]OutboxMessage, error) {
+	msgs, err := r.consumeOutbox(ctx, contract, limit)
+	if err != nil {
+		return nil, err
+	}
+	r.logger.Debug("consumed outbox messages", "contract", contract.String(), "num_of_msgs", len(msgs))
+	return msgs, nil
+}
+
+func (r *routerImpl) FlushUndelivered() []UndeliveredMessage {
+	return r.undelivered.flush()
+}
+
+func (r *routerImpl) Redrive(msgs []UndeliveredMessage) {
+	for _, msg := range msgs {
+		r.logger.Info("redriving undelivered message", "namespace", msg.Namespace, "evm_tx_hash", msg.GetEvmTxHash())
+		r.send(msg.Namespace, msg.SendMessageRequest)
+	}
+}
+
+func (r *routerImpl) FlushRouted() []RoutedMessage {
+	return r.routed.flush()
+}
@@ -86,8 +86,16 @@ func (s *Sequencer) Serve() {
 }
 
 // FlushMessages empties and returns all messages stored in the queue.
-func (s *Sequencer) FlushMessages() ([]*types.SubmitShardTxRequest, []*namespacetypes.UpdateNamespaceRequest) {
-	return s.tq.FlushTxQueue(), s.tq.FlushInitQueue()
+func (s *Sequencer) FlushMessages() (
+	[]*types.SubmitShardTxRequest, []*namespacetypes.UpdateNamespaceRequest, []*types.SubmitStateRootRequest,
+) {
+	return s.tq.FlushTxQueue(), s.tq.FlushInitQueue(), s.tq.FlushStateRootQueue()
+}
+
+// FlushOutbox empties and returns the outbox deliveries stored in the queue, so that their messages can be stored on
+// chain.
+func (s *Sequencer) FlushOutbox() []*shard.DeliverOutboxRequest {
+	return s.tq.FlushOutboxQueue()
 }
 
 // Submit appends the game shard tx submission to the tx queue.
@@ -108,15 +116,26 @@ func (s *Sequencer) Submit(_ context.Context, req *shard.SubmitTransactionsReque
 	return &shard.SubmitTransactionsResponse{}, nil
 }
 
-// RegisterGameShard saves a namespace <> gRPC address pair for use with Router.
+// RegisterGameShard saves a namespace <> gRPC address pair, together with the game shard's genesis tick and schema
+// hash, for use with Router.
 func (s *Sequencer) RegisterGameShard(
 	_ context.Context,
 	req *shard.RegisterGameShardRequest,
 ) (*shard.RegisterGameShardResponse, error) {
-	s.tq.AddInitMsg(req.GetNamespace(), req.GetRouterAddress())
+	s.tq.AddInitMsg(req.GetNamespace(), req.GetRouterAddress(), req.GetGenesisTick(), req.GetSchemaHash())
 	return &shard.RegisterGameShardResponse{}, nil
 }
 
+// SubmitStateRoot appends the game shard's state root to the queue. The state root is checked against the previous
+// state roots of the game shard when it is sequenced.
+func (s *Sequencer) SubmitStateRoot(
+	_ context.Context,
+	req *shard.SubmitStateRootRequest,
+) (*shard.SubmitStateRootResponse, error) {
+	s.tq.AddStateRoot(req.GetNamespace(), req.GetStartTick(), req.GetEndTick(), req.GetStateRoot())
+	return &shard.SubmitStateRootResponse{}, nil
+}
+
 // QueryTransactions is a proxy method that calls x/shard's QueryTransactions. This is needed so Cardinal can just
 // run a `Rift` gRPC client, instead of needing to run the `Cosmos` gRPC client.
 func (s *Sequencer) QueryTransactions(
@@ -151,6 +170,22 @@ func (s *Sequencer) QueryTransactions(
 	return convertedResponse, nil
 }
 
+// DeliverOutbox appends the outbox messages of a game shard to the queue, so that they are stored on chain in the next
+// block. The response acknowledges the messages up to the highest ID stored on chain as of the last committed block, so
+// the game shard delivers the messages of this request again until they are committed.
+func (s *Sequencer) DeliverOutbox(_ context.Context, req *shard.DeliverOutboxRequest) (
+	*shard.DeliverOutboxResponse, error,
+) {
+	cosmosCtx, err := s.queryCtxGetter(0, false)
+	if err != nil {
+		return nil, eris.Wrap(err, "failed to get query context")
+	}
+	if len(req.GetMessages()) > 0 {
+		s.tq.AddOutboxMessages(req)
+	}
+	return &shard.DeliverOutboxResponse{AckedId: s.shardKeeper.OutboxAckedID(cosmosCtx, req.GetNamespace())}, nil
+}
+
 // serverCallInterceptor catches calls to handlers and ensures they have the right secret routerKey.
 func (s *Sequencer) serverCallInterceptor(
 	ctx context.Context,
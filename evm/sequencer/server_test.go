@@ -49,7 +49,7 @@ func TestMessagesAreOrderedAndProtoMarshalled(t *testing.T) {
 	_, err := seq.Submit(context.Background(), &req)
 	assert.NilError(t, err)
 
-	flushedMessages, _ := seq.FlushMessages()
+	flushedMessages, _, _ := seq.FlushMessages()
 	assert.Len(t, flushedMessages, 1)
 	messages := flushedMessages[0]
 	assert.Len(t, messages.Txs, 2)
@@ -66,7 +66,7 @@ func TestMessagesAreOrderedAndProtoMarshalled(t *testing.T) {
 	assert.Check(t, proto.Equal(pbMsg, req.GetTransactions()[44].GetTxs()[0]))
 }
 
-func TestGetBothSlices(t *testing.T) {
+func TestGetAllSlices(t *testing.T) {
 	t.Parallel()
 	seq := New(keeper.NewKeeper(nil, "foo"), nil)
 	_, err := seq.RegisterGameShard(context.Background(), &shardv2.RegisterGameShardRequest{
@@ -90,8 +90,16 @@ func TestGetBothSlices(t *testing.T) {
 			},
 		})
 	assert.NilError(t, err)
-	txs, inits := seq.FlushMessages()
+	_, err = seq.SubmitStateRoot(context.Background(), &shardv2.SubmitStateRootRequest{
+		Namespace: "foo",
+		StartTick: 0,
+		EndTick:   1,
+		StateRoot: make([]byte, 32),
+	})
+	assert.NilError(t, err)
+	txs, inits, roots := seq.FlushMessages()
 
 	assert.Len(t, txs, 1)
 	assert.Len(t, inits, 1)
+	assert.Len(t, roots, 1)
 }
@@ -7,6 +7,7 @@ import (
 
 	namespacetypes "pkg.world.dev/world-engine/evm/x/namespace/types"
 	"pkg.world.dev/world-engine/evm/x/shard/types"
+	shard "pkg.world.dev/world-engine/rift/shard/v2"
 )
 
 // TxQueue acts as a transaction queue. Transactions come in to the TxQueue with an epoch.
@@ -14,6 +15,8 @@ type TxQueue struct {
 	lock       sync.Mutex
 	txQueue    map[string]map[uint64]*types.SubmitShardTxRequest
 	initQueue  []*namespacetypes.UpdateNamespaceRequest
+	rootQueue  []*types.SubmitStateRootRequest
+	outbox     []*shard.DeliverOutboxRequest
 	moduleAddr string
 }
 
@@ -22,11 +25,12 @@ func NewTxQueue(moduleAddr string) *TxQueue {
 		lock:       sync.Mutex{},
 		txQueue:    make(map[string]map[uint64]*types.SubmitShardTxRequest),
 		initQueue:  make([]*namespacetypes.UpdateNamespaceRequest, 0),
+		rootQueue:  make([]*types.SubmitStateRootRequest, 0),
 		moduleAddr: moduleAddr,
 	}
 }
 
-func (tc *TxQueue) AddInitMsg(namespace, routerAddr string) {
+func (tc *TxQueue) AddInitMsg(namespace, routerAddr string, genesisTick uint64, schemaHash string) {
 	tc.lock.Lock()
 	defer tc.lock.Unlock()
 	tc.initQueue = append(tc.initQueue, &namespacetypes.UpdateNamespaceRequest{
@@ -34,10 +38,32 @@ func (tc *TxQueue) AddInitMsg(namespace, routerAddr string) {
 		Namespace: &namespacetypes.Namespace{
 			ShardName:    namespace,
 			ShardAddress: routerAddr,
+			GenesisTick:  genesisTick,
+			SchemaHash:   schemaHash,
 		},
 	})
 }
 
+// AddStateRoot adds a state root of the game shard of the namespace to the queue.
+func (tc *TxQueue) AddStateRoot(namespace string, startTick, endTick uint64, stateRoot []byte) {
+	tc.lock.Lock()
+	defer tc.lock.Unlock()
+	tc.rootQueue = append(tc.rootQueue, &types.SubmitStateRootRequest{
+		Sender:    tc.moduleAddr,
+		Namespace: namespace,
+		StartTick: startTick,
+		EndTick:   endTick,
+		StateRoot: stateRoot,
+	})
+}
+
+// AddOutboxMessages adds the outbox messages that a game shard delivered to the queue.
+func (tc *TxQueue) AddOutboxMessages(req *shard.DeliverOutboxRequest) {
+	tc.lock.Lock()
+	defer tc.lock.Unlock()
+	tc.outbox = append(tc.outbox, req)
+}
+
 // AddTx adds a transaction to the queue.
 func (tc *TxQueue) AddTx(namespace string, epoch, unixTimestamp, txID uint64, payload []byte) {
 	tc.lock.Lock()
@@ -95,6 +121,34 @@ func (tc *TxQueue) FlushInitQueue() []*namespacetypes.UpdateNamespaceRequest {
 	return reqs
 }
 
+// FlushStateRootQueue gets all currently queued state roots in the order they were submitted, and then clears the
+// queue.
+func (tc *TxQueue) FlushStateRootQueue() []*types.SubmitStateRootRequest {
+	tc.lock.Lock()
+	defer tc.lock.Unlock()
+	if len(tc.rootQueue) == 0 {
+		return nil
+	}
+	reqs := make([]*types.SubmitStateRootRequest, len(tc.rootQueue))
+	copy(reqs, tc.rootQueue)
+	tc.rootQueue = tc.rootQueue[:0]
+	return reqs
+}
+
+// FlushOutboxQueue gets all currently queued outbox deliveries in the order they were received, and then clears the
+// queue.
+func (tc *TxQueue) FlushOutboxQueue() []*shard.DeliverOutboxRequest {
+	tc.lock.Lock()
+	defer tc.lock.Unlock()
+	if len(tc.outbox) == 0 {
+		return nil
+	}
+	reqs := make([]*shard.DeliverOutboxRequest, len(tc.outbox))
+	copy(reqs, tc.outbox)
+	tc.outbox = tc.outbox[:0]
+	return reqs
+}
+
 func sortMapKeys[S map[K]V, K cmp.Ordered, V any](m S) []K {
 	keys := make([]K, 0, len(m))
 	for key := range m {
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"pkg.world.dev/world-engine/assert"
+	shard "pkg.world.dev/world-engine/rift/shard/v2"
 )
 
 // TestAddTx tests that txs can be added to the queue, and then flushed sorted by namespace & epoch.
@@ -35,17 +36,56 @@ func TestAddInitMsg(t *testing.T) {
 	namespace2 := "hi"
 	addr2 := "foo:123"
 
-	txq.AddInitMsg(namespace, addr)
-	txq.AddInitMsg(namespace2, addr2)
+	txq.AddInitMsg(namespace, addr, 10, "abc")
+	txq.AddInitMsg(namespace2, addr2, 0, "")
 
 	inits := txq.FlushInitQueue()
 	assert.Len(t, inits, 2)
 	assert.Equal(t, inits[0].Namespace.ShardName, namespace)
 	assert.Equal(t, inits[0].Namespace.ShardAddress, addr)
+	assert.Equal(t, inits[0].Namespace.GenesisTick, uint64(10))
+	assert.Equal(t, inits[0].Namespace.SchemaHash, "abc")
 
 	assert.Equal(t, inits[1].Namespace.ShardName, namespace2)
 	assert.Equal(t, inits[1].Namespace.ShardAddress, addr2)
 
-	txq.AddInitMsg("foo", "bar")
+	txq.AddInitMsg("foo", "bar", 0, "")
 	assert.Len(t, txq.FlushInitQueue(), 1)
 }
+
+func TestAddStateRoot(t *testing.T) {
+	txq := NewTxQueue("0xfoo")
+	txq.AddStateRoot("foo", 0, 9, []byte("root1"))
+	txq.AddStateRoot("bar", 0, 4, []byte("root2"))
+
+	roots := txq.FlushStateRootQueue()
+	assert.Len(t, roots, 2)
+	// state roots keep the order they were submitted in.
+	assert.Equal(t, roots[0].Namespace, "foo")
+	assert.Equal(t, roots[0].Sender, "0xfoo")
+	assert.Equal(t, roots[0].EndTick, uint64(9))
+	assert.Equal(t, string(roots[0].StateRoot), "root1")
+	assert.Equal(t, roots[1].Namespace, "bar")
+
+	assert.Len(t, txq.FlushStateRootQueue(), 0)
+}
+
+func TestAddOutboxMessages(t *testing.T) {
+	txq := NewTxQueue("0xfoo")
+	txq.AddOutboxMessages(&shard.DeliverOutboxRequest{
+		Namespace: "foo",
+		Messages:  []*shard.OutboxMessage{{Id: 1, Payload: []byte("first")}},
+	})
+	txq.AddOutboxMessages(&shard.DeliverOutboxRequest{
+		Namespace: "bar",
+		Messages:  []*shard.OutboxMessage{{Id: 1, Payload: []byte("other")}},
+	})
+
+	reqs := txq.FlushOutboxQueue()
+	assert.Len(t, reqs, 2)
+	// deliveries keep the order they were received in.
+	assert.Equal(t, reqs[0].GetNamespace(), "foo")
+	assert.Equal(t, reqs[1].GetNamespace(), "bar")
+
+	assert.Len(t, txq.FlushOutboxQueue(), 0)
+}
@@ -23,6 +23,7 @@ func NewQueryCmd() *cobra.Command {
 	queryCmd.AddCommand(
 		NewQueryNamespacesCmd(),
 		NewQueryAddressCmd(),
+		NewQueryParamsCmd(),
 	)
 	return queryCmd
 }
@@ -79,3 +80,26 @@ func NewQueryAddressCmd() *cobra.Command {
 	}
 	return cmd
 }
+
+func NewQueryParamsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "params",
+		Short:   "Return the parameters of the router",
+		Example: fmt.Sprintf("%s query namespace params", version.AppName),
+		Args:    cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			clientCtx, err := client.GetClientTxContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			queryClient := types.NewQueryServiceClient(clientCtx)
+			res, err := queryClient.Params(cmd.Context(), &types.ParamsRequest{})
+			if err != nil {
+				return err
+			}
+			return clientCtx.PrintProto(res)
+		},
+	}
+	return cmd
+}
@@ -1,12 +1,14 @@
 package keeper
 
 import (
+	"context"
 	"log"
 	"strings"
 
 	storetypes "cosmossdk.io/store/types"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	govtypes "github.com/cosmos/cosmos-sdk/x/gov/types"
 
 	"pkg.world.dev/world-engine/evm/sequencer"
 	namespacetypes "pkg.world.dev/world-engine/evm/x/namespace/types"
@@ -16,6 +18,9 @@ type Keeper struct {
 	storeKey *storetypes.KVStoreKey
 	// authority is the bech32 address that is allowed to execute governance proposals.
 	authority string
+	// govAuthority is the bech32 address of the governance module, which updates the params and can register
+	// namespaces.
+	govAuthority string
 }
 
 func NewKeeper(storeKey *storetypes.KVStoreKey, auth string) *Keeper {
@@ -26,18 +31,31 @@ func NewKeeper(storeKey *storetypes.KVStoreKey, auth string) *Keeper {
 		}
 	}
 	return &Keeper{
-		storeKey:  storeKey,
-		authority: auth,
+		storeKey:     storeKey,
+		authority:    auth,
+		govAuthority: authtypes.NewModuleAddress(govtypes.ModuleName).String(),
 	}
 }
 
+// GetParams returns the parameters of the router.
+func (k *Keeper) GetParams(ctx context.Context) (namespacetypes.Params, error) {
+	return k.getParams(sdk.UnwrapSDKContext(ctx))
+}
+
 func (k *Keeper) InitGenesis(ctx sdk.Context, gen *namespacetypes.Genesis) {
 	for _, ns := range gen.Namespaces {
 		k.setNamespace(ctx, ns)
 	}
+	if err := k.setParams(ctx, gen.Params); err != nil {
+		panic(err)
+	}
 }
 
 func (k *Keeper) ExportGenesis(ctx sdk.Context) *namespacetypes.Genesis {
 	nameSpaces := k.getAllNamespaces(ctx)
-	return &namespacetypes.Genesis{Namespaces: nameSpaces}
+	params, err := k.getParams(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return &namespacetypes.Genesis{Namespaces: nameSpaces, Params: params}
 }
@@ -14,14 +14,35 @@ var _ types.MsgServer = &Keeper{}
 func (k *Keeper) UpdateNamespace(ctx context.Context, request *types.UpdateNamespaceRequest) (
 	*types.UpdateNamespaceResponse, error,
 ) {
-	// check that router module called this method, not an external user.
-	if k.authority != request.Authority {
+	sdkCtx := sdk.UnwrapSDKContext(ctx)
+	// check that router module, governance or an authorized address called this method, not an external user.
+	params, err := k.getParams(sdkCtx)
+	if err != nil {
+		return nil, err
+	}
+	if request.Authority != k.authority && request.Authority != k.govAuthority &&
+		!params.IsAuthorized(request.Authority) {
 		return nil, sdkerrors.ErrUnauthorized.
 			Wrapf("%s is not allowed to update namespaces, expected %s", request.Authority, k.authority)
 	}
-	sdkCtx := sdk.UnwrapSDKContext(ctx)
 
 	k.setNamespace(sdkCtx, request.Namespace)
 
 	return &types.UpdateNamespaceResponse{}, nil
 }
+
+func (k *Keeper) UpdateParams(ctx context.Context, request *types.UpdateParamsRequest) (
+	*types.UpdateParamsResponse, error,
+) {
+	if k.govAuthority != request.Authority {
+		return nil, sdkerrors.ErrUnauthorized.
+			Wrapf("%s is not allowed to update params, expected %s", request.Authority, k.govAuthority)
+	}
+	if err := request.Params.Validate(); err != nil {
+		return nil, err
+	}
+	if err := k.setParams(sdk.UnwrapSDKContext(ctx), request.Params); err != nil {
+		return nil, err
+	}
+	return &types.UpdateParamsResponse{}, nil
+}
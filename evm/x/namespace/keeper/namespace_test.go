@@ -11,6 +11,8 @@ import (
 	simtestutil "github.com/cosmos/cosmos-sdk/testutil/sims"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	moduletestutil "github.com/cosmos/cosmos-sdk/types/module/testutil"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	govtypes "github.com/cosmos/cosmos-sdk/x/gov/types"
 	"github.com/stretchr/testify/suite"
 
 	"pkg.world.dev/world-engine/evm/x/namespace"
@@ -108,6 +110,47 @@ func (s *TestSuite) TestGetAllNamespaces() {
 	}
 }
 
+func (s *TestSuite) TestReRegisteringKeepsGenesisTick() {
+	ns := &namespacetypes.Namespace{
+		ShardName:    "foo",
+		ShardAddress: "localhost:9310",
+		GenesisTick:  5,
+		SchemaHash:   "abc",
+	}
+	_, err := s.keeper.UpdateNamespace(s.ctx, &namespacetypes.UpdateNamespaceRequest{
+		Authority: s.authority.String(),
+		Namespace: ns,
+	})
+	s.Require().NoError(err)
+
+	res, err := s.keeper.Namespaces(s.ctx, &namespacetypes.NamespacesRequest{})
+	s.Require().NoError(err)
+	s.Require().Len(res.Namespaces, 1)
+	s.Require().Equal(ns, res.Namespaces[0])
+
+	// the shard restarts at a later tick with a new address and schema
+	_, err = s.keeper.UpdateNamespace(s.ctx, &namespacetypes.UpdateNamespaceRequest{
+		Authority: s.authority.String(),
+		Namespace: &namespacetypes.Namespace{
+			ShardName:    "foo",
+			ShardAddress: "localhost:9311",
+			GenesisTick:  90,
+			SchemaHash:   "def",
+		},
+	})
+	s.Require().NoError(err)
+
+	res, err = s.keeper.Namespaces(s.ctx, &namespacetypes.NamespacesRequest{})
+	s.Require().NoError(err)
+	s.Require().Len(res.Namespaces, 1)
+	s.Require().Equal(&namespacetypes.Namespace{
+		ShardName:    "foo",
+		ShardAddress: "localhost:9311",
+		GenesisTick:  5,
+		SchemaHash:   "def",
+	}, res.Namespaces[0])
+}
+
 func (s *TestSuite) TestUpdateNamespace_Unauthorized() {
 	notAuth := s.addrs[1].String()
 	_, err := s.keeper.UpdateNamespace(s.ctx, &namespacetypes.UpdateNamespaceRequest{
@@ -116,3 +159,85 @@ func (s *TestSuite) TestUpdateNamespace_Unauthorized() {
 	})
 	s.Require().ErrorContains(err, notAuth+" is not allowed to update namespaces")
 }
+
+func (s *TestSuite) TestUpdateParams() {
+	govAuthority := authtypes.NewModuleAddress(govtypes.ModuleName).String()
+	params := namespacetypes.Params{
+		AuthorizedAddresses: []string{s.addrs[2].String()},
+		MessageFee:          sdk.NewCoins(sdk.NewInt64Coin("uworld", 10)),
+		NamespaceMessageFees: []namespacetypes.NamespaceMessageFee{
+			{Namespace: "foo", Fee: sdk.NewCoins(sdk.NewInt64Coin("uworld", 20))},
+		},
+		GasPerMessage:    20000,
+		GasPerByte:       16,
+		FeeExemptSenders: []string{"0x61d2B2315605660c3855C8BE139B82e0635E13E3"},
+	}
+
+	res, err := s.keeper.Params(s.ctx, &namespacetypes.ParamsRequest{})
+	s.Require().NoError(err)
+	s.Require().Equal(namespacetypes.DefaultParams(), res.Params)
+
+	// only governance can update the params, not the sequencer
+	_, err = s.keeper.UpdateParams(s.ctx, &namespacetypes.UpdateParamsRequest{
+		Authority: s.authority.String(),
+		Params:    params,
+	})
+	s.Require().ErrorContains(err, s.authority.String()+" is not allowed to update params")
+
+	invalid := params
+	invalid.FeeExemptSenders = []string{"not an address"}
+	_, err = s.keeper.UpdateParams(s.ctx, &namespacetypes.UpdateParamsRequest{
+		Authority: govAuthority,
+		Params:    invalid,
+	})
+	s.Require().ErrorContains(err, "invalid fee exempt sender")
+
+	_, err = s.keeper.UpdateParams(s.ctx, &namespacetypes.UpdateParamsRequest{
+		Authority: govAuthority,
+		Params:    params,
+	})
+	s.Require().NoError(err)
+
+	res, err = s.keeper.Params(s.ctx, &namespacetypes.ParamsRequest{})
+	s.Require().NoError(err)
+	s.Require().Equal(params, res.Params)
+	s.Require().Equal(params, s.keeper.ExportGenesis(s.ctx).Params)
+}
+
+func (s *TestSuite) TestGovernanceAndAuthorizedAddressesCanUpdateNamespaces() {
+	govAuthority := authtypes.NewModuleAddress(govtypes.ModuleName).String()
+	authorized := s.addrs[2].String()
+	ns := &namespacetypes.Namespace{
+		ShardName:    "foo",
+		ShardAddress: "localhost:9310",
+	}
+
+	_, err := s.keeper.UpdateNamespace(s.ctx, &namespacetypes.UpdateNamespaceRequest{
+		Authority: authorized,
+		Namespace: ns,
+	})
+	s.Require().ErrorContains(err, authorized+" is not allowed to update namespaces")
+
+	_, err = s.keeper.UpdateParams(s.ctx, &namespacetypes.UpdateParamsRequest{
+		Authority: govAuthority,
+		Params:    namespacetypes.Params{AuthorizedAddresses: []string{authorized}},
+	})
+	s.Require().NoError(err)
+
+	_, err = s.keeper.UpdateNamespace(s.ctx, &namespacetypes.UpdateNamespaceRequest{
+		Authority: authorized,
+		Namespace: ns,
+	})
+	s.Require().NoError(err)
+
+	ns.ShardAddress = "localhost:9311"
+	_, err = s.keeper.UpdateNamespace(s.ctx, &namespacetypes.UpdateNamespaceRequest{
+		Authority: govAuthority,
+		Namespace: ns,
+	})
+	s.Require().NoError(err)
+
+	res, err := s.keeper.Address(s.ctx, &namespacetypes.AddressRequest{Namespace: ns.ShardName})
+	s.Require().NoError(err)
+	s.Require().Equal(ns.ShardAddress, res.Address)
+}
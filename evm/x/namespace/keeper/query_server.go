@@ -23,3 +23,11 @@ func (k *Keeper) Address(ctx context.Context, request *types.AddressRequest) (*t
 	}
 	return &types.AddressResponse{Address: addr}, nil
 }
+
+func (k *Keeper) Params(ctx context.Context, _ *types.ParamsRequest) (*types.ParamsResponse, error) {
+	params, err := k.getParams(sdk.UnwrapSDKContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	return &types.ParamsResponse{Params: params}, nil
+}
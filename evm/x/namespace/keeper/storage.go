@@ -1,6 +1,7 @@
 package keeper
 
 import (
+	"encoding/binary"
 	"fmt"
 
 	"cosmossdk.io/store/prefix"
@@ -10,13 +11,24 @@ import (
 )
 
 var (
-	namespacePrefix = []byte("ns")
+	namespacePrefix   = []byte("ns")
+	genesisTickPrefix = []byte("gt")
+	schemaHashPrefix  = []byte("sh")
+	paramsKey         = []byte("pa")
 )
 
 func (k *Keeper) getNamespaceStore(ctx sdk.Context) prefix.Store {
 	return prefix.NewStore(ctx.KVStore(k.storeKey), namespacePrefix)
 }
 
+func (k *Keeper) getGenesisTickStore(ctx sdk.Context) prefix.Store {
+	return prefix.NewStore(ctx.KVStore(k.storeKey), genesisTickPrefix)
+}
+
+func (k *Keeper) getSchemaHashStore(ctx sdk.Context) prefix.Store {
+	return prefix.NewStore(ctx.KVStore(k.storeKey), schemaHashPrefix)
+}
+
 func (k *Keeper) getAddressForNamespace(ctx sdk.Context, ns string) (string, error) {
 	store := k.getNamespaceStore(ctx)
 	addr := store.Get([]byte(ns))
@@ -26,20 +38,58 @@ func (k *Keeper) getAddressForNamespace(ctx sdk.Context, ns string) (string, err
 	return string(addr), nil
 }
 
+// setNamespace saves the address and metadata of a namespace. The genesis tick is only saved the first time a
+// namespace is set, so that shards re-registering after a restart keep their original genesis tick.
 func (k *Keeper) setNamespace(ctx sdk.Context, ns *types.Namespace) {
 	store := k.getNamespaceStore(ctx)
 	store.Set([]byte(ns.ShardName), []byte(ns.ShardAddress))
+
+	tickStore := k.getGenesisTickStore(ctx)
+	if !tickStore.Has([]byte(ns.ShardName)) {
+		tickStore.Set([]byte(ns.ShardName), binary.BigEndian.AppendUint64(nil, ns.GenesisTick))
+	}
+	k.getSchemaHashStore(ctx).Set([]byte(ns.ShardName), []byte(ns.SchemaHash))
 }
 
 func (k *Keeper) getAllNamespaces(ctx sdk.Context) []*types.Namespace {
 	store := k.getNamespaceStore(ctx)
+	tickStore := k.getGenesisTickStore(ctx)
+	hashStore := k.getSchemaHashStore(ctx)
 	it := store.Iterator(nil, nil)
 	namespaces := make([]*types.Namespace, 0)
 	for ; it.Valid(); it.Next() {
-		namespaces = append(namespaces, &types.Namespace{
+		ns := &types.Namespace{
 			ShardName:    string(it.Key()),
 			ShardAddress: string(it.Value()),
-		})
+			SchemaHash:   string(hashStore.Get(it.Key())),
+		}
+		// namespaces set before shards registered with metadata have no genesis tick
+		if tick := tickStore.Get(it.Key()); tick != nil {
+			ns.GenesisTick = binary.BigEndian.Uint64(tick)
+		}
+		namespaces = append(namespaces, ns)
 	}
 	return namespaces
 }
+
+// getParams returns the parameters of the router, or the default parameters if they were never set.
+func (k *Keeper) getParams(ctx sdk.Context) (types.Params, error) {
+	bz := ctx.KVStore(k.storeKey).Get(paramsKey)
+	if bz == nil {
+		return types.DefaultParams(), nil
+	}
+	var params types.Params
+	if err := params.Unmarshal(bz); err != nil {
+		return types.Params{}, fmt.Errorf("failed to unmarshal params: %w", err)
+	}
+	return params, nil
+}
+
+func (k *Keeper) setParams(ctx sdk.Context, params types.Params) error {
+	bz, err := params.Marshal()
+	if err != nil {
+		return fmt.Errorf("failed to marshal params: %w", err)
+	}
+	ctx.KVStore(k.storeKey).Set(paramsKey, bz)
+	return nil
+}
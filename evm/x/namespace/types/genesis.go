@@ -1,7 +1,7 @@
 package types
 
 func DefaultGenesis() *Genesis {
-	return &Genesis{}
+	return &Genesis{Params: DefaultParams()}
 }
 
 func (g *Genesis) Validate() error {
@@ -10,5 +10,5 @@ func (g *Genesis) Validate() error {
 			return err
 		}
 	}
-	return nil
+	return g.Params.Validate()
 }
@@ -5,6 +5,7 @@ package types
 
 import (
 	fmt "fmt"
+	_ "github.com/cosmos/gogoproto/gogoproto"
 	proto "github.com/cosmos/gogoproto/proto"
 	io "io"
 	math "math"
@@ -24,6 +25,7 @@ const _ = proto.GoGoProtoPackageIsVersion3 // please upgrade the proto package
 
 type Genesis struct {
 	Namespaces []*Namespace `protobuf:"bytes,1,rep,name=namespaces,proto3" json:"namespaces,omitempty"`
+	Params     Params       `protobuf:"bytes,2,opt,name=params,proto3" json:"params"`
 }
 
 func (m *Genesis) Reset()         { *m = Genesis{} }
@@ -66,6 +68,13 @@ func (m *Genesis) GetNamespaces() []*Namespace {
 	return nil
 }
 
+func (m *Genesis) GetParams() Params {
+	if m != nil {
+		return m.Params
+	}
+	return Params{}
+}
+
 func init() {
 	proto.RegisterType((*Genesis)(nil), "namespace.v1.Genesis")
 }
@@ -73,18 +82,22 @@ func init() {
 func init() { proto.RegisterFile("namespace/v1/genesis.proto", fileDescriptor_8b24f99146805833) }
 
 var fileDescriptor_8b24f99146805833 = []byte{
-	// 176 bytes of a gzipped FileDescriptorProto
+	// 225 bytes of a gzipped FileDescriptorProto
 	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x92, 0xca, 0x4b, 0xcc, 0x4d,
 	0x2d, 0x2e, 0x48, 0x4c, 0x4e, 0xd5, 0x2f, 0x33, 0xd4, 0x4f, 0x4f, 0xcd, 0x4b, 0x2d, 0xce, 0x2c,
-	0xd6, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0xe2, 0x81, 0xcb, 0xe9, 0x95, 0x19, 0x4a, 0x49, 0xa0,
-	0xa8, 0x2c, 0x2c, 0x4d, 0x2d, 0xaa, 0x84, 0xa8, 0x53, 0x72, 0xe2, 0x62, 0x77, 0x87, 0x68, 0x14,
-	0x32, 0xe7, 0xe2, 0x82, 0x2b, 0x2b, 0x96, 0x60, 0x54, 0x60, 0xd6, 0xe0, 0x36, 0x12, 0xd7, 0x43,
-	0x36, 0x47, 0xcf, 0x0f, 0xc6, 0x09, 0x42, 0x52, 0xea, 0xe4, 0x73, 0xe2, 0x91, 0x1c, 0xe3, 0x85,
-	0x47, 0x72, 0x8c, 0x0f, 0x1e, 0xc9, 0x31, 0x4e, 0x78, 0x2c, 0xc7, 0x70, 0xe1, 0xb1, 0x1c, 0xc3,
-	0x8d, 0xc7, 0x72, 0x0c, 0x51, 0x46, 0x05, 0xd9, 0xe9, 0x7a, 0xe5, 0xf9, 0x45, 0x39, 0x29, 0x7a,
-	0x29, 0xa9, 0x65, 0xfa, 0x60, 0x96, 0x6e, 0x6a, 0x5e, 0x7a, 0x66, 0x5e, 0xaa, 0x7e, 0x72, 0x46,
-	0x62, 0x66, 0x9e, 0x7e, 0x85, 0x3e, 0xc2, 0x69, 0x25, 0x95, 0x05, 0xa9, 0xc5, 0x49, 0x6c, 0x60,
-	0x87, 0x19, 0x03, 0x02, 0x00, 0x00, 0xff, 0xff, 0x13, 0xf0, 0x81, 0xf8, 0xde, 0x00, 0x00, 0x00,
+	0xd6, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0xe2, 0x81, 0xcb, 0xe9, 0x95, 0x19, 0x4a, 0x89, 0xa4,
+	0xe7, 0xa7, 0xe7, 0x83, 0x25, 0xf4, 0x41, 0x2c, 0x88, 0x1a, 0x29, 0x49, 0x14, 0xfd, 0x05, 0x89,
+	0x45, 0x89, 0xb9, 0x50, 0xed, 0x52, 0x12, 0x28, 0x52, 0x85, 0xa5, 0xa9, 0x45, 0x95, 0x10, 0x19,
+	0xa5, 0x32, 0x2e, 0x76, 0x77, 0x88, 0x4d, 0x42, 0xe6, 0x5c, 0x5c, 0x70, 0x65, 0xc5, 0x12, 0x8c,
+	0x0a, 0xcc, 0x1a, 0xdc, 0x46, 0xe2, 0x7a, 0xc8, 0x16, 0xeb, 0xf9, 0xc1, 0x38, 0x41, 0x48, 0x4a,
+	0x85, 0x8c, 0xb8, 0xd8, 0x20, 0xb6, 0x49, 0x30, 0x29, 0x30, 0x6a, 0x70, 0x1b, 0x89, 0xa0, 0x6a,
+	0x0a, 0x00, 0xcb, 0x39, 0xb1, 0x9c, 0xb8, 0x27, 0xcf, 0x10, 0x04, 0x55, 0xe9, 0xe4, 0x73, 0xe2,
+	0x91, 0x1c, 0xe3, 0x85, 0x47, 0x72, 0x8c, 0x0f, 0x1e, 0xc9, 0x31, 0x4e, 0x78, 0x2c, 0xc7, 0x70,
+	0xe1, 0xb1, 0x1c, 0xc3, 0x8d, 0xc7, 0x72, 0x0c, 0x51, 0x46, 0x05, 0xd9, 0xe9, 0x7a, 0xe5, 0xf9,
+	0x45, 0x39, 0x29, 0x7a, 0x29, 0xa9, 0x65, 0xfa, 0x60, 0x96, 0x6e, 0x6a, 0x5e, 0x7a, 0x66, 0x5e,
+	0xaa, 0x7e, 0x72, 0x46, 0x62, 0x66, 0x9e, 0x7e, 0x85, 0x3e, 0xc2, 0x3b, 0x25, 0x95, 0x05, 0xa9,
+	0xc5, 0x49, 0x6c, 0x60, 0xcf, 0x18, 0x03, 0x06, 0x00, 0xae, 0x6b, 0x07, 0xc6, 0x43, 0x01, 0x00,
+	0x00,
 }
 
 func (m *Genesis) Marshal() (dAtA []byte, err error) {
@@ -107,6 +120,16 @@ func (m *Genesis) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	{
+		size, err := m.Params.MarshalToSizedBuffer(dAtA[:i])
+		if err != nil {
+			return 0, err
+		}
+		i -= size
+		i = encodeVarintGenesis(dAtA, i, uint64(size))
+	}
+	i--
+	dAtA[i] = 0x12
 	if len(m.Namespaces) > 0 {
 		for iNdEx := len(m.Namespaces) - 1; iNdEx >= 0; iNdEx-- {
 			{
@@ -147,6 +170,8 @@ func (m *Genesis) Size() (n int) {
 			n += 1 + l + sovGenesis(uint64(l))
 		}
 	}
+	l = m.Params.Size()
+	n += 1 + l + sovGenesis(uint64(l))
 	return n
 }
 
@@ -219,6 +244,39 @@ func (m *Genesis) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Params", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenesis
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenesis
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenesis
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.Params.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipGenesis(dAtA[iNdEx:])
@@ -12,6 +12,8 @@ import (
 var (
 	_ sdk.Msg              = &UpdateNamespaceRequest{}
 	_ sdk.HasValidateBasic = &UpdateNamespaceRequest{}
+	_ sdk.Msg              = &UpdateParamsRequest{}
+	_ sdk.HasValidateBasic = &UpdateParamsRequest{}
 )
 
 func (m *UpdateNamespaceRequest) ValidateBasic() error {
@@ -24,6 +26,13 @@ func (m *UpdateNamespaceRequest) ValidateBasic() error {
 	return m.Namespace.Validate()
 }
 
+func (m *UpdateParamsRequest) ValidateBasic() error {
+	if _, err := sdk.AccAddressFromBech32(m.Authority); err != nil {
+		return err
+	}
+	return m.Params.Validate()
+}
+
 func (ns *Namespace) Validate() error {
 	if ns.ShardName == "" {
 		return sdkerrors.ErrInvalidRequest.Wrap("shard name cannot be empty")
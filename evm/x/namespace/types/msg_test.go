@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/btcsuite/btcutil/bech32"
+	sdk "github.com/cosmos/cosmos-sdk/types"
 	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
 	"gotest.tools/v3/assert"
 )
@@ -96,3 +97,69 @@ func TestUpdateNamespaceRequest(t *testing.T) {
 		})
 	}
 }
+
+func TestUpdateParamsRequest(t *testing.T) {
+	validAddr := "cosmos1luyncewxk4lm24k6gqy8y5dxkj0klr4tu0lmnj"
+	testCases := []struct {
+		name   string
+		msg    UpdateParamsRequest
+		expErr error
+	}{
+		{
+			name: "valid",
+			msg: UpdateParamsRequest{
+				Authority: validAddr,
+				Params: Params{
+					AuthorizedAddresses: []string{validAddr},
+					MessageFee:          sdk.NewCoins(sdk.NewInt64Coin("uworld", 10)),
+					NamespaceMessageFees: []NamespaceMessageFee{
+						{Namespace: "foo", Fee: sdk.NewCoins(sdk.NewInt64Coin("uworld", 20))},
+						{Namespace: "free"},
+					},
+					FeeExemptSenders: []string{"0x61d2B2315605660c3855C8BE139B82e0635E13E3"},
+				},
+			},
+		},
+		{
+			name:   "invalid authorized address",
+			msg:    UpdateParamsRequest{Authority: validAddr, Params: Params{AuthorizedAddresses: []string{"blah"}}},
+			expErr: sdkerrors.ErrInvalidAddress,
+		},
+		{
+			name: "unsorted fee",
+			msg: UpdateParamsRequest{
+				Authority: validAddr,
+				Params: Params{
+					MessageFee: sdk.Coins{sdk.NewInt64Coin("uworld", 1), sdk.NewInt64Coin("stake", 1)},
+				},
+			},
+			expErr: sdkerrors.ErrInvalidCoins,
+		},
+		{
+			name: "duplicate namespace fee",
+			msg: UpdateParamsRequest{
+				Authority: validAddr,
+				Params: Params{
+					NamespaceMessageFees: []NamespaceMessageFee{{Namespace: "foo"}, {Namespace: "foo"}},
+				},
+			},
+			expErr: sdkerrors.ErrInvalidRequest,
+		},
+		{
+			name:   "invalid fee exempt sender",
+			msg:    UpdateParamsRequest{Authority: validAddr, Params: Params{FeeExemptSenders: []string{validAddr}}},
+			expErr: sdkerrors.ErrInvalidAddress,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.msg.ValidateBasic()
+			if tc.expErr != nil {
+				assert.ErrorContains(t, err, tc.expErr.Error())
+			} else {
+				assert.NilError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package types
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// DefaultParams returns the parameters of a new chain: messages to game shards are free, and only the sequencer and
+// the governance module may register namespaces.
+func DefaultParams() Params {
+	return Params{}
+}
+
+func (p Params) Validate() error {
+	for _, addr := range p.AuthorizedAddresses {
+		if _, err := sdk.AccAddressFromBech32(addr); err != nil {
+			return sdkerrors.ErrInvalidAddress.Wrapf("invalid authorized address %q: %v", addr, err)
+		}
+	}
+	if err := p.MessageFee.Validate(); err != nil {
+		return sdkerrors.ErrInvalidCoins.Wrapf("invalid message fee: %v", err)
+	}
+	seen := make(map[string]bool, len(p.NamespaceMessageFees))
+	for _, nsFee := range p.NamespaceMessageFees {
+		if nsFee.Namespace == "" {
+			return sdkerrors.ErrInvalidRequest.Wrap("namespace of a message fee cannot be empty")
+		}
+		if seen[nsFee.Namespace] {
+			return sdkerrors.ErrInvalidRequest.Wrapf("duplicate message fee for namespace %q", nsFee.Namespace)
+		}
+		seen[nsFee.Namespace] = true
+		if err := nsFee.Fee.Validate(); err != nil {
+			return sdkerrors.ErrInvalidCoins.Wrapf("invalid message fee for namespace %q: %v", nsFee.Namespace, err)
+		}
+	}
+	for _, addr := range p.FeeExemptSenders {
+		if !common.IsHexAddress(addr) {
+			return sdkerrors.ErrInvalidAddress.Wrapf("invalid fee exempt sender %q: must be a hex address", addr)
+		}
+	}
+	return nil
+}
+
+// IsAuthorized returns whether the given bech32 address is one of the authorized addresses.
+func (p Params) IsAuthorized(addr string) bool {
+	for _, authorized := range p.AuthorizedAddresses {
+		if authorized == addr {
+			return true
+		}
+	}
+	return false
+}
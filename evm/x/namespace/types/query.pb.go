@@ -34,6 +34,11 @@ type Namespace struct {
 	ShardName string `protobuf:"bytes,1,opt,name=shard_name,json=shardName,proto3" json:"shard_name,omitempty"`
 	// shard_address is the gRPC address the shard runs at (i.e. 127.0.0.1:51835)
 	ShardAddress string `protobuf:"bytes,2,opt,name=shard_address,json=shardAddress,proto3" json:"shard_address,omitempty"`
+	// genesis_tick is the tick the shard was at when it first registered. re-registering the shard does not change it.
+	GenesisTick uint64 `protobuf:"varint,3,opt,name=genesis_tick,json=genesisTick,proto3" json:"genesis_tick,omitempty"`
+	// schema_hash is a hash of the messages and queries the shard exposes to the EVM. it changes whenever a shard is
+	// deployed with a different set of messages or queries, or with changed message or query types.
+	SchemaHash string `protobuf:"bytes,4,opt,name=schema_hash,json=schemaHash,proto3" json:"schema_hash,omitempty"`
 }
 
 func (m *Namespace) Reset()         { *m = Namespace{} }
@@ -83,6 +88,20 @@ func (m *Namespace) GetShardAddress() string {
 	return ""
 }
 
+func (m *Namespace) GetGenesisTick() uint64 {
+	if m != nil {
+		return m.GenesisTick
+	}
+	return 0
+}
+
+func (m *Namespace) GetSchemaHash() string {
+	if m != nil {
+		return m.SchemaHash
+	}
+	return ""
+}
+
 type NamespacesRequest struct {
 }
 
@@ -251,43 +270,132 @@ func (m *AddressResponse) GetAddress() string {
 	return ""
 }
 
+type ParamsRequest struct {
+}
+
+func (m *ParamsRequest) Reset()         { *m = ParamsRequest{} }
+func (m *ParamsRequest) String() string { return proto.CompactTextString(m) }
+func (*ParamsRequest) ProtoMessage()    {}
+func (*ParamsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8bd337073aa47f1a, []int{5}
+}
+func (m *ParamsRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ParamsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ParamsRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *ParamsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ParamsRequest.Merge(m, src)
+}
+func (m *ParamsRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *ParamsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ParamsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ParamsRequest proto.InternalMessageInfo
+
+type ParamsResponse struct {
+	Params Params `protobuf:"bytes,1,opt,name=params,proto3" json:"params"`
+}
+
+func (m *ParamsResponse) Reset()         { *m = ParamsResponse{} }
+func (m *ParamsResponse) String() string { return proto.CompactTextString(m) }
+func (*ParamsResponse) ProtoMessage()    {}
+func (*ParamsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8bd337073aa47f1a, []int{6}
+}
+func (m *ParamsResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ParamsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ParamsResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *ParamsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ParamsResponse.Merge(m, src)
+}
+func (m *ParamsResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *ParamsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ParamsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ParamsResponse proto.InternalMessageInfo
+
+func (m *ParamsResponse) GetParams() Params {
+	if m != nil {
+		return m.Params
+	}
+	return Params{}
+}
+
 func init() {
 	proto.RegisterType((*Namespace)(nil), "namespace.v1.Namespace")
 	proto.RegisterType((*NamespacesRequest)(nil), "namespace.v1.NamespacesRequest")
 	proto.RegisterType((*NamespacesResponse)(nil), "namespace.v1.NamespacesResponse")
 	proto.RegisterType((*AddressRequest)(nil), "namespace.v1.AddressRequest")
 	proto.RegisterType((*AddressResponse)(nil), "namespace.v1.AddressResponse")
+	proto.RegisterType((*ParamsRequest)(nil), "namespace.v1.ParamsRequest")
+	proto.RegisterType((*ParamsResponse)(nil), "namespace.v1.ParamsResponse")
 }
 
 func init() { proto.RegisterFile("namespace/v1/query.proto", fileDescriptor_8bd337073aa47f1a) }
 
 var fileDescriptor_8bd337073aa47f1a = []byte{
-	// 389 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x52, 0x4d, 0x4b, 0xeb, 0x40,
-	0x14, 0x6d, 0xfa, 0xe0, 0x95, 0xdc, 0xd7, 0xa7, 0x38, 0x0a, 0x86, 0xd0, 0xc6, 0x12, 0x11, 0x0b,
-	0xd2, 0x0c, 0x6d, 0x17, 0xe2, 0x52, 0xd7, 0x7e, 0x60, 0xdd, 0xb9, 0x29, 0x63, 0x33, 0xa4, 0xc1,
-	0x3a, 0x93, 0x66, 0xd2, 0x68, 0x11, 0x05, 0xfd, 0x05, 0xa2, 0x7f, 0xca, 0x65, 0xc1, 0x8d, 0x4b,
-	0x69, 0xfd, 0x21, 0x92, 0xaf, 0x69, 0xba, 0x28, 0xb8, 0xbb, 0x39, 0xe7, 0xe4, 0x9c, 0x73, 0x6f,
-	0x02, 0x1a, 0x23, 0x37, 0x54, 0x78, 0xa4, 0x47, 0x71, 0xd8, 0xc4, 0xc3, 0x11, 0xf5, 0xc7, 0x96,
-	0xe7, 0xf3, 0x80, 0xa3, 0xb2, 0x64, 0xac, 0xb0, 0xa9, 0x6f, 0x38, 0xdc, 0xe1, 0x31, 0x81, 0xa3,
-	0x29, 0xd1, 0xe8, 0x15, 0x87, 0x73, 0x67, 0x40, 0x31, 0xf1, 0x5c, 0x4c, 0x18, 0xe3, 0x01, 0x09,
-	0x5c, 0xce, 0x44, 0xc2, 0x9a, 0x67, 0xa0, 0x9e, 0x66, 0x1e, 0xa8, 0x0a, 0x20, 0xfa, 0xc4, 0xb7,
-	0xbb, 0x91, 0xad, 0xa6, 0xd4, 0x94, 0xba, 0xda, 0x51, 0x63, 0x24, 0xd2, 0xa0, 0x6d, 0xf8, 0x9f,
-	0xd0, 0xc4, 0xb6, 0x7d, 0x2a, 0x84, 0x56, 0x8c, 0x15, 0xe5, 0x18, 0x3c, 0x4c, 0x30, 0x73, 0x1d,
-	0xd6, 0xa4, 0xa1, 0xe8, 0xd0, 0xe1, 0x88, 0x8a, 0xc0, 0x3c, 0x01, 0x94, 0x07, 0x85, 0xc7, 0x99,
-	0xa0, 0x68, 0x1f, 0x40, 0xf6, 0x17, 0x9a, 0x52, 0xfb, 0x53, 0xff, 0xd7, 0xda, 0xb4, 0xf2, 0x2b,
-	0x59, 0xf2, 0xad, 0x4e, 0x4e, 0x6a, 0x5a, 0xb0, 0x92, 0xc6, 0xa5, 0x01, 0xa8, 0x02, 0xaa, 0xe4,
-	0xb3, 0xe2, 0x12, 0x30, 0xf7, 0x60, 0x55, 0xea, 0xd3, 0x6c, 0x0d, 0x4a, 0xd9, 0x16, 0x89, 0x3c,
-	0x7b, 0x6c, 0xbd, 0x16, 0xa1, 0x7c, 0x1e, 0xdd, 0xf8, 0x82, 0xfa, 0xa1, 0xdb, 0xa3, 0xe8, 0x11,
-	0x60, 0x5e, 0x1e, 0x6d, 0x2d, 0x29, 0x98, 0x55, 0xd1, 0x6b, 0xcb, 0x05, 0x49, 0xb6, 0xd9, 0x78,
-	0xfe, 0xf8, 0x7e, 0x2b, 0xee, 0xa2, 0x1d, 0x7c, 0xcb, 0xfd, 0x81, 0xdd, 0xa5, 0xcc, 0x71, 0x19,
-	0xc5, 0x0b, 0x5f, 0x79, 0xbe, 0x2d, 0x7a, 0x52, 0xa0, 0x94, 0xd6, 0x47, 0x95, 0x45, 0xf3, 0xc5,
-	0x2b, 0xe8, 0xd5, 0x25, 0x6c, 0x9a, 0x7b, 0x10, 0xe7, 0xb6, 0x51, 0xf3, 0x57, 0xb9, 0xf8, 0x5e,
-	0xce, 0x0f, 0x47, 0xc7, 0xef, 0x53, 0x43, 0x99, 0x4c, 0x0d, 0xe5, 0x6b, 0x6a, 0x28, 0x2f, 0x33,
-	0xa3, 0x30, 0x99, 0x19, 0x85, 0xcf, 0x99, 0x51, 0xb8, 0x6c, 0x79, 0xd7, 0x8e, 0x15, 0xfb, 0x59,
-	0x36, 0x0d, 0x13, 0xe7, 0x46, 0xea, 0xdc, 0xeb, 0x13, 0x97, 0xe1, 0xbb, 0x5c, 0x42, 0x30, 0xf6,
-	0xa8, 0xb8, 0xfa, 0x1b, 0xff, 0x7b, 0xed, 0x9f, 0x00, 0x00, 0x00, 0xff, 0xff, 0x24, 0x82, 0x39,
-	0xb7, 0xd9, 0x02, 0x00, 0x00,
+	// 508 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x53, 0xcd, 0x6e, 0xd3, 0x40,
+	0x18, 0x8c, 0xdb, 0x28, 0x55, 0xbe, 0xa4, 0xad, 0x58, 0x2a, 0x61, 0x42, 0xea, 0xa4, 0xae, 0x10,
+	0x41, 0xa8, 0x5e, 0xc5, 0x3d, 0x20, 0x8e, 0x54, 0x1c, 0x38, 0x00, 0x02, 0xc3, 0x89, 0x4b, 0xb4,
+	0xd8, 0x2b, 0xdb, 0x4a, 0xb3, 0xeb, 0x7a, 0xdd, 0x40, 0x85, 0x40, 0xa2, 0x4f, 0x80, 0xd4, 0x97,
+	0xea, 0xb1, 0x12, 0x17, 0x4e, 0x08, 0x25, 0x3c, 0x08, 0xca, 0xfe, 0xb8, 0xb1, 0xd4, 0x20, 0x6e,
+	0xce, 0xcc, 0x7c, 0xdf, 0xcc, 0xce, 0x6e, 0xc0, 0x66, 0x64, 0x42, 0x45, 0x46, 0x42, 0x8a, 0xa7,
+	0x43, 0x7c, 0x72, 0x4a, 0xf3, 0x33, 0x2f, 0xcb, 0x79, 0xc1, 0x51, 0xbb, 0x64, 0xbc, 0xe9, 0xb0,
+	0xb3, 0x13, 0xf3, 0x98, 0x4b, 0x02, 0x2f, 0xbe, 0x94, 0xa6, 0xd3, 0x8d, 0x39, 0x8f, 0x8f, 0x29,
+	0x26, 0x59, 0x8a, 0x09, 0x63, 0xbc, 0x20, 0x45, 0xca, 0x99, 0xd0, 0xec, 0xdd, 0xca, 0xee, 0x8c,
+	0xe4, 0x64, 0xa2, 0x29, 0xf7, 0xc2, 0x82, 0xe6, 0x2b, 0xc3, 0xa2, 0x5d, 0x00, 0x91, 0x90, 0x3c,
+	0x1a, 0x2d, 0x06, 0x6c, 0xab, 0x6f, 0x0d, 0x9a, 0x41, 0x53, 0x22, 0x0b, 0x0d, 0xda, 0x87, 0x4d,
+	0x45, 0x93, 0x28, 0xca, 0xa9, 0x10, 0xf6, 0x9a, 0x54, 0xb4, 0x25, 0xf8, 0x54, 0x61, 0x68, 0x0f,
+	0xda, 0x31, 0x65, 0x54, 0xa4, 0x62, 0x54, 0xa4, 0xe1, 0xd8, 0x5e, 0xef, 0x5b, 0x83, 0x7a, 0xd0,
+	0xd2, 0xd8, 0xbb, 0x34, 0x1c, 0xa3, 0x1e, 0xb4, 0x44, 0x98, 0xd0, 0x09, 0x19, 0x25, 0x44, 0x24,
+	0x76, 0x5d, 0x6e, 0x01, 0x05, 0x3d, 0x27, 0x22, 0x71, 0x6f, 0xc3, 0xad, 0x32, 0x94, 0x08, 0xe8,
+	0xc9, 0x29, 0x15, 0x85, 0xfb, 0x12, 0xd0, 0x32, 0x28, 0x32, 0xce, 0x04, 0x45, 0x8f, 0x01, 0xca,
+	0xd3, 0x09, 0xdb, 0xea, 0xaf, 0x0f, 0x5a, 0xfe, 0x1d, 0x6f, 0xb9, 0x32, 0xaf, 0x9c, 0x0a, 0x96,
+	0xa4, 0xae, 0x07, 0x5b, 0x3a, 0xb2, 0x36, 0x40, 0x5d, 0x68, 0x96, 0xbc, 0x39, 0x7c, 0x09, 0xb8,
+	0x8f, 0x60, 0xbb, 0xd4, 0x6b, 0x6f, 0x1b, 0x36, 0x4c, 0x13, 0x4a, 0x6e, 0x7e, 0xba, 0xdb, 0xb0,
+	0xf9, 0x5a, 0xd6, 0x6c, 0xc2, 0x3f, 0x83, 0x2d, 0x03, 0xe8, 0x61, 0x1f, 0x1a, 0xea, 0x26, 0xe4,
+	0x6c, 0xcb, 0xdf, 0xa9, 0x86, 0x56, 0xea, 0xa3, 0xfa, 0xe5, 0xaf, 0x5e, 0x2d, 0xd0, 0x4a, 0xff,
+	0x7c, 0x1d, 0xda, 0x6f, 0x16, 0x4f, 0xe3, 0x2d, 0xcd, 0xa7, 0x69, 0x48, 0xd1, 0x57, 0x80, 0xeb,
+	0x4e, 0x50, 0x6f, 0xc5, 0xb9, 0x4d, 0x8a, 0x4e, 0x7f, 0xb5, 0x40, 0xa5, 0x72, 0x0f, 0xce, 0x7f,
+	0xfc, 0xb9, 0x58, 0x7b, 0x80, 0xee, 0xe3, 0x8f, 0x3c, 0x3f, 0x8e, 0x46, 0x94, 0xc5, 0x29, 0xa3,
+	0xb8, 0xf2, 0x80, 0xd8, 0xb5, 0xe3, 0x37, 0x0b, 0x36, 0xcc, 0xc5, 0x77, 0xab, 0xcb, 0xab, 0xe5,
+	0x76, 0x76, 0x57, 0xb0, 0xda, 0xf7, 0x89, 0xf4, 0x3d, 0x44, 0xc3, 0xff, 0xf2, 0xc5, 0x9f, 0xcb,
+	0xef, 0x2f, 0x88, 0x41, 0x43, 0x95, 0x85, 0xee, 0xdd, 0x54, 0xa1, 0x09, 0xd0, 0xbd, 0x99, 0xd4,
+	0xfe, 0x0f, 0xa5, 0xff, 0x3e, 0xda, 0xfb, 0x87, 0xbf, 0xba, 0x84, 0xa3, 0x17, 0x97, 0x33, 0xc7,
+	0xba, 0x9a, 0x39, 0xd6, 0xef, 0x99, 0x63, 0x7d, 0x9f, 0x3b, 0xb5, 0xab, 0xb9, 0x53, 0xfb, 0x39,
+	0x77, 0x6a, 0xef, 0xfd, 0x6c, 0x1c, 0x7b, 0x72, 0xde, 0x8b, 0xe8, 0x54, 0x6d, 0x3a, 0xd0, 0x9b,
+	0xc2, 0x84, 0xa4, 0x0c, 0x7f, 0x5a, 0xda, 0x58, 0x9c, 0x65, 0x54, 0x7c, 0x68, 0xc8, 0xff, 0xe1,
+	0xe1, 0xdf, 0x01, 0x00, 0xca, 0xd8, 0xd7, 0xc1, 0x00, 0x04, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -306,6 +414,8 @@ type QueryServiceClient interface {
 	Namespaces(ctx context.Context, in *NamespacesRequest, opts ...grpc.CallOption) (*NamespacesResponse, error)
 	// Address returns the address for a given namespace.
 	Address(ctx context.Context, in *AddressRequest, opts ...grpc.CallOption) (*AddressResponse, error)
+	// Params returns the parameters of the router.
+	Params(ctx context.Context, in *ParamsRequest, opts ...grpc.CallOption) (*ParamsResponse, error)
 }
 
 type queryServiceClient struct {
@@ -334,12 +444,23 @@ func (c *queryServiceClient) Address(ctx context.Context, in *AddressRequest, op
 	return out, nil
 }
 
+func (c *queryServiceClient) Params(ctx context.Context, in *ParamsRequest, opts ...grpc.CallOption) (*ParamsResponse, error) {
+	out := new(ParamsResponse)
+	err := c.cc.Invoke(ctx, "/namespace.v1.QueryService/Params", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // QueryServiceServer is the server API for QueryService service.
 type QueryServiceServer interface {
 	// Namespaces returns all the namespace:address pairs.
 	Namespaces(context.Context, *NamespacesRequest) (*NamespacesResponse, error)
 	// Address returns the address for a given namespace.
 	Address(context.Context, *AddressRequest) (*AddressResponse, error)
+	// Params returns the parameters of the router.
+	Params(context.Context, *ParamsRequest) (*ParamsResponse, error)
 }
 
 // UnimplementedQueryServiceServer can be embedded to have forward compatible implementations.
@@ -352,6 +473,9 @@ func (*UnimplementedQueryServiceServer) Namespaces(ctx context.Context, req *Nam
 func (*UnimplementedQueryServiceServer) Address(ctx context.Context, req *AddressRequest) (*AddressResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Address not implemented")
 }
+func (*UnimplementedQueryServiceServer) Params(ctx context.Context, req *ParamsRequest) (*ParamsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Params not implemented")
+}
 
 func RegisterQueryServiceServer(s grpc1.Server, srv QueryServiceServer) {
 	s.RegisterService(&_QueryService_serviceDesc, srv)
@@ -393,6 +517,24 @@ func _QueryService_Address_Handler(srv interface{}, ctx context.Context, dec fun
 	return interceptor(ctx, in, info, handler)
 }
 
+func _QueryService_Params_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ParamsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServiceServer).Params(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/namespace.v1.QueryService/Params",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServiceServer).Params(ctx, req.(*ParamsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _QueryService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "namespace.v1.QueryService",
 	HandlerType: (*QueryServiceServer)(nil),
@@ -405,6 +547,10 @@ var _QueryService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Address",
 			Handler:    _QueryService_Address_Handler,
 		},
+		{
+			MethodName: "Params",
+			Handler:    _QueryService_Params_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "namespace/v1/query.proto",
@@ -430,6 +576,18 @@ func (m *Namespace) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.SchemaHash) > 0 {
+		i -= len(m.SchemaHash)
+		copy(dAtA[i:], m.SchemaHash)
+		i = encodeVarintQuery(dAtA, i, uint64(len(m.SchemaHash)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if m.GenesisTick != 0 {
+		i = encodeVarintQuery(dAtA, i, uint64(m.GenesisTick))
+		i--
+		dAtA[i] = 0x18
+	}
 	if len(m.ShardAddress) > 0 {
 		i -= len(m.ShardAddress)
 		copy(dAtA[i:], m.ShardAddress)
@@ -567,6 +725,62 @@ func (m *AddressResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *ParamsRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ParamsRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ParamsRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	return len(dAtA) - i, nil
+}
+
+func (m *ParamsResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ParamsResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ParamsResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	{
+		size, err := m.Params.MarshalToSizedBuffer(dAtA[:i])
+		if err != nil {
+			return 0, err
+		}
+		i -= size
+		i = encodeVarintQuery(dAtA, i, uint64(size))
+	}
+	i--
+	dAtA[i] = 0xa
+	return len(dAtA) - i, nil
+}
+
 func encodeVarintQuery(dAtA []byte, offset int, v uint64) int {
 	offset -= sovQuery(v)
 	base := offset
@@ -592,6 +806,13 @@ func (m *Namespace) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovQuery(uint64(l))
 	}
+	if m.GenesisTick != 0 {
+		n += 1 + sovQuery(uint64(m.GenesisTick))
+	}
+	l = len(m.SchemaHash)
+	if l > 0 {
+		n += 1 + l + sovQuery(uint64(l))
+	}
 	return n
 }
 
@@ -645,6 +866,26 @@ func (m *AddressResponse) Size() (n int) {
 	return n
 }
 
+func (m *ParamsRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	return n
+}
+
+func (m *ParamsResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = m.Params.Size()
+	n += 1 + l + sovQuery(uint64(l))
+	return n
+}
+
 func sovQuery(x uint64) (n int) {
 	return (math_bits.Len64(x|1) + 6) / 7
 }
@@ -744,6 +985,57 @@ func (m *Namespace) Unmarshal(dAtA []byte) error {
 			}
 			m.ShardAddress = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field GenesisTick", wireType)
+			}
+			m.GenesisTick = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.GenesisTick |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SchemaHash", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.SchemaHash = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipQuery(dAtA[iNdEx:])
@@ -1063,6 +1355,139 @@ func (m *AddressResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *ParamsRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowQuery
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ParamsRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ParamsRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		default:
+			iNdEx = preIndex
+			skippy, err := skipQuery(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ParamsResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowQuery
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ParamsResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ParamsResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Params", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.Params.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipQuery(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func skipQuery(dAtA []byte) (n int, err error) {
 	l := len(dAtA)
 	iNdEx := 0
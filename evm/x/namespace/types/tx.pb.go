@@ -121,35 +121,135 @@ func (m *UpdateNamespaceResponse) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_UpdateNamespaceResponse proto.InternalMessageInfo
 
+// `UpdateParamsRequest` is the Msg/UpdateParams request type.
+type UpdateParamsRequest struct {
+	// authority is the address of the governance module.
+	Authority string `protobuf:"bytes,1,opt,name=authority,proto3" json:"authority,omitempty"`
+	// params are the new parameters. all of the parameters must be set, as they replace the current ones.
+	Params Params `protobuf:"bytes,2,opt,name=params,proto3" json:"params"`
+}
+
+func (m *UpdateParamsRequest) Reset()         { *m = UpdateParamsRequest{} }
+func (m *UpdateParamsRequest) String() string { return proto.CompactTextString(m) }
+func (*UpdateParamsRequest) ProtoMessage()    {}
+func (*UpdateParamsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_79ea26741d52287d, []int{2}
+}
+func (m *UpdateParamsRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *UpdateParamsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_UpdateParamsRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *UpdateParamsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateParamsRequest.Merge(m, src)
+}
+func (m *UpdateParamsRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *UpdateParamsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateParamsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpdateParamsRequest proto.InternalMessageInfo
+
+func (m *UpdateParamsRequest) GetAuthority() string {
+	if m != nil {
+		return m.Authority
+	}
+	return ""
+}
+
+func (m *UpdateParamsRequest) GetParams() Params {
+	if m != nil {
+		return m.Params
+	}
+	return Params{}
+}
+
+// `UpdateParamsResponse` defines the response structure for executing a UpdateParamsRequest message.
+type UpdateParamsResponse struct {
+}
+
+func (m *UpdateParamsResponse) Reset()         { *m = UpdateParamsResponse{} }
+func (m *UpdateParamsResponse) String() string { return proto.CompactTextString(m) }
+func (*UpdateParamsResponse) ProtoMessage()    {}
+func (*UpdateParamsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_79ea26741d52287d, []int{3}
+}
+func (m *UpdateParamsResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *UpdateParamsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_UpdateParamsResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *UpdateParamsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateParamsResponse.Merge(m, src)
+}
+func (m *UpdateParamsResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *UpdateParamsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateParamsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpdateParamsResponse proto.InternalMessageInfo
+
 func init() {
 	proto.RegisterType((*UpdateNamespaceRequest)(nil), "namespace.v1.UpdateNamespaceRequest")
 	proto.RegisterType((*UpdateNamespaceResponse)(nil), "namespace.v1.UpdateNamespaceResponse")
+	proto.RegisterType((*UpdateParamsRequest)(nil), "namespace.v1.UpdateParamsRequest")
+	proto.RegisterType((*UpdateParamsResponse)(nil), "namespace.v1.UpdateParamsResponse")
 }
 
 func init() { proto.RegisterFile("namespace/v1/tx.proto", fileDescriptor_79ea26741d52287d) }
 
 var fileDescriptor_79ea26741d52287d = []byte{
-	// 320 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x12, 0xcd, 0x4b, 0xcc, 0x4d,
-	0x2d, 0x2e, 0x48, 0x4c, 0x4e, 0xd5, 0x2f, 0x33, 0xd4, 0x2f, 0xa9, 0xd0, 0x2b, 0x28, 0xca, 0x2f,
-	0xc9, 0x17, 0xe2, 0x81, 0x0b, 0xeb, 0x95, 0x19, 0x4a, 0x89, 0x27, 0xe7, 0x17, 0xe7, 0xe6, 0x17,
-	0xeb, 0xe7, 0x16, 0xa7, 0x83, 0x54, 0xe5, 0x16, 0xa7, 0x43, 0x94, 0x49, 0x49, 0x42, 0x24, 0xe2,
-	0xc1, 0x3c, 0x7d, 0x08, 0x07, 0x2a, 0x25, 0x92, 0x9e, 0x9f, 0x9e, 0x0f, 0x11, 0x07, 0xb1, 0xa0,
-	0xa2, 0x12, 0x28, 0xd6, 0x15, 0x96, 0xa6, 0x16, 0x55, 0x42, 0x64, 0x94, 0xa6, 0x33, 0x72, 0x89,
-	0x85, 0x16, 0xa4, 0x24, 0x96, 0xa4, 0xfa, 0xc1, 0x94, 0x04, 0xa5, 0x16, 0x96, 0xa6, 0x16, 0x97,
-	0x08, 0x99, 0x71, 0x71, 0x26, 0x96, 0x96, 0x64, 0xe4, 0x17, 0x65, 0x96, 0x54, 0x4a, 0x30, 0x2a,
-	0x30, 0x6a, 0x70, 0x3a, 0x49, 0x5c, 0xda, 0xa2, 0x2b, 0x02, 0xb5, 0xcf, 0x31, 0x25, 0xa5, 0x28,
-	0xb5, 0xb8, 0x38, 0xb8, 0xa4, 0x28, 0x33, 0x2f, 0x3d, 0x08, 0xa1, 0x54, 0xc8, 0x94, 0x8b, 0x13,
-	0x6e, 0x9d, 0x04, 0x93, 0x02, 0xa3, 0x06, 0xb7, 0x91, 0xb8, 0x1e, 0xb2, 0xc7, 0xf4, 0x10, 0x56,
-	0x21, 0x54, 0x5a, 0xf1, 0x35, 0x3d, 0xdf, 0xa0, 0x85, 0x30, 0x46, 0x49, 0x92, 0x4b, 0x1c, 0xc3,
-	0x61, 0xc5, 0x05, 0xf9, 0x79, 0xc5, 0xa9, 0x46, 0x39, 0x5c, 0xcc, 0xbe, 0xc5, 0xe9, 0x42, 0x71,
-	0x5c, 0xfc, 0x68, 0x2a, 0x84, 0x54, 0x50, 0x2d, 0xc2, 0xee, 0x33, 0x29, 0x55, 0x02, 0xaa, 0x20,
-	0xd6, 0x48, 0xb1, 0x36, 0x3c, 0xdf, 0xa0, 0xc5, 0xe8, 0xe4, 0x73, 0xe2, 0x91, 0x1c, 0xe3, 0x85,
-	0x47, 0x72, 0x8c, 0x0f, 0x1e, 0xc9, 0x31, 0x4e, 0x78, 0x2c, 0xc7, 0x70, 0xe1, 0xb1, 0x1c, 0xc3,
-	0x8d, 0xc7, 0x72, 0x0c, 0x51, 0x46, 0x05, 0xd9, 0xe9, 0x7a, 0xe5, 0xf9, 0x45, 0x39, 0x29, 0x7a,
-	0x29, 0xa9, 0x65, 0xfa, 0x60, 0x96, 0x6e, 0x6a, 0x5e, 0x7a, 0x66, 0x5e, 0xaa, 0x7e, 0x72, 0x46,
-	0x62, 0x66, 0x9e, 0x7e, 0x85, 0x3e, 0x22, 0xe4, 0x4b, 0x2a, 0x0b, 0x52, 0x8b, 0x93, 0xd8, 0xc0,
-	0xe1, 0x6e, 0x0c, 0x08, 0x00, 0x00, 0xff, 0xff, 0x41, 0x6e, 0x7a, 0x0b, 0x02, 0x02, 0x00, 0x00,
+	// 401 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x92, 0x3f, 0x6f, 0xe2, 0x30,
+	0x18, 0xc6, 0xe3, 0xfb, 0x83, 0x84, 0x0f, 0xdd, 0x49, 0xb9, 0x1c, 0x84, 0x0c, 0x39, 0x2e, 0xba,
+	0x93, 0x10, 0x12, 0xb1, 0xc8, 0xe9, 0x6e, 0xe8, 0x56, 0xe6, 0xb6, 0xaa, 0xa8, 0x58, 0x3a, 0xb4,
+	0x4a, 0x89, 0x65, 0xa2, 0x36, 0x71, 0x88, 0x0d, 0x85, 0xad, 0xea, 0x27, 0x68, 0xa7, 0x7e, 0x0d,
+	0x86, 0x7e, 0x83, 0x2e, 0x8c, 0xa8, 0x53, 0xa7, 0xaa, 0x82, 0x81, 0xaf, 0x51, 0x11, 0x07, 0x42,
+	0x0a, 0x6a, 0x87, 0x6e, 0xb6, 0x9f, 0xe7, 0xf5, 0xf3, 0x7b, 0xed, 0x17, 0xfe, 0xf0, 0x6d, 0x0f,
+	0xb3, 0xc0, 0x6e, 0x61, 0xd4, 0xab, 0x21, 0xde, 0x37, 0x83, 0x90, 0x72, 0x2a, 0xe7, 0x96, 0xc7,
+	0x66, 0xaf, 0xa6, 0x15, 0x5a, 0x94, 0x79, 0x94, 0x21, 0x8f, 0x91, 0xb9, 0xcb, 0x63, 0x44, 0xd8,
+	0xb4, 0xa2, 0x10, 0x8e, 0xa3, 0x1d, 0x12, 0x9b, 0x58, 0x52, 0x08, 0x25, 0x54, 0x9c, 0xcf, 0x57,
+	0x8b, 0x82, 0x54, 0x5c, 0x60, 0x87, 0xb6, 0xb7, 0x28, 0x50, 0x53, 0x52, 0xa7, 0x8b, 0xc3, 0x81,
+	0x50, 0x8c, 0x1b, 0x00, 0xf3, 0xcd, 0xc0, 0xb1, 0x39, 0xde, 0x5b, 0x58, 0x1a, 0xb8, 0xd3, 0xc5,
+	0x8c, 0xcb, 0xff, 0x61, 0xd6, 0xee, 0xf2, 0x36, 0x0d, 0x5d, 0x3e, 0x50, 0x41, 0x09, 0x94, 0xb3,
+	0x75, 0xf5, 0xfe, 0xb6, 0xaa, 0xc4, 0x28, 0xdb, 0x8e, 0x13, 0x62, 0xc6, 0x0e, 0x78, 0xe8, 0xfa,
+	0xa4, 0x91, 0x58, 0xe5, 0x7f, 0x30, 0xbb, 0x8c, 0x53, 0x3f, 0x94, 0x40, 0xf9, 0x8b, 0x55, 0x30,
+	0x57, 0x7b, 0x36, 0x93, 0xa8, 0xc4, 0xb9, 0xf5, 0xf5, 0x72, 0x36, 0xac, 0x24, 0xd7, 0x18, 0x45,
+	0x58, 0x58, 0x03, 0x63, 0x01, 0xf5, 0x19, 0x36, 0xae, 0x01, 0xfc, 0x2e, 0xb4, 0xfd, 0xa8, 0xcb,
+	0xf7, 0x12, 0x5b, 0x30, 0x23, 0x9e, 0x2b, 0xc6, 0x55, 0xd2, 0xb8, 0x22, 0xa4, 0xfe, 0x69, 0xf4,
+	0xf8, 0x53, 0x6a, 0xc4, 0xce, 0x35, 0xdc, 0x3c, 0x54, 0xd2, 0x48, 0x82, 0xd5, 0xba, 0x03, 0xf0,
+	0xe3, 0x2e, 0x23, 0xf2, 0x11, 0xfc, 0xf6, 0xa2, 0x1d, 0xf9, 0x77, 0x3a, 0x66, 0xf3, 0x37, 0x68,
+	0x7f, 0xde, 0x70, 0x89, 0x1c, 0xb9, 0x09, 0x73, 0xab, 0xf9, 0xf2, 0xaf, 0x4d, 0x65, 0xa9, 0xe7,
+	0xd2, 0x8c, 0xd7, 0x2c, 0xe2, 0x5a, 0xed, 0xf3, 0xc5, 0x6c, 0x58, 0x01, 0xf5, 0x9d, 0xd1, 0x44,
+	0x07, 0xe3, 0x89, 0x0e, 0x9e, 0x26, 0x3a, 0xb8, 0x9a, 0xea, 0xd2, 0x78, 0xaa, 0x4b, 0x0f, 0x53,
+	0x5d, 0x3a, 0xb4, 0x82, 0x53, 0x62, 0x9e, 0xd3, 0xf0, 0xcc, 0x31, 0x1d, 0xdc, 0x43, 0xd1, 0xaa,
+	0x8a, 0x7d, 0xe2, 0xfa, 0x18, 0xb5, 0xda, 0xb6, 0xeb, 0xa3, 0x3e, 0x4a, 0xa6, 0x8f, 0x0f, 0x02,
+	0xcc, 0x4e, 0x32, 0xd1, 0xec, 0xfd, 0x7d, 0x1e, 0x00, 0x7e, 0x9e, 0x76, 0x9b, 0x21, 0x03, 0x00,
+	0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -165,6 +265,8 @@ const _ = grpc.SupportPackageIsVersion4
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
 type MsgClient interface {
 	UpdateNamespace(ctx context.Context, in *UpdateNamespaceRequest, opts ...grpc.CallOption) (*UpdateNamespaceResponse, error)
+	// UpdateParams replaces the parameters of the router. It is executed by governance proposals.
+	UpdateParams(ctx context.Context, in *UpdateParamsRequest, opts ...grpc.CallOption) (*UpdateParamsResponse, error)
 }
 
 type msgClient struct {
@@ -184,9 +286,20 @@ func (c *msgClient) UpdateNamespace(ctx context.Context, in *UpdateNamespaceRequ
 	return out, nil
 }
 
+func (c *msgClient) UpdateParams(ctx context.Context, in *UpdateParamsRequest, opts ...grpc.CallOption) (*UpdateParamsResponse, error) {
+	out := new(UpdateParamsResponse)
+	err := c.cc.Invoke(ctx, "/namespace.v1.Msg/UpdateParams", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // MsgServer is the server API for Msg service.
 type MsgServer interface {
 	UpdateNamespace(context.Context, *UpdateNamespaceRequest) (*UpdateNamespaceResponse, error)
+	// UpdateParams replaces the parameters of the router. It is executed by governance proposals.
+	UpdateParams(context.Context, *UpdateParamsRequest) (*UpdateParamsResponse, error)
 }
 
 // UnimplementedMsgServer can be embedded to have forward compatible implementations.
@@ -196,6 +309,9 @@ type UnimplementedMsgServer struct {
 func (*UnimplementedMsgServer) UpdateNamespace(ctx context.Context, req *UpdateNamespaceRequest) (*UpdateNamespaceResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UpdateNamespace not implemented")
 }
+func (*UnimplementedMsgServer) UpdateParams(ctx context.Context, req *UpdateParamsRequest) (*UpdateParamsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateParams not implemented")
+}
 
 func RegisterMsgServer(s grpc1.Server, srv MsgServer) {
 	s.RegisterService(&_Msg_serviceDesc, srv)
@@ -219,6 +335,24 @@ func _Msg_UpdateNamespace_Handler(srv interface{}, ctx context.Context, dec func
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Msg_UpdateParams_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateParamsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MsgServer).UpdateParams(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/namespace.v1.Msg/UpdateParams",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MsgServer).UpdateParams(ctx, req.(*UpdateParamsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Msg_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "namespace.v1.Msg",
 	HandlerType: (*MsgServer)(nil),
@@ -227,6 +361,10 @@ var _Msg_serviceDesc = grpc.ServiceDesc{
 			MethodName: "UpdateNamespace",
 			Handler:    _Msg_UpdateNamespace_Handler,
 		},
+		{
+			MethodName: "UpdateParams",
+			Handler:    _Msg_UpdateParams_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "namespace/v1/tx.proto",
@@ -297,6 +435,69 @@ func (m *UpdateNamespaceResponse) MarshalToSizedBuffer(dAtA []byte) (int, error)
 	return len(dAtA) - i, nil
 }
 
+func (m *UpdateParamsRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *UpdateParamsRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *UpdateParamsRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	{
+		size, err := m.Params.MarshalToSizedBuffer(dAtA[:i])
+		if err != nil {
+			return 0, err
+		}
+		i -= size
+		i = encodeVarintTx(dAtA, i, uint64(size))
+	}
+	i--
+	dAtA[i] = 0x12
+	if len(m.Authority) > 0 {
+		i -= len(m.Authority)
+		copy(dAtA[i:], m.Authority)
+		i = encodeVarintTx(dAtA, i, uint64(len(m.Authority)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *UpdateParamsResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *UpdateParamsResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *UpdateParamsResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	return len(dAtA) - i, nil
+}
+
 func encodeVarintTx(dAtA []byte, offset int, v uint64) int {
 	offset -= sovTx(v)
 	base := offset
@@ -334,6 +535,30 @@ func (m *UpdateNamespaceResponse) Size() (n int) {
 	return n
 }
 
+func (m *UpdateParamsRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Authority)
+	if l > 0 {
+		n += 1 + l + sovTx(uint64(l))
+	}
+	l = m.Params.Size()
+	n += 1 + l + sovTx(uint64(l))
+	return n
+}
+
+func (m *UpdateParamsResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	return n
+}
+
 func sovTx(x uint64) (n int) {
 	return (math_bits.Len64(x|1) + 6) / 7
 }
@@ -508,6 +733,171 @@ func (m *UpdateNamespaceResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *UpdateParamsRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTx
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: UpdateParamsRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: UpdateParamsRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Authority", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTx
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTx
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Authority = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Params", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTx
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTx
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.Params.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTx(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTx
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *UpdateParamsResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTx
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: UpdateParamsResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: UpdateParamsResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTx(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTx
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func skipTx(dAtA []byte) (n int, err error) {
 	l := len(dAtA)
 	iNdEx := 0
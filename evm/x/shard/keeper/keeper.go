@@ -37,6 +37,20 @@ func (k *Keeper) InitGenesis(ctx sdk.Context, genesis *types.GenesisState) {
 			}
 		}
 	}
+	for _, nsRoots := range genesis.NamespaceStateRoots {
+		for _, root := range nsRoots.StateRoots {
+			if err := k.saveStateRoot(ctx, nsRoots.Namespace, root); err != nil {
+				panic(err)
+			}
+		}
+	}
+	for _, nsMsgs := range genesis.NamespaceRoutedMessages {
+		for _, msg := range nsMsgs.RoutedMessages {
+			if err := k.SaveRoutedMessage(ctx, nsMsgs.Namespace, msg); err != nil {
+				panic(err)
+			}
+		}
+	}
 }
 
 func (k *Keeper) ExportGenesis(ctx sdk.Context) *types.GenesisState {
@@ -53,6 +67,24 @@ func (k *Keeper) ExportGenesis(ctx sdk.Context) *types.GenesisState {
 		res.NamespaceTransactions = append(res.NamespaceTransactions, nstxs)
 		return true
 	})
+	k.iterateNamespaceStateRoots(ctx, func(ns string, root *types.StateRoot) bool {
+		last := len(res.NamespaceStateRoots) - 1
+		if last < 0 || res.NamespaceStateRoots[last].Namespace != ns {
+			res.NamespaceStateRoots = append(res.NamespaceStateRoots, &types.NamespaceStateRoots{Namespace: ns})
+			last++
+		}
+		res.NamespaceStateRoots[last].StateRoots = append(res.NamespaceStateRoots[last].StateRoots, root)
+		return true
+	})
+	k.iterateNamespaceRoutedMessages(ctx, func(ns string, msg *types.RoutedMessage) bool {
+		last := len(res.NamespaceRoutedMessages) - 1
+		if last < 0 || res.NamespaceRoutedMessages[last].Namespace != ns {
+			res.NamespaceRoutedMessages = append(res.NamespaceRoutedMessages, &types.NamespaceRoutedMessages{Namespace: ns})
+			last++
+		}
+		res.NamespaceRoutedMessages[last].RoutedMessages = append(res.NamespaceRoutedMessages[last].RoutedMessages, msg)
+		return true
+	})
 	return res
 }
 
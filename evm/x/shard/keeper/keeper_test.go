@@ -212,6 +212,246 @@ func (s *TestSuite) TestExportGenesis() {
 	})
 }
 
+func (s *TestSuite) TestUndeliveredMessages() {
+	s.keeper.SaveUndeliveredMessage(s.ctx, "foo", "0x02", []byte("second"))
+	s.keeper.SaveUndeliveredMessage(s.ctx, "foo", "0x01", []byte("first"))
+	s.keeper.SaveUndeliveredMessage(s.ctx, "foobar", "0x03", []byte("other"))
+
+	msgs := s.keeper.TakeUndeliveredMessages(s.ctx, "foo")
+	s.Require().Equal([][]byte{[]byte("first"), []byte("second")}, msgs)
+	s.Require().Empty(s.keeper.TakeUndeliveredMessages(s.ctx, "foo"))
+	s.Require().Len(s.keeper.TakeUndeliveredMessages(s.ctx, "foobar"), 1)
+
+	// undelivered messages are not transactions of the namespace.
+	s.Require().Empty(s.keeper.ExportGenesis(s.ctx).NamespaceTransactions)
+}
+
+func (s *TestSuite) TestOutboxMessages() {
+	contract := []byte("0123456789abcdefghij")
+	other := []byte("abcdefghij0123456789")
+	s.Require().True(s.keeper.SaveOutboxMessage(s.ctx, "foo", 1, contract, []byte("first")))
+	s.Require().True(s.keeper.SaveOutboxMessage(s.ctx, "foo", 2, other, []byte("other")))
+	s.Require().True(s.keeper.SaveOutboxMessage(s.ctx, "foo", 3, contract, []byte("second")))
+	s.Require().True(s.keeper.SaveOutboxMessage(s.ctx, "bar", 1, contract, []byte("bar")))
+	s.Require().Equal(uint64(3), s.keeper.OutboxAckedID(s.ctx, "foo"))
+	s.Require().Equal(uint64(1), s.keeper.OutboxAckedID(s.ctx, "bar"))
+
+	// the game shard delivers messages again until it sees the acknowledgement.
+	s.Require().False(s.keeper.SaveOutboxMessage(s.ctx, "foo", 3, contract, []byte("second")))
+	s.keeper.AckOutboxMessage(s.ctx, "foo", 4)
+	s.Require().False(s.keeper.SaveOutboxMessage(s.ctx, "foo", 4, contract, []byte("dropped")))
+
+	msgs := s.keeper.TakeOutboxMessages(s.ctx, contract, 2)
+	s.Require().Equal([]keeper.OutboxMessage{
+		{Namespace: "bar", Message: []byte("bar")},
+		{Namespace: "foo", Message: []byte("first")},
+	}, msgs)
+	msgs = s.keeper.TakeOutboxMessages(s.ctx, contract, 0)
+	s.Require().Equal([]keeper.OutboxMessage{{Namespace: "foo", Message: []byte("second")}}, msgs)
+	s.Require().Empty(s.keeper.TakeOutboxMessages(s.ctx, contract, 0))
+	s.Require().Len(s.keeper.TakeOutboxMessages(s.ctx, other, 0), 1)
+
+	// taking the messages doesn't reset the acknowledged ID.
+	s.Require().Equal(uint64(4), s.keeper.OutboxAckedID(s.ctx, "foo"))
+}
+
+func (s *TestSuite) TestStateRoots() {
+	submit := func(ns string, start, end uint64) error {
+		root := make([]byte, types.StateRootSize)
+		root[0] = byte(end)
+		msg := &types.SubmitStateRootRequest{
+			Sender:    s.auth,
+			Namespace: ns,
+			StartTick: start,
+			EndTick:   end,
+			StateRoot: root,
+		}
+		s.Require().NoError(msg.ValidateBasic())
+		_, err := s.keeper.SubmitStateRoot(s.ctx.WithBlockHeight(int64(end)), msg)
+		return err
+	}
+	s.Require().NoError(submit("foo", 0, 9))
+	s.Require().NoError(submit("foo", 10, 19))
+	// ticks may be skipped between state roots.
+	s.Require().NoError(submit("foo", 30, 39))
+	s.Require().NoError(submit("foobar", 0, 9))
+	s.Require().ErrorIs(submit("foo", 35, 49), sdkerrors.ErrInvalidRequest)
+	s.Require().ErrorIs(submit("foo", 39, 49), sdkerrors.ErrInvalidRequest)
+
+	res, err := s.keeper.StateRoot(s.ctx, &types.QueryStateRootRequest{Namespace: "foo", Tick: 15})
+	s.Require().NoError(err)
+	s.Require().Equal(uint64(10), res.StateRoot.StartTick)
+	s.Require().Equal(uint64(19), res.StateRoot.EndTick)
+	s.Require().Equal(byte(19), res.StateRoot.StateRoot[0])
+	s.Require().Equal(int64(19), res.StateRoot.BlockHeight)
+	res, err = s.keeper.StateRoot(s.ctx, &types.QueryStateRootRequest{Namespace: "foo", Tick: 39})
+	s.Require().NoError(err)
+	s.Require().Equal(uint64(30), res.StateRoot.StartTick)
+	for _, tick := range []uint64{25, 40} {
+		_, err = s.keeper.StateRoot(s.ctx, &types.QueryStateRootRequest{Namespace: "foo", Tick: tick})
+		s.Require().ErrorIs(err, sdkerrors.ErrNotFound)
+	}
+
+	page, err := s.keeper.StateRoots(s.ctx, &types.QueryStateRootsRequest{
+		Namespace: "foo",
+		Page:      &types.PageRequest{Limit: 2},
+	})
+	s.Require().NoError(err)
+	s.Require().Len(page.StateRoots, 2)
+	s.Require().Equal(uint64(19), page.StateRoots[1].EndTick)
+	page, err = s.keeper.StateRoots(s.ctx, &types.QueryStateRootsRequest{
+		Namespace: "foo",
+		Page:      &types.PageRequest{Key: page.Page.Key, Limit: 2},
+	})
+	s.Require().NoError(err)
+	s.Require().Len(page.StateRoots, 1)
+	s.Require().Equal(uint64(39), page.StateRoots[0].EndTick)
+	s.Require().Nil(page.Page.Key)
+
+	// state roots are exported per namespace, and are not transactions of the namespace.
+	gen := s.keeper.ExportGenesis(s.ctx)
+	s.Require().NoError(gen.Validate())
+	s.Require().Empty(gen.NamespaceTransactions)
+	s.Require().Len(gen.NamespaceStateRoots, 2)
+	s.Require().Equal("foo", gen.NamespaceStateRoots[0].Namespace)
+	s.Require().Len(gen.NamespaceStateRoots[0].StateRoots, 3)
+	s.Require().Equal("foobar", gen.NamespaceStateRoots[1].Namespace)
+	s.Require().Len(gen.NamespaceStateRoots[1].StateRoots, 1)
+}
+
+func (s *TestSuite) TestSubmitStateRoot_Invalid() {
+	msg := &types.SubmitStateRootRequest{
+		Sender:    s.addrs[1].String(),
+		Namespace: "foo",
+		StartTick: 10,
+		EndTick:   19,
+		StateRoot: make([]byte, types.StateRootSize),
+	}
+	_, err := s.keeper.SubmitStateRoot(s.ctx, msg)
+	s.Require().ErrorIs(err, sdkerrors.ErrUnauthorized)
+
+	msg.EndTick = 9
+	s.Require().ErrorIs(msg.ValidateBasic(), sdkerrors.ErrInvalidRequest)
+	msg.EndTick = 19
+	msg.StateRoot = []byte("root")
+	s.Require().ErrorIs(msg.ValidateBasic(), sdkerrors.ErrInvalidRequest)
+}
+
+func (s *TestSuite) TestRoutedMessages() {
+	save := func(ns, evmTxHash, sender string, code uint32, height int64) {
+		s.Require().NoError(s.keeper.SaveRoutedMessage(s.ctx, ns, &types.RoutedMessage{
+			EvmTxHash:   evmTxHash,
+			Sender:      sender,
+			MessageId:   "move",
+			Code:        code,
+			BlockHeight: height,
+		}))
+	}
+	save("foo", "0x03", "0xalice", 0, 12)
+	save("foo", "0x01", "0xalice", 0, 10)
+	save("foo", "0x02", "0xbob", 103, 10)
+	save("foo", "0x04", "0xbob", 0, 15)
+	save("foobar", "0x05", "0xalice", 0, 10)
+
+	query := func(req *types.QueryRoutedMessagesRequest) ([]string, []byte) {
+		res, err := s.keeper.RoutedMessages(s.ctx, req)
+		s.Require().NoError(err)
+		hashes := make([]string, 0, len(res.RoutedMessages))
+		for _, msg := range res.RoutedMessages {
+			hashes = append(hashes, msg.EvmTxHash)
+		}
+		return hashes, res.Page.Key
+	}
+	hashes, _ := query(&types.QueryRoutedMessagesRequest{Namespace: "foo"})
+	s.Require().Equal([]string{"0x01", "0x02", "0x03", "0x04"}, hashes)
+	hashes, _ = query(&types.QueryRoutedMessagesRequest{Namespace: "foo", Sender: "0xBOB"})
+	s.Require().Equal([]string{"0x02", "0x04"}, hashes)
+	hashes, _ = query(&types.QueryRoutedMessagesRequest{
+		Namespace: "foo",
+		Status:    types.RoutedMessageStatus_ROUTED_MESSAGE_STATUS_FAILED,
+	})
+	s.Require().Equal([]string{"0x02"}, hashes)
+	hashes, _ = query(&types.QueryRoutedMessagesRequest{
+		Namespace:  "foo",
+		Status:     types.RoutedMessageStatus_ROUTED_MESSAGE_STATUS_SUCCEEDED,
+		FromHeight: 11,
+	})
+	s.Require().Equal([]string{"0x03", "0x04"}, hashes)
+	hashes, _ = query(&types.QueryRoutedMessagesRequest{Namespace: "foo", FromHeight: 10, ToHeight: 12})
+	s.Require().Equal([]string{"0x01", "0x02", "0x03"}, hashes)
+
+	// pages only count the messages that pass the filters.
+	hashes, key := query(&types.QueryRoutedMessagesRequest{
+		Namespace: "foo",
+		Sender:    "0xalice",
+		Page:      &types.PageRequest{Limit: 1},
+	})
+	s.Require().Equal([]string{"0x01"}, hashes)
+	hashes, key = query(&types.QueryRoutedMessagesRequest{
+		Namespace: "foo",
+		Sender:    "0xalice",
+		Page:      &types.PageRequest{Key: key, Limit: 1},
+	})
+	s.Require().Equal([]string{"0x03"}, hashes)
+	s.Require().Nil(key)
+
+	_, err := s.keeper.RoutedMessages(s.ctx, &types.QueryRoutedMessagesRequest{})
+	s.Require().ErrorIs(err, sdkerrors.ErrInvalidRequest)
+	_, err = s.keeper.RoutedMessages(s.ctx, &types.QueryRoutedMessagesRequest{
+		Namespace:  "foo",
+		FromHeight: 12,
+		ToHeight:   10,
+	})
+	s.Require().ErrorIs(err, sdkerrors.ErrInvalidRequest)
+
+	// routed messages are exported per namespace, and are not transactions of the namespace.
+	gen := s.keeper.ExportGenesis(s.ctx)
+	s.Require().NoError(gen.Validate())
+	s.Require().Empty(gen.NamespaceTransactions)
+	s.Require().Len(gen.NamespaceRoutedMessages, 2)
+	s.Require().Equal("foo", gen.NamespaceRoutedMessages[0].Namespace)
+	s.Require().Len(gen.NamespaceRoutedMessages[0].RoutedMessages, 4)
+	s.Require().Equal("foobar", gen.NamespaceRoutedMessages[1].Namespace)
+	s.Require().Len(gen.NamespaceRoutedMessages[1].RoutedMessages, 1)
+}
+
+func (s *TestSuite) TestRoutedMessagesArePruned() {
+	save := func(evmTxHash, sender string, code uint32, height int64) {
+		s.Require().NoError(s.keeper.SaveRoutedMessage(s.ctx, "foo", &types.RoutedMessage{
+			EvmTxHash:   evmTxHash,
+			Sender:      sender,
+			Code:        code,
+			BlockHeight: height,
+		}))
+	}
+	save("0x01", "0xalice", 0, 1)
+	save("0x02", "0xbob", 103, 2)
+	save("0x03", "0xalice", 103, 3)
+	save("0x04", "0xbob", 0, keeper.RoutedMessageRetention+3)
+
+	query := func(req *types.QueryRoutedMessagesRequest) []string {
+		res, err := s.keeper.RoutedMessages(s.ctx, req)
+		s.Require().NoError(err)
+		hashes := make([]string, 0, len(res.RoutedMessages))
+		for _, msg := range res.RoutedMessages {
+			hashes = append(hashes, msg.EvmTxHash)
+		}
+		return hashes
+	}
+	s.Require().Equal([]string{"0x03", "0x04"}, query(&types.QueryRoutedMessagesRequest{Namespace: "foo"}))
+	// the indexes are pruned with the messages.
+	s.Require().Equal([]string{"0x03"}, query(&types.QueryRoutedMessagesRequest{Namespace: "foo", Sender: "0xalice"}))
+	s.Require().Equal([]string{"0x03"}, query(&types.QueryRoutedMessagesRequest{
+		Namespace: "foo",
+		Status:    types.RoutedMessageStatus_ROUTED_MESSAGE_STATUS_FAILED,
+	}))
+	s.Require().Empty(query(&types.QueryRoutedMessagesRequest{
+		Namespace: "foo",
+		Sender:    "0xbob",
+		Status:    types.RoutedMessageStatus_ROUTED_MESSAGE_STATUS_FAILED,
+	}))
+}
+
 func TestTestSuite(t *testing.T) {
 	suite.Run(t, new(TestSuite))
 }
@@ -31,3 +31,29 @@ func (k *Keeper) SubmitShardTx(
 
 	return &types.SubmitShardTxResponse{}, nil
 }
+
+func (k *Keeper) SubmitStateRoot(
+	ctx context.Context, msg *types.SubmitStateRootRequest,
+) (*types.SubmitStateRootResponse, error) {
+	if msg.Sender != k.auth {
+		return nil, sdkerrors.ErrUnauthorized.Wrapf("SubmitStateRoot is a system function and cannot be called "+
+			"externally. expected %s, got %s", k.auth, msg.Sender)
+	}
+	sdkCtx := sdk.UnwrapSDKContext(ctx)
+
+	if last := k.lastStateRoot(sdkCtx, msg.Namespace); last != nil && msg.StartTick <= last.EndTick {
+		return nil, sdkerrors.ErrInvalidRequest.Wrapf("state root of ticks %d to %d overlaps the last state root of "+
+			"namespace %q, which ends at tick %d", msg.StartTick, msg.EndTick, msg.Namespace, last.EndTick)
+	}
+	err := k.saveStateRoot(sdkCtx, msg.Namespace, &types.StateRoot{
+		StartTick:   msg.StartTick,
+		EndTick:     msg.EndTick,
+		StateRoot:   msg.StateRoot,
+		BlockHeight: sdkCtx.BlockHeight(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.SubmitStateRootResponse{}, nil
+}
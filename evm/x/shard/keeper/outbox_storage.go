@@ -0,0 +1,94 @@
+package keeper
+
+import (
+	"encoding/binary"
+
+	"cosmossdk.io/store/prefix"
+	"github.com/cosmos/cosmos-sdk/runtime"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+var (
+	// outboxStorePrefix and outboxAckedStorePrefix start with a slash, which namespaces can't contain, so their keys
+	// can't fall into the transaction store of a namespace.
+	outboxStorePrefix      = []byte("/outbox/")
+	outboxAckedStorePrefix = []byte("/outbox-acked/")
+)
+
+// OutboxMessage is a message that a game shard system emitted to an EVM contract, as stored on chain.
+type OutboxMessage struct {
+	// Namespace is the namespace of the game shard that emitted the message.
+	Namespace string
+	// Message is the message, as passed to SaveOutboxMessage.
+	Message []byte
+}
+
+// outboxStore retrieves the store for the messages that game shards emitted to the given contract.
+func (k *Keeper) outboxStore(ctx sdk.Context, contract []byte) prefix.Store {
+	store := runtime.KVStoreAdapter(k.storeService.OpenKVStore(ctx))
+	return prefix.NewStore(store, append(append([]byte{}, outboxStorePrefix...), contract...))
+}
+
+// outbox messages of a contract are keyed via the namespace of the game shard that emitted them, followed by their ID,
+// so the messages of every game shard are sorted by ID.
+func (k *Keeper) getOutboxMessageKey(ns string, id uint64) []byte {
+	key := make([]byte, 0, len(ns)+1+uint64Size)
+	key = append(key, ns+"/"...)
+	return binary.BigEndian.AppendUint64(key, id)
+}
+
+func (k *Keeper) outboxAckedKey(ns string) []byte {
+	return append(append([]byte{}, outboxAckedStorePrefix...), ns...)
+}
+
+// OutboxAckedID returns the highest ID of the outbox messages of the namespace that were stored on chain. The game
+// shard can forget the messages up to this ID.
+func (k *Keeper) OutboxAckedID(ctx sdk.Context, ns string) uint64 {
+	store := runtime.KVStoreAdapter(k.storeService.OpenKVStore(ctx))
+	bz := store.Get(k.outboxAckedKey(ns))
+	if bz == nil {
+		return 0
+	}
+	return binary.BigEndian.Uint64(bz)
+}
+
+// AckOutboxMessage raises the acknowledged ID of the namespace to the given ID without storing a message, e.g. for a
+// message that can never be delivered.
+func (k *Keeper) AckOutboxMessage(ctx sdk.Context, ns string, id uint64) {
+	store := runtime.KVStoreAdapter(k.storeService.OpenKVStore(ctx))
+	store.Set(k.outboxAckedKey(ns), binary.BigEndian.AppendUint64(nil, id))
+}
+
+// SaveOutboxMessage stores a message that the game shard of the namespace emitted to the given contract, and
+// acknowledges it. Game shards deliver messages at least once, so the message is dropped if its ID is not higher than
+// the acknowledged ID of the namespace. It returns whether the message was stored.
+func (k *Keeper) SaveOutboxMessage(ctx sdk.Context, ns string, id uint64, contract, msg []byte) bool {
+	if id <= k.OutboxAckedID(ctx, ns) {
+		return false
+	}
+	k.outboxStore(ctx, contract).Set(k.getOutboxMessageKey(ns, id), msg)
+	k.AckOutboxMessage(ctx, ns, id)
+	return true
+}
+
+// TakeOutboxMessages removes and returns up to limit messages destined for the given contract, sorted by namespace and
+// by ID. A limit of 0 takes all messages.
+func (k *Keeper) TakeOutboxMessages(ctx sdk.Context, contract []byte, limit int) []OutboxMessage {
+	store := k.outboxStore(ctx, contract)
+	var keys [][]byte
+	var msgs []OutboxMessage
+	it := store.Iterator(nil, nil)
+	for ; it.Valid() && (limit == 0 || len(msgs) < limit); it.Next() {
+		key := it.Key()
+		keys = append(keys, key)
+		msgs = append(msgs, OutboxMessage{
+			Namespace: string(key[:len(key)-uint64Size-1]),
+			Message:   it.Value(),
+		})
+	}
+	it.Close()
+	for _, key := range keys {
+		store.Delete(key)
+	}
+	return msgs
+}
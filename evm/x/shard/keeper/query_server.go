@@ -1,7 +1,9 @@
 package keeper
 
 import (
+	"bytes"
 	"context"
+	"strings"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
@@ -38,3 +40,91 @@ func (k *Keeper) Transactions(
 	)
 	return &res, nil
 }
+
+func (k *Keeper) StateRoots(
+	ctx context.Context, req *types.QueryStateRootsRequest,
+) (*types.QueryStateRootsResponse, error) {
+	if req.Namespace == "" {
+		return nil, sdkerrors.ErrInvalidRequest.Wrap("namespace required but not supplied")
+	}
+	key, limit := types.ExtractPageRequest(req.Page)
+	res := types.QueryStateRootsResponse{
+		StateRoots: make([]*types.StateRoot, 0, limit),
+		Page:       &types.PageResponse{},
+	}
+	k.iterateStateRoots(sdk.UnwrapSDKContext(ctx), key, nil, req.Namespace, func(root *types.StateRoot) bool {
+		if uint32(len(res.StateRoots)) == limit {
+			res.Page.Key = k.getStateRootKey(root.EndTick)
+			return false
+		}
+		res.StateRoots = append(res.StateRoots, root)
+		return true
+	})
+	return &res, nil
+}
+
+func (k *Keeper) StateRoot(
+	ctx context.Context, req *types.QueryStateRootRequest,
+) (*types.QueryStateRootResponse, error) {
+	if req.Namespace == "" {
+		return nil, sdkerrors.ErrInvalidRequest.Wrap("namespace required but not supplied")
+	}
+	root := k.getStateRoot(sdk.UnwrapSDKContext(ctx), req.Namespace, req.Tick)
+	if root == nil {
+		return nil, sdkerrors.ErrNotFound.Wrapf("no state root of namespace %q covers tick %d", req.Namespace, req.Tick)
+	}
+	return &types.QueryStateRootResponse{StateRoot: root}, nil
+}
+
+func (k *Keeper) RoutedMessages(
+	ctx context.Context, req *types.QueryRoutedMessagesRequest,
+) (*types.QueryRoutedMessagesResponse, error) {
+	if req.Namespace == "" {
+		return nil, sdkerrors.ErrInvalidRequest.Wrap("namespace required but not supplied")
+	}
+	if req.FromHeight < 0 || req.ToHeight < 0 || (req.ToHeight != 0 && req.ToHeight < req.FromHeight) {
+		return nil, sdkerrors.ErrInvalidRequest.Wrapf("invalid block range [%d, %d]", req.FromHeight, req.ToHeight)
+	}
+	key, limit := types.ExtractPageRequest(req.Page)
+	if start := k.getRoutedMessageKey(req.FromHeight, ""); bytes.Compare(key, start) < 0 {
+		key = start
+	}
+	var end []byte
+	if req.ToHeight != 0 {
+		end = k.getRoutedMessageKey(req.ToHeight+1, "")
+	}
+	res := types.QueryRoutedMessagesResponse{
+		RoutedMessages: make([]*types.RoutedMessage, 0, limit),
+		Page:           &types.PageResponse{},
+	}
+	k.iterateRoutedMessages(sdk.UnwrapSDKContext(ctx), key, end, req.Namespace, req.Sender, req.Status,
+		func(msg *types.RoutedMessage) bool {
+			// the messages of a sender are read from its index, and may still have to be filtered by status.
+			if !matchesRoutedMessage(req, msg) {
+				return true
+			}
+			if uint32(len(res.RoutedMessages)) == limit {
+				res.Page.Key = k.getRoutedMessageKey(msg.BlockHeight, msg.EvmTxHash)
+				return false
+			}
+			res.RoutedMessages = append(res.RoutedMessages, msg)
+			return true
+		},
+	)
+	return &res, nil
+}
+
+// matchesRoutedMessage returns true if the message passes the sender and status filters of the request.
+func matchesRoutedMessage(req *types.QueryRoutedMessagesRequest, msg *types.RoutedMessage) bool {
+	if req.Sender != "" && !strings.EqualFold(req.Sender, msg.Sender) {
+		return false
+	}
+	switch req.Status {
+	case types.RoutedMessageStatus_ROUTED_MESSAGE_STATUS_SUCCEEDED:
+		return msg.Code == 0
+	case types.RoutedMessageStatus_ROUTED_MESSAGE_STATUS_FAILED:
+		return msg.Code != 0
+	default:
+		return true
+	}
+}
@@ -0,0 +1,160 @@
+package keeper
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"strings"
+
+	"cosmossdk.io/store/prefix"
+	storetypes "cosmossdk.io/store/types"
+	"github.com/cosmos/cosmos-sdk/runtime"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"pkg.world.dev/world-engine/evm/x/shard/types"
+)
+
+// RoutedMessageRetention is the number of blocks that routed messages are kept for. Older messages of a namespace are
+// pruned when a new message of the namespace is stored.
+const RoutedMessageRetention = 100_000
+
+var (
+	// routedStorePrefix starts with a slash, which namespaces can't contain, so the keys of routed messages can't fall
+	// into the transaction store of a namespace.
+	routedStorePrefix = []byte("/routed/")
+	// routedSenderStorePrefix and routedStatusStorePrefix index the routed messages of a namespace by sender and by
+	// status. The keys of the indexes end with the key of the message in the routed store.
+	routedSenderStorePrefix = []byte("/routed-sender/")
+	routedStatusStorePrefix = []byte("/routed-status/")
+)
+
+// routedStore retrieves the store for the messages that the router sent to the given namespace.
+func (k *Keeper) routedStore(ctx sdk.Context, ns string) prefix.Store {
+	store := runtime.KVStoreAdapter(k.storeService.OpenKVStore(ctx))
+	return prefix.NewStore(store, append(append([]byte{}, routedStorePrefix...), ns+"/"...))
+}
+
+// routedSenderStore retrieves the index of the messages that the given EVM contract sent to the namespace.
+func (k *Keeper) routedSenderStore(ctx sdk.Context, ns, sender string) prefix.Store {
+	store := runtime.KVStoreAdapter(k.storeService.OpenKVStore(ctx))
+	key := append(append([]byte{}, routedSenderStorePrefix...), ns+"/"+strings.ToLower(sender)+"/"...)
+	return prefix.NewStore(store, key)
+}
+
+// routedStatusStore retrieves the index of the messages sent to the namespace that have the given status.
+func (k *Keeper) routedStatusStore(ctx sdk.Context, ns string, status types.RoutedMessageStatus) prefix.Store {
+	store := runtime.KVStoreAdapter(k.storeService.OpenKVStore(ctx))
+	key := append(append([]byte{}, routedStatusStorePrefix...), ns+"/"...)
+	return prefix.NewStore(store, append(key, byte(status)))
+}
+
+// routed messages are keyed via the block height their result was stored in, followed by the hash of the EVM
+// transaction that sent them, so they are sorted by height.
+func (k *Keeper) getRoutedMessageKey(height int64, evmTxHash string) []byte {
+	buf := make([]byte, uint64Size, uint64Size+len(evmTxHash))
+	binary.BigEndian.PutUint64(buf, uint64(height))
+	return append(buf, evmTxHash...)
+}
+
+// routedMessageStatus returns whether the message succeeded or failed.
+func routedMessageStatus(msg *types.RoutedMessage) types.RoutedMessageStatus {
+	if msg.Code == 0 {
+		return types.RoutedMessageStatus_ROUTED_MESSAGE_STATUS_SUCCEEDED
+	}
+	return types.RoutedMessageStatus_ROUTED_MESSAGE_STATUS_FAILED
+}
+
+// SaveRoutedMessage stores a message that the router sent to the game shard of the namespace, along with its result,
+// and prunes the messages of the namespace that are older than RoutedMessageRetention blocks.
+func (k *Keeper) SaveRoutedMessage(ctx sdk.Context, ns string, msg *types.RoutedMessage) error {
+	bz, err := msg.Marshal()
+	if err != nil {
+		return err
+	}
+	key := k.getRoutedMessageKey(msg.BlockHeight, msg.EvmTxHash)
+	k.routedStore(ctx, ns).Set(key, bz)
+	k.routedSenderStore(ctx, ns, msg.Sender).Set(key, []byte{})
+	k.routedStatusStore(ctx, ns, routedMessageStatus(msg)).Set(key, []byte{})
+	k.pruneRoutedMessages(ctx, ns, msg.BlockHeight-RoutedMessageRetention)
+	return nil
+}
+
+// pruneRoutedMessages deletes the messages of the namespace whose result was stored before the given block height.
+func (k *Keeper) pruneRoutedMessages(ctx sdk.Context, ns string, before int64) {
+	if before <= 0 {
+		return
+	}
+	store := k.routedStore(ctx, ns)
+	var keys [][]byte
+	var msgs []*types.RoutedMessage
+	it := store.Iterator(nil, k.getRoutedMessageKey(before, ""))
+	for ; it.Valid(); it.Next() {
+		keys = append(keys, it.Key())
+		msgs = append(msgs, unmarshalRoutedMessage(it.Value()))
+	}
+	it.Close()
+	for i, key := range keys {
+		store.Delete(key)
+		k.routedSenderStore(ctx, ns, msgs[i].Sender).Delete(key)
+		k.routedStatusStore(ctx, ns, routedMessageStatus(msgs[i])).Delete(key)
+	}
+}
+
+// iterateRoutedMessages iterates over the routed messages of the namespace between the start and end keys. If sender
+// or status is set, only the messages of the sender or with the status are read, from their index.
+func (k *Keeper) iterateRoutedMessages(
+	ctx sdk.Context,
+	start, end []byte,
+	ns, sender string,
+	status types.RoutedMessageStatus,
+	cb func(msg *types.RoutedMessage) bool,
+) {
+	store := k.routedStore(ctx, ns)
+	index := store
+	indexed := true
+	switch {
+	case sender != "":
+		index = k.routedSenderStore(ctx, ns, sender)
+	case status != types.RoutedMessageStatus_ROUTED_MESSAGE_STATUS_UNSPECIFIED:
+		index = k.routedStatusStore(ctx, ns, status)
+	default:
+		indexed = false
+	}
+	it := index.Iterator(start, end)
+	defer it.Close()
+	for ; it.Valid(); it.Next() {
+		bz := it.Value()
+		if indexed {
+			bz = store.Get(it.Key())
+		}
+		// if callback returns false, we stop.
+		if !cb(unmarshalRoutedMessage(bz)) {
+			break
+		}
+	}
+}
+
+// iterateNamespaceRoutedMessages iterates over the routed messages of all namespaces, sorted by namespace and block
+// height.
+func (k *Keeper) iterateNamespaceRoutedMessages(ctx sdk.Context, cb func(ns string, msg *types.RoutedMessage) bool) {
+	store := runtime.KVStoreAdapter(k.storeService.OpenKVStore(ctx))
+	it := storetypes.KVStorePrefixIterator(store, routedStorePrefix)
+	defer it.Close()
+	for ; it.Valid(); it.Next() {
+		// keys are made of the prefix, the namespace, a slash, the block height and the EVM tx hash.
+		key := it.Key()[len(routedStorePrefix):]
+		ns := string(key[:bytes.IndexByte(key, '/')])
+		if !cb(ns, unmarshalRoutedMessage(it.Value())) {
+			break
+		}
+	}
+}
+
+func unmarshalRoutedMessage(bz []byte) *types.RoutedMessage {
+	msg := new(types.RoutedMessage)
+	if err := msg.Unmarshal(bz); err != nil {
+		// this shouldn't ever happen, so lets just panic if it somehow does.
+		panic(fmt.Errorf("error while unmarshalling routed message bytes into %T: %w", msg, err))
+	}
+	return msg
+}
@@ -0,0 +1,105 @@
+package keeper
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"cosmossdk.io/store/prefix"
+	storetypes "cosmossdk.io/store/types"
+	"github.com/cosmos/cosmos-sdk/runtime"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"pkg.world.dev/world-engine/evm/x/shard/types"
+)
+
+var (
+	// stateRootStorePrefix starts with a slash, which namespaces can't contain, so the keys of state roots can't fall
+	// into the transaction store of a namespace.
+	stateRootStorePrefix = []byte("/stateroot/")
+)
+
+// stateRootStore retrieves the store for the state roots submitted by the game shard of the given namespace.
+func (k *Keeper) stateRootStore(ctx sdk.Context, ns string) prefix.Store {
+	store := runtime.KVStoreAdapter(k.storeService.OpenKVStore(ctx))
+	return prefix.NewStore(store, append(append([]byte{}, stateRootStorePrefix...), ns+"/"...))
+}
+
+// state roots are keyed via their end tick, so the state root of the range that contains a tick is the first one whose
+// key is not lower than the tick.
+func (k *Keeper) getStateRootKey(endTick uint64) []byte {
+	buf := make([]byte, uint64Size)
+	binary.BigEndian.PutUint64(buf, endTick)
+	return buf
+}
+
+func (k *Keeper) saveStateRoot(ctx sdk.Context, ns string, root *types.StateRoot) error {
+	bz, err := root.Marshal()
+	if err != nil {
+		return err
+	}
+	k.stateRootStore(ctx, ns).Set(k.getStateRootKey(root.EndTick), bz)
+	return nil
+}
+
+func (k *Keeper) iterateStateRoots(
+	ctx sdk.Context,
+	start, end []byte,
+	ns string,
+	cb func(root *types.StateRoot) bool,
+) {
+	it := k.stateRootStore(ctx, ns).Iterator(start, end)
+	defer it.Close()
+	for ; it.Valid(); it.Next() {
+		// if callback returns false, we stop.
+		if !cb(unmarshalStateRoot(it.Value())) {
+			break
+		}
+	}
+}
+
+// lastStateRoot returns the state root of the namespace with the highest end tick, or nil if the namespace has not
+// submitted a state root yet.
+func (k *Keeper) lastStateRoot(ctx sdk.Context, ns string) *types.StateRoot {
+	it := k.stateRootStore(ctx, ns).ReverseIterator(nil, nil)
+	defer it.Close()
+	if !it.Valid() {
+		return nil
+	}
+	return unmarshalStateRoot(it.Value())
+}
+
+// getStateRoot returns the state root of the range of ticks that contains the given tick, or nil if there is none.
+func (k *Keeper) getStateRoot(ctx sdk.Context, ns string, tick uint64) *types.StateRoot {
+	var root *types.StateRoot
+	k.iterateStateRoots(ctx, k.getStateRootKey(tick), nil, ns, func(r *types.StateRoot) bool {
+		if r.StartTick <= tick {
+			root = r
+		}
+		return false
+	})
+	return root
+}
+
+// iterateNamespaceStateRoots iterates over the state roots of all namespaces, sorted by namespace and tick.
+func (k *Keeper) iterateNamespaceStateRoots(ctx sdk.Context, cb func(ns string, root *types.StateRoot) bool) {
+	store := runtime.KVStoreAdapter(k.storeService.OpenKVStore(ctx))
+	it := storetypes.KVStorePrefixIterator(store, stateRootStorePrefix)
+	defer it.Close()
+	for ; it.Valid(); it.Next() {
+		// keys are made of the prefix, the namespace, a slash and the end tick.
+		key := it.Key()
+		ns := string(key[len(stateRootStorePrefix) : len(key)-uint64Size-1])
+		if !cb(ns, unmarshalStateRoot(it.Value())) {
+			break
+		}
+	}
+}
+
+func unmarshalStateRoot(bz []byte) *types.StateRoot {
+	root := new(types.StateRoot)
+	if err := root.Unmarshal(bz); err != nil {
+		// this shouldn't ever happen, so lets just panic if it somehow does.
+		panic(fmt.Errorf("error while unmarshalling state root bytes into %T: %w", root, err))
+	}
+	return root
+}
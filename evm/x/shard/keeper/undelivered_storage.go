@@ -0,0 +1,42 @@
+package keeper
+
+import (
+	"cosmossdk.io/store/prefix"
+	"github.com/cosmos/cosmos-sdk/runtime"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+var (
+	// undeliveredStorePrefix starts with a slash, which namespaces can't contain, so the keys of undelivered messages
+	// can't fall into the transaction store of a namespace.
+	undeliveredStorePrefix = []byte("/undelivered/")
+)
+
+// undeliveredStore retrieves the store for the messages that the router could not deliver to the given namespace.
+func (k *Keeper) undeliveredStore(ctx sdk.Context, ns string) prefix.Store {
+	store := runtime.KVStoreAdapter(k.storeService.OpenKVStore(ctx))
+	return prefix.NewStore(store, append(append([]byte{}, undeliveredStorePrefix...), ns+"/"...))
+}
+
+// SaveUndeliveredMessage stores a message that the router could not deliver to the game shard of the namespace, keyed
+// by the hash of the EVM transaction that sent it.
+func (k *Keeper) SaveUndeliveredMessage(ctx sdk.Context, ns, evmTxHash string, msg []byte) {
+	k.undeliveredStore(ctx, ns).Set([]byte(evmTxHash), msg)
+}
+
+// TakeUndeliveredMessages removes and returns the undelivered messages of the namespace, sorted by EVM transaction
+// hash.
+func (k *Keeper) TakeUndeliveredMessages(ctx sdk.Context, ns string) [][]byte {
+	store := k.undeliveredStore(ctx, ns)
+	var keys, msgs [][]byte
+	it := store.Iterator(nil, nil)
+	for ; it.Valid(); it.Next() {
+		keys = append(keys, it.Key())
+		msgs = append(msgs, it.Value())
+	}
+	it.Close()
+	for _, key := range keys {
+		store.Delete(key)
+	}
+	return msgs
+}
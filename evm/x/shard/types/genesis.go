@@ -26,5 +26,29 @@ func (g *GenesisState) Validate() error {
 			}
 		}
 	}
+	for i, nsRoots := range g.NamespaceStateRoots {
+		if nsRoots.Namespace == "" {
+			return fmt.Errorf("empty namespace of state roots at %d", i)
+		}
+		for j, root := range nsRoots.StateRoots {
+			if root.EndTick < root.StartTick || len(root.StateRoot) != StateRootSize {
+				return fmt.Errorf("invalid state root %d in namespace %s", j, nsRoots.Namespace)
+			}
+			if j > 0 && root.StartTick <= nsRoots.StateRoots[j-1].EndTick {
+				return fmt.Errorf("state root %d overlaps the previous state root in namespace %s", j,
+					nsRoots.Namespace)
+			}
+		}
+	}
+	for i, nsMsgs := range g.NamespaceRoutedMessages {
+		if nsMsgs.Namespace == "" {
+			return fmt.Errorf("empty namespace of routed messages at %d", i)
+		}
+		for j, msg := range nsMsgs.RoutedMessages {
+			if msg.EvmTxHash == "" {
+				return fmt.Errorf("no EVM tx hash for routed message %d in namespace %s", j, nsMsgs.Namespace)
+			}
+		}
+	}
 	return nil
 }
@@ -25,6 +25,10 @@ const _ = proto.GoGoProtoPackageIsVersion3 // please upgrade the proto package
 type GenesisState struct {
 	// namespace_transactions contains a world's namespace, and all the transactions that occurred within that world.
 	NamespaceTransactions []*NamespaceTransactions `protobuf:"bytes,1,rep,name=namespace_transactions,json=namespaceTransactions,proto3" json:"namespace_transactions,omitempty"`
+	// namespace_routed_messages contains a world's namespace, and all the messages the router sent to that world.
+	NamespaceRoutedMessages []*NamespaceRoutedMessages `protobuf:"bytes,2,rep,name=namespace_routed_messages,json=namespaceRoutedMessages,proto3" json:"namespace_routed_messages,omitempty"`
+	// namespace_state_roots contains a world's namespace, and all the state roots submitted by that world.
+	NamespaceStateRoots []*NamespaceStateRoots `protobuf:"bytes,3,rep,name=namespace_state_roots,json=namespaceStateRoots,proto3" json:"namespace_state_roots,omitempty"`
 }
 
 func (m *GenesisState) Reset()         { *m = GenesisState{} }
@@ -67,6 +71,20 @@ func (m *GenesisState) GetNamespaceTransactions() []*NamespaceTransactions {
 	return nil
 }
 
+func (m *GenesisState) GetNamespaceRoutedMessages() []*NamespaceRoutedMessages {
+	if m != nil {
+		return m.NamespaceRoutedMessages
+	}
+	return nil
+}
+
+func (m *GenesisState) GetNamespaceStateRoots() []*NamespaceStateRoots {
+	if m != nil {
+		return m.NamespaceStateRoots
+	}
+	return nil
+}
+
 type NamespaceTransactions struct {
 	// namespace is the namespace the transactions occurred in.
 	Namespace string `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
@@ -121,31 +139,148 @@ func (m *NamespaceTransactions) GetEpochs() []*Epoch {
 	return nil
 }
 
+type NamespaceRoutedMessages struct {
+	// namespace is the namespace the messages were routed to.
+	Namespace string `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	// routed_messages are sorted by block height.
+	RoutedMessages []*RoutedMessage `protobuf:"bytes,2,rep,name=routed_messages,json=routedMessages,proto3" json:"routed_messages,omitempty"`
+}
+
+func (m *NamespaceRoutedMessages) Reset()         { *m = NamespaceRoutedMessages{} }
+func (m *NamespaceRoutedMessages) String() string { return proto.CompactTextString(m) }
+func (*NamespaceRoutedMessages) ProtoMessage()    {}
+func (*NamespaceRoutedMessages) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4ec65e0ff6cb305e, []int{2}
+}
+func (m *NamespaceRoutedMessages) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *NamespaceRoutedMessages) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_NamespaceRoutedMessages.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *NamespaceRoutedMessages) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NamespaceRoutedMessages.Merge(m, src)
+}
+func (m *NamespaceRoutedMessages) XXX_Size() int {
+	return m.Size()
+}
+func (m *NamespaceRoutedMessages) XXX_DiscardUnknown() {
+	xxx_messageInfo_NamespaceRoutedMessages.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NamespaceRoutedMessages proto.InternalMessageInfo
+
+func (m *NamespaceRoutedMessages) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *NamespaceRoutedMessages) GetRoutedMessages() []*RoutedMessage {
+	if m != nil {
+		return m.RoutedMessages
+	}
+	return nil
+}
+
+type NamespaceStateRoots struct {
+	// namespace is the namespace the state roots were submitted by.
+	Namespace string `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	// state_roots are sorted by tick.
+	StateRoots []*StateRoot `protobuf:"bytes,2,rep,name=state_roots,json=stateRoots,proto3" json:"state_roots,omitempty"`
+}
+
+func (m *NamespaceStateRoots) Reset()         { *m = NamespaceStateRoots{} }
+func (m *NamespaceStateRoots) String() string { return proto.CompactTextString(m) }
+func (*NamespaceStateRoots) ProtoMessage()    {}
+func (*NamespaceStateRoots) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4ec65e0ff6cb305e, []int{3}
+}
+func (m *NamespaceStateRoots) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *NamespaceStateRoots) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_NamespaceStateRoots.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *NamespaceStateRoots) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NamespaceStateRoots.Merge(m, src)
+}
+func (m *NamespaceStateRoots) XXX_Size() int {
+	return m.Size()
+}
+func (m *NamespaceStateRoots) XXX_DiscardUnknown() {
+	xxx_messageInfo_NamespaceStateRoots.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NamespaceStateRoots proto.InternalMessageInfo
+
+func (m *NamespaceStateRoots) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *NamespaceStateRoots) GetStateRoots() []*StateRoot {
+	if m != nil {
+		return m.StateRoots
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*GenesisState)(nil), "shard.v1.GenesisState")
 	proto.RegisterType((*NamespaceTransactions)(nil), "shard.v1.NamespaceTransactions")
+	proto.RegisterType((*NamespaceRoutedMessages)(nil), "shard.v1.NamespaceRoutedMessages")
+	proto.RegisterType((*NamespaceStateRoots)(nil), "shard.v1.NamespaceStateRoots")
 }
 
 func init() { proto.RegisterFile("shard/v1/genesis.proto", fileDescriptor_4ec65e0ff6cb305e) }
 
 var fileDescriptor_4ec65e0ff6cb305e = []byte{
-	// 242 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x12, 0x2b, 0xce, 0x48, 0x2c,
-	0x4a, 0xd1, 0x2f, 0x33, 0xd4, 0x4f, 0x4f, 0xcd, 0x4b, 0x2d, 0xce, 0x2c, 0xd6, 0x2b, 0x28, 0xca,
-	0x2f, 0xc9, 0x17, 0xe2, 0x00, 0x8b, 0xeb, 0x95, 0x19, 0x4a, 0x89, 0xc0, 0x55, 0x94, 0x54, 0x16,
-	0xa4, 0x42, 0xe5, 0x95, 0xd2, 0xb8, 0x78, 0xdc, 0x21, 0x1a, 0x82, 0x4b, 0x12, 0x4b, 0x52, 0x85,
-	0xc2, 0xb8, 0xc4, 0xf2, 0x12, 0x73, 0x53, 0x8b, 0x0b, 0x12, 0x93, 0x53, 0xe3, 0x4b, 0x8a, 0x12,
-	0xf3, 0x8a, 0x13, 0x93, 0x4b, 0x32, 0xf3, 0xf3, 0x8a, 0x25, 0x18, 0x15, 0x98, 0x35, 0xb8, 0x8d,
-	0xe4, 0xf5, 0x60, 0x06, 0xea, 0xf9, 0xc1, 0xd4, 0x85, 0x20, 0x29, 0x0b, 0x12, 0xcd, 0xc3, 0x26,
-	0xac, 0x14, 0xc7, 0x25, 0x8a, 0x55, 0xbd, 0x90, 0x0c, 0x17, 0x27, 0x5c, 0x87, 0x04, 0xa3, 0x02,
-	0xa3, 0x06, 0x67, 0x10, 0x42, 0x40, 0x48, 0x9d, 0x8b, 0x2d, 0xb5, 0x20, 0x3f, 0x39, 0xa3, 0x58,
-	0x82, 0x09, 0x6c, 0x3d, 0x3f, 0xc2, 0x7a, 0x57, 0x90, 0x78, 0x10, 0x54, 0xda, 0xc9, 0xe3, 0xc4,
-	0x23, 0x39, 0xc6, 0x0b, 0x8f, 0xe4, 0x18, 0x1f, 0x3c, 0x92, 0x63, 0x9c, 0xf0, 0x58, 0x8e, 0xe1,
-	0xc2, 0x63, 0x39, 0x86, 0x1b, 0x8f, 0xe5, 0x18, 0xa2, 0xf4, 0x0a, 0xb2, 0xd3, 0xf5, 0xca, 0xf3,
-	0x8b, 0x72, 0x52, 0xf4, 0x52, 0x52, 0xcb, 0xf4, 0xc1, 0x2c, 0xdd, 0xd4, 0xbc, 0xf4, 0xcc, 0xbc,
-	0x54, 0xfd, 0xe4, 0x8c, 0xc4, 0xcc, 0x3c, 0xfd, 0x0a, 0x7d, 0x48, 0xd0, 0x80, 0xc3, 0x25, 0x89,
-	0x0d, 0x1c, 0x30, 0xc6, 0x80, 0x00, 0x00, 0x00, 0xff, 0xff, 0x10, 0xad, 0x6c, 0x3d, 0x52, 0x01,
-	0x00, 0x00,
+	// 359 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x92, 0xc1, 0x4e, 0xea, 0x40,
+	0x14, 0x86, 0x29, 0x24, 0xe4, 0x32, 0xdc, 0x5c, 0x92, 0x72, 0x81, 0x6a, 0xb4, 0x62, 0x37, 0xb2,
+	0x71, 0x1a, 0xd4, 0x07, 0x30, 0x26, 0x46, 0x37, 0x9a, 0x38, 0x1a, 0x17, 0x26, 0xda, 0x8c, 0xed,
+	0xa4, 0x34, 0xca, 0x4c, 0x33, 0x67, 0x44, 0x79, 0x0b, 0x97, 0x3e, 0x92, 0x4b, 0x96, 0x2e, 0x0d,
+	0xbc, 0x88, 0x61, 0xa0, 0x14, 0xe3, 0x18, 0x76, 0xd3, 0xff, 0xfc, 0xfd, 0xfe, 0xfc, 0x27, 0x07,
+	0x35, 0xa1, 0x47, 0x65, 0xe4, 0x0f, 0xba, 0x7e, 0xcc, 0x38, 0x83, 0x04, 0x70, 0x2a, 0x85, 0x12,
+	0xf6, 0x1f, 0xad, 0xe3, 0x41, 0x77, 0xfd, 0xff, 0xc2, 0xa1, 0x86, 0x29, 0x9b, 0xcf, 0xbd, 0xb7,
+	0x22, 0xfa, 0x7b, 0x32, 0xfb, 0xe3, 0x52, 0x51, 0xc5, 0xec, 0x6b, 0xd4, 0xe4, 0xb4, 0xcf, 0x20,
+	0xa5, 0x21, 0x0b, 0x94, 0xa4, 0x1c, 0x68, 0xa8, 0x12, 0xc1, 0xc1, 0xb1, 0xda, 0xa5, 0x4e, 0x75,
+	0x6f, 0x0b, 0x67, 0x44, 0x7c, 0x9e, 0xf9, 0xae, 0x96, 0x6c, 0xa4, 0xc1, 0x4d, 0xb2, 0x7d, 0x8b,
+	0xd6, 0x72, 0xae, 0x14, 0x4f, 0x8a, 0x45, 0x41, 0x9f, 0x01, 0xd0, 0x98, 0x81, 0x53, 0xd4, 0xe8,
+	0x6d, 0x03, 0x9a, 0x68, 0xe7, 0xd9, 0xdc, 0x48, 0x5a, 0xdc, 0x3c, 0xb0, 0x2f, 0x50, 0x9e, 0x1b,
+	0xc0, 0xb4, 0x49, 0x20, 0x85, 0x50, 0xe0, 0x94, 0x34, 0x7a, 0xd3, 0x80, 0xd6, 0x7d, 0xc9, 0xd4,
+	0x44, 0xea, 0xfc, 0xa7, 0xe8, 0xdd, 0xa1, 0x86, 0xb1, 0xa1, 0xbd, 0x81, 0x2a, 0x0b, 0xbf, 0x63,
+	0xb5, 0xad, 0x4e, 0x85, 0xe4, 0x82, 0xbd, 0x83, 0xca, 0x2c, 0x15, 0x61, 0x2f, 0x6b, 0x55, 0xcb,
+	0xa3, 0x8f, 0xa7, 0x3a, 0x99, 0x8f, 0xbd, 0x21, 0x6a, 0xfd, 0x52, 0x73, 0x45, 0xc2, 0x21, 0xaa,
+	0x99, 0x17, 0xd8, 0xca, 0xa3, 0xbe, 0x01, 0xc9, 0x3f, 0xb9, 0xfc, 0x09, 0x5e, 0x82, 0xea, 0x86,
+	0x35, 0xac, 0x88, 0x3d, 0x40, 0xd5, 0xe5, 0xc5, 0xce, 0x22, 0xeb, 0x79, 0xe4, 0x02, 0x44, 0x10,
+	0x64, 0x4f, 0x38, 0x3a, 0x7d, 0x1f, 0xbb, 0xd6, 0x68, 0xec, 0x5a, 0x9f, 0x63, 0xd7, 0x7a, 0x9d,
+	0xb8, 0x85, 0xd1, 0xc4, 0x2d, 0x7c, 0x4c, 0xdc, 0xc2, 0x0d, 0x4e, 0x1f, 0x62, 0xfc, 0x2c, 0xe4,
+	0x63, 0x84, 0x23, 0x36, 0xf0, 0xf5, 0x6b, 0x97, 0xf1, 0x38, 0xe1, 0xcc, 0x0f, 0x7b, 0x34, 0xe1,
+	0xfe, 0x8b, 0x3f, 0xbb, 0x59, 0x7d, 0xb0, 0xf7, 0x65, 0x7d, 0xb1, 0xfb, 0x5f, 0x03, 0x00, 0x12,
+	0x1e, 0x98, 0xaf, 0xeb, 0x02, 0x00, 0x00,
 }
 
 func (m *GenesisState) Marshal() (dAtA []byte, err error) {
@@ -168,6 +303,34 @@ func (m *GenesisState) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.NamespaceStateRoots) > 0 {
+		for iNdEx := len(m.NamespaceStateRoots) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.NamespaceStateRoots[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintGenesis(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
+	if len(m.NamespaceRoutedMessages) > 0 {
+		for iNdEx := len(m.NamespaceRoutedMessages) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.NamespaceRoutedMessages[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintGenesis(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
+	}
 	if len(m.NamespaceTransactions) > 0 {
 		for iNdEx := len(m.NamespaceTransactions) - 1; iNdEx >= 0; iNdEx-- {
 			{
@@ -229,6 +392,94 @@ func (m *NamespaceTransactions) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *NamespaceRoutedMessages) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *NamespaceRoutedMessages) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *NamespaceRoutedMessages) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.RoutedMessages) > 0 {
+		for iNdEx := len(m.RoutedMessages) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.RoutedMessages[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintGenesis(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if len(m.Namespace) > 0 {
+		i -= len(m.Namespace)
+		copy(dAtA[i:], m.Namespace)
+		i = encodeVarintGenesis(dAtA, i, uint64(len(m.Namespace)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *NamespaceStateRoots) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *NamespaceStateRoots) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *NamespaceStateRoots) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.StateRoots) > 0 {
+		for iNdEx := len(m.StateRoots) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.StateRoots[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintGenesis(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if len(m.Namespace) > 0 {
+		i -= len(m.Namespace)
+		copy(dAtA[i:], m.Namespace)
+		i = encodeVarintGenesis(dAtA, i, uint64(len(m.Namespace)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
 func encodeVarintGenesis(dAtA []byte, offset int, v uint64) int {
 	offset -= sovGenesis(v)
 	base := offset
@@ -252,6 +503,18 @@ func (m *GenesisState) Size() (n int) {
 			n += 1 + l + sovGenesis(uint64(l))
 		}
 	}
+	if len(m.NamespaceRoutedMessages) > 0 {
+		for _, e := range m.NamespaceRoutedMessages {
+			l = e.Size()
+			n += 1 + l + sovGenesis(uint64(l))
+		}
+	}
+	if len(m.NamespaceStateRoots) > 0 {
+		for _, e := range m.NamespaceStateRoots {
+			l = e.Size()
+			n += 1 + l + sovGenesis(uint64(l))
+		}
+	}
 	return n
 }
 
@@ -274,6 +537,44 @@ func (m *NamespaceTransactions) Size() (n int) {
 	return n
 }
 
+func (m *NamespaceRoutedMessages) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Namespace)
+	if l > 0 {
+		n += 1 + l + sovGenesis(uint64(l))
+	}
+	if len(m.RoutedMessages) > 0 {
+		for _, e := range m.RoutedMessages {
+			l = e.Size()
+			n += 1 + l + sovGenesis(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *NamespaceStateRoots) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Namespace)
+	if l > 0 {
+		n += 1 + l + sovGenesis(uint64(l))
+	}
+	if len(m.StateRoots) > 0 {
+		for _, e := range m.StateRoots {
+			l = e.Size()
+			n += 1 + l + sovGenesis(uint64(l))
+		}
+	}
+	return n
+}
+
 func sovGenesis(x uint64) (n int) {
 	return (math_bits.Len64(x|1) + 6) / 7
 }
@@ -343,6 +644,74 @@ func (m *GenesisState) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NamespaceRoutedMessages", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenesis
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenesis
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenesis
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.NamespaceRoutedMessages = append(m.NamespaceRoutedMessages, &NamespaceRoutedMessages{})
+			if err := m.NamespaceRoutedMessages[len(m.NamespaceRoutedMessages)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NamespaceStateRoots", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenesis
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenesis
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenesis
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.NamespaceStateRoots = append(m.NamespaceStateRoots, &NamespaceStateRoots{})
+			if err := m.NamespaceStateRoots[len(m.NamespaceStateRoots)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipGenesis(dAtA[iNdEx:])
@@ -480,6 +849,238 @@ func (m *NamespaceTransactions) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *NamespaceRoutedMessages) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGenesis
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: NamespaceRoutedMessages: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: NamespaceRoutedMessages: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Namespace", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenesis
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGenesis
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenesis
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Namespace = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RoutedMessages", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenesis
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenesis
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenesis
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.RoutedMessages = append(m.RoutedMessages, &RoutedMessage{})
+			if err := m.RoutedMessages[len(m.RoutedMessages)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGenesis(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthGenesis
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *NamespaceStateRoots) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGenesis
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: NamespaceStateRoots: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: NamespaceStateRoots: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Namespace", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenesis
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGenesis
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenesis
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Namespace = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StateRoots", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenesis
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenesis
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenesis
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.StateRoots = append(m.StateRoots, &StateRoot{})
+			if err := m.StateRoots[len(m.StateRoots)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGenesis(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthGenesis
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func skipGenesis(dAtA []byte) (n int, err error) {
 	l := len(dAtA)
 	iNdEx := 0
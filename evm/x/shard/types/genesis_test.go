@@ -58,3 +58,56 @@ func TestGenesisValidate(t *testing.T) {
 		})
 	}
 }
+
+func TestGenesisValidateStateRoots(t *testing.T) {
+	root := make([]byte, StateRootSize)
+	testCases := []struct {
+		name   string
+		mutate func(*GenesisState)
+		err    string
+	}{
+		{
+			name: "no namespace",
+			mutate: func(state *GenesisState) {
+				state.NamespaceStateRoots = append(state.NamespaceStateRoots, &NamespaceStateRoots{})
+			},
+			err: "empty namespace of state roots",
+		},
+		{
+			name: "state roots are ok",
+			mutate: func(state *GenesisState) {
+				state.NamespaceStateRoots[0].Namespace = "foo"
+				state.NamespaceStateRoots[0].StateRoots = []*StateRoot{
+					{StartTick: 0, EndTick: 9, StateRoot: root},
+					{StartTick: 10, EndTick: 19, StateRoot: root},
+				}
+			},
+		},
+		{
+			name: "overlapping state roots",
+			mutate: func(state *GenesisState) {
+				state.NamespaceStateRoots[0].StateRoots[1].StartTick = 9
+			},
+			err: "state root 1 overlaps the previous state root in namespace foo",
+		},
+		{
+			name: "short state root",
+			mutate: func(state *GenesisState) {
+				state.NamespaceStateRoots[0].StateRoots[0].StateRoot = root[:20]
+			},
+			err: "invalid state root 0 in namespace foo",
+		},
+	}
+	g := &GenesisState{}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mutate(g)
+			err := g.Validate()
+			if tc.err != "" {
+				assert.ErrorContains(t, err, tc.err)
+			} else {
+				assert.NilError(t, err)
+			}
+		})
+	}
+}
@@ -8,6 +8,12 @@ import (
 var (
 	_ sdk.Msg              = &SubmitShardTxRequest{}
 	_ sdk.HasValidateBasic = &SubmitShardTxRequest{}
+	_ sdk.Msg              = &SubmitStateRootRequest{}
+	_ sdk.HasValidateBasic = &SubmitStateRootRequest{}
+)
+
+const (
+	StateRootSize = 32
 )
 
 func (m *SubmitShardTxRequest) ValidateBasic() error {
@@ -16,3 +22,19 @@ func (m *SubmitShardTxRequest) ValidateBasic() error {
 	}
 	return nil
 }
+
+func (m *SubmitStateRootRequest) ValidateBasic() error {
+	if _, err := sdk.AccAddressFromBech32(m.Sender); err != nil {
+		return sdkerrors.ErrInvalidAddress.Wrap(err.Error())
+	}
+	if m.Namespace == "" {
+		return sdkerrors.ErrInvalidRequest.Wrap("namespace required but not supplied")
+	}
+	if m.EndTick < m.StartTick {
+		return sdkerrors.ErrInvalidRequest.Wrapf("end tick %d is before start tick %d", m.EndTick, m.StartTick)
+	}
+	if len(m.StateRoot) != StateRootSize {
+		return sdkerrors.ErrInvalidRequest.Wrapf("state root must be %d bytes, got %d", StateRootSize, len(m.StateRoot))
+	}
+	return nil
+}
@@ -31,6 +31,38 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.GoGoProtoPackageIsVersion3 // please upgrade the proto package
 
+// RoutedMessageStatus filters routed messages by their result.
+type RoutedMessageStatus int32
+
+const (
+	// ROUTED_MESSAGE_STATUS_UNSPECIFIED matches all messages.
+	RoutedMessageStatus_ROUTED_MESSAGE_STATUS_UNSPECIFIED RoutedMessageStatus = 0
+	// ROUTED_MESSAGE_STATUS_SUCCEEDED matches the messages that the game shard executed successfully.
+	RoutedMessageStatus_ROUTED_MESSAGE_STATUS_SUCCEEDED RoutedMessageStatus = 1
+	// ROUTED_MESSAGE_STATUS_FAILED matches the messages that failed, or could not be delivered.
+	RoutedMessageStatus_ROUTED_MESSAGE_STATUS_FAILED RoutedMessageStatus = 2
+)
+
+var RoutedMessageStatus_name = map[int32]string{
+	0: "ROUTED_MESSAGE_STATUS_UNSPECIFIED",
+	1: "ROUTED_MESSAGE_STATUS_SUCCEEDED",
+	2: "ROUTED_MESSAGE_STATUS_FAILED",
+}
+
+var RoutedMessageStatus_value = map[string]int32{
+	"ROUTED_MESSAGE_STATUS_UNSPECIFIED": 0,
+	"ROUTED_MESSAGE_STATUS_SUCCEEDED":   1,
+	"ROUTED_MESSAGE_STATUS_FAILED":      2,
+}
+
+func (x RoutedMessageStatus) String() string {
+	return proto.EnumName(RoutedMessageStatus_name, int32(x))
+}
+
+func (RoutedMessageStatus) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_1088f6b90570984a, []int{0}
+}
+
 type QueryTransactionsRequest struct {
 	Namespace string       `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
 	Page      *PageRequest `protobuf:"bytes,2,opt,name=page,proto3" json:"page,omitempty"`
@@ -138,6 +170,350 @@ func (m *QueryTransactionsResponse) GetPage() *PageResponse {
 	return nil
 }
 
+type QueryStateRootsRequest struct {
+	Namespace string       `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Page      *PageRequest `protobuf:"bytes,2,opt,name=page,proto3" json:"page,omitempty"`
+}
+
+func (m *QueryStateRootsRequest) Reset()         { *m = QueryStateRootsRequest{} }
+func (m *QueryStateRootsRequest) String() string { return proto.CompactTextString(m) }
+func (*QueryStateRootsRequest) ProtoMessage()    {}
+func (*QueryStateRootsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_1088f6b90570984a, []int{2}
+}
+func (m *QueryStateRootsRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *QueryStateRootsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_QueryStateRootsRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *QueryStateRootsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryStateRootsRequest.Merge(m, src)
+}
+func (m *QueryStateRootsRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *QueryStateRootsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryStateRootsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryStateRootsRequest proto.InternalMessageInfo
+
+func (m *QueryStateRootsRequest) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *QueryStateRootsRequest) GetPage() *PageRequest {
+	if m != nil {
+		return m.Page
+	}
+	return nil
+}
+
+type QueryStateRootsResponse struct {
+	StateRoots []*StateRoot `protobuf:"bytes,1,rep,name=state_roots,json=stateRoots,proto3" json:"state_roots,omitempty"`
+	// page contains information on how to query the next items in the collection, if any.
+	// when page is nil/empty, there is nothing left to query.
+	Page *PageResponse `protobuf:"bytes,2,opt,name=page,proto3" json:"page,omitempty"`
+}
+
+func (m *QueryStateRootsResponse) Reset()         { *m = QueryStateRootsResponse{} }
+func (m *QueryStateRootsResponse) String() string { return proto.CompactTextString(m) }
+func (*QueryStateRootsResponse) ProtoMessage()    {}
+func (*QueryStateRootsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_1088f6b90570984a, []int{3}
+}
+func (m *QueryStateRootsResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *QueryStateRootsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_QueryStateRootsResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *QueryStateRootsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryStateRootsResponse.Merge(m, src)
+}
+func (m *QueryStateRootsResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *QueryStateRootsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryStateRootsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryStateRootsResponse proto.InternalMessageInfo
+
+func (m *QueryStateRootsResponse) GetStateRoots() []*StateRoot {
+	if m != nil {
+		return m.StateRoots
+	}
+	return nil
+}
+
+func (m *QueryStateRootsResponse) GetPage() *PageResponse {
+	if m != nil {
+		return m.Page
+	}
+	return nil
+}
+
+type QueryStateRootRequest struct {
+	Namespace string `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Tick      uint64 `protobuf:"varint,2,opt,name=tick,proto3" json:"tick,omitempty"`
+}
+
+func (m *QueryStateRootRequest) Reset()         { *m = QueryStateRootRequest{} }
+func (m *QueryStateRootRequest) String() string { return proto.CompactTextString(m) }
+func (*QueryStateRootRequest) ProtoMessage()    {}
+func (*QueryStateRootRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_1088f6b90570984a, []int{4}
+}
+func (m *QueryStateRootRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *QueryStateRootRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_QueryStateRootRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *QueryStateRootRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryStateRootRequest.Merge(m, src)
+}
+func (m *QueryStateRootRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *QueryStateRootRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryStateRootRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryStateRootRequest proto.InternalMessageInfo
+
+func (m *QueryStateRootRequest) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *QueryStateRootRequest) GetTick() uint64 {
+	if m != nil {
+		return m.Tick
+	}
+	return 0
+}
+
+type QueryStateRootResponse struct {
+	StateRoot *StateRoot `protobuf:"bytes,1,opt,name=state_root,json=stateRoot,proto3" json:"state_root,omitempty"`
+}
+
+func (m *QueryStateRootResponse) Reset()         { *m = QueryStateRootResponse{} }
+func (m *QueryStateRootResponse) String() string { return proto.CompactTextString(m) }
+func (*QueryStateRootResponse) ProtoMessage()    {}
+func (*QueryStateRootResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_1088f6b90570984a, []int{5}
+}
+func (m *QueryStateRootResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *QueryStateRootResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_QueryStateRootResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *QueryStateRootResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryStateRootResponse.Merge(m, src)
+}
+func (m *QueryStateRootResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *QueryStateRootResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryStateRootResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryStateRootResponse proto.InternalMessageInfo
+
+func (m *QueryStateRootResponse) GetStateRoot() *StateRoot {
+	if m != nil {
+		return m.StateRoot
+	}
+	return nil
+}
+
+type QueryRoutedMessagesRequest struct {
+	Namespace string `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	// sender only returns the messages sent by the given EVM contract address, if set.
+	Sender string `protobuf:"bytes,2,opt,name=sender,proto3" json:"sender,omitempty"`
+	// status only returns the messages with the given result, if set.
+	Status RoutedMessageStatus `protobuf:"varint,3,opt,name=status,proto3,enum=shard.v1.RoutedMessageStatus" json:"status,omitempty"`
+	// from_height is the lowest block height to return messages of.
+	FromHeight int64 `protobuf:"varint,4,opt,name=from_height,json=fromHeight,proto3" json:"from_height,omitempty"`
+	// to_height is the highest block height to return messages of. 0 means there is no upper bound.
+	ToHeight int64        `protobuf:"varint,5,opt,name=to_height,json=toHeight,proto3" json:"to_height,omitempty"`
+	Page     *PageRequest `protobuf:"bytes,6,opt,name=page,proto3" json:"page,omitempty"`
+}
+
+func (m *QueryRoutedMessagesRequest) Reset()         { *m = QueryRoutedMessagesRequest{} }
+func (m *QueryRoutedMessagesRequest) String() string { return proto.CompactTextString(m) }
+func (*QueryRoutedMessagesRequest) ProtoMessage()    {}
+func (*QueryRoutedMessagesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_1088f6b90570984a, []int{6}
+}
+func (m *QueryRoutedMessagesRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *QueryRoutedMessagesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_QueryRoutedMessagesRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *QueryRoutedMessagesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryRoutedMessagesRequest.Merge(m, src)
+}
+func (m *QueryRoutedMessagesRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *QueryRoutedMessagesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryRoutedMessagesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryRoutedMessagesRequest proto.InternalMessageInfo
+
+func (m *QueryRoutedMessagesRequest) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *QueryRoutedMessagesRequest) GetSender() string {
+	if m != nil {
+		return m.Sender
+	}
+	return ""
+}
+
+func (m *QueryRoutedMessagesRequest) GetStatus() RoutedMessageStatus {
+	if m != nil {
+		return m.Status
+	}
+	return RoutedMessageStatus_ROUTED_MESSAGE_STATUS_UNSPECIFIED
+}
+
+func (m *QueryRoutedMessagesRequest) GetFromHeight() int64 {
+	if m != nil {
+		return m.FromHeight
+	}
+	return 0
+}
+
+func (m *QueryRoutedMessagesRequest) GetToHeight() int64 {
+	if m != nil {
+		return m.ToHeight
+	}
+	return 0
+}
+
+func (m *QueryRoutedMessagesRequest) GetPage() *PageRequest {
+	if m != nil {
+		return m.Page
+	}
+	return nil
+}
+
+type QueryRoutedMessagesResponse struct {
+	RoutedMessages []*RoutedMessage `protobuf:"bytes,1,rep,name=routed_messages,json=routedMessages,proto3" json:"routed_messages,omitempty"`
+	// page contains information on how to query the next items in the collection, if any.
+	// when page is nil/empty, there is nothing left to query.
+	Page *PageResponse `protobuf:"bytes,2,opt,name=page,proto3" json:"page,omitempty"`
+}
+
+func (m *QueryRoutedMessagesResponse) Reset()         { *m = QueryRoutedMessagesResponse{} }
+func (m *QueryRoutedMessagesResponse) String() string { return proto.CompactTextString(m) }
+func (*QueryRoutedMessagesResponse) ProtoMessage()    {}
+func (*QueryRoutedMessagesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_1088f6b90570984a, []int{7}
+}
+func (m *QueryRoutedMessagesResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *QueryRoutedMessagesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_QueryRoutedMessagesResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *QueryRoutedMessagesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryRoutedMessagesResponse.Merge(m, src)
+}
+func (m *QueryRoutedMessagesResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *QueryRoutedMessagesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryRoutedMessagesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryRoutedMessagesResponse proto.InternalMessageInfo
+
+func (m *QueryRoutedMessagesResponse) GetRoutedMessages() []*RoutedMessage {
+	if m != nil {
+		return m.RoutedMessages
+	}
+	return nil
+}
+
+func (m *QueryRoutedMessagesResponse) GetPage() *PageResponse {
+	if m != nil {
+		return m.Page
+	}
+	return nil
+}
+
 // PageRequest represents a request for a paged query.
 type PageRequest struct {
 	// key is the cosmos SDK store key to begin the iteration on.
@@ -152,7 +528,7 @@ func (m *PageRequest) Reset()         { *m = PageRequest{} }
 func (m *PageRequest) String() string { return proto.CompactTextString(m) }
 func (*PageRequest) ProtoMessage()    {}
 func (*PageRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_1088f6b90570984a, []int{2}
+	return fileDescriptor_1088f6b90570984a, []int{8}
 }
 func (m *PageRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -206,7 +582,7 @@ func (m *PageResponse) Reset()         { *m = PageResponse{} }
 func (m *PageResponse) String() string { return proto.CompactTextString(m) }
 func (*PageResponse) ProtoMessage()    {}
 func (*PageResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_1088f6b90570984a, []int{3}
+	return fileDescriptor_1088f6b90570984a, []int{9}
 }
 func (m *PageResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -243,8 +619,15 @@ func (m *PageResponse) GetKey() []byte {
 }
 
 func init() {
+	proto.RegisterEnum("shard.v1.RoutedMessageStatus", RoutedMessageStatus_name, RoutedMessageStatus_value)
 	proto.RegisterType((*QueryTransactionsRequest)(nil), "shard.v1.QueryTransactionsRequest")
 	proto.RegisterType((*QueryTransactionsResponse)(nil), "shard.v1.QueryTransactionsResponse")
+	proto.RegisterType((*QueryStateRootsRequest)(nil), "shard.v1.QueryStateRootsRequest")
+	proto.RegisterType((*QueryStateRootsResponse)(nil), "shard.v1.QueryStateRootsResponse")
+	proto.RegisterType((*QueryStateRootRequest)(nil), "shard.v1.QueryStateRootRequest")
+	proto.RegisterType((*QueryStateRootResponse)(nil), "shard.v1.QueryStateRootResponse")
+	proto.RegisterType((*QueryRoutedMessagesRequest)(nil), "shard.v1.QueryRoutedMessagesRequest")
+	proto.RegisterType((*QueryRoutedMessagesResponse)(nil), "shard.v1.QueryRoutedMessagesResponse")
 	proto.RegisterType((*PageRequest)(nil), "shard.v1.PageRequest")
 	proto.RegisterType((*PageResponse)(nil), "shard.v1.PageResponse")
 }
@@ -252,31 +635,54 @@ func init() {
 func init() { proto.RegisterFile("shard/v1/query.proto", fileDescriptor_1088f6b90570984a) }
 
 var fileDescriptor_1088f6b90570984a = []byte{
-	// 373 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x51, 0xcd, 0x4e, 0xea, 0x40,
-	0x14, 0xa6, 0x97, 0x0b, 0xb9, 0x0c, 0xdc, 0x68, 0x26, 0xa8, 0x85, 0x90, 0xa6, 0xa9, 0x0b, 0xd1,
-	0xc4, 0x4e, 0xc0, 0xf8, 0x02, 0x26, 0x26, 0x2e, 0xb5, 0x31, 0x31, 0x71, 0xa3, 0x63, 0x39, 0x19,
-	0x1a, 0xe8, 0xcc, 0xd0, 0x29, 0x28, 0x6f, 0xe1, 0x63, 0xb9, 0x64, 0xe9, 0xd2, 0xc0, 0x8b, 0x98,
-	0xce, 0x94, 0x14, 0x7f, 0x77, 0xe7, 0x7c, 0x73, 0xce, 0xf7, 0x33, 0x07, 0x35, 0xd5, 0x90, 0x26,
-	0x03, 0x32, 0xeb, 0x91, 0xc9, 0x14, 0x92, 0xb9, 0x2f, 0x13, 0x91, 0x0a, 0xfc, 0x4f, 0xa3, 0xfe,
-	0xac, 0xd7, 0x6e, 0x85, 0x42, 0xc5, 0x42, 0xdd, 0x69, 0x9c, 0x98, 0xc6, 0x0c, 0xb5, 0xf7, 0x4c,
-	0x47, 0x62, 0xc5, 0xb2, 0xfd, 0x58, 0xb1, 0xfc, 0xa1, 0xc9, 0x04, 0x13, 0x66, 0x21, 0xab, 0x72,
-	0xb4, 0xc3, 0x84, 0x60, 0x63, 0x20, 0x54, 0x46, 0x84, 0x72, 0x2e, 0x52, 0x9a, 0x46, 0x82, 0xaf,
-	0xc9, 0x0a, 0x1f, 0xe9, 0x5c, 0x42, 0x8e, 0x7a, 0x21, 0xb2, 0xaf, 0x32, 0x5b, 0xd7, 0x09, 0xe5,
-	0x8a, 0x86, 0x7a, 0x21, 0x80, 0xc9, 0x14, 0x54, 0x8a, 0x3b, 0xa8, 0xc6, 0x69, 0x0c, 0x4a, 0xd2,
-	0x10, 0x6c, 0xcb, 0xb5, 0xba, 0xb5, 0xa0, 0x00, 0xf0, 0x21, 0xfa, 0x2b, 0x29, 0x03, 0xfb, 0x8f,
-	0x6b, 0x75, 0xeb, 0xfd, 0x1d, 0x7f, 0x1d, 0xc8, 0xbf, 0xa4, 0x0c, 0x72, 0x8a, 0x40, 0x8f, 0x78,
-	0x12, 0xb5, 0xbe, 0x11, 0x51, 0x52, 0x70, 0x05, 0xf8, 0x00, 0x55, 0x41, 0x8a, 0x70, 0xa8, 0x6c,
-	0xcb, 0x2d, 0x77, 0xeb, 0xfd, 0xad, 0x82, 0xe9, 0x3c, 0xc3, 0x83, 0xfc, 0x19, 0x1f, 0x7d, 0x10,
-	0xdc, 0xfd, 0x2c, 0x68, 0xe8, 0x72, 0xc5, 0x53, 0x54, 0xdf, 0xb0, 0x81, 0xb7, 0x51, 0x79, 0x04,
-	0x73, 0x9d, 0xa1, 0x11, 0x64, 0x25, 0x6e, 0xa2, 0xca, 0x38, 0x8a, 0xa3, 0x54, 0xb3, 0xfd, 0x0f,
-	0x4c, 0xe3, 0xb9, 0xa8, 0xb1, 0x49, 0xf6, 0x75, 0xaf, 0x7f, 0x8f, 0x2a, 0x3a, 0x0a, 0xbe, 0x41,
-	0x8d, 0xcd, 0x38, 0xd8, 0x2b, 0xfc, 0xfc, 0xf4, 0xa1, 0xed, 0xfd, 0x5f, 0x67, 0x8c, 0xe6, 0xd9,
-	0xc5, 0xcb, 0xd2, 0xb1, 0x16, 0x4b, 0xc7, 0x7a, 0x5b, 0x3a, 0xd6, 0xf3, 0xca, 0x29, 0x2d, 0x56,
-	0x4e, 0xe9, 0x75, 0xe5, 0x94, 0x6e, 0x7d, 0x39, 0x62, 0xfe, 0xa3, 0x48, 0xc6, 0x03, 0x7f, 0x00,
-	0x33, 0xa2, 0xab, 0x63, 0xe0, 0x2c, 0xe2, 0x40, 0xc2, 0x21, 0x8d, 0x38, 0x79, 0x22, 0xe6, 0xc8,
-	0xfa, 0xc2, 0x0f, 0x55, 0x7d, 0xe2, 0x93, 0xf7, 0x00, 0x00, 0x00, 0xff, 0xff, 0x94, 0x55, 0x85,
-	0x19, 0x82, 0x02, 0x00, 0x00,
+	// 743 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x55, 0x4d, 0x4f, 0xdb, 0x4a,
+	0x14, 0x8d, 0x49, 0x88, 0xc8, 0x0d, 0x0f, 0xa2, 0xe1, 0x2b, 0x04, 0x5e, 0x30, 0xe1, 0xa1, 0x97,
+	0x22, 0x35, 0x16, 0x29, 0x6c, 0xba, 0x2a, 0x25, 0xa6, 0x44, 0x02, 0x0a, 0xe3, 0x44, 0x95, 0xba,
+	0xb1, 0xdc, 0x64, 0xea, 0x58, 0x10, 0x8f, 0xf1, 0x38, 0xb4, 0x08, 0x75, 0xd3, 0x2e, 0xbb, 0xa9,
+	0xd4, 0x55, 0x57, 0xfd, 0x3b, 0x5d, 0x22, 0x75, 0xd3, 0x65, 0x05, 0xfd, 0x05, 0xfd, 0x05, 0x95,
+	0xc7, 0x93, 0x98, 0x84, 0x84, 0x0f, 0xa9, 0xbb, 0x99, 0x7b, 0xcf, 0x3d, 0x67, 0xce, 0xd5, 0x9d,
+	0x19, 0x98, 0x64, 0x0d, 0xc3, 0xad, 0x2b, 0x27, 0xab, 0xca, 0x71, 0x8b, 0xb8, 0xa7, 0x05, 0xc7,
+	0xa5, 0x1e, 0x45, 0x23, 0x3c, 0x5a, 0x38, 0x59, 0xcd, 0xcc, 0xd6, 0x28, 0x6b, 0x52, 0xa6, 0xf3,
+	0xb8, 0x12, 0x6c, 0x02, 0x50, 0x66, 0x26, 0xd8, 0x29, 0x4d, 0x66, 0xfa, 0xf5, 0x4d, 0x66, 0x8a,
+	0xc4, 0xa4, 0x49, 0x4d, 0x1a, 0x14, 0xf8, 0x2b, 0x11, 0x9d, 0x37, 0x29, 0x35, 0x8f, 0x88, 0x62,
+	0x38, 0x96, 0x62, 0xd8, 0x36, 0xf5, 0x0c, 0xcf, 0xa2, 0x76, 0x9b, 0x2c, 0x3c, 0x87, 0x77, 0xea,
+	0x10, 0x11, 0xcd, 0xd5, 0x20, 0x7d, 0xe0, 0x1f, 0xab, 0xe2, 0x1a, 0x36, 0x33, 0x6a, 0xbc, 0x00,
+	0x93, 0xe3, 0x16, 0x61, 0x1e, 0x9a, 0x87, 0x84, 0x6d, 0x34, 0x09, 0x73, 0x8c, 0x1a, 0x49, 0x4b,
+	0xb2, 0x94, 0x4f, 0xe0, 0x30, 0x80, 0x1e, 0x40, 0xcc, 0x31, 0x4c, 0x92, 0x1e, 0x92, 0xa5, 0x7c,
+	0xb2, 0x38, 0x55, 0x68, 0x1b, 0x2a, 0xec, 0x1b, 0x26, 0x11, 0x14, 0x98, 0x43, 0x72, 0x0e, 0xcc,
+	0xf6, 0x11, 0x61, 0x0e, 0xb5, 0x19, 0x41, 0xff, 0x43, 0x9c, 0x38, 0xb4, 0xd6, 0x60, 0x69, 0x49,
+	0x8e, 0xe6, 0x93, 0xc5, 0xf1, 0x90, 0x49, 0xf5, 0xe3, 0x58, 0xa4, 0xd1, 0x4a, 0x97, 0xe0, 0x74,
+	0xaf, 0x60, 0x40, 0x27, 0x14, 0x0d, 0x98, 0xe6, 0x8a, 0x9a, 0x67, 0x78, 0x04, 0x53, 0xea, 0xfd,
+	0x7d, 0x53, 0x67, 0x30, 0x73, 0x4d, 0x42, 0x58, 0x5a, 0x83, 0x24, 0xf3, 0xa3, 0xba, 0xeb, 0x87,
+	0x85, 0xaf, 0x89, 0x90, 0xac, 0x53, 0x82, 0x81, 0x75, 0xaa, 0xef, 0xe5, 0xaf, 0x0c, 0x53, 0xdd,
+	0xe2, 0x77, 0xb3, 0x87, 0x20, 0xe6, 0x59, 0xb5, 0x43, 0x2e, 0x11, 0xc3, 0x7c, 0x9d, 0xdb, 0xe9,
+	0x6d, 0x55, 0xc7, 0x46, 0x11, 0x20, 0xb4, 0xc1, 0xc9, 0x06, 0xb8, 0x48, 0x74, 0x5c, 0xe4, 0x7e,
+	0x4b, 0x90, 0xe1, 0x74, 0x98, 0xb6, 0x3c, 0x52, 0xdf, 0x25, 0x8c, 0x19, 0x26, 0xb9, 0x63, 0xf7,
+	0xa7, 0x21, 0xce, 0x88, 0x5d, 0x27, 0x2e, 0x3f, 0x60, 0x02, 0x8b, 0x1d, 0x5a, 0x87, 0xb8, 0xaf,
+	0xd0, 0x62, 0xe9, 0xa8, 0x2c, 0xe5, 0xc7, 0x8a, 0xff, 0x86, 0x87, 0xe8, 0x92, 0xd1, 0x38, 0x08,
+	0x0b, 0x30, 0x5a, 0x80, 0xe4, 0x6b, 0x97, 0x36, 0xf5, 0x06, 0xb1, 0xcc, 0x86, 0x97, 0x8e, 0xc9,
+	0x52, 0x3e, 0x8a, 0xc1, 0x0f, 0x6d, 0xf3, 0x08, 0x9a, 0x83, 0x84, 0x47, 0xdb, 0xe9, 0x61, 0x9e,
+	0x1e, 0xf1, 0xa8, 0x48, 0xb6, 0x47, 0x21, 0x7e, 0xfb, 0x28, 0x7c, 0x94, 0x60, 0xae, 0xaf, 0x69,
+	0xd1, 0xc8, 0x27, 0x30, 0xee, 0xf2, 0x8c, 0xde, 0x14, 0x29, 0x31, 0x13, 0x33, 0x03, 0x8c, 0xe0,
+	0x31, 0xb7, 0x8b, 0xe9, 0x5e, 0xb3, 0xb1, 0x0e, 0xc9, 0x2b, 0x47, 0x44, 0x29, 0x88, 0x1e, 0x92,
+	0x53, 0xde, 0xec, 0x51, 0xec, 0x2f, 0xd1, 0x24, 0x0c, 0x1f, 0x59, 0x4d, 0xcb, 0xe3, 0x6c, 0xff,
+	0xe0, 0x60, 0x93, 0x93, 0x61, 0xf4, 0x2a, 0xd9, 0xf5, 0xba, 0x95, 0x0f, 0x12, 0x4c, 0xf4, 0xe9,
+	0x37, 0x5a, 0x86, 0x45, 0xfc, 0xbc, 0x5a, 0x51, 0x4b, 0xfa, 0xae, 0xaa, 0x69, 0x1b, 0xcf, 0x54,
+	0x5d, 0xab, 0x6c, 0x54, 0xaa, 0x9a, 0x5e, 0xdd, 0xd3, 0xf6, 0xd5, 0xcd, 0xf2, 0x56, 0x59, 0x2d,
+	0xa5, 0x22, 0x68, 0x09, 0x16, 0xfa, 0xc3, 0xb4, 0xea, 0xe6, 0xa6, 0xaa, 0x96, 0xd4, 0x52, 0x4a,
+	0x42, 0x32, 0xcc, 0xf7, 0x07, 0x6d, 0x6d, 0x94, 0x77, 0xd4, 0x52, 0x6a, 0xa8, 0xf8, 0x35, 0x0a,
+	0xc3, 0xbc, 0xd9, 0xe8, 0x05, 0x8c, 0x5e, 0x7d, 0x51, 0x50, 0x2e, 0x6c, 0xcb, 0xa0, 0x37, 0x2d,
+	0xb3, 0x74, 0x23, 0x46, 0x58, 0x3f, 0x00, 0x08, 0x6f, 0x35, 0x92, 0x7b, 0x4a, 0xae, 0xbd, 0x29,
+	0x99, 0xc5, 0x1b, 0x10, 0x82, 0x72, 0x0f, 0x12, 0x9d, 0x28, 0x5a, 0x18, 0x84, 0x6f, 0x13, 0xca,
+	0x83, 0x01, 0x82, 0xef, 0x8b, 0x04, 0x63, 0xdd, 0xd3, 0x86, 0xfe, 0xeb, 0x29, 0xea, 0x7b, 0x03,
+	0x33, 0xcb, 0xb7, 0xa0, 0x02, 0xfe, 0xdc, 0xe3, 0xf7, 0xdf, 0x7f, 0x7d, 0x1e, 0x5a, 0x43, 0x45,
+	0xe5, 0x0d, 0x75, 0x8f, 0xea, 0x3a, 0xb1, 0x4d, 0xcb, 0x26, 0x4a, 0xe7, 0x0f, 0xe9, 0x99, 0x67,
+	0xe5, 0xac, 0x73, 0x8b, 0xdf, 0x3d, 0xdd, 0xfe, 0x76, 0x91, 0x95, 0xce, 0x2f, 0xb2, 0xd2, 0xcf,
+	0x8b, 0xac, 0xf4, 0xe9, 0x32, 0x1b, 0x39, 0xbf, 0xcc, 0x46, 0x7e, 0x5c, 0x66, 0x23, 0x2f, 0x0b,
+	0xce, 0xa1, 0x59, 0xe0, 0x84, 0x85, 0x3a, 0x39, 0x09, 0xa8, 0x1f, 0x0a, 0xea, 0x5a, 0xc3, 0xb0,
+	0x6c, 0xe5, 0xad, 0x90, 0xe0, 0x7f, 0xd4, 0xab, 0x38, 0xff, 0xa4, 0x1e, 0xfd, 0x19, 0x00, 0xdc,
+	0x06, 0x60, 0xe2, 0x44, 0x07, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -292,6 +698,13 @@ const _ = grpc.SupportPackageIsVersion4
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
 type QueryClient interface {
 	Transactions(ctx context.Context, in *QueryTransactionsRequest, opts ...grpc.CallOption) (*QueryTransactionsResponse, error)
+	// StateRoots returns the state roots submitted by a game shard, sorted by tick.
+	StateRoots(ctx context.Context, in *QueryStateRootsRequest, opts ...grpc.CallOption) (*QueryStateRootsResponse, error)
+	// StateRoot returns the state root of the range of ticks that contains the given tick.
+	StateRoot(ctx context.Context, in *QueryStateRootRequest, opts ...grpc.CallOption) (*QueryStateRootResponse, error)
+	// RoutedMessages returns the messages that the router sent to the game shard of a namespace, sorted by the height of
+	// the block their result was stored in. Messages are kept for 100000 blocks.
+	RoutedMessages(ctx context.Context, in *QueryRoutedMessagesRequest, opts ...grpc.CallOption) (*QueryRoutedMessagesResponse, error)
 }
 
 type queryClient struct {
@@ -311,9 +724,43 @@ func (c *queryClient) Transactions(ctx context.Context, in *QueryTransactionsReq
 	return out, nil
 }
 
+func (c *queryClient) StateRoots(ctx context.Context, in *QueryStateRootsRequest, opts ...grpc.CallOption) (*QueryStateRootsResponse, error) {
+	out := new(QueryStateRootsResponse)
+	err := c.cc.Invoke(ctx, "/shard.v1.Query/StateRoots", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *queryClient) StateRoot(ctx context.Context, in *QueryStateRootRequest, opts ...grpc.CallOption) (*QueryStateRootResponse, error) {
+	out := new(QueryStateRootResponse)
+	err := c.cc.Invoke(ctx, "/shard.v1.Query/StateRoot", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *queryClient) RoutedMessages(ctx context.Context, in *QueryRoutedMessagesRequest, opts ...grpc.CallOption) (*QueryRoutedMessagesResponse, error) {
+	out := new(QueryRoutedMessagesResponse)
+	err := c.cc.Invoke(ctx, "/shard.v1.Query/RoutedMessages", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // QueryServer is the server API for Query service.
 type QueryServer interface {
 	Transactions(context.Context, *QueryTransactionsRequest) (*QueryTransactionsResponse, error)
+	// StateRoots returns the state roots submitted by a game shard, sorted by tick.
+	StateRoots(context.Context, *QueryStateRootsRequest) (*QueryStateRootsResponse, error)
+	// StateRoot returns the state root of the range of ticks that contains the given tick.
+	StateRoot(context.Context, *QueryStateRootRequest) (*QueryStateRootResponse, error)
+	// RoutedMessages returns the messages that the router sent to the game shard of a namespace, sorted by the height of
+	// the block their result was stored in. Messages are kept for 100000 blocks.
+	RoutedMessages(context.Context, *QueryRoutedMessagesRequest) (*QueryRoutedMessagesResponse, error)
 }
 
 // UnimplementedQueryServer can be embedded to have forward compatible implementations.
@@ -323,6 +770,15 @@ type UnimplementedQueryServer struct {
 func (*UnimplementedQueryServer) Transactions(ctx context.Context, req *QueryTransactionsRequest) (*QueryTransactionsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Transactions not implemented")
 }
+func (*UnimplementedQueryServer) StateRoots(ctx context.Context, req *QueryStateRootsRequest) (*QueryStateRootsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StateRoots not implemented")
+}
+func (*UnimplementedQueryServer) StateRoot(ctx context.Context, req *QueryStateRootRequest) (*QueryStateRootResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StateRoot not implemented")
+}
+func (*UnimplementedQueryServer) RoutedMessages(ctx context.Context, req *QueryRoutedMessagesRequest) (*QueryRoutedMessagesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RoutedMessages not implemented")
+}
 
 func RegisterQueryServer(s grpc1.Server, srv QueryServer) {
 	s.RegisterService(&_Query_serviceDesc, srv)
@@ -346,14 +802,80 @@ func _Query_Transactions_Handler(srv interface{}, ctx context.Context, dec func(
 	return interceptor(ctx, in, info, handler)
 }
 
-var _Query_serviceDesc = grpc.ServiceDesc{
-	ServiceName: "shard.v1.Query",
-	HandlerType: (*QueryServer)(nil),
-	Methods: []grpc.MethodDesc{
+func _Query_StateRoots_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryStateRootsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServer).StateRoots(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/shard.v1.Query/StateRoots",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServer).StateRoots(ctx, req.(*QueryStateRootsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Query_StateRoot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryStateRootRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServer).StateRoot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/shard.v1.Query/StateRoot",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServer).StateRoot(ctx, req.(*QueryStateRootRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Query_RoutedMessages_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryRoutedMessagesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServer).RoutedMessages(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/shard.v1.Query/RoutedMessages",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServer).RoutedMessages(ctx, req.(*QueryRoutedMessagesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Query_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "shard.v1.Query",
+	HandlerType: (*QueryServer)(nil),
+	Methods: []grpc.MethodDesc{
 		{
 			MethodName: "Transactions",
 			Handler:    _Query_Transactions_Handler,
 		},
+		{
+			MethodName: "StateRoots",
+			Handler:    _Query_StateRoots_Handler,
+		},
+		{
+			MethodName: "StateRoot",
+			Handler:    _Query_StateRoot_Handler,
+		},
+		{
+			MethodName: "RoutedMessages",
+			Handler:    _Query_RoutedMessages_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "shard/v1/query.proto",
@@ -450,7 +972,7 @@ func (m *QueryTransactionsResponse) MarshalToSizedBuffer(dAtA []byte) (int, erro
 	return len(dAtA) - i, nil
 }
 
-func (m *PageRequest) Marshal() (dAtA []byte, err error) {
+func (m *QueryStateRootsRequest) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -460,32 +982,123 @@ func (m *PageRequest) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *PageRequest) MarshalTo(dAtA []byte) (int, error) {
+func (m *QueryStateRootsRequest) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *PageRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *QueryStateRootsRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if m.Limit != 0 {
-		i = encodeVarintQuery(dAtA, i, uint64(m.Limit))
+	if m.Page != nil {
+		{
+			size, err := m.Page.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintQuery(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Namespace) > 0 {
+		i -= len(m.Namespace)
+		copy(dAtA[i:], m.Namespace)
+		i = encodeVarintQuery(dAtA, i, uint64(len(m.Namespace)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *QueryStateRootsResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *QueryStateRootsResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *QueryStateRootsResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Page != nil {
+		{
+			size, err := m.Page.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintQuery(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.StateRoots) > 0 {
+		for iNdEx := len(m.StateRoots) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.StateRoots[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintQuery(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *QueryStateRootRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *QueryStateRootRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *QueryStateRootRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Tick != 0 {
+		i = encodeVarintQuery(dAtA, i, uint64(m.Tick))
 		i--
 		dAtA[i] = 0x10
 	}
-	if len(m.Key) > 0 {
-		i -= len(m.Key)
-		copy(dAtA[i:], m.Key)
-		i = encodeVarintQuery(dAtA, i, uint64(len(m.Key)))
+	if len(m.Namespace) > 0 {
+		i -= len(m.Namespace)
+		copy(dAtA[i:], m.Namespace)
+		i = encodeVarintQuery(dAtA, i, uint64(len(m.Namespace)))
 		i--
 		dAtA[i] = 0xa
 	}
 	return len(dAtA) - i, nil
 }
 
-func (m *PageResponse) Marshal() (dAtA []byte, err error) {
+func (m *QueryStateRootResponse) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -495,109 +1108,1069 @@ func (m *PageResponse) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *PageResponse) MarshalTo(dAtA []byte) (int, error) {
+func (m *QueryStateRootResponse) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *PageResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *QueryStateRootResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if len(m.Key) > 0 {
-		i -= len(m.Key)
-		copy(dAtA[i:], m.Key)
-		i = encodeVarintQuery(dAtA, i, uint64(len(m.Key)))
+	if m.StateRoot != nil {
+		{
+			size, err := m.StateRoot.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintQuery(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *QueryRoutedMessagesRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *QueryRoutedMessagesRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *QueryRoutedMessagesRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Page != nil {
+		{
+			size, err := m.Page.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintQuery(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x32
+	}
+	if m.ToHeight != 0 {
+		i = encodeVarintQuery(dAtA, i, uint64(m.ToHeight))
+		i--
+		dAtA[i] = 0x28
+	}
+	if m.FromHeight != 0 {
+		i = encodeVarintQuery(dAtA, i, uint64(m.FromHeight))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.Status != 0 {
+		i = encodeVarintQuery(dAtA, i, uint64(m.Status))
+		i--
+		dAtA[i] = 0x18
+	}
+	if len(m.Sender) > 0 {
+		i -= len(m.Sender)
+		copy(dAtA[i:], m.Sender)
+		i = encodeVarintQuery(dAtA, i, uint64(len(m.Sender)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Namespace) > 0 {
+		i -= len(m.Namespace)
+		copy(dAtA[i:], m.Namespace)
+		i = encodeVarintQuery(dAtA, i, uint64(len(m.Namespace)))
 		i--
 		dAtA[i] = 0xa
 	}
-	return len(dAtA) - i, nil
-}
+	return len(dAtA) - i, nil
+}
+
+func (m *QueryRoutedMessagesResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *QueryRoutedMessagesResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *QueryRoutedMessagesResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Page != nil {
+		{
+			size, err := m.Page.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintQuery(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.RoutedMessages) > 0 {
+		for iNdEx := len(m.RoutedMessages) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.RoutedMessages[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintQuery(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *PageRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *PageRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *PageRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Limit != 0 {
+		i = encodeVarintQuery(dAtA, i, uint64(m.Limit))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Key) > 0 {
+		i -= len(m.Key)
+		copy(dAtA[i:], m.Key)
+		i = encodeVarintQuery(dAtA, i, uint64(len(m.Key)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *PageResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *PageResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *PageResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Key) > 0 {
+		i -= len(m.Key)
+		copy(dAtA[i:], m.Key)
+		i = encodeVarintQuery(dAtA, i, uint64(len(m.Key)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func encodeVarintQuery(dAtA []byte, offset int, v uint64) int {
+	offset -= sovQuery(v)
+	base := offset
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return base
+}
+func (m *QueryTransactionsRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Namespace)
+	if l > 0 {
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	if m.Page != nil {
+		l = m.Page.Size()
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	return n
+}
+
+func (m *QueryTransactionsResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Epochs) > 0 {
+		for _, e := range m.Epochs {
+			l = e.Size()
+			n += 1 + l + sovQuery(uint64(l))
+		}
+	}
+	if m.Page != nil {
+		l = m.Page.Size()
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	return n
+}
+
+func (m *QueryStateRootsRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Namespace)
+	if l > 0 {
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	if m.Page != nil {
+		l = m.Page.Size()
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	return n
+}
+
+func (m *QueryStateRootsResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.StateRoots) > 0 {
+		for _, e := range m.StateRoots {
+			l = e.Size()
+			n += 1 + l + sovQuery(uint64(l))
+		}
+	}
+	if m.Page != nil {
+		l = m.Page.Size()
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	return n
+}
+
+func (m *QueryStateRootRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Namespace)
+	if l > 0 {
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	if m.Tick != 0 {
+		n += 1 + sovQuery(uint64(m.Tick))
+	}
+	return n
+}
+
+func (m *QueryStateRootResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.StateRoot != nil {
+		l = m.StateRoot.Size()
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	return n
+}
+
+func (m *QueryRoutedMessagesRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Namespace)
+	if l > 0 {
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	l = len(m.Sender)
+	if l > 0 {
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	if m.Status != 0 {
+		n += 1 + sovQuery(uint64(m.Status))
+	}
+	if m.FromHeight != 0 {
+		n += 1 + sovQuery(uint64(m.FromHeight))
+	}
+	if m.ToHeight != 0 {
+		n += 1 + sovQuery(uint64(m.ToHeight))
+	}
+	if m.Page != nil {
+		l = m.Page.Size()
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	return n
+}
+
+func (m *QueryRoutedMessagesResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.RoutedMessages) > 0 {
+		for _, e := range m.RoutedMessages {
+			l = e.Size()
+			n += 1 + l + sovQuery(uint64(l))
+		}
+	}
+	if m.Page != nil {
+		l = m.Page.Size()
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	return n
+}
+
+func (m *PageRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Key)
+	if l > 0 {
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	if m.Limit != 0 {
+		n += 1 + sovQuery(uint64(m.Limit))
+	}
+	return n
+}
+
+func (m *PageResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Key)
+	if l > 0 {
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	return n
+}
+
+func sovQuery(x uint64) (n int) {
+	return (math_bits.Len64(x|1) + 6) / 7
+}
+func sozQuery(x uint64) (n int) {
+	return sovQuery(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (m *QueryTransactionsRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowQuery
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: QueryTransactionsRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: QueryTransactionsRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Namespace", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Namespace = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Page", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Page == nil {
+				m.Page = &PageRequest{}
+			}
+			if err := m.Page.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipQuery(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *QueryTransactionsResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowQuery
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: QueryTransactionsResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: QueryTransactionsResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Epochs", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Epochs = append(m.Epochs, &Epoch{})
+			if err := m.Epochs[len(m.Epochs)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Page", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Page == nil {
+				m.Page = &PageResponse{}
+			}
+			if err := m.Page.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipQuery(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *QueryStateRootsRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowQuery
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: QueryStateRootsRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: QueryStateRootsRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Namespace", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Namespace = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Page", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Page == nil {
+				m.Page = &PageRequest{}
+			}
+			if err := m.Page.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipQuery(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *QueryStateRootsResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowQuery
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: QueryStateRootsResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: QueryStateRootsResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StateRoots", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.StateRoots = append(m.StateRoots, &StateRoot{})
+			if err := m.StateRoots[len(m.StateRoots)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Page", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Page == nil {
+				m.Page = &PageResponse{}
+			}
+			if err := m.Page.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipQuery(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *QueryStateRootRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowQuery
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: QueryStateRootRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: QueryStateRootRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Namespace", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Namespace = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Tick", wireType)
+			}
+			m.Tick = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Tick |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipQuery(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
 
-func encodeVarintQuery(dAtA []byte, offset int, v uint64) int {
-	offset -= sovQuery(v)
-	base := offset
-	for v >= 1<<7 {
-		dAtA[offset] = uint8(v&0x7f | 0x80)
-		v >>= 7
-		offset++
-	}
-	dAtA[offset] = uint8(v)
-	return base
-}
-func (m *QueryTransactionsRequest) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	l = len(m.Namespace)
-	if l > 0 {
-		n += 1 + l + sovQuery(uint64(l))
-	}
-	if m.Page != nil {
-		l = m.Page.Size()
-		n += 1 + l + sovQuery(uint64(l))
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
 	}
-	return n
+	return nil
 }
-
-func (m *QueryTransactionsResponse) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	if len(m.Epochs) > 0 {
-		for _, e := range m.Epochs {
-			l = e.Size()
-			n += 1 + l + sovQuery(uint64(l))
+func (m *QueryStateRootResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowQuery
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: QueryStateRootResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: QueryStateRootResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StateRoot", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.StateRoot == nil {
+				m.StateRoot = &StateRoot{}
+			}
+			if err := m.StateRoot.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipQuery(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
 		}
 	}
-	if m.Page != nil {
-		l = m.Page.Size()
-		n += 1 + l + sovQuery(uint64(l))
-	}
-	return n
-}
-
-func (m *PageRequest) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	l = len(m.Key)
-	if l > 0 {
-		n += 1 + l + sovQuery(uint64(l))
-	}
-	if m.Limit != 0 {
-		n += 1 + sovQuery(uint64(m.Limit))
-	}
-	return n
-}
 
-func (m *PageResponse) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	l = len(m.Key)
-	if l > 0 {
-		n += 1 + l + sovQuery(uint64(l))
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
 	}
-	return n
-}
-
-func sovQuery(x uint64) (n int) {
-	return (math_bits.Len64(x|1) + 6) / 7
-}
-func sozQuery(x uint64) (n int) {
-	return sovQuery(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+	return nil
 }
-func (m *QueryTransactionsRequest) Unmarshal(dAtA []byte) error {
+func (m *QueryRoutedMessagesRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -620,10 +2193,10 @@ func (m *QueryTransactionsRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: QueryTransactionsRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: QueryRoutedMessagesRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: QueryTransactionsRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: QueryRoutedMessagesRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
@@ -659,6 +2232,95 @@ func (m *QueryTransactionsRequest) Unmarshal(dAtA []byte) error {
 			m.Namespace = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Sender", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Sender = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Status", wireType)
+			}
+			m.Status = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Status |= RoutedMessageStatus(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FromHeight", wireType)
+			}
+			m.FromHeight = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.FromHeight |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ToHeight", wireType)
+			}
+			m.ToHeight = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ToHeight |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 6:
 			if wireType != 2 {
 				return fmt.Errorf("proto: wrong wireType = %d for field Page", wireType)
 			}
@@ -715,7 +2377,7 @@ func (m *QueryTransactionsRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *QueryTransactionsResponse) Unmarshal(dAtA []byte) error {
+func (m *QueryRoutedMessagesResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -738,15 +2400,15 @@ func (m *QueryTransactionsResponse) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: QueryTransactionsResponse: wiretype end group for non-group")
+			return fmt.Errorf("proto: QueryRoutedMessagesResponse: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: QueryTransactionsResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: QueryRoutedMessagesResponse: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Epochs", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field RoutedMessages", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -773,8 +2435,8 @@ func (m *QueryTransactionsResponse) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Epochs = append(m.Epochs, &Epoch{})
-			if err := m.Epochs[len(m.Epochs)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			m.RoutedMessages = append(m.RoutedMessages, &RoutedMessage{})
+			if err := m.RoutedMessages[len(m.RoutedMessages)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
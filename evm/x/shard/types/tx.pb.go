@@ -147,39 +147,165 @@ func (m *SubmitShardTxResponse) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_SubmitShardTxResponse proto.InternalMessageInfo
 
+// SubmitStateRootRequest commits a game shard to its state at the end of a range of ticks, so that third parties can
+// verify the results the game shard publishes against it.
+type SubmitStateRootRequest struct {
+	// sender is the address of the sender. this will be set to the module address.
+	Sender string `protobuf:"bytes,1,opt,name=sender,proto3" json:"sender,omitempty"`
+	// namespace is the namespace of the world the state root belongs to.
+	Namespace string `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	// start_tick is the first tick of the range. it must follow the end tick of the last state root of the namespace.
+	StartTick uint64 `protobuf:"varint,3,opt,name=start_tick,json=startTick,proto3" json:"start_tick,omitempty"`
+	// end_tick is the last tick of the range.
+	EndTick uint64 `protobuf:"varint,4,opt,name=end_tick,json=endTick,proto3" json:"end_tick,omitempty"`
+	// state_root is the 32 byte merkle root of the state of the game shard after end_tick.
+	StateRoot []byte `protobuf:"bytes,5,opt,name=state_root,json=stateRoot,proto3" json:"state_root,omitempty"`
+}
+
+func (m *SubmitStateRootRequest) Reset()         { *m = SubmitStateRootRequest{} }
+func (m *SubmitStateRootRequest) String() string { return proto.CompactTextString(m) }
+func (*SubmitStateRootRequest) ProtoMessage()    {}
+func (*SubmitStateRootRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2ea9067d7c94eab8, []int{2}
+}
+func (m *SubmitStateRootRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *SubmitStateRootRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_SubmitStateRootRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *SubmitStateRootRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SubmitStateRootRequest.Merge(m, src)
+}
+func (m *SubmitStateRootRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *SubmitStateRootRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SubmitStateRootRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SubmitStateRootRequest proto.InternalMessageInfo
+
+func (m *SubmitStateRootRequest) GetSender() string {
+	if m != nil {
+		return m.Sender
+	}
+	return ""
+}
+
+func (m *SubmitStateRootRequest) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *SubmitStateRootRequest) GetStartTick() uint64 {
+	if m != nil {
+		return m.StartTick
+	}
+	return 0
+}
+
+func (m *SubmitStateRootRequest) GetEndTick() uint64 {
+	if m != nil {
+		return m.EndTick
+	}
+	return 0
+}
+
+func (m *SubmitStateRootRequest) GetStateRoot() []byte {
+	if m != nil {
+		return m.StateRoot
+	}
+	return nil
+}
+
+type SubmitStateRootResponse struct {
+}
+
+func (m *SubmitStateRootResponse) Reset()         { *m = SubmitStateRootResponse{} }
+func (m *SubmitStateRootResponse) String() string { return proto.CompactTextString(m) }
+func (*SubmitStateRootResponse) ProtoMessage()    {}
+func (*SubmitStateRootResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2ea9067d7c94eab8, []int{3}
+}
+func (m *SubmitStateRootResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *SubmitStateRootResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_SubmitStateRootResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *SubmitStateRootResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SubmitStateRootResponse.Merge(m, src)
+}
+func (m *SubmitStateRootResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *SubmitStateRootResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SubmitStateRootResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SubmitStateRootResponse proto.InternalMessageInfo
+
 func init() {
 	proto.RegisterType((*SubmitShardTxRequest)(nil), "shard.v1.SubmitShardTxRequest")
 	proto.RegisterType((*SubmitShardTxResponse)(nil), "shard.v1.SubmitShardTxResponse")
+	proto.RegisterType((*SubmitStateRootRequest)(nil), "shard.v1.SubmitStateRootRequest")
+	proto.RegisterType((*SubmitStateRootResponse)(nil), "shard.v1.SubmitStateRootResponse")
 }
 
 func init() { proto.RegisterFile("shard/v1/tx.proto", fileDescriptor_2ea9067d7c94eab8) }
 
 var fileDescriptor_2ea9067d7c94eab8 = []byte{
-	// 370 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x51, 0xcd, 0x4e, 0xea, 0x40,
-	0x14, 0x66, 0x6e, 0x81, 0x5c, 0x86, 0x70, 0x93, 0x3b, 0x29, 0xa1, 0xb7, 0xb9, 0xa9, 0x84, 0xc4,
-	0x48, 0x48, 0xe8, 0x08, 0xee, 0xdc, 0xc9, 0xca, 0x8d, 0x89, 0x29, 0xac, 0x5c, 0x48, 0x4a, 0x3b,
-	0x29, 0x8d, 0x76, 0xa6, 0xf6, 0x0c, 0x58, 0x77, 0xc6, 0x27, 0xf0, 0x51, 0x58, 0xf8, 0x10, 0x2e,
-	0x89, 0x2b, 0xdd, 0x19, 0x58, 0xf0, 0x1a, 0xa6, 0x3f, 0x84, 0x68, 0x74, 0x37, 0xdf, 0xcf, 0xf9,
-	0x66, 0xe6, 0x7c, 0xf8, 0x2f, 0x4c, 0xed, 0xc8, 0xa5, 0xf3, 0x1e, 0x95, 0xb1, 0x19, 0x46, 0x42,
-	0x0a, 0xf2, 0x3b, 0xa5, 0xcc, 0x79, 0x4f, 0xff, 0xe7, 0x08, 0x08, 0x04, 0x8c, 0x53, 0x9e, 0x66,
-	0x20, 0x33, 0xe9, 0x8d, 0x0c, 0xd1, 0x00, 0xbc, 0x64, 0x38, 0x00, 0x2f, 0x17, 0xd4, 0x5d, 0xe0,
-	0x5d, 0xc8, 0x72, 0x7b, 0xeb, 0x0d, 0x61, 0x75, 0x38, 0x9b, 0x04, 0xbe, 0x1c, 0x26, 0xf2, 0x28,
-	0xb6, 0xd8, 0xcd, 0x8c, 0x81, 0x24, 0x87, 0xb8, 0x0c, 0x8c, 0xbb, 0x2c, 0xd2, 0x50, 0x13, 0xb5,
-	0x2b, 0x03, 0xed, 0xe5, 0xa9, 0xab, 0xe6, 0x37, 0x9d, 0xb8, 0x6e, 0xc4, 0x00, 0x86, 0x32, 0xf2,
-	0xb9, 0x67, 0xe5, 0x3e, 0xf2, 0x1f, 0x57, 0xb8, 0x1d, 0x30, 0x08, 0x6d, 0x87, 0x69, 0xbf, 0x92,
-	0x21, 0x6b, 0x47, 0x10, 0x15, 0x97, 0x58, 0x28, 0x9c, 0xa9, 0xa6, 0x34, 0x51, 0xbb, 0x68, 0x65,
-	0x80, 0xec, 0xe3, 0x3f, 0x33, 0xee, 0xc7, 0x63, 0xe9, 0x07, 0x0c, 0xa4, 0x1d, 0x84, 0x5a, 0x31,
-	0x95, 0x6b, 0x09, 0x3b, 0xda, 0x92, 0xe4, 0x00, 0x2b, 0x32, 0x06, 0xad, 0xd4, 0x54, 0xda, 0xd5,
-	0x7e, 0xdd, 0xdc, 0xee, 0xc1, 0x1c, 0x45, 0x36, 0x07, 0xdb, 0x91, 0xbe, 0xe0, 0x56, 0xe2, 0x38,
-	0xae, 0x3e, 0x6c, 0x16, 0x9d, 0xfc, 0x41, 0xad, 0x06, 0xae, 0x7f, 0xf9, 0x1a, 0x84, 0x82, 0x03,
-	0xeb, 0x5f, 0x62, 0xe5, 0x0c, 0x3c, 0x72, 0x8e, 0x6b, 0x9f, 0x74, 0x62, 0xec, 0x92, 0xbf, 0xdb,
-	0x89, 0xbe, 0xf7, 0xa3, 0x9e, 0x05, 0xeb, 0xa5, 0xfb, 0xcd, 0xa2, 0x83, 0x06, 0xa7, 0xcf, 0x2b,
-	0x03, 0x2d, 0x57, 0x06, 0x7a, 0x5f, 0x19, 0xe8, 0x71, 0x6d, 0x14, 0x96, 0x6b, 0xa3, 0xf0, 0xba,
-	0x36, 0x0a, 0x17, 0x66, 0x78, 0xe5, 0x99, 0xb7, 0x22, 0xba, 0x76, 0x4d, 0x97, 0xcd, 0x69, 0x7a,
-	0xea, 0x32, 0xee, 0xf9, 0x9c, 0x51, 0x67, 0x6a, 0xfb, 0x9c, 0xc6, 0x34, 0xeb, 0x29, 0x2d, 0x69,
-	0x52, 0x4e, 0x5b, 0x3a, 0xfa, 0x08, 0x00, 0x00, 0xff, 0xff, 0x70, 0x9c, 0x49, 0x6d, 0x0e, 0x02,
-	0x00, 0x00,
+	// 464 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x52, 0x3f, 0x6f, 0xd3, 0x40,
+	0x14, 0xcf, 0x91, 0xa6, 0x34, 0x57, 0x0a, 0xe2, 0x94, 0x12, 0xc7, 0x02, 0x63, 0x22, 0x21, 0xa2,
+	0x4a, 0xb5, 0x69, 0xd9, 0xd8, 0xe8, 0xc4, 0x82, 0x84, 0x9c, 0x88, 0x81, 0x25, 0x72, 0xed, 0x27,
+	0xe7, 0x14, 0x7c, 0x67, 0xee, 0x5e, 0x82, 0xd9, 0x10, 0x9f, 0x80, 0x8f, 0xd2, 0x01, 0xf1, 0x19,
+	0x98, 0x50, 0xc5, 0x04, 0x1b, 0x4a, 0x86, 0x7e, 0x0d, 0xe4, 0x3b, 0x57, 0x51, 0x13, 0x60, 0x63,
+	0xbb, 0xf7, 0xfb, 0xf3, 0xee, 0xfd, 0x9e, 0x1e, 0xbd, 0xad, 0x27, 0xb1, 0x4a, 0xc3, 0xf9, 0x51,
+	0x88, 0x65, 0x50, 0x28, 0x89, 0x92, 0xed, 0x18, 0x28, 0x98, 0x1f, 0xb9, 0xbd, 0x44, 0xea, 0x5c,
+	0xea, 0xb1, 0xc1, 0x43, 0x5b, 0x58, 0x91, 0xdb, 0xb5, 0x55, 0x98, 0xeb, 0xac, 0x32, 0xe7, 0x3a,
+	0xab, 0x89, 0xce, 0xaa, 0xe1, 0xfb, 0x02, 0x6a, 0x79, 0xff, 0x27, 0xa1, 0x9d, 0xe1, 0xec, 0x34,
+	0xe7, 0x38, 0xac, 0xe8, 0x51, 0x19, 0xc1, 0xdb, 0x19, 0x68, 0x64, 0x8f, 0xe9, 0xb6, 0x06, 0x91,
+	0x82, 0x72, 0x88, 0x4f, 0x06, 0xed, 0x13, 0xe7, 0xfb, 0xe7, 0xc3, 0x4e, 0xfd, 0xd3, 0xb3, 0x34,
+	0x55, 0xa0, 0xf5, 0x10, 0x15, 0x17, 0x59, 0x54, 0xeb, 0xd8, 0x5d, 0xda, 0x16, 0x71, 0x0e, 0xba,
+	0x88, 0x13, 0x70, 0xae, 0x55, 0xa6, 0x68, 0x05, 0xb0, 0x0e, 0x6d, 0x41, 0x21, 0x93, 0x89, 0xd3,
+	0xf4, 0xc9, 0x60, 0x2b, 0xb2, 0x05, 0x7b, 0x48, 0x6f, 0xce, 0x04, 0x2f, 0xc7, 0xc8, 0x73, 0xd0,
+	0x18, 0xe7, 0x85, 0xb3, 0x65, 0xe8, 0xbd, 0x0a, 0x1d, 0x5d, 0x82, 0xec, 0x11, 0x6d, 0x62, 0xa9,
+	0x9d, 0x96, 0xdf, 0x1c, 0xec, 0x1e, 0xef, 0x07, 0x97, 0x7b, 0x08, 0x46, 0x2a, 0x16, 0x3a, 0x4e,
+	0x90, 0x4b, 0x11, 0x55, 0x8a, 0xa7, 0xbb, 0x1f, 0x2f, 0xce, 0x0e, 0xea, 0x81, 0xfa, 0x5d, 0xba,
+	0xbf, 0x16, 0x4d, 0x17, 0x52, 0x68, 0xe8, 0x7f, 0x23, 0xf4, 0x4e, 0xcd, 0x60, 0x8c, 0x10, 0x49,
+	0x89, 0xff, 0x2b, 0xf6, 0x3d, 0x4a, 0x35, 0xc6, 0x0a, 0xc7, 0xc8, 0x93, 0x69, 0x9d, 0xbd, 0x6d,
+	0x90, 0x11, 0x4f, 0xa6, 0xac, 0x47, 0x77, 0x40, 0xa4, 0x96, 0xb4, 0xc9, 0xaf, 0x83, 0x48, 0x0d,
+	0x65, 0x9d, 0x08, 0x63, 0x25, 0x25, 0x3a, 0x2d, 0x9f, 0x0c, 0x6e, 0x18, 0xa7, 0x9d, 0xf7, 0x6a,
+	0xd2, 0x1e, 0xed, 0x6e, 0xe4, 0xb1, 0x59, 0x8f, 0xbf, 0x10, 0xda, 0x7c, 0xa1, 0x33, 0xf6, 0x92,
+	0xee, 0x5d, 0x59, 0x06, 0xf3, 0x56, 0x6b, 0xfc, 0xd3, 0x01, 0xb8, 0xf7, 0xff, 0xca, 0xdb, 0xce,
+	0xec, 0x15, 0xbd, 0xb5, 0xf6, 0x29, 0xf3, 0x37, 0x3c, 0x6b, 0xfb, 0x75, 0x1f, 0xfc, 0x43, 0x61,
+	0xfb, 0xba, 0xad, 0x0f, 0x17, 0x67, 0x07, 0xe4, 0xe4, 0xf9, 0xd7, 0x85, 0x47, 0xce, 0x17, 0x1e,
+	0xf9, 0xb5, 0xf0, 0xc8, 0xa7, 0xa5, 0xd7, 0x38, 0x5f, 0x7a, 0x8d, 0x1f, 0x4b, 0xaf, 0xf1, 0x3a,
+	0x28, 0xa6, 0x59, 0xf0, 0x4e, 0xaa, 0x37, 0x69, 0x90, 0xc2, 0x3c, 0x34, 0xaf, 0x43, 0x10, 0x19,
+	0x17, 0x10, 0x26, 0x93, 0x98, 0x8b, 0xb0, 0x0c, 0xed, 0xb1, 0x9b, 0x4b, 0x3f, 0xdd, 0x36, 0xa7,
+	0xfe, 0xe4, 0x77, 0x00, 0x00, 0x00, 0xff, 0xff, 0x20, 0x61, 0xd7, 0xa1, 0x53, 0x03, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -195,6 +321,7 @@ const _ = grpc.SupportPackageIsVersion4
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
 type MsgClient interface {
 	SubmitShardTx(ctx context.Context, in *SubmitShardTxRequest, opts ...grpc.CallOption) (*SubmitShardTxResponse, error)
+	SubmitStateRoot(ctx context.Context, in *SubmitStateRootRequest, opts ...grpc.CallOption) (*SubmitStateRootResponse, error)
 }
 
 type msgClient struct {
@@ -214,9 +341,19 @@ func (c *msgClient) SubmitShardTx(ctx context.Context, in *SubmitShardTxRequest,
 	return out, nil
 }
 
+func (c *msgClient) SubmitStateRoot(ctx context.Context, in *SubmitStateRootRequest, opts ...grpc.CallOption) (*SubmitStateRootResponse, error) {
+	out := new(SubmitStateRootResponse)
+	err := c.cc.Invoke(ctx, "/shard.v1.Msg/SubmitStateRoot", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // MsgServer is the server API for Msg service.
 type MsgServer interface {
 	SubmitShardTx(context.Context, *SubmitShardTxRequest) (*SubmitShardTxResponse, error)
+	SubmitStateRoot(context.Context, *SubmitStateRootRequest) (*SubmitStateRootResponse, error)
 }
 
 // UnimplementedMsgServer can be embedded to have forward compatible implementations.
@@ -226,6 +363,9 @@ type UnimplementedMsgServer struct {
 func (*UnimplementedMsgServer) SubmitShardTx(ctx context.Context, req *SubmitShardTxRequest) (*SubmitShardTxResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SubmitShardTx not implemented")
 }
+func (*UnimplementedMsgServer) SubmitStateRoot(ctx context.Context, req *SubmitStateRootRequest) (*SubmitStateRootResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SubmitStateRoot not implemented")
+}
 
 func RegisterMsgServer(s grpc1.Server, srv MsgServer) {
 	s.RegisterService(&_Msg_serviceDesc, srv)
@@ -249,6 +389,24 @@ func _Msg_SubmitShardTx_Handler(srv interface{}, ctx context.Context, dec func(i
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Msg_SubmitStateRoot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SubmitStateRootRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MsgServer).SubmitStateRoot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/shard.v1.Msg/SubmitStateRoot",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MsgServer).SubmitStateRoot(ctx, req.(*SubmitStateRootRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Msg_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "shard.v1.Msg",
 	HandlerType: (*MsgServer)(nil),
@@ -257,6 +415,10 @@ var _Msg_serviceDesc = grpc.ServiceDesc{
 			MethodName: "SubmitShardTx",
 			Handler:    _Msg_SubmitShardTx_Handler,
 		},
+		{
+			MethodName: "SubmitStateRoot",
+			Handler:    _Msg_SubmitStateRoot_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "shard/v1/tx.proto",
@@ -346,6 +508,83 @@ func (m *SubmitShardTxResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *SubmitStateRootRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SubmitStateRootRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SubmitStateRootRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.StateRoot) > 0 {
+		i -= len(m.StateRoot)
+		copy(dAtA[i:], m.StateRoot)
+		i = encodeVarintTx(dAtA, i, uint64(len(m.StateRoot)))
+		i--
+		dAtA[i] = 0x2a
+	}
+	if m.EndTick != 0 {
+		i = encodeVarintTx(dAtA, i, uint64(m.EndTick))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.StartTick != 0 {
+		i = encodeVarintTx(dAtA, i, uint64(m.StartTick))
+		i--
+		dAtA[i] = 0x18
+	}
+	if len(m.Namespace) > 0 {
+		i -= len(m.Namespace)
+		copy(dAtA[i:], m.Namespace)
+		i = encodeVarintTx(dAtA, i, uint64(len(m.Namespace)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Sender) > 0 {
+		i -= len(m.Sender)
+		copy(dAtA[i:], m.Sender)
+		i = encodeVarintTx(dAtA, i, uint64(len(m.Sender)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *SubmitStateRootResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SubmitStateRootResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SubmitStateRootResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	return len(dAtA) - i, nil
+}
+
 func encodeVarintTx(dAtA []byte, offset int, v uint64) int {
 	offset -= sovTx(v)
 	base := offset
@@ -395,6 +634,42 @@ func (m *SubmitShardTxResponse) Size() (n int) {
 	return n
 }
 
+func (m *SubmitStateRootRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Sender)
+	if l > 0 {
+		n += 1 + l + sovTx(uint64(l))
+	}
+	l = len(m.Namespace)
+	if l > 0 {
+		n += 1 + l + sovTx(uint64(l))
+	}
+	if m.StartTick != 0 {
+		n += 1 + sovTx(uint64(m.StartTick))
+	}
+	if m.EndTick != 0 {
+		n += 1 + sovTx(uint64(m.EndTick))
+	}
+	l = len(m.StateRoot)
+	if l > 0 {
+		n += 1 + l + sovTx(uint64(l))
+	}
+	return n
+}
+
+func (m *SubmitStateRootResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	return n
+}
+
 func sovTx(x uint64) (n int) {
 	return (math_bits.Len64(x|1) + 6) / 7
 }
@@ -637,6 +912,242 @@ func (m *SubmitShardTxResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *SubmitStateRootRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTx
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SubmitStateRootRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SubmitStateRootRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Sender", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTx
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTx
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Sender = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Namespace", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTx
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTx
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Namespace = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StartTick", wireType)
+			}
+			m.StartTick = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.StartTick |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field EndTick", wireType)
+			}
+			m.EndTick = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.EndTick |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StateRoot", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthTx
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTx
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.StateRoot = append(m.StateRoot[:0], dAtA[iNdEx:postIndex]...)
+			if m.StateRoot == nil {
+				m.StateRoot = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTx(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTx
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SubmitStateRootResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTx
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SubmitStateRootResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SubmitStateRootResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTx(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTx
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func skipTx(dAtA []byte) (n int, err error) {
 	l := len(dAtA)
 	iNdEx := 0
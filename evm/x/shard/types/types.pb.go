@@ -138,32 +138,239 @@ func (m *Epoch) GetTxs() []*Transaction {
 	return nil
 }
 
+// StateRoot is a commitment of a game shard to its state at the end of a range of ticks.
+type StateRoot struct {
+	// start_tick is the first tick of the range.
+	StartTick uint64 `protobuf:"varint,1,opt,name=start_tick,json=startTick,proto3" json:"start_tick,omitempty"`
+	// end_tick is the last tick of the range. the state root commits to the state of the game shard after this tick.
+	EndTick uint64 `protobuf:"varint,2,opt,name=end_tick,json=endTick,proto3" json:"end_tick,omitempty"`
+	// state_root is the 32 byte merkle root of the state of the game shard.
+	StateRoot []byte `protobuf:"bytes,3,opt,name=state_root,json=stateRoot,proto3" json:"state_root,omitempty"`
+	// block_height is the height of the block the state root was submitted in.
+	BlockHeight int64 `protobuf:"varint,4,opt,name=block_height,json=blockHeight,proto3" json:"block_height,omitempty"`
+}
+
+func (m *StateRoot) Reset()         { *m = StateRoot{} }
+func (m *StateRoot) String() string { return proto.CompactTextString(m) }
+func (*StateRoot) ProtoMessage()    {}
+func (*StateRoot) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0a60f84bb846c47b, []int{2}
+}
+func (m *StateRoot) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *StateRoot) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_StateRoot.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *StateRoot) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StateRoot.Merge(m, src)
+}
+func (m *StateRoot) XXX_Size() int {
+	return m.Size()
+}
+func (m *StateRoot) XXX_DiscardUnknown() {
+	xxx_messageInfo_StateRoot.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StateRoot proto.InternalMessageInfo
+
+func (m *StateRoot) GetStartTick() uint64 {
+	if m != nil {
+		return m.StartTick
+	}
+	return 0
+}
+
+func (m *StateRoot) GetEndTick() uint64 {
+	if m != nil {
+		return m.EndTick
+	}
+	return 0
+}
+
+func (m *StateRoot) GetStateRoot() []byte {
+	if m != nil {
+		return m.StateRoot
+	}
+	return nil
+}
+
+func (m *StateRoot) GetBlockHeight() int64 {
+	if m != nil {
+		return m.BlockHeight
+	}
+	return 0
+}
+
+// RoutedMessage is a message that the router sent from an EVM transaction to a game shard, along with its result.
+type RoutedMessage struct {
+	// evm_tx_hash is the hash of the EVM transaction that sent the message.
+	EvmTxHash string `protobuf:"bytes,1,opt,name=evm_tx_hash,json=evmTxHash,proto3" json:"evm_tx_hash,omitempty"`
+	// sender is the address of the EVM contract that sent the message.
+	Sender string `protobuf:"bytes,2,opt,name=sender,proto3" json:"sender,omitempty"`
+	// persona_tag is the persona the message was sent on behalf of.
+	PersonaTag string `protobuf:"bytes,3,opt,name=persona_tag,json=personaTag,proto3" json:"persona_tag,omitempty"`
+	// message_id is the name of the game shard message.
+	MessageId string `protobuf:"bytes,4,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	// message is the encoded game shard message.
+	Message []byte `protobuf:"bytes,5,opt,name=message,proto3" json:"message,omitempty"`
+	// code is the result code of the message. 0 means the game shard executed it successfully, any other code is an error
+	// of the game shard or of the router.
+	Code uint32 `protobuf:"varint,6,opt,name=code,proto3" json:"code,omitempty"`
+	// errs contains the errors that occurred while routing or executing the message, if any.
+	Errs string `protobuf:"bytes,7,opt,name=errs,proto3" json:"errs,omitempty"`
+	// result is the encoded result returned by the game shard.
+	Result []byte `protobuf:"bytes,8,opt,name=result,proto3" json:"result,omitempty"`
+	// block_height is the height of the block the result of the message was stored in.
+	BlockHeight int64 `protobuf:"varint,9,opt,name=block_height,json=blockHeight,proto3" json:"block_height,omitempty"`
+}
+
+func (m *RoutedMessage) Reset()         { *m = RoutedMessage{} }
+func (m *RoutedMessage) String() string { return proto.CompactTextString(m) }
+func (*RoutedMessage) ProtoMessage()    {}
+func (*RoutedMessage) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0a60f84bb846c47b, []int{3}
+}
+func (m *RoutedMessage) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *RoutedMessage) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_RoutedMessage.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *RoutedMessage) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RoutedMessage.Merge(m, src)
+}
+func (m *RoutedMessage) XXX_Size() int {
+	return m.Size()
+}
+func (m *RoutedMessage) XXX_DiscardUnknown() {
+	xxx_messageInfo_RoutedMessage.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RoutedMessage proto.InternalMessageInfo
+
+func (m *RoutedMessage) GetEvmTxHash() string {
+	if m != nil {
+		return m.EvmTxHash
+	}
+	return ""
+}
+
+func (m *RoutedMessage) GetSender() string {
+	if m != nil {
+		return m.Sender
+	}
+	return ""
+}
+
+func (m *RoutedMessage) GetPersonaTag() string {
+	if m != nil {
+		return m.PersonaTag
+	}
+	return ""
+}
+
+func (m *RoutedMessage) GetMessageId() string {
+	if m != nil {
+		return m.MessageId
+	}
+	return ""
+}
+
+func (m *RoutedMessage) GetMessage() []byte {
+	if m != nil {
+		return m.Message
+	}
+	return nil
+}
+
+func (m *RoutedMessage) GetCode() uint32 {
+	if m != nil {
+		return m.Code
+	}
+	return 0
+}
+
+func (m *RoutedMessage) GetErrs() string {
+	if m != nil {
+		return m.Errs
+	}
+	return ""
+}
+
+func (m *RoutedMessage) GetResult() []byte {
+	if m != nil {
+		return m.Result
+	}
+	return nil
+}
+
+func (m *RoutedMessage) GetBlockHeight() int64 {
+	if m != nil {
+		return m.BlockHeight
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterType((*Transaction)(nil), "shard.v1.Transaction")
 	proto.RegisterType((*Epoch)(nil), "shard.v1.Epoch")
+	proto.RegisterType((*StateRoot)(nil), "shard.v1.StateRoot")
+	proto.RegisterType((*RoutedMessage)(nil), "shard.v1.RoutedMessage")
 }
 
 func init() { proto.RegisterFile("shard/v1/types.proto", fileDescriptor_0a60f84bb846c47b) }
 
 var fileDescriptor_0a60f84bb846c47b = []byte{
-	// 262 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x12, 0x29, 0xce, 0x48, 0x2c,
-	0x4a, 0xd1, 0x2f, 0x33, 0xd4, 0x2f, 0xa9, 0x2c, 0x48, 0x2d, 0xd6, 0x2b, 0x28, 0xca, 0x2f, 0xc9,
-	0x17, 0xe2, 0x00, 0x8b, 0xea, 0x95, 0x19, 0x2a, 0x45, 0x70, 0x71, 0x87, 0x14, 0x25, 0xe6, 0x15,
-	0x27, 0x26, 0x97, 0x64, 0xe6, 0xe7, 0x09, 0x09, 0x73, 0xb1, 0x96, 0x54, 0xc4, 0x67, 0xa6, 0x48,
-	0x30, 0x2a, 0x30, 0x6a, 0xb0, 0x04, 0xb1, 0x94, 0x54, 0x78, 0xa6, 0x08, 0x99, 0x70, 0x89, 0xa5,
-	0x27, 0xe6, 0xa6, 0xc6, 0x83, 0x35, 0xc5, 0x97, 0x20, 0x94, 0x4b, 0x30, 0x29, 0x30, 0x6a, 0xf0,
-	0x04, 0x89, 0x80, 0x64, 0x83, 0x41, 0x92, 0x48, 0x46, 0x29, 0xe5, 0x72, 0xb1, 0xba, 0x16, 0xe4,
-	0x27, 0x67, 0x08, 0x89, 0x70, 0xb1, 0xa6, 0x82, 0x18, 0x50, 0x33, 0x21, 0x1c, 0x21, 0x55, 0x2e,
-	0xbe, 0xd2, 0xbc, 0xcc, 0x8a, 0xf8, 0x92, 0xcc, 0xdc, 0xd4, 0xe2, 0x92, 0xc4, 0xdc, 0x02, 0xb0,
-	0x61, 0x2c, 0x41, 0xbc, 0x20, 0xd1, 0x10, 0x98, 0xa0, 0x90, 0x3a, 0x17, 0x73, 0x49, 0x45, 0xb1,
-	0x04, 0xb3, 0x02, 0xb3, 0x06, 0xb7, 0x91, 0xa8, 0x1e, 0xcc, 0xdd, 0x7a, 0x48, 0x36, 0x05, 0x81,
-	0x54, 0x38, 0x79, 0x9c, 0x78, 0x24, 0xc7, 0x78, 0xe1, 0x91, 0x1c, 0xe3, 0x83, 0x47, 0x72, 0x8c,
-	0x13, 0x1e, 0xcb, 0x31, 0x5c, 0x78, 0x2c, 0xc7, 0x70, 0xe3, 0xb1, 0x1c, 0x43, 0x94, 0x5e, 0x41,
-	0x76, 0xba, 0x5e, 0x79, 0x7e, 0x51, 0x4e, 0x8a, 0x5e, 0x4a, 0x6a, 0x99, 0x3e, 0x98, 0xa5, 0x9b,
-	0x9a, 0x97, 0x9e, 0x99, 0x97, 0xaa, 0x9f, 0x9c, 0x91, 0x98, 0x99, 0xa7, 0x5f, 0xa1, 0x0f, 0x09,
-	0x25, 0x70, 0x10, 0x25, 0xb1, 0x81, 0xc3, 0xc8, 0x18, 0x10, 0x00, 0x00, 0xff, 0xff, 0x39, 0xd2,
-	0x46, 0x65, 0x3b, 0x01, 0x00, 0x00,
+	// 472 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x64, 0x92, 0x3f, 0x8f, 0xd3, 0x4c,
+	0x10, 0xc6, 0xe3, 0x8b, 0xf3, 0xc7, 0x93, 0xcb, 0x5b, 0xec, 0x1b, 0x4e, 0xa6, 0xc0, 0x84, 0x48,
+	0x88, 0x34, 0xd8, 0x3a, 0xe0, 0x13, 0x20, 0x21, 0xe5, 0x0a, 0x9a, 0xbd, 0x14, 0x88, 0xc6, 0xda,
+	0xf3, 0x8e, 0x6c, 0x2b, 0xb1, 0xd7, 0xda, 0x9d, 0x18, 0xd3, 0x23, 0xd1, 0xf2, 0xb1, 0x28, 0xaf,
+	0xa4, 0x44, 0xc9, 0x17, 0x41, 0x5e, 0x3b, 0x70, 0xd2, 0x75, 0x33, 0xbf, 0xd9, 0x7d, 0xe6, 0xd9,
+	0xd9, 0x81, 0x85, 0xc9, 0x84, 0x96, 0x51, 0x7d, 0x1d, 0xd1, 0xd7, 0x0a, 0x4d, 0x58, 0x69, 0x45,
+	0x8a, 0x4d, 0x2d, 0x0d, 0xeb, 0xeb, 0xd5, 0x27, 0x98, 0x6d, 0xb5, 0x28, 0x8d, 0x48, 0x28, 0x57,
+	0x25, 0xfb, 0x1f, 0x46, 0xd4, 0xc4, 0xb9, 0xf4, 0x9d, 0xa5, 0xb3, 0x76, 0xb9, 0x4b, 0xcd, 0x8d,
+	0x64, 0xef, 0xe0, 0x2a, 0x15, 0x05, 0xc6, 0xf6, 0x52, 0x4c, 0xff, 0x8e, 0xfb, 0x17, 0x4b, 0x67,
+	0x7d, 0xc9, 0x17, 0x6d, 0xf5, 0xb6, 0x2d, 0x3e, 0x90, 0x5a, 0x15, 0x30, 0xfa, 0x50, 0xa9, 0x24,
+	0x63, 0x0b, 0x18, 0x61, 0x1b, 0xf4, 0x9a, 0x5d, 0xc2, 0x5e, 0xc2, 0x7f, 0x87, 0x32, 0x6f, 0x62,
+	0xca, 0x0b, 0x34, 0x24, 0x8a, 0xca, 0x8a, 0xb9, 0x7c, 0xde, 0xd2, 0xed, 0x19, 0xb2, 0x57, 0x30,
+	0xa4, 0xc6, 0xf8, 0xc3, 0xe5, 0x70, 0x3d, 0x7b, 0xf3, 0x24, 0x3c, 0xfb, 0x0e, 0x1f, 0x74, 0xe2,
+	0xed, 0x89, 0xd5, 0x77, 0x07, 0xbc, 0x5b, 0x12, 0x84, 0x5c, 0x29, 0x62, 0xcf, 0x00, 0x0c, 0x09,
+	0x4d, 0x31, 0xe5, 0xc9, 0xae, 0x6f, 0xec, 0x59, 0xb2, 0xcd, 0x93, 0x1d, 0x7b, 0x0a, 0x53, 0x2c,
+	0x65, 0x57, 0xec, 0xda, 0x4e, 0xb0, 0x94, 0xb6, 0xd4, 0xdd, 0x24, 0x8c, 0xb5, 0x52, 0xe4, 0x0f,
+	0xed, 0x03, 0x3d, 0xf3, 0x57, 0xf8, 0x05, 0x5c, 0xde, 0xed, 0x55, 0xb2, 0x8b, 0x33, 0xcc, 0xd3,
+	0x8c, 0x7c, 0x77, 0xe9, 0xac, 0x87, 0x7c, 0x66, 0xd9, 0xc6, 0xa2, 0xd5, 0xb7, 0x0b, 0x98, 0x73,
+	0x75, 0x20, 0x94, 0x1f, 0xd1, 0x18, 0x91, 0x22, 0x0b, 0x60, 0x86, 0x75, 0x11, 0x53, 0x13, 0x67,
+	0xc2, 0x74, 0x73, 0xf0, 0xb8, 0x87, 0x75, 0xb1, 0x6d, 0x36, 0xc2, 0x64, 0xec, 0x0a, 0xc6, 0x06,
+	0x4b, 0x89, 0xda, 0x9a, 0xf1, 0x78, 0x9f, 0xb1, 0xe7, 0x30, 0xab, 0x50, 0x1b, 0x55, 0x8a, 0x98,
+	0x44, 0x6a, 0xcd, 0x78, 0x1c, 0x7a, 0xb4, 0x15, 0x69, 0x6b, 0xb6, 0xe8, 0x7a, 0xb4, 0x7f, 0xe6,
+	0x76, 0xba, 0x3d, 0xb9, 0x91, 0xcc, 0x87, 0x49, 0x9f, 0xf8, 0x23, 0xfb, 0x90, 0x73, 0xca, 0x18,
+	0xb8, 0x89, 0x92, 0xe8, 0x8f, 0x97, 0xce, 0x7a, 0xce, 0x6d, 0xdc, 0x32, 0xd4, 0xda, 0xf8, 0x13,
+	0x2b, 0x63, 0xe3, 0xd6, 0x99, 0x46, 0x73, 0xd8, 0x93, 0x3f, 0xb5, 0x02, 0x7d, 0xf6, 0x68, 0x0c,
+	0xde, 0xa3, 0x31, 0xbc, 0xdf, 0xfc, 0x3c, 0x06, 0xce, 0xfd, 0x31, 0x70, 0x7e, 0x1f, 0x03, 0xe7,
+	0xc7, 0x29, 0x18, 0xdc, 0x9f, 0x82, 0xc1, 0xaf, 0x53, 0x30, 0xf8, 0x1c, 0x56, 0xbb, 0x34, 0xfc,
+	0xa2, 0xf4, 0x5e, 0x86, 0x12, 0xeb, 0xc8, 0x46, 0xaf, 0xb1, 0x4c, 0xf3, 0x12, 0xa3, 0x24, 0x13,
+	0x79, 0x19, 0x35, 0x51, 0xb7, 0xb6, 0x76, 0x67, 0xef, 0xc6, 0x76, 0x69, 0xdf, 0xfe, 0x19, 0x00,
+	0x71, 0x78, 0xda, 0x46, 0xcc, 0x02, 0x00, 0x00,
 }
 
 func (m *Transaction) Marshal() (dAtA []byte, err error) {
@@ -248,6 +455,133 @@ func (m *Epoch) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *StateRoot) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *StateRoot) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *StateRoot) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.BlockHeight != 0 {
+		i = encodeVarintTypes(dAtA, i, uint64(m.BlockHeight))
+		i--
+		dAtA[i] = 0x20
+	}
+	if len(m.StateRoot) > 0 {
+		i -= len(m.StateRoot)
+		copy(dAtA[i:], m.StateRoot)
+		i = encodeVarintTypes(dAtA, i, uint64(len(m.StateRoot)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if m.EndTick != 0 {
+		i = encodeVarintTypes(dAtA, i, uint64(m.EndTick))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.StartTick != 0 {
+		i = encodeVarintTypes(dAtA, i, uint64(m.StartTick))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *RoutedMessage) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *RoutedMessage) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *RoutedMessage) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.BlockHeight != 0 {
+		i = encodeVarintTypes(dAtA, i, uint64(m.BlockHeight))
+		i--
+		dAtA[i] = 0x48
+	}
+	if len(m.Result) > 0 {
+		i -= len(m.Result)
+		copy(dAtA[i:], m.Result)
+		i = encodeVarintTypes(dAtA, i, uint64(len(m.Result)))
+		i--
+		dAtA[i] = 0x42
+	}
+	if len(m.Errs) > 0 {
+		i -= len(m.Errs)
+		copy(dAtA[i:], m.Errs)
+		i = encodeVarintTypes(dAtA, i, uint64(len(m.Errs)))
+		i--
+		dAtA[i] = 0x3a
+	}
+	if m.Code != 0 {
+		i = encodeVarintTypes(dAtA, i, uint64(m.Code))
+		i--
+		dAtA[i] = 0x30
+	}
+	if len(m.Message) > 0 {
+		i -= len(m.Message)
+		copy(dAtA[i:], m.Message)
+		i = encodeVarintTypes(dAtA, i, uint64(len(m.Message)))
+		i--
+		dAtA[i] = 0x2a
+	}
+	if len(m.MessageId) > 0 {
+		i -= len(m.MessageId)
+		copy(dAtA[i:], m.MessageId)
+		i = encodeVarintTypes(dAtA, i, uint64(len(m.MessageId)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if len(m.PersonaTag) > 0 {
+		i -= len(m.PersonaTag)
+		copy(dAtA[i:], m.PersonaTag)
+		i = encodeVarintTypes(dAtA, i, uint64(len(m.PersonaTag)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.Sender) > 0 {
+		i -= len(m.Sender)
+		copy(dAtA[i:], m.Sender)
+		i = encodeVarintTypes(dAtA, i, uint64(len(m.Sender)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.EvmTxHash) > 0 {
+		i -= len(m.EvmTxHash)
+		copy(dAtA[i:], m.EvmTxHash)
+		i = encodeVarintTypes(dAtA, i, uint64(len(m.EvmTxHash)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
 func encodeVarintTypes(dAtA []byte, offset int, v uint64) int {
 	offset -= sovTypes(v)
 	base := offset
@@ -296,6 +630,71 @@ func (m *Epoch) Size() (n int) {
 	return n
 }
 
+func (m *StateRoot) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.StartTick != 0 {
+		n += 1 + sovTypes(uint64(m.StartTick))
+	}
+	if m.EndTick != 0 {
+		n += 1 + sovTypes(uint64(m.EndTick))
+	}
+	l = len(m.StateRoot)
+	if l > 0 {
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	if m.BlockHeight != 0 {
+		n += 1 + sovTypes(uint64(m.BlockHeight))
+	}
+	return n
+}
+
+func (m *RoutedMessage) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.EvmTxHash)
+	if l > 0 {
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	l = len(m.Sender)
+	if l > 0 {
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	l = len(m.PersonaTag)
+	if l > 0 {
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	l = len(m.MessageId)
+	if l > 0 {
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	l = len(m.Message)
+	if l > 0 {
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	if m.Code != 0 {
+		n += 1 + sovTypes(uint64(m.Code))
+	}
+	l = len(m.Errs)
+	if l > 0 {
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	l = len(m.Result)
+	if l > 0 {
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	if m.BlockHeight != 0 {
+		n += 1 + sovTypes(uint64(m.BlockHeight))
+	}
+	return n
+}
+
 func sovTypes(x uint64) (n int) {
 	return (math_bits.Len64(x|1) + 6) / 7
 }
@@ -527,6 +926,463 @@ func (m *Epoch) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *StateRoot) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTypes
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: StateRoot: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: StateRoot: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StartTick", wireType)
+			}
+			m.StartTick = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.StartTick |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field EndTick", wireType)
+			}
+			m.EndTick = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.EndTick |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StateRoot", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.StateRoot = append(m.StateRoot[:0], dAtA[iNdEx:postIndex]...)
+			if m.StateRoot == nil {
+				m.StateRoot = []byte{}
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BlockHeight", wireType)
+			}
+			m.BlockHeight = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.BlockHeight |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTypes(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *RoutedMessage) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTypes
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: RoutedMessage: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: RoutedMessage: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field EvmTxHash", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.EvmTxHash = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Sender", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Sender = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PersonaTag", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.PersonaTag = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MessageId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.MessageId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Message", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Message = append(m.Message[:0], dAtA[iNdEx:postIndex]...)
+			if m.Message == nil {
+				m.Message = []byte{}
+			}
+			iNdEx = postIndex
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Code", wireType)
+			}
+			m.Code = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Code |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Errs", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Errs = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Result", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Result = append(m.Result[:0], dAtA[iNdEx:postIndex]...)
+			if m.Result == nil {
+				m.Result = []byte{}
+			}
+			iNdEx = postIndex
+		case 9:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BlockHeight", wireType)
+			}
+			m.BlockHeight = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.BlockHeight |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTypes(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func skipTypes(dAtA []byte) (n int, err error) {
 	l := len(dAtA)
 	iNdEx := 0
@@ -8,6 +8,7 @@ import (
 	"io"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/rotisserie/eris"
 
@@ -16,6 +17,35 @@ import (
 	"pkg.world.dev/world-engine/relay/nakama/utils"
 )
 
+const (
+	// idempotencyKeyField is the field of a transaction RPC payload that carries the optional client-generated
+	// idempotency key. It is removed from the payload before the message is signed and is sent to Cardinal in the
+	// idempotencyKeyHeader, so that a retried RPC is answered with the original transaction instead of being executed
+	// again.
+	idempotencyKeyField  = "_idempotencyKey"
+	idempotencyKeyHeader = "Idempotency-Key"
+
+	// healthCheckTimeout is how long the nakama/health RPC waits for Cardinal to answer.
+	healthCheckTimeout = 2 * time.Second
+)
+
+// retryLaterError is returned for transactions that Cardinal did not accept because it is overloaded. Body is
+// Cardinal's retry-after response, which says why, when to retry, and how full its transaction queue is.
+type retryLaterError struct {
+	retryAfter string
+	body       string
+}
+
+func (e *retryLaterError) Error() string {
+	return "cardinal is overloaded, retry after " + e.retryAfter + " seconds: " + e.body
+}
+
+// cardinalHealth is the response from the cardinal health endpoint.
+type cardinalHealth struct {
+	IsServerRunning   bool `json:"isServerRunning"`
+	IsGameLoopRunning bool `json:"isGameLoopRunning"`
+}
+
 // world is the response from the cardinal world endpoint.
 type world struct {
 	Namespace  string        `json:"namespace"`
@@ -62,12 +92,60 @@ func getCardinalEndpoints(cardinalAddress string) (txEndpoints []string, queryEn
 	return txEndpoints, queryEndpoints, err
 }
 
+func getCardinalHealth(ctx context.Context, cardinalAddress string) (*cardinalHealth, error) {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, utils.MakeHTTPURL(HealthEndpoint, cardinalAddress), nil)
+	if err != nil {
+		return nil, eris.Wrap(err, "")
+	}
+	utils.SetCardinalAPIKey(req.Header)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, eris.Wrap(err, "health check failed")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, eris.Errorf("health check failed with status %s", resp.Status)
+	}
+	var health cardinalHealth
+	if err = json.NewDecoder(resp.Body).Decode(&health); err != nil {
+		return nil, eris.Wrap(err, "failed to decode cardinal health")
+	}
+	return &health, nil
+}
+
+// extractIdempotencyKey removes the idempotency key from a transaction RPC payload and returns the remaining payload
+// and the key. Payloads without a key are returned unchanged with an empty key.
+func extractIdempotencyKey(payload string) (string, string, error) {
+	var fields map[string]json.RawMessage
+	if json.Unmarshal([]byte(payload), &fields) != nil {
+		// Not a JSON object, so it can't carry a key. Cardinal rejects the message if it's malformed.
+		return payload, "", nil
+	}
+	raw, ok := fields[idempotencyKeyField]
+	if !ok {
+		return payload, "", nil
+	}
+	var key string
+	if err := json.Unmarshal(raw, &key); err != nil {
+		return "", "", eris.Wrapf(err, "%s must be a string", idempotencyKeyField)
+	}
+	delete(fields, idempotencyKeyField)
+	buf, err := json.Marshal(fields)
+	if err != nil {
+		return "", "", eris.Wrap(err, "")
+	}
+	return string(buf), key, nil
+}
+
 func makeRequestAndReadResp(
 	ctx context.Context,
 	notifier *events.Notifier,
 	endpoint string,
 	payload io.Reader,
 	cardinalAddress string,
+	idempotencyKey string,
 ) (res string, err error) {
 	req, err := http.NewRequestWithContext(
 		ctx,
@@ -79,6 +157,10 @@ func makeRequestAndReadResp(
 		return res, eris.Wrapf(err, "request setup failed for endpoint %q", endpoint)
 	}
 	req.Header.Set("Content-Type", "application/json")
+	utils.SetCardinalAPIKey(req.Header)
+	if idempotencyKey != "" {
+		req.Header.Set(idempotencyKeyHeader, idempotencyKey)
+	}
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
 		return res, eris.Wrapf(err, "request failed for endpoint %q", endpoint)
@@ -89,6 +171,10 @@ func makeRequestAndReadResp(
 		if err != nil {
 			return res, eris.Wrapf(err, "failed to read response body, bad status: %s: %s", resp.Status, body)
 		}
+		if retryAfter := resp.Header.Get("Retry-After"); resp.StatusCode == http.StatusServiceUnavailable &&
+			retryAfter != "" {
+			return res, &retryLaterError{retryAfter: retryAfter, body: string(body)}
+		}
 		return res, eris.Errorf("bad status code: %s: %s", resp.Status, body)
 	}
 	body, err := io.ReadAll(resp.Body)
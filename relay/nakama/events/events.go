@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
 	"sync"
 	"time"
 
@@ -18,12 +19,34 @@ var (
 	ErrEventHubIsShuttingDown = errors.New("event hub is shutting down")
 )
 
+const (
+	// minReconnectDelay and maxReconnectDelay bound the delay between two attempts to connect to Cardinal.
+	minReconnectDelay = 500 * time.Millisecond
+	maxReconnectDelay = 30 * time.Second
+)
+
 type EventHub struct {
 	inputConnection *websocket.Conn
-	channels        *sync.Map // map[string]chan []byte or []Receipt
+	channels        *sync.Map // map[string]chan []byte, []Receipt or TickResults
 	connectMutex    *sync.Mutex
 	didShutdown     bool
 	wsURL           string
+	backoff         utils.Backoff
+
+	statusMutex     *sync.Mutex
+	status          ConnectionStatus
+	connectedBefore bool
+}
+
+// ConnectionStatus describes the websocket connection of the EventHub to Cardinal.
+type ConnectionStatus struct {
+	Connected bool `json:"connected"`
+	// Since is when the connection was made, or when it was lost.
+	Since time.Time `json:"since"`
+	// LastError is the error of the last failed attempt to connect, while the EventHub is not connected.
+	LastError string `json:"lastError,omitempty"`
+	// Reconnects is the number of times the connection was made again after it was lost.
+	Reconnects int `json:"reconnects"`
 }
 
 type TickResults struct {
@@ -39,6 +62,9 @@ func NewEventHub(logger runtime.Logger, eventsEndpoint string, cardinalAddress s
 		connectMutex: &sync.Mutex{},
 		didShutdown:  false,
 		wsURL:        utils.MakeWebSocketURL(eventsEndpoint, cardinalAddress),
+		backoff:      utils.Backoff{Min: minReconnectDelay, Max: maxReconnectDelay},
+		statusMutex:  &sync.Mutex{},
+		status:       ConnectionStatus{Since: time.Now()},
 	}
 	if err := res.connectWithRetry(logger); err != nil {
 		return nil, eris.Wrap(err, "failed to make initial websocket connection")
@@ -47,23 +73,55 @@ func NewEventHub(logger runtime.Logger, eventsEndpoint string, cardinalAddress s
 	return res, nil
 }
 
-// connectWithRetry attempts to make a websocket connection. If Shutdown is called while this method is
-// running ErrEventHubIsShuttingDown will be returned
+// connectWithRetry attempts to make a websocket connection, waiting longer after every failed attempt. If Shutdown is
+// called while this method is running ErrEventHubIsShuttingDown will be returned
 func (eh *EventHub) connectWithRetry(logger runtime.Logger) error {
-	for tries := 1; ; tries++ {
-		if err := eh.establishConnection(); errors.Is(err, ErrEventHubIsShuttingDown) {
+	for {
+		err := eh.establishConnection()
+		if errors.Is(err, ErrEventHubIsShuttingDown) {
 			return ErrEventHubIsShuttingDown
 		} else if err != nil {
-			// sleep a little try again...
-			logger.Info("No host found: %v", err)
-			time.Sleep(2 * time.Second) //nolint:gomnd // its ok.
+			eh.setDisconnected(err)
+			delay := eh.backoff.Next()
+			logger.Info("No host found, retrying in %s: %v", delay, err)
+			time.Sleep(delay)
 			continue
 		}
 
 		// success!
-		break
+		eh.backoff.Reset()
+		eh.setConnected()
+		return nil
 	}
-	return nil
+}
+
+// Status returns the current state of the connection to Cardinal.
+func (eh *EventHub) Status() ConnectionStatus {
+	eh.statusMutex.Lock()
+	defer eh.statusMutex.Unlock()
+	return eh.status
+}
+
+func (eh *EventHub) setConnected() {
+	eh.statusMutex.Lock()
+	defer eh.statusMutex.Unlock()
+	if eh.connectedBefore {
+		eh.status.Reconnects++
+	}
+	eh.connectedBefore = true
+	eh.status.Connected = true
+	eh.status.Since = time.Now()
+	eh.status.LastError = ""
+}
+
+func (eh *EventHub) setDisconnected(err error) {
+	eh.statusMutex.Lock()
+	defer eh.statusMutex.Unlock()
+	if eh.status.Connected {
+		eh.status.Connected = false
+		eh.status.Since = time.Now()
+	}
+	eh.status.LastError = err.Error()
 }
 
 // establishConnection attempts to establish a connection to cardinal. A previous connection will be closed before
@@ -81,7 +139,9 @@ func (eh *EventHub) establishConnection() error {
 		}
 		eh.inputConnection = nil
 	}
-	webSocketConnection, _, err := websocket.DefaultDialer.Dial(eh.wsURL, nil) //nolint:bodyclose // no need.
+	header := http.Header{}
+	utils.SetCardinalAPIKey(header)
+	webSocketConnection, _, err := websocket.DefaultDialer.Dial(eh.wsURL, header) //nolint:bodyclose // no need.
 	if err != nil {
 		return eris.Wrap(err, "websocket dial failed")
 	}
@@ -101,6 +161,14 @@ func (eh *EventHub) SubscribeToReceipts(session string) chan []Receipt {
 	return channel
 }
 
+// SubscribeToTickResults returns a channel that receives the results of every tick, in order. The channel must be
+// drained continuously, since the EventHub waits for every subscriber to receive a tick before it reads the next one.
+func (eh *EventHub) SubscribeToTickResults(session string) chan TickResults {
+	channel := make(chan TickResults)
+	eh.channels.Store(session, channel)
+	return channel
+}
+
 func (eh *EventHub) Unsubscribe(session string) {
 	eventChannelUntyped, ok := eh.channels.Load(session)
 	if !ok {
@@ -112,6 +180,8 @@ func (eh *EventHub) Unsubscribe(session string) {
 		close(ch)
 	case chan []Receipt:
 		close(ch)
+	case chan TickResults:
+		close(ch)
 	default:
 		panic(eris.New("found object that was not a recognized channel type in event hub"))
 	}
@@ -139,6 +209,7 @@ func (eh *EventHub) readMessage(log runtime.Logger) (messageType int, message []
 		messageType, message, err = eh.inputConnection.ReadMessage()
 		if err != nil {
 			log.Warn("read from websocket failed: %v", err)
+			eh.setDisconnected(err)
 			// Something went wrong. Try to reestablish the connection.
 			if err = eh.connectWithRetry(log); err != nil {
 				return 0, nil, eris.Wrap(err, "failed to reestablish a websocket connection")
@@ -186,6 +257,8 @@ func (eh *EventHub) Dispatch(log runtime.Logger) error {
 				}
 			case chan []Receipt:
 				ch <- receivedTickResults.Receipts
+			case chan TickResults:
+				ch <- receivedTickResults
 			default:
 				log.Warn("Found an unhandled channel type")
 			}
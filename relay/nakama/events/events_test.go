@@ -190,6 +190,10 @@ func TestEventHub_WhenWebSocketDisconnects_EventHubAutomaticallyReconnects(t *te
 
 	assert.Contains(t, string(gotTickResults), wantMsg)
 
+	status := eventHub.Status()
+	assert.Check(t, status.Connected)
+	assert.Equal(t, 1, status.Reconnects)
+
 	// Cleanup and shutdown
 	eventHub.Shutdown()
 
@@ -27,8 +27,11 @@ type TransactionReceiptsReply struct {
 	Receipts  []*Receipt `json:"receipts"`
 }
 
+// Receipt is the receipt of a transaction. Tick is the tick at which the transaction was applied, which clients that
+// predict the outcome of their transactions reconcile with.
 type Receipt struct {
 	TxHash string         `json:"txHash"`
+	Tick   uint64         `json:"tick"`
 	Result map[string]any `json:"result"`
 	Errors []string       `json:"errors"`
 }
@@ -115,6 +118,7 @@ func (r *Notifier) handleReceipt(receipts []Receipt) error {
 
 		data := map[string]any{
 			"txHash": receipt.TxHash,
+			"tick":   receipt.Tick,
 			"result": receipt.Result,
 			"errors": receipt.Errors,
 		}
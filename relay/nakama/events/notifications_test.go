@@ -41,6 +41,7 @@ func TestNotifierIntegrationWithEventHub(t *testing.T) {
 			Subject: "receipt",
 			Content: map[string]any{
 				"txHash": txHash,
+				"tick":   uint64(100),
 				"result": map[string]any{"status": "success"},
 				"errors": []string{},
 			},
@@ -77,6 +78,7 @@ func TestNotifierIntegrationWithEventHub(t *testing.T) {
 	tr.Events = append(tr.Events, event)
 	tr.Receipts = append(tr.Receipts, Receipt{
 		TxHash: txHash,
+		Tick:   100,
 		Result: map[string]any{"status": "success"},
 		Errors: []string{},
 	})
@@ -134,6 +136,7 @@ func TestHandleReceipt(t *testing.T) {
 			Subject: "receipt",
 			Content: map[string]interface{}{
 				"txHash": txHash,
+				"tick":   uint64(0),
 				"result": (map[string]interface{})(nil),
 				"errors": ([]string)(nil),
 			},
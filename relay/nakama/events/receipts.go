@@ -0,0 +1,74 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/relay/nakama/utils"
+)
+
+const (
+	listReceiptsEndpoint = "query/receipts/list"
+	receiptPollInterval  = 250 * time.Millisecond
+)
+
+// WaitForReceipt polls Cardinal for the receipt of the given transaction, which was submitted in the given tick, until
+// it is found or ctx is done.
+func WaitForReceipt(ctx context.Context, cardinalAddress, txHash string, tick uint64) (*Receipt, error) {
+	startTick := tick
+	for {
+		reply, err := listReceipts(ctx, cardinalAddress, startTick)
+		if err != nil && ctx.Err() == nil {
+			return nil, err
+		}
+		if reply != nil {
+			for _, receipt := range reply.Receipts {
+				if receipt != nil && receipt.TxHash == txHash {
+					return receipt, nil
+				}
+			}
+			if reply.EndTick > startTick {
+				startTick = reply.EndTick
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return nil, eris.Wrapf(ctx.Err(), "timeout while waiting for the receipt of transaction %s", txHash)
+		case <-time.After(receiptPollInterval):
+		}
+	}
+}
+
+func listReceipts(ctx context.Context, cardinalAddress string, startTick uint64) (*TransactionReceiptsReply, error) {
+	buf, err := json.Marshal(map[string]uint64{"startTick": startTick})
+	if err != nil {
+		return nil, eris.Wrap(err, "")
+	}
+	httpReq, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodPost,
+		utils.MakeHTTPURL(listReceiptsEndpoint, cardinalAddress),
+		bytes.NewReader(buf),
+	)
+	if err != nil {
+		return nil, eris.Wrap(err, "")
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	utils.SetCardinalAPIKey(httpReq.Header)
+	httpResp, err := utils.DoRequest(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer httpResp.Body.Close()
+
+	var reply TransactionReceiptsReply
+	if err = json.NewDecoder(httpResp.Body).Decode(&reply); err != nil {
+		return nil, eris.Wrap(err, "unable to decode receipts")
+	}
+	return &reply, nil
+}
@@ -10,15 +10,19 @@ import (
 	"sync"
 	"time"
 
+	"github.com/heroiclabs/nakama-common/api"
 	"github.com/heroiclabs/nakama-common/runtime"
 	"github.com/rotisserie/eris"
 	"google.golang.org/grpc/codes"
 
 	"pkg.world.dev/world-engine/relay/nakama/allowlist"
 	"pkg.world.dev/world-engine/relay/nakama/events"
+	"pkg.world.dev/world-engine/relay/nakama/match"
 	"pkg.world.dev/world-engine/relay/nakama/persona"
 	"pkg.world.dev/world-engine/relay/nakama/signer"
+	"pkg.world.dev/world-engine/relay/nakama/sponsor"
 	"pkg.world.dev/world-engine/relay/nakama/utils"
+	"pkg.world.dev/world-engine/relay/nakama/wallet"
 )
 
 // nakamaRPCHandler is the signature required for handlers that are passed to Nakama's RegisterRpc method.
@@ -32,6 +36,7 @@ type nakamaRPCHandler func(
 func handleClaimPersona(
 	verifier *persona.Verifier,
 	notifier *events.Notifier,
+	sponsorship *sponsor.Sponsor,
 	txSigner signer.Signer,
 	cardinalAddress string,
 	globalNamespace string,
@@ -54,6 +59,13 @@ func handleClaimPersona(
 				err)
 		}
 
+		// Persona creation is paid for by the relayer, so it counts towards the sponsorship quotas.
+		if sponsorship != nil {
+			if err := sponsorship.Reserve(ctx, sponsor.ActionClaimPersona); err != nil {
+				return utils.LogError(logger, err, codes.ResourceExhausted)
+			}
+		}
+
 		result, err := persona.ClaimPersona(
 			ctx,
 			nk,
@@ -84,6 +96,100 @@ func handleClaimPersona(
 	}
 }
 
+// handleClaimPersonaAndWait claims a persona tag with a key pair of the current user and waits until Cardinal has
+// processed the claim.
+func handleClaimPersonaAndWait(
+	sponsorship *sponsor.Sponsor,
+	cardinalAddress string,
+	globalNamespace string,
+	globalPersonaAssignment *sync.Map,
+) nakamaRPCHandler {
+	return func(
+		ctx context.Context,
+		logger runtime.Logger,
+		_ *sql.DB,
+		nk runtime.NakamaModule,
+		payload string,
+	) (string, error) {
+		var req persona.ClaimRequest
+		if err := json.Unmarshal([]byte(payload), &req); err != nil {
+			return utils.LogErrorWithMessageAndCode(
+				logger,
+				err,
+				codes.InvalidArgument,
+				"unable to unmarshal payload: %v",
+				err)
+		}
+
+		if sponsorship != nil {
+			if err := sponsorship.Reserve(ctx, sponsor.ActionClaimPersona); err != nil {
+				return utils.LogError(logger, err, codes.ResourceExhausted)
+			}
+		}
+
+		result, err := persona.ClaimPersonaAndWait(
+			ctx,
+			nk,
+			req,
+			cardinalAddress,
+			globalNamespace,
+			globalPersonaAssignment,
+			persona.DefaultClaimTimeout,
+		)
+		if err == nil {
+			return utils.MarshalResult(logger, result)
+		}
+
+		switch cause := eris.Cause(err); {
+		case errors.Is(cause, persona.ErrPersonaTagEmpty):
+			return utils.LogErrorWithMessageAndCode(
+				logger,
+				err,
+				codes.InvalidArgument,
+				"claim persona tag request must have personaTag field",
+			)
+		case errors.Is(cause, persona.ErrPersonaTagUnavailable):
+			return utils.LogError(logger, err, codes.AlreadyExists)
+		case errors.Is(cause, allowlist.ErrNotAllowlisted):
+			return utils.LogError(logger, err, codes.PermissionDenied)
+		}
+		return utils.LogError(logger, err, codes.FailedPrecondition)
+	}
+}
+
+// handleAuthorizeAddress binds an EVM address to the persona tag of the current user. The transaction is signed and
+// paid for by the relayer.
+func handleAuthorizeAddress(sponsorship *sponsor.Sponsor) nakamaRPCHandler {
+	return func(
+		ctx context.Context,
+		logger runtime.Logger,
+		_ *sql.DB,
+		_ runtime.NakamaModule,
+		payload string,
+	) (string, error) {
+		var req sponsor.AuthorizeAddressRequest
+		if err := json.Unmarshal([]byte(payload), &req); err != nil {
+			return utils.LogErrorWithMessageAndCode(logger, err, codes.InvalidArgument, "unable to unmarshal payload: %v",
+				err)
+		}
+		res, err := sponsorship.AuthorizeAddress(ctx, req)
+		if err == nil {
+			return utils.MarshalResult(logger, res)
+		}
+		switch {
+		case errors.Is(err, sponsor.ErrQuotaExceeded), errors.Is(err, sponsor.ErrBudgetExhausted):
+			return utils.LogError(logger, err, codes.ResourceExhausted)
+		case errors.Is(err, sponsor.ErrInvalidSignature):
+			return utils.LogError(logger, err, codes.PermissionDenied)
+		case errors.Is(err, sponsor.ErrAddressAlreadyAuthorized):
+			return utils.LogError(logger, err, codes.AlreadyExists)
+		case errors.Is(err, sponsor.ErrPersonaNotClaimed):
+			return utils.LogError(logger, err, codes.FailedPrecondition)
+		}
+		return utils.LogError(logger, err, codes.Internal)
+	}
+}
+
 func handleShowPersona(txSigner signer.Signer, cardinalAddress string) nakamaRPCHandler {
 	return func(
 		ctx context.Context,
@@ -230,6 +336,15 @@ func handleCardinalRequest(
 		//    a missing signer address failure).
 		// 3) Make the request again. If this fails again, there's nothing else we can do.
 
+		var idempotencyKey string
+		if strings.HasPrefix(currEndpoint, TransactionEndpointPrefix) {
+			var err error
+			payload, idempotencyKey, err = extractIdempotencyKey(payload)
+			if err != nil {
+				return utils.LogErrorWithMessageAndCode(logger, err, codes.InvalidArgument, "invalid idempotency key")
+			}
+		}
+
 		// //////////////////////////////
 		// Try to send the transaction //
 		// //////////////////////////////
@@ -237,11 +352,14 @@ func handleCardinalRequest(
 		if err != nil {
 			return utils.LogErrorWithMessageAndCode(logger, err, codes.FailedPrecondition, "unable to make payload")
 		}
-		result, err := makeRequestAndReadResp(ctx, notifier, currEndpoint, resultPayload, cardinalAddress)
+		result, err := makeRequestAndReadResp(ctx, notifier, currEndpoint, resultPayload, cardinalAddress, idempotencyKey)
 		if err == nil {
 			// The request was successful. Return the result.
 			return result, nil
 		}
+		if retryLater := new(retryLaterError); errors.As(err, &retryLater) {
+			return retryLaterResult(logger, retryLater)
+		}
 		initialResult, initialErr := utils.LogErrorWithMessageAndCode(logger, err, codes.FailedPrecondition, "")
 
 		// ///////////////////////////
@@ -272,14 +390,23 @@ func handleCardinalRequest(
 		if err != nil {
 			return utils.LogErrorWithMessageAndCode(logger, err, codes.FailedPrecondition, "unable to make payload")
 		}
-		result, err = makeRequestAndReadResp(ctx, notifier, currEndpoint, resultPayload, cardinalAddress)
-		if err != nil {
+		result, err = makeRequestAndReadResp(ctx, notifier, currEndpoint, resultPayload, cardinalAddress, idempotencyKey)
+		if retryLater := new(retryLaterError); errors.As(err, &retryLater) {
+			return retryLaterResult(logger, retryLater)
+		} else if err != nil {
 			return utils.LogErrorWithMessageAndCode(logger, err, codes.FailedPrecondition, "")
 		}
 		return result, nil
 	}
 }
 
+// retryLaterResult returns the error for an RPC whose transaction Cardinal did not accept because it is overloaded.
+// The message of the error is Cardinal's retry-after response as it is, so that clients can parse when to retry.
+func retryLaterResult(logger runtime.Logger, err *retryLaterError) (string, error) {
+	logger.Warn(err.Error())
+	return "", runtime.NewError(err.body, int(codes.Unavailable))
+}
+
 func blockUntilPersonaTagTxHasBeenProcessed(logger runtime.Logger, eventHub *events.EventHub, txHash string) {
 	ch := eventHub.SubscribeToReceipts(txHash)
 	defer func() {
@@ -309,3 +436,184 @@ func blockUntilPersonaTagTxHasBeenProcessed(logger runtime.Logger, eventHub *eve
 func isResultASignerError(err error) bool {
 	return strings.Contains(err.Error(), "could not get signer for persona")
 }
+
+func handleWalletReconcile(bridge *wallet.Bridge) nakamaRPCHandler {
+	return func(
+		ctx context.Context,
+		logger runtime.Logger,
+		_ *sql.DB,
+		nk runtime.NakamaModule,
+		_ string,
+	) (string, error) {
+		userID, personaTag, err := acceptedPersonaTag(ctx, nk)
+		if err != nil {
+			return utils.LogErrorWithMessageAndCode(logger, err, codes.FailedPrecondition, "no accepted persona tag")
+		}
+		report, err := bridge.Reconcile(ctx, logger, userID, personaTag)
+		if errors.Is(err, wallet.ErrBalanceMismatch) {
+			return utils.LogErrorWithMessageAndCode(logger, err, codes.DataLoss, "wallet balances don't match")
+		} else if err != nil {
+			return utils.LogError(logger, err, codes.FailedPrecondition)
+		}
+		return utils.MarshalResult(logger, report)
+	}
+}
+
+type matchResponse struct {
+	MatchID string `json:"matchId"`
+	match.Label
+}
+
+type matchNamespaceRequest struct {
+	MatchID string `json:"matchId"`
+}
+
+// handleCreateMatch creates a match that is backed by a new Cardinal world.
+func handleCreateMatch(
+	ctx context.Context,
+	logger runtime.Logger,
+	_ *sql.DB,
+	nk runtime.NakamaModule,
+	_ string,
+) (string, error) {
+	matchID, err := nk.MatchCreate(ctx, match.ModuleName, nil)
+	if err != nil {
+		return utils.LogErrorWithMessageAndCode(logger, err, codes.Unavailable, "failed to create match")
+	}
+	return utils.MarshalResult(logger, matchResponse{
+		MatchID: matchID,
+		Label:   match.NewLabel(match.NamespaceForMatch(matchID)),
+	})
+}
+
+// handleMatchNamespace returns the namespace and route prefix of the Cardinal world that backs a running match.
+func handleMatchNamespace(
+	ctx context.Context,
+	logger runtime.Logger,
+	_ *sql.DB,
+	nk runtime.NakamaModule,
+	payload string,
+) (string, error) {
+	var req matchNamespaceRequest
+	if err := json.Unmarshal([]byte(payload), &req); err != nil {
+		return utils.LogErrorWithMessageAndCode(logger, err, codes.InvalidArgument, "unable to unmarshal payload")
+	}
+	m, err := nk.MatchGet(ctx, req.MatchID)
+	if err != nil {
+		return utils.LogErrorWithMessageAndCode(logger, err, codes.Internal, "failed to get match")
+	}
+	if m == nil {
+		return utils.LogErrorWithMessageAndCode(
+			logger, eris.Errorf("match %q not found", req.MatchID), codes.NotFound, "match not found")
+	}
+	var label match.Label
+	if err = json.Unmarshal([]byte(m.GetLabel().GetValue()), &label); err != nil || label.Namespace == "" {
+		return utils.LogErrorWithMessageAndCode(
+			logger, eris.Errorf("match %q is not backed by a cardinal world", req.MatchID), codes.FailedPrecondition,
+			"match is not backed by a cardinal world")
+	}
+	return utils.MarshalResult(logger, matchResponse{MatchID: req.MatchID, Label: label})
+}
+
+func handleValidatedPurchaseApple(bridge *wallet.Bridge) func(
+	context.Context, runtime.Logger, *sql.DB, runtime.NakamaModule,
+	*api.ValidatePurchaseResponse, *api.ValidatePurchaseAppleRequest,
+) error {
+	return func(
+		ctx context.Context,
+		logger runtime.Logger,
+		_ *sql.DB,
+		nk runtime.NakamaModule,
+		out *api.ValidatePurchaseResponse,
+		_ *api.ValidatePurchaseAppleRequest,
+	) error {
+		return creditValidatedPurchases(ctx, logger, nk, bridge, out)
+	}
+}
+
+func handleValidatedPurchaseGoogle(bridge *wallet.Bridge) func(
+	context.Context, runtime.Logger, *sql.DB, runtime.NakamaModule,
+	*api.ValidatePurchaseResponse, *api.ValidatePurchaseGoogleRequest,
+) error {
+	return func(
+		ctx context.Context,
+		logger runtime.Logger,
+		_ *sql.DB,
+		nk runtime.NakamaModule,
+		out *api.ValidatePurchaseResponse,
+		_ *api.ValidatePurchaseGoogleRequest,
+	) error {
+		return creditValidatedPurchases(ctx, logger, nk, bridge, out)
+	}
+}
+
+func creditValidatedPurchases(
+	ctx context.Context,
+	logger runtime.Logger,
+	nk runtime.NakamaModule,
+	bridge *wallet.Bridge,
+	out *api.ValidatePurchaseResponse,
+) error {
+	userID, personaTag, err := acceptedPersonaTag(ctx, nk)
+	if err != nil {
+		return eris.Wrap(err, "cannot credit purchase without an accepted persona tag")
+	}
+	entries, err := bridge.CreditValidatedPurchases(ctx, userID, personaTag, out.GetValidatedPurchases())
+	if err != nil {
+		logger.Error("failed to credit validated purchases: %s", eris.ToString(err, true))
+		return err
+	}
+	logger.Debug("credited %d validated purchases for user %q", len(entries), userID)
+	return nil
+}
+
+// acceptedPersonaTag returns the current user and their persona tag. An error is returned if the user's persona tag
+// has not been accepted by Cardinal.
+func acceptedPersonaTag(ctx context.Context, nk runtime.NakamaModule) (userID string, personaTag string, err error) {
+	userID, err = utils.GetUserID(ctx)
+	if err != nil {
+		return "", "", err
+	}
+	ptr, err := persona.LoadPersonaTagStorageObj(ctx, nk)
+	if err != nil {
+		return "", "", err
+	}
+	if ptr.Status != persona.StatusAccepted {
+		return "", "", eris.Wrap(persona.ErrNoPersonaTagForUser, "")
+	}
+	return userID, ptr.PersonaTag, nil
+}
+
+// healthResponse is the response of the nakama/health RPC.
+type healthResponse struct {
+	CardinalAddress string `json:"cardinalAddress"`
+	Namespace       string `json:"namespace"`
+	// Events is the state of the websocket connection that relays Cardinal's events and receipts to Nakama.
+	Events events.ConnectionStatus `json:"events"`
+	// Reachable is true if Cardinal answered the health check. Error is why it didn't.
+	Reachable         bool   `json:"reachable"`
+	IsGameLoopRunning bool   `json:"isGameLoopRunning"`
+	Error             string `json:"error,omitempty"`
+}
+
+// handleHealth reports whether Nakama is connected to Cardinal. The RPC itself doesn't fail if Cardinal can't be
+// reached, so that it can be used to monitor the connection.
+func handleHealth(eventHub *events.EventHub, cardinalAddress string, globalNamespace string) nakamaRPCHandler {
+	return func(ctx context.Context, logger runtime.Logger, _ *sql.DB, _ runtime.NakamaModule, _ string) (
+		string, error,
+	) {
+		res := healthResponse{
+			CardinalAddress: cardinalAddress,
+			Namespace:       globalNamespace,
+			Events:          eventHub.Status(),
+		}
+		health, err := getCardinalHealth(ctx, cardinalAddress)
+		if err != nil {
+			res.Error = err.Error()
+		} else {
+			res.Reachable = health.IsServerRunning
+			res.IsGameLoopRunning = health.IsGameLoopRunning
+		}
+		return utils.MarshalResult(logger, res)
+	}
+}
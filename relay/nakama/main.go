@@ -26,9 +26,12 @@ import (
 const (
 	EnvCardinalAddr           = "CARDINAL_ADDR"
 	EnvCardinalNamespace      = "CARDINAL_NAMESPACE"
+	EnvCardinalAPIKey         = "CARDINAL_API_KEY"         // #nosec G101
 	EnvKMSCredentialsFile     = "GCP_KMS_CREDENTIALS_FILE" // #nosec G101
 	EnvKMSKeyName             = "GCP_KMS_KEY_NAME"
+	EnvSignerPrivateKey       = "SIGNER_PRIVATE_KEY" // #nosec G101
 	WorldEndpoint             = "world"
+	HealthEndpoint            = "health"
 	EventEndpoint             = "events"
 	TransactionEndpointPrefix = "tx/"
 )
@@ -41,13 +44,14 @@ func InitModule(
 	initializer runtime.Initializer,
 ) error {
 	utils.DebugEnabled = getDebugModeFromEnvironment()
+	utils.CardinalAPIKey = getEnv(ctx, EnvCardinalAPIKey)
 
-	cardinalAddress, err := initCardinalAddress()
+	cardinalAddress, err := initCardinalAddress(ctx)
 	if err != nil {
 		return eris.Wrap(err, "failed to init cardinal address")
 	}
 
-	globalNamespace, err := initNamespace()
+	globalNamespace, err := initNamespace(ctx)
 	if err != nil {
 		return eris.Wrap(err, "failed to init globalNamespace")
 	}
@@ -76,12 +80,24 @@ func InitModule(
 	}
 
 	verifier := persona.NewVerifier(logger, nk, eventHub)
+	sessionSigners := persona.NewSessionSigners(nk, txSigner, cardinalAddress, globalNamespace)
+	if err := initializer.RegisterEventSessionEnd(func(ctx context.Context, _ runtime.Logger, _ *api.Event) {
+		sessionSigners.Forget(ctx)
+	}); err != nil {
+		return eris.Wrap(err, "failed to register session end event")
+	}
+
+	sponsorship, err := initSponsorship(logger, initializer, nk, txSigner, cardinalAddress, globalNamespace)
+	if err != nil {
+		return eris.Wrap(err, "failed to init sponsorship")
+	}
 
 	if err := initPersonaTagEndpoints(
 		logger,
 		initializer,
 		verifier,
 		notifier,
+		sponsorship,
 		txSigner,
 		cardinalAddress,
 		globalNamespace,
@@ -96,12 +112,39 @@ func InitModule(
 		notifier,
 		eventHub,
 		txSigner,
+		sessionSigners,
 		cardinalAddress,
 		globalNamespace,
 	); err != nil {
 		return eris.Wrap(err, "failed to init cardinal endpoints")
 	}
 
+	if err := initWalletBridge(
+		ctx,
+		logger,
+		initializer,
+		nk,
+		eventHub,
+		txSigner,
+		cardinalAddress,
+		globalNamespace,
+		globalPersonaAssignment,
+	); err != nil {
+		return eris.Wrap(err, "failed to init wallet bridge")
+	}
+
+	if err := initMatchLifecycle(initializer, cardinalAddress); err != nil {
+		return eris.Wrap(err, "failed to init match lifecycle")
+	}
+
+	if err := initLockstepMatch(initializer, eventHub, sessionSigners, cardinalAddress); err != nil {
+		return eris.Wrap(err, "failed to init lockstep match")
+	}
+
+	if err := initHealthEndpoint(initializer, eventHub, cardinalAddress, globalNamespace); err != nil {
+		return eris.Wrap(err, "failed to init health endpoint")
+	}
+
 	if err := initAllowlist(logger, initializer); err != nil {
 		return eris.Wrap(err, "failed to init allowlist endpoints")
 	}
@@ -123,9 +166,12 @@ func InitModule(
 func selectSigner(ctx context.Context, logger runtime.Logger, nk runtime.NakamaModule) (signer.Signer, error) {
 	nonceManager := signer.NewNakamaNonceManager(nk)
 
-	kmsCredsFile := os.Getenv(EnvKMSCredentialsFile)
-	kmsKeyName := os.Getenv(EnvKMSKeyName)
+	kmsCredsFile := getEnv(ctx, EnvKMSCredentialsFile)
+	kmsKeyName := getEnv(ctx, EnvKMSKeyName)
 	if kmsCredsFile == "" && kmsKeyName == "" {
+		if privateKeyHex := getEnv(ctx, EnvSignerPrivateKey); privateKeyHex != "" {
+			return signer.NewNakamaSignerFromKey(ctx, nk, privateKeyHex, nonceManager)
+		}
 		// Neither the KMS creds file nor the key name is set. Assume the user wants to store the private key on
 		// Nakama's DB.
 		return signer.NewNakamaSigner(ctx, logger, nk, nonceManager)
@@ -230,6 +276,7 @@ func initCardinalEndpoints(
 	notifier *events.Notifier,
 	eventHub *events.EventHub,
 	txSigner signer.Signer,
+	sessionSigners *persona.SessionSigners,
 	cardinalAddress string,
 	globalNamespace string,
 ) error {
@@ -238,11 +285,11 @@ func initCardinalEndpoints(
 		return err
 	}
 
-	createTransaction := func(payload string, endpoint string, nk runtime.NakamaModule, ctx context.Context,
+	createTransaction := func(payload string, endpoint string, _ runtime.NakamaModule, ctx context.Context,
 	) (io.Reader, error) {
 		logger.Debug("The %s endpoint requires a signed payload", endpoint)
 		var transaction io.Reader
-		transaction, err = makeTransaction(ctx, nk, txSigner, payload, cardinalAddress, globalNamespace)
+		transaction, err = makeTransaction(ctx, sessionSigners, payload)
 		if err != nil {
 			return nil, err
 		}
@@ -301,27 +348,8 @@ func initCardinalEndpoints(
 	return nil
 }
 
-func makeTransaction(ctx context.Context,
-	nk runtime.NakamaModule,
-	txSigner signer.Signer,
-	payload string,
-	cardinalAddress string,
-	globalNamespace string,
-) (io.Reader, error) {
-	ptr, err := persona.LoadPersonaTagStorageObj(ctx, nk)
-	if err != nil {
-		return nil, err
-	}
-	ptr, err = ptr.AttemptToUpdatePending(ctx, nk, txSigner, cardinalAddress)
-	if err != nil {
-		return nil, err
-	}
-
-	if ptr.Status != persona.StatusAccepted {
-		return nil, eris.Wrap(persona.ErrNoPersonaTagForUser, "")
-	}
-	personaTag := ptr.PersonaTag
-	sp, err := txSigner.SignTx(ctx, personaTag, globalNamespace, payload)
+func makeTransaction(ctx context.Context, sessionSigners *persona.SessionSigners, payload string) (io.Reader, error) {
+	sp, err := sessionSigners.SignTx(ctx, payload)
 	if err != nil {
 		return nil, err
 	}
@@ -332,16 +360,27 @@ func makeTransaction(ctx context.Context,
 	return bytes.NewReader(buf), nil
 }
 
-func initCardinalAddress() (string, error) {
-	globalCardinalAddress := os.Getenv(EnvCardinalAddr)
+// getEnv returns the value of a Nakama runtime environment variable, which are set in the runtime.env section of
+// Nakama's config. Variables that are not set there are read from the process environment.
+func getEnv(ctx context.Context, key string) string {
+	if env, ok := ctx.Value(runtime.RUNTIME_CTX_ENV).(map[string]string); ok {
+		if value, ok := env[key]; ok {
+			return value
+		}
+	}
+	return os.Getenv(key)
+}
+
+func initCardinalAddress(ctx context.Context) (string, error) {
+	globalCardinalAddress := getEnv(ctx, EnvCardinalAddr)
 	if globalCardinalAddress == "" {
 		return "", eris.Errorf("must specify a cardinal server via %s", EnvCardinalAddr)
 	}
 	return globalCardinalAddress, nil
 }
 
-func initNamespace() (string, error) {
-	globalNamespace := os.Getenv(EnvCardinalNamespace)
+func initNamespace(ctx context.Context) (string, error) {
+	globalNamespace := getEnv(ctx, EnvCardinalNamespace)
 	if globalNamespace == "" {
 		return "", eris.Errorf("must specify a cardinal namespace via %s", EnvCardinalNamespace)
 	}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/heroiclabs/nakama-common/runtime"
+
+	"pkg.world.dev/world-engine/assert"
+)
+
+func TestRuntimeEnvTakesPrecedenceOverProcessEnv(t *testing.T) {
+	t.Setenv(EnvCardinalAddr, "localhost:4040")
+	t.Setenv(EnvCardinalNamespace, "process-namespace")
+	ctx := context.WithValue(context.Background(), runtime.RUNTIME_CTX_ENV, map[string]string{
+		EnvCardinalNamespace: "runtime-namespace",
+	})
+
+	addr, err := initCardinalAddress(ctx)
+	assert.NilError(t, err)
+	assert.Equal(t, "localhost:4040", addr)
+	namespace, err := initNamespace(ctx)
+	assert.NilError(t, err)
+	assert.Equal(t, "runtime-namespace", namespace)
+
+	t.Setenv(EnvCardinalAddr, "")
+	_, err = initCardinalAddress(ctx)
+	assert.ErrorContains(t, err, EnvCardinalAddr)
+}
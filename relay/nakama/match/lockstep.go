@@ -0,0 +1,396 @@
+package match
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/heroiclabs/nakama-common/runtime"
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/relay/nakama/events"
+	"pkg.world.dev/world-engine/relay/nakama/utils"
+	"pkg.world.dev/world-engine/sign"
+)
+
+const (
+	// LockstepModuleName is the name the lockstep match handler is registered under. Pass it to nk.MatchCreate to
+	// create a realtime match of the world.
+	LockstepModuleName = "cardinal-lockstep"
+
+	// OpCodeTick is the op code of the TickMessage that is broadcast to the presences for every Cardinal tick.
+	OpCodeTick int64 = 1
+	// OpCodeInput is the op code of the Input that presences send to the match.
+	OpCodeInput int64 = 2
+	// OpCodeInputResult is the op code of the InputResult that is sent back to the presence that sent an Input.
+	OpCodeInputResult int64 = 3
+
+	// DefaultLockstepLoopRate is the number of match loop iterations per second if no rate is configured. It bounds the
+	// delay between the end of a Cardinal tick and its broadcast, not the rate at which the match advances.
+	DefaultLockstepLoopRate = 20
+	// LockstepEnabledEnvVar enables the lockstep match handler.
+	LockstepEnabledEnvVar = "ENABLE_LOCKSTEP_MATCH"
+	// LockstepLoopRateEnvVar overrides DefaultLockstepLoopRate.
+	LockstepLoopRateEnvVar = "LOCKSTEP_MATCH_LOOP_RATE"
+
+	txEndpointPrefix = "tx/"
+)
+
+var ErrInvalidInput = errors.New("invalid match input")
+
+// TickSource delivers the results of Cardinal's ticks. It is implemented by events.EventHub.
+type TickSource interface {
+	SubscribeToTickResults(session string) chan events.TickResults
+	Unsubscribe(session string)
+}
+
+// TxSigner signs a transaction on behalf of the persona of the user and session in the context. It is implemented by
+// persona.SessionSigners.
+type TxSigner interface {
+	SignTx(ctx context.Context, data any) (*sign.Transaction, error)
+}
+
+// Input is the data of a match message with OpCodeInput. It is forwarded to Cardinal as a transaction of the sender's
+// persona.
+type Input struct {
+	// Message is the full name of the message, e.g. "game.move".
+	Message string `json:"message"`
+	// Body is the message.
+	Body json.RawMessage `json:"body"`
+	// ID is chosen by the client to match the InputResult to the input.
+	ID string `json:"id,omitempty"`
+}
+
+// InputResult is sent to the sender of an Input once it was forwarded to Cardinal, or rejected.
+type InputResult struct {
+	ID     string `json:"id,omitempty"`
+	TxHash string `json:"txHash,omitempty"`
+	// Tick is the tick in which Cardinal executes the transaction.
+	Tick  uint64 `json:"tick,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// TickMessage is broadcast to the presences of a match for every Cardinal tick. It carries what changed in the tick:
+// the events that the systems emitted, and the receipts of the transactions that were sent through the match.
+type TickMessage struct {
+	Tick     uint64            `json:"tick"`
+	Events   []json.RawMessage `json:"events"`
+	Receipts []events.Receipt  `json:"receipts"`
+}
+
+// Lockstep is a reference implementation of an authoritative match whose state is the state of the Cardinal world.
+// The match advances with Cardinal: every tick of the world is broadcast to the presences, in order, as soon as it is
+// done. Input of the presences is forwarded to Cardinal as transactions of their personas, so the world stays the only
+// authority. Games can copy it as a starting point for their own realtime integration.
+type Lockstep struct {
+	ticks           TickSource
+	signers         TxSigner
+	cardinalAddress string
+	loopRate        int
+}
+
+func NewLockstep(ticks TickSource, signers TxSigner, cardinalAddress string, loopRate int) *Lockstep {
+	if loopRate <= 0 {
+		loopRate = DefaultLockstepLoopRate
+	}
+	return &Lockstep{
+		ticks:           ticks,
+		signers:         signers,
+		cardinalAddress: cardinalAddress,
+		loopRate:        loopRate,
+	}
+}
+
+// NewMatch returns the match handler that is registered under LockstepModuleName.
+func (l *Lockstep) NewMatch(
+	_ context.Context, _ runtime.Logger, _ *sql.DB, _ runtime.NakamaModule,
+) (runtime.Match, error) {
+	return &lockstepHandler{lockstep: l}, nil
+}
+
+// lockstepState is the state of a single lockstep match.
+type lockstepState struct {
+	matchID    string
+	presences  map[string]runtime.Presence
+	emptyTicks int
+	// lastTick is the last Cardinal tick that was broadcast.
+	lastTick uint64
+
+	// mu guards the fields below, which are written by the goroutines that receive ticks and forward input.
+	mu      sync.Mutex
+	ticks   []events.TickResults
+	results []inputResult
+	// txHashes are the hashes of the transactions that were sent through the match and are not executed yet.
+	txHashes map[string]bool
+	ended    bool
+}
+
+// inputResult is the result of an input, and the presence it is sent to.
+type inputResult struct {
+	presence runtime.Presence
+	result   InputResult
+}
+
+// lockstepHandler implements the match handler of Lockstep.
+type lockstepHandler struct {
+	lockstep *Lockstep
+}
+
+var _ runtime.Match = &lockstepHandler{}
+
+func (h *lockstepHandler) MatchInit(
+	ctx context.Context, logger runtime.Logger, _ *sql.DB, _ runtime.NakamaModule, _ map[string]any,
+) (any, int, string) {
+	matchID, ok := ctx.Value(runtime.RUNTIME_CTX_MATCH_ID).(string)
+	if !ok || matchID == "" {
+		logger.Error("failed to create match: %v", ErrNoMatchID)
+		return nil, 0, ""
+	}
+	s := &lockstepState{
+		matchID:   matchID,
+		presences: map[string]runtime.Presence{},
+		txHashes:  map[string]bool{},
+	}
+	ticks := h.lockstep.ticks.SubscribeToTickResults(matchID)
+	go func() {
+		// The ticks are queued until the next loop iteration, so that the event hub is never blocked by the match.
+		for tick := range ticks {
+			s.mu.Lock()
+			s.ticks = append(s.ticks, tick)
+			s.mu.Unlock()
+		}
+	}()
+	return s, h.lockstep.loopRate, ""
+}
+
+func (h *lockstepHandler) MatchJoinAttempt(
+	_ context.Context, _ runtime.Logger, _ *sql.DB, _ runtime.NakamaModule, _ runtime.MatchDispatcher, _ int64,
+	s any, _ runtime.Presence, _ map[string]string,
+) (any, bool, string) {
+	return s, true, ""
+}
+
+func (h *lockstepHandler) MatchJoin(
+	_ context.Context, _ runtime.Logger, _ *sql.DB, _ runtime.NakamaModule, _ runtime.MatchDispatcher, _ int64,
+	s any, presences []runtime.Presence,
+) any {
+	matchState, _ := s.(*lockstepState)
+	for _, p := range presences {
+		matchState.presences[p.GetSessionId()] = p
+	}
+	return matchState
+}
+
+func (h *lockstepHandler) MatchLeave(
+	_ context.Context, _ runtime.Logger, _ *sql.DB, _ runtime.NakamaModule, _ runtime.MatchDispatcher, _ int64,
+	s any, presences []runtime.Presence,
+) any {
+	matchState, _ := s.(*lockstepState)
+	for _, p := range presences {
+		delete(matchState.presences, p.GetSessionId())
+	}
+	return matchState
+}
+
+func (h *lockstepHandler) MatchLoop(
+	ctx context.Context, logger runtime.Logger, _ *sql.DB, _ runtime.NakamaModule, dispatcher runtime.MatchDispatcher,
+	_ int64, s any, messages []runtime.MatchData,
+) any {
+	matchState, _ := s.(*lockstepState)
+	for _, msg := range messages {
+		if msg.GetOpCode() != OpCodeInput {
+			continue
+		}
+		h.forward(ctx, logger, matchState, msg)
+	}
+
+	matchState.mu.Lock()
+	ticks, results := matchState.ticks, matchState.results
+	matchState.ticks, matchState.results = nil, nil
+	matchState.mu.Unlock()
+
+	for _, r := range results {
+		h.send(logger, dispatcher, OpCodeInputResult, r.result, []runtime.Presence{r.presence})
+	}
+	for _, tick := range ticks {
+		if tick.Tick <= matchState.lastTick && matchState.lastTick != 0 {
+			// Cardinal replayed a tick after a reconnect
+			continue
+		}
+		matchState.lastTick = tick.Tick
+		h.send(logger, dispatcher, OpCodeTick, matchState.tickMessage(tick), nil)
+	}
+
+	if len(matchState.presences) > 0 {
+		matchState.emptyTicks = 0
+		return matchState
+	}
+	matchState.emptyTicks++
+	if matchState.emptyTicks < emptyMatchTimeoutTicks*h.lockstep.loopRate {
+		return matchState
+	}
+	// Nakama does not call MatchTerminate when the loop ends the match.
+	h.end(matchState)
+	return nil
+}
+
+func (h *lockstepHandler) MatchTerminate(
+	_ context.Context, _ runtime.Logger, _ *sql.DB, _ runtime.NakamaModule, _ runtime.MatchDispatcher, _ int64,
+	s any, _ int,
+) any {
+	matchState, _ := s.(*lockstepState)
+	h.end(matchState)
+	return matchState
+}
+
+func (h *lockstepHandler) MatchSignal(
+	_ context.Context, _ runtime.Logger, _ *sql.DB, _ runtime.NakamaModule, _ runtime.MatchDispatcher, _ int64,
+	s any, data string,
+) (any, string) {
+	matchState, _ := s.(*lockstepState)
+	if data != SignalEnd {
+		return matchState, ""
+	}
+	// Let the next loop iteration end the match.
+	matchState.presences = map[string]runtime.Presence{}
+	matchState.emptyTicks = emptyMatchTimeoutTicks * h.lockstep.loopRate
+	return matchState, matchState.matchID
+}
+
+// forward signs the input of a presence as a transaction of its persona and sends it to Cardinal. The request is made
+// in the background, so that the match loop keeps its rate, and its result is sent to the presence by the next loop
+// iteration.
+func (h *lockstepHandler) forward(ctx context.Context, logger runtime.Logger, s *lockstepState, msg runtime.MatchData) {
+	presence := runtime.Presence(msg)
+	var input Input
+	if err := json.Unmarshal(msg.GetData(), &input); err != nil {
+		s.addResult(presence, InputResult{Error: eris.Wrap(ErrInvalidInput, err.Error()).Error()})
+		return
+	}
+	group, name, ok := strings.Cut(input.Message, ".")
+	if !ok || group == "" || name == "" || len(input.Body) == 0 {
+		s.addResult(presence, InputResult{ID: input.ID, Error: eris.Wrap(ErrInvalidInput,
+			"message must be the full name of a message, e.g. game.move, and body must be set").Error()})
+		return
+	}
+
+	// The transaction is signed by the persona of the sender, whose session is not the one of the match loop
+	//nolint:staticcheck // this is how Nakama passes the user and session to the runtime.
+	ctx = context.WithValue(context.WithValue(ctx, runtime.RUNTIME_CTX_USER_ID, msg.GetUserId()),
+		runtime.RUNTIME_CTX_SESSION_ID, msg.GetSessionId())
+	go func() {
+		result := InputResult{ID: input.ID}
+		hash, tick, err := h.sendTransaction(ctx, txEndpointPrefix+group+"/"+name, input.Body)
+		if err != nil {
+			logger.Debug("failed to forward input of %q: %s", msg.GetUserId(), eris.ToString(err, true))
+			result.Error = err.Error()
+		} else {
+			result.TxHash, result.Tick = hash, tick
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if err == nil {
+			s.txHashes[hash] = true
+		}
+		s.results = append(s.results, inputResult{presence: presence, result: result})
+	}()
+}
+
+// sendTransaction signs the message body as a transaction of the persona in ctx and sends it to the given endpoint
+// of Cardinal. It returns the hash of the transaction and the tick in which it is executed.
+func (h *lockstepHandler) sendTransaction(
+	ctx context.Context, endpoint string, body json.RawMessage,
+) (string, uint64, error) {
+	tx, err := h.lockstep.signers.SignTx(ctx, body)
+	if err != nil {
+		return "", 0, err
+	}
+	bz, err := json.Marshal(tx)
+	if err != nil {
+		return "", 0, eris.Wrap(err, "")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		utils.MakeHTTPURL(endpoint, h.lockstep.cardinalAddress), bytes.NewReader(bz))
+	if err != nil {
+		return "", 0, eris.Wrapf(err, "unable to make request to %q", endpoint)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	utils.SetCardinalAPIKey(req.Header)
+	resp, err := utils.DoRequest(req)
+	if err != nil {
+		return "", 0, err
+	}
+	defer resp.Body.Close()
+	var reply struct {
+		TxHash string
+		Tick   uint64
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&reply); err != nil {
+		return "", 0, eris.Wrapf(err, "unable to decode response from %q", endpoint)
+	}
+	return reply.TxHash, reply.Tick, nil
+}
+
+// tickMessage returns the message that is broadcast for a tick. Only the receipts of the transactions that were sent
+// through the match are included.
+func (s *lockstepState) tickMessage(tick events.TickResults) TickMessage {
+	msg := TickMessage{
+		Tick:     tick.Tick,
+		Events:   make([]json.RawMessage, 0, len(tick.Events)),
+		Receipts: []events.Receipt{},
+	}
+	for _, event := range tick.Events {
+		if json.Valid(event) {
+			msg.Events = append(msg.Events, event)
+			continue
+		}
+		// The event content isn't in JSON format. Wrap whatever it is in a JSON blob, like the event notifications.
+		wrapped, _ := json.Marshal(map[string]string{"message": string(event)})
+		msg.Events = append(msg.Events, wrapped)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, receipt := range tick.Receipts {
+		if s.txHashes[receipt.TxHash] {
+			delete(s.txHashes, receipt.TxHash)
+			msg.Receipts = append(msg.Receipts, receipt)
+		}
+	}
+	return msg
+}
+
+func (s *lockstepState) addResult(presence runtime.Presence, result InputResult) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.results = append(s.results, inputResult{presence: presence, result: result})
+}
+
+// send sends a message to the given presences, or to every presence of the match if presences is nil.
+func (h *lockstepHandler) send(
+	logger runtime.Logger, dispatcher runtime.MatchDispatcher, opCode int64, msg any, presences []runtime.Presence,
+) {
+	bz, err := json.Marshal(msg)
+	if err != nil {
+		logger.Error("failed to marshal match message: %s", eris.ToString(eris.Wrap(err, ""), true))
+		return
+	}
+	if err = dispatcher.BroadcastMessage(opCode, bz, presences, nil, true); err != nil {
+		logger.Error("failed to broadcast match message: %v", err)
+	}
+}
+
+// end stops receiving the ticks of the match.
+func (h *lockstepHandler) end(s *lockstepState) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ended {
+		return
+	}
+	s.ended = true
+	h.lockstep.ticks.Unsubscribe(s.matchID)
+}
@@ -0,0 +1,166 @@
+package match
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/heroiclabs/nakama-common/runtime"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/relay/nakama/events"
+	"pkg.world.dev/world-engine/relay/nakama/testutils"
+	"pkg.world.dev/world-engine/relay/nakama/utils"
+	"pkg.world.dev/world-engine/sign"
+)
+
+type fakeTickSource struct {
+	ch           chan events.TickResults
+	unsubscribed bool
+}
+
+func (f *fakeTickSource) SubscribeToTickResults(string) chan events.TickResults {
+	return f.ch
+}
+
+func (f *fakeTickSource) Unsubscribe(string) {
+	f.unsubscribed = true
+	close(f.ch)
+}
+
+// fakeSigner signs transactions with the user ID in the context as the persona tag.
+type fakeSigner struct{}
+
+func (fakeSigner) SignTx(ctx context.Context, data any) (*sign.Transaction, error) {
+	userID, err := utils.GetUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	body, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	return &sign.Transaction{PersonaTag: userID, Namespace: "world", Signature: "sig", Body: body}, nil
+}
+
+// fakePresence implements runtime.MatchData, which includes runtime.Presence.
+type fakePresence struct {
+	runtime.Presence
+	userID string
+	opCode int64
+	data   []byte
+}
+
+func (p fakePresence) GetUserId() string     { return p.userID }
+func (p fakePresence) GetSessionId() string  { return "session-" + p.userID }
+func (p fakePresence) GetOpCode() int64      { return p.opCode }
+func (p fakePresence) GetData() []byte       { return p.data }
+func (p fakePresence) GetReliable() bool     { return true }
+func (p fakePresence) GetReceiveTime() int64 { return 0 }
+func (p fakePresence) GetNodeId() string     { return "nakama1" }
+func (p fakePresence) GetUsername() string   { return p.userID }
+func (p fakePresence) GetHidden() bool       { return false }
+func (p fakePresence) GetPersistence() bool  { return false }
+func (p fakePresence) GetStatus() string     { return "" }
+func (p fakePresence) GetReason() runtime.PresenceReason {
+	return runtime.PresenceReasonUnknown
+}
+
+type broadcast struct {
+	opCode    int64
+	data      string
+	presences []runtime.Presence
+}
+
+type recordingDispatcher struct {
+	runtime.MatchDispatcher
+	broadcasts []broadcast
+}
+
+func (d *recordingDispatcher) BroadcastMessage(
+	opCode int64, data []byte, presences []runtime.Presence, _ runtime.Presence, _ bool,
+) error {
+	d.broadcasts = append(d.broadcasts, broadcast{opCode: opCode, data: string(data), presences: presences})
+	return nil
+}
+
+func TestLockstepMatchBroadcastsTicksAndForwardsInput(t *testing.T) {
+	var mu sync.Mutex
+	var posted []sign.Transaction
+	cardinal := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		assert.Equal(t, "/tx/game/move", r.URL.Path)
+		var tx sign.Transaction
+		assert.NilError(t, json.NewDecoder(r.Body).Decode(&tx))
+		posted = append(posted, tx)
+		_, _ = w.Write([]byte(`{"TxHash":"0xabc","Tick":7}`))
+	}))
+	t.Cleanup(cardinal.Close)
+
+	ticks := &fakeTickSource{ch: make(chan events.TickResults)}
+	lockstep := NewLockstep(ticks, fakeSigner{}, strings.TrimPrefix(cardinal.URL, "http://"), 0)
+	logger := &testutils.FakeLogger{}
+	m, err := lockstep.NewMatch(context.Background(), logger, nil, nil)
+	assert.NilError(t, err)
+
+	//nolint:staticcheck // this is how Nakama passes the match ID to the match handler.
+	ctx := context.WithValue(context.Background(), runtime.RUNTIME_CTX_MATCH_ID, "abc-123.nakama1")
+	s, rate, _ := m.MatchInit(ctx, logger, nil, nil, nil)
+	assert.Equal(t, DefaultLockstepLoopRate, rate)
+	alice := fakePresence{userID: "alice"}
+	s = m.MatchJoin(ctx, logger, nil, nil, nil, 1, s, []runtime.Presence{alice})
+
+	// Input is forwarded as a transaction of the sender's persona, and the result is sent back to the sender
+	dispatcher := &recordingDispatcher{}
+	input := fakePresence{userID: "alice", opCode: OpCodeInput,
+		data: []byte(`{"message":"game.move","body":{"direction":"up"},"id":"1"}`)}
+	invalid := fakePresence{userID: "alice", opCode: OpCodeInput, data: []byte(`{"message":"move"}`)}
+	s = m.MatchLoop(ctx, logger, nil, nil, dispatcher, 2, s, []runtime.MatchData{input, invalid})
+	for i := 0; len(dispatcher.broadcasts) < 2; i++ {
+		assert.Assert(t, i < 100, "the input was not forwarded")
+		time.Sleep(10 * time.Millisecond)
+		s = m.MatchLoop(ctx, logger, nil, nil, dispatcher, int64(3+i), s, nil)
+	}
+	results := map[string]bool{}
+	for _, b := range dispatcher.broadcasts {
+		assert.Equal(t, OpCodeInputResult, b.opCode)
+		assert.Equal(t, 1, len(b.presences))
+		assert.Equal(t, "alice", b.presences[0].GetUserId())
+		results[b.data] = true
+	}
+	assert.Assert(t, results[`{"id":"1","txHash":"0xabc","tick":7}`])
+	mu.Lock()
+	assert.Equal(t, 1, len(posted))
+	assert.Equal(t, "alice", posted[0].PersonaTag)
+	assert.Equal(t, `{"direction":"up"}`, string(posted[0].Body))
+	mu.Unlock()
+
+	// Every tick is broadcast in order, with the receipts of the transactions of the match
+	ticks.ch <- events.TickResults{Tick: 7, Events: [][]byte{[]byte(`{"moved":true}`), []byte("plain")},
+		Receipts: []events.Receipt{{TxHash: "0xabc", Tick: 7}, {TxHash: "0xother", Tick: 7}}}
+	ticks.ch <- events.TickResults{Tick: 8}
+	dispatcher.broadcasts = nil
+	for i := 0; len(dispatcher.broadcasts) < 2; i++ {
+		assert.Assert(t, i < 100, "the ticks were not broadcast")
+		s = m.MatchLoop(ctx, logger, nil, nil, dispatcher, int64(200+i), s, nil)
+		time.Sleep(time.Millisecond)
+	}
+	assert.Equal(t, OpCodeTick, dispatcher.broadcasts[0].opCode)
+	assert.Assert(t, dispatcher.broadcasts[0].presences == nil)
+	assert.Equal(t, `{"tick":7,"events":[{"moved":true},{"message":"plain"}],`+
+		`"receipts":[{"txHash":"0xabc","tick":7,"result":null,"errors":null}]}`, dispatcher.broadcasts[0].data)
+	assert.Equal(t, `{"tick":8,"events":[],"receipts":[]}`, dispatcher.broadcasts[1].data)
+
+	// The match stops receiving ticks when it ends
+	s, _ = m.MatchSignal(ctx, logger, nil, nil, nil, 300, s, SignalEnd)
+	s = m.MatchLoop(ctx, logger, nil, nil, dispatcher, 301, s, nil)
+	assert.Assert(t, s == nil)
+	assert.Assert(t, ticks.unsubscribed)
+	assert.Equal(t, 0, len(logger.GetErrors()))
+}
@@ -0,0 +1,252 @@
+package match
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/heroiclabs/nakama-common/runtime"
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/relay/nakama/utils"
+)
+
+const (
+	// ModuleName is the name the match handler is registered under. Pass it to nk.MatchCreate to create a match that
+	// is backed by its own Cardinal world.
+	ModuleName = "cardinal-world"
+
+	// tickRate is the number of match loop iterations per second. The match loop only tracks presences, so a low rate
+	// is enough.
+	tickRate = 1
+	// emptyMatchTimeoutTicks is how many ticks a match may stay empty before it ends and its world is torn down.
+	emptyMatchTimeoutTicks = 30 * tickRate
+
+	// SignalEnd ends the match (and tears down its world) when sent with nk.MatchSignal.
+	SignalEnd = "end"
+
+	worldsEndpoint = "worlds"
+)
+
+var (
+	EnabledEnvVar      = "ENABLE_MATCH_LIFECYCLE"
+	AdminAddressEnvVar = "CARDINAL_ADMIN_ADDRESS"
+	AdminTokenEnvVar   = "CARDINAL_ADMIN_TOKEN"
+
+	ErrNoMatchID = errors.New("context does not contain a match ID")
+)
+
+// Label is the label of every match created by the match handler. It is returned by nk.MatchGet and nk.MatchList and
+// tells clients which Cardinal world serves the match.
+type Label struct {
+	Namespace string `json:"namespace"`
+	// RoutePrefix is the path under which the world's HTTP routes (tx, query, events, ...) are served by Cardinal's
+	// world manager, e.g. worlds/match-1234/tx/game/move.
+	RoutePrefix string `json:"routePrefix"`
+}
+
+type createWorldRequest struct {
+	Name string `json:"name"`
+}
+
+type worldInfo struct {
+	Name string `json:"name"`
+	Tick uint64 `json:"tick"`
+}
+
+// Lifecycle ties Nakama matches to Cardinal worlds. Every match created with ModuleName gets a world of its own in
+// Cardinal's world manager (see cardinal.WorldManager.EnableInstanceAPI), which is stopped when the match ends.
+type Lifecycle struct {
+	adminAddress string
+	adminToken   string
+}
+
+func NewLifecycle(adminAddress, adminToken string) *Lifecycle {
+	return &Lifecycle{
+		adminAddress: adminAddress,
+		adminToken:   adminToken,
+	}
+}
+
+// NamespaceForMatch returns the namespace of the world that backs the given match. Nakama match IDs have the form
+// <uuid>.<node>; the node is dropped because namespaces may only contain alphanumeric characters and hyphens.
+func NamespaceForMatch(matchID string) string {
+	id, _, _ := strings.Cut(matchID, ".")
+	return "match-" + id
+}
+
+// NewLabel returns the label of a match whose world has the given namespace.
+func NewLabel(namespace string) Label {
+	return Label{
+		Namespace:   namespace,
+		RoutePrefix: worldsEndpoint + "/" + namespace,
+	}
+}
+
+// CreateWorld asks Cardinal to create and start a world with the given namespace.
+func (l *Lifecycle) CreateWorld(ctx context.Context, namespace string) error {
+	body, err := json.Marshal(createWorldRequest{Name: namespace})
+	if err != nil {
+		return eris.Wrap(err, "failed to marshal create world request")
+	}
+	resp, err := l.do(ctx, http.MethodPost, worldsEndpoint, body)
+	if err != nil {
+		return eris.Wrapf(err, "failed to create world %q", namespace)
+	}
+	defer resp.Body.Close()
+	var info worldInfo
+	return eris.Wrapf(json.NewDecoder(resp.Body).Decode(&info), "unable to decode response from %q", worldsEndpoint)
+}
+
+// StopWorld asks Cardinal to stop the world with the given namespace.
+func (l *Lifecycle) StopWorld(ctx context.Context, namespace string) error {
+	resp, err := l.do(ctx, http.MethodDelete, worldsEndpoint+"/"+namespace, nil)
+	if err != nil {
+		return eris.Wrapf(err, "failed to stop world %q", namespace)
+	}
+	return eris.Wrap(resp.Body.Close(), "")
+}
+
+func (l *Lifecycle) do(ctx context.Context, method, endpoint string, body []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(
+		ctx,
+		method,
+		utils.MakeHTTPURL(endpoint, l.adminAddress),
+		bytes.NewReader(body),
+	)
+	if err != nil {
+		return nil, eris.Wrapf(err, "unable to make request to %q", endpoint)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if l.adminToken != "" {
+		req.Header.Set("Authorization", "Bearer "+l.adminToken)
+	}
+	return utils.DoRequest(req)
+}
+
+// NewMatch returns the match handler that is registered under ModuleName.
+func (l *Lifecycle) NewMatch(
+	_ context.Context, _ runtime.Logger, _ *sql.DB, _ runtime.NakamaModule,
+) (runtime.Match, error) {
+	return &handler{lifecycle: l}, nil
+}
+
+// state is the state of a single match.
+type state struct {
+	namespace  string
+	presences  map[string]runtime.Presence
+	emptyTicks int
+}
+
+// handler is an authoritative match handler whose only job is to keep the match's Cardinal world alive for as long
+// as the match runs. Game traffic goes directly to the world's routes, not through the match loop.
+type handler struct {
+	lifecycle *Lifecycle
+}
+
+var _ runtime.Match = &handler{}
+
+func (h *handler) MatchInit(
+	ctx context.Context, logger runtime.Logger, _ *sql.DB, _ runtime.NakamaModule, _ map[string]any,
+) (any, int, string) {
+	matchID, ok := ctx.Value(runtime.RUNTIME_CTX_MATCH_ID).(string)
+	if !ok || matchID == "" {
+		logger.Error("failed to create match: %v", ErrNoMatchID)
+		return nil, 0, ""
+	}
+	namespace := NamespaceForMatch(matchID)
+	label, err := json.Marshal(NewLabel(namespace))
+	if err != nil {
+		logger.Error("failed to marshal label of match %q: %s", matchID, eris.ToString(err, true))
+		return nil, 0, ""
+	}
+	if err = h.lifecycle.CreateWorld(ctx, namespace); err != nil {
+		// Returning a nil state makes Nakama reject the match.
+		logger.Error("failed to create world for match %q: %s", matchID, eris.ToString(err, true))
+		return nil, 0, ""
+	}
+	logger.Debug("created world %q for match %q", namespace, matchID)
+	return &state{namespace: namespace, presences: map[string]runtime.Presence{}}, tickRate, string(label)
+}
+
+func (h *handler) MatchJoinAttempt(
+	_ context.Context, _ runtime.Logger, _ *sql.DB, _ runtime.NakamaModule, _ runtime.MatchDispatcher, _ int64,
+	s any, _ runtime.Presence, _ map[string]string,
+) (any, bool, string) {
+	return s, true, ""
+}
+
+func (h *handler) MatchJoin(
+	_ context.Context, _ runtime.Logger, _ *sql.DB, _ runtime.NakamaModule, _ runtime.MatchDispatcher, _ int64,
+	s any, presences []runtime.Presence,
+) any {
+	matchState, _ := s.(*state)
+	for _, p := range presences {
+		matchState.presences[p.GetSessionId()] = p
+	}
+	return matchState
+}
+
+func (h *handler) MatchLeave(
+	_ context.Context, _ runtime.Logger, _ *sql.DB, _ runtime.NakamaModule, _ runtime.MatchDispatcher, _ int64,
+	s any, presences []runtime.Presence,
+) any {
+	matchState, _ := s.(*state)
+	for _, p := range presences {
+		delete(matchState.presences, p.GetSessionId())
+	}
+	return matchState
+}
+
+func (h *handler) MatchLoop(
+	ctx context.Context, logger runtime.Logger, _ *sql.DB, _ runtime.NakamaModule, _ runtime.MatchDispatcher, _ int64,
+	s any, _ []runtime.MatchData,
+) any {
+	matchState, _ := s.(*state)
+	if len(matchState.presences) > 0 {
+		matchState.emptyTicks = 0
+		return matchState
+	}
+	matchState.emptyTicks++
+	if matchState.emptyTicks < emptyMatchTimeoutTicks {
+		return matchState
+	}
+	// Nakama does not call MatchTerminate when the loop ends the match, so the world is torn down here.
+	h.teardown(ctx, logger, matchState)
+	return nil
+}
+
+func (h *handler) MatchTerminate(
+	ctx context.Context, logger runtime.Logger, _ *sql.DB, _ runtime.NakamaModule, _ runtime.MatchDispatcher, _ int64,
+	s any, _ int,
+) any {
+	matchState, _ := s.(*state)
+	h.teardown(ctx, logger, matchState)
+	return matchState
+}
+
+func (h *handler) MatchSignal(
+	ctx context.Context, logger runtime.Logger, _ *sql.DB, _ runtime.NakamaModule, _ runtime.MatchDispatcher, _ int64,
+	s any, data string,
+) (any, string) {
+	matchState, _ := s.(*state)
+	if data != SignalEnd {
+		return matchState, ""
+	}
+	// Let the next loop iteration end the match.
+	matchState.presences = map[string]runtime.Presence{}
+	matchState.emptyTicks = emptyMatchTimeoutTicks
+	return matchState, matchState.namespace
+}
+
+func (h *handler) teardown(ctx context.Context, logger runtime.Logger, s *state) {
+	if err := h.lifecycle.StopWorld(ctx, s.namespace); err != nil {
+		logger.Error("failed to tear down world %q: %s", s.namespace, eris.ToString(err, true))
+		return
+	}
+	logger.Debug("tore down world %q", s.namespace)
+}
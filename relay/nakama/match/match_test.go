@@ -0,0 +1,113 @@
+package match
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/heroiclabs/nakama-common/runtime"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/relay/nakama/testutils"
+)
+
+// fakeWorldManager records the worlds that are created and stopped through the instance API.
+type fakeWorldManager struct {
+	mu     sync.Mutex
+	worlds map[string]bool
+}
+
+func (f *fakeWorldManager) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if r.Header.Get("Authorization") != "Bearer secret" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/worlds":
+		var req createWorldRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || f.worlds[req.Name] {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		f.worlds[req.Name] = true
+		_ = json.NewEncoder(w).Encode(worldInfo{Name: req.Name})
+	case r.Method == http.MethodDelete && strings.HasPrefix(r.URL.Path, "/worlds/"):
+		name := strings.TrimPrefix(r.URL.Path, "/worlds/")
+		if !f.worlds[name] {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		delete(f.worlds, name)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func newTestLifecycle(t *testing.T) (*Lifecycle, *fakeWorldManager) {
+	manager := &fakeWorldManager{worlds: map[string]bool{}}
+	server := httptest.NewServer(manager)
+	t.Cleanup(server.Close)
+	return NewLifecycle(strings.TrimPrefix(server.URL, "http://"), "secret"), manager
+}
+
+func TestNamespaceForMatchIsAValidNamespace(t *testing.T) {
+	assert.Equal(t, "match-4c2e1a07-2b5f-4f7e-9d0c-3f6f0b1e2a9d",
+		NamespaceForMatch("4c2e1a07-2b5f-4f7e-9d0c-3f6f0b1e2a9d.nakama1"))
+}
+
+func TestMatchOwnsItsWorld(t *testing.T) {
+	lifecycle, manager := newTestLifecycle(t)
+	logger := &testutils.FakeLogger{}
+	m, err := lifecycle.NewMatch(context.Background(), logger, nil, nil)
+	assert.NilError(t, err)
+
+	//nolint:staticcheck // this is how Nakama passes the match ID to the match handler.
+	ctx := context.WithValue(context.Background(), runtime.RUNTIME_CTX_MATCH_ID, "abc-123.nakama1")
+	s, rate, labelStr := m.MatchInit(ctx, logger, nil, nil, nil)
+	assert.Assert(t, s != nil)
+	assert.Equal(t, tickRate, rate)
+	var label Label
+	assert.NilError(t, json.Unmarshal([]byte(labelStr), &label))
+	assert.Equal(t, Label{Namespace: "match-abc-123", RoutePrefix: "worlds/match-abc-123"}, label)
+	assert.Assert(t, manager.worlds["match-abc-123"])
+
+	// A second match with the same ID cannot be created because its world already exists.
+	s2, _, _ := m.MatchInit(ctx, logger, nil, nil, nil)
+	assert.Assert(t, s2 == nil)
+	assert.Equal(t, 1, len(logger.GetErrors()))
+
+	// The match keeps running (and its world alive) until it has been empty long enough.
+	for i := 1; i < emptyMatchTimeoutTicks; i++ {
+		s = m.MatchLoop(ctx, logger, nil, nil, nil, int64(i), s, nil)
+		assert.Assert(t, s != nil)
+	}
+	assert.Assert(t, manager.worlds["match-abc-123"])
+	s = m.MatchLoop(ctx, logger, nil, nil, nil, emptyMatchTimeoutTicks, s, nil)
+	assert.Assert(t, s == nil)
+	assert.Assert(t, !manager.worlds["match-abc-123"])
+}
+
+func TestEndSignalTearsDownWorld(t *testing.T) {
+	lifecycle, manager := newTestLifecycle(t)
+	logger := &testutils.FakeLogger{}
+	m, err := lifecycle.NewMatch(context.Background(), logger, nil, nil)
+	assert.NilError(t, err)
+
+	//nolint:staticcheck // this is how Nakama passes the match ID to the match handler.
+	ctx := context.WithValue(context.Background(), runtime.RUNTIME_CTX_MATCH_ID, "def-456.nakama1")
+	s, _, _ := m.MatchInit(ctx, logger, nil, nil, nil)
+	assert.Assert(t, s != nil)
+
+	s, namespace := m.MatchSignal(ctx, logger, nil, nil, nil, 1, s, SignalEnd)
+	assert.Equal(t, "match-def-456", namespace)
+	s = m.MatchLoop(ctx, logger, nil, nil, nil, 2, s, nil)
+	assert.Assert(t, s == nil)
+	assert.Equal(t, 0, len(manager.worlds))
+	assert.Equal(t, 0, len(logger.GetErrors()))
+}
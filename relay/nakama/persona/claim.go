@@ -0,0 +1,142 @@
+package persona
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/heroiclabs/nakama-common/runtime"
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/relay/nakama/allowlist"
+	"pkg.world.dev/world-engine/relay/nakama/events"
+	"pkg.world.dev/world-engine/relay/nakama/signer"
+	"pkg.world.dev/world-engine/relay/nakama/utils"
+)
+
+// DefaultClaimTimeout is how long ClaimPersonaAndWait waits for Cardinal to process the claim by default.
+const DefaultClaimTimeout = 10 * time.Second
+
+var ErrPersonaTagUnavailable = errors.New("persona tag is not available")
+
+// ClaimRequest is the payload of the nakama/claim-persona-and-wait RPC.
+type ClaimRequest struct {
+	PersonaTag string `json:"personaTag"`
+}
+
+// ClaimResult is the reply of the nakama/claim-persona-and-wait RPC.
+type ClaimResult struct {
+	PersonaTag    string           `json:"personaTag"`
+	Status        personaTagStatus `json:"status"`
+	Tick          uint64           `json:"tick"`
+	TxHash        string           `json:"txHash"`
+	SignerAddress string           `json:"signerAddress"`
+	// Errors are the errors that Cardinal returned for the claim if it was rejected.
+	Errors []string `json:"errors,omitempty"`
+}
+
+// ClaimPersonaAndWait claims a persona tag for the current user like ClaimPersona, but registers the persona tag with
+// a key pair of the user's own, which is generated and saved in Nakama's storage layer the first time, and waits until
+// Cardinal has processed the claim. The nonces of the user's key are tracked in Nakama's storage layer as well, so
+// claims and transactions of the same user on several Nakama nodes never reuse a nonce.
+//
+// The status of the result is accepted or rejected, or still pending if Cardinal didn't process the claim before the
+// timeout. A pending claim is resolved later by ShowPersona, like the claims of ClaimPersona.
+func ClaimPersonaAndWait(
+	ctx context.Context,
+	nk runtime.NakamaModule,
+	req ClaimRequest,
+	cardinalAddress string,
+	namespace string,
+	personaTagAssignment *sync.Map,
+	timeout time.Duration,
+) (*ClaimResult, error) {
+	userID, err := utils.GetUserID(ctx)
+	if err != nil {
+		return nil, eris.Wrap(err, "failed to get userID for claim persona request")
+	}
+	if verified, err := allowlist.IsUserVerified(ctx, nk, userID); err != nil {
+		return nil, eris.Wrap(err, "failed to check if user is validated")
+	} else if !verified {
+		return nil, eris.Wrap(allowlist.ErrNotAllowlisted, "")
+	}
+	if req.PersonaTag == "" {
+		return nil, ErrPersonaTagEmpty
+	}
+
+	tag, err := LoadPersonaTagStorageObj(ctx, nk)
+	switch {
+	case eris.Is(eris.Cause(err), ErrPersonaTagStorageObjNotFound):
+		tag = &StorageObj{}
+	case err != nil:
+		return nil, eris.Wrap(err, "unable to get persona tag storage object")
+	case tag.Status == StatusPending:
+		return nil, eris.Errorf("persona tag %q is pending for this account", tag.PersonaTag)
+	case tag.Status == StatusAccepted:
+		return nil, eris.Errorf("persona tag %q already associated with this account", tag.PersonaTag)
+	}
+
+	// The persona tag is reserved for the user before the claim is sent, so that two users can't claim it at the same
+	// time.
+	if ok := setPersonaTagAssignment(req.PersonaTag, userID, personaTagAssignment); !ok {
+		return nil, eris.Wrapf(ErrPersonaTagUnavailable, "%q", req.PersonaTag)
+	}
+	release := func() {
+		personaTagAssignment.CompareAndDelete(req.PersonaTag, userID)
+	}
+
+	txSigner, err := signer.LoadOrCreateUserSigner(ctx, nk, userID)
+	if err != nil {
+		release()
+		return nil, eris.Wrap(err, "unable to load the signer of the user")
+	}
+	txHash, tick, err := createPersona(ctx, txSigner, req.PersonaTag, cardinalAddress, namespace)
+	if err != nil {
+		release()
+		return nil, eris.Wrap(err, "unable to make create persona request to cardinal")
+	}
+
+	// The version of the loaded storage object is kept, so that this save fails if a concurrent claim of the same
+	// user saved first.
+	tag.PersonaTag = req.PersonaTag
+	tag.Status = StatusPending
+	tag.Tick = tick
+	tag.TxHash = txHash
+	tag.SignerAddress = txSigner.SignerAddress()
+	if err = tag.SavePersonaTagStorageObj(ctx, nk); err != nil {
+		return nil, eris.Wrap(err, "unable to save persona tag storage object")
+	}
+	if tag, err = LoadPersonaTagStorageObj(ctx, nk); err != nil {
+		return nil, eris.Wrap(err, "unable to get persona tag storage object")
+	}
+
+	result := &ClaimResult{
+		PersonaTag:    tag.PersonaTag,
+		Status:        StatusPending,
+		Tick:          tick,
+		TxHash:        txHash,
+		SignerAddress: tag.SignerAddress,
+	}
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	receipt, err := events.WaitForReceipt(waitCtx, cardinalAddress, txHash, tick)
+	if eris.Is(err, context.DeadlineExceeded) {
+		return result, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	if success, _ := receipt.Result[createPersonaSuccess].(bool); success && len(receipt.Errors) == 0 {
+		tag.Status = StatusAccepted
+	} else {
+		tag.Status = StatusRejected
+		result.Errors = receipt.Errors
+		release()
+	}
+	if err = tag.SavePersonaTagStorageObj(ctx, nk); err != nil {
+		return nil, eris.Wrap(err, "unable to save persona tag storage object")
+	}
+	result.Status = tag.Status
+	return result, nil
+}
@@ -55,6 +55,9 @@ func ReclaimPersona(
 		return "", eris.Wrap(err, "re-claim failed, status must already have been accepted")
 	}
 	personaTag := tag.PersonaTag
+	if txSigner, err = tag.Signer(ctx, nk, txSigner); err != nil {
+		return "", eris.Wrap(err, "re-claim failed")
+	}
 
 	txHash, _, err = createPersona(ctx, txSigner, personaTag, cardinalAddress, namespace)
 	if err != nil {
@@ -189,6 +192,7 @@ func createPersona(
 		return "", 0, eris.Wrapf(err, "unable to make request to %q", createPersonaEndpoint)
 	}
 	req.Header.Set("Content-Type", "application/json")
+	utils.SetCardinalAPIKey(req.Header)
 	resp, err := utils.DoRequest(req)
 	if err != nil {
 		return "", 0, err
@@ -197,8 +201,8 @@ func createPersona(
 	defer resp.Body.Close()
 
 	if code := resp.StatusCode; code != http.StatusOK {
-		buf, err = io.ReadAll(resp.Body)
-		return "", 0, eris.Wrapf(err, "create persona response is not 200. code %v, body: %v", code, string(buf))
+		buf, _ = io.ReadAll(resp.Body)
+		return "", 0, eris.Errorf("create persona response is not 200. code %v, body: %v", code, string(buf))
 	}
 
 	var createPersonaResponse TxResponse
@@ -0,0 +1,161 @@
+package persona
+
+import (
+	"context"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"github.com/heroiclabs/nakama-common/runtime"
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/relay/nakama/signer"
+	"pkg.world.dev/world-engine/relay/nakama/utils"
+	"pkg.world.dev/world-engine/sign"
+)
+
+const (
+	// sessionCacheTTL is how long the persona tag and the signer of a session are cached. It bounds the memory that is
+	// used by sessions whose end is never reported, like RPCs that are called over HTTP.
+	sessionCacheTTL = 10 * time.Minute
+	// userLockCount is the number of locks that serialize the signing of transactions of the same user.
+	userLockCount = 64
+)
+
+// SessionSigners signs Cardinal transactions on behalf of the persona of the current session. The persona tag and the
+// signer (Nakama's signer, or the user's own one if the persona tag was claimed with ClaimPersonaAndWait) of a session
+// are loaded from Nakama's storage layer the first time the session signs a transaction, and cached afterwards. Only
+// personas that were accepted by Cardinal are cached.
+//
+// The nonces of the signers are kept in Nakama's storage layer. Transactions of the same user are signed one at a time
+// on this node, so that concurrent requests of the user neither collide on a nonce nor have to retry the conditional
+// writes of the nonce; the conditional writes still protect the nonce from requests on other Nakama nodes.
+type SessionSigners struct {
+	nk              runtime.NakamaModule
+	txSigner        signer.Signer
+	cardinalAddress string
+	namespace       string
+
+	mu       sync.Mutex
+	sessions map[string]*session
+	// userLocks are indexed by a hash of the user ID, so that the number of locks doesn't grow with the number of users.
+	userLocks [userLockCount]sync.Mutex
+}
+
+type session struct {
+	userID     string
+	personaTag string
+	txSigner   signer.Signer
+	expiresAt  time.Time
+}
+
+func NewSessionSigners(
+	nk runtime.NakamaModule,
+	txSigner signer.Signer,
+	cardinalAddress string,
+	namespace string,
+) *SessionSigners {
+	return &SessionSigners{
+		nk:              nk,
+		txSigner:        txSigner,
+		cardinalAddress: cardinalAddress,
+		namespace:       namespace,
+		sessions:        map[string]*session{},
+	}
+}
+
+// SignTx signs the given payload as a transaction of the persona of the current session. ErrNoPersonaTagForUser is
+// returned if the user doesn't have a persona tag that was accepted by Cardinal.
+func (s *SessionSigners) SignTx(ctx context.Context, data any) (*sign.Transaction, error) {
+	sess, err := s.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	lock := s.userLock(sess.userID)
+	lock.Lock()
+	defer lock.Unlock()
+	return sess.txSigner.SignTx(ctx, sess.personaTag, s.namespace, data)
+}
+
+// PersonaTag returns the accepted persona tag of the current session.
+func (s *SessionSigners) PersonaTag(ctx context.Context) (string, error) {
+	sess, err := s.load(ctx)
+	if err != nil {
+		return "", err
+	}
+	return sess.personaTag, nil
+}
+
+// Forget drops the cached persona tag and signer of the current session. It is called when the session ends.
+func (s *SessionSigners) Forget(ctx context.Context) {
+	userID, err := utils.GetUserID(ctx)
+	if err != nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.sessions, sessionKey(ctx, userID))
+}
+
+func (s *SessionSigners) load(ctx context.Context) (*session, error) {
+	userID, err := utils.GetUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	key := sessionKey(ctx, userID)
+	now := time.Now()
+
+	s.mu.Lock()
+	sess, ok := s.sessions[key]
+	s.mu.Unlock()
+	if ok && now.Before(sess.expiresAt) {
+		return sess, nil
+	}
+
+	ptr, err := LoadPersonaTagStorageObj(ctx, s.nk)
+	if err != nil {
+		return nil, err
+	}
+	ptr, err = ptr.AttemptToUpdatePending(ctx, s.nk, s.txSigner, s.cardinalAddress)
+	if err != nil {
+		return nil, err
+	}
+	if ptr.Status != StatusAccepted {
+		return nil, eris.Wrap(ErrNoPersonaTagForUser, "")
+	}
+	txSigner, err := ptr.Signer(ctx, s.nk, s.txSigner)
+	if err != nil {
+		return nil, err
+	}
+	sess = &session{
+		userID:     userID,
+		personaTag: ptr.PersonaTag,
+		txSigner:   txSigner,
+		expiresAt:  now.Add(sessionCacheTTL),
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for k, other := range s.sessions {
+		if !now.Before(other.expiresAt) {
+			delete(s.sessions, k)
+		}
+	}
+	s.sessions[key] = sess
+	return sess, nil
+}
+
+func (s *SessionSigners) userLock(userID string) *sync.Mutex {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(userID))
+	return &s.userLocks[h.Sum32()%userLockCount]
+}
+
+// sessionKey returns the key that the session of ctx is cached under. Requests that are not made in a session are
+// cached by their user.
+func sessionKey(ctx context.Context, userID string) string {
+	if sessionID := utils.GetSessionID(ctx); sessionID != "" {
+		return "session:" + sessionID
+	}
+	return "user:" + userID
+}
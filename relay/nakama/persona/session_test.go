@@ -0,0 +1,126 @@
+package persona_test
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/heroiclabs/nakama-common/runtime"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/relay/nakama/persona"
+	"pkg.world.dev/world-engine/relay/nakama/signer"
+	"pkg.world.dev/world-engine/relay/nakama/testutils"
+)
+
+func ctxWithSession(userID, sessionID string) context.Context {
+	//nolint:staticcheck // Nakama uses plain string keys for its runtime context values.
+	return context.WithValue(testutils.CtxWithUserID(userID), runtime.RUNTIME_CTX_SESSION_ID, sessionID)
+}
+
+func TestSessionSignersCacheTheAcceptedPersona(t *testing.T) {
+	ctx := ctxWithSession("alice", "session-1")
+	nk := testutils.NewFakeNakamaModule()
+	txSigner, err := signer.NewNakamaSigner(ctx, testutils.MockNoopLogger(t), nk, signer.NewNakamaNonceManager(nk))
+	assert.NilError(t, err)
+	sessions := persona.NewSessionSigners(nk, txSigner, "localhost:4040", "ns")
+
+	_, err = sessions.SignTx(ctx, map[string]any{"foo": "bar"})
+	assert.ErrorIs(t, err, persona.ErrPersonaTagStorageObjNotFound)
+
+	tag := &persona.StorageObj{PersonaTag: "alice-tag", Status: persona.StatusAccepted}
+	assert.NilError(t, tag.SavePersonaTagStorageObj(ctx, nk))
+
+	for wantNonce := uint64(1); wantNonce <= 3; wantNonce++ {
+		tx, err := sessions.SignTx(ctx, map[string]any{"foo": "bar"})
+		assert.NilError(t, err)
+		assert.Equal(t, "alice-tag", tx.PersonaTag)
+		assert.Equal(t, "ns", tx.Namespace)
+		assert.Equal(t, wantNonce, tx.Nonce)
+	}
+
+	// The persona of the session is cached, so it is not read from the storage layer again.
+	tag, err = persona.LoadPersonaTagStorageObj(ctx, nk)
+	assert.NilError(t, err)
+	tag.PersonaTag = "renamed"
+	assert.NilError(t, tag.SavePersonaTagStorageObj(ctx, nk))
+	personaTag, err := sessions.PersonaTag(ctx)
+	assert.NilError(t, err)
+	assert.Equal(t, "alice-tag", personaTag)
+
+	// Once the session ends, the persona is loaded again.
+	sessions.Forget(ctx)
+	personaTag, err = sessions.PersonaTag(ctx)
+	assert.NilError(t, err)
+	assert.Equal(t, "renamed", personaTag)
+}
+
+func TestSessionSignersDontCachePersonasThatAreNotAccepted(t *testing.T) {
+	ctx := ctxWithSession("alice", "session-1")
+	nk := testutils.NewFakeNakamaModule()
+	txSigner, err := signer.NewNakamaSigner(ctx, testutils.MockNoopLogger(t), nk, signer.NewNakamaNonceManager(nk))
+	assert.NilError(t, err)
+	sessions := persona.NewSessionSigners(nk, txSigner, "localhost:4040", "ns")
+
+	tag := &persona.StorageObj{PersonaTag: "alice-tag", Status: persona.StatusRejected}
+	assert.NilError(t, tag.SavePersonaTagStorageObj(ctx, nk))
+	_, err = sessions.SignTx(ctx, map[string]any{"foo": "bar"})
+	assert.ErrorIs(t, err, persona.ErrNoPersonaTagForUser)
+
+	tag, err = persona.LoadPersonaTagStorageObj(ctx, nk)
+	assert.NilError(t, err)
+	tag.Status = persona.StatusAccepted
+	assert.NilError(t, tag.SavePersonaTagStorageObj(ctx, nk))
+	_, err = sessions.SignTx(ctx, map[string]any{"foo": "bar"})
+	assert.NilError(t, err)
+}
+
+func TestSessionSignersUseTheUserSignerWithoutNonceCollisions(t *testing.T) {
+	ctx := ctxWithSession("alice", "session-1")
+	nk := testutils.NewFakeNakamaModule()
+	txSigner, err := signer.NewNakamaSigner(ctx, testutils.MockNoopLogger(t), nk, signer.NewNakamaNonceManager(nk))
+	assert.NilError(t, err)
+	userSigner, err := signer.LoadOrCreateUserSigner(ctx, nk, "alice")
+	assert.NilError(t, err)
+	tag := &persona.StorageObj{
+		PersonaTag:    "alice-tag",
+		Status:        persona.StatusAccepted,
+		SignerAddress: userSigner.SignerAddress(),
+	}
+	assert.NilError(t, tag.SavePersonaTagStorageObj(ctx, nk))
+	sessions := persona.NewSessionSigners(nk, txSigner, "localhost:4040", "ns")
+
+	// Concurrent requests of the same user, some of them in other sessions.
+	const requests = 20
+	var (
+		mu     sync.Mutex
+		nonces []uint64
+		wg     sync.WaitGroup
+	)
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			reqCtx := ctx
+			if i%2 == 1 {
+				reqCtx = ctxWithSession("alice", "session-2")
+			}
+			tx, err := sessions.SignTx(reqCtx, map[string]any{"i": i})
+			assert.Check(t, err == nil, err)
+			if err != nil {
+				return
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			nonces = append(nonces, tx.Nonce)
+		}(i)
+	}
+	wg.Wait()
+
+	sort.Slice(nonces, func(i, j int) bool { return nonces[i] < nonces[j] })
+	assert.Equal(t, requests, len(nonces))
+	for i, nonce := range nonces {
+		assert.Equal(t, uint64(i+1), nonce)
+	}
+}
@@ -29,6 +29,10 @@ type StorageObj struct {
 	Status     personaTagStatus `json:"status"`
 	Tick       uint64           `json:"tick"`
 	TxHash     string           `json:"txHash"`
+	// SignerAddress is the address that the persona tag is registered with in Cardinal if it was claimed with a key
+	// pair of the user's own (see ClaimPersonaAndWait). It is empty if the persona tag is registered with the address of
+	// Nakama's signer.
+	SignerAddress string `json:"signerAddress,omitempty"`
 	// version is used with Nakama storage layer to allow for optimistic locking. Saving this storage
 	// object succeeds only if the passed in version matches the version in the storage layer.
 	// see https://heroiclabs.com/docs/nakama/concepts/storage/collections/#conditional-writes for more info.
@@ -125,9 +129,35 @@ func (p *StorageObj) verifyPersonaTag(ctx context.Context, txSigner signer.Signe
 		return false, err
 	}
 	signerAddress := txSigner.SignerAddress()
+	if p.SignerAddress != "" {
+		signerAddress = p.SignerAddress
+	}
 	return gameSignerAddress == signerAddress, nil
 }
 
+// Signer returns the signer that transactions of the persona tag must be signed with: the user's own signer if the
+// persona tag was claimed with a key pair of the user's own, and txSigner otherwise. The user is read from ctx.
+func (p *StorageObj) Signer(
+	ctx context.Context, nk runtime.NakamaModule, txSigner signer.Signer,
+) (signer.Signer, error) {
+	if p.SignerAddress == "" {
+		return txSigner, nil
+	}
+	userID, err := utils.GetUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	userSigner, err := signer.LoadUserSigner(ctx, nk, userID)
+	if err != nil {
+		return nil, eris.Wrap(err, "failed to load the signer of the user")
+	}
+	if userSigner.SignerAddress() != p.SignerAddress {
+		return nil, eris.Errorf("the persona tag is registered with %s, but the key of the user has address %s",
+			p.SignerAddress, userSigner.SignerAddress())
+	}
+	return userSigner, nil
+}
+
 // SavePersonaTagStorageObj saves the given StorageObj to the Nakama DB for the current user.
 func (p *StorageObj) SavePersonaTagStorageObj(ctx context.Context, nk runtime.NakamaModule) error {
 	userID, err := utils.GetUserID(ctx)
@@ -183,6 +213,7 @@ func queryPersonaSigner(
 		return "", eris.Wrap(err, "")
 	}
 	httpReq.Header.Set("Content-Type", "application/json")
+	utils.SetCardinalAPIKey(httpReq.Header)
 	httpResp, err := utils.DoRequest(httpReq)
 	if err != nil {
 		return "", err
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"io"
 	"os"
 	"strconv"
@@ -12,8 +13,11 @@ import (
 
 	"pkg.world.dev/world-engine/relay/nakama/allowlist"
 	"pkg.world.dev/world-engine/relay/nakama/events"
+	"pkg.world.dev/world-engine/relay/nakama/match"
 	"pkg.world.dev/world-engine/relay/nakama/persona"
 	"pkg.world.dev/world-engine/relay/nakama/signer"
+	"pkg.world.dev/world-engine/relay/nakama/sponsor"
+	"pkg.world.dev/world-engine/relay/nakama/wallet"
 )
 
 // initPersonaEndpoints sets up the nakame RPC endpoints that are used to claim a persona tag and display a persona tag.
@@ -22,6 +26,7 @@ func initPersonaTagEndpoints(
 	initializer runtime.Initializer,
 	verifier *persona.Verifier,
 	notifier *events.Notifier,
+	sponsorship *sponsor.Sponsor,
 	txSigner signer.Signer,
 	cardinalAddress string,
 	globalNamespace string,
@@ -32,6 +37,7 @@ func initPersonaTagEndpoints(
 		handleClaimPersona(
 			verifier,
 			notifier,
+			sponsorship,
 			txSigner,
 			cardinalAddress,
 			globalNamespace,
@@ -41,9 +47,62 @@ func initPersonaTagEndpoints(
 	if err != nil {
 		return eris.Wrap(err, "")
 	}
+	err = initializer.RegisterRpc(
+		"nakama/claim-persona-and-wait",
+		handleClaimPersonaAndWait(sponsorship, cardinalAddress, globalNamespace, globalPersonaAssignment),
+	)
+	if err != nil {
+		return eris.Wrap(err, "")
+	}
 	return eris.Wrap(initializer.RegisterRpc("nakama/show-persona", handleShowPersona(txSigner, cardinalAddress)), "")
 }
 
+// initSponsorship sets up the relayer that pays for persona creation and address binding on behalf of new players.
+// It returns nil if sponsorship is not enabled.
+func initSponsorship(
+	_ runtime.Logger,
+	initializer runtime.Initializer,
+	nk runtime.NakamaModule,
+	txSigner signer.Signer,
+	cardinalAddress string,
+	globalNamespace string,
+) (*sponsor.Sponsor, error) {
+	enabledStr := os.Getenv(sponsor.EnabledEnvVar)
+	if enabledStr == "" {
+		return nil, nil //nolint:nilnil // sponsorship is disabled
+	}
+	enabled, err := strconv.ParseBool(enabledStr)
+	if err != nil {
+		return nil, eris.Wrapf(err, "the %s flag was set, however the value %q is invalid", sponsor.EnabledEnvVar,
+			enabledStr)
+	}
+	if !enabled {
+		return nil, nil //nolint:nilnil // sponsorship is disabled
+	}
+
+	limits := sponsor.DefaultLimits()
+	for envVar, limit := range map[string]*int{
+		sponsor.MaxAddressesEnvVar: &limits.MaxAddressesPerUser,
+		sponsor.DailyBudgetEnvVar:  &limits.DailyBudget,
+		sponsor.MaxPerIPEnvVar:     &limits.MaxPerIPPerDay,
+	} {
+		value := os.Getenv(envVar)
+		if value == "" {
+			continue
+		}
+		if *limit, err = strconv.Atoi(value); err != nil || *limit < 0 {
+			return nil, eris.Errorf("%s must be a non-negative integer, got %q", envVar, value)
+		}
+	}
+
+	sponsorship := sponsor.New(nk, txSigner, cardinalAddress, globalNamespace, limits)
+	err = initializer.RegisterRpc("nakama/authorize-address", handleAuthorizeAddress(sponsorship))
+	if err != nil {
+		return nil, eris.Wrap(err, "failed to register rpc")
+	}
+	return sponsorship, nil
+}
+
 func initAllowlist(_ runtime.Logger, initializer runtime.Initializer) error {
 	enabledStr := os.Getenv(allowlist.EnabledEnvVar)
 	if enabledStr == "" {
@@ -70,6 +129,125 @@ func initAllowlist(_ runtime.Logger, initializer runtime.Initializer) error {
 	return nil
 }
 
+// initWalletBridge sets up the hooks that keep Nakama's wallet in sync with Cardinal's wallet plugin. Validated in-app
+// purchases credit currency in Cardinal and then in Nakama, currency spent or earned in Cardinal is applied to the
+// Nakama wallet, unsettled journal entries are retried in the background, and the nakama/wallet-reconcile endpoint
+// reports any mismatch between the two.
+func initWalletBridge(
+	ctx context.Context,
+	logger runtime.Logger,
+	initializer runtime.Initializer,
+	nk runtime.NakamaModule,
+	eventHub *events.EventHub,
+	txSigner signer.Signer,
+	cardinalAddress string,
+	globalNamespace string,
+	globalPersonaAssignment *sync.Map,
+) error {
+	enabledStr := os.Getenv(wallet.EnabledEnvVar)
+	if enabledStr == "" {
+		return nil
+	}
+	enabled, err := strconv.ParseBool(enabledStr)
+	if err != nil {
+		return eris.Wrapf(err, "the %s flag was set, however the value %q is invalid", wallet.EnabledEnvVar, enabledStr)
+	}
+	if !enabled {
+		return nil
+	}
+
+	currency := os.Getenv(wallet.CurrencyEnvVar)
+	if currency == "" {
+		return eris.Errorf("must specify a wallet currency via %s", wallet.CurrencyEnvVar)
+	}
+	products := map[string]int64{}
+	if productsStr := os.Getenv(wallet.ProductsEnvVar); productsStr != "" {
+		if err = json.Unmarshal([]byte(productsStr), &products); err != nil {
+			return eris.Wrapf(err, "%s must be a JSON object of product IDs to amounts", wallet.ProductsEnvVar)
+		}
+	}
+
+	bridge := wallet.NewBridge(nk, txSigner, cardinalAddress, globalNamespace, currency, products, globalPersonaAssignment)
+	go bridge.ConsumeEvents(ctx, logger, eventHub.SubscribeToEvents("wallet"))
+	go bridge.RunRedrive(ctx, logger, wallet.RedriveInterval)
+
+	if err = initializer.RegisterAfterValidatePurchaseApple(handleValidatedPurchaseApple(bridge)); err != nil {
+		return eris.Wrap(err, "failed to register apple purchase hook")
+	}
+	if err = initializer.RegisterAfterValidatePurchaseGoogle(handleValidatedPurchaseGoogle(bridge)); err != nil {
+		return eris.Wrap(err, "failed to register google purchase hook")
+	}
+	return eris.Wrap(initializer.RegisterRpc("nakama/wallet-reconcile", handleWalletReconcile(bridge)), "")
+}
+
+// initMatchLifecycle registers the match handler that backs every match with its own Cardinal world, and the RPCs
+// used to create such matches. Cardinal must be hosting its worlds with a WorldManager that has the instance API
+// enabled.
+func initMatchLifecycle(initializer runtime.Initializer, cardinalAddress string) error {
+	enabledStr := os.Getenv(match.EnabledEnvVar)
+	if enabledStr == "" {
+		return nil
+	}
+	enabled, err := strconv.ParseBool(enabledStr)
+	if err != nil {
+		return eris.Wrapf(err, "the %s flag was set, however the value %q is invalid", match.EnabledEnvVar, enabledStr)
+	}
+	if !enabled {
+		return nil
+	}
+
+	adminAddress := os.Getenv(match.AdminAddressEnvVar)
+	if adminAddress == "" {
+		adminAddress = cardinalAddress
+	}
+	lifecycle := match.NewLifecycle(adminAddress, os.Getenv(match.AdminTokenEnvVar))
+	if err = initializer.RegisterMatch(match.ModuleName, lifecycle.NewMatch); err != nil {
+		return eris.Wrap(err, "failed to register match handler")
+	}
+	if err = initializer.RegisterRpc("nakama/create-match", handleCreateMatch); err != nil {
+		return eris.Wrap(err, "")
+	}
+	return eris.Wrap(initializer.RegisterRpc("nakama/match-namespace", handleMatchNamespace), "")
+}
+
+// initLockstepMatch registers the lockstep match handler, which broadcasts every Cardinal tick to the presences of a
+// match and forwards their input to Cardinal as transactions of their personas.
+func initLockstepMatch(
+	initializer runtime.Initializer, eventHub *events.EventHub, sessionSigners *persona.SessionSigners,
+	cardinalAddress string,
+) error {
+	enabledStr := os.Getenv(match.LockstepEnabledEnvVar)
+	if enabledStr == "" {
+		return nil
+	}
+	enabled, err := strconv.ParseBool(enabledStr)
+	if err != nil {
+		return eris.Wrapf(err, "the %s flag was set, however the value %q is invalid", match.LockstepEnabledEnvVar,
+			enabledStr)
+	}
+	if !enabled {
+		return nil
+	}
+
+	loopRate := 0
+	if loopRateStr := os.Getenv(match.LockstepLoopRateEnvVar); loopRateStr != "" {
+		if loopRate, err = strconv.Atoi(loopRateStr); err != nil {
+			return eris.Wrapf(err, "the value %q of %s is invalid", loopRateStr, match.LockstepLoopRateEnvVar)
+		}
+	}
+	lockstep := match.NewLockstep(eventHub, sessionSigners, cardinalAddress, loopRate)
+	return eris.Wrap(initializer.RegisterMatch(match.LockstepModuleName, lockstep.NewMatch),
+		"failed to register lockstep match handler")
+}
+
+// initHealthEndpoint sets up the RPC that reports the connectivity to Cardinal.
+func initHealthEndpoint(
+	initializer runtime.Initializer, eventHub *events.EventHub, cardinalAddress string, globalNamespace string,
+) error {
+	return eris.Wrap(
+		initializer.RegisterRpc("nakama/health", handleHealth(eventHub, cardinalAddress, globalNamespace)), "")
+}
+
 func initSaveFileStorage(_ runtime.Logger, initializer runtime.Initializer) error {
 	err := initializer.RegisterRpc(
 		"nakama/save",
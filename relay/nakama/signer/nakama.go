@@ -6,6 +6,7 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"errors"
+	"strings"
 
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/heroiclabs/nakama-common/runtime"
@@ -25,6 +26,7 @@ var _ Signer = &nakamaSigner{}
 var (
 	ErrNoStorageObjectFound       = errors.New("no storage object found")
 	ErrTooManyStorageObjectsFound = errors.New("too many storage objects found")
+	ErrInvalidPrivateKey          = errors.New("invalid private key")
 )
 
 type privateKeyStorageObj struct {
@@ -93,9 +95,33 @@ func NewNakamaSigner(ctx context.Context, logger runtime.Logger, nk runtime.Naka
 		}
 	}
 	// We've either loaded the existing private key, or initialized a new one
-	privateKey, err := crypto.HexToECDSA(privateKeyHex)
+	return newNakamaSigner(nk, privateKeyHex, nonceManager)
+}
+
+// NewNakamaSignerFromKey makes a signer from the given hex encoded private key instead of the one stored in Nakama's
+// DB, e.g. to keep the signer address when Nakama's DB is reset. The nonce is still stored in Nakama's DB.
+func NewNakamaSignerFromKey(
+	ctx context.Context, nk runtime.NakamaModule, privateKeyHex string, nonceManager NonceManager,
+) (Signer, error) {
+	s, err := newNakamaSigner(nk, privateKeyHex, nonceManager)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = getNonce(ctx, nk); err != nil {
+		if !eris.Is(eris.Cause(err), ErrNoStorageObjectFound) {
+			return nil, eris.Wrap(err, "failed to get nonce")
+		}
+		if err = nonceManager.SetNonce(ctx, 1); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+func newNakamaSigner(nk runtime.NakamaModule, privateKeyHex string, nonceManager NonceManager) (Signer, error) {
+	privateKey, err := crypto.HexToECDSA(strings.TrimPrefix(privateKeyHex, "0x"))
 	if err != nil {
-		return nil, eris.Wrap(err, "")
+		return nil, eris.Wrap(ErrInvalidPrivateKey, err.Error())
 	}
 	signerAddress := crypto.PubkeyToAddress(privateKey.PublicKey).Hex()
 	return &nakamaSigner{
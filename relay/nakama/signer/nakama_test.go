@@ -2,8 +2,12 @@ package signer
 
 import (
 	"context"
+	"encoding/hex"
+	"errors"
 	"testing"
 
+	"github.com/ethereum/go-ethereum/crypto"
+
 	"pkg.world.dev/world-engine/assert"
 	"pkg.world.dev/world-engine/relay/nakama/testutils"
 )
@@ -58,3 +62,31 @@ func TestNonceIsIncremented(t *testing.T) {
 		assert.Equal(t, tx.Nonce, uint64(wantNonce))
 	}
 }
+
+func TestSignerCanUseTheGivenPrivateKey(t *testing.T) {
+	ctx := context.Background()
+	nk := testutils.NewFakeNakamaModule()
+	nonceManager := NewNakamaNonceManager(nk)
+
+	privateKey, err := crypto.GenerateKey()
+	assert.NilError(t, err)
+	privateKeyHex := hex.EncodeToString(crypto.FromECDSA(privateKey))
+
+	txSigner, err := NewNakamaSignerFromKey(ctx, nk, "0x"+privateKeyHex, nonceManager)
+	assert.NilError(t, err)
+	assert.Equal(t, crypto.PubkeyToAddress(privateKey.PublicKey).Hex(), txSigner.SignerAddress())
+
+	tx, err := txSigner.SignTx(ctx, "foobar", "baz", map[string]any{"foo": "bar"})
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(1), tx.Nonce)
+
+	// The nonce is kept when the signer is made again.
+	txSigner, err = NewNakamaSignerFromKey(ctx, nk, privateKeyHex, nonceManager)
+	assert.NilError(t, err)
+	tx, err = txSigner.SignTx(ctx, "foobar", "baz", map[string]any{"foo": "bar"})
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(2), tx.Nonce)
+
+	_, err = NewNakamaSignerFromKey(ctx, nk, "not-a-key", nonceManager)
+	assert.Check(t, errors.Is(err, ErrInvalidPrivateKey))
+}
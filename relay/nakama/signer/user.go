@@ -0,0 +1,159 @@
+package signer
+
+// user.go manages the key pairs that are generated for individual players, so that each persona can be registered
+// with its own signer address instead of Nakama's.
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/heroiclabs/nakama-common/runtime"
+	"github.com/rotisserie/eris"
+)
+
+const (
+	userKeyCollection = "user_key_collection"
+	userKeyKey        = "private_key"
+	userNonceKey      = "nonce"
+	// maxNonceAttempts is the number of times a nonce is re-read after a concurrent request incremented it first.
+	maxNonceAttempts = 10
+)
+
+// LoadUserSigner returns a Signer that signs with the key pair of the given user. ErrNoStorageObjectFound is returned
+// if no key pair was generated for the user.
+func LoadUserSigner(ctx context.Context, nk runtime.NakamaModule, userID string) (Signer, error) {
+	objs, err := nk.StorageRead(ctx, []*runtime.StorageRead{
+		{Collection: userKeyCollection, Key: userKeyKey, UserID: userID},
+	})
+	if err != nil {
+		return nil, eris.Wrap(err, "")
+	}
+	if len(objs) == 0 {
+		return nil, eris.Wrap(ErrNoStorageObjectFound, "")
+	}
+	var pkObj privateKeyStorageObj
+	if err = json.Unmarshal([]byte(objs[0].GetValue()), &pkObj); err != nil {
+		return nil, eris.Wrap(err, "")
+	}
+	privateKey, err := crypto.HexToECDSA(pkObj.Value)
+	if err != nil {
+		return nil, eris.Wrap(err, "")
+	}
+	return &nakamaSigner{
+		nk:            nk,
+		privateKey:    privateKey,
+		signerAddress: crypto.PubkeyToAddress(privateKey.PublicKey).Hex(),
+		nonceManager:  &userNonceManager{nk: nk, userID: userID},
+	}, nil
+}
+
+// LoadOrCreateUserSigner returns a Signer that signs with the key pair of the given user. The key pair is generated
+// and saved in Nakama's storage layer the first time.
+func LoadOrCreateUserSigner(ctx context.Context, nk runtime.NakamaModule, userID string) (Signer, error) {
+	txSigner, err := LoadUserSigner(ctx, nk, userID)
+	if !eris.Is(eris.Cause(err), ErrNoStorageObjectFound) {
+		return txSigner, err
+	}
+
+	privateKey, err := crypto.GenerateKey()
+	if err != nil {
+		return nil, eris.Wrap(err, "")
+	}
+	keyBuf, err := json.Marshal(privateKeyStorageObj{Value: hex.EncodeToString(crypto.FromECDSA(privateKey))})
+	if err != nil {
+		return nil, eris.Wrap(err, "")
+	}
+	nonceBuf, err := json.Marshal(privateKeyStorageObj{Value: "1"})
+	if err != nil {
+		return nil, eris.Wrap(err, "")
+	}
+	// The key and its nonce are only written if the user has no key yet, so that concurrent requests of the same user
+	// can't overwrite each other's key.
+	_, writeErr := nk.StorageWrite(ctx, []*runtime.StorageWrite{
+		userStorageWrite(userID, userKeyKey, string(keyBuf), "*"),
+		userStorageWrite(userID, userNonceKey, string(nonceBuf), "*"),
+	})
+	txSigner, err = LoadUserSigner(ctx, nk, userID)
+	if err != nil {
+		if writeErr != nil {
+			return nil, eris.Wrap(writeErr, "failed to save the key pair of the user")
+		}
+		return nil, err
+	}
+	return txSigner, nil
+}
+
+func userStorageWrite(userID, key, value, version string) *runtime.StorageWrite {
+	return &runtime.StorageWrite{
+		Collection:      userKeyCollection,
+		Key:             key,
+		UserID:          userID,
+		Value:           value,
+		Version:         version,
+		PermissionRead:  runtime.STORAGE_PERMISSION_NO_READ,
+		PermissionWrite: runtime.STORAGE_PERMISSION_NO_WRITE,
+	}
+}
+
+// userNonceManager keeps track of the nonce of a user's key pair. Unlike nakamaNonceManager, it doesn't rely on a
+// mutex: the nonce is updated with a conditional write, so that Nakama nodes that sign for the same user at the same
+// time never use the same nonce.
+type userNonceManager struct {
+	nk     runtime.NakamaModule
+	userID string
+}
+
+var _ NonceManager = &userNonceManager{}
+
+func (u *userNonceManager) SetNonce(ctx context.Context, nonce uint64) error {
+	buf, err := json.Marshal(privateKeyStorageObj{Value: strconv.FormatUint(nonce, 10)})
+	if err != nil {
+		return eris.Wrap(err, "")
+	}
+	_, err = u.nk.StorageWrite(ctx, []*runtime.StorageWrite{userStorageWrite(u.userID, userNonceKey, string(buf), "")})
+	return eris.Wrap(err, "")
+}
+
+func (u *userNonceManager) IncNonce(ctx context.Context) (nonce uint64, err error) {
+	for attempt := 0; attempt < maxNonceAttempts; attempt++ {
+		var version string
+		nonce, version, err = u.getNonce(ctx)
+		if err != nil {
+			return 0, err
+		}
+		var buf []byte
+		buf, err = json.Marshal(privateKeyStorageObj{Value: strconv.FormatUint(nonce+1, 10)})
+		if err != nil {
+			return 0, eris.Wrap(err, "")
+		}
+		_, err = u.nk.StorageWrite(ctx, []*runtime.StorageWrite{
+			userStorageWrite(u.userID, userNonceKey, string(buf), version),
+		})
+		if err == nil {
+			return nonce, nil
+		}
+		// Another request incremented the nonce since it was read. Read it again.
+	}
+	return 0, eris.Wrapf(err, "failed to increment the nonce of user %q after %d attempts", u.userID, maxNonceAttempts)
+}
+
+func (u *userNonceManager) getNonce(ctx context.Context) (nonce uint64, version string, err error) {
+	objs, err := u.nk.StorageRead(ctx, []*runtime.StorageRead{
+		{Collection: userKeyCollection, Key: userNonceKey, UserID: u.userID},
+	})
+	if err != nil {
+		return 0, "", eris.Wrap(err, "")
+	}
+	if len(objs) == 0 {
+		return 0, "", eris.Wrap(ErrNoStorageObjectFound, "nonce of the user")
+	}
+	var obj privateKeyStorageObj
+	if err = json.Unmarshal([]byte(objs[0].GetValue()), &obj); err != nil {
+		return 0, "", eris.Wrap(err, "")
+	}
+	nonce, err = strconv.ParseUint(obj.Value, 10, 64)
+	return nonce, objs[0].GetVersion(), eris.Wrap(err, "")
+}
@@ -0,0 +1,90 @@
+package signer
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"testing"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/relay/nakama/testutils"
+)
+
+func TestUserKeyIsCreatedOnce(t *testing.T) {
+	ctx := context.Background()
+	nk := testutils.NewFakeNakamaModule()
+
+	_, err := LoadUserSigner(ctx, nk, "alice")
+	assert.ErrorIs(t, err, ErrNoStorageObjectFound)
+
+	first, err := LoadOrCreateUserSigner(ctx, nk, "alice")
+	assert.NilError(t, err)
+	second, err := LoadOrCreateUserSigner(ctx, nk, "alice")
+	assert.NilError(t, err)
+	assert.Equal(t, first.SignerAddress(), second.SignerAddress())
+
+	loaded, err := LoadUserSigner(ctx, nk, "alice")
+	assert.NilError(t, err)
+	assert.Equal(t, first.SignerAddress(), loaded.SignerAddress())
+
+	// Every user gets a key of their own.
+	other, err := LoadOrCreateUserSigner(ctx, nk, "bob")
+	assert.NilError(t, err)
+	assert.NotEqual(t, first.SignerAddress(), other.SignerAddress())
+}
+
+func TestUserNonceIsIncremented(t *testing.T) {
+	ctx := context.Background()
+	nk := testutils.NewFakeNakamaModule()
+
+	txSigner, err := LoadOrCreateUserSigner(ctx, nk, "alice")
+	assert.NilError(t, err)
+	for wantNonce := 1; wantNonce <= 5; wantNonce++ {
+		tx, err := txSigner.SignTx(ctx, "foobar", "baz", map[string]any{"foo": "bar"})
+		assert.NilError(t, err)
+		assert.Equal(t, tx.Nonce, uint64(wantNonce))
+	}
+
+	// The nonce is kept in the storage layer, so a signer that is loaded later continues from it.
+	txSigner, err = LoadUserSigner(ctx, nk, "alice")
+	assert.NilError(t, err)
+	tx, err := txSigner.SignTx(ctx, "foobar", "baz", map[string]any{"foo": "bar"})
+	assert.NilError(t, err)
+	assert.Equal(t, tx.Nonce, uint64(6))
+}
+
+func TestUserNonceIsNeverReusedConcurrently(t *testing.T) {
+	ctx := context.Background()
+	nk := testutils.NewFakeNakamaModule()
+	_, err := LoadOrCreateUserSigner(ctx, nk, "alice")
+	assert.NilError(t, err)
+
+	const workers, perWorker = 3, 3
+	var (
+		mu     sync.Mutex
+		nonces []uint64
+		wg     sync.WaitGroup
+	)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Each worker has its own manager, like separate Nakama nodes.
+			manager := &userNonceManager{nk: nk, userID: "alice"}
+			for j := 0; j < perWorker; j++ {
+				nonce, err := manager.IncNonce(ctx)
+				assert.Check(t, err == nil, err)
+				mu.Lock()
+				nonces = append(nonces, nonce)
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	sort.Slice(nonces, func(i, j int) bool { return nonces[i] < nonces[j] })
+	assert.Equal(t, workers*perWorker, len(nonces))
+	for i, nonce := range nonces {
+		assert.Equal(t, uint64(i+1), nonce)
+	}
+}
@@ -0,0 +1,339 @@
+// Package sponsor lets the shard operator's relayer pay for the onboarding transactions of new players: claiming a
+// persona tag and binding an EVM address to it. Players never need to fund a wallet before they can play; the relayer
+// signs and submits the transactions on their behalf.
+//
+// Since every sponsored transaction costs the operator, sponsorship is rate limited: each user can bind a limited
+// number of addresses, each client IP can only use a limited number of sponsored transactions per day, and the relayer
+// stops sponsoring once its daily budget is spent.
+package sponsor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/heroiclabs/nakama-common/api"
+	"github.com/heroiclabs/nakama-common/runtime"
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/relay/nakama/persona"
+	"pkg.world.dev/world-engine/relay/nakama/signer"
+	"pkg.world.dev/world-engine/relay/nakama/utils"
+)
+
+const (
+	ActionClaimPersona     Action = "claim-persona"
+	ActionAuthorizeAddress Action = "authorize-address"
+
+	// maxWriteAttempts is the number of times a usage record is re-read and written again when another request
+	// updated it concurrently.
+	maxWriteAttempts = 3
+)
+
+var (
+	EnabledEnvVar      = "ENABLE_SPONSORSHIP"
+	MaxAddressesEnvVar = "SPONSOR_MAX_ADDRESSES_PER_USER"
+	DailyBudgetEnvVar  = "SPONSOR_DAILY_BUDGET"
+	MaxPerIPEnvVar     = "SPONSOR_MAX_PER_IP_PER_DAY"
+	Collection         = "sponsorship"
+
+	authorizeAddressEndpoint = "tx/persona/authorize-persona-address"
+	userUsageKey             = "usage"
+
+	ErrQuotaExceeded            = errors.New("sponsorship quota exceeded")
+	ErrBudgetExhausted          = errors.New("daily sponsorship budget exhausted")
+	ErrInvalidSignature         = errors.New("signature was not made by the address")
+	ErrAddressAlreadyAuthorized = errors.New("address is already authorized for this persona")
+	ErrPersonaNotClaimed        = errors.New("user does not have an accepted persona tag")
+)
+
+// Action is a kind of transaction that the relayer sponsors.
+type Action string
+
+// Limits are the quotas that protect the relayer from abuse. A zero value disables the limit.
+type Limits struct {
+	// MaxAddressesPerUser is the number of EVM addresses a single user can bind to their persona.
+	MaxAddressesPerUser int
+	// DailyBudget is the number of transactions the relayer sponsors per day (UTC), over all users.
+	DailyBudget int
+	// MaxPerIPPerDay is the number of transactions the relayer sponsors per day (UTC) for a single client IP.
+	MaxPerIPPerDay int
+}
+
+// DefaultLimits returns the limits that are used when no environment variable overrides them.
+func DefaultLimits() Limits {
+	return Limits{
+		MaxAddressesPerUser: 3,
+		MaxPerIPPerDay:      10,
+	}
+}
+
+// AuthorizeAddressRequest is the payload of a sponsored address binding. Signature must be an EIP-191 personal
+// signature of AuthorizeAddressMessage made by Address, which proves that the player controls the address.
+type AuthorizeAddressRequest struct {
+	Address   string `json:"address"`
+	Signature string `json:"signature"`
+}
+
+type TxResponse struct {
+	TxHash string `json:"txHash"`
+	Tick   uint64 `json:"tick"`
+}
+
+// userUsage is saved for every user that had a transaction sponsored.
+type userUsage struct {
+	Addresses []string `json:"addresses"`
+}
+
+// dayUsage counts the transactions that were sponsored on a single day.
+type dayUsage struct {
+	Total int            `json:"total"`
+	PerIP map[string]int `json:"perIp"`
+}
+
+// Sponsor signs and submits onboarding transactions to Cardinal on behalf of players.
+type Sponsor struct {
+	nk              runtime.NakamaModule
+	txSigner        signer.Signer
+	cardinalAddress string
+	namespace       string
+	limits          Limits
+	now             func() time.Time
+}
+
+func New(
+	nk runtime.NakamaModule,
+	txSigner signer.Signer,
+	cardinalAddress string,
+	namespace string,
+	limits Limits,
+) *Sponsor {
+	return &Sponsor{
+		nk:              nk,
+		txSigner:        txSigner,
+		cardinalAddress: cardinalAddress,
+		namespace:       namespace,
+		limits:          limits,
+		now:             time.Now,
+	}
+}
+
+// AuthorizeAddressMessage is the message a player signs to prove that they control the address they want to bind to
+// their persona tag.
+func AuthorizeAddressMessage(address, personaTag, namespace string) string {
+	return fmt.Sprintf("Authorize %s to act for persona %q in %s", strings.ToLower(address), personaTag, namespace)
+}
+
+// Reserve counts a sponsored transaction against the daily budget and the quota of the calling client's IP.
+// ErrBudgetExhausted or ErrQuotaExceeded is returned if the transaction must not be sponsored.
+func (s *Sponsor) Reserve(ctx context.Context, action Action) error {
+	clientIP, _ := ctx.Value(runtime.RUNTIME_CTX_CLIENT_IP).(string)
+	key := "day-" + s.now().UTC().Format(time.DateOnly)
+	return s.update(ctx, "", key, func(buf []byte) ([]byte, error) {
+		usage := dayUsage{PerIP: map[string]int{}}
+		if buf != nil {
+			if err := json.Unmarshal(buf, &usage); err != nil {
+				return nil, eris.Wrap(err, "failed to decode sponsorship usage")
+			}
+		}
+		if s.limits.DailyBudget > 0 && usage.Total >= s.limits.DailyBudget {
+			return nil, eris.Wrapf(ErrBudgetExhausted, "cannot sponsor %s", action)
+		}
+		if s.limits.MaxPerIPPerDay > 0 && clientIP != "" && usage.PerIP[clientIP] >= s.limits.MaxPerIPPerDay {
+			return nil, eris.Wrapf(ErrQuotaExceeded, "client IP has used %d sponsored transactions today",
+				usage.PerIP[clientIP])
+		}
+		usage.Total++
+		if clientIP != "" {
+			if usage.PerIP == nil {
+				usage.PerIP = map[string]int{}
+			}
+			usage.PerIP[clientIP]++
+		}
+		return json.Marshal(usage)
+	})
+}
+
+// AuthorizeAddress binds an EVM address to the calling user's persona tag, so that the player can act for their
+// persona from smart contracts.
+func (s *Sponsor) AuthorizeAddress(ctx context.Context, req AuthorizeAddressRequest) (*TxResponse, error) {
+	userID, err := utils.GetUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tag, err := persona.LoadPersonaTagStorageObj(ctx, s.nk)
+	if err != nil {
+		return nil, eris.Wrap(ErrPersonaNotClaimed, err.Error())
+	}
+	if tag.Status != persona.StatusAccepted {
+		return nil, eris.Wrap(ErrPersonaNotClaimed, "")
+	}
+	if err = verifyAddress(req, AuthorizeAddressMessage(req.Address, tag.PersonaTag, s.namespace)); err != nil {
+		return nil, err
+	}
+	address := strings.ToLower(req.Address)
+
+	// The address is recorded before the transaction is sent, so that concurrent requests cannot exceed the quota.
+	err = s.updateAddresses(ctx, userID, func(addresses []string) ([]string, error) {
+		if slices.Contains(addresses, address) {
+			return nil, eris.Wrapf(ErrAddressAlreadyAuthorized, "%s", address)
+		}
+		if s.limits.MaxAddressesPerUser > 0 && len(addresses) >= s.limits.MaxAddressesPerUser {
+			return nil, eris.Wrapf(ErrQuotaExceeded, "user has already bound %d addresses", len(addresses))
+		}
+		return append(addresses, address), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.Reserve(ctx, ActionAuthorizeAddress)
+	var res *TxResponse
+	if err == nil {
+		res, err = s.sendAuthorization(ctx, tag, address)
+	}
+	if err != nil {
+		// Release the address, so that the player can try again.
+		releaseErr := s.updateAddresses(ctx, userID, func(addresses []string) ([]string, error) {
+			return slices.DeleteFunc(addresses, func(a string) bool { return a == address }), nil
+		})
+		return nil, errors.Join(err, releaseErr)
+	}
+	return res, nil
+}
+
+func (s *Sponsor) sendAuthorization(ctx context.Context, tag *persona.StorageObj, address string) (
+	*TxResponse, error,
+) {
+	txSigner, err := tag.Signer(ctx, s.nk, s.txSigner)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := txSigner.SignTx(ctx, tag.PersonaTag, s.namespace, struct {
+		Address string `json:"address"`
+	}{Address: address})
+	if err != nil {
+		return nil, eris.Wrap(err, "unable to sign address authorization")
+	}
+	buf, err := tx.Marshal()
+	if err != nil {
+		return nil, eris.Wrap(err, "unable to marshal address authorization")
+	}
+	return s.post(ctx, authorizeAddressEndpoint, buf)
+}
+
+// updateAddresses applies fn to the addresses that the user has bound with sponsored transactions.
+func (s *Sponsor) updateAddresses(
+	ctx context.Context, userID string, fn func(addresses []string) ([]string, error),
+) error {
+	return s.update(ctx, userID, userUsageKey, func(buf []byte) ([]byte, error) {
+		var usage userUsage
+		if buf != nil {
+			if err := json.Unmarshal(buf, &usage); err != nil {
+				return nil, eris.Wrap(err, "failed to decode sponsorship usage")
+			}
+		}
+		addresses, err := fn(usage.Addresses)
+		if err != nil {
+			return nil, err
+		}
+		usage.Addresses = addresses
+		return json.Marshal(usage)
+	})
+}
+
+// verifyAddress checks that the signature of the request was made by the address of the request.
+func verifyAddress(req AuthorizeAddressRequest, message string) error {
+	if !common.IsHexAddress(req.Address) {
+		return eris.Errorf("%q is not a valid address", req.Address)
+	}
+	sig, err := hexutil.Decode(req.Signature)
+	if err != nil || len(sig) != crypto.SignatureLength {
+		return eris.Wrap(ErrInvalidSignature, "signature must be a hex encoded 65 byte signature")
+	}
+	// Wallets produce recovery IDs of 27 or 28.
+	if sig[crypto.RecoveryIDOffset] >= 27 {
+		sig[crypto.RecoveryIDOffset] -= 27
+	}
+	pubKey, err := crypto.SigToPub(accounts.TextHash([]byte(message)), sig)
+	if err != nil {
+		return eris.Wrap(ErrInvalidSignature, err.Error())
+	}
+	if crypto.PubkeyToAddress(*pubKey) != common.HexToAddress(req.Address) {
+		return eris.Wrap(ErrInvalidSignature, "")
+	}
+	return nil
+}
+
+// update applies fn to the value stored under the given key with an optimistic lock, retrying if the value was
+// changed concurrently. fn receives nil if there is no stored value yet.
+func (s *Sponsor) update(ctx context.Context, userID, key string, fn func([]byte) ([]byte, error)) error {
+	var err error
+	for attempt := 0; attempt < maxWriteAttempts; attempt++ {
+		var objs []*api.StorageObject
+		objs, err = s.nk.StorageRead(ctx, []*runtime.StorageRead{{Collection: Collection, Key: key, UserID: userID}})
+		if err != nil {
+			return eris.Wrap(err, "failed to read sponsorship usage")
+		}
+		var current []byte
+		version := "*"
+		if len(objs) > 0 {
+			current = []byte(objs[0].GetValue())
+			version = objs[0].GetVersion()
+		}
+		var next []byte
+		if next, err = fn(current); err != nil {
+			return err
+		}
+		_, err = s.nk.StorageWrite(ctx, []*runtime.StorageWrite{{
+			Collection:      Collection,
+			Key:             key,
+			UserID:          userID,
+			Value:           string(next),
+			Version:         version,
+			PermissionRead:  runtime.STORAGE_PERMISSION_NO_READ,
+			PermissionWrite: runtime.STORAGE_PERMISSION_NO_WRITE,
+		}})
+		if err == nil {
+			return nil
+		}
+	}
+	return eris.Wrap(err, "failed to save sponsorship usage")
+}
+
+func (s *Sponsor) post(ctx context.Context, endpoint string, body []byte) (*TxResponse, error) {
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodPost,
+		utils.MakeHTTPURL(endpoint, s.cardinalAddress),
+		bytes.NewReader(body),
+	)
+	if err != nil {
+		return nil, eris.Wrapf(err, "unable to make request to %q", endpoint)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	utils.SetCardinalAPIKey(req.Header)
+	resp, err := utils.DoRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, eris.Errorf("%s response is not 200. code %v", endpoint, resp.StatusCode)
+	}
+	var res TxResponse
+	if err = json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return nil, eris.Wrapf(err, "unable to decode response from %q", endpoint)
+	}
+	return &res, nil
+}
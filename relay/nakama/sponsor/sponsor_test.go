@@ -0,0 +1,59 @@
+package sponsor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/heroiclabs/nakama-common/runtime"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/relay/nakama/testutils"
+)
+
+func ctxWithClientIP(ip string) context.Context {
+	//nolint:staticcheck // this is how Nakama reads client IPs from the context.
+	return context.WithValue(context.Background(), runtime.RUNTIME_CTX_CLIENT_IP, ip)
+}
+
+func TestReserveEnforcesDailyBudgetAndPerIPQuota(t *testing.T) {
+	s := New(testutils.NewFakeNakamaModule(), nil, "", "", Limits{DailyBudget: 3, MaxPerIPPerDay: 2})
+	day := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return day }
+
+	alice, bob := ctxWithClientIP("1.1.1.1"), ctxWithClientIP("2.2.2.2")
+	assert.NilError(t, s.Reserve(alice, ActionClaimPersona))
+	assert.NilError(t, s.Reserve(alice, ActionAuthorizeAddress))
+	assert.ErrorIs(t, s.Reserve(alice, ActionAuthorizeAddress), ErrQuotaExceeded)
+
+	assert.NilError(t, s.Reserve(bob, ActionClaimPersona))
+	assert.ErrorIs(t, s.Reserve(bob, ActionClaimPersona), ErrBudgetExhausted)
+
+	// The quotas are reset every day.
+	s.now = func() time.Time { return day.Add(24 * time.Hour) }
+	assert.NilError(t, s.Reserve(alice, ActionClaimPersona))
+}
+
+func TestVerifyAddress(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	assert.NilError(t, err)
+	address := crypto.PubkeyToAddress(key.PublicKey).Hex()
+	message := AuthorizeAddressMessage(address, "hero", "my-world")
+	sig, err := crypto.Sign(accounts.TextHash([]byte(message)), key)
+	assert.NilError(t, err)
+	// Wallets return signatures with a recovery ID of 27 or 28.
+	sig[crypto.RecoveryIDOffset] += 27
+
+	req := AuthorizeAddressRequest{Address: address, Signature: hexutil.Encode(sig)}
+	assert.NilError(t, verifyAddress(req, message))
+
+	// The signature must be made for the same persona tag and by the same address.
+	assert.ErrorIs(t, verifyAddress(req, AuthorizeAddressMessage(address, "villain", "my-world")), ErrInvalidSignature)
+	other, err := crypto.GenerateKey()
+	assert.NilError(t, err)
+	req.Address = crypto.PubkeyToAddress(other.PublicKey).Hex()
+	assert.ErrorIs(t, verifyAddress(req, AuthorizeAddressMessage(req.Address, "hero", "my-world")), ErrInvalidSignature)
+}
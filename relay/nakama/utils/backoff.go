@@ -0,0 +1,37 @@
+package utils
+
+import (
+	"math/rand"
+	"time"
+)
+
+// Backoff computes the delays between the attempts of an operation that is retried until it succeeds, e.g.
+// reconnecting to Cardinal. The delay doubles after every failed attempt, from Min up to Max, and is randomized by up
+// to half of its length so that many clients don't retry at the same time.
+type Backoff struct {
+	Min time.Duration
+	Max time.Duration
+
+	attempts int
+}
+
+// Next returns how long to wait before the next attempt.
+func (b *Backoff) Next() time.Duration {
+	delay := b.Min
+	for i := 0; i < b.attempts && delay < b.Max; i++ {
+		delay *= 2
+	}
+	if delay > b.Max {
+		delay = b.Max
+	}
+	b.attempts++
+	if half := int64(delay / 2); half > 0 {
+		delay -= time.Duration(rand.Int63n(half)) //nolint:gosec // the jitter doesn't need to be secure
+	}
+	return delay
+}
+
+// Reset starts over from Min, after the operation succeeded.
+func (b *Backoff) Reset() {
+	b.attempts = 0
+}
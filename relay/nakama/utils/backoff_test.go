@@ -0,0 +1,21 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"pkg.world.dev/world-engine/assert"
+)
+
+func TestBackoffDoublesUpToMax(t *testing.T) {
+	b := Backoff{Min: time.Second, Max: 8 * time.Second}
+	for _, want := range []time.Duration{1, 2, 4, 8, 8} {
+		want *= time.Second
+		delay := b.Next()
+		assert.Check(t, delay > want/2 && delay <= want, "got %s, want at most %s", delay, want)
+	}
+
+	b.Reset()
+	delay := b.Next()
+	assert.Check(t, delay <= time.Second, "got %s after reset", delay)
+}
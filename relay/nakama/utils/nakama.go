@@ -22,6 +22,13 @@ func GetUserID(ctx context.Context) (string, error) {
 	return userID, nil
 }
 
+// GetSessionID gets the ID of the Nakama session from the given context. It is empty if the request is not made in a
+// session, e.g. an RPC that is called with the HTTP key.
+func GetSessionID(ctx context.Context) string {
+	sessionID, _ := ctx.Value(runtime.RUNTIME_CTX_SESSION_ID).(string)
+	return sessionID
+}
+
 // MarshalResult marshals the given result and converts any marshalling error into a "Internal" RPC error.
 func MarshalResult(logger runtime.Logger, result any) (string, error) {
 	bz, err := json.Marshal(result)
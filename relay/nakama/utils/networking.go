@@ -34,6 +34,21 @@ func DoRequest(req *http.Request) (*http.Response, error) {
 	return resp, nil
 }
 
+// CardinalAPIKey is sent to Cardinal with every request if it is set, so that Nakama is authenticated when Cardinal
+// requires API keys. It is read from CARDINAL_API_KEY.
+var CardinalAPIKey string
+
+// cardinalAPIKeyHeader is the header of Cardinal's API keys, see server.APIKeyHeader in Cardinal.
+const cardinalAPIKeyHeader = "Cardinal-API-Key"
+
+// SetCardinalAPIKey sets the API key of Nakama in the headers of a request to Cardinal. Requests to other services
+// must not carry it.
+func SetCardinalAPIKey(header http.Header) {
+	if CardinalAPIKey != "" {
+		header.Set(cardinalAPIKeyHeader, CardinalAPIKey)
+	}
+}
+
 func MakeHTTPURL(resource string, url string) string {
 	return fmt.Sprintf("http://%s/%s", url, resource)
 }
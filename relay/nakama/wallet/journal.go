@@ -0,0 +1,137 @@
+package wallet
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/heroiclabs/nakama-common/runtime"
+	"github.com/rotisserie/eris"
+)
+
+const journalListLimit = 100
+
+// createJournalEntry saves a new journal entry to Nakama's storage layer. ErrJournalEntryExists is returned if an
+// entry with the same ID has already been saved for this user. The version of the saved object is returned so the
+// entry can later be updated with an optimistic lock.
+func (b *Bridge) createJournalEntry(ctx context.Context, entry *JournalEntry) (string, error) {
+	if entry.ID == "" {
+		return "", eris.New("journal entry must have an ID")
+	}
+	existing, _, err := b.loadJournalEntry(ctx, entry.UserID, entry.ID)
+	if err != nil {
+		return "", err
+	}
+	if existing != nil {
+		return "", eris.Wrapf(ErrJournalEntryExists, "journal id %q", entry.ID)
+	}
+	return b.writeJournalEntry(ctx, entry, versionWriteIfDoesNotExist)
+}
+
+// saveJournalEntry updates an existing journal entry.
+func (b *Bridge) saveJournalEntry(ctx context.Context, entry *JournalEntry, version string) error {
+	_, err := b.writeJournalEntry(ctx, entry, version)
+	return err
+}
+
+// failJournalEntry marks the journal entry as failed and returns the original cause.
+func (b *Bridge) failJournalEntry(ctx context.Context, entry *JournalEntry, version string, cause error) error {
+	entry.Status = StatusFailed
+	entry.Error = cause.Error()
+	if err := b.saveJournalEntry(ctx, entry, version); err != nil {
+		return eris.Wrap(cause, err.Error())
+	}
+	return cause
+}
+
+func (b *Bridge) writeJournalEntry(ctx context.Context, entry *JournalEntry, version string) (string, error) {
+	entry.UpdatedAt = time.Now().Unix()
+	buf, err := json.Marshal(entry)
+	if err != nil {
+		return "", eris.Wrap(err, "")
+	}
+	acks, err := b.nk.StorageWrite(ctx, []*runtime.StorageWrite{
+		{
+			Collection:      JournalCollection,
+			Key:             entry.ID,
+			UserID:          entry.UserID,
+			Value:           string(buf),
+			Version:         version,
+			PermissionRead:  runtime.STORAGE_PERMISSION_OWNER_READ,
+			PermissionWrite: runtime.STORAGE_PERMISSION_NO_WRITE,
+		},
+	})
+	if err != nil {
+		return "", eris.Wrapf(err, "failed to write journal entry %q", entry.ID)
+	}
+	if len(acks) == 0 {
+		return "", nil
+	}
+	return acks[0].GetVersion(), nil
+}
+
+func (b *Bridge) loadJournalEntry(ctx context.Context, userID, journalID string) (*JournalEntry, string, error) {
+	objs, err := b.nk.StorageRead(ctx, []*runtime.StorageRead{
+		{
+			Collection: JournalCollection,
+			Key:        journalID,
+			UserID:     userID,
+		},
+	})
+	if err != nil {
+		return nil, "", eris.Wrap(err, "")
+	}
+	if len(objs) == 0 {
+		return nil, "", nil
+	}
+	entry := &JournalEntry{}
+	if err = json.Unmarshal([]byte(objs[0].GetValue()), entry); err != nil {
+		return nil, "", eris.Wrap(err, "")
+	}
+	return entry, objs[0].GetVersion(), nil
+}
+
+type journalObject struct {
+	entry   *JournalEntry
+	version string
+}
+
+// unsettledJournalEntries returns all of the user's journal entries that are neither committed nor rejected.
+func (b *Bridge) unsettledJournalEntries(ctx context.Context, userID string) ([]JournalEntry, error) {
+	objs, err := b.listUnsettled(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	unsettled := make([]JournalEntry, 0, len(objs))
+	for _, obj := range objs {
+		unsettled = append(unsettled, *obj.entry)
+	}
+	return unsettled, nil
+}
+
+// listUnsettled returns the journal entries of the user that are neither committed nor rejected, along with their
+// versions. The entries of all users are returned if userID is empty.
+func (b *Bridge) listUnsettled(ctx context.Context, userID string) ([]journalObject, error) {
+	unsettled := []journalObject{}
+	cursor := ""
+	for {
+		objs, nextCursor, err := b.nk.StorageList(ctx, "", userID, JournalCollection, journalListLimit, cursor)
+		if err != nil {
+			return nil, eris.Wrap(err, "")
+		}
+		for _, obj := range objs {
+			entry := &JournalEntry{}
+			if err = json.Unmarshal([]byte(obj.GetValue()), entry); err != nil {
+				return nil, eris.Wrap(err, "")
+			}
+			if entry.Status != StatusCommitted && entry.Status != StatusRejected {
+				unsettled = append(unsettled, journalObject{entry: entry, version: obj.GetVersion()})
+			}
+		}
+		if nextCursor == "" {
+			break
+		}
+		cursor = nextCursor
+	}
+	return unsettled, nil
+}
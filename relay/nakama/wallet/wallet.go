@@ -0,0 +1,546 @@
+package wallet
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/heroiclabs/nakama-common/api"
+	"github.com/heroiclabs/nakama-common/runtime"
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/relay/nakama/events"
+	"pkg.world.dev/world-engine/relay/nakama/signer"
+	"pkg.world.dev/world-engine/relay/nakama/utils"
+)
+
+const (
+	// See: https://heroiclabs.com/docs/nakama/concepts/storage/collections/#conditional-writes
+	// Setting the "version" of a storage write to this value means the value will only be written if it doesn't already
+	// exist in the storage layer. This is what makes journal entries idempotent.
+	versionWriteIfDoesNotExist = "*"
+
+	StatusPending   journalStatus = "pending"
+	StatusCommitted journalStatus = "committed"
+	StatusFailed    journalStatus = "failed"
+	// StatusRejected is used for credits that Cardinal refused to apply (e.g. the persona doesn't exist). They were
+	// applied to neither side and are not retried.
+	StatusRejected journalStatus = "rejected"
+
+	// DirectionCredit is used for currency that enters the game through Nakama (e.g. a validated in-app purchase)
+	// and must be mirrored in Cardinal.
+	DirectionCredit journalDirection = "credit"
+	// DirectionSpend is used for currency that was spent inside Cardinal and must be mirrored in the Nakama wallet.
+	DirectionSpend journalDirection = "spend"
+	// DirectionEarn is used for currency that was earned inside Cardinal (e.g. a reward or a trade) and must be
+	// mirrored in the Nakama wallet.
+	DirectionEarn journalDirection = "earn"
+)
+
+var (
+	EnabledEnvVar     = "ENABLE_WALLET_BRIDGE"
+	CurrencyEnvVar    = "WALLET_CURRENCY"
+	ProductsEnvVar    = "WALLET_PRODUCTS"
+	JournalCollection = "wallet_journal"
+	CreditEndpoint    = "tx/wallet/credit"
+	BalanceEndpoint   = "query/wallet/balance"
+	SpendEventType    = "wallet-spend"
+	EarnEventType     = "wallet-earn"
+
+	// ReceiptTimeout is how long a credit waits for Cardinal to apply it before it is left for Redrive.
+	ReceiptTimeout = 10 * time.Second
+	// RedriveInterval is how often unsettled journal entries are retried.
+	RedriveInterval = time.Minute
+	// RedriveMinAge is how long a journal entry must go without updates before Redrive retries it, so that entries
+	// that are still being settled are not applied twice.
+	RedriveMinAge = time.Minute
+
+	ErrJournalEntryExists = errors.New("journal entry already exists")
+	ErrUnknownPersonaTag  = errors.New("persona tag is not assigned to any user")
+	ErrInvalidAmount      = errors.New("wallet amount must be positive")
+	ErrCreditRejected     = errors.New("cardinal rejected the wallet credit")
+	ErrBalanceMismatch    = errors.New("nakama and cardinal wallet balances don't match")
+)
+
+type journalStatus string
+
+type journalDirection string
+
+// JournalEntry records a single currency movement between the Nakama wallet and Cardinal. An entry is written
+// before the change is applied to either side so that a crash part way through leaves a pending (or failed) entry
+// behind, which Redrive settles later and which shows up in reconciliation reports until then.
+type JournalEntry struct {
+	ID         string           `json:"id"`
+	UserID     string           `json:"userId"`
+	PersonaTag string           `json:"personaTag"`
+	Currency   string           `json:"currency"`
+	Amount     int64            `json:"amount"`
+	Direction  journalDirection `json:"direction"`
+	Status     journalStatus    `json:"status"`
+	TxHash     string           `json:"txHash,omitempty"`
+	Tick       uint64           `json:"tick,omitempty"`
+	Error      string           `json:"error,omitempty"`
+	Attempts   int              `json:"attempts"`
+	CreatedAt  int64            `json:"createdAt"`
+	UpdatedAt  int64            `json:"updatedAt"`
+}
+
+// Event is the event that Cardinal's wallet plugin emits when soft currency is spent (SpendEventType) or earned
+// (EarnEventType) in-game.
+type Event struct {
+	Event      string `json:"event"`
+	JournalID  string `json:"journalId"`
+	PersonaTag string `json:"personaTag"`
+	Currency   string `json:"currency"`
+	Amount     int64  `json:"amount"`
+}
+
+// CreditMsg is the admin transaction that is sent to the CreditEndpoint when Nakama credits a wallet. Cardinal ignores
+// credits with a journal ID it has already applied, so the same credit can safely be sent more than once.
+type CreditMsg struct {
+	JournalID  string `json:"journalId"`
+	PersonaTag string `json:"personaTag"`
+	Currency   string `json:"currency"`
+	Amount     int64  `json:"amount"`
+}
+
+type balanceRequest struct {
+	PersonaTag string `json:"personaTag"`
+	Currency   string `json:"currency"`
+}
+
+type balanceResponse struct {
+	Balance int64 `json:"balance"`
+}
+
+type txResponse struct {
+	TxHash string `json:"txHash"`
+	Tick   uint64 `json:"tick"`
+}
+
+// ReconciliationReport compares a user's Nakama wallet with the balance that Cardinal reports for the same persona.
+type ReconciliationReport struct {
+	UserID          string         `json:"userId"`
+	PersonaTag      string         `json:"personaTag"`
+	Currency        string         `json:"currency"`
+	NakamaBalance   int64          `json:"nakamaBalance"`
+	CardinalBalance int64          `json:"cardinalBalance"`
+	Difference      int64          `json:"difference"`
+	Mismatch        bool           `json:"mismatch"`
+	Unsettled       []JournalEntry `json:"unsettled"`
+}
+
+// Bridge keeps a single soft currency in sync between Nakama's wallet and a Cardinal game shard.
+type Bridge struct {
+	nk                      runtime.NakamaModule
+	txSigner                signer.Signer
+	cardinalAddress         string
+	namespace               string
+	currency                string
+	products                map[string]int64
+	globalPersonaAssignment *sync.Map
+}
+
+func NewBridge(
+	nk runtime.NakamaModule,
+	txSigner signer.Signer,
+	cardinalAddress string,
+	namespace string,
+	currency string,
+	products map[string]int64,
+	globalPersonaAssignment *sync.Map,
+) *Bridge {
+	return &Bridge{
+		nk:                      nk,
+		txSigner:                txSigner,
+		cardinalAddress:         cardinalAddress,
+		namespace:               namespace,
+		currency:                currency,
+		products:                products,
+		globalPersonaAssignment: globalPersonaAssignment,
+	}
+}
+
+// Currency returns the name of the wallet currency that this bridge keeps in sync.
+func (b *Bridge) Currency() string {
+	return b.currency
+}
+
+// ProductAmount returns the amount of currency that should be credited for the given in-app purchase product.
+func (b *Bridge) ProductAmount(productID string) (int64, bool) {
+	amount, ok := b.products[productID]
+	return amount, ok
+}
+
+// Credit submits a credit transaction to Cardinal and, once Cardinal's receipt shows that the credit was applied, adds
+// the same amount to the user's Nakama wallet. The journalID must be unique for each credit (e.g. the store's
+// transaction ID); crediting the same journalID twice returns ErrJournalEntryExists and has no effect. If the credit
+// can't be settled (e.g. Cardinal is unreachable), the error is returned and the journal entry is left for Redrive.
+func (b *Bridge) Credit(
+	ctx context.Context,
+	userID, personaTag, journalID string,
+	amount int64,
+) (*JournalEntry, error) {
+	if amount <= 0 {
+		return nil, eris.Wrapf(ErrInvalidAmount, "got %d", amount)
+	}
+	entry := &JournalEntry{
+		ID:         journalID,
+		UserID:     userID,
+		PersonaTag: personaTag,
+		Currency:   b.currency,
+		Amount:     amount,
+		Direction:  DirectionCredit,
+		Status:     StatusPending,
+		CreatedAt:  time.Now().Unix(),
+	}
+	version, err := b.createJournalEntry(ctx, entry)
+	if err != nil {
+		return nil, err
+	}
+	return entry, b.settle(ctx, entry, version)
+}
+
+// ApplyEvent mirrors an in-game spend or earning in the Nakama wallet of the user that owns the event's persona tag.
+// Events are deduplicated by their journal ID, so receiving the same event more than once is harmless.
+func (b *Bridge) ApplyEvent(ctx context.Context, event Event) (*JournalEntry, error) {
+	if event.Amount <= 0 {
+		return nil, eris.Wrapf(ErrInvalidAmount, "got %d", event.Amount)
+	}
+	userID, ok := b.lookupUserID(event.PersonaTag)
+	if !ok {
+		return nil, eris.Wrapf(ErrUnknownPersonaTag, "persona tag %q", event.PersonaTag)
+	}
+	entry := &JournalEntry{
+		ID:         event.JournalID,
+		UserID:     userID,
+		PersonaTag: event.PersonaTag,
+		Currency:   event.Currency,
+		Amount:     event.Amount,
+		Direction:  DirectionSpend,
+		Status:     StatusPending,
+		CreatedAt:  time.Now().Unix(),
+	}
+	if event.Event == EarnEventType {
+		entry.Direction = DirectionEarn
+	}
+	version, err := b.createJournalEntry(ctx, entry)
+	if err != nil {
+		return nil, err
+	}
+	return entry, b.settle(ctx, entry, version)
+}
+
+// Redrive settles every pending or failed journal entry that has not been updated for RedriveMinAge, and returns the
+// number of entries that were settled.
+func (b *Bridge) Redrive(ctx context.Context, logger runtime.Logger) (int, error) {
+	unsettled, err := b.listUnsettled(ctx, "")
+	if err != nil {
+		return 0, err
+	}
+	return b.redrive(ctx, logger, unsettled), nil
+}
+
+// RunRedrive calls Redrive every interval until ctx is done. It is meant to be called in a goroutine.
+func (b *Bridge) RunRedrive(ctx context.Context, logger runtime.Logger, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			settled, err := b.Redrive(ctx, logger)
+			if err != nil {
+				logger.Error("failed to redrive wallet journal: %s", eris.ToString(err, true))
+			} else if settled > 0 {
+				logger.Info("settled %d wallet journal entries", settled)
+			}
+		}
+	}
+}
+
+func (b *Bridge) redrive(ctx context.Context, logger runtime.Logger, unsettled []journalObject) int {
+	settled := 0
+	for _, obj := range unsettled {
+		if time.Since(time.Unix(obj.entry.UpdatedAt, 0)) < RedriveMinAge {
+			continue
+		}
+		if err := b.settle(ctx, obj.entry, obj.version); err != nil {
+			logger.Warn("failed to settle wallet journal entry %q: %s", obj.entry.ID, eris.ToString(err, true))
+			continue
+		}
+		settled++
+	}
+	return settled
+}
+
+// settle applies a pending or failed journal entry to both sides and marks it as committed. A credit is applied to
+// the Nakama wallet only after Cardinal has applied it, so a credit that never reaches Cardinal doesn't show up in
+// the Nakama wallet. Both steps can be repeated: Cardinal ignores credits with a journal ID it has already applied,
+// and updateNakamaWallet checks the wallet ledger before retrying.
+func (b *Bridge) settle(ctx context.Context, entry *JournalEntry, version string) error {
+	entry.Attempts++
+	if entry.Direction == DirectionCredit {
+		if err := b.creditCardinal(ctx, entry); errors.Is(err, ErrCreditRejected) {
+			entry.Status = StatusRejected
+			entry.Error = err.Error()
+			if saveErr := b.saveJournalEntry(ctx, entry, version); saveErr != nil {
+				return eris.Wrap(err, saveErr.Error())
+			}
+			return err
+		} else if err != nil {
+			return b.failJournalEntry(ctx, entry, version, err)
+		}
+	}
+	if err := b.updateNakamaWallet(ctx, entry); err != nil {
+		return b.failJournalEntry(ctx, entry, version, eris.Wrap(err, "failed to update nakama wallet"))
+	}
+	entry.Status = StatusCommitted
+	entry.Error = ""
+	return b.saveJournalEntry(ctx, entry, version)
+}
+
+// creditCardinal submits the credit to Cardinal and waits for its receipt. ErrCreditRejected is returned if the
+// receipt has errors.
+func (b *Bridge) creditCardinal(ctx context.Context, entry *JournalEntry) error {
+	res, err := b.submitCredit(ctx, entry)
+	if err != nil {
+		return err
+	}
+	entry.TxHash, entry.Tick = res.TxHash, res.Tick
+	waitCtx, cancel := context.WithTimeout(ctx, ReceiptTimeout)
+	defer cancel()
+	receipt, err := events.WaitForReceipt(waitCtx, b.cardinalAddress, res.TxHash, res.Tick)
+	if err != nil {
+		return err
+	}
+	if len(receipt.Errors) > 0 {
+		return eris.Wrap(ErrCreditRejected, strings.Join(receipt.Errors, "; "))
+	}
+	return nil
+}
+
+// updateNakamaWallet applies the journal entry to the user's Nakama wallet. If an earlier attempt was made, the entry
+// is only applied if the wallet ledger doesn't have it yet.
+func (b *Bridge) updateNakamaWallet(ctx context.Context, entry *JournalEntry) error {
+	if entry.Attempts > 1 {
+		applied, err := b.inWalletLedger(ctx, entry)
+		if err != nil || applied {
+			return err
+		}
+	}
+	amount := entry.Amount
+	if entry.Direction == DirectionSpend {
+		amount = -amount
+	}
+	_, _, err := b.nk.WalletUpdate(ctx, entry.UserID, map[string]int64{entry.Currency: amount}, entry.metadata(), true)
+	return err
+}
+
+func (b *Bridge) inWalletLedger(ctx context.Context, entry *JournalEntry) (bool, error) {
+	cursor := ""
+	for {
+		items, nextCursor, err := b.nk.WalletLedgerList(ctx, entry.UserID, journalListLimit, cursor)
+		if err != nil {
+			return false, eris.Wrap(err, "failed to list nakama wallet ledger")
+		}
+		for _, item := range items {
+			if item.GetMetadata()["journalId"] == entry.ID {
+				return true, nil
+			}
+		}
+		if nextCursor == "" {
+			return false, nil
+		}
+		cursor = nextCursor
+	}
+}
+
+// ConsumeEvents applies every wallet Event found on the given channel. Other events are ignored. This function blocks
+// until the channel is closed, so it is meant to be called in a goroutine.
+func (b *Bridge) ConsumeEvents(ctx context.Context, logger runtime.Logger, ch <-chan []byte) {
+	for bz := range ch {
+		var event Event
+		if err := json.Unmarshal(bz, &event); err != nil ||
+			(event.Event != SpendEventType && event.Event != EarnEventType) {
+			continue
+		}
+		if event.Currency == "" {
+			event.Currency = b.currency
+		}
+		if _, err := b.ApplyEvent(ctx, event); err != nil {
+			if errors.Is(err, ErrJournalEntryExists) {
+				continue
+			}
+			logger.Error("failed to apply wallet event %q: %s", event.JournalID, eris.ToString(err, true))
+		}
+	}
+}
+
+// Reconcile settles the user's unsettled journal entries, then compares the user's Nakama wallet balance with the
+// balance reported by Cardinal and collects the journal entries that are still unsettled. If the balances don't
+// match, the mismatch is logged as an error and the report is returned along with ErrBalanceMismatch.
+func (b *Bridge) Reconcile(
+	ctx context.Context,
+	logger runtime.Logger,
+	userID string,
+	personaTag string,
+) (*ReconciliationReport, error) {
+	unsettled, err := b.listUnsettled(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	b.redrive(ctx, logger, unsettled)
+
+	nakamaBalance, err := b.nakamaBalance(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	cardinalBalance, err := b.cardinalBalance(ctx, personaTag)
+	if err != nil {
+		return nil, err
+	}
+	stillUnsettled, err := b.unsettledJournalEntries(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	report := &ReconciliationReport{
+		UserID:          userID,
+		PersonaTag:      personaTag,
+		Currency:        b.currency,
+		NakamaBalance:   nakamaBalance,
+		CardinalBalance: cardinalBalance,
+		Difference:      nakamaBalance - cardinalBalance,
+		Mismatch:        nakamaBalance != cardinalBalance,
+		Unsettled:       stillUnsettled,
+	}
+	if report.Mismatch {
+		err = eris.Wrapf(ErrBalanceMismatch, "user %q (persona %q): nakama has %d %s, cardinal has %d, %d unsettled",
+			userID, personaTag, nakamaBalance, b.currency, cardinalBalance, len(stillUnsettled))
+		logger.Error("wallet reconciliation failed: %s", err.Error())
+		return report, err
+	}
+	return report, nil
+}
+
+func (b *Bridge) lookupUserID(personaTag string) (string, bool) {
+	val, ok := b.globalPersonaAssignment.Load(personaTag)
+	if !ok {
+		return "", false
+	}
+	userID, ok := val.(string)
+	return userID, ok
+}
+
+func (b *Bridge) submitCredit(ctx context.Context, entry *JournalEntry) (*txResponse, error) {
+	tx, err := b.txSigner.SignSystemTx(ctx, b.namespace, CreditMsg{
+		JournalID:  entry.ID,
+		PersonaTag: entry.PersonaTag,
+		Currency:   entry.Currency,
+		Amount:     entry.Amount,
+	})
+	if err != nil {
+		return nil, eris.Wrap(err, "unable to sign wallet credit")
+	}
+	buf, err := tx.Marshal()
+	if err != nil {
+		return nil, eris.Wrap(err, "unable to marshal wallet credit")
+	}
+	var res txResponse
+	if err = b.post(ctx, CreditEndpoint, buf, &res); err != nil {
+		return nil, err
+	}
+	if res.TxHash == "" {
+		return nil, eris.New("wallet credit response does not have a tx hash")
+	}
+	return &res, nil
+}
+
+func (b *Bridge) cardinalBalance(ctx context.Context, personaTag string) (int64, error) {
+	buf, err := json.Marshal(balanceRequest{PersonaTag: personaTag, Currency: b.currency})
+	if err != nil {
+		return 0, eris.Wrap(err, "")
+	}
+	var res balanceResponse
+	if err = b.post(ctx, BalanceEndpoint, buf, &res); err != nil {
+		return 0, err
+	}
+	return res.Balance, nil
+}
+
+func (b *Bridge) nakamaBalance(ctx context.Context, userID string) (int64, error) {
+	account, err := b.nk.AccountGetId(ctx, userID)
+	if err != nil {
+		return 0, eris.Wrap(err, "failed to get nakama account")
+	}
+	if account.GetWallet() == "" {
+		return 0, nil
+	}
+	wallet := map[string]int64{}
+	if err = json.Unmarshal([]byte(account.GetWallet()), &wallet); err != nil {
+		return 0, eris.Wrap(err, "failed to decode nakama wallet")
+	}
+	return wallet[b.currency], nil
+}
+
+func (b *Bridge) post(ctx context.Context, endpoint string, body []byte, result any) error {
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodPost,
+		utils.MakeHTTPURL(endpoint, b.cardinalAddress),
+		bytes.NewReader(body),
+	)
+	if err != nil {
+		return eris.Wrapf(err, "unable to make request to %q", endpoint)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	utils.SetCardinalAPIKey(req.Header)
+	resp, err := utils.DoRequest(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return eris.Wrapf(json.NewDecoder(resp.Body).Decode(result), "unable to decode response from %q", endpoint)
+}
+
+func (e *JournalEntry) metadata() map[string]any {
+	return map[string]any{
+		"journalId":  e.ID,
+		"personaTag": e.PersonaTag,
+		"direction":  e.Direction,
+	}
+}
+
+// CreditValidatedPurchases credits the wallet for every newly validated purchase of a known product. The store's
+// transaction ID is used as the journal ID, so replayed validations do not credit the wallet twice.
+func (b *Bridge) CreditValidatedPurchases(
+	ctx context.Context,
+	userID string,
+	personaTag string,
+	purchases []*api.ValidatedPurchase,
+) ([]*JournalEntry, error) {
+	entries := make([]*JournalEntry, 0, len(purchases))
+	for _, purchase := range purchases {
+		if purchase.GetSeenBefore() {
+			continue
+		}
+		amount, ok := b.ProductAmount(purchase.GetProductId())
+		if !ok {
+			continue
+		}
+		entry, err := b.Credit(ctx, userID, personaTag, "iap:"+purchase.GetTransactionId(), amount)
+		if errors.Is(err, ErrJournalEntryExists) {
+			continue
+		} else if err != nil {
+			return entries, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
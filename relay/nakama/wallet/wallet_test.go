@@ -0,0 +1,252 @@
+package wallet
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/heroiclabs/nakama-common/runtime"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/relay/nakama/events"
+	"pkg.world.dev/world-engine/relay/nakama/testutils"
+	"pkg.world.dev/world-engine/sign"
+)
+
+// fakeWalletModule extends the fake nakama module with an in-memory wallet.
+type fakeWalletModule struct {
+	*testutils.FakeNakamaModule
+	mu      sync.Mutex
+	wallets map[string]map[string]int64
+	ledger  map[string][]runtime.WalletLedgerItem
+	updates int
+}
+
+type fakeLedgerItem struct {
+	runtime.WalletLedgerItem
+	metadata map[string]any
+}
+
+func (i fakeLedgerItem) GetMetadata() map[string]any {
+	return i.metadata
+}
+
+func newFakeWalletModule() *fakeWalletModule {
+	return &fakeWalletModule{
+		FakeNakamaModule: testutils.NewFakeNakamaModule(),
+		wallets:          map[string]map[string]int64{},
+		ledger:           map[string][]runtime.WalletLedgerItem{},
+	}
+}
+
+func (f *fakeWalletModule) WalletUpdate(
+	_ context.Context,
+	userID string,
+	changeset map[string]int64,
+	metadata map[string]any,
+	_ bool,
+) (map[string]int64, map[string]int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.updates++
+	f.ledger[userID] = append(f.ledger[userID], fakeLedgerItem{metadata: metadata})
+	if f.wallets[userID] == nil {
+		f.wallets[userID] = map[string]int64{}
+	}
+	previous := map[string]int64{}
+	for currency, amount := range changeset {
+		previous[currency] = f.wallets[userID][currency]
+		f.wallets[userID][currency] += amount
+	}
+	return f.wallets[userID], previous, nil
+}
+
+func (f *fakeWalletModule) WalletLedgerList(
+	_ context.Context,
+	userID string,
+	_ int,
+	_ string,
+) ([]runtime.WalletLedgerItem, string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.ledger[userID], "", nil
+}
+
+type fakeSigner struct{}
+
+func (fakeSigner) SignTx(_ context.Context, personaTag, namespace string, data any) (*sign.Transaction, error) {
+	body, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	return &sign.Transaction{PersonaTag: personaTag, Namespace: namespace, Body: body}, nil
+}
+
+func (s fakeSigner) SignSystemTx(ctx context.Context, namespace string, data any) (*sign.Transaction, error) {
+	return s.SignTx(ctx, sign.SystemPersonaTag, namespace, data)
+}
+
+func (fakeSigner) SignerAddress() string {
+	return ""
+}
+
+// fakeCardinal answers credit transactions with a receipt that has the given errors.
+type fakeCardinal struct {
+	mu            sync.Mutex
+	credits       []CreditMsg
+	receiptErrors []string
+}
+
+func (c *fakeCardinal) numCredits() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.credits)
+}
+
+// newFakeCardinal starts a fakeCardinal server and returns its address.
+func newFakeCardinal(t *testing.T, receiptErrors ...string) (string, *fakeCardinal) {
+	cardinal := &fakeCardinal{receiptErrors: receiptErrors}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/"+CreditEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		var tx sign.Transaction
+		assert.NilError(t, json.NewDecoder(r.Body).Decode(&tx))
+		var credit CreditMsg
+		assert.NilError(t, json.Unmarshal(tx.Body, &credit))
+		cardinal.mu.Lock()
+		cardinal.credits = append(cardinal.credits, credit)
+		cardinal.mu.Unlock()
+		assert.NilError(t, json.NewEncoder(w).Encode(txResponse{TxHash: "0x" + credit.JournalID, Tick: 7}))
+	})
+	mux.HandleFunc("/query/receipts/list", func(w http.ResponseWriter, _ *http.Request) {
+		cardinal.mu.Lock()
+		defer cardinal.mu.Unlock()
+		reply := events.TransactionReceiptsReply{StartTick: 7, EndTick: 8}
+		for _, credit := range cardinal.credits {
+			reply.Receipts = append(reply.Receipts, &events.Receipt{
+				TxHash: "0x" + credit.JournalID,
+				Tick:   7,
+				Errors: cardinal.receiptErrors,
+			})
+		}
+		assert.NilError(t, json.NewEncoder(w).Encode(reply))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return strings.TrimPrefix(server.URL, "http://"), cardinal
+}
+
+func TestApplyEventIsIdempotent(t *testing.T) {
+	nk := newFakeWalletModule()
+	assignments := &sync.Map{}
+	assignments.Store("hero", "user-1")
+	bridge := NewBridge(nk, nil, "", "", "gold", nil, assignments)
+	ctx := context.Background()
+
+	event := Event{
+		Event:      SpendEventType,
+		JournalID:  "spend-1",
+		PersonaTag: "hero",
+		Currency:   "gold",
+		Amount:     25,
+	}
+	entry, err := bridge.ApplyEvent(ctx, event)
+	assert.NilError(t, err)
+	assert.Equal(t, StatusCommitted, entry.Status)
+	assert.Equal(t, int64(-25), nk.wallets["user-1"]["gold"])
+
+	// Replaying the same event must not touch the wallet again.
+	_, err = bridge.ApplyEvent(ctx, event)
+	assert.ErrorIs(t, err, ErrJournalEntryExists)
+	assert.Equal(t, 1, nk.updates)
+	assert.Equal(t, int64(-25), nk.wallets["user-1"]["gold"])
+
+	saved, _, err := bridge.loadJournalEntry(ctx, "user-1", "spend-1")
+	assert.NilError(t, err)
+	assert.Equal(t, StatusCommitted, saved.Status)
+	assert.Equal(t, DirectionSpend, saved.Direction)
+}
+
+func TestApplyEventRejectsUnknownPersonaAndBadAmounts(t *testing.T) {
+	bridge := NewBridge(newFakeWalletModule(), nil, "", "", "gold", nil, &sync.Map{})
+	ctx := context.Background()
+
+	_, err := bridge.ApplyEvent(ctx, Event{JournalID: "a", PersonaTag: "nobody", Currency: "gold", Amount: 1})
+	assert.ErrorIs(t, err, ErrUnknownPersonaTag)
+
+	_, err = bridge.ApplyEvent(ctx, Event{JournalID: "b", PersonaTag: "nobody", Currency: "gold", Amount: -1})
+	assert.ErrorIs(t, err, ErrInvalidAmount)
+}
+
+func TestConsumeEventsIgnoresOtherEvents(t *testing.T) {
+	nk := newFakeWalletModule()
+	assignments := &sync.Map{}
+	assignments.Store("hero", "user-1")
+	bridge := NewBridge(nk, nil, "", "", "gold", nil, assignments)
+
+	ch := make(chan []byte, 4)
+	ch <- []byte(`{"event":"something-else","journalId":"x","personaTag":"hero","amount":5}`)
+	ch <- []byte(`not json`)
+	ch <- []byte(`{"event":"wallet-spend","journalId":"y","personaTag":"hero","amount":5}`)
+	ch <- []byte(`{"event":"wallet-earn","journalId":"z","personaTag":"hero","amount":8}`)
+	close(ch)
+	bridge.ConsumeEvents(context.Background(), &testutils.FakeLogger{}, ch)
+
+	assert.Equal(t, 2, nk.updates)
+	assert.Equal(t, int64(3), nk.wallets["user-1"]["gold"])
+}
+
+func TestCreditUpdatesNakamaAfterCardinalAppliesIt(t *testing.T) {
+	nk := newFakeWalletModule()
+	cardinalAddress, cardinal := newFakeCardinal(t)
+	bridge := NewBridge(nk, fakeSigner{}, cardinalAddress, "ns", "gold", nil, &sync.Map{})
+	ctx := context.Background()
+
+	entry, err := bridge.Credit(ctx, "user-1", "hero", "iap:1", 50)
+	assert.NilError(t, err)
+	assert.Equal(t, StatusCommitted, entry.Status)
+	assert.Equal(t, "0xiap:1", entry.TxHash)
+	assert.Equal(t, 1, cardinal.numCredits())
+	assert.Equal(t, int64(50), nk.wallets["user-1"]["gold"])
+
+	_, err = bridge.Credit(ctx, "user-1", "hero", "iap:1", 50)
+	assert.ErrorIs(t, err, ErrJournalEntryExists)
+	assert.Equal(t, 1, cardinal.numCredits())
+	assert.Equal(t, int64(50), nk.wallets["user-1"]["gold"])
+}
+
+func TestRejectedCreditDoesNotUpdateNakama(t *testing.T) {
+	nk := newFakeWalletModule()
+	cardinalAddress, _ := newFakeCardinal(t, "persona hero does not exist")
+	bridge := NewBridge(nk, fakeSigner{}, cardinalAddress, "ns", "gold", nil, &sync.Map{})
+	ctx := context.Background()
+
+	_, err := bridge.Credit(ctx, "user-1", "hero", "iap:1", 50)
+	assert.ErrorIs(t, err, ErrCreditRejected)
+	assert.Equal(t, 0, nk.updates)
+
+	saved, _, err := bridge.loadJournalEntry(ctx, "user-1", "iap:1")
+	assert.NilError(t, err)
+	assert.Equal(t, StatusRejected, saved.Status)
+}
+
+func TestSettlingAFailedCreditAgainDoesNotCreditNakamaTwice(t *testing.T) {
+	nk := newFakeWalletModule()
+	cardinalAddress, cardinal := newFakeCardinal(t)
+	bridge := NewBridge(nk, fakeSigner{}, cardinalAddress, "ns", "gold", nil, &sync.Map{})
+	ctx := context.Background()
+
+	entry, err := bridge.Credit(ctx, "user-1", "hero", "iap:1", 50)
+	assert.NilError(t, err)
+
+	// Pretend that saving the committed entry failed, so the entry is settled again.
+	entry.Status = StatusFailed
+	assert.NilError(t, bridge.settle(ctx, entry, ""))
+	assert.Equal(t, StatusCommitted, entry.Status)
+	assert.Equal(t, 2, cardinal.numCredits())
+	assert.Equal(t, 1, nk.updates)
+	assert.Equal(t, int64(50), nk.wallets["user-1"]["gold"])
+}
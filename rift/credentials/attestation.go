@@ -0,0 +1,94 @@
+package credentials
+
+import (
+	"crypto/ed25519"
+	"encoding/binary"
+	"encoding/hex"
+	"time"
+
+	"github.com/rotisserie/eris"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	routerv1 "pkg.world.dev/world-engine/rift/router/v1"
+)
+
+// DefaultAttestationMaxAge is how old an attestation can be before game shards reject it. The router signs every
+// attempt to send a message again, so retries don't need more time. It is shorter than the time game shards remember
+// the responses to messages, so an attestation can't be replayed once the message is forgotten.
+const DefaultAttestationMaxAge = 5 * time.Minute
+
+// attestationDomain separates attestation signatures from the signatures of the same key for any other purpose.
+const attestationDomain = "world-engine/router/v1/attestation"
+
+// AttestationPayload returns the bytes that the router signs to attest that the request to the game shard of the given
+// namespace was sent by the base shard at the given time. Every field is length-prefixed, so that the bytes of one
+// field can't be moved to another.
+func AttestationPayload(namespace string, req *routerv1.SendMessageRequest, timestampMs uint64) []byte {
+	fields := [][]byte{
+		[]byte(attestationDomain),
+		[]byte(namespace),
+		[]byte(req.GetSender()),
+		[]byte(req.GetPersonaTag()),
+		[]byte(req.GetMessageId()),
+		[]byte(req.GetEvmTxHash()),
+		req.GetMessage(),
+	}
+	var payload []byte
+	for _, field := range fields {
+		payload = binary.BigEndian.AppendUint32(payload, uint32(len(field)))
+		payload = append(payload, field...)
+	}
+	return binary.BigEndian.AppendUint64(payload, timestampMs)
+}
+
+// Attest signs the request to the game shard of the given namespace with the router's key.
+func Attest(
+	key ed25519.PrivateKey, namespace string, req *routerv1.SendMessageRequest, now time.Time,
+) *routerv1.Attestation {
+	timestampMs := uint64(now.UnixMilli())
+	return &routerv1.Attestation{
+		TimestampMs: timestampMs,
+		Signature:   ed25519.Sign(key, AttestationPayload(namespace, req, timestampMs)),
+	}
+}
+
+// VerifyAttestation returns an Unauthenticated error unless the request carries an attestation that was signed with
+// the given router key for the game shard of the namespace, no more than maxAge from now.
+func VerifyAttestation(
+	key ed25519.PublicKey, namespace string, req *routerv1.SendMessageRequest, now time.Time, maxAge time.Duration,
+) error {
+	attestation := req.GetAttestation()
+	if attestation == nil {
+		return status.Error(codes.Unauthenticated, "missing router attestation")
+	}
+	signedAt := time.UnixMilli(int64(attestation.GetTimestampMs()))
+	if age := now.Sub(signedAt); age > maxAge || age < -maxAge {
+		return status.Errorf(codes.Unauthenticated, "router attestation signed at %s has expired",
+			signedAt.UTC().Format(time.RFC3339))
+	}
+	payload := AttestationPayload(namespace, req, attestation.GetTimestampMs())
+	if !ed25519.Verify(key, payload, attestation.GetSignature()) {
+		return status.Error(codes.Unauthenticated, "invalid router attestation")
+	}
+	return nil
+}
+
+// ParseAttestationKey parses the hex encoded 32 byte seed of the router's attestation key.
+func ParseAttestationKey(s string) (ed25519.PrivateKey, error) {
+	seed, err := hex.DecodeString(s)
+	if err != nil || len(seed) != ed25519.SeedSize {
+		return nil, eris.Errorf("invalid attestation key, must be %d hex encoded bytes", ed25519.SeedSize)
+	}
+	return ed25519.NewKeyFromSeed(seed), nil
+}
+
+// ParseAttestationPublicKey parses the hex encoded public key of the router's attestation key.
+func ParseAttestationPublicKey(s string) (ed25519.PublicKey, error) {
+	key, err := hex.DecodeString(s)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return nil, eris.Errorf("invalid attestation public key, must be %d hex encoded bytes",
+			ed25519.PublicKeySize)
+	}
+	return ed25519.PublicKey(key), nil
+}
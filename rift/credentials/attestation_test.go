@@ -0,0 +1,134 @@
+package credentials
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	routerv1 "pkg.world.dev/world-engine/rift/router/v1"
+)
+
+func TestVerifyAttestation(t *testing.T) {
+	key, err := ParseAttestationKey(hex.EncodeToString(make([]byte, ed25519.SeedSize)))
+	require.NoError(t, err)
+	pubKey, ok := key.Public().(ed25519.PublicKey)
+	require.True(t, ok)
+	otherPubKey, _, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	now := time.Now()
+	newReq := func() *routerv1.SendMessageRequest {
+		return &routerv1.SendMessageRequest{
+			Sender:     "0xabc",
+			PersonaTag: "tyler",
+			MessageId:  "game.move",
+			EvmTxHash:  "0x123",
+			Message:    []byte("hello"),
+		}
+	}
+
+	testCases := []struct {
+		name          string
+		req           func() *routerv1.SendMessageRequest
+		key           ed25519.PublicKey
+		namespace     string
+		expectedError string
+	}{
+		{
+			name: "Valid attestation",
+			req: func() *routerv1.SendMessageRequest {
+				req := newReq()
+				req.Attestation = Attest(key, "cardinal", req, now)
+				return req
+			},
+			key:       pubKey,
+			namespace: "cardinal",
+		},
+		{
+			name:          "Missing attestation",
+			req:           newReq,
+			key:           pubKey,
+			namespace:     "cardinal",
+			expectedError: "missing router attestation",
+		},
+		{
+			name: "Signed by another key",
+			req: func() *routerv1.SendMessageRequest {
+				req := newReq()
+				req.Attestation = Attest(key, "cardinal", req, now)
+				return req
+			},
+			key:           otherPubKey,
+			namespace:     "cardinal",
+			expectedError: "invalid router attestation",
+		},
+		{
+			name: "Signed for another namespace",
+			req: func() *routerv1.SendMessageRequest {
+				req := newReq()
+				req.Attestation = Attest(key, "other-shard", req, now)
+				return req
+			},
+			key:           pubKey,
+			namespace:     "cardinal",
+			expectedError: "invalid router attestation",
+		},
+		{
+			name: "Message changed after signing",
+			req: func() *routerv1.SendMessageRequest {
+				req := newReq()
+				req.Attestation = Attest(key, "cardinal", req, now)
+				req.Message = []byte("goodbye")
+				return req
+			},
+			key:           pubKey,
+			namespace:     "cardinal",
+			expectedError: "invalid router attestation",
+		},
+		{
+			name: "Expired attestation",
+			req: func() *routerv1.SendMessageRequest {
+				req := newReq()
+				req.Attestation = Attest(key, "cardinal", req, now.Add(-DefaultAttestationMaxAge-time.Second))
+				return req
+			},
+			key:           pubKey,
+			namespace:     "cardinal",
+			expectedError: "has expired",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := VerifyAttestation(tc.key, tc.namespace, tc.req(), now, DefaultAttestationMaxAge)
+			if tc.expectedError != "" {
+				assert.ErrorContains(t, err, tc.expectedError)
+				assert.Equal(t, codes.Unauthenticated, status.Code(err))
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestParseAttestationKeys(t *testing.T) {
+	_, err := ParseAttestationKey("abcd")
+	assert.Error(t, err)
+	_, err = ParseAttestationKey("zz" + hex.EncodeToString(make([]byte, ed25519.SeedSize-1)))
+	assert.Error(t, err)
+
+	key, err := ParseAttestationKey(hex.EncodeToString(make([]byte, ed25519.SeedSize)))
+	require.NoError(t, err)
+	pubKey, err := ParseAttestationPublicKey(hex.EncodeToString(key.Public().(ed25519.PublicKey)))
+	require.NoError(t, err)
+	assert.True(t, pubKey.Equal(key.Public()))
+
+	_, err = ParseAttestationPublicKey(hex.EncodeToString(make([]byte, ed25519.SeedSize+1)))
+	assert.Error(t, err)
+}
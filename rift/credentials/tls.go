@@ -0,0 +1,197 @@
+package credentials
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/rotisserie/eris"
+)
+
+// TLSFiles are the paths of the PEM encoded files of one side of a TLS connection between the router and a game
+// shard.
+type TLSFiles struct {
+	// CertPath and KeyPath are the certificate and the private key that identify this side of the connection. They are
+	// required for servers, and make clients present a client certificate.
+	CertPath string
+	KeyPath  string
+	// CAPath are the certificate authorities that verify the other side. Servers with a CA require and verify client
+	// certificates, and clients verify the server with it instead of the system roots.
+	CAPath string
+}
+
+// NewServerTLSConfig returns the TLS config of a gRPC server. The certificate and the client CAs are read again when
+// their files change, so that they can be rotated without restarting the server. Connections that are already open
+// keep using the certificates of their handshake.
+func NewServerTLSConfig(files TLSFiles) (*tls.Config, error) {
+	if files.CertPath == "" || files.KeyPath == "" {
+		return nil, eris.New("a server TLS config needs a certificate and a key")
+	}
+	certs := &keyPairReloader{certPath: files.CertPath, keyPath: files.KeyPath}
+	if _, err := certs.get(); err != nil {
+		return nil, err
+	}
+	cfg := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return certs.get()
+		},
+	}
+	if files.CAPath == "" {
+		return cfg, nil
+	}
+
+	cas := &certPoolReloader{path: files.CAPath}
+	if _, err := cas.get(); err != nil {
+		return nil, err
+	}
+	// The client certificate is verified by hand rather than with ClientCAs, so that the CAs can change after the
+	// config is handed to gRPC.
+	cfg.ClientAuth = tls.RequireAnyClientCert
+	cfg.VerifyPeerCertificate = func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+		pool, err := cas.get()
+		if err != nil {
+			return err
+		}
+		return verifyClientCertificate(pool, rawCerts)
+	}
+	return cfg, nil
+}
+
+// verifyClientCertificate verifies the certificate chain that a client presented against the given CAs.
+func verifyClientCertificate(pool *x509.CertPool, rawCerts [][]byte) error {
+	if len(rawCerts) == 0 {
+		return eris.New("no client certificate")
+	}
+	certs := make([]*x509.Certificate, 0, len(rawCerts))
+	for _, raw := range rawCerts {
+		cert, err := x509.ParseCertificate(raw)
+		if err != nil {
+			return eris.Wrap(err, "invalid client certificate")
+		}
+		certs = append(certs, cert)
+	}
+	opts := x509.VerifyOptions{
+		Roots:         pool,
+		Intermediates: x509.NewCertPool(),
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	for _, cert := range certs[1:] {
+		opts.Intermediates.AddCert(cert)
+	}
+	if _, err := certs[0].Verify(opts); err != nil {
+		return eris.Wrap(err, "untrusted client certificate")
+	}
+	return nil
+}
+
+// NewClientTLSConfig returns the TLS config of a gRPC client. The client certificate is read again when its files
+// change, so that it can be rotated without a restart. The CA is read once.
+func NewClientTLSConfig(files TLSFiles) (*tls.Config, error) {
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12}
+	if (files.CertPath == "") != (files.KeyPath == "") {
+		return nil, eris.New("a client certificate needs both a certificate and a key")
+	}
+	if files.CertPath != "" {
+		certs := &keyPairReloader{certPath: files.CertPath, keyPath: files.KeyPath}
+		if _, err := certs.get(); err != nil {
+			return nil, err
+		}
+		cfg.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			return certs.get()
+		}
+	}
+	if files.CAPath != "" {
+		pool, err := loadCertPool(files.CAPath)
+		if err != nil {
+			return nil, err
+		}
+		cfg.RootCAs = pool
+	}
+	return cfg, nil
+}
+
+// keyPairReloader loads a certificate and its key again when one of their files is modified. A rotation that fails,
+// e.g. because the certificate was replaced before the key, keeps the previous certificate and is tried again on the
+// next handshake.
+type keyPairReloader struct {
+	certPath, keyPath string
+
+	mu      sync.Mutex
+	cert    *tls.Certificate
+	modTime time.Time
+}
+
+func (r *keyPairReloader) get() (*tls.Certificate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	modTime, err := lastModified(r.certPath, r.keyPath)
+	if r.cert != nil && (err != nil || !modTime.After(r.modTime)) {
+		return r.cert, nil
+	}
+	cert, err := tls.LoadX509KeyPair(r.certPath, r.keyPath)
+	if err != nil {
+		if r.cert != nil {
+			return r.cert, nil
+		}
+		return nil, eris.Wrapf(err, "failed to load the TLS certificate %s", r.certPath)
+	}
+	r.cert, r.modTime = &cert, modTime
+	return r.cert, nil
+}
+
+// certPoolReloader loads a CA file again when it is modified, like keyPairReloader.
+type certPoolReloader struct {
+	path string
+
+	mu      sync.Mutex
+	pool    *x509.CertPool
+	modTime time.Time
+}
+
+func (r *certPoolReloader) get() (*x509.CertPool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	modTime, err := lastModified(r.path)
+	if r.pool != nil && (err != nil || !modTime.After(r.modTime)) {
+		return r.pool, nil
+	}
+	pool, err := loadCertPool(r.path)
+	if err != nil {
+		if r.pool != nil {
+			return r.pool, nil
+		}
+		return nil, err
+	}
+	r.pool, r.modTime = pool, modTime
+	return r.pool, nil
+}
+
+func loadCertPool(path string) (*x509.CertPool, error) {
+	bz, err := os.ReadFile(path)
+	if err != nil {
+		return nil, eris.Wrapf(err, "failed to read the CA file %s", path)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(bz) {
+		return nil, eris.Errorf("the CA file %s has no PEM encoded certificates", path)
+	}
+	return pool, nil
+}
+
+// lastModified returns the latest modification time of the given files.
+func lastModified(paths ...string) (time.Time, error) {
+	var latest time.Time
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return time.Time{}, eris.Wrapf(err, "failed to stat %s", path)
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest, nil
+}
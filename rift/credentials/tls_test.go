@@ -0,0 +1,194 @@
+package credentials
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func newTestCA(t *testing.T, name string) testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return testCA{cert: cert, key: key}
+}
+
+// issue writes a certificate signed by the CA, and its key, to the given files.
+func (ca testCA) issue(t *testing.T, name string, usage x509.ExtKeyUsage, certPath, keyPath string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	writePEM(t, certPath, "CERTIFICATE", der)
+	writePEM(t, keyPath, "EC PRIVATE KEY", keyDER)
+}
+
+func (ca testCA) write(t *testing.T, path string) {
+	writePEM(t, path, "CERTIFICATE", ca.cert.Raw)
+}
+
+// writePEM writes the file with a modification time later than any of the previous writes, so that reloaders notice
+// it even on file systems with coarse timestamps.
+func writePEM(t *testing.T, path, blockType string, der []byte) {
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600))
+	modTime := time.Now().Add(time.Duration(writes) * time.Second)
+	writes++
+	require.NoError(t, os.Chtimes(path, modTime, modTime))
+}
+
+var writes int
+
+// handshake connects a client with the given config to a server with the given config, and returns the certificate
+// that the server presented.
+func handshake(t *testing.T, serverCfg, clientCfg *tls.Config) (*x509.Certificate, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	serverErr := make(chan error, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			serverErr <- err
+			return
+		}
+		server := tls.Server(conn, serverCfg)
+		defer server.Close()
+		serverErr <- server.Handshake()
+	}()
+	conn, err := tls.Dial("tcp", listener.Addr().String(), clientCfg)
+	if err == nil {
+		defer conn.Close()
+	}
+	if sErr := <-serverErr; sErr != nil {
+		return nil, sErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	return conn.ConnectionState().PeerCertificates[0], nil
+}
+
+func TestTLSConfigRequiresClientCertificatesFromTheCA(t *testing.T) {
+	dir := t.TempDir()
+	path := func(name string) string { return filepath.Join(dir, name) }
+	ca := newTestCA(t, "ca")
+	ca.write(t, path("ca.pem"))
+	ca.issue(t, "game.shard", x509.ExtKeyUsageServerAuth, path("server.pem"), path("server.key"))
+	ca.issue(t, "router", x509.ExtKeyUsageClientAuth, path("client.pem"), path("client.key"))
+	other := newTestCA(t, "other")
+	other.issue(t, "intruder", x509.ExtKeyUsageClientAuth, path("intruder.pem"), path("intruder.key"))
+
+	serverCfg, err := NewServerTLSConfig(TLSFiles{CertPath: path("server.pem"), KeyPath: path("server.key"),
+		CAPath: path("ca.pem")})
+	require.NoError(t, err)
+	ca.write(t, path("server-ca.pem"))
+	newClientCfg := func(files TLSFiles) *tls.Config {
+		files.CAPath = path("server-ca.pem")
+		cfg, err := NewClientTLSConfig(files)
+		require.NoError(t, err)
+		cfg.ServerName = "game.shard"
+		return cfg
+	}
+
+	router := TLSFiles{CertPath: path("client.pem"), KeyPath: path("client.key")}
+	intruder := TLSFiles{CertPath: path("intruder.pem"), KeyPath: path("intruder.key")}
+
+	_, err = handshake(t, serverCfg, newClientCfg(router))
+	assert.NoError(t, err)
+
+	_, err = handshake(t, serverCfg, newClientCfg(TLSFiles{}))
+	assert.Error(t, err, "clients without a certificate are rejected")
+
+	_, err = handshake(t, serverCfg, newClientCfg(intruder))
+	assert.ErrorContains(t, err, "untrusted client certificate")
+
+	// The server trusts the new CA as soon as its file changes. The client still verifies the server with the old CA,
+	// which signed the server's certificate.
+	other.write(t, path("ca.pem"))
+	_, err = handshake(t, serverCfg, newClientCfg(intruder))
+	assert.NoError(t, err)
+	_, err = handshake(t, serverCfg, newClientCfg(router))
+	assert.ErrorContains(t, err, "untrusted client certificate")
+}
+
+func TestServerTLSConfigRotatesCertificates(t *testing.T) {
+	dir := t.TempDir()
+	path := func(name string) string { return filepath.Join(dir, name) }
+	ca := newTestCA(t, "ca")
+	ca.write(t, path("ca.pem"))
+	ca.issue(t, "game.shard", x509.ExtKeyUsageServerAuth, path("server.pem"), path("server.key"))
+
+	serverCfg, err := NewServerTLSConfig(TLSFiles{CertPath: path("server.pem"), KeyPath: path("server.key")})
+	require.NoError(t, err)
+	clientCfg, err := NewClientTLSConfig(TLSFiles{CAPath: path("ca.pem")})
+	require.NoError(t, err)
+	clientCfg.ServerName = "game.shard"
+
+	first, err := handshake(t, serverCfg, clientCfg)
+	require.NoError(t, err)
+
+	ca.issue(t, "game.shard", x509.ExtKeyUsageServerAuth, path("server.pem"), path("server.key"))
+	second, err := handshake(t, serverCfg, clientCfg)
+	require.NoError(t, err)
+	assert.NotEqual(t, first.SerialNumber, second.SerialNumber)
+
+	// A certificate whose key hasn't been replaced yet doesn't stop the server from using the previous one.
+	require.NoError(t, os.WriteFile(path("server.key"), []byte("half written"), 0o600))
+	third, err := handshake(t, serverCfg, clientCfg)
+	require.NoError(t, err)
+	assert.Equal(t, second.SerialNumber, third.SerialNumber)
+}
+
+func TestNewTLSConfigErrors(t *testing.T) {
+	_, err := NewServerTLSConfig(TLSFiles{})
+	assert.Error(t, err)
+	_, err = NewServerTLSConfig(TLSFiles{CertPath: "missing.pem", KeyPath: "missing.key"})
+	assert.Error(t, err)
+	_, err = NewClientTLSConfig(TLSFiles{CertPath: "client.pem"})
+	assert.Error(t, err)
+	_, err = NewClientTLSConfig(TLSFiles{CAPath: "missing.pem"})
+	assert.Error(t, err)
+}
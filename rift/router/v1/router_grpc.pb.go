@@ -24,6 +24,9 @@ const _ = grpc.SupportPackageIsVersion7
 type MsgClient interface {
 	SendMessage(ctx context.Context, in *SendMessageRequest, opts ...grpc.CallOption) (*SendMessageResponse, error)
 	QueryShard(ctx context.Context, in *QueryShardRequest, opts ...grpc.CallOption) (*QueryShardResponse, error)
+	// GetCatalog returns the messages and queries of the game shard that can be used from the EVM, together with the ABI
+	// types of their payloads, so relayers and code generators don't need to hardcode them.
+	GetCatalog(ctx context.Context, in *GetCatalogRequest, opts ...grpc.CallOption) (*GetCatalogResponse, error)
 }
 
 type msgClient struct {
@@ -52,12 +55,24 @@ func (c *msgClient) QueryShard(ctx context.Context, in *QueryShardRequest, opts
 	return out, nil
 }
 
+func (c *msgClient) GetCatalog(ctx context.Context, in *GetCatalogRequest, opts ...grpc.CallOption) (*GetCatalogResponse, error) {
+	out := new(GetCatalogResponse)
+	err := c.cc.Invoke(ctx, "/world.engine.router.v1.Msg/GetCatalog", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // MsgServer is the server API for Msg service.
 // All implementations must embed UnimplementedMsgServer
 // for forward compatibility
 type MsgServer interface {
 	SendMessage(context.Context, *SendMessageRequest) (*SendMessageResponse, error)
 	QueryShard(context.Context, *QueryShardRequest) (*QueryShardResponse, error)
+	// GetCatalog returns the messages and queries of the game shard that can be used from the EVM, together with the ABI
+	// types of their payloads, so relayers and code generators don't need to hardcode them.
+	GetCatalog(context.Context, *GetCatalogRequest) (*GetCatalogResponse, error)
 	mustEmbedUnimplementedMsgServer()
 }
 
@@ -71,6 +86,9 @@ func (UnimplementedMsgServer) SendMessage(context.Context, *SendMessageRequest)
 func (UnimplementedMsgServer) QueryShard(context.Context, *QueryShardRequest) (*QueryShardResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method QueryShard not implemented")
 }
+func (UnimplementedMsgServer) GetCatalog(context.Context, *GetCatalogRequest) (*GetCatalogResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCatalog not implemented")
+}
 func (UnimplementedMsgServer) mustEmbedUnimplementedMsgServer() {}
 
 // UnsafeMsgServer may be embedded to opt out of forward compatibility for this service.
@@ -120,6 +138,24 @@ func _Msg_QueryShard_Handler(srv interface{}, ctx context.Context, dec func(inte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Msg_GetCatalog_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCatalogRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MsgServer).GetCatalog(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/world.engine.router.v1.Msg/GetCatalog",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MsgServer).GetCatalog(ctx, req.(*GetCatalogRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Msg_ServiceDesc is the grpc.ServiceDesc for Msg service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -135,6 +171,10 @@ var Msg_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "QueryShard",
 			Handler:    _Msg_QueryShard_Handler,
 		},
+		{
+			MethodName: "GetCatalog",
+			Handler:    _Msg_GetCatalog_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "router/v1/router.proto",
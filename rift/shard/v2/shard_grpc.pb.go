@@ -28,6 +28,11 @@ type TransactionHandlerClient interface {
 	Submit(ctx context.Context, in *SubmitTransactionsRequest, opts ...grpc.CallOption) (*SubmitTransactionsResponse, error)
 	// QueryTransactions queries the base shard for sequenced transactions.
 	QueryTransactions(ctx context.Context, in *QueryTransactionsRequest, opts ...grpc.CallOption) (*QueryTransactionsResponse, error)
+	// DeliverOutbox hands messages that game shard systems emitted to EVM contracts over to the base shard, which stores
+	// them on chain. Messages may be delivered more than once; the base shard drops messages it has already stored.
+	DeliverOutbox(ctx context.Context, in *DeliverOutboxRequest, opts ...grpc.CallOption) (*DeliverOutboxResponse, error)
+	// SubmitStateRoot commits the game shard to its state at the end of a range of ticks on chain.
+	SubmitStateRoot(ctx context.Context, in *SubmitStateRootRequest, opts ...grpc.CallOption) (*SubmitStateRootResponse, error)
 }
 
 type transactionHandlerClient struct {
@@ -65,6 +70,24 @@ func (c *transactionHandlerClient) QueryTransactions(ctx context.Context, in *Qu
 	return out, nil
 }
 
+func (c *transactionHandlerClient) DeliverOutbox(ctx context.Context, in *DeliverOutboxRequest, opts ...grpc.CallOption) (*DeliverOutboxResponse, error) {
+	out := new(DeliverOutboxResponse)
+	err := c.cc.Invoke(ctx, "/world.engine.shard.v2.TransactionHandler/DeliverOutbox", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *transactionHandlerClient) SubmitStateRoot(ctx context.Context, in *SubmitStateRootRequest, opts ...grpc.CallOption) (*SubmitStateRootResponse, error) {
+	out := new(SubmitStateRootResponse)
+	err := c.cc.Invoke(ctx, "/world.engine.shard.v2.TransactionHandler/SubmitStateRoot", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // TransactionHandlerServer is the server API for TransactionHandler service.
 // All implementations must embed UnimplementedTransactionHandlerServer
 // for forward compatibility
@@ -75,6 +98,11 @@ type TransactionHandlerServer interface {
 	Submit(context.Context, *SubmitTransactionsRequest) (*SubmitTransactionsResponse, error)
 	// QueryTransactions queries the base shard for sequenced transactions.
 	QueryTransactions(context.Context, *QueryTransactionsRequest) (*QueryTransactionsResponse, error)
+	// DeliverOutbox hands messages that game shard systems emitted to EVM contracts over to the base shard, which stores
+	// them on chain. Messages may be delivered more than once; the base shard drops messages it has already stored.
+	DeliverOutbox(context.Context, *DeliverOutboxRequest) (*DeliverOutboxResponse, error)
+	// SubmitStateRoot commits the game shard to its state at the end of a range of ticks on chain.
+	SubmitStateRoot(context.Context, *SubmitStateRootRequest) (*SubmitStateRootResponse, error)
 	mustEmbedUnimplementedTransactionHandlerServer()
 }
 
@@ -91,6 +119,12 @@ func (UnimplementedTransactionHandlerServer) Submit(context.Context, *SubmitTran
 func (UnimplementedTransactionHandlerServer) QueryTransactions(context.Context, *QueryTransactionsRequest) (*QueryTransactionsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method QueryTransactions not implemented")
 }
+func (UnimplementedTransactionHandlerServer) DeliverOutbox(context.Context, *DeliverOutboxRequest) (*DeliverOutboxResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeliverOutbox not implemented")
+}
+func (UnimplementedTransactionHandlerServer) SubmitStateRoot(context.Context, *SubmitStateRootRequest) (*SubmitStateRootResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SubmitStateRoot not implemented")
+}
 func (UnimplementedTransactionHandlerServer) mustEmbedUnimplementedTransactionHandlerServer() {}
 
 // UnsafeTransactionHandlerServer may be embedded to opt out of forward compatibility for this service.
@@ -158,6 +192,42 @@ func _TransactionHandler_QueryTransactions_Handler(srv interface{}, ctx context.
 	return interceptor(ctx, in, info, handler)
 }
 
+func _TransactionHandler_DeliverOutbox_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeliverOutboxRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TransactionHandlerServer).DeliverOutbox(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/world.engine.shard.v2.TransactionHandler/DeliverOutbox",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TransactionHandlerServer).DeliverOutbox(ctx, req.(*DeliverOutboxRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TransactionHandler_SubmitStateRoot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SubmitStateRootRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TransactionHandlerServer).SubmitStateRoot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/world.engine.shard.v2.TransactionHandler/SubmitStateRoot",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TransactionHandlerServer).SubmitStateRoot(ctx, req.(*SubmitStateRootRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // TransactionHandler_ServiceDesc is the grpc.ServiceDesc for TransactionHandler service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -177,6 +247,14 @@ var TransactionHandler_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "QueryTransactions",
 			Handler:    _TransactionHandler_QueryTransactions_Handler,
 		},
+		{
+			MethodName: "DeliverOutbox",
+			Handler:    _TransactionHandler_DeliverOutbox_Handler,
+		},
+		{
+			MethodName: "SubmitStateRoot",
+			Handler:    _TransactionHandler_SubmitStateRoot_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "shard/v2/shard.proto",
@@ -0,0 +1,154 @@
+package sign
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/rotisserie/eris"
+)
+
+const (
+	// EIP712SignaturePrefix marks the signature of a transaction that was signed as EIP-712 typed data, e.g. with
+	// MetaMask's eth_signTypedData_v4. The rest of the signature is the hex encoded signature returned by the wallet.
+	EIP712SignaturePrefix = "eip712:"
+
+	// EIP712DomainName and EIP712DomainVersion are the domain of the typed data of Cardinal transactions.
+	EIP712DomainName    = "Cardinal"
+	EIP712DomainVersion = "1"
+	// EIP712PrimaryType is the type of the typed data of Cardinal transactions.
+	EIP712PrimaryType = "Transaction"
+)
+
+var (
+	eip712DomainTypeHash  = crypto.Keccak256([]byte("EIP712Domain(string name,string version)"))
+	eip712DomainSeparator = crypto.Keccak256(
+		eip712DomainTypeHash,
+		crypto.Keccak256([]byte(EIP712DomainName)),
+		crypto.Keccak256([]byte(EIP712DomainVersion)),
+	)
+)
+
+// NewEIP712Transaction is like NewTransaction, but signs the transaction as EIP-712 typed data, like a browser wallet
+// does. See Transaction.TypedData.
+func NewEIP712Transaction(
+	pk *ecdsa.PrivateKey,
+	personaTag,
+	namespace string,
+	nonce uint64,
+	data any,
+) (*Transaction, error) {
+	if len(personaTag) == 0 || personaTag == SystemPersonaTag {
+		return nil, ErrInvalidPersonaTag
+	}
+	return signEIP712(pk, personaTag, namespace, nonce, data)
+}
+
+// NewEIP712SystemTransaction is like NewSystemTransaction, but signs the transaction as EIP-712 typed data.
+func NewEIP712SystemTransaction(pk *ecdsa.PrivateKey, namespace string, nonce uint64, data any) (*Transaction, error) {
+	return signEIP712(pk, SystemPersonaTag, namespace, nonce, data)
+}
+
+func signEIP712(pk *ecdsa.PrivateKey, personaTag, namespace string, nonce uint64, data any) (*Transaction, error) {
+	sp, err := newUnsignedTransaction(personaTag, namespace, nonce, data)
+	if err != nil {
+		return nil, err
+	}
+	// The signature is marked before the hash is computed, since the hash depends on the signature scheme.
+	sp.Signature = EIP712SignaturePrefix
+	sp.populateHash()
+	buf, err := crypto.Sign(sp.Hash.Bytes(), pk)
+	if err != nil {
+		return nil, eris.Wrap(err, "error signing hash")
+	}
+	// Wallets return V as 27 or 28.
+	buf[crypto.RecoveryIDOffset] += 27
+	sp.Signature = EIP712SignaturePrefix + hexutil.Encode(buf)
+	return sp, nil
+}
+
+// IsEIP712 returns true if the transaction was signed as EIP-712 typed data.
+func (s *Transaction) IsEIP712() bool {
+	return strings.HasPrefix(s.Signature, EIP712SignaturePrefix)
+}
+
+// TypedData returns the EIP-712 typed data of the transaction, in the format of eth_signTypedData_v4. A browser wallet
+// signs a transaction by signing this typed data, and prefixing the returned signature with EIP712SignaturePrefix.
+// The body is signed as the string of the JSON encoded body, which must be compact and have sorted keys. The expiry
+// and the co-signers are only part of the typed data if they are set.
+func (s *Transaction) TypedData() map[string]any {
+	fields := s.eip712Fields()
+	types := make([]map[string]string, 0, len(fields))
+	message := make(map[string]any, len(fields))
+	for _, f := range fields {
+		types = append(types, map[string]string{"name": f.name, "type": f.typ})
+		message[f.name] = f.value
+	}
+	return map[string]any{
+		"types": map[string]any{
+			"EIP712Domain": []map[string]string{
+				{"name": "name", "type": "string"},
+				{"name": "version", "type": "string"},
+			},
+			EIP712PrimaryType: types,
+		},
+		"primaryType": EIP712PrimaryType,
+		"domain": map[string]any{
+			"name":    EIP712DomainName,
+			"version": EIP712DomainVersion,
+		},
+		"message": message,
+	}
+}
+
+// eip712Field is a field of the typed data of a transaction.
+type eip712Field struct {
+	name string
+	typ  string
+	// value is the value of the field in the typed data message, and encoded is its encoding in the struct hash.
+	value   any
+	encoded []byte
+}
+
+// eip712Fields returns the fields of the typed data of the transaction. Optional fields are left out of the type when
+// they are not set, so that the typed data of transactions that don't use them don't change.
+func (s *Transaction) eip712Fields() []eip712Field {
+	fields := []eip712Field{
+		{"personaTag", "string", s.PersonaTag, crypto.Keccak256([]byte(s.PersonaTag))},
+		{"namespace", "string", s.Namespace, crypto.Keccak256([]byte(s.Namespace))},
+		{"nonce", "uint256", strconv.FormatUint(s.Nonce, 10), math.U256Bytes(new(big.Int).SetUint64(s.Nonce))},
+	}
+	if s.ExpiresAtTick != 0 {
+		fields = append(fields, eip712Field{
+			"expiresAtTick", "uint256",
+			strconv.FormatUint(s.ExpiresAtTick, 10), math.U256Bytes(new(big.Int).SetUint64(s.ExpiresAtTick)),
+		})
+	}
+	if len(s.CoSigners) > 0 {
+		hashes := make([][]byte, 0, len(s.CoSigners))
+		for _, coSigner := range s.CoSigners {
+			hashes = append(hashes, crypto.Keccak256([]byte(coSigner)))
+		}
+		fields = append(fields, eip712Field{"coSigners", "string[]", s.CoSigners, crypto.Keccak256(hashes...)})
+	}
+	return append(fields, eip712Field{"body", "string", string(s.Body), crypto.Keccak256(s.Body)})
+}
+
+// eip712Hash returns the EIP-712 hash of the typed data of the transaction.
+func (s *Transaction) eip712Hash() common.Hash {
+	fields := s.eip712Fields()
+	members := make([]string, 0, len(fields))
+	encoded := make([][]byte, 0, len(fields)+1)
+	for _, f := range fields {
+		members = append(members, f.typ+" "+f.name)
+		encoded = append(encoded, f.encoded)
+	}
+	typeHash := crypto.Keccak256([]byte(EIP712PrimaryType + "(" + strings.Join(members, ",") + ")"))
+	structHash := crypto.Keccak256(append([][]byte{typeHash}, encoded...)...)
+	return crypto.Keccak256Hash([]byte{0x19, 0x01}, eip712DomainSeparator, structHash)
+}
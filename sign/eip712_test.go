@@ -0,0 +1,102 @@
+package sign
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/rotisserie/eris"
+	"gotest.tools/v3/assert"
+)
+
+func TestCanSignAndVerifyEIP712Payload(t *testing.T) {
+	goodKey, err := crypto.GenerateKey()
+	assert.NilError(t, err)
+	badKey, err := crypto.GenerateKey()
+	assert.NilError(t, err)
+	goodAddressHex := crypto.PubkeyToAddress(goodKey.PublicKey).Hex()
+	badAddressHex := crypto.PubkeyToAddress(badKey.PublicKey).Hex()
+
+	sp, err := NewEIP712Transaction(goodKey, "my-tag", "my-namespace", 100, `{"msg": "this is a request body"}`)
+	assert.NilError(t, err)
+	assert.Assert(t, sp.IsEIP712())
+	// Wallets return 0x prefixed signatures with V as 27 or 28.
+	assert.Assert(t, strings.HasPrefix(sp.Signature, EIP712SignaturePrefix+"0x"))
+
+	buf, err := sp.Marshal()
+	assert.NilError(t, err)
+	toBeVerified, err := UnmarshalTransaction(buf)
+	assert.NilError(t, err)
+	assert.NilError(t, toBeVerified.Verify(goodAddressHex))
+	assert.ErrorIs(t, eris.Cause(toBeVerified.Verify(badAddressHex)), ErrSignatureValidationFailed)
+
+	// The EIP-712 hash is not the hash of the other scheme, so a signature can't be replayed in the other scheme.
+	legacy, err := NewTransaction(goodKey, "my-tag", "my-namespace", 100, `{"msg": "this is a request body"}`)
+	assert.NilError(t, err)
+	assert.Assert(t, legacy.Hash != toBeVerified.Hash)
+	legacy.Signature = toBeVerified.Signature[len(EIP712SignaturePrefix):]
+	assert.ErrorIs(t, eris.Cause(legacy.Verify(goodAddressHex)), ErrSignatureValidationFailed)
+}
+
+func TestEIP712SignatureCoversEveryField(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	assert.NilError(t, err)
+	addressHex := crypto.PubkeyToAddress(key.PublicKey).Hex()
+
+	tamper := map[string]func(tx *Transaction){
+		"personaTag": func(tx *Transaction) { tx.PersonaTag = "other-tag" },
+		"namespace":  func(tx *Transaction) { tx.Namespace = "other-namespace" },
+		"nonce":      func(tx *Transaction) { tx.Nonce++ },
+		"body":       func(tx *Transaction) { tx.Body = json.RawMessage(`{"msg":"other"}`) },
+		// The expiry can't be added to a transaction that was signed without one
+		"expiresAtTick": func(tx *Transaction) { tx.ExpiresAtTick = 100 },
+		"coSigners":     func(tx *Transaction) { tx.CoSigners = []string{"other-tag"} },
+	}
+	for field, fn := range tamper {
+		t.Run(field, func(t *testing.T) {
+			sp, err := NewEIP712Transaction(key, "my-tag", "my-namespace", 1, map[string]any{"msg": "hello"})
+			assert.NilError(t, err)
+			buf, err := sp.Marshal()
+			assert.NilError(t, err)
+			tx, err := UnmarshalTransaction(buf)
+			assert.NilError(t, err)
+			assert.NilError(t, tx.Verify(addressHex))
+
+			fn(tx)
+			tx.populateHash()
+			assert.ErrorIs(t, eris.Cause(tx.Verify(addressHex)), ErrSignatureValidationFailed)
+		})
+	}
+}
+
+func TestEIP712SystemTransaction(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	assert.NilError(t, err)
+	sp, err := NewEIP712SystemTransaction(key, "my-namespace", 1, map[string]any{"personaTag": "hero"})
+	assert.NilError(t, err)
+	assert.Assert(t, sp.IsSystemTransaction())
+	assert.NilError(t, sp.Verify(crypto.PubkeyToAddress(key.PublicKey).Hex()))
+
+	_, err = NewEIP712Transaction(key, SystemPersonaTag, "my-namespace", 1, map[string]any{"personaTag": "hero"})
+	assert.ErrorIs(t, err, ErrInvalidPersonaTag)
+}
+
+func TestTypedDataDescribesTheTransaction(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	assert.NilError(t, err)
+	sp, err := NewEIP712Transaction(key, "my-tag", "my-namespace", 42, map[string]any{"b": 2, "a": 1})
+	assert.NilError(t, err)
+
+	typedData := sp.TypedData()
+	assert.Equal(t, EIP712PrimaryType, typedData["primaryType"])
+	assert.DeepEqual(t, map[string]any{
+		"personaTag": "my-tag",
+		"namespace":  "my-namespace",
+		"nonce":      "42",
+		"body":       `{"a":1,"b":2}`,
+	}, typedData["message"])
+	// The typed data is passed to wallets as JSON.
+	_, err = json.Marshal(typedData)
+	assert.NilError(t, err)
+}
@@ -8,7 +8,9 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"slices"
 	"strconv"
+	"strings"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -26,6 +28,8 @@ var (
 	ErrCannotSignEmptyBody       = errors.New("cannot sign empty body")
 	ErrInvalidPersonaTag         = errors.New("invalid persona tag")
 	ErrInvalidNamespace          = errors.New("invalid namespace")
+	ErrInvalidCoSigners          = errors.New("invalid co-signers")
+	ErrNotCoSigner               = errors.New("persona is not a co-signer of the transaction")
 
 	ErrNoPersonaTagField = errors.New("transaction must contain personaTag field")
 	ErrNoNamespaceField  = errors.New("transaction must contain namespace field")
@@ -40,6 +44,16 @@ type Transaction struct {
 	Signature  string          `json:"signature"` // hex encoded string
 	Hash       common.Hash     `json:"hash,omitempty" swaggertype:"string"`
 	Body       json.RawMessage `json:"body" swaggertype:"object"` // json string
+	// ExpiresAtTick is the tick from which the transaction is no longer executed, or 0 if it doesn't expire. A
+	// transaction that is still waiting to be executed at this tick is dropped, so that a world that fell behind does
+	// not act on stale input. It is part of the signed hash.
+	ExpiresAtTick uint64 `json:"expiresAtTick,omitempty"`
+	// CoSigners are the other personas that must sign the transaction before it is executed, e.g. the other player of
+	// a trade. They are part of the signed hash.
+	CoSigners []string `json:"coSigners,omitempty"`
+	// CoSignatures maps the co-signers that signed the transaction to their hex encoded signature of the hash. They
+	// are not part of the hash, so they can be collected after the transaction was signed. See CoSign.
+	CoSignatures map[string]string `json:"coSignatures,omitempty"`
 }
 
 func UnmarshalTransaction(bz []byte) (*Transaction, error) {
@@ -80,12 +94,15 @@ func (s *Transaction) checkRequiredFields() error {
 func MappedTransaction(tx map[string]interface{}) (*Transaction, error) {
 	s := new(Transaction)
 	transactionKeys := map[string]bool{
-		"personaTag": true,
-		"namespace":  true,
-		"signature":  true,
-		"nonce":      true,
-		"body":       true,
-		"hash":       true,
+		"personaTag":    true,
+		"namespace":     true,
+		"signature":     true,
+		"nonce":         true,
+		"body":          true,
+		"hash":          true,
+		"expiresAtTick": true,
+		"coSigners":     true,
+		"coSignatures":  true,
 	}
 	for key := range tx {
 		if !transactionKeys[key] {
@@ -149,6 +166,30 @@ func normalizeJSON(data any) ([]byte, error) {
 
 // sign uses the given private key to sign the personaTag, namespace, nonce, and data.
 func sign(pk *ecdsa.PrivateKey, personaTag, namespace string, nonce uint64, data any) (*Transaction, error) {
+	sp, err := newUnsignedTransaction(personaTag, namespace, nonce, data)
+	if err != nil {
+		return nil, err
+	}
+	if err = sp.sign(pk); err != nil {
+		return nil, err
+	}
+	return sp, nil
+}
+
+// sign signs the transaction with the given private key.
+func (s *Transaction) sign(pk *ecdsa.PrivateKey) error {
+	s.populateHash()
+	buf, err := crypto.Sign(s.Hash.Bytes(), pk)
+	if err != nil {
+		return eris.Wrap(err, "error signing hash")
+	}
+	s.Signature = common.Bytes2Hex(buf)
+	return nil
+}
+
+// newUnsignedTransaction returns a transaction of the given personaTag, namespace, nonce, and normalized data, without
+// a signature.
+func newUnsignedTransaction(personaTag, namespace string, nonce uint64, data any) (*Transaction, error) {
 	if data == nil || reflect.ValueOf(data).IsZero() {
 		return nil, ErrCannotSignEmptyBody
 	}
@@ -162,19 +203,12 @@ func sign(pk *ecdsa.PrivateKey, personaTag, namespace string, nonce uint64, data
 	if len(bz) == 0 {
 		return nil, ErrCannotSignEmptyBody
 	}
-	sp := &Transaction{
+	return &Transaction{
 		PersonaTag: personaTag,
 		Namespace:  namespace,
 		Nonce:      nonce,
 		Body:       bz,
-	}
-	sp.populateHash()
-	buf, err := crypto.Sign(sp.Hash.Bytes(), pk)
-	if err != nil {
-		return nil, eris.Wrap(err, "error signing hash")
-	}
-	sp.Signature = common.Bytes2Hex(buf)
-	return sp, nil
+	}, nil
 }
 
 // NewSystemTransaction signs a given body, and nonce with the given private key using the SystemPersonaTag.
@@ -196,6 +230,131 @@ func NewTransaction(
 	return sign(pk, personaTag, namespace, nonce, data)
 }
 
+// NewExpiringTransaction is like NewTransaction, but the transaction is dropped instead of executed if it is still
+// waiting to be executed at the tick expiresAtTick.
+func NewExpiringTransaction(
+	pk *ecdsa.PrivateKey,
+	personaTag,
+	namespace string,
+	nonce uint64,
+	expiresAtTick uint64,
+	data any,
+) (*Transaction, error) {
+	if len(personaTag) == 0 || personaTag == SystemPersonaTag {
+		return nil, ErrInvalidPersonaTag
+	}
+	sp, err := newUnsignedTransaction(personaTag, namespace, nonce, data)
+	if err != nil {
+		return nil, err
+	}
+	sp.ExpiresAtTick = expiresAtTick
+	if err = sp.sign(pk); err != nil {
+		return nil, err
+	}
+	return sp, nil
+}
+
+// NewCoSignedTransaction is like NewTransaction, but the transaction is only executed once it was also signed by all
+// the given co-signers with CoSign.
+func NewCoSignedTransaction(
+	pk *ecdsa.PrivateKey,
+	personaTag,
+	namespace string,
+	nonce uint64,
+	coSigners []string,
+	data any,
+) (*Transaction, error) {
+	if len(personaTag) == 0 || personaTag == SystemPersonaTag {
+		return nil, ErrInvalidPersonaTag
+	}
+	sp, err := newUnsignedTransaction(personaTag, namespace, nonce, data)
+	if err != nil {
+		return nil, err
+	}
+	sp.CoSigners = coSigners
+	if err = sp.ValidateCoSigners(); err != nil {
+		return nil, err
+	}
+	if err = sp.sign(pk); err != nil {
+		return nil, err
+	}
+	return sp, nil
+}
+
+// ValidateCoSigners returns an error if a co-signer is empty, contains a comma, is the system persona or the persona
+// that signed the transaction, or is listed more than once.
+func (s *Transaction) ValidateCoSigners() error {
+	seen := make(map[string]bool, len(s.CoSigners))
+	for _, coSigner := range s.CoSigners {
+		switch {
+		case coSigner == "" || coSigner == SystemPersonaTag || strings.Contains(coSigner, ","):
+			return eris.Wrapf(ErrInvalidCoSigners, "%q can't co-sign a transaction", coSigner)
+		case coSigner == s.PersonaTag:
+			return eris.Wrapf(ErrInvalidCoSigners, "%q already signed the transaction", coSigner)
+		case seen[coSigner]:
+			return eris.Wrapf(ErrInvalidCoSigners, "%q is listed more than once", coSigner)
+		}
+		seen[coSigner] = true
+	}
+	return nil
+}
+
+// IsCoSigner returns true if the given persona is one of the co-signers of the transaction.
+func (s *Transaction) IsCoSigner(personaTag string) bool {
+	return slices.Contains(s.CoSigners, personaTag)
+}
+
+// CoSign adds the signature of the given co-signer to the transaction.
+func (s *Transaction) CoSign(pk *ecdsa.PrivateKey, personaTag string) error {
+	if !s.IsCoSigner(personaTag) {
+		return eris.Wrapf(ErrNotCoSigner, "%q", personaTag)
+	}
+	if isZeroHash(s.Hash) {
+		s.populateHash()
+	}
+	buf, err := crypto.Sign(s.Hash.Bytes(), pk)
+	if err != nil {
+		return eris.Wrap(err, "error signing hash")
+	}
+	if s.CoSignatures == nil {
+		s.CoSignatures = map[string]string{}
+	}
+	s.CoSignatures[personaTag] = common.Bytes2Hex(buf)
+	return nil
+}
+
+// VerifyCoSignature verifies that the signature of the given co-signer is a valid signature of the hash by the given
+// address.
+func (s *Transaction) VerifyCoSignature(personaTag, hexAddress string) error {
+	if !s.IsCoSigner(personaTag) {
+		return eris.Wrapf(ErrNotCoSigner, "%q", personaTag)
+	}
+	signature, ok := s.CoSignatures[personaTag]
+	if !ok {
+		return eris.Wrapf(ErrSignatureValidationFailed, "%q did not sign the transaction", personaTag)
+	}
+	if isZeroHash(s.Hash) {
+		s.populateHash()
+	}
+	return verifySignature(s.Hash, signature, hexAddress)
+}
+
+// MissingCoSigners returns the co-signers that have not signed the transaction yet, in the order of CoSigners.
+func (s *Transaction) MissingCoSigners() []string {
+	var missing []string
+	for _, coSigner := range s.CoSigners {
+		if _, ok := s.CoSignatures[coSigner]; !ok {
+			missing = append(missing, coSigner)
+		}
+	}
+	return missing
+}
+
+// IsExpired returns true if the transaction must not be executed in the given tick anymore.
+func (s *Transaction) IsExpired(tick uint64) bool {
+	return s.ExpiresAtTick != 0 && tick >= s.ExpiresAtTick
+}
+
 func (s *Transaction) IsSystemTransaction() bool {
 	return s.PersonaTag == SystemPersonaTag
 }
@@ -219,42 +378,74 @@ func (s *Transaction) HashHex() string {
 	return s.Hash.Hex()
 }
 
-// Verify verifies this Transaction has a valid signature. If nil is returned, the signature is valid.
+// Verify verifies this Transaction has a valid signature. If nil is returned, the signature is valid. Both signatures
+// of the hash of the transaction and EIP-712 typed data signatures (see TypedData) are accepted.
 // Signature verification follows the pattern in crypto.TestSign:
 // https://github.com/ethereum/go-ethereum/blob/master/crypto/crypto_test.go#L94
 // TODO: Review this signature verification, and compare it to geth's sig verification
 func (s *Transaction) Verify(hexAddress string) error {
-	addr := common.HexToAddress(hexAddress)
-
 	if isZeroHash(s.Hash) {
 		s.populateHash()
 	}
+	return verifySignature(s.Hash, strings.TrimPrefix(s.Signature, EIP712SignaturePrefix), hexAddress)
+}
 
-	sig := common.Hex2Bytes(s.Signature)
-	if len(sig) < crypto.RecoveryIDOffset {
-		return eris.Wrap(ErrSignatureValidationFailed, "hex to bytes failed")
-	}
-	if sig[crypto.RecoveryIDOffset] == 27 || sig[crypto.RecoveryIDOffset] == 28 {
-		sig[crypto.RecoveryIDOffset] -= 27 // Transform yellow paper V from 27/28 to 0/1
+// Signer returns the address that signed this Transaction, e.g. to tell which of several keys that may sign for the
+// persona signed it.
+func (s *Transaction) Signer() (common.Address, error) {
+	if isZeroHash(s.Hash) {
+		s.populateHash()
 	}
+	return recoverSigner(s.Hash, strings.TrimPrefix(s.Signature, EIP712SignaturePrefix))
+}
 
-	signerPubKey, err := crypto.SigToPub(s.Hash.Bytes(), sig)
-	err = eris.Wrap(err, "")
+// verifySignature verifies that the hex encoded signature is a signature of the hash by the given address.
+func verifySignature(hash common.Hash, signature string, hexAddress string) error {
+	signerAddr, err := recoverSigner(hash, signature)
 	if err != nil {
 		return err
 	}
-	signerAddr := crypto.PubkeyToAddress(*signerPubKey)
-	if signerAddr != addr {
+	if signerAddr != common.HexToAddress(hexAddress) {
 		return eris.Wrap(ErrSignatureValidationFailed, "")
 	}
 	return nil
 }
 
+// recoverSigner returns the address that produced the hex encoded signature of the hash.
+func recoverSigner(hash common.Hash, signature string) (common.Address, error) {
+	sig := common.Hex2Bytes(strings.TrimPrefix(signature, "0x"))
+	if len(sig) <= crypto.RecoveryIDOffset {
+		return common.Address{}, eris.Wrap(ErrSignatureValidationFailed, "hex to bytes failed")
+	}
+	if sig[crypto.RecoveryIDOffset] == 27 || sig[crypto.RecoveryIDOffset] == 28 {
+		sig[crypto.RecoveryIDOffset] -= 27 // Transform yellow paper V from 27/28 to 0/1
+	}
+
+	signerPubKey, err := crypto.SigToPub(hash.Bytes(), sig)
+	if err != nil {
+		return common.Address{}, eris.Wrap(err, "")
+	}
+	return crypto.PubkeyToAddress(*signerPubKey), nil
+}
+
 func (s *Transaction) populateHash() {
-	s.Hash = crypto.Keccak256Hash(
+	if s.IsEIP712() {
+		s.Hash = s.eip712Hash()
+		return
+	}
+	fields := [][]byte{
 		[]byte(s.PersonaTag),
 		[]byte(s.Namespace),
 		[]byte(strconv.FormatUint(s.Nonce, 10)),
 		s.Body,
-	)
+	}
+	// The expiry is only hashed if it is set, so that the hashes of transactions without an expiry don't change
+	if s.ExpiresAtTick != 0 {
+		fields = append(fields, []byte("expiresAtTick:"+strconv.FormatUint(s.ExpiresAtTick, 10)))
+	}
+	// Like the expiry, the co-signers are only hashed if there are any.
+	if len(s.CoSigners) > 0 {
+		fields = append(fields, []byte("coSigners:"+strings.Join(s.CoSigners, ",")))
+	}
+	s.Hash = crypto.Keccak256Hash(fields...)
 }
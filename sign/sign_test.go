@@ -46,6 +46,20 @@ func TestCanSignAndVerifyPayload(t *testing.T) {
 	assert.ErrorIs(t, err, ErrSignatureValidationFailed)
 }
 
+func TestSignerIsRecovered(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	assert.NilError(t, err)
+	tx, err := NewTransaction(key, "my-tag", "my-namespace", 1, `{"msg": "hello"}`)
+	assert.NilError(t, err)
+	signer, err := tx.Signer()
+	assert.NilError(t, err)
+	assert.Equal(t, crypto.PubkeyToAddress(key.PublicKey), signer)
+
+	tx.Signature = "0x1234"
+	_, err = tx.Signer()
+	assert.ErrorIs(t, eris.Unwrap(err), ErrSignatureValidationFailed)
+}
+
 func TestCanParseAMappedTransaction(t *testing.T) {
 	goodKey, err := crypto.GenerateKey()
 	assert.NilError(t, err)
@@ -67,6 +81,73 @@ func TestCanParseAMappedTransaction(t *testing.T) {
 	assert.DeepEqual(t, sp, gotSP)
 }
 
+func TestExpiringTransaction(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	assert.NilError(t, err)
+	addressHex := crypto.PubkeyToAddress(key.PublicKey).Hex()
+	body := `{"msg": "this is a request body"}`
+
+	sp, err := NewExpiringTransaction(key, "my-tag", "my-namespace", 1, 10, body)
+	assert.NilError(t, err)
+	buf, err := sp.Marshal()
+	assert.NilError(t, err)
+	tx, err := UnmarshalTransaction(buf)
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(10), tx.ExpiresAtTick)
+	assert.NilError(t, tx.Verify(addressHex))
+	assert.Check(t, !tx.IsExpired(9))
+	assert.Check(t, tx.IsExpired(10))
+
+	// The expiry is signed, so it can't be extended or removed
+	for _, expiresAtTick := range []uint64{20, 0} {
+		tx.ExpiresAtTick = expiresAtTick
+		tx.populateHash()
+		assert.ErrorIs(t, eris.Cause(tx.Verify(addressHex)), ErrSignatureValidationFailed)
+	}
+
+	// Transactions without an expiry never expire
+	sp, err = NewTransaction(key, "my-tag", "my-namespace", 1, body)
+	assert.NilError(t, err)
+	assert.Check(t, !sp.IsExpired(1_000_000))
+}
+
+func TestCoSignedTransaction(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	assert.NilError(t, err)
+	aliceKey, err := crypto.GenerateKey()
+	assert.NilError(t, err)
+	aliceAddressHex := crypto.PubkeyToAddress(aliceKey.PublicKey).Hex()
+	body := `{"msg": "this is a request body"}`
+
+	sp, err := NewCoSignedTransaction(key, "my-tag", "my-namespace", 1, []string{"alice", "bob"}, body)
+	assert.NilError(t, err)
+	assert.NilError(t, sp.Verify(crypto.PubkeyToAddress(key.PublicKey).Hex()))
+	assert.DeepEqual(t, []string{"alice", "bob"}, sp.MissingCoSigners())
+	assert.ErrorIs(t, eris.Cause(sp.CoSign(aliceKey, "carol")), ErrNotCoSigner)
+
+	// The co-signatures are collected after the transaction was signed, and don't change its hash
+	hash := sp.Hash
+	assert.NilError(t, sp.CoSign(aliceKey, "alice"))
+	buf, err := sp.Marshal()
+	assert.NilError(t, err)
+	tx, err := UnmarshalTransaction(buf)
+	assert.NilError(t, err)
+	assert.Equal(t, hash, tx.Hash)
+	assert.DeepEqual(t, []string{"bob"}, tx.MissingCoSigners())
+	assert.NilError(t, tx.VerifyCoSignature("alice", aliceAddressHex))
+	assert.ErrorIs(t, eris.Cause(tx.VerifyCoSignature("bob", aliceAddressHex)), ErrSignatureValidationFailed)
+
+	// The co-signers are signed, so they can't be changed
+	tx.CoSigners = []string{"alice"}
+	tx.populateHash()
+	assert.ErrorIs(t, eris.Cause(tx.VerifyCoSignature("alice", aliceAddressHex)), ErrSignatureValidationFailed)
+
+	for _, coSigners := range [][]string{{"my-tag"}, {"alice", "alice"}, {""}, {SystemPersonaTag}, {"alice,bob"}} {
+		_, err = NewCoSignedTransaction(key, "my-tag", "my-namespace", 1, coSigners, body)
+		assert.ErrorIs(t, eris.Cause(err), ErrInvalidCoSigners)
+	}
+}
+
 func TestCanGetHashHex(t *testing.T) {
 	goodKey, err := crypto.GenerateKey()
 	assert.NilError(t, err)